@@ -0,0 +1,7 @@
+module github.com/akarsh323/Go-tutorials-
+
+go 1.25
+
+require github.com/akarsh323/Go-tutorials-/go_projects/pkg v0.0.0
+
+replace github.com/akarsh323/Go-tutorials-/go_projects/pkg => ./go_projects/pkg
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -91,30 +93,8 @@ func streamWeatherData() {
 }
 
 func main() {
-	fmt.Println("═══════════════════════════════════════════════════════════")
-	fmt.Println("EXAMPLE 1: Basic Synchronization (The 'Baton Pass')")
-	fmt.Println("═══════════════════════════════════════════════════════════\n")
-
-	demonstrateBasicChannel()
-
-	// ─────────────────────────────────────────────────────────────────────────────
-
-	fmt.Println("\n═══════════════════════════════════════════════════════════")
-	fmt.Println("EXAMPLE 2: The Deadlock Mistake")
-	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// We are not running the actual code here because it would crash the program.
-	// Instead, we describe the scenario explained in the transcript.
-	demonstrateDeadlock()
-
-	// ─────────────────────────────────────────────────────────────────────────────
-
-	fmt.Println("\n═══════════════════════════════════════════════════════════")
-	fmt.Println("EXAMPLE 3: Continuous Streams (Range over Channel)")
-	fmt.Println("═══════════════════════════════════════════════════════════\n")
-
-	fmt.Println("Simulating Weather API Stream...")
-	streamWeatherData()
+	section.Run(sections)
 
 	// ─────────────────────────────────────────────────────────────────────────────
 
@@ -152,3 +132,31 @@ Consider replacing 'time.Sleep' hacks with 'sync.WaitGroup' for robust
 synchronization in complex apps.
     `)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "demonstrate-basic-channel", Run: func() {
+		fmt.Println("═══════════════════════════════════════════════════════════")
+		fmt.Println("EXAMPLE 1: Basic Synchronization (The 'Baton Pass')")
+		fmt.Println("═══════════════════════════════════════════════════════════\n")
+
+		demonstrateBasicChannel()
+	}},
+	{Name: "demonstrate-deadlock", Run: func() {
+		fmt.Println("\n═══════════════════════════════════════════════════════════")
+		fmt.Println("EXAMPLE 2: The Deadlock Mistake")
+		fmt.Println("═══════════════════════════════════════════════════════════\n")
+
+		// We are not running the actual code here because it would crash the program.
+		// Instead, we describe the scenario explained in the transcript.
+		demonstrateDeadlock()
+	}},
+	{Name: "stream-weather-data", Run: func() {
+		fmt.Println("\n═══════════════════════════════════════════════════════════")
+		fmt.Println("EXAMPLE 3: Continuous Streams (Range over Channel)")
+		fmt.Println("═══════════════════════════════════════════════════════════\n")
+
+		fmt.Println("Simulating Weather API Stream...")
+		streamWeatherData()
+	}},
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -144,14 +146,7 @@ func main() {
 	fmt.Println("TOPIC: CHANNELS & RUNTIME BLOCKING")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// 1. Run Scenario A (Unbuffered / Synchronous)
-	scenarioA_SuccessfulWait()
-
-	// 2. Run Buffered Example (Asynchronous)
-	bufferedChannelDemo()
-
-	// 3. Run The Fix (How to avoid indefinite waiting)
-	preventingDeadlock()
+	section.Run(sections)
 
 	// 4. SCENARIO B (DEADLOCK)
 	// Uncomment the line below to see the crash!
@@ -159,3 +154,19 @@ func main() {
 
 	fmt.Println("Note: Uncomment 'scenarioB_Deadlock()' in code to see the crash.")
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "scenario-a-successful-wait", Run: func() {
+		// 1. Run Scenario A (Unbuffered / Synchronous)
+		scenarioA_SuccessfulWait()
+	}},
+	{Name: "buffered-channel-demo", Run: func() {
+		// 2. Run Buffered Example (Asynchronous)
+		bufferedChannelDemo()
+	}},
+	{Name: "preventing-deadlock", Run: func() {
+		// 3. Run The Fix (How to avoid indefinite waiting)
+		preventingDeadlock()
+	}},
+}
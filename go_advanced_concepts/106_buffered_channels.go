@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -138,17 +140,26 @@ func main() {
 	fmt.Println("TOPIC: BUFFERED CHANNELS")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// 1. Basic Storage
-	example1_BasicStorage()
-
-	// 2. Blocking on Full
-	example2_BlockingOnFull()
-
-	// 3. Blocking on Empty
-	example3_BlockingOnEmpty()
+	section.Run(sections)
 
 	// 4. Deadlock (Uncomment to see crash)
 	// example4_DeadlockSimulation()
 
 	fmt.Println("Note: Uncomment 'example4_DeadlockSimulation()' to see the crash.")
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic-storage", Run: func() {
+		// 1. Basic Storage
+		example1_BasicStorage()
+	}},
+	{Name: "blocking-on-full", Run: func() {
+		// 2. Blocking on Full
+		example2_BlockingOnFull()
+	}},
+	{Name: "blocking-on-empty", Run: func() {
+		// 3. Blocking on Empty
+		example3_BlockingOnEmpty()
+	}},
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -138,14 +140,7 @@ func main() {
 	fmt.Println("TOPIC: CHANNEL SYNCHRONIZATION")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// Run Pattern 1
-	syncSingleGoroutine()
-
-	// Run Pattern 2
-	syncMultipleGoroutines()
-
-	// Run Pattern 3
-	syncDataStream()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -160,3 +155,19 @@ func main() {
    to let the receiver know the stream is finished.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "sync-single-goroutine", Run: func() {
+		// Run Pattern 1
+		syncSingleGoroutine()
+	}},
+	{Name: "sync-multiple-goroutines", Run: func() {
+		// Run Pattern 2
+		syncMultipleGoroutines()
+	}},
+	{Name: "sync-data-stream", Run: func() {
+		// Run Pattern 3
+		syncDataStream()
+	}},
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -171,11 +173,7 @@ func main() {
 	fmt.Println("TOPIC: MULTIPLEXING WITH SELECT")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	example1_NaiveBlocking()
-	example2_NonBlocking()
-	example3_TheRace()
-	example4_Timeout()
-	example5_GracefulShutdown()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -188,3 +186,12 @@ func main() {
    gracefully instead of receiving infinite zero-values.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "naive-blocking", Run: example1_NaiveBlocking},
+	{Name: "non-blocking", Run: example2_NonBlocking},
+	{Name: "the-race", Run: example3_TheRace},
+	{Name: "timeout", Run: example4_Timeout},
+	{Name: "graceful-shutdown", Run: example5_GracefulShutdown},
+}
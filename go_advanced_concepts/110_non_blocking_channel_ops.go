@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -126,14 +128,7 @@ func main() {
 	fmt.Println("TOPIC: NON-BLOCKING CHANNEL OPERATIONS")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// 1. Run Peek Strategy
-	example1_NonBlockingReceive()
-
-	// 2. Run Drop Strategy
-	example2_NonBlockingSend()
-
-	// 3. Run Smart Worker
-	example3_SmartWorker()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS & BEST PRACTICES")
@@ -150,3 +145,19 @@ func main() {
    Without sleep, a 'default' loop will burn 100% of your CPU spinning uselessly.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "non-blocking-receive", Run: func() {
+		// 1. Run Peek Strategy
+		example1_NonBlockingReceive()
+	}},
+	{Name: "non-blocking-send", Run: func() {
+		// 2. Run Drop Strategy
+		example2_NonBlockingSend()
+	}},
+	{Name: "smart-worker", Run: func() {
+		// 3. Run Smart Worker
+		example3_SmartWorker()
+	}},
+}
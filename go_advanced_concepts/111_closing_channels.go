@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -164,10 +166,7 @@ func main() {
 	fmt.Println("TOPIC: CLOSING CHANNELS")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	example1_CommaOk()
-	example2_RangeLoop()
-	example3_Pipeline()
-	example4_CommonMistakes()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -180,3 +179,11 @@ func main() {
    channel when it finishes processing.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "comma-ok", Run: example1_CommaOk},
+	{Name: "range-loop", Run: example2_RangeLoop},
+	{Name: "pipeline", Run: example3_Pipeline},
+	{Name: "common-mistakes", Run: example4_CommonMistakes},
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -24,6 +26,15 @@ ROOT CONTEXTS:
 // ---------------------------------------------------------
 // Example 1: Carrying Values (WithValue)
 // ---------------------------------------------------------
+
+// ctxKey is an unexported key type. Only this package can create ctxKey
+// values, so no other package can read or overwrite our entries by accident
+// (a plain string key like "requestID" would collide with anyone else's).
+// See go_projects/145_request_scoped_values.go for the full pattern.
+type ctxKey string
+
+const requestIDKey ctxKey = "requestID"
+
 // Scenario: We want to attach a "Request ID" to the context so
 // every function down the chain knows which request it is handling.
 func example_WithValue() {
@@ -33,9 +44,8 @@ func example_WithValue() {
 	rootCtx := context.Background()
 
 	// 2. Add a value to the "Box"
-	// Key: "requestID", Value: "12345-ABC"
-	// Note: In real apps, use custom types for keys to avoid collisions.
-	ctxWithValue := context.WithValue(rootCtx, "requestID", "12345-ABC")
+	// Key: requestIDKey (typed, not a bare string), Value: "12345-ABC"
+	ctxWithValue := context.WithValue(rootCtx, requestIDKey, "12345-ABC")
 
 	// 3. Pass the box to the next function
 	processRequest(ctxWithValue)
@@ -44,7 +54,7 @@ func example_WithValue() {
 
 func processRequest(ctx context.Context) {
 	// 4. Retrieve value inside the function
-	val := ctx.Value("requestID")
+	val := ctx.Value(requestIDKey)
 
 	if val != nil {
 		fmt.Printf("Processing request with ID: %v\n", val)
@@ -145,7 +155,7 @@ func performHeavyWork(ctx context.Context) {
 // Real-World Pattern: Contextual Logging
 // ---------------------------------------------------------
 func logWithContext(ctx context.Context, msg string) {
-	reqID := ctx.Value("requestID")
+	reqID := ctx.Value(requestIDKey)
 	if reqID == nil {
 		reqID = "unknown"
 	}
@@ -157,14 +167,7 @@ func main() {
 	fmt.Println("TOPIC: THE CONTEXT PACKAGE")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// 1. Carrying Values
-	example_WithValue()
-
-	// 2. Timeouts
-	example_WithTimeout()
-
-	// 3. Manual Cancellation
-	example_WithCancel()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("TEACHER'S RULES (BEST PRACTICES)")
@@ -183,3 +186,19 @@ func main() {
    in the same function that created the context.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "example-with-value", Run: func() {
+		// 1. Carrying Values
+		example_WithValue()
+	}},
+	{Name: "example-with-timeout", Run: func() {
+		// 2. Timeouts
+		example_WithTimeout()
+	}},
+	{Name: "example-with-cancel", Run: func() {
+		// 3. Manual Cancellation
+		example_WithCancel()
+	}},
+}
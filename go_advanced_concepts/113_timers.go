@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -132,10 +134,7 @@ func main() {
 	fmt.Println("TOPIC: TIMERS IN GO")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	example1_BasicTimer()
-	example2_StopAndReset()
-	example3_TimeoutPattern()
-	example4_BackgroundDelay()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -154,3 +153,11 @@ func main() {
    other events (like user input or network requests).
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic-timer", Run: example1_BasicTimer},
+	{Name: "stop-and-reset", Run: example2_StopAndReset},
+	{Name: "timeout-pattern", Run: example3_TimeoutPattern},
+	{Name: "background-delay", Run: example4_BackgroundDelay},
+}
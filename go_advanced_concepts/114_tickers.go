@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -146,10 +148,7 @@ func main() {
 	fmt.Println("TOPIC: TICKERS IN GO")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	example1_BasicTicker()
-	example2_Heartbeat()
-	example3_RateLimiter()
-	example4_TimeTickTrap()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -163,3 +162,11 @@ func main() {
 4. RATE LIMITING: Use a Ticker to throttle fast loops (e.g., API requests).
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic-ticker", Run: example1_BasicTicker},
+	{Name: "heartbeat", Run: example2_Heartbeat},
+	{Name: "rate-limiter", Run: example3_RateLimiter},
+	{Name: "time-tick-trap", Run: example4_TimeTickTrap},
+}
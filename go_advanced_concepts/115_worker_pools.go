@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -150,11 +152,7 @@ func main() {
 	fmt.Println("TOPIC: WORKER POOLS")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	// Run Basic Example
-	example1_IntegerPool()
-
-	// Run Real-World Example
-	example2_TicketSystem()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -170,3 +168,15 @@ func main() {
    This tells the workers to stop their 'range' loops and shut down gracefully.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "integer-pool", Run: func() {
+		// Run Basic Example
+		example1_IntegerPool()
+	}},
+	{Name: "ticket-system", Run: func() {
+		// Run Real-World Example
+		example2_TicketSystem()
+	}},
+}
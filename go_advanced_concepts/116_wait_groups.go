@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -163,10 +165,7 @@ func main() {
 	fmt.Println("TOPIC: SYNC.WAITGROUP")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	example1_Basic()
-	example2_WithChannels()
-	example3_Structs()
-	example4_Pitfalls()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -178,3 +177,11 @@ func main() {
    and Close() so the main thread can Range over results.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic", Run: example1_Basic},
+	{Name: "with-channels", Run: example2_WithChannels},
+	{Name: "structs", Run: example3_Structs},
+	{Name: "pitfalls", Run: example4_Pitfalls},
+}
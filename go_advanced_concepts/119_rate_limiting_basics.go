@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -102,9 +104,7 @@ func main() {
 	fmt.Println("TOPIC: BASICS OF RATE LIMITING")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	strictLimiter()
-
-	burstyLimiter()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -117,3 +117,9 @@ func main() {
    The channel size determines the max burst (e.g., size 3 = 3 instant requests).
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "strict-limiter", Run: strictLimiter},
+	{Name: "bursty-limiter", Run: burstyLimiter},
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"sort"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -162,10 +164,7 @@ func main() {
 	fmt.Println("TOPIC: SORTING STRATEGIES")
 	fmt.Println("═══════════════════════════════════════════════════════════\n")
 
-	example1_Primitives()
-	example2_Interface()
-	example3_Functional()
-	example4_SortSlice()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -179,3 +178,11 @@ func main() {
    if you are building a library or need strict reusable types.
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "primitives", Run: example1_Primitives},
+	{Name: "interface", Run: example2_Interface},
+	{Name: "functional", Run: example3_Functional},
+	{Name: "sort-slice", Run: example4_SortSlice},
+}
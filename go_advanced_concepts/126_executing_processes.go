@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -164,10 +166,7 @@ func main() {
 		fmt.Println("═══════════════════════════════════════════════════════════\n")
 	}
 
-	example1_BasicExec()
-	example2_StdinPipe()
-	example3_Lifecycle()
-	example4_StreamingPipe()
+	section.Run(sections)
 
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("KEY TAKEAWAYS")
@@ -180,3 +179,11 @@ func main() {
    cannot release the process resources (Zombie processes).
 	`)
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic-exec", Run: example1_BasicExec},
+	{Name: "stdin-pipe", Run: example2_StdinPipe},
+	{Name: "lifecycle", Run: example3_Lifecycle},
+	{Name: "streaming-pipe", Run: example4_StreamingPipe},
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/telemetry"
+)
+
+/*
+TOPIC: OPT-IN TELEMETRY WITH A LOCAL QUEUE
+
+CONCEPT:
+Usage telemetry answers questions like "which lessons do people actually run?"
+Done badly, it leaks private data. Done well, it is:
+
+1. OPT-IN:     Nothing is recorded until the user says yes.
+2. ANONYMOUS:  Events carry an allow-list of harmless fields. Everything else
+               (paths, emails, free text) is stripped BEFORE it touches disk.
+3. OFFLINE-FIRST: Events are appended to a local queue file. Running a lesson
+               never waits on the network.
+4. BATCHED:    A flush sends the queue in batches, retrying with exponential
+               backoff, and only deletes events the server accepted — or
+               rejected (4xx): a batch refused once is refused again, and
+               kept at the head of the queue it would block the rest.
+
+ARCHITECTURE:
+   Record(event) ──► Redact ──► append JSON line ──► queue.jsonl
+                                                         │
+   Flush(ctx) ◄──────────── read batches ◄───────────────┘
+        │
+        └──► POST endpoint (retry + backoff) ──► keep leftovers for next time
+
+   Other processes may Record while a flush is sending, so Flush never
+   rewrites the file they append to: it takes a lock (flock(2) on
+   flush.lock, which the kernel releases if the flusher dies), renames queue.jsonl to sending.jsonl, and keeps the leftovers
+   there. New events start a fresh queue.jsonl.
+
+   The client lives in pkg/telemetry, with the redaction and flush tests;
+   this file is its command and demo.
+
+USAGE:
+   go run 138_telemetry_opt_in.go          → full demo (uses a temp dir)
+   go run 138_telemetry_opt_in.go status   → show YOUR opt-in state
+   go run 138_telemetry_opt_in.go on|off   → change it ("off" also deletes the queue)
+
+   The endpoint comes from GOTUT_TELEMETRY_ENDPOINT (see Topic 92).
+*/
+
+// ---------------------------------------------------------
+// Part 1: The "status | on | off" Command
+// ---------------------------------------------------------
+
+func userTelemetry() (*telemetry.Telemetry, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return telemetry.New(filepath.Join(base, "gotut", "telemetry"), os.Getenv("GOTUT_TELEMETRY_ENDPOINT")), nil
+}
+
+func runCommand(cmd string) error {
+	t, err := userTelemetry()
+	if err != nil {
+		return err
+	}
+	switch cmd {
+	case "status":
+		pending, err := t.Pending()
+		if err != nil {
+			return err
+		}
+		state := "OFF (nothing is recorded)"
+		if t.Enabled() {
+			state = "ON"
+		}
+		fmt.Printf("telemetry: %s\n", state)
+		fmt.Printf("directory: %s\n", t.Dir)
+		fmt.Printf("queued:    %d event(s)\n", len(pending))
+		if t.Endpoint == "" {
+			fmt.Println("endpoint:  (not configured, flush is disabled)")
+		} else {
+			fmt.Printf("endpoint:  %s\n", t.Endpoint)
+		}
+	case "on":
+		if err := t.SetEnabled(true); err != nil {
+			return err
+		}
+		fmt.Println("telemetry enabled — thank you! Run 'status' to see what is queued.")
+	case "off":
+		if err := t.SetEnabled(false); err != nil {
+			return err
+		}
+		fmt.Println("telemetry disabled and local queue deleted.")
+	default:
+		return fmt.Errorf("unknown command %q (want status, on, or off)", cmd)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 2: Demo Against a Fake Collector
+// ---------------------------------------------------------
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "gotut_telemetry_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// A fake collector that fails the first two requests with 503.
+	var (
+		mu       sync.Mutex
+		calls    int
+		received [][]byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls <= 2 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		var buf bytes.Buffer
+		buf.ReadFrom(r.Body)
+		received = append(received, buf.Bytes())
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	t := telemetry.New(dir, server.URL)
+	t.BatchSize = 2
+	t.BaseDelay = 50 * time.Millisecond
+	t.OnRetry = func(attempt int, delay time.Duration, err error) {
+		fmt.Printf("   ↻ retry %d in %v (last error: %v)\n", attempt, delay, err)
+	}
+
+	fmt.Println("--- Example 1: Nothing Is Recorded Without Consent ---")
+	t.Record(telemetry.Event{Name: "lesson_run", Topic: "73", Time: time.Now()})
+	pending, _ := t.Pending()
+	fmt.Printf("Queued before opt-in: %d event(s)\n\n", len(pending))
+
+	fmt.Println("--- Example 2: Opt In and Record (With Redaction) ---")
+	if err := t.SetEnabled(true); err != nil {
+		return err
+	}
+	home, _ := os.UserHomeDir()
+	for i, topic := range []string{"70", "73", "82", "87", "93"} {
+		err := t.Record(telemetry.Event{
+			Name:  "lesson_run",
+			Topic: topic,
+			Time:  time.Now(),
+			Props: map[string]string{
+				"go_version":  runtime.Version(),
+				"os":          runtime.GOOS,
+				"duration_ms": fmt.Sprint(120 * (i + 1)),
+				"result":      filepath.Join(home, "secret-project", "main.go"), // Oops: a path!
+				"email":       "learner@example.com",                            // Not allow-listed
+				"cwd":         "/home/learner/work",                             // Not allow-listed
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	raw, _ := os.ReadFile(t.QueuePath())
+	fmt.Println("Queue file on disk (first line):")
+	fmt.Printf("   %s\n\n", strings.SplitN(string(raw), "\n", 2)[0])
+
+	fmt.Println("--- Example 3: Batched Flush With Retries ---")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sent, err := t.Flush(ctx)
+	fmt.Printf("Delivered %d event(s), error: %v\n", sent, err)
+	pending, _ = t.Pending()
+	fmt.Printf("Left in queue: %d\n", len(pending))
+	fmt.Printf("Server saw %d request(s), accepted %d batch(es)\n\n", calls, len(received))
+
+	fmt.Println("--- Example 4: Redaction Checks ---")
+	payload := string(bytes.Join(received, nil))
+	forbidden := []string{"@", "secret-project", "/home/", "email", "cwd"}
+	if home != "" {
+		forbidden = append(forbidden, home)
+	}
+	failures := 0
+	for _, needle := range forbidden {
+		if strings.Contains(payload, needle) {
+			fmt.Printf("   ✗ FAIL: payload contains %q\n", needle)
+			failures++
+		}
+	}
+	if failures == 0 {
+		fmt.Printf("   ✓ PASS: none of %d forbidden values reached the server\n", len(forbidden))
+	}
+
+	fmt.Println("\n--- Example 5: Opting Out Deletes Local Data ---")
+	t.Record(telemetry.Event{Name: "lesson_run", Topic: "99", Time: time.Now()})
+	if err := t.SetEnabled(false); err != nil {
+		return err
+	}
+	_, statErr := os.Stat(t.QueuePath())
+	fmt.Printf("Queue exists after 'off'? %v\n", !errors.Is(statErr, os.ErrNotExist))
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: OPT-IN TELEMETRY WITH A LOCAL QUEUE")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. CONSENT FIRST: Check the opt-in marker before doing ANY work.
+2. ALLOW-LIST:    Keep only known-safe fields; redact before writing to disk.
+3. QUEUE LOCALLY: Appending to a file is fast and survives crashes/offline use.
+4. RETRY SMART:   Back off on 5xx/network errors, give up fast on 4xx.
+5. NEVER LOSE:    Delete only what the server accepted or refused for good;
+                 claim the queue first.
+6. EASY EXIT:     "off" must also delete whatever was collected.
+	`)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/ring"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/safe"
+)
+
+/*
+TOPIC: CRASH REPORTS WITH STACK TRACES
+
+CONCEPT:
+When a program panics, Go prints a stack trace to stderr and exits.
+For a CLI that learners run, that trace scrolls away and is lost.
+A CRASH REPORT captures everything needed to debug the problem in one file:
+
+  • Go version, OS, architecture
+  • Which topic/command was running
+  • The panic value and the full stack trace
+  • The last N log lines leading up to the crash
+
+THE BUILDING BLOCKS:
+1. RING BUFFER:   A fixed-size log sink that keeps only the most recent lines.
+                  Memory stays bounded no matter how long the program runs.
+                  (pkg/ring)
+2. SAFE RUNNER:   A function that wraps work in defer/recover (Topic: recover)
+                  and turns a panic into a saved report + a normal error.
+                  (pkg/safe: CrashReport, Runner.Run, and Runner.Report for
+                  a caller with its own recover, like gotut's CLI.Run)
+
+USAGE:
+   go run 139_crash_reports.go         → demo (reports go to a temp dir)
+   go run 139_crash_reports.go crash   → real crash, report saved in your config dir
+*/
+
+// The ring buffer is pkg/ring, and the report and the safe runner are
+// pkg/safe: gotut's own top-level recover (gotut/cli.go) saves its crash
+// reports through them too.
+
+// ---------------------------------------------------------
+// Part 1: A Buggy "Lesson" to Crash
+// ---------------------------------------------------------
+
+func buggyLesson(logger *log.Logger) {
+	scores := map[string][]int{"alice": {90, 85}, "bob": {}}
+	for _, name := range []string{"alice", "bob"} {
+		logger.Printf("computing best score for %s", name)
+		best := scores[name][0] // bob has no scores → index out of range
+		logger.Printf("best score for %s is %d", name, best)
+	}
+}
+
+func main() {
+	recent := ring.New(5)
+	// Every log line goes to BOTH the terminal and the ring buffer.
+	logger := log.New(io.MultiWriter(os.Stdout, recent), "[lesson] ", log.Ltime)
+
+	if len(os.Args) > 1 && os.Args[1] == "crash" {
+		dir, err := safe.Dir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		runner := &safe.Runner{ReportDir: dir, Log: recent}
+		if err := runner.Run("139", func() { buggyLesson(logger) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: CRASH REPORTS WITH STACK TRACES")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: The Ring Buffer Keeps Only Recent Lines ---")
+	demoRing := ring.New(3)
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(demoRing, "line %d\n", i)
+	}
+	fmt.Printf("Wrote 5 lines into a ring of 3, kept: %q\n\n", demoRing.Lines())
+
+	dir, err := os.MkdirTemp("", "gotut_crashes_*")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	runner := &safe.Runner{ReportDir: dir, Log: recent}
+
+	fmt.Println("--- Example 2: A Topic That Works ---")
+	err = runner.Run("demo-ok", func() { logger.Println("all good") })
+	fmt.Printf("Run returned: %v\n\n", err)
+
+	fmt.Println("--- Example 3: A Topic That Panics ---")
+	err = runner.Run("demo-buggy", func() { buggyLesson(logger) })
+	fmt.Printf("\nRun returned:\n%v\n\n", err)
+	fmt.Println("(Notice: the program did NOT die. main() is still running.)")
+
+	fmt.Println("\n--- Example 4: What the Report Contains ---")
+	matches, _ := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	if len(matches) == 1 {
+		data, _ := os.ReadFile(matches[0])
+		lines := strings.Split(string(data), "\n")
+		for _, line := range lines[:min(len(lines), 22)] {
+			fmt.Println("   │ " + line)
+		}
+		fmt.Println("   │ ...")
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. recover() only works inside a DEFERRED function in the SAME goroutine.
+   A panic in another goroutine still kills the program — wrap those too.
+2. Call debug.Stack() inside the deferred function to capture the panic site.
+3. A ring buffer gives you "what happened just before" at a fixed memory cost.
+4. Save the report with restrictive permissions (0600): it may hold log data.
+5. Tell the user WHERE the report is. A saved report nobody finds is useless.
+	`)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/trace"
+)
+
+/*
+TOPIC: DEBUG TRACING WITH SPANS
+
+CONCEPT:
+A log line says "something happened". A SPAN says "this piece of work
+started HERE, ended THERE, and was part of THAT bigger piece of work".
+
+    lesson 82                          ├────────────────────────┤ 310ms
+      ├─ section: md5                  ├──────┤                   80ms
+      ├─ section: sha256               │      ├─────────┤         120ms
+      └─ section: avalanche            │                ├──────┤  110ms
+
+A SPAN has:
+  • A name ("section: md5")
+  • Start and end times → duration
+  • Attributes (key/value details: bytes hashed, worker count...)
+  • A parent (so spans form a tree)
+  • A trace ID shared by every span in the same tree
+
+HOW SPANS FIND THEIR PARENT:
+The current span travels inside context.Context (Topic 112).
+Start(ctx, name) looks up the parent in ctx and returns a NEW ctx
+holding the child. Pass that ctx down and nesting happens automatically.
+
+THE PIECES (pkg/trace):
+  Tracer.Start(ctx, name) → a child of ctx's span, and a ctx that holds it
+  Span.SetAttr, Span.End  → details, and the end time; nil-safe
+  Tracer.Spans            → the finished spans, for ExportText / ExportJSON
+
+USAGE:
+   go run 140_tracing_spans.go            → run the "lesson" quietly
+   go run 140_tracing_spans.go -trace     → also print a timing breakdown
+   go run 140_tracing_spans.go -trace -trace-format json
+*/
+
+// Span, Tracer and the two exporters are pkg/trace: 141 exports the same
+// spans as OTLP, 172 times a lesson's sections with them, and
+// `gotut run --trace` prints this breakdown for any lesson.
+
+// ---------------------------------------------------------
+// Part 1: An Instrumented "Lesson"
+// ---------------------------------------------------------
+
+func runLesson(ctx context.Context, tracer *trace.Tracer) {
+	ctx, lesson := tracer.Start(ctx, "lesson 82: hashing")
+	defer lesson.End()
+
+	sections := []struct {
+		name string
+		work time.Duration
+		size int
+	}{
+		{"section: md5", 30 * time.Millisecond, 1 << 10},
+		{"section: sha256", 50 * time.Millisecond, 1 << 20},
+		{"section: avalanche", 20 * time.Millisecond, 64},
+	}
+
+	for _, sec := range sections {
+		runSection(ctx, tracer, sec.name, sec.work, sec.size)
+	}
+}
+
+func runSection(ctx context.Context, tracer *trace.Tracer, name string, work time.Duration, size int) {
+	ctx, span := tracer.Start(ctx, name)
+	defer span.End()
+	span.SetAttr("bytes", size)
+
+	fmt.Printf("running %s...\n", name)
+
+	// Nested span: prepare input, then process.
+	_, prep := tracer.Start(ctx, "prepare input")
+	time.Sleep(work / 4)
+	prep.End()
+
+	_, proc := tracer.Start(ctx, "compute")
+	time.Sleep(work)
+	proc.End()
+}
+
+func main() {
+	traceOn := flag.Bool("trace", false, "print a span timing breakdown after the lesson")
+	format := flag.String("trace-format", "text", "trace output format: text or json")
+	flag.Parse()
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: DEBUG TRACING WITH SPANS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	// With -trace off, tracer is nil and every span call is a cheap no-op.
+	var tracer *trace.Tracer
+	if *traceOn {
+		tracer = trace.New()
+	}
+
+	fmt.Println("--- Example 1: Running an Instrumented Lesson ---")
+	runLesson(context.Background(), tracer)
+
+	if !*traceOn {
+		fmt.Println("\n(Run again with -trace to see the timing breakdown.)")
+	} else {
+		fmt.Println("\n--- Example 2: Timing Breakdown ---")
+		spans := tracer.Spans()
+		switch *format {
+		case "json":
+			if err := trace.ExportJSON(os.Stdout, spans); err != nil {
+				fmt.Println("export failed:", err)
+			}
+		default:
+			trace.ExportText(os.Stdout, spans)
+		}
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A span = name + start/end + attributes + parent. Spans form a tree.
+2. Carry the current span in context.Context; children find parents for free.
+3. Always 'defer span.End()' right after Start, like 'defer f.Close()'.
+4. Make the disabled path nil-safe so instrumentation costs ~nothing when off.
+5. Export is separate from collection: the same spans can become text,
+   JSON, or (next topic) OpenTelemetry.
+	`)
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/trace"
+)
+
+/*
+TOPIC: DISTRIBUTED TRACING AND OPENTELEMETRY (OTLP/JSON) EXPORT
+
+CONCEPT:
+Topic 140 traced work inside ONE process. Real requests cross processes:
+
+    browser ──► frontend ──HTTP──► backend ──► database
+
+To see the whole journey as ONE trace, every hop must agree on the trace ID.
+The W3C "traceparent" header carries it across HTTP:
+
+    traceparent: 00-<32 hex trace id>-<16 hex parent span id>-01
+                 ↑ version                                    ↑ sampled flag
+
+THE FLOW:
+  1. Client side: take the current span from ctx, INJECT it into the header.
+  2. Server side: middleware EXTRACTS the header, starts a child span,
+     and puts it into r.Context() so handlers continue the same trace.
+  3. Each process EXPORTS its finished spans to a COLLECTOR.
+
+OTLP (OpenTelemetry Protocol) is the standard export format. Its JSON flavour
+is plain HTTP POST to /v1/traces and /v1/metrics — easy to produce with
+encoding/json. Collectors (Jaeger, Tempo, the OTel Collector) accept it.
+
+In this lesson a fake collector (httptest.Server) receives the export and the
+demo checks that all spans from both services landed in ONE trace.
+*/
+
+// Spans are pkg/trace's, Topic 140's: a span's Kind (trace.KindServer,
+// trace.KindClient) and its tracer's Service are the two fields OTLP adds.
+
+// ---------------------------------------------------------
+// Part 1: Context Propagation Over HTTP (traceparent)
+// ---------------------------------------------------------
+
+// Inject writes the current span into the outgoing request headers.
+func Inject(ctx context.Context, h http.Header) {
+	if s := trace.FromContext(ctx); s != nil {
+		h.Set("traceparent", fmt.Sprintf("00-%s-%s-01", s.TraceID, s.SpanID))
+	}
+}
+
+// Extract parses traceparent into a "remote parent" span stored in ctx.
+// Malformed headers are ignored: a bad header must never break the request.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	parts := strings.Split(h.Get("traceparent"), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, &trace.Span{TraceID: parts[1], SpanID: parts[2]})
+}
+
+// TracingMiddleware starts a SERVER span for every request, continuing the
+// caller's trace when a traceparent header is present.
+func TracingMiddleware(t *trace.Tracer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := Extract(r.Context(), r.Header)
+		ctx, span := t.Start(ctx, r.Method+" "+r.URL.Path)
+		span.Kind = trace.KindServer
+		span.SetAttr("http.method", r.Method)
+		span.SetAttr("http.target", r.URL.Path)
+		defer span.End()
+
+		w.Header().Set("X-Trace-Id", span.TraceID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// TracedGet performs a GET as a CLIENT span and propagates the trace.
+func TracedGet(ctx context.Context, t *trace.Tracer, url string) (string, error) {
+	ctx, span := t.Start(ctx, "GET "+url)
+	span.Kind = trace.KindClient
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	Inject(ctx, req.Header)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	span.SetAttr("http.status_code", resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// ---------------------------------------------------------
+// Part 2: OTLP/JSON Exporter (traces + metrics)
+// ---------------------------------------------------------
+
+// OTLP JSON uses lists of {key, value:{stringValue}} instead of plain maps,
+// hex strings for IDs, and nanosecond timestamps encoded as strings.
+type otlpAttr struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+func toAttrs(m map[string]string) []otlpAttr {
+	out := make([]otlpAttr, 0, len(m))
+	for k, v := range m {
+		out = append(out, otlpAttr{Key: k, Value: map[string]string{"stringValue": v}})
+	}
+	return out
+}
+
+func nanos(t time.Time) string { return strconv.FormatInt(t.UnixNano(), 10) }
+
+type OTLPExporter struct {
+	Endpoint string // e.g. http://localhost:4318
+	Client   *http.Client
+}
+
+// ExportSpans POSTs all finished spans of a tracer to /v1/traces.
+func (e *OTLPExporter) ExportSpans(ctx context.Context, t *trace.Tracer) error {
+	finished := t.Spans()
+	spans := make([]map[string]any, 0, len(finished))
+	for _, s := range finished {
+		spans = append(spans, map[string]any{
+			"traceId":           s.TraceID,
+			"spanId":            s.SpanID,
+			"parentSpanId":      s.ParentID,
+			"name":              s.Name,
+			"kind":              s.Kind,
+			"startTimeUnixNano": nanos(s.Start),
+			"endTimeUnixNano":   nanos(s.Finish),
+			"attributes":        toAttrs(s.Attributes),
+		})
+	}
+
+	payload := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource":   map[string]any{"attributes": toAttrs(map[string]string{"service.name": t.Service})},
+			"scopeSpans": []any{map[string]any{"scope": map[string]string{"name": "gotut"}, "spans": spans}},
+		}},
+	}
+	return e.post(ctx, "/v1/traces", payload)
+}
+
+// Counter is a monotonic metric, exported as an OTLP cumulative Sum.
+type Counter struct {
+	Name    string
+	started time.Time
+	value   atomic.Int64
+}
+
+func NewCounter(name string) *Counter { return &Counter{Name: name, started: time.Now()} }
+func (c *Counter) Add(n int64)        { c.value.Add(n) }
+
+func (e *OTLPExporter) ExportCounters(ctx context.Context, service string, counters ...*Counter) error {
+	metrics := make([]any, 0, len(counters))
+	for _, c := range counters {
+		metrics = append(metrics, map[string]any{
+			"name": c.Name,
+			"sum": map[string]any{
+				"aggregationTemporality": 2, // CUMULATIVE
+				"isMonotonic":            true,
+				"dataPoints": []any{map[string]any{
+					"asInt":             strconv.FormatInt(c.value.Load(), 10),
+					"startTimeUnixNano": nanos(c.started),
+					"timeUnixNano":      nanos(time.Now()),
+				}},
+			},
+		})
+	}
+	payload := map[string]any{
+		"resourceMetrics": []any{map[string]any{
+			"resource":     map[string]any{"attributes": toAttrs(map[string]string{"service.name": service})},
+			"scopeMetrics": []any{map[string]any{"scope": map[string]string{"name": "gotut"}, "metrics": metrics}},
+		}},
+	}
+	return e.post(ctx, "/v1/metrics", payload)
+}
+
+func (e *OTLPExporter) post(ctx context.Context, path string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned %s for %s", resp.Status, path)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 3: A Fake Collector
+// ---------------------------------------------------------
+
+type receivedSpan struct {
+	Service, TraceID, SpanID, ParentID, Name string
+}
+
+type FakeCollector struct {
+	mu      sync.Mutex
+	Spans   []receivedSpan
+	Metrics map[string]string
+}
+
+func (c *FakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/traces":
+		var req struct {
+			ResourceSpans []struct {
+				Resource struct {
+					Attributes []otlpAttr `json:"attributes"`
+				} `json:"resource"`
+				ScopeSpans []struct {
+					Spans []struct {
+						TraceID      string `json:"traceId"`
+						SpanID       string `json:"spanId"`
+						ParentSpanID string `json:"parentSpanId"`
+						Name         string `json:"name"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, rs := range req.ResourceSpans {
+			service := rs.Resource.Attributes[0].Value["stringValue"]
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					c.Spans = append(c.Spans, receivedSpan{service, s.TraceID, s.SpanID, s.ParentSpanID, s.Name})
+				}
+			}
+		}
+	case "/v1/metrics":
+		var req struct {
+			ResourceMetrics []struct {
+				ScopeMetrics []struct {
+					Metrics []struct {
+						Name string `json:"name"`
+						Sum  struct {
+							DataPoints []struct {
+								AsInt string `json:"asInt"`
+							} `json:"dataPoints"`
+						} `json:"sum"`
+					} `json:"metrics"`
+				} `json:"scopeMetrics"`
+			} `json:"resourceMetrics"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, rm := range req.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					c.Metrics[m.Name] = m.Sum.DataPoints[0].AsInt
+				}
+			}
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: DISTRIBUTED TRACING AND OTLP EXPORT")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	collector := &FakeCollector{Metrics: map[string]string{}}
+	collectorSrv := httptest.NewServer(collector)
+	defer collectorSrv.Close()
+
+	// Service 2: backend
+	backendTracer := &trace.Tracer{Service: "backend"}
+	backend := httptest.NewServer(TracingMiddleware(backendTracer, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, db := backendTracer.Start(r.Context(), "SELECT lessons")
+			db.Kind = trace.KindInternal
+			time.Sleep(5 * time.Millisecond)
+			db.End()
+			fmt.Fprint(w, `["70","73","82"]`)
+		})))
+	defer backend.Close()
+
+	// Service 1: frontend, which calls the backend
+	frontendTracer := &trace.Tracer{Service: "frontend"}
+	requests := NewCounter("http.server.requests")
+	frontend := httptest.NewServer(TracingMiddleware(frontendTracer, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			lessons, err := TracedGet(r.Context(), frontendTracer, backend.URL+"/lessons")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			fmt.Fprintf(w, "lessons: %s", lessons)
+		})))
+	defer frontend.Close()
+
+	fmt.Println("--- Example 1: One Request, Two Services ---")
+	resp, err := http.Get(frontend.URL + "/home")
+	if err != nil {
+		fmt.Println("request failed:", err)
+		return
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	fmt.Printf("Response:   %s\n", body)
+	fmt.Printf("X-Trace-Id: %s\n\n", resp.Header.Get("X-Trace-Id"))
+
+	fmt.Println("--- Example 2: Export to the (Fake) OTLP Collector ---")
+	ctx := context.Background()
+	exporter := &OTLPExporter{Endpoint: collectorSrv.URL, Client: collectorSrv.Client()}
+	for _, t := range []*trace.Tracer{frontendTracer, backendTracer} {
+		if err := exporter.ExportSpans(ctx, t); err != nil {
+			fmt.Println("export failed:", err)
+			return
+		}
+	}
+	if err := exporter.ExportCounters(ctx, "frontend", requests); err != nil {
+		fmt.Println("export failed:", err)
+		return
+	}
+	for _, s := range collector.Spans {
+		parent := s.ParentID
+		if parent == "" {
+			parent = "(root)"
+		}
+		fmt.Printf("  %-9s %-22s span=%s parent=%s\n", s.Service, s.Name, s.SpanID, parent)
+	}
+	fmt.Printf("  metric http.server.requests = %s\n\n", collector.Metrics["http.server.requests"])
+
+	fmt.Println("--- Example 3: Verifying the Trace ---")
+	traceIDs := map[string]bool{}
+	spanIDs := map[string]bool{}
+	for _, s := range collector.Spans {
+		traceIDs[s.TraceID] = true
+		spanIDs[s.SpanID] = true
+	}
+	orphans := 0
+	for _, s := range collector.Spans {
+		if s.ParentID != "" && !spanIDs[s.ParentID] {
+			orphans++
+		}
+	}
+	check := func(ok bool, msg string) {
+		mark := "✓ PASS"
+		if !ok {
+			mark = "✗ FAIL"
+		}
+		fmt.Printf("  %s: %s\n", mark, msg)
+	}
+	check(len(collector.Spans) == 4, fmt.Sprintf("collector received 4 spans (got %d)", len(collector.Spans)))
+	check(len(traceIDs) == 1, "all spans share ONE trace ID across both services")
+	check(orphans == 0, "every parent span ID points at a received span")
+	check(traceIDs[resp.Header.Get("X-Trace-Id")], "X-Trace-Id header matches the exported trace")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. PROPAGATION: Inject traceparent on the way out, Extract on the way in.
+2. MIDDLEWARE:  One wrapper gives every handler a server span for free.
+3. CONTEXT:     Always build outgoing requests from r.Context() — that is
+                what links the client span to the server span.
+4. OTLP/JSON:   Just HTTP + JSON. Hex IDs, nanosecond strings, attribute lists.
+5. METRICS:     Counters are exported as cumulative Sums next to the traces.
+6. In production use go.opentelemetry.io/otel; the concepts are identical.
+	`)
+}
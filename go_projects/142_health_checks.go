@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/health"
+)
+
+/*
+TOPIC: HEALTH CHECKS — LIVENESS VS READINESS
+
+CONCEPT:
+Orchestrators (Kubernetes, systemd, load balancers) keep asking your server
+two DIFFERENT questions:
+
+  /healthz  LIVENESS   "Is the process stuck or broken beyond repair?"
+                       NO → the orchestrator RESTARTS you.
+  /readyz   READINESS  "Can you serve traffic RIGHT NOW?"
+                       NO → the load balancer stops SENDING you requests,
+                            but leaves you running to recover.
+
+GETTING IT WRONG:
+  • Put the database check in LIVENESS and a DB blip restarts every replica
+    at once. Restarting doesn't fix the database — it makes things worse.
+  • Leave readiness always-true and traffic hits you during startup
+    (cache empty, migrations running) and fails.
+
+RULE OF THUMB:
+  Liveness  → only things a restart would fix (deadlocked main loop).
+  Readiness → dependencies (DB, cache, job queue) and warm-up state.
+
+THE REGISTRY (pkg/health):
+Subsystems register a named check with a kind and a timeout. The handler runs
+all checks of that kind CONCURRENTLY, each bounded by its own timeout, and
+returns aggregated JSON. A slow check can never hang the health endpoint.
+*/
+
+// The registry is pkg/health: the course's web front end (go_projects/web)
+// serves its /healthz and /readyz from it, and 155's daemon too.
+
+// ---------------------------------------------------------
+// Part 1: Fake Subsystems
+// ---------------------------------------------------------
+
+type fakeDB struct{ down atomic.Bool }
+
+func (d *fakeDB) Ping(ctx context.Context) error {
+	if d.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+type fakeCache struct{ latency time.Duration }
+
+func (c *fakeCache) Ping(ctx context.Context) error {
+	select {
+	case <-time.After(c.latency):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fakeQueue struct{ warmedUp atomic.Bool }
+
+func (q *fakeQueue) Ready(ctx context.Context) error {
+	if !q.warmedUp.Load() {
+		return errors.New("still replaying pending jobs")
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 2: Demo
+// ---------------------------------------------------------
+
+func probe(base, path string) {
+	resp, err := http.Get(base + path)
+	if err != nil {
+		fmt.Println("  probe failed:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var report health.Report
+	body, _ := io.ReadAll(resp.Body)
+	json.Unmarshal(body, &report)
+
+	fmt.Printf("  GET %-8s → %d %s\n", path, resp.StatusCode, report.Status)
+	names := make([]string, 0, len(report.Checks))
+	for name := range report.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c := report.Checks[name]
+		fmt.Printf("      %-10s %-4s %3dms %s\n", name, c.Status, c.DurationMS, c.Error)
+	}
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: HEALTH CHECKS — LIVENESS VS READINESS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	db := &fakeDB{}
+	cache := &fakeCache{latency: 10 * time.Millisecond}
+	queue := &fakeQueue{}
+
+	// The main loop "heartbeat": liveness fails only if it stops ticking.
+	var lastBeat atomic.Int64
+	lastBeat.Store(time.Now().UnixNano())
+
+	checks := &health.Registry{}
+	checks.Register("mainloop", health.Liveness, 100*time.Millisecond, func(ctx context.Context) error {
+		if age := time.Since(time.Unix(0, lastBeat.Load())); age > time.Second {
+			return fmt.Errorf("no heartbeat for %v", age.Round(time.Millisecond))
+		}
+		return nil
+	})
+	checks.Register("database", health.Readiness, 200*time.Millisecond, db.Ping)
+	checks.Register("cache", health.Readiness, 50*time.Millisecond, cache.Ping)
+	checks.Register("jobqueue", health.Readiness, 100*time.Millisecond, queue.Ready)
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", checks.Handler(health.Liveness))
+	mux.Handle("/readyz", checks.Handler(health.Readiness))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	fmt.Println("--- Example 1: During Startup (queue still warming up) ---")
+	probe(srv.URL, "/healthz")
+	probe(srv.URL, "/readyz")
+	fmt.Println("  → Alive, but NOT ready: keep the process, hold back traffic.")
+
+	fmt.Println("\n--- Example 2: Warmed Up ---")
+	queue.warmedUp.Store(true)
+	probe(srv.URL, "/readyz")
+
+	fmt.Println("\n--- Example 3: Database Outage + Slow Cache ---")
+	db.down.Store(true)
+	cache.latency = 500 * time.Millisecond // Longer than its 50ms timeout
+	start := time.Now()
+	probe(srv.URL, "/readyz")
+	fmt.Printf("  Endpoint answered in %v despite a 500ms cache.\n", time.Since(start).Round(10*time.Millisecond))
+	probe(srv.URL, "/healthz")
+	fmt.Println("  → Liveness stays OK: restarting would not fix the database.")
+
+	fmt.Println("\n--- Example 4: A Stuck Main Loop ---")
+	lastBeat.Store(time.Now().Add(-5 * time.Second).UnixNano())
+	probe(srv.URL, "/healthz")
+	fmt.Println("  → THIS is when a restart helps.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. /healthz (liveness) = "restart me?"  /readyz (readiness) = "send me traffic?"
+2. Dependencies belong in READINESS, never in liveness.
+3. Give every check its own timeout; run them concurrently.
+4. Return 503 + a JSON breakdown so humans AND machines can read the result.
+5. Use readiness for graceful shutdown too: fail /readyz first, then drain.
+	`)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/flags"
+)
+
+/*
+TOPIC: FEATURE FLAGS WITH PERCENTAGE ROLLOUT AND LIVE RELOAD
+
+CONCEPT:
+A feature flag separates DEPLOYING code from RELEASING a feature.
+The new code ships "dark" and a config file decides who sees it.
+
+TWO KINDS OF FLAGS:
+  1. BOOLEAN:    on for everyone, or off for everyone.
+  2. PERCENTAGE: on for N% of users — a gradual ROLLOUT.
+
+STICKY BUCKETING (why not rand.Intn(100)?):
+If a user gets the new renderer on one run and the old one on the next,
+the experience flickers. Instead we HASH the user ID (Topic 82):
+
+    bucket = sha256("flag-name:user-id") → first 8 bytes → mod 100
+
+The same user always lands in the same bucket, so raising the rollout from
+10% to 30% keeps the first 10% AND adds 20% more. Including the flag name in
+the hash means different flags pick different users.
+
+LIVE RELOAD:
+A watcher polls the config file's modification time. On change it parses the
+new file and SWAPS an atomic.Pointer to the new flag set. Readers never lock.
+A broken config file is rejected and the old flags keep working.
+
+THE PIECES (pkg/flags):
+  Bucket(flag, user)         → the user's stable bucket, 0..99
+  Set.IsEnabled(flag, user)  → one snapshot's answer
+  NewStore, Store.Watch      → a file's flags, reloaded when it changes
+
+Example flags.json:
+  {
+    "fancy_renderer": {"enabled": true, "rollout": 30},
+    "dark_mode":      {"enabled": true}
+  }
+*/
+
+// Flag, Set, Bucket and the reloading Store live in pkg/flags; what's
+// left here is a feature to put behind one.
+
+// ---------------------------------------------------------
+// Part 1: An Experimental Renderer Behind a Flag
+// ---------------------------------------------------------
+
+func renderPlain(title string) string { return "== " + title + " ==" }
+
+func renderFancy(title string) string {
+	line := strings.Repeat("─", len(title)+2)
+	return "╭" + line + "╮\n  │ " + title + " │\n  ╰" + line + "╯"
+}
+
+func renderTitle(store *flags.Store, user, title string) string {
+	if store.IsEnabled("fancy_renderer", user) {
+		return renderFancy(title)
+	}
+	return renderPlain(title)
+}
+
+func writeConfig(path, content string) {
+	os.WriteFile(path, []byte(content), 0o644)
+	// Bump mtime explicitly: two writes within the same filesystem tick
+	// would otherwise look unchanged to the watcher.
+	now := time.Now()
+	os.Chtimes(path, now, now)
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: FEATURE FLAGS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "gotut_flags_*")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.json")
+
+	fmt.Println("--- Example 1: Sticky Bucketing ---")
+	for _, user := range []string{"alice", "bob", "carol"} {
+		fmt.Printf("  %-6s bucket(fancy_renderer)=%2d  bucket(dark_mode)=%2d  (again: %2d)\n",
+			user, flags.Bucket("fancy_renderer", user), flags.Bucket("dark_mode", user), flags.Bucket("fancy_renderer", user))
+	}
+
+	fmt.Println("\n--- Example 2: Rollout Percentages Over 10,000 Users ---")
+	for _, pct := range []int{0, 10, 50, 100} {
+		set := flags.Set{"f": {Enabled: true, Rollout: &pct}}
+		on := 0
+		for i := 0; i < 10000; i++ {
+			if set.IsEnabled("f", fmt.Sprintf("user-%d", i)) {
+				on++
+			}
+		}
+		fmt.Printf("  rollout %3d%% → %5d users enabled (%.1f%%)\n", pct, on, float64(on)/100)
+	}
+
+	fmt.Println("\n--- Example 3: Toggling the Renderer at Runtime ---")
+	writeConfig(path, `{"fancy_renderer": {"enabled": false}}`)
+	store, err := flags.NewStore(path)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	reloaded := make(chan string, 10)
+	store.OnEvent = func(msg string) { reloaded <- msg }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, 20*time.Millisecond)
+
+	steps := []struct{ label, config string }{
+		{"flag OFF", ""},
+		{"flag ON for everyone", `{"fancy_renderer": {"enabled": true}}`},
+		{"broken config", `{"fancy_renderer": {"enabled": tru`},
+		{"flag OFF again", `{"fancy_renderer": {"enabled": false}}`},
+	}
+	for _, step := range steps {
+		if step.config != "" {
+			writeConfig(path, step.config)
+			select {
+			case msg := <-reloaded:
+				fmt.Printf("  [watcher] %s\n", msg)
+			case <-time.After(time.Second):
+				fmt.Println("  [watcher] no change detected")
+			}
+		}
+		fmt.Printf("  %s:\n  %s\n\n", step.label, renderTitle(store, "alice", "Topic 82: Hashing"))
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Unknown or disabled flags evaluate to OFF — the safe default.
+2. Hash (flag + user) for sticky, independent percentage buckets.
+3. Publish flag snapshots through atomic.Pointer: lock-free reads.
+4. Validate before swapping; a bad file must not take down the app.
+5. Delete flags once a rollout hits 100% — old flags are tech debt.
+	`)
+}
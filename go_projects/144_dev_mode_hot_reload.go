@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+TOPIC: DEV MODE — HOT-RELOADING TEMPLATES AND CONFIG
+
+CONCEPT:
+While building a web UI you edit templates and config constantly.
+Restarting the server after every edit is slow. In DEV MODE the server
+watches its source files and reloads them while it keeps serving requests.
+
+THE CONCURRENCY HAZARD:
+HTTP handlers run on many goroutines at once. The "obvious" reload is:
+
+    templates["home"] = newTemplate      // ✗ writes a map...
+    ... meanwhile a handler does ...
+    t := templates["home"]               // ✗ ...while others read it
+
+That is a DATA RACE. Go maps are not safe for concurrent read+write; the
+runtime may even crash with "concurrent map read and map write".
+A subtler bug: reloading templates and config SEPARATELY lets a request see
+the NEW template with the OLD config (a "torn" read).
+
+THE FIX: COPY-ON-WRITE + atomic.Pointer
+  1. Build a completely NEW state (template map + config) off to the side.
+  2. Validate it. If anything fails, keep the old state.
+  3. Publish it with one atomic.Pointer.Store.
+  4. Handlers call Load() ONCE per request and use that snapshot throughout.
+
+    handler ──Load()──► snapshot v1 {templates, config}   (never mutated)
+    reloader builds v2 ──Store()──► future Loads see v2
+
+Old snapshots are garbage-collected once no request uses them.
+
+USAGE:
+   go run 144_dev_mode_hot_reload.go          → demo with dev mode ON
+   go run 144_dev_mode_hot_reload.go -dev=false
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Immutable Snapshot
+// ---------------------------------------------------------
+
+type SiteConfig struct {
+	SiteName string `json:"site_name"`
+	Theme    string `json:"theme"`
+}
+
+// snapshot is built once and NEVER modified after it is published.
+type snapshot struct {
+	version   int
+	templates map[string]*template.Template
+	config    SiteConfig
+}
+
+func loadSnapshot(dir string, version int) (*snapshot, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "templates", "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string]*template.Template, len(files))
+	for _, f := range files {
+		t, err := template.ParseFiles(f)
+		if err != nil {
+			return nil, err // One broken template rejects the whole reload
+		}
+		templates[strings.TrimSuffix(filepath.Base(f), ".tmpl")] = t
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return nil, err
+	}
+	var cfg SiteConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config.json: %w", err)
+	}
+	return &snapshot{version: version, templates: templates, config: cfg}, nil
+}
+
+// ---------------------------------------------------------
+// Part 2: The Server and the Reloader
+// ---------------------------------------------------------
+
+type Server struct {
+	dir     string
+	state   atomic.Pointer[snapshot]
+	version atomic.Int64
+	Log     func(format string, args ...any)
+}
+
+func NewServer(dir string) (*Server, error) {
+	s := &Server{dir: dir, Log: func(string, ...any) {}}
+	snap, err := loadSnapshot(dir, 1)
+	if err != nil {
+		return nil, err
+	}
+	s.version.Store(1)
+	s.state.Store(snap)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snap := s.state.Load() // ONE load per request: a consistent view
+	name := strings.Trim(r.URL.Path, "/")
+	if name == "" {
+		name = "home"
+	}
+	t, ok := snap.templates[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("X-Snapshot-Version", fmt.Sprint(snap.version))
+	if err := t.Execute(w, snap.config); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Reload builds a new snapshot and swaps it in, or keeps the old one on error.
+func (s *Server) Reload() error {
+	snap, err := loadSnapshot(s.dir, int(s.version.Load())+1)
+	if err != nil {
+		return err
+	}
+	s.version.Store(int64(snap.version))
+	s.state.Store(snap)
+	return nil
+}
+
+// fingerprint summarizes modification times of every watched file.
+// Any edit, addition, or deletion changes it.
+func fingerprint(dir string) string {
+	var b strings.Builder
+	paths, _ := filepath.Glob(filepath.Join(dir, "templates", "*.tmpl"))
+	paths = append(paths, filepath.Join(dir, "config.json"))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&b, "%s@%d;", p, info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+// WatchAndReload polls for changes (dev mode only).
+func (s *Server) WatchAndReload(ctx context.Context, interval time.Duration) {
+	last := fingerprint(s.dir)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fp := fingerprint(s.dir)
+			if fp == last {
+				continue
+			}
+			last = fp
+			if err := s.Reload(); err != nil {
+				s.Log("reload failed, still serving v%d: %v", s.version.Load(), err)
+				continue
+			}
+			s.Log("reloaded → v%d", s.version.Load())
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 3: Demo
+// ---------------------------------------------------------
+
+func writeFile(path, content string) {
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte(content), 0o644)
+	now := time.Now()
+	os.Chtimes(path, now, now)
+}
+
+func fetch(url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Sprintf("v%s %s", resp.Header.Get("X-Snapshot-Version"), strings.TrimSpace(string(body)))
+}
+
+func main() {
+	dev := flag.Bool("dev", true, "watch templates and config and reload on change")
+	flag.Parse()
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: DEV MODE — HOT-RELOADING TEMPLATES AND CONFIG")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "gotut_devmode_*")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	home := filepath.Join(dir, "templates", "home.tmpl")
+	config := filepath.Join(dir, "config.json")
+	writeFile(home, `<h1>{{.SiteName}}</h1>`)
+	writeFile(config, `{"site_name": "Go Tutorials", "theme": "light"}`)
+
+	srv, err := NewServer(dir)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	reloads := make(chan string, 10)
+	srv.Log = func(format string, args ...any) { reloads <- fmt.Sprintf(format, args...) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if *dev {
+		go srv.WatchAndReload(ctx, 20*time.Millisecond)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	waitReload := func() {
+		select {
+		case msg := <-reloads:
+			fmt.Printf("  [dev] %s\n", msg)
+		case <-time.After(300 * time.Millisecond):
+			fmt.Println("  [dev] no reload (dev mode off?)")
+		}
+	}
+
+	fmt.Println("--- Example 1: Initial Page ---")
+	fmt.Println("  " + fetch(ts.URL+"/"))
+
+	fmt.Println("\n--- Example 2: Edit the Template ---")
+	writeFile(home, `<h1 class="{{.Theme}}">{{.SiteName}}</h1>`)
+	waitReload()
+	fmt.Println("  " + fetch(ts.URL+"/"))
+
+	fmt.Println("\n--- Example 3: Edit the Config ---")
+	writeFile(config, `{"site_name": "Go Tutorials", "theme": "dark"}`)
+	waitReload()
+	fmt.Println("  " + fetch(ts.URL+"/"))
+
+	fmt.Println("\n--- Example 4: A Broken Template Is Rejected ---")
+	writeFile(home, `<h1>{{.SiteName</h1>`)
+	waitReload()
+	fmt.Println("  " + fetch(ts.URL+"/"))
+
+	writeFile(home, `<h1 class="{{.Theme}}">{{.SiteName}}</h1>`) // Fix the typo
+	waitReload()
+
+	fmt.Println("\n--- Example 5: Hammering the Server During Reloads ---")
+	// Snapshots guarantee theme and template always come from the SAME version.
+	var wg sync.WaitGroup
+	var served atomic.Int64
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					if strings.Contains(fetch(ts.URL+"/"), "<h1") {
+						served.Add(1)
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		writeFile(config, fmt.Sprintf(`{"site_name": "Go Tutorials #%d", "theme": "dark"}`, i))
+		waitReload()
+	}
+	close(stop)
+	wg.Wait()
+	fmt.Printf("  %d requests served while reloading, zero locks on the read path.\n", served.Load())
+	fmt.Println("  (Run with 'go run -race' to confirm there are no data races.)")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Never mutate a map that request goroutines are reading.
+2. Build the NEW state completely, validate it, then swap ONE pointer.
+3. Bundle everything that must be consistent (templates + config) in ONE snapshot.
+4. Each request loads the snapshot once and uses only that.
+5. Keep hot-reload behind a dev flag; production should load once at startup.
+	`)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+)
+
+/*
+TOPIC: REQUEST-SCOPED VALUES DONE RIGHT (context.Value)
+
+CONCEPT:
+Topic 112 showed context.WithValue. It is easy to misuse. This lesson shows
+the pitfalls and a small "reqctx" layer with TYPED accessors that every
+middleware and handler uses.
+
+PITFALL 1: STRING KEYS COLLIDE
+    ctx = context.WithValue(ctx, "user", "alice")       // package auth
+    ctx = context.WithValue(ctx, "user", 42)            // package metrics
+    ctx.Value("user") → 42   ✗ auth's value is silently shadowed
+
+PITFALL 2: UNCHECKED TYPE ASSERTIONS PANIC
+    id := ctx.Value("requestID").(string)   ✗ panics when the key is missing
+
+PITFALL 3: TYPOS COMPILE FINE
+    ctx.Value("requestId")                  ✗ returns nil, no error anywhere
+
+PITFALL 4: USING CONTEXT AS A GRAB-BAG
+    Putting the database handle or optional function parameters in ctx hides
+    dependencies. Context values are for REQUEST-SCOPED data that crosses
+    API boundaries: request ID, authenticated user, request logger, trace span.
+
+THE FIX: one small package owns the keys.
+  • Keys are an UNEXPORTED type → no other package can collide.
+  • Exported WithX / X functions are the ONLY way in and out.
+  • Getters return (value, ok) or a safe default — never panic.
+
+    reqctx.WithRequestID(ctx, id)    reqctx.RequestID(ctx) string
+    reqctx.WithUser(ctx, u)          reqctx.UserFrom(ctx) (User, bool)
+    reqctx.WithLogger(ctx, l)        reqctx.Logger(ctx) *log.Logger
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Pitfalls, Live
+// ---------------------------------------------------------
+
+func demoPitfalls() {
+	fmt.Println("--- Example 1: The Pitfalls ---")
+
+	ctx := context.WithValue(context.Background(), "user", "alice")
+	ctx = context.WithValue(ctx, "user", 42) // Some other package, same string
+	fmt.Printf("  Collision: ctx.Value(\"user\") = %v (alice is gone)\n", ctx.Value("user"))
+
+	fmt.Printf("  Typo:      ctx.Value(\"requestId\") = %v (no compile error)\n", ctx.Value("requestId"))
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Printf("  Panic:     %v\n", r)
+			}
+		}()
+		_ = ctx.Value("requestID").(string)
+	}()
+	fmt.Println()
+}
+
+// ---------------------------------------------------------
+// Part 2: The reqctx Layer (typed accessors)
+// ---------------------------------------------------------
+// In a larger program this block would be its own package "reqctx".
+
+// key is unexported: outside code cannot construct a key of this type,
+// so it cannot read or overwrite our values except through the functions below.
+type key int
+
+const (
+	requestIDKey key = iota
+	userKey
+	loggerKey
+)
+
+type User struct {
+	ID   string
+	Role string
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID or "-" when none is set.
+func RequestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+	return "-"
+}
+
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey, u)
+}
+
+// UserFrom reports the authenticated user; ok is false for anonymous requests.
+func UserFrom(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey).(User)
+	return u, ok
+}
+
+func WithLogger(ctx context.Context, l *log.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// Logger never returns nil: callers can log unconditionally.
+func Logger(ctx context.Context) *log.Logger {
+	if l, ok := ctx.Value(loggerKey).(*log.Logger); ok {
+		return l
+	}
+	return log.New(io.Discard, "", 0)
+}
+
+// ---------------------------------------------------------
+// Part 3: Middleware and Handlers Using reqctx
+// ---------------------------------------------------------
+
+// RequestIDMiddleware reuses an incoming X-Request-Id or generates one, and
+// attaches a logger that stamps every line with it.
+func RequestIDMiddleware(base io.Writer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if id == "" {
+			b := make([]byte, 4)
+			rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		ctx := WithRequestID(r.Context(), id)
+		ctx = WithLogger(ctx, log.New(base, "[req "+id+"] ", 0))
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AuthMiddleware turns a bearer token into a User in the context.
+func AuthMiddleware(next http.Handler) http.Handler {
+	tokens := map[string]User{"t-alice": {ID: "alice", Role: "admin"}, "t-bob": {ID: "bob", Role: "learner"}}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if u, ok := tokens[token]; ok {
+			r = r.WithContext(WithUser(r.Context(), u))
+			Logger(r.Context()).Printf("authenticated as %s", u.ID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func progressHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := Logger(ctx)
+
+	u, ok := UserFrom(ctx)
+	if !ok {
+		log.Printf("anonymous request rejected")
+		http.Error(w, "login required (request "+RequestID(ctx)+")", http.StatusUnauthorized)
+		return
+	}
+	completed := loadProgress(ctx, u.ID)
+	log.Printf("served progress for %s", u.ID)
+	fmt.Fprintf(w, "%s has completed %d topics", u.ID, completed)
+}
+
+// loadProgress is a deeper layer. It takes ctx (first param, as in Topic 112)
+// and can still log with the request ID, without any extra parameters.
+func loadProgress(ctx context.Context, userID string) int {
+	Logger(ctx).Printf("querying progress table for %s", userID)
+	return len(userID) * 7
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: REQUEST-SCOPED VALUES DONE RIGHT")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	demoPitfalls()
+
+	fmt.Println("--- Example 2: Typed Accessors Never Panic ---")
+	empty := context.Background()
+	_, ok := UserFrom(empty)
+	fmt.Printf("  RequestID(empty) = %q, UserFrom(empty) ok = %v\n", RequestID(empty), ok)
+	Logger(empty).Println("this goes nowhere, and that's fine")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Middleware → Handler → Deeper Layer ---")
+	handler := RequestIDMiddleware(os.Stdout, AuthMiddleware(http.HandlerFunc(progressHandler)))
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	for _, tc := range []struct{ token, reqID string }{
+		{"t-alice", "abc123"},
+		{"", ""},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/progress", nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		if tc.reqID != "" {
+			req.Header.Set("X-Request-Id", tc.reqID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			fmt.Println("  request failed:", err)
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("  → %d %s\n\n", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Never use built-in types (string, int) as context keys.
+2. Hide keys behind an unexported type; expose WithX/X functions only.
+3. Getters use the comma-ok assertion and return safe defaults.
+4. Context values are for request-scoped data, NOT for dependencies
+   (DB handles, config) — pass those explicitly.
+5. Middleware adds values; handlers and deeper layers only read them.
+	`)
+}
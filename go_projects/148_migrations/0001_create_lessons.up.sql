@@ -0,0 +1,5 @@
+CREATE TABLE lessons (
+    id         INTEGER PRIMARY KEY,
+    title      TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
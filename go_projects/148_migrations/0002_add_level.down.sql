@@ -0,0 +1,2 @@
+DROP INDEX idx_lessons_level;
+ALTER TABLE lessons DROP COLUMN level;
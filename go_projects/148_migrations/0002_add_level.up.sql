@@ -0,0 +1,2 @@
+ALTER TABLE lessons ADD COLUMN level TEXT NOT NULL DEFAULT 'beginner';
+CREATE INDEX idx_lessons_level ON lessons (level);
@@ -0,0 +1,6 @@
+CREATE TABLE progress (
+    user_id   TEXT NOT NULL,
+    lesson_id INTEGER NOT NULL REFERENCES lessons (id),
+    done_at   TIMESTAMP,
+    PRIMARY KEY (user_id, lesson_id)
+);
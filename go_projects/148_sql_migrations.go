@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"strings"
+	"testing/fstest"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/migrate"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/migrate/memdb"
+)
+
+/*
+TOPIC: A SQL MIGRATION RUNNER
+
+CONCEPT:
+A database schema changes over time. MIGRATIONS are numbered SQL files that
+move the schema forward (up) or back (down), applied in order, exactly once:
+
+    148_migrations/
+      0001_create_lessons.up.sql     0001_create_lessons.down.sql
+      0002_add_level.up.sql          0002_add_level.down.sql
+      0003_create_progress.up.sql    0003_create_progress.down.sql
+
+The runner remembers what it applied in a bookkeeping table:
+
+    schema_migrations (version INTEGER PRIMARY KEY, dirty BOOLEAN)
+
+DIRTY STATE — the most important safety feature:
+  1. Before running migration N, insert (N, dirty=true).
+  2. Run the SQL.
+  3. On success set dirty=false.
+If the process crashes or the SQL fails in step 2, the row STAYS dirty.
+Some databases (MySQL) cannot roll back DDL, so the schema may be half-changed.
+The runner then REFUSES to do anything until a human inspects the database and
+runs "force". Guessing would risk corrupting production data.
+
+EMBEDDING:
+The .sql files are compiled into the binary with //go:embed (Topic 89), so
+the migrations always match the code that expects them.
+
+ABOUT THE DATABASE IN THIS LESSON:
+The standard library has database/sql but no database driver. To keep this
+file runnable with plain "go run", it opens pkg/migrate/memdb, a tiny driver
+that understands just enough SQL to track tables. The runner itself only uses
+database/sql and works unchanged with a real driver (sqlite, postgres, mysql).
+
+USAGE (the same commands the demo runs, and gotut tool migrate's):
+    migrate status | up [N] | down [N] | force VERSION
+*/
+
+//go:embed 148_migrations/*.sql
+var embeddedMigrations embed.FS
+
+// Load, the Migrator and its status/up/down/force commands are
+// pkg/migrate; the in-memory database is pkg/migrate/memdb.
+
+// reload gives a Migrator the files in fsys's m/ directory.
+func reload(ctx context.Context, db *sql.DB, fsys fstest.MapFS) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(fsys, "m")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(ctx, db, migrations)
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: A SQL MIGRATION RUNNER")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	db, err := sql.Open("memdb", "lessons")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	migrations, err := migrate.Load(embeddedMigrations, "148_migrations")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	m, err := migrate.New(ctx, db, migrations)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	step := func(title string, args ...string) {
+		fmt.Printf("$ migrate %s   # %s\n", strings.Join(args, " "), title)
+		if err := m.Command(ctx, args); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+		}
+		fmt.Printf("  tables: %v\n\n", memdb.Tables("lessons"))
+	}
+
+	fmt.Println("--- Example 1: Fresh Database ---")
+	step("nothing applied yet", "status")
+	step("apply everything", "up")
+	step("idempotent: nothing to do", "up")
+
+	fmt.Println("--- Example 2: Rolling Back ---")
+	step("revert the latest two", "down", "2")
+	step("re-apply one", "up", "1")
+
+	fmt.Println("--- Example 3: A Failing Migration Leaves a Dirty Marker ---")
+	broken := fstest.MapFS{}
+	for _, mig := range migrations {
+		broken[fmt.Sprintf("m/%04d_%s.up.sql", mig.Version, mig.Name)] = &fstest.MapFile{Data: []byte(mig.Up)}
+		broken[fmt.Sprintf("m/%04d_%s.down.sql", mig.Version, mig.Name)] = &fstest.MapFile{Data: []byte(mig.Down)}
+	}
+	broken["m/0004_add_scores.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE scores (id INTEGER);\nALTR TABLE scores ADD COLUMN points INTEGER;")}
+	if m, err = reload(ctx, db, broken); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	step("0004 has a typo", "up")
+	step("look: 0004 is DIRTY", "status")
+	step("runner refuses to continue", "up")
+	fmt.Println("  (A human fixes 0004, checks the schema: scores was rolled back.)")
+	broken["m/0004_add_scores.up.sql"].Data = []byte("CREATE TABLE scores (id INTEGER, points INTEGER);")
+	if m, err = reload(ctx, db, broken); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	step("record that only 0001..0003 are in effect", "force", "3")
+	step("now it works", "up")
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Number migrations; apply in order; record each in schema_migrations.
+2. Mark DIRTY before running, CLEAN after. Refuse to run while dirty.
+3. Wrap each migration in a transaction where the database supports it.
+4. "force" only edits bookkeeping — use it after a human has checked.
+5. Embed migrations so binary and schema can never drift apart.
+6. Never edit an applied migration; add a new one instead.
+	`)
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+TOPIC: TRANSACTIONS DONE RIGHT — WithTx AND RETRY ON SERIALIZATION FAILURES
+
+CONCEPT:
+Moving money between two accounts is TWO writes. Without a transaction,
+two concurrent transfers can interleave like this:
+
+    transfer #1                         transfer #2
+    read  alice = 100
+                                        read  alice = 100
+    write alice = 100 - 10 = 90
+                                        write alice = 100 - 25 = 75   ✗
+    → alice paid 35, but lost only 25. Money appeared out of nowhere.
+
+This is a LOST UPDATE. The fix is a transaction at a strict isolation level
+(SERIALIZABLE). The database then detects the conflict and ABORTS one
+transaction with a "serialization failure" (SQLSTATE 40001).
+
+An aborted transaction is not a bug — it is the database saying "try again".
+Because ROLLBACK undid everything, retrying the WHOLE function is safe.
+
+THE HELPER:
+    err := WithTx(ctx, db, func(tx *sql.Tx) error {
+        ... reads and writes ...
+        return nil   // commit
+    })
+
+WithTx guarantees:
+  1. BEGIN, then COMMIT if fn returns nil.
+  2. ROLLBACK if fn returns an error.
+  3. ROLLBACK if fn PANICS (then re-panic — never swallow a panic).
+  4. RETRY with backoff when the error is retryable, i.e. a *DatabaseError
+     whose CanRetry() reports true (serialization failure, deadlock).
+
+CanRetry here is based on the error CODE. Compare Topic 69, where CanRetry
+looked at the operation: a single write is unsafe to repeat, but a whole
+rolled-back transaction is always safe to repeat.
+
+ABOUT THE DATABASE:
+Part 4 registers a tiny in-memory driver with optimistic concurrency control
+so this file runs with plain "go run". WithTx only uses database/sql.
+*/
+
+// ---------------------------------------------------------
+// Part 1: DatabaseError (extends the Topic 69 type with a SQLSTATE code)
+// ---------------------------------------------------------
+
+type DatabaseError struct {
+	Operation string // SELECT, UPDATE, COMMIT...
+	Table     string
+	Code      string // SQLSTATE, e.g. 40001
+	Inner     error
+}
+
+func (d *DatabaseError) Error() string {
+	return fmt.Sprintf("Database Error: %s on table '%s' [%s], caused by: %v", d.Operation, d.Table, d.Code, d.Inner)
+}
+
+func (d *DatabaseError) Unwrap() error { return d.Inner }
+
+// CanRetry: 40001 serialization_failure and 40P01 deadlock_detected mean
+// "the transaction was rolled back, run it again".
+func (d *DatabaseError) CanRetry() bool {
+	return d.Code == "40001" || d.Code == "40P01"
+}
+
+// ---------------------------------------------------------
+// Part 2: WithTx
+// ---------------------------------------------------------
+
+type TxOptions struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	OnRetry     func(attempt int, err error)
+}
+
+var defaultTxOptions = TxOptions{MaxAttempts: 5, BaseDelay: time.Millisecond}
+
+// WithTx runs fn inside a serializable transaction and retries it on
+// retryable database errors.
+func WithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	return WithTxOptions(ctx, db, defaultTxOptions, fn)
+}
+
+func WithTxOptions(ctx context.Context, db *sql.DB, opts TxOptions, fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
+		err = runTx(ctx, db, fn)
+
+		var dbErr *DatabaseError
+		if err == nil || !errors.As(err, &dbErr) || !dbErr.CanRetry() {
+			return err
+		}
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, err)
+		}
+		// Exponential backoff with jitter so the conflicting transactions
+		// don't collide again in lockstep.
+		delay := opts.BaseDelay << (attempt - 1)
+		delay += time.Duration(rand.Int63n(int64(delay) + 1))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("transaction failed after %d attempts: %w", opts.MaxAttempts, err)
+}
+
+func runTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after cleanup: the caller decides what a panic means
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback also failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// ---------------------------------------------------------
+// Part 3: Transfers — Naive vs WithTx
+// ---------------------------------------------------------
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func move(ctx context.Context, q querier, from, to string, amount int) error {
+	var fromBal, toBal int
+	if err := q.QueryRowContext(ctx, `SELECT balance FROM accounts WHERE id = ?`, from).Scan(&fromBal); err != nil {
+		return err
+	}
+	if err := q.QueryRowContext(ctx, `SELECT balance FROM accounts WHERE id = ?`, to).Scan(&toBal); err != nil {
+		return err
+	}
+	if fromBal < amount {
+		return ErrInsufficientFunds // Business error: NOT retried
+	}
+	time.Sleep(50 * time.Microsecond) // Real work between read and write widens the race window
+	if _, err := q.ExecContext(ctx, `UPDATE accounts SET balance = ? WHERE id = ?`, fromBal-amount, from); err != nil {
+		return err
+	}
+	_, err := q.ExecContext(ctx, `UPDATE accounts SET balance = ? WHERE id = ?`, toBal+amount, to)
+	return err
+}
+
+// TransferNaive uses autocommit statements: each one is its own transaction.
+func TransferNaive(ctx context.Context, db *sql.DB, from, to string, amount int) error {
+	return move(ctx, db, from, to, amount)
+}
+
+func Transfer(ctx context.Context, db *sql.DB, opts TxOptions, from, to string, amount int) error {
+	return WithTxOptions(ctx, db, opts, func(tx *sql.Tx) error {
+		return move(ctx, tx, from, to, amount)
+	})
+}
+
+// ---------------------------------------------------------
+// Part 4: A Toy Bank Driver With Optimistic Concurrency
+// ---------------------------------------------------------
+// Each row has a version. A transaction remembers the versions it read and
+// buffers its writes. At COMMIT, if any row it read has changed, the commit
+// fails with SQLSTATE 40001 — exactly what a SERIALIZABLE database does.
+
+type bank struct {
+	mu       sync.Mutex
+	balances map[string]int
+	versions map[string]int
+}
+
+type bankDriver struct{ b *bank }
+
+func (d bankDriver) Open(string) (driver.Conn, error) { return &bankConn{b: d.b}, nil }
+
+type bankConn struct {
+	b      *bank
+	inTx   bool
+	reads  map[string]int
+	writes map[string]int
+}
+
+func (c *bankConn) Prepare(q string) (driver.Stmt, error) { return &bankStmt{c, q}, nil }
+func (c *bankConn) Close() error                          { return nil }
+func (c *bankConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *bankConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.inTx, c.reads, c.writes = true, map[string]int{}, map[string]int{}
+	return c, nil
+}
+
+func (c *bankConn) Commit() error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	defer func() { c.inTx = false }()
+	for id, v := range c.reads {
+		if c.b.versions[id] != v {
+			return &DatabaseError{Operation: "COMMIT", Table: "accounts", Code: "40001",
+				Inner: errors.New("could not serialize access due to concurrent update")}
+		}
+	}
+	for id, bal := range c.writes {
+		c.b.balances[id] = bal
+		c.b.versions[id]++
+	}
+	return nil
+}
+
+func (c *bankConn) Rollback() error { c.inTx = false; return nil }
+
+type bankStmt struct {
+	c *bankConn
+	q string
+}
+
+func (s *bankStmt) Close() error  { return nil }
+func (s *bankStmt) NumInput() int { return -1 }
+
+var (
+	selectBalance = regexp.MustCompile(`^SELECT balance FROM accounts WHERE id = \?$`)
+	updateBalance = regexp.MustCompile(`^UPDATE accounts SET balance = \? WHERE id = \?$`)
+)
+
+func (s *bankStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !updateBalance.MatchString(s.q) {
+		return nil, fmt.Errorf("unsupported statement %q", s.q)
+	}
+	bal, id := int(args[0].(int64)), args[1].(string)
+	if s.c.inTx {
+		s.c.writes[id] = bal
+		return driver.RowsAffected(1), nil
+	}
+	s.c.b.mu.Lock()
+	defer s.c.b.mu.Unlock()
+	s.c.b.balances[id] = bal
+	s.c.b.versions[id]++
+	return driver.RowsAffected(1), nil
+}
+
+func (s *bankStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !selectBalance.MatchString(s.q) {
+		return nil, fmt.Errorf("unsupported query %q", s.q)
+	}
+	id := args[0].(string)
+	if s.c.inTx {
+		if bal, ok := s.c.writes[id]; ok {
+			return &bankRows{val: int64(bal)}, nil
+		}
+	}
+	s.c.b.mu.Lock()
+	defer s.c.b.mu.Unlock()
+	bal, ok := s.c.b.balances[id]
+	if !ok {
+		return &bankRows{done: true}, nil
+	}
+	if s.c.inTx {
+		if _, seen := s.c.reads[id]; !seen {
+			s.c.reads[id] = s.c.b.versions[id]
+		}
+	}
+	return &bankRows{val: int64(bal)}, nil
+}
+
+type bankRows struct {
+	val  int64
+	done bool
+}
+
+func (r *bankRows) Columns() []string { return []string{"balance"} }
+func (r *bankRows) Close() error      { return nil }
+func (r *bankRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0], r.done = r.val, true
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+func openBank(name string) (*sql.DB, *bank) {
+	b := &bank{
+		balances: map[string]int{"alice": 1000, "bob": 1000, "carol": 1000},
+		versions: map[string]int{},
+	}
+	sql.Register(name, bankDriver{b})
+	db, _ := sql.Open(name, "")
+	db.SetMaxOpenConns(8)
+	return db, b
+}
+
+func (b *bank) total() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sum := 0
+	for _, v := range b.balances {
+		sum += v
+	}
+	return sum
+}
+
+func hammer(transfer func(from, to string, amount int) error) (failed int) {
+	people := []string{"alice", "bob", "carol"}
+	var wg sync.WaitGroup
+	var fails atomic.Int64
+	for i := 0; i < 300; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			from, to := people[i%3], people[(i+1)%3]
+			if err := transfer(from, to, 1+i%7); err != nil && !errors.Is(err, ErrInsufficientFunds) {
+				fails.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return int(fails.Load())
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: TRANSACTIONS AND RETRY ON SERIALIZATION FAILURES")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+	ctx := context.Background()
+
+	fmt.Println("--- Example 1: Naive Transfers (no transaction) ---")
+	db1, bank1 := openBank("bank-naive")
+	defer db1.Close()
+	hammer(func(from, to string, amount int) error { return TransferNaive(ctx, db1, from, to, amount) })
+	fmt.Printf("  Total money before: 3000, after 300 concurrent transfers: %d\n", bank1.total())
+	fmt.Println("  → Transfers only MOVE money; any difference is a lost update.")
+
+	fmt.Println("\n--- Example 2: Transfers With WithTx + Retry ---")
+	db2, bank2 := openBank("bank-tx")
+	defer db2.Close()
+	var retries atomic.Int64
+	opts := defaultTxOptions
+	opts.MaxAttempts = 50
+	opts.OnRetry = func(int, error) { retries.Add(1) }
+	failed := hammer(func(from, to string, amount int) error { return Transfer(ctx, db2, opts, from, to, amount) })
+	fmt.Printf("  Total money after: %d (conflicts retried: %d, gave up: %d)\n", bank2.total(), retries.Load(), failed)
+
+	fmt.Println("\n--- Example 3: Business Errors Are NOT Retried ---")
+	attempts := 0
+	err := WithTx(ctx, db2, func(tx *sql.Tx) error {
+		attempts++
+		return move(ctx, tx, "alice", "bob", 1_000_000)
+	})
+	fmt.Printf("  err = %v after %d attempt(s)\n", err, attempts)
+
+	fmt.Println("\n--- Example 4: A Panic Rolls Back, Then Propagates ---")
+	before := bank2.total()
+	func() {
+		defer func() { fmt.Printf("  recovered in caller: %v\n", recover()) }()
+		WithTx(ctx, db2, func(tx *sql.Tx) error {
+			tx.ExecContext(ctx, `UPDATE accounts SET balance = ? WHERE id = ?`, 0, "alice")
+			panic("bug in transfer code")
+		})
+	}()
+	fmt.Printf("  Total unchanged by the half-done transaction: %v\n", bank2.total() == before)
+
+	fmt.Println("\n--- Example 5: Seeing a Single Retry ---")
+	opts.OnRetry = func(attempt int, err error) {
+		fmt.Printf("  attempt %d failed: %v\n", attempt, err)
+	}
+	first := true
+	err = WithTxOptions(ctx, db2, opts, func(tx *sql.Tx) error {
+		if err := move(ctx, tx, "bob", "carol", 5); err != nil {
+			return err
+		}
+		if first { // Someone else sneaks in a write to bob before we commit
+			first = false
+			db2.ExecContext(ctx, `UPDATE accounts SET balance = ? WHERE id = ?`, 999, "bob")
+		}
+		return nil
+	})
+	fmt.Printf("  final result: %v\n", err)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Read-modify-write without a transaction loses updates under concurrency.
+2. SERIALIZABLE turns races into errors (40001) instead of wrong data.
+3. Retry the WHOLE transaction function, never a single statement.
+4. Classify errors: retry serialization/deadlock, never business errors.
+5. Roll back on error AND on panic; re-panic after cleanup.
+6. Keep transaction functions free of side effects (emails, HTTP calls):
+   they may run more than once.
+	`)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+TOPIC: A SAFE SQL QUERY BUILDER (AND WHY Sprintf IS DANGEROUS)
+
+CONCEPT:
+SQL INJECTION happens when user input is pasted INTO the SQL text:
+
+    q := fmt.Sprintf("SELECT * FROM users WHERE name = '%s'", name)
+
+With  name = x' OR '1'='1  the query becomes
+
+    SELECT * FROM users WHERE name = 'x' OR '1'='1'     ← matches EVERY row
+
+The database cannot tell your SQL apart from the attacker's. The fix is
+PARAMETER BINDING: the SQL text contains placeholders, and the values travel
+separately. The database never parses the values as SQL.
+
+    db.Query("SELECT * FROM users WHERE name = ?", name)   ✓
+
+A query builder makes the safe way the EASY way:
+
+    q, args, err := Select("id", "name").
+        From("users").
+        Where("age > ?", 30).
+        Where("country = ?", country).
+        OrderBy("name").
+        Limit(10).
+        ToSQL()
+
+    q    = "SELECT id, name FROM users WHERE age > ? AND country = ? ORDER BY name LIMIT 10"
+    args = [30 "NL"]
+
+WHAT BINDING CANNOT DO:
+Placeholders work only for VALUES. Table and column names are part of the SQL
+text, so the builder VALIDATES identifiers against a strict pattern and
+rejects anything else. (Topic 147 goes further with allow-lists.)
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Builder
+// ---------------------------------------------------------
+
+// Placeholder style differs per database: MySQL/SQLite use "?", Postgres "$1".
+type Placeholder int
+
+const (
+	Question Placeholder = iota // ?
+	Dollar                      // $1, $2, ...
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+type SelectBuilder struct {
+	columns []string
+	table   string
+	wheres  []string
+	args    []any
+	orderBy []string
+	limit   int
+	style   Placeholder
+	err     error // First error; reported by ToSQL so calls can be chained
+}
+
+func Select(columns ...string) *SelectBuilder {
+	b := &SelectBuilder{}
+	for _, c := range columns {
+		b.checkIdent(c)
+	}
+	b.columns = columns
+	return b
+}
+
+func (b *SelectBuilder) checkIdent(name string) {
+	if b.err == nil && name != "*" && !identifier.MatchString(name) {
+		b.err = fmt.Errorf("invalid identifier %q", name)
+	}
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.checkIdent(table)
+	b.table = table
+	return b
+}
+
+// Where adds a condition joined with AND. The expression is TRUSTED code
+// written by the programmer; every value must go through args.
+func (b *SelectBuilder) Where(expr string, args ...any) *SelectBuilder {
+	if n := strings.Count(expr, "?"); n != len(args) && b.err == nil {
+		b.err = fmt.Errorf("where %q: %d placeholder(s) but %d arg(s)", expr, n, len(args))
+	}
+	b.wheres = append(b.wheres, expr)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// OrderBy accepts "col" or "col DESC".
+func (b *SelectBuilder) OrderBy(terms ...string) *SelectBuilder {
+	for _, t := range terms {
+		col, dir, _ := strings.Cut(t, " ")
+		b.checkIdent(col)
+		if dir != "" && dir != "ASC" && dir != "DESC" && b.err == nil {
+			b.err = fmt.Errorf("invalid sort direction %q", dir)
+		}
+	}
+	b.orderBy = append(b.orderBy, terms...)
+	return b
+}
+
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+func (b *SelectBuilder) PlaceholderFormat(p Placeholder) *SelectBuilder {
+	b.style = p
+	return b
+}
+
+// ToSQL renders the query text and its arguments.
+func (b *SelectBuilder) ToSQL() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if b.table == "" {
+		return "", nil, errors.New("select: missing From")
+	}
+	cols := "*"
+	if len(b.columns) > 0 {
+		cols = strings.Join(b.columns, ", ")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT " + cols + " FROM " + b.table)
+	if len(b.wheres) > 0 {
+		parts := make([]string, len(b.wheres))
+		for i, w := range b.wheres {
+			parts[i] = w
+			if len(b.wheres) > 1 && strings.Contains(strings.ToUpper(w), " OR ") {
+				parts[i] = "(" + w + ")" // Keep "a OR b" from leaking into the AND chain
+			}
+		}
+		sb.WriteString(" WHERE " + strings.Join(parts, " AND "))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	if b.limit > 0 {
+		sb.WriteString(" LIMIT " + strconv.Itoa(b.limit)) // An int we own: safe to format
+	}
+
+	sql := sb.String()
+	if b.style == Dollar {
+		sql = toDollar(sql)
+	}
+	return sql, b.args, nil
+}
+
+// toDollar rewrites ? as $1, $2, ... (skipping ? inside quoted literals).
+func toDollar(sql string) string {
+	var sb strings.Builder
+	n, inQuote := 0, false
+	for _, r := range sql {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+			sb.WriteRune(r)
+		case r == '?' && !inQuote:
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ---------------------------------------------------------
+// Part 2: The Attack, Simulated
+// ---------------------------------------------------------
+// A tiny "database" that evaluates  name = '<literal>' [OR '<a>'='<b>']
+// just well enough to show what each query would return.
+
+var users = []string{"alice", "bob", "carol"}
+
+var naiveWhere = regexp.MustCompile(`name = '([^']*)'(?: OR '([^']*)'='([^']*)')?`)
+
+func runRawSQL(sql string) []string {
+	m := naiveWhere.FindStringSubmatch(sql)
+	if m == nil {
+		return nil
+	}
+	var out []string
+	for _, u := range users {
+		if u == m[1] || (m[2] != "" && m[2] == m[3]) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func runBound(sql string, args []any) []string {
+	var out []string
+	for _, u := range users {
+		if len(args) == 1 && u == args[0] { // The value is compared, never parsed
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: A SAFE SQL QUERY BUILDER")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	attack := "x' OR '1'='1"
+
+	fmt.Println("--- Example 1: Injection Against Sprintf ---")
+	naive := fmt.Sprintf("SELECT id FROM users WHERE name = '%s'", attack)
+	fmt.Printf("  input: %s\n  SQL:   %s\n  rows:  %v  ✗ every user leaked\n\n", attack, naive, runRawSQL(naive))
+
+	fmt.Println("--- Example 2: The Same Input Through the Builder ---")
+	q, args, err := Select("id").From("users").Where("name = ?", attack).ToSQL()
+	fmt.Printf("  SQL:   %s\n  args:  %q\n  rows:  %v  ✓ no user has that odd name\n  err:   %v\n\n", q, args, runBound(q, args), err)
+
+	fmt.Println("--- Example 3: A Realistic Query, Two Dialects ---")
+	b := Select("u.id", "u.name").
+		From("users").
+		Where("age > ?", 30).
+		Where("country = ? OR country = ?", "NL", "BE").
+		OrderBy("u.name", "u.id DESC").
+		Limit(10)
+	q, args, _ = b.ToSQL()
+	fmt.Printf("  ?  style: %s\n", q)
+	q, _, _ = b.PlaceholderFormat(Dollar).ToSQL()
+	fmt.Printf("  $n style: %s\n  args:     %v\n\n", q, args)
+
+	fmt.Println("--- Example 4: The Builder Refuses Unsafe Identifiers ---")
+	bad := []*SelectBuilder{
+		Select("id").From("users; DROP TABLE users"),
+		Select("id").From("users").OrderBy("name; DELETE FROM users"),
+		Select("id").From("users").OrderBy("name SIDEWAYS"),
+		Select("id").From("users").Where("name = ? AND age = ?", "bob"),
+	}
+	for _, sb := range bad {
+		_, _, err := sb.ToSQL()
+		fmt.Printf("  ✗ %v\n", err)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. NEVER build SQL with Sprintf or + from user input.
+2. Values → placeholders (? or $1) + args. The driver sends them separately.
+3. Identifiers can't be bound: validate them or pick from an allow-list.
+4. A builder should carry errors to ToSQL() so chains stay readable.
+5. Wrap OR conditions in parentheses when combining with AND.
+	`)
+}
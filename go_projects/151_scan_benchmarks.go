@@ -0,0 +1,340 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+/*
+TOPIC: DATA ACCESS BENCHMARKS — rows.Scan vs REFLECTION vs GENERATED SCANNERS
+
+CONCEPT:
+Turning SQL rows into Go structs can be done three ways:
+
+ 1. MANUAL rows.Scan — you list every field by hand.
+        rows.Scan(&l.ID, &l.Title, &l.Level, &l.Minutes)
+    Fastest, but tedious and easy to get out of order.
+
+ 2. REFLECTION mapping (what sqlx does) — read `db:"..."` tags, match them to
+    rows.Columns(), and build the Scan destinations with reflect (Topic 128).
+        lessons, err := ScanAll[Lesson](rows)
+    Convenient; costs a little per row.
+
+ 3. GENERATED scanners — a tool uses reflection ONCE, at build time, to WRITE
+    the manual code for you (like sqlc or easyjson).
+        go run 151_scan_benchmarks.go gen   → prints scanLessonGen
+    Fast AND convenient; needs a generation step.
+
+We measure all three with testing.Benchmark, which runs a benchmark from a
+normal program (no _test.go needed; see Topic 125 for `go test -bench`).
+
+RUN:
+    go run 151_scan_benchmarks.go         → benchmarks + guidance
+    go run 151_scan_benchmarks.go gen     → print the generated scanner
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Model
+// ---------------------------------------------------------
+
+type Lesson struct {
+	ID        int64   `db:"id"`
+	Title     string  `db:"title"`
+	Level     string  `db:"level"`
+	Minutes   int64   `db:"minutes"`
+	Rating    float64 `db:"rating"`
+	Published bool    `db:"published"`
+}
+
+// ---------------------------------------------------------
+// Part 2: Strategy 1 — Manual Scan
+// ---------------------------------------------------------
+
+func scanManual(rows *sql.Rows) ([]Lesson, error) {
+	var out []Lesson
+	for rows.Next() {
+		var l Lesson
+		if err := rows.Scan(&l.ID, &l.Title, &l.Level, &l.Minutes, &l.Rating, &l.Published); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// ---------------------------------------------------------
+// Part 3: Strategy 2 — Reflection Mapping (sqlx-style)
+// ---------------------------------------------------------
+
+// fieldIndexes maps each result column to a struct field index via `db` tags.
+func fieldIndexes(t reflect.Type, columns []string) ([]int, error) {
+	byTag := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("db"); tag != "" && tag != "-" {
+			byTag[tag] = i
+		}
+	}
+	idx := make([]int, len(columns))
+	for i, c := range columns {
+		f, ok := byTag[c]
+		if !ok {
+			return nil, fmt.Errorf("column %q has no matching field in %s", c, t)
+		}
+		idx[i] = f
+	}
+	return idx, nil
+}
+
+// mappingCache remembers the column→field plan per (type, columns), so the
+// tag parsing happens once per query shape instead of once per call.
+var mappingCache sync.Map // key: string → []int
+
+func cachedIndexes(t reflect.Type, columns []string) ([]int, error) {
+	key := t.String() + "|" + strings.Join(columns, ",")
+	if v, ok := mappingCache.Load(key); ok {
+		return v.([]int), nil
+	}
+	idx, err := fieldIndexes(t, columns)
+	if err != nil {
+		return nil, err
+	}
+	mappingCache.Store(key, idx)
+	return idx, nil
+}
+
+// ScanAll maps every row into a T using `db` struct tags.
+func ScanAll[T any](rows *sql.Rows) ([]T, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := cachedIndexes(reflect.TypeOf((*T)(nil)).Elem(), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []T
+	dest := make([]any, len(columns))
+	for rows.Next() {
+		var item T
+		v := reflect.ValueOf(&item).Elem()
+		for i, f := range idx {
+			dest[i] = v.Field(f).Addr().Interface() // The per-row reflection cost
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+// ---------------------------------------------------------
+// Part 4: Strategy 3 — A Generated Scanner
+// ---------------------------------------------------------
+// genScanner uses reflection at "build time" to WRITE Strategy 1 for us.
+// Its output for Lesson is pasted below as scanLessonGen.
+
+func genScanner(t reflect.Type) string {
+	var targets []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("db"); tag != "" && tag != "-" {
+			targets = append(targets, "&v."+t.Field(i).Name)
+		}
+	}
+	name := t.Name()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by genScanner from %s; DO NOT EDIT.\n\n", name)
+	fmt.Fprintf(&sb, "func scan%sGen(rows *sql.Rows) ([]%s, error) {\n", name, name)
+	fmt.Fprintf(&sb, "\tvar out []%s\n\tfor rows.Next() {\n\t\tvar v %s\n", name, name)
+	fmt.Fprintf(&sb, "\t\tif err := rows.Scan(%s); err != nil {\n", strings.Join(targets, ", "))
+	fmt.Fprintf(&sb, "\t\t\treturn nil, err\n\t\t}\n\t\tout = append(out, v)\n\t}\n\treturn out, rows.Err()\n}\n")
+	return sb.String()
+}
+
+// Output of `go run 151_scan_benchmarks.go gen`, pasted in. (In a real project
+// it would live in its own lesson_scan.go carrying the "Code generated" header,
+// which tells linters and reviewers to skip the file.)
+
+func scanLessonGen(rows *sql.Rows) ([]Lesson, error) {
+	var out []Lesson
+	for rows.Next() {
+		var v Lesson
+		if err := rows.Scan(&v.ID, &v.Title, &v.Level, &v.Minutes, &v.Rating, &v.Published); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// ---------------------------------------------------------
+// Part 5: A Table Driver That Streams N Rows
+// ---------------------------------------------------------
+// No network, no disk: what we measure is database/sql + our mapping code.
+// The DSN is the row count, e.g. sql.Open("gotutrows", "1000").
+
+type rowsDriver struct{}
+
+func (rowsDriver) Open(dsn string) (driver.Conn, error) {
+	var n int
+	if _, err := fmt.Sscan(dsn, &n); err != nil {
+		return nil, err
+	}
+	return &rowsConn{n: n}, nil
+}
+
+type rowsConn struct{ n int }
+
+func (c *rowsConn) Prepare(q string) (driver.Stmt, error) { return &rowsStmt{c}, nil }
+func (c *rowsConn) Close() error                          { return nil }
+func (c *rowsConn) Begin() (driver.Tx, error)             { return nil, driver.ErrSkip }
+
+type rowsStmt struct{ c *rowsConn }
+
+func (s *rowsStmt) Close() error                               { return nil }
+func (s *rowsStmt) NumInput() int                              { return -1 }
+func (s *rowsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s *rowsStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &lessonRows{n: s.c.n}, nil
+}
+
+var titles = []string{"Goroutines", "Channels", "Generics", "Reflection"}
+
+type lessonRows struct{ i, n int }
+
+func (r *lessonRows) Columns() []string {
+	return []string{"id", "title", "level", "minutes", "rating", "published"}
+}
+func (r *lessonRows) Close() error { return nil }
+func (r *lessonRows) Next(dest []driver.Value) error {
+	if r.i == r.n {
+		return io.EOF
+	}
+	r.i++
+	dest[0] = int64(r.i)
+	dest[1] = titles[r.i%len(titles)]
+	dest[2] = "intermediate"
+	dest[3] = int64(10 + r.i%50)
+	dest[4] = 4.5
+	dest[5] = r.i%2 == 0
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 6: Benchmarks
+// ---------------------------------------------------------
+
+const rowsPerQuery = 1000
+
+func bench(db *sql.DB, scan func(*sql.Rows) ([]Lesson, error)) testing.BenchmarkResult {
+	return testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rows, err := db.Query("SELECT id, title, level, minutes, rating, published FROM lessons")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := scan(rows); err != nil {
+				b.Fatal(err)
+			}
+			rows.Close()
+		}
+	})
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "gen" {
+		fmt.Print(genScanner(reflect.TypeOf(Lesson{})))
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: rows.Scan vs REFLECTION vs GENERATED SCANNERS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	sql.Register("gotutrows", rowsDriver{})
+	db, err := sql.Open("gotutrows", fmt.Sprint(rowsPerQuery))
+	if err != nil {
+		fmt.Println("open:", err)
+		return
+	}
+	defer db.Close()
+
+	strategies := []struct {
+		name string
+		scan func(*sql.Rows) ([]Lesson, error)
+	}{
+		{"manual rows.Scan", scanManual},
+		{"reflection (ScanAll)", ScanAll[Lesson]},
+		{"generated scanner", scanLessonGen},
+	}
+
+	fmt.Println("--- Example 1: All Strategies Agree ---")
+	var first []Lesson
+	for _, s := range strategies {
+		rows, _ := db.Query("SELECT * FROM lessons")
+		got, err := s.scan(rows)
+		rows.Close()
+		switch {
+		case err != nil:
+			fmt.Printf("  ✗ %-22s %v\n", s.name, err)
+		case first == nil:
+			first = got
+			fmt.Printf("  ✓ %-22s %d rows, row[1] = %+v\n", s.name, len(got), got[1])
+		case reflect.DeepEqual(first, got):
+			fmt.Printf("  ✓ %-22s identical result\n", s.name)
+		default:
+			fmt.Printf("  ✗ %-22s results differ\n", s.name)
+		}
+	}
+
+	fmt.Println("\n--- Example 2: Reflection Catches Mismatched Columns ---")
+	type Wrong struct {
+		ID int64 `db:"id"`
+	}
+	rows, _ := db.Query("SELECT * FROM lessons")
+	_, err = ScanAll[Wrong](rows)
+	rows.Close()
+	fmt.Println("  ScanAll[Wrong]:", err)
+
+	fmt.Printf("\n--- Example 3: Benchmarks (%d rows per query, ~1s each) ---\n", rowsPerQuery)
+	results := make([]testing.BenchmarkResult, len(strategies))
+	for i, s := range strategies {
+		results[i] = bench(db, s.scan)
+		r := results[i]
+		perRow := float64(r.NsPerOp()) / rowsPerQuery
+		fmt.Printf("  %-22s %8.1f ns/row  %6.2f allocs/row  %7.1f B/row\n",
+			s.name, perRow, float64(r.AllocsPerOp())/rowsPerQuery, float64(r.AllocedBytesPerOp())/rowsPerQuery)
+	}
+	base := float64(results[0].NsPerOp())
+	fmt.Printf("\n  reflection overhead vs manual: %+.0f%%\n", (float64(results[1].NsPerOp())/base-1)*100)
+
+	fmt.Println("\n--- Example 4: When Does the Overhead Matter? ---")
+	overheadPerRow := float64(results[1].NsPerOp()-results[0].NsPerOp()) / rowsPerQuery
+	for _, n := range []int{1, 100, 10_000, 1_000_000} {
+		extra := overheadPerRow * float64(n) / 1e6 // milliseconds
+		fmt.Printf("  %9d rows → reflection adds ≈ %8.3f ms  (a real DB round trip is ~0.5–5 ms)\n", n, extra)
+	}
+	fmt.Println("  Rule of thumb: for typical API queries (≤ 1000 rows) the network")
+	fmt.Println("  dominates. Reach for generated scanners in batch jobs and exports.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Manual rows.Scan is fastest but fragile: column order must match by hand.
+2. Reflection mapping is convenient; CACHE the column→field plan per query shape.
+3. The remaining reflection cost is per row (Addr().Interface() per field).
+4. Generated code gives manual speed without manual typing.
+5. Measure with testing.Benchmark / go test -bench before optimizing.
+	`)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// Part 1: The Skip List (an ordered in-memory index)
+// ---------------------------------------------------------
+// A skip list is a sorted linked list with "express lanes". Each node gets a
+// random height; level 0 links every node, level 1 about half of them, level 2
+// a quarter... Searching starts in the top lane and drops down, so Get, Put
+// and Delete are O(log n) on average — like a balanced tree, with far simpler
+// code and no rebalancing.
+//
+//   level 2:  head ─────────────────────► "go" ─────────────────► nil
+//   level 1:  head ───────► "chan" ─────► "go" ──────► "map" ───► nil
+//   level 0:  head ► "api" ► "chan" ► "ctx" ► "go" ► "io" ► "map" ► nil
+
+const maxLevel = 24 // Enough for ~16M keys at p = 1/2
+
+type node struct {
+	key   string
+	value []byte
+	next  []*node // next[i] is the successor in lane i
+}
+
+// SkipList maps string keys to byte values in sorted key order.
+// It is not safe for concurrent use; Store adds the locking.
+type SkipList struct {
+	head  *node
+	level int // Number of lanes currently in use
+	n     int
+	rng   *rand.Rand
+}
+
+func NewSkipList() *SkipList {
+	return &SkipList{
+		head:  &node{next: make([]*node, maxLevel)},
+		level: 1,
+		rng:   rand.New(rand.NewPCG(1, 2)), // Fixed seed: the shape is reproducible in tests
+	}
+}
+
+func (s *SkipList) randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && s.rng.IntN(2) == 0 {
+		lvl++
+	}
+	return lvl
+}
+
+// findPredecessors returns, for every lane, the last node whose key < key.
+func (s *SkipList) findPredecessors(key string) [maxLevel]*node {
+	var update [maxLevel]*node
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	return update
+}
+
+func (s *SkipList) Get(key string) ([]byte, bool) {
+	update := s.findPredecessors(key)
+	if x := update[0].next[0]; x != nil && x.key == key {
+		return x.value, true
+	}
+	return nil, false
+}
+
+// Put inserts or replaces a key. The value is stored as given (not copied).
+func (s *SkipList) Put(key string, value []byte) {
+	update := s.findPredecessors(key)
+	if x := update[0].next[0]; x != nil && x.key == key {
+		x.value = value
+		return
+	}
+
+	lvl := s.randomLevel()
+	if lvl > s.level {
+		for i := s.level; i < lvl; i++ {
+			update[i] = s.head
+		}
+		s.level = lvl
+	}
+	x := &node{key: key, value: value, next: make([]*node, lvl)}
+	for i := 0; i < lvl; i++ {
+		x.next[i] = update[i].next[i]
+		update[i].next[i] = x
+	}
+	s.n++
+}
+
+func (s *SkipList) Delete(key string) bool {
+	update := s.findPredecessors(key)
+	x := update[0].next[0]
+	if x == nil || x.key != key {
+		return false
+	}
+	for i := 0; i < len(x.next); i++ {
+		update[i].next[i] = x.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.n--
+	return true
+}
+
+func (s *SkipList) Len() int { return s.n }
+
+// Range calls fn for every key in [start, end) in order; end == "" means no
+// upper bound. Returning false from fn stops the scan early.
+func (s *SkipList) Range(start, end string, fn func(key string, value []byte) bool) {
+	x := s.findPredecessors(start)[0].next[0]
+	for ; x != nil && (end == "" || x.key < end); x = x.next[0] {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// Scan visits every key that starts with prefix. Because keys are sorted,
+// the matches are contiguous: seek to the prefix, stop at the first miss.
+func (s *SkipList) Scan(prefix string, fn func(key string, value []byte) bool) {
+	x := s.findPredecessors(prefix)[0].next[0]
+	for ; x != nil && strings.HasPrefix(x.key, prefix); x = x.next[0] {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 2: Page Serialization With encoding/binary
+// ---------------------------------------------------------
+// On disk the index is a sequence of fixed-size PAGES, like a database file:
+//
+//   page := magic(uint32) | count(uint16) | used(uint16) | records... | zero padding
+//   record := uvarint(len key) | key | uvarint(len value) | value
+//
+// Fixed-size pages mean a reader can jump to page N at offset N*PageSize,
+// and a partially written page is detectable (bad magic).
+
+const (
+	PageSize   = 4096
+	pageMagic  = 0x4B565047 // "KVPG"
+	pageHeader = 8
+)
+
+var (
+	ErrRecordTooLarge = errors.New("kvstore: record does not fit in a page")
+	ErrCorruptPage    = errors.New("kvstore: corrupt page")
+)
+
+// WritePages streams the list to w in sorted order and returns the page count.
+func (s *SkipList) WritePages(w io.Writer) (int, error) {
+	page := make([]byte, PageSize)
+	used, count, pages := pageHeader, 0, 0
+	var scratch [binary.MaxVarintLen64]byte
+
+	flush := func() error {
+		binary.LittleEndian.PutUint32(page[0:], pageMagic)
+		binary.LittleEndian.PutUint16(page[4:], uint16(count))
+		binary.LittleEndian.PutUint16(page[6:], uint16(used))
+		clear(page[used:]) // Zero padding keeps the file deterministic
+		if _, err := w.Write(page); err != nil {
+			return err
+		}
+		used, count = pageHeader, 0
+		pages++
+		return nil
+	}
+
+	var err error
+	s.Range("", "", func(key string, value []byte) bool {
+		size := binary.PutUvarint(scratch[:], uint64(len(key))) + len(key) +
+			binary.PutUvarint(scratch[:], uint64(len(value))) + len(value)
+		if pageHeader+size > PageSize {
+			err = fmt.Errorf("%w: key %q needs %d bytes", ErrRecordTooLarge, key, size)
+			return false
+		}
+		if used+size > PageSize {
+			if err = flush(); err != nil {
+				return false
+			}
+		}
+		used += binary.PutUvarint(page[used:], uint64(len(key)))
+		used += copy(page[used:], key)
+		used += binary.PutUvarint(page[used:], uint64(len(value)))
+		used += copy(page[used:], value)
+		count++
+		return true
+	})
+	if err != nil {
+		return pages, err
+	}
+	if count > 0 {
+		err = flush()
+	}
+	return pages, err
+}
+
+// ReadPages rebuilds a skip list from pages written by WritePages.
+func ReadPages(r io.Reader) (*SkipList, error) {
+	s := NewSkipList()
+	br := bufio.NewReaderSize(r, PageSize)
+	page := make([]byte, PageSize)
+	for pageNo := 0; ; pageNo++ {
+		if _, err := io.ReadFull(br, page); err == io.EOF {
+			return s, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: page %d: %v", ErrCorruptPage, pageNo, err)
+		}
+		if err := decodePage(page, s); err != nil {
+			return nil, fmt.Errorf("%w: page %d: %v", ErrCorruptPage, pageNo, err)
+		}
+	}
+}
+
+func decodePage(page []byte, s *SkipList) error {
+	if binary.LittleEndian.Uint32(page[0:]) != pageMagic {
+		return errors.New("bad magic")
+	}
+	count := int(binary.LittleEndian.Uint16(page[4:]))
+	used := int(binary.LittleEndian.Uint16(page[6:]))
+	if used < pageHeader || used > PageSize {
+		return fmt.Errorf("bad used size %d", used)
+	}
+	buf := page[pageHeader:used]
+	field := func() ([]byte, error) {
+		n, k := binary.Uvarint(buf)
+		if k <= 0 || n > uint64(len(buf)-k) {
+			return nil, errors.New("truncated record")
+		}
+		b := buf[k : k+int(n)]
+		buf = buf[k+int(n):]
+		return b, nil
+	}
+	for i := 0; i < count; i++ {
+		key, err := field()
+		if err != nil {
+			return err
+		}
+		value, err := field()
+		if err != nil {
+			return err
+		}
+		s.Put(string(key), append([]byte(nil), value...)) // Copy: page is reused
+	}
+	if len(buf) != 0 {
+		return fmt.Errorf("%d trailing bytes", len(buf))
+	}
+	return nil
+}
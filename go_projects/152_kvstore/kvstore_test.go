@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------
+// Table tests for the basic operations
+// ---------------------------------------------------------
+
+func TestSkipListBasics(t *testing.T) {
+	s := NewSkipList()
+	if _, ok := s.Get("missing"); ok {
+		t.Fatal("Get on empty list reported a hit")
+	}
+
+	s.Put("b", []byte("2"))
+	s.Put("a", []byte("1"))
+	s.Put("c", []byte("3"))
+	s.Put("b", []byte("two")) // Replace, not insert
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{"a", "1", true},
+		{"b", "two", true},
+		{"c", "3", true},
+		{"d", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := s.Get(tt.key)
+		if ok != tt.wantOK || string(got) != tt.want {
+			t.Errorf("Get(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len = %d; want 3", s.Len())
+	}
+	if !s.Delete("b") || s.Delete("b") {
+		t.Error("Delete should succeed once and then report false")
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len after delete = %d; want 2", s.Len())
+	}
+}
+
+func TestScanPrefix(t *testing.T) {
+	s := NewSkipList()
+	for _, k := range []string{"user:1:a", "user:1:b", "user:10:a", "user:2:a", "users", "u"} {
+		s.Put(k, nil)
+	}
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"user:1:", []string{"user:1:a", "user:1:b"}},
+		{"user:1", []string{"user:10:a", "user:1:a", "user:1:b"}}, // '0' sorts before ':'
+		{"user", []string{"user:10:a", "user:1:a", "user:1:b", "user:2:a", "users"}},
+		{"", []string{"u", "user:10:a", "user:1:a", "user:1:b", "user:2:a", "users"}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		s.Scan(tt.prefix, func(k string, _ []byte) bool {
+			got = append(got, k)
+			return true
+		})
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Scan(%q) = %v; want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestRangeStopsEarly(t *testing.T) {
+	s := NewSkipList()
+	for i := 0; i < 10; i++ {
+		s.Put(fmt.Sprint(i), nil)
+	}
+	var got []string
+	s.Range("3", "8", func(k string, _ []byte) bool {
+		got = append(got, k)
+		return k != "5"
+	})
+	if want := "[3 4 5]"; fmt.Sprint(got) != want {
+		t.Errorf("Range = %v; want %s", got, want)
+	}
+}
+
+// ---------------------------------------------------------
+// Model-based test: the skip list must behave exactly like a map
+// ---------------------------------------------------------
+
+func checkAgainstModel(t *testing.T, s *SkipList, model map[string]string) {
+	t.Helper()
+	if s.Len() != len(model) {
+		t.Fatalf("Len = %d; model has %d", s.Len(), len(model))
+	}
+	want := make([]string, 0, len(model))
+	for k := range model {
+		want = append(want, k)
+	}
+	sort.Strings(want)
+	var got []string
+	s.Range("", "", func(k string, v []byte) bool {
+		if string(v) != model[k] {
+			t.Errorf("value of %q = %q; model has %q", k, v, model[k])
+		}
+		got = append(got, k)
+		return true
+	})
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("iteration order differs from sorted model\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestRandomOpsMatchModel(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 7))
+	s := NewSkipList()
+	model := map[string]string{}
+	for i := 0; i < 20_000; i++ {
+		k := fmt.Sprintf("k%03d", rng.IntN(300))
+		switch rng.IntN(3) {
+		case 0, 1:
+			v := fmt.Sprint(i)
+			s.Put(k, []byte(v))
+			model[k] = v
+		case 2:
+			_, inModel := model[k]
+			if s.Delete(k) != inModel {
+				t.Fatalf("op %d: Delete(%q) disagrees with model", i, k)
+			}
+			delete(model, k)
+		}
+	}
+	checkAgainstModel(t, s, model)
+}
+
+// ---------------------------------------------------------
+// Page serialization
+// ---------------------------------------------------------
+
+func TestPagesRoundTrip(t *testing.T) {
+	s := NewSkipList()
+	model := map[string]string{}
+	for i := 0; i < 5000; i++ { // Enough to span many pages
+		k, v := fmt.Sprintf("key-%05d", i), strings.Repeat("v", i%50)
+		s.Put(k, []byte(v))
+		model[k] = v
+	}
+	s.Put("empty", nil)
+	model["empty"] = ""
+
+	var buf bytes.Buffer
+	pages, err := s.WritePages(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages < 2 || buf.Len() != pages*PageSize {
+		t.Fatalf("pages = %d, bytes = %d; want several full pages", pages, buf.Len())
+	}
+
+	loaded, err := ReadPages(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAgainstModel(t, loaded, model)
+}
+
+func TestPagesEmptyAndTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	if pages, err := NewSkipList().WritePages(&buf); pages != 0 || err != nil || buf.Len() != 0 {
+		t.Errorf("empty list: pages=%d err=%v len=%d", pages, err, buf.Len())
+	}
+
+	s := NewSkipList()
+	s.Put("big", make([]byte, PageSize))
+	if _, err := s.WritePages(&buf); !errors.Is(err, ErrRecordTooLarge) {
+		t.Errorf("err = %v; want ErrRecordTooLarge", err)
+	}
+}
+
+func TestReadPagesRejectsCorruption(t *testing.T) {
+	s := NewSkipList()
+	s.Put("a", []byte("1"))
+	var good bytes.Buffer
+	s.WritePages(&good)
+
+	tests := []struct {
+		name   string
+		mutate func([]byte) []byte
+	}{
+		{"bad magic", func(b []byte) []byte { b[0] ^= 1; return b }},
+		{"short page", func(b []byte) []byte { return b[:100] }},
+		{"count too high", func(b []byte) []byte { b[4] = 9; return b }},
+		{"used too large", func(b []byte) []byte { b[6], b[7] = 0xFF, 0xFF; return b }},
+		{"used too small", func(b []byte) []byte { b[6], b[7] = 1, 0; return b }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := tt.mutate(bytes.Clone(good.Bytes()))
+			if _, err := ReadPages(bytes.NewReader(page)); !errors.Is(err, ErrCorruptPage) {
+				t.Errorf("err = %v; want ErrCorruptPage", err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------
+// Store
+// ---------------------------------------------------------
+
+func TestStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Put("a:1", []byte("x"))
+	db.Put("a:2", []byte("y"))
+	db.Put("b:1", []byte("z"))
+	db.Delete("a:2")
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db.Scan("a:"); len(got) != 1 || got[0].Key != "a:1" || string(got[0].Value) != "x" {
+		t.Errorf("Scan(a:) after reopen = %v", got)
+	}
+}
+
+func TestStoreReturnsCopies(t *testing.T) {
+	db, _ := Open(t.TempDir())
+	in := []byte("hello")
+	db.Put("k", in)
+	in[0] = 'J' // Caller reuses its buffer
+	out, _ := db.Get("k")
+	out[1] = 'A' // Caller scribbles on the result
+	if again, _ := db.Get("k"); string(again) != "hello" {
+		t.Errorf("stored value changed to %q", again)
+	}
+}
+
+// ---------------------------------------------------------
+// Fuzzing: go test -fuzz=FuzzSkipList
+// ---------------------------------------------------------
+// The fuzzer feeds arbitrary bytes; we decode them into a sequence of
+// operations, apply them to the skip list AND a map, and compare. Then we
+// round-trip through pages and compare again.
+
+func FuzzSkipList(f *testing.F) {
+	f.Add([]byte("\x00a\x00b\x01a\x00c"))
+	f.Add([]byte("\x00\x00\x00\x00\x02"))
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := NewSkipList()
+		model := map[string]string{}
+		for i := 0; i+1 < len(ops); i += 2 {
+			k := string(ops[i+1] % 32) // Small key space → many collisions
+			switch ops[i] % 3 {
+			case 0:
+				s.Put(k, []byte{ops[i]})
+				model[k] = string([]byte{ops[i]})
+			case 1:
+				s.Delete(k)
+				delete(model, k)
+			case 2:
+				var n int
+				s.Scan(k, func(string, []byte) bool { n++; return true })
+				if _, ok := model[k]; ok && n == 0 {
+					t.Fatalf("Scan(%q) missed an existing key", k)
+				}
+			}
+		}
+		checkAgainstModel(t, s, model)
+
+		var buf bytes.Buffer
+		if _, err := s.WritePages(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := ReadPages(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkAgainstModel(t, loaded, model)
+	})
+}
+
+// FuzzReadPages makes sure arbitrary bytes never panic the decoder.
+func FuzzReadPages(f *testing.F) {
+	s := NewSkipList()
+	s.Put("seed", []byte("value"))
+	var buf bytes.Buffer
+	s.WritePages(&buf)
+	f.Add(buf.Bytes())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ReadPages(bytes.NewReader(data)) // Errors are fine; panics are not
+	})
+}
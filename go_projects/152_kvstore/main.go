@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+TOPIC: AN EMBEDDED KEY-VALUE STORE WITH A SORTED INDEX (CAPSTONE)
+
+CONCEPT:
+A hash map answers "what is the value of key K?" in O(1), but it has no
+ORDER. Questions like these need a SORTED index:
+
+    Scan("user:42:")          → every key with that prefix
+    Range("2024-01", "2024-02") → every key in a half-open interval
+
+Databases use B-trees or LSM trees for this. We use a SKIP LIST: same
+O(log n) behaviour, a fraction of the code. To survive restarts the index is
+serialized into fixed-size PAGES with encoding/binary, and every mutation
+is first appended to a WRITE-AHEAD LOG so a crash loses nothing that was
+acknowledged.
+
+FILES IN THIS LESSON (one package, several files — like a real project):
+    index.go       → SkipList + page format (WritePages / ReadPages)
+    store.go       → Store: locking, Scan(prefix), checkpointing Flush
+    wal.go         → write-ahead log with CRC32 records and torn-write recovery
+    snapshot.go    → gzip'd tar snapshots with SHA-256, point-in-time restore
+    main.go        → this walkthrough + the "kv" tool commands
+    kvstore_test.go → table tests, a model-based random test, fuzzing
+    wal_test.go    → crash simulations: truncate and corrupt the log
+
+RUN (this directory is a package, not a single file):
+    cd go_projects/152_kvstore
+    go run .
+    go test -v .
+    go test -fuzz=FuzzSkipList -fuzztime=10s .
+
+TOOL COMMANDS (the "gotut tool kv" subcommands):
+    go run . snapshot DATA_DIR OUT.tar.gz
+    go run . restore [-wal DATA_DIR/wal.log] [-lsn N] SNAPSHOT.tar.gz NEW_DIR
+*/
+
+// runTool implements the snapshot/restore commands.
+func runTool(args []string) error {
+	switch args[0] {
+	case "snapshot":
+		if len(args) != 3 {
+			return errors.New("usage: snapshot DATA_DIR OUT.tar.gz")
+		}
+		if _, err := os.Stat(args[1]); err != nil {
+			return err // Open would happily create an empty store
+		}
+		db, err := Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer db.wal.Close() // Read-only use: no checkpoint, keep the WAL for restores
+		meta, err := db.SnapshotFile(args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("snapshot %s: LSN %d, %d keys, sha256 %s\n", args[2], meta.LSN, meta.Keys, meta.SHA256)
+		return nil
+
+	case "restore":
+		fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+		wal := fs.String("wal", "", "WAL to roll forward from")
+		lsn := fs.Uint64("lsn", 0, "stop after this LSN (0 = end of log)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return errors.New("usage: restore [-wal PATH] [-lsn N] SNAPSHOT.tar.gz NEW_DIR")
+		}
+		res, err := Restore(fs.Arg(0), fs.Arg(1), RestoreOptions{WALPath: *wal, UpToLSN: *lsn})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("restored %s: snapshot LSN %d + %d WAL records = LSN %d\n", fs.Arg(1), res.SnapshotLSN, res.Replayed, res.LSN)
+		if *lsn != 0 && res.LSN < *lsn {
+			fmt.Printf("warning: log ended before LSN %d\n", *lsn)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown command %q (want snapshot or restore)", args[0])
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if err := runTool(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "kv:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: AN EMBEDDED KEY-VALUE STORE WITH A SORTED INDEX")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "gotut-kv-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	fmt.Println("--- Example 1: Put, Get, Delete ---")
+	db, err := Open(dir)
+	if err != nil {
+		fmt.Println("open:", err)
+		return
+	}
+	for _, kv := range [][2]string{
+		{"user:1:name", "Ann"}, {"user:1:email", "ann@example.com"},
+		{"user:2:name", "Bob"}, {"user:10:name", "Cy"},
+		{"topic:73", "regex"}, {"topic:80", "bufio"}, {"topic:112", "context"},
+	} {
+		if err := db.Put(kv[0], []byte(kv[1])); err != nil {
+			fmt.Println("put:", err)
+			return
+		}
+	}
+	v, ok := db.Get("user:2:name")
+	fmt.Printf("  Get(user:2:name) = %q, %v\n", v, ok)
+	deleted, _ := db.Delete("topic:80")
+	fmt.Printf("  Delete(topic:80) = %v, Len = %d, LSN = %d\n\n", deleted, db.Len(), db.LSN())
+
+	fmt.Println("--- Example 2: Scan(prefix) Walks a Contiguous Run ---")
+	for _, prefix := range []string{"user:1:", "user:1", "topic:", "nope:"} {
+		var keys []string
+		for _, e := range db.Scan(prefix) {
+			keys = append(keys, e.Key)
+		}
+		fmt.Printf("  Scan(%-8q) → %v\n", prefix, keys)
+	}
+	fmt.Println("  Note: \"user:1\" also matches user:10 — end prefixes with a separator.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Range With an Early Stop ---")
+	seen := 0
+	db.idx.Range("topic:", "user:2", func(k string, v []byte) bool {
+		fmt.Printf("  %s = %s\n", k, v)
+		seen++
+		return seen < 3 // Like LIMIT 3: the walk ends here, nothing else is touched
+	})
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Persist to Pages and Reopen ---")
+	db.wal.Sync = false // Bulk load: one fsync at the checkpoint instead of 2000
+	for i := 0; i < 2000; i++ {
+		db.Put(fmt.Sprintf("log:%05d", i), bytes.Repeat([]byte{'x'}, 20))
+	}
+	if err := db.Close(); err != nil {
+		fmt.Println("close:", err)
+		return
+	}
+	info, _ := os.Stat(filepath.Join(dir, indexFile))
+	fmt.Printf("  wrote %d keys into %d pages of %d bytes\n", db.Len(), info.Size()/PageSize, PageSize)
+
+	db2, err := Open(dir)
+	if err != nil {
+		fmt.Println("reopen:", err)
+		return
+	}
+	v, _ = db2.Get("user:1:email")
+	fmt.Printf("  after reopen: Len = %d, user:1:email = %q, Scan(log:0199) = %d keys\n\n",
+		db2.Len(), v, len(db2.Scan("log:0199")))
+
+	fmt.Println("--- Example 5: Crash Recovery From the WAL ---")
+	db2.Put("user:3:name", []byte("Dee"))
+	db2.Delete("user:2:name")
+	db2.wal.Close() // CRASH: no Flush, the index file is stale
+	walPath := filepath.Join(dir, walFile)
+	torn := encodeRecord(Record{LSN: 99, Op: OpPut, Key: "user:4:name", Value: []byte("Eve")})
+	f, _ := os.OpenFile(walPath, os.O_APPEND|os.O_WRONLY, 0)
+	f.Write([]byte{1, 2, 3, 4, byte(len(torn)), 0, 0, 0}) // Header made it to disk...
+	f.Write(torn[:len(torn)/2])                           // ...the payload only halfway
+	f.Close()
+
+	db3, err := Open(dir)
+	if err != nil {
+		fmt.Println("recover:", err)
+		return
+	}
+	v, _ = db3.Get("user:3:name")
+	_, bobLeft := db3.Get("user:2:name")
+	_, eve := db3.Get("user:4:name")
+	fmt.Printf("  replayed %d records up to LSN %d, discarded %d torn bytes\n",
+		db3.Recovered.Records, db3.Recovered.LastLSN, db3.Recovered.TornBytes)
+	fmt.Printf("  user:3:name = %q, user:2:name present = %v, torn user:4 present = %v\n\n", v, bobLeft, eve)
+	db3.Close()
+
+	fmt.Println("--- Example 6: Corruption Is Detected, Not Silently Loaded ---")
+	path := filepath.Join(dir, indexFile)
+	raw, _ := os.ReadFile(path)
+	raw[PageSize+1] ^= 0xFF // Flip bits in the second page's magic
+	os.WriteFile(path, raw, 0o644)
+	_, err = Open(dir)
+	fmt.Printf("  Open → %v\n  errors.Is(err, ErrCorruptPage) = %v\n\n", err, errors.Is(err, ErrCorruptPage))
+
+	fmt.Println("--- Example 7: Why Not a Map + sort? ---")
+	const n = 200_000
+	m := make(map[string][]byte, n)
+	sl := NewSkipList()
+	for i := 0; i < n; i++ {
+		k := fmt.Sprintf("k:%03d:%06d", i%500, i)
+		m[k] = nil
+		sl.Put(k, nil)
+	}
+	start := time.Now()
+	var keys []string
+	for k := range m { // A map must look at EVERY key for each query
+		if strings.HasPrefix(k, "k:123:") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	mapTime := time.Since(start)
+	start = time.Now()
+	count := 0
+	sl.Scan("k:123:", func(string, []byte) bool { count++; return true })
+	fmt.Printf("  map + filter + sort: %d keys in %v\n", len(keys), mapTime)
+	fmt.Printf("  skip list Scan:      %d keys in %v\n\n", count, time.Since(start))
+
+	fmt.Println("--- Example 8: Snapshot, Accident, Point-in-Time Restore ---")
+	liveDir := filepath.Join(dir, "live")
+	live, err := Open(liveDir)
+	if err != nil {
+		fmt.Println("open:", err)
+		return
+	}
+	for _, acct := range []string{"a", "b", "c"} {
+		live.Put("acct:"+acct, []byte("100"))
+	}
+	snapPath := filepath.Join(dir, "kv.snap.tar.gz")
+	meta, err := live.SnapshotFile(snapPath)
+	if err != nil {
+		fmt.Println("snapshot:", err)
+		return
+	}
+	fmt.Printf("  snapshot at LSN %d: %d keys, sha256 %s…\n", meta.LSN, meta.Keys, meta.SHA256[:12])
+	live.Put("acct:a", []byte("50"))  // LSN 4
+	live.Put("acct:b", []byte("150")) // LSN 5
+	live.Delete("acct:c")             // LSN 6 — the accident
+	fmt.Printf("  live store now at LSN %d, acct:c deleted by mistake\n", live.LSN())
+
+	restoreDir := filepath.Join(dir, "restored")
+	res, err := Restore(snapPath, restoreDir, RestoreOptions{WALPath: filepath.Join(liveDir, walFile), UpToLSN: 5})
+	if err != nil {
+		fmt.Println("restore:", err)
+		return
+	}
+	restored, _ := Open(restoreDir)
+	fmt.Printf("  restored snapshot@%d + %d WAL records → LSN %d:\n", res.SnapshotLSN, res.Replayed, res.LSN)
+	for _, e := range restored.Scan("acct:") {
+		fmt.Printf("    %s = %s\n", e.Key, e.Value)
+	}
+	restored.Close()
+
+	live.Flush() // Checkpoint: LSNs 4–6 leave the WAL
+	_, err = Restore(snapPath, filepath.Join(dir, "too-late"), RestoreOptions{WALPath: filepath.Join(liveDir, walFile)})
+	fmt.Printf("  after a checkpoint the log no longer reaches back: %v\n", err)
+	live.Close()
+
+	snap, _ := os.ReadFile(snapPath)
+	snap[len(snap)/2] ^= 0xFF
+	os.WriteFile(snapPath, snap, 0o644)
+	_, err = Restore(snapPath, filepath.Join(dir, "damaged"), RestoreOptions{})
+	fmt.Printf("  damaged archive: errors.Is(err, ErrSnapshotCorrupt) = %v\n", errors.Is(err, ErrSnapshotCorrupt))
+	fmt.Println("  Lesson: snapshot BEFORE you checkpoint, or archive old WAL segments.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Sorted indexes turn prefix and range queries into seek + walk.
+2. A skip list gives O(log n) search with random "express lanes".
+3. Fixed-size pages with a magic number make corruption detectable.
+4. encoding/binary uvarints keep small keys small on disk.
+5. Write a temp file, fsync, rename: the old index survives a crash.
+6. Log first, apply second: a WAL makes every acknowledged write durable.
+7. CRC32 per record + truncate at the first bad one = torn-write recovery.
+8. Snapshots (tar.gz + SHA-256) plus the WAL give point-in-time restore.
+9. Test data structures against a simple model (a map) and fuzz them.
+	`)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ---------------------------------------------------------
+// Part 5: Snapshots and Point-in-Time Restore
+// ---------------------------------------------------------
+// A snapshot is a .tar.gz with two entries:
+//
+//   meta.json → {"lsn": 2010, "keys": 2006, "sha256": "...", "created": "..."}
+//   index.db  → the index pages, exactly as Flush would write them
+//
+// The SHA-256 in meta.json covers index.db, so a restore refuses a damaged
+// or tampered archive (CRC32 would only catch accidents — see Topic 153).
+//
+// Taking a snapshot does NOT reset the WAL. Records after the snapshot's LSN
+// stay in the log, so Restore can roll the snapshot FORWARD to any later LSN:
+//
+//   snapshot @ LSN 100 ──► replay WAL 101..150 ──► store as of LSN 150
+
+const snapshotFormat = 1
+
+var (
+	ErrSnapshotCorrupt = errors.New("kvstore: snapshot checksum mismatch")
+	ErrWALGap          = errors.New("kvstore: WAL does not continue from snapshot LSN")
+	ErrRestoreTarget   = errors.New("kvstore: restore target is not empty")
+)
+
+type SnapshotMeta struct {
+	Format  int       `json:"format"`
+	LSN     uint64    `json:"lsn"`
+	Keys    int       `json:"keys"`
+	SHA256  string    `json:"sha256"`
+	Created time.Time `json:"created"`
+}
+
+// Snapshot writes a consistent, compressed copy of the store to w.
+// Readers keep working meanwhile; writers wait only while pages are encoded.
+func (s *Store) Snapshot(w io.Writer) (SnapshotMeta, error) {
+	s.mu.RLock()
+	var pages bytes.Buffer
+	_, err := s.idx.WritePages(&pages)
+	meta := SnapshotMeta{Format: snapshotFormat, LSN: s.lsn, Keys: s.idx.Len(), Created: time.Now().UTC()}
+	s.mu.RUnlock()
+	if err != nil {
+		return meta, err
+	}
+	sum := sha256.Sum256(pages.Bytes())
+	meta.SHA256 = hex.EncodeToString(sum[:])
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return meta, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{"meta.json", metaJSON}, {indexFile, pages.Bytes()}} {
+		hdr := &tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.data)), ModTime: meta.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return meta, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return meta, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return meta, err
+	}
+	return meta, gz.Close()
+}
+
+// SnapshotFile writes the snapshot to path atomically (temp file + rename).
+func (s *Store) SnapshotFile(path string) (SnapshotMeta, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return SnapshotMeta{}, err
+	}
+	defer os.Remove(tmp.Name())
+	meta, err := s.Snapshot(tmp)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return meta, err
+	}
+	return meta, os.Rename(tmp.Name(), path)
+}
+
+// readSnapshot extracts and verifies a snapshot archive.
+func readSnapshot(r io.Reader) (SnapshotMeta, *SkipList, error) {
+	var meta SnapshotMeta
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return meta, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return meta, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+		}
+		if files[hdr.Name], err = io.ReadAll(tr); err != nil {
+			return meta, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+		}
+	}
+	// tar stops at its end-of-archive marker; drain gzip so it checks its
+	// own CRC32 and length trailer too.
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return meta, nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+	}
+
+	if err := json.Unmarshal(files["meta.json"], &meta); err != nil {
+		return meta, nil, fmt.Errorf("%w: meta.json: %v", ErrSnapshotCorrupt, err)
+	}
+	if meta.Format != snapshotFormat {
+		return meta, nil, fmt.Errorf("kvstore: unsupported snapshot format %d", meta.Format)
+	}
+	pages, ok := files[indexFile]
+	sum := sha256.Sum256(pages)
+	if !ok || hex.EncodeToString(sum[:]) != meta.SHA256 {
+		return meta, nil, ErrSnapshotCorrupt
+	}
+	idx, err := ReadPages(bytes.NewReader(pages))
+	if err != nil {
+		return meta, nil, err
+	}
+	return meta, idx, nil
+}
+
+// RestoreOptions controls point-in-time recovery.
+type RestoreOptions struct {
+	WALPath string // Log to roll forward from; "" restores the snapshot as-is
+	UpToLSN uint64 // Stop after this LSN; 0 means "everything in the log"
+}
+
+type RestoreResult struct {
+	SnapshotLSN uint64
+	Replayed    int    // WAL records applied on top of the snapshot
+	LSN         uint64 // LSN of the restored store
+}
+
+// Restore builds a new store in dstDir from a snapshot, optionally replaying
+// WAL records with SnapshotLSN < LSN ≤ UpToLSN. dstDir must be empty.
+func Restore(snapshotPath, dstDir string, opts RestoreOptions) (RestoreResult, error) {
+	var res RestoreResult
+	if entries, err := os.ReadDir(dstDir); err == nil && len(entries) > 0 {
+		return res, fmt.Errorf("%w: %s", ErrRestoreTarget, dstDir)
+	}
+
+	f, err := os.Open(snapshotPath)
+	if err != nil {
+		return res, err
+	}
+	meta, idx, err := readSnapshot(f)
+	f.Close()
+	if err != nil {
+		return res, err
+	}
+	res.SnapshotLSN, res.LSN = meta.LSN, meta.LSN
+
+	if opts.WALPath != "" {
+		if err := rollForward(idx, opts, &res); err != nil {
+			return res, err
+		}
+	}
+
+	s, err := Open(dstDir)
+	if err != nil {
+		return res, err
+	}
+	s.idx, s.lsn = idx, res.LSN
+	return res, s.Close() // Close checkpoints: index.db + a WAL holding only res.LSN
+}
+
+func rollForward(idx *SkipList, opts RestoreOptions, res *RestoreResult) error {
+	f, err := os.Open(opts.WALPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	upTo := opts.UpToLSN
+	if upTo == 0 {
+		upTo = ^uint64(0)
+	}
+	var gap error
+	_, err = replay(f, func(r Record) {
+		next := res.LSN + 1
+		switch {
+		case gap != nil:
+		case r.Op == OpCheckpoint:
+			// A checkpoint AFTER the snapshot means the records between were
+			// truncated away: they are in index.db, not in this log.
+			if r.LSN > res.LSN && res.LSN < upTo {
+				gap = fmt.Errorf("%w: snapshot at %d, log checkpointed at %d", ErrWALGap, res.LSN, r.LSN)
+			}
+		case r.LSN < next, r.LSN > upTo:
+			// Already in the snapshot, or past the target
+		case r.LSN != next:
+			gap = fmt.Errorf("%w: expected LSN %d, found %d", ErrWALGap, next, r.LSN)
+		default:
+			if r.Op == OpPut {
+				idx.Put(r.Key, r.Value)
+			} else {
+				idx.Delete(r.Key)
+			}
+			res.LSN = r.LSN
+			res.Replayed++
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return gap
+}
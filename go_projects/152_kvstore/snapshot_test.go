@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// history builds a store with a snapshot after LSN 3 and three more writes.
+func history(t *testing.T) (liveDir, snapPath string) {
+	t.Helper()
+	root := t.TempDir()
+	liveDir, snapPath = filepath.Join(root, "live"), filepath.Join(root, "snap.tar.gz")
+	db, err := Open(liveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.wal.Sync = false
+	db.Put("k1", []byte("a")) // 1
+	db.Put("k2", []byte("b")) // 2
+	db.Put("k3", []byte("c")) // 3
+	if meta, err := db.SnapshotFile(snapPath); err != nil || meta.LSN != 3 || meta.Keys != 3 {
+		t.Fatalf("SnapshotFile = %+v, %v", meta, err)
+	}
+	db.Put("k1", []byte("A")) // 4
+	db.Delete("k2")           // 5
+	db.Put("k4", []byte("d")) // 6
+	db.wal.Close()
+	return liveDir, snapPath
+}
+
+func dump(t *testing.T, dir string) string {
+	t.Helper()
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.wal.Close()
+	var s string
+	for _, e := range db.Scan("") {
+		s += fmt.Sprintf("%s=%s ", e.Key, e.Value)
+	}
+	return fmt.Sprintf("@%d %s", db.LSN(), s)
+}
+
+func TestRestorePointInTime(t *testing.T) {
+	liveDir, snap := history(t)
+	wal := filepath.Join(liveDir, walFile)
+
+	tests := []struct {
+		name string
+		opts RestoreOptions
+		want string
+	}{
+		{"snapshot only", RestoreOptions{}, "@3 k1=a k2=b k3=c "},
+		{"up to LSN 3", RestoreOptions{WALPath: wal, UpToLSN: 3}, "@3 k1=a k2=b k3=c "},
+		{"up to LSN 4", RestoreOptions{WALPath: wal, UpToLSN: 4}, "@4 k1=A k2=b k3=c "},
+		{"up to LSN 5", RestoreOptions{WALPath: wal, UpToLSN: 5}, "@5 k1=A k3=c "},
+		{"whole log", RestoreOptions{WALPath: wal}, "@6 k1=A k3=c k4=d "},
+		{"target past end", RestoreOptions{WALPath: wal, UpToLSN: 99}, "@6 k1=A k3=c k4=d "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "restored")
+			if _, err := Restore(snap, dst, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := dump(t, dst); got != tt.want {
+				t.Errorf("restored %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreMatchesLiveStore(t *testing.T) {
+	liveDir, snap := history(t)
+	dst := filepath.Join(t.TempDir(), "restored")
+	if _, err := Restore(snap, dst, RestoreOptions{WALPath: filepath.Join(liveDir, walFile)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := dump(t, dst), dump(t, liveDir); got != want {
+		t.Errorf("restored %q; live store is %q", got, want)
+	}
+}
+
+func TestRestoreDetectsWALGap(t *testing.T) {
+	liveDir, snap := history(t)
+	db, _ := Open(liveDir)
+	db.Flush() // Checkpoint at LSN 6: records 4–6 leave the log
+	db.Put("k5", nil)
+	db.wal.Close()
+
+	_, err := Restore(snap, filepath.Join(t.TempDir(), "r"), RestoreOptions{WALPath: filepath.Join(liveDir, walFile)})
+	if !errors.Is(err, ErrWALGap) {
+		t.Errorf("err = %v; want ErrWALGap", err)
+	}
+}
+
+func TestRestoreRejectsBadInput(t *testing.T) {
+	_, snap := history(t)
+
+	notEmpty := t.TempDir()
+	os.WriteFile(filepath.Join(notEmpty, "keep.txt"), []byte("x"), 0o644)
+	if _, err := Restore(snap, notEmpty, RestoreOptions{}); !errors.Is(err, ErrRestoreTarget) {
+		t.Errorf("non-empty target: err = %v; want ErrRestoreTarget", err)
+	}
+
+	raw, _ := os.ReadFile(snap)
+	for _, off := range []int{0, len(raw) / 2, len(raw) - 1} {
+		bad := filepath.Join(t.TempDir(), "bad.tar.gz")
+		damaged := append([]byte(nil), raw...)
+		damaged[off] ^= 0xFF
+		os.WriteFile(bad, damaged, 0o644)
+		if _, err := Restore(bad, filepath.Join(t.TempDir(), "r"), RestoreOptions{}); !errors.Is(err, ErrSnapshotCorrupt) {
+			t.Errorf("byte %d flipped: err = %v; want ErrSnapshotCorrupt", off, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ---------------------------------------------------------
+// Part 3: The Store (index + WAL + locking)
+// ---------------------------------------------------------
+
+const indexFile = "index.db"
+
+type Entry struct {
+	Key   string
+	Value []byte
+}
+
+// Store is a small persistent key-value store. Reads share a lock; writes
+// take it exclusively. Every mutation goes to the WAL (Part 4) before it
+// touches the index, so an acknowledged Put survives a crash.
+//
+// Flush is a CHECKPOINT: it writes a complete new index file, renames it over
+// the old one, then empties the WAL. A crash at any point leaves either the
+// old index + full log, or the new index + a log whose records are already
+// applied — replaying them again is harmless because Put/Delete are idempotent.
+type Store struct {
+	dir       string
+	mu        sync.RWMutex
+	idx       *SkipList
+	wal       *WAL
+	lsn       uint64       // LSN of the last applied mutation
+	Recovered ReplayResult // What Open found in the WAL
+}
+
+// Open loads dir/index.db (if present) and replays dir/wal.log on top of it.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, idx: NewSkipList()}
+	f, err := os.Open(filepath.Join(dir, indexFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		s.idx, err = ReadPages(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.wal, s.Recovered, err = OpenWAL(filepath.Join(dir, walFile), s.apply)
+	if err != nil {
+		return nil, err
+	}
+	s.lsn = s.Recovered.LastLSN
+	return s, nil
+}
+
+func (s *Store) apply(r Record) {
+	switch r.Op {
+	case OpPut:
+		s.idx.Put(r.Key, r.Value)
+	case OpDelete:
+		s.idx.Delete(r.Key)
+	}
+}
+
+// write logs the record first, then applies it. Caller holds s.mu.
+func (s *Store) write(op Op, key string, value []byte) error {
+	r := Record{LSN: s.lsn + 1, Op: op, Key: key, Value: value}
+	if err := s.wal.Append(r); err != nil {
+		return err // Not logged → not applied: memory never runs ahead of disk
+	}
+	s.lsn = r.LSN
+	s.apply(r)
+	return nil
+}
+
+func (s *Store) Get(key string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.idx.Get(key)
+	return append([]byte(nil), v...), ok // Callers can't mutate our copy
+}
+
+func (s *Store) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(OpPut, key, append([]byte(nil), value...))
+}
+
+// Delete reports whether the key existed. Deleting a missing key logs nothing.
+func (s *Store) Delete(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.idx.Get(key); !ok {
+		return false, nil
+	}
+	return true, s.write(OpDelete, key, nil)
+}
+
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.idx.Len()
+}
+
+// LSN returns the sequence number of the last applied mutation.
+func (s *Store) LSN() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lsn
+}
+
+// Scan returns every entry whose key starts with prefix, in key order.
+func (s *Store) Scan(prefix string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []Entry
+	s.idx.Scan(prefix, func(k string, v []byte) bool {
+		out = append(out, Entry{k, append([]byte(nil), v...)})
+		return true
+	})
+	return out
+}
+
+// Flush checkpoints: write temp index, fsync, rename, then reset the WAL.
+func (s *Store) Flush() error {
+	s.mu.Lock() // Exclusive: the WAL must not grow between rename and reset
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, indexFile+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if _, err := s.idx.WritePages(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, indexFile)); err != nil {
+		return err
+	}
+	return s.wal.Reset(s.lsn)
+}
+
+// Close checkpoints and closes the log. The store must not be used afterwards.
+func (s *Store) Close() error {
+	err := s.Flush()
+	if cerr := s.wal.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// crashAfter simulates a crash: the WAL file is closed without a checkpoint,
+// so the next Open must rebuild everything from the log.
+func crashAfter(t *testing.T, dir string, fn func(*Store)) {
+	t.Helper()
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.wal.Sync = false // The OS page cache is enough for a simulated crash
+	fn(db)
+	db.wal.Close()
+}
+
+func TestWALReplayAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+	crashAfter(t, dir, func(db *Store) {
+		db.Put("a", []byte("1"))
+		db.Put("b", []byte("2"))
+		db.Put("a", []byte("3"))
+		db.Delete("b")
+	})
+
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if v, _ := db.Get("a"); string(v) != "3" {
+		t.Errorf("a = %q; want 3", v)
+	}
+	if _, ok := db.Get("b"); ok {
+		t.Error("b should stay deleted after replay")
+	}
+	if db.Recovered.Records != 4 || db.LSN() != 4 || db.Recovered.TornBytes != 0 {
+		t.Errorf("Recovered = %+v, LSN = %d; want 4 records, LSN 4, no torn bytes", db.Recovered, db.LSN())
+	}
+}
+
+// TestWALTruncatedAtEveryOffset cuts the log at every possible byte and
+// checks that Open recovers exactly the records that were fully written.
+func TestWALTruncatedAtEveryOffset(t *testing.T) {
+	src := t.TempDir()
+	var ends []int64 // File size after each complete record
+	crashAfter(t, src, func(db *Store) {
+		for i := 0; i < 5; i++ {
+			db.Put(fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value-%d", i)))
+			info, _ := db.wal.f.Stat()
+			ends = append(ends, info.Size())
+		}
+	})
+	full, err := os.ReadFile(filepath.Join(src, walFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for cut := 0; cut <= len(full); cut++ {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, walFile), full[:cut], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		want := 0
+		for _, end := range ends {
+			if int64(cut) >= end {
+				want++
+			}
+		}
+
+		db, err := Open(dir)
+		if err != nil {
+			t.Fatalf("cut %d: Open: %v", cut, err)
+		}
+		if db.Len() != want {
+			t.Errorf("cut %d: recovered %d keys; want %d", cut, db.Len(), want)
+		}
+		var good int64
+		if want > 0 {
+			good = ends[want-1]
+		}
+		if db.Recovered.TornBytes != int64(cut)-good {
+			t.Errorf("cut %d: TornBytes = %d; want %d", cut, db.Recovered.TornBytes, int64(cut)-good)
+		}
+
+		// New writes must land after the last good record, not after garbage.
+		if err := db.Put("after", []byte("crash")); err != nil {
+			t.Fatal(err)
+		}
+		db.wal.Close()
+		db, err = Open(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := db.Get("after"); string(v) != "crash" || db.Len() != want+1 {
+			t.Errorf("cut %d: write after recovery lost (len %d, after=%q)", cut, db.Len(), v)
+		}
+		db.wal.Close()
+	}
+}
+
+func TestWALStopsAtChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	var secondStart int64
+	crashAfter(t, dir, func(db *Store) {
+		db.Put("first", []byte("ok"))
+		info, _ := db.wal.f.Stat()
+		secondStart = info.Size()
+		db.Put("second", []byte("will be corrupted"))
+		db.Put("third", []byte("unreachable"))
+	})
+
+	path := filepath.Join(dir, walFile)
+	raw, _ := os.ReadFile(path)
+	raw[secondStart+walHeader+10] ^= 0x01 // One flipped bit inside the payload
+	os.WriteFile(path, raw, 0o644)
+
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, ok := db.Get("first"); !ok {
+		t.Error("record before the corruption was lost")
+	}
+	for _, k := range []string{"second", "third"} {
+		if _, ok := db.Get(k); ok {
+			t.Errorf("%s replayed past a bad checksum", k)
+		}
+	}
+}
+
+func TestLSNContinuesAcrossCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	db, _ := Open(dir)
+	db.Put("a", nil)
+	db.Put("b", nil)
+	if err := db.Close(); err != nil { // Checkpoint: WAL now holds only LSN 2
+		t.Fatal(err)
+	}
+
+	db, _ = Open(dir)
+	if db.LSN() != 2 {
+		t.Fatalf("LSN after checkpoint = %d; want 2", db.LSN())
+	}
+	db.Put("c", nil)
+	if db.LSN() != 3 {
+		t.Errorf("next LSN = %d; want 3", db.LSN())
+	}
+	db.Close()
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	tests := []Record{
+		{LSN: 1, Op: OpPut, Key: "k", Value: []byte("v")},
+		{LSN: 1 << 40, Op: OpDelete, Key: "unicode-ключ"},
+		{LSN: 7, Op: OpCheckpoint},
+		{LSN: 8, Op: OpPut, Key: "", Value: make([]byte, 300)},
+	}
+	for _, want := range tests {
+		got, err := decodeRecord(encodeRecord(want))
+		if err != nil || fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("round trip %+v → %+v, %v", want, got, err)
+		}
+	}
+	if _, err := decodeRecord(encodeRecord(Record{LSN: 1, Op: 9})); err == nil {
+		t.Error("unknown op should be rejected")
+	}
+}
@@ -0,0 +1,821 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/blobstore"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/filetype"
+)
+
+/*
+TOPIC: BACKUP UTILITY CAPSTONE — RULES, MANIFESTS, INCREMENTALS, VERIFY
+
+CONCEPT:
+A backup tool is a tour of the standard library:
+
+    filepath.WalkDir (87)  → find every file under the source
+    path.Match       (86)  → include / exclude rules like "*.go", "vendor/"
+    io.Copy          (99)  → copy contents without loading them into memory
+    crypto/sha256    (82)  → fingerprint every file
+    encoding/json    (94)  → write a MANIFEST describing the backup
+    os.Chtimes / Link      → preserve mtimes; share unchanged files
+    flag + subcommands (90, 91) → the command line
+
+LAYOUT:
+    backups/
+      20261016T091500Z/            ← one timestamped directory per run
+        manifest.json              ← path, size, mtime, sha256 for every file
+        files/...                  ← the copied tree
+      20261016T101500Z/            ← an INCREMENTAL run
+        manifest.json
+        files/...                  ← unchanged files are HARD LINKS to the
+                                     previous run: no extra space used
+
+OR, with -blobs, CONTENT-ADDRESSED (pkg/blobstore):
+    backups/
+      blobs/3a/1f/3a1f…e9          ← every distinct content, once, named
+                                     by its SHA-256
+      20261016T091500Z/
+        manifest.json              ← the only thing a backup adds: its
+                                     sha256 fields say which blobs
+    Two files with the same bytes, in one backup or in ten, are one blob.
+    Deleting a backup frees nothing by itself; "gc" removes the blobs no
+    remaining manifest mentions.
+
+INCREMENTAL RULE (cheap first, expensive only when needed):
+    same size AND same mtime as last time  → unchanged, skip hashing
+    otherwise hash it; same SHA-256         → unchanged (only "touched")
+    otherwise                               → changed, copy
+
+VERIFY re-hashes every file in a backup and compares with the manifest.
+
+A hash only proves the backup matches what was copied. A photo that was
+already garbage (truncated, or encrypted by ransomware) is backed up and
+verifies perfectly. So while copying, the tool also sniffs each file's
+magic number (pkg/filetype, Topic 187) and warns when a .png, .jpg, .zip,
+.gz or .pdf doesn't start like one.
+
+RUN:
+    go run 154_backup_tool.go                     → guided demo in a temp dir
+    go run 154_backup_tool.go backup [-incremental] [-include "*.go"] [-exclude ".git/"] SRC DEST
+    go run 154_backup_tool.go backup -dry-run -incremental SRC DEST   → list, write nothing
+    go run 154_backup_tool.go backup -blobs -incremental SRC DEST      → content-addressed
+    go run 154_backup_tool.go verify BACKUP_DIR
+    go run 154_backup_tool.go gc DEST                 → after deleting -blobs backups
+*/
+
+// ---------------------------------------------------------
+// Part 1: Include / Exclude Rules
+// ---------------------------------------------------------
+// Patterns use path.Match syntax on slash-separated paths relative to the
+// source root. A pattern without "/" matches the base name anywhere
+// ("*.log"); a trailing "/" matches a directory and everything below it
+// (".git/", "build/tmp/"). Exclude beats include; no includes = include all.
+
+type Rules struct {
+	Include []string
+	Exclude []string
+}
+
+func matchAny(patterns []string, rel string, isDir bool) bool {
+	for _, p := range patterns {
+		dirOnly := strings.HasSuffix(p, "/")
+		p = strings.TrimSuffix(p, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if !strings.Contains(p, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// skipDir reports whether a whole directory is excluded.
+func (r Rules) skipDir(rel string) bool { return matchAny(r.Exclude, rel, true) }
+
+// keepFile reports whether a file should be backed up.
+func (r Rules) keepFile(rel string) bool {
+	if matchAny(r.Exclude, rel, false) {
+		return false
+	}
+	return len(r.Include) == 0 || matchAny(r.Include, rel, false)
+}
+
+// ---------------------------------------------------------
+// Part 2: The Manifest
+// ---------------------------------------------------------
+
+type FileEntry struct {
+	Path    string    `json:"path"` // Slash-separated, relative to the source root
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	SHA256  string    `json:"sha256"`
+	Reused  bool      `json:"reused,omitempty"` // Linked from the previous backup
+}
+
+type Manifest struct {
+	Source   string      `json:"source"`
+	Created  time.Time   `json:"created"`
+	Previous string      `json:"previous,omitempty"` // Base of an incremental run
+	Blobs    bool        `json:"blobs,omitempty"`    // Contents are in ../blobs, by SHA256
+	Rules    Rules       `json:"rules"`
+	Files    []FileEntry `json:"files"`
+}
+
+const manifestName = "manifest.json"
+
+// blobsName is the blob store's directory, beside the backups.
+const blobsName = "blobs"
+
+func readManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestName, err)
+	}
+	return &m, nil
+}
+
+func writeManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestName)) // Manifest appears last, all at once
+}
+
+// latestBackup returns the newest timestamped directory under dest that has
+// a manifest (an interrupted run has none and is ignored).
+func latestBackup(dest string) (string, error) {
+	entries, err := os.ReadDir(dest)
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for _, e := range entries {
+		if _, err := os.Stat(filepath.Join(dest, e.Name(), manifestName)); e.IsDir() && err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names) // The timestamp format sorts chronologically
+	return filepath.Join(dest, names[len(names)-1]), nil
+}
+
+// ---------------------------------------------------------
+// Part 3: Backup
+// ---------------------------------------------------------
+
+type Options struct {
+	Rules       Rules
+	Incremental bool
+	Now         func() time.Time // Injectable clock for the demo
+	// DryRun walks, compares and hashes exactly as a real run would but
+	// writes nothing: each file that would be copied or linked is reported
+	// to Log instead, and Stats are filled in as usual.
+	DryRun bool
+	Log    io.Writer
+	// Blobs stores contents in DEST/blobs instead of a files/ tree per
+	// backup: each distinct content once, across paths and backups.
+	Blobs bool
+}
+
+type Stats struct {
+	Dir                      string
+	Copied, Reused, Skipped  int
+	Hashed                   int // Files we had to read to hash
+	CopiedBytes, ReusedBytes int64
+	Suspect                  []Problem // Copied files whose content isn't what the name says
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile copies src to dst, hashing while copying, and keeps the mtime.
+// It also returns the type src's magic number says it is: Detect peeks
+// at the first bytes of the same read, so that costs nothing extra.
+func copyFile(src, dst string, mtime time.Time) (string, filetype.Type, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", filetype.Unknown, err
+	}
+	defer in.Close()
+	typ, r, err := filetype.Detect(in)
+	if err != nil {
+		return "", typ, err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", typ, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), r); err != nil {
+		out.Close()
+		return "", typ, err
+	}
+	if err := out.Close(); err != nil {
+		return "", typ, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), typ, os.Chtimes(dst, mtime, mtime)
+}
+
+// putFile is copyFile for a blob store: the address it returns is the
+// SHA-256, and Detect's peek is free here too.
+func putFile(store *blobstore.Store, src string) (string, filetype.Type, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", filetype.Unknown, err
+	}
+	defer in.Close()
+	typ, r, err := filetype.Detect(in)
+	if err != nil {
+		return "", typ, err
+	}
+	addr, err := store.Put(r)
+	return addr, typ, err
+}
+
+// linkOrCopy shares an unchanged file with the previous backup.
+func linkOrCopy(prev, dst string, mtime time.Time) error {
+	if err := os.Link(prev, dst); err == nil {
+		return nil
+	}
+	_, _, err := copyFile(prev, dst, mtime) // Filesystems without hard links
+	return err
+}
+
+func Backup(src, dest string, opts Options) (Stats, error) {
+	var st Stats
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	created := now().UTC()
+	st.Dir = filepath.Join(dest, created.Format("20060102T150405Z"))
+	if !opts.DryRun {
+		if err := os.MkdirAll(st.Dir, 0o755); err != nil {
+			return st, err
+		}
+	}
+
+	m := &Manifest{Source: src, Created: created, Rules: opts.Rules, Blobs: opts.Blobs}
+	var store *blobstore.Store
+	if opts.Blobs && !opts.DryRun {
+		var err error
+		if store, err = blobstore.Open(filepath.Join(dest, blobsName)); err != nil {
+			return st, err
+		}
+	}
+	prevFiles := map[string]FileEntry{}
+	var prevDir string
+	if opts.Incremental {
+		var err error
+		if prevDir, err = latestBackup(dest); err != nil {
+			return st, err
+		}
+		if prevDir == st.Dir {
+			return st, fmt.Errorf("backup %s already exists", st.Dir)
+		}
+		if prevDir != "" {
+			prev, err := readManifest(prevDir)
+			if err != nil {
+				return st, err
+			}
+			m.Previous = filepath.Base(prevDir)
+			for _, f := range prev.Files {
+				prevFiles[f.Path] = f
+			}
+		}
+	}
+
+	filesDir := filepath.Join(st.Dir, "files")
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if opts.Rules.skipDir(rel) {
+				st.Skipped++
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !opts.Rules.keepFile(rel) {
+			st.Skipped++ // Symlinks, sockets and excluded files
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(filesDir, filepath.FromSlash(rel))
+		if !opts.DryRun && !opts.Blobs {
+			if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+				return err
+			}
+		}
+		entry := FileEntry{Path: rel, Size: info.Size(), ModTime: info.ModTime().UTC()}
+
+		if old, ok := prevFiles[rel]; ok && old.Size == entry.Size {
+			sum := old.SHA256
+			if !old.ModTime.Equal(entry.ModTime) { // Touched? Only hashing can tell
+				st.Hashed++
+				if sum, err = hashFile(p); err != nil {
+					return err
+				}
+			}
+			// With blobs there is nothing to link: the manifest entry is
+			// the reuse. Unless the previous backup was a files/ one.
+			if sum == old.SHA256 && (store == nil || store.Has(sum)) {
+				prevPath := filepath.Join(prevDir, "files", filepath.FromSlash(rel))
+				if opts.DryRun {
+					how := "link"
+					if opts.Blobs {
+						how = "reuse"
+					}
+					dryLog(opts.Log, "would %s %s (unchanged since %s)", how, rel, filepath.Base(prevDir))
+				} else if store == nil {
+					if err := linkOrCopy(prevPath, dst, entry.ModTime); err != nil {
+						return err
+					}
+				}
+				entry.SHA256, entry.Reused = sum, true
+				m.Files = append(m.Files, entry)
+				st.Reused++
+				st.ReusedBytes += entry.Size
+				return nil
+			}
+		}
+
+		if opts.DryRun {
+			dryLog(opts.Log, "would copy %s (%d B)", rel, entry.Size)
+		} else {
+			var typ filetype.Type
+			if store != nil {
+				entry.SHA256, typ, err = putFile(store, p)
+			} else {
+				entry.SHA256, typ, err = copyFile(p, dst, entry.ModTime)
+			}
+			if err != nil {
+				return err
+			}
+			// Back it up anyway: the old version is still in an earlier
+			// backup, and this one may be all there is.
+			if want, ok := filetype.ByExt(rel); ok && typ != want {
+				st.Suspect = append(st.Suspect, Problem{rel, fmt.Sprintf("named %s but content is %v", path.Ext(rel), typ)})
+			}
+		}
+		m.Files = append(m.Files, entry)
+		st.Copied++
+		st.CopiedBytes += entry.Size
+		return nil
+	})
+	if err != nil {
+		return st, err // No manifest: latestBackup will ignore this partial run
+	}
+	if opts.DryRun {
+		dryLog(opts.Log, "would write %s", filepath.Join(st.Dir, manifestName))
+		return st, nil
+	}
+	return st, writeManifest(st.Dir, m)
+}
+
+func dryLog(w io.Writer, format string, args ...any) {
+	if w != nil {
+		fmt.Fprintf(w, format+"\n", args...)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 4: Verify
+// ---------------------------------------------------------
+
+type Problem struct {
+	Path, Issue string
+}
+
+// Verify re-hashes every file listed in the manifest and also reports files
+// present in the backup that the manifest does not know about.
+func Verify(dir string) ([]Problem, error) {
+	m, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if m.Blobs {
+		return verifyBlobs(dir, m)
+	}
+	var problems []Problem
+	listed := map[string]bool{}
+	for _, f := range m.Files {
+		listed[f.Path] = true
+		p := filepath.Join(dir, "files", filepath.FromSlash(f.Path))
+		info, err := os.Stat(p)
+		if err != nil {
+			problems = append(problems, Problem{f.Path, "missing"})
+			continue
+		}
+		if info.Size() != f.Size {
+			problems = append(problems, Problem{f.Path, fmt.Sprintf("size %d, manifest says %d", info.Size(), f.Size)})
+			continue
+		}
+		if sum, err := hashFile(p); err != nil || sum != f.SHA256 {
+			problems = append(problems, Problem{f.Path, "content does not match SHA-256"})
+		}
+	}
+	root := filepath.Join(dir, "files")
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		if !listed[filepath.ToSlash(rel)] {
+			problems = append(problems, Problem{filepath.ToSlash(rel), "not in manifest"})
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) && len(m.Files) == 0 {
+		err = nil // An empty backup has no files/ directory
+	}
+	return problems, err
+}
+
+// verifyBlobs checks a -blobs backup: every entry's blob is there, and
+// reading it through blobstore.Get to the end proves the content still
+// hashes to the address.
+func verifyBlobs(dir string, m *Manifest) ([]Problem, error) {
+	store, err := blobstore.Open(filepath.Join(filepath.Dir(dir), blobsName))
+	if err != nil {
+		return nil, err
+	}
+	var problems []Problem
+	for _, f := range m.Files {
+		rc, err := store.Get(f.SHA256)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			problems = append(problems, Problem{f.Path, "missing"})
+			continue
+		}
+		if err != nil {
+			return problems, err
+		}
+		n, err := io.Copy(io.Discard, rc)
+		rc.Close()
+		switch {
+		case errors.Is(err, blobstore.ErrCorrupt):
+			problems = append(problems, Problem{f.Path, "content does not match SHA-256"})
+		case err != nil:
+			return problems, err
+		case n != f.Size:
+			problems = append(problems, Problem{f.Path, fmt.Sprintf("size %d, manifest says %d", n, f.Size)})
+		}
+	}
+	return problems, nil
+}
+
+// GC removes the blobs under dest that no backup's manifest mentions:
+// what deleting backups left behind. A backup still being written has
+// no manifest yet, so its blobs would go too: don't run GC during one.
+// A manifest that can't be read stops GC, since it may mention blobs.
+func GC(dest string) (blobstore.Collected, error) {
+	entries, err := os.ReadDir(dest)
+	if err != nil {
+		return blobstore.Collected{}, err
+	}
+	live := map[string]bool{}
+	for _, e := range entries {
+		m, err := readManifest(filepath.Join(dest, e.Name()))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // blobs/, or an interrupted run
+		}
+		if err != nil {
+			return blobstore.Collected{}, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		for _, f := range m.Files {
+			live[f.SHA256] = true
+		}
+	}
+	store, err := blobstore.Open(filepath.Join(dest, blobsName))
+	if err != nil {
+		return blobstore.Collected{}, err
+	}
+	return store.GC(live)
+}
+
+// ---------------------------------------------------------
+// Part 5: Command Line
+// ---------------------------------------------------------
+
+type listFlag []string
+
+func (l *listFlag) String() string     { return strings.Join(*l, ",") }
+func (l *listFlag) Set(v string) error { *l = append(*l, v); return nil }
+
+func run(args []string) error {
+	switch args[0] {
+	case "backup":
+		fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+		var inc, exc listFlag
+		fs.Var(&inc, "include", "glob to include (repeatable)")
+		fs.Var(&exc, "exclude", "glob to exclude (repeatable, trailing / for dirs)")
+		incremental := fs.Bool("incremental", false, "reuse unchanged files from the latest backup")
+		dryRun := fs.Bool("dry-run", false, "list what would be copied or linked, write nothing")
+		blobs := fs.Bool("blobs", false, "store contents once, by SHA-256, in DEST/blobs")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return errors.New("usage: backup [flags] SRC DEST")
+		}
+		st, err := Backup(fs.Arg(0), fs.Arg(1), Options{
+			Rules: Rules{inc, exc}, Incremental: *incremental, DryRun: *dryRun, Log: os.Stdout, Blobs: *blobs,
+		})
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			fmt.Print("dry run, nothing written. ")
+		}
+		fmt.Printf("%s: %d copied (%d B), %d reused (%d B), %d skipped\n",
+			st.Dir, st.Copied, st.CopiedBytes, st.Reused, st.ReusedBytes, st.Skipped)
+		for _, p := range st.Suspect {
+			fmt.Printf("⚠ %s: %s\n", p.Path, p.Issue)
+		}
+		return nil
+	case "verify":
+		if len(args) != 2 {
+			return errors.New("usage: verify BACKUP_DIR")
+		}
+		problems, err := Verify(args[1])
+		if err != nil {
+			return err
+		}
+		for _, p := range problems {
+			fmt.Printf("✗ %s: %s\n", p.Path, p.Issue)
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%d problem(s)", len(problems))
+		}
+		fmt.Println("✓ backup verified")
+		return nil
+	case "gc":
+		if len(args) != 2 {
+			return errors.New("usage: gc DEST")
+		}
+		c, err := GC(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("removed %d unreferenced blob(s), %d B\n", c.Blobs, c.Bytes)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q (want backup, verify or gc)", args[0])
+}
+
+// ---------------------------------------------------------
+// Part 6: Guided Demo
+// ---------------------------------------------------------
+
+func writeTree(root string, files map[string]string) {
+	for name, body := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		os.WriteFile(p, []byte(body), 0o644)
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if err := run(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "backup:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: BACKUP UTILITY CAPSTONE")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	tmp, err := os.MkdirTemp("", "gotut-backup-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+	src, dest := filepath.Join(tmp, "project"), filepath.Join(tmp, "backups")
+
+	writeTree(src, map[string]string{
+		"main.go":            "package main\n",
+		"README.md":          "# project\n",
+		"internal/db/db.go":  "package db\n",
+		"internal/db/db.log": "noise\n",
+		".git/HEAD":          "ref: refs/heads/main\n",
+		"build/app":          "BINARY",
+		"docs/guide.md":      "guide\n",
+	})
+	clock := time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+	opts := Options{
+		Rules:       Rules{Exclude: []string{".git/", "build/", "*.log"}},
+		Incremental: true,
+		Now:         func() time.Time { return clock },
+	}
+
+	fmt.Println("--- Example 1: Rules ---")
+	for _, rel := range []string{"main.go", "internal/db/db.log", ".git/HEAD", "docs/guide.md"} {
+		dir := strings.Split(rel, "/")[0]
+		keep := opts.Rules.keepFile(rel) && (dir == rel || !opts.Rules.skipDir(dir))
+		fmt.Printf("  %-20s keep=%v\n", rel, keep)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: First (Full) Backup ---")
+	st, err := Backup(src, dest, opts)
+	if err != nil {
+		fmt.Println("backup:", err)
+		return
+	}
+	fmt.Printf("  %s: copied %d, reused %d, skipped %d\n", filepath.Base(st.Dir), st.Copied, st.Reused, st.Skipped)
+	m, _ := readManifest(st.Dir)
+	for _, f := range m.Files {
+		fmt.Printf("    %-18s %3d B  %s…\n", f.Path, f.Size, f.SHA256[:12])
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Incremental Backup After Some Edits ---")
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644) // Changed
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(src, "README.md"), later, later)   // Touched, same content
+	writeTree(src, map[string]string{"docs/new.md": "fresh\n"}) // Added
+	os.Remove(filepath.Join(src, "docs/guide.md"))              // Deleted
+	clock = clock.Add(time.Hour)
+	before, _ := os.ReadDir(dest)
+	var plan strings.Builder
+	dry := opts
+	dry.DryRun, dry.Log = true, &plan
+	if _, err := Backup(src, dest, dry); err != nil {
+		fmt.Println("backup -dry-run:", err)
+		return
+	}
+	after, _ := os.ReadDir(dest)
+	fmt.Printf("  -dry-run first (%d backup dir(s) before, %d after):\n", len(before), len(after))
+	for l := range strings.Lines(strings.ReplaceAll(plan.String(), dest+string(filepath.Separator), "")) {
+		fmt.Print("    ", l)
+	}
+	st2, err := Backup(src, dest, opts)
+	if err != nil {
+		fmt.Println("backup:", err)
+		return
+	}
+	fmt.Printf("  %s: copied %d (%d B), reused %d (%d B), hashed %d to check mtimes\n",
+		filepath.Base(st2.Dir), st2.Copied, st2.CopiedBytes, st2.Reused, st2.ReusedBytes, st2.Hashed)
+	m2, _ := readManifest(st2.Dir)
+	for _, f := range m2.Files {
+		how := "copied"
+		if f.Reused {
+			how = "hard link to " + m2.Previous
+		}
+		fmt.Printf("    %-18s %s\n", f.Path, how)
+	}
+	fmt.Println("  docs/guide.md is gone from this manifest but still safe in the first backup.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Verify, Then Damage and Verify Again ---")
+	problems, err := Verify(st2.Dir)
+	fmt.Printf("  clean backup: %d problem(s), err=%v\n", len(problems), err)
+	os.Remove(filepath.Join(st2.Dir, "files", "docs", "new.md"))
+	os.WriteFile(filepath.Join(st2.Dir, "files", "stray.txt"), []byte("?"), 0o644)
+	// The hard-linked README is shared: flipping it damages BOTH backups.
+	os.WriteFile(filepath.Join(st2.Dir, "files", "README.md"), []byte("# PROJECT\n"), 0o644)
+	for _, dir := range []string{st.Dir, st2.Dir} {
+		problems, _ := Verify(dir)
+		fmt.Printf("  %s:\n", filepath.Base(dir))
+		for _, p := range problems {
+			fmt.Printf("    ✗ %-14s %s\n", p.Path, p.Issue)
+		}
+	}
+	fmt.Println("  Hard links save space but share damage — keep an offsite full copy too.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: A Photo That Isn't One ---")
+	writeTree(src, map[string]string{
+		"photos/cat.png":     "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+		"photos/holiday.jpg": "\x9c\x1e\xe3\x07\x5a\xc1 encrypted by a ransomware payload",
+		"docs/spec.pdf":      "%PDF-1.7\n",
+	})
+	clock = clock.Add(time.Hour)
+	st3, err := Backup(src, filepath.Join(tmp, "offsite"), opts) // Not from the damaged ones
+	if err != nil {
+		fmt.Println("backup:", err)
+		return
+	}
+	fmt.Printf("  %s: copied %d\n", filepath.Base(st3.Dir), st3.Copied)
+	for _, p := range st3.Suspect {
+		fmt.Printf("    ⚠ %-18s %s\n", p.Path, p.Issue)
+	}
+	problems, _ = Verify(st3.Dir)
+	fmt.Printf("  Verify: %d problem(s). The hash matches what was copied, so only the\n", len(problems))
+	fmt.Println("  magic-number check at copy time notices the .jpg stopped being a JPEG.")
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Content-Addressed Backups (-blobs) ---")
+	cas := filepath.Join(tmp, "cas")
+	blobOpts := opts
+	blobOpts.Blobs = true
+	writeTree(src, map[string]string{"docs/copy-of-new.md": "fresh\n"}) // docs/new.md's bytes
+	clock = clock.Add(time.Hour)
+	b1, err := Backup(src, cas, blobOpts)
+	if err != nil {
+		fmt.Println("backup:", err)
+		return
+	}
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nfunc main() { run() }\n"), 0o644)
+	clock = clock.Add(time.Hour)
+	b2, err := Backup(src, cas, blobOpts)
+	if err != nil {
+		fmt.Println("backup:", err)
+		return
+	}
+	store, _ := blobstore.Open(filepath.Join(cas, blobsName))
+	blobs, _ := store.All()
+	bm1, _ := readManifest(b1.Dir)
+	bm2, _ := readManifest(b2.Dir)
+	fmt.Printf("  2 backups, %d manifest entries, %d blobs: new.md and copy-of-new.md share one,\n",
+		len(bm1.Files)+len(bm2.Files), len(blobs))
+	fmt.Println("  and the second backup stored only the new main.go.")
+	problems, _ = Verify(b2.Dir)
+	fmt.Printf("  Verify %s: %d problem(s)\n", filepath.Base(b2.Dir), len(problems))
+
+	os.RemoveAll(b1.Dir)
+	c, err := GC(cas)
+	fmt.Printf("  deleted %s; gc removed %d blob(s), %d B (the old main.go), err=%v\n",
+		filepath.Base(b1.Dir), c.Blobs, c.Bytes, err)
+	var readme string
+	for _, f := range bm2.Files {
+		if f.Path == "README.md" {
+			readme = f.SHA256
+		}
+	}
+	os.WriteFile(filepath.Join(cas, blobsName, readme[:2], readme[2:4], readme), []byte("# PROJECT\n"), 0o644)
+	problems, _ = Verify(b2.Dir)
+	for _, p := range problems {
+		fmt.Printf("    ✗ %-14s %s (a flipped blob, found by reading it back)\n", p.Path, p.Issue)
+	}
+	fmt.Println("  One blob, shared by every backup that has README.md: the same trade as hard links.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. WalkDir + SkipDir prunes excluded directories without visiting them.
+2. Hash while copying with io.MultiWriter: one read, two results.
+3. Write the manifest LAST and atomically; no manifest = incomplete backup.
+4. Incremental: size+mtime is the cheap check, SHA-256 the decisive one.
+5. Hard links make incrementals nearly free but share corruption.
+6. A backup you have not verified is a hope, not a backup.
+7. Sniff magic numbers while copying: a hash can't tell garbage was saved.
+8. Name contents by their hash and each is stored once; gc what nothing lists.
+	`)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// Part 1: Schedules (a cron subset plus "@every")
+// ---------------------------------------------------------
+// Two spellings are accepted in the config file:
+//
+//   "@every 30s"       → fixed interval (any time.ParseDuration string)
+//   "0 3 * * *"        → classic 5-field cron: minute hour day month weekday
+//                        each field: *  |  */n  |  a  |  a-b  |  a,b,c
+//   "@hourly", "@daily" → shorthands for "0 * * * *" and "0 0 * * *"
+//
+// Next(t) returns the first activation strictly after t.
+
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+type every time.Duration
+
+func (e every) Next(after time.Time) time.Time { return after.Add(time.Duration(e)) }
+
+// cronSpec stores, for each field, the set of allowed values as a bitmask.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59}, {"hour", 0, 23}, {"day", 1, 31}, {"month", 1, 12}, {"weekday", 0, 6},
+}
+
+func ParseSchedule(s string) (Schedule, error) {
+	switch s = strings.TrimSpace(s); {
+	case strings.HasPrefix(s, "@every "):
+		d, err := time.ParseDuration(strings.TrimPrefix(s, "@every "))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("schedule %q: bad interval", s)
+		}
+		return every(d), nil
+	case s == "@hourly":
+		s = "0 * * * *"
+	case s == "@daily":
+		s = "0 0 * * *"
+	}
+
+	parts := strings.Fields(s)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("schedule %q: want 5 fields, got %d", s, len(parts))
+	}
+	var masks [5]uint64
+	for i, p := range parts {
+		m, err := parseField(p, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %s: %w", s, cronFields[i].name, err)
+		}
+		masks[i] = m
+	}
+	return &cronSpec{masks[0], masks[1], masks[2], masks[3], masks[4]}, nil
+}
+
+func parseField(f string, min, max int) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(f, ",") {
+		lo, hi, step := min, max, 1
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step %q", stepPart)
+			}
+			step = n
+		}
+		if rangePart != "*" {
+			a, b, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("bad value %q", a)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("bad value %q", b)
+				}
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+// Next walks forward minute by minute. At most a year of minutes (~525k
+// steps) is scanned; a spec like "0 0 31 2 *" (Feb 31) never fires.
+func (c *cronSpec) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.AddDate(1, 0, 0); t.Before(limit); t = t.Add(time.Minute) {
+		if c.month&(1<<int(t.Month())) != 0 &&
+			c.dom&(1<<t.Day()) != 0 &&
+			c.dow&(1<<int(t.Weekday())) != 0 &&
+			c.hour&(1<<t.Hour()) != 0 &&
+			c.minute&(1<<t.Minute()) != 0 {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/health"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/negotiate"
+)
+
+// ---------------------------------------------------------
+// Part 4: Config
+// ---------------------------------------------------------
+
+// Duration reads "30s" / "24h" from JSON (time.Duration itself wants nanoseconds).
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	*d = Duration(v)
+	return err
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) { return json.Marshal(time.Duration(d).String()) }
+
+type Config struct {
+	Listen string   `json:"listen"`
+	Tick   Duration `json:"tick"` // How often the scheduler looks for due jobs
+	Log    struct {
+		Path     string `json:"path"`
+		MaxBytes int64  `json:"max_bytes"`
+		Backups  int    `json:"backups"`
+	} `json:"log"`
+	Janitor struct {
+		Dir     string   `json:"dir"`
+		Pattern string   `json:"pattern"`
+		MaxAge  Duration `json:"max_age"`
+	} `json:"janitor"`
+	Jobs []JobConfig `json:"jobs"`
+}
+
+type JobConfig struct {
+	Name     string `json:"name"` // Must be a registered task: "janitor", "rotate-logs"
+	Schedule string `json:"schedule"`
+}
+
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Tick == 0 {
+		cfg.Tick = Duration(time.Second)
+	}
+	if cfg.Janitor.Dir == "" {
+		cfg.Janitor.Dir = os.TempDir()
+	}
+	if err := checkSweepDir(cfg.Janitor.Dir); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err) // Caught at startup and on reload, not at 3 a.m.
+	}
+	return cfg, nil
+}
+
+// ---------------------------------------------------------
+// Part 5: The Daemon Lifecycle
+// ---------------------------------------------------------
+//   start:   open log → build jobs → listen → mark READY
+//   run:     every tick, start due jobs (never two copies of the same job)
+//   reload:  SIGHUP re-reads the config; bad config keeps the old one
+//   stop:    ctx cancelled → NOT ready → wait for running jobs → close HTTP → close log
+
+type TaskFunc func(ctx context.Context, d *Daemon) (string, error)
+
+var tasks = map[string]TaskFunc{
+	"janitor": func(ctx context.Context, d *Daemon) (string, error) {
+		d.jobsMu.Lock() // reload may swap the janitor settings
+		j := d.cfg.Janitor
+		d.jobsMu.Unlock()
+		res, err := Janitor{Dir: j.Dir, Pattern: j.Pattern, MaxAge: time.Duration(j.MaxAge), DryRun: d.DryRun}.Sweep(time.Now())
+		if d.DryRun {
+			return fmt.Sprintf("dry run: would remove %v (%d B), kept %d", res.Removed, res.Bytes, res.Kept), err
+		}
+		return fmt.Sprintf("removed %d (%d B), kept %d", len(res.Removed), res.Bytes, res.Kept), err
+	},
+	"rotate-logs": func(ctx context.Context, d *Daemon) (string, error) {
+		if d.DryRun {
+			return "dry run: would rotate " + d.logw.Path, nil
+		}
+		return "rotated " + d.logw.Path, d.logw.Rotate()
+	},
+}
+
+type job struct {
+	JobConfig
+	schedule Schedule
+	task     TaskFunc
+
+	next     time.Time
+	running  atomic.Bool
+	mu       sync.Mutex // Guards the fields below
+	runs     int
+	lastRun  time.Time
+	lastErr  string
+	lastNote string
+}
+
+type JobStatus struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Next     time.Time `json:"next"`
+	Runs     int       `json:"runs"`
+	LastRun  time.Time `json:"last_run,omitzero"`
+	LastNote string    `json:"last_note,omitempty"`
+	LastErr  string    `json:"last_error,omitempty"`
+}
+
+type Daemon struct {
+	ConfigPath string
+	Reload     chan struct{} // Send to re-read ConfigPath (main wires SIGHUP here)
+	Started    chan string   // Receives the listen address once READY
+	// CaptureStdLog redirects the standard "log" package to the rotating
+	// file while running (set when detached: there is no terminal).
+	CaptureStdLog bool
+	// DryRun makes scheduled chores report what they would delete or
+	// rotate instead of doing it. The daemon's own log still grows.
+	DryRun bool
+
+	cfg       Config
+	jobs      []*job
+	logw      *RotatingWriter
+	log       *log.Logger
+	ready     atomic.Bool
+	heartbeat atomic.Int64 // Unix nanos of the last scheduler tick
+	wg        sync.WaitGroup
+	jobsMu    sync.Mutex
+}
+
+func NewDaemon(configPath string) *Daemon {
+	return &Daemon{ConfigPath: configPath, Reload: make(chan struct{}, 1), Started: make(chan string, 1)}
+}
+
+func buildJobs(cfg Config) ([]*job, error) {
+	var jobs []*job
+	for _, jc := range cfg.Jobs {
+		task, ok := tasks[jc.Name]
+		if !ok {
+			return nil, fmt.Errorf("job %q: unknown task", jc.Name)
+		}
+		s, err := ParseSchedule(jc.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", jc.Name, err)
+		}
+		jobs = append(jobs, &job{JobConfig: jc, schedule: s, task: task, next: s.Next(time.Now())})
+	}
+	return jobs, nil
+}
+
+// Run blocks until ctx is cancelled, then shuts down gracefully.
+func (d *Daemon) Run(ctx context.Context) error {
+	cfg, err := LoadConfig(d.ConfigPath)
+	if err != nil {
+		return err
+	}
+	jobs, err := buildJobs(cfg)
+	if err != nil {
+		return err
+	}
+	d.cfg, d.jobs = cfg, jobs
+
+	if d.logw, err = OpenRotating(cfg.Log.Path, cfg.Log.MaxBytes, cfg.Log.Backups); err != nil {
+		return err
+	}
+	defer d.logw.Close()
+	d.log = log.New(d.logw, "gotut ", log.LstdFlags|log.Lmsgprefix)
+	if d.CaptureStdLog {
+		log.SetOutput(d.logw) // Stray log.Printf calls from libraries land in our file
+		defer log.SetOutput(os.Stderr)
+	}
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: d.routes(), ReadHeaderTimeout: 5 * time.Second}
+	go srv.Serve(ln)
+
+	d.heartbeat.Store(time.Now().UnixNano())
+	d.ready.Store(true)
+	d.log.Printf("started on %s with %d job(s)", ln.Addr(), len(jobs))
+	d.Started <- ln.Addr().String()
+
+	ticker := time.NewTicker(time.Duration(cfg.Tick))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return d.shutdown(srv)
+		case <-d.Reload:
+			d.reload()
+		case now := <-ticker.C:
+			d.heartbeat.Store(now.UnixNano())
+			d.runDue(ctx, now)
+		}
+	}
+}
+
+func (d *Daemon) runDue(ctx context.Context, now time.Time) {
+	d.jobsMu.Lock()
+	defer d.jobsMu.Unlock()
+	for _, j := range d.jobs {
+		if now.Before(j.next) {
+			continue
+		}
+		j.next = j.schedule.Next(now)
+		if !j.running.CompareAndSwap(false, true) {
+			d.log.Printf("job %s: still running, skipping this slot", j.Name)
+			continue
+		}
+		d.wg.Add(1)
+		go func(j *job) {
+			defer d.wg.Done()
+			defer j.running.Store(false)
+			note, err := j.task(ctx, d)
+			j.mu.Lock()
+			j.runs++
+			j.lastRun, j.lastNote, j.lastErr = time.Now(), note, ""
+			if err != nil {
+				j.lastErr = err.Error()
+			}
+			j.mu.Unlock()
+			d.log.Printf("job %s: %s err=%v", j.Name, note, err)
+		}(j)
+	}
+}
+
+func (d *Daemon) reload() {
+	cfg, err := LoadConfig(d.ConfigPath)
+	if err == nil {
+		var jobs []*job
+		if jobs, err = buildJobs(cfg); err == nil {
+			d.jobsMu.Lock()
+			d.cfg.Janitor, d.jobs = cfg.Janitor, jobs // Listen/log changes need a restart
+			d.jobsMu.Unlock()
+			d.log.Printf("reloaded config: %d job(s)", len(jobs))
+			return
+		}
+	}
+	d.log.Printf("reload rejected, keeping old config: %v", err)
+}
+
+func (d *Daemon) shutdown(srv *http.Server) error {
+	d.ready.Store(false) // Load balancers stop sending traffic first
+	d.log.Printf("shutting down: waiting for running jobs")
+
+	done := make(chan struct{})
+	go func() { d.wg.Wait(); close(done) }()
+	var errs []error
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		errs = append(errs, errors.New("jobs did not finish within 10s"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errs = append(errs, srv.Shutdown(ctx))
+	d.log.Printf("stopped")
+	return errors.Join(errs...)
+}
+
+// ---------------------------------------------------------
+// Part 6: Health and Status Endpoints
+// ---------------------------------------------------------
+//   /healthz → live if the scheduler ticked recently (a stuck loop = restart me)
+//   /readyz  → ready between startup and the start of shutdown
+//              (both pkg/health registries, as in Topic 142)
+//   /status  → every job: JSON for scripts and curl, an HTML table for a
+//              browser, chosen by the Accept header (Topic 188)
+
+func (d *Daemon) routes() http.Handler {
+	checks := &health.Registry{}
+	checks.Register("scheduler", health.Liveness, time.Second, func(context.Context) error {
+		if age := time.Since(time.Unix(0, d.heartbeat.Load())); age > 3*time.Duration(d.cfg.Tick) {
+			return fmt.Errorf("stalled for %v", age.Round(time.Millisecond))
+		}
+		return nil
+	})
+	checks.Register("lifecycle", health.Readiness, time.Second, func(context.Context) error {
+		if !d.ready.Load() {
+			return errors.New("starting or shutting down")
+		}
+		return nil
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", checks.Handler(health.Liveness))
+	mux.Handle("/readyz", checks.Handler(health.Readiness))
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept") // The same URL has two bodies: caches must key on Accept
+		switch negotiate.Best(r.Header.Get("Accept"), statusTypes) {
+		case "application/json":
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.Encode(d.Status())
+		case "text/html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			statusPage.Execute(w, d.Status())
+		default:
+			http.Error(w, fmt.Sprintf("/status is available as %v", statusTypes), http.StatusNotAcceptable)
+		}
+	})
+	return mux
+}
+
+// statusTypes are what /status can send, JSON first: curl's "*/*" and a
+// request with no Accept header get what they always got.
+var statusTypes = []string{"application/json", "text/html"}
+
+var statusPage = template.Must(template.New("status").Parse(`<!doctype html>
+<title>gotut daemon</title>
+<table>
+<tr><th>job</th><th>schedule</th><th>runs</th><th>last run</th><th>next</th><th>note</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Schedule}}</td><td>{{.Runs}}</td><td>{{if not .LastRun.IsZero}}{{.LastRun.Format "15:04:05"}}{{end}}</td><td>{{.Next.Format "15:04:05"}}</td><td>{{with .LastErr}}error: {{.}}{{else}}{{.LastNote}}{{end}}</td></tr>
+{{end}}</table>
+`))
+
+func (d *Daemon) Status() []JobStatus {
+	d.jobsMu.Lock()
+	defer d.jobsMu.Unlock()
+	out := make([]JobStatus, 0, len(d.jobs))
+	for _, j := range d.jobs {
+		j.mu.Lock()
+		out = append(out, JobStatus{j.Name, j.Schedule, j.next, j.runs, j.lastRun, j.lastNote, j.lastErr})
+		j.mu.Unlock()
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
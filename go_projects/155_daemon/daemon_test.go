@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStatusNegotiation checks /status answers by the Accept header: JSON
+// for scripts and curl, HTML for a browser, 406 for anything else.
+func TestStatusNegotiation(t *testing.T) {
+	d := NewDaemon("unused.json")
+	d.jobs = []*job{{JobConfig: JobConfig{Name: "janitor", Schedule: "@every 1m"}, next: time.Now()}}
+	h := d.routes()
+
+	for _, tc := range []struct {
+		accept, wantType string
+		wantCode         int
+	}{
+		{"", "application/json", http.StatusOK},
+		{"*/*", "application/json", http.StatusOK},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "text/html; charset=utf-8", http.StatusOK},
+		{"application/xml", "text/plain; charset=utf-8", http.StatusNotAcceptable},
+	} {
+		req := httptest.NewRequest("GET", "/status", nil)
+		if tc.accept != "" {
+			req.Header.Set("Accept", tc.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.wantCode || rec.Header().Get("Content-Type") != tc.wantType {
+			t.Errorf("Accept %q: %d %s, want %d %s", tc.accept, rec.Code, rec.Header().Get("Content-Type"), tc.wantCode, tc.wantType)
+		}
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: no Vary: Accept, so a cache could serve the wrong body", tc.accept)
+		}
+		switch body := rec.Body.String(); tc.wantType {
+		case "application/json":
+			var st []JobStatus
+			if err := json.Unmarshal([]byte(body), &st); err != nil || len(st) != 1 || st[0].Name != "janitor" {
+				t.Errorf("Accept %q: JSON body %q (%v)", tc.accept, body, err)
+			}
+		case "text/html; charset=utf-8":
+			if !strings.Contains(body, "<td>janitor</td>") {
+				t.Errorf("Accept %q: HTML body has no janitor row:\n%s", tc.accept, body)
+			}
+		}
+	}
+}
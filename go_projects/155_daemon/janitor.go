@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/fileops"
+)
+
+// ---------------------------------------------------------
+// Part 2: The Temp-File Janitor
+// ---------------------------------------------------------
+// Lessons and tools create gotut-* directories in os.TempDir(). A crash or
+// Ctrl-C skips their deferred RemoveAll, so leftovers pile up. The janitor
+// deletes entries matching Pattern that have not been modified for MaxAge.
+// With DryRun set it still decides and reports, but deletes nothing (the
+// approach of 174_fileops).
+//
+// Dir and Pattern come from a config file, so neither is trusted. A
+// janitor pointed at "/" or a home directory refuses to sweep at all. A
+// pattern is one name: a separator or ".." in it would let Glob reach
+// past Dir ("../*", "*/*"), so it is refused before anything is matched.
+// Every match is checked to be inside Dir anyway, and deleted through
+// pkg/fileops' SafeRemoveAll with Dir as its root.
+
+type Janitor struct {
+	Dir     string        // Usually os.TempDir()
+	Pattern string        // filepath.Match pattern, e.g. "gotut-*"
+	MaxAge  time.Duration // Younger entries may still be in use
+	DryRun  bool          // Report what would be removed, remove nothing
+}
+
+type SweepResult struct {
+	Removed []string // In a dry run: what would have been removed
+	Kept    int
+	Bytes   int64
+}
+
+func (j Janitor) Sweep(now time.Time) (SweepResult, error) {
+	var res SweepResult
+	if err := checkSweepDir(j.Dir); err != nil {
+		return res, err
+	}
+	if j.Pattern == "" || strings.Contains(j.Pattern, "..") ||
+		strings.ContainsRune(j.Pattern, '/') || strings.ContainsRune(j.Pattern, filepath.Separator) {
+		return res, fmt.Errorf("%w: %q", errBadPattern, j.Pattern)
+	}
+	matches, err := filepath.Glob(filepath.Join(j.Dir, j.Pattern))
+	if err != nil {
+		return res, err
+	}
+	for _, m := range matches {
+		if rel, err := filepath.Rel(j.Dir, m); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return res, fmt.Errorf("%w: %s is not inside %s", errBadPattern, m, j.Dir)
+		}
+		info, err := os.Lstat(m)
+		if err != nil {
+			continue // Vanished meanwhile: someone else cleaned up
+		}
+		if now.Sub(info.ModTime()) < j.MaxAge {
+			res.Kept++
+			continue
+		}
+		size := dirSize(m)
+		if err := fileops.SafeRemoveAll(m, fileops.SafeOptions{Ops: fileops.Ops{DryRun: j.DryRun}, Root: j.Dir}); err != nil {
+			return res, fmt.Errorf("janitor: %w", err)
+		}
+		res.Removed = append(res.Removed, filepath.Base(m))
+		res.Bytes += size
+	}
+	return res, nil
+}
+
+var (
+	errProtectedDir = errors.New("janitor: refusing to sweep a protected directory")
+	errBadPattern   = errors.New("janitor: pattern must name entries directly in dir")
+)
+
+// checkSweepDir refuses an empty Dir, a filesystem root, the home directory
+// and any directory above home. SafeRemoveAll judges each path, not the
+// root it is given, so this is the check on the root.
+func checkSweepDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("%w: empty dir", errProtectedDir)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if r, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = r
+	}
+	if abs == filepath.VolumeName(abs)+string(filepath.Separator) {
+		return fmt.Errorf("%w: %s", errProtectedDir, abs)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if h, err := filepath.EvalSymlinks(home); err == nil {
+			home = h
+		}
+		if rel, err := filepath.Rel(abs, home); err == nil && !strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%w: %s contains your home directory", errProtectedDir, abs)
+		}
+	}
+	return nil
+}
+
+func dirSize(p string) int64 {
+	var n int64
+	filepath.Walk(p, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			n += info.Size()
+		}
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// junkDir holds two stale gotut-* dirs, one fresh one and an unrelated one.
+func junkDir(t *testing.T, now time.Time) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"gotut-old-1", "gotut-old-2", "gotut-fresh", "keep-me"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "data"), make([]byte, 100), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := now.Add(-48 * time.Hour)
+	for _, name := range []string{"gotut-old-1", "gotut-old-2", "keep-me"} {
+		os.Chtimes(filepath.Join(dir, name), old, old)
+	}
+	return dir
+}
+
+func names(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Name())
+	}
+	return out
+}
+
+// listTree maps every path under dir to its size and mtime.
+func listTree(dir string) map[string]string {
+	m := map[string]string{}
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err == nil {
+			m[p] = fmt.Sprint(info.Size(), info.ModTime())
+		}
+		return nil
+	})
+	return m
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Now()
+	for _, dryRun := range []bool{false, true} {
+		dir := junkDir(t, now)
+		before := listTree(dir)
+		res, err := Janitor{Dir: dir, Pattern: "gotut-*", MaxAge: 24 * time.Hour, DryRun: dryRun}.Sweep(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"gotut-old-1", "gotut-old-2"}; !slices.Equal(res.Removed, want) || res.Kept != 1 || res.Bytes != 200 {
+			t.Errorf("dry run %v: Sweep = %+v, want Removed %v, Kept 1, Bytes 200", dryRun, res, want)
+		}
+		left := names(t, dir)
+		switch {
+		case dryRun && !maps.Equal(before, listTree(dir)):
+			t.Errorf("dry run changed the directory: left %v", left)
+		case !dryRun && !slices.Equal(left, []string{"gotut-fresh", "keep-me"}):
+			t.Errorf("after Sweep: %v, want [gotut-fresh keep-me]", left)
+		}
+	}
+}
+
+func TestSweepRefusesProtectedDirs(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory:", err)
+	}
+	for _, dir := range []string{"", "/", home, filepath.Dir(home)} {
+		res, err := Janitor{Dir: dir, Pattern: "*", DryRun: true}.Sweep(time.Now())
+		if !errors.Is(err, errProtectedDir) || len(res.Removed) != 0 {
+			t.Errorf("Sweep(%q) = %+v, %v; want errProtectedDir", dir, res, err)
+		}
+	}
+}
+
+func TestSweepRefusesPatternsOutsideDir(t *testing.T) {
+	now := time.Now()
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "tmp")
+	victim := filepath.Join(parent, "victim")
+	for _, d := range []string{filepath.Join(dir, "gotut-x", "sub"), victim} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := now.Add(-48 * time.Hour)
+	for _, d := range []string{victim, filepath.Join(dir, "gotut-x", "sub")} {
+		os.Chtimes(d, old, old)
+	}
+	for _, pattern := range []string{"", "../*", "../victim", "..", "*/*", "gotut-x/sub", string(filepath.Separator) + "*"} {
+		res, err := Janitor{Dir: dir, Pattern: pattern}.Sweep(now)
+		if !errors.Is(err, errBadPattern) || len(res.Removed) != 0 {
+			t.Errorf("Sweep(pattern %q) = %+v, %v; want errBadPattern", pattern, res, err)
+		}
+	}
+	for _, d := range []string{victim, filepath.Join(dir, "gotut-x", "sub")} {
+		if _, err := os.Stat(d); err != nil {
+			t.Errorf("%s was removed: %v", d, err)
+		}
+	}
+}
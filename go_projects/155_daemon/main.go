@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+/*
+TOPIC: DAEMON MODE — SCHEDULED MAINTENANCE IN ONE LONG-RUNNING PROCESS
+
+CONCEPT:
+Short programs run and exit. A DAEMON runs until told to stop, doing chores
+on a schedule. This capstone puts the operational pieces together:
+
+    cron.go     → "@every 30s" and "0 3 * * *" schedules
+    janitor.go  → delete stale gotut-* temp dirs (Topic 88 leftovers)
+    rotate.go   → size- and schedule-based log rotation (Topic 93)
+    daemon.go   → config file, lifecycle, health endpoints (Topic 142),
+                  /status as JSON or HTML by Accept header (Topic 188)
+    main.go     → this walkthrough and the "daemon" command
+    janitor_test.go → sweeps for real and with --dry-run (go test .)
+    daemon_test.go  → /status picks JSON, HTML or 406 from Accept
+
+LIFECYCLE (what every well-behaved service does):
+    1. Load and VALIDATE config before doing anything else.
+    2. Open resources (log file, listener); report READY.
+    3. Loop: tick → run due jobs. Never overlap two runs of one job.
+    4. SIGHUP → reload config. A broken file keeps the old config.
+    5. SIGINT/SIGTERM → NOT ready → finish jobs → close server → close log.
+
+RUN (a multi-file package):
+    cd go_projects/155_daemon
+    go run .                              → guided demo
+    go run . daemon -config gotut.json --foreground   → run for real
+    go run . daemon --dry-run --foreground   → jobs only report
+    curl localhost:8088/status ; kill -HUP <pid> ; kill <pid>
+    Detaching, PID files and unit files: see service.go (go run . service).
+*/
+
+func runDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	config := fs.String("config", "gotut.json", "path to the JSON config")
+	foreground := fs.Bool("foreground", false, "stay attached (use under systemd/launchd)")
+	pidPath := fs.String("pidfile", "", "lock this file and write our PID into it")
+	dryRun := fs.Bool("dry-run", false, "jobs log what they would delete or rotate, and change nothing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if !*foreground && !isDetachedChild() {
+		cfg, err := LoadConfig(*config) // Fail here, in the terminal, not silently in the child
+		if err != nil {
+			return err
+		}
+		if *pidPath == "" {
+			return errors.New("detaching needs -pidfile (or pass --foreground)")
+		}
+		probe, err := AcquirePIDFile(*pidPath) // Report "already running" here, not in the child's log
+		if err != nil {
+			return err
+		}
+		probe.Release()
+		pid, err := detach(append([]string{"daemon"}, args...), cfg.Log.Path+".stderr", *pidPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("started in background, pid %d\n", pid)
+		return nil
+	}
+
+	if *pidPath != "" {
+		pf, err := AcquirePIDFile(*pidPath)
+		if err != nil {
+			return err
+		}
+		defer pf.Release()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	d := NewDaemon(*config)
+	d.CaptureStdLog = isDetachedChild()
+	d.DryRun = *dryRun
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			select {
+			case d.Reload <- struct{}{}:
+			default: // A reload is already pending
+			}
+		}
+	}()
+	go func() { fmt.Println("listening on", <-d.Started) }()
+	err := d.Run(ctx)
+	signal.Stop(hup)
+	close(hup)
+	return err
+}
+
+func get(base, path string) string {
+	resp, err := http.Get(base + path)
+	if err != nil {
+		return err.Error()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Sprintf("%d %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// contentType GETs url with an Accept header and reports what came back.
+func contentType(url, accept string) string {
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("Accept", accept)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err.Error()
+	}
+	resp.Body.Close()
+	return fmt.Sprintf("%d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+}
+
+const demoConfig = `{
+  "listen": "127.0.0.1:0",
+  "tick": "50ms",
+  "log": {"path": %q, "max_bytes": 1024, "backups": 2},
+  "janitor": {"dir": %q, "pattern": "gotut-*", "max_age": "24h"},
+  "jobs": [
+    {"name": "janitor", "schedule": "@every 200ms"},
+    {"name": "rotate-logs", "schedule": %q}
+  ]
+}`
+
+func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "daemon":
+			err = runDaemon(os.Args[2:])
+		case "service":
+			err = serviceDemo()
+		case "unit":
+			switch strings.Join(os.Args[2:], " ") {
+			case "systemd":
+				fmt.Print(systemdUnit)
+			case "launchd":
+				fmt.Print(launchdPlist)
+			default:
+				err = errors.New("usage: unit systemd|launchd")
+			}
+		default:
+			err = fmt.Errorf("unknown command %q (want daemon, service or unit)", os.Args[1])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, os.Args[1]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: DAEMON MODE — SCHEDULED MAINTENANCE")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Parsing Schedules ---")
+	from := time.Date(2026, 10, 16, 14, 7, 0, 0, time.UTC) // A Friday
+	for _, spec := range []string{"@every 90s", "*/15 * * * *", "0 3 * * *", "30 9 * * 1-5", "@hourly", "61 * * * *", "* * *"} {
+		s, err := ParseSchedule(spec)
+		if err != nil {
+			fmt.Printf("  %-14s ✗ %v\n", spec, err)
+			continue
+		}
+		fmt.Printf("  %-14s next after %s → %s\n", spec, from.Format("Mon 15:04"), s.Next(from).Format("Mon 15:04:05"))
+	}
+	fmt.Println()
+
+	tmp, err := os.MkdirTemp("", "demo-daemon-*") // Not gotut-*: the janitor must not eat it
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+	junk := filepath.Join(tmp, "junk")
+	for _, name := range []string{"gotut-old-1", "gotut-old-2", "gotut-fresh", "keep-me"} {
+		os.MkdirAll(filepath.Join(junk, name), 0o755)
+		os.WriteFile(filepath.Join(junk, name, "data"), make([]byte, 500), 0o644)
+	}
+	twoDaysAgo := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(filepath.Join(junk, "gotut-old-1"), twoDaysAgo, twoDaysAgo)
+	os.Chtimes(filepath.Join(junk, "gotut-old-2"), twoDaysAgo, twoDaysAgo)
+
+	logPath := filepath.Join(tmp, "logs", "gotut.log")
+	cfgPath := filepath.Join(tmp, "gotut.json")
+	os.WriteFile(cfgPath, fmt.Appendf(nil, demoConfig, logPath, junk, "@every 300ms"), 0o644)
+
+	fmt.Println("--- Example 2: Start, Probe, Let Jobs Run ---")
+	preview, _ := Janitor{Dir: junk, Pattern: "gotut-*", MaxAge: 24 * time.Hour, DryRun: true}.Sweep(time.Now())
+	fmt.Printf("  janitor dry run: would remove %v, keep %d (daemon --dry-run does this on schedule)\n", preview.Removed, preview.Kept)
+	ctx, cancel := context.WithCancel(context.Background())
+	d := NewDaemon(cfgPath)
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+	var base string
+	select {
+	case addr := <-d.Started:
+		base = "http://" + addr
+	case err := <-done:
+		fmt.Println("  daemon failed to start:", err)
+		return
+	}
+	fmt.Println("  /healthz →", get(base, "/healthz"))
+	fmt.Println("  /readyz  →", get(base, "/readyz"))
+	time.Sleep(700 * time.Millisecond)
+	for _, s := range d.Status() {
+		fmt.Printf("  job %-12s runs=%d last=%q\n", s.Name, s.Runs, s.LastNote)
+	}
+	entries, _ := os.ReadDir(junk)
+	var left []string
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	fmt.Printf("  temp dir after janitor: %v\n", left)
+	for _, accept := range []string{"", "text/html,application/xhtml+xml,*/*;q=0.8", "application/xml"} {
+		fmt.Printf("  /status, Accept %-44q → %s\n", accept, contentType(base+"/status", accept))
+	}
+	fmt.Println("  curl and scripts get JSON, a browser gets a table, and the rest a 406.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Reload With a Broken Config ---")
+	os.WriteFile(cfgPath, fmt.Appendf(nil, demoConfig, logPath, junk, "99 * * * *"), 0o644)
+	d.Reload <- struct{}{}
+	time.Sleep(100 * time.Millisecond)
+	fmt.Printf("  jobs still scheduled: %d (old config kept)\n\n", len(d.Status()))
+
+	fmt.Println("--- Example 4: Graceful Shutdown ---")
+	cancel()
+	err = <-done
+	fmt.Printf("  Run returned: %v\n", err)
+	fmt.Println("  /readyz  →", get(base, "/readyz"))
+	logs, _ := filepath.Glob(logPath + "*")
+	for _, l := range logs {
+		info, _ := os.Stat(l)
+		fmt.Printf("  %-12s %4d B\n", filepath.Base(l), info.Size())
+	}
+	data, _ := os.ReadFile(logPath)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	fmt.Println("  last lines of the current log:")
+	for _, l := range lines[max(0, len(lines)-3):] {
+		fmt.Println("   ", l[strings.Index(l, "gotut"):]) // Drop the timestamp
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. One select loop: ctx.Done, reload requests, scheduler ticks.
+2. Validate config up front; on reload, keep the old config if the new one is bad.
+3. Skip a job's slot if the previous run is still going.
+4. Liveness = "the loop is ticking"; readiness = "between start and shutdown".
+5. Shutdown order: stop traffic → finish work → close server → close log.
+6. Janitors and log rotation keep a long-running process from filling the disk.
+	`)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ---------------------------------------------------------
+// Part 3: The Rotating Log Writer
+// ---------------------------------------------------------
+// Topic 93 recommends rotation and points at lumberjack; this is the core of
+// it in ~60 lines. The current file is Path; older ones are Path.1 (newest)
+// up to Path.N (oldest). Rotation happens when a write would exceed
+// MaxBytes, or when the scheduler calls Rotate (e.g. nightly).
+
+type RotatingWriter struct {
+	Path     string
+	MaxBytes int64
+	Backups  int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func OpenRotating(path string, maxBytes int64, backups int) (*RotatingWriter, error) {
+	w := &RotatingWriter{Path: path, MaxBytes: maxBytes, Backups: backups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return w, w.open()
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := os.OpenFile(w.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f, w.size = f, info.Size()
+	return nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.MaxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.MaxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate forces a rotation; an empty current file is left alone.
+func (w *RotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size == 0 {
+		return nil
+	}
+	return w.rotate()
+}
+
+// rotate shifts Path.i → Path.i+1 (dropping the oldest) and reopens Path.
+func (w *RotatingWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	os.Remove(fmt.Sprintf("%s.%d", w.Path, w.Backups))
+	for i := w.Backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.Path, i), fmt.Sprintf("%s.%d", w.Path, i+1))
+	}
+	if w.Backups > 0 {
+		if err := os.Rename(w.Path, w.Path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(w.Path); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
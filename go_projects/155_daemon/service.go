@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+TOPIC: RUNNING THE DAEMON AS A BACKGROUND SERVICE
+
+CONCEPT:
+"Run in the background" means different things to different supervisors:
+
+    By hand (classic UNIX)   → the program DETACHES itself: starts a copy in a
+                               new session, parent exits, shell prompt returns.
+    systemd / launchd        → the supervisor IS the background. The program
+                               must stay in the FOREGROUND, log to stdout/stderr,
+                               and exit on SIGTERM. Detaching confuses them.
+    Windows services         → the Service Control Manager starts the binary
+                               and talks to it through golang.org/x/sys/windows/svc.
+                               Out of scope here; we only detach from the console.
+
+So the daemon detaches by default and offers --foreground for supervisors.
+
+PID FILES:
+A PID file tells scripts which process to signal. A plain file is not
+enough: after a crash the file stays behind and a new start refuses to run,
+or two starts race and both think they won. We hold an OS-level LOCK on the
+file for the whole lifetime of the process; the OS releases it on exit,
+crash included. The lock, not the file's existence, means "running".
+
+OS-SPECIFIC CODE LIVES BEHIND BUILD TAGS:
+    service_unix.go     //go:build unix              flock, Setsid
+    service_windows.go  //go:build windows           CreateFile share modes, DETACHED_PROCESS
+    service_other.go    //go:build !unix && !windows stubs
+The rest of the code calls openLocked / releaseLocked / detachAttr / terminate
+and never mentions an operating system.
+
+LOGS:
+A detached process has no terminal. Everything from the daemon's logger goes
+to the rotating writer (rotate.go), the standard "log" package is redirected
+there too, and raw stderr (panics, runtime errors) goes to <log>.stderr.
+
+RUN:
+    go run . daemon -config gotut.json -pidfile gotut.pid   → detach
+    go run . daemon -config gotut.json --foreground           → for systemd
+    go run . service                                           → guided demo
+    go run . unit systemd|launchd                              → print a unit file
+*/
+
+// ---------------------------------------------------------
+// Part 7: PID Files With Locking
+// ---------------------------------------------------------
+
+var errLocked = errors.New("pid file is locked")
+
+type AlreadyRunningError struct {
+	Path string
+	PID  int // 0 if the file could not be read
+}
+
+func (e *AlreadyRunningError) Error() string {
+	return fmt.Sprintf("already running (pid %d, %s)", e.PID, e.Path)
+}
+
+type PIDFile struct {
+	f *os.File
+}
+
+// AcquirePIDFile locks path and writes our PID into it.
+func AcquirePIDFile(path string) (*PIDFile, error) {
+	f, err := openLocked(path)
+	if errors.Is(err, errLocked) {
+		pid, _ := ReadPID(path)
+		return nil, &AlreadyRunningError{Path: path, PID: pid}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(0); err != nil { // A stale PID from a crash may be longer
+		releaseLocked(f)
+		return nil, err
+	}
+	if _, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		releaseLocked(f)
+		return nil, err
+	}
+	return &PIDFile{f: f}, nil
+}
+
+func (p *PIDFile) Release() error { return releaseLocked(p.f) }
+
+func ReadPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// ---------------------------------------------------------
+// Part 8: Detaching
+// ---------------------------------------------------------
+// Go cannot fork() safely (the runtime has many threads), so "detach" means:
+// start a fresh copy of ourselves with the same arguments, marked by an
+// environment variable, with stdin closed and stdout/stderr sent to a file.
+
+const childEnv = "GOTUT_DAEMON_CHILD"
+
+func isDetachedChild() bool { return os.Getenv(childEnv) == "1" }
+
+// detach starts the background copy and waits until it has taken the PID
+// file, so "started" really means started.
+func detach(args []string, stderrPath, pidPath string) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(stderrPath), 0o755); err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(stderrPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close() // The child has its own copy of the descriptor
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), childEnv+"=1")
+	cmd.Stdout, cmd.Stderr = out, out
+	cmd.SysProcAttr = detachAttr()
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	deadline := time.After(5 * time.Second)
+	for {
+		if pid, err := ReadPID(pidPath); err == nil && pid == cmd.Process.Pid {
+			return pid, nil
+		}
+		select {
+		case err := <-exited:
+			return 0, fmt.Errorf("daemon exited during startup (%v); see %s", err, stderrPath)
+		case <-deadline:
+			return cmd.Process.Pid, fmt.Errorf("daemon did not write %s within 5s", pidPath)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 9: Unit Files for Supervisors
+// ---------------------------------------------------------
+
+const systemdUnit = `[Unit]
+Description=gotut maintenance daemon
+After=network.target
+
+[Service]
+Type=simple
+ExecStart=/usr/local/bin/gotut daemon -config /etc/gotut/gotut.json --foreground
+ExecReload=/bin/kill -HUP $MAINPID
+Restart=on-failure
+# No PIDFile=: systemd tracks the process itself.
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const launchdPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key><string>dev.gotut.daemon</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>/usr/local/bin/gotut</string><string>daemon</string>
+    <string>-config</string><string>/usr/local/etc/gotut.json</string>
+    <string>--foreground</string>
+  </array>
+  <key>KeepAlive</key><true/>
+  <key>StandardErrorPath</key><string>/usr/local/var/log/gotut.stderr</string>
+</dict>
+</plist>
+`
+
+// ---------------------------------------------------------
+// Part 10: Guided Demo (go run . service)
+// ---------------------------------------------------------
+
+func freePort() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	return ln.Addr().String(), nil
+}
+
+func serviceDemo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: RUNNING THE DAEMON AS A BACKGROUND SERVICE")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	tmp, err := os.MkdirTemp("", "demo-service-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	addr, err := freePort()
+	if err != nil {
+		return err
+	}
+	cfgPath, pidPath := filepath.Join(tmp, "gotut.json"), filepath.Join(tmp, "gotut.pid")
+	logPath := filepath.Join(tmp, "logs", "gotut.log")
+	cfg := strings.Replace(fmt.Sprintf(demoConfig, logPath, tmp, "@daily"), `"127.0.0.1:0"`, strconv.Quote(addr), 1)
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	start := func() (string, error) {
+		out, err := exec.Command(exe, "daemon", "-config", cfgPath, "-pidfile", pidPath).CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	fmt.Println("--- Example 1: Start Detached ---")
+	out, err := start()
+	if err != nil {
+		return fmt.Errorf("start: %v: %s", err, out)
+	}
+	pid, _ := ReadPID(pidPath)
+	fmt.Printf("  parent said: %s\n  parent has exited; pid file says %d\n", out, pid)
+	fmt.Println("  /readyz →", get("http://"+addr, "/readyz"))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: A Second Start Is Refused ---")
+	out, err = start()
+	fmt.Printf("  %s (exit error: %v)\n\n", out, err != nil)
+
+	fmt.Println("--- Example 3: Stop It Like a Supervisor Would ---")
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := terminate(proc); err != nil {
+		return err
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(pidPath); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	_, statErr := os.Stat(pidPath)
+	fmt.Printf("  pid file removed: %v\n", errors.Is(statErr, os.ErrNotExist))
+	_, httpErr := http.Get("http://" + addr + "/readyz")
+	fmt.Printf("  server gone: %v\n", httpErr != nil)
+	logData, err := os.ReadFile(logPath)
+	if err != nil {
+		return fmt.Errorf("reading the daemon's log: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	fmt.Printf("  last log line: %s\n", lines[len(lines)-1][strings.Index(lines[len(lines)-1], "gotut"):])
+	stderr, err := os.ReadFile(logPath + ".stderr")
+	if err != nil {
+		return fmt.Errorf("reading the daemon's stderr: %w", err)
+	}
+	fmt.Printf("  %s: %d bytes (panics would land here)\n\n", filepath.Base(logPath)+".stderr", len(stderr))
+
+	fmt.Println("--- Example 4: Stale PID File After a Crash ---")
+	os.WriteFile(pidPath, []byte("999999\n"), 0o644) // Left behind, nobody holds the lock
+	p, err := AcquirePIDFile(pidPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  acquired despite the stale file; now contains %d (us)\n", func() int { n, _ := ReadPID(pidPath); return n }())
+	p.Release()
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Detach by re-executing yourself in a new session; Go cannot fork().
+2. Under systemd/launchd stay in the foreground: --foreground.
+3. A PID file means nothing without a lock; the OS frees the lock on exit.
+4. Redirect stderr to a file: a detached panic is otherwise invisible.
+5. Keep OS differences in *_unix.go / *_windows.go behind //go:build.
+	`)
+	return nil
+}
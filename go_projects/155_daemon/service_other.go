@@ -0,0 +1,20 @@
+//go:build !unix && !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Platforms such as wasip1 or plan9 have neither flock nor detached
+// processes. The daemon still runs with --foreground and no PID file.
+
+func openLocked(path string) (*os.File, error) { return nil, errors.ErrUnsupported }
+
+func releaseLocked(f *os.File) error { return f.Close() }
+
+func detachAttr() *syscall.SysProcAttr { return nil }
+
+func terminate(p *os.Process) error { return p.Kill() }
@@ -0,0 +1,47 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openLocked opens the PID file and takes an exclusive, non-blocking flock.
+// The kernel drops the lock when the process dies, however it dies, so a
+// leftover file from a crash never blocks the next start.
+func openLocked(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLocked
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// releaseLocked removes the file while still holding the lock: unlocking
+// first would let a new instance lock the old inode we are about to unlink.
+func releaseLocked(f *os.File) error {
+	err := os.Remove(f.Name())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// detachAttr starts the child in a new session: no controlling terminal,
+// so closing the shell (SIGHUP to the session) does not kill it.
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// terminate asks the process to shut down gracefully (the daemon's
+// signal.NotifyContext turns SIGTERM into ctx cancellation).
+func terminate(p *os.Process) error { return p.Signal(syscall.SIGTERM) }
@@ -0,0 +1,57 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+const errorSharingViolation syscall.Errno = 32
+
+// openLocked opens the PID file with no write sharing: while we hold the
+// handle, no other process can open it for writing. Windows closes the
+// handle when the process exits, which releases the "lock".
+func openLocked(path string) (*os.File, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(p,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		syscall.FILE_SHARE_READ, // Others may read the PID, not write it
+		nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if errors.Is(err, errorSharingViolation) {
+		return nil, errLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
+
+// releaseLocked must close before removing: Windows refuses to delete a
+// file that has an open handle without FILE_SHARE_DELETE.
+func releaseLocked(f *os.File) error {
+	err := f.Close()
+	if rerr := os.Remove(f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// detachAttr starts the child without a console window, in its own process
+// group so Ctrl-C in the parent's console does not reach it.
+func detachAttr() *syscall.SysProcAttr {
+	const detachedProcess = 0x00000008
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
+
+// terminate has no graceful option for a plain detached process on Windows:
+// os.Process.Signal supports only Kill. Real services receive a stop request
+// from the Service Control Manager instead.
+func terminate(p *os.Process) error { return p.Kill() }
@@ -0,0 +1,625 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*
+TOPIC: A RESOURCE-CLEANUP LINTER WITH go/ast
+
+CONCEPT:
+Some calls hand you something that MUST be released:
+
+    f, err := os.Open(name)        → f.Close()
+    f, err := os.Create(name)      → f.Close()   (and CHECK its error!)
+    w := bufio.NewWriter(f)        → w.Flush()   (or the tail of the data is lost)
+
+Forgetting is easy and the program usually still "works" — until it runs out
+of file descriptors or silently writes truncated files. A STATIC ANALYZER can
+find these by reading the source, without running it:
+
+    1. go/parser turns a .go file into an AST (abstract syntax tree).
+    2. ast.Inspect visits every node.
+    3. For each function, record acquisitions, then look for a release.
+
+(New to go/parser and go/ast? Topic 158 introduces them on a small file.)
+
+THE RULES:
+    cleanup/missing      An acquisition with no Close/Flush anywhere in the
+                         function, and the value does not escape (returned,
+                         stored in a struct, passed to a goroutine...).
+    cleanup/discarded    The result is thrown away: os.Create(...) or _, err :=.
+    cleanup/write-close  defer f.Close() on a file opened for WRITING: the
+                         Close error (which may be the write error!) is lost.
+
+A second analyzer in the same framework looks for errors thrown away:
+    errors/discarded     data, _ := os.ReadFile(name) — the error went to _.
+    errors/unchecked-write
+                         f.WriteString(...) or w.Flush() as a bare statement
+                         on a file opened for writing or a bufio.Writer.
+                         (A bare f.Close() on an error path is fine: the error
+                         being returned is the one that matters.)
+
+ERROR POLICY:
+Lessons may ignore an error where checking it would bury the idea being
+taught; the full run (go run 157_cleanup_linter.go ..) lists those as advice.
+LIBRARY code may not: files in multi-file packages that other files build on
+(152_kvstore/store.go, pkg/fileops/safe.go, intermediate_topics' demo files
+that the runner calls...). "-lib" checks only those and fails the build; CI
+runs it on every push (.github/workflows/lint.yml). The runner side of the
+policy is in intermediate_topics/demo_errors.go.
+
+ABOUT go/analysis:
+The standard framework for Go linters is golang.org/x/tools/go/analysis
+(it powers go vet and gopls). It lives outside the standard library, and
+the lessons' module requires nothing but pkg/, so the analyzer below uses
+the SAME SHAPE — an Analyzer with a Run(*Pass) function and pass.Reportf —
+on top of go/ast alone.
+Moving it to x/tools is a matter of swapping the Pass type.
+
+RUN:
+    go run 157_cleanup_linter.go          → demo on a small sample
+    go run 157_cleanup_linter.go ..       → lint the whole repository (advice)
+    go run 157_cleanup_linter.go -lib ..  → library files only; exit 1 on findings
+*/
+
+// ---------------------------------------------------------
+// Part 1: A Minimal Analyzer Framework (go/analysis look-alike)
+// ---------------------------------------------------------
+
+type Diagnostic struct {
+	Pos      token.Position
+	Category string
+	Message  string
+}
+
+type Pass struct {
+	Fset  *token.FileSet
+	Files []*ast.File
+	diags []Diagnostic
+}
+
+func (p *Pass) Reportf(pos token.Pos, category, format string, args ...any) {
+	p.diags = append(p.diags, Diagnostic{p.Fset.Position(pos), category, fmt.Sprintf(format, args...)})
+}
+
+type Analyzer struct {
+	Name string
+	Doc  string
+	Run  func(*Pass)
+}
+
+// ---------------------------------------------------------
+// Part 2: The Cleanup Analyzer
+// ---------------------------------------------------------
+
+// acquirers maps "pkg.Func" to the method that releases its first result
+// and whether the resource is opened for writing.
+var acquirers = map[string]struct {
+	release string
+	writes  bool
+}{
+	"os.Open":             {"Close", false},
+	"os.Create":           {"Close", true},
+	"os.OpenFile":         {"Close", true},
+	"os.CreateTemp":       {"Close", true},
+	"bufio.NewWriter":     {"Flush", false},
+	"bufio.NewWriterSize": {"Flush", false},
+}
+
+var CleanupAnalyzer = &Analyzer{
+	Name: "cleanup",
+	Doc:  "report os.Open/Create/CreateTemp and bufio.NewWriter results that are never closed or flushed",
+	Run:  runCleanup,
+}
+
+type acquisition struct {
+	name   string // Variable holding the resource ("" if discarded)
+	call   string // e.g. "os.Create"
+	pos    token.Pos
+	writes bool
+}
+
+func calleeName(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return pkg.Name + "." + sel.Sel.Name
+}
+
+// eachFunc calls check on the body of every function and function literal.
+func eachFunc(pass *Pass, check func(*Pass, *ast.BlockStmt)) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch fn := n.(type) {
+			case *ast.FuncDecl:
+				if fn.Body != nil {
+					check(pass, fn.Body)
+				}
+			case *ast.FuncLit:
+				check(pass, fn.Body)
+			}
+			return true
+		})
+	}
+}
+
+func runCleanup(pass *Pass) { eachFunc(pass, checkFunc) }
+
+// checkFunc looks at acquisitions made directly in body (nested function
+// literals are checked on their own) and releases anywhere in body.
+func checkFunc(pass *Pass, body *ast.BlockStmt) {
+	for _, a := range acquisitions(body) {
+		release := acquirers[a.call].release
+		switch {
+		case a.name == "<field>":
+		case a.name == "_":
+			pass.Reportf(a.pos, "cleanup/discarded", "result of %s is discarded; it can never be %sd", a.call, strings.ToLower(release))
+		case escapes(body, a.name):
+		case !released(body, a.name, release):
+			pass.Reportf(a.pos, "cleanup/missing", "%s from %s is never %sd", a.name, a.call, verb(release))
+		case a.writes && onlyDeferredClose(body, a.name):
+			pass.Reportf(a.pos, "cleanup/write-close", "%s from %s: deferred Close discards the write error", a.name, a.call)
+		}
+	}
+}
+
+func acquisitions(body *ast.BlockStmt) []acquisition {
+	var acquired []acquisition
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false // Its own scope
+		case *ast.AssignStmt:
+			for i, rhs := range s.Rhs {
+				call, ok := rhs.(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				info, ok := acquirers[calleeName(call)]
+				if !ok {
+					continue
+				}
+				// f, err := os.Open(..) has 1 RHS and 2 LHS; the resource is LHS[0].
+				lhs := s.Lhs[0]
+				if len(s.Lhs) == len(s.Rhs) {
+					lhs = s.Lhs[i]
+				}
+				a := acquisition{call: calleeName(call), pos: call.Pos(), writes: info.writes}
+				if id, ok := lhs.(*ast.Ident); ok {
+					a.name = id.Name
+				} else {
+					a.name = "<field>" // Stored in a struct field: someone else owns it
+				}
+				acquired = append(acquired, a)
+			}
+		case *ast.ExprStmt:
+			if call, ok := s.X.(*ast.CallExpr); ok {
+				if _, ok := acquirers[calleeName(call)]; ok {
+					acquired = append(acquired, acquisition{name: "_", call: calleeName(call), pos: call.Pos()})
+				}
+			}
+		}
+		return true
+	})
+	return acquired
+}
+
+func verb(release string) string {
+	if release == "Flush" {
+		return "flushe"
+	}
+	return "close"
+}
+
+// released reports whether body calls name.Close() / name.Flush() anywhere,
+// including inside deferred closures.
+func released(body *ast.BlockStmt, name, method string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == method {
+				if id, ok := sel.X.(*ast.Ident); ok && id.Name == name {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// escapes reports whether the value leaves the function: returned, stored
+// in a composite literal or field, sent on a channel, or passed to a go or
+// defer statement. Then releasing it is somebody else's job.
+func escapes(body *ast.BlockStmt, name string) bool {
+	isName := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && id.Name == name
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.ReturnStmt:
+			for _, r := range s.Results {
+				found = found || isName(r)
+			}
+		case *ast.CompositeLit:
+			for _, elt := range s.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				found = found || isName(elt)
+			}
+		case *ast.AssignStmt:
+			for i, r := range s.Rhs {
+				if _, isIdent := s.Lhs[min(i, len(s.Lhs)-1)].(*ast.Ident); !isIdent && isName(r) {
+					found = true // x.field = f
+				}
+			}
+		case *ast.SendStmt:
+			found = found || isName(s.Value)
+		case *ast.GoStmt:
+			for _, arg := range s.Call.Args {
+				found = found || isName(arg)
+			}
+		case *ast.DeferStmt:
+			for _, arg := range s.Call.Args {
+				found = found || isName(arg) // defer closeQuietly(f): the helper owns it
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// onlyDeferredClose: every Close of name is a bare "defer name.Close()".
+func onlyDeferredClose(body *ast.BlockStmt, name string) bool {
+	deferred, checked := false, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.DeferStmt:
+			if isMethodCall(s.Call, name, "Close") {
+				deferred = true
+				return false // Don't count the inner call as a checked Close
+			}
+		case *ast.CallExpr:
+			if isMethodCall(s, name, "Close") {
+				checked = true
+			}
+		}
+		return true
+	})
+	return deferred && !checked
+}
+
+func isMethodCall(call *ast.CallExpr, recv, method string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != method {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == recv
+}
+
+// ---------------------------------------------------------
+// Part 3: The Error-Discard Analyzer
+// ---------------------------------------------------------
+// Without type information we cannot know every function that returns an
+// error, so, like acquirers, this works from a list: packages whose
+// functions put an error last, a few extra functions, and the exceptions.
+
+var errPackages = map[string]bool{
+	"os": true, "io": true, "ioutil": true, "filepath": true, "strconv": true,
+	"json": true, "xml": true, "url": true, "exec": true, "template": true,
+}
+
+var errFuncs = map[string]bool{
+	"time.Parse": true, "time.ParseDuration": true, "time.ParseInLocation": true,
+	"time.LoadLocation": true, "fmt.Sscan": true, "fmt.Sscanf": true,
+	"http.Get": true, "http.NewRequest": true,
+}
+
+// notErrFuncs return something else last: a bool, a string, a second value.
+var notErrFuncs = map[string]bool{
+	"os.LookupEnv": true, "filepath.Split": true, "io.Pipe": true,
+}
+
+var ErrorsAnalyzer = &Analyzer{
+	Name: "errors",
+	Doc:  "report errors assigned to _ and unchecked writes to files and buffered writers",
+	Run:  func(pass *Pass) { eachFunc(pass, checkErrors) },
+}
+
+func returnsError(call *ast.CallExpr) bool {
+	name := calleeName(call)
+	if name == "" || notErrFuncs[name] {
+		return false
+	}
+	pkg, _, _ := strings.Cut(name, ".")
+	return errFuncs[name] || errPackages[pkg]
+}
+
+func isBlank(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// writeMethods return an error that IS the failed write (Close included:
+// the last buffered bytes may only reach the disk when the file closes).
+var writeMethods = map[string]bool{"Write": true, "WriteString": true, "Flush": true, "Sync": true, "Close": true}
+
+func checkErrors(pass *Pass, body *ast.BlockStmt) {
+	// Writers we know about: files opened for writing and bufio.Writers.
+	writers := map[string]string{}
+	for _, a := range acquisitions(body) {
+		if a.writes || acquirers[a.call].release == "Flush" {
+			writers[a.name] = a.call
+		}
+	}
+	// On an error path ("if err != nil { f.Close(); return err }") the
+	// error being returned is the one that matters; Close is only tidying.
+	errorPath := map[ast.Node]bool{}
+	unchecked := func(call *ast.CallExpr) {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !writeMethods[sel.Sel.Name] || (sel.Sel.Name == "Close" && errorPath[call]) {
+			return
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && writers[id.Name] != "" {
+			pass.Reportf(call.Pos(), "errors/unchecked-write", "error from %s.%s is ignored (%s from %s)", id.Name, sel.Sel.Name, id.Name, writers[id.Name])
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false // Its own scope
+		case *ast.DeferStmt:
+			return false // defer f.Close() is cleanup/write-close's business
+		case *ast.BlockStmt:
+			if len(s.List) == 0 || s == body {
+				break
+			}
+			if _, ok := s.List[len(s.List)-1].(*ast.ReturnStmt); ok {
+				for _, st := range s.List {
+					if es, ok := st.(*ast.ExprStmt); ok {
+						errorPath[es.X] = true
+					}
+				}
+			}
+		case *ast.ExprStmt:
+			if call, ok := s.X.(*ast.CallExpr); ok {
+				unchecked(call)
+			}
+		case *ast.AssignStmt:
+			if len(s.Rhs) != 1 || !isBlank(s.Lhs[len(s.Lhs)-1]) {
+				return true
+			}
+			call, ok := s.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return true // v, _ := m[k] and x, _ := v.(T) are not errors
+			}
+			if returnsError(call) {
+				pass.Reportf(call.Pos(), "errors/discarded", "error from %s assigned to _", calleeName(call))
+			} else {
+				unchecked(call)
+			}
+		}
+		return true
+	})
+}
+
+// ---------------------------------------------------------
+// Part 4: Running It Over Files and Directories
+// ---------------------------------------------------------
+
+// isLibrary reports whether f is library code: a non-test file in a
+// multi-file package that does not itself declare main. Lessons and demos
+// may cut corners for readability; code other files build on may not.
+func isLibrary(path string, f *ast.File) bool {
+	if strings.HasSuffix(path, "_test.go") {
+		return false
+	}
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return false
+		}
+	}
+	siblings, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.go"))
+	return len(siblings) > 1
+}
+
+func lintPaths(analyzers []*Analyzer, roots []string, libOnly bool) ([]Diagnostic, int, error) {
+	fset := token.NewFileSet()
+	pass := &Pass{Fset: fset}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && (d.Name() == "testdata" || (strings.HasPrefix(d.Name(), ".") && p != root)) {
+				return filepath.SkipDir
+			}
+			if d.IsDir() || !strings.HasSuffix(p, ".go") {
+				return nil
+			}
+			f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+			if err != nil {
+				return nil // Not our job: the compiler reports syntax errors
+			}
+			if !libOnly || isLibrary(p, f) {
+				pass.Files = append(pass.Files, f)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, a := range analyzers {
+		a.Run(pass)
+	}
+	sort.Slice(pass.diags, func(i, j int) bool {
+		pi, pj := pass.diags[i].Pos, pass.diags[j].Pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Line < pj.Line
+	})
+	return pass.diags, len(pass.Files), nil
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+const sample = `package sample
+
+import ("bufio"; "os")
+
+func leaks(name string) {
+	f, _ := os.Open(name)           // never closed
+	_ = f.Name()
+}
+
+func good(name string) error {
+	f, err := os.Open(name)
+	if err != nil { return err }
+	defer f.Close()                  // fine: read-only
+	return nil
+}
+
+func lostWrite(name string) error {
+	f, err := os.Create(name)
+	if err != nil { return err }
+	defer f.Close()                  // write error discarded
+	w := bufio.NewWriter(f)          // never flushed
+	_, err = w.WriteString("hi")
+	return err
+}
+
+func checked(name string) (err error) {
+	f, err := os.Create(name)
+	if err != nil { return err }
+	defer func() {
+		if cerr := f.Close(); err == nil { err = cerr }
+	}()
+	w := bufio.NewWriter(f)
+	w.WriteString("hi")
+	return w.Flush()
+}
+
+func handOff(name string) (*os.File, error) {
+	f, err := os.Open(name)
+	return f, err                    // caller closes
+}
+
+func discard(name string) {
+	os.Create(name)                  // handle lost
+}
+
+func ignored(name string) []byte {
+	data, _ := os.ReadFile(name)     // error thrown away
+	return data
+}
+
+func unchecked(name string) error {
+	f, err := os.Create(name)
+	if err != nil { return err }
+	if _, err := f.WriteString("hi"); err != nil {
+		f.Close()                    // fine: error path
+		return err
+	}
+	f.WriteString("!")               // write error ignored
+	return f.Close()
+}
+`
+
+func main() {
+	if len(os.Args) > 1 {
+		args, libOnly := os.Args[1:], os.Args[1] == "-lib"
+		if libOnly {
+			args = args[1:]
+		}
+		diags, files, err := lintPaths([]*Analyzer{CleanupAnalyzer, ErrorsAnalyzer}, args, libOnly)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cleanup:", err)
+			os.Exit(2)
+		}
+		for _, d := range diags {
+			fmt.Printf("%s:%d: [%s] %s\n", d.Pos.Filename, d.Pos.Line, d.Category, d.Message)
+		}
+		fmt.Printf("%d file(s), %d finding(s)\n", files, len(diags))
+		if len(diags) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: A RESOURCE-CLEANUP LINTER WITH go/ast")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", sample, 0)
+	if err != nil {
+		fmt.Println("parse:", err)
+		return
+	}
+
+	fmt.Println("--- Example 1: What the Parser Sees ---")
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if _, ok := acquirers[calleeName(call)]; ok {
+				fmt.Printf("  line %2d: *ast.CallExpr  Fun=%s\n", fset.Position(call.Pos()).Line, calleeName(call))
+			}
+		}
+		return true
+	})
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Findings on the Sample ---")
+	pass := &Pass{Fset: fset, Files: []*ast.File{file}}
+	CleanupAnalyzer.Run(pass)
+	ErrorsAnalyzer.Run(pass)
+	for _, d := range pass.diags {
+		fmt.Printf("  sample.go:%d  [%s] %s\n", d.Pos.Line, d.Category, d.Message)
+	}
+	fmt.Println("  good(), checked() and handOff() produce nothing — as intended,")
+	fmt.Println("  and neither does the Close on unchecked()'s error path.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Lint This Repository ---")
+	fmt.Println("  go run 157_cleanup_linter.go ..")
+	fmt.Println("  (The cleanup/* findings it reported when this lesson was written were")
+	fmt.Println("   fixed in the same change; errors/* findings in lessons are advice.)")
+	fmt.Println("  go run 157_cleanup_linter.go -lib ..")
+	fmt.Println("  (Library files only. CI runs this; it must come back clean.)")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. go/parser + ast.Inspect are enough for many useful checks.
+2. Match calls by selector (pkg.Func); match releases by receiver name.
+3. Let values that ESCAPE (returned, stored) go: the owner closes them.
+4. For files you WRITE, check Close's error — defer alone discards it.
+5. Syntactic linters have false positives; keep rules narrow and explainable.
+6. Shape your tool like go/analysis so it can graduate to x/tools later.
+7. Enforce strictly where it pays (library code), advise elsewhere.
+	`)
+}
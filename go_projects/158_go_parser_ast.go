@@ -0,0 +1,493 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+TOPIC: READING GO SOURCE WITH go/parser AND go/ast
+
+CONCEPT:
+Go ships its own parser in the standard library. Any program can read .go
+files the way the compiler does — no regexes over source text:
+
+    go/token   → positions: a FileSet maps token.Pos to file:line:column
+    go/parser  → ParseFile turns source into an *ast.File
+    go/ast     → the tree: Decls (GenDecl, FuncDecl), Comments, Inspect
+
+WHAT AN *ast.File HOLDS:
+    Name       the package name
+    Imports    every import spec
+    Decls      top-level declarations, in source order
+                 *ast.GenDecl   import / const / type / var (Tok says which)
+                 *ast.FuncDecl  functions and methods (Recv != nil)
+    Doc        the comment directly above "package" (if any)
+    Comments   EVERY comment in the file (needs parser.ParseComments)
+
+THE PAYOFF:
+This tree has ~150 lessons and no index. Each lesson already says what it is
+in its header comment, so the index can be GENERATED from the source:
+
+    go run 158_go_parser_ast.go manifest ..   → JSON for every topic
+    go run 158_go_parser_ast.go registry ..   → Go source: var Topics = ...
+
+The cleanup linter (Topic 157) is built on exactly these pieces; read this
+topic first if ast.Inspect there looked like magic.
+
+RUN:
+    go run 158_go_parser_ast.go
+*/
+
+// ---------------------------------------------------------
+// Part 1: Parsing One File
+// ---------------------------------------------------------
+
+const sample = `// Package main is a tiny lesson used to show what go/parser returns.
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+TOPIC: SHOUTING STRINGS
+*/
+
+const greeting = "hello"
+
+type Counter struct{ n int }
+
+// Shout upper-cases s and adds an exclamation mark.
+func Shout(s string) string { return strings.ToUpper(s) + "!" }
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() { c.n++ }
+
+func main() {
+	// Not a doc comment: it is inside a function body.
+	fmt.Println(Shout(greeting))
+}
+`
+
+// parseMode keeps comments (off by default) and skips the old identifier
+// resolution pass, which nothing here needs.
+const parseMode = parser.ParseComments | parser.SkipObjectResolution
+
+// ---------------------------------------------------------
+// Part 2: Walking Declarations
+// ---------------------------------------------------------
+
+type Func struct {
+	Name string `json:"name"`
+	Recv string `json:"recv,omitempty"` // "*Counter" for methods
+	Doc  string `json:"doc,omitempty"`
+	Line int    `json:"line"`
+}
+
+// recvType renders a receiver type: T, *T, T[K], *T[K, V].
+func recvType(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + recvType(t.X)
+	case *ast.IndexExpr:
+		return recvType(t.X) + "[" + recvType(t.Index) + "]"
+	case *ast.IndexListExpr:
+		var params []string
+		for _, p := range t.Indices {
+			params = append(params, recvType(p))
+		}
+		return recvType(t.X) + "[" + strings.Join(params, ", ") + "]"
+	}
+	return "?"
+}
+
+func funcsOf(fset *token.FileSet, f *ast.File) []Func {
+	var out []Func
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		item := Func{Name: fn.Name.Name, Line: fset.Position(fn.Pos()).Line}
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			item.Recv = recvType(fn.Recv.List[0].Type)
+		}
+		if fn.Doc != nil {
+			item.Doc = strings.TrimSpace(fn.Doc.Text()) // Text() strips the // and /* */
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// hasMain: package main with a plain func main — "go run" will work.
+func hasMain(f *ast.File) bool {
+	if f.Name.Name != "main" {
+		return false
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------
+// Part 3: Finding the Topic Title in Comments
+// ---------------------------------------------------------
+// Lessons in this tree use three header styles:
+//     TOPIC: THE CONTEXT PACKAGE          (inside a /* */ block)
+//     // Topic 83: Writing Files
+//     ═══ WORKING WITH DIRECTORIES IN GO ═══   (a banner block)
+// A header comment comes before the first function, so comments inside
+// function bodies are never mistaken for one.
+
+var topicPrefix = regexp.MustCompile(`(?i)^topic\s+\d+\s*:\s*`)
+
+func topicTitle(f *ast.File) string {
+	var header []*ast.CommentGroup
+	end := firstFunc(f)
+	for _, cg := range f.Comments {
+		if end != token.NoPos && cg.Pos() > end {
+			break
+		}
+		header = append(header, cg)
+	}
+	for _, cg := range header {
+		for _, line := range strings.Split(cg.Text(), "\n") {
+			if t, ok := strings.CutPrefix(strings.TrimSpace(line), "TOPIC:"); ok {
+				return strings.TrimSpace(t)
+			}
+		}
+	}
+	for _, cg := range header {
+		for _, line := range strings.Split(cg.Text(), "\n") {
+			line = strings.Trim(line, " \t═─━=*")
+			if line == "" || strings.HasPrefix(line, "go:build") {
+				continue
+			}
+			line = topicPrefix.ReplaceAllString(line, "")
+			if before, _, ok := strings.Cut(line, " — "); ok {
+				line = before
+			}
+			return line
+		}
+	}
+	return ""
+}
+
+func firstFunc(f *ast.File) token.Pos {
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			return fn.Pos()
+		}
+	}
+	return token.NoPos
+}
+
+// ---------------------------------------------------------
+// Part 4: Generating the Topic Registry
+// ---------------------------------------------------------
+
+type Topic struct {
+	Num      int    `json:"num,omitempty"` // From the file name; 0 for unnumbered examples
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	Package  string `json:"package,omitempty"`
+	Runnable bool   `json:"runnable"`
+	Funcs    []Func `json:"funcs,omitempty"`
+	Err      string `json:"error,omitempty"` // The file does not parse
+}
+
+var numPrefix = regexp.MustCompile(`^(\d+)_`)
+
+func topicNum(name string) int {
+	m := numPrefix.FindStringSubmatch(name)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+func fallbackTitle(name string) string {
+	name = strings.TrimSuffix(numPrefix.ReplaceAllString(name, ""), ".go")
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// topicFromFiles builds one Topic from one or more files of a package.
+// For a multi-file lesson, main.go's header names the topic.
+func topicFromFiles(fset *token.FileSet, path string, files []string) Topic {
+	t := Topic{Num: topicNum(filepath.Base(path)), Path: path}
+	sort.SliceStable(files, func(i, j int) bool {
+		return filepath.Base(files[i]) == "main.go" && filepath.Base(files[j]) != "main.go"
+	})
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, parseMode)
+		if err != nil {
+			if t.Err == "" {
+				t.Err = err.Error() // A scanner.ErrorList: the first error says enough
+			}
+			continue
+		}
+		t.Package = f.Name.Name
+		t.Runnable = t.Runnable || hasMain(f)
+		t.Funcs = append(t.Funcs, funcsOf(fset, f)...)
+		if t.Title == "" {
+			t.Title = topicTitle(f)
+		}
+	}
+	if t.Title == "" {
+		t.Title = fallbackTitle(filepath.Base(path))
+	}
+	return t
+}
+
+// BuildManifest walks roots. A numbered directory (152_kvstore/) is one
+// multi-file topic; every other .go file is a topic of its own.
+func BuildManifest(roots ...string) ([]Topic, error) {
+	fset := token.NewFileSet()
+	var topics []Topic
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if d.IsDir() {
+				if path != root && (strings.HasPrefix(name, ".") || name == "testdata") {
+					return filepath.SkipDir
+				}
+				if path != root && topicNum(name) > 0 {
+					files, _ := filepath.Glob(filepath.Join(path, "*.go"))
+					files = dropTests(files)
+					if len(files) > 0 {
+						topics = append(topics, topicFromFiles(fset, path, files))
+					}
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+				topics = append(topics, topicFromFiles(fset, path, []string{path}))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(topics, func(i, j int) bool {
+		a, b := topics[i], topics[j]
+		if (a.Num == 0) != (b.Num == 0) {
+			return b.Num == 0 // Numbered topics first
+		}
+		if a.Num != b.Num {
+			return a.Num < b.Num
+		}
+		return a.Path < b.Path
+	})
+	return topics, nil
+}
+
+func dropTests(files []string) []string {
+	out := files[:0]
+	for _, f := range files {
+		if !strings.HasSuffix(f, "_test.go") {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// RegistrySource renders topics as a Go file. format.Source runs gofmt on
+// it, so the template does not have to get alignment right.
+func RegistrySource(topics []Topic) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by 158_go_parser_ast.go registry; DO NOT EDIT.\n\n")
+	b.WriteString("package main\n\n")
+	b.WriteString("type Topic struct {\nNum int\nTitle string\nPath string\nRunnable bool\n}\n\n")
+	b.WriteString("var Topics = []Topic{\n")
+	for _, t := range topics {
+		if t.Err != "" {
+			continue // Broken files stay out of the registry; the manifest lists them
+		}
+		fmt.Fprintf(&b, "{Num: %d, Title: %q, Path: %q, Runnable: %t},\n", t.Num, t.Title, filepath.ToSlash(t.Path), t.Runnable)
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		roots := os.Args[2:]
+		if len(roots) == 0 {
+			roots = []string{"."}
+		}
+		topics, err := BuildManifest(roots...)
+		if err == nil {
+			switch os.Args[1] {
+			case "manifest":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(topics)
+			case "registry":
+				var src []byte
+				if src, err = RegistrySource(topics); err == nil {
+					_, err = os.Stdout.Write(src)
+				}
+			default:
+				err = fmt.Errorf("unknown command %q (want manifest or registry)", os.Args[1])
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, os.Args[1]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: READING GO SOURCE WITH go/parser AND go/ast")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Parse a File ---")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "shout.go", sample, parseMode)
+	if err != nil {
+		fmt.Println("parse:", err)
+		return
+	}
+	fmt.Printf("  package %s, %d import(s), %d top-level decl(s), %d comment group(s)\n",
+		file.Name.Name, len(file.Imports), len(file.Decls), len(file.Comments))
+	for _, imp := range file.Imports {
+		fmt.Printf("  import %s  (line %d)\n", imp.Path.Value, fset.Position(imp.Pos()).Line)
+	}
+	fmt.Printf("  package doc: %q\n", strings.TrimSpace(file.Doc.Text()))
+	_, err = parser.ParseFile(fset, "broken.go", "package main\nfunc main() { fmt.Println(\"hi\" }\n", parseMode)
+	fmt.Printf("  a broken file: %v\n", err)
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Walk the Declarations ---")
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			var names []string
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.ImportSpec:
+					names = append(names, s.Path.Value)
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						names = append(names, n.Name)
+					}
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				}
+			}
+			fmt.Printf("  *ast.GenDecl   %-6s %s\n", d.Tok, strings.Join(names, ", "))
+		case *ast.FuncDecl:
+			kind := "func"
+			if d.Recv != nil {
+				kind = "method"
+			}
+			fmt.Printf("  *ast.FuncDecl  %-6s %s\n", kind, d.Name.Name)
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Function Names and Doc Comments ---")
+	for _, fn := range funcsOf(fset, file) {
+		name := fn.Name
+		if fn.Recv != "" {
+			name = "(" + fn.Recv + ") " + name
+		}
+		doc := fn.Doc
+		if doc == "" {
+			doc = "(no doc comment)"
+		}
+		fmt.Printf("  line %2d  %-18s %s\n", fn.Line, name, doc)
+	}
+	fmt.Printf("  topic title from the header: %q, runnable: %t\n", topicTitle(file), hasMain(file))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Manifest of This Repository ---")
+	topics, err := BuildManifest("..")
+	if err != nil {
+		fmt.Println("  walk:", err)
+		return
+	}
+	seen := map[int][]string{}
+	var broken []Topic
+	for _, t := range topics {
+		if t.Err != "" {
+			broken = append(broken, t)
+		}
+		if t.Num > 0 {
+			seen[t.Num] = append(seen[t.Num], filepath.Base(t.Path))
+		}
+	}
+	fmt.Printf("  %d topic(s), %d numbered\n", len(topics), len(seen))
+	for _, t := range topics {
+		if t.Num >= 82 && t.Num <= 88 {
+			fmt.Printf("  %3d  %-40.40s runnable=%-5t %d func(s)\n", t.Num, t.Title, t.Runnable, len(t.Funcs))
+		}
+	}
+	fmt.Printf("  %d file(s) do not parse, e.g.:\n", len(broken))
+	for _, t := range broken[:min(3, len(broken))] {
+		fmt.Printf("    %s\n", t.Err)
+	}
+	var dups []int
+	for n, paths := range seen {
+		if len(paths) > 1 {
+			dups = append(dups, n)
+		}
+	}
+	sort.Ints(dups)
+	for _, n := range dups {
+		fmt.Printf("  number %d is used by %v\n", n, seen[n])
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: The Generated Registry ---")
+	src, err := RegistrySource(topics)
+	if err != nil {
+		fmt.Println("  generate:", err)
+		return
+	}
+	lines := strings.Split(string(src), "\n")
+	for _, l := range lines[:12] {
+		fmt.Println("  " + l)
+	}
+	fmt.Printf("  ... (%d lines; go run 158_go_parser_ast.go registry .. > topics_gen.go)\n", len(lines))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. token.FileSet + parser.ParseFile give you the same tree the compiler sees.
+2. Pass parser.ParseComments, or Doc and Comments come back empty.
+3. file.Decls: *ast.GenDecl (import/const/type/var) and *ast.FuncDecl.
+4. fn.Doc.Text() is the doc comment without // markers; Recv marks methods.
+5. A parse error is data too: record it and keep walking.
+6. Generate indexes from the source so they can never drift from it.
+	`)
+}
@@ -0,0 +1,494 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+TOPIC: REWRITING CODE — AST MUTATION, go/format AND DIFFS
+
+CONCEPT:
+Topic 158 READ source with go/parser. This topic CHANGES it. The pipeline
+every Go refactoring tool follows (gofmt -r, gopls rename, go fix):
+
+    source ──parse──► AST ──mutate──► AST ──go/format──► new source ──diff──► review
+
+THE EXAMPLE REWRITE:
+Lessons print with fmt.Println. A STRUCTURED renderer knows what each line
+MEANS, so the same lesson can be shown as text, JSON, or in a web page:
+
+    fmt.Println("═══...")              ┐
+    fmt.Println("TOPIC: CHANNELS")      ├─►  ui.Title("TOPIC: CHANNELS")
+    fmt.Println("═══...")              ┘
+    fmt.Println("--- Example 1: X ---")  ─►  ui.Section("Example 1: X")
+    fmt.Println()                        ─►  ui.Blank()
+    fmt.Println(a, b)                    ─►  ui.Line(a, b)
+
+Doing this with sed would break on multi-line calls, strings containing
+"fmt.Println" and comments. On the AST, a call is a call.
+
+ABOUT astutil:
+golang.org/x/tools/go/ast/astutil has Apply (a cursor-based rewriter) and
+AddImport/DeleteImport. The lessons' module requires nothing but pkg/, so
+Part 3 does the same small jobs by hand — which is also the best way to see what they do.
+Go's own diff package is internal, so Part 4 is a compact LCS diff.
+
+RUN:
+    go run 159_ast_rewrite.go                    → demo on a sample
+    go run 159_ast_rewrite.go -d FILE.go         → print a diff
+    go run 159_ast_rewrite.go -w FILE.go         → rewrite in place
+(go run treats leading .go arguments as source files, hence the flag.)
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Structured Renderer (the rewrite's target API)
+// ---------------------------------------------------------
+// In the rewritten lessons these are calls on package "gotut/ui". Here the
+// same API is a type with two backends, to show why the rewrite is worth it.
+
+type Renderer interface {
+	Title(text string)
+	Section(text string)
+	Line(args ...any)
+	Blank()
+}
+
+type TextRenderer struct{ W io.Writer }
+
+func (r TextRenderer) Title(text string) {
+	bar := strings.Repeat("═", 59)
+	fmt.Fprintf(r.W, "%s\n%s\n%s\n", bar, text, bar)
+}
+func (r TextRenderer) Section(text string) { fmt.Fprintf(r.W, "--- %s ---\n", text) }
+func (r TextRenderer) Line(args ...any)    { fmt.Fprintln(r.W, args...) }
+func (r TextRenderer) Blank()              { fmt.Fprintln(r.W) }
+
+// JSONRenderer emits one event per call: a UI, a test or a grader can
+// consume the lesson without scraping text.
+type JSONRenderer struct{ Enc *json.Encoder }
+
+func (r JSONRenderer) emit(kind, text string) {
+	r.Enc.Encode(map[string]string{"kind": kind, "text": text})
+}
+func (r JSONRenderer) Title(text string)   { r.emit("title", text) }
+func (r JSONRenderer) Section(text string) { r.emit("section", text) }
+func (r JSONRenderer) Line(args ...any) {
+	r.emit("line", strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+func (r JSONRenderer) Blank() {}
+
+// ---------------------------------------------------------
+// Part 2: Matching and Mutating Calls
+// ---------------------------------------------------------
+
+const uiImport = "gotut/ui"
+
+// isPkgCall reports whether call is pkg.name(...).
+func isPkgCall(n ast.Node, pkg, name string) (*ast.CallExpr, bool) {
+	stmt, ok := n.(*ast.ExprStmt)
+	if ok {
+		n = stmt.X
+	}
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return nil, false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return call, ok && id.Name == pkg
+}
+
+// stringArg returns the value of a single string-literal argument.
+func stringArg(call *ast.CallExpr) (string, bool) {
+	if len(call.Args) != 1 {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+func isBanner(stmt ast.Stmt) bool {
+	call, ok := isPkgCall(stmt, "fmt", "Println")
+	if !ok {
+		return false
+	}
+	s, ok := stringArg(call)
+	return ok && strings.HasPrefix(strings.TrimLeft(s, "\n"), "═══")
+}
+
+// retarget turns call into ui.<name>(args...), keeping its position so
+// comments around it stay where they were.
+func retarget(call *ast.CallExpr, name string, args ...ast.Expr) {
+	pos := call.Fun.Pos()
+	call.Fun = &ast.SelectorExpr{X: &ast.Ident{Name: "ui", NamePos: pos}, Sel: ast.NewIdent(name)}
+	call.Args = args
+}
+
+func strLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+var sectionLine = regexp.MustCompile(`^--- (.+) ---$`)
+
+type Stats struct{ Titles, Sections, Blanks, Lines int }
+
+func (s Stats) Total() int { return s.Titles + s.Sections + s.Blanks + s.Lines }
+
+// rewriteBlock handles the statement-level pattern first (three statements
+// become one), then single calls. Nested blocks are visited by the caller.
+// MergeLine folds the removed lines into one, or the printer would keep a
+// blank gap where they used to be.
+func rewriteBlock(tf *token.File, block *ast.BlockStmt, st *Stats) {
+	var out []ast.Stmt
+	for i := 0; i < len(block.List); i++ {
+		stmt := block.List[i]
+		if i+2 < len(block.List) && isBanner(stmt) && isBanner(block.List[i+2]) {
+			if call, ok := isPkgCall(block.List[i+1], "fmt", "Println"); ok {
+				if text, ok := stringArg(call); ok {
+					retarget(call, "Title", strLit(text))
+					call.Fun.(*ast.SelectorExpr).X.(*ast.Ident).NamePos = stmt.Pos()
+					out = append(out, block.List[i+1])
+					line := tf.Line(stmt.Pos())
+					tf.MergeLine(line)
+					tf.MergeLine(line)
+					st.Titles++
+					i += 2
+					continue
+				}
+			}
+		}
+		out = append(out, stmt)
+	}
+	block.List = out
+}
+
+func rewriteCall(call *ast.CallExpr, st *Stats) {
+	if _, ok := isPkgCall(call, "fmt", "Println"); !ok {
+		return
+	}
+	if len(call.Args) == 0 {
+		retarget(call, "Blank")
+		st.Blanks++
+		return
+	}
+	if s, ok := stringArg(call); ok {
+		if m := sectionLine.FindStringSubmatch(s); m != nil {
+			retarget(call, "Section", strLit(m[1]))
+			st.Sections++
+			return
+		}
+	}
+	retarget(call, "Line", call.Args...)
+	st.Lines++
+}
+
+// ---------------------------------------------------------
+// Part 3: Imports (what astutil.AddImport/DeleteImport do)
+// ---------------------------------------------------------
+
+func usesPkg(f *ast.File, pkg string) bool {
+	used := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == pkg {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// fixImports adds uiImport and drops "fmt" if nothing uses it any more.
+// The new spec takes the position of the last one, so the printer keeps it
+// inside the parentheses instead of hoisting it to the top of the file.
+func fixImports(f *ast.File) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		keepFmt := usesPkg(f, "fmt")
+		var specs []ast.Spec
+		for _, spec := range gen.Specs {
+			if spec.(*ast.ImportSpec).Path.Value == `"fmt"` && !keepFmt {
+				continue
+			}
+			specs = append(specs, spec)
+		}
+		last := gen.Specs[len(gen.Specs)-1].End()
+		specs = append(specs, &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(uiImport), ValuePos: last}})
+		gen.Specs = specs
+		if !gen.Lparen.IsValid() && len(specs) > 1 {
+			gen.Lparen, gen.Rparen = gen.Pos(), last // One import became two: needs ( )
+		}
+		break
+	}
+	f.Imports = f.Imports[:0]
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			for _, spec := range gen.Specs {
+				f.Imports = append(f.Imports, spec.(*ast.ImportSpec))
+			}
+		}
+	}
+}
+
+// Rewrite parses src, applies every rule and returns gofmt-formatted output.
+func Rewrite(filename string, src []byte) ([]byte, Stats, error) {
+	var st Stats
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, st, err
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if block, ok := n.(*ast.BlockStmt); ok {
+			rewriteBlock(fset.File(f.Pos()), block, &st) // Before its calls are visited below
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			rewriteCall(call, &st)
+		}
+		return true
+	})
+	if st.Total() == 0 {
+		return src, st, nil
+	}
+	fixImports(f)
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, st, err
+	}
+	return buf.Bytes(), st, nil
+}
+
+// ---------------------------------------------------------
+// Part 4: A Unified Diff
+// ---------------------------------------------------------
+// Longest common subsequence over lines, then hunks with 3 lines of
+// context. O(n·m) memory is fine for source files; real tools use Myers.
+
+func Diff(oldName, newName string, a, b []byte) string {
+	x := strings.SplitAfter(string(a), "\n")
+	y := strings.SplitAfter(string(b), "\n")
+	if x[len(x)-1] == "" {
+		x = x[:len(x)-1] // "a\nb\n" splits into "a\n", "b\n", ""
+	}
+	if y[len(y)-1] == "" {
+		y = y[:len(y)-1]
+	}
+	n, m := len(x), len(y)
+	lcs := make([][]int, n+1) // lcs[i][j] = LCS length of x[i:], y[j:]
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type edit struct {
+		op   byte // ' ', '-', '+'
+		line string
+		i, j int // 0-based line numbers in a and b before this edit
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && x[i] == y[j]:
+			edits = append(edits, edit{' ', x[i], i, j})
+			i, j = i+1, j+1
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', x[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', y[j], i, j})
+			j++
+		}
+	}
+
+	const context = 3
+	var out strings.Builder
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+		start := max(0, k-context)
+		end := k
+		for end < len(edits) { // Extend while changes are within 2*context lines
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == ' ' {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = min(len(edits), end+context)
+				break
+			}
+			end = run
+		}
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		var oldN, newN int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				oldN++
+			}
+			if e.op != '-' {
+				newN++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", edits[start].i+1, oldN, edits[start].j+1, newN)
+		for _, e := range edits[start:end] {
+			line := e.line
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n\\ No newline at end of file\n"
+			}
+			out.WriteString(string(e.op) + line)
+		}
+		k = end
+	}
+	return out.String()
+}
+
+const sample = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: CHANNELS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Send and Receive ---")
+	ch := make(chan int, 1)
+	ch <- 42
+	fmt.Println("  received:", <-ch) // fmt.Println inside a comment is left alone
+	fmt.Println("--- not an example header")
+}
+`
+
+func main() {
+	write := flag.Bool("w", false, "write the result back to the file")
+	flag.Bool("d", true, "print a diff (the default)")
+	flag.Parse()
+	if flag.NArg() > 0 {
+		failed := false
+		for _, name := range flag.Args() {
+			src, err := os.ReadFile(name)
+			if err == nil {
+				var out []byte
+				var st Stats
+				out, st, err = Rewrite(name, src)
+				switch {
+				case err != nil:
+				case *write:
+					err = os.WriteFile(name, out, 0o644)
+					fmt.Printf("%s: %d call(s) rewritten\n", name, st.Total())
+				default:
+					fmt.Print(Diff(name, name+" (rewritten)", src, out))
+				}
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "rewrite:", err)
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: REWRITING CODE — AST MUTATION, go/format AND DIFFS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: One Lesson, Two Renderers ---")
+	show := func(r Renderer) {
+		r.Title("TOPIC: CHANNELS")
+		r.Section("Example 1: Send and Receive")
+		r.Line("  received:", 42)
+	}
+	show(TextRenderer{os.Stdout})
+	show(JSONRenderer{json.NewEncoder(os.Stdout)})
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Rewrite the Sample and Diff It ---")
+	out, st, err := Rewrite("sample.go", []byte(sample))
+	if err != nil {
+		fmt.Println("  rewrite:", err)
+		return
+	}
+	fmt.Print(Diff("sample.go", "sample.go (rewritten)", []byte(sample), out))
+	fmt.Printf("  %+v\n", st)
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The Output Is Still Valid, gofmt'ed Go ---")
+	if _, err := parser.ParseFile(token.NewFileSet(), "out.go", out, 0); err != nil {
+		fmt.Println("  ✗ does not parse:", err)
+	} else if formatted, _ := format.Source(out); !bytes.Equal(formatted, out) {
+		fmt.Println("  ✗ parses, but gofmt would change it")
+	} else {
+		fmt.Println("  ✓ parses, and gofmt leaves it unchanged")
+	}
+	again, st2, _ := Rewrite("out.go", out)
+	fmt.Printf("  rewriting twice changes %d call(s), identical output: %t\n", st2.Total(), bytes.Equal(again, out))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Real Lesson (dry run) ---")
+	if src, err := os.ReadFile("153_crc32_checksums.go"); err == nil {
+		res, st, err := Rewrite("153_crc32_checksums.go", src)
+		if err != nil {
+			fmt.Println("  rewrite:", err)
+		} else {
+			d := Diff("a", "b", src, res)
+			fmt.Printf("  153_crc32_checksums.go: %d titles, %d sections, %d blanks, %d lines\n", st.Titles, st.Sections, st.Blanks, st.Lines)
+			fmt.Printf("  diff: %d hunk(s); see it with: go run 159_ast_rewrite.go -d 153_crc32_checksums.go\n", strings.Count(d, "\n@@ "))
+		}
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Parse → mutate the AST → format.Node → diff: the shape of every refactoring tool.
+2. Match on structure (a *ast.CallExpr on fmt.Println), never on text.
+3. Statement-level rewrites edit a BlockStmt's List; expression rewrites edit in place.
+4. Keep positions on new nodes, or comments and imports drift.
+5. Fix imports after the rewrite: add what you use, drop what you no longer use.
+6. Good rewrites are IDEMPOTENT: running them twice changes nothing.
+	`)
+}
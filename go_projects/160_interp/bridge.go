@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ---------------------------------------------------------
+// Part 8: The Standard Library Bridge
+// ---------------------------------------------------------
+// Writing each builtin by hand means checking argument counts and types
+// every time. Bridge does it once, with reflection (Topic 128): give it
+// any Go function over string, bool, float64 and int, and it converts
+// script values in and Go results out. A trailing error result becomes a
+// script error, and a Go panic (strings.Repeat with a negative count)
+// is recovered into one — a script must never crash its host.
+
+var errType = reflect.TypeFor[error]()
+
+// Bridge wraps fn for scripts. It panics on unsupported signatures: that
+// is a bug in the host program, found the first time it starts.
+func Bridge(name string, fn any) *Builtin {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.IsVariadic() {
+		panic(fmt.Sprintf("Bridge(%s): want a non-variadic func, got %v", name, ft))
+	}
+	for i := range ft.NumIn() {
+		if !bridgeable(ft.In(i)) {
+			panic(fmt.Sprintf("Bridge(%s): parameter %d has unsupported type %v", name, i+1, ft.In(i)))
+		}
+	}
+	nOut := ft.NumOut()
+	withErr := nOut > 0 && ft.Out(nOut-1) == errType
+	if withErr {
+		nOut--
+	}
+	if nOut > 1 || (nOut == 1 && !bridgeable(ft.Out(0))) {
+		panic(fmt.Sprintf("Bridge(%s): unsupported results %v", name, ft))
+	}
+
+	return &Builtin{Name: name, Fn: func(_ *runtime, args []Value) (result Value, err error) {
+		if len(args) != ft.NumIn() {
+			return nil, fmt.Errorf("takes %d argument(s), got %d", ft.NumIn(), len(args))
+		}
+		in := make([]reflect.Value, len(args))
+		for i, a := range args {
+			if in[i], err = toGo(a, ft.In(i)); err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i+1, err)
+			}
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				result, err = nil, fmt.Errorf("%v", p)
+			}
+		}()
+		out := fv.Call(in)
+		if withErr && !out[len(out)-1].IsNil() {
+			return nil, out[len(out)-1].Interface().(error)
+		}
+		if nOut == 0 {
+			return nil, nil
+		}
+		return fromGo(out[0]), nil
+	}}
+}
+
+func bridgeable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float64, reflect.Int:
+		return true
+	}
+	return false
+}
+
+func toGo(v Value, t reflect.Type) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.String:
+		if s, ok := v.(string); ok {
+			return reflect.ValueOf(s), nil
+		}
+	case reflect.Bool:
+		if b, ok := v.(bool); ok {
+			return reflect.ValueOf(b), nil
+		}
+	case reflect.Float64:
+		if f, ok := v.(float64); ok {
+			return reflect.ValueOf(f), nil
+		}
+	case reflect.Int:
+		if f, ok := v.(float64); ok {
+			if f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+				return reflect.Value{}, fmt.Errorf("want a whole number, got %s", FormatValue(f))
+			}
+			return reflect.ValueOf(int(f)), nil
+		}
+		return reflect.Value{}, fmt.Errorf("want number, got %s", typeName(v))
+	}
+	return reflect.Value{}, fmt.Errorf("want %s, got %s", t.Kind(), typeName(v))
+}
+
+func fromGo(v reflect.Value) Value {
+	if v.Kind() == reflect.Int {
+		return float64(v.Int()) // The language has one number type
+	}
+	return v.Interface()
+}
+
+// builtins is the universe scope. print and str take any value, so they
+// are written by hand; the rest go through Bridge.
+func builtins() map[string]Value {
+	b := map[string]Value{
+		"print": &Builtin{Name: "print", Fn: func(rt *runtime, args []Value) (Value, error) {
+			parts := make([]string, len(args))
+			for i, a := range args {
+				parts[i] = display(a)
+			}
+			_, err := fmt.Fprintln(rt.out, strings.Join(parts, " "))
+			return nil, err
+		}},
+		"str": &Builtin{Name: "str", Fn: func(_ *runtime, args []Value) (Value, error) {
+			if len(args) != 1 {
+				return nil, errors.New("takes 1 argument")
+			}
+			return display(args[0]), nil
+		}},
+	}
+	for name, fn := range map[string]any{
+		"len":      utf8.RuneCountInString,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"trim":     strings.TrimSpace,
+		"repeat":   strings.Repeat,
+		"contains": strings.Contains,
+		"replace":  strings.ReplaceAll,
+		"num":      func(s string) (float64, error) { return strconv.ParseFloat(strings.TrimSpace(s), 64) },
+		"sqrt":     math.Sqrt,
+		"floor":    math.Floor,
+	} {
+		b[name] = Bridge(name, fn)
+	}
+	return b
+}
+
+// display is FormatValue without quotes around strings, for print/str.
+func display(v Value) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return FormatValue(v)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// Part 9: Bytecode — a flat program for a stack machine
+// ---------------------------------------------------------
+// The tree-walker re-discovers the same facts on every run: which node
+// type is this, where does "x" live, how many scopes up is it? A
+// compiler answers those questions ONCE and writes the answers down as
+// bytes. "i = i + 1" inside a function becomes
+//
+//     GET_LOCAL 0     push the value in slot 0
+//     CONST     2     push the constant 1
+//     ADD             pop two, push the sum
+//     SET_LOCAL 0     store the top into slot 0 (it stays on the stack)
+//     POP
+//
+// Each instruction is one opcode byte followed by its operands. Most
+// operands are 2-byte big-endian numbers: a constant, slot or jump target.
+
+type Opcode byte
+
+const (
+	OpConst Opcode = iota // idx: push Consts[idx]
+	OpNil                 // push nil
+	OpPop                 // drop the top of the stack
+	OpHalt                // end of the script: the top is its value
+
+	OpGetGlobal    // idx: push a global (or a builtin)
+	OpSetGlobal    // idx: define/update a global (top-level "x = 1")
+	OpAssignGlobal // idx: update a global that must already exist
+	OpGetLocal     // slot
+	OpSetLocal     // slot
+	OpNewCell      // slot: a fresh box for a captured variable
+	OpBoxLocal     // slot: move a captured parameter into a box
+	OpGetCell      // slot: read through the box in a slot
+	OpSetCell      // slot
+	OpGetFree      // idx: read a variable captured by this closure
+	OpSetFree      // idx
+
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpEq
+	OpNe
+	OpLt
+	OpLe
+	OpGt
+	OpGe
+	OpNeg
+	OpNot
+
+	OpJump          // target
+	OpJumpFalse     // target, ctx: pop a bool, jump if false
+	OpJumpFalseKeep // target, ctx: for &&, leave the bool if jumping
+	OpJumpTrueKeep  // target, ctx: for ||
+	OpCheckBool     // ctx: the right side of && and || must be bool too
+
+	OpClosure // idx: wrap the *Proto in Consts[idx] with its captured cells
+	OpCall    // argc (1 byte)
+	OpReturn
+)
+
+// opInfo describes each opcode for the disassembler: its name and the
+// width of each operand in bytes.
+var opInfo = [...]struct {
+	name     string
+	operands []int
+}{
+	OpConst: {"CONST", []int{2}}, OpNil: {"NIL", nil}, OpPop: {"POP", nil}, OpHalt: {"HALT", nil},
+	OpGetGlobal: {"GET_GLOBAL", []int{2}}, OpSetGlobal: {"SET_GLOBAL", []int{2}}, OpAssignGlobal: {"ASSIGN_GLOBAL", []int{2}},
+	OpGetLocal: {"GET_LOCAL", []int{2}}, OpSetLocal: {"SET_LOCAL", []int{2}},
+	OpNewCell: {"NEW_CELL", []int{2}}, OpBoxLocal: {"BOX_LOCAL", []int{2}},
+	OpGetCell: {"GET_CELL", []int{2}}, OpSetCell: {"SET_CELL", []int{2}},
+	OpGetFree: {"GET_FREE", []int{2}}, OpSetFree: {"SET_FREE", []int{2}},
+	OpAdd: {"ADD", nil}, OpSub: {"SUB", nil}, OpMul: {"MUL", nil}, OpDiv: {"DIV", nil}, OpMod: {"MOD", nil},
+	OpEq: {"EQ", nil}, OpNe: {"NE", nil}, OpLt: {"LT", nil}, OpLe: {"LE", nil}, OpGt: {"GT", nil}, OpGe: {"GE", nil},
+	OpNeg: {"NEG", nil}, OpNot: {"NOT", nil},
+	OpJump: {"JUMP", []int{2}}, OpJumpFalse: {"JUMP_FALSE", []int{2, 1}},
+	OpJumpFalseKeep: {"JUMP_FALSE_KEEP", []int{2, 1}}, OpJumpTrueKeep: {"JUMP_TRUE_KEEP", []int{2, 1}},
+	OpCheckBool: {"CHECK_BOOL", []int{1}},
+	OpClosure:   {"CLOSURE", []int{2}}, OpCall: {"CALL", []int{1}}, OpReturn: {"RETURN", nil},
+}
+
+// binaryOps maps the arithmetic and comparison opcodes back to the
+// source operator, so the VM can fall back to binaryOp for messages.
+var binaryOps = map[string]Opcode{
+	"+": OpAdd, "-": OpSub, "*": OpMul, "/": OpDiv, "%": OpMod,
+	"==": OpEq, "!=": OpNe, "<": OpLt, "<=": OpLe, ">": OpGt, ">=": OpGe,
+}
+
+var opSymbol = func() map[Opcode]string {
+	m := map[Opcode]string{}
+	for sym, op := range binaryOps {
+		m[op] = sym
+	}
+	return m
+}()
+
+// A condition context tells a failed bool check which message to give,
+// so both engines report "if condition is number, want bool" alike.
+const (
+	ctxIf byte = iota
+	ctxWhile
+	ctxAnd
+	ctxOr
+)
+
+var ctxName = [...]string{ctxIf: "if", ctxWhile: "while", ctxAnd: "&&", ctxOr: "||"}
+
+// Proto is one compiled function (or the whole script): its code, its
+// constants, and a Pos for every byte of code so runtime errors still
+// point at the source.
+type Proto struct {
+	Name      string
+	NumParams int
+	NumLocals int // Slots a call needs, parameters included
+	Code      []byte
+	Pos       []Pos
+	Consts    []Value
+	Free      []freeVar // What OpClosure captures, in order
+	globals   *symbols  // The VM's global names, for the disassembler
+}
+
+// freeVar says where a closure's captured cell comes from when it is
+// created: a slot of the enclosing call, or one of the enclosing
+// closure's own captured cells.
+type freeVar struct {
+	Name      string
+	FromLocal bool
+	Index     int
+}
+
+func (p *Proto) u16(at int) int { return int(binary.BigEndian.Uint16(p.Code[at:])) }
+
+// Disassemble prints a compiled script and every function nested in it.
+func Disassemble(w io.Writer, script *Proto) { disassemble(w, script, "<script>") }
+
+func disassemble(w io.Writer, p *Proto, title string) {
+	fmt.Fprintf(w, "== %s  params=%d locals=%d ==\n", title, p.NumParams, p.NumLocals)
+	for _, fv := range p.Free {
+		from := "free"
+		if fv.FromLocal {
+			from = "slot"
+		}
+		fmt.Fprintf(w, "   captures %s from enclosing %s %d\n", fv.Name, from, fv.Index)
+	}
+	var last Pos
+	for ip := 0; ip < len(p.Code); {
+		op := Opcode(p.Code[ip])
+		info := opInfo[op]
+		line := "   |"
+		if p.Pos[ip].Line != last.Line {
+			line = fmt.Sprintf("%4d", p.Pos[ip].Line)
+			last = p.Pos[ip]
+		}
+		var args []string
+		at := ip + 1
+		for _, width := range info.operands {
+			if width == 2 {
+				args = append(args, fmt.Sprint(p.u16(at)))
+			} else {
+				args = append(args, fmt.Sprint(p.Code[at]))
+			}
+			at += width
+		}
+		text := fmt.Sprintf("%04d %s  %-16s %-8s%s", ip, line, info.name, strings.Join(args, " "), p.comment(op, ip))
+		fmt.Fprintln(w, strings.TrimRight(text, " "))
+		ip = at
+	}
+	for _, c := range p.Consts {
+		if fn, ok := c.(*Proto); ok {
+			fmt.Fprintln(w)
+			disassemble(w, fn, FormatValue(&VMClosure{Proto: fn}))
+		}
+	}
+}
+
+// comment explains an operand: which constant, which name, which check.
+func (p *Proto) comment(op Opcode, ip int) string {
+	switch op {
+	case OpConst:
+		return "; " + FormatValue(p.Consts[p.u16(ip+1)])
+	case OpClosure:
+		return "; " + FormatValue(&VMClosure{Proto: p.Consts[p.u16(ip+1)].(*Proto)})
+	case OpGetGlobal, OpSetGlobal, OpAssignGlobal:
+		return "; " + p.globals.names[p.u16(ip+1)]
+	case OpGetFree, OpSetFree:
+		return "; " + p.Free[p.u16(ip+1)].Name
+	case OpJumpFalse, OpJumpFalseKeep, OpJumpTrueKeep:
+		return "; " + ctxName[p.Code[ip+3]]
+	case OpCheckBool:
+		return "; " + ctxName[p.Code[ip+1]]
+	}
+	return ""
+}
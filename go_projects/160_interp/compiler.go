@@ -0,0 +1,413 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// ---------------------------------------------------------
+// Part 10: The Compiler — resolving names once
+// ---------------------------------------------------------
+// The biggest saving is in name lookup. The tree-walker searches a chain
+// of maps for every "x"; the compiler decides at compile time where x
+// lives and emits the matching instruction:
+//
+//     local     a parameter or let inside a function or block → a SLOT
+//               in the call's stack window: GET_LOCAL 3
+//     free      a local of an ENCLOSING function, used by a closure →
+//               the closure's captured cells: GET_FREE 0
+//     global    anything else → an index into the VM's globals, looked
+//               up at run time, so functions can call ones defined later
+//
+// Closures need one extra trick. A captured local must outlive its call
+// and be SHARED by every closure that captured it (the counter in
+// Example 8), so the compiler keeps such locals in a heap box — a CELL —
+// and the slot holds the box. Locals nobody captures stay plain values.
+//
+// One difference from the tree-walker, on purpose: names resolve where
+// they are WRITTEN, like Go. In { fn f() { return x }; let x = 1 } the
+// f sees the global x, not the block's later let.
+
+// symbols is the VM's table of global names; the compiler adds to it.
+type symbols struct {
+	index map[string]int
+	names []string
+}
+
+func (s *symbols) lookup(name string) int {
+	if i, ok := s.index[name]; ok {
+		return i
+	}
+	s.index[name] = len(s.names)
+	s.names = append(s.names, name)
+	return len(s.names) - 1
+}
+
+type local struct {
+	slot int
+	cell bool // Captured by a closure: the slot holds a *cell
+}
+
+// funcState is the compiler's view of one function being compiled.
+type funcState struct {
+	parent   *funcState
+	proto    *Proto
+	scopes   []map[string]local // Innermost last; empty at the script's top level
+	captured map[string]bool    // Names used inside nested functions
+	free     map[string]int
+}
+
+type compiler struct {
+	fs      *funcState
+	globals *symbols
+}
+
+// Compile turns a parsed program into a script Proto for vm.
+func (vm *VM) Compile(prog *Block) (p *Proto, err error) {
+	c := &compiler{globals: vm.globals}
+	c.fs = &funcState{proto: &Proto{globals: vm.globals}, captured: capturedNames(prog.Stmts)}
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(*Error) // Limits (too many constants, ...) abort with an *Error
+			if !ok {
+				panic(r)
+			}
+			p, err = nil, perr
+		}
+	}()
+	for i, s := range prog.Stmts {
+		if x, ok := s.(*ExprStmt); ok && i == len(prog.Stmts)-1 {
+			c.expr(x.X) // The script's value, as ExecProgram returns it
+			c.emit(x.Pos(), OpHalt)
+			return c.fs.proto, nil
+		}
+		c.stmt(s)
+	}
+	c.emit(prog.P, OpNil)
+	c.emit(prog.P, OpHalt)
+	return c.fs.proto, nil
+}
+
+// capturedNames lists every name used inside a function literal nested
+// in stmts. A local with one of these names gets a cell. That is
+// conservative — a shadowed name may be boxed needlessly — but never wrong.
+func capturedNames(stmts []Stmt) map[string]bool {
+	names := map[string]bool{}
+	var walk func(n Node, inFn bool)
+	walk = func(n Node, inFn bool) {
+		switch n := n.(type) {
+		case *Ident:
+			if inFn {
+				names[n.Name] = true
+			}
+		case *Assign:
+			if inFn {
+				names[n.Name] = true
+			}
+			walk(n.X, inFn)
+		case *Unary:
+			walk(n.X, inFn)
+		case *Binary:
+			walk(n.L, inFn)
+			walk(n.R, inFn)
+		case *Call:
+			walk(n.Fn, inFn)
+			for _, a := range n.Args {
+				walk(a, inFn)
+			}
+		case *FuncLit:
+			walk(n.Body, true)
+		case *Block:
+			for _, s := range n.Stmts {
+				walk(s, inFn)
+			}
+		case *LetStmt:
+			walk(n.X, inFn)
+		case *IfStmt:
+			walk(n.Cond, inFn)
+			walk(n.Then, inFn)
+			if n.Else != nil {
+				walk(n.Else, inFn)
+			}
+		case *WhileStmt:
+			walk(n.Cond, inFn)
+			walk(n.Body, inFn)
+		case *ReturnStmt:
+			if n.X != nil {
+				walk(n.X, inFn)
+			}
+		case *ExprStmt:
+			walk(n.X, inFn)
+		}
+	}
+	for _, s := range stmts {
+		walk(s, false)
+	}
+	return names
+}
+
+// ---- Emitting bytes ----
+
+func (c *compiler) emit(pos Pos, op Opcode, operands ...int) int {
+	p := c.fs.proto
+	at := len(p.Code)
+	p.Code = append(p.Code, byte(op))
+	for i, width := range opInfo[op].operands {
+		if width == 2 {
+			if operands[i] > math.MaxUint16 {
+				panic(errorf(pos, "function too large: operand %d does not fit in 2 bytes", operands[i]))
+			}
+			p.Code = binary.BigEndian.AppendUint16(p.Code, uint16(operands[i]))
+		} else {
+			p.Code = append(p.Code, byte(operands[i]))
+		}
+	}
+	for len(p.Pos) < len(p.Code) {
+		p.Pos = append(p.Pos, pos)
+	}
+	return at
+}
+
+// patch points the jump at offset at to the current end of the code.
+func (c *compiler) patch(at int) {
+	p := c.fs.proto
+	if len(p.Code) > math.MaxUint16 {
+		panic(errorf(p.Pos[at], "function too large: jump past %d bytes", math.MaxUint16))
+	}
+	binary.BigEndian.PutUint16(p.Code[at+1:], uint16(len(p.Code)))
+}
+
+func (c *compiler) constant(v Value) int {
+	p := c.fs.proto
+	for i, k := range p.Consts {
+		if k == v { // Reuse: a loop's "1" is stored once
+			return i
+		}
+	}
+	p.Consts = append(p.Consts, v)
+	return len(p.Consts) - 1
+}
+
+// ---- Scopes ----
+
+func (c *compiler) openScope()  { c.fs.scopes = append(c.fs.scopes, map[string]local{}) }
+func (c *compiler) closeScope() { c.fs.scopes = c.fs.scopes[:len(c.fs.scopes)-1] }
+
+// newSlot reserves a slot in the current call's window. Slots are not
+// reused when a block ends: simpler, and a closure made in the block may
+// still hold a cell that lives in one.
+func (c *compiler) newSlot() int {
+	p := c.fs.proto
+	p.NumLocals++
+	return p.NumLocals - 1
+}
+
+func (fs *funcState) lookupLocal(name string) (local, bool) {
+	for i := len(fs.scopes) - 1; i >= 0; i-- {
+		if l, ok := fs.scopes[i][name]; ok {
+			return l, true
+		}
+	}
+	return local{}, false
+}
+
+// resolveFree finds name in an enclosing function and records how this
+// function's closures will capture it. -1 means it is a global.
+func (fs *funcState) resolveFree(name string) int {
+	if i, ok := fs.free[name]; ok {
+		return i
+	}
+	if fs.parent == nil {
+		return -1
+	}
+	fv := freeVar{Name: name}
+	if l, ok := fs.parent.lookupLocal(name); ok {
+		fv.FromLocal, fv.Index = true, l.slot // Always a cell: capturedNames saw the use
+	} else if fv.Index = fs.parent.resolveFree(name); fv.Index < 0 {
+		return -1
+	}
+	fs.proto.Free = append(fs.proto.Free, fv)
+	fs.free[name] = len(fs.proto.Free) - 1
+	return fs.free[name]
+}
+
+func (c *compiler) load(pos Pos, name string) {
+	if l, ok := c.fs.lookupLocal(name); ok {
+		if l.cell {
+			c.emit(pos, OpGetCell, l.slot)
+		} else {
+			c.emit(pos, OpGetLocal, l.slot)
+		}
+	} else if i := c.fs.resolveFree(name); i >= 0 {
+		c.emit(pos, OpGetFree, i)
+	} else {
+		c.emit(pos, OpGetGlobal, c.globals.lookup(name))
+	}
+}
+
+func (c *compiler) store(pos Pos, name string) {
+	if l, ok := c.fs.lookupLocal(name); ok {
+		if l.cell {
+			c.emit(pos, OpSetCell, l.slot)
+		} else {
+			c.emit(pos, OpSetLocal, l.slot)
+		}
+	} else if i := c.fs.resolveFree(name); i >= 0 {
+		c.emit(pos, OpSetFree, i)
+	} else if c.fs.parent == nil && len(c.fs.scopes) == 0 {
+		c.emit(pos, OpSetGlobal, c.globals.lookup(name)) // Top level: x = 1 declares x
+	} else {
+		c.emit(pos, OpAssignGlobal, c.globals.lookup(name))
+	}
+}
+
+// ---- Statements ----
+
+func (c *compiler) block(b *Block) {
+	c.openScope()
+	for _, s := range b.Stmts {
+		c.stmt(s)
+	}
+	c.closeScope()
+}
+
+func (c *compiler) stmt(s Stmt) {
+	switch s := s.(type) {
+	case *ExprStmt:
+		c.expr(s.X)
+		c.emit(s.Pos(), OpPop)
+	case *LetStmt:
+		c.let(s)
+	case *Block:
+		c.block(s)
+	case *IfStmt:
+		c.expr(s.Cond)
+		jumpElse := c.emit(s.Cond.Pos(), OpJumpFalse, 0, int(ctxIf))
+		c.block(s.Then)
+		if s.Else == nil {
+			c.patch(jumpElse)
+			return
+		}
+		jumpEnd := c.emit(s.P, OpJump, 0)
+		c.patch(jumpElse)
+		c.stmt(s.Else)
+		c.patch(jumpEnd)
+	case *WhileStmt:
+		top := len(c.fs.proto.Code)
+		c.expr(s.Cond)
+		exit := c.emit(s.Cond.Pos(), OpJumpFalse, 0, int(ctxWhile))
+		c.block(s.Body)
+		c.emit(s.P, OpJump, top)
+		c.patch(exit)
+	case *ReturnStmt:
+		if s.X != nil {
+			c.expr(s.X)
+		} else {
+			c.emit(s.P, OpNil)
+		}
+		c.emit(s.P, OpReturn) // At the top level the VM reports "return outside a function"
+	}
+}
+
+func (c *compiler) let(s *LetStmt) {
+	if len(c.fs.scopes) == 0 { // The script's top level: a global, as in NewEnv
+		c.expr(s.X)
+		c.emit(s.P, OpSetGlobal, c.globals.lookup(s.Name))
+		c.emit(s.P, OpPop)
+		return
+	}
+	l := local{slot: c.newSlot(), cell: c.fs.captured[s.Name]}
+	if l.cell {
+		c.emit(s.P, OpNewCell, l.slot) // A fresh cell each time: each loop pass gets its own
+	}
+	// let f = fn() { f() } must see itself; let x = x + 1 must see the OUTER x.
+	scope := c.fs.scopes[len(c.fs.scopes)-1]
+	if _, isFn := s.X.(*FuncLit); isFn {
+		scope[s.Name] = l
+	}
+	c.expr(s.X)
+	scope[s.Name] = l
+	c.store(s.P, s.Name)
+	c.emit(s.P, OpPop)
+}
+
+// ---- Expressions: each leaves exactly one value on the stack ----
+
+func (c *compiler) expr(n Node) {
+	switch n := n.(type) {
+	case *NumberLit:
+		c.emit(n.P, OpConst, c.constant(n.Val))
+	case *StringLit:
+		c.emit(n.P, OpConst, c.constant(n.Val))
+	case *BoolLit:
+		c.emit(n.P, OpConst, c.constant(n.Val))
+	case *Ident:
+		c.load(n.P, n.Name)
+	case *Assign:
+		c.expr(n.X)
+		c.store(n.P, n.Name)
+	case *Unary:
+		c.expr(n.X)
+		if n.Op == "-" {
+			c.emit(n.P, OpNeg)
+		} else {
+			c.emit(n.P, OpNot)
+		}
+	case *Binary:
+		c.binary(n)
+	case *Call:
+		c.expr(n.Fn)
+		for _, a := range n.Args {
+			c.expr(a)
+		}
+		if len(n.Args) > math.MaxUint8 {
+			panic(errorf(n.P, "too many arguments (%d)", len(n.Args)))
+		}
+		c.emit(n.P, OpCall, len(n.Args))
+	case *FuncLit:
+		c.funcLit(n)
+	}
+}
+
+func (c *compiler) binary(n *Binary) {
+	c.expr(n.L)
+	switch n.Op {
+	case "&&", "||":
+		op, ctx := OpJumpFalseKeep, ctxAnd
+		if n.Op == "||" {
+			op, ctx = OpJumpTrueKeep, ctxOr
+		}
+		end := c.emit(n.P, op, 0, int(ctx))
+		c.expr(n.R)
+		c.emit(n.P, OpCheckBool, int(ctx))
+		c.patch(end)
+		return
+	}
+	c.expr(n.R)
+	c.emit(n.P, binaryOps[n.Op])
+}
+
+// funcLit compiles fn into its own Proto, then emits CLOSURE to build
+// the function value — with its captured cells — when the code runs.
+func (c *compiler) funcLit(fn *FuncLit) {
+	fs := &funcState{
+		parent:   c.fs,
+		proto:    &Proto{Name: fn.Name, NumParams: len(fn.Params), globals: c.globals},
+		scopes:   []map[string]local{{}},
+		captured: capturedNames(fn.Body.Stmts),
+		free:     map[string]int{},
+	}
+	c.fs = fs
+	for _, param := range fn.Params {
+		l := local{slot: c.newSlot(), cell: fs.captured[param]}
+		fs.scopes[0][param] = l
+		if l.cell {
+			c.emit(fn.P, OpBoxLocal, l.slot)
+		}
+	}
+	c.block(fn.Body)
+	c.emit(fn.Body.P, OpNil) // Falling off the end returns nil
+	c.emit(fn.Body.P, OpReturn)
+	c.fs = fs.parent
+	c.emit(fn.P, OpClosure, c.constant(fs.proto))
+}
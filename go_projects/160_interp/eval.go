@@ -0,0 +1,242 @@
+package main
+
+import (
+	"cmp"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// ---------------------------------------------------------
+// Part 3: A Tree-Walking Evaluator
+// ---------------------------------------------------------
+// Eval is one big type switch: each node computes its children, then
+// itself. Values are plain Go values — float64, string or bool — so the
+// language borrows Go's own types instead of inventing wrappers.
+
+type Value = any
+
+// Env is one scope. Lookups walk outward through parent scopes; the
+// outermost is the "universe" holding the built-in functions (Part 7).
+type Env struct {
+	vars   map[string]Value
+	parent *Env
+	rt     *runtime // Shared by every scope of one interpreter
+}
+
+// NewEnv returns an empty global scope whose parent holds the builtins.
+// print writes to os.Stdout until SetOutput says otherwise.
+func NewEnv() *Env {
+	rt := &runtime{out: os.Stdout}
+	universe := &Env{vars: builtins(), rt: rt}
+	return &Env{vars: map[string]Value{}, parent: universe, rt: rt}
+}
+
+// Child opens a nested scope (a block or a function call).
+func (e *Env) Child() *Env { return &Env{vars: map[string]Value{}, parent: e, rt: e.rt} }
+
+// SetOutput redirects print for this interpreter.
+func (e *Env) SetOutput(w io.Writer) { e.rt.out = w }
+
+func (e *Env) Get(name string) (Value, bool) {
+	for s := e; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Define creates name in this scope, shadowing any outer one.
+func (e *Env) Define(name string, v Value) { e.vars[name] = v }
+
+// Assign updates the nearest scope that has name. The universe is never
+// written: "print = 1" shadows the builtin instead of replacing it.
+func (e *Env) Assign(name string, v Value) bool {
+	for s := e; s.parent != nil; s = s.parent {
+		if _, ok := s.vars[name]; ok {
+			s.vars[name] = v
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Env) Names() []string {
+	names := make([]string, 0, len(e.vars))
+	for name := range e.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func typeName(v Value) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case *Closure, *Builtin, *VMClosure:
+		return "function"
+	}
+	return "nil"
+}
+
+func FormatValue(v Value) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case *Closure:
+		return "<fn " + cmp.Or(v.Fn.Name, "anonymous") + ">"
+	case *Builtin:
+		return "<builtin " + v.Name + ">"
+	case *VMClosure:
+		return "<fn " + cmp.Or(v.Proto.Name, "anonymous") + ">"
+	}
+	return "nil"
+}
+
+func Eval(n Node, env *Env) (Value, error) {
+	switch n := n.(type) {
+	case *NumberLit:
+		return n.Val, nil
+	case *StringLit:
+		return n.Val, nil
+	case *BoolLit:
+		return n.Val, nil
+	case *Ident:
+		v, ok := env.Get(n.Name)
+		if !ok {
+			return nil, errorf(n.P, "undefined: %s", n.Name)
+		}
+		return v, nil
+	case *Assign:
+		v, err := Eval(n.X, env)
+		if err != nil {
+			return nil, err
+		}
+		if !env.Assign(n.Name, v) {
+			if env.parent != nil && env.parent.parent != nil {
+				return nil, errorf(n.P, "assignment to undeclared %s (use let)", n.Name)
+			}
+			env.Define(n.Name, v) // At the top level, x = 1 declares x: REPL convenience
+		}
+		return v, nil
+	case *Unary:
+		x, err := Eval(n.X, env)
+		if err != nil {
+			return nil, err
+		}
+		switch x := x.(type) {
+		case float64:
+			if n.Op == "-" {
+				return -x, nil
+			}
+		case bool:
+			if n.Op == "!" {
+				return !x, nil
+			}
+		}
+		return nil, errorf(n.P, "operator %s not defined on %s", n.Op, typeName(x))
+	case *Binary:
+		return evalBinary(n, env)
+	case *FuncLit:
+		return &Closure{Fn: n, Env: env}, nil // Captures the scope it was created in
+	case *Call:
+		return evalCall(n, env)
+	}
+	return nil, errorf(n.Pos(), "cannot evaluate %T", n)
+}
+
+func evalBinary(n *Binary, env *Env) (Value, error) {
+	l, err := Eval(n.L, env)
+	if err != nil {
+		return nil, err
+	}
+	if n.Op == "&&" || n.Op == "||" { // Short-circuit: R may never run
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, errorf(n.P, "operator %s needs bool operands, got %s", n.Op, typeName(l))
+		}
+		if lb == (n.Op == "||") {
+			return lb, nil
+		}
+		r, err := Eval(n.R, env)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := r.(bool); !ok {
+			return nil, errorf(n.P, "operator %s needs bool operands, got %s", n.Op, typeName(r))
+		}
+		return r, nil
+	}
+	r, err := Eval(n.R, env)
+	if err != nil {
+		return nil, err
+	}
+	return binaryOp(n.P, n.Op, l, r)
+}
+
+// binaryOp is shared by every later stage of the interpreter.
+func binaryOp(pos Pos, op string, l, r Value) (Value, error) {
+	switch op {
+	case "==":
+		return l == r, nil // Different types are simply unequal
+	case "!=":
+		return l != r, nil
+	}
+	switch l := l.(type) {
+	case float64:
+		if r, ok := r.(float64); ok {
+			switch op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/", "%":
+				if r == 0 {
+					return nil, errorf(pos, "division by zero")
+				}
+				if op == "%" {
+					return math.Mod(l, r), nil
+				}
+				return l / r, nil
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			}
+		}
+	case string:
+		if r, ok := r.(string); ok {
+			switch op {
+			case "+":
+				return l + r, nil
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			}
+		}
+	}
+	return nil, errorf(pos, "operator %s not defined on %s and %s", op, typeName(l), typeName(r))
+}
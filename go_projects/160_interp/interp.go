@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// Part 7: Executing Statements — the Environment Model
+// ---------------------------------------------------------
+// Every scope is an Env with a pointer to its parent:
+//
+//     universe   print, len, upper, ...      (builtins, read-only)
+//        ▲
+//     globals    let total = 0; fn add(..)   (NewEnv)
+//        ▲
+//     call #1    a, b                         (one per call of add)
+//        ▲
+//     block      let tmp = ...                (one per { } that runs)
+//
+// Lookup walks UP the chain. "let" always writes the innermost scope, so
+// an inner let SHADOWS an outer name; "x = ..." updates the nearest x.
+// A function value (Closure) remembers the scope it was CREATED in —
+// not the one it is called from. That one rule gives closures for free:
+// a counter's variable lives on in the scope of the call that made it.
+
+type runtime struct {
+	out   io.Writer // Where print writes
+	depth int       // Nested calls right now
+}
+
+// maxDepth stops runaway recursion with a script error long before the
+// Go stack itself would overflow.
+const maxDepth = 500
+
+type Closure struct {
+	Fn  *FuncLit
+	Env *Env // The defining scope
+}
+
+type Builtin struct {
+	Name string
+	Fn   func(rt *runtime, args []Value) (Value, error)
+}
+
+// returnSignal unwinds the Go call stack from a return statement to the
+// call that is running; it is never shown to users unless it escapes.
+type returnSignal struct {
+	pos Pos
+	val Value
+}
+
+func (r *returnSignal) Error() string { return "return outside a function" }
+
+// ExecProgram runs prog in env itself (no new scope, so top-level lets
+// stay visible — the REPL relies on that) and returns the last value.
+func ExecProgram(prog *Block, env *Env) (Value, error) {
+	v, err := execBlock(prog, env)
+	if r, ok := err.(*returnSignal); ok {
+		return nil, errorf(r.pos, "return outside a function")
+	}
+	return v, err
+}
+
+func execBlock(b *Block, env *Env) (Value, error) {
+	var last Value
+	for _, s := range b.Stmts {
+		v, err := Exec(s, env)
+		if err != nil {
+			return nil, err
+		}
+		last = v
+	}
+	return last, nil
+}
+
+// Exec runs one statement. Only expression statements produce a value.
+func Exec(s Stmt, env *Env) (Value, error) {
+	switch s := s.(type) {
+	case *ExprStmt:
+		return Eval(s.X, env)
+	case *LetStmt:
+		v, err := Eval(s.X, env)
+		if err != nil {
+			return nil, err
+		}
+		env.Define(s.Name, v) // A closure looks names up when CALLED, so fn f can call f
+		return nil, nil
+	case *Block:
+		_, err := execBlock(s, env.Child())
+		return nil, err
+	case *IfStmt:
+		ok, err := condition(s.Cond, env, "if")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			_, err = execBlock(s.Then, env.Child())
+		} else if s.Else != nil {
+			_, err = Exec(s.Else, env)
+		}
+		return nil, err
+	case *WhileStmt:
+		for {
+			ok, err := condition(s.Cond, env, "while")
+			if err != nil || !ok {
+				return nil, err
+			}
+			if _, err := execBlock(s.Body, env.Child()); err != nil {
+				return nil, err
+			}
+		}
+	case *ReturnStmt:
+		var v Value
+		if s.X != nil {
+			var err error
+			if v, err = Eval(s.X, env); err != nil {
+				return nil, err
+			}
+		}
+		return nil, &returnSignal{s.P, v}
+	}
+	return nil, errorf(s.Pos(), "cannot execute %T", s)
+}
+
+func condition(x Node, env *Env, what string) (bool, error) {
+	v, err := Eval(x, env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errorf(x.Pos(), "%s condition is %s, want bool", what, typeName(v))
+	}
+	return b, nil
+}
+
+func evalCall(n *Call, env *Env) (Value, error) {
+	fv, err := Eval(n.Fn, env)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]Value, len(n.Args))
+	for i, a := range n.Args {
+		if args[i], err = Eval(a, env); err != nil {
+			return nil, err
+		}
+	}
+
+	switch f := fv.(type) {
+	case *Builtin:
+		v, err := f.Fn(env.rt, args)
+		if err != nil {
+			return nil, errorf(n.P, "%s: %v", f.Name, err)
+		}
+		return v, nil
+	case *Closure:
+		name := FormatValue(f)
+		if len(args) != len(f.Fn.Params) {
+			return nil, errorf(n.P, "%s takes %d argument(s), got %d", name, len(f.Fn.Params), len(args))
+		}
+		if env.rt.depth >= maxDepth {
+			return nil, errorf(n.P, "stack overflow: more than %d nested calls", maxDepth)
+		}
+		env.rt.depth++
+		defer func() { env.rt.depth-- }()
+
+		scope := f.Env.Child() // Parent is the DEFINING scope, not env
+		for i, p := range f.Fn.Params {
+			scope.Define(p, args[i])
+		}
+		_, err := execBlock(f.Fn.Body, scope)
+		if r, ok := err.(*returnSignal); ok {
+			return r.val, nil
+		}
+		var perr *Error
+		if errors.As(err, &perr) {
+			perr.Trace = append(perr.Trace, fmt.Sprintf("%s called at %s", name, n.P))
+		}
+		return nil, err // nil, nil when the body falls off the end
+	}
+	return nil, errorf(n.P, "cannot call %s", typeName(fv))
+}
+
+// ReportError prints err the way compilers do: file:line:col, the source
+// line, a caret under the column, then the calls it passed through.
+func ReportError(w io.Writer, filename, src string, err error) {
+	var perr *Error
+	if !errors.As(err, &perr) {
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintf(w, "%s:%s: %s\n", filename, perr.Pos, perr.Msg)
+	lines := strings.Split(src, "\n")
+	if l := perr.Pos.Line; l >= 1 && l <= len(lines) {
+		line := []rune(lines[l-1])
+		pad := []rune(strings.Repeat(" ", min(perr.Pos.Col-1, len(line))))
+		for i := range pad {
+			if line[i] == '\t' {
+				pad[i] = '\t' // Keep tabs so the caret lines up
+			}
+		}
+		fmt.Fprintf(w, "    %s\n    %s^\n", string(line), string(pad))
+	}
+	for i, t := range perr.Trace {
+		if i == 5 {
+			fmt.Fprintf(w, "    ... %d more call(s)\n", len(perr.Trace)-i)
+			break
+		}
+		fmt.Fprintln(w, "    in", t)
+	}
+}
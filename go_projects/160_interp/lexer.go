@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ---------------------------------------------------------
+// Part 1: The Lexer — source text to tokens
+// ---------------------------------------------------------
+//   "price * (1 + tax)"  →  Ident(price) Op(*) Op(() Number(1) Op(+) Ident(tax) Op())
+// Every token remembers where it started, so every later error can point
+// at the exact column.
+
+type Pos struct{ Line, Col int }
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+type TokKind int
+
+const (
+	TokEOF TokKind = iota
+	TokNumber
+	TokString
+	TokIdent
+	TokOp
+)
+
+func (k TokKind) String() string {
+	return [...]string{"EOF", "Number", "String", "Ident", "Op"}[k]
+}
+
+type Token struct {
+	Kind TokKind
+	Text string // Source text; for strings, the unquoted value
+	Pos  Pos
+}
+
+// Error is every error the language reports: lexing, parsing and running.
+type Error struct {
+	Pos Pos
+	Msg string
+	// Incomplete: the input ended too early ("if x {" with no "}"). The
+	// REPL reads another line instead of reporting it.
+	Incomplete bool
+	Trace      []string // Function calls the error passed through, innermost first
+}
+
+func (e *Error) Error() string { return e.Pos.String() + ": " + e.Msg }
+
+func errorf(pos Pos, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// ops lists two-character operators first, so "<=" is not read as "<" "=".
+var ops = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", "=", "(", ")", "{", "}", ",", ";",
+}
+
+func Lex(src string) ([]Token, error) {
+	var toks []Token
+	rs := []rune(src)
+	line, col := 1, 1
+	advance := func(n int) {
+		for _, r := range rs[:n] {
+			if r == '\n' {
+				line, col = line+1, 1
+			} else {
+				col++
+			}
+		}
+		rs = rs[n:]
+	}
+
+	for len(rs) > 0 {
+		pos := Pos{line, col}
+		r := rs[0]
+		switch {
+		case unicode.IsSpace(r):
+			advance(1)
+
+		case r == '/' && len(rs) > 1 && rs[1] == '/': // Comment to end of line
+			n := 0
+			for n < len(rs) && rs[n] != '\n' {
+				n++
+			}
+			advance(n)
+
+		case unicode.IsDigit(r):
+			n := 0
+			for n < len(rs) && (unicode.IsDigit(rs[n]) || rs[n] == '.') {
+				n++
+			}
+			text := string(rs[:n])
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, errorf(pos, "bad number %q", text)
+			}
+			toks = append(toks, Token{TokNumber, text, pos})
+			advance(n)
+
+		case r == '_' || unicode.IsLetter(r):
+			n := 0
+			for n < len(rs) && (rs[n] == '_' || unicode.IsLetter(rs[n]) || unicode.IsDigit(rs[n])) {
+				n++
+			}
+			toks = append(toks, Token{TokIdent, string(rs[:n]), pos})
+			advance(n)
+
+		case r == '"':
+			n := 1
+			for n < len(rs) && rs[n] != '"' && rs[n] != '\n' {
+				if rs[n] == '\\' {
+					n++
+				}
+				n++
+			}
+			if n >= len(rs) || rs[n] != '"' {
+				return nil, errorf(pos, "string not terminated")
+			}
+			s, err := strconv.Unquote(string(rs[:n+1]))
+			if err != nil {
+				return nil, errorf(pos, "bad string: %v", err)
+			}
+			toks = append(toks, Token{TokString, s, pos})
+			advance(n + 1)
+
+		default:
+			op := ""
+			for _, o := range ops {
+				if strings.HasPrefix(string(rs[:min(2, len(rs))]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, errorf(pos, "unexpected character %q", r)
+			}
+			toks = append(toks, Token{TokOp, op, pos})
+			advance(len([]rune(op)))
+		}
+	}
+	return append(toks, Token{TokEOF, "", Pos{line, col}}), nil
+}
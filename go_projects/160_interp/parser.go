@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// Part 2: The AST and a Pratt Parser
+// ---------------------------------------------------------
+// Precedence climbing: parse a unary operand, then keep absorbing binary
+// operators whose precedence is at least minPrec. Higher numbers bind
+// tighter, so "1 + 2 * 3" groups as (+ 1 (* 2 3)).
+
+type Node interface {
+	Pos() Pos
+	String() string // S-expression form, for printing trees
+}
+
+type (
+	NumberLit struct {
+		P   Pos
+		Val float64
+	}
+	StringLit struct {
+		P   Pos
+		Val string
+	}
+	BoolLit struct {
+		P   Pos
+		Val bool
+	}
+	Ident struct {
+		P    Pos
+		Name string
+	}
+	Unary struct {
+		P  Pos
+		Op string
+		X  Node
+	}
+	Binary struct {
+		P    Pos // Position of the operator
+		Op   string
+		L, R Node
+	}
+	Assign struct {
+		P    Pos
+		Name string
+		X    Node
+	}
+	Call struct {
+		P    Pos // Position of the "("
+		Fn   Node
+		Args []Node
+	}
+	FuncLit struct {
+		P      Pos
+		Name   string // "" for an anonymous fn(...) { }
+		Params []string
+		Body   *Block
+	}
+)
+
+func (n *NumberLit) Pos() Pos { return n.P }
+func (n *StringLit) Pos() Pos { return n.P }
+func (n *BoolLit) Pos() Pos   { return n.P }
+func (n *Ident) Pos() Pos     { return n.P }
+func (n *Unary) Pos() Pos     { return n.P }
+func (n *Binary) Pos() Pos    { return n.P }
+func (n *Assign) Pos() Pos    { return n.P }
+func (n *Call) Pos() Pos      { return n.P }
+func (n *FuncLit) Pos() Pos   { return n.P }
+
+func (n *NumberLit) String() string { return strconv.FormatFloat(n.Val, 'g', -1, 64) }
+func (n *StringLit) String() string { return strconv.Quote(n.Val) }
+func (n *BoolLit) String() string   { return strconv.FormatBool(n.Val) }
+func (n *Ident) String() string     { return n.Name }
+func (n *Unary) String() string     { return fmt.Sprintf("(%s %s)", n.Op, n.X) }
+func (n *Binary) String() string    { return fmt.Sprintf("(%s %s %s)", n.Op, n.L, n.R) }
+func (n *Assign) String() string    { return fmt.Sprintf("(= %s %s)", n.Name, n.X) }
+func (n *Call) String() string      { return fmt.Sprintf("(call %s%s)", n.Fn, spaced(n.Args)) }
+func (n *FuncLit) String() string {
+	return fmt.Sprintf("(fn (%s) %s)", strings.Join(n.Params, " "), n.Body)
+}
+
+func spaced[T fmt.Stringer](items []T) string {
+	var b strings.Builder
+	for _, it := range items {
+		b.WriteString(" " + it.String())
+	}
+	return b.String()
+}
+
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type parser struct {
+	toks []Token
+	i    int
+	open int // Unclosed "{": running out of input there means "keep typing"
+}
+
+func (p *parser) peek() Token { return p.toks[p.i] }
+
+func (p *parser) next() Token {
+	t := p.toks[p.i]
+	if t.Kind != TokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) is(op string) bool {
+	t := p.peek()
+	return t.Kind == TokOp && t.Text == op
+}
+
+func (p *parser) expect(op string) (Token, error) {
+	if !p.is(op) {
+		return Token{}, p.unexpected(fmt.Sprintf("%q", op))
+	}
+	return p.next(), nil
+}
+
+func (p *parser) unexpected(want string) error {
+	t := p.peek()
+	got := fmt.Sprintf("%q", t.Text)
+	if t.Kind == TokEOF {
+		got = "end of input"
+	}
+	err := errorf(t.Pos, "expected %s, found %s", want, got)
+	err.Incomplete = t.Kind == TokEOF && p.open > 0
+	return err
+}
+
+// ParseExpr parses one line of input: an expression or "name = expression".
+func ParseExpr(src string) (Node, error) {
+	toks, err := Lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	n, err := p.assignment()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().Kind != TokEOF {
+		return nil, p.unexpected("an operator")
+	}
+	return n, nil
+}
+
+func (p *parser) assignment() (Node, error) {
+	if t := p.peek(); t.Kind == TokIdent && p.toks[p.i+1].Kind == TokOp && p.toks[p.i+1].Text == "=" {
+		p.i += 2
+		x, err := p.assignment() // Right-associative: a = b = 1
+		if err != nil {
+			return nil, err
+		}
+		return &Assign{t.Pos, t.Text, x}, nil
+	}
+	return p.expr(1)
+}
+
+// postfix parses calls after an operand: f(1)(2) calls the result of f(1).
+func (p *parser) postfix(x Node) (Node, error) {
+	for p.is("(") {
+		call := &Call{P: p.next().Pos, Fn: x}
+		for !p.is(")") {
+			arg, err := p.expr(1)
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if !p.is(")") {
+				if _, err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+		p.next()
+		x = call
+	}
+	return x, nil
+}
+
+func (p *parser) expr(minPrec int) (Node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.Text]
+		if t.Kind != TokOp || !ok || prec < minPrec {
+			return left, nil
+		}
+		p.next()
+		right, err := p.expr(prec + 1) // +1: left-associative, 1-2-3 is (1-2)-3
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{t.Pos, t.Text, left, right}
+	}
+}
+
+func (p *parser) unary() (Node, error) {
+	if p.is("-") || p.is("!") {
+		t := p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{t.Pos, t.Text, x}, nil
+	}
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	return p.postfix(x)
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.peek()
+	switch t.Kind {
+	case TokNumber:
+		p.next()
+		v, err := strconv.ParseFloat(t.Text, 64) // The lexer checked the syntax; the range is checked here
+		if err != nil {
+			return nil, errorf(t.Pos, "number %s is out of range", t.Text)
+		}
+		return &NumberLit{t.Pos, v}, nil
+	case TokString:
+		p.next()
+		return &StringLit{t.Pos, t.Text}, nil
+	case TokIdent:
+		p.next()
+		switch {
+		case t.Text == "true" || t.Text == "false":
+			return &BoolLit{t.Pos, t.Text == "true"}, nil
+		case t.Text == "fn":
+			return p.funcLit(t.Pos, "")
+		case keywords[t.Text]:
+			return nil, errorf(t.Pos, "unexpected keyword %q in an expression", t.Text)
+		}
+		return &Ident{t.Pos, t.Text}, nil
+	}
+	if p.is("(") {
+		p.next()
+		x, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, p.unexpected("an expression")
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/term"
+)
+
+// ---------------------------------------------------------
+// Part 5: The REPL — Read, Eval, Print, Loop
+// ---------------------------------------------------------
+//   read:  a line editor in raw mode (Tab completes, ↑/↓ walk history),
+//          or plain lines when stdin is a pipe
+//   eval:  ParseExpr + Eval against one Env that lives for the session
+//   print: the value, or the error with a caret under its column
+//   loop:  every line is saved to a history file in the config dir
+
+const (
+	prompt     = "gotut> "
+	contPrompt = "  ...> " // Inside an unfinished if/while/fn block
+)
+
+const maxHistory = 500
+
+var commands = []string{":help", ":vars", ":history", ":quit"}
+
+var errQuit = errors.New("quit")
+
+type REPL struct {
+	Env         *Env
+	Out         io.Writer
+	HistoryPath string // "" keeps history in memory only
+	Echo        bool   // Print the prompt and input for piped lines (demos, transcripts)
+
+	history []string
+	names   Trie   // Variables, keywords and commands, for Tab
+	tty     bool   // The input line is on screen after the prompt
+	pending string // Lines of an unfinished statement
+}
+
+func (r *REPL) prompt() string {
+	if r.pending != "" {
+		return contPrompt
+	}
+	return prompt
+}
+
+func NewREPL(out io.Writer) *REPL {
+	r := &REPL{Env: NewEnv(), Out: out}
+	r.Env.SetOutput(out)
+	for _, w := range append([]string{"true", "false"}, commands...) {
+		r.names.Insert(w)
+	}
+	for kw := range keywords {
+		r.names.Insert(kw)
+	}
+	for name := range r.Env.parent.vars { // The builtins
+		r.names.Insert(name)
+	}
+	return r
+}
+
+// DefaultHistoryPath is <config dir>/gotut/repl_history, next to the
+// telemetry settings of Topic 138.
+func DefaultHistoryPath() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gotut", "repl_history"), nil
+}
+
+func (r *REPL) loadHistory() error {
+	if r.HistoryPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(r.HistoryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	r.history = lines[max(0, len(lines)-maxHistory):]
+	return nil
+}
+
+func (r *REPL) remember(line string) error {
+	if n := len(r.history); n > 0 && r.history[n-1] == line {
+		return nil // Like HISTCONTROL=ignoredups
+	}
+	r.history = append(r.history, line)
+	if r.HistoryPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(r.HistoryPath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.HistoryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, line)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Handle runs one line of input and prints the result.
+func (r *REPL) Handle(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" && r.pending == "" {
+		return nil
+	}
+	if line != "" {
+		if err := r.remember(line); err != nil {
+			fmt.Fprintln(r.Out, "history:", err) // Not fatal: keep going
+		}
+	}
+	if r.pending != "" {
+		return r.eval(r.pending + line)
+	}
+	switch line {
+	case ":quit", ":q":
+		return errQuit
+	case ":help":
+		fmt.Fprintln(r.Out, "expressions: 1 + 2 * 3, \"a\" + \"b\", x > 1 && !done, upper(\"hi\")")
+		fmt.Fprintln(r.Out, "variables:   x = 42 or let x = 42 (kept for the session)")
+		fmt.Fprintln(r.Out, "statements:  if, while, fn name(a) { return a } — an open { continues on the next line")
+		fmt.Fprintln(r.Out, "commands:   ", strings.Join(commands, " "), "— Tab completes names and commands")
+		return nil
+	case ":vars":
+		for _, name := range r.Env.Names() {
+			v, _ := r.Env.Get(name)
+			fmt.Fprintf(r.Out, "%s = %s\n", name, FormatValue(v))
+		}
+		return nil
+	case ":history":
+		start := max(0, len(r.history)-10)
+		for i, h := range r.history[start:] {
+			fmt.Fprintf(r.Out, "%4d  %s\n", start+i+1, h)
+		}
+		return nil
+	}
+	return r.eval(line)
+}
+
+// eval parses and runs src. Input that ends inside a block is kept in
+// pending until a later line completes it.
+func (r *REPL) eval(src string) error {
+	multiline := r.pending != ""
+	prog, err := ParseProgram(src)
+	var perr *Error
+	if errors.As(err, &perr) && perr.Incomplete {
+		r.pending = src + "\n"
+		return nil
+	}
+	r.pending = ""
+	var v Value
+	if err == nil {
+		v, err = ExecProgram(prog, r.Env)
+	}
+	if errors.As(err, &perr) {
+		if multiline {
+			ReportError(r.Out, "input", src, err)
+			return nil
+		}
+		if !r.tty && !r.Echo {
+			fmt.Fprintln(r.Out, prompt+src) // Give the caret something to point at
+		}
+		fmt.Fprintf(r.Out, "%s^\n", strings.Repeat(" ", len(prompt)+perr.Pos.Col-1))
+		fmt.Fprintln(r.Out, "error:", perr.Msg)
+		for _, t := range perr.Trace[:min(5, len(perr.Trace))] {
+			fmt.Fprintln(r.Out, "    in", t)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range declaredNames(prog) {
+		r.names.Insert(name) // Now Tab knows it
+	}
+	if n := len(prog.Stmts); n > 0 && v != nil {
+		if es, ok := prog.Stmts[n-1].(*ExprStmt); ok {
+			if _, isAssign := es.X.(*Assign); !isAssign {
+				fmt.Fprintln(r.Out, FormatValue(v))
+			}
+		}
+	}
+	return nil
+}
+
+// Run reads from in until EOF or :quit. A terminal gets the line editor.
+func (r *REPL) Run(in *os.File) error {
+	if err := r.loadHistory(); err != nil {
+		fmt.Fprintln(r.Out, "history:", err)
+	}
+	restore, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return r.runLines(in)
+	}
+	defer restore()
+	r.tty = true
+	fmt.Fprintln(r.Out, "gotut expression REPL — :help for help, Ctrl-D to exit")
+	keys := term.NewReader(in)
+	for {
+		line, err := r.readLine(keys)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.Handle(line); err != nil {
+			if err == errQuit {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// runLines is the fallback for pipes and files: one expression per line.
+func (r *REPL) runLines(in io.Reader) error {
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		if r.Echo {
+			fmt.Fprintln(r.Out, r.prompt()+sc.Text())
+		}
+		if err := r.Handle(sc.Text()); err != nil {
+			if err == errQuit {
+				return nil
+			}
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// readLine is a minimal line editor: typing and Backspace at the end of
+// the line, Tab, ↑/↓ history, Ctrl-C to drop the line, Ctrl-D to exit.
+// pkg/term turns the bytes the terminal sends into keys. Every change
+// redraws the whole line: \r goes to column 0, ESC[K clears.
+func (r *REPL) readLine(keys *term.Reader) (string, error) {
+	var buf []rune
+	hist := len(r.history)
+	redraw := func() { fmt.Fprintf(r.Out, "\r\033[K%s%s", r.prompt(), string(buf)) }
+	redraw()
+	for {
+		k, err := keys.ReadKey()
+		if err != nil {
+			return "", err
+		}
+		switch k {
+		case term.KeyEnter:
+			fmt.Fprint(r.Out, "\n")
+			return string(buf), nil
+		case term.KeyInterrupt:
+			fmt.Fprint(r.Out, "^C\n")
+			buf, r.pending = buf[:0], ""
+			redraw()
+		case term.KeyEOF:
+			if len(buf) == 0 {
+				fmt.Fprint(r.Out, "\n")
+				return "", io.EOF
+			}
+		case term.KeyBackspace:
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				redraw()
+			}
+		case term.KeyTab:
+			buf = r.complete(buf)
+			redraw()
+		case term.KeyUp:
+			if hist > 0 {
+				hist--
+				buf = []rune(r.history[hist])
+			}
+			redraw()
+		case term.KeyDown:
+			if hist < len(r.history)-1 {
+				hist++
+				buf = []rune(r.history[hist])
+			} else {
+				hist, buf = len(r.history), buf[:0]
+			}
+			redraw()
+		default:
+			if c := rune(k); k.IsRune() && unicode.IsPrint(c) {
+				buf = append(buf, c)
+				fmt.Fprint(r.Out, string(c))
+			}
+		}
+	}
+}
+
+// complete expands the word before the cursor: one match is filled in,
+// several are extended to their common prefix or listed.
+func (r *REPL) complete(buf []rune) []rune {
+	start := len(buf)
+	for start > 0 && (buf[start-1] == '_' || buf[start-1] == ':' || unicode.IsLetter(buf[start-1]) || unicode.IsDigit(buf[start-1])) {
+		start--
+	}
+	prefix := string(buf[start:])
+	if prefix == "" {
+		return buf
+	}
+	matches := r.names.Complete(prefix)
+	switch {
+	case len(matches) == 0:
+		fmt.Fprint(r.Out, "\a") // Bell
+	case len(matches) == 1:
+		return append(buf[:start], []rune(matches[0])...)
+	default:
+		if common := commonPrefix(matches); len(common) > len(prefix) {
+			return append(buf[:start], []rune(common)...)
+		}
+		fmt.Fprintf(r.Out, "\n%s\n", strings.Join(matches, "  "))
+	}
+	return buf
+}
@@ -0,0 +1,273 @@
+package main
+
+import "fmt"
+
+// ---------------------------------------------------------
+// Part 6: Statements — from expressions to a scripting language
+// ---------------------------------------------------------
+//   let x = expr            declare in the CURRENT scope
+//   x = expr                update the nearest existing x
+//   fn name(a, b) { ... }   same as: let name = fn(a, b) { ... }
+//   if c { } else if d { } else { }
+//   while c { }
+//   return expr
+// Semicolons are optional: a statement ends where its expression cannot
+// continue, as in Lua. "//" starts a comment.
+
+type Stmt interface {
+	Node
+	stmt()
+}
+
+type (
+	Block struct {
+		P     Pos
+		Stmts []Stmt
+	}
+	LetStmt struct {
+		P    Pos
+		Name string
+		X    Node
+	}
+	IfStmt struct {
+		P    Pos
+		Cond Node
+		Then *Block
+		Else Stmt // nil, *Block or *IfStmt
+	}
+	WhileStmt struct {
+		P    Pos
+		Cond Node
+		Body *Block
+	}
+	ReturnStmt struct {
+		P Pos
+		X Node // nil for a bare "return"
+	}
+	ExprStmt struct{ X Node }
+)
+
+func (*Block) stmt()      {}
+func (*LetStmt) stmt()    {}
+func (*IfStmt) stmt()     {}
+func (*WhileStmt) stmt()  {}
+func (*ReturnStmt) stmt() {}
+func (*ExprStmt) stmt()   {}
+
+func (s *Block) Pos() Pos      { return s.P }
+func (s *LetStmt) Pos() Pos    { return s.P }
+func (s *IfStmt) Pos() Pos     { return s.P }
+func (s *WhileStmt) Pos() Pos  { return s.P }
+func (s *ReturnStmt) Pos() Pos { return s.P }
+func (s *ExprStmt) Pos() Pos   { return s.X.Pos() }
+
+func (s *Block) String() string     { return "(block" + spaced(s.Stmts) + ")" }
+func (s *LetStmt) String() string   { return fmt.Sprintf("(let %s %s)", s.Name, s.X) }
+func (s *WhileStmt) String() string { return fmt.Sprintf("(while %s %s)", s.Cond, s.Body) }
+func (s *ExprStmt) String() string  { return s.X.String() }
+func (s *IfStmt) String() string {
+	if s.Else == nil {
+		return fmt.Sprintf("(if %s %s)", s.Cond, s.Then)
+	}
+	return fmt.Sprintf("(if %s %s %s)", s.Cond, s.Then, s.Else)
+}
+func (s *ReturnStmt) String() string {
+	if s.X == nil {
+		return "(return)"
+	}
+	return fmt.Sprintf("(return %s)", s.X)
+}
+
+var keywords = map[string]bool{"let": true, "fn": true, "if": true, "else": true, "while": true, "return": true}
+
+// ParseProgram parses a whole script (or one REPL line) into statements.
+func ParseProgram(src string) (*Block, error) {
+	toks, err := Lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	prog := &Block{P: Pos{1, 1}}
+	for p.peek().Kind != TokEOF {
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		prog.Stmts = append(prog.Stmts, s)
+	}
+	return prog, nil
+}
+
+func (p *parser) keyword(kw string) bool {
+	t := p.peek()
+	return t.Kind == TokIdent && t.Text == kw
+}
+
+func (p *parser) ident() (Token, error) {
+	t := p.peek()
+	if t.Kind != TokIdent || keywords[t.Text] {
+		return Token{}, p.unexpected("a name")
+	}
+	return p.next(), nil
+}
+
+func (p *parser) statement() (Stmt, error) {
+	s, err := p.statementNoSemi()
+	if err == nil && p.is(";") {
+		p.next()
+	}
+	return s, err
+}
+
+func (p *parser) statementNoSemi() (Stmt, error) {
+	t := p.peek()
+	switch {
+	case p.is("{"):
+		return p.block()
+	case p.keyword("let"):
+		p.next()
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect("="); err != nil {
+			return nil, err
+		}
+		x, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		return &LetStmt{t.Pos, name.Text, x}, nil
+	case p.keyword("fn") && p.toks[p.i+1].Kind == TokIdent: // fn name(...): a declaration
+		p.next()
+		name := p.next()
+		fn, err := p.funcLit(t.Pos, name.Text)
+		if err != nil {
+			return nil, err
+		}
+		return &LetStmt{t.Pos, name.Text, fn}, nil
+	case p.keyword("if"):
+		return p.ifStmt()
+	case p.keyword("while"):
+		p.next()
+		cond, err := p.expr(1)
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return &WhileStmt{t.Pos, cond, body}, nil
+	case p.keyword("return"):
+		p.next()
+		if p.is("}") || p.is(";") || p.peek().Kind == TokEOF {
+			return &ReturnStmt{t.Pos, nil}, nil
+		}
+		x, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		return &ReturnStmt{t.Pos, x}, nil
+	case p.keyword("else"):
+		return nil, errorf(t.Pos, "else without if")
+	}
+	x, err := p.assignment()
+	if err != nil {
+		return nil, err
+	}
+	return &ExprStmt{x}, nil
+}
+
+func (p *parser) ifStmt() (Stmt, error) {
+	t := p.next()
+	cond, err := p.expr(1)
+	if err != nil {
+		return nil, err
+	}
+	then, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	s := &IfStmt{P: t.Pos, Cond: cond, Then: then}
+	if p.keyword("else") {
+		p.next()
+		if p.keyword("if") {
+			s.Else, err = p.ifStmt()
+		} else {
+			s.Else, err = p.block()
+		}
+	}
+	return s, err
+}
+
+func (p *parser) block() (*Block, error) {
+	open, err := p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+	b := &Block{P: open.Pos}
+	p.open++
+	for !p.is("}") {
+		if p.peek().Kind == TokEOF {
+			return nil, p.unexpected(`"}"`)
+		}
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		b.Stmts = append(b.Stmts, s)
+	}
+	p.next()
+	p.open--
+	return b, nil
+}
+
+// funcLit parses "(a, b) { body }" after the fn keyword (and name).
+func (p *parser) funcLit(pos Pos, name string) (*FuncLit, error) {
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+	fn := &FuncLit{P: pos, Name: name}
+	seen := map[string]bool{}
+	for !p.is(")") {
+		param, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if seen[param.Text] {
+			return nil, errorf(param.Pos, "duplicate parameter %s", param.Text)
+		}
+		seen[param.Text] = true
+		fn.Params = append(fn.Params, param.Text)
+		if !p.is(")") {
+			if _, err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.next()
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	fn.Body = body
+	return fn, nil
+}
+
+// declaredNames lists the names a program defines at its top level, for
+// the REPL's Tab completion.
+func declaredNames(prog *Block) []string {
+	var names []string
+	for _, s := range prog.Stmts {
+		switch s := s.(type) {
+		case *LetStmt:
+			names = append(names, s.Name)
+		case *ExprStmt:
+			if a, ok := s.X.(*Assign); ok {
+				names = append(names, a.Name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,84 @@
+package main
+
+import "sort"
+
+// ---------------------------------------------------------
+// Part 4: A Trie for Tab Completion
+// ---------------------------------------------------------
+// Each edge is one rune; a word is a path from the root. Finding every
+// word that starts with "pr" means walking "p", "r", then collecting the
+// subtree — the cost depends on the prefix, not on how many words exist.
+
+type Trie struct {
+	root trieNode
+	size int
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	word     bool // A word ends here
+}
+
+// Insert adds word and reports whether it was new.
+func (t *Trie) Insert(word string) bool {
+	n := &t.root
+	for _, r := range word {
+		if n.children == nil {
+			n.children = map[rune]*trieNode{}
+		}
+		child, ok := n.children[r]
+		if !ok {
+			child = &trieNode{}
+			n.children[r] = child
+		}
+		n = child
+	}
+	if n.word {
+		return false
+	}
+	n.word = true
+	t.size++
+	return true
+}
+
+func (t *Trie) Len() int { return t.size }
+
+// Complete returns every word starting with prefix, sorted.
+func (t *Trie) Complete(prefix string) []string {
+	n := &t.root
+	for _, r := range prefix {
+		if n = n.children[r]; n == nil {
+			return nil
+		}
+	}
+	var out []string
+	var walk func(n *trieNode, word []rune)
+	walk = func(n *trieNode, word []rune) {
+		if n.word {
+			out = append(out, string(word))
+		}
+		keys := make([]rune, 0, len(n.children))
+		for r := range n.children {
+			keys = append(keys, r)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for _, r := range keys {
+			walk(n.children[r], append(word, r))
+		}
+	}
+	walk(n, []rune(prefix))
+	return out
+}
+
+// commonPrefix of a sorted list is the common prefix of its first and last.
+func commonPrefix(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	a, b := []rune(words[0]), []rune(words[len(words)-1])
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return string(a[:n])
+}
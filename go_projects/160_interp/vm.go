@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// ---------------------------------------------------------
+// Part 11: The Virtual Machine — one loop, one stack
+// ---------------------------------------------------------
+// The VM is a for loop around a switch on the next opcode. All values
+// live on one slice used as a stack; a call does not allocate a scope,
+// it just marks where its window of slots starts:
+//
+//     stack: [ ...caller... | fn | arg0 arg1 | let slots | temporaries ]
+//                                ▲ frame.base
+//
+// Nothing here re-examines the AST: the compiler has already decided
+// where every name lives and where every jump lands.
+
+// VMClosure is a compiled function value: the code plus the cells it
+// captured when CLOSURE ran.
+type VMClosure struct {
+	Proto *Proto
+	cells []*cell
+}
+
+// cell is the heap box a captured variable lives in (Part 10).
+type cell struct{ v Value }
+
+// unset marks a global slot that has a name but no value yet.
+type unset struct{}
+
+type frame struct {
+	cl   *VMClosure // nil for the script itself
+	code []byte
+	ip   int
+	base int
+	call int // Offset of the CALL that made the NEXT frame, for traces
+}
+
+// VM runs compiled code. Globals persist across Run calls, so a VM can
+// serve a REPL the same way one Env does.
+type VM struct {
+	globals  *symbols
+	values   []Value // Indexed like globals.names
+	universe map[string]Value
+	rt       *runtime
+	script   *Proto // The one Run is executing
+	stack    []Value
+	frames   []frame
+}
+
+func NewVM() *VM {
+	return &VM{
+		globals:  &symbols{index: map[string]int{}},
+		universe: builtins(),
+		rt:       &runtime{out: os.Stdout},
+	}
+}
+
+// SetOutput redirects print for this VM.
+func (vm *VM) SetOutput(w io.Writer) { vm.rt.out = w }
+
+// Run executes a script compiled by vm.Compile and returns its value.
+func (vm *VM) Run(script *Proto) (Value, error) {
+	for len(vm.values) < len(vm.globals.names) {
+		vm.values = append(vm.values, unset{})
+	}
+	vm.script = script
+	vm.stack = append(vm.stack[:0], make([]Value, script.NumLocals)...)
+	vm.frames = append(vm.frames[:0], frame{code: script.Code})
+	v, err := vm.loop()
+	if perr, ok := err.(*Error); ok {
+		vm.addTrace(perr)
+	}
+	clear(vm.stack) // Drop references so the GC can reclaim them
+	return v, err
+}
+
+// addTrace records the calls an error passed through, innermost first,
+// in the same words as the tree-walker's evalCall.
+func (vm *VM) addTrace(err *Error) {
+	for i := len(vm.frames) - 1; i > 0; i-- {
+		caller := vm.frames[i-1]
+		err.Trace = append(err.Trace, fmt.Sprintf("%s called at %s", FormatValue(vm.frames[i].cl), vm.protoOf(caller).Pos[caller.call]))
+	}
+}
+
+func (vm *VM) protoOf(f frame) *Proto {
+	if f.cl == nil {
+		return vm.script
+	}
+	return f.cl.Proto
+}
+
+func (vm *VM) pop() Value {
+	v := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return v
+}
+
+func (vm *VM) push(v Value) { vm.stack = append(vm.stack, v) }
+
+func (vm *VM) loop() (Value, error) {
+	f := &vm.frames[len(vm.frames)-1]
+	p := vm.protoOf(*f)
+	code := f.code
+	arg := func() int { // The next 2-byte operand
+		n := int(binary.BigEndian.Uint16(code[f.ip:]))
+		f.ip += 2
+		return n
+	}
+	for {
+		at := f.ip
+		op := Opcode(code[at])
+		f.ip++
+		switch op {
+		case OpConst:
+			vm.push(p.Consts[arg()])
+		case OpNil:
+			vm.push(nil)
+		case OpPop:
+			vm.stack = vm.stack[:len(vm.stack)-1]
+		case OpHalt:
+			return vm.pop(), nil
+
+		case OpGetGlobal:
+			i := arg()
+			v := vm.values[i]
+			if v == (unset{}) {
+				b, ok := vm.universe[vm.globals.names[i]]
+				if !ok {
+					return nil, errorf(p.Pos[at], "undefined: %s", vm.globals.names[i])
+				}
+				v = b
+			}
+			vm.push(v)
+		case OpSetGlobal:
+			vm.values[arg()] = vm.stack[len(vm.stack)-1]
+		case OpAssignGlobal:
+			i := arg()
+			if vm.values[i] == (unset{}) {
+				return nil, errorf(p.Pos[at], "assignment to undeclared %s (use let)", vm.globals.names[i])
+			}
+			vm.values[i] = vm.stack[len(vm.stack)-1]
+		case OpGetLocal:
+			vm.push(vm.stack[f.base+arg()])
+		case OpSetLocal:
+			vm.stack[f.base+arg()] = vm.stack[len(vm.stack)-1]
+		case OpNewCell:
+			vm.stack[f.base+arg()] = &cell{}
+		case OpBoxLocal:
+			slot := f.base + arg()
+			vm.stack[slot] = &cell{vm.stack[slot]}
+		case OpGetCell:
+			vm.push(vm.stack[f.base+arg()].(*cell).v)
+		case OpSetCell:
+			vm.stack[f.base+arg()].(*cell).v = vm.stack[len(vm.stack)-1]
+		case OpGetFree:
+			vm.push(f.cl.cells[arg()].v)
+		case OpSetFree:
+			f.cl.cells[arg()].v = vm.stack[len(vm.stack)-1]
+
+		case OpAdd, OpSub, OpMul, OpDiv, OpMod, OpEq, OpNe, OpLt, OpLe, OpGt, OpGe:
+			r := vm.pop()
+			l := vm.stack[len(vm.stack)-1]
+			// Fast path: two numbers, the common case in loops.
+			if a, ok := l.(float64); ok {
+				if b, ok := r.(float64); ok && (b != 0 || (op != OpDiv && op != OpMod)) {
+					vm.stack[len(vm.stack)-1] = numberOp(op, a, b)
+					continue
+				}
+			}
+			v, err := binaryOp(p.Pos[at], opSymbol[op], l, r)
+			if err != nil {
+				return nil, err
+			}
+			vm.stack[len(vm.stack)-1] = v
+		case OpNeg:
+			x, ok := vm.stack[len(vm.stack)-1].(float64)
+			if !ok {
+				return nil, errorf(p.Pos[at], "operator - not defined on %s", typeName(vm.stack[len(vm.stack)-1]))
+			}
+			vm.stack[len(vm.stack)-1] = -x
+		case OpNot:
+			x, ok := vm.stack[len(vm.stack)-1].(bool)
+			if !ok {
+				return nil, errorf(p.Pos[at], "operator ! not defined on %s", typeName(vm.stack[len(vm.stack)-1]))
+			}
+			vm.stack[len(vm.stack)-1] = !x
+
+		case OpJump:
+			f.ip = arg()
+		case OpJumpFalse, OpJumpFalseKeep, OpJumpTrueKeep:
+			target := arg()
+			ctx := code[f.ip]
+			f.ip++
+			b, err := vm.checkBool(p.Pos[at], ctx)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case op == OpJumpFalse:
+				vm.stack = vm.stack[:len(vm.stack)-1]
+				if !b {
+					f.ip = target
+				}
+			case b == (op == OpJumpTrueKeep): // Short-circuit: the bool is the answer
+				f.ip = target
+			default:
+				vm.stack = vm.stack[:len(vm.stack)-1]
+			}
+		case OpCheckBool:
+			ctx := code[f.ip]
+			f.ip++
+			if _, err := vm.checkBool(p.Pos[at], ctx); err != nil {
+				return nil, err
+			}
+
+		case OpClosure:
+			proto := p.Consts[arg()].(*Proto)
+			cl := &VMClosure{Proto: proto, cells: make([]*cell, len(proto.Free))}
+			for i, fv := range proto.Free {
+				if fv.FromLocal {
+					cl.cells[i] = vm.stack[f.base+fv.Index].(*cell)
+				} else {
+					cl.cells[i] = f.cl.cells[fv.Index]
+				}
+			}
+			vm.push(cl)
+		case OpCall:
+			argc := int(code[f.ip])
+			f.ip++
+			fnAt := len(vm.stack) - argc - 1
+			switch fn := vm.stack[fnAt].(type) {
+			case *Builtin:
+				v, err := fn.Fn(vm.rt, vm.stack[fnAt+1:]) // Builtins never keep args
+				if err != nil {
+					return nil, errorf(p.Pos[at], "%s: %v", fn.Name, err)
+				}
+				vm.stack = append(vm.stack[:fnAt], v)
+			case *VMClosure:
+				if argc != fn.Proto.NumParams {
+					return nil, errorf(p.Pos[at], "%s takes %d argument(s), got %d", FormatValue(fn), fn.Proto.NumParams, argc)
+				}
+				if len(vm.frames)-1 >= maxDepth { // Frame 0 is the script
+					return nil, errorf(p.Pos[at], "stack overflow: more than %d nested calls", maxDepth)
+				}
+				f.call = at
+				for range fn.Proto.NumLocals - argc {
+					vm.push(nil)
+				}
+				vm.frames = append(vm.frames, frame{cl: fn, code: fn.Proto.Code, base: fnAt + 1})
+				f = &vm.frames[len(vm.frames)-1] // append may have moved the frames
+				p, code = fn.Proto, f.code
+			default:
+				return nil, errorf(p.Pos[at], "cannot call %s", typeName(fn))
+			}
+		case OpReturn:
+			if len(vm.frames) == 1 {
+				return nil, errorf(p.Pos[at], "return outside a function")
+			}
+			v := vm.pop()
+			vm.stack = append(vm.stack[:f.base-1], v) // Replace fn and its window with the result
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			f = &vm.frames[len(vm.frames)-1]
+			p, code = vm.protoOf(*f), f.code
+		default:
+			return nil, errorf(p.Pos[at], "bad opcode %d", op)
+		}
+	}
+}
+
+func (vm *VM) checkBool(pos Pos, ctx byte) (bool, error) {
+	v := vm.stack[len(vm.stack)-1]
+	b, ok := v.(bool)
+	if !ok {
+		if ctx == ctxIf || ctx == ctxWhile {
+			return false, errorf(pos, "%s condition is %s, want bool", ctxName[ctx], typeName(v))
+		}
+		return false, errorf(pos, "operator %s needs bool operands, got %s", ctxName[ctx], typeName(v))
+	}
+	return b, nil
+}
+
+func numberOp(op Opcode, a, b float64) Value {
+	switch op {
+	case OpAdd:
+		return a + b
+	case OpSub:
+		return a - b
+	case OpMul:
+		return a * b
+	case OpDiv:
+		return a / b
+	case OpMod:
+		return math.Mod(a, b)
+	case OpEq:
+		return a == b
+	case OpNe:
+		return a != b
+	case OpLt:
+		return a < b
+	case OpLe:
+		return a <= b
+	case OpGt:
+		return a > b
+	}
+	return a >= b
+}
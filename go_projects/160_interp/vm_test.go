@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// ---------------------------------------------------------
+// The VM must behave exactly like the tree-walker
+// ---------------------------------------------------------
+
+// treeWalk and compileRun return everything a script printed, followed
+// by its value or its ReportError output.
+func treeWalk(src string) string {
+	var out bytes.Buffer
+	env := NewEnv()
+	env.SetOutput(&out)
+	prog, err := ParseProgram(src)
+	var v Value
+	if err == nil {
+		v, err = ExecProgram(prog, env)
+	}
+	return finish(&out, src, v, err)
+}
+
+func compileRun(src string) string {
+	var out bytes.Buffer
+	vm := NewVM()
+	vm.SetOutput(&out)
+	prog, err := ParseProgram(src)
+	var v Value
+	if err == nil {
+		var script *Proto
+		if script, err = vm.Compile(prog); err == nil {
+			v, err = vm.Run(script)
+		}
+	}
+	return finish(&out, src, v, err)
+}
+
+func finish(out *bytes.Buffer, src string, v Value, err error) string {
+	if err != nil {
+		ReportError(out, "t", src, err)
+	} else {
+		out.WriteString("=> " + FormatValue(v) + "\n")
+	}
+	return out.String()
+}
+
+func TestVMMatchesTreeWalker(t *testing.T) {
+	tests := []struct{ name, src string }{
+		{"fizzbuzz", scriptFizz},
+		{"scopes", scriptScopes},
+		{"error trace", scriptErrors},
+		{"arithmetic", `1 + 2 * 3 - 10 % 4 / 2`},
+		{"strings", `"ab" + "cd" < "b"`},
+		{"short circuit", `let t = true || missing; let f = false && missing; t != f`},
+		{"recursion", "fn fib(n) { if n < 2 { return n } return fib(n-1) + fib(n-2) }\nfib(15)"},
+		{"closures per iteration", `
+let fs = 0
+let i = 0
+fn keep(f) { let prev = fs; fs = fn(k) { if k == 0 { return f() } return prev(k - 1) } }
+while i < 3 { let j = i * 10; keep(fn() { return j }); i = i + 1 }
+fs(0) + fs(1) + fs(2)`},
+		{"three levels", `
+fn outer(a) {
+    fn middle(b) {
+        return fn(c) { a = a + 1; return a + b + c }
+    }
+    return middle
+}
+let f = outer(100)(10)
+f(1); f(1)`},
+		{"local recursion", "fn go() { fn down(n) { if n == 0 { return \"done\" } return down(n - 1) } return down(50) }\ngo()"},
+		{"shadowing", "let x = 1\n{ let x = x + 1; print(x) }\nx"},
+		{"builtins", `print(upper("vm"), len("héllo"), sqrt(16)); floor(2.7)`},
+		{"bare return", "fn f() { return }\nf()"},
+		{"fall off end", "fn f() { let a = 1 }\nf()"},
+		{"top-level assign", "total = 5\ntotal"},
+		{"top-level block", "{ let n = 1; fn bump() { n = n + 1; return n } bump(); print(bump()) }"},
+		// Errors: same message, same position, same trace.
+		{"division by zero", "let a = 1\na / (a - 1)"},
+		{"modulo by zero", "5 % 0"},
+		{"undefined", "fn f() { return nope }\nf()"},
+		{"undeclared assign", "let total = 0\nfn add(x) { totl = total + x }\nadd(1)"},
+		{"builtin shadow", "fn f() { print = 1 }\nf()"},
+		{"bad operand", `-"x"`},
+		{"bad not", `!1`},
+		{"if condition", "if 1 { }"},
+		{"while condition", "while \"y\" { }"},
+		{"and operand", "true && 1"},
+		{"or operand", "1 || true"},
+		{"arity", "fn f(a, b) { }\nf(1)"},
+		{"not callable", "let x = 3\nx(1)"},
+		{"builtin error", `repeat("ab", -1)`},
+		{"overflow", "fn down(n) { return down(n + 1) }\ndown(0)"},
+		{"return outside", "print(1)\nreturn 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, got := treeWalk(tt.src), compileRun(tt.src)
+			if got != want {
+				t.Errorf("engines disagree\ntree-walker:\n%s\nvm:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestVMGlobalsPersistAcrossRuns(t *testing.T) {
+	vm := NewVM()
+	for _, src := range []string{"let n = 40", "fn add(x) { return n + x }", "add(2)"} {
+		prog, err := ParseProgram(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script, err := vm.Compile(prog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := vm.Run(script)
+		if err != nil {
+			t.Fatalf("%s: %v", src, err)
+		}
+		if src == "add(2)" && v != 42.0 {
+			t.Errorf("add(2) = %v, want 42", v)
+		}
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	for op := range Opcode(len(opInfo)) {
+		if opInfo[op].name == "" {
+			t.Errorf("opcode %d has no name", op)
+		}
+	}
+	vm := NewVM()
+	prog, _ := ParseProgram(scriptScopes + scriptFizz)
+	script, err := vm.Compile(prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	Disassemble(&out, script)
+	for _, want := range []string{"== <script>", "== <fn counter>", "CLOSURE", "GET_FREE", "NEW_CELL", "JUMP_FALSE", "; label"} {
+		if !bytes.Contains(out.Bytes(), []byte(want)) {
+			t.Errorf("disassembly lacks %q", want)
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Benchmarks: go test -bench . -benchmem
+// ---------------------------------------------------------
+
+const benchFib = `fn fib(n) { if n < 2 { return n } return fib(n - 1) + fib(n - 2) }
+fib(20)`
+
+// benchLoop avoids %: math.Mod is slow enough that a profile of a
+// FizzBuzz-style loop shows it, not the interpreter, as half the time.
+const benchLoop = `let total = 0
+{
+    let i = 0
+    while i < 100000 {
+        if i < 50000 || i > 90000 { total = total + i * 2 } else { total = total - 1 }
+        i = i + 1
+    }
+}
+total`
+
+const benchStrings = `fn pad(s, n) {
+    while len(s) < n { s = s + "." }
+    return s
+}
+let i = 0
+while i < 2000 { pad(str(i), 12); i = i + 1 }`
+
+func benchmarkEngines(b *testing.B, src string) {
+	prog, err := ParseProgram(src)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("tree", func(b *testing.B) {
+		for b.Loop() {
+			env := NewEnv()
+			env.SetOutput(io.Discard)
+			if _, err := ExecProgram(prog, env); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("vm", func(b *testing.B) {
+		for b.Loop() {
+			vm := NewVM() // Compiling is part of the price
+			vm.SetOutput(io.Discard)
+			script, err := vm.Compile(prog)
+			if err == nil {
+				_, err = vm.Run(script)
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkFib(b *testing.B)     { benchmarkEngines(b, benchFib) }
+func BenchmarkLoop(b *testing.B)    { benchmarkEngines(b, benchLoop) }
+func BenchmarkStrings(b *testing.B) { benchmarkEngines(b, benchStrings) }
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/atomicfile"
+)
+
+/*
+TOPIC: IMAGE PROCESSING WITH image, image/png AND image/jpeg
+
+CONCEPT:
+The standard library splits images into three layers:
+
+    image          the IN-MEMORY model: image.Image, Rectangle, Point,
+                   concrete types *image.RGBA, *image.Gray, *image.NRGBA ...
+    image/color    one pixel: color.Color, color.RGBA, color.Gray, models
+    image/png      ENCODERS and DECODERS that turn bytes ↔ image.Image
+    image/jpeg
+
+    image.Decode(r)    sniffs the format from the first bytes and calls the
+                       decoder that REGISTERED itself for it. Importing
+                       image/png (or _ "image/png") is what registers it.
+
+THE image.Image INTERFACE:
+    Bounds() Rectangle      // NOT always (0,0)-(w,h): a SubImage keeps
+                            // its parent's coordinates
+    At(x, y) Color          // generic, allocates — fine for clarity
+    ColorModel() color.Model
+
+For speed, type-assert to *image.RGBA (or *image.Gray) and index Pix
+directly:  i := img.PixOffset(x, y); r, g, b, a := Pix[i], Pix[i+1], ...
+
+THIS LESSON:
+    1. Generate a test picture and save it as PNG and JPEG, atomically
+    2. Decode: sniff the format, read just the header with DecodeConfig
+    3. Pixel access: At vs Pix, and why Bounds().Min matters
+    4. Filters: grayscale and invert
+    5. Resize with nearest-neighbor
+    6. Tiles on a worker pool (Topic 115), checked against the serial result
+
+RUN:
+    go run 163_image_processing.go
+*/
+
+// ---------------------------------------------------------
+// Part 1: A Test Picture and Atomic Writes
+// ---------------------------------------------------------
+// No sample files ship with the lessons, so the picture is drawn in
+// code: a diagonal gradient with a solid disc in the middle.
+
+func testPicture(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	cx, cy, r := w/2, h/2, min(w, h)/3
+	for y := range h {
+		for x := range w {
+			c := color.RGBA{uint8(255 * x / w), uint8(255 * y / h), 160, 255}
+			if dx, dy := x-cx, y-cy; dx*dx+dy*dy <= r*r {
+				c = color.RGBA{240, 200, 40, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// savePNG and saveJPEG write through pkg/atomicfile, so a reader sees
+// the old file or the new one — never half an image.
+func savePNG(path string, img image.Image) error {
+	return atomicfile.Write(path, 0o644, func(w io.Writer) error { return png.Encode(w, img) })
+}
+
+func saveJPEG(path string, img image.Image, quality int) error {
+	return atomicfile.Write(path, 0o644, func(w io.Writer) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	})
+}
+
+// ---------------------------------------------------------
+// Part 2: Decoding
+// ---------------------------------------------------------
+
+func load(path string) (image.Image, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	return image.Decode(f) // format is "png" or "jpeg": whoever registered
+}
+
+// probe reads only the header: size and color model, no pixel data.
+func probe(path string) (image.Config, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, "", err
+	}
+	defer f.Close()
+	return image.DecodeConfig(f)
+}
+
+// ---------------------------------------------------------
+// Part 3: Pixel Access
+// ---------------------------------------------------------
+// Decoders return whatever type suits the file: PNG often gives
+// *image.NRGBA or *image.RGBA, JPEG gives *image.YCbCr. Filters below
+// convert once into *image.RGBA and then work on Pix directly.
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok {
+		return rgba
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(b) // Same bounds, including a non-zero Min
+	draw.Draw(dst, b, src, b.Min, draw.Src)
+	return dst
+}
+
+// ---------------------------------------------------------
+// Part 4: Filters
+// ---------------------------------------------------------
+// Each filter works on ONE rectangle of the image. Run it over Bounds()
+// and it is a normal filter; hand different rectangles to different
+// goroutines and it is a parallel one (Part 6) — with no locks, because
+// no two tiles share a pixel.
+
+type filter func(img *image.RGBA, r image.Rectangle)
+
+// grayscale uses the ITU-R BT.601 luma weights, the same ones
+// color.GrayModel uses: green looks brightest to the eye, blue darkest.
+func grayscale(img *image.RGBA, r image.Rectangle) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		i := img.PixOffset(r.Min.X, y)
+		for x := r.Min.X; x < r.Max.X; x, i = x+1, i+4 {
+			p := img.Pix[i : i+3 : i+3]
+			lum := (19595*uint32(p[0]) + 38470*uint32(p[1]) + 7471*uint32(p[2]) + 1<<15) >> 16
+			p[0], p[1], p[2] = uint8(lum), uint8(lum), uint8(lum)
+		}
+	}
+}
+
+// invert flips each color channel and leaves alpha alone. RGBA is
+// alpha-PREMULTIPLIED, so "255 - c" is only right for opaque pixels;
+// a channel can never exceed alpha, hence "a - c".
+func invert(img *image.RGBA, r image.Rectangle) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		i := img.PixOffset(r.Min.X, y)
+		for x := r.Min.X; x < r.Max.X; x, i = x+1, i+4 {
+			a := img.Pix[i+3]
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2] = a-img.Pix[i], a-img.Pix[i+1], a-img.Pix[i+2]
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 5: Resizing With Nearest-Neighbor
+// ---------------------------------------------------------
+// For each DESTINATION pixel, pick the source pixel it lands on. No
+// blending: fast and exact for pixel art, blocky for photos. (Smoother
+// filters live in golang.org/x/image/draw: ApproxBiLinear, CatmullRom.)
+
+func resizeNearest(src image.Image, w, h int) *image.RGBA {
+	s := toRGBA(src)
+	sb := s.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		sy := sb.Min.Y + y*sb.Dy()/h
+		for x := range w {
+			sx := sb.Min.X + x*sb.Dx()/w
+			si, di := s.PixOffset(sx, sy), dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], s.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// ascii renders an image as characters, darkest to lightest, so the
+// lesson can show its results in the terminal.
+func ascii(img image.Image, w int) string {
+	b := img.Bounds()
+	h := max(1, w*b.Dy()/b.Dx()/2) // Characters are about twice as tall as wide
+	small := resizeNearest(img, w, h)
+	const ramp = " .:-=+*#%@"
+	var sb strings.Builder
+	for y := range h {
+		sb.WriteString("    ")
+		for x := range w {
+			g := color.GrayModel.Convert(small.At(x, y)).(color.Gray)
+			sb.WriteByte(ramp[int(g.Y)*(len(ramp)-1)/255])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// ---------------------------------------------------------
+// Part 6: Tiles on a Worker Pool
+// ---------------------------------------------------------
+// Same shape as Topic 115: a jobs channel, a fixed number of workers,
+// a WaitGroup. The jobs are rectangles; the workers write straight into
+// the shared image because their rectangles never overlap.
+
+func tiles(b image.Rectangle, size int) []image.Rectangle {
+	var out []image.Rectangle
+	for y := b.Min.Y; y < b.Max.Y; y += size {
+		for x := b.Min.X; x < b.Max.X; x += size {
+			out = append(out, image.Rect(x, y, x+size, y+size).Intersect(b))
+		}
+	}
+	return out
+}
+
+func applyParallel(img *image.RGBA, workers, tileSize int, filters ...filter) {
+	jobs := make(chan image.Rectangle)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				for _, f := range filters {
+					f(img, r)
+				}
+			}
+		}()
+	}
+	for _, r := range tiles(img.Bounds(), tileSize) {
+		jobs <- r
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func applySerial(img *image.RGBA, filters ...filter) {
+	for _, f := range filters {
+		f(img, img.Bounds())
+	}
+}
+
+func clone(img *image.RGBA) *image.RGBA {
+	c := *img
+	c.Pix = bytes.Clone(img.Pix)
+	return &c
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: IMAGE PROCESSING WITH image, image/png AND image/jpeg")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "gotut-images-*")
+	if err != nil {
+		fmt.Println("  ✗", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	fmt.Println("--- Example 1: Draw, Then Save as PNG and JPEG ---")
+	pic := testPicture(320, 200)
+	pngPath, jpgPath := filepath.Join(dir, "picture.png"), filepath.Join(dir, "picture.jpg")
+	for _, save := range []struct {
+		path string
+		fn   func() error
+	}{
+		{pngPath, func() error { return savePNG(pngPath, pic) }},
+		{jpgPath, func() error { return saveJPEG(jpgPath, pic, 85) }},
+	} {
+		if err := save.fn(); err != nil {
+			fmt.Println("  ✗", err)
+			return
+		}
+		info, _ := os.Stat(save.path)
+		fmt.Printf("  ✓ %-12s %6d bytes\n", filepath.Base(save.path), info.Size())
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp-*"))
+	fmt.Printf("  Temp files left behind by the atomic writes: %d\n", len(leftovers))
+	fmt.Print(ascii(pic, 40))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Decoding — the Format Is Sniffed ---")
+	for _, path := range []string{pngPath, jpgPath} {
+		cfg, format, err := probe(path)
+		if err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		img, _, err := load(path)
+		if err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		fmt.Printf("  %-12s format=%-4s %dx%d (header only)  decoded as %T\n",
+			filepath.Base(path), format, cfg.Width, cfg.Height, img)
+	}
+	_, _, err = image.Decode(strings.NewReader("GIF89a not really"))
+	fmt.Println("  A GIF nobody registered:", err)
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Pixel Access and Bounds ---")
+	decoded, _, _ := load(jpgPath)
+	r, g, b, a := pic.At(10, 10).RGBA()
+	fmt.Printf("  At(10,10) on the original: r=%d g=%d b=%d a=%d  (16-bit, premultiplied)\n", r, g, b, a)
+	r, g, b, _ = decoded.At(10, 10).RGBA()
+	fmt.Printf("  At(10,10) after JPEG:      r=%d g=%d b=%d         (lossy: close, not equal)\n", r, g, b)
+	i := pic.PixOffset(10, 10)
+	fmt.Printf("  Pix[%d:%d] on the original: %v  (8-bit, direct)\n", i, i+4, pic.Pix[i:i+4])
+	sub := pic.SubImage(image.Rect(100, 50, 140, 70)).(*image.RGBA)
+	fmt.Printf("  SubImage bounds: %v — Min is NOT (0,0), and it SHARES Pix with the parent\n", sub.Bounds())
+	fmt.Printf("  sub.At(100,50) == pic.At(100,50): %v\n", sub.At(100, 50) == pic.At(100, 50))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Grayscale and Invert ---")
+	gray := clone(pic)
+	applySerial(gray, grayscale)
+	inverted := clone(pic)
+	applySerial(inverted, invert)
+	fmt.Printf("  The disc pixel %v → gray %v → inverted %v\n",
+		pic.RGBAAt(160, 100), gray.RGBAAt(160, 100), inverted.RGBAAt(160, 100))
+	want := color.GrayModel.Convert(pic.At(160, 100)).(color.Gray).Y
+	fmt.Printf("  Our luma matches color.GrayModel (%d): %v\n", want, gray.Pix[gray.PixOffset(160, 100)] == want)
+	twice := clone(inverted)
+	applySerial(twice, invert)
+	fmt.Println("  Invert twice gives the original back:", bytes.Equal(twice.Pix, pic.Pix))
+	fmt.Print(ascii(inverted, 40))
+	if err := savePNG(filepath.Join(dir, "gray.png"), gray); err != nil {
+		fmt.Println("  ✗", err)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Nearest-Neighbor Resize ---")
+	for _, size := range [][2]int{{160, 100}, {32, 20}, {640, 400}} {
+		small := resizeNearest(decoded, size[0], size[1])
+		path := filepath.Join(dir, fmt.Sprintf("resized_%dx%d.png", size[0], size[1]))
+		if err := savePNG(path, small); err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		info, _ := os.Stat(path)
+		fmt.Printf("  %3dx%-3d → %-22s %6d bytes\n", size[0], size[1], filepath.Base(path), info.Size())
+	}
+	checker := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	checker.SetRGBA(0, 0, color.RGBA{255, 255, 255, 255})
+	checker.SetRGBA(1, 1, color.RGBA{255, 255, 255, 255})
+	big := resizeNearest(checker, 4, 4)
+	fmt.Println("  A 2x2 checkerboard scaled ×2 stays crisp — every pixel is copied, none blended:")
+	fmt.Print(ascii(big, 8))
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Tiles on a Worker Pool ---")
+	large := testPicture(2000, 1500)
+	serial := clone(large)
+	start := time.Now()
+	applySerial(serial, grayscale, invert)
+	serialTime := time.Since(start)
+	workers := runtime.GOMAXPROCS(0)
+	for _, size := range []int{64, 256} {
+		parallel := clone(large)
+		start = time.Now()
+		applyParallel(parallel, workers, size, grayscale, invert)
+		elapsed := time.Since(start)
+		mark := "✓"
+		if !bytes.Equal(parallel.Pix, serial.Pix) {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %d workers, %3dpx tiles (%4d tiles): %-10v same pixels as serial\n",
+			mark, workers, size, len(tiles(large.Bounds(), size)), elapsed.Round(time.Microsecond))
+	}
+	fmt.Printf("  Serial: %v\n", serialTime.Round(time.Microsecond))
+	if workers == 1 {
+		fmt.Println("  (GOMAXPROCS is 1 here: no speedup to see, but the pixels must still match)")
+	}
+	edge := tiles(image.Rect(0, 0, 100, 70), 64)
+	fmt.Println("  Edge tiles are clipped with Intersect:", edge)
+	if err := savePNG(filepath.Join(dir, "processed.png"), serial); err != nil {
+		fmt.Println("  ✗", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	fmt.Printf("  Files written to %s: %d\n", filepath.Base(dir), len(entries))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. image.Decode sniffs the format; importing image/png registers PNG.
+2. image.DecodeConfig reads the size without decoding any pixels.
+3. Bounds().Min is not always (0,0) — SubImages keep parent coordinates.
+4. At() is convenient; Pix with PixOffset is fast.
+5. RGBA is premultiplied: a channel never exceeds alpha.
+6. Nearest-neighbor copies pixels: crisp, blocky, and very fast.
+7. Filters over disjoint rectangles parallelize without locks.
+8. Write outputs to a temp file and rename: readers never see half a file.
+	`)
+}
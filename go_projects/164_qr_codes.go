@@ -0,0 +1,711 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/atomicfile"
+)
+
+/*
+TOPIC: GENERATING QR CODES FROM SCRATCH
+
+CONCEPT:
+A QR code is a square grid of MODULES (dark or light). Some are fixed
+FUNCTION patterns that let a camera find and align the grid; the rest
+carry data, protected by Reed–Solomon error correction.
+
+    ███████ ▄ █ ███████      finder patterns: three 7x7 "eyes"
+    █ ▄▄▄ █  ▀▄ █ ▄▄▄ █      timing patterns: alternating row/column 6
+    █ ███ █ ▀▄▀ █ ███ █      alignment pattern: a 5x5 eye (version ≥ 2)
+    █▄▄▄▄▄█ ▄▀█ █▄▄▄▄▄█      format info: error level + mask, twice
+    ...                      data: zigzag up and down two columns at a time
+
+THE PIPELINE (this file follows it top to bottom):
+    text ─► bit stream ─► codewords ─► + Reed–Solomon ─► place in grid
+         mode+length+bytes    pad to capacity     GF(256)
+                                                   ─► try 8 masks, keep
+                                                      the least "confusing"
+                                                   ─► write format info
+
+SCOPE: byte mode, versions 1–5 (21x21 to 37x37), error levels L and M
+where the data fits in ONE Reed–Solomon block. That covers URLs up to
+106 characters. Larger versions split data into interleaved blocks —
+the same math, more bookkeeping.
+
+pkg/term reads keys; it doesn't draw. Terminal output is written here
+with Unicode half blocks (▀ ▄ █): one character shows two
+rows of modules. PNG output uses the image packages from Topic 163.
+
+RUN:
+    go run 164_qr_codes.go
+    go run 164_qr_codes.go -o DIR           also keep the PNGs in DIR
+    go run 164_qr_codes.go -text "any text" print one code and exit
+*/
+
+// ---------------------------------------------------------
+// Part 1: Versions and Capacity
+// ---------------------------------------------------------
+
+type ECLevel int
+
+const (
+	ECLow    ECLevel = iota // L: recovers ~7% damage
+	ECMedium                // M: recovers ~15%
+)
+
+func (l ECLevel) String() string { return [...]string{"L", "M"}[l] }
+
+// formatBits is the level's 2-bit code in the format info. The standard
+// numbers them L=01 M=00 Q=11 H=10, not in order.
+func (l ECLevel) formatBits() int { return [...]int{1, 0}[l] }
+
+// capacity[level][version] = {data codewords, error-correction codewords}
+// for the single-block versions. Zero means "needs interleaved blocks".
+var capacity = [2][6][2]int{
+	ECLow:    {1: {19, 7}, 2: {34, 10}, 3: {55, 15}, 4: {80, 20}, 5: {108, 26}},
+	ECMedium: {1: {16, 10}, 2: {28, 16}, 3: {44, 26}},
+}
+
+var ErrTooLong = errors.New("qr: text too long for versions 1-5 at this level")
+
+// chooseVersion finds the smallest version whose data codewords hold the
+// 4-bit mode, the 8-bit length and the bytes themselves.
+func chooseVersion(n int, level ECLevel) (int, error) {
+	for v := 1; v <= 5; v++ {
+		if dc := capacity[level][v][0]; dc > 0 && 4+8+8*n <= dc*8 {
+			return v, nil
+		}
+	}
+	return 0, ErrTooLong
+}
+
+// ---------------------------------------------------------
+// Part 2: Text to Codewords
+// ---------------------------------------------------------
+
+type bitWriter struct {
+	buf  []byte
+	nbit int
+}
+
+func (w *bitWriter) write(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.nbit%8 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		if v>>i&1 == 1 {
+			w.buf[w.nbit/8] |= 0x80 >> (w.nbit % 8)
+		}
+		w.nbit++
+	}
+}
+
+// dataCodewords builds: 0100 (byte mode) | length | bytes | terminator |
+// padding 0xEC 0x11 0xEC ... up to the version's capacity.
+func dataCodewords(text []byte, dataLen int) []byte {
+	var w bitWriter
+	w.write(0b0100, 4)
+	w.write(len(text), 8)
+	for _, b := range text {
+		w.write(int(b), 8)
+	}
+	w.write(0, min(4, dataLen*8-w.nbit)) // Terminator: up to four zero bits
+	for pad := 0xEC; len(w.buf) < dataLen; pad ^= 0xEC ^ 0x11 {
+		w.buf = append(w.buf, byte(pad)) // The last partial byte is already zero-filled
+	}
+	return w.buf
+}
+
+// ---------------------------------------------------------
+// Part 3: Reed–Solomon Error Correction
+// ---------------------------------------------------------
+// Codewords are numbers in GF(256): addition is XOR, multiplication is
+// carry-less and reduced by x⁸+x⁴+x³+x²+1 (0x11D). The error-correction
+// bytes are the remainder of dividing the data (as a polynomial) by a
+// generator polynomial — like a checksum that can also REPAIR damage.
+
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsGenerator returns (x-α⁰)(x-α¹)...(x-αⁿ⁻¹) without its leading 1.
+func rsGenerator(n int) []byte {
+	g := make([]byte, n)
+	g[n-1] = 1
+	root := byte(1)
+	for range n {
+		for j := range g {
+			g[j] = gfMul(g[j], root)
+			if j+1 < n {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return g
+}
+
+func rsRemainder(data []byte, n int) []byte {
+	gen := rsGenerator(n)
+	rem := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for i := range rem {
+			rem[i] ^= gfMul(gen[i], factor)
+		}
+	}
+	return rem
+}
+
+// ---------------------------------------------------------
+// Part 4: The Grid
+// ---------------------------------------------------------
+
+type QR struct {
+	Version int
+	Level   ECLevel
+	Mask    int
+	Size    int
+	dark    [][]bool
+	fixed   [][]bool // Function modules: never data, never masked
+}
+
+func newGrid(version int) *QR {
+	q := &QR{Version: version, Size: 17 + 4*version}
+	q.dark, q.fixed = make([][]bool, q.Size), make([][]bool, q.Size)
+	for y := range q.Size {
+		q.dark[y], q.fixed[y] = make([]bool, q.Size), make([]bool, q.Size)
+	}
+	q.drawFunctionPatterns()
+	return q
+}
+
+func (q *QR) set(x, y int, dark bool) {
+	q.dark[y][x], q.fixed[y][x] = dark, true
+}
+
+func (q *QR) drawFunctionPatterns() {
+	for i := range q.Size { // Timing patterns, drawn first; finders overwrite the ends
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+	for _, c := range [][2]int{{3, 3}, {q.Size - 4, 3}, {3, q.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ { // 7x7 eye plus its 1-module light separator
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || y < 0 || x >= q.Size || y >= q.Size {
+					continue
+				}
+				d := max(abs(dx), abs(dy))
+				q.set(x, y, d != 2 && d != 4)
+			}
+		}
+	}
+	if q.Version >= 2 { // Versions 2-6 have exactly one alignment pattern
+		p := 4*q.Version + 10
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				q.set(p+dx, p+dy, max(abs(dx), abs(dy)) != 1)
+			}
+		}
+	}
+	q.drawFormat(0) // Reserve the format areas; the real bits come last
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// formatPositions lists where format bit i goes, in both copies.
+func (q *QR) formatPositions(i int) (a, b [2]int) {
+	switch {
+	case i < 6:
+		a = [2]int{8, i}
+	case i < 8:
+		a = [2]int{8, i + 1} // Skip the timing row
+	case i == 8:
+		a = [2]int{7, 8}
+	default:
+		a = [2]int{14 - i, 8}
+	}
+	if i < 8 {
+		b = [2]int{q.Size - 1 - i, 8}
+	} else {
+		b = [2]int{8, q.Size - 15 + i}
+	}
+	return a, b
+}
+
+// formatInfo is 5 bits (level, mask) plus 10 BCH check bits, XORed with
+// a fixed pattern so it is never all zero.
+func formatInfo(level ECLevel, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (q *QR) drawFormat(bits int) {
+	for i := range 15 {
+		a, b := q.formatPositions(i)
+		q.set(a[0], a[1], bits>>i&1 == 1)
+		q.set(b[0], b[1], bits>>i&1 == 1)
+	}
+	q.set(8, q.Size-8, true) // The "dark module", always dark
+}
+
+// zigzag visits data modules in placement order: from the bottom-right,
+// two columns at a time, alternating up and down, skipping column 6.
+func (q *QR) zigzag(visit func(x, y int)) {
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for v := range q.Size {
+			y := v
+			if upward {
+				y = q.Size - 1 - v
+			}
+			for _, x := range []int{right, right - 1} {
+				if !q.fixed[y][x] {
+					visit(x, y)
+				}
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 5: Masks and Penalties
+// ---------------------------------------------------------
+// Data can accidentally draw something that looks like a finder eye, or
+// big blobs that confuse a camera. XORing with one of 8 fixed patterns
+// breaks that up; the encoder scores all 8 and keeps the best.
+
+var masks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+func (q *QR) applyMask(m int) {
+	for y := range q.Size {
+		for x := range q.Size {
+			if !q.fixed[y][x] && masks[m](x, y) {
+				q.dark[y][x] = !q.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the four rules of the standard: long runs, 2x2 blocks,
+// finder look-alikes, and an unbalanced dark/light ratio.
+func (q *QR) penalty() int {
+	score, darkCount := 0, 0
+	at := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return q.dark[y][x]
+		}
+		return q.dark[x][y]
+	}
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for _, h := range []bool{true, false} {
+		for a := range q.Size {
+			run := 1
+			for b := 1; b < q.Size; b++ {
+				if at(b, a, h) == at(b-1, a, h) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += 3 + run - 5
+			}
+			for b := 0; b+11 <= q.Size; b++ {
+				for _, pat := range finderLike {
+					match := true
+					for k, want := range pat {
+						if at(b+k, a, h) != want {
+							match = false
+							break
+						}
+					}
+					if match {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+	for y := range q.Size {
+		for x := range q.Size {
+			if q.dark[y][x] {
+				darkCount++
+			}
+			if x > 0 && y > 0 && q.dark[y][x] == q.dark[y-1][x] && q.dark[y][x] == q.dark[y][x-1] && q.dark[y][x] == q.dark[y-1][x-1] {
+				score += 3
+			}
+		}
+	}
+	percent := darkCount * 100 / (q.Size * q.Size)
+	return score + abs(percent-50)/5*10
+}
+
+// ---------------------------------------------------------
+// Part 6: Putting It Together
+// ---------------------------------------------------------
+
+// Encode builds the smallest QR code for text. mask -1 picks the best.
+func Encode(text string, level ECLevel, mask int) (*QR, error) {
+	version, err := chooseVersion(len(text), level)
+	if err != nil {
+		return nil, err
+	}
+	dc, ec := capacity[level][version][0], capacity[level][version][1]
+	data := dataCodewords([]byte(text), dc)
+	codewords := append(data, rsRemainder(data, ec)...)
+
+	q := newGrid(version)
+	q.Level = level
+	i := 0
+	q.zigzag(func(x, y int) {
+		if i < len(codewords)*8 { // Leftover modules (v2-5 have 7) stay light
+			q.dark[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+		}
+		i++
+	})
+
+	best, bestScore := mask, -1
+	if mask < 0 {
+		for m := range masks {
+			q.applyMask(m)
+			q.drawFormat(formatInfo(level, m))
+			if s := q.penalty(); bestScore < 0 || s < bestScore {
+				best, bestScore = m, s
+			}
+			q.applyMask(m) // XOR again to undo
+		}
+	}
+	q.Mask = best
+	q.applyMask(best)
+	q.drawFormat(formatInfo(level, best))
+	return q, nil
+}
+
+// ---------------------------------------------------------
+// Part 7: Reading It Back — the Scanner's Side
+// ---------------------------------------------------------
+// With no decoder in the standard library, the lesson checks itself:
+// read the format bits, undo the mask, collect the codewords in zigzag
+// order, verify Reed–Solomon (a zero remainder), and parse the bytes.
+
+func Decode(dark [][]bool) (string, error) {
+	size := len(dark)
+	version := (size - 17) / 4
+	if version < 1 || version > 5 || size != 17+4*version {
+		return "", fmt.Errorf("qr: unsupported size %d", size)
+	}
+	q := newGrid(version) // For the function-module map
+	var bits int
+	for i := range 15 {
+		a, _ := q.formatPositions(i)
+		if dark[a[1]][a[0]] {
+			bits |= 1 << i
+		}
+	}
+	level, mask, found := ECLevel(0), 0, false
+	for l := range ECLevel(2) { // Nearest valid code: survives a few wrong bits
+		for m := range 8 {
+			if diff := bits ^ formatInfo(l, m); popcount(diff) <= 3 {
+				level, mask, found = l, m, true
+			}
+		}
+	}
+	if !found || capacity[level][version][0] == 0 {
+		return "", errors.New("qr: unreadable format information")
+	}
+	dc, ec := capacity[level][version][0], capacity[level][version][1]
+	codewords := make([]byte, dc+ec)
+	i := 0
+	q.zigzag(func(x, y int) {
+		if i < len(codewords)*8 && dark[y][x] != masks[mask](x, y) {
+			codewords[i/8] |= 0x80 >> (i % 8)
+		}
+		i++
+	})
+	data := codewords[:dc]
+	if !bytes.Equal(rsRemainder(data, ec), codewords[dc:]) {
+		return "", errors.New("qr: error-correction check failed")
+	}
+	if data[0]>>4 != 0b0100 {
+		return "", fmt.Errorf("qr: mode %04b is not byte mode", data[0]>>4)
+	}
+	n := int(data[0]&0x0F)<<4 | int(data[1]>>4)
+	out := make([]byte, n)
+	for k := range n { // Each byte straddles two codewords: 4 bits + 4 bits
+		out[k] = data[1+k]<<4 | data[2+k]>>4
+	}
+	return string(out), nil
+}
+
+func popcount(x int) int {
+	n := 0
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return n
+}
+
+// ---------------------------------------------------------
+// Part 8: Rendering — Terminal and PNG
+// ---------------------------------------------------------
+
+const quiet = 4 // Light border the standard requires around the code
+
+func (q *QR) module(x, y int) bool {
+	x, y = x-quiet, y-quiet
+	return x >= 0 && y >= 0 && x < q.Size && y < q.Size && q.dark[y][x]
+}
+
+// Terminal draws two module rows per line with half blocks, dark
+// modules as █. On a dark-themed terminal that comes out inverted (light
+// code on dark); most phone scanners accept either.
+func (q *QR) Terminal(indent string) string {
+	var b strings.Builder
+	total := q.Size + 2*quiet
+	for y := 0; y < total; y += 2 {
+		b.WriteString(indent)
+		for x := range total {
+			top, bottom := q.module(x, y), q.module(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Image renders scale×scale pixels per module, quiet zone included.
+func (q *QR) Image(scale int) *image.Gray {
+	total := (q.Size + 2*quiet) * scale
+	img := image.NewGray(image.Rect(0, 0, total, total))
+	for y := range total {
+		for x := range total {
+			if !q.module(x/scale, y/scale) {
+				img.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+	return img
+}
+
+// readImage samples the centre of each module: the reverse of Image.
+func readImage(img image.Image, size, scale int) [][]bool {
+	dark := make([][]bool, size)
+	for y := range size {
+		dark[y] = make([]bool, size)
+		for x := range size {
+			px := img.At((x+quiet)*scale+scale/2, (y+quiet)*scale+scale/2)
+			dark[y][x] = color.GrayModel.Convert(px).(color.Gray).Y < 128
+		}
+	}
+	return dark
+}
+
+// lessonURL points at a topic file in the repository on GitHub.
+func lessonURL(file string) string {
+	return "https://github.com/akarsh323/Go-tutorials-/blob/main/go_projects/" + file
+}
+
+func main() {
+	outDir, text := "", ""
+	for i := 1; i+1 < len(os.Args); i += 2 {
+		switch os.Args[i] {
+		case "-o":
+			outDir = os.Args[i+1]
+		case "-text":
+			text = os.Args[i+1]
+		}
+	}
+	if text != "" {
+		q, err := Encode(text, ECLow, -1)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Print(q.Terminal(""))
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: GENERATING QR CODES FROM SCRATCH")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Checking the Math Against the Standard ---")
+	// "HELLO WORLD" at 1-Q, the worked example in most QR tutorials.
+	hello := []byte{0x20, 0x5B, 0x0B, 0x78, 0xD1, 0x72, 0xDC, 0x4D, 0x43, 0x40, 0xEC, 0x11, 0xEC}
+	wantEC := []byte{0xA8, 0x48, 0x16, 0x52, 0xD9, 0x36, 0x9C, 0x00, 0x2E, 0x0F, 0xB4, 0x7A, 0x10}
+	check := func(ok bool, what string) {
+		mark := "✓"
+		if !ok {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %s\n", mark, what)
+	}
+	check(bytes.Equal(rsRemainder(hello, 13), wantEC), "Reed–Solomon bytes for HELLO WORLD (1-Q) match the published ones")
+	check(formatInfo(ECLow, 0) == 0b111011111000100, "format info L/mask 0 = 111011111000100")
+	check(formatInfo(ECMedium, 0) == 0b101010000010010, "format info M/mask 0 = 101010000010010")
+	check(bytes.Equal(dataCodewords([]byte("hi"), 4), []byte{0x40, 0x26, 0x86, 0x90}), "\"hi\" → 0100 | 00000010 | 'h' | 'i' | 0000")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: A Code for a Lesson URL ---")
+	url := lessonURL("164_qr_codes.go")
+	q, err := Encode(url, ECLow, -1)
+	if err != nil {
+		fmt.Println("  ✗", err)
+		return
+	}
+	fmt.Printf("  %s\n  %d bytes → version %d (%dx%d), level %s, mask %d\n", url, len(url), q.Version, q.Size, q.Size, q.Level, q.Mask)
+	fmt.Print(q.Terminal("  "))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Versions Grow With the Text ---")
+	for _, s := range []string{"go", lessonURL("1.go"), lessonURL("163_image_processing.go"), strings.Repeat("x", 106), strings.Repeat("x", 107)} {
+		for _, level := range []ECLevel{ECLow, ECMedium} {
+			v, err := chooseVersion(len(s), level)
+			if err != nil {
+				fmt.Printf("  %3d bytes at %s: ✗ %v\n", len(s), level, err)
+				continue
+			}
+			fmt.Printf("  %3d bytes at %s: version %d, %dx%d modules\n", len(s), level, v, 17+4*v, 17+4*v)
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Why Masks Matter ---")
+	for m := range masks {
+		qm, _ := Encode("MASKS", ECLow, m)
+		fmt.Printf("  mask %d: penalty %4d\n", m, qm.penalty())
+	}
+	auto, _ := Encode("MASKS", ECLow, -1)
+	fmt.Printf("  The encoder picked mask %d.\n", auto.Mask)
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Read Every Code Back ---")
+	for _, s := range []string{"hi", "Hello, 世界", lessonURL("160_interp/"), strings.Repeat("z", 106)} {
+		for _, level := range []ECLevel{ECLow, ECMedium} {
+			qr, err := Encode(s, level, -1)
+			if err != nil {
+				continue
+			}
+			got, err := Decode(qr.dark)
+			check(err == nil && got == s, fmt.Sprintf("v%d-%s mask %d: %.40q", qr.Version, level, qr.Mask, got))
+		}
+	}
+	damaged, _ := Encode("hi", ECLow, -1)
+	damaged.dark[8][1] = !damaged.dark[8][1] // One format bit
+	got, err := Decode(damaged.dark)
+	check(err == nil && got == "hi", "still readable with a flipped format bit (the BCH code corrects it)")
+	damaged.dark[20][20] = !damaged.dark[20][20] // One data bit
+	_, err = Decode(damaged.dark)
+	fmt.Printf("  A flipped DATA bit: %v\n", err)
+	fmt.Println("  (A real scanner CORRECTS that with the RS bytes; this reader only detects it.)")
+	fmt.Println()
+
+	fmt.Println("--- Example 6: PNG Output, Decoded Again ---")
+	dir := outDir
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "gotut-qr-*"); err != nil {
+			fmt.Println("  ✗", err)
+			return
+		}
+		defer os.RemoveAll(dir)
+	}
+	for _, file := range []string{"163_image_processing.go", "164_qr_codes.go"} {
+		qr, err := Encode(lessonURL(file), ECLow, -1)
+		if err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		path := filepath.Join(dir, strings.TrimSuffix(file, ".go")+".png")
+		img := qr.Image(8)
+		if err := atomicfile.Write(path, 0o644, func(w io.Writer) error { return png.Encode(w, img) }); err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		back, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			fmt.Println("  ✗", err)
+			continue
+		}
+		text, err := Decode(readImage(back, qr.Size, 8))
+		info, _ := os.Stat(path)
+		check(err == nil && text == lessonURL(file), fmt.Sprintf("%-28s %dx%d px, %5d bytes, reads back as the URL",
+			filepath.Base(path), img.Bounds().Dx(), img.Bounds().Dy(), info.Size()))
+	}
+	if outDir != "" {
+		fmt.Println("  Kept in", outDir)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A QR code is bits in a grid plus fixed patterns a camera can lock onto.
+2. Reed–Solomon bytes are a polynomial remainder in GF(256).
+3. Data is laid out in a zigzag, two columns at a time, around fixed parts.
+4. Masks only break up confusing shapes; the format info says which one.
+5. Format info has its own BCH code, so it survives a few bad modules.
+6. Check an encoder with published vectors AND by reading its output back.
+7. Half-block characters draw two module rows per terminal line.
+	`)
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+TOPIC: CHARTS IN THE TERMINAL — BAR CHARTS, SPARKLINES, HISTOGRAMS
+
+CONCEPT:
+A tool that prints "200: 1412, 404: 87, 500: 3" makes the reader do the
+comparing. A bar makes the eye do it:
+
+    200 █████████████████████████████████████ 1412
+    404 ██▎                                     87
+    500 ▏                                        3
+
+Three building blocks cover most tool output:
+
+    BarChart    one labelled bar per value          (status codes, timings)
+    Sparkline   one character per value, no labels  (a trend: ▁▂▄▇█▆▃)
+    Histogram   bucket raw values, then a BarChart  (quiz scores, latencies)
+
+WIDTH-AWARE SCALING:
+The longest bar gets whatever columns are left after the label and the
+number, so the same chart fits a 40-column split pane or a 200-column
+monitor. Unicode has EIGHTHS of a block (▏▎▍▌▋▊▉█), so a bar's length
+is accurate to 1/8 of a column instead of a whole one.
+
+The width comes from -width, else $COLUMNS (set by most shells for
+interactive sessions), else 80. Asking the terminal itself needs an
+ioctl; pkg/term (used by 160_interp) shows that with build tags.
+
+This file is written the way a shared chart package would be, then
+used by three "tools": an access-log summary, a benchmark comparison
+(reading real `go test -bench` output), and a quiz score report.
+
+RUN:
+    go run 165_ascii_charts.go
+    go run 165_ascii_charts.go -width 50
+    (cd 160_interp && go test -run - -bench . -benchmem) | go run 165_ascii_charts.go -bench
+*/
+
+// ---------------------------------------------------------
+// Part 1: How Wide Is the Terminal?
+// ---------------------------------------------------------
+
+const defaultWidth = 80
+
+func terminalWidth(flagWidth int) int {
+	if flagWidth > 0 {
+		return flagWidth
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultWidth
+}
+
+// ---------------------------------------------------------
+// Part 2: Bars With 1/8-Column Precision
+// ---------------------------------------------------------
+
+var eighths = []rune(" ▏▎▍▌▋▊▉") // Index n = n eighths of a column
+
+// bar draws a bar of length cols (fractional) using full blocks plus
+// one partial block. Any value above zero shows at least ▏, so a tiny
+// count never looks like no count at all.
+func bar(cols float64) string {
+	if cols <= 0 {
+		return ""
+	}
+	n := int(math.Round(cols * 8))
+	s := strings.Repeat("█", n/8)
+	if n%8 > 0 {
+		s += string(eighths[n%8])
+	}
+	if s == "" {
+		s = "▏"
+	}
+	return s
+}
+
+// padRight pads by runes, not bytes: "日本" is 2 runes but 6 bytes.
+// (East Asian characters are 2 columns wide on most terminals; a full
+// solution uses a width table such as golang.org/x/text/width.)
+func padRight(s string, n int) string {
+	return s + strings.Repeat(" ", max(0, n-utf8.RuneCountInString(s)))
+}
+
+func padLeft(s string, n int) string {
+	return strings.Repeat(" ", max(0, n-utf8.RuneCountInString(s))) + s
+}
+
+// ---------------------------------------------------------
+// Part 3: BarChart
+// ---------------------------------------------------------
+
+type Bar struct {
+	Label string
+	Value float64
+}
+
+type BarChart struct {
+	Title  string
+	Bars   []Bar
+	Width  int                  // Total columns, label and number included
+	Format func(float64) string // How to print each value; default %g
+	Sort   bool                 // Largest first
+}
+
+func (c BarChart) Render(w io.Writer) {
+	format := c.Format
+	if format == nil {
+		format = func(v float64) string { return strconv.FormatFloat(v, 'g', 4, 64) }
+	}
+	bars := slices.Clone(c.Bars)
+	if c.Sort {
+		slices.SortStableFunc(bars, func(a, b Bar) int { return cmp.Compare(b.Value, a.Value) })
+	}
+	labelW, numW, maxV := 0, 0, 0.0
+	for _, b := range bars {
+		labelW = max(labelW, utf8.RuneCountInString(b.Label))
+		numW = max(numW, utf8.RuneCountInString(format(b.Value)))
+		maxV = max(maxV, b.Value)
+	}
+	// "  label │bar  number": 2 indent + label + " │" + bar + " " + number
+	barW := max(1, cmp.Or(c.Width, defaultWidth)-2-labelW-2-1-numW)
+	if c.Title != "" {
+		fmt.Fprintf(w, "  %s\n", c.Title)
+	}
+	for _, b := range bars {
+		cols := 0.0
+		if maxV > 0 {
+			cols = b.Value / maxV * float64(barW)
+		}
+		fmt.Fprintf(w, "  %s │%s %s\n", padLeft(b.Label, labelW), padRight(bar(cols), barW), padLeft(format(b.Value), numW))
+	}
+}
+
+// ---------------------------------------------------------
+// Part 4: Sparklines
+// ---------------------------------------------------------
+// Eight heights, scaled between the series' own min and max: good for
+// the SHAPE of a trend, useless for reading exact values. NaN marks a
+// missing sample and prints as a gap.
+
+var levels = []rune("▁▂▃▄▅▆▇█")
+
+func Sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo: // A flat series: draw it at mid-height, not as zero
+			b.WriteRune(levels[len(levels)/2])
+		default:
+			b.WriteRune(levels[int((v-lo)/(hi-lo)*float64(len(levels)-1)+0.5)])
+		}
+	}
+	return b.String()
+}
+
+// fitSparkline averages neighbouring values until the series fits in
+// width columns: a day of per-minute samples still fits one line.
+func fitSparkline(values []float64, width int) string {
+	if len(values) <= width {
+		return Sparkline(values)
+	}
+	out := make([]float64, width)
+	for i := range out {
+		from, to := i*len(values)/width, (i+1)*len(values)/width
+		sum := 0.0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		out[i] = sum / float64(to-from)
+	}
+	return Sparkline(out)
+}
+
+// ---------------------------------------------------------
+// Part 5: Histograms
+// ---------------------------------------------------------
+
+// Histogram counts values into equal buckets of size step from lo up,
+// labelled "lo–hi". The last bucket is closed so a perfect score counts.
+func Histogram(values []float64, lo, hi, step float64) []Bar {
+	n := int(math.Ceil((hi - lo) / step))
+	bars := make([]Bar, n)
+	for i := range bars {
+		from := lo + float64(i)*step
+		bars[i].Label = fmt.Sprintf("%g–%g", from, min(hi, from+step))
+	}
+	for _, v := range values {
+		i := int((v - lo) / step)
+		if v == hi {
+			i = n - 1
+		}
+		if i >= 0 && i < n {
+			bars[i].Value++
+		}
+	}
+	return bars
+}
+
+// ---------------------------------------------------------
+// Part 6: Tool 1 — Status Codes From an Access Log
+// ---------------------------------------------------------
+
+const accessLog = `127.0.0.1 - - [16/Oct/2026:10:00:01 +0000] "GET / HTTP/1.1" 200 512
+127.0.0.1 - - [16/Oct/2026:10:00:02 +0000] "GET /lessons HTTP/1.1" 200 2048
+10.0.0.7 - - [16/Oct/2026:10:00:02 +0000] "GET /favicon.ico HTTP/1.1" 404 0
+10.0.0.7 - - [16/Oct/2026:10:00:03 +0000] "POST /quiz HTTP/1.1" 201 64
+10.0.0.9 - - [16/Oct/2026:10:00:05 +0000] "GET /lessons/163 HTTP/1.1" 304 0
+10.0.0.9 - - [16/Oct/2026:10:00:06 +0000] "GET /lessons/999 HTTP/1.1" 404 0
+10.0.0.4 - - [16/Oct/2026:10:00:07 +0000] "GET /admin HTTP/1.1" 403 0
+10.0.0.4 - - [16/Oct/2026:10:00:09 +0000] "POST /quiz HTTP/1.1" 500 0
+malformed line
+`
+
+// statusCounts reads the status field of Common Log Format lines — the
+// first field after the quoted request — and counts each code.
+func statusCounts(r io.Reader) (map[string]int, int) {
+	counts, bad := map[string]int{}, 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		_, after, ok := strings.Cut(sc.Text(), `" `)
+		fields := strings.Fields(after)
+		if !ok || len(fields) < 1 {
+			bad++
+			continue
+		}
+		counts[fields[0]]++
+	}
+	return counts, bad
+}
+
+// ---------------------------------------------------------
+// Part 7: Tool 2 — Benchmark Comparison
+// ---------------------------------------------------------
+// Reads `go test -bench` lines: "BenchmarkFib/vm-8  529  2475844 ns/op ..."
+
+type benchResult struct {
+	Name   string
+	NsOp   float64
+	Allocs float64
+}
+
+func parseBench(r io.Reader) []benchResult {
+	var out []benchResult
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 4 || !strings.HasPrefix(f[0], "Benchmark") {
+			continue
+		}
+		res := benchResult{Name: strings.TrimPrefix(f[0], "Benchmark")}
+		if i := strings.LastIndexByte(res.Name, '-'); i > 0 { // Drop the -GOMAXPROCS suffix
+			if _, err := strconv.Atoi(res.Name[i+1:]); err == nil {
+				res.Name = res.Name[:i]
+			}
+		}
+		for i := 2; i+1 < len(f); i += 2 {
+			v, err := strconv.ParseFloat(f[i], 64)
+			if err != nil {
+				continue
+			}
+			switch f[i+1] {
+			case "ns/op":
+				res.NsOp = v
+			case "allocs/op":
+				res.Allocs = v
+			}
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+// Output of `go test -bench . -benchmem` in 160_interp, kept as a sample.
+const benchSample = `goos: linux
+goarch: amd64
+BenchmarkFib/tree         	     110	  10636658 ns/op	10477189 B/op	  225710 allocs/op
+BenchmarkFib/vm           	     529	   2475844 ns/op	  242240 B/op	   28745 allocs/op
+BenchmarkLoop/tree        	      26	  46726891 ns/op	20083276 B/op	 1110036 allocs/op
+BenchmarkLoop/vm          	     100	  12216816 ns/op	 2088402 B/op	  260071 allocs/op
+BenchmarkStrings/tree     	      82	  14713215 ns/op	 4511108 B/op	  211127 allocs/op
+BenchmarkStrings/vm       	     121	   9815763 ns/op	 1618114 B/op	  116745 allocs/op
+PASS
+`
+
+func duration(ns float64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.2fs", ns/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.2fms", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.1fµs", ns/1e3)
+	}
+	return fmt.Sprintf("%.0fns", ns)
+}
+
+func renderBench(w io.Writer, results []benchResult, width int) {
+	var timeBars, allocBars []Bar
+	for _, r := range results {
+		timeBars = append(timeBars, Bar{r.Name, r.NsOp})
+		allocBars = append(allocBars, Bar{r.Name, r.Allocs})
+	}
+	BarChart{Title: "time per op (shorter is faster)", Bars: timeBars, Width: width, Format: duration}.Render(w)
+	fmt.Fprintln(w)
+	BarChart{Title: "allocations per op", Bars: allocBars, Width: width,
+		Format: func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }}.Render(w)
+}
+
+func main() {
+	width, benchStdin := 0, false
+	for i := 1; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "-width":
+			if i+1 < len(os.Args) {
+				width, _ = strconv.Atoi(os.Args[i+1])
+				i++
+			}
+		case "-bench":
+			benchStdin = true
+		}
+	}
+	width = terminalWidth(width)
+	if benchStdin {
+		renderBench(os.Stdout, parseBench(os.Stdin), width)
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: CHARTS IN THE TERMINAL — BAR CHARTS, SPARKLINES, HISTOGRAMS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Eighths of a Block ---")
+	for _, cols := range []float64{0, 0.1, 0.5, 1, 2.25, 3.875, 6} {
+		fmt.Printf("  %5.3f columns │%s\n", cols, bar(cols))
+	}
+	fmt.Printf("  Chart width: %d columns (-width, then $COLUMNS, then %d)\n", width, defaultWidth)
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Status Codes From an Access Log ---")
+	counts, bad := statusCounts(strings.NewReader(accessLog))
+	for code, n := range map[string]int{"200": 1410, "304": 220, "404": 85, "201": 40} {
+		counts[code] += n // The rest of a busy day, so the bars have something to compare
+	}
+	var bars []Bar
+	for code, n := range counts {
+		bars = append(bars, Bar{code, float64(n)})
+	}
+	BarChart{Title: "requests by status", Bars: bars, Width: width, Sort: true}.Render(os.Stdout)
+	fmt.Printf("  (%d malformed line skipped)\n", bad)
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The Same Chart in a Narrow Pane ---")
+	BarChart{Bars: bars, Width: 36, Sort: true}.Render(os.Stdout)
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Benchmarks, Tree-Walker vs VM ---")
+	results := parseBench(strings.NewReader(benchSample))
+	renderBench(os.Stdout, results, width)
+	for i := 0; i+1 < len(results); i += 2 {
+		fmt.Printf("  %-8s vm is %.1fx faster\n", strings.Split(results[i].Name, "/")[0], results[i].NsOp/results[i+1].NsOp)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Sparklines ---")
+	latency := []float64{12, 14, 13, 15, 22, 41, 38, 19, 14, 13, math.NaN(), 12, 12, 16, 30, 55, 48, 21, 15, 13}
+	fmt.Printf("  p50 latency (ms)  %s  12–55 ms; the gap is a missing sample\n", Sparkline(latency))
+	fmt.Printf("  flat series       %s\n", Sparkline([]float64{3, 3, 3, 3}))
+	var day []float64
+	for minute := range 24 * 60 {
+		h := float64(minute) / 60
+		day = append(day, 100+80*math.Sin((h-9)/24*2*math.Pi)+float64(minute%7))
+	}
+	fmt.Printf("  1440 samples → %d cols  %s\n", 48, fitSparkline(day, 48))
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Histogram of Quiz Scores ---")
+	scores := []float64{42, 55, 61, 64, 68, 70, 71, 73, 75, 75, 77, 78, 80, 81, 83, 85, 86, 88, 90, 92, 95, 100, 100}
+	BarChart{
+		Title:  fmt.Sprintf("%d learners, median %g", len(scores), scores[len(scores)/2]),
+		Bars:   Histogram(scores, 40, 100, 10),
+		Width:  min(width, 60),
+		Format: func(v float64) string { return strconv.Itoa(int(v)) },
+	}.Render(os.Stdout)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Give the longest bar whatever width is left after labels and numbers.
+2. Eighth-blocks make bars accurate to 1/8 of a column.
+3. Never let a non-zero value draw as an empty bar.
+4. Pad by runes, not bytes — and wide characters need a width table.
+5. Sparklines show shape; scale them to the series' own min and max.
+6. Downsample long series to the width instead of wrapping them.
+7. Read width from a flag, then $COLUMNS, then fall back to 80.
+	`)
+}
@@ -0,0 +1,722 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/splitters"
+)
+
+/*
+TOPIC: ONE --output FLAG FOR EVERY TOOL — TABLE, CSV AND JSON REPORTS
+
+CONCEPT:
+Small tools grow one output format at a time: du prints a table, then
+someone wants CSV for a spreadsheet, then JSON for a script — and each
+tool invents its own flags and its own quirks. Instead, every tool
+builds the same thing, a REPORT, and one exporter writes it:
+
+    tool logic ──► Report{Schema, Rows} ──► --output table   humans
+                                        ├─► --output csv     spreadsheets
+                                        └─► --output json    jq, scripts
+
+THE SCHEMA IS TYPED. A column knows whether it holds bytes, a duration,
+a time or a count, so each format can render it properly:
+
+    Kind       table          csv / json
+    Bytes      "1.5 MiB"      1572864        (column "size_bytes")
+    Duration   "1.2s"         1.2            (column "elapsed_seconds")
+    Time       "10:04:05"     "2026-10-16T10:04:05Z"
+    Int        right-aligned  42
+
+Humans get friendly units; machines get plain numbers in a unit named
+in the header — never "1.5 MiB" in a CSV cell.
+
+SPREADSHEET SAFETY: a CSV cell starting with = + - @ is run as a FORMULA
+by Excel and friends ("CSV injection"). File names and log lines are
+user data, so the CSV writer quotes them with a leading '.
+
+The tree has no separate tool binaries or table/csv helpers, so this
+file plays all the parts: the report "package" first, then four tools
+(du, dupfind, logstats, progress) that share its --output flag.
+
+RUN:
+    go run 166_report_exporter.go
+    go run 166_report_exporter.go du --output csv .
+    go run 166_report_exporter.go dupfind --output json .
+    go run 166_report_exporter.go logstats --format app app.log
+*/
+
+// ---------------------------------------------------------
+// Part 1: Typed Columns
+// ---------------------------------------------------------
+
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindFloat
+	KindBytes
+	KindDuration
+	KindTime
+)
+
+type Column struct {
+	Name string
+	Kind Kind
+}
+
+// Key is the machine-readable column name: the unit goes in the header
+// so the values can stay plain numbers.
+func (c Column) Key() string {
+	switch c.Kind {
+	case KindBytes:
+		return c.Name + "_bytes"
+	case KindDuration:
+		return c.Name + "_seconds"
+	}
+	return c.Name
+}
+
+type Schema []Column
+
+// Report is what every tool produces. Add checks each value against its
+// column, so a tool bug shows up where it happens, not in the CSV.
+type Report struct {
+	Schema Schema
+	Rows   [][]any
+}
+
+var ErrSchema = errors.New("report: value does not match schema")
+
+func (r *Report) Add(values ...any) error {
+	if len(values) != len(r.Schema) {
+		return fmt.Errorf("%w: %d values for %d columns", ErrSchema, len(values), len(r.Schema))
+	}
+	for i, v := range values {
+		ok := false
+		switch r.Schema[i].Kind {
+		case KindString:
+			_, ok = v.(string)
+		case KindInt, KindBytes:
+			_, ok = v.(int64)
+		case KindFloat:
+			_, ok = v.(float64)
+		case KindDuration:
+			_, ok = v.(time.Duration)
+		case KindTime:
+			_, ok = v.(time.Time)
+		}
+		if !ok {
+			return fmt.Errorf("%w: column %s got %T", ErrSchema, r.Schema[i].Name, v)
+		}
+	}
+	r.Rows = append(r.Rows, values)
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 2: The Shared --output Flag
+// ---------------------------------------------------------
+// A flag.Value rejects bad formats at parse time, with the list of good
+// ones, in every tool at once.
+
+type Format string
+
+var formats = []Format{"table", "csv", "json"}
+
+func (f *Format) String() string { return string(*f) }
+
+func (f *Format) Set(s string) error {
+	if !slices.Contains(formats, Format(s)) {
+		return fmt.Errorf("want one of %v", formats)
+	}
+	*f = Format(s)
+	return nil
+}
+
+// OutputFlag registers --output on a tool's FlagSet.
+func OutputFlag(fs *flag.FlagSet) *Format {
+	f := Format("table")
+	fs.Var(&f, "output", "output format: table, csv or json")
+	return &f
+}
+
+// toolFlags is how every tool starts: its own FlagSet, usage to stderr,
+// and the shared --output flag already registered.
+func toolFlags(name string, stderr io.Writer) (*flag.FlagSet, *Format) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags, OutputFlag(flags)
+}
+
+// ---------------------------------------------------------
+// Part 3: Three Writers
+// ---------------------------------------------------------
+
+func (r *Report) Write(w io.Writer, f Format) error {
+	switch f {
+	case "csv":
+		return r.writeCSV(w)
+	case "json":
+		return r.writeJSON(w)
+	}
+	return r.writeTable(w)
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// writeTable aligns columns by rune count: numbers to the right so
+// their digits line up, text to the left.
+func (r *Report) writeTable(w io.Writer) error {
+	rows := make([][]string, 0, len(r.Rows)+1)
+	header := make([]string, len(r.Schema))
+	for i, c := range r.Schema {
+		header[i] = strings.ToUpper(c.Name)
+	}
+	rows = append(rows, header)
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			switch v := v.(type) {
+			case int64:
+				if r.Schema[i].Kind == KindBytes {
+					cells[i] = humanBytes(v)
+				} else {
+					cells[i] = strconv.FormatInt(v, 10)
+				}
+			case float64:
+				cells[i] = strconv.FormatFloat(v, 'f', 1, 64)
+			case time.Duration:
+				cells[i] = v.Round(time.Millisecond).String()
+			case time.Time:
+				cells[i] = v.Format(time.TimeOnly)
+			default:
+				cells[i] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, cells)
+	}
+	widths := make([]int, len(r.Schema))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			if k := r.Schema[i].Kind; k == KindString || k == KindTime {
+				line.WriteString(cell + pad)
+			} else {
+				line.WriteString(pad + cell)
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// machine renders one value for CSV: plain numbers in the header's unit.
+func machine(v any) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Duration:
+		return strconv.FormatFloat(v.Seconds(), 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v // Would otherwise be a spreadsheet formula
+		}
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+func (r *Report) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(r.Schema))
+	for i, c := range r.Schema {
+		header[i] = c.Key()
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		rec := make([]string, len(row))
+		for i, v := range row {
+			rec[i] = machine(v)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error() // csv.Writer buffers: the error surfaces only here
+}
+
+// writeJSON emits one array of objects with keys in schema order (a
+// map would sort them). Numbers stay numbers; JSON has no formula
+// problem, so strings are written as they are.
+func (r *Report) writeJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for n, row := range r.Rows {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for i, v := range row {
+			switch t := v.(type) {
+			case time.Duration:
+				v = t.Seconds()
+			case time.Time:
+				v = t.UTC().Format(time.RFC3339)
+			}
+			key, _ := json.Marshal(r.Schema[i].Key())
+			val, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(val)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// ---------------------------------------------------------
+// Part 4: The Tools
+// ---------------------------------------------------------
+// Each tool: parse flags (including the shared --output), compute, fill
+// a Report, hand it to Write. None of them knows what CSV is.
+
+type tool func(args []string, stdout, stderr io.Writer) error
+
+var tools = map[string]tool{
+	"du":       duTool,
+	"dupfind":  dupfindTool,
+	"logstats": logstatsTool,
+	"progress": progressTool,
+}
+
+// du: disk usage per top-level entry of a directory, largest first.
+func duTool(args []string, stdout, stderr io.Writer) error {
+	flags, output := toolFlags("du", stderr)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := cmp.Or(flags.Arg(0), ".")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	r := &Report{Schema: Schema{{"path", KindString}, {"size", KindBytes}, {"files", KindInt}}}
+	type usage struct {
+		path  string
+		size  int64
+		files int64
+	}
+	var all []usage
+	for _, e := range entries {
+		u := usage{path: e.Name()}
+		err := filepath.WalkDir(filepath.Join(root, e.Name()), func(_ string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			u.size += info.Size()
+			u.files++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		all = append(all, u)
+	}
+	slices.SortFunc(all, func(a, b usage) int { return int(b.size - a.size) })
+	for _, u := range all {
+		if err := r.Add(u.path, u.size, u.files); err != nil {
+			return err
+		}
+	}
+	return r.Write(stdout, *output)
+}
+
+// dupfind: files with identical contents, grouped by SHA-256.
+func dupfindTool(args []string, stdout, stderr io.Writer) error {
+	flags, output := toolFlags("dupfind", stderr)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := cmp.Or(flags.Arg(0), ".")
+	groups := map[[32]byte][]string{}
+	sizes := map[[32]byte]int64{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		rel, _ := filepath.Rel(root, path)
+		groups[sum] = append(groups[sum], rel)
+		sizes[sum] = int64(len(data))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r := &Report{Schema: Schema{{"group", KindInt}, {"sha256", KindString}, {"size", KindBytes}, {"path", KindString}}}
+	var sums [][32]byte
+	for sum, paths := range groups {
+		if len(paths) > 1 {
+			sums = append(sums, sum)
+		}
+	}
+	slices.SortFunc(sums, func(a, b [32]byte) int { return bytes.Compare(a[:], b[:]) })
+	for g, sum := range sums {
+		slices.Sort(groups[sum])
+		for _, p := range groups[sum] {
+			if err := r.Add(int64(g+1), fmt.Sprintf("%x", sum[:6]), sizes[sum], p); err != nil {
+				return err
+			}
+		}
+	}
+	return r.Write(stdout, *output)
+}
+
+// logstats: requests and bytes per status code from a Common Log Format
+// file, or with --format app, entries per level from an application log.
+// An app log entry starts with a timestamp and may go on for lines (a
+// panic's stack trace), so it is scanned with splitters.Timestamped: the
+// trace stays one entry instead of a dozen lines with no level.
+func logstatsTool(args []string, stdout, stderr io.Writer) error {
+	flags, output := toolFlags("logstats", stderr)
+	format := flags.String("format", "clf", "log format: clf (access log) or app (timestamped, multi-line)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r *Report
+	switch *format {
+	case "clf":
+		r, err = accessStats(f)
+	case "app":
+		r, err = levelStats(f)
+	default:
+		return fmt.Errorf("unknown --format %q (want clf or app)", *format)
+	}
+	if err != nil {
+		return err
+	}
+	return r.Write(stdout, *output)
+}
+
+func accessStats(in io.Reader) (*Report, error) {
+	type stat struct{ requests, bytes int64 }
+	stats := map[string]*stat{}
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		_, after, ok := strings.Cut(sc.Text(), `" `)
+		f := strings.Fields(after)
+		if !ok || len(f) < 2 {
+			continue
+		}
+		s := stats[f[0]]
+		if s == nil {
+			s = &stat{}
+			stats[f[0]] = s
+		}
+		n, _ := strconv.ParseInt(f[1], 10, 64)
+		s.requests++
+		s.bytes += n
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	r := &Report{Schema: Schema{{"status", KindString}, {"requests", KindInt}, {"sent", KindBytes}}}
+	for _, code := range slices.Sorted(maps.Keys(stats)) {
+		if err := r.Add(code, stats[code].requests, stats[code].bytes); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func levelStats(in io.Reader) (*Report, error) {
+	type stat struct{ entries, traces, traceLines int64 }
+	stats := map[string]*stat{}
+	sc := bufio.NewScanner(in)
+	sc.Buffer(nil, 1<<20) // A goroutine dump can outgrow the default 64 KiB
+	sc.Split(splitters.Timestamped)
+	for sc.Scan() {
+		first, rest, _ := strings.Cut(sc.Text(), "\n")
+		level := "-" // Lines before the first timestamp
+		if splitters.StartsWithTimestamp([]byte(first)) {
+			// Skip the date, the time (with any fraction or zone) and take
+			// the next word: "2026-10-16T10:00:02.5Z ERROR msg" → ERROR.
+			if f := strings.Fields(first[len("2006-01-02T15:04"):]); len(f) > 1 {
+				level = f[1]
+			}
+		}
+		s := stats[level]
+		if s == nil {
+			s = &stat{}
+			stats[level] = s
+		}
+		s.entries++
+		if rest != "" {
+			s.traces++
+			s.traceLines += int64(strings.Count(rest, "\n") + 1)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	r := &Report{Schema: Schema{{"level", KindString}, {"entries", KindInt}, {"with_trace", KindInt}, {"trace_lines", KindInt}}}
+	for _, level := range slices.Sorted(maps.Keys(stats)) {
+		s := stats[level]
+		if err := r.Add(level, s.entries, s.traces, s.traceLines); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// progress: a snapshot of running transfers — the report a progress
+// display would show, for a script that polls instead of watching.
+func progressTool(args []string, stdout, stderr io.Writer) error {
+	flags, output := toolFlags("progress", stderr)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	start := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	r := &Report{Schema: Schema{{"task", KindString}, {"started", KindTime}, {"elapsed", KindDuration}, {"done", KindBytes}, {"percent", KindFloat}}}
+	for _, t := range []struct {
+		name       string
+		offset     time.Duration
+		elapsed    time.Duration
+		done, size int64
+	}{
+		{"backup.tar", 0, 95 * time.Second, 3 << 30, 4 << 30},
+		{"=HYPERLINK(\"http://x\")", 40 * time.Second, 1500 * time.Millisecond, 512 << 10, 1 << 20},
+		{"logs.zip", 90 * time.Second, 250 * time.Millisecond, 100, 100},
+	} {
+		pct := float64(t.done) * 100 / float64(t.size)
+		if err := r.Add(t.name, start.Add(t.offset), t.elapsed, t.done, pct); err != nil {
+			return err
+		}
+	}
+	return r.Write(stdout, *output)
+}
+
+// ---------------------------------------------------------
+// Part 5: A Sandbox to Run the Tools In
+// ---------------------------------------------------------
+
+func makeSandbox() (string, error) {
+	dir, err := os.MkdirTemp("", "gotut-report-*")
+	if err != nil {
+		return "", err
+	}
+	files := map[string]string{
+		"docs/a.txt":        "hello\n",
+		"docs/copy-of-a":    "hello\n",
+		"photos/1.raw":      strings.Repeat("x", 300_000),
+		"photos/2.raw":      strings.Repeat("y", 120_000),
+		"photos/backup.raw": strings.Repeat("y", 120_000),
+		"notes.md":          "# notes\n",
+		"access.log": `1.2.3.4 - - [16/Oct/2026:10:00:01 +0000] "GET / HTTP/1.1" 200 512
+1.2.3.4 - - [16/Oct/2026:10:00:02 +0000] "GET /big HTTP/1.1" 200 1048576
+5.6.7.8 - - [16/Oct/2026:10:00:03 +0000] "GET /nope HTTP/1.1" 404 0
+5.6.7.8 - - [16/Oct/2026:10:00:04 +0000] "POST /quiz HTTP/1.1" 500 17
+`,
+		"app.log": `2026-10-16T10:00:01Z INFO listening on :8080
+2026-10-16T10:00:04Z ERROR POST /quiz: handler panicked: runtime error: index out of range [3] with length 3
+goroutine 21 [running]:
+main.grade(...)
+	/srv/quiz/grade.go:42 +0x1d
+main.quizHandler({0x7f3a2c, 0xc000120000}, 0xc000132000)
+	/srv/quiz/handler.go:17 +0x8b
+2026-10-16T10:00:05Z WARN slow request: GET /big took 1.2s
+2026-10-16T10:00:09Z ERROR write /var/log/quiz: no space left on device
+2026-10-16T10:00:10Z INFO shutting down
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return dir, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		t, ok := tools[os.Args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown tool %q (want du, dupfind, logstats or progress)\n", os.Args[1])
+			os.Exit(2)
+		}
+		if err := t(os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintln(os.Stderr, os.Args[1]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: ONE --output FLAG FOR EVERY TOOL — TABLE, CSV AND JSON REPORTS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := makeSandbox()
+	if dir != "" {
+		defer os.RemoveAll(dir)
+	}
+	if err != nil {
+		fmt.Println("  ✗", err)
+		return
+	}
+	run := func(args ...string) {
+		fmt.Printf("  $ %s\n", strings.ReplaceAll(strings.Join(args, " "), dir, "$SANDBOX"))
+		var out bytes.Buffer
+		if err := tools[args[0]](args[1:], &out, io.Discard); err != nil { // Errors are shown below
+			fmt.Println("  ✗", err)
+		}
+		for line := range strings.Lines(out.String()) {
+			fmt.Print("    ", line)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("--- Example 1: du — the Same Report Three Ways ---")
+	run("du", dir)
+	run("du", "--output", "csv", dir)
+	run("du", "--output", "json", dir)
+
+	fmt.Println("--- Example 2: dupfind and logstats Share the Flag ---")
+	run("dupfind", dir)
+	run("logstats", "--output", "csv", filepath.Join(dir, "access.log"))
+	run("logstats", "--format", "app", filepath.Join(dir, "app.log"))
+	fmt.Println("  The panic's five trace lines stayed with their ERROR entry: the")
+	fmt.Println("  multi-line splitter (pkg/splitters, Topic 80) ends an entry only")
+	fmt.Println("  where the next timestamp starts. Read line by line, they would")
+	fmt.Println("  be five more \"entries\" with no level.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Durations, Times and a Hostile Task Name ---")
+	run("progress")
+	run("progress", "--output", "csv")
+	fmt.Println("  The task named =HYPERLINK(...) became '=HYPERLINK(...) in the CSV:")
+	fmt.Println("  a spreadsheet shows it as text instead of running it.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Bad Input Fails Early ---")
+	run("du", "--output", "xml", dir)
+	r := &Report{Schema: Schema{{"size", KindBytes}}}
+	fmt.Println("  Add(\"12MB\") to a Bytes column:", r.Add("12MB"))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Scripts Read It Back ---")
+	var out bytes.Buffer
+	if err := duTool([]string{"--output", "json", dir}, &out, os.Stderr); err == nil {
+		var rows []struct {
+			Path  string `json:"path"`
+			Size  int64  `json:"size_bytes"`
+			Files int64  `json:"files"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+			fmt.Println("  ✗", err)
+		} else {
+			var total int64
+			for _, row := range rows {
+				total += row.Size
+			}
+			fmt.Printf("  ✓ decoded %d rows into a struct; total %d bytes (%s)\n", len(rows), total, humanBytes(total))
+		}
+	}
+	out.Reset()
+	if err := duTool([]string{"--output", "csv", dir}, &out, os.Stderr); err == nil {
+		recs, err := csv.NewReader(&out).ReadAll()
+		fmt.Printf("  ✓ csv.Reader got %d records, header %v, err=%v\n", len(recs), recs[0], err)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Tools build a Report; one exporter owns every output format.
+2. Typed columns let each format render values its own way.
+3. Machines get plain numbers with the unit in the header name.
+4. A flag.Value validates --output in every tool at parse time.
+5. Prefix CSV cells starting with = + - @ to stop formula injection.
+6. csv.Writer buffers: check cw.Error() after Flush.
+7. Split records where they really end: a stack trace is one entry.
+	`)
+}
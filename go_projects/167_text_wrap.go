@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/textwrap"
+)
+
+/*
+TOPIC: TEXT WRAPPING — REFLOWING PROSE TO THE TERMINAL'S WIDTH
+
+CONCEPT:
+Lesson headers are hard-wrapped at about 72 columns in the source. In a
+50-column split pane every line overflows and the terminal folds it
+mid-word:
+
+    Small tools grow one output format at a time: du p
+    rints a table, then someone wants CSV for a spreads
+
+REFLOWING means throwing the old line breaks away and choosing new ones
+for the width we actually have. The algorithm is GREEDY: put words on
+the line until the next one would not fit, then start a new line. It is
+what terminals, editors and `fmt(1)` do, and it is optimal for
+monospace text where every line just has to fit.
+
+WHAT COUNTS AS A WORD:
+- Words are separated by spaces, tabs and newlines only. A no-break
+  space (U+00A0) glues "10 MB" together on purpose.
+- A `code span` is one word even when it contains spaces: breaking
+  `go test -run X` across lines makes it impossible to copy.
+- ANSI colour codes take no columns: "\x1b[1mbold\x1b[0m" is 4 wide.
+- A word longer than the whole line (a URL) gets a line of its own and
+  overflows. Cutting it in half would break copy and paste.
+
+WHAT IS NOT PROSE:
+- Indented lines are code or diagrams and are printed verbatim.
+- A list item ("- ", "* ", "3. ") wraps with a HANGING INDENT, so the
+  continuation lines line up under the text, not under the marker.
+- A heading line ("RUN:") stays on its own line.
+
+Wrap, WrapIndent, Width and Words are pkg/textwrap, which gotut's help
+and 168's notes wrap with too. This file writes the lesson renderer on
+top of them — Render — and then uses it on the other lessons' own
+headers.
+
+RUN:
+    go run 167_text_wrap.go
+    go run 167_text_wrap.go -width 50 165_ascii_charts.go
+    COLUMNS=40 go run 167_text_wrap.go 166_report_exporter.go
+*/
+
+// ---------------------------------------------------------
+// Part 1: How Wide Is the Terminal?
+// ---------------------------------------------------------
+
+const defaultWidth = 80
+
+// terminalWidth tries, in order: the -width flag, $COLUMNS, asking the
+// terminal with stty, and 80. 165 stops before stty; it is worth the
+// extra step here because $COLUMNS is a shell variable that is usually
+// NOT exported, so `go run` rarely sees it. stty reads the size with an
+// ioctl on the terminal we give it as stdin — no cgo, no build tags —
+// and simply fails on Windows or when there is no terminal.
+func terminalWidth(flagWidth int) int {
+	if flagWidth > 0 {
+		return flagWidth
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		cmd := exec.Command("stty", "size") // Prints "rows cols"
+		cmd.Stdin = os.Stdin
+		if out, err := cmd.Output(); err == nil {
+			if f := strings.Fields(string(out)); len(f) == 2 {
+				if n, err := strconv.Atoi(f[1]); err == nil && n > 0 {
+					return n
+				}
+			}
+		}
+	}
+	return defaultWidth
+}
+
+// Width, Words, Wrap and WrapIndent are pkg/textwrap; the renderer
+// below decides what to wrap, and how.
+
+// ---------------------------------------------------------
+// Part 2: The Renderer — Prose, Lists, Headings and Code
+// ---------------------------------------------------------
+
+// listMarker matches "- ", "* " and "12. " at the start of a line.
+var listMarker = regexp.MustCompile(`^(?:[-*]|\d+\.) +`)
+
+// heading matches a short line ending in a colon with no lowercase
+// letters: "RUN:", "WHAT COUNTS AS A WORD:".
+var heading = regexp.MustCompile(`^[^a-z]{1,40}:$`)
+
+func indented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+// Render reflows lesson prose to width. The input is split into blocks
+// at blank lines; inside a block, runs of indented lines are code and
+// are copied verbatim, list items get a hanging indent, headings keep
+// their own line, and everything else is one paragraph.
+func Render(w io.Writer, text string, width int) error {
+	var out strings.Builder
+	blocks := strings.Split(strings.ReplaceAll(strings.Trim(text, "\n"), "\r\n", "\n"), "\n\n")
+	for i, block := range blocks {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		renderBlock(&out, strings.Split(strings.Trim(block, "\n"), "\n"), width)
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func renderBlock(out *strings.Builder, lines []string, width int) {
+	var para []string // Prose lines waiting to be wrapped together
+	var marker string // Non-empty while para is a list item
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		text := strings.Join(para, " ")
+		if marker == "" {
+			out.WriteString(textwrap.Wrap(text, width) + "\n")
+		} else {
+			hang := strings.Repeat(" ", textwrap.Width(marker))
+			out.WriteString(textwrap.WrapIndent(text, width, marker, hang) + "\n")
+		}
+		para, marker = nil, ""
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue // Whitespace-only lines inside a block
+		}
+		m := listMarker.FindString(line)
+		switch {
+		case m != "":
+			flush()
+			marker, para = m, []string{line[len(m):]}
+		case marker != "" && indented(line):
+			// "  continued" under a list item belongs to the item
+			para = append(para, strings.TrimSpace(line))
+		case indented(line):
+			flush()
+			out.WriteString(strings.TrimRight(line, " \t") + "\n")
+		case heading.MatchString(line):
+			flush()
+			out.WriteString(line + "\n")
+		default:
+			if marker != "" {
+				flush() // An unindented line ends the item
+			}
+			para = append(para, line)
+		}
+	}
+	flush()
+}
+
+// ---------------------------------------------------------
+// Part 3: Checking the Rules
+// ---------------------------------------------------------
+
+// headerOf returns a lesson's first /* ... */ comment, the prose the
+// renderer exists for.
+func headerOf(path string) (string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	_, after, ok := bytes.Cut(src, []byte("/*"))
+	body, _, closed := bytes.Cut(after, []byte("*/"))
+	if !ok || !closed {
+		return "", fmt.Errorf("%s: no /* */ header", path)
+	}
+	return string(body), nil
+}
+
+// codeLines are the lines Render must leave alone: indented ones that
+// are not the continuation of a list item.
+func codeLines(text string) []string {
+	var code []string
+	inItem := false
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			inItem = false
+		case listMarker.MatchString(line):
+			inItem = true
+		case indented(line) && !inItem:
+			code = append(code, strings.TrimRight(line, " \t"))
+		case !indented(line):
+			inItem = false
+		}
+	}
+	return code
+}
+
+// audit renders text at width and checks three promises: every word
+// survives in order, code comes out byte for byte, and no prose line is
+// wider than width unless it is a single word that cannot fit anywhere.
+func audit(text string, width int) (tooWide, unbreakable int, err error) {
+	var out strings.Builder
+	Render(&out, text, width)
+	rendered := out.String()
+	if !slices.Equal(textwrap.Words(text), textwrap.Words(rendered)) {
+		return 0, 0, fmt.Errorf("words changed at width %d", width)
+	}
+	code := codeLines(text)
+	for _, line := range strings.Split(rendered, "\n") {
+		if i := slices.Index(code, line); i >= 0 && indented(line) {
+			code = slices.Delete(code, i, i+1)
+			continue
+		}
+		if textwrap.Width(line) <= width {
+			continue
+		}
+		if len(textwrap.Words(strings.TrimLeft(listMarker.ReplaceAllString(line, ""), " "))) == 1 {
+			unbreakable++
+		} else {
+			tooWide++
+		}
+	}
+	if len(code) > 0 {
+		return 0, 0, fmt.Errorf("code line changed: %q", code[0])
+	}
+	return tooWide, unbreakable, nil
+}
+
+// ---------------------------------------------------------
+// Part 4: Demo
+// ---------------------------------------------------------
+
+const sample = "Lesson prose is written at about 72 columns, with a line break at the end\n" +
+	"of each line. Reflowing ignores those breaks and picks new ones.\n" +
+	"\n" +
+	"RUN:\n" +
+	"    go run 87_directories.go -skip vendor -skip node_modules\n" +
+	"\n" +
+	"- Return `filepath.SkipDir` from the callback to skip a directory without\n" +
+	"  stopping the walk.\n" +
+	"- Sizes like 10 MB never split, and \x1b[1mbold text\x1b[0m is measured without\n" +
+	"  its escape codes.\n" +
+	"\n" +
+	"See https://pkg.go.dev/path/filepath#example-WalkDir for the full contract."
+
+func main() {
+	width := 0
+	var files []string
+	for i := 1; i < len(os.Args); i++ {
+		switch {
+		case os.Args[i] == "-width" && i+1 < len(os.Args):
+			width, _ = strconv.Atoi(os.Args[i+1])
+			i++
+		default:
+			files = append(files, os.Args[i])
+		}
+	}
+	width = terminalWidth(width)
+
+	// With file arguments this is a tool: reflow their headers and stop.
+	if len(files) > 0 {
+		for _, f := range files {
+			text, err := headerOf(f)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			Render(os.Stdout, text, width)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: TEXT WRAPPING — REFLOWING PROSE TO THE TERMINAL'S WIDTH")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Greedy Wrap ---")
+	para := "Reflowing means throwing the old line breaks away and choosing new ones for the width we actually have."
+	for _, w := range []int{40, 24} {
+		fmt.Printf("  Wrap(text, %d):\n", w)
+		ruler := strings.Repeat("·", w)
+		fmt.Println("    " + ruler)
+		for line := range strings.Lines(textwrap.Wrap(para, w) + "\n") {
+			fmt.Print("    " + line)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("--- Example 2: Hanging Indent ---")
+	item := "Return `filepath.SkipDir` from the callback to skip one directory without stopping the whole walk."
+	for line := range strings.Lines(textwrap.WrapIndent(item, 36, "  3. ", "     ") + "\n") {
+		fmt.Print(line)
+	}
+	fmt.Println()
+	fmt.Println("  Continuation lines start under \"Return\", not under \"3.\".")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: What Counts as One Word ---")
+	for _, s := range []string{"go\u00a0test", "`go test -run X`", "\x1b[1mbold\x1b[0m", "été"} {
+		fmt.Printf("  words=%d  width=%2d  runes=%2d  %q\n", len(textwrap.Words(s)), textwrap.Width(s), utf8.RuneCountInString(s), s)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Rendering Mixed Prose at 44 Columns ---")
+	fmt.Println("  " + strings.Repeat("·", 44))
+	var out strings.Builder
+	Render(&out, sample, 44)
+	for line := range strings.Lines(out.String()) {
+		fmt.Print("  " + line)
+	}
+	fmt.Println()
+	fmt.Println("  The code line and the URL are wider than 44 on purpose: code is")
+	fmt.Println("  verbatim, and a URL split in two no longer works when copied.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Every Lesson Header, Audited ---")
+	paths, _ := filepath.Glob("*.go")
+	for _, w := range []int{40, 60, width} {
+		lessons, lines, tooWide, unbreakable := 0, 0, 0, 0
+		var failed error
+		for _, p := range paths {
+			text, err := headerOf(p)
+			if err != nil {
+				continue
+			}
+			lessons++
+			lines += strings.Count(text, "\n")
+			tw, ub, err := audit(text, w)
+			if err != nil && failed == nil {
+				failed = fmt.Errorf("%s: %w", p, err)
+			}
+			tooWide += tw
+			unbreakable += ub
+		}
+		mark := "✓"
+		if failed != nil || tooWide > 0 {
+			mark = "✗"
+		}
+		fmt.Printf("  %s width %3d: %d headers (%d lines), %d prose lines too wide, %d overlong single words\n",
+			mark, w, lessons, lines, tooWide, unbreakable)
+		if failed != nil {
+			fmt.Println("    ", failed)
+		}
+	}
+	fmt.Printf("  Your width: %d (from -width, $COLUMNS, stty, or the default %d)\n", width, defaultWidth)
+	if os.Getenv("COLUMNS") == "" {
+		fmt.Println("  $COLUMNS is not exported here; try: COLUMNS=50 go run 167_text_wrap.go 166_report_exporter.go")
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Reflow = drop the old breaks, then fill lines greedily.
+2. Measure columns, not bytes: skip ANSI codes and combining marks.
+3. Break only at real spaces; U+00A0 and code spans hold together.
+4. Hanging indent: first prefix for the marker, spaces for the rest.
+5. Indented text is code — copy it, never reflow it.
+6. Width: flag, then $COLUMNS, then stty, then a sane default.
+	`)
+}
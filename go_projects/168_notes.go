@@ -0,0 +1,552 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/atomicfile"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/lazy"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/textwrap"
+)
+
+/*
+TOPIC: BOOKMARKS AND NOTES — A SMALL PERSISTENT STORE DONE RIGHT
+
+CONCEPT:
+Re-reading a lesson a week later, the thing you needed is the one-line
+insight you had the first time: "remember SkipDir returns from the
+callback". This lesson keeps those lines next to the topic and shows
+them again when the topic runs:
+
+    $ go run 168_notes.go note 87 "remember SkipDir returns from the callback"
+    $ go run 168_notes.go run 87
+    ...the lesson's own output...
+    ┌─ your notes for topic 87
+    │ 1. remember SkipDir returns from the callback     (16 Oct)
+    └─
+
+WHERE: the same config store as Topic 138's telemetry and the REPL
+history of Topic 160 — <os.UserConfigDir()>/gotut/notes.json. Set
+GOTUT_CONFIG_DIR to keep it somewhere else.
+
+THE FILE IS JSON, VERSIONED:
+    {"version": 1, "topics": {"87": {"bookmarked": true, "notes": [...]}}}
+"version" costs nothing today and lets a later release migrate the file
+instead of guessing what it is looking at.
+
+ATOMIC WRITES. Saving rewrites the whole file. Writing it in place
+means a crash or a full disk halfway through leaves HALF a JSON file —
+and every note gone. Instead:
+
+    1. write notes.json.tmp-XXXX in the SAME directory
+    2. Sync it (the bytes are on disk, not just in the page cache)
+    3. Rename it over notes.json — atomic on one filesystem
+
+A reader sees the old file or the new one, never a mix. Those steps are
+pkg/atomicfile, which Topic 138's queue and 197's checkpoints use too.
+A corrupt file is reported, never silently replaced with an empty one.
+
+RUN:
+    go run 168_notes.go                        (demo in a temp dir)
+    go run 168_notes.go note 165 "eighths of a block: ▏▎▍▌▋▊▉█"
+    go run 168_notes.go bookmark 165
+    go run 168_notes.go notes
+    go run 168_notes.go run 165
+    go run 168_notes.go note -rm 165 1
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Data
+// ---------------------------------------------------------
+
+const notebookVersion = 1
+
+type Note struct {
+	Text  string    `json:"text"`
+	Added time.Time `json:"added"`
+}
+
+type TopicNotes struct {
+	Bookmarked bool   `json:"bookmarked,omitempty"`
+	Notes      []Note `json:"notes,omitempty"`
+}
+
+// Notebook is the whole file. Topics are keyed by their number as a
+// string ("87"): JSON object keys are strings anyway.
+type Notebook struct {
+	Version int                    `json:"version"`
+	Topics  map[string]*TopicNotes `json:"topics"`
+}
+
+func (nb *Notebook) topic(id string) *TopicNotes {
+	if nb.Topics == nil {
+		nb.Topics = map[string]*TopicNotes{}
+	}
+	t := nb.Topics[id]
+	if t == nil {
+		t = &TopicNotes{}
+		nb.Topics[id] = t
+	}
+	return t
+}
+
+// prune drops topics with nothing left in them, so removing the last
+// note leaves no empty entry behind in the file.
+func (nb *Notebook) prune() {
+	maps.DeleteFunc(nb.Topics, func(_ string, t *TopicNotes) bool {
+		return !t.Bookmarked && len(t.Notes) == 0
+	})
+}
+
+// topicIDs returns topic numbers in numeric order: "9" before "87".
+func (nb *Notebook) topicIDs() []string {
+	return slices.SortedFunc(maps.Keys(nb.Topics), func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	})
+}
+
+// parseTopic accepts "87" or "087" and returns the canonical "87".
+func parseTopic(s string) (string, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return "", fmt.Errorf("topic must be a number, got %q", s)
+	}
+	return strconv.Itoa(n), nil
+}
+
+// ---------------------------------------------------------
+// Part 2: The Store — Load, Atomic Save, Update
+// ---------------------------------------------------------
+
+type Store struct {
+	Path string
+}
+
+// ErrCorrupt wraps a JSON error so callers can tell "your file is
+// damaged" from "the disk failed".
+var ErrCorrupt = errors.New("notes file is corrupt")
+
+// configDir is $GOTUT_CONFIG_DIR, else <config dir>/gotut. It is looked
+// up once, on first use (Topic 183), so every caller agrees on it.
+var configDir = lazy.OfErr(func() (string, error) {
+	if dir := os.Getenv("GOTUT_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gotut"), nil
+})
+
+// DefaultStore is notes.json in configDir, next to the other gotut
+// settings.
+func DefaultStore() (*Store, error) {
+	dir, err := configDir.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Path: filepath.Join(dir, "notes.json")}, nil
+}
+
+// Load returns an empty notebook if the file does not exist yet.
+func (s *Store) Load() (*Notebook, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Notebook{Version: notebookVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var nb Notebook
+	if err := json.Unmarshal(data, &nb); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, s.Path, err)
+	}
+	if nb.Version > notebookVersion {
+		return nil, fmt.Errorf("%s was written by a newer version (format %d, this reads %d)",
+			s.Path, nb.Version, notebookVersion)
+	}
+	nb.Version = notebookVersion
+	return &nb, nil
+}
+
+// Save writes the notebook atomically (pkg/atomicfile). Notes are
+// personal, hence 0600.
+func (s *Store) Save(nb *Notebook) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return err
+	}
+	return atomicfile.Write(s.Path, 0o600, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(nb)
+	})
+}
+
+// Update is load, change, save. Two terminals updating at the same
+// moment can still lose one change (last rename wins); for one person's
+// notes that is an acceptable trade for not needing a lock file.
+func (s *Store) Update(change func(*Notebook) error) error {
+	nb, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if err := change(nb); err != nil {
+		return err
+	}
+	nb.prune()
+	return s.Save(nb)
+}
+
+// ---------------------------------------------------------
+// Part 3: Showing Notes
+// ---------------------------------------------------------
+
+// showTopic prints one topic's notes in a box, the way `run` shows them
+// after a lesson. It prints nothing for a topic without notes.
+func showTopic(w io.Writer, id string, t *TopicNotes) {
+	if t == nil || (len(t.Notes) == 0 && !t.Bookmarked) {
+		return
+	}
+	title := "your notes for topic " + id
+	if t.Bookmarked {
+		title += "  ★ bookmarked"
+	}
+	fmt.Fprintln(w, "┌─ "+title)
+	for i, n := range t.Notes {
+		num := fmt.Sprintf("%d. ", i+1)
+		for j, line := range strings.Split(textwrap.Wrap(n.Text, 50), "\n") {
+			if j == 0 {
+				fmt.Fprintf(w, "│ %s%-50s (%s)\n", num, line, n.Added.Local().Format("2 Jan"))
+			} else {
+				fmt.Fprintf(w, "│ %s%s\n", strings.Repeat(" ", len(num)), line)
+			}
+		}
+	}
+	fmt.Fprintln(w, "└─")
+}
+
+// listAll prints every topic with notes or a bookmark, numerically.
+func listAll(w io.Writer, nb *Notebook) {
+	if len(nb.Topics) == 0 {
+		fmt.Fprintln(w, `no notes yet — add one with: note 87 "text"`)
+		return
+	}
+	for _, id := range nb.topicIDs() {
+		showTopic(w, id, nb.Topics[id])
+	}
+}
+
+// ---------------------------------------------------------
+// Part 4: The Commands
+// ---------------------------------------------------------
+
+const usage = `usage:
+  note TOPIC TEXT...     add a note
+  note -rm TOPIC N       remove note N
+  notes [TOPIC]          list notes
+  bookmark TOPIC         toggle a bookmark
+  run TOPIC              run the lesson, then show its notes`
+
+// courseDirs are where topics live, relative to go_projects/: 57–102
+// are in intermediate_topics, 103–128 in go_advanced_concepts.
+var courseDirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+// lessonPath finds the file or directory for a topic number:
+// 165 → 165_ascii_charts.go, 160 → 160_interp/. A .go file wins over a
+// directory of the same number (73 has both).
+func lessonPath(id string) (string, error) {
+	var matches []string
+	for _, dir := range courseDirs {
+		m, _ := filepath.Glob(filepath.Join(dir, id+"_*"))
+		matches = append(matches, m...)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no lesson %s in %s", id, strings.Join(courseDirs, ", "))
+	}
+	if i := slices.IndexFunc(matches, func(m string) bool { return strings.HasSuffix(m, ".go") }); i >= 0 {
+		return matches[i], nil
+	}
+	return matches[0], nil
+}
+
+// runnerFiles lists the files intermediate_examples.go is built from:
+// itself, and every package main file in dir without a main of its own.
+// The directory is no package: most of its topics are programs.
+func runnerFiles(dir string) ([]string, error) {
+	files := []string{"intermediate_examples.go"}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(src, []byte("package main\n")) && !bytes.Contains(src, []byte("\nfunc main()")) {
+			files = append(files, filepath.Base(p))
+		}
+	}
+	return files, nil
+}
+
+func runCommand(s *Store, args []string, stdout io.Writer) error {
+	cmd, args := args[0], args[1:]
+	switch {
+	case cmd == "note" && len(args) == 3 && args[0] == "-rm":
+		id, err := parseTopic(args[1])
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("note number must be a number, got %q", args[2])
+		}
+		return s.Update(func(nb *Notebook) error {
+			t := nb.topic(id)
+			if n < 1 || n > len(t.Notes) {
+				return fmt.Errorf("topic %s has %d note(s), no note %d", id, len(t.Notes), n)
+			}
+			t.Notes = slices.Delete(t.Notes, n-1, n)
+			return nil
+		})
+
+	case cmd == "note" && len(args) >= 2:
+		id, err := parseTopic(args[0])
+		if err != nil {
+			return err
+		}
+		text := strings.Join(args[1:], " ")
+		if strings.TrimSpace(text) == "" {
+			return errors.New("empty note")
+		}
+		var n int
+		err = s.Update(func(nb *Notebook) error {
+			t := nb.topic(id)
+			t.Notes = append(t.Notes, Note{Text: text, Added: time.Now().UTC().Truncate(time.Second)})
+			n = len(t.Notes)
+			return nil
+		})
+		if err == nil { // Only claim success once the file is on disk
+			fmt.Fprintf(stdout, "saved note %d for topic %s\n", n, id)
+		}
+		return err
+
+	case cmd == "notes" && len(args) <= 1:
+		nb, err := s.Load()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			listAll(stdout, nb)
+			return nil
+		}
+		id, err := parseTopic(args[0])
+		if err != nil {
+			return err
+		}
+		if nb.Topics[id] == nil {
+			fmt.Fprintf(stdout, "no notes for topic %s\n", id)
+		}
+		showTopic(stdout, id, nb.Topics[id])
+		return nil
+
+	case cmd == "bookmark" && len(args) == 1:
+		id, err := parseTopic(args[0])
+		if err != nil {
+			return err
+		}
+		var on bool
+		err = s.Update(func(nb *Notebook) error {
+			t := nb.topic(id)
+			t.Bookmarked = !t.Bookmarked
+			on = t.Bookmarked
+			return nil
+		})
+		if err == nil {
+			fmt.Fprintf(stdout, "topic %s bookmarked: %v\n", id, on)
+		}
+		return err
+
+	case cmd == "run" && len(args) == 1:
+		id, err := parseTopic(args[0])
+		if err != nil {
+			return err
+		}
+		path, err := lessonPath(id)
+		if err != nil {
+			return err
+		}
+		run := exec.Command("go", "run", path)
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			run = exec.Command("go", "run", ".") // Multi-file lessons run from inside
+			run.Dir = path
+		}
+		if src, err := os.ReadFile(path); err == nil && !bytes.Contains(src, []byte("\nfunc main()")) {
+			// Some intermediate topics only define DemoNN(); their main
+			// is intermediate_examples.go, asked for this topic alone.
+			files, err := runnerFiles(filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			run = exec.Command("go", append(append([]string{"run"}, files...), id)...)
+			run.Dir = filepath.Dir(path)
+		}
+		run.Stdin, run.Stdout, run.Stderr = os.Stdin, stdout, os.Stderr
+		runErr := run.Run()
+		// Notes are shown even if the lesson failed: that is often when
+		// they are needed most.
+		nb, err := s.Load()
+		if err != nil {
+			return errors.Join(runErr, err)
+		}
+		fmt.Fprintln(stdout)
+		showTopic(stdout, id, nb.Topics[id])
+		return runErr
+	}
+	return errors.New(usage)
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo in a Temp Directory
+// ---------------------------------------------------------
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "gotut_notes_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	s := &Store{Path: filepath.Join(dir, "gotut", "notes.json")}
+	run := func(args ...string) {
+		fmt.Printf("  $ %s\n", strings.Join(args, " "))
+		var out strings.Builder
+		err := runCommand(s, args, &out)
+		for line := range strings.Lines(out.String()) {
+			fmt.Print("    " + line)
+		}
+		if err != nil {
+			fmt.Println("    ✗", strings.ReplaceAll(err.Error(), "\n", "\n      "))
+		}
+	}
+
+	fmt.Println("--- Example 1: Taking Notes ---")
+	run("note", "87", "remember SkipDir returns from the callback")
+	run("note", "087", "WalkDir is cheaper than Walk: it doesn't Lstat every entry, the DirEntry already has the type")
+	run("note", "165", "eighths of a block: ▏▎▍▌▋▊▉█")
+	run("bookmark", "9")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Listing Them ---")
+	run("notes")
+	run("notes", "165")
+	run("notes", "40")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The File on Disk ---")
+	data, _ := os.ReadFile(s.Path)
+	fi, _ := os.Stat(s.Path)
+	fmt.Printf("  %s (mode %v)\n", strings.TrimPrefix(s.Path, dir+string(filepath.Separator)), fi.Mode().Perm())
+	for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if i < 12 {
+			fmt.Println("    " + line)
+		}
+	}
+	fmt.Println("    ...")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Re-running a Topic Shows Its Notes ---")
+	// The real command runs `go run 87_*.go`; here a stand-in prints
+	// the lesson's output so the demo doesn't depend on other files.
+	fmt.Println("  $ run 87")
+	fmt.Println("    ...Topic 87's own output...")
+	nb, _ := s.Load()
+	var box strings.Builder
+	showTopic(&box, "87", nb.Topics["87"])
+	for line := range strings.Lines(box.String()) {
+		fmt.Print("    " + line)
+	}
+	fmt.Println()
+	run("note", "-rm", "87", "5")
+	run("note", "-rm", "87", "1")
+	run("note", "-rm", "87", "1")
+	nb, _ = s.Load()
+	_, left := nb.Topics["87"]
+	fmt.Printf("  Topic 87 still in the file after removing its last note? %v\n", left)
+	fmt.Println()
+
+	fmt.Println("--- Example 5: A Failed Save Leaves the Old File Intact ---")
+	before, _ := os.ReadFile(s.Path)
+	// The write fails halfway, the way a full disk would.
+	saveErr := atomicfile.Write(s.Path, 0o600, func(w io.Writer) error {
+		w.Write(before[:len(before)/2])
+		return errors.New("no space left on device (simulated)")
+	})
+	after, _ := os.ReadFile(s.Path)
+	entries, _ := os.ReadDir(filepath.Dir(s.Path))
+	if saveErr != nil && string(before) == string(after) && len(entries) == 1 {
+		fmt.Println("  ✓ save failed:", saveErr)
+		fmt.Println("  ✓ notes.json is unchanged and no temp file was left behind")
+	} else {
+		fmt.Println("  ✗ the old file changed or a temp file leaked")
+	}
+
+	// A truncated file — what an in-place write leaves after a crash.
+	os.WriteFile(s.Path, before[:len(before)/2], 0o600)
+	_, err = s.Load()
+	fmt.Printf("  truncated file: errors.Is(err, ErrCorrupt) = %v\n", errors.Is(err, ErrCorrupt))
+	run("note", "87", "this must not overwrite the damaged file")
+	now, _ := os.ReadFile(s.Path)
+	fmt.Printf("  damaged file left for the user to inspect: %v\n", len(now) == len(before)/2)
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		s, err := DefaultStore()
+		if err == nil {
+			err = runCommand(s, os.Args[1:], os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: BOOKMARKS AND NOTES — A SMALL PERSISTENT STORE DONE RIGHT")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Keep user data under os.UserConfigDir(), with an env override.
+2. Version the file format from day one.
+3. Save atomically: temp file in the same dir, Sync, then Rename.
+4. On any failure, remove the temp file and keep the old one.
+5. Report a corrupt file; never "fix" it by writing an empty one.
+6. Load-change-save is enough for one user; shared data needs a lock.
+	`)
+}
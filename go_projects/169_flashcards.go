@@ -0,0 +1,538 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/lazy"
+)
+
+/*
+TOPIC: FLASHCARDS FROM REFERENCE TABLES — SPACED REPETITION
+
+CONCEPT:
+Many lessons end with a reference table: the fmt verbs of Topic 71, the
+filepath functions of 86, the magic layout numbers of 76. Reading the
+table once doesn't make it stick; being ASKED it a few days later does.
+This lesson turns each table into structured REFERENCE CARDS and drills
+them:
+
+    $ go run 169_flashcards.go cards 71
+    [71] verb for a value in Go syntax, e.g. []int{1, 2}
+    > %#v
+    ✓ correct — next review in 3 days
+
+SPACED REPETITION (the Leitner system). Every card sits in a box:
+
+    box      1    2    3    4     5
+    review  1d   3d   7d   14d   30d later
+
+Know the answer → the card moves up a box and comes back later.
+Miss it → it drops back to box 1 and comes back tomorrow. Cards you
+know drift out of the way; cards you don't keep coming back. A session
+only shows cards that are DUE, so five minutes a day is enough.
+
+GRADING. Answers like "%#v" or "filepath.Ext" are checked exactly. For
+anything else the card shows the answer and asks "did you know it?" —
+self-grading is honest enough, and it is what paper flashcards do.
+
+The tree has no scheduler or gotut CLI, so both are written here. The
+recall history lives next to the notes of Topic 168, in
+<config dir>/gotut/cards.json, saved with the same atomic write.
+
+RUN:
+    go run 169_flashcards.go               (demo: two simulated weeks)
+    go run 169_flashcards.go cards         (decks and how many are due)
+    go run 169_flashcards.go cards 71      (drill the due cards)
+    go run 169_flashcards.go cards 71 -all (drill every card now)
+*/
+
+// ---------------------------------------------------------
+// Part 1: Reference Cards, One Deck per Topic
+// ---------------------------------------------------------
+
+type Card struct {
+	Front string // The question
+	Back  string // The answer
+}
+
+// decks mirrors the reference tables at the end of the lessons. A
+// lesson registry would let each topic register its own; without one,
+// they are collected here, keyed by topic number.
+var decks = map[int][]Card{
+	71: {
+		{"verb for the default format of any value", "%v"},
+		{"verb that adds field names to a struct: {Name:Ann}", "%+v"},
+		{"verb for a value in Go syntax, e.g. []int{1, 2}", "%#v"},
+		{"verb for the TYPE of a value", "%T"},
+		{"verb for a double-quoted, escaped string", "%q"},
+		{"verb for lowercase hexadecimal", "%x"},
+		{"format a string left-aligned in 10 columns", "%-10s"},
+		{"format a float with 2 decimals in 8 columns", "%8.2f"},
+		{"format an int zero-padded to 5 digits", "%05d"},
+		{"print a literal percent sign", "%%"},
+	},
+	76: {
+		{"reference year in a time layout", "2006"},
+		{"reference month (number) in a time layout", "01"},
+		{"reference day in a time layout", "02"},
+		{"reference hour, 24-hour clock", "15"},
+		{"reference minute in a time layout", "04"},
+		{"reference second in a time layout", "05"},
+		{"layout constant for 2006-01-02T15:04:05Z07:00", "time.RFC3339"},
+		{"layout constant for just the date, 2006-01-02", "time.DateOnly"},
+	},
+	86: {
+		{"join path elements with the OS separator", "filepath.Join"},
+		{"last element of a path", "filepath.Base"},
+		{"everything but the last element of a path", "filepath.Dir"},
+		{"file extension, including the dot", "filepath.Ext"},
+		{"remove ./ and ../ and doubled separators", "filepath.Clean"},
+		{"make a path absolute", "filepath.Abs"},
+		{"path of target relative to base", "filepath.Rel"},
+		{"split into directory and file name", "filepath.Split"},
+		{"walk a tree, getting a DirEntry per file", "filepath.WalkDir"},
+		{"return from a WalkDir callback to skip a directory", "filepath.SkipDir"},
+	},
+}
+
+// cardID names a card in the history file. It uses the question text,
+// so reordering a deck doesn't mix up anyone's progress; rewording a
+// question starts that card fresh, which is usually right anyway.
+func cardID(topic int, c Card) string { return strconv.Itoa(topic) + ":" + c.Front }
+
+// ---------------------------------------------------------
+// Part 2: The Leitner Scheduler
+// ---------------------------------------------------------
+
+// intervals[b] is how long a card in box b+1 waits before its next
+// review. Roughly doubling gaps are the whole trick.
+var intervals = []time.Duration{1 * day, 3 * day, 7 * day, 14 * day, 30 * day}
+
+const day = 24 * time.Hour
+
+// Recall is everything remembered about one card.
+type Recall struct {
+	Box     int       `json:"box"` // 1..len(intervals)
+	Due     time.Time `json:"due"`
+	Reviews int       `json:"reviews"`
+	Lapses  int       `json:"lapses"` // Times it fell back to box 1
+}
+
+// Review applies one answer at time now and returns the new state.
+// A card never seen before starts in box 1 and is due immediately.
+func (r Recall) Review(correct bool, now time.Time) Recall {
+	if r.Box == 0 {
+		r.Box = 1
+	}
+	r.Reviews++
+	if correct {
+		r.Box = min(r.Box+1, len(intervals))
+	} else {
+		if r.Box > 1 {
+			r.Lapses++
+		}
+		r.Box = 1
+	}
+	// Due at the start of the day, so "tomorrow" means tomorrow
+	// morning, not exactly 24h after the last answer.
+	y, m, d := now.Date()
+	r.Due = time.Date(y, m, d, 0, 0, 0, 0, now.Location()).Add(intervals[r.Box-1])
+	return r
+}
+
+// IsDue: new cards (zero Due) and cards whose date has come.
+func (r Recall) IsDue(now time.Time) bool { return !now.Before(r.Due) }
+
+// History is the cards.json file: card ID → recall.
+type History struct {
+	Version int               `json:"version"`
+	Cards   map[string]Recall `json:"cards"`
+}
+
+// dueCards returns the cards of a topic that are due, lowest box first
+// (the shakiest cards get asked while attention is fresh), shuffled
+// within a box so the order itself can't be memorised.
+func dueCards(h *History, topic int, now time.Time, all bool, rng *rand.Rand) []Card {
+	var due []Card
+	for _, c := range decks[topic] {
+		if all || h.Cards[cardID(topic, c)].IsDue(now) {
+			due = append(due, c)
+		}
+	}
+	rng.Shuffle(len(due), func(i, j int) { due[i], due[j] = due[j], due[i] })
+	slices.SortStableFunc(due, func(a, b Card) int {
+		return h.Cards[cardID(topic, a)].Box - h.Cards[cardID(topic, b)].Box
+	})
+	return due
+}
+
+// ---------------------------------------------------------
+// Part 3: A Drill Session
+// ---------------------------------------------------------
+
+// matches grades a typed answer. It forgives surrounding space and a
+// trailing "()" on function names, and case in names — but not in fmt
+// verbs, where %x and %X are different verbs.
+func matches(answer, back string) bool {
+	answer = strings.TrimSuffix(strings.TrimSpace(answer), "()")
+	if strings.HasPrefix(back, "%") {
+		return answer == back
+	}
+	return strings.EqualFold(answer, back)
+}
+
+// exact reports whether an answer can be checked by comparison: short
+// and a single token, like "%#v" or "filepath.Ext".
+func exact(back string) bool { return !strings.ContainsRune(back, ' ') && len(back) <= 20 }
+
+type Session struct {
+	In    *bufio.Scanner
+	Out   io.Writer
+	Now   func() time.Time
+	Right int
+	Wrong int
+}
+
+// Drill asks each card once and records the result in h. An empty
+// answer or "?" means "show me"; EOF ends the session early with
+// everything answered so far kept.
+func (s *Session) Drill(h *History, topic int, cards []Card) {
+	for i, c := range cards {
+		fmt.Fprintf(s.Out, "[%d %d/%d] %s\n> ", topic, i+1, len(cards), c.Front)
+		if !s.In.Scan() {
+			fmt.Fprintln(s.Out)
+			return
+		}
+		answer := s.In.Text()
+		var correct bool
+		switch {
+		case exact(c.Back) && matches(answer, c.Back):
+			correct = true
+		case exact(c.Back) && answer != "" && answer != "?":
+			fmt.Fprintf(s.Out, "✗ it is %s\n", c.Back)
+		default:
+			fmt.Fprintf(s.Out, "  answer: %s — did you know it? [y/n] ", c.Back)
+			correct = s.In.Scan() && strings.HasPrefix(strings.ToLower(s.In.Text()), "y")
+			fmt.Fprintln(s.Out)
+		}
+		id := cardID(topic, c)
+		r := h.Cards[id].Review(correct, s.Now())
+		h.Cards[id] = r
+		if correct {
+			s.Right++
+			fmt.Fprintf(s.Out, "✓ box %d — next review %s\n", r.Box, until(s.Now(), r.Due))
+		} else {
+			s.Wrong++
+			fmt.Fprintln(s.Out, "  back to box 1 — you'll see it tomorrow")
+		}
+	}
+}
+
+// until describes a due date relative to now in whole days.
+func until(now, due time.Time) string {
+	y, m, d := now.Date()
+	days := int(due.Sub(time.Date(y, m, d, 0, 0, 0, 0, now.Location())) / day)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
+
+// ---------------------------------------------------------
+// Part 4: Saving the History (as in 168)
+// ---------------------------------------------------------
+
+// configDir is $GOTUT_CONFIG_DIR, else <config dir>/gotut, looked up
+// once on first use (Topic 183).
+var configDir = lazy.OfErr(func() (string, error) {
+	if dir := os.Getenv("GOTUT_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gotut"), nil
+})
+
+func historyPath() (string, error) {
+	dir, err := configDir.Get()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cards.json"), nil
+}
+
+func loadHistory(path string) (*History, error) {
+	h := &History{Version: 1, Cards: map[string]Recall{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %v", path, err)
+	}
+	if h.Cards == nil {
+		h.Cards = map[string]Recall{}
+	}
+	return h, nil
+}
+
+// saveHistory writes a temp file in the same directory, syncs it and
+// renames it over the old one; see 168 for why each step matters.
+func saveHistory(path string, h *History) (err error) {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "cards.json.tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ---------------------------------------------------------
+// Part 5: The "cards" Command
+// ---------------------------------------------------------
+
+// summary prints every deck with its due count and a box histogram.
+func summary(w io.Writer, h *History, now time.Time) {
+	fmt.Fprintln(w, "topic  cards  due   boxes 1 2 3 4 5")
+	for _, topic := range slices.Sorted(maps.Keys(decks)) {
+		var boxes [5]int
+		due := 0
+		for _, c := range decks[topic] {
+			r := h.Cards[cardID(topic, c)]
+			if r.IsDue(now) {
+				due++
+			}
+			if r.Box > 0 {
+				boxes[r.Box-1]++
+			}
+		}
+		fmt.Fprintf(w, "%5d  %5d  %3d        ", topic, len(decks[topic]), due)
+		for _, n := range boxes {
+			fmt.Fprintf(w, " %d", n)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func runCommand(args []string) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	h, err := loadHistory(path)
+	if err != nil {
+		return err
+	}
+	if args[0] != "cards" || len(args) > 3 {
+		return errors.New("usage: cards [TOPIC [-all]]")
+	}
+	if len(args) == 1 {
+		summary(os.Stdout, h, time.Now())
+		return nil
+	}
+	topic, err := strconv.Atoi(args[1])
+	if err != nil || decks[topic] == nil {
+		return fmt.Errorf("no reference cards for topic %q (have %v)", args[1], slices.Sorted(maps.Keys(decks)))
+	}
+	all := len(args) == 3 && args[2] == "-all"
+	cards := dueCards(h, topic, time.Now(), all, rand.New(rand.NewPCG(rand.Uint64(), 0)))
+	if len(cards) == 0 {
+		fmt.Println("nothing due — come back tomorrow, or use -all")
+		return nil
+	}
+	s := &Session{In: bufio.NewScanner(os.Stdin), Out: os.Stdout, Now: time.Now}
+	s.Drill(h, topic, cards)
+	fmt.Printf("\n%d right, %d to practise\n", s.Right, s.Wrong)
+	return saveHistory(path, h)
+}
+
+// ---------------------------------------------------------
+// Part 6: Demo — a Simulated Week
+// ---------------------------------------------------------
+
+func demo() error {
+	h := &History{Version: 1, Cards: map[string]Recall{}}
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC) // A Monday
+	rng := rand.New(rand.NewPCG(1, 2))                     // Fixed seed: same demo every run
+
+	// A learner who knows the common verbs but keeps mixing up %x and
+	// %q, and gets things right the second time round.
+	known := map[string]bool{"%v": true, "%+v": true, "%T": true, "%%": true, "%-10s": true}
+	seen := map[string]int{}
+	answer := func(c Card) string {
+		seen[c.Back]++
+		if known[c.Back] || seen[c.Back] > 1 {
+			return c.Back
+		}
+		return map[string]string{"%x": "%h", "%q": "%s"}[c.Back] // Wrong or blank
+	}
+
+	fmt.Println("--- Example 1: One Session, Typed Answers ---")
+	cards := dueCards(h, 71, start, false, rng)
+	var script strings.Builder
+	for _, c := range cards[:4] {
+		a := answer(c)
+		script.WriteString(a + "\n")
+		if a == "" {
+			script.WriteString("n\n") // Answers the "did you know it?" prompt
+		}
+	}
+	var out strings.Builder
+	s := &Session{In: bufio.NewScanner(strings.NewReader(script.String())), Out: &out, Now: func() time.Time { return start }}
+	s.Drill(h, 71, cards[:4])
+	for line := range strings.Lines(out.String()) {
+		fmt.Print("  " + line)
+	}
+	fmt.Printf("\n  Input ran out after 4 of %d cards: those 4 are saved, the rest stay due.\n\n", len(cards))
+
+	fmt.Println("--- Example 2: Two Weeks of Five-Minute Sessions ---")
+	fmt.Println("  day        asked  right  wrong   boxes 1 2 3 4 5")
+	for d := range 14 {
+		now := start.AddDate(0, 0, d)
+		due := dueCards(h, 71, now, false, rng)
+		var in strings.Builder
+		for _, c := range due {
+			a := answer(c)
+			in.WriteString(a + "\n")
+			if a == "" {
+				in.WriteString("n\n")
+			}
+		}
+		s := &Session{In: bufio.NewScanner(strings.NewReader(in.String())), Out: io.Discard, Now: func() time.Time { return now }}
+		s.Drill(h, 71, due)
+		var boxes [5]int
+		for _, c := range decks[71] {
+			if b := h.Cards[cardID(71, c)].Box; b > 0 {
+				boxes[b-1]++
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+		fmt.Printf("  %s   %5d  %5d  %5d         %d %d %d %d %d\n", now.Format("Mon 02 Jan"), len(due), s.Right, s.Wrong,
+			boxes[0], boxes[1], boxes[2], boxes[3], boxes[4])
+	}
+	fmt.Println("  Reviews spread out as cards climb the boxes; days with nothing due are skipped.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: One Card's History ---")
+	r, now := Recall{}, start
+	for _, ok := range []bool{true, true, false, true, true, true} {
+		r = r.Review(ok, now)
+		mark := "✓"
+		if !ok {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %s → box %d, due %s (%s)\n", now.Format("02 Jan"), mark, r.Box, r.Due.Format("02 Jan"), until(now, r.Due))
+		now = r.Due.Add(9 * time.Hour)
+	}
+	fmt.Printf("  reviews=%d lapses=%d\n\n", r.Reviews, r.Lapses)
+
+	fmt.Println("--- Example 4: Every Deck at a Glance ---")
+	out.Reset()
+	summary(&out, h, start.AddDate(0, 0, 14))
+	for line := range strings.Lines(out.String()) {
+		fmt.Print("  " + line)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Grading ---")
+	for _, tc := range []struct{ back, answer string }{
+		{"%#v", " %#v "}, {"filepath.Ext", "filepath.Ext()"}, {"time.RFC3339", "TIME.rfc3339"}, {"%x", "%X"}, {"time.RFC3339", "RFC3339"},
+	} {
+		fmt.Printf("  %-18q for %-13s → %v\n", tc.answer, tc.back, matches(tc.answer, tc.back))
+	}
+	fmt.Printf("  Names ignore case; verbs don't: %s (hex in CAPITALS) is a different verb.\n", "%X")
+
+	dir, err := os.MkdirTemp("", "gotut_cards_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cards.json")
+	if err := saveHistory(path, h); err != nil {
+		return err
+	}
+	back, err := loadHistory(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n  ✓ history round-trips through cards.json: %d cards, equal=%v\n",
+		len(back.Cards), maps.EqualFunc(h.Cards, back.Cards, func(a, b Recall) bool {
+			return a.Box == b.Box && a.Due.Equal(b.Due) && a.Reviews == b.Reviews && a.Lapses == b.Lapses
+		}))
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: FLASHCARDS FROM REFERENCE TABLES — SPACED REPETITION")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Reference tables become data: one deck of cards per topic.
+2. Leitner boxes: right moves a card up, wrong sends it to box 1.
+3. Growing gaps (1, 3, 7, 14, 30 days) keep daily sessions short.
+4. Ask only due cards, shakiest first, shuffled within a box.
+5. Check short answers exactly; let the learner grade the rest.
+6. Inject the clock: a week of reviews runs in milliseconds.
+	`)
+}
@@ -0,0 +1,916 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/course"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/deprecate"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/lazy"
+)
+
+/*
+TOPIC: SNIPPET EXTRACTION — ONE EXAMPLE AS ITS OWN PROGRAM
+
+CONCEPT:
+A lesson is a 500-line file; the example you want to poke at is 30 of
+those lines. This lesson cuts one example out into a standalone main.go
+in a temp workspace, ready for `go run .` and editing:
+
+    $ go run 170_snippets.go snippet 73 2
+    wrote /tmp/snippet-73-2-…/main.go (119 lines, helpers [], imports [fmt regexp strings])
+      cd /tmp/snippet-73-2-… && go run .
+
+WHAT IS "CODE BLOCK N"? The lessons use three shapes, and go/ast (Topic
+158) finds all of them:
+
+    func part2FindingPatterns()           73, 80: partN / sectionN
+    func Example4_WalkingDirectoryTrees() 86, 87: ExampleN_...
+    fmt.Println("--- Example 2: ... ---") go_projects: inline in main/demo
+
+WHAT GETS COPIED:
+- the block itself (a function, or the statements up to the next
+  "--- Example" marker);
+- every top-level type, func, var and const it uses, transitively, with
+  the methods of the types it uses;
+- for an inline block, the earlier statements in the same function that
+  declare a variable it uses (a temp dir made in Example 1 and used in
+  Example 3), plus any defer that cleans those up;
+- only the imports the result needs, worked out from the code.
+
+Text is copied by byte offset, not re-printed, so comments inside the
+example survive. The result is TYPE-CHECKED in-process (go/types) so a
+snippet that doesn't stand alone is reported instead of handed over.
+
+THE PLAYGROUND: go.dev/play has no self-contained URL format; a snippet
+is uploaded (POST to /_/share) and stored there under a short ID.
+Uploading publishes the code, so -share only happens when asked for.
+The demo uses a fake server.
+
+RUN:
+    go run 170_snippets.go
+    go run 170_snippets.go snippet 73           (list the blocks)
+    go run 170_snippets.go snippet 73 2         (extract block 2)
+    go run 170_snippets.go snippet 165 1 -share (upload to go.dev/play)
+*/
+
+// ---------------------------------------------------------
+// Part 1: Finding the Lesson File
+// ---------------------------------------------------------
+
+// courseDirs are where topics live, relative to go_projects/ (see 168).
+var courseDirs = course.Dirs()
+
+// pkgModule is the module of go_projects/pkg, the course's own packages.
+const pkgModule = "github.com/akarsh323/Go-tutorials-/go_projects/pkg"
+
+// lessonFile returns the single .go file for a topic.
+//
+// Deprecated: use course.File.
+func lessonFile(topic int) (string, error) {
+	deprecate.Warn("lessonFile")
+	return course.File(topic)
+}
+
+// ---------------------------------------------------------
+// Part 2: Finding the Blocks
+// ---------------------------------------------------------
+
+// Block is one extractable example.
+type Block struct {
+	N     int
+	Title string
+	Func  *ast.FuncDecl // A whole function (partN, ExampleN_...)
+	Host  *ast.FuncDecl // Or: the function holding an inline block
+	Stmts []ast.Stmt    // ...and its statements
+}
+
+var (
+	blockFunc   = regexp.MustCompile(`^(?i:example|part|section)(\d+)(?:_?([A-Za-z]\w*))?$`)
+	blockMarker = regexp.MustCompile(`^\s*--- Example (\d+): (.*?) ---`)
+
+	// Compiled on first use rather than at startup or on every call
+	// (Topic 183): most runs never split a title or meet a /vN import.
+	camelBreak   = lazy.Of(func() *regexp.Regexp { return regexp.MustCompile(`([a-z])([A-Z])`) })
+	majorVersion = lazy.Of(func() *regexp.Regexp { return regexp.MustCompile(`^v\d+$`) })
+)
+
+// markerOf reports whether stmt is fmt.Println("--- Example N: ... ---")
+// and returns N and the title.
+func markerOf(stmt ast.Stmt) (int, string, bool) {
+	call, ok := printlnArg(stmt)
+	if !ok {
+		return 0, "", false
+	}
+	m := blockMarker.FindStringSubmatch(call)
+	if m == nil {
+		return 0, "", false
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n, m[2], true
+}
+
+// printlnArg returns the string literal of a fmt.Println("...") or
+// fmt.Printf("...") statement.
+func printlnArg(stmt ast.Stmt) (string, bool) {
+	es, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return "", false
+	}
+	call, ok := es.X.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !slices.Contains([]string{"Println", "Printf"}, sel.Sel.Name) {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// endsBlock: the takeaways banner or a title line ends the last block.
+func endsBlock(stmt ast.Stmt) bool {
+	s, ok := printlnArg(stmt)
+	return ok && strings.Contains(s, "═══")
+}
+
+// Blocks lists the examples of a parsed lesson in order.
+func Blocks(f *ast.File) []Block {
+	var blocks []Block
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Body == nil {
+			continue
+		}
+		if m := blockFunc.FindStringSubmatch(fd.Name.Name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			blocks = append(blocks, Block{N: n, Title: splitCamel(m[2]), Func: fd})
+			continue
+		}
+		var cur *Block
+		for _, stmt := range fd.Body.List {
+			if n, title, ok := markerOf(stmt); ok {
+				if cur != nil {
+					blocks = append(blocks, *cur)
+				}
+				cur = &Block{N: n, Title: title, Host: fd}
+				continue // The marker line itself is not part of the code
+			}
+			if cur != nil && endsBlock(stmt) {
+				blocks = append(blocks, *cur)
+				cur = nil
+			}
+			if cur != nil {
+				cur.Stmts = append(cur.Stmts, stmt)
+			}
+		}
+		if cur != nil {
+			blocks = append(blocks, *cur)
+		}
+	}
+	slices.SortStableFunc(blocks, func(a, b Block) int { return a.N - b.N })
+	return blocks
+}
+
+// splitCamel turns "WalkingDirectoryTrees" into "Walking Directory Trees".
+func splitCamel(s string) string {
+	return strings.TrimSpace(camelBreak.Get().ReplaceAllString(s, "$1 $2"))
+}
+
+// ---------------------------------------------------------
+// Part 3: Pulling in What the Block Uses
+// ---------------------------------------------------------
+
+// identsIn collects every identifier name under the nodes. Shadowed
+// names can pull in a declaration that isn't needed; that costs a few
+// extra lines, never a broken snippet.
+func identsIn(nodes ...ast.Node) map[string]bool {
+	names := map[string]bool{}
+	for _, n := range nodes {
+		ast.Inspect(n, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				names[id.Name] = true
+			}
+			return true
+		})
+	}
+	return names
+}
+
+// recvName returns T for methods on T or *T (and generic T[K]).
+func recvName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return ""
+	}
+	t := fd.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.IndexExpr:
+		return t.X.(*ast.Ident).Name
+	case *ast.IndexListExpr:
+		return t.X.(*ast.Ident).Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// declNames returns the top-level names a declaration introduces.
+func declNames(d ast.Decl) []string {
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil {
+			return []string{d.Name.Name}
+		}
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					names = append(names, n.Name)
+				}
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// dependencies returns the top-level decls reachable from roots, in
+// source order. Methods come along with their receiver type.
+func dependencies(f *ast.File, roots []ast.Node, skip *ast.FuncDecl) []ast.Decl {
+	byName := map[string][]ast.Decl{}
+	for _, d := range f.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			continue
+		}
+		if fd, ok := d.(*ast.FuncDecl); ok && fd == skip {
+			continue // The host function (often main) is replaced
+		}
+		for _, n := range declNames(d) {
+			byName[n] = append(byName[n], d)
+		}
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv != nil {
+			byName[recvName(fd)] = append(byName[recvName(fd)], d)
+		}
+	}
+	keep := map[ast.Decl]bool{}
+	queue := slices.Collect(func(yield func(string) bool) {
+		for n := range identsIn(roots...) {
+			if !yield(n) {
+				return
+			}
+		}
+	})
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		for _, d := range byName[name] {
+			if !keep[d] {
+				keep[d] = true
+				for n := range identsIn(d) {
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(f.Decls), func(d ast.Decl) bool { return !keep[d] })
+}
+
+// prelude returns the statements before the block in the same function
+// that declare a name the block (or another prelude statement) uses,
+// plus defers that mention those names — cleanup comes along with the
+// thing it cleans up.
+func prelude(b Block) []ast.Stmt {
+	var earlier []ast.Stmt
+	for _, stmt := range b.Host.Body.List {
+		if stmt == b.Stmts[0] {
+			break
+		}
+		earlier = append(earlier, stmt)
+	}
+	need := identsIn(stmtNodes(b.Stmts)...)
+	keep := map[ast.Stmt]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, stmt := range slices.Backward(earlier) {
+			if keep[stmt] || !declaresAny(stmt, need) {
+				continue
+			}
+			keep[stmt] = true
+			changed = true
+			for n := range identsIn(stmt) {
+				need[n] = true
+			}
+		}
+	}
+	var out []ast.Stmt
+	for _, stmt := range earlier {
+		_, isDefer := stmt.(*ast.DeferStmt)
+		if keep[stmt] || (isDefer && usesDeclared(stmt, earlier, keep)) {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
+
+func stmtNodes(stmts []ast.Stmt) []ast.Node {
+	nodes := make([]ast.Node, len(stmts))
+	for i, s := range stmts {
+		nodes[i] = s
+	}
+	return nodes
+}
+
+// declaredBy lists the names a := or var statement introduces.
+func declaredBy(stmt ast.Stmt) []string {
+	var names []string
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok == token.DEFINE {
+			for _, l := range s.Lhs {
+				if id, ok := l.(*ast.Ident); ok && id.Name != "_" {
+					names = append(names, id.Name)
+				}
+			}
+		}
+	case *ast.DeclStmt:
+		names = declNames(s.Decl.(*ast.GenDecl))
+	}
+	return names
+}
+
+func declaresAny(stmt ast.Stmt, need map[string]bool) bool {
+	return slices.ContainsFunc(declaredBy(stmt), func(n string) bool { return need[n] })
+}
+
+func usesDeclared(stmt ast.Stmt, earlier []ast.Stmt, keep map[ast.Stmt]bool) bool {
+	used := identsIn(stmt)
+	for _, e := range earlier {
+		if keep[e] && slices.ContainsFunc(declaredBy(e), func(n string) bool { return used[n] }) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------
+// Part 4: Writing main.go
+// ---------------------------------------------------------
+
+// Snippet is the extracted program and what went into it.
+type Snippet struct {
+	Source  []byte
+	Helpers []string // Top-level names copied besides the block
+	Imports []string
+	Local   map[string]string // Import path in Source → the package dir it is copied from
+}
+
+// Extract builds a standalone program for block b of file f (whose
+// source is src).
+func Extract(fset *token.FileSet, f *ast.File, src []byte, b Block) (*Snippet, error) {
+	text := func(n ast.Node) string {
+		start := n.Pos()
+		switch n := n.(type) { // Keep doc comments with their decl
+		case *ast.FuncDecl:
+			if n.Doc != nil {
+				start = n.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if n.Doc != nil {
+				start = n.Doc.Pos()
+			}
+		}
+		return string(src[fset.Position(start).Offset:fset.Position(n.End()).Offset])
+	}
+
+	var roots []ast.Node
+	var body strings.Builder
+	if b.Func != nil {
+		roots = []ast.Node{b.Func}
+		fmt.Fprintf(&body, "func main() {\n%s()\n}\n\n", b.Func.Name.Name)
+	} else {
+		if len(b.Stmts) == 0 {
+			return nil, fmt.Errorf("block %d is empty", b.N)
+		}
+		pre := prelude(b)
+		stmts := append(pre, b.Stmts...)
+		roots = stmtNodes(stmts)
+		// A block from a func returning error (demo() error) may say
+		// "return err"; it becomes run() error, called from main.
+		results := b.Host.Type.Results
+		returnsErr := results != nil && len(results.List) == 1 && len(results.List[0].Names) <= 1 &&
+			types.ExprString(results.List[0].Type) == "error"
+		switch {
+		case returnsErr:
+			body.WriteString("func main() {\nif err := run(); err != nil {\npanic(err)\n}\n}\n\nfunc run() error {\n")
+		case results != nil:
+			return nil, fmt.Errorf("block %d is inside %s, which returns values", b.N, b.Host.Name.Name)
+		default:
+			body.WriteString("func main() {\n")
+		}
+		for _, s := range stmts {
+			body.WriteString(text(s) + "\n")
+		}
+		if _, ok := b.Stmts[len(b.Stmts)-1].(*ast.ReturnStmt); returnsErr && !ok {
+			body.WriteString("return nil\n")
+		}
+		body.WriteString("}\n\n")
+	}
+
+	s := &Snippet{}
+	for _, d := range dependencies(f, roots, b.Host) {
+		body.WriteString(text(d) + "\n\n")
+		if fd, ok := d.(*ast.FuncDecl); ok && fd == b.Func {
+			continue
+		}
+		s.Helpers = append(s.Helpers, declNames(d)...)
+	}
+
+	// Keep the imports whose package name the body uses as pkg.X.
+	used := map[string]bool{}
+	probe, err := parser.ParseFile(token.NewFileSet(), "", "package main\n"+body.String(), parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %v", b.N, err)
+	}
+	ast.Inspect(probe, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	var imports strings.Builder
+	for _, spec := range f.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(p)
+		if majorVersion.Get().MatchString(name) {
+			name = path.Base(path.Dir(p)) // math/rand/v2 is package rand
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !used[name] {
+			continue
+		}
+		s.Imports = append(s.Imports, p)
+		rest, ok := strings.CutPrefix(p, pkgModule+"/")
+		if !ok {
+			imports.WriteString(text(spec) + "\n")
+			continue
+		}
+		// A course package (pkg/lazy, pkg/splitters) is found through the
+		// repository's go.work, which a snippet outside it doesn't have;
+		// it goes into the workspace's module with the snippet.
+		local := "snippet/pkg/" + rest
+		if s.Local == nil {
+			s.Local = map[string]string{}
+		}
+		s.Local[local] = filepath.Join("pkg", filepath.FromSlash(rest))
+		if spec.Name != nil {
+			imports.WriteString(spec.Name.Name + " ")
+		}
+		imports.WriteString(strconv.Quote(local) + "\n")
+	}
+
+	var out bytes.Buffer
+	name := filepath.Base(fset.File(f.Pos()).Name())
+	fmt.Fprintf(&out, "// Code extracted from %s, block %d (%s).\n", name, b.N, b.Title)
+	fmt.Fprintf(&out, "// Edit freely: this is a copy, not the lesson.\n\npackage main\n\nimport (\n%s)\n\n%s", imports.String(), body.String())
+	s.Source, err = format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %v", b.N, err)
+	}
+	s.Source = silenceUnused(s.Source, s.Local)
+	return s, nil
+}
+
+// silenceUnused lets the type checker drive one repair. The prelude can
+// declare more than the block reads — "dir, err := os.MkdirTemp(...)"
+// without the "if err != nil" that followed it, or a variable a later
+// example shadows — and Go rejects unused variables. For each
+// "declared and not used: x" error, "_ = x" goes after the statement
+// that declared it; then check again.
+func silenceUnused(src []byte, local map[string]string) []byte {
+	for range 20 { // Each pass fixes at least one; 20 is plenty
+		var fix []int // Offsets at which to insert, with names
+		var names []string
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "main.go", src, 0)
+		if err != nil {
+			return src
+		}
+		for _, terr := range typeErrors(fset, f, local) {
+			name, ok := strings.CutPrefix(terr.Msg, "declared and not used: ")
+			if !ok {
+				continue
+			}
+			if end := enclosingStmtEnd(f, terr.Pos); end.IsValid() {
+				fix = append(fix, fset.Position(end).Offset)
+				names = append(names, name)
+			}
+		}
+		if len(fix) == 0 {
+			return src
+		}
+		for i := len(fix) - 1; i >= 0; i-- { // Back to front keeps offsets valid
+			src = slices.Insert(src, fix[i], []byte("\n_ = "+names[i])...)
+		}
+		if formatted, err := format.Source(src); err == nil {
+			src = formatted
+		}
+	}
+	return src
+}
+
+// enclosingStmtEnd finds the innermost statement in a block that
+// contains pos and returns where it ends.
+func enclosingStmtEnd(f *ast.File, pos token.Pos) token.Pos {
+	var end token.Pos
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil || pos < n.Pos() || pos >= n.End() {
+			return n == nil
+		}
+		if b, ok := n.(*ast.BlockStmt); ok {
+			for _, s := range b.List {
+				if s.Pos() <= pos && pos < s.End() {
+					end = s.End()
+				}
+			}
+		}
+		return true
+	})
+	return end
+}
+
+// imp is shared by every check so each std package is loaded once.
+var imp = importer.Default()
+
+// srcImp type-checks a lesson's local packages from their source. It is
+// made on first use: most snippets have none.
+var srcImp = lazy.Of(func() types.ImporterFrom {
+	return importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+})
+
+// localImporter resolves a snippet's local packages with srcImp. The
+// standard library comes from srcImp too: a local package's bufio must
+// be the snippet's bufio, or a bufio.SplitFunc it exports won't fit
+// sc.Split.
+type localImporter map[string]string
+
+func (l localImporter) Import(p string) (*types.Package, error) {
+	if dir, ok := l[p]; ok {
+		// srcImp caches by the path it is given, so import "./splitters"
+		// from pkg/, not "." from pkg/splitters: every local package
+		// would be ".".
+		return srcImp.Get().ImportFrom("./"+filepath.Base(dir), filepath.Dir(dir), 0)
+	}
+	return srcImp.Get().Import(p)
+}
+
+// Check type-checks a snippet the way the compiler would, without
+// writing it anywhere or running the go command.
+func Check(s *Snippet) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", s.Source, 0)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, terr := range typeErrors(fset, f, s.Local) {
+		errs = append(errs, terr)
+	}
+	return errors.Join(errs...)
+}
+
+func typeErrors(fset *token.FileSet, f *ast.File, local map[string]string) []types.Error {
+	var errs []types.Error
+	imp := types.Importer(imp)
+	if len(local) > 0 {
+		imp = localImporter(local)
+	}
+	conf := types.Config{Importer: imp, Error: func(err error) { errs = append(errs, err.(types.Error)) }}
+	conf.Check("main", fset, []*ast.File{f}, nil)
+	return errs
+}
+
+// Workspace writes main.go and a go.mod into a new temp directory, with
+// a copy of each local package the snippet imports.
+func Workspace(topic, n int, s *Snippet) (string, error) {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("snippet-%d-%d-", topic, n))
+	if err != nil {
+		return "", err
+	}
+	mod := "module snippet\n\ngo 1.22\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+		return "", err
+	}
+	for local, from := range s.Local {
+		files, err := filepath.Glob(filepath.Join(from, "*.go"))
+		if err != nil {
+			return "", err
+		}
+		to := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(local, "snippet/")))
+		if err := os.MkdirAll(to, 0o755); err != nil {
+			return "", err
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			data, err := os.ReadFile(file)
+			if err == nil {
+				err = os.WriteFile(filepath.Join(to, filepath.Base(file)), data, 0o644)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return dir, os.WriteFile(filepath.Join(dir, "main.go"), s.Source, 0o644)
+}
+
+// ---------------------------------------------------------
+// Part 5: Sharing on the Go Playground
+// ---------------------------------------------------------
+
+const playground = "https://go.dev"
+
+// Share uploads src and returns its go.dev/play URL. The share endpoint
+// takes the raw source as the body and answers with a short ID.
+func Share(client *http.Client, base string, src []byte) (string, error) {
+	resp, err := client.Post(base+"/_/share", "text/plain; charset=utf-8", bytes.NewReader(src))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	id, err := io.ReadAll(io.LimitReader(resp.Body, 128))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("playground: %s", resp.Status)
+	}
+	return playground + "/play/p/" + strings.TrimSpace(string(id)), nil
+}
+
+// ---------------------------------------------------------
+// Part 6: The "snippet" Command
+// ---------------------------------------------------------
+
+func load(topic int) (*token.FileSet, *ast.File, []byte, error) {
+	path, err := lessonFile(topic)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	return fset, f, src, err
+}
+
+func runCommand(args []string) error {
+	if len(args) < 2 || args[0] != "snippet" {
+		return errors.New("usage: snippet TOPIC [N [-share]]")
+	}
+	topic, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("topic must be a number, got %q", args[1])
+	}
+	fset, f, src, err := load(topic)
+	if err != nil {
+		return err
+	}
+	blocks := Blocks(f)
+	if len(args) == 2 {
+		for _, b := range blocks {
+			fmt.Printf("%3d  %s\n", b.N, b.Title)
+		}
+		return nil
+	}
+	n, _ := strconv.Atoi(args[2])
+	i := slices.IndexFunc(blocks, func(b Block) bool { return b.N == n })
+	if i < 0 {
+		return fmt.Errorf("topic %d has no block %q (run: snippet %d)", topic, args[2], topic)
+	}
+	s, err := Extract(fset, f, src, blocks[i])
+	if err != nil {
+		return err
+	}
+	if err := Check(s); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: the snippet does not compile on its own:\n%v\n", err)
+	}
+	dir, err := Workspace(topic, n, s)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s (%d lines, helpers %v, imports %v)\n", filepath.Join(dir, "main.go"),
+		bytes.Count(s.Source, []byte("\n")), s.Helpers, s.Imports)
+	fmt.Printf("  cd %s && go run .\n", dir)
+	if slices.Contains(args[3:], "-share") {
+		if len(s.Local) > 0 {
+			return fmt.Errorf("not shared: the snippet needs %v, and a share is one file", slices.Sorted(maps.Keys(s.Local)))
+		}
+		url, err := Share(&http.Client{Timeout: 10 * time.Second}, playground, s.Source)
+		if err != nil {
+			return err
+		}
+		fmt.Println("shared (public):", url)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 7: Demo
+// ---------------------------------------------------------
+
+func demo() error {
+	fmt.Println("--- Example 1: The Blocks of Three Lesson Shapes ---")
+	for _, topic := range []int{73, 87, 165} {
+		_, f, _, err := load(topic)
+		if err != nil {
+			fmt.Printf("  %d: %v\n", topic, err)
+			continue
+		}
+		var titles []string
+		for _, b := range Blocks(f) {
+			titles = append(titles, fmt.Sprintf("%d %s", b.N, b.Title))
+		}
+		fmt.Printf("  %3d: %s\n", topic, strings.Join(titles, " · "))
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: An Inline Block With Its Helpers ---")
+	fset, f, src, err := load(165)
+	if err != nil {
+		return err
+	}
+	blocks := Blocks(f)
+	s, err := Extract(fset, f, src, blocks[1])
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(s.Source), "\n")
+	for _, line := range lines[:min(len(lines), 22)] {
+		fmt.Println("  │ " + line)
+	}
+	fmt.Printf("  │ ... %d lines in all\n", len(lines))
+	fmt.Printf("  helpers: %v\n  imports: %v\n", s.Helpers, s.Imports)
+	if err := Check(s); err != nil {
+		fmt.Println("  ✗", err)
+	} else {
+		fmt.Println("  ✓ type-checks on its own")
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Running It in a Temp Workspace ---")
+	dir, err := Workspace(165, blocks[1].N, s)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Printf("  ✗ go run: %v\n%s", err, out)
+	} else {
+		fmt.Printf("  $ cd %s && go run .\n", filepath.Base(dir))
+		for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n")[:min(6, strings.Count(string(out), "\n"))] {
+			fmt.Println("    " + line)
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Every Block in the Course ---")
+	start := time.Now()
+	total, ok := 0, 0
+	var failures []string
+	for _, dir := range courseDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "[0-9]*.go"))
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+			if err != nil {
+				continue
+			}
+			for _, b := range Blocks(f) {
+				total++
+				s, err := Extract(fset, f, src, b)
+				if err == nil {
+					err = Check(s)
+				}
+				if err != nil {
+					first, _, _ := strings.Cut(err.Error(), "\n")
+					failures = append(failures, fmt.Sprintf("%s #%d: %s", filepath.Base(file), b.N, first))
+					continue
+				}
+				ok++
+			}
+		}
+	}
+	fmt.Printf("  ✓ %d of %d blocks stand alone (type-checked in %v)\n", ok, total, time.Since(start).Round(time.Millisecond))
+	for _, fail := range failures[:min(len(failures), 4)] {
+		fmt.Println("  ✗", fail)
+	}
+	if len(failures) > 4 {
+		fmt.Printf("    ... and %d more\n", len(failures)-4)
+	}
+	fmt.Println("  A block that leans on state set up some other way than a plain")
+	fmt.Println("  declaration (a global changed in an earlier example) can still fail;")
+	fmt.Println("  `snippet` prints the type errors instead of a broken workspace.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Sharing (Against a Fake Playground) ---")
+	var got []byte
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/_/share" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "Xq3fZ1tNwLe")
+	}))
+	defer fake.Close()
+	url, err := Share(fake.Client(), fake.URL, s.Source)
+	fmt.Printf("  POST /_/share with %d bytes of source → %s (err=%v)\n", len(got), url, err)
+	fmt.Println("  The real -share uploads to go.dev: anyone with the link can read it.")
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: SNIPPET EXTRACTION — ONE EXAMPLE AS ITS OWN PROGRAM")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Find blocks with go/ast, not regexes over source.
+2. Copy what the block uses, transitively: decls, methods, imports.
+3. Copy text by byte offset so comments inside the code survive.
+4. go/types checks the result in-process before anyone runs it.
+5. Sharing to the Playground publishes code — make it opt-in.
+	`)
+}
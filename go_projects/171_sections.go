@@ -0,0 +1,889 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/course"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/deprecate"
+)
+
+/*
+TOPIC: PER-SECTION EXECUTION — RUNNING ONE PART OF A TOPIC
+
+CONCEPT:
+Every lesson runs top to bottom. To look at the avalanche effect in
+Topic 82 again you sit through five other examples first, and a menu or
+web UI can only offer "run all of it". The fix is a SECTION REGISTRY:
+the lesson lists its parts as data, and the runner picks one.
+
+    var sections = []section{
+        {"basic-sha256-hashing", basicSHA256Hashing},
+        {"avalanche-effect",     avalancheEffect},
+        ...
+    }
+
+    $ go run 82_sha_detailed.go -section avalanche
+
+NAMES come from the function names — basicSHA256Hashing becomes
+basic-sha256-hashing — and a query matches a whole name, a unique
+prefix ("avalanche"), or a position ("4"). An ambiguous query lists
+the candidates instead of guessing.
+
+THE REFACTOR. Lessons come in two shapes, and only one can be converted
+by a machine:
+
+    FUNCTION-SHAPED   main() { banner; partOne(); partTwo(); ... }
+                      Each call is already a section. sectionize (Part 4)
+                      rewrites main into the registry, type-checking the
+                      file before and after (as 170 does for snippets).
+    INLINE            main() { ...Example 1...; ...Example 2... }
+                      Examples share variables; cutting them into funcs
+                      needs a person. Until then the runner runs the
+                      whole lesson and SHOWS only the asked-for section,
+                      using the "--- Example N: ... ---" markers.
+
+A converted lesson imports pkg/section for the matching and the -section
+flag, so the rewrite itself is only the table and one call in main. This
+file audits the whole course and converts one file at a time with -w;
+the diff is small enough to review by hand, which is the point. Every
+function-shaped lesson was converted this way, with its output checked
+unchanged.
+
+RUN:
+    go run 171_sections.go                             (demo + audit)
+    go run 171_sections.go sections 165                (list; -json for a UI)
+    go run 171_sections.go run 165 -section sparklines
+    go run 171_sections.go sectionize FILE.go          (print the diff)
+    go run 171_sections.go sectionize -w FILE.go       (rewrite the file)
+*/
+
+// ---------------------------------------------------------
+// Part 1: Section Names and Matching
+// ---------------------------------------------------------
+
+var (
+	camelBreak   = regexp.MustCompile(`([a-z0-9])([A-Z])|([A-Z])([A-Z][a-z])`)
+	nonWord      = regexp.MustCompile(`[^a-z0-9]+`)
+	numberedPart = regexp.MustCompile(`^(?i:example|part|section)\d+_?`)
+)
+
+// Slug turns a function name or a title into a section name:
+// basicSHA256Hashing → basic-sha256-hashing, part2FindingPatterns →
+// finding-patterns, "Status Codes From an Access Log" → status-codes-
+// from-an-access-log.
+func Slug(s string) string {
+	s = numberedPart.ReplaceAllString(s, "")
+	s = camelBreak.ReplaceAllString(s, "$1$3-$2$4")
+	return strings.Trim(nonWord.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// ErrNoSection and ErrAmbiguous let callers print the right help.
+var (
+	ErrNoSection = errors.New("no such section")
+	ErrAmbiguous = errors.New("ambiguous section")
+)
+
+// Match finds the index of the section a query means: an exact name,
+// a 1-based position, a unique prefix, or a unique substring — tried in
+// that order, so "md5" never loses to "md5-vs-sha256".
+func Match(names []string, query string) (int, error) {
+	q := Slug(query)
+	if i := slices.Index(names, q); i >= 0 {
+		return i, nil
+	}
+	if n, err := strconv.Atoi(query); err == nil && n >= 1 && n <= len(names) {
+		return n - 1, nil
+	}
+	for _, pick := range []func(string) bool{
+		func(name string) bool { return strings.HasPrefix(name, q) },
+		func(name string) bool { return strings.Contains(name, q) },
+	} {
+		var hits []int
+		for i, name := range names {
+			if q != "" && pick(name) {
+				hits = append(hits, i)
+			}
+		}
+		switch len(hits) {
+		case 0:
+			continue
+		case 1:
+			return hits[0], nil
+		}
+		var cands []string
+		for _, i := range hits {
+			cands = append(cands, names[i])
+		}
+		return -1, fmt.Errorf("%w %q: could be %s", ErrAmbiguous, query, strings.Join(cands, ", "))
+	}
+	return -1, fmt.Errorf("%w %q (have: %s)", ErrNoSection, query, strings.Join(names, ", "))
+}
+
+// ---------------------------------------------------------
+// Part 2: A Registry Inside One Program
+// ---------------------------------------------------------
+// This is what a converted lesson looks like from the inside — here as
+// a three-part hashing "lesson", so the demo doesn't need other files.
+
+type Section struct {
+	Name  string            `json:"name"`
+	Title string            `json:"title"`
+	Run   func(w io.Writer) `json:"-"`
+}
+
+var hashingLesson = []Section{
+	{Title: "MD5 Is Fast and Broken", Run: func(w io.Writer) {
+		fmt.Fprintf(w, "md5(\"gopher\")    = %x\n", md5.Sum([]byte("gopher")))
+	}},
+	{Title: "SHA-256 for Integrity", Run: func(w io.Writer) {
+		fmt.Fprintf(w, "sha256(\"gopher\") = %x…\n", sha256.Sum256([]byte("gopher")))
+	}},
+	{Title: "Avalanche Effect", Run: func(w io.Writer) {
+		a, b := sha256.Sum256([]byte("gopher")), sha256.Sum256([]byte("gophes"))
+		diff := 0
+		for i := range a {
+			for x := a[i] ^ b[i]; x != 0; x &= x - 1 {
+				diff++
+			}
+		}
+		fmt.Fprintf(w, "one letter changed → %d of 256 bits flipped\n", diff)
+	}},
+}
+
+func init() {
+	for i := range hashingLesson {
+		hashingLesson[i].Name = Slug(hashingLesson[i].Title)
+	}
+}
+
+// RunSections runs the section query picks, or all of them if query is
+// empty, and returns how long each took — what a menu or web UI needs
+// to show a progress list.
+func RunSections(w io.Writer, sections []Section, query string) (map[string]time.Duration, error) {
+	pick := sections
+	if query != "" {
+		names := make([]string, len(sections))
+		for i, s := range sections {
+			names[i] = s.Name
+		}
+		i, err := Match(names, query)
+		if err != nil {
+			return nil, err
+		}
+		pick = sections[i : i+1]
+	}
+	took := map[string]time.Duration{}
+	for _, s := range pick {
+		fmt.Fprintf(w, "--- %s ---\n", s.Title)
+		start := time.Now()
+		s.Run(w)
+		took[s.Name] = time.Since(start)
+	}
+	return took, nil
+}
+
+// ---------------------------------------------------------
+// Part 3: Sections of the Lessons on Disk
+// ---------------------------------------------------------
+
+// courseDirs are where topics live, relative to go_projects/ (see 168).
+var courseDirs = course.Dirs()
+
+// lessonFile returns the single .go file for a topic.
+//
+// Deprecated: use course.File.
+func lessonFile(topic int) (string, error) {
+	deprecate.Warn("lessonFile")
+	return course.File(topic)
+}
+
+// Shape is how a lesson's main is built.
+type Shape struct {
+	Host      *ast.FuncDecl
+	Calls     []*ast.ExprStmt // FUNCTION-SHAPED: one call per section
+	Markers   []string        // INLINE: the "--- Example N: T ---" titles
+	Converted bool            // Already has "var sections"
+	Reason    string          // Why it can't be converted, if it can't
+}
+
+var marker = regexp.MustCompile(`^\s*--- Example (\d+): (.*?) ---`)
+
+// niladicFuncs are the top-level funcs that take and return nothing:
+// the only calls that can become a section as they are.
+func niladicFuncs(f *ast.File) map[string]bool {
+	funcs := map[string]bool{}
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Type.Params.NumFields() == 0 &&
+			fd.Type.Results.NumFields() == 0 && fd.Name.Name != "main" && fd.Name.Name != "init" {
+			funcs[fd.Name.Name] = true
+		}
+	}
+	return funcs
+}
+
+// sectionCall returns the function name if stmt is a bare call f().
+func sectionCall(stmt ast.Stmt, funcs map[string]bool) (string, bool) {
+	es, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return "", false
+	}
+	call, ok := es.X.(*ast.CallExpr)
+	if !ok || len(call.Args) > 0 {
+		return "", false
+	}
+	id, ok := call.Fun.(*ast.Ident)
+	if !ok || !funcs[id.Name] {
+		return "", false
+	}
+	return id.Name, true
+}
+
+// isPrint reports whether stmt is a fmt.Print* call (banners, blanks).
+func isPrint(stmt ast.Stmt) bool {
+	es, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := es.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "fmt" && strings.HasPrefix(sel.Sel.Name, "Print")
+}
+
+// ShapeOf classifies a parsed lesson.
+func ShapeOf(f *ast.File) Shape {
+	var s Shape
+	if f.Name.Name != "main" {
+		s.Reason = "package " + f.Name.Name + " (runs from a shared runner)"
+		return s
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Name.Name == "main" && d.Recv == nil {
+				s.Host = d
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.VAR && slices.Contains(declNames(d), "sections") {
+				s.Converted = true
+			}
+		}
+	}
+	if s.Host == nil {
+		s.Reason = "no main (runs from a shared runner)"
+		return s
+	}
+	funcs := niladicFuncs(f)
+	first, last := -1, -1
+	for i, stmt := range s.Host.Body.List {
+		if _, ok := sectionCall(stmt, funcs); ok {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	ast.Inspect(s.Host, func(n ast.Node) bool {
+		if stmt, ok := n.(ast.Stmt); ok && isPrint(stmt) {
+			call := stmt.(*ast.ExprStmt).X.(*ast.CallExpr)
+			if len(call.Args) == 0 {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+				if text, err := strconv.Unquote(lit.Value); err == nil {
+					if m := marker.FindStringSubmatch(text); m != nil {
+						s.Markers = append(s.Markers, m[2])
+					}
+				}
+			}
+		}
+		return true
+	})
+	switch {
+	case s.Converted:
+	case first < 0 || first == last:
+		s.Reason = "inline examples"
+	default:
+		for _, stmt := range s.Host.Body.List[first : last+1] {
+			if _, ok := sectionCall(stmt, funcs); ok {
+				s.Calls = append(s.Calls, stmt.(*ast.ExprStmt))
+			} else if !isPrint(stmt) {
+				s.Reason = "code between sections"
+				s.Calls = nil
+				break
+			}
+		}
+	}
+	return s
+}
+
+func declNames(d *ast.GenDecl) []string {
+	var names []string
+	for _, spec := range d.Specs {
+		if vs, ok := spec.(*ast.ValueSpec); ok {
+			for _, n := range vs.Names {
+				names = append(names, n.Name)
+			}
+		}
+	}
+	return names
+}
+
+// ---------------------------------------------------------
+// Part 4: sectionize — the Mechanical Refactor
+// ---------------------------------------------------------
+
+// Sectionize rewrites a function-shaped lesson: each section call,
+// together with the banner printed just before it, moves into
+// "var sections", and main hands them to pkg/section, which runs the
+// ones asked for. The moved text is copied verbatim, comments and all;
+// nothing else changes.
+//
+// Where does section 1's banner start? Section 2's is everything
+// between call 1 and call 2, so section 1 takes as many statements
+// before call 1 as section 2 has — the rest is the lesson's own title.
+func Sectionize(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	shape := ShapeOf(f)
+	switch {
+	case shape.Converted:
+		return nil, errors.New("already has var sections")
+	case shape.Calls == nil:
+		return nil, fmt.Errorf("not function-shaped: %s", shape.Reason)
+	}
+	off := func(p token.Pos) int { return fset.Position(p).Offset }
+	funcs := niladicFuncs(f)
+	body := shape.Host.Body.List
+	index := func(stmt ast.Stmt) int { return slices.Index(body, stmt) }
+
+	first, second := index(shape.Calls[0]), index(shape.Calls[1])
+	start := max(first-(second-first-1), 0)
+	// The segment of section k is the text from the end of the
+	// statement before it to the end of its call. Section 1's starts at
+	// its first statement: a comment above it ("// Run all examples")
+	// is about all the calls, and stays in main above section.Run.
+	segStart := []int{off(body[start].Pos())}
+	for _, call := range shape.Calls[:len(shape.Calls)-1] {
+		segStart = append(segStart, off(call.End()))
+	}
+
+	var table strings.Builder
+	table.WriteString("\n// sections are this lesson's parts, in order.\nvar sections = []section.Section{\n")
+	for k, call := range shape.Calls {
+		name, _ := sectionCall(call, funcs)
+		seg := dropSeparators(strings.TrimSpace(string(src[segStart[k]:off(call.End())])))
+		if seg == name+"()" {
+			fmt.Fprintf(&table, "{Name: %q, Run: %s},\n", Slug(name), name)
+		} else {
+			fmt.Fprintf(&table, "{Name: %q, Run: func() {\n%s\n}},\n", Slug(name), seg)
+		}
+	}
+	table.WriteString("}\n")
+
+	var out bytes.Buffer
+	out.Write(src[:segStart[0]])
+	out.WriteString("section.Run(sections)")
+	out.Write(src[off(shape.Calls[len(shape.Calls)-1].End()):off(shape.Host.End())])
+	out.WriteString("\n" + table.String())
+	out.Write(src[off(shape.Host.End()):])
+	return addImport(out.Bytes(), sectionImport)
+}
+
+// dropSeparators removes the comments a segment starts with when a
+// blank line follows them: a "// ─────" rule between two parts of main
+// separates them, and means nothing inside a section of its own.
+func dropSeparators(seg string) string {
+	for strings.HasPrefix(seg, "//") {
+		block, rest, ok := strings.Cut(seg, "\n\n")
+		if !ok || slices.ContainsFunc(strings.Split(block, "\n"), func(l string) bool {
+			return !strings.HasPrefix(strings.TrimSpace(l), "//")
+		}) {
+			break // The comment is on the banner or the call itself
+		}
+		seg = strings.TrimSpace(rest)
+	}
+	return seg
+}
+
+// sectionImport is pkg/section's import path. Every lesson, wherever it
+// is in the repository, imports it by that one path through go.work.
+const sectionImport = "github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
+
+// addImport adds the import of a course package to the file, after the
+// standard library's in a group of its own, as the lessons write them;
+// then gofmts, which sorts the group.
+func addImport(src []byte, path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	quoted := strconv.Quote(path)
+	if slices.ContainsFunc(f.Imports, func(s *ast.ImportSpec) bool { return s.Path.Value == quoted }) {
+		return format.Source(src)
+	}
+	var decl *ast.GenDecl
+	for _, d := range f.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			decl = gd
+			break
+		}
+	}
+	off := func(p token.Pos) int { return fset.Position(p).Offset }
+	if decl == nil { // No imports at all
+		return format.Source(slices.Insert(src, off(f.Name.End()), []byte("\n\nimport "+quoted)...))
+	}
+	sep := "\n\n" // A new group, unless the last import is a course package already
+	last, _ := strconv.Unquote(decl.Specs[len(decl.Specs)-1].(*ast.ImportSpec).Path.Value)
+	if build.IsLocalImport(last) {
+		sep = "\n"
+	}
+	if decl.Lparen.IsValid() { // import ( ... )
+		src = slices.Insert(src, off(decl.Rparen), []byte(sep+quoted+"\n")...)
+	} else { // import "fmt"
+		spec := string(src[off(decl.Specs[0].Pos()):off(decl.End())])
+		src = slices.Replace(src, off(decl.Pos()), off(decl.End()), []byte("import (\n"+spec+sep+quoted+"\n)")...)
+	}
+	return format.Source(src)
+}
+
+// imp type-checks imports from source, looked up from the lesson's
+// directory, so the course's own packages — pkg/section, pkg/rxlib —
+// resolve through go.work. The standard library comes from
+// source too: a regexp.Regexp that rxlib was checked against has to be
+// the same type as the lesson's.
+var imp = importer.ForCompiler(token.NewFileSet(), "source", nil)
+
+// typeCheck reports the first type error in the lesson at filename, or
+// nil; src is its text, rewritten or not.
+func typeCheck(filename string, src []byte) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return err
+	}
+	conf := types.Config{Importer: imp}
+	_, err = conf.Check("main", fset, []*ast.File{f}, nil)
+	return err
+}
+
+// ---------------------------------------------------------
+// Part 5: Showing One Section of an Inline Lesson
+// ---------------------------------------------------------
+
+// FilterSection copies the part of a lesson's output that belongs to
+// the section query picks: from its "--- Example N" line up to the
+// next one or the takeaways banner.
+func FilterSection(w io.Writer, r io.Reader, titles []string, query string) error {
+	names := make([]string, len(titles))
+	for i, t := range titles {
+		names[i] = Slug(t)
+	}
+	i, err := Match(names, query)
+	if err != nil {
+		return err
+	}
+	in := false
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if m := marker.FindStringSubmatch(line); m != nil {
+			in = Slug(m[2]) == names[i]
+		} else if strings.HasPrefix(line, "═══") {
+			in = false
+		}
+		if in {
+			fmt.Fprintln(w, line)
+		}
+	}
+	return sc.Err()
+}
+
+// ---------------------------------------------------------
+// Part 6: Commands
+// ---------------------------------------------------------
+
+func loadShape(topic int) (string, Shape, []Section, error) {
+	path, err := lessonFile(topic)
+	if err != nil {
+		return "", Shape{}, nil, err
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if err != nil {
+		return "", Shape{}, nil, err
+	}
+	shape := ShapeOf(f)
+	var list []Section
+	switch {
+	case shape.Calls != nil:
+		for _, c := range shape.Calls {
+			name, _ := sectionCall(c, niladicFuncs(f))
+			list = append(list, Section{Name: Slug(name), Title: name})
+		}
+	default:
+		for _, t := range shape.Markers {
+			list = append(list, Section{Name: Slug(t), Title: t})
+		}
+	}
+	return path, shape, list, nil
+}
+
+func runCommand(args []string) error {
+	usage := errors.New("usage: sections TOPIC [-json] | run TOPIC -section NAME | sectionize [-w] FILE")
+	if len(args) < 2 {
+		return usage
+	}
+	switch args[0] {
+	case "sections":
+		topic, err := strconv.Atoi(args[1])
+		if err != nil {
+			return usage
+		}
+		_, _, list, err := loadShape(topic)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(args, "-json") { // What a web UI or menu reads
+			return json.NewEncoder(os.Stdout).Encode(list)
+		}
+		for i, s := range list {
+			fmt.Printf("%3d  %s\n", i+1, s.Name)
+		}
+		return nil
+
+	case "run":
+		topic, err := strconv.Atoi(args[1])
+		if err != nil {
+			return usage
+		}
+		// -section the way the lesson parses it (pkg/section): with
+		// package flag, so -section=NAME works and anything else is refused.
+		fs := flag.NewFlagSet("run", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		query := fs.String("section", "", "")
+		if fs.Parse(args[2:]) != nil || fs.NArg() > 0 || *query == "" {
+			return usage
+		}
+		path, shape, list, err := loadShape(topic)
+		if err != nil {
+			return err
+		}
+		if shape.Converted {
+			cmd := exec.Command("go", "run", path, "-section", *query)
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			return cmd.Run()
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("topic %d has no sections to pick from", topic)
+		}
+		titles, names := make([]string, len(list)), make([]string, len(list))
+		for i, s := range list {
+			titles[i], names[i] = s.Title, s.Name
+		}
+		if _, err := Match(names, *query); err != nil {
+			return err // Fail before running anything
+		}
+		cmd := exec.Command("go", "run", path)
+		out, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("go run %s: %w", path, err)
+		}
+		fmt.Fprintf(os.Stderr, "(topic %d is not sectionized yet: it ran in full, showing one part)\n", topic)
+		return FilterSection(os.Stdout, bytes.NewReader(out), titles, *query)
+
+	case "sectionize":
+		write := args[1] == "-w"
+		file := args[len(args)-1]
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		out, err := Sectionize(file, src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if before, after := typeCheck(file, src), typeCheck(file, out); before == nil && after != nil {
+			return fmt.Errorf("%s: the rewrite does not compile, file left alone: %v", file, after)
+		}
+		if !write {
+			fmt.Print(Diff(file, file, src, out))
+			return nil
+		}
+		return os.WriteFile(file, out, 0o644)
+	}
+	return usage
+}
+
+// Diff is the line diff of Topic 159, trimmed: LCS over lines, hunks
+// with 3 lines of context.
+func Diff(oldName, newName string, a, b []byte) string {
+	x := strings.SplitAfter(string(a), "\n")
+	y := strings.SplitAfter(string(b), "\n")
+	n, m := len(x), len(y)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	type edit struct {
+		op   byte
+		line string
+		i, j int
+	}
+	var edits []edit
+	for i, j := 0, 0; i < n || j < m; {
+		switch {
+		case i < n && j < m && x[i] == y[j]:
+			edits = append(edits, edit{' ', x[i], i, j})
+			i, j = i+1, j+1
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', x[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', y[j], i, j})
+			j++
+		}
+	}
+	var out strings.Builder
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+		start, end := max(0, k-3), k
+		for end < len(edits) && (edits[end].op != ' ' || slices.ContainsFunc(edits[end:min(len(edits), end+7)], func(e edit) bool { return e.op != ' ' })) {
+			end++
+		}
+		end = min(len(edits), end+3)
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		fmt.Fprintf(&out, "@@ -%d +%d @@\n", edits[start].i+1, edits[start].j+1)
+		for _, e := range edits[start:end] {
+			out.WriteString(string(e.op) + e.line)
+		}
+		k = end
+	}
+	return out.String()
+}
+
+// ---------------------------------------------------------
+// Part 7: Demo
+// ---------------------------------------------------------
+
+func demo() error {
+	fmt.Println("--- Example 1: Names and Matching ---")
+	for _, fn := range []string{"basicSHA256Hashing", "avalancheEffect", "part2FindingPatterns",
+		"Example4_WalkingDirectoryTrees", "Status Codes From an Access Log"} {
+		fmt.Printf("  %-34s → %s\n", fn, Slug(fn))
+	}
+	names := []string{"md5-vs-sha256", "md5", "sha256-basics", "sha512", "avalanche-effect"}
+	for _, q := range []string{"avalanche", "md5", "4", "sha", "bcrypt"} {
+		i, err := Match(names, q)
+		if err != nil {
+			fmt.Printf("  -section %-10s ✗ %v\n", q, err)
+		} else {
+			fmt.Printf("  -section %-10s → %s\n", q, names[i])
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Running One Section of a Registered Lesson ---")
+	var out strings.Builder
+	took, err := RunSections(&out, hashingLesson, "avalanche")
+	if err != nil {
+		return err
+	}
+	for line := range strings.Lines(out.String()) {
+		fmt.Print("  " + line)
+	}
+	fmt.Printf("  ran %d of %d sections: %v\n", len(took), len(hashingLesson), slices.Collect(maps.Keys(took)))
+	list, _ := json.Marshal(hashingLesson)
+	fmt.Printf("  what a menu or web UI gets: %s\n\n", list)
+
+	fmt.Println("--- Example 3: The Whole Course, Classified ---")
+	var fnShaped, inline, noMain, converted []string
+	var rewriteOK, brokenBefore, convertedOK int
+	for _, dir := range courseDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "[0-9]*.go"))
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), file, src, parser.ParseComments)
+			if err != nil {
+				continue
+			}
+			name := strings.SplitN(filepath.Base(file), "_", 2)[0]
+			switch shape := ShapeOf(f); {
+			case shape.Converted:
+				converted = append(converted, name)
+				if typeCheck(file, src) == nil {
+					convertedOK++
+				}
+			case shape.Calls != nil:
+				fnShaped = append(fnShaped, name)
+				rewritten, err := Sectionize(file, src)
+				switch {
+				case err != nil:
+				case typeCheck(file, src) != nil:
+					brokenBefore++ // Can't prove anything about a file that never compiled
+				case typeCheck(file, rewritten) == nil:
+					rewriteOK++
+				}
+			case shape.Host == nil:
+				noMain = append(noMain, name)
+			default:
+				inline = append(inline, name)
+			}
+		}
+	}
+	byNumber := func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	}
+	slices.SortStableFunc(fnShaped, byNumber)
+	slices.SortStableFunc(noMain, byNumber)
+	fmt.Printf("  function-shaped %3d  %s\n", len(fnShaped), strings.Join(fnShaped, " "))
+	fmt.Printf("  inline          %3d  (sections come from \"--- Example N\" markers)\n", len(inline))
+	fmt.Printf("  shared runner   %3d  %s\n", len(noMain), strings.Join(noMain, " "))
+	slices.SortStableFunc(converted, byNumber)
+	fmt.Printf("  converted       %3d  %s\n", len(converted), strings.Join(converted, " "))
+	fmt.Printf("  ✓ %d of %d converted lessons type-check\n", convertedOK, len(converted))
+	fmt.Printf("  ✓ sectionize output type-checks for %d of %d still function-shaped;\n", rewriteOK, len(fnShaped))
+	fmt.Printf("    %d did not type-check before the rewrite\n\n", brokenBefore)
+
+	fmt.Println("--- Example 4: The Refactor as a Diff ---")
+	const demoPath = "999_pipeline.go"
+	src := []byte(`package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("TOPIC: PIPELINES")
+	fmt.Println()
+	generateStage()
+	squareStage()
+	fanIn()
+}
+
+func generateStage() { fmt.Println("--- Example 1: Generator ---") }
+func squareStage()   { fmt.Println("--- Example 2: Square ---") }
+func fanIn()         { fmt.Println("--- Example 3: Fan-In ---") }
+`)
+	rewritten, err := Sectionize(demoPath, src)
+	if err != nil {
+		return err
+	}
+	for line := range strings.Lines(Diff(demoPath, demoPath, src, rewritten)) {
+		fmt.Print("  " + line)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: One Section of an Inline Lesson ---")
+	path, err := lessonFile(165)
+	if err != nil {
+		return err
+	}
+	_, _, sections, err := loadShape(165)
+	if err != nil {
+		return err
+	}
+	titles := make([]string, len(sections))
+	for i, s := range sections {
+		titles[i] = s.Title
+	}
+	cmd := exec.Command("go", "run", path, "-width", "50")
+	lessonOut, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("go run %s: %w", path, err)
+	}
+	fmt.Printf("  $ run 165 -section sparklines   (%d lines of output → one section)\n", bytes.Count(lessonOut, []byte("\n")))
+	out.Reset()
+	if err := FilterSection(&out, bytes.NewReader(lessonOut), titles, "sparklines"); err != nil {
+		return err
+	}
+	for line := range strings.Lines(out.String()) {
+		fmt.Print("  │ " + line)
+	}
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: PER-SECTION EXECUTION — RUNNING ONE PART OF A TOPIC")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A section registry is data: names and funcs a runner can pick from.
+2. Derive names from code (basicSHA256Hashing → basic-sha256-hashing).
+3. Match exact, then number, then unique prefix; list on ambiguity.
+4. Automate only the mechanical refactor; type-check before and after.
+5. Until a lesson is converted, filter its output by section markers.
+	`)
+}
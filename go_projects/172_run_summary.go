@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/a11y"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/course"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/deprecate"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/trace"
+)
+
+/*
+TOPIC: A TIMING AND STEP SUMMARY AFTER EACH LESSON RUN
+
+CONCEPT:
+Run a lesson and it scrolls past. Which example was the slow one? How
+much did each part print? The runner can answer both without touching
+the lesson, because it already sits on the lesson's stdout:
+
+    go run 113_timers.go ──stdout──▶ Recorder ──▶ terminal
+                                        │
+                                        ▼ one span per section
+    ── run summary: 113_timers.go ── exit 0 ── 8.05s ──
+     #  section                   time  share                 lines
+        startup                   47ms                            0
+        intro                     33µs                    0%      4
+     1  Basic Timer                 2s  ████             25%      5
+     2  Stopping & Resetting        1s  ██               12%      6
+     ...
+
+SPANS (Topic 140, pkg/trace) fit exactly: a root span for the run, a "startup"
+child for build + launch (everything until the first byte), then one
+child per section. A section span starts when its "--- Example N: ---"
+marker line arrives and ends when the next one does — the same markers
+171 uses to pick a section out of an inline lesson. Every span carries
+the lines and bytes printed under it.
+
+WHAT IT CAN'T SEE: the timing is "when the line reached the pipe". A
+lesson that buffers its output (bufio.Writer, Topic 80) would look
+instant until it flushes. Every lesson in this course prints with fmt
+straight to os.Stdout, which is unbuffered, so the times are honest.
+
+The recorder is an io.Writer, so the lesson's output still reaches the
+terminal byte for byte; the summary is printed after it, once the
+process has exited.
+
+RUN:
+    go run 172_run_summary.go                        (demo)
+    go run 172_run_summary.go run 113                (lesson, then summary)
+    go run 172_run_summary.go run -quiet 113         (summary only)
+    go run 172_run_summary.go run -spans out.jsonl 124 -- -section 2
+    go run 172_run_summary.go run -accessible 113    (for a screen reader, Topic 190)
+*/
+
+// ---------------------------------------------------------
+// Part 1: Steps
+// ---------------------------------------------------------
+
+// Step is one timed step of a run: the run itself, its startup, or one
+// section. Its timing is a pkg/trace span (Topic 140), a child of the
+// run's; the counts are what the recorder adds. JSON flattens the two.
+type Step struct {
+	*trace.Span
+	Section  int  `json:"section,omitempty"` // Example number, 0 if not a section
+	Lines    int  `json:"lines"`
+	Bytes    int  `json:"bytes"`
+	ExitCode *int `json:"exit_code,omitempty"` // Root step only
+}
+
+// ---------------------------------------------------------
+// Part 2: The Recorder
+// ---------------------------------------------------------
+
+var (
+	marker = regexp.MustCompile(`^\s*--- (?:Example (\d+): )?(.+?) ---\s*$`)
+	rule   = regexp.MustCompile(`^\s*(═{10,}|={10,})\s*$`)
+)
+
+// Recorder passes a lesson's output through to out and cuts it into
+// steps at section markers. Set it as the command's Stdout and Stderr.
+type Recorder struct {
+	out     io.Writer
+	tracer  *trace.Tracer
+	ctx     context.Context // Holds the root span, so every step is its child
+	steps   []*Step
+	root    *Step
+	cur     *Step
+	partial []byte // Text after the last newline, waiting for the rest of its line
+	section int    // Sections seen so far, for numbering markers without one
+}
+
+// NewRecorder starts the root step and its startup child. now is the
+// clock; nil means time.Now (the demo passes a fake one).
+func NewRecorder(out io.Writer, name string, now func() time.Time) *Recorder {
+	r := &Recorder{out: out, tracer: &trace.Tracer{Now: now}, ctx: context.Background()}
+	r.root = r.start(name)
+	r.ctx = trace.ContextWithSpan(r.ctx, r.root.Span)
+	r.cur = r.start("startup")
+	return r
+}
+
+func (r *Recorder) start(name string) *Step {
+	_, span := r.tracer.Start(r.ctx, name)
+	s := &Step{Span: span}
+	r.steps = append(r.steps, s)
+	return s
+}
+
+// next ends the current child step and starts the one after it.
+func (r *Recorder) next(name string, section int) {
+	r.cur.End()
+	r.cur = r.start(name)
+	r.cur.Section = section
+}
+
+func (r *Recorder) Write(p []byte) (int, error) {
+	if _, err := r.out.Write(p); err != nil {
+		return 0, err
+	}
+	if r.cur.Name == "startup" && len(p) > 0 {
+		r.next("intro", 0) // First byte: the build is done and main is running
+	}
+	r.partial = append(r.partial, p...)
+	for {
+		i := bytes.IndexByte(r.partial, '\n')
+		if i < 0 {
+			break
+		}
+		r.line(r.partial[:i+1])
+		r.partial = r.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (r *Recorder) line(b []byte) {
+	text := strings.TrimRight(string(b), "\r\n")
+	switch m := marker.FindStringSubmatch(text); {
+	case m != nil:
+		r.section++
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			r.section = n
+		}
+		r.next(m[2], r.section)
+	case rule.MatchString(text) && r.section > 0 && r.cur.Name != "takeaways":
+		r.next("takeaways", 0) // The closing banner: KEY TAKEAWAYS and friends
+	}
+	r.cur.Lines++
+	r.cur.Bytes += len(b)
+}
+
+// Close ends the open steps once the process has exited and returns
+// them, root first.
+func (r *Recorder) Close(exitCode int) []*Step {
+	if len(r.partial) > 0 {
+		r.line(r.partial) // A last line with no newline still counts
+		r.partial = nil
+	}
+	r.cur.End()
+	r.root.End()
+	r.root.ExitCode = &exitCode
+	for _, s := range r.steps[1:] {
+		r.root.Lines += s.Lines
+		r.root.Bytes += s.Bytes
+	}
+	return r.steps
+}
+
+// ---------------------------------------------------------
+// Part 3: The Summary Table
+// ---------------------------------------------------------
+
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Microsecond)
+}
+
+// Summarize prints the table shown after a run. Shares are of the time
+// spent running main, so a slow build doesn't flatten every bar.
+func Summarize(w io.Writer, spans []*Step) {
+	if len(spans) == 0 {
+		return
+	}
+	root, steps := spans[0], spans[1:]
+	var running time.Duration
+	for _, s := range steps {
+		if s.Name != "startup" {
+			running += s.Duration()
+		}
+	}
+	exit := 0
+	if root.ExitCode != nil {
+		exit = *root.ExitCode
+	}
+	fmt.Fprintf(w, "  ── run summary: %s ── exit %d ── %v ──\n", root.Name, exit, round(root.Duration()))
+	fmt.Fprintf(w, "   %2s  %-26s %8s  %-21s %6s %7s\n", "#", "section", "time", "share", "lines", "bytes")
+	var slowest *Step
+	for _, s := range steps {
+		num, bar := "", ""
+		if s.Section > 0 {
+			num = strconv.Itoa(s.Section)
+			if slowest == nil || s.Duration() > slowest.Duration() {
+				slowest = s
+			}
+		}
+		if s.Name != "startup" && running > 0 {
+			share := float64(s.Duration()) / float64(running)
+			n := int(share*16 + 0.5)
+			bar = fmt.Sprintf("%s%s %3.0f%%", strings.Repeat("█", n), strings.Repeat(" ", 16-n), share*100)
+		}
+		name := s.Name
+		if len([]rune(name)) > 26 {
+			name = string([]rune(name)[:25]) + "…"
+		}
+		fmt.Fprintf(w, "   %2s  %-26s %8v  %-21s %6d %7d\n", num, name, round(s.Duration()), bar, s.Lines, s.Bytes)
+	}
+	fmt.Fprintf(w, "   %2s  %-26s %8v  %-21s %6d %7d\n", "", "total", round(root.Duration()), "", root.Lines, root.Bytes)
+	switch {
+	case slowest == nil && exit != 0:
+		fmt.Fprintln(w, "  no sections ran; the output above says why")
+	case slowest == nil:
+		fmt.Fprintln(w, "  no \"--- Example N: ... ---\" markers, so no per-section times")
+	default:
+		fmt.Fprintf(w, "  slowest: %d %s (%v, %.0f%% of the run)\n", slowest.Section, slowest.Name,
+			round(slowest.Duration()), 100*float64(slowest.Duration())/float64(running))
+	}
+}
+
+// WriteSpans writes one JSON object per step (JSON Lines), as
+// trace.ExportJSON does with the counts added.
+func WriteSpans(w io.Writer, spans []*Step) error {
+	enc := json.NewEncoder(w)
+	for _, s := range spans {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 4: Running a Lesson
+// ---------------------------------------------------------
+
+// lessonFile finds a topic's lesson file.
+//
+// Deprecated: use course.File.
+func lessonFile(topic int) (string, error) {
+	deprecate.Warn("lessonFile")
+	return course.File(topic)
+}
+
+// RunLesson runs one lesson with go run, sending its output to out, and
+// returns the steps. A lesson that fails to build or exits non-zero is
+// still a finished run — its exit code is on the root span; err is only
+// for a run that couldn't start at all.
+func RunLesson(path string, args []string, out io.Writer) ([]*Step, error) {
+	cmd := exec.Command("go", append([]string{"run", filepath.Base(path)}, args...)...)
+	cmd.Dir = filepath.Dir(path)
+	rec := NewRecorder(out, filepath.Base(path), nil)
+	cmd.Stdout = rec
+	cmd.Stderr = rec // The same writer, so exec copies both through one pipe in order
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	return rec.Close(exitCode), nil
+}
+
+func cmdRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	quiet := fs.Bool("quiet", false, "hide the lesson's output, print only the summary")
+	spansPath := fs.String("spans", "", "also write the spans as JSON Lines to this file")
+	accessible := fs.Bool("accessible", false, "plain text for screen readers (also $GOTUT_ACCESSIBLE, or config.json)")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		return errors.New("usage: run [-quiet] [-accessible] [-spans FILE] TOPIC [-- lesson flags]")
+	}
+	on, err := a11y.Enabled(*accessible)
+	if err != nil {
+		return err
+	}
+	topic, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("topic %q is not a number", fs.Arg(0))
+	}
+	path, err := lessonFile(topic)
+	if err != nil {
+		return err
+	}
+	lessonArgs := fs.Args()[1:]
+	if len(lessonArgs) > 0 && lessonArgs[0] == "--" {
+		lessonArgs = lessonArgs[1:]
+	}
+
+	// The accessible Writer goes after the Recorder, which needs the
+	// "--- Example N ---" markers as the lesson printed them.
+	var term io.Writer = os.Stdout
+	if on {
+		w := a11y.NewWriter(os.Stdout)
+		defer w.Flush()
+		term = w
+	}
+	out := term
+	if *quiet {
+		out = io.Discard
+	}
+	spans, err := RunLesson(path, lessonArgs, out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(term)
+	Summarize(term, spans)
+	if w, ok := term.(*a11y.Writer); ok {
+		w.Flush() // Before a failing lesson's os.Exit below skips the defer
+	}
+	if *spansPath != "" {
+		var buf bytes.Buffer
+		if err := WriteSpans(&buf, spans); err != nil {
+			return err
+		}
+		if err := os.WriteFile(*spansPath, buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	if code := *spans[0].ExitCode; code != 0 {
+		os.Exit(code) // Pass the lesson's failure on to whoever ran us
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+// fakeClock moves only when told to, so the scripted run below prints
+// the same table every time.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newFakeClock() *fakeClock               { return &fakeClock{t: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)} }
+func check(ok bool, pass, fail string) string {
+	if ok {
+		return "✓ " + pass
+	}
+	return "✗ " + fail
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: A TIMING AND STEP SUMMARY AFTER EACH LESSON RUN")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: A Scripted Run Through the Recorder ---")
+	// What a lesson's stdout looks like to the runner: chunks of bytes
+	// arriving over time. Chunks don't respect lines — the second write
+	// ends mid-line, as a real pipe read can.
+	script := []struct {
+		after time.Duration
+		text  string
+	}{
+		{350 * time.Millisecond, "════════════\nTOPIC: PIPELINES\n════════════\n\n--- Example 1: Gene"},
+		{0, "rator ---\n1 2 3\n"},
+		{2 * time.Millisecond, "--- Example 2: Fan-Out ---\nworker 1: 4\nworker 2: 9\n"},
+		{1500 * time.Millisecond, "worker 3: 16\n\n--- Example 3: Cancellation ---\nstopped\n"},
+		{300 * time.Millisecond, "\n════════════\nKEY TAKEAWAYS\n════════════\n1. Close from the sender."},
+		{time.Millisecond, ""},
+	}
+	clock := newFakeClock()
+	var passed bytes.Buffer
+	rec := NewRecorder(&passed, "999_pipelines.go", clock.now)
+	var sent strings.Builder
+	for _, step := range script {
+		clock.advance(step.after)
+		rec.Write([]byte(step.text))
+		sent.WriteString(step.text)
+	}
+	spans := rec.Close(0)
+	for line := range strings.Lines(passed.String()) {
+		fmt.Print("  │ ", line)
+	}
+	fmt.Println()
+	fmt.Println(" ", check(passed.String() == sent.String(),
+		"the lesson's output passed through byte for byte",
+		"the recorder changed the output"))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: The Summary Table ---")
+	Summarize(os.Stdout, spans)
+	lines := strings.Count(sent.String(), "\n") + 1 // +1: the last line has no newline
+	fmt.Println(" ", check(spans[0].Lines == lines,
+		fmt.Sprintf("the spans account for all %d lines", lines),
+		fmt.Sprintf("spans hold %d lines, output has %d", spans[0].Lines, lines)))
+	fmt.Println(" ", check(spans[3].Name == "Generator",
+		"a marker split across two writes still names its section",
+		"the split marker was missed"))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The Same Spans as JSON Lines ---")
+	var jsonl bytes.Buffer
+	if err := WriteSpans(&jsonl, spans); err != nil {
+		return err
+	}
+	for i, line := range strings.Split(strings.TrimSpace(jsonl.String()), "\n") {
+		if i == 0 || i == 4 {
+			fmt.Println("  " + line)
+		}
+	}
+	fmt.Printf("  ... %d spans; a dashboard can sum them across runs to find slow demos\n\n", len(spans))
+
+	fmt.Println("--- Example 4: A Real Lesson (113, timers) ---")
+	path, err := lessonFile(113)
+	if err != nil {
+		return err
+	}
+	fmt.Println("  $ run -quiet 113   (the lesson sleeps on purpose; this takes a few seconds)")
+	spans, err = RunLesson(path, nil, io.Discard)
+	if err != nil {
+		return err
+	}
+	Summarize(os.Stdout, spans)
+	sections := 0
+	for _, s := range spans {
+		if s.Section > 0 {
+			sections++
+		}
+	}
+	fmt.Println(" ", check(sections == 4, "one span per example (113 has 4)",
+		fmt.Sprintf("expected 4 section spans, got %d", sections)))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: A Lesson That Doesn't Build (82) ---")
+	path, err = lessonFile(82)
+	if err != nil {
+		return err
+	}
+	var buildOut bytes.Buffer
+	spans, err = RunLesson(path, nil, &buildOut)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  (%d lines of compiler output hidden)\n", spans[0].Lines)
+	Summarize(os.Stdout, spans)
+	fmt.Println(" ", check(*spans[0].ExitCode != 0, "the failed build is still summarized, with its exit code",
+		"82 built; its \"=\"*80 lines must have been fixed"))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. The runner already owns the lesson's stdout; wrap it in an io.Writer.
+2. One span per section: start at its marker, end at the next one.
+3. Split on lines, not writes — a pipe read can end mid-marker.
+4. Startup (build + launch) is its own span; shares exclude it.
+5. A failed run is still a run: summarize it and pass the exit code on.
+	`)
+	return nil
+}
+
+func main() {
+	var err error
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "run":
+		err = cmdRun(os.Args[2:])
+	case len(os.Args) > 1:
+		err = fmt.Errorf("unknown command %q (try: run)", os.Args[1])
+	default:
+		err = demo()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
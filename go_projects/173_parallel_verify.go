@@ -0,0 +1,704 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+TOPIC: PARALLEL LESSON VERIFICATION WITH ISOLATION
+
+CONCEPT:
+"Verify" means: every lesson builds, runs, and exits 0. Over a hundred
+lessons one after another is slow — many of them sleep on purpose
+(timers, tickers, rate limiters) — so run them on a worker pool
+(Topic 115). The catch is that lessons aren't polite neighbours:
+
+    83  writes output.txt and example.txt into the current directory
+    87  creates and removes directories relative to the current directory
+    168 keeps notes under the user's config dir
+
+and the current directory belongs to the whole PROCESS, not to a
+goroutine. One goroutine calling os.Chdir moves every other goroutine
+with it (Example 1). So each lesson runs as its own process, in its
+own sandbox:
+
+    $TMPDIR/verify-123/83_write_file_detailed/
+        lesson              the built binary
+        tree/               the course, mirrored with symlinks:
+          go_projects/        → reads of course files still work
+          intermediate_topics/  ← the lesson's working directory;
+          go_advanced_concepts/   new files land HERE, not in the repo
+        home/  tmp/         HOME, XDG_* and TMPDIR point here
+
+Symlinks make reads free, but a lesson that OVERWRITES an existing
+course file writes through the link. The verifier can't prevent that,
+so it detects it: the course tree is snapshotted (size + mtime) before
+and after, and any difference is reported as a leak.
+
+Lessons whose package isn't main (most of 59–84) are built from a copy
+whose package clause says main, as 170 does for snippets. Lessons with
+no main at all run from a shared runner and are reported as skipped.
+
+All failures — build errors, non-zero exits, timeouts — are collected
+into ONE report at the end, in topic order, instead of stopping at the
+first.
+
+RUN:
+    go run 173_parallel_verify.go                   (demo)
+    go run 173_parallel_verify.go verify            (every lesson)
+    go run 173_parallel_verify.go verify -j 8 -timeout 20s 104 113 127
+    go run 173_parallel_verify.go verify -keep 83   (keep the sandbox to inspect)
+*/
+
+// ---------------------------------------------------------
+// Part 1: Finding Lessons
+// ---------------------------------------------------------
+
+// courseDirs are where topics live, relative to go_projects/ (see 168).
+var courseDirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+type Kind int
+
+const (
+	Runnable Kind = iota // package main with a func main
+	Renamed              // func main in another package; built from a renamed copy
+	NoMain               // part of a shared runner; nothing to run on its own
+)
+
+type Lesson struct {
+	Topic int
+	Path  string // Absolute
+	Kind  Kind
+}
+
+func (l Lesson) Name() string { return strings.TrimSuffix(filepath.Base(l.Path), ".go") }
+
+// FindLessons returns the numbered lessons in dirs, by topic. If topics
+// is not empty, only those are returned.
+func FindLessons(dirs []string, topics ...int) ([]Lesson, error) {
+	var lessons []Lesson
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "[0-9]*.go"))
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			topic, err := strconv.Atoi(strings.SplitN(filepath.Base(file), "_", 2)[0])
+			if err != nil || len(topics) > 0 && !slices.Contains(topics, topic) {
+				continue
+			}
+			abs, err := filepath.Abs(file)
+			if err != nil {
+				return nil, err
+			}
+			lessons = append(lessons, Lesson{Topic: topic, Path: abs, Kind: kindOf(abs)})
+		}
+	}
+	slices.SortStableFunc(lessons, func(a, b Lesson) int { return a.Topic - b.Topic })
+	return lessons, nil
+}
+
+func kindOf(path string) Kind {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return Runnable // Let the build report the syntax error
+	}
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			if f.Name.Name == "main" {
+				return Runnable
+			}
+			return Renamed
+		}
+	}
+	return NoMain
+}
+
+// asMain returns src with its package clause changed to main.
+func asMain(src []byte) ([]byte, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+	if err != nil {
+		return nil, err
+	}
+	start, end := int(f.Name.Pos())-1, int(f.Name.End())-1 // Pos is 1-based
+	return slices.Concat(src[:start], []byte("main"), src[end:]), nil
+}
+
+// ---------------------------------------------------------
+// Part 2: The Sandbox
+// ---------------------------------------------------------
+
+// sandbox lays out one lesson's private directory (see the diagram at
+// the top) and returns the working directory to run it in.
+func sandbox(dir string, tree []string, l Lesson) (string, error) {
+	for _, sub := range []string{"home", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return "", err
+		}
+	}
+	for _, src := range tree {
+		dst := filepath.Join(dir, "tree", filepath.Base(src))
+		if err := os.MkdirAll(dst, 0o755); err != nil {
+			return "", err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return "", err
+		}
+		for _, e := range entries {
+			if err := os.Symlink(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return "", err
+			}
+		}
+	}
+	cwd := filepath.Join(dir, "tree", filepath.Base(filepath.Dir(l.Path)))
+	if _, err := os.Stat(cwd); err != nil {
+		cwd = filepath.Join(dir, "tree") // A lesson from outside the course starts at the top
+	}
+	return cwd, nil
+}
+
+// sandboxEnv is the environment with every "where do I keep my files"
+// variable pointed into the sandbox.
+func sandboxEnv(dir string, extra []string) []string {
+	home, tmp := filepath.Join(dir, "home"), filepath.Join(dir, "tmp")
+	override := map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": filepath.Join(home, ".config"),
+		"XDG_CACHE_HOME":  filepath.Join(home, ".cache"),
+		"XDG_DATA_HOME":   filepath.Join(home, ".local", "share"),
+		"TMPDIR":          tmp,
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		if k, _, _ := strings.Cut(kv, "="); override[k] == "" {
+			env = append(env, kv)
+		}
+	}
+	for k, v := range override {
+		env = append(env, k+"="+v)
+	}
+	return append(env, extra...)
+}
+
+// created lists the regular files a lesson left in its sandbox. Walking
+// doesn't follow symlinks, so the mirrored course itself never shows up.
+func created(dir string) []string {
+	var files []string
+	for _, sub := range []string{"tree", "home", "tmp"} {
+		filepath.WalkDir(filepath.Join(dir, sub), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() {
+				rel, _ := filepath.Rel(dir, path)
+				files = append(files, rel)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+type fileState struct {
+	size int64
+	mod  time.Time
+}
+
+// snapshot records every file under dirs, to catch writes that went
+// through a symlink into the real course.
+func snapshot(dirs []string) map[string]fileState {
+	files := map[string]fileState{}
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				files[path] = fileState{info.Size(), info.ModTime()}
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+func leaks(before, after map[string]fileState) []string {
+	var out []string
+	for path, a := range after {
+		if b, ok := before[path]; !ok {
+			out = append(out, "created "+path)
+		} else if a.size != b.size || !a.mod.Equal(b.mod) {
+			out = append(out, "changed "+path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			out = append(out, "removed "+path)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// ---------------------------------------------------------
+// Part 3: Verifying One Lesson
+// ---------------------------------------------------------
+
+type Status string
+
+const (
+	Pass       Status = "pass"
+	Fail       Status = "fail"    // Ran and exited non-zero
+	BuildError Status = "build"   // Didn't compile
+	Timeout    Status = "timeout" // Still running when time ran out
+	Skipped    Status = "skip"    // No main of its own
+)
+
+type Result struct {
+	Lesson
+	Status  Status
+	Build   time.Duration
+	Run     time.Duration
+	Output  []byte   // Build errors or the lesson's stdout and stderr
+	Err     error    // What went wrong, for everything but Pass and Skipped
+	Created []string // Files the lesson wrote, all inside its sandbox
+}
+
+type Verifier struct {
+	Root    string        // One sandbox per lesson is made under here
+	Tree    []string      // Absolute course dirs, mirrored into every sandbox
+	Workers int           // Lessons verified at once
+	Timeout time.Duration // Per lesson, for the run (not the build)
+	Env     []string      // Extra KEY=value for every lesson (GOCACHE, see main)
+}
+
+func (v *Verifier) verifyOne(ctx context.Context, l Lesson) Result {
+	r := Result{Lesson: l, Status: Pass}
+	if l.Kind == NoMain {
+		r.Status = Skipped
+		return r
+	}
+	dir := filepath.Join(v.Root, l.Name())
+	cwd, err := sandbox(dir, v.Tree, l)
+	if err != nil {
+		r.Status, r.Err = Fail, err
+		return r
+	}
+
+	// Build from the lesson's own directory, so //go:embed finds its
+	// files; a renamed copy is built from the sandbox instead.
+	bin := filepath.Join(dir, "lesson")
+	build := exec.CommandContext(ctx, "go", "build", "-o", bin, filepath.Base(l.Path))
+	build.Dir = filepath.Dir(l.Path)
+	if l.Kind == Renamed {
+		src, err := os.ReadFile(l.Path)
+		if err == nil {
+			src, err = asMain(src)
+		}
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, "main.go"), src, 0o644)
+		}
+		if err != nil {
+			r.Status, r.Err = BuildError, err
+			return r
+		}
+		build.Args[len(build.Args)-1] = "main.go"
+		build.Dir = dir
+	}
+	start := time.Now()
+	out, err := build.CombinedOutput()
+	r.Build = time.Since(start)
+	if err != nil {
+		r.Status, r.Err, r.Output = BuildError, err, out
+		return r
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, v.Timeout)
+	defer cancel()
+	run := exec.CommandContext(runCtx, bin)
+	run.Dir = cwd
+	run.Env = sandboxEnv(dir, v.Env)
+	var buf bytes.Buffer
+	run.Stdout, run.Stderr = &buf, &buf
+	// Stdin stays nil, so a lesson that prompts reads EOF instead of
+	// hanging. On timeout it gets an interrupt to clean up, then a kill.
+	run.Cancel = func() error { return run.Process.Signal(os.Interrupt) }
+	run.WaitDelay = 2 * time.Second
+	start = time.Now()
+	err = run.Run()
+	r.Run = time.Since(start)
+	r.Output = buf.Bytes()
+	r.Created = created(dir)
+	switch {
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		r.Status, r.Err = Timeout, fmt.Errorf("still running after %v", v.Timeout)
+	case err != nil:
+		r.Status, r.Err = Fail, err
+	}
+	return r
+}
+
+// ---------------------------------------------------------
+// Part 4: The Worker Pool
+// ---------------------------------------------------------
+
+// Verify checks every lesson on v.Workers goroutines and returns the
+// results in the order the lessons were given, whatever order they
+// finished in. progress, if not nil, is called as each one finishes,
+// always from the calling goroutine.
+func (v *Verifier) Verify(ctx context.Context, lessons []Lesson, progress func(done int, r Result)) []Result {
+	type indexed struct {
+		i int
+		r Result
+	}
+	jobs := make(chan int)
+	done := make(chan indexed)
+	var wg sync.WaitGroup
+	for range max(v.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				done <- indexed{i, v.verifyOne(ctx, lessons[i])}
+			}
+		}()
+	}
+	go func() {
+		for i := range lessons {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	results := make([]Result, len(lessons))
+	n := 0
+	for d := range done {
+		results[d.i] = d.r
+		n++
+		if progress != nil {
+			progress(n, d.r)
+		}
+	}
+	return results
+}
+
+// ---------------------------------------------------------
+// Part 5: One Report
+// ---------------------------------------------------------
+
+func tail(out []byte, n int) []string {
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) > n {
+		lines = append([]string{fmt.Sprintf("... %d lines above", len(lines)-n)}, lines[len(lines)-n:]...)
+	}
+	return lines
+}
+
+// Report prints the totals, then every failure with the end of its
+// output, then any leaks. It returns false if anything failed.
+func Report(w io.Writer, results []Result, wall time.Duration, leaked []string) bool {
+	count := map[Status]int{}
+	var serial time.Duration
+	for _, r := range results {
+		count[r.Status]++
+		serial += r.Build + r.Run
+	}
+	fmt.Fprintf(w, "  %d lessons in %v (one after another: ~%v)\n",
+		len(results), wall.Round(100*time.Millisecond), serial.Round(100*time.Millisecond))
+	fmt.Fprintf(w, "  pass %d   fail %d   build %d   timeout %d   skip %d\n",
+		count[Pass], count[Fail], count[BuildError], count[Timeout], count[Skipped])
+
+	ok := len(leaked) == 0
+	for _, r := range results {
+		if r.Status == Pass || r.Status == Skipped {
+			continue
+		}
+		ok = false
+		fmt.Fprintf(w, "\n  ✗ %-34s %-7s %v\n", filepath.Base(r.Path), r.Status, r.Err)
+		for _, line := range tail(r.Output, 4) {
+			fmt.Fprintf(w, "      │ %s\n", line)
+		}
+	}
+	if len(leaked) > 0 {
+		fmt.Fprintln(w, "\n  ✗ the course tree changed during the run (a write went through a symlink):")
+		for _, l := range leaked {
+			fmt.Fprintln(w, "      "+l)
+		}
+	}
+	return ok
+}
+
+// ---------------------------------------------------------
+// Part 6: The verify Command
+// ---------------------------------------------------------
+
+// goEnv pins the Go caches to the caller's, since HOME moves into the
+// sandbox: without it a lesson that runs "go" itself (170–172) would
+// start from a cold build cache.
+func goEnv() []string {
+	out, err := exec.Command("go", "env", "GOCACHE", "GOMODCACHE", "GOPATH").Output()
+	if err != nil {
+		return nil
+	}
+	var env []string
+	for i, v := range strings.Fields(string(out)) {
+		env = append(env, []string{"GOCACHE", "GOMODCACHE", "GOPATH"}[i]+"="+v)
+	}
+	return env
+}
+
+func newVerifier(workers int, timeout time.Duration) (*Verifier, error) {
+	root, err := os.MkdirTemp("", "verify-")
+	if err != nil {
+		return nil, err
+	}
+	var tree []string
+	for _, dir := range courseDirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		tree = append(tree, abs)
+	}
+	return &Verifier{Root: root, Tree: tree, Workers: workers, Timeout: timeout, Env: goEnv()}, nil
+}
+
+// defaultWorkers is more than the CPU count on purpose: most lessons
+// spend their time sleeping, not computing.
+func defaultWorkers() int { return max(4, 2*runtime.NumCPU()) }
+
+func progressLine(total int) func(int, Result) {
+	return func(done int, r Result) {
+		fmt.Printf("  [%3d/%d] %-7s %-34s %v\n", done, total, r.Status,
+			filepath.Base(r.Path), (r.Build + r.Run).Round(10*time.Millisecond))
+	}
+}
+
+func cmdVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	workers := fs.Int("j", defaultWorkers(), "lessons to verify at once")
+	timeout := fs.Duration("timeout", 30*time.Second, "time limit for each lesson's run")
+	keep := fs.Bool("keep", false, "keep the sandboxes and print where they are")
+	fs.Parse(args)
+	var topics []int
+	for _, a := range fs.Args() {
+		n, err := strconv.Atoi(a)
+		if err != nil {
+			return fmt.Errorf("topic %q is not a number", a)
+		}
+		topics = append(topics, n)
+	}
+	lessons, err := FindLessons(courseDirs, topics...)
+	if err != nil {
+		return err
+	}
+	// This lesson's demo verifies other lessons; verifying it here would
+	// recurse, so it's left out.
+	lessons = slices.DeleteFunc(lessons, func(l Lesson) bool {
+		return filepath.Base(l.Path) == "173_parallel_verify.go"
+	})
+	if len(lessons) == 0 {
+		return errors.New("no lessons to verify")
+	}
+	v, err := newVerifier(*workers, *timeout)
+	if err != nil {
+		return err
+	}
+	if *keep {
+		fmt.Println("  sandboxes:", v.Root)
+	} else {
+		defer os.RemoveAll(v.Root)
+	}
+
+	before := snapshot(v.Tree)
+	start := time.Now()
+	results := v.Verify(context.Background(), lessons, progressLine(len(lessons)))
+	wall := time.Since(start)
+	fmt.Println()
+	if !Report(os.Stdout, results, wall, leaks(before, snapshot(v.Tree))) {
+		if !*keep {
+			os.RemoveAll(v.Root) // os.Exit skips the deferred cleanup
+		}
+		os.Exit(1)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 7: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) string {
+	if ok {
+		return "✓ " + pass
+	}
+	return "✗ " + fail
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: PARALLEL LESSON VERIFICATION WITH ISOLATION")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: The Working Directory Belongs to the Process ---")
+	orig, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	elsewhere, err := os.MkdirTemp("", "chdir-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(elsewhere)
+	moved, seen := make(chan struct{}), make(chan string)
+	go func() { // "Lesson A" changes directory...
+		os.Chdir(elsewhere)
+		close(moved)
+	}()
+	go func() { // ...and "lesson B", which never asked, moves with it
+		<-moved
+		wd, _ := os.Getwd()
+		seen <- wd
+	}()
+	wd := <-seen
+	if err := os.Chdir(orig); err != nil {
+		return err
+	}
+	fmt.Println("  goroutine A: os.Chdir(" + filepath.Base(elsewhere) + ")")
+	fmt.Println("  goroutine B: os.Getwd() =", filepath.Base(wd))
+	fmt.Println(" ", check(filepath.Base(wd) == filepath.Base(elsewhere),
+		"B sees A's directory: goroutines can't isolate lessons, processes can",
+		"B kept its own directory"))
+	fmt.Println()
+
+	v, err := newVerifier(defaultWorkers(), 15*time.Second)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(v.Root)
+
+	fmt.Println("--- Example 2: One Lesson in Its Sandbox ---")
+	// 83 is the course's file-writing lesson, but it doesn't compile
+	// today (Example 4 shows why), so this stand-in does what it does
+	// plus what 88 and 168 do: write to the cwd, the temp dir and the
+	// config dir. Like 83 it's package intermediate, so it gets renamed.
+	standIn := filepath.Join(v.Root, "999_writes_everywhere.go")
+	err = os.WriteFile(standIn, []byte(`package intermediate
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	os.WriteFile("output.txt", []byte("hello\n"), 0o644)
+	os.MkdirAll("reports/2026", 0o755)
+	os.WriteFile("reports/2026/summary.txt", []byte("ok\n"), 0o644)
+	if f, err := os.CreateTemp("", "scratch-*.txt"); err == nil {
+		f.Close() // Left behind on purpose
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		os.MkdirAll(filepath.Join(dir, "gotut"), 0o755)
+		os.WriteFile(filepath.Join(dir, "gotut", "notes.json"), []byte("{}\n"), 0o644)
+	}
+}
+`), 0o644)
+	if err != nil {
+		return err
+	}
+	before := snapshot(v.Tree)
+	r := v.verifyOne(context.Background(), Lesson{Topic: 999, Path: standIn, Kind: kindOf(standIn)})
+	fmt.Printf("  %s: %s (package intermediate, built from a renamed copy)\n", filepath.Base(r.Path), r.Status)
+	fmt.Println("  files it wrote:")
+	for _, f := range r.Created {
+		if strings.HasPrefix(f, "tmp/scratch-") {
+			f = "tmp/scratch-NNN.txt" // CreateTemp's random part
+		}
+		fmt.Println("    $SANDBOX/" + f)
+	}
+	leaked := leaks(before, snapshot(v.Tree))
+	fmt.Println(" ", check(r.Status == Pass && len(r.Created) == 4,
+		"all four writes landed in the sandbox",
+		fmt.Sprintf("expected 4 files in the sandbox, found %d (%s)", len(r.Created), r.Status)))
+	fmt.Println(" ", check(len(leaked) == 0, "the course tree is unchanged",
+		fmt.Sprintf("leaked into the course: %v", leaked)))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Verifying Many Lessons at Once ---")
+	var topics []int
+	for t := 103; t <= 128; t++ {
+		topics = append(topics, t)
+	}
+	topics = append(topics, 83, 89) // 83 doesn't compile; 89 has no main
+	lessons, err := FindLessons(courseDirs, topics...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %d lessons, %d workers, %v timeout each\n", len(lessons), v.Workers, v.Timeout)
+	var finished []int
+	before = snapshot(v.Tree)
+	start := time.Now()
+	results := v.Verify(context.Background(), lessons, func(done int, r Result) {
+		finished = append(finished, r.Topic)
+		if r.Status != Pass {
+			progressLine(len(lessons))(done, r)
+		}
+	})
+	wall := time.Since(start)
+	fmt.Printf("  (passing lessons not shown; %d finished)\n", len(finished))
+	inOrder := slices.IsSortedFunc(results, func(a, b Result) int { return a.Topic - b.Topic })
+	fmt.Println(" ", check(inOrder && !slices.IsSorted(finished),
+		"finished out of order, reported in topic order",
+		"results came back out of topic order"))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: The Report ---")
+	Report(os.Stdout, results, wall, leaks(before, snapshot(v.Tree)))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. The working directory is per process: isolate lessons with processes.
+2. Give each run its own cwd, HOME and TMPDIR; mirror the course with symlinks.
+3. What you can't prevent, detect: snapshot the tree before and after.
+4. Collect results by index so the report is in order, however they finish.
+5. Report every failure at the end, with the tail of its output.
+	`)
+	return nil
+}
+
+func main() {
+	var err error
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "verify":
+		err = cmdVerify(os.Args[2:])
+	case len(os.Args) > 1:
+		err = fmt.Errorf("unknown command %q (try: verify)", os.Args[1])
+	default:
+		err = demo()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/fileops"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/prompt"
+)
+
+/*
+TOPIC: DRY-RUN MODE — SHOW WHAT A DESTRUCTIVE COMMAND WOULD DO
+
+CONCEPT:
+"rm -rf $DIR/" with an empty $DIR is the classic disaster. Good tools let you
+ask first:
+
+    tool clean --dry-run     → would remove -r build (412 files, 9.1 MB)
+    tool clean               → does it
+
+The trick is architectural, not a flag check sprinkled everywhere: route every
+filesystem CHANGE through one small type, and let that type decide whether to
+act or to describe. Reads stay direct, so the dry run sees exactly the tree the
+real run would.
+
+    pkg/fileops     → Ops{DryRun, Log}: MkdirAll, WriteFile, Rename, Remove, RemoveAll;
+                      SafeRemoveAll: allow-listed root, never "/" or home, confirm
+                      (its tests prove a dry run writes nothing, and every refusal)
+    pkg/prompt      → a [y/N] prompt that defaults to no
+    main.go         → this walkthrough and the "rm" command
+
+SAFETY RAILS:
+A computed path is one empty variable away from "/". SafeRemoveAll deletes
+only strictly inside an allow-listed root, refuses "/" and your home directory
+outright, resolves symlinks before judging, and can ask first:
+
+    remove -r /tmp/gotut-x/build (3 files, 3072 B)? [y/N]
+
+What still uses plain os.RemoveAll: "defer os.RemoveAll(dir)" straight after
+a successful os.MkdirTemp. That path came from the OS a moment ago, so it is
+the one safe case. 155's janitor deletes through pkg/fileops too; lesson 87,
+which stays a single file of the standard library, carries its own copy.
+
+The same --dry-run flag is threaded through the other tools that change files:
+    155_daemon      → daemon --dry-run: the janitor lists, never deletes
+    154_backup_tool → backup --dry-run: counts what would be copied or linked
+    87 directories  → Example 5 shows what RemoveAll is about to delete
+
+RUN:
+    cd go_projects/174_fileops
+    go run .                         → guided demo
+    go run . rm --dry-run PATH...    → see what would go
+    go run . rm -i [-root DIR] PATH... → ask before each one
+    cd ../pkg && go test -v ./fileops ./prompt
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// rm is "rm -r" with --dry-run and -i: the smallest useful example of
+// threading the flags into SafeRemoveAll.
+func rm(args []string) error {
+	fs := flag.NewFlagSet("rm", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print what would be removed, remove nothing")
+	interactive := fs.Bool("i", false, "ask before removing each path")
+	root := fs.String("root", ".", "refuse to remove anything outside this directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: rm [--dry-run] [-i] [-root DIR] PATH...")
+	}
+	opts := fileops.SafeOptions{Ops: fileops.Ops{DryRun: *dryRun, Log: os.Stdout}, Root: *root}
+	if *interactive {
+		opts.Confirm = prompt.New(os.Stdin, os.Stdout).YesNo
+	}
+	for _, p := range fs.Args() {
+		if err := fileops.SafeRemoveAll(p, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot records every path under root with its size and mtime, so a
+// dry run can be checked for side effects.
+func snapshot(root string) map[string]string {
+	snap := map[string]string{}
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		info, _ := d.Info()
+		snap[filepath.ToSlash(rel)] = fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return snap
+}
+
+func listing(root string) string {
+	var names []string
+	for _, p := range slices.Sorted(maps.Keys(snapshot(root))) {
+		if p != "." {
+			names = append(names, p)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// clean is a typical tidy-up job written once against Ops.
+func clean(ops fileops.Ops, dir string) error {
+	if err := fileops.SafeRemoveAll(filepath.Join(dir, "build"), fileops.SafeOptions{Ops: ops, Root: dir}); err != nil {
+		return err
+	}
+	tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	for _, t := range tmps {
+		if err := ops.Remove(t); err != nil {
+			return err
+		}
+	}
+	if err := ops.MkdirAll(filepath.Join(dir, "archive"), 0o755); err != nil {
+		return err
+	}
+	if err := ops.Rename(filepath.Join(dir, "notes.txt"), filepath.Join(dir, "archive", "notes.txt")); err != nil {
+		return err
+	}
+	return ops.WriteFile(filepath.Join(dir, "archive", "README"), []byte("old notes\n"), 0o644)
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "rm":
+			err = rm(os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command %q (want rm)", os.Args[1])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, os.Args[1]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: DRY-RUN MODE FOR FILESYSTEM CHANGES")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "demo-fileops-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	for name, size := range map[string]int{
+		"build/app": 2048, "build/obj/a.o": 512, "build/obj/b.o": 512,
+		"cache.tmp": 100, "notes.txt": 40, "main.go": 300,
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		os.WriteFile(p, make([]byte, size), 0o644)
+	}
+	short := strings.NewReplacer(dir+string(filepath.Separator), "")
+
+	fmt.Println("--- Example 1: The Same Job, Dry Run First ---")
+	fmt.Println("  tree:", listing(dir))
+	before := snapshot(dir)
+	var plan strings.Builder
+	err = clean(fileops.Ops{DryRun: true, Log: &plan}, dir)
+	for l := range strings.Lines(short.Replace(plan.String())) {
+		fmt.Print("    ", l)
+	}
+	check(err == nil && maps.Equal(before, snapshot(dir)),
+		"dry run left every file, size and mtime untouched",
+		fmt.Sprintf("dry run changed the tree (err=%v)", err))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Now For Real ---")
+	var done strings.Builder
+	err = clean(fileops.Ops{Log: &done}, dir)
+	check(err == nil && short.Replace(done.String()) == strings.ReplaceAll(short.Replace(plan.String()), "would ", ""),
+		"the real run did exactly what the dry run promised",
+		fmt.Sprintf("real run differs from the plan (err=%v):\n%s", err, done.String()))
+	fmt.Println("  tree:", listing(dir))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Dry Runs Still Catch Errors ---")
+	// A plan that would fail halfway is worth knowing about before anything
+	// is deleted: the dry run reports the same error the real run would hit.
+	err = fileops.Ops{DryRun: true}.Remove(filepath.Join(dir, "cache.tmp"))
+	check(errors.Is(err, fs.ErrNotExist), "second Remove of cache.tmp: "+short.Replace(err.Error()), "expected a not-exist error")
+	err = fileops.Ops{DryRun: true}.Rename(filepath.Join(dir, "notes.txt"), filepath.Join(dir, "x"))
+	check(errors.Is(err, fs.ErrNotExist), "second Rename of notes.txt: "+short.Replace(err.Error()), "expected a not-exist error")
+	err = fileops.Ops{DryRun: true}.RemoveAll(filepath.Join(dir, "build"))
+	check(err == nil, "RemoveAll of a missing path is fine, as with os.RemoveAll", fmt.Sprint(err))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Threading the Flag ---")
+	fmt.Println("  flag → Ops → every change; nothing else needs to know:")
+	var out strings.Builder
+	fileops.Ops{DryRun: true, Log: &out}.RemoveAll(filepath.Join(dir, "archive"))
+	fmt.Print("    $ go run . rm --dry-run archive\n    ", short.Replace(out.String()))
+	fmt.Println("  same flag elsewhere in the course:")
+	fmt.Println("    155_daemon:      go run . daemon --dry-run --foreground")
+	fmt.Println("    154_backup_tool: go run 154_backup_tool.go backup --dry-run SRC DEST")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Safety Rails ---")
+	home, _ := os.UserHomeDir()
+	outside, _ := os.MkdirTemp("", "demo-fileops-outside-*")
+	defer os.RemoveAll(outside) // Straight from MkdirTemp: the one safe raw call
+	os.WriteFile(filepath.Join(outside, "x"), []byte("not yours"), 0o644)
+	os.Symlink(outside, filepath.Join(dir, "escape"))
+	os.MkdirAll(filepath.Join(dir, "scratch", "a"), 0o755)
+	rails := fileops.SafeOptions{Root: dir}
+	for _, tc := range []struct{ label, path string }{
+		{`""  (an unset variable)`, ""},
+		{`"/"`, "/"},
+		{"home directory", home},
+		{"the root itself", dir},
+		{"another temp dir", outside},
+		{"escape/x via a symlink", filepath.Join(dir, "escape", "x")},
+		{"../ out of the root", filepath.Join(dir, "..", filepath.Base(outside))},
+	} {
+		err := fileops.SafeRemoveAll(tc.path, rails)
+		check(err != nil, fmt.Sprintf("%-24s refused: %v", tc.label, errors.Unwrap(err)), tc.label+" was NOT refused")
+	}
+	_, err = os.Stat(filepath.Join(outside, "x"))
+	check(err == nil, "nothing outside the root was touched", "the outside directory is gone!")
+	fmt.Println()
+
+	fmt.Println("  with a confirmation prompt (answers scripted here, typed in real use):")
+	for _, answer := range []string{"\n", "yes\n"} {
+		var out strings.Builder
+		rails.Confirm = prompt.New(strings.NewReader(answer), &out).YesNo
+		err := fileops.SafeRemoveAll(filepath.Join(dir, "scratch"), rails)
+		fmt.Printf("    %s%q → err=%v\n", short.Replace(out.String()), strings.TrimSpace(answer), err)
+	}
+	_, err = os.Stat(filepath.Join(dir, "scratch"))
+	check(errors.Is(err, fs.ErrNotExist), "scratch removed only after \"yes\"", "scratch should be gone")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Route every filesystem change through one type; the dry run lives there.
+2. Keep reads direct so the dry run plans against the real tree.
+3. Say how much is at stake: "remove -r build (3 files, 3072 B)".
+4. A dry run should fail where the real run would fail.
+5. Test it: snapshot the tree, dry-run, compare sizes and mtimes.
+6. Computed paths get rails: inside an allowed root, never "/" or home.
+7. Resolve symlinks before deciding; default every prompt to NO.
+	`)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+/*
+TOPIC: STRUCTURED EXIT CODES — A CONTRACT FOR SCRIPTS AND CI
+
+CONCEPT:
+A CLI has two audiences. People read the message; scripts read one number,
+$?. If every failure is "exit 1", a CI job can't tell "the lesson is broken"
+from "the runner was called wrong" from "the disk is full", and all it can do
+is go red. A small, documented set of codes lets it branch:
+
+    gotut verify 83 || case $? in
+        2) echo "fix the pipeline config" ;;
+        3) echo "lesson 83 is broken" ;;
+        *) echo "infrastructure problem, retry" ;;
+    esac
+
+The contract (gotut help prints it):
+    0  ok
+    1  runtime error          I/O, timeout, a bug in gotut
+    2  usage error            bad flags or arguments, unknown topic
+    3  verification failure   a lesson didn't build or exited non-zero
+    4  exercise failure       the tests ran and failed
+
+THE DESIGN, in the course's CLI (go_projects/gotut): errors carry a
+fine-grained CODE from where they happen; ONE table maps codes to exit
+statuses; main is the only place that calls os.Exit. Nothing in between
+needs to know.
+
+    gotut/apperrors.go → Code, *Error, E(), CodeOf — the codes errors carry;
+                         M() for messages in the user's language
+    gotut/exit.go      → the contract, ExitCode(err), report()
+    gotut/cli.go       → CLI.Run recovers panics as CodeInternal
+    gotut/main_test.go → builds the binary and checks $? for every outcome
+
+This lesson builds gotut and drives it the way a script does: run a
+command, read $?.
+
+RUN:
+    cd go_projects && go run 175_exitcodes.go
+    cd gotut && go build -o gotut . && ./gotut -dir .. verify 153 175; echo $?
+"go run" itself exits 1 whatever the program's status ("exit status 2"),
+so build the binary to see the real $?.
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// course is a tiny course with one lesson for each outcome. Like the
+// real one, it is a module: go test needs one.
+var course = map[string]string{
+	"go.mod":        "module example.com/course\n\ngo 1.25\n",
+	"001_hello.go":  "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"hello\") }\n",
+	"002_broken.go": "package main\n\nfunc main() { undefined() }\n",
+	"003_sleeps.go": "package main\n\nimport \"time\"\n\nfunc main() { time.Sleep(time.Minute) }\n",
+	"004_panics.go": "package main\n\nfunc main() { panic(\"boom\") }\n",
+	"005_sum/sum.go": "package main\n\nfunc Sum(xs ...int) int { return len(xs) } // The exercise: fix me\n\n" +
+		"func main() {}\n",
+	"005_sum/sum_test.go": "package main\n\nimport \"testing\"\n\n" +
+		"func TestSum(t *testing.T) {\n\tif got := Sum(2, 3); got != 5 {\n\t\tt.Errorf(\"Sum(2, 3) = %d, want 5\", got)\n\t}\n}\n",
+}
+
+func writeCourse(dir string) error {
+	for name, src := range course {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// status runs bin with args and returns $? and the first line of stderr,
+// with the course directory shortened to $COURSE.
+func status(dir, bin string, args ...string) (int, string, error) {
+	var stderr strings.Builder
+	cmd := exec.Command(bin, args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C") // English, whatever the reader's locale
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return 0, "", err
+	}
+	first, _, _ := strings.Cut(strings.ReplaceAll(stderr.String(), dir, "$COURSE"), "\n")
+	return cmd.ProcessState.ExitCode(), first, nil
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: STRUCTURED EXIT CODES")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	tmp, err := os.MkdirTemp("", "demo-exitcodes-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	dir, gotut := filepath.Join(tmp, "course"), filepath.Join(tmp, "gotut")
+	if err := writeCourse(dir); err != nil {
+		return err
+	}
+	build := exec.Command("go", "build", "-o", gotut, ".")
+	build.Dir = "gotut" // Its own module: built from inside it
+	if out, err := build.CombinedOutput(); err != nil {
+		return fmt.Errorf("building gotut (run this from go_projects): %w\n%s", err, out)
+	}
+
+	fmt.Println("--- Example 1: The Contract ---")
+	out, err := exec.Command(gotut, "help").Output()
+	if err != nil {
+		return err
+	}
+	_, table, ok := strings.Cut(string(out), "Exit status:\n")
+	check(ok, "gotut help ends with the table scripts rely on:", "gotut help has no Exit status section")
+	for l := range strings.Lines(table) {
+		fmt.Print("  ", l)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Every Outcome, Through the Real Commands ---")
+	for _, tc := range []struct {
+		args string
+		want int
+	}{
+		{"verify 1", 0},
+		{"run -timeout 1s 3", 1},
+		{"run -timeout soon 1", 2},
+		{"verify 42", 2},
+		{"launch 1", 2},
+		{"verify 1 2", 3},
+		{"test 5", 4},
+		{"verify 2 5", 3}, // 5 runs fine; its tests aren't verify's business
+	} {
+		got, first, err := status(dir, gotut, append([]string{"-dir", dir}, strings.Fields(tc.args)...)...)
+		if err != nil {
+			return err
+		}
+		check(got == tc.want, fmt.Sprintf("gotut %-20s → %d  %s", tc.args, got, first),
+			fmt.Sprintf("gotut %s → %d, want %d: %s", tc.args, got, tc.want, first))
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: A Panic Would Say \"Usage Error\" ---")
+	// The Go runtime exits 2 on an unrecovered panic — the usage code. A
+	// script would tell the user to fix their command line. gotut's Run
+	// recovers, so its own bugs are 1; a lesson's panic is that lesson
+	// failing, 3.
+	panics := filepath.Join(tmp, "panics")
+	build = exec.Command("go", "build", "-o", panics, "004_panics.go")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		return fmt.Errorf("building 004_panics.go: %w\n%s", err, out)
+	}
+	raw, first, err := status(dir, panics)
+	if err != nil {
+		return err
+	}
+	check(raw == 2, fmt.Sprintf("./panics           → %d  %s", raw, first),
+		fmt.Sprintf("./panics → %d, want the runtime's 2", raw))
+	got, first, err := status(dir, gotut, "-dir", dir, "run", "4")
+	if err != nil {
+		return err
+	}
+	check(got == 3, fmt.Sprintf("gotut run 4        → %d  %s", got, first),
+		fmt.Sprintf("gotut run 4 → %d, want 3", got))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: The Message Is Translated, the Code Is Not ---")
+	// Usage errors carry a catalog key (apperrors.M), so report can say
+	// them in -lang's language, or the locale's. Scripts read $?, which
+	// doesn't change.
+	for _, lang := range []string{"en", "es", "fr-CA", "pt-BR"} {
+		got, first, err := status(dir, gotut, "-dir", dir, "-lang", lang, "verify", "42")
+		if err != nil {
+			return err
+		}
+		check(got == 2, fmt.Sprintf("-lang %-5s → %d  %s", lang, got, first),
+			fmt.Sprintf("-lang %s → %d, want 2", lang, got))
+	}
+	fmt.Println("  pt-BR has no catalog: English.")
+
+	fmt.Println(`
+QUICK REFERENCE
+  E(code, op, err)                    an *Error carrying code (gotut/apperrors.go)
+  M(code, op, key, args...)           the same, with a message catalog key
+  CodeOf(err)                         the outermost code in err's chain
+  ExitCode(err)                       code → exit status, the one table (gotut/exit.go)
+  os.Exit(report(stderr, lang, err))  main, the only caller of os.Exit
+  exec.ExitError.ExitCode()           $? from a test or a script's side`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Exit codes are an API: few, documented, and never renumbered.
+2. Classify failures where they happen; map codes to statuses in ONE table.
+3. Call os.Exit in main only; everything else returns an error.
+4. Recover panics at the top: the runtime's exit status 2 means "usage".
+5. When several things fail, exit with the most specific code.
+6. Test the contract on the built binary: $? is what scripts see.
+7. Translate what people read (Topic 189); never the exit code scripts read.
+	`)
+	return nil
+}
+
+func main() {
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,787 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/a11y"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/course"
+)
+
+/*
+TOPIC: MACHINE-READABLE RUN OUTPUT — LESSONS AS A STREAM OF JSON EVENTS
+
+CONCEPT:
+Prose is for people. A web UI that puts each example's code next to its
+output, a grader that checks what Example 3 printed, an editor that jumps
+to the line that produced a failure — all of them would have to scrape
+the text. Give them events instead, one JSON object per line (JSON Lines,
+as 140 and 172 write spans):
+
+    $ go run 176_run_events.go run 153 --format json
+    {"v":1,"seq":1,"type":"start","lesson":"153_crc32_checksums.go",...}
+    {"v":1,"seq":2,"type":"output","stream":"stdout","text":"═══..."}
+    {"v":1,"seq":6,"type":"section","section":1,"title":"One Input, Two Tables"}
+    {"v":1,"seq":7,"type":"code","section":1,"line":101,"code":"fmt.Println(..."}
+    {"v":1,"seq":8,"type":"output","section":1,"stream":"stdout","text":"  IEEE: ..."}
+    ...
+    {"v":1,"seq":64,"type":"summary","exit_code":0,"duration_ms":212.4,...}
+
+EVENTS:
+    start    the lesson and its arguments
+    section  a section began: "--- Example N: Title ---" (172's markers),
+             or the older "=== EXAMPLE N: Title ===" of lessons like 85
+    code     that section's source, found with go/ast (158): the whole
+             function when the marker opens one, otherwise the statements
+             from this marker to the next
+    output   one line, live, tagged stdout, stderr or build
+    summary  exit code, duration, and lines and time per section — always
+             the last event, even when the lesson didn't build
+
+THE RULES A CONSUMER CAN RELY ON:
+  • Every line on stdout is one event; nothing else is ever printed there.
+  • "v" is the schema version. New fields may appear; existing ones keep
+    their meaning until v changes. Empty fields are left out.
+  • "seq" counts from 1 with no gaps, so a lost line is detectable.
+  • Events are written as they happen, not at the end: a UI can show a
+    slow lesson's output while it runs.
+  • The exit status is 175's contract: 0 ok, 1 the runner failed, 2 usage,
+    3 the lesson didn't build or exited non-zero.
+
+RUN:
+    go run 176_run_events.go                              (demo)
+    go run 176_run_events.go run 85 --format json         (events)
+    go run 176_run_events.go run 153                      (plain text)
+    go run 176_run_events.go run 124 --format json -- -section 2
+    go run 176_run_events.go run 153 --accessible         (for a screen reader, Topic 190)
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Event Schema
+// ---------------------------------------------------------
+
+// SchemaVersion is bumped only when an existing field changes meaning.
+const SchemaVersion = 1
+
+const (
+	EventStart   = "start"
+	EventSection = "section"
+	EventCode    = "code"
+	EventOutput  = "output"
+	EventSummary = "summary"
+)
+
+// Event is every event type in one flat struct. Fields not used by a
+// type are empty and omitted, so each line carries only what it means.
+type Event struct {
+	V       int       `json:"v"`
+	Seq     int       `json:"seq"`
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Section int       `json:"section,omitempty"` // Example number; 0 before the first section
+	Title   string    `json:"title,omitempty"`
+
+	Lesson string   `json:"lesson,omitempty"` // start
+	Args   []string `json:"args,omitempty"`
+
+	Func string `json:"func,omitempty"` // code: the enclosing function, the first line in the lesson file
+	Line int    `json:"line,omitempty"`
+	Code string `json:"code,omitempty"`
+
+	Stream string `json:"stream,omitempty"` // output: "stdout", "stderr" or "build"
+	Text   string `json:"text,omitempty"`   // Without its newline; omitted for an empty line
+
+	ExitCode   *int          `json:"exit_code,omitempty"` // summary
+	Failed     string        `json:"failed,omitempty"`    // "build" or "run"; empty on success
+	Error      string        `json:"error,omitempty"`     // The runner's own failure, if any
+	DurationMS float64       `json:"duration_ms,omitempty"`
+	Lines      int           `json:"lines,omitempty"`
+	Sections   []SectionStat `json:"sections,omitempty"`
+}
+
+type SectionStat struct {
+	Section    int     `json:"section,omitempty"`
+	Title      string  `json:"title"`
+	DurationMS float64 `json:"duration_ms"`
+	Lines      int     `json:"lines"`
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Round(100*time.Microsecond)) / float64(time.Millisecond)
+}
+
+// ---------------------------------------------------------
+// Part 2: The Emitter
+// ---------------------------------------------------------
+
+// Emitter numbers, stamps and writes events, one JSON line each. stdout
+// and stderr are copied by two goroutines, so it locks.
+type Emitter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+	seq int
+	err error // The first write error; later events are dropped
+}
+
+// NewEmitter writes to w. now is the clock; nil means time.Now.
+func NewEmitter(w io.Writer, now func() time.Time) *Emitter {
+	if now == nil {
+		now = time.Now
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false) // Code is full of < and &; keep it readable
+	return &Emitter{enc: enc, now: now}
+}
+
+func (e *Emitter) Emit(ev Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	e.seq++
+	ev.V, ev.Seq, ev.Time = SchemaVersion, e.seq, e.now()
+	e.err = e.enc.Encode(ev)
+}
+
+// Err reports whether any event failed to write — a UI that went away.
+func (e *Emitter) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// ---------------------------------------------------------
+// Part 3: Code Blocks From the Lesson's Source
+// ---------------------------------------------------------
+
+var (
+	marker = regexp.MustCompile(`^\s*(?:---|===) (?i:example (\d+): )?(.+?) (?:---|===)\s*$`)
+	rule   = regexp.MustCompile(`^\s*(═{10,}|={10,})\s*$`)
+)
+
+// Block is the source behind one section.
+type Block struct {
+	Func string
+	Line int
+	Code string
+}
+
+// markerIn returns the section title a statement prints, if it is a call
+// with a string literal holding a marker line; rule reports a banner.
+func markerIn(stmt ast.Stmt) (title string, isRule bool) {
+	es, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return "", false
+	}
+	call, ok := es.X.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	for _, arg := range call.Args {
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			continue
+		}
+		for line := range strings.Lines(s) {
+			if m := marker.FindStringSubmatch(line); m != nil {
+				return m[2], false
+			}
+			if rule.MatchString(line) {
+				isRule = true
+			}
+		}
+	}
+	return "", isRule
+}
+
+// CodeBlocks finds the source of every section in a lesson, by title.
+// A marker that opens a function claims the whole function (the
+// function-shaped lessons); one in the middle of main claims the
+// statements up to the next marker or banner (the inline lessons).
+func CodeBlocks(path string, src []byte) (map[string]Block, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	blocks := map[string]Block{}
+	text := func(fn string, from, to token.Pos) Block {
+		start, end := fset.Position(from).Offset, fset.Position(to).Offset
+		start = bytes.LastIndexByte(src[:start], '\n') + 1 // Back to the line start, for dedent
+		return Block{Func: fn, Line: fset.Position(from).Line, Code: dedent(string(src[start:end]))}
+	}
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			b, ok := n.(*ast.BlockStmt)
+			if !ok {
+				return true
+			}
+			for i, stmt := range b.List {
+				title, _ := markerIn(stmt)
+				if title == "" || blocks[title].Code != "" {
+					continue
+				}
+				if b == fd.Body && i == 0 {
+					blocks[title] = text(fd.Name.Name, fd.Pos(), fd.End())
+					continue
+				}
+				end := b.List[len(b.List)-1].End()
+				for _, next := range b.List[i+1:] {
+					if t, isRule := markerIn(next); t != "" || isRule {
+						break
+					}
+					end = next.End()
+				}
+				blocks[title] = text(fd.Name.Name, stmt.Pos(), end)
+			}
+			return true
+		})
+	}
+	return blocks, nil
+}
+
+// dedent removes the indentation every non-blank line shares.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	prefix, first := "", true
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if first {
+			prefix, first = indent, false
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for i, l := range lines {
+		lines[i] = strings.TrimPrefix(l, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ---------------------------------------------------------
+// Part 4: From Output Lines to Events
+// ---------------------------------------------------------
+
+// Run turns one lesson's output into events as it arrives. Writer gives
+// an io.Writer per stream to hang on the command.
+type Run struct {
+	emit   *Emitter
+	now    func() time.Time
+	blocks map[string]Block
+
+	mu       sync.Mutex // Both streams write through line()
+	start    time.Time
+	section  int // The current section's number; 0 outside one
+	numbered int // The last number seen, for markers without one
+	stats    []SectionStat
+	secStart time.Time
+	lines    int
+}
+
+func NewRun(emit *Emitter, lesson string, args []string, blocks map[string]Block) *Run {
+	r := &Run{emit: emit, now: emit.now, blocks: blocks}
+	r.start = r.now()
+	emit.Emit(Event{Type: EventStart, Lesson: lesson, Args: args})
+	return r
+}
+
+type lineWriter struct {
+	run     *Run
+	stream  string
+	partial []byte
+}
+
+// Writer returns the io.Writer for one stream. Lines are split here, not
+// by write: a pipe read can end in the middle of a line, or a marker.
+func (r *Run) Writer(stream string) io.Writer { return &lineWriter{run: r, stream: stream} }
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.run.line(w.stream, strings.TrimRight(string(w.partial[:i]), "\r"))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), w.run.emit.Err()
+}
+
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.run.line(w.stream, string(w.partial)) // A last line with no newline still counts
+		w.partial = nil
+	}
+}
+
+func (r *Run) line(stream, text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stream == "stdout" {
+		switch m := marker.FindStringSubmatch(text); {
+		case m != nil:
+			r.numbered++
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				r.numbered = n
+			}
+			r.next(r.numbered, m[2])
+		case rule.MatchString(text) && r.section > 0:
+			r.next(0, "takeaways") // The closing banner, as in 172
+		}
+	}
+	if len(r.stats) > 0 {
+		r.stats[len(r.stats)-1].Lines++
+	}
+	r.lines++
+	r.emit.Emit(Event{Type: EventOutput, Section: r.section, Stream: stream, Text: text})
+}
+
+// next closes the current section's stats and announces the new one,
+// with its code when the source has it.
+func (r *Run) next(section int, title string) {
+	now := r.now()
+	if len(r.stats) > 0 {
+		r.stats[len(r.stats)-1].DurationMS = ms(now.Sub(r.secStart))
+	}
+	r.section, r.secStart = section, now
+	r.stats = append(r.stats, SectionStat{Section: section, Title: title})
+	r.emit.Emit(Event{Type: EventSection, Section: section, Title: title})
+	if b, ok := r.blocks[title]; ok {
+		r.emit.Emit(Event{Type: EventCode, Section: section, Func: b.Func, Line: b.Line, Code: b.Code})
+	}
+}
+
+// Close flushes the writers' last partial lines and emits the summary.
+// failed is "build", "run" or ""; runErr is the runner's own failure.
+func (r *Run) Close(writers []io.Writer, exitCode int, failed string, runErr error) {
+	for _, w := range writers {
+		w.(*lineWriter).flush()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if len(r.stats) > 0 {
+		r.stats[len(r.stats)-1].DurationMS = ms(now.Sub(r.secStart))
+	}
+	ev := Event{Type: EventSummary, ExitCode: &exitCode, Failed: failed,
+		DurationMS: ms(now.Sub(r.start)), Lines: r.lines, Sections: r.stats}
+	if runErr != nil {
+		ev.Error = runErr.Error()
+	}
+	r.emit.Emit(ev)
+}
+
+// ---------------------------------------------------------
+// Part 5: Running a Lesson
+// ---------------------------------------------------------
+
+// asMain returns src with its package clause changed to main (see 173):
+// most of 59–84 are "package intermediate".
+func asMain(src []byte) ([]byte, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+	if err != nil {
+		return nil, err
+	}
+	start, end := int(f.Name.Pos())-1, int(f.Name.End())-1 // Pos is 1-based
+	return slices.Concat(src[:start], []byte("main"), src[end:]), nil
+}
+
+// Exit statuses, from 175's contract.
+const (
+	exitOK      = 0
+	exitRuntime = 1
+	exitUsage   = 2
+	exitLesson  = 3
+)
+
+// RunEvents builds the lesson and runs it with its working directory
+// next to the lesson file, as "go run" from there would. It builds first
+// rather than using "go run", which reports every failure as exit
+// status 1. The summary is always emitted; the returned status follows
+// the contract above.
+func RunEvents(path string, args []string, emit *Emitter) int {
+	run := NewRun(emit, filepath.Base(path), args, nil)
+	build := run.Writer("build")
+	fail := func(status int, failed string, err error) int {
+		run.Close([]io.Writer{build}, status, failed, err)
+		return status
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fail(exitRuntime, "", err)
+	}
+	if run.blocks, err = CodeBlocks(path, src); err != nil {
+		run.blocks = nil // A file that doesn't parse won't build either; let the build say why
+	}
+	tmp, err := os.MkdirTemp("", "run-events-")
+	if err != nil {
+		return fail(exitRuntime, "", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// Build from the lesson's own directory, as 173 does, so //go:embed
+	// and go.work resolve; only a renamed copy is built from tmp.
+	bin := filepath.Join(tmp, "lesson")
+	cmd := exec.Command("go", "build", "-o", bin, filepath.Base(path))
+	cmd.Dir = filepath.Dir(path)
+	if main, err := asMain(src); err == nil && !bytes.Equal(main, src) {
+		if err := os.WriteFile(filepath.Join(tmp, filepath.Base(path)), main, 0o644); err != nil {
+			return fail(exitRuntime, "", err)
+		}
+		cmd.Dir = tmp
+	}
+	cmd.Stdout, cmd.Stderr = build, build
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fail(exitRuntime, "", err)
+		}
+		return fail(exitLesson, "build", nil)
+	}
+
+	stdout, stderr := run.Writer("stdout"), run.Writer("stderr")
+	cmd = exec.Command(bin, args...)
+	cmd.Dir = filepath.Dir(path)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			run.Close([]io.Writer{stdout, stderr}, exitRuntime, "", err)
+			return exitRuntime
+		}
+		// The lesson's own exit code goes in the summary; the runner's
+		// status says only "the lesson failed".
+		run.Close([]io.Writer{stdout, stderr}, exitErr.ExitCode(), "run", nil)
+		return exitLesson
+	}
+	run.Close([]io.Writer{stdout, stderr}, 0, "", nil)
+	return exitOK
+}
+
+// cmdRun parses "run TOPIC [--format text|json] [--accessible] [-- lesson
+// args]". The flags may come before or after the topic: "run 85 --format
+// json" is how people type it.
+func cmdRun(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	format := fs.String("format", "text", "text (the lesson as usual) or json (one event per line)")
+	accessible := fs.Bool("accessible", false, "text for screen readers (also $GOTUT_ACCESSIBLE, or config.json); json is unchanged")
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: run TOPIC [--format text|json] [--accessible] [-- lesson flags]")
+		return exitUsage
+	}
+	topicArg := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil { // Flags after the topic
+		return exitUsage
+	}
+	topic, err := strconv.Atoi(topicArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run: topic %q is not a number\n", topicArg)
+		return exitUsage
+	}
+	path, err := course.File(topic)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "run:", err)
+		return exitUsage
+	}
+
+	switch *format {
+	case "json":
+		return RunEvents(path, fs.Args(), NewEmitter(os.Stdout, nil))
+	case "text":
+		on, err := a11y.Enabled(*accessible)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "run:", err)
+			return exitUsage
+		}
+		cmd := exec.Command("go", append([]string{"run", filepath.Base(path)}, fs.Args()...)...)
+		cmd.Dir = filepath.Dir(path)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if on {
+			// One Writer for both streams, so a ✗ on stderr is still "Fail:".
+			w := a11y.NewWriter(os.Stdout)
+			defer w.Flush()
+			cmd.Stdout, cmd.Stderr = w, w
+		}
+		if err := cmd.Run(); err != nil {
+			return exitLesson
+		}
+		return exitOK
+	}
+	fmt.Fprintf(os.Stderr, "run: unknown format %q (want text or json)\n", *format)
+	return exitUsage
+}
+
+// ---------------------------------------------------------
+// Part 6: Demo
+// ---------------------------------------------------------
+
+// fakeClock moves only when told to, so the scripted run below prints
+// the same events every time.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func check(ok bool, pass, fail string) string {
+	if ok {
+		return "✓ " + pass
+	}
+	return "✗ " + fail
+}
+
+// sampleLesson is the source behind the scripted run in Example 1.
+const sampleLesson = `package main
+
+import "fmt"
+
+func generator() {
+	fmt.Println("--- Example 1: Generator ---")
+	for i := 1; i <= 3; i++ {
+		fmt.Print(i, " ")
+	}
+	fmt.Println()
+}
+
+func main() {
+	generator()
+	fmt.Println("--- Example 2: Fan-Out ---")
+	fmt.Println("worker 1: 4")
+}
+`
+
+// decode reads a JSON Lines stream back, as a consumer would.
+func decode(r io.Reader) ([]Event, error) {
+	var events []Event
+	dec := json.NewDecoder(r)
+	for {
+		var ev Event
+		if err := dec.Decode(&ev); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return events, err
+		}
+		events = append(events, ev)
+	}
+}
+
+func short(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: MACHINE-READABLE RUN OUTPUT")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: A Scripted Run as Events ---")
+	// What the runner sees: chunks on two pipes, arriving over time. The
+	// first stdout chunk ends mid-marker, as a real pipe read can.
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)}
+	var stream bytes.Buffer
+	emit := NewEmitter(&stream, clock.now)
+	blocks, err := CodeBlocks("sample.go", []byte(sampleLesson))
+	if err != nil {
+		return err
+	}
+	run := NewRun(emit, "999_pipelines.go", nil, blocks)
+	stdout, stderr := run.Writer("stdout"), run.Writer("stderr")
+	script := []struct {
+		after time.Duration
+		w     io.Writer
+		text  string
+	}{
+		{300 * time.Millisecond, stdout, "TOPIC: PIPELINES\n\n--- Example 1: Gen"},
+		{0, stdout, "erator ---\n1 2 3 \n"},
+		{2 * time.Millisecond, stderr, "warning: GOMAXPROCS=1\n"},
+		{1500 * time.Millisecond, stdout, "--- Example 2: Fan-Out ---\nworker 1: 4\n"},
+	}
+	var sent strings.Builder
+	for _, step := range script {
+		clock.advance(step.after)
+		step.w.Write([]byte(step.text))
+		if step.w == stdout {
+			sent.WriteString(step.text)
+		}
+	}
+	clock.advance(time.Millisecond)
+	run.Close([]io.Writer{stdout, stderr}, 0, "", nil)
+	for line := range strings.Lines(stream.String()) {
+		line = strings.Replace(strings.TrimSpace(line), `"v":1,`, "", 1)
+		line = regexp.MustCompile(`"time":"[^"]+",`).ReplaceAllString(line, "")
+		fmt.Println("  " + short(line, 110))
+	}
+	fmt.Println("  (\"v\":1 and \"time\" left out above to fit)")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Consuming the Stream ---")
+	events, err := decode(bytes.NewReader(stream.Bytes()))
+	if err != nil {
+		return err
+	}
+	gapless := true
+	for i, ev := range events {
+		gapless = gapless && ev.Seq == i+1
+	}
+	fmt.Println(" ", check(gapless && events[0].Type == EventStart && events[len(events)-1].Type == EventSummary,
+		fmt.Sprintf("%d events: start first, summary last, seq 1..%d with no gaps", len(events), len(events)),
+		"the stream is out of shape"))
+	// A UI rebuilding the terminal from events must get the same text.
+	var rebuilt strings.Builder
+	for _, ev := range events {
+		if ev.Type == EventOutput && ev.Stream == "stdout" {
+			rebuilt.WriteString(ev.Text + "\n")
+		}
+	}
+	fmt.Println(" ", check(rebuilt.String() == sent.String(),
+		"stdout rebuilt from output events matches what the lesson printed",
+		fmt.Sprintf("rebuilt %q, sent %q", rebuilt.String(), sent.String())))
+	var code1 Event
+	for _, ev := range events {
+		if ev.Type == EventCode && ev.Section == 1 {
+			code1 = ev
+		}
+	}
+	fmt.Println(" ", check(code1.Func == "generator" && strings.HasPrefix(code1.Code, "func generator()"),
+		"section 1's code is the whole generator function (its marker opens it)",
+		fmt.Sprintf("section 1's code came from %q", code1.Func)))
+	sum := events[len(events)-1]
+	fmt.Println(" ", check(len(sum.Sections) == 2 && sum.Sections[0].DurationMS == 1502,
+		"summary: Generator took 1502ms, up to the next marker",
+		fmt.Sprintf("summary sections: %+v", sum.Sections)))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Code Blocks From Real Lessons ---")
+	for _, topic := range []int{124, 153} {
+		path, err := course.File(topic)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		blocks, err := CodeBlocks(path, src)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %s\n", filepath.Base(path))
+		for _, title := range slices.Sorted(maps.Keys(blocks)) {
+			b := blocks[title]
+			fmt.Printf("    line %-4d %-22s %3d lines  %s\n", b.Line, b.Func, strings.Count(b.Code, "\n")+1, short(title, 40))
+		}
+	}
+	fmt.Println("  (124 is function-shaped: whole functions; 153 is inline: slices of main)")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Real Lesson (153) ---")
+	path, err := course.File(153)
+	if err != nil {
+		return err
+	}
+	stream.Reset()
+	status := RunEvents(path, nil, NewEmitter(&stream, nil))
+	events, err = decode(&stream)
+	if err != nil {
+		return err
+	}
+	counts := map[string]int{}
+	for _, ev := range events {
+		counts[ev.Type]++
+	}
+	fmt.Printf("  $ run 153 --format json → %d events: %d section, %d code, %d output, %d summary\n",
+		len(events), counts[EventSection], counts[EventCode], counts[EventOutput], counts[EventSummary])
+	sum = events[len(events)-1]
+	fmt.Println(" ", check(status == exitOK && *sum.ExitCode == 0 && counts[EventCode] == 6,
+		"exit 0, and every one of the six examples came with its code",
+		fmt.Sprintf("status %d, %d code events", status, counts[EventCode])))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: A Lesson That Doesn't Build (82) ---")
+	path, err = course.File(82)
+	if err != nil {
+		return err
+	}
+	stream.Reset()
+	status = RunEvents(path, nil, NewEmitter(&stream, nil))
+	events, err = decode(&stream)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		if ev.Type == EventOutput && ev.Stream == "build" && strings.Contains(ev.Text, ".go:") {
+			fmt.Println("  build:", short(ev.Text, 90))
+			break
+		}
+	}
+	sum = events[len(events)-1]
+	fmt.Println(" ", check(status == exitLesson && sum.Failed == "build",
+		fmt.Sprintf("summary still last: failed=%q, exit status %d", sum.Failed, status),
+		"82 built; its \"=\"*80 lines must have been fixed"))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. One JSON object per line, and nothing else on stdout.
+2. Version the schema; add fields freely, never change their meaning.
+3. Number events: a consumer can tell a gap from a quiet lesson.
+4. Stream as it happens; split on lines, not on writes.
+5. Always end with a summary, even when the build fails.
+6. Pair output with its source: go/ast finds the code behind each marker.
+	`)
+	return nil
+}
+
+func main() {
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "run":
+		os.Exit(cmdRun(os.Args[2:]))
+	case len(os.Args) > 1:
+		fmt.Fprintf(os.Stderr, "unknown command %q (try: run)\n", os.Args[1])
+		os.Exit(exitUsage)
+	}
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(exitRuntime)
+	}
+}
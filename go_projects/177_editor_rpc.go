@@ -0,0 +1,824 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+TOPIC: AN EDITOR ENDPOINT — JSON-RPC 2.0 OVER STDIO ("lsp-lite")
+
+CONCEPT:
+An editor extension wants a sidebar: the lessons, a ▶ button on each, and a
+tick next to the exercises that pass. It shouldn't reimplement any of that.
+It starts ONE process and talks to it over stdin/stdout, the way editors
+talk to language servers:
+
+    editor ──stdin──▶  go run 177_editor_rpc.go lsp-lite  ──▶ 158 manifest
+           ◀─stdout──         (this server)               ──▶ 176 run --format json
+                                                          ──▶ go test -json
+
+JSON-RPC 2.0 in four shapes:
+    request       {"jsonrpc":"2.0","id":1,"method":"topics/list","params":{...}}
+    response      {"jsonrpc":"2.0","id":1,"result":[...]}
+    error         {"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"..."}}
+    notification  {"jsonrpc":"2.0","method":"$/runEvent","params":{...}}  (no id,
+                  no answer)
+
+FRAMING: a pipe is a byte stream, so each message is preceded by its length,
+exactly as in the Language Server Protocol. The editor libraries that speak
+LSP (vscode-jsonrpc and friends) speak this out of the box:
+
+    Content-Length: 46\r\n
+    \r\n
+    {"jsonrpc":"2.0","id":1,"method":"initialize"}
+
+METHODS:
+    initialize        → server name, schema version, the methods below
+    topics/list       {query?}         → numbered topics from 158's manifest
+    topics/run        {topic, args?}   → streams 176's events as "$/runEvent"
+                                         notifications, answers with the summary
+    exercises/status  {topic?}         → pass / fail / error for every lesson
+                                         package with tests (go test -json)
+    shutdown, exit    → finish what's running, then stop
+    $/cancelRequest   {id}             → stop a running topics/run
+
+Nothing is reimplemented: the lessons are main packages, which nothing can
+import, so the server runs them as processes and passes their JSON through. Every
+request runs in its own goroutine, so a long run doesn't block a list;
+responses can come back in a different order, which JSON-RPC allows —
+the id says which is which.
+
+STDOUT IS THE PROTOCOL: one stray fmt.Println and the editor sees garbage.
+The server logs to stderr, and children's output reaches stdout only inside
+notifications.
+
+RUN:
+    go run 177_editor_rpc.go                  (demo: a scripted editor session)
+    go run 177_editor_rpc.go lsp-lite         (serve on stdin/stdout)
+*/
+
+// ---------------------------------------------------------
+// Part 1: Messages and Framing
+// ---------------------------------------------------------
+
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for a notification
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"` // "null" is a result; nil means error
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// RPCError is a JSON-RPC error object. Handlers return one to choose the
+// code; any other error becomes CodeInternalError.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string { return fmt.Sprintf("%s (%d)", e.Message, e.Code) }
+
+// The codes from the JSON-RPC spec, LSP's cancellation code, and one of
+// ours from the range the spec leaves to servers.
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeRequestCancelled = -32800
+	CodeUnknownTopic     = -32001
+)
+
+// readMessage reads one Content-Length framed message.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length < 0 {
+				return nil, io.EOF // Clean end between messages
+			}
+			return nil, fmt.Errorf("reading header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil || length < 0 {
+				return nil, fmt.Errorf("bad Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("message without Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	return body, nil
+}
+
+// writeMessage frames v. The caller serializes writers.
+func writeMessage(w io.Writer, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// ---------------------------------------------------------
+// Part 2: The Server Loop
+// ---------------------------------------------------------
+
+type handler func(ctx context.Context, id json.RawMessage, params json.RawMessage) (any, error)
+
+type Server struct {
+	Dir string    // go_projects: where 158, 176 and the lessons are
+	Log io.Writer // Never stdout
+
+	wmu sync.Mutex // One message at a time on the wire
+	out io.Writer
+
+	mu       sync.Mutex
+	running  map[string]context.CancelFunc // By request id, for $/cancelRequest
+	manifest []Topic
+	runner   string // 176, built once
+	tmp      string
+	wg       sync.WaitGroup
+	methods  map[string]handler
+}
+
+func NewServer(dir string, log io.Writer) *Server {
+	s := &Server{Dir: dir, Log: log, running: map[string]context.CancelFunc{}}
+	s.methods = map[string]handler{
+		"initialize":       s.initialize,
+		"topics/list":      s.listTopics,
+		"topics/run":       s.runTopic,
+		"exercises/status": s.exerciseStatus,
+	}
+	return s
+}
+
+func (s *Server) send(v any) {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	if err := writeMessage(s.out, v); err != nil {
+		fmt.Fprintln(s.Log, "write:", err)
+	}
+}
+
+func (s *Server) reply(id json.RawMessage, result any, err error) {
+	resp := Response{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+	} else if resp.Result, err = json.Marshal(result); err != nil {
+		resp.Error = &RPCError{Code: CodeInternalError, Message: err.Error()}
+	}
+	s.send(resp)
+}
+
+// Serve reads requests from r until "exit" or EOF, answering on w. It
+// waits for requests still running before it returns.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = w
+	in := bufio.NewReader(r)
+	defer func() {
+		s.wg.Wait()
+		if s.tmp != "" {
+			os.RemoveAll(s.tmp)
+		}
+	}()
+	for {
+		body, err := readMessage(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err // The stream is out of step; no way to find the next message
+		}
+		var req Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.reply(json.RawMessage("null"), nil, &RPCError{Code: CodeParseError, Message: err.Error()})
+			continue
+		}
+		switch {
+		case req.JSONRPC != "2.0" || req.Method == "":
+			if req.ID != nil {
+				s.reply(req.ID, nil, &RPCError{Code: CodeInvalidRequest, Message: `want "jsonrpc":"2.0" and a method`})
+			}
+		case req.Method == "shutdown" && req.ID != nil:
+			s.wg.Wait() // Answer once everything in flight has been answered
+			s.reply(req.ID, nil, nil)
+		case req.Method == "exit":
+			s.cancelAll()
+			return nil
+		case req.Method == "$/cancelRequest":
+			var p struct {
+				ID json.RawMessage `json:"id"`
+			}
+			if json.Unmarshal(req.Params, &p) == nil {
+				s.cancel(string(p.ID))
+			}
+		case req.ID == nil:
+			fmt.Fprintln(s.Log, "ignoring notification", req.Method) // Notifications never get an answer
+		default:
+			h, ok := s.methods[req.Method]
+			if !ok {
+				s.reply(req.ID, nil, &RPCError{Code: CodeMethodNotFound, Message: "no method " + req.Method})
+				continue
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			s.mu.Lock()
+			s.running[string(req.ID)] = cancel
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				result, err := h(ctx, req.ID, req.Params)
+				if ctx.Err() != nil && err != nil {
+					err = &RPCError{Code: CodeRequestCancelled, Message: "cancelled"}
+				}
+				s.cancel(string(req.ID))
+				s.reply(req.ID, result, err)
+			}()
+		}
+	}
+}
+
+func (s *Server) cancel(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+		delete(s.running, id)
+	}
+}
+
+func (s *Server) cancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, cancel := range s.running {
+		cancel()
+		delete(s.running, id)
+	}
+}
+
+// params decodes a request's params, reporting a mismatch as
+// CodeInvalidParams rather than an internal error.
+func params(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 3: The Methods
+// ---------------------------------------------------------
+
+// Topic is the part of 158's manifest entry the editor needs.
+type Topic struct {
+	Num      int    `json:"num"`
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	Runnable bool   `json:"runnable"`
+	Err      string `json:"error,omitempty"`
+}
+
+// courseDirs are where topics live, relative to go_projects/ (see 168).
+var courseDirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+// goCmd runs the go tool in the server's directory.
+func (s *Server) goCmd(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = s.Dir
+	cmd.Stderr = s.Log
+	return cmd
+}
+
+// runnerBinary builds 176 the first time a run is asked for. Running the
+// binary rather than "go run" matters for cancelling: killing "go run"
+// kills the go tool and leaves 176 running underneath.
+func (s *Server) runnerBinary(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runner != "" {
+		return s.runner, nil
+	}
+	if s.tmp == "" {
+		tmp, err := os.MkdirTemp("", "lsp-lite-")
+		if err != nil {
+			return "", err
+		}
+		s.tmp = tmp
+	}
+	bin := filepath.Join(s.tmp, "run_events")
+	if err := s.goCmd(ctx, "build", "-o", bin, "176_run_events.go").Run(); err != nil {
+		return "", fmt.Errorf("building 176: %w", err)
+	}
+	s.runner = bin
+	return bin, nil
+}
+
+func (s *Server) initialize(context.Context, json.RawMessage, json.RawMessage) (any, error) {
+	return map[string]any{
+		"serverInfo":    map[string]string{"name": "gotut lsp-lite", "version": "1"},
+		"schemaVersion": 1, // Of the run events, as in 176
+		"methods":       []string{"topics/list", "topics/run", "exercises/status", "shutdown", "exit", "$/cancelRequest"},
+	}, nil
+}
+
+// topics loads 158's manifest once and keeps it; refresh reloads it after
+// the editor saw files change.
+func (s *Server) topics(ctx context.Context, refresh bool) ([]Topic, error) {
+	s.mu.Lock()
+	cached := s.manifest
+	s.mu.Unlock()
+	if cached != nil && !refresh {
+		return cached, nil
+	}
+	out, err := s.goCmd(ctx, append([]string{"run", "158_go_parser_ast.go", "manifest"}, courseDirs...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("158 manifest: %w", err)
+	}
+	var all []Topic
+	if err := json.Unmarshal(out, &all); err != nil {
+		return nil, fmt.Errorf("158 manifest: %w", err)
+	}
+	var topics []Topic
+	for _, t := range all {
+		if t.Num > 0 {
+			topics = append(topics, t)
+		}
+	}
+	s.mu.Lock()
+	s.manifest = topics
+	s.mu.Unlock()
+	return topics, nil
+}
+
+func (s *Server) topic(ctx context.Context, num int) (Topic, error) {
+	topics, err := s.topics(ctx, false)
+	if err != nil {
+		return Topic{}, err
+	}
+	for _, t := range topics {
+		if t.Num == num {
+			return t, nil
+		}
+	}
+	return Topic{}, &RPCError{Code: CodeUnknownTopic, Message: fmt.Sprintf("no topic %d", num)}
+}
+
+func (s *Server) listTopics(ctx context.Context, _, raw json.RawMessage) (any, error) {
+	var p struct {
+		Query   string `json:"query"`
+		Refresh bool   `json:"refresh"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	topics, err := s.topics(ctx, p.Refresh)
+	if err != nil {
+		return nil, err
+	}
+	q := strings.ToLower(p.Query)
+	list := []Topic{} // [] rather than null when nothing matches
+	for _, t := range topics {
+		if q == "" || strings.Contains(strings.ToLower(t.Title), q) || strconv.Itoa(t.Num) == q {
+			list = append(list, t)
+		}
+	}
+	return list, nil
+}
+
+// runTopic runs 176 with --format json and forwards each event as a
+// "$/runEvent" notification tagged with the request id. The answer is the
+// summary event. Cancelling kills 176; the lesson under it dies at its
+// next write, when its stdout pipe is gone.
+func (s *Server) runTopic(ctx context.Context, id, raw json.RawMessage) (any, error) {
+	var p struct {
+		Topic int      `json:"topic"`
+		Args  []string `json:"args"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	t, err := s.topic(ctx, p.Topic)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Runnable || !strings.HasSuffix(t.Path, ".go") {
+		return nil, &RPCError{Code: CodeInvalidParams,
+			Message: fmt.Sprintf("topic %d is not a single-file lesson with a main", p.Topic), Data: t.Path}
+	}
+	runner, err := s.runnerBinary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"run", strconv.Itoa(p.Topic), "--format", "json"}
+	if len(p.Args) > 0 {
+		args = append(append(args, "--"), p.Args...)
+	}
+	cmd := exec.CommandContext(ctx, runner, args...)
+	cmd.Dir, cmd.Stderr = s.Dir, s.Log
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	var summary json.RawMessage
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(nil, 1<<20) // A code event carries a whole function
+	for sc.Scan() {
+		ev := json.RawMessage(bytes.Clone(sc.Bytes()))
+		var head struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(ev, &head) == nil && head.Type == "summary" {
+			summary = ev
+		}
+		s.send(Notification{JSONRPC: "2.0", Method: "$/runEvent", Params: map[string]any{"id": id, "event": ev}})
+	}
+	cmd.Wait() // 176 exits 3 when the lesson fails; the summary says so
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if summary == nil {
+		return nil, errors.New("176 ended without a summary event")
+	}
+	return summary, nil
+}
+
+// ExerciseStatus is one lesson package's test result.
+type ExerciseStatus struct {
+	Topic  int      `json:"topic"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"` // "pass", "fail" or "error" (didn't build)
+	Failed []string `json:"failed,omitempty"`
+	Tests  int      `json:"tests"`
+}
+
+// exerciseStatus runs go test -json in every numbered package directory
+// that has tests, or just the one asked for.
+func (s *Server) exerciseStatus(ctx context.Context, _, raw json.RawMessage) (any, error) {
+	var p struct {
+		Topic int `json:"topic"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	topics, err := s.topics(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []ExerciseStatus{}
+	for _, t := range topics {
+		if p.Topic != 0 && t.Num != p.Topic {
+			continue
+		}
+		tests, _ := filepath.Glob(filepath.Join(s.Dir, t.Path, "*_test.go"))
+		if len(tests) == 0 {
+			if p.Topic != 0 {
+				return nil, &RPCError{Code: CodeInvalidParams, Message: fmt.Sprintf("topic %d has no tests", p.Topic)}
+			}
+			continue
+		}
+		st, err := s.testPackage(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, st)
+	}
+	if p.Topic != 0 && len(statuses) == 0 {
+		return nil, &RPCError{Code: CodeUnknownTopic, Message: fmt.Sprintf("no topic %d", p.Topic)}
+	}
+	return statuses, nil
+}
+
+// testPackage reads go test -json (test2json): one event per line, with
+// "Action" pass or fail per test and once more for the package.
+func (s *Server) testPackage(ctx context.Context, t Topic) (ExerciseStatus, error) {
+	cmd := s.goCmd(ctx, "test", "-json", ".")
+	cmd.Dir = filepath.Join(s.Dir, t.Path)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return ExerciseStatus{}, err // go itself didn't run
+	}
+	st := ExerciseStatus{Topic: t.Num, Title: t.Title, Status: "error"}
+	for line := range bytes.Lines(out) {
+		var ev struct {
+			Action, Test string
+		}
+		if json.Unmarshal(line, &ev) != nil {
+			continue // A build failure prints plain text first
+		}
+		switch {
+		case ev.Test != "" && strings.Contains(ev.Test, "/"):
+			// Subtests roll up into their parent
+		case ev.Test != "" && (ev.Action == "pass" || ev.Action == "fail"):
+			st.Tests++
+			if ev.Action == "fail" {
+				st.Failed = append(st.Failed, ev.Test)
+			}
+		case ev.Test == "" && ev.Action == "pass":
+			st.Status = "pass"
+		case ev.Test == "" && ev.Action == "fail" && st.Tests > 0:
+			st.Status = "fail"
+		}
+	}
+	return st, nil
+}
+
+// ---------------------------------------------------------
+// Part 4: Demo — A Scripted Editor Session
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) string {
+	if ok {
+		return "✓ " + pass
+	}
+	return "✗ " + fail
+}
+
+// client is the editor's side: it frames requests and reads until the
+// answer to one of them, collecting the notifications on the way.
+type client struct {
+	w    io.Writer
+	r    *bufio.Reader
+	next int
+}
+
+type reply struct {
+	Response
+	Method string          `json:"method"` // Set for a notification
+	Params json.RawMessage `json:"params"`
+}
+
+func (c *client) request(method string, p any) (int, error) {
+	c.next++
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return 0, err
+	}
+	return c.next, writeMessage(c.w, Request{JSONRPC: "2.0", ID: json.RawMessage(strconv.Itoa(c.next)), Method: method, Params: raw})
+}
+
+// await reads messages until the response to id, passing every
+// notification to onNote (which may be nil).
+func (c *client) await(id int, onNote func(reply)) (reply, error) {
+	for {
+		body, err := readMessage(c.r)
+		if err != nil {
+			return reply{}, err
+		}
+		var m reply
+		if err := json.Unmarshal(body, &m); err != nil {
+			return reply{}, err
+		}
+		if m.Method != "" {
+			if onNote != nil {
+				onNote(m)
+			}
+			continue
+		}
+		if string(m.ID) == strconv.Itoa(id) {
+			return m, nil
+		}
+	}
+}
+
+func (c *client) call(method string, p any, onNote func(reply)) (reply, error) {
+	id, err := c.request(method, p)
+	if err != nil {
+		return reply{}, err
+	}
+	return c.await(id, onNote)
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: AN EDITOR ENDPOINT — JSON-RPC OVER STDIO")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Framing ---")
+	var wire bytes.Buffer
+	if err := writeMessage(&wire, Request{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "initialize"}); err != nil {
+		return err
+	}
+	fmt.Printf("  %q\n", wire.String())
+	body, err := readMessage(bufio.NewReader(&wire))
+	fmt.Println(" ", check(err == nil && json.Valid(body), "read back exactly one message: "+string(body), fmt.Sprint(err)))
+	fmt.Println()
+
+	// The server gets one end of two pipes; the "editor" below the other.
+	toServer, fromEditor := io.Pipe()
+	toEditor, fromServer := io.Pipe()
+	var serverLog bytes.Buffer
+	srv := NewServer(".", &serverLog)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(toServer, fromServer)
+		fromServer.Close()
+	}()
+	c := &client{w: fromEditor, r: bufio.NewReader(toEditor)}
+
+	fmt.Println("--- Example 2: initialize and topics/list ---")
+	r, err := c.call("initialize", nil, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Println("  ← initialize:", string(r.Result))
+	r, err = c.call("topics/list", map[string]string{"query": "checksum"}, nil)
+	if err != nil {
+		return err
+	}
+	var topics []Topic
+	if err := json.Unmarshal(r.Result, &topics); err != nil {
+		return err
+	}
+	for _, t := range topics {
+		fmt.Printf("  ← %3d  %-40s %s\n", t.Num, t.Title, t.Path)
+	}
+	fmt.Println(" ", check(len(topics) > 0 && topics[0].Num == 153, "the manifest came from 158, filtered by the query",
+		fmt.Sprintf("expected topic 153 first, got %+v", topics)))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: topics/run Streams Events ---")
+	counts := map[string]int{}
+	r, err = c.call("topics/run", map[string]int{"topic": 153}, func(n reply) {
+		var p struct {
+			Event struct{ Type, Title string }
+		}
+		json.Unmarshal(n.Params, &p)
+		counts[p.Event.Type]++
+		if p.Event.Type == "section" {
+			fmt.Println("  ← $/runEvent section:", p.Event.Title)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	var summary struct {
+		ExitCode *int `json:"exit_code"`
+		Sections []struct{ Title string }
+	}
+	json.Unmarshal(r.Result, &summary)
+	fmt.Printf("  ← result: summary with exit_code %d, %d sections (after %d output and %d code events)\n",
+		*summary.ExitCode, len(summary.Sections), counts["output"], counts["code"])
+	fmt.Println(" ", check(r.Error == nil && *summary.ExitCode == 0 && counts["code"] == 6,
+		"176's events passed through untouched; the answer is the summary",
+		fmt.Sprintf("error %v, counts %v", r.Error, counts)))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Cancelling a Slow Run (113, timers) ---")
+	id, err := c.request("topics/run", map[string]int{"topic": 113})
+	if err != nil {
+		return err
+	}
+	cancelled := false
+	start := time.Now()
+	r, err = c.await(id, func(n reply) {
+		var p struct{ Event struct{ Type, Title string } }
+		json.Unmarshal(n.Params, &p)
+		if p.Event.Type == "section" && !cancelled {
+			fmt.Println("  ← $/runEvent section:", p.Event.Title, "→ the user presses Stop")
+			cancelled = true
+			// From inside the read loop: the editor doesn't wait for a turn.
+			go writeMessage(c.w, Notification{JSONRPC: "2.0", Method: "$/cancelRequest", Params: map[string]int{"id": id}})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(" ", check(r.Error != nil && r.Error.Code == CodeRequestCancelled,
+		fmt.Sprintf("answered with error %d %q after %v (the lesson takes ~8s)", r.Error.Code, r.Error.Message, time.Since(start).Round(100*time.Millisecond)),
+		fmt.Sprintf("expected a cancelled error, got %+v", r)))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: exercises/status ---")
+	r, err = c.call("exercises/status", map[string]int{"topic": 155}, nil)
+	if err != nil {
+		return err
+	}
+	var statuses []ExerciseStatus
+	json.Unmarshal(r.Result, &statuses)
+	for _, st := range statuses {
+		fmt.Printf("  ← %d %s: %s (%d tests)\n", st.Topic, st.Title[:min(len(st.Title), 30)], st.Status, st.Tests)
+	}
+	fmt.Println(" ", check(len(statuses) == 1 && statuses[0].Status == "pass", "go test -json, read back per test",
+		fmt.Sprintf("got %+v (error %v)", statuses, r.Error)))
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Errors Are Answers Too ---")
+	for _, tc := range []struct {
+		method string
+		params any
+		want   int
+	}{
+		{"topics/open", nil, CodeMethodNotFound},
+		{"topics/run", map[string]string{"topic": "eighty-five"}, CodeInvalidParams},
+		{"topics/run", map[string]int{"topic": 999}, CodeUnknownTopic},
+		{"exercises/status", map[string]int{"topic": 153}, CodeInvalidParams},
+	} {
+		r, err := c.call(tc.method, tc.params, nil)
+		if err != nil {
+			return err
+		}
+		got, msg := 0, ""
+		if r.Error != nil {
+			got, msg = r.Error.Code, r.Error.Message
+		}
+		fmt.Println(" ", check(got == tc.want, fmt.Sprintf("%-17s → %d %s", tc.method, got, msg),
+			fmt.Sprintf("%s → %d, want %d", tc.method, got, tc.want)))
+	}
+	if _, err := c.call("shutdown", nil, nil); err != nil {
+		return err
+	}
+	if err := writeMessage(c.w, Notification{JSONRPC: "2.0", Method: "exit"}); err != nil {
+		return err
+	}
+	fmt.Println(" ", check(<-done == nil, "shutdown, exit: the server returned cleanly", "the server failed"))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Stdio + JSON-RPC: no ports, no auth, one process per editor window.
+2. Frame messages with Content-Length; LSP clients already speak it.
+3. stdout is the protocol — log to stderr, always.
+4. Run each request in a goroutine; ids pair answers with questions.
+5. Stream progress as notifications; answer once, at the end.
+6. Reuse by process: 158's manifest and 176's events pass straight through.
+	`)
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lsp-lite":
+			fs := flag.NewFlagSet("lsp-lite", flag.ExitOnError)
+			dir := fs.String("dir", ".", "the go_projects directory")
+			fs.Parse(os.Args[2:])
+			if err := NewServer(*dir, os.Stderr).Serve(os.Stdin, os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, "lsp-lite:", err)
+				os.Exit(1)
+			}
+			return
+		}
+		fmt.Fprintf(os.Stderr, "unknown command %q (try: lsp-lite)\n", os.Args[1])
+		os.Exit(2)
+	}
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
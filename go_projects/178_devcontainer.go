@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/lazy"
+)
+
+/*
+TOPIC: REPRODUCIBLE ENVIRONMENTS — GENERATING A DEV CONTAINER FROM TEMPLATES
+
+CONCEPT:
+"Works on my machine" usually means "my machine has a different Go". This
+course needs Go 1.25 or newer (sync.WaitGroup.Go, and every go.mod says
+go 1.25) and — for the editor — gopls and delve. A learner
+on an older Go sees compile errors that have nothing to do with the
+lesson. A reproducible environment pins every one of those:
+
+    what              pinned as                       drifts if left to chance
+    Go toolchain      FROM golang:1.25-bookworm       whatever the laptop has
+    toolchain swaps   GOTOOLCHAIN=local               go may fetch another Go
+    editor tools      go install gopls@v0.18.1        "latest" changes weekly
+    user              uid 1000, not root              174/168 touch $HOME
+
+The files that describe it are GENERATED, not hand-written:
+
+    go run 178_devcontainer.go tool devcontainer -go 1.25 -tools gopls,delve
+        → ../.devcontainer/Dockerfile
+        → ../.devcontainer/compose.yaml
+        → ../.devcontainer/devcontainer.json   (VS Code "Reopen in Container")
+
+The templates live in 178_templates/ and are compiled in with //go:embed
+(Topic 89, as 148 embeds its migrations), so the binary always carries the
+templates it was built with.
+
+SCAFFOLDING: the course had no scaffolder, so Part 1 is a small general one
+— render a directory of templates, then create, update or leave each file —
+and the dev container is its first user. The rules any scaffolder needs:
+    • Same inputs, same bytes: no timestamps, tools in a fixed order. A
+      regenerated file that didn't change shows no diff.
+    • Never overwrite a file someone edited, unless told to (-force).
+    • Show the plan first (-dry-run, as in 174).
+    • Validate parameters before rendering: a typo in -go should be an error
+      here, not a failed docker build later.
+
+This is for people, not CI: a CI job pins its own image.
+
+RUN:
+    go run 178_devcontainer.go                                     (demo)
+    go run 178_devcontainer.go tool devcontainer -dry-run
+    go run 178_devcontainer.go tool devcontainer -go 1.25.1 -tools gopls,delve,staticcheck
+    docker compose -f ../.devcontainer/compose.yaml run --rm playground go run 153_crc32_checksums.go
+*/
+
+//go:embed 178_templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// ---------------------------------------------------------
+// Part 1: A Small Scaffolder
+// ---------------------------------------------------------
+
+// Templates are parsed NAME.tmpl files, keyed by NAME.
+type Templates map[string]*template.Template
+
+// ParseTemplates parses every NAME.tmpl in dir of fsys. missingkey=error:
+// a template that asks for a field the data doesn't have fails at Render
+// instead of writing "<no value>".
+func ParseTemplates(fsys fs.FS, dir string) (Templates, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	ts := Templates{}
+	for _, name := range names {
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		out := strings.TrimSuffix(path.Base(name), ".tmpl")
+		if ts[out], err = template.New(out).Option("missingkey=error").Parse(string(src)); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+// Render executes every template and returns the results by NAME.
+func (ts Templates) Render(data any) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for name, t := range ts {
+		var b bytes.Buffer
+		if err := t.Execute(&b, data); err != nil {
+			return nil, err
+		}
+		files[name] = b.Bytes()
+	}
+	return files, nil
+}
+
+// Render parses the templates in dir of fsys and executes them.
+func Render(fsys fs.FS, dir string, data any) (map[string][]byte, error) {
+	ts, err := ParseTemplates(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return ts.Render(data)
+}
+
+// builtin is 178_templates, parsed on first use and kept (Topic 183).
+// A *template.Template is safe to Execute from many goroutines at once.
+var builtin = lazy.OfErr(func() (Templates, error) {
+	return ParseTemplates(embeddedTemplates, "178_templates")
+})
+
+// RenderBuiltin renders the embedded templates with data.
+func RenderBuiltin(data any) (map[string][]byte, error) {
+	ts, err := builtin.Get()
+	if err != nil {
+		return nil, err
+	}
+	return ts.Render(data)
+}
+
+// What Apply did, or would do, to one file.
+const (
+	Create    = "create"
+	Update    = "update"
+	Unchanged = "unchanged"
+	Kept      = "kept" // Exists with other content and -force wasn't given
+)
+
+var ErrConflict = errors.New("files differ from the templates; -force to overwrite")
+
+type ApplyOptions struct {
+	DryRun bool
+	Force  bool
+	Log    io.Writer // One line per file; nil = quiet
+}
+
+// Apply writes files into dest. Every file is looked at before ErrConflict
+// is returned, so one run lists every conflict, not just the first.
+func Apply(files map[string][]byte, dest string, opts ApplyOptions) (map[string]string, error) {
+	if opts.Log == nil {
+		opts.Log = io.Discard
+	}
+	if !opts.DryRun {
+		if err := os.MkdirAll(dest, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	actions := map[string]string{}
+	conflict := false
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		p := filepath.Join(dest, name)
+		old, err := os.ReadFile(p)
+		action := Update
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			action = Create
+		case err != nil:
+			return actions, err
+		case bytes.Equal(old, files[name]):
+			action = Unchanged
+		case !opts.Force:
+			action, conflict = Kept, true
+		}
+		actions[name] = action
+		prefix := ""
+		if opts.DryRun && (action == Create || action == Update) {
+			prefix = "would "
+		}
+		fmt.Fprintf(opts.Log, "%s%-9s %s\n", prefix, action, p)
+		if opts.DryRun || action == Unchanged || action == Kept {
+			continue
+		}
+		if err := writeAtomic(p, files[name]); err != nil {
+			return actions, err
+		}
+	}
+	if conflict {
+		return actions, ErrConflict
+	}
+	return actions, nil
+}
+
+// writeAtomic replaces p in one step (168): a half-written Dockerfile is
+// worse than the old one.
+func writeAtomic(p string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after the rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil { // CreateTemp makes it 0600
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// ---------------------------------------------------------
+// Part 2: The Dev Container's Parameters
+// ---------------------------------------------------------
+
+type Tool struct {
+	Name    string
+	Package string
+	Version string
+}
+
+// knownTools pins each tool. Bumping one is a one-line change here, and the
+// regenerated Dockerfile shows exactly that line in review.
+var knownTools = []Tool{
+	{"gopls", "golang.org/x/tools/gopls", "v0.18.1"},
+	{"delve", "github.com/go-delve/delve/cmd/dlv", "v1.24.1"},
+	{"staticcheck", "honnef.co/go/tools/cmd/staticcheck", "2025.1.1"},
+}
+
+// minGo is the oldest Go the lessons build with.
+const minGo = 25
+
+var goVersion = regexp.MustCompile(`^1\.(\d+)(\.\d+)?$`)
+
+type Devcontainer struct {
+	Header    string // The "generated by" line, with the command to regenerate
+	GoVersion string
+	Tools     []Tool
+	UID       int
+}
+
+func (d Devcontainer) HasTool(name string) bool {
+	return slices.ContainsFunc(d.Tools, func(t Tool) bool { return t.Name == name })
+}
+
+// NewDevcontainer validates the parameters. Tools come out in the order
+// of knownTools whatever order they were asked for in, so "-tools
+// delve,gopls" and "-tools gopls,delve" render the same bytes.
+func NewDevcontainer(version string, tools []string, uid int) (Devcontainer, error) {
+	m := goVersion.FindStringSubmatch(version)
+	if m == nil {
+		return Devcontainer{}, fmt.Errorf("go version %q: want 1.N or 1.N.P", version)
+	}
+	if minor, _ := strconv.Atoi(m[1]); minor < minGo {
+		return Devcontainer{}, fmt.Errorf("go version %s: the lessons need 1.%d or newer", version, minGo)
+	}
+	if uid <= 0 {
+		return Devcontainer{}, fmt.Errorf("uid %d: want a normal user, not root", uid)
+	}
+	d := Devcontainer{GoVersion: version, UID: uid}
+	var names []string
+	for _, t := range knownTools {
+		if slices.Contains(tools, t.Name) {
+			d.Tools = append(d.Tools, t)
+			names = append(names, t.Name)
+		}
+	}
+	for _, name := range tools {
+		if !slices.Contains(names, name) {
+			var known []string
+			for _, t := range knownTools {
+				known = append(known, t.Name)
+			}
+			return Devcontainer{}, fmt.Errorf("unknown tool %q (known: %s)", name, strings.Join(known, ", "))
+		}
+	}
+	d.Header = fmt.Sprintf("Generated by: go run 178_devcontainer.go tool devcontainer -go %s -tools %s. Edit 178_templates/, not this file.",
+		version, strings.Join(names, ","))
+	return d, nil
+}
+
+// ---------------------------------------------------------
+// Part 3: The Command
+// ---------------------------------------------------------
+
+func toolDevcontainer(args []string) error {
+	fs := flag.NewFlagSet("devcontainer", flag.ContinueOnError)
+	version := fs.String("go", fmt.Sprintf("1.%d", minGo), "Go version for the golang image: 1.N or, better, 1.N.P")
+	tools := fs.String("tools", "gopls,delve", "comma-separated tools to install (empty for none)")
+	uid := fs.Int("uid", 1000, "uid of the container user; match yours so files on the mount stay yours")
+	out := fs.String("o", "../.devcontainer", "directory to write into")
+	dryRun := fs.Bool("dry-run", false, "show what would be written, write nothing")
+	force := fs.Bool("force", false, "overwrite files that differ from the templates")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var names []string
+	if *tools != "" {
+		names = strings.Split(*tools, ",")
+	}
+	d, err := NewDevcontainer(*version, names, *uid)
+	if err != nil {
+		return err
+	}
+	files, err := RenderBuiltin(d)
+	if err != nil {
+		return err
+	}
+	_, err = Apply(files, *out, ApplyOptions{DryRun: *dryRun, Force: *force, Log: os.Stdout})
+	return err
+}
+
+// ---------------------------------------------------------
+// Part 4: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func sum(files map[string][]byte) string {
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, files[name])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:12]
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: REPRODUCIBLE ENVIRONMENTS — A GENERATED DEV CONTAINER")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: Rendering the Templates ---")
+	d, err := NewDevcontainer("1.25", []string{"gopls", "delve"}, 1000)
+	if err != nil {
+		return err
+	}
+	files, err := RenderBuiltin(d)
+	if err != nil {
+		return err
+	}
+	for l := range strings.Lines(string(files["Dockerfile"])) {
+		fmt.Print("  │ ", l)
+	}
+	fmt.Printf("  (+ %s)\n\n", strings.Join(slices.DeleteFunc(slices.Sorted(maps.Keys(files)),
+		func(n string) bool { return n == "Dockerfile" }), ", "))
+
+	fmt.Println("--- Example 2: Same Inputs, Same Bytes ---")
+	again, err := NewDevcontainer("1.25", []string{"delve", "gopls"}, 1000)
+	if err != nil {
+		return err
+	}
+	files2, err := RenderBuiltin(again)
+	if err != nil {
+		return err
+	}
+	check(sum(files) == sum(files2), "rendered twice, tools asked for in another order: sha256 "+sum(files),
+		"the output depends on something other than the inputs")
+	bumped, err := NewDevcontainer("1.25.1", []string{"gopls", "delve"}, 1000)
+	if err != nil {
+		return err
+	}
+	files3, err := RenderBuiltin(bumped)
+	if err != nil {
+		return err
+	}
+	changed := 0
+	old := strings.Split(string(files["Dockerfile"]), "\n")
+	for i, l := range strings.Split(string(files3["Dockerfile"]), "\n") {
+		if i < len(old) && l != old[i] {
+			changed++
+			fmt.Printf("  - %s\n  + %s\n", old[i], l)
+		}
+	}
+	check(changed == 2, "pinning the patch release changes the lines that mention it, nothing else",
+		fmt.Sprintf("%d lines changed", changed))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Bad Parameters Fail Here, Not in docker build ---")
+	for _, tc := range []struct {
+		version string
+		tools   []string
+		uid     int
+	}{
+		{"1.x", nil, 1000},
+		{"1.24", nil, 1000},
+		{"1.25", []string{"gopls", "emacs"}, 1000},
+		{"1.25", nil, 0},
+	} {
+		_, err := NewDevcontainer(tc.version, tc.tools, tc.uid)
+		check(err != nil, "refused: "+fmt.Sprint(err), fmt.Sprintf("%s %v %d was accepted", tc.version, tc.tools, tc.uid))
+	}
+	typo := fstest.MapFS{"t/x.tmpl": {Data: []byte("{{.GoVersoin}}")}}
+	_, err = Render(typo, "t", d)
+	check(err != nil, "a template typo is an error, not \"<no value>\" in the file", "the typo rendered")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Writing — Plan, Create, Re-run, Hand Edits ---")
+	dir, err := os.MkdirTemp("", "demo-devcontainer-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	dest := filepath.Join(dir, ".devcontainer")
+	short := strings.NewReplacer(dir+string(filepath.Separator), "")
+	var log strings.Builder
+	run := func(label string, opts ApplyOptions) (map[string]string, error) {
+		log.Reset()
+		opts.Log = &log
+		actions, err := Apply(files, dest, opts)
+		fmt.Println("  $", label)
+		for l := range strings.Lines(short.Replace(log.String())) {
+			fmt.Print("    ", l)
+		}
+		return actions, err
+	}
+	if _, err := run("devcontainer -dry-run", ApplyOptions{DryRun: true}); err != nil {
+		return err
+	}
+	_, statErr := os.Stat(dest)
+	check(errors.Is(statErr, fs.ErrNotExist), "the dry run created nothing", "the dry run wrote files")
+	if _, err := run("devcontainer", ApplyOptions{}); err != nil {
+		return err
+	}
+	actions, err := run("devcontainer   (again)", ApplyOptions{})
+	if err != nil {
+		return err
+	}
+	check(!slices.ContainsFunc(slices.Collect(maps.Values(actions)), func(a string) bool { return a != Unchanged }),
+		"a second run changes nothing", fmt.Sprintf("second run: %v", actions))
+	compose := filepath.Join(dest, "compose.yaml")
+	edited := append(bytes.Clone(files["compose.yaml"]), "    ports: [\"8080:8080\"]\n"...)
+	if err := os.WriteFile(compose, edited, 0o644); err != nil {
+		return err
+	}
+	_, err = run("devcontainer   (after a hand edit to compose.yaml)", ApplyOptions{})
+	now, readErr := os.ReadFile(compose)
+	check(errors.Is(err, ErrConflict) && readErr == nil && bytes.Equal(now, edited),
+		"the edit was kept and reported: "+fmt.Sprint(err), "the hand edit was overwritten")
+	if _, err := run("devcontainer -force", ApplyOptions{Force: true}); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Using It ---")
+	fmt.Println("  go run 178_devcontainer.go tool devcontainer            → ../.devcontainer/")
+	fmt.Println("  VS Code: \"Dev Containers: Reopen in Container\"          → gopls, delve, Go 1.25")
+	fmt.Println("  without an editor:")
+	fmt.Println("    docker compose -f ../.devcontainer/compose.yaml run --rm playground \\")
+	fmt.Println("        go run 153_crc32_checksums.go")
+	fmt.Println("  Commit the generated files: a reviewer sees a tool bump as a one-line diff.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Pin everything a lesson depends on: Go, GOTOOLCHAIN, tools.
+2. Generate environment files from templates; embed the templates.
+3. Same inputs, same bytes: no timestamps, stable ordering.
+4. Validate parameters and use missingkey=error before writing anything.
+5. Never overwrite hand edits silently; plan with -dry-run, override with -force.
+	`)
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch {
+		case len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "devcontainer":
+			err = toolDevcontainer(os.Args[3:])
+		default:
+			err = fmt.Errorf("unknown command %q (want: tool devcontainer)", strings.Join(os.Args[1:], " "))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "devcontainer:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,930 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+TOPIC: CROSS-COMPILATION — ONE MACHINE, EVERY PLATFORM
+
+CONCEPT:
+The Go toolchain carries a compiler and linker for every platform it
+supports. Two environment variables choose the target:
+
+    GOOS=linux   GOARCH=amd64  go build     → an ELF binary for Linux/x86-64
+    GOOS=darwin  GOARCH=arm64  go build     → a Mach-O binary for Apple silicon
+    GOOS=windows GOARCH=amd64  go build     → a PE .exe for Windows
+
+No cross toolchain, no VM, no Docker. "go tool dist list" prints every
+GOOS/GOARCH pair the installed Go knows.
+
+CGO_ENABLED: the catch is C. A package that imports "C" needs a C compiler
+FOR THE TARGET, which the laptop doesn't have. When GOOS/GOARCH differ from
+the host, go build turns cgo off unless told otherwise; set CGO_ENABLED=0
+explicitly anyway, so the native build is the same pure-Go program as the
+others (net and os/user then use their Go implementations). Files that
+import "C" are then skipped like any other excluded file.
+
+FILE NAMES ARE BUILD CONSTRAINTS: go build picks files by their suffix,
+before reading a line of them:
+
+    path.go               every platform
+    path_windows.go       GOOS=windows only
+    path_linux_arm64.go   GOOS=linux and GOARCH=arm64 only
+    path_test.go          go test only
+
+So platform code is split by file, and each target gets its own set.
+
+ARTIFACT NAMES: the convention (goreleaser, the Go downloads page) is
+NAME_GOOS_GOARCH, plus .exe for Windows, which won't run a file without it.
+Next to them goes SHA256SUMS in the format sha256sum -c reads, so anyone can
+check a download. With -trimpath (no local paths in the binary) the same
+source and Go version give the same bytes, and the same sums.
+
+    gotut tool xbuild --targets linux/amd64,darwin/arm64,windows/amd64
+        dist/gotut_linux_amd64
+        dist/gotut_darwin_arm64
+        dist/gotut_windows_amd64.exe
+        dist/SHA256SUMS
+
+RELEASES: -package turns the binaries into what people download — one
+archive per target (tar.gz, or zip for Windows) holding the binary, LICENSE
+and lessons.json (158's manifest) — and signs SHA256SUMS with an HMAC key
+from $GOTUT_RELEASE_KEY:
+
+    dist/gotut_linux_amd64.tar.gz  dist/gotut_darwin_arm64.tar.gz
+    dist/gotut_windows_amd64.zip   dist/SHA256SUMS  dist/SHA256SUMS.hmac
+
+The archives are reproducible too: fixed timestamps (SOURCE_DATE_EPOCH),
+no owner names, fixed entry order.
+
+gotut/ is the course's CLI, so it is what xbuild builds by default. The
+tree has no LICENSE file, so -package needs -license pointing at one.
+
+RUN:
+    go run 179_xbuild.go                                  (demo)
+    go run 179_xbuild.go tool xbuild                      → dist/gotut_*
+    go run 179_xbuild.go tool xbuild --targets linux/arm64,windows/amd64 153_crc32_checksums.go
+    cd dist && sha256sum -c SHA256SUMS
+    export GOTUT_RELEASE_KEY=$(openssl rand -hex 32)
+    go run 179_xbuild.go tool xbuild -package -license LICENSE.txt
+    go run 179_xbuild.go tool xbuild -verify dist
+*/
+
+// ---------------------------------------------------------
+// Part 1: Targets and Artifact Names
+// ---------------------------------------------------------
+
+type Target struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (t Target) String() string { return t.GOOS + "/" + t.GOARCH }
+
+// ArtifactName is NAME_GOOS_GOARCH, with .exe on Windows.
+func ArtifactName(name string, t Target) string {
+	s := name + "_" + t.GOOS + "_" + t.GOARCH
+	if t.GOOS == "windows" {
+		s += ".exe"
+	}
+	return s
+}
+
+var defaultTargets = "linux/amd64,darwin/arm64,windows/amd64"
+
+// ParseTargets reads "os/arch,os/arch" and checks each pair against what
+// the installed Go supports, so a typo fails before anything is built.
+func ParseTargets(s string, supported []Target) ([]Target, error) {
+	var targets []Target
+	for _, f := range strings.Split(s, ",") {
+		goos, goarch, ok := strings.Cut(strings.TrimSpace(f), "/")
+		t := Target{goos, goarch}
+		switch {
+		case !ok || goos == "" || goarch == "":
+			return nil, fmt.Errorf("target %q: want GOOS/GOARCH, e.g. linux/amd64", f)
+		case !slices.Contains(supported, t):
+			return nil, fmt.Errorf("target %s: not supported by this Go (go tool dist list)", t)
+		case slices.Contains(targets, t):
+			return nil, fmt.Errorf("target %s: listed twice", t)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// SupportedTargets asks the toolchain: the list grows with each Go release.
+func SupportedTargets() ([]Target, error) {
+	out, err := exec.Command("go", "tool", "dist", "list").Output()
+	if err != nil {
+		return nil, fmt.Errorf("go tool dist list: %w", err)
+	}
+	var targets []Target
+	for l := range strings.Lines(string(out)) {
+		if goos, goarch, ok := strings.Cut(strings.TrimSpace(l), "/"); ok {
+			targets = append(targets, Target{goos, goarch})
+		}
+	}
+	return targets, nil
+}
+
+// ---------------------------------------------------------
+// Part 2: Building the Matrix
+// ---------------------------------------------------------
+
+type Artifact struct {
+	Target Target
+	Path   string
+	SHA256 string
+}
+
+type XBuild struct {
+	Src  string // A package directory, or a single .go file
+	Name string // Artifact base name
+	Dist string // Output directory
+}
+
+// Build compiles Src once per target, in parallel: each go build is its
+// own process, and the build cache is safe to share between them. Every
+// failure is reported, not just the first; the artifacts come back in
+// target order whatever order the builds finished in.
+func (x XBuild) Build(targets []Target) ([]Artifact, error) {
+	if err := os.MkdirAll(x.Dist, 0o755); err != nil {
+		return nil, err
+	}
+	artifacts := make([]Artifact, len(targets))
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Go(func() {
+			artifacts[i], errs[i] = x.build(t)
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+func (x XBuild) build(t Target) (Artifact, error) {
+	out, err := filepath.Abs(filepath.Join(x.Dist, ArtifactName(x.Name, t)))
+	if err != nil {
+		return Artifact{}, err
+	}
+	args := []string{"build", "-trimpath", "-o", out}
+	cmd := exec.Command("go")
+	if strings.HasSuffix(x.Src, ".go") {
+		cmd.Dir, args = filepath.Dir(x.Src), append(args, filepath.Base(x.Src))
+	} else {
+		cmd.Dir, args = x.Src, append(args, ".") // From inside its module, as gotut/ is one of its own
+	}
+	cmd.Args = append(cmd.Args, args...)
+	cmd.Env = append(os.Environ(), "GOOS="+t.GOOS, "GOARCH="+t.GOARCH, "CGO_ENABLED=0")
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return Artifact{}, fmt.Errorf("%s: %w\n%s", t, err, bytes.TrimSpace(msg))
+	}
+	sum, err := fileSHA256(out)
+	if err != nil {
+		return Artifact{}, err
+	}
+	return Artifact{Target: t, Path: out, SHA256: sum}, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ---------------------------------------------------------
+// Part 3: SHA256SUMS
+// ---------------------------------------------------------
+
+// WriteSums writes "HASH  NAME" lines for files in dist, sorted by name —
+// the format sha256sum -c checks — replacing the file in one step (168).
+func WriteSums(dist string, files []string) error {
+	var b strings.Builder
+	for _, name := range slices.Sorted(slices.Values(files)) {
+		sum, err := fileSHA256(filepath.Join(dist, name))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s  %s\n", sum, name)
+	}
+	tmp := filepath.Join(dist, ".SHA256SUMS.tmp")
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dist, "SHA256SUMS"))
+}
+
+// VerifySums is sha256sum -c: every listed file must exist and match.
+func VerifySums(dist string) error {
+	f, err := os.Open(filepath.Join(dist, "SHA256SUMS"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var errs []error
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		want, name, ok := strings.Cut(sc.Text(), "  ")
+		if !ok {
+			errs = append(errs, fmt.Errorf("malformed line %q", sc.Text()))
+			continue
+		}
+		got, err := fileSHA256(filepath.Join(dist, name))
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case got != want:
+			errs = append(errs, fmt.Errorf("%s: checksum mismatch", name))
+		}
+	}
+	return errors.Join(append(errs, sc.Err())...)
+}
+
+// Describe reads the executable's header the way the target's loader
+// would: proof that a binary is for a platform without running it there.
+func Describe(path string) (string, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		return "ELF " + f.Machine.String(), nil
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return "Mach-O " + f.Cpu.String(), nil
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		return fmt.Sprintf("PE machine %#x", f.Machine), nil
+	}
+	return "", fmt.Errorf("%s: not an ELF, Mach-O or PE executable", path)
+}
+
+// ---------------------------------------------------------
+// Part 4: Release Archives
+// ---------------------------------------------------------
+// A release is what people download: one archive per target with the
+// binary, the LICENSE it is distributed under, and lessons.json — 158's
+// manifest, so the binary's topics can be listed without the source.
+// tar.gz for Unix (keeps the executable bit), zip for Windows (opens
+// without extra tools). Both are written with the standard library.
+//
+//	gotut_linux_amd64.tar.gz      gotut_linux_amd64/gotut
+//	                              gotut_linux_amd64/LICENSE
+//	                              gotut_linux_amd64/lessons.json
+//	gotut_windows_amd64.zip       gotut_windows_amd64/gotut.exe  …
+
+type PackageOptions struct {
+	License  []byte
+	Manifest []byte
+	// ModTime is stamped on every entry. A fixed time, not time.Now(),
+	// keeps the archives byte-for-byte reproducible like the binaries.
+	ModTime time.Time
+}
+
+// epoch is used when SOURCE_DATE_EPOCH (the reproducible-builds.org
+// convention) isn't set: the earliest time a zip entry can hold.
+var epoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func SourceDateEpoch() (time.Time, error) {
+	v := os.Getenv("SOURCE_DATE_EPOCH")
+	if v == "" {
+		return epoch, nil
+	}
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || sec < epoch.Unix() {
+		return time.Time{}, fmt.Errorf("SOURCE_DATE_EPOCH=%q: want Unix seconds after 1980", v)
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
+type archiveEntry struct {
+	name string
+	mode int64
+	data []byte
+}
+
+// Package archives one artifact next to it in dist and returns the
+// archive's file name.
+func Package(a Artifact, name string, opts PackageOptions) (string, error) {
+	bin, err := os.ReadFile(a.Path)
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(filepath.Base(a.Path), ".exe")
+	exe := name
+	if a.Target.GOOS == "windows" {
+		exe += ".exe"
+	}
+	entries := []archiveEntry{
+		{base + "/" + exe, 0o755, bin},
+		{base + "/LICENSE", 0o644, opts.License},
+		{base + "/lessons.json", 0o644, opts.Manifest},
+	}
+	archive, write := base+".tar.gz", writeTarGz
+	if a.Target.GOOS == "windows" {
+		archive, write = base+".zip", writeZip
+	}
+	var b bytes.Buffer
+	if err := write(&b, entries, opts.ModTime); err != nil {
+		return "", err
+	}
+	tmp := filepath.Join(filepath.Dir(a.Path), "."+archive+".tmp")
+	if err := os.WriteFile(tmp, b.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	return archive, os.Rename(tmp, filepath.Join(filepath.Dir(a.Path), archive))
+}
+
+// writeTarGz leaves out everything that differs between machines: owner
+// names and ids, and the gzip header's name and time.
+func writeTarGz(w io.Writer, entries []archiveEntry, mtime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:    e.name,
+			Mode:    e.mode,
+			Size:    int64(len(e.data)),
+			ModTime: mtime,
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeZip(w io.Writer, entries []archiveEntry, mtime time.Time) error {
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: mtime}
+		hdr.SetMode(fs.FileMode(e.mode))
+		f, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(e.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// LessonManifest asks 158 for the course manifest, as 177 does.
+func LessonManifest() ([]byte, error) {
+	cmd := exec.Command("go", append([]string{"run", "158_go_parser_ast.go", "manifest"}, courseDirs...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("158 manifest: %w", err)
+	}
+	return out, nil
+}
+
+var courseDirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+// ---------------------------------------------------------
+// Part 5: Signing SHA256SUMS
+// ---------------------------------------------------------
+// SHA256SUMS catches a corrupted download, but not a swapped one: whoever
+// can replace an archive can rewrite the sums next to it. A MAC over the
+// sums file closes that gap for anyone holding the key.
+//
+// HMAC is a SHARED-SECRET signature: every verifier holds the key, so every
+// verifier could also sign. That fits a release pipeline checking its own
+// output (build job signs, publish job verifies). A public release, where
+// downloaders verify, needs a public-key signature — crypto/ed25519,
+// minisign, cosign — with the same shape: sign the sums file, not each
+// archive.
+
+const keyEnv = "GOTUT_RELEASE_KEY"
+
+// ReleaseKey reads the hex key from the environment, never from a flag
+// (flags end up in shell history and ps output).
+func ReleaseKey() ([]byte, error) {
+	v := os.Getenv(keyEnv)
+	if v == "" {
+		return nil, fmt.Errorf("%s is not set; generate one with: openssl rand -hex 32", keyEnv)
+	}
+	key, err := hex.DecodeString(v)
+	if err != nil || len(key) < sha256.Size {
+		return nil, fmt.Errorf("%s: want at least %d bytes, hex-encoded", keyEnv, sha256.Size)
+	}
+	return key, nil
+}
+
+func sumsMAC(dist string, key []byte) ([]byte, error) {
+	sums, err := os.ReadFile(filepath.Join(dist, "SHA256SUMS"))
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(sums)
+	return mac.Sum(nil), nil
+}
+
+// SignSums writes SHA256SUMS.hmac: "hmac-sha256 HEX".
+func SignSums(dist string, key []byte) error {
+	mac, err := sumsMAC(dist, key)
+	if err != nil {
+		return err
+	}
+	line := "hmac-sha256 " + hex.EncodeToString(mac) + "\n"
+	return os.WriteFile(filepath.Join(dist, "SHA256SUMS.hmac"), []byte(line), 0o644)
+}
+
+var ErrBadSignature = errors.New("SHA256SUMS.hmac does not match: wrong key, or the sums were changed")
+
+// VerifyRelease checks the signature first — sums that aren't signed
+// prove nothing — and then every file against the sums.
+func VerifyRelease(dist string, key []byte) error {
+	sig, err := os.ReadFile(filepath.Join(dist, "SHA256SUMS.hmac"))
+	if err != nil {
+		return err
+	}
+	alg, hexMAC, _ := strings.Cut(strings.TrimSpace(string(sig)), " ")
+	got, err := hex.DecodeString(hexMAC)
+	if alg != "hmac-sha256" || err != nil {
+		return fmt.Errorf("SHA256SUMS.hmac: want \"hmac-sha256 HEX\"")
+	}
+	want, err := sumsMAC(dist, key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(got, want) { // Constant time: no timing hints for a forger
+		return ErrBadSignature
+	}
+	return VerifySums(dist)
+}
+
+// Release packages every artifact, removes the loose binaries (they are in
+// the archives), and writes and signs SHA256SUMS over the archives.
+func Release(dist, name string, artifacts []Artifact, opts PackageOptions, key []byte) ([]string, error) {
+	var archives []string
+	for _, a := range artifacts {
+		archive, err := Package(a, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, archive)
+		if err := os.Remove(a.Path); err != nil {
+			return nil, err
+		}
+	}
+	if err := WriteSums(dist, archives); err != nil {
+		return nil, err
+	}
+	return archives, SignSums(dist, key)
+}
+
+// ---------------------------------------------------------
+// Part 6: The Command
+// ---------------------------------------------------------
+
+func toolXbuild(args []string) error {
+	fs := flag.NewFlagSet("xbuild", flag.ContinueOnError)
+	targets := fs.String("targets", defaultTargets, "comma-separated GOOS/GOARCH pairs")
+	dist := fs.String("o", "dist", "output directory")
+	name := fs.String("name", "", "artifact base name (default: the package or lesson name)")
+	pkg := fs.Bool("package", false, "archive each target with LICENSE and lessons.json; sign SHA256SUMS with $"+keyEnv)
+	license := fs.String("license", "../LICENSE", "license file to put in the archives (-package)")
+	verify := fs.String("verify", "", "check the signed SHA256SUMS in this directory, build nothing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *verify != "" {
+		key, err := ReleaseKey()
+		if err != nil {
+			return err
+		}
+		if err := VerifyRelease(*verify, key); err != nil {
+			return err
+		}
+		fmt.Println(filepath.Join(*verify, "SHA256SUMS"), "signature and checksums OK")
+		return nil
+	}
+	src := "gotut"
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		src = fs.Arg(0)
+	default:
+		return fmt.Errorf("want at most one package or file, got %q", fs.Args())
+	}
+	if *name == "" {
+		*name = strings.TrimSuffix(filepath.Base(src), ".go")
+	}
+	supported, err := SupportedTargets()
+	if err != nil {
+		return err
+	}
+	ts, err := ParseTargets(*targets, supported)
+	if err != nil {
+		return err
+	}
+	// Everything -package needs is checked before the slow part.
+	var opts PackageOptions
+	var key []byte
+	if *pkg {
+		if opts.License, err = os.ReadFile(*license); err != nil {
+			return fmt.Errorf("a release must say how it may be used: %w (pass -license FILE)", err)
+		}
+		if key, err = ReleaseKey(); err != nil {
+			return err
+		}
+		if opts.ModTime, err = SourceDateEpoch(); err != nil {
+			return err
+		}
+		if opts.Manifest, err = LessonManifest(); err != nil {
+			return err
+		}
+	}
+	artifacts, err := XBuild{Src: src, Name: *name, Dist: *dist}.Build(ts)
+	if err != nil {
+		return err
+	}
+	files := []string{}
+	if *pkg {
+		if files, err = Release(*dist, *name, artifacts, opts, key); err != nil {
+			return err
+		}
+	} else {
+		for _, a := range artifacts {
+			files = append(files, filepath.Base(a.Path))
+		}
+		if err := WriteSums(*dist, files); err != nil {
+			return err
+		}
+	}
+	for _, f := range files {
+		fmt.Println(filepath.Join(*dist, f))
+	}
+	fmt.Println(filepath.Join(*dist, "SHA256SUMS"))
+	if *pkg {
+		fmt.Println(filepath.Join(*dist, "SHA256SUMS.hmac"))
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 7: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// writePlatformPkg lays out a package whose answer depends on which file
+// the build picked: the file name is the only constraint. It is a module
+// of its own, as every package the go tool builds by directory must be.
+func writePlatformPkg(dir string) error {
+	files := map[string]string{
+		"go.mod":                 "module platform\n\ngo 1.25\n",
+		"main.go":                "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"built from\", platform) }\n",
+		"platform_linux.go":      "package main\n\nconst platform = \"platform_linux.go\"\n",
+		"platform_darwin.go":     "package main\n\nconst platform = \"platform_darwin.go\"\n",
+		"platform_windows.go":    "package main\n\nconst platform = \"platform_windows.go\"\n",
+		"platform_other.go":      "//go:build !linux && !darwin && !windows\n\npackage main\n\nconst platform = \"platform_other.go\"\n",
+		"accel_cgo.go":           "package main\n\n// int fast(void) { return 1; }\nimport \"C\"\n\nvar _ = C.fast\n",
+		"platform_linux_test.go": "package main\n\nimport \"testing\"\n\nfunc TestPlatform(t *testing.T) {}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listArchive reads an archive back: name, mode and size of each entry.
+func listArchive(path string) ([]string, error) {
+	var out []string
+	if strings.HasSuffix(path, ".zip") {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			out = append(out, fmt.Sprintf("%v %8d  %s", f.Mode(), f.UncompressedSize64, f.Name))
+		}
+		return out, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, fmt.Sprintf("%v %8d  %s", hdr.FileInfo().Mode(), hdr.Size, hdr.Name))
+	}
+}
+
+func names(artifacts []Artifact) []string {
+	var ns []string
+	for _, a := range artifacts {
+		ns = append(ns, filepath.Base(a.Path))
+	}
+	return ns
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: CROSS-COMPILATION")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: What This Go Can Target ---")
+	supported, err := SupportedTargets()
+	if err != nil {
+		return err
+	}
+	byOS := map[string][]string{}
+	for _, t := range supported {
+		byOS[t.GOOS] = append(byOS[t.GOOS], t.GOARCH)
+	}
+	for _, goos := range []string{"linux", "darwin", "windows"} {
+		fmt.Printf("  %-8s %s\n", goos, strings.Join(byOS[goos], " "))
+	}
+	fmt.Printf("  … %d pairs in all; this machine is %s/%s\n", len(supported), runtime.GOOS, runtime.GOARCH)
+	for _, tc := range []struct {
+		targets string
+		ok      bool
+	}{
+		{"linux/amd64,darwin/arm64", true},
+		{"linux-amd64", false},
+		{"plan9/arm64", false},
+		{"linux/amd64,linux/amd64", false},
+	} {
+		_, err := ParseTargets(tc.targets, supported)
+		result := "accepted"
+		if err != nil {
+			result = "refused: " + err.Error()
+		}
+		check((err == nil) == tc.ok, fmt.Sprintf("%-24s %s", tc.targets, result),
+			fmt.Sprintf("%s: %s", tc.targets, result))
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Artifact Names ---")
+	for _, t := range []Target{{"linux", "amd64"}, {"darwin", "arm64"}, {"windows", "amd64"}} {
+		fmt.Printf("  %-16s → %s\n", t, ArtifactName("gotut", t))
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Three Platforms From One Machine ---")
+	dir, err := os.MkdirTemp("", "demo-xbuild-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	pkg := filepath.Join(dir, "hello")
+	if err := os.Mkdir(pkg, 0o755); err != nil {
+		return err
+	}
+	if err := writePlatformPkg(pkg); err != nil {
+		return err
+	}
+	fmt.Println("  package: main.go, platform_{linux,darwin,windows,other}.go,")
+	fmt.Println("           accel_cgo.go (import \"C\"), platform_linux_test.go")
+	targets, err := ParseTargets(defaultTargets, supported)
+	if err != nil {
+		return err
+	}
+	dist := filepath.Join(dir, "dist")
+	x := XBuild{Src: pkg, Name: "hello", Dist: dist}
+	artifacts, err := x.Build(targets)
+	if err != nil {
+		return err
+	}
+	want := map[string]string{
+		"linux/amd64":   "ELF EM_X86_64",
+		"darwin/arm64":  "Mach-O CpuArm64",
+		"windows/amd64": "PE machine 0x8664",
+	}
+	for _, a := range artifacts {
+		got, err := Describe(a.Path)
+		check(err == nil && got == want[a.Target.String()],
+			fmt.Sprintf("%-16s %-24s %s", a.Target, filepath.Base(a.Path), got),
+			fmt.Sprintf("%s: %s %v, want %s", a.Target, got, err, want[a.Target.String()]))
+	}
+	// Only the native binary can run here; it tells which file it was built from.
+	native := filepath.Join(dist, ArtifactName("hello", Target{runtime.GOOS, runtime.GOARCH}))
+	if out, err := exec.Command(native).Output(); err == nil {
+		fmt.Printf("  running the %s one: %s", runtime.GOOS, out)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 4: CGO_ENABLED ---")
+	// Ask go list which files each configuration compiles.
+	for _, env := range [][]string{
+		{"GOOS=windows", "GOARCH=amd64", "CGO_ENABLED=0"},
+		{"GOOS=" + runtime.GOOS, "GOARCH=" + runtime.GOARCH, "CGO_ENABLED=1"},
+	} {
+		cmd := exec.Command("go", "list", "-f", "{{.GoFiles}} {{.CgoFiles}}", ".")
+		cmd.Dir = pkg
+		cmd.Env = append(os.Environ(), env...)
+		out, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("go list: %w", err)
+		}
+		fmt.Printf("  %-45s %s", strings.Join(env, " "), out)
+	}
+	fmt.Println("  With cgo off, accel_cgo.go is excluded like a file for another OS;")
+	fmt.Println("  _test.go files are never in a build. Write a pure-Go fallback.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: SHA256SUMS and Reproducibility ---")
+	if err := WriteSums(dist, names(artifacts)); err != nil {
+		return err
+	}
+	sums, err := os.ReadFile(filepath.Join(dist, "SHA256SUMS"))
+	if err != nil {
+		return err
+	}
+	for l := range strings.Lines(string(sums)) {
+		fmt.Print("  │ ", l[:16], "…", l[64:])
+	}
+	check(VerifySums(dist) == nil, "sha256sum -c equivalent: all OK", "the sums don't match the files")
+	again, err := XBuild{Src: pkg, Name: "hello", Dist: filepath.Join(dir, "dist2")}.Build(targets)
+	if err != nil {
+		return err
+	}
+	same := true
+	for i := range again {
+		same = same && again[i].SHA256 == artifacts[i].SHA256
+	}
+	check(same, "rebuilt into another directory with -trimpath: identical sums",
+		"the rebuild differs — the binaries depend on where they were built")
+	f, err := os.OpenFile(artifacts[0].Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write([]byte{0})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	err = VerifySums(dist)
+	check(err != nil, "one byte appended to a binary: "+fmt.Sprint(err), "the tampered binary passed")
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Release Archives ---")
+	manifest, err := LessonManifest()
+	if err != nil {
+		return err
+	}
+	opts := PackageOptions{
+		License:  []byte("Demo License: do as you like.\n"),
+		Manifest: manifest,
+		ModTime:  epoch,
+	}
+	key := bytes.Repeat([]byte{0x42}, sha256.Size) // The real one comes from $GOTUT_RELEASE_KEY
+	release := func(dist string, artifacts []Artifact) ([]string, error) {
+		return Release(dist, "hello", artifacts, opts, key)
+	}
+	rel := filepath.Join(dir, "dist2")
+	archives, err := release(rel, again)
+	if err != nil {
+		return err
+	}
+	for _, archive := range archives {
+		listing, err := listArchive(filepath.Join(rel, archive))
+		if err != nil {
+			return err
+		}
+		fmt.Println(" ", archive)
+		for _, l := range listing {
+			fmt.Println("     ", l)
+		}
+	}
+	// Build and package once more elsewhere: the archives must match too.
+	rel2 := filepath.Join(dir, "dist3")
+	third, err := XBuild{Src: pkg, Name: "hello", Dist: rel2}.Build(targets)
+	if err != nil {
+		return err
+	}
+	if _, err := release(rel2, third); err != nil {
+		return err
+	}
+	sums1, err1 := os.ReadFile(filepath.Join(rel, "SHA256SUMS"))
+	sums2, err2 := os.ReadFile(filepath.Join(rel2, "SHA256SUMS"))
+	check(err1 == nil && err2 == nil && bytes.Equal(sums1, sums2),
+		"a second build and package gives the same SHA256SUMS: no timestamps, owners or paths inside",
+		"the archives differ between runs")
+	fmt.Println()
+
+	fmt.Println("--- Example 7: Signed SHA256SUMS ---")
+	check(VerifyRelease(rel, key) == nil, "signature and every checksum verify", "the fresh release failed to verify")
+	err = VerifyRelease(rel, bytes.Repeat([]byte{0x24}, sha256.Size))
+	check(errors.Is(err, ErrBadSignature), "another key: "+fmt.Sprint(err), "a wrong key verified")
+	// An attacker swaps an archive and rewrites SHA256SUMS to match.
+	swapped := filepath.Join(rel, archives[0])
+	if err := os.WriteFile(swapped, []byte("not the real archive"), 0o644); err != nil {
+		return err
+	}
+	if err := WriteSums(rel, archives); err != nil {
+		return err
+	}
+	check(VerifySums(rel) == nil && errors.Is(VerifyRelease(rel, key), ErrBadSignature),
+		"archive swapped and sums rewritten: sha256sum -c passes, the signature doesn't",
+		"the swap went unnoticed")
+	fmt.Println("  HMAC proves the sums came from a key holder; for downloaders, sign with")
+	fmt.Println("  a public key (ed25519, minisign, cosign) instead — same file, same flow.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. GOOS and GOARCH pick the target; the toolchain already has every platform.
+2. Set CGO_ENABLED=0 for release builds: cgo needs a C compiler per target.
+3. File suffixes (_linux.go, _windows_amd64.go) are build constraints.
+4. Name artifacts NAME_GOOS_GOARCH, with .exe on Windows.
+5. Ship SHA256SUMS; build with -trimpath so the sums are reproducible.
+6. Check the result with debug/elf, debug/macho, debug/pe, not by running it.
+7. Ship archives: tar.gz for Unix, zip for Windows, with LICENSE inside.
+8. Fix every timestamp and owner in the archives (SOURCE_DATE_EPOCH).
+9. Sign SHA256SUMS, not each file; verify the signature before the sums.
+	`)
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch {
+		case len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "xbuild":
+			err = toolXbuild(os.Args[3:])
+		default:
+			err = fmt.Errorf("unknown command %q (want: tool xbuild)", strings.Join(os.Args[1:], " "))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "xbuild:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,596 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+TOPIC: LAZY REGISTRATION — PAY FOR CONTENT WHEN IT'S USED
+
+CONCEPT:
+A single binary that carries the whole course — every lesson's text, so
+"gotut show 83" works without the source tree — has an obvious design: each
+lesson is a Go file that registers itself.
+
+    func init() { register(Lesson{Num: 83, Title: "...", Source: "...40 KB..."}) }
+
+That's how database/sql drivers and image decoders do it, and for a few
+small entries it is right. For ~140 lesson files and 1.4 MB of text — and
+growing with every topic — it has costs:
+
+    binary size   every byte of every literal is in the executable
+    init          every init runs before main, even for "gotut list"
+    memory        the registry's slice, map and headers are built eagerly
+
+THE LAZY REDESIGN keeps a small index in Go and everything else in ONE
+embedded, compressed asset that is opened only when a lesson is asked for:
+
+    var index = []Lesson{{Num: 83, Title: "...", Path: "83_sha.go"}, ...}
+
+    //go:embed lessons.zip
+    var lessonsZip []byte                  ← deflated, ~3x smaller
+
+    Source(i) → zip.NewReader once (sync.OnceValues), then open ONE entry,
+                inflate it, cache it.
+
+Zip, not tar.gz, because a zip has a central directory: any entry can be read
+without inflating the ones before it. A .tar.gz is one stream; reading the
+last lesson means inflating all of them.
+
+MEASURED, NOT ASSUMED: this lesson generates both programs from the real
+course, builds them, and measures what each costs: binary size, wall time
+of "list", the main package's init cost (GODEBUG=inittrace=1), heap and
+RSS, and the price of the first Source() call.
+
+TRADE-OFFS (on this course, roughly: 5.3 vs 4.7 MB; init 16 KB in 8 allocs
+vs nothing; first Source() free vs ~0.4 ms):
+    • Lazy is smaller on disk and does nothing at init. The first look at
+      a lesson pays to inflate it, its heap grows by what it inflated, and
+      errors that were compile errors (a missing lesson) become runtime
+      errors.
+    • String literals live in read-only data and are not copied, so eager
+      init is cheaper than it looks. The win is size first, init second —
+      and both grow with the course, which is the reason to switch before
+      they hurt. Measure before redesigning.
+    • The index must stay in sync with the asset: generate both in one step
+      (as 158 generates its registry), never edit either by hand.
+
+RUN:
+    go run 180_lazy_registry.go                  (demo: generates, builds and measures)
+    go run 180_lazy_registry.go measure -runs 50
+    go run 180_lazy_registry.go build -o /tmp/registry    (keeps eager.bin, lazy.bin)
+*/
+
+var courseDirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+// ---------------------------------------------------------
+// Part 1: Collecting the Course
+// ---------------------------------------------------------
+
+type Lesson struct {
+	Num    int
+	Title  string
+	Path   string
+	Source string
+}
+
+var (
+	numPrefix = regexp.MustCompile(`^(\d+)_`)
+	topicLine = regexp.MustCompile(`(?mi)^\s*topic(?: \d+)?:\s*(.+?)\s*$`)
+)
+
+// CollectLessons reads every numbered .go file under roots: the content a
+// self-contained gotut would have to carry. Test files stay out.
+func CollectLessons(roots ...string) ([]Lesson, error) {
+	var lessons []Lesson
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			m := numPrefix.FindStringSubmatch(filepath.Base(path))
+			if m == nil {
+				m = numPrefix.FindStringSubmatch(filepath.Base(filepath.Dir(path)))
+			}
+			if m == nil {
+				return nil
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			num, _ := strconv.Atoi(m[1])
+			title := strings.TrimSuffix(filepath.Base(path), ".go")
+			if t := topicLine.FindSubmatch(src); t != nil {
+				title = string(t[1])
+			}
+			// Paths relative to the repository: "../x" is not a valid fs.FS
+			// name, and the zip is read through fs.FS.
+			rel := filepath.ToSlash(filepath.Join("go_projects", path))
+			lessons = append(lessons, Lesson{Num: num, Title: title, Path: rel, Source: string(src)})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(lessons, func(a, b Lesson) int {
+		if a.Num != b.Num {
+			return a.Num - b.Num
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return lessons, nil
+}
+
+// ---------------------------------------------------------
+// Part 2: Generating Both Designs
+// ---------------------------------------------------------
+
+// mainSrc is shared by both programs; each design supplies Count, Title
+// and Source. It prints one JSON line of measurements, so the parent
+// compares like with like.
+const mainSrc = `package main
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Lesson struct {
+	Num    int
+	Title  string
+	Path   string
+	Source string
+}
+
+type Stats struct {
+	Lessons   int     ` + "`json:\"lessons\"`" + `
+	HeapAlloc uint64  ` + "`json:\"heap_alloc\"`" + `
+	RSSKB     int     ` + "`json:\"rss_kb\"`" + `
+	FirstNS   int64   ` + "`json:\"first_ns,omitempty\"`" + `
+	AgainNS   int64   ` + "`json:\"again_ns,omitempty\"`" + `
+}
+
+// rssKB reads VmRSS where /proc exists; elsewhere it reports 0.
+func rssKB() int {
+	b, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return 0
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		if v, ok := strings.CutPrefix(l, "VmRSS:"); ok {
+			n, _ := strconv.Atoi(strings.Fields(v)[0])
+			return n
+		}
+	}
+	return 0
+}
+
+func main() {
+	var st Stats
+	switch os.Args[1] {
+	case "list":
+		w := 0
+		for i := range Count() {
+			w += len(Title(i))
+		}
+		st.Lessons = Count()
+		_ = w
+	case "show":
+		i, _ := strconv.Atoi(os.Args[2])
+		start := time.Now()
+		first, err := Source(i)
+		st.FirstNS = int64(time.Since(start))
+		start = time.Now()
+		again, _ := Source(i)
+		st.AgainNS = int64(time.Since(start))
+		if err != nil || first != again {
+			os.Exit(1)
+		}
+		st.Lessons = 1
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	st.HeapAlloc, st.RSSKB = m.HeapAlloc, rssKB()
+	json.NewEncoder(os.Stdout).Encode(st)
+}
+`
+
+// EagerSource is the self-registering design: one init per lesson.
+func EagerSource(lessons []Lesson) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by 180_lazy_registry.go; DO NOT EDIT.\n\npackage main\n\n")
+	b.WriteString("var registry []Lesson\n\nfunc register(l Lesson) { registry = append(registry, l) }\n\n")
+	b.WriteString("func Count() int { return len(registry) }\n\n")
+	b.WriteString("func Title(i int) string { return registry[i].Title }\n\n")
+	b.WriteString("func Source(i int) (string, error) { return registry[i].Source, nil }\n")
+	for _, l := range lessons {
+		fmt.Fprintf(&b, "\nfunc init() {\n\tregister(Lesson{Num: %d, Title: %q, Path: %q, Source: %q})\n}\n",
+			l.Num, l.Title, l.Path, l.Source)
+	}
+	return format.Source(b.Bytes())
+}
+
+const lazyLoader = `
+//go:embed lessons.zip
+var lessonsZip []byte
+
+var archive = sync.OnceValues(func() (*zip.Reader, error) {
+	return zip.NewReader(bytes.NewReader(lessonsZip), int64(len(lessonsZip)))
+})
+
+var sources sync.Map // Path → inflated source, filled on first use
+
+func Count() int { return len(index) }
+
+func Title(i int) string { return index[i].Title }
+
+func Source(i int) (string, error) {
+	path := index[i].Path
+	if s, ok := sources.Load(path); ok {
+		return s.(string), nil
+	}
+	zr, err := archive()
+	if err != nil {
+		return "", err
+	}
+	f, err := zr.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	s, _ := sources.LoadOrStore(path, string(b))
+	return s.(string), nil
+}
+`
+
+// LazySource is the redesign: the index in Go, the content in a zip that
+// is generated in the same step, so the two can't drift.
+func LazySource(lessons []Lesson) (src, asset []byte, err error) {
+	var z bytes.Buffer
+	zw := zip.NewWriter(&z)
+	for _, l := range lessons {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: l.Path, Method: zip.Deflate})
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := f.Write([]byte(l.Source)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString("// Code generated by 180_lazy_registry.go; DO NOT EDIT.\n\npackage main\n\n")
+	b.WriteString("import (\n\t\"archive/zip\"\n\t\"bytes\"\n\t_ \"embed\"\n\t\"io\"\n\t\"sync\"\n)\n\n")
+	b.WriteString("var index = []Lesson{\n")
+	for _, l := range lessons {
+		fmt.Fprintf(&b, "{Num: %d, Title: %q, Path: %q},\n", l.Num, l.Title, l.Path)
+	}
+	b.WriteString("}\n")
+	b.WriteString(lazyLoader)
+	src, err = format.Source(b.Bytes())
+	return src, z.Bytes(), err
+}
+
+// ---------------------------------------------------------
+// Part 3: Building and Measuring
+// ---------------------------------------------------------
+
+type Stats struct {
+	Lessons   int    `json:"lessons"`
+	HeapAlloc uint64 `json:"heap_alloc"`
+	RSSKB     int    `json:"rss_kb"`
+	FirstNS   int64  `json:"first_ns,omitempty"`
+	AgainNS   int64  `json:"again_ns,omitempty"`
+}
+
+type Measurement struct {
+	Design     string
+	Size       int64         // Bytes on disk
+	Startup    time.Duration // Median wall time of "list", process start to exit
+	InitBytes  int           // Heap allocated by package main's init, from GODEBUG=inittrace=1
+	InitAllocs int
+	List, Show Stats
+}
+
+// build writes one program into dir/name and builds it.
+func build(dir, name string, files map[string][]byte) (string, error) {
+	src := filepath.Join(dir, name)
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		return "", err
+	}
+	files["main.go"] = []byte(mainSrc)
+	files["go.mod"] = []byte("module " + name + "\n\ngo 1.25\n")
+	for f, b := range files {
+		if err := os.WriteFile(filepath.Join(src, f), b, 0o644); err != nil {
+			return "", err
+		}
+	}
+	bin := filepath.Join(dir, name+".bin")
+	cmd := exec.Command("go", "build", "-trimpath", "-o", bin, ".")
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s: %w\n%s", name, err, out)
+	}
+	return bin, nil
+}
+
+func runStats(bin string, env []string, args ...string) (Stats, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(bin, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return Stats{}, "", fmt.Errorf("%s %v: %w", filepath.Base(bin), args, err)
+	}
+	var st Stats
+	err := json.Unmarshal(stdout.Bytes(), &st)
+	return st, stderr.String(), err
+}
+
+var initMain = regexp.MustCompile(`(?m)^init main @\S+ ms, \S+ ms clock, (\d+) bytes, (\d+) allocs`)
+
+// Measure runs bin the ways a user would: "list" many times for the
+// startup median, once under inittrace, and "show" for the first access.
+func Measure(design, bin string, runs, show int) (Measurement, error) {
+	m := Measurement{Design: design}
+	fi, err := os.Stat(bin)
+	if err != nil {
+		return m, err
+	}
+	m.Size = fi.Size()
+	var times []time.Duration
+	for range runs {
+		start := time.Now()
+		if m.List, _, err = runStats(bin, nil, "list"); err != nil {
+			return m, err
+		}
+		times = append(times, time.Since(start))
+	}
+	slices.Sort(times)
+	m.Startup = times[len(times)/2]
+	_, trace, err := runStats(bin, []string{"GODEBUG=inittrace=1"}, "list")
+	if err != nil {
+		return m, err
+	}
+	if t := initMain.FindStringSubmatch(trace); t != nil { // No line: main had nothing to init
+		m.InitBytes, _ = strconv.Atoi(t[1])
+		m.InitAllocs, _ = strconv.Atoi(t[2])
+	}
+	m.Show, _, err = runStats(bin, nil, "show", strconv.Itoa(show))
+	return m, err
+}
+
+// BuildBoth generates both designs into dir and builds them as
+// dir/eager.bin and dir/lazy.bin.
+func BuildBoth(lessons []Lesson, dir string) (eagerBin, lazyBin string, err error) {
+	eager, err := EagerSource(lessons)
+	if err != nil {
+		return "", "", err
+	}
+	lazy, asset, err := LazySource(lessons)
+	if err != nil {
+		return "", "", err
+	}
+	if eagerBin, err = build(dir, "eager", map[string][]byte{"registry_gen.go": eager}); err != nil {
+		return "", "", err
+	}
+	lazyBin, err = build(dir, "lazy", map[string][]byte{"index_gen.go": lazy, "lessons.zip": asset})
+	return eagerBin, lazyBin, err
+}
+
+// Compare generates, builds and measures both designs in a temp dir.
+func Compare(lessons []Lesson, runs int) ([]Measurement, error) {
+	dir, err := os.MkdirTemp("", "lazy-registry-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	eagerBin, lazyBin, err := BuildBoth(lessons, dir)
+	if err != nil {
+		return nil, err
+	}
+	// The biggest lesson: the worst case for a first access.
+	show := 0
+	for i, l := range lessons {
+		if len(l.Source) > len(lessons[show].Source) {
+			show = i
+		}
+	}
+	var ms []Measurement
+	for _, d := range []struct{ name, bin string }{{"eager", eagerBin}, {"lazy", lazyBin}} {
+		m, err := Measure(d.name, d.bin, runs, show)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func mb(n int64) string { return fmt.Sprintf("%.1f MB", float64(n)/(1<<20)) }
+
+func printTable(ms []Measurement) {
+	fmt.Printf("  %-26s %16s %16s\n", "", ms[0].Design, ms[1].Design)
+	row := func(label string, f func(Measurement) string) {
+		fmt.Printf("  %-26s %16s %16s\n", label, f(ms[0]), f(ms[1]))
+	}
+	row("binary size", func(m Measurement) string { return mb(m.Size) })
+	row("list: median wall time", func(m Measurement) string { return m.Startup.Round(10 * time.Microsecond).String() })
+	row("init main", func(m Measurement) string { return fmt.Sprintf("%d B, %d allocs", m.InitBytes, m.InitAllocs) })
+	row("list: heap in use", func(m Measurement) string { return fmt.Sprintf("%d KB", m.List.HeapAlloc>>10) })
+	row("list: RSS", func(m Measurement) string { return fmt.Sprintf("%d KB", m.List.RSSKB) })
+	row("show: first Source()", func(m Measurement) string {
+		return time.Duration(m.Show.FirstNS).Round(time.Microsecond).String()
+	})
+	row("show: cached Source()", func(m Measurement) string { return time.Duration(m.Show.AgainNS).String() })
+	row("show: heap in use", func(m Measurement) string { return fmt.Sprintf("%d KB", m.Show.HeapAlloc>>10) })
+}
+
+// ---------------------------------------------------------
+// Part 4: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func demo(runs int) error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: LAZY REGISTRATION")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	fmt.Println("--- Example 1: The Content ---")
+	lessons, err := CollectLessons(courseDirs...)
+	if err != nil {
+		return err
+	}
+	var total int
+	for _, l := range lessons {
+		total += len(l.Source)
+	}
+	fmt.Printf("  %d lesson files, %s of source; the biggest:\n", len(lessons), mb(int64(total)))
+	bySize := slices.Clone(lessons)
+	slices.SortFunc(bySize, func(a, b Lesson) int { return len(b.Source) - len(a.Source) })
+	for _, l := range bySize[:3] {
+		fmt.Printf("    %7d bytes  %s\n", len(l.Source), l.Path)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: The Two Designs, Generated ---")
+	eager, err := EagerSource(lessons[:1])
+	if err != nil {
+		return err
+	}
+	lazy, asset, err := LazySource(lessons)
+	if err != nil {
+		return err
+	}
+	for l := range strings.Lines(string(eager)) {
+		if strings.HasPrefix(l, "func init") || strings.HasPrefix(l, "\tregister") {
+			if len(l) > 90 {
+				l = l[:90] + "…\n"
+			}
+			fmt.Print("  eager │ ", l)
+		}
+	}
+	fmt.Printf("  lazy  │ var index = []Lesson{ … %d entries, no Source … }\n", len(lessons))
+	fmt.Printf("  lazy  │ //go:embed lessons.zip   (%s, deflated from %s)\n", mb(int64(len(asset))), mb(int64(total)))
+	check(bytes.Contains(lazy, []byte("sync.OnceValues")), "the zip is opened once, on the first Source()", "no lazy open")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Built and Measured ---")
+	fmt.Printf("  (building both from the real course, then %d runs of \"list\" each)\n", runs)
+	ms, err := Compare(lessons, runs)
+	if err != nil {
+		return err
+	}
+	printTable(ms)
+	e, l := ms[0], ms[1]
+	check(l.Size < e.Size, fmt.Sprintf("lazy binary is %s smaller", mb(e.Size-l.Size)), "lazy is not smaller")
+	check(e.InitAllocs > l.InitAllocs,
+		"eager builds its registry before main; lazy only sets up its once-func",
+		"lazy allocates as much at init as eager")
+	check(e.Show.FirstNS < l.Show.FirstNS, "eager's first Source() is free; lazy pays to inflate once",
+		"lazy's first access was as cheap as eager's")
+	check(l.Show.AgainNS < l.Show.FirstNS, "after that lazy serves from its cache", "the cache didn't help")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Reading the Numbers ---")
+	fmt.Println("  • Size is the clear win: literals are stored as-is, the zip is deflated.")
+	fmt.Println("  • Startup differs by little: literals sit in read-only data and are not")
+	fmt.Println("    copied; the eager cost is the init calls and the registry slice.")
+	fmt.Println("  • The first Source() moves from build time to run time, once per lesson,")
+	fmt.Println("    and lazy's heap grows by what it inflated: pay per lesson shown.")
+	fmt.Println("  • A missing lesson was a compile error; now it's an error from Source().")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. init-time registration is simple and right for small registries.
+2. For bulk content, embed one compressed asset and index it in Go.
+3. Use zip for random access; tar.gz must be inflated from the start.
+4. Open lazily with sync.OnceValues; cache what you inflate.
+5. Generate the index and the asset together so they can't drift.
+6. Measure size, init (GODEBUG=inittrace=1), heap and first access first.
+	`)
+	return nil
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 {
+		fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+		runs := fs.Int("runs", 20, "runs of each binary for the startup median (measure)")
+		out := fs.String("o", ".", "directory for eager.bin and lazy.bin (build)")
+		fs.Parse(args[1:])
+		lessons, err := CollectLessons(courseDirs...)
+		switch {
+		case err != nil:
+		case args[0] == "measure":
+			var ms []Measurement
+			if ms, err = Compare(lessons, *runs); err == nil {
+				printTable(ms)
+			}
+		case args[0] == "build": // Keeps the binaries, for 181's size breakdown
+			var eagerBin, lazyBin string
+			if eagerBin, lazyBin, err = BuildBoth(lessons, *out); err == nil {
+				fmt.Println(eagerBin)
+				fmt.Println(lazyBin)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q (want: measure or build)\n", args[0])
+			os.Exit(2)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, args[0]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := demo(10); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,576 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+TOPIC: WHAT MAKES A GO BINARY BIG — READING IT WITH nm, debug/elf AND buildinfo
+
+CONCEPT:
+"The binary is 5 MB" is a symptom. Before trimming anything, find out where
+the bytes are. Three standard tools answer three different questions:
+
+    debug/elf, debug/macho, debug/pe   SECTIONS: code, read-only data, the
+                                       function table, DWARF, symbols
+    go tool nm -size -sort size        SYMBOLS: which package owns which bytes
+    debug/buildinfo (go version -m)    HOW it was built: Go version, GOOS,
+                                       CGO_ENABLED, -trimpath, -ldflags
+
+WHERE THE BYTES GO in a typical Go binary:
+    .text            machine code
+    .rodata          constants, string data, type descriptors
+    .gopclntab       function names, file:line tables — what makes stack
+                     traces and runtime.Caller work; it can't be stripped
+    .debug_*         DWARF for debuggers; -ldflags=-w drops it
+    .symtab/.strtab  the symbol table nm reads; -ldflags=-s drops it
+
+WHAT nm CAN'T SEE: string data. Every string literal and every //go:embed
+string or embed.FS file lands in one symbol, go:string.*, of size 0. A
+//go:embed []byte gets its own symbol (main..gobytes.1), as does the backing
+array of a []byte literal. So a binary full of literals shows nothing in
+the symbol table — only .rodata grows. That is why this tool reports
+sections AND symbols.
+
+    gotut tool bloat                  builds gotut (go_projects/gotut) and reports
+    gotut tool bloat -top 20 ./bin    reports on an existing binary
+
+The data behind Topic 180's redesign: Example 5 breaks down its eager and
+lazy binaries. The eager binary's lesson text is invisible in nm and shows
+up only as .rodata.
+
+RUN:
+    go run 181_bloat.go                                      (demo)
+    go run 181_bloat.go tool bloat
+    go run 181_bloat.go tool bloat 179_xbuild.go
+    go run 181_bloat.go tool bloat -top 25 /path/to/binary
+*/
+
+// ---------------------------------------------------------
+// Part 1: Sections
+// ---------------------------------------------------------
+
+type Section struct {
+	Name string
+	Size int64 // Bytes in the file; zero-filled sections (.bss) take none
+}
+
+// Sections reads the section table of an ELF, Mach-O or PE file.
+func Sections(path string) ([]Section, error) {
+	var out []Section
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		for _, s := range f.Sections {
+			if s.Type != elf.SHT_NOBITS && s.Name != "" {
+				out = append(out, Section{s.Name, int64(s.FileSize)})
+			}
+		}
+		return out, nil
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		const zerofill = 0x1 // S_ZEROFILL in the low byte of Flags
+		for _, s := range f.Sections {
+			if s.Flags&0xff != zerofill {
+				out = append(out, Section{s.Name, int64(s.Size)})
+			}
+		}
+		return out, nil
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		for _, s := range f.Sections {
+			out = append(out, Section{s.Name, int64(s.Size)})
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%s: not an ELF, Mach-O or PE executable", path)
+}
+
+// sectionKind groups section names the three formats spell differently
+// (.text / __text, .rodata / __rodata / .rdata, .debug_info / __zdebug_info).
+func sectionKind(name string) string {
+	n := strings.ToLower(name)
+	switch {
+	case strings.Contains(n, "debug"):
+		return "DWARF debug info"
+	case strings.Contains(n, "pclntab"):
+		return "func table (pclntab)"
+	case strings.Contains(n, "symtab") || strings.HasSuffix(n, ".strtab"):
+		return "symbol table"
+	case strings.Contains(n, "text"):
+		return "code"
+	case strings.Contains(n, "rodata") || n == ".rdata" || strings.Contains(n, "typelink") ||
+		strings.Contains(n, "itablink") || strings.Contains(n, "go.type") || strings.Contains(n, "go.func"):
+		return "read-only data"
+	case strings.Contains(n, "data"):
+		return "data"
+	}
+	return "other"
+}
+
+type Row struct {
+	Name string
+	Size int64
+}
+
+// ByKind totals sections by kind, biggest first. What the sections don't
+// cover — file headers, alignment padding — is its own row, so the rows
+// add up to the file size.
+func ByKind(sections []Section, fileSize int64) []Row {
+	totals := map[string]int64{}
+	var covered int64
+	for _, s := range sections {
+		totals[sectionKind(s.Name)] += s.Size
+		covered += s.Size
+	}
+	if fileSize > covered {
+		totals["headers and padding"] += fileSize - covered
+	}
+	return sortedRows(totals)
+}
+
+func sortedRows(totals map[string]int64) []Row {
+	var rows []Row
+	for name, size := range totals {
+		rows = append(rows, Row{name, size})
+	}
+	slices.SortFunc(rows, func(a, b Row) int {
+		if a.Size != b.Size {
+			return int(b.Size - a.Size)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rows
+}
+
+// ---------------------------------------------------------
+// Part 2: Symbols by Package
+// ---------------------------------------------------------
+
+type Symbol struct {
+	Name string
+	Size int64
+	Type byte // nm's letter: T code, R read-only, D data, B bss; lower case = local
+}
+
+// Symbols runs go tool nm, which reads the symbol table of any Go binary
+// for any GOOS — it fails on one built with -ldflags=-s.
+func Symbols(path string) ([]Symbol, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "tool", "nm", "-size", "-sort", "size", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go tool nm: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	var syms []Symbol
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		// ADDR SIZE TYPE NAME — the name itself may contain spaces
+		// (generic instances: HashTrieMap[interface {},interface {}]).
+		f := strings.Fields(sc.Text())
+		if len(f) < 4 || len(f[2]) != 1 {
+			continue
+		}
+		size, err := strconv.ParseInt(f[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		syms = append(syms, Symbol{Name: strings.Join(f[3:], " "), Size: size, Type: f[2][0]})
+	}
+	return syms, sc.Err()
+}
+
+// Package is the import path a symbol belongs to: everything up to the
+// first dot after the last slash. Linker-made symbols (go:func.*, type:*)
+// are grouped under their prefix.
+func Package(sym string) string {
+	if strings.HasPrefix(sym, "go:") || strings.HasPrefix(sym, "type:") {
+		prefix, _, _ := strings.Cut(sym, ".")
+		return "(" + strings.TrimSuffix(prefix, ":*") + ")"
+	}
+	name := sym
+	if i := strings.IndexAny(name, "[("); i >= 0 {
+		name = name[:i] // Generic arguments and receivers may hold slashes of their own
+	}
+	last := strings.LastIndex(name, "/")
+	if i := strings.Index(sym[last+1:], "."); i >= 0 {
+		return sym[:last+1+i]
+	}
+	return sym
+}
+
+// IsByteData is the backing array of a //go:embed []byte or a []byte
+// literal: the one kind of bulk data with a symbol of its own. Strings and
+// embed.FS files are in go:string.*.
+func IsByteData(sym string) bool { return strings.Contains(sym, "..gobytes.") }
+
+type PackageRow struct {
+	Package    string
+	Code, Data int64
+	Bytes      int64 // The part of Data that is []byte data: go:embed or literals
+}
+
+func (r PackageRow) Total() int64 { return r.Code + r.Data }
+
+// ByPackage totals the symbols that take space in the file. B and b
+// (zeroed memory) exist only at run time and are left out.
+func ByPackage(syms []Symbol) []PackageRow {
+	rows := map[string]*PackageRow{}
+	for _, s := range syms {
+		kind := s.Type | 0x20 // Lower-case: local and global count alike
+		if kind == 'b' || s.Size == 0 {
+			continue
+		}
+		pkg := Package(s.Name)
+		r := rows[pkg]
+		if r == nil {
+			r = &PackageRow{Package: pkg}
+			rows[pkg] = r
+		}
+		if kind == 't' {
+			r.Code += s.Size
+			continue
+		}
+		r.Data += s.Size
+		if IsByteData(s.Name) {
+			r.Bytes += s.Size
+		}
+	}
+	var out []PackageRow
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	slices.SortFunc(out, func(a, b PackageRow) int {
+		if a.Total() != b.Total() {
+			return int(b.Total() - a.Total())
+		}
+		return strings.Compare(a.Package, b.Package)
+	})
+	return out
+}
+
+// ---------------------------------------------------------
+// Part 3: The Report
+// ---------------------------------------------------------
+
+func kb(n int64) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%.2f MB", float64(n)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+}
+
+func pct(n, of int64) string { return fmt.Sprintf("%5.1f%%", 100*float64(n)/float64(of)) }
+
+// BuildLine is go version -m in one line: only the settings that change
+// the size or the behaviour of the binary.
+func BuildLine(path string) string {
+	bi, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return "no build info: " + err.Error()
+	}
+	parts := []string{bi.GoVersion}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "GOOS", "GOARCH", "CGO_ENABLED", "-trimpath", "-ldflags", "-tags":
+			parts = append(parts, s.Key+"="+s.Value)
+		}
+	}
+	if len(bi.Deps) == 0 {
+		parts = append(parts, "no module deps")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d module deps", len(bi.Deps)))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// Report writes the whole breakdown for one binary.
+func Report(w io.Writer, path string, top int) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	sections, err := Sections(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: %s\n  %s\n\n", filepath.Base(path), kb(fi.Size()), BuildLine(path))
+	fmt.Fprintf(w, "  %-24s %10s %7s\n", "SECTIONS", "size", "")
+	for _, r := range ByKind(sections, fi.Size()) {
+		fmt.Fprintf(w, "  %-24s %10s %7s\n", r.Name, kb(r.Size), pct(r.Size, fi.Size()))
+	}
+	syms, err := Symbols(path)
+	if err != nil {
+		fmt.Fprintf(w, "\n  no symbol breakdown: %v\n", err)
+		return nil
+	}
+	rows := ByPackage(syms)
+	var attributed int64
+	for _, r := range rows {
+		attributed += r.Total()
+	}
+	fmt.Fprintf(w, "\n  %-34s %10s %10s %10s %7s\n", "PACKAGES (by symbol)", "code", "data", "total", "")
+	for i, r := range rows {
+		if i == top {
+			var rest int64
+			for _, r := range rows[top:] {
+				rest += r.Total()
+			}
+			fmt.Fprintf(w, "  %-34s %10s %10s %10s %7s\n", fmt.Sprintf("… %d more", len(rows)-top), "", "", kb(rest), pct(rest, attributed))
+			break
+		}
+		name := r.Package
+		if r.Bytes >= 1<<10 {
+			name += fmt.Sprintf(" (%s []byte)", kb(r.Bytes))
+		}
+		fmt.Fprintf(w, "  %-34s %10s %10s %10s %7s\n", name, kb(r.Code), kb(r.Data), kb(r.Total()), pct(r.Total(), attributed))
+	}
+	fmt.Fprintf(w, "  symbols account for %s of %s; string data (literals, embedded\n", kb(attributed), kb(fi.Size()))
+	fmt.Fprintln(w, "  strings and embed.FS files) has no symbol — see read-only data above.")
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 4: The Command
+// ---------------------------------------------------------
+
+// buildTarget builds a package directory or a single .go file into dir.
+func buildTarget(src, dir string, ldflags string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(src), ".go")
+	bin := filepath.Join(dir, name)
+	args := []string{"build", "-trimpath", "-o", bin}
+	if ldflags != "" {
+		args = append(args, "-ldflags="+ldflags)
+	}
+	cmd := exec.Command("go")
+	if strings.HasSuffix(src, ".go") {
+		cmd.Dir, args = filepath.Dir(src), append(args, filepath.Base(src))
+	} else {
+		cmd.Dir, args = src, append(args, ".") // From inside its module, as gotut/ is one of its own
+	}
+	cmd.Args = append(cmd.Args, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s: %w\n%s", src, err, out)
+	}
+	return bin, nil
+}
+
+func toolBloat(args []string) error {
+	fs := flag.NewFlagSet("bloat", flag.ContinueOnError)
+	top := fs.Int("top", 12, "packages to list before summing the rest")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target := "gotut"
+	if fs.NArg() > 1 {
+		return fmt.Errorf("want at most one binary, package or file, got %q", fs.Args())
+	}
+	if fs.NArg() == 1 {
+		target = fs.Arg(0)
+	}
+	// A lesson file or a package directory is built first; anything else
+	// is taken to be a binary.
+	if fi, err := os.Stat(target); err == nil && (fi.IsDir() || strings.HasSuffix(target, ".go")) {
+		dir, err := os.MkdirTemp("", "bloat-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		if target, err = buildTarget(target, dir, ""); err != nil {
+			return err
+		}
+	}
+	return Report(os.Stdout, target, *top)
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func indent(s string) {
+	for l := range strings.Lines(s) {
+		fmt.Print("  ", l)
+	}
+}
+
+func sizeOf(path string) int64 {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+// kindSize is one row of ByKind, 0 if the binary has no such section.
+func kindSize(path, kind string) int64 {
+	sections, err := Sections(path)
+	if err != nil {
+		return 0
+	}
+	for _, r := range ByKind(sections, sizeOf(path)) {
+		if r.Name == kind {
+			return r.Size
+		}
+	}
+	return 0
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: WHAT MAKES A GO BINARY BIG")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "demo-bloat-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	fmt.Println("--- Example 1: gotut, Section by Section and Package by Package ---")
+	gotut, err := buildTarget("gotut", dir, "")
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if err := Report(&b, gotut, 10); err != nil {
+		return err
+	}
+	indent(b.String())
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Naming the Owner of a Symbol ---")
+	for _, tc := range []struct{ sym, want string }{
+		{"fmt.(*pp).printValue", "fmt"},
+		{"internal/strconv.pow10Tab", "internal/strconv"},
+		{"crypto/internal/fips140/aes.(*Block).Encrypt", "crypto/internal/fips140/aes"},
+		{"internal/sync..dict.HashTrieMap[interface {},interface {}]", "internal/sync"},
+		{"slices.SortFunc[go.shape.[]main.Row]", "slices"},
+		{"main..gobytes.1", "main"},
+		{"go:func.*", "(go:func)"},
+		{"type:*", "(type)"},
+	} {
+		got := Package(tc.sym)
+		check(got == tc.want, fmt.Sprintf("%-58s → %s", tc.sym, got), fmt.Sprintf("%s → %s, want %s", tc.sym, got, tc.want))
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: -ldflags=\"-s -w\" ---")
+	stripped, err := buildTarget("gotut", filepath.Join(dir, "stripped"), "-s -w")
+	if err != nil {
+		return err
+	}
+	full, small := sizeOf(gotut), sizeOf(stripped)
+	fmt.Printf("  default   %10s   DWARF %s, symbol table %s\n", kb(full),
+		kb(kindSize(gotut, "DWARF debug info")), kb(kindSize(gotut, "symbol table")))
+	fmt.Printf("  -s -w     %10s   (%s smaller)\n", kb(small), pct(full-small, full))
+	check(kindSize(stripped, "DWARF debug info") == 0 && kindSize(stripped, "func table (pclntab)") > 0,
+		"DWARF and symbols gone; pclntab stays, so panics still print file:line",
+		"unexpected sections after stripping")
+	_, err = Symbols(stripped)
+	check(err != nil, "and nm has nothing to read: analyze before you strip", "nm read a stripped binary")
+	fmt.Println("  Cost: no debugger symbols. Cheaper than any code change; ship it that way.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Build Info Is in Every Binary ---")
+	fmt.Println(" ", BuildLine(gotut))
+	fmt.Println(" ", BuildLine(stripped))
+	fmt.Println("  (go version -m BINARY shows the same; -s keeps it)")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: The Data Behind Topic 180 ---")
+	cmd := exec.Command("go", "run", "180_lazy_registry.go", "build", "-o", filepath.Join(dir, "registry"))
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("180 build: %w", err)
+	}
+	bins := strings.Fields(string(out))
+	if len(bins) != 2 {
+		return fmt.Errorf("180 build: want two binaries, got %q", out)
+	}
+	eager, lazy := bins[0], bins[1]
+	fmt.Printf("  %-22s %12s %12s %12s\n", "", "eager", "lazy", "difference")
+	for _, kind := range []string{"read-only data", "data", "code", "func table (pclntab)"} {
+		e, l := kindSize(eager, kind), kindSize(lazy, kind)
+		fmt.Printf("  %-22s %12s %12s %12s\n", kind, kb(e), kb(l), kb(e-l))
+	}
+	fmt.Printf("  %-22s %12s %12s %12s\n", "file", kb(sizeOf(eager)), kb(sizeOf(lazy)), kb(sizeOf(eager)-sizeOf(lazy)))
+	var mainRow [2]PackageRow
+	for i, bin := range bins {
+		syms, err := Symbols(bin)
+		if err != nil {
+			return err
+		}
+		for _, r := range ByPackage(syms) {
+			if r.Package == "main" {
+				mainRow[i] = r
+			}
+		}
+	}
+	fmt.Printf("  package main by symbol: eager %s, lazy %s (%s of it the embedded zip)\n",
+		kb(mainRow[0].Total()), kb(mainRow[1].Total()), kb(mainRow[1].Bytes))
+	check(mainRow[0].Total() < mainRow[1].Total() && kindSize(eager, "read-only data") > kindSize(lazy, "read-only data"),
+		"nm says eager's main is the SMALLER one: its lesson text is string data, visible only as .rodata",
+		"nm attributed eager's literals after all")
+	check(mainRow[1].Bytes > 0, "lazy's zip has a symbol of its own: main..gobytes.1", "no embedded []byte found in lazy")
+	fmt.Println("  Lazy pays ~100 KB of code for archive/zip and compress/flate to save the rest.")
+	fmt.Println("  Sections found the bloat; symbols alone would have pointed the wrong way.")
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Measure before trimming: sections say what kind, symbols say whose.
+2. go tool nm -size -sort size groups by package with a little parsing.
+3. String literals and embedded strings have no symbol; watch .rodata.
+4. -ldflags="-s -w" drops DWARF and symbols; pclntab stays for tracebacks.
+5. debug/buildinfo tells how any Go binary was built — even a stripped one.
+6. Zero-filled sections (.bss) cost memory, not file size.
+	`)
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch {
+		case len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "bloat":
+			err = toolBloat(os.Args[3:])
+		default:
+			err = fmt.Errorf("unknown command %q (want: tool bloat)", strings.Join(os.Args[1:], " "))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "bloat:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,584 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+TOPIC: PACKAGE INITIALIZATION — ORDER, COST, AND MEASURING STARTUP
+
+CONCEPT:
+Before main runs, every package in the program is initialized, once, in an
+order the spec fixes:
+
+    1. Packages: a package is initialized after every package it imports.
+       Among packages that are ready, the one with the smallest import path
+       goes first (Go 1.21+), so the order is the same on every build.
+    2. Within a package: package-level variables, in DEPENDENCY order — a
+       var that uses another is initialized after it, whatever the source
+       order says; otherwise in the order of the files as given to the
+       compiler (sorted by name) and declaration order within a file.
+    3. Then the package's init() functions, in the same file order. A file
+       may have several; none can be called or referenced.
+    4. main.main, last.
+
+All of it runs on one goroutine before main — for every command, even
+"gotut help". A package-level
+
+    var Email = regexp.MustCompile(`...`)
+
+costs its compile time on every start, whether Email is used or not.
+
+MEASURING STARTUP: init runs before main, so runtime/trace — started in
+main — can't see it. Two tools cover the two halves:
+
+    GODEBUG=inittrace=1 ./prog    one line per package that does work at
+                                  init: clock time, bytes, allocations
+    runtime/trace                 what happens after main starts; a
+                                  trace.WithRegion around the first use
+                                  shows the lazily deferred work
+
+THE FIX for a heavy, rarely-used init is to defer it to first use:
+
+    var Email = sync.OnceValue(func() *regexp.Regexp {
+        return regexp.MustCompile(`...`)
+    })
+    ... rx.Email().MatchString(s)
+
+sync.OnceValue (Go 1.21) runs the function once, on the first call, safely
+from any goroutine, and returns the cached result after that.
+
+THE PRICE: a bad pattern no longer crashes the program at start — it
+panics on first use, maybe in production. A test that calls every getter
+gets the start-up check back (Example 5).
+
+The course has no rx package, so this lesson generates one: a regex
+cookbook (the patterns of Topic 73 and their usual companions), eager and
+lazy, and a small program that uses one of them.
+
+RUN:
+    go run 182_init_order.go
+    go run 182_init_order.go measure -runs 50 -trace .
+    go tool trace lazy.trace           (User-defined regions → "first match")
+*/
+
+// ---------------------------------------------------------
+// Part 1: A Program Whose Init Order You Can Read
+// ---------------------------------------------------------
+
+// orderProgram prints each initialization step as it happens. main
+// imports b and a; b imports a; everyone imports say, which prints.
+var orderProgram = map[string]string{
+	"say/say.go": `package say
+
+import "fmt"
+
+func Say(s string) int { fmt.Println(s); return 0 }
+`,
+	"a/a1.go": `package a
+
+import "prog/say"
+
+var A1 = A2 + say.Say("a.A1   (a1.go, declared first, needs A2)")
+
+func init() { say.Say("a init (a1.go)") }
+`,
+	"a/a2.go": `package a
+
+import "prog/say"
+
+var A2 = say.Say("a.A2   (a2.go)")
+
+func init() { say.Say("a init (a2.go)") }
+`,
+	"b/b.go": `package b
+
+import (
+	"prog/a"
+	"prog/say"
+)
+
+var B = a.A1 + say.Say("b.B")
+
+func init() { say.Say("b init") }
+`,
+	"main.go": `package main
+
+import (
+	"prog/a"
+	"prog/b"
+	"prog/say"
+)
+
+var M = a.A1 + b.B + say.Say("main.M")
+
+func init() { say.Say("main init #1") }
+
+func init() { say.Say("main init #2") }
+
+func main() { say.Say("main()") }
+`,
+}
+
+var wantOrder = []string{
+	"a.A2   (a2.go)",
+	"a.A1   (a1.go, declared first, needs A2)",
+	"a init (a1.go)",
+	"a init (a2.go)",
+	"b.B",
+	"b init",
+	"main.M",
+	"main init #1",
+	"main init #2",
+	"main()",
+}
+
+// writeProgram lays files out under dir, a module named prog: its
+// packages import each other as "prog/a".
+func writeProgram(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module prog\n\ngo 1.25\n"), 0o644); err != nil {
+		return err
+	}
+	for name, src := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildProgram builds the module in dir.
+func buildProgram(dir string) (string, error) {
+	bin := filepath.Join(dir, "prog")
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s: %w\n%s", filepath.Base(dir), err, out)
+	}
+	return bin, nil
+}
+
+// ---------------------------------------------------------
+// Part 2: The rx Cookbook, Eager and Lazy
+// ---------------------------------------------------------
+
+type recipe struct{ name, pattern string }
+
+var cookbook = []recipe{
+	{"Email", `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`},
+	{"URL", `^https?://[\w.-]+(?::\d{1,5})?(?:/[\w./%-]*)?(?:\?[\w=&%.-]*)?(?:#[\w-]*)?$`},
+	{"Domain", `^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}$`},
+	{"IPv4", `^(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)$`},
+	{"IPv6", `^(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}$`},
+	{"MAC", `^(?:[0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`},
+	{"UUID", `^[0-9a-f]{8}-[0-9a-f]{4}-[1-8][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+	{"ISODate", `^\d{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])$`},
+	{"ISOTime", `^(?:[01]\d|2[0-3]):[0-5]\d(?::[0-5]\d(?:\.\d{1,9})?)?$`},
+	{"RFC3339", `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d{1,9})?(?:Z|[+-]\d{2}:\d{2})$`},
+	{"Time12", `^(?:1[0-2]|0?[1-9]):[0-5]\d\s?(?:AM|PM|am|pm)$`},
+	{"Duration", `^(?:\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h))+$`},
+	{"SemVer", `^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`},
+	{"HexColor", `^#(?:[0-9a-fA-F]{3}){1,2}$`},
+	{"RGBColor", `rgba?\(\s*\d{1,3}\s*,\s*\d{1,3}\s*,\s*\d{1,3}\s*(?:,\s*(?:0|1|0?\.\d+)\s*)?\)`},
+	{"USPhone", `^\(?\d{3}\)?[-. ]?\d{3}[-. ]?\d{4}$`},
+	{"E164", `^\+[1-9]\d{1,14}$`},
+	{"ZIP", `^\d{5}(?:-\d{4})?$`},
+	{"UKPostcode", `^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`},
+	{"CreditCard", `^(?:4\d{12}(?:\d{3})?|5[1-5]\d{14}|3[47]\d{13}|6(?:011|5\d{2})\d{12})$`},
+	{"Price", `^\$?\d{1,3}(?:,\d{3})*(?:\.\d{2})?$`},
+	{"Latitude", `^-?(?:90(?:\.0+)?|[1-8]?\d(?:\.\d+)?)$`},
+	{"Roman", `^M{0,3}(?:CM|CD|D?C{0,3})(?:XC|XL|L?X{0,3})(?:IX|IV|V?I{0,3})$`},
+	{"Slug", `^[a-z0-9]+(?:-[a-z0-9]+)*$`},
+	{"Username", `^[\p{L}\p{N}_.-]{3,32}$`},
+	{"PersonName", `^[\p{L}\p{M}' .-]{1,100}$`},
+	{"Hashtag", `#[\p{L}\p{N}_]+`},
+	{"Mention", `@[A-Za-z0-9_]{1,15}`},
+	{"Word", `\p{L}+`},
+	{"GoIdent", `^[\p{L}_][\p{L}\p{N}_]*$`},
+	{"GoImport", `^\s*import\s+(?:\w+\s+)?"([^"]+)"`},
+	{"GoFunc", `(?m)^func\s+(?:\([^)]*\)\s*)?(\w+)`},
+	{"TopicFile", `^(\d+)_(\w+)\.go$`},
+	{"LogLine", `^(\S+) (\S+) \[(\w+)\] (.*)$`},
+	{"ApacheLog", `^(\S+) \S+ \S+ \[([^\]]+)\] "(\w+) ([^"]*) HTTP/[\d.]+" (\d{3}) (\d+|-)`},
+	{"KeyValue", `(\w+)=("[^"]*"|\S+)`},
+	{"JSONNumber", `^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`},
+	{"Base64", `^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$`},
+	{"JWT", `^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`},
+	{"SHA256", `^[a-f0-9]{64}$`},
+	{"MarkdownLink", `\[([^\]]+)\]\(([^)\s]+)\)`},
+	{"HTMLTag", `<([a-zA-Z][a-zA-Z0-9]*)\b[^>]*>`},
+	{"UnixPath", `^(?:/[^/\x00]+)+/?$`},
+	{"WindowsPath", `^[A-Za-z]:\\(?:[^\\/:*?"<>|\r\n]+\\)*[^\\/:*?"<>|\r\n]*$`},
+}
+
+// rxSource renders the cookbook as package rx. All returns every pattern
+// — in the lazy version, by compiling each — so a test can check them.
+func rxSource(recipes []recipe, lazy bool) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by 182_init_order.go; DO NOT EDIT.\n\n")
+	b.WriteString("// Package rx is a cookbook of compiled regular expressions.\npackage rx\n\n")
+	if lazy {
+		b.WriteString("import (\n\"regexp\"\n\"sync\"\n)\n\n")
+	} else {
+		b.WriteString("import \"regexp\"\n\n")
+	}
+	b.WriteString("var (\n")
+	for _, r := range recipes {
+		if lazy {
+			fmt.Fprintf(&b, "%s = sync.OnceValue(func() *regexp.Regexp { return regexp.MustCompile(%q) })\n", r.name, r.pattern)
+		} else {
+			fmt.Fprintf(&b, "%s = regexp.MustCompile(%q)\n", r.name, r.pattern)
+		}
+	}
+	b.WriteString(")\n\nfunc All() []*regexp.Regexp {\nreturn []*regexp.Regexp{\n")
+	for _, r := range recipes {
+		if lazy {
+			fmt.Fprintf(&b, "%s(),\n", r.name)
+		} else {
+			fmt.Fprintf(&b, "%s,\n", r.name)
+		}
+	}
+	b.WriteString("}\n}\n")
+	return format.Source(b.Bytes())
+}
+
+// rxMain is the user: a validator that only ever needs Email. It traces
+// the first match when STARTUP_TRACE names a file, and prints the result
+// and how long the first match took, counted from the start of main.
+const rxMain = `package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/trace"
+	"time"
+
+	"prog/rx"
+)
+
+func main() {
+	start := time.Now()
+	if p := os.Getenv("STARTUP_TRACE"); p != "" {
+		f, err := os.Create(p)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if err := trace.Start(f); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer trace.Stop()
+		start = time.Now()
+	}
+	var ok bool
+	trace.WithRegion(context.Background(), "first match", func() {
+		ok = EMAIL.MatchString(os.Args[1])
+	})
+	fmt.Println(ok, time.Since(start).Nanoseconds())
+}
+`
+
+const rxTest = `package rx
+
+import "testing"
+
+// TestAllCompile brings back the start-up check lazy compilation gave up:
+// every pattern is compiled here, so a typo fails the build, not a user.
+func TestAllCompile(t *testing.T) {
+	if got := len(All()); got == 0 {
+		t.Fatal("empty cookbook")
+	}
+}
+`
+
+func writeRx(dir string, recipes []recipe, lazy bool) (string, error) {
+	src, err := rxSource(recipes, lazy)
+	if err != nil {
+		return "", err
+	}
+	email := "rx.Email"
+	if lazy {
+		email += "()"
+	}
+	err = writeProgram(dir, map[string]string{
+		"rx/rx.go":      string(src),
+		"rx/rx_test.go": rxTest,
+		"main.go":       strings.Replace(rxMain, "EMAIL", email, 1),
+	})
+	if err != nil {
+		return "", err
+	}
+	return buildProgram(dir)
+}
+
+// ---------------------------------------------------------
+// Part 3: Measuring
+// ---------------------------------------------------------
+
+type Startup struct {
+	Design     string
+	Wall       time.Duration // Median, process start to exit
+	InitClock  time.Duration // Package rx's init, from inittrace
+	InitBytes  int
+	InitAllocs int
+	FirstMatch time.Duration // Inside main: the first Email match
+}
+
+var inittraceRx = regexp.MustCompile(`(?m)^init \S*/rx @\S+ ms, (\S+) ms clock, (\d+) bytes, (\d+) allocs`)
+
+func run(bin string, env ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(bin, "learner@example.com")
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func measure(design, bin string, runs int, tracePath string) (Startup, error) {
+	s := Startup{Design: design}
+	var walls, firsts []time.Duration
+	for range runs {
+		start := time.Now()
+		out, _, err := run(bin)
+		if err != nil {
+			return s, err
+		}
+		walls = append(walls, time.Since(start))
+		f := strings.Fields(out)
+		if len(f) != 2 || f[0] != "true" {
+			return s, fmt.Errorf("%s: unexpected output %q", design, out)
+		}
+		ns, _ := strconv.ParseInt(f[1], 10, 64)
+		firsts = append(firsts, time.Duration(ns))
+	}
+	slices.Sort(walls)
+	slices.Sort(firsts)
+	s.Wall, s.FirstMatch = walls[len(walls)/2], firsts[len(firsts)/2]
+	_, trace, err := run(bin, "GODEBUG=inittrace=1")
+	if err != nil {
+		return s, err
+	}
+	if m := inittraceRx.FindStringSubmatch(trace); m != nil { // No line: rx did nothing at init
+		ms, _ := strconv.ParseFloat(m[1], 64)
+		s.InitClock = time.Duration(ms * float64(time.Millisecond))
+		s.InitBytes, _ = strconv.Atoi(m[2])
+		s.InitAllocs, _ = strconv.Atoi(m[3])
+	}
+	if tracePath != "" {
+		if _, _, err := run(bin, "STARTUP_TRACE="+tracePath); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// Compare builds both rx programs in a temp dir and measures them. With
+// traceDir set, each writes DESIGN.trace there.
+func Compare(runs int, traceDir string) ([]Startup, error) {
+	dir, err := os.MkdirTemp("", "init-order-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	var out []Startup
+	for _, design := range []string{"eager", "lazy"} {
+		bin, err := writeRx(filepath.Join(dir, design), cookbook, design == "lazy")
+		if err != nil {
+			return nil, err
+		}
+		tracePath := ""
+		if traceDir != "" {
+			if tracePath, err = filepath.Abs(filepath.Join(traceDir, design+".trace")); err != nil {
+				return nil, err
+			}
+		}
+		s, err := measure(design, bin, runs, tracePath)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func printStartups(ss []Startup) {
+	fmt.Printf("  %-28s %14s %14s\n", "", ss[0].Design, ss[1].Design)
+	row := func(label string, f func(Startup) string) {
+		fmt.Printf("  %-28s %14s %14s\n", label, f(ss[0]), f(ss[1]))
+	}
+	row("rx init (inittrace)", func(s Startup) string { return s.InitClock.Round(time.Microsecond).String() })
+	row("rx init allocations", func(s Startup) string { return fmt.Sprintf("%d KB, %d", s.InitBytes>>10, s.InitAllocs) })
+	row("first Email match in main", func(s Startup) string { return s.FirstMatch.Round(time.Microsecond).String() })
+	row("process wall time (median)", func(s Startup) string { return s.Wall.Round(10 * time.Microsecond).String() })
+}
+
+// ---------------------------------------------------------
+// Part 4: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func demo() error {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: PACKAGE INITIALIZATION AND STARTUP COST")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	dir, err := os.MkdirTemp("", "demo-init-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	fmt.Println("--- Example 1: The Order, Printed ---")
+	fmt.Println("  main imports a and b; b imports a; a1.go declares A1 = A2 + …")
+	orderDir := filepath.Join(dir, "order")
+	if err := writeProgram(orderDir, orderProgram); err != nil {
+		return err
+	}
+	bin, err := buildProgram(orderDir)
+	if err != nil {
+		return err
+	}
+	out, err := exec.Command(bin).Output()
+	if err != nil {
+		return err
+	}
+	got := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i, l := range got {
+		fmt.Printf("  %2d. %s\n", i+1, l)
+	}
+	check(slices.Equal(got, wantOrder),
+		"imports first; vars by dependency, then init()s in file order; main last",
+		fmt.Sprintf("order was %q", got))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: GODEBUG=inittrace=1 ---")
+	eagerBin, err := writeRx(filepath.Join(dir, "eager"), cookbook, false)
+	if err != nil {
+		return err
+	}
+	_, trace, err := run(eagerBin, "GODEBUG=inittrace=1")
+	if err != nil {
+		return err
+	}
+	for l := range strings.Lines(trace) {
+		if strings.HasPrefix(l, "init ") && (strings.Contains(l, "regexp") || strings.Contains(l, "/rx ") || strings.Contains(l, "unicode")) {
+			fmt.Print("  ", strings.Replace(l, filepath.ToSlash(filepath.Join(dir, "eager")), "…", 1))
+		}
+	}
+	fmt.Printf("  (runtime, os, … trimmed) rx compiles %d patterns before main; the program uses one.\n", len(cookbook))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Eager vs sync.OnceValue, Measured ---")
+	ss, err := Compare(30, dir)
+	if err != nil {
+		return err
+	}
+	printStartups(ss)
+	e, l := ss[0], ss[1]
+	check(l.InitClock < e.InitClock && l.InitAllocs < e.InitAllocs,
+		fmt.Sprintf("rx init: %v → %v", e.InitClock.Round(time.Microsecond), l.InitClock.Round(time.Microsecond)),
+		"lazy rx still does work at init")
+	check(l.FirstMatch > e.FirstMatch,
+		"the one pattern used is compiled on first use instead — inside main, where a trace sees it",
+		"the first match cost the same")
+	check(l.Wall < e.Wall, fmt.Sprintf("whole process: %v faster per start",
+		(e.Wall-l.Wall).Round(10*time.Microsecond)), "no wall-time gain at this size (noise wins)")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: runtime/trace Sees Only main ---")
+	for _, design := range []string{"eager", "lazy"} {
+		fi, err := os.Stat(filepath.Join(dir, design+".trace"))
+		check(err == nil && fi.Size() > 0, fmt.Sprintf("%s.trace written (%d bytes)", design, fi.Size()), fmt.Sprint(err))
+	}
+	fmt.Println("  go tool trace lazy.trace → User-defined regions → \"first match\"")
+	fmt.Println("  holds the Email compile; in eager.trace it's just the match. Init")
+	fmt.Println("  itself is in neither: it finished before trace.Start could run.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: What Lazy Gives Up, and Getting It Back ---")
+	broken := append(slices.Clone(cookbook), recipe{"Typo", `(unclosed`})
+	brokenDir := filepath.Join(dir, "broken")
+	brokenBin, err := writeRx(brokenDir, broken, true)
+	if err != nil {
+		return err
+	}
+	out2, _, err := run(brokenBin)
+	check(err == nil && strings.HasPrefix(out2, "true"),
+		"a lazy cookbook with a typo starts and runs — the typo waits for its first user",
+		fmt.Sprintf("unexpected: %v %q", err, out2))
+	test := exec.Command("go", "test", "./rx")
+	test.Dir = brokenDir
+	testOut, err := test.CombinedOutput()
+	_, msg, _ := strings.Cut(string(testOut), "panic: ")
+	msg, _, _ = strings.Cut(msg, "\n")
+	msg, _, _ = strings.Cut(msg, " [recovered")
+	check(err != nil && strings.Contains(msg, "missing closing )"),
+		"TestAllCompile catches it before anyone runs it: "+msg,
+		fmt.Sprintf("the test passed: %s", testOut))
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Imports init first; vars by dependency; init()s in file order; main last.
+2. Everything at init runs on every start, for every command.
+3. GODEBUG=inittrace=1 measures init; runtime/trace only starts in main.
+4. Defer heavy, rarely-used setup with sync.OnceValue; it's goroutine-safe.
+5. Lazy moves failures to first use: test every getter to get them back.
+	`)
+	return nil
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "measure" {
+		fs := flag.NewFlagSet("measure", flag.ExitOnError)
+		runs := fs.Int("runs", 20, "runs of each program for the medians")
+		traceDir := fs.String("trace", "", "write eager.trace and lazy.trace into this directory")
+		fs.Parse(args[1:])
+		ss, err := Compare(max(*runs, 1), *traceDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "measure:", err)
+			os.Exit(1)
+		}
+		printStartups(ss)
+		if *traceDir != "" {
+			fmt.Printf("traces: %s, %s\n", filepath.Join(*traceDir, "eager.trace"), filepath.Join(*traceDir, "lazy.trace"))
+		}
+		return
+	}
+	if err := demo(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
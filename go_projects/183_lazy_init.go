@@ -0,0 +1,407 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/lazy"
+)
+
+/*
+TOPIC: LAZY INITIALIZATION — RACES, SYNC.ONCE, AND pkg/lazy
+
+CONCEPT:
+Topic 182 moved expensive package-level work out of init and into "first
+use". The first thing everyone writes for that is
+
+    var cfg *Config
+
+    func config() *Config {
+        if cfg == nil {     // ① read
+            cfg = load()    // ② write
+        }
+        return cfg
+    }
+
+which is correct with one goroutine and wrong with two: both can pass ①
+before either reaches ②, so load runs twice, and the unsynchronized
+read/write of cfg is a DATA RACE — undefined behaviour, which `go run
+-race` reports. Adding a "loaded bool" doesn't help; it is one more racy
+variable.
+
+THE TOOLS, oldest first:
+
+    sync.Once.Do(f)          runs f once; everyone else waits for it
+    sync.OnceFunc(f)         the same, as a func() (Go 1.21)
+    sync.OnceValue(f)        ... that returns f's result, cached
+    sync.OnceValues(f)       ... result and error, cached
+
+PANICS are where they differ. If f panics inside Once.Do, the Once counts
+as done: the next Do returns at once and the caller reads a nil value, far
+from the real failure. OnceFunc/OnceValue re-panic with the same value on
+every call instead.
+
+pkg/lazy is the course's name for the pattern: lazy.Of(fn) over
+sync.OnceValue, lazy.OfErr(fn) over sync.OnceValues, and Done to ask "has
+it loaded?" without loading. The course uses it for the regexes 170 rarely
+needs, the templates 178 parses, and the config dir 168 and 169 look up.
+pkg/lazy is a real package in the pkg module, imported by its module
+path through the repository's go.work.
+
+RUN:
+    go run 183_lazy_init.go
+    go test ./pkg/lazy
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Check-Then-Set Race
+// ---------------------------------------------------------
+
+type Config struct {
+	Theme string
+	Width int
+}
+
+// loads counts calls to load; load is slow enough that callers overlap,
+// as a real file read or network call would be.
+var loads atomic.Int32
+
+func load() *Config {
+	loads.Add(1)
+	time.Sleep(time.Millisecond)
+	return &Config{Theme: "dark", Width: 80}
+}
+
+// racyLoader is the check-then-set pattern from the header. It is kept in
+// a struct, not a package var, so each example starts fresh; the race is
+// the same.
+type racyLoader struct{ cfg *Config }
+
+func (r *racyLoader) Get() *Config {
+	if r.cfg == nil {
+		r.cfg = load()
+	}
+	return r.cfg
+}
+
+// callers runs get from n goroutines at once and returns how many
+// distinct *Config values they saw.
+func callers(n int, get func() *Config) int {
+	var (
+		mu   sync.Mutex
+		seen = map[*Config]bool{}
+		wg   sync.WaitGroup
+	)
+	start := make(chan struct{})
+	for range n {
+		wg.Go(func() {
+			<-start // Line everyone up, then release them together
+			c := get()
+			mu.Lock()
+			seen[c] = true
+			mu.Unlock()
+		})
+	}
+	close(start)
+	wg.Wait()
+	return len(seen)
+}
+
+// racyProgram is the same pattern as a program of its own, for -race.
+const racyProgram = `package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+var cfg map[string]string
+
+func config() map[string]string {
+	if cfg == nil {
+		cfg = map[string]string{"theme": "dark"}
+	}
+	return cfg
+}
+
+func main() {
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() { _ = config()["theme"] })
+	}
+	wg.Wait()
+	fmt.Println("done")
+}
+`
+
+// raceReport runs racyProgram under the race detector and returns the
+// first lines of its report. The detector needs cgo; without a C
+// compiler it can't run and the error says so.
+func raceReport() ([]string, error) {
+	dir, err := os.MkdirTemp("", "lazy-race-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(racyProgram), 0o644); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("go", "run", "-race", "main.go")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=1")
+	out, err := cmd.CombinedOutput()
+	if !strings.Contains(string(out), "WARNING: DATA RACE") {
+		if err == nil {
+			err = errors.New("no race reported (the goroutines happened not to overlap)")
+		}
+		return nil, fmt.Errorf("%v\n%s", err, out)
+	}
+	var lines []string
+	for l := range strings.Lines(string(out)) {
+		if l = strings.TrimRight(l, "\n"); strings.HasPrefix(l, "WARNING") || strings.Contains(l, "main.config()") ||
+			strings.HasPrefix(l, "Read at") || strings.HasPrefix(l, "Previous write at") {
+			lines = append(lines, l)
+		}
+	}
+	return lines[:min(len(lines), 5)], nil
+}
+
+// ---------------------------------------------------------
+// Part 2: Once, Done Right
+// ---------------------------------------------------------
+
+// onceLoader is the sync.Once version: the Once guards both the call and
+// the publication of cfg, so readers after Do see the write.
+type onceLoader struct {
+	once sync.Once
+	cfg  *Config
+}
+
+func (o *onceLoader) Get() *Config {
+	o.once.Do(func() { o.cfg = load() })
+	return o.cfg
+}
+
+// ---------------------------------------------------------
+// Part 3: When the Load Fails
+// ---------------------------------------------------------
+
+// tryGet calls get and returns what it returned or the panic it raised.
+func tryGet[T any](get func() T) (v T, panicked any) {
+	defer func() { panicked = recover() }()
+	return get(), nil
+}
+
+var errNoFile = errors.New("open settings.json: no such file or directory")
+
+// ---------------------------------------------------------
+// Part 4: Where the Course Uses pkg/lazy
+// ---------------------------------------------------------
+
+// courseDirs are where topics live, relative to go_projects/ (see 168).
+var courseDirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+// Use is one lesson that imports pkg/lazy and the values it makes lazy.
+type Use struct {
+	File  string
+	Names []string
+}
+
+// Adopters parses every lesson and lists those that import pkg/lazy,
+// with the package-level names initialized by lazy.Of or lazy.OfErr.
+func Adopters() []Use {
+	var uses []Use
+	for _, dir := range courseDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "[0-9]*.go"))
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+			if err != nil {
+				continue // A lesson that doesn't parse doesn't build either
+			}
+			imports := slices.ContainsFunc(f.Imports, func(spec *ast.ImportSpec) bool {
+				p, _ := strconv.Unquote(spec.Path.Value)
+				return p == "github.com/akarsh323/Go-tutorials-/go_projects/pkg/lazy"
+			})
+			if !imports || filepath.Base(file) == "183_lazy_init.go" {
+				continue
+			}
+			u := Use{File: filepath.Base(file)}
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, v := range vs.Values {
+						if call, ok := v.(*ast.CallExpr); ok && isLazyCall(call) {
+							u.Names = append(u.Names, vs.Names[i].Name)
+						}
+					}
+				}
+			}
+			uses = append(uses, u)
+		}
+	}
+	return uses
+}
+
+func isLazyCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "lazy" && (sel.Sel.Name == "Of" || sel.Sel.Name == "OfErr")
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func demo() error {
+	const n = 50
+
+	fmt.Println("--- Example 1: Check-Then-Set From 50 Goroutines ---")
+	worst := 0
+	for range 5 {
+		loads.Store(0)
+		distinct := callers(n, (&racyLoader{}).Get)
+		worst = max(worst, int(loads.Load()))
+		fmt.Printf("  load() ran %2d times; callers saw %2d different configs\n", loads.Load(), distinct)
+	}
+	check(worst > 1, fmt.Sprintf("up to %d loads for one config: every caller passed the nil check first", worst),
+		"the goroutines never overlapped this time; the race is still there (Example 2)")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: go run -race Says So ---")
+	if lines, err := raceReport(); err != nil {
+		first, _, _ := strings.Cut(err.Error(), "\n")
+		fmt.Println("  (skipped:", first+")")
+	} else {
+		for _, l := range lines {
+			fmt.Println("  │ " + l)
+		}
+		check(true, "a read and a write of cfg in config(), from different goroutines, unordered",
+			"no report")
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: sync.Once and lazy.Of ---")
+	loads.Store(0)
+	distinct := callers(n, (&onceLoader{}).Get)
+	check(loads.Load() == 1 && distinct == 1, "sync.Once: one load, one config, 50 callers",
+		fmt.Sprintf("%d loads, %d configs", loads.Load(), distinct))
+
+	loads.Store(0)
+	cfg := lazy.Of(load)
+	fmt.Printf("  lazy.Of(load): Done before any Get = %v\n", cfg.Done())
+	distinct = callers(n, cfg.Get)
+	check(loads.Load() == 1 && distinct == 1 && cfg.Done(), "lazy.Of: one load, one config, Done afterwards",
+		fmt.Sprintf("%d loads, %d configs, Done=%v", loads.Load(), distinct, cfg.Done()))
+	start := time.Now()
+	for range 1_000_000 {
+		cfg.Get()
+	}
+	fmt.Printf("  after the load, Get is an atomic load and a call: %v per Get\n",
+		(time.Since(start) / 1_000_000).Round(time.Nanosecond))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: When the Load Panics or Fails ---")
+	var once sync.Once
+	var pattern *strings.Replacer
+	for i := 1; i <= 2; i++ {
+		_, p := tryGet(func() bool {
+			once.Do(func() { panic("bad pattern") })
+			return pattern != nil
+		})
+		fmt.Printf("  sync.Once, call %d: panic=%v\n", i, p)
+	}
+	check(pattern == nil, "sync.Once: the second call returned quietly — with a nil value, far from the cause",
+		"the value was set")
+
+	calls := 0
+	rx := lazy.Of(func() *strings.Replacer {
+		calls++
+		panic("bad pattern")
+	})
+	var panics []any
+	for range 2 {
+		if _, p := tryGet(rx.Get); p != nil {
+			panics = append(panics, p)
+		}
+	}
+	check(len(panics) == 2 && calls == 1 && !rx.Done(),
+		fmt.Sprintf("lazy.Of: both calls panic %q, fn ran once, Done=false", panics[0]),
+		fmt.Sprintf("panics=%v calls=%d Done=%v", panics, calls, rx.Done()))
+
+	calls = 0
+	settings := lazy.OfErr(func() (*Config, error) {
+		calls++
+		return nil, errNoFile
+	})
+	_, err1 := settings.Get()
+	_, err2 := settings.Get()
+	check(errors.Is(err1, errNoFile) && err2 == err1 && calls == 1,
+		"lazy.OfErr: the error is cached too — a file created later is not seen",
+		fmt.Sprintf("calls=%d err1=%v err2=%v", calls, err1, err2))
+	fmt.Println("  Anything that must notice a changed file wants a reload (143, 144), not lazy.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Where the Course Uses It ---")
+	uses := Adopters()
+	for _, u := range uses {
+		fmt.Printf("  %-22s %s\n", u.File, strings.Join(u.Names, ", "))
+	}
+	check(len(uses) >= 4, fmt.Sprintf("%d lessons import pkg/lazy", len(uses)), "fewer adopters than expected")
+	fmt.Println("  170's snippets copy pkg/lazy into their workspace, so those blocks")
+	fmt.Println("  still build on their own.")
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: LAZY INITIALIZATION — RACES, SYNC.ONCE, AND pkg/lazy")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. "if x == nil { x = load() }" is a data race once two goroutines call it.
+2. go run -race / go test -race finds it; a passing run without -race proves nothing.
+3. sync.Once, OnceValue and OnceValues run the load once and publish it safely.
+4. A panic in Once.Do leaves a zero value behind; OnceValue re-panics every time.
+5. Errors are cached with the value: lazy is for things that don't change.
+6. pkg/lazy names the pattern; Done asks "loaded yet?" without loading.
+	`)
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*
+TOPIC: IMMUTABLE CONFIG SNAPSHOTS — LOCK-FREE READS WITH atomic.Pointer
+
+CONCEPT:
+A server reads its config on every request and changes it a few times a
+day. Guarding it with a sync.RWMutex makes every one of those reads pay
+for a lock that is almost never contended by a writer:
+
+    func (s *Store) Limit() int {
+        s.mu.RLock()             // an atomic add on a shared counter
+        defer s.mu.RUnlock()     // ...and another
+        return s.cfg.Limit
+    }
+
+The counter lives in one cache line that every core writes, and a waiting
+writer makes NEW readers queue behind it.
+
+THE SNAPSHOT PATTERN (143 and 144 used it for flags and templates; here it
+is on its own):
+
+  1. A *Config, once published, is NEVER modified. Every field is a value
+     or a copy the loader owns; accessors hand out copies of maps/slices.
+  2. Readers: cfg := store.Load() — one atomic load, no lock — then read
+     as many fields as they like. They all come from the SAME version.
+  3. Reload: parse and validate a NEW Config off to the side, then
+     publish it with one atomic pointer write. A reader that already
+     holds the old one finishes with it; the GC frees it when nobody does.
+  4. Writers that derive the next config from the current one (set one
+     field from an admin endpoint) use CompareAndSwap in a loop, so two at
+     once can't lose an update.
+
+The demo compares it with the RWMutex version under heavy read load
+while a writer keeps reloading.
+
+RUN:
+    go run 184_config_snapshot.go
+*/
+
+// ---------------------------------------------------------
+// Part 1: An Immutable Config
+// ---------------------------------------------------------
+
+// Config is never modified after Parse returns it. Fields that are
+// reference types are unexported, and their accessors return copies.
+type Config struct {
+	Version   int
+	Listen    string
+	RateLimit int           // Requests per second per client
+	Timeout   time.Duration // Per request
+	admins    []string
+	routes    map[string]string // Path prefix → backend
+}
+
+// Admins returns a copy: a caller that appends to it or sorts it must not
+// change what every other reader sees.
+func (c *Config) Admins() []string { return slices.Clone(c.admins) }
+
+// Backend returns the backend for path, by longest matching prefix.
+func (c *Config) Backend(path string) (string, bool) {
+	best := ""
+	for prefix := range c.routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	backend, ok := c.routes[best]
+	return backend, ok && best != ""
+}
+
+// with returns a copy of c changed by edit. The copy gets its own
+// slice and map, so edit may change them freely.
+func (c *Config) with(edit func(*Config)) *Config {
+	next := *c
+	next.admins = slices.Clone(c.admins)
+	next.routes = maps.Clone(c.routes)
+	edit(&next)
+	next.Version = c.Version + 1
+	return &next
+}
+
+type fileConfig struct {
+	Listen    string            `json:"listen"`
+	RateLimit int               `json:"rate_limit"`
+	Timeout   string            `json:"timeout"`
+	Admins    []string          `json:"admins"`
+	Routes    map[string]string `json:"routes"`
+}
+
+// Parse validates data completely before returning anything: a bad file
+// is rejected whole and the running config stays.
+func Parse(data []byte) (*Config, error) {
+	var fc fileConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, err
+	}
+	timeout, err := time.ParseDuration(fc.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("timeout: %w", err)
+	}
+	if fc.RateLimit <= 0 {
+		return nil, fmt.Errorf("rate_limit must be positive, got %d", fc.RateLimit)
+	}
+	for prefix := range fc.Routes {
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("route %q must start with /", prefix)
+		}
+	}
+	return &Config{Listen: fc.Listen, RateLimit: fc.RateLimit, Timeout: timeout,
+		admins: fc.Admins, routes: fc.Routes}, nil // Unmarshal made them; nobody else has them
+}
+
+// ---------------------------------------------------------
+// Part 2: The Snapshot Store
+// ---------------------------------------------------------
+
+type Store struct {
+	path    string
+	current atomic.Pointer[Config]
+	reload  sync.Mutex // Serializes reloads only; readers never touch it
+}
+
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load returns the current snapshot. Hold on to it for the whole request
+// so every field comes from one version.
+func (s *Store) Load() *Config { return s.current.Load() }
+
+// Reload reads the file and publishes it. Two reloads at once would both
+// read the file and race to publish; the mutex makes the later read win.
+// The file replaces whatever Update did before it: it is the source of
+// truth. The CompareAndSwap loop only keeps Version increasing.
+func (s *Store) Reload() error {
+	s.reload.Lock()
+	defer s.reload.Unlock()
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	next, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	for {
+		old := s.current.Load()
+		next.Version = 1 // Not published yet, so still ours to change
+		if old != nil {
+			next.Version = old.Version + 1
+		}
+		if s.current.CompareAndSwap(old, next) {
+			return nil
+		}
+	}
+}
+
+// Update derives the next snapshot from the current one, for changes
+// that don't come from the file (an admin endpoint raising a limit). If
+// another writer published first, CompareAndSwap fails and edit runs
+// again on the newer snapshot: no update is lost.
+func (s *Store) Update(edit func(*Config)) *Config {
+	for {
+		old := s.current.Load()
+		next := old.with(edit)
+		if s.current.CompareAndSwap(old, next) {
+			return next
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 3: The RWMutex Version, for Comparison
+// ---------------------------------------------------------
+
+type LockedStore struct {
+	mu  sync.RWMutex
+	cfg Config
+}
+
+func (s *LockedStore) RateLimit() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg.RateLimit
+}
+
+func (s *LockedStore) Timeout() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg.Timeout
+}
+
+func (s *LockedStore) Set(cfg Config) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+}
+
+// ---------------------------------------------------------
+// Part 4: Benchmark Under Heavy Read Load
+// ---------------------------------------------------------
+
+// readerGoroutines per GOMAXPROCS in the benchmarks: request handlers
+// far outnumber cores.
+const readerGoroutines = 16
+
+// withWriter runs write every interval until the returned stop is called.
+func withWriter(interval time.Duration, write func(i int)) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				write(i)
+			}
+		}
+	})
+	return func() { close(done); wg.Wait() }
+}
+
+// A read is what a request does with the config: two fields, which must
+// be from the same version.
+func benchSnapshot(s *Store, writeEvery time.Duration) testing.BenchmarkResult {
+	return testing.Benchmark(func(b *testing.B) {
+		b.SetParallelism(readerGoroutines)
+		stop := withWriter(writeEvery, func(i int) { s.Update(func(c *Config) { c.RateLimit = 100 + i%2 }) })
+		defer stop()
+		b.RunParallel(func(pb *testing.PB) {
+			sum := 0
+			for pb.Next() {
+				cfg := s.Load()
+				sum += cfg.RateLimit + int(cfg.Timeout)
+			}
+			_ = sum
+		})
+	})
+}
+
+func benchLocked(s *LockedStore, writeEvery time.Duration) testing.BenchmarkResult {
+	return testing.Benchmark(func(b *testing.B) {
+		b.SetParallelism(readerGoroutines)
+		stop := withWriter(writeEvery, func(i int) {
+			s.Set(Config{RateLimit: 100 + i%2, Timeout: time.Second})
+		})
+		defer stop()
+		b.RunParallel(func(pb *testing.PB) {
+			sum := 0
+			for pb.Next() {
+				sum += s.RateLimit() + int(s.Timeout()) // Two locks, and possibly two versions
+			}
+			_ = sum
+		})
+	})
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+const firstConfig = `{
+  "listen": ":8080",
+  "rate_limit": 100,
+  "timeout": "2s",
+  "admins": ["ana", "raj"],
+  "routes": {"/api/": "api:9000", "/api/v2/": "api2:9000", "/static/": "cdn:80"}
+}`
+
+func writeConfig(path, content string) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path) // Readers of the file never see half of it
+}
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "config-snapshot-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := writeConfig(path, firstConfig); err != nil {
+		return err
+	}
+
+	fmt.Println("--- Example 1: Loading and Reading a Snapshot ---")
+	store, err := NewStore(path)
+	if err != nil {
+		return err
+	}
+	cfg := store.Load()
+	backend, _ := cfg.Backend("/api/v2/users")
+	fmt.Printf("  v%d listen=%s rate_limit=%d timeout=%v admins=%v\n",
+		cfg.Version, cfg.Listen, cfg.RateLimit, cfg.Timeout, cfg.Admins())
+	fmt.Printf("  /api/v2/users → %s (longest prefix)\n", backend)
+	admins := cfg.Admins()
+	admins[0] = "mallory"
+	check(cfg.Admins()[0] == "ana", "changing the slice Admins returned left the snapshot alone",
+		"a caller rewrote the shared config")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Reload Swaps; Old Readers Keep Their Version ---")
+	held := store.Load() // A request that started before the reload
+	next := strings.Replace(firstConfig, `"rate_limit": 100`, `"rate_limit": 250`, 1)
+	if err := writeConfig(path, next); err != nil {
+		return err
+	}
+	if err := store.Reload(); err != nil {
+		return err
+	}
+	check(store.Load().RateLimit == 250 && held.RateLimit == 100,
+		fmt.Sprintf("new requests see v%d (limit 250); the one in flight still has v%d (limit 100)",
+			store.Load().Version, held.Version),
+		"the held snapshot changed under its reader")
+	for _, bad := range []string{`{"rate_limit": 0, "timeout": "1s"}`, `{"rate_limit": 5, "timeout": "soon"}`, `{"rate_limit":`} {
+		if err := writeConfig(path, bad); err != nil {
+			return err
+		}
+		err := store.Reload()
+		check(err != nil && store.Load().RateLimit == 250, "rejected, v"+fmt.Sprint(store.Load().Version)+" stays: "+fmt.Sprint(err),
+			"a bad file was published")
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Concurrent Updates Don't Lose Writes ---")
+	before := store.Load().RateLimit
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { store.Update(func(c *Config) { c.RateLimit++ }) })
+	}
+	wg.Wait()
+	check(store.Load().RateLimit == before+100, fmt.Sprintf("100 concurrent +1s: %d → %d (v%d)",
+		before, store.Load().RateLimit, store.Load().Version), fmt.Sprintf("got %d", store.Load().RateLimit))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: One Snapshot Is One Version ---")
+	// Writers keep RateLimit*1ms == Timeout. Readers of a snapshot always
+	// see a matching pair; two separate locked reads can straddle a Set.
+	locked := &LockedStore{cfg: Config{RateLimit: 1, Timeout: time.Millisecond}}
+	store.Update(func(c *Config) { c.RateLimit, c.Timeout = 1, time.Millisecond })
+	stop := withWriter(10*time.Microsecond, func(i int) {
+		n := 1 + i%2
+		locked.Set(Config{RateLimit: n, Timeout: time.Duration(n) * time.Millisecond})
+		store.Update(func(c *Config) { c.RateLimit, c.Timeout = n, time.Duration(n)*time.Millisecond })
+	})
+	var tornLocked, tornSnapshot atomic.Int64
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for range 4 {
+		wg.Go(func() {
+			for time.Now().Before(deadline) {
+				limit, c := locked.RateLimit(), store.Load()
+				runtime.Gosched() // The handler does other work between its two reads
+				if time.Duration(limit)*time.Millisecond != locked.Timeout() {
+					tornLocked.Add(1)
+				}
+				if time.Duration(c.RateLimit)*time.Millisecond != c.Timeout {
+					tornSnapshot.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	stop()
+	fmt.Printf("  mismatched pairs in 200ms: RWMutex (two reads) %d, snapshot %d\n", tornLocked.Load(), tornSnapshot.Load())
+	check(tornSnapshot.Load() == 0, "the snapshot never mixes versions", "a snapshot was torn")
+	fmt.Println("  The locked store can be fixed with one method returning both — which is")
+	fmt.Println("  a snapshot again, copied under the lock.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Benchmark, Heavy Reads With a Writer Every 100µs ---")
+	fmt.Printf("  GOMAXPROCS=%d, %d reader goroutines per P\n", runtime.GOMAXPROCS(0), readerGoroutines)
+	snap := benchSnapshot(store, 100*time.Microsecond)
+	lock := benchLocked(&LockedStore{cfg: Config{RateLimit: 100, Timeout: time.Second}}, 100*time.Microsecond)
+	fmt.Printf("  %-26s %8.1f ns/read  %d allocs\n", "atomic.Pointer snapshot", float64(snap.NsPerOp()), snap.AllocsPerOp())
+	fmt.Printf("  %-26s %8.1f ns/read  %d allocs\n", "RWMutex, two locked reads", float64(lock.NsPerOp()), lock.AllocsPerOp())
+	if snap.NsPerOp() > 0 {
+		check(snap.NsPerOp() < lock.NsPerOp(), fmt.Sprintf("snapshot reads are %.1fx faster", float64(lock.NsPerOp())/float64(snap.NsPerOp())),
+			"the RWMutex version won on this machine")
+	} else {
+		check(lock.NsPerOp() > 0, "snapshot reads took under 1ns each", "both too fast to tell apart")
+	}
+	if runtime.GOMAXPROCS(0) == 1 {
+		fmt.Println("  One P: this is the cost of the lock instructions alone. With more")
+		fmt.Println("  cores the RWMutex reader count bounces between caches and the gap grows.")
+	}
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: IMMUTABLE CONFIG SNAPSHOTS — LOCK-FREE READS WITH atomic.Pointer")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Publish config as an immutable *Config behind an atomic.Pointer.
+2. Readers Load once per request: no lock, and every field from one version.
+3. Copy maps and slices on the way in and out; "immutable" is a promise you enforce.
+4. Reload = parse + validate off to the side, then publish. Bad files never do.
+5. Read-modify-write updates use CompareAndSwap in a loop so none are lost.
+6. RWMutex reads still write a shared counter; under heavy reads that shows.
+	`)
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+TOPIC: ZERO-ALLOCATION LOGGING — A LEVELLED LOGGER'S HOT PATH
+
+CONCEPT:
+Topic 93 builds levels out of the standard library: one *log.Logger per
+level, each made with log.New(w, "INFO: ", flags), and a level check in
+front. It is fine for a CLI. In a server that logs on every request, each
+line costs allocations:
+
+    logger.Printf("GET %s %d %v", path, status, took)
+                  └─ args ...any: path and took are boxed into interfaces
+                     (heap), fmt walks them with reflection, and a debug
+                     line that is filtered out has already paid for all
+                     of it if the check comes after the formatting.
+
+The course has no levelled-logger package, so this lesson writes one —
+levellog — and then takes its hot path apart:
+
+  1. CHECK THE LEVEL FIRST, with one atomic load. A filtered line must do
+     no other work.
+  2. PREFORMATTED PREFIXES. "INFO  " is a []byte made once, not
+     fmt.Sprintf("%-5s ", level) per line, and the timestamp is
+     formatted once per second and shared by every line in it.
+  3. A POOLED BUFFER. Each line is appended into a []byte from a
+     sync.Pool and written with ONE Write (one syscall, never
+     interleaved with another goroutine's line).
+  4. NO fmt FOR THE COMMON CASES. Typed fields — Str, Int, Dur, Err —
+     append with strconv and time.AppendFormat; nothing is boxed, and a
+     variadic []Field that doesn't escape lives on the stack.
+  5. Keep Infof for the rare line: fmt.Appendf into the same pooled
+     buffer still saves the string fmt.Sprintf would make.
+
+testing.Benchmark and testing.AllocsPerRun measure both versions from
+inside the program (see 151).
+
+RUN:
+    go run 185_fast_logging.go
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Naive Version, as in Topic 93
+// ---------------------------------------------------------
+
+type Level int32
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+// NaiveLogger is 93's log.New-per-level approach with a minimum level.
+type NaiveLogger struct {
+	min     Level
+	loggers [4]*log.Logger
+}
+
+func NewNaive(w io.Writer, min Level) *NaiveLogger {
+	n := &NaiveLogger{min: min}
+	for lvl, prefix := range []string{"DEBUG ", "INFO  ", "WARN  ", "ERROR "} {
+		n.loggers[lvl] = log.New(w, prefix, log.LstdFlags|log.Lmsgprefix)
+	}
+	return n
+}
+
+// Logf formats first and filters second: the shape of code that grew a
+// level check after the fact.
+func (n *NaiveLogger) Logf(lvl Level, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if lvl < n.min {
+		return
+	}
+	n.loggers[lvl].Print(msg)
+}
+
+// ---------------------------------------------------------
+// Part 2: levellog — Level Check and Prefixes
+// ---------------------------------------------------------
+
+// prefixes are formatted once. Each line is
+// "2006/01/02 15:04:05 INFO  message key=value ...\n", like the naive
+// version with log.Lmsgprefix.
+var prefixes = [...][]byte{
+	Debug: []byte("DEBUG "),
+	Info:  []byte("INFO  "),
+	Warn:  []byte("WARN  "),
+	Error: []byte("ERROR "),
+}
+
+type Logger struct {
+	min   atomic.Int32 // Changed at runtime (a SIGHUP, an admin endpoint) without a lock
+	mu    sync.Mutex   // One Write at a time, so lines don't interleave
+	w     io.Writer
+	now   func() time.Time
+	stamp atomic.Pointer[stamp]
+}
+
+// stamp is the formatted time for one second. Lines in the same second
+// share it; time.AppendFormat runs once per second, not once per line.
+type stamp struct {
+	sec  int64
+	text []byte
+}
+
+func New(w io.Writer, min Level) *Logger {
+	l := &Logger{w: w, now: time.Now}
+	l.min.Store(int32(min))
+	return l
+}
+
+func (l *Logger) SetLevel(lvl Level)     { l.min.Store(int32(lvl)) }
+func (l *Logger) Enabled(lvl Level) bool { return int32(lvl) >= l.min.Load() }
+
+// ---------------------------------------------------------
+// Part 3: Typed Fields Instead of ...any
+// ---------------------------------------------------------
+
+type fieldKind uint8
+
+const (
+	kindString fieldKind = iota
+	kindInt
+	kindDuration
+	kindError
+)
+
+// Field is a key and a value that is never boxed: the kind says which
+// of the value fields is set.
+type Field struct {
+	Key  string
+	kind fieldKind
+	str  string
+	num  int64
+	err  error
+}
+
+func Str(key, v string) Field     { return Field{Key: key, kind: kindString, str: v} }
+func Int(key string, v int) Field { return Field{Key: key, kind: kindInt, num: int64(v)} }
+func Dur(key string, v time.Duration) Field {
+	return Field{Key: key, kind: kindDuration, num: int64(v)}
+}
+func Err(err error) Field { return Field{Key: "err", kind: kindError, err: err} }
+
+// appendValue quotes a string only when it needs it, so most values are
+// copied as they are.
+func appendValue(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '"' || c == '=' || c >= utf8.RuneSelf {
+			return strconv.AppendQuote(b, s)
+		}
+	}
+	if s == "" {
+		return append(b, `""`...)
+	}
+	return append(b, s...)
+}
+
+func (f Field) appendTo(b []byte) []byte {
+	b = append(b, ' ')
+	b = append(b, f.Key...)
+	b = append(b, '=')
+	switch f.kind {
+	case kindString:
+		b = appendValue(b, f.str)
+	case kindInt:
+		b = strconv.AppendInt(b, f.num, 10)
+	case kindDuration:
+		b = appendDuration(b, time.Duration(f.num))
+	case kindError:
+		if f.err == nil {
+			return append(b, "<nil>"...)
+		}
+		b = appendValue(b, f.err.Error())
+	}
+	return b
+}
+
+// appendDuration writes d like Duration.String for the values a request
+// log sees, without the string: whole µs below a millisecond, ms with
+// one decimal up to a second, else seconds with three.
+func appendDuration(b []byte, d time.Duration) []byte {
+	switch {
+	case d < time.Millisecond:
+		return append(strconv.AppendInt(b, int64(d/time.Microsecond), 10), "µs"...)
+	case d < time.Second:
+		return append(strconv.AppendFloat(b, float64(d)/float64(time.Millisecond), 'f', 1, 64), "ms"...)
+	default:
+		return append(strconv.AppendFloat(b, d.Seconds(), 'f', 3, 64), 's')
+	}
+}
+
+// ---------------------------------------------------------
+// Part 4: The Pooled Buffer and the Write
+// ---------------------------------------------------------
+
+// bufPool holds *[]byte, not []byte: putting a slice in an interface
+// would allocate its header on every Put.
+var bufPool = sync.Pool{New: func() any {
+	b := make([]byte, 0, 256)
+	return &b
+}}
+
+// maxPooled keeps one huge line (a dumped request body) from pinning a
+// huge buffer in the pool forever.
+const maxPooled = 16 << 10
+
+func (l *Logger) header(b []byte, lvl Level) []byte {
+	t := l.now()
+	s := l.stamp.Load()
+	if s == nil || s.sec != t.Unix() {
+		// Two goroutines may both format the new second; either result is
+		// right, and that happens once a second.
+		s = &stamp{sec: t.Unix(), text: t.AppendFormat(nil, "2006/01/02 15:04:05 ")}
+		l.stamp.Store(s)
+	}
+	b = append(b, s.text...)
+	return append(b, prefixes[lvl]...)
+}
+
+func (l *Logger) write(bp *[]byte, b []byte) {
+	b = append(b, '\n')
+	l.mu.Lock()
+	l.w.Write(b)
+	l.mu.Unlock()
+	if cap(b) <= maxPooled {
+		*bp = b[:0]
+		bufPool.Put(bp)
+	}
+}
+
+// Log writes msg and fields at lvl. Nothing is formatted, pooled or
+// locked for a level that is filtered out.
+func (l *Logger) Log(lvl Level, msg string, fields ...Field) {
+	if !l.Enabled(lvl) {
+		return
+	}
+	bp := bufPool.Get().(*[]byte)
+	b := append(l.header(*bp, lvl), msg...)
+	for _, f := range fields {
+		b = f.appendTo(b)
+	}
+	l.write(bp, b)
+}
+
+func (l *Logger) Debug(msg string, fields ...Field) { l.Log(Debug, msg, fields...) }
+func (l *Logger) Info(msg string, fields ...Field)  { l.Log(Info, msg, fields...) }
+func (l *Logger) Warn(msg string, fields ...Field)  { l.Log(Warn, msg, fields...) }
+func (l *Logger) Error(msg string, fields ...Field) { l.Log(Error, msg, fields...) }
+
+// Logf is the escape hatch for lines that need fmt. The arguments are
+// still boxed, but the text goes straight into the pooled buffer.
+func (l *Logger) Logf(lvl Level, format string, args ...any) {
+	if !l.Enabled(lvl) {
+		return
+	}
+	bp := bufPool.Get().(*[]byte)
+	l.write(bp, fmt.Appendf(l.header(*bp, lvl), format, args...))
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// A request line, with values that escape when boxed: a string, and a
+// status and duration too large for Go's small-integer cache.
+var (
+	path   = "/api/v2/lessons/185"
+	status = 404
+	took   = 1530 * time.Microsecond
+)
+
+func benchmark(fn func()) testing.BenchmarkResult {
+	return testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			fn()
+		}
+	})
+}
+
+func demo() error {
+	fmt.Println("--- Example 1: Same Lines, Two Loggers ---")
+	var naiveOut, fastOut bytes.Buffer
+	naive := NewNaive(&naiveOut, Info)
+	fast := New(&fastOut, Info)
+	naive.Logf(Info, "request path=%s status=%d took=%v", path, status, took)
+	naive.Logf(Debug, "cache miss key=%s", path)
+	fast.Info("request", Str("path", path), Int("status", status), Dur("took", took))
+	fast.Debug("cache miss", Str("key", path))
+	fast.Warn("slow handler", Str("route", "lesson view"), Err(os.ErrDeadlineExceeded))
+	fmt.Print("  naive │ ", naiveOut.String())
+	for line := range bytes.Lines(fastOut.Bytes()) {
+		fmt.Print("  fast  │ ", string(line))
+	}
+	check(bytes.Count(fastOut.Bytes(), []byte("\n")) == 2, "the debug line was filtered; values with spaces are quoted",
+		"unexpected output")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Allocations per Line ---")
+	naive = NewNaive(io.Discard, Info)
+	fast = New(io.Discard, Info)
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"naive  Info  Printf", func() { naive.Logf(Info, "request path=%s status=%d took=%v", path, status, took) }},
+		{"naive  Debug (filtered)", func() { naive.Logf(Debug, "cache miss key=%s", path) }},
+		{"fast   Info  fields", func() { fast.Info("request", Str("path", path), Int("status", status), Dur("took", took)) }},
+		{"fast   Debug (filtered)", func() { fast.Debug("cache miss", Str("key", path)) }},
+		{"fast   Info  Logf", func() { fast.Logf(Info, "request path=%s status=%d took=%v", path, status, took) }},
+	}
+	results := make([]testing.BenchmarkResult, len(cases))
+	for i, c := range cases {
+		results[i] = benchmark(c.fn)
+		fmt.Printf("  %-24s %7d ns/line %4d allocs %5d B\n", c.name,
+			results[i].NsPerOp(), results[i].AllocsPerOp(), results[i].AllocedBytesPerOp())
+	}
+	check(testing.AllocsPerRun(100, cases[2].fn) == 0, "typed fields: zero allocations per line",
+		"the fields path allocates")
+	check(testing.AllocsPerRun(100, cases[3].fn) == 0 && results[1].AllocsPerOp() > 0,
+		fmt.Sprintf("a filtered line: 0 allocations, %dns — the naive one formats it anyway (%d allocs)",
+			results[3].NsPerOp(), results[1].AllocsPerOp()), "filtering costs allocations")
+	if results[2].NsPerOp() > 0 {
+		fmt.Printf("  fields vs naive Printf: %.1fx faster\n", float64(results[0].NsPerOp())/float64(results[2].NsPerOp()))
+	}
+	fmt.Println("  Logf saves allocations, not much time: fmt's reflection is most of its cost.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Concurrent Lines Stay Whole ---")
+	var out bytes.Buffer
+	shared := New(&out, Debug)
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				shared.Info("tick", Int("g", g), Int("i", i))
+			}
+		})
+	}
+	wg.Wait()
+	whole := 0
+	for line := range bytes.Lines(out.Bytes()) {
+		if bytes.Contains(line, []byte("INFO  tick g=")) && bytes.HasSuffix(line, []byte("\n")) {
+			whole++
+		}
+	}
+	check(whole == 1600, "1600 lines from 8 goroutines, none torn (one Write per line, under the mutex)",
+		fmt.Sprintf("%d whole lines", whole))
+	shared.SetLevel(Error)
+	before := out.Len()
+	shared.Info("dropped")
+	check(out.Len() == before, "SetLevel(Error) at runtime: Info is dropped with one atomic load", "Info still written")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: What Still Costs ---")
+	big := bytes.Repeat([]byte("x"), 64<<10)
+	fast.Info("dump", Str("body", string(big)))
+	b := bufPool.Get().(*[]byte)
+	check(cap(*b) <= maxPooled, fmt.Sprintf("a 64 KiB line's buffer was not kept: the pool hands back cap %d", cap(*b)),
+		"the pool kept a 64 KiB buffer")
+	bufPool.Put(b)
+	fmt.Println("  Logf still boxes its arguments; time.Now and the Write are the rest of the")
+	fmt.Println("  cost. An io.Writer that is a file makes one syscall per line — put a")
+	fmt.Println("  bufio.Writer in front (and flush on exit) when lines are hot.")
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: ZERO-ALLOCATION LOGGING — A LEVELLED LOGGER'S HOT PATH")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Check the level before doing anything else; an atomic load is enough.
+2. Format prefixes once; append into a pooled []byte; one Write per line.
+3. ...any boxes values onto the heap; typed fields with strconv don't.
+4. Don't pool huge buffers: cap what goes back into the sync.Pool.
+5. Prove it: testing.AllocsPerRun == 0 is a check you can keep in a test.
+	`)
+}
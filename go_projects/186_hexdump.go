@@ -0,0 +1,480 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+TOPIC: LOOKING AT BYTES — A HEXDUMP TOOL AND READING BINARY FORMATS
+
+CONCEPT:
+Text formats can be read with cat. Binary ones (an image, a WAL segment
+from 152, a gob stream, a protobuf message from 254) can't. The first
+tool for them is a hex dump, three columns per row:
+
+    00000000  89 50 4e 47 0d 0a 1a 0a  00 00 00 0d 49 48 44 52  |.PNG........IHDR|
+    └ offset  └ the bytes in hex, two groups                    └ printable ASCII
+
+It needs nothing but bufio and three verbs from Topic 71:
+
+    %08x    the offset, zero-padded to 8 hex digits
+    % x     a []byte as spaced hex: "89 50 4e 47" (the space flag)
+    %-*s    left-justify in a width given as an argument, so a short
+            last row still lines up the ASCII column
+
+    gotut tool hexdump [-w 16] [-s OFFSET] [-n LENGTH] [-v] FILE|-
+
+-s and -n take decimal or 0x hex; a negative -s counts from the end of
+the file. Runs of identical rows print as one "*", like hexdump -C,
+unless -v.
+
+READING A BINARY FORMAT is then four habits:
+    1. Check the MAGIC NUMBER first: PNG starts 89 50 4E 47 0D 0A 1A 0A.
+    2. Know the BYTE ORDER. PNG and network protocols are big-endian;
+       x86 and ARM are little-endian, and so are many newer formats.
+       encoding/binary names both.
+    3. Never trust a LENGTH field: check it against what is left before
+       allocating.
+    4. Verify the CHECKSUM (CRC-32, Topic 153) before using the data.
+
+encoding/binary reads and writes fixed layouts. encoding/gob is Go's own
+self-describing format: the stream carries the type, so both ends need
+not agree on a layout beforehand. The last example dumps one record
+written both ways, and as JSON.
+
+Like 181, this file is the tool too: gotut runs lessons, it doesn't
+carry their tools.
+
+RUN:
+    go run 186_hexdump.go                                  (demo)
+    go run 186_hexdump.go tool hexdump 186_hexdump.go
+    go run 186_hexdump.go tool hexdump -w 8 -s 0x40 -n 64 /bin/ls
+    head -c 64 /dev/urandom | go run 186_hexdump.go tool hexdump -
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Dumper
+// ---------------------------------------------------------
+
+type Options struct {
+	Width   int   // Bytes per row; 0 means 16
+	Offset  int64 // Offset of the first byte, for the left column
+	Verbose bool  // Print every row; otherwise repeats collapse to "*"
+}
+
+// Dump writes r as offset/hex/ASCII rows. It reads through a
+// bufio.Reader so that each row is one io.ReadFull however r delivers
+// the bytes, and writes through a bufio.Writer: one Write per 4 KiB,
+// not per row.
+func Dump(w io.Writer, r io.Reader, opt Options) error {
+	width := cmp.Or(opt.Width, 16)
+	if width < 1 {
+		return fmt.Errorf("hexdump: width %d", width)
+	}
+	br, bw := bufio.NewReader(r), bufio.NewWriter(w)
+	row, prev := make([]byte, width), make([]byte, width)
+	off, havePrev, starred := opt.Offset, false, false
+	for {
+		n, err := io.ReadFull(br, row)
+		if n > 0 {
+			if !opt.Verbose && havePrev && n == width && bytes.Equal(row, prev) {
+				if !starred {
+					bw.WriteString("*\n")
+					starred = true
+				}
+			} else {
+				writeRow(bw, off, row[:n], width)
+				starred = false
+			}
+			havePrev = n == width
+			copy(prev, row)
+			off += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(bw, "%08x\n", off) // Where the data ends, as hexdump does
+	return bw.Flush()
+}
+
+func writeRow(w *bufio.Writer, off int64, row []byte, width int) {
+	half := (width + 1) / 2
+	hex := fmt.Sprintf("% x", row[:min(len(row), half)])
+	if len(row) > half {
+		hex += fmt.Sprintf("  % x", row[half:])
+	}
+	ascii := make([]byte, len(row))
+	for i, b := range row {
+		ascii[i] = '.'
+		if b >= ' ' && b <= '~' {
+			ascii[i] = b
+		}
+	}
+	// Pad a short row to the width of a full one (two digits per byte, a
+	// space between bytes, one more between the groups) so | lines up.
+	full := 3*width - 1
+	if width > 1 {
+		full++
+	}
+	fmt.Fprintf(w, "%08x  %-*s  |%s|\n", off, full, hex, ascii)
+}
+
+// ---------------------------------------------------------
+// Part 2: gotut tool hexdump
+// ---------------------------------------------------------
+
+func toolHexdump(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("hexdump", flag.ContinueOnError)
+	width := fs.Int("w", 16, "bytes per row")
+	skip := fs.Int64("s", 0, "start at this offset (0x hex ok); negative counts from the end")
+	length := fs.Int64("n", -1, "dump at most this many bytes (0x hex ok)")
+	verbose := fs.Bool("v", false, "print repeated rows instead of *")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("want one FILE, or - for stdin")
+	}
+	if *width < 1 || *width > 64 {
+		return fmt.Errorf("-w %d: want 1 to 64", *width)
+	}
+	var r io.Reader
+	if name := fs.Arg(0); name == "-" {
+		if *skip < 0 {
+			return errors.New("-s from the end needs a file, not stdin")
+		}
+		// Stdin can't seek: read the skipped bytes and throw them away.
+		if _, err := io.CopyN(io.Discard, os.Stdin, *skip); err != nil && err != io.EOF {
+			return err
+		}
+		r = os.Stdin
+	} else {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if *skip < 0 {
+			*skip = max(0, info.Size()+*skip)
+		}
+		if _, err := f.Seek(*skip, io.SeekStart); err != nil {
+			return err
+		}
+		r = f
+	}
+	if *length >= 0 {
+		r = io.LimitReader(r, *length)
+	}
+	return Dump(stdout, r, Options{Width: *width, Offset: *skip, Verbose: *verbose})
+}
+
+// ---------------------------------------------------------
+// Part 3: Reading a Binary Format — PNG
+// ---------------------------------------------------------
+// A PNG is an 8-byte signature, then chunks:
+//
+//	length uint32 BE | type [4]byte | data [length]byte | crc uint32 BE
+//
+// where crc is CRC-32 (IEEE) of type and data.
+
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
+type Chunk struct {
+	Offset int64 // Of the length field, for finding it in a dump
+	Type   string
+	Data   []byte
+	CRCOK  bool
+}
+
+var ErrFormat = errors.New("not a valid PNG")
+
+func PNGChunks(data []byte) ([]Chunk, error) {
+	if !bytes.HasPrefix(data, pngMagic) {
+		return nil, fmt.Errorf("%w: signature % x", ErrFormat, data[:min(len(data), 8)])
+	}
+	r := bytes.NewReader(data[len(pngMagic):])
+	var chunks []Chunk
+	for r.Len() > 0 {
+		c := Chunk{Offset: int64(len(data) - r.Len())}
+		var length uint32
+		var typ [4]byte
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return chunks, fmt.Errorf("%w: chunk at %#x: %v", ErrFormat, c.Offset, err)
+		}
+		if _, err := io.ReadFull(r, typ[:]); err != nil {
+			return chunks, fmt.Errorf("%w: chunk at %#x: %v", ErrFormat, c.Offset, err)
+		}
+		// The length is data from the file. Check it before allocating:
+		// 0xffffffff would ask for 4 GiB.
+		if int64(length)+4 > int64(r.Len()) {
+			return chunks, fmt.Errorf("%w: %s chunk at %#x claims %d bytes, %d left", ErrFormat, typ, c.Offset, length, r.Len())
+		}
+		c.Type, c.Data = string(typ[:]), make([]byte, length)
+		io.ReadFull(r, c.Data)
+		var crc uint32
+		binary.Read(r, binary.BigEndian, &crc)
+		c.CRCOK = crc == crc32.Update(crc32.ChecksumIEEE(typ[:]), crc32.IEEETable, c.Data)
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// IHDR is the header chunk's layout: 13 bytes, all fixed-size fields,
+// so binary.Read fills it in one call.
+type IHDR struct {
+	Width, Height     uint32
+	BitDepth, Color   uint8
+	Compression       uint8
+	Filter, Interlace uint8
+}
+
+func samplePNG() ([]byte, error) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	img.Set(1, 0, color.NRGBA{G: 0xff, A: 0xff})
+	img.Set(2, 1, color.NRGBA{B: 0xff, A: 0x80})
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), err
+}
+
+// ---------------------------------------------------------
+// Part 4: One Record, Three Encodings
+// ---------------------------------------------------------
+
+// Reading is all fixed-size fields, so encoding/binary can write it as
+// its raw layout: binary.Size(Reading{}) == 10.
+type Reading struct {
+	Sensor  uint16
+	Millis  uint32
+	Celsius float32
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// indent prints a dump two spaces in, like the rest of the demo.
+func indent(w io.Writer, r io.Reader, opt Options) error {
+	var out bytes.Buffer
+	if err := Dump(&out, r, opt); err != nil {
+		return err
+	}
+	for line := range strings.Lines(out.String()) {
+		fmt.Fprint(w, "  ", line)
+	}
+	return nil
+}
+
+func demo() error {
+	fmt.Println("--- Example 1: Three Columns ---")
+	mixed := append([]byte("GOTUT\x00\x01"), 0xde, 0xad, 0xbe, 0xef, '\n', '\t')
+	mixed = append(mixed, "lesson 186"...)
+	if err := indent(os.Stdout, bytes.NewReader(mixed), Options{}); err != nil {
+		return err
+	}
+	fmt.Println("  Bytes outside ' '..'~' show as '.' on the right; the hex column")
+	fmt.Println("  is the truth. -w 8 gives narrower rows, same bytes:")
+	if err := indent(os.Stdout, bytes.NewReader(mixed), Options{Width: 8}); err != nil {
+		return err
+	}
+	fmt.Printf("  The row is three verbs: %%08x → %08x, %% x → %s, %%-*s pads it.\n",
+		0x10, fmt.Sprintf("% x", mixed[:4]))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Ranges and Repeats ---")
+	block := make([]byte, 96)
+	copy(block, "header")
+	copy(block[80:], "trailer")
+	if err := indent(os.Stdout, bytes.NewReader(block), Options{}); err != nil {
+		return err
+	}
+	fmt.Println("  Three rows of zeros became one \"*\"; the offsets show what was skipped.")
+	dir, err := os.MkdirTemp("", "gotut-hexdump-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "block.bin")
+	if err := os.WriteFile(path, block, 0o644); err != nil {
+		return err
+	}
+	for _, args := range [][]string{
+		{"-s", "0x50", "-n", "7", path},
+		{"-s", "-16", "-w", "8", path},
+	} {
+		fmt.Printf("  $ gotut tool hexdump %s\n", strings.ReplaceAll(strings.Join(args, " "), path, "block.bin"))
+		var out bytes.Buffer
+		if err := toolHexdump(args, &out); err != nil {
+			return err
+		}
+		for line := range strings.Lines(out.String()) {
+			fmt.Print("    ", line)
+		}
+	}
+	fmt.Println("  The offset column keeps the FILE's offsets, so a range can be matched")
+	fmt.Println("  with a full dump. The tool seeks; on stdin it reads and discards.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Reading a PNG With encoding/binary ---")
+	img, err := samplePNG()
+	if err != nil {
+		return err
+	}
+	if err := indent(os.Stdout, bytes.NewReader(img[:0x30]), Options{}); err != nil {
+		return err
+	}
+	chunks, err := PNGChunks(img)
+	if err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		fmt.Printf("  %#06x  %s  %3d bytes  crc ok=%v\n", c.Offset, c.Type, len(c.Data), c.CRCOK)
+	}
+	var hdr IHDR
+	if err := binary.Read(bytes.NewReader(chunks[0].Data), binary.BigEndian, &hdr); err != nil {
+		return err
+	}
+	fmt.Printf("  IHDR via binary.Read: %+v\n", hdr)
+	check(hdr.Width == 3 && hdr.Height == 2 && binary.Size(hdr) == 13,
+		"3×2, and binary.Size(IHDR{}) is exactly the chunk's 13 bytes",
+		fmt.Sprintf("IHDR %+v", hdr))
+	fmt.Println("  In the dump: 89 50 4e 47 is the magic, 00 00 00 0d the first length")
+	fmt.Println("  (13), then \"IHDR\" and 00 00 00 03 / 00 00 00 02, the width and height.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Flipped Bit, the Wrong Byte Order, a Lying Length ---")
+	bad := bytes.Clone(img)
+	idat := chunks[1].Offset + 8 // Past the length and the type
+	bad[idat] ^= 0x01
+	badChunks, _ := PNGChunks(bad)
+	check(!badChunks[1].CRCOK, fmt.Sprintf("one bit flipped at %#x: the %s CRC no longer matches", idat, badChunks[1].Type),
+		"the corruption went unnoticed")
+	le := binary.LittleEndian.Uint32(chunks[0].Data[:4])
+	check(le != hdr.Width, fmt.Sprintf("width read little-endian: %d, not %d (00 00 00 03 backwards)", le, hdr.Width),
+		"byte order made no difference?")
+	lying := bytes.Clone(img)
+	binary.BigEndian.PutUint32(lying[8:], 0xffffffff)
+	_, err = PNGChunks(lying)
+	check(errors.Is(err, ErrFormat), fmt.Sprintf("length 0xffffffff rejected before allocating: %v", err),
+		"the lying length was believed")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: One Record, Three Encodings ---")
+	r1 := Reading{Sensor: 7, Millis: 120_000, Celsius: 21.5}
+	r2 := Reading{Sensor: 7, Millis: 121_000, Celsius: 21.75}
+	var raw bytes.Buffer
+	if err := binary.Write(&raw, binary.LittleEndian, r1); err != nil {
+		return err
+	}
+	fmt.Printf("  binary.Write, little-endian (%d bytes: the struct's layout, nothing else):\n", raw.Len())
+	if err := indent(os.Stdout, bytes.NewReader(raw.Bytes()), Options{}); err != nil {
+		return err
+	}
+	var back Reading
+	binary.Read(bytes.NewReader(raw.Bytes()), binary.LittleEndian, &back)
+	check(back == r1, "binary.Read gives the record back", fmt.Sprintf("got %+v", back))
+
+	var g bytes.Buffer
+	enc := gob.NewEncoder(&g)
+	if err := enc.Encode(r1); err != nil {
+		return err
+	}
+	first := g.Len()
+	if err := enc.Encode(r2); err != nil {
+		return err
+	}
+	fmt.Printf("\n  gob, two records on one Encoder (%d bytes, then %d):\n", first, g.Len()-first)
+	if err := indent(os.Stdout, bytes.NewReader(g.Bytes()), Options{}); err != nil {
+		return err
+	}
+	fmt.Println("  The first message (0x36 = 54 bytes after its length) is the TYPE: the")
+	fmt.Println("  names Reading, Sensor, Millis and Celsius are right there in the ASCII")
+	fmt.Println("  column. Each value after it refers to the type by number (ff 80) and")
+	fmt.Println("  carries only the fields.")
+	dec := gob.NewDecoder(bytes.NewReader(g.Bytes()))
+	var d1, d2 Reading
+	err = errors.Join(dec.Decode(&d1), dec.Decode(&d2))
+	check(err == nil && d1 == r1 && d2 == r2, "gob.Decoder reads both back, no layout agreed beforehand",
+		fmt.Sprintf("decode: %v", err))
+
+	js, _ := json.Marshal(r1)
+	fmt.Printf("\n  JSON (%d bytes): %s\n", len(js), js)
+	fmt.Println()
+	fmt.Println("  binary: smallest and fastest, but reader and writer must agree on")
+	fmt.Println("  every field, its size and the byte order; adding a field breaks old")
+	fmt.Println("  files (152's WAL records and 153's CRC trailers are written this way).")
+	fmt.Println("  gob: self-describing and tolerant of added or missing fields, Go only.")
+	fmt.Println("  protobuf (247-256) is the cross-language middle ground.")
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch {
+		case len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "hexdump":
+			err = toolHexdump(os.Args[3:], os.Stdout)
+		default:
+			err = fmt.Errorf("unknown command %q (want: tool hexdump)", strings.Join(os.Args[1:], " "))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hexdump:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: LOOKING AT BYTES — A HEXDUMP TOOL AND READING BINARY FORMATS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A hex dump is three fmt verbs over a bufio.Reader and bufio.Writer.
+2. Check the magic number, then read fields in the format's byte order.
+3. Bound every length field by the bytes that are left before allocating.
+4. Verify checksums before trusting the data they cover.
+5. encoding/binary writes bare layouts; gob sends the type with the data.
+	`)
+}
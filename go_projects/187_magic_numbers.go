@@ -0,0 +1,350 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/blobstore"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/filetype"
+)
+
+/*
+TOPIC: MAGIC NUMBERS — WHAT A FILE IS, FROM ITS FIRST BYTES
+
+CONCEPT:
+A file name is a claim anyone can make: rename a program to cat.png and
+the extension says image. So does the Content-Type an upload arrives with
+— the client picked it. Most binary formats, though, start with fixed
+bytes of their own, a MAGIC NUMBER (Topic 186 dumped PNG's):
+
+    PNG    89 50 4e 47 0d 0a 1a 0a   "\x89PNG\r\n\x1a\n"
+    JPEG   ff d8 ff
+    GZIP   1f 8b
+    ZIP    50 4b 03 04               "PK", also .docx .xlsx .jar .apk
+    PDF    25 50 44 46 2d            "%PDF-"
+    ELF    7f 45 4c 46               "\x7fELF": Linux executables
+
+pkg/filetype checks those:
+
+    t, r, err := filetype.Detect(body)   // t == filetype.PNG
+    io.Copy(dst, r)                      // r still has EVERY byte
+
+DON'T CONSUME THE STREAM. An upload body or a pipe can't be rewound: read
+8 bytes to look at them and they are gone from the copy. bufio.Reader.Peek
+returns the next bytes without advancing, so Detect peeks and hands back
+the bufio.Reader to read everything from.
+
+net/http has DetectContentType (the WHATWG sniffing algorithm) for the
+same job in browsers' terms. It looks at up to 512 bytes, knows HTML and
+text but not executables, and never fails: unknown is
+"application/octet-stream".
+
+A MAGIC NUMBER IS A CLAIM TOO, just a harder one to make by accident. It
+says what a file starts as, not that it is complete or safe; decoding is
+the only full check.
+
+In the course: the upload handler below, which keeps what it accepts in
+a pkg/blobstore under the content's SHA-256, and 154's backup tool, which
+warns when a .jpg being backed up no longer starts like a JPEG.
+
+RUN:
+    go run 187_magic_numbers.go
+    go test ./pkg/filetype ./pkg/blobstore
+*/
+
+// ---------------------------------------------------------
+// Part 1: Samples of Each Format
+// ---------------------------------------------------------
+
+func sampleImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := range 4 {
+		img.Set(i, i, color.NRGBA{R: 0xcc, G: 0x33, B: 0x66, A: 0xff})
+	}
+	return img
+}
+
+type sample struct {
+	name string
+	data []byte
+}
+
+// samples makes one file of each format with the standard library, plus
+// the first bytes of this program's own binary and some text.
+func samples() ([]sample, error) {
+	var p, j, g, z bytes.Buffer
+	img := sampleImage()
+	if err := png.Encode(&p, img); err != nil {
+		return nil, err
+	}
+	if err := jpeg.Encode(&j, img, nil); err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(&g)
+	gz.Write([]byte("log line\n"))
+	zw := zip.NewWriter(&z)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte("<w:document/>"))
+	if err := errors.Join(gz.Close(), zw.Close()); err != nil {
+		return nil, err
+	}
+	out := []sample{
+		{"cat.png", p.Bytes()},
+		{"cat.jpg", j.Bytes()},
+		{"app.log.gz", g.Bytes()},
+		{"report.docx", z.Bytes()},
+		{"spec.pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")},
+		{"notes.txt", []byte("Topic 187: magic numbers\n")},
+	}
+	// go run builds a real executable; its header is an ELF one on Linux
+	// (Mach-O on macOS, PE on Windows, which this package doesn't know).
+	if exe, err := os.Executable(); err == nil {
+		if f, err := os.Open(exe); err == nil {
+			head := make([]byte, 4096)
+			n, _ := io.ReadFull(f, head)
+			f.Close()
+			out = append(out, sample{"program", head[:n]})
+		}
+	}
+	return out, nil
+}
+
+// ---------------------------------------------------------
+// Part 2: An Upload Handler That Checks the Bytes
+// ---------------------------------------------------------
+// The client's file name and Content-Type are kept for the log but
+// decide nothing. The part is streamed: Detect peeks at it, and the same
+// reader goes into the blob store, size-limited on the way. The store
+// names it by its hash, so the client doesn't pick the name either, and
+// the same file uploaded twice is kept once.
+
+var acceptUploads = []filetype.Type{filetype.PNG, filetype.JPEG, filetype.PDF}
+
+const maxUpload = 1 << 20
+
+type uploadHandler struct {
+	store *blobstore.Store
+}
+
+func (h uploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	part, err := mr.NextPart()
+	if err != nil || part.FormName() != "file" {
+		http.Error(w, "want a multipart form with a \"file\" field", http.StatusBadRequest)
+		return
+	}
+	typ, body, err := filetype.Detect(part)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !slices.Contains(acceptUploads, typ) {
+		http.Error(w, fmt.Sprintf("%s is %v, not PNG, JPEG or PDF", part.FileName(), typ), http.StatusUnsupportedMediaType)
+		return
+	}
+	addr, err := h.store.Put(body) // Nothing is stored unless all of it arrives
+	if err != nil {
+		if mbe := (*http.MaxBytesError)(nil); errors.As(err, &mbe) {
+			http.Error(w, fmt.Sprintf("larger than %d bytes", mbe.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, "stored %s as %s: %s…", part.FileName(), typ, addr[:12])
+}
+
+// upload posts data as a multipart "file" field, claiming the given file
+// name and Content-Type.
+func upload(url, name, contentType string, data []byte) (int, string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="file"; filename=%q`, name)},
+		"Content-Type":        {contentType},
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	pw.Write(data)
+	mw.Close()
+	resp, err := http.Post(url, mw.FormDataContentType(), &body)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, strings.TrimSpace(string(msg)), nil
+}
+
+// ---------------------------------------------------------
+// Part 3: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func find(all []sample, name string) []byte {
+	for _, s := range all {
+		if s.name == name {
+			return s.data
+		}
+	}
+	return nil
+}
+
+func demo() error {
+	all, err := samples()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("--- Example 1: The First Bytes of Each Format ---")
+	fmt.Printf("  %-12s %-25s %-8s %s\n", "file", "first 8 bytes", "Detect", "http.DetectContentType")
+	for _, s := range all {
+		t, _, _ := filetype.Detect(bytes.NewReader(s.data))
+		fmt.Printf("  %-12s %-25s %-8v %s\n", s.name, fmt.Sprintf("% x", s.data[:min(8, len(s.data))]), t, http.DetectContentType(s.data))
+	}
+	fmt.Println("  DetectContentType calls the .docx a zip too and knows text, but calls")
+	fmt.Println("  an executable octet-stream. Use whichever knows the formats you need.")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Peek, Don't Read ---")
+	pic := find(all, "cat.png")
+	pipe := func() io.Reader { // A stream that can't be rewound, like a request body
+		r, w := io.Pipe()
+		go func() { w.Write(pic); w.Close() }()
+		return r
+	}
+	r := pipe()
+	head := make([]byte, 8)
+	io.ReadFull(r, head)
+	rest, _ := io.ReadAll(r)
+	_, errRead := png.Decode(bytes.NewReader(rest))
+	fmt.Printf("  ReadFull 8 bytes to look: %v, then %d bytes left; png.Decode: %v\n",
+		filetype.Match(head), len(rest), errRead)
+	typ, br, _ := filetype.Detect(pipe())
+	all2, _ := io.ReadAll(br)
+	_, errPeek := png.Decode(bytes.NewReader(all2))
+	fmt.Printf("  Detect (Peek):            %v, then %d bytes left; png.Decode: %v\n", typ, len(all2), errPeek)
+	check(errRead != nil && errPeek == nil && len(all2) == len(pic),
+		"Peek looked at the header without taking it out of the stream",
+		"peeking lost bytes")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Validating Uploads ---")
+	dir, err := os.MkdirTemp("", "gotut-uploads-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	store, err := blobstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	srv := httptest.NewServer(uploadHandler{store})
+	defer srv.Close()
+	big := append(bytes.Clone(find(all, "spec.pdf")), make([]byte, maxUpload)...)
+	for _, u := range []struct {
+		name, contentType string
+		data              []byte
+		want              int
+	}{
+		{"cat.png", "image/png", pic, http.StatusCreated},
+		{"photo.jpeg", "application/octet-stream", find(all, "cat.jpg"), http.StatusCreated},
+		{"invoice.pdf", "application/pdf", find(all, "spec.pdf"), http.StatusCreated},
+		{"cat-copy.png", "image/png", pic, http.StatusCreated},
+		{"cute-cat.png", "image/png", find(all, "program"), http.StatusUnsupportedMediaType},
+		{"resume.pdf", "application/pdf", find(all, "report.docx"), http.StatusUnsupportedMediaType},
+		{"scan.pdf", "application/pdf", big, http.StatusRequestEntityTooLarge},
+	} {
+		code, msg, err := upload(srv.URL, u.name, u.contentType, u.data)
+		if err != nil {
+			return err
+		}
+		mark := "✓"
+		if code != u.want {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %-13s claims %-24s → %d %s\n", mark, u.name, u.contentType, code, msg)
+	}
+	stored, err := store.All()
+	if err != nil {
+		return err
+	}
+	check(len(stored) == 3,
+		"4 uploads accepted, 3 blobs stored: cat-copy.png is cat.png's bytes, so its address",
+		fmt.Sprintf("%d blobs stored", len(stored)))
+	fmt.Println("  The program named cute-cat.png said image/png twice and was refused;")
+	fmt.Println("  photo.jpeg sent no useful Content-Type and was accepted as a JPEG.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: What a Magic Number Doesn't Tell You ---")
+	cut := pic[:len(pic)/2]
+	t, _, _ := filetype.Detect(bytes.NewReader(cut))
+	_, errCut := png.Decode(bytes.NewReader(cut))
+	check(t == filetype.PNG && errCut != nil,
+		fmt.Sprintf("half a PNG still starts like one (%v); only decoding finds %q", t, errCut.Error()),
+		"truncation was caught by the magic number?")
+	want, _ := filetype.ByExt("report.docx")
+	zr, err := zip.NewReader(bytes.NewReader(find(all, "report.docx")), int64(len(find(all, "report.docx"))))
+	inside := "?"
+	if err == nil && len(zr.File) > 0 {
+		inside = zr.File[0].Name
+	}
+	check(want == filetype.ZIP,
+		fmt.Sprintf(".docx promises %v: a Word file is a ZIP; only its entries (%s) say Word", want, inside),
+		"ByExt disagrees about .docx")
+	fmt.Println("  So: magic numbers to route and to refuse early; a real decoder (image,")
+	fmt.Println("  zip, a PDF library) before trusting the contents.")
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: MAGIC NUMBERS — WHAT A FILE IS, FROM ITS FIRST BYTES")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. File names and Content-Types are claims; the first bytes are evidence.
+2. bufio.Reader.Peek looks ahead without consuming a stream you can't rewind.
+3. Name stored uploads yourself: by the hash of the content, never the client's name.
+4. Limit the body (http.MaxBytesReader) before copying it anywhere.
+5. A magic number says how a file starts; decode it before trusting the rest.
+	`)
+}
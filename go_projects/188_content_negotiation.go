@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/negotiate"
+)
+
+/*
+TOPIC: MIME TYPES AND CONTENT NEGOTIATION — ONE URL, JSON OR HTML
+
+CONCEPT:
+A MIME type ("media type") names a format: type/subtype plus parameters.
+
+    text/html; charset=utf-8
+    └──┬──┘└┬┘ └─────┬─────┘
+     type  sub   parameter
+
+Go's mime package does two jobs with them:
+
+    mime.TypeByExtension(filepath.Ext(name))  ".json" → "application/json"
+                                              (Ext is Topic 86's)
+    mime.ParseMediaType(header)               split type and parameters,
+                                              lower-cased, quotes handled
+
+A file server needs the first; anything that reads Content-Type or
+Accept needs the second. Comparing headers as strings breaks on
+"Application/JSON; charset=utf-8".
+
+CONTENT NEGOTIATION: the client says what it can take, with QUALITY values
+from 0 to 1 (q defaults to 1, q=0 means "not this"). "(anything)" below
+is the wildcard range, star-slash-star, which can't be spelled inside
+this comment:
+
+    browser  Accept: text/html,application/xhtml+xml,(anything);q=0.8
+    curl     Accept: (anything)
+    script   Accept: application/json
+
+and the server picks the best of what it can OFFER:
+
+    negotiate.Best(r.Header.Get("Accept"), []string{"application/json", "text/html"})
+
+For each offer, the most specific matching range sets its quality
+(text/html beats text/(any) beats (anything)). The highest quality
+wins; ties go to the server's order. Nothing acceptable → 406 Not Acceptable.
+
+THE CACHE RULE: a URL whose body depends on Accept must send
+"Vary: Accept", or a shared cache serves the HTML it stored for a browser
+to the next script.
+
+155's daemon does this for /status; this lesson builds it from the parts.
+
+RUN:
+    go run 188_content_negotiation.go
+    go test ./pkg/negotiate
+*/
+
+// ---------------------------------------------------------
+// Part 1: One Endpoint, Three Representations
+// ---------------------------------------------------------
+
+type Lesson struct {
+	Num   int    `json:"num"`
+	Title string `json:"title"`
+	File  string `json:"file"`
+}
+
+var catalog = []Lesson{
+	{186, "Looking at bytes", "186_hexdump.go"},
+	{187, "Magic numbers", "187_magic_numbers.go"},
+	{188, "Content negotiation", "188_content_negotiation.go"},
+}
+
+// lessonTypes are what /lessons can send, in order of preference: a
+// client that doesn't care gets JSON.
+var lessonTypes = []string{"application/json", "text/html", "text/csv"}
+
+var lessonPage = template.Must(template.New("lessons").Parse(`<!doctype html>
+<title>Lessons</title>
+<ul>{{range .}}
+<li>{{.Num}} <a href="/src/{{.File}}">{{.Title}}</a></li>{{end}}
+</ul>
+`))
+
+func lessonsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Vary", "Accept")
+	switch typ := negotiate.Best(r.Header.Get("Accept"), lessonTypes); typ {
+	case "application/json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(catalog)
+	case "text/html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		lessonPage.Execute(w, catalog)
+	case "text/csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"num", "title", "file"})
+		for _, l := range catalog {
+			cw.Write([]string{fmt.Sprint(l.Num), l.Title, l.File})
+		}
+		cw.Flush()
+	default:
+		http.Error(w, "available as "+strings.Join(lessonTypes, ", "), http.StatusNotAcceptable)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 2: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+const (
+	firefox = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	curl    = "*/*"
+)
+
+func demo() error {
+	fmt.Println("--- Example 1: Extensions to Content Types ---")
+	for _, name := range []string{"index.html", "STYLE.CSS", "lessons.json", "cat.jpeg", "186_hexdump.go", "backup.tar.gz", "README"} {
+		ext := filepath.Ext(name) // Topic 86: ".gz" for backup.tar.gz, "" for README
+		typ := mime.TypeByExtension(ext)
+		if typ == "" {
+			typ = "(unknown: send application/octet-stream)"
+		}
+		fmt.Printf("  %-15s Ext %-6q → %s\n", name, ext, typ)
+	}
+	fmt.Println("  Text types come back with \"; charset=utf-8\" added, case doesn't matter,")
+	fmt.Println("  and past a built-in core (html, css, js, json, png, jpeg, pdf, svg, ...)")
+	fmt.Println("  the answer comes from the machine's mime.types, so it varies by system.")
+	if err := mime.AddExtensionType(".gotut", "application/x-gotut-notes"); err != nil {
+		return err
+	}
+	check(mime.TypeByExtension(".gotut") == "application/x-gotut-notes",
+		`mime.AddExtensionType(".gotut", ...) registers our own for this process`,
+		"AddExtensionType had no effect")
+	exts, _ := mime.ExtensionsByType("image/jpeg")
+	fmt.Printf("  and back: ExtensionsByType(\"image/jpeg\") = %v\n", exts)
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Parse Media Types, Don't Compare Strings ---")
+	header := `Application/JSON; Charset="UTF-8"`
+	mt, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %-36s == \"application/json\"? %v\n", header, header == "application/json")
+	fmt.Printf("  ParseMediaType → %q, params %v\n", mt, params)
+	check(mt == "application/json" && params["charset"] == "UTF-8",
+		"type lower-cased, parameter names lower-cased, quotes removed",
+		"ParseMediaType surprised us")
+	fmt.Printf("  FormatMediaType builds one: %s\n", mime.FormatMediaType("text/plain", map[string]string{"charset": "utf-8", "format": "flowed"}))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Reading an Accept Header ---")
+	for _, accept := range []string{firefox, curl, "text/*;q=0.5, text/html, application/json;q=0"} {
+		fmt.Printf("  %s\n", accept)
+		for _, r := range negotiate.Parse(accept) {
+			fmt.Printf("    %-24s q=%.1f\n", r.Type+"/"+r.Subtype, r.Q)
+		}
+	}
+	fmt.Println("  Sorted by quality, then specificity. q=0 is a refusal, not a low bid.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Best(accept, offers) ---")
+	offers := []string{"application/json", "text/html"}
+	for _, tc := range []struct{ who, accept, want string }{
+		{"browser", firefox, "text/html"},
+		{"curl", curl, "application/json"},
+		{"no header", "", "application/json"},
+		{"script", "application/json", "application/json"},
+		{"prefers HTML less", "text/html;q=0.5, application/json;q=0.6", "application/json"},
+		{"a tie", "text/html, application/json", "application/json"},
+		{"anything but JSON", "*/*, application/json;q=0", "text/html"},
+		{"only XML", "application/xml", ""},
+	} {
+		got := negotiate.Best(tc.accept, offers)
+		mark := "✓"
+		if got != tc.want {
+			mark = "✗"
+		}
+		shown := got
+		if shown == "" {
+			shown = `"" → 406`
+		}
+		fmt.Printf("  %s %-18s %-44q → %s\n", mark, tc.who, tc.accept, shown)
+	}
+	fmt.Println("  A tie goes to the server's first offer: JSON, so curl and old scripts")
+	fmt.Println("  keep getting what they got before HTML existed.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: One URL, Three Representations ---")
+	srv := httptest.NewServer(http.HandlerFunc(lessonsHandler))
+	defer srv.Close()
+	for _, accept := range []string{curl, firefox, "text/csv", "image/png"} {
+		req, _ := http.NewRequest("GET", srv.URL+"/lessons", nil)
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		first, _, _ := strings.Cut(strings.TrimSpace(string(body)), "\n")
+		fmt.Printf("  Accept %-28.28q → %d %-26s Vary=%s\n", accept, resp.StatusCode, resp.Header.Get("Content-Type"), resp.Header.Get("Vary"))
+		fmt.Printf("    %.70s\n", first)
+	}
+	fmt.Println("  155_daemon's /status works the same way: curl gets JSON, a browser a table.")
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: MIME TYPES AND CONTENT NEGOTIATION — ONE URL, JSON OR HTML")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. mime.TypeByExtension(filepath.Ext(name)) for serving files; unknown → octet-stream.
+2. mime.ParseMediaType before comparing: types and parameters are case-insensitive.
+3. The most specific matching range sets an offer's quality; q=0 refuses it.
+4. Put the default representation first: ties and */* go to it.
+5. A body that depends on Accept needs Vary: Accept; nothing acceptable is a 406.
+	`)
+}
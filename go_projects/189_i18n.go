@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/msg"
+)
+
+/*
+TOPIC: INTERNATIONALIZED MESSAGES — CATALOGS, PLURALS AND PITFALLS
+
+CONCEPT:
+Every string a person reads becomes a KEY; each language has a CATALOG
+from key to an fmt format. pkg/msg embeds its catalogs (catalogs/en.json,
+es.json, fr.json) with //go:embed and looks them up:
+
+    msg.T(lang, "cli.no-lesson", 42, dir)   en: no lesson 42 in ./course
+                                            es: no hay ninguna lección 42 en ./course
+    msg.N(lang, "lesson.count", n)          KEY.one or KEY.other, by lang's rule
+
+WHICH LANGUAGE: the first of these that says anything, then msg.Default:
+
+    a flag          gotut -lang es ...               (go_projects/gotut)
+    the locale      LC_ALL, LC_MESSAGES, LANG        msg.FromEnv()
+    HTTP            Accept-Language: es-MX,es;q=0.9  msg.ParseAcceptLanguage
+
+msg.Match picks the first one with a catalog, trying "es-MX" then "es"
+before moving on, and a lookup falls back key by key:
+pt-BR → pt → en → the key itself.
+
+THE PITFALLS this lesson walks through:
+    1. Building sentences by concatenation: word order is the language's.
+    2. "file(s)": plural rules differ (French 0 is singular; Polish has 4).
+    3. Matching on error TEXT: it changes with the language. Match codes.
+    4. Translating what MACHINES read: exit codes, JSON keys, log lines.
+    5. A missing key must be visible, and a test must catch it first.
+
+Real programs with many languages use golang.org/x/text (message,
+language.Matcher, feature/plural, number formats). pkg/msg is the small
+stdlib-only version of the same idea, enough for three catalogs.
+
+RUN:
+    go run 189_i18n.go
+    go test ./pkg/msg
+*/
+
+// ---------------------------------------------------------
+// Part 1: Errors With a Key, Not Just Text
+// ---------------------------------------------------------
+// The same shape as gotut's apperrors.M: the error keeps its key and
+// arguments, so the text can be rendered in any language later, and code
+// compares the key, never the text.
+
+type lessonError struct {
+	Key  string
+	Args []any
+}
+
+func (e *lessonError) Error() string { return msg.T(msg.Default, e.Key, e.Args...) }
+
+// In renders the error for a reader of lang.
+func (e *lessonError) In(lang string) string { return msg.T(lang, e.Key, e.Args...) }
+
+func findLesson(n int) error {
+	return &lessonError{Key: "cli.no-lesson", Args: []any{n, "./course"}}
+}
+
+// ---------------------------------------------------------
+// Part 2: Language From an HTTP Request
+// ---------------------------------------------------------
+
+// greet answers in the request's language. The body depends on
+// Accept-Language, so it says so in Vary (Topic 188's rule, another
+// header), and Content-Language says which one it picked.
+func greet(w http.ResponseWriter, r *http.Request) {
+	lang := msg.Match(msg.ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+	if q := r.URL.Query().Get("lang"); q != "" {
+		lang = msg.Match(q) // An explicit choice beats the browser's guess
+	}
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", lang)
+	fmt.Fprintln(w, msg.T(lang, "greeting", r.URL.Query().Get("name")))
+}
+
+// ---------------------------------------------------------
+// Part 3: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func demo() error {
+	fmt.Println("--- Example 1: Concatenation vs a Catalog ---")
+	owner, topic := "Ana", "regex"
+	fmt.Printf("  English-shaped code:  %q\n", owner+"'s "+topic+" lesson")
+	fmt.Println("  A translator can't reorder what the code glued together. A key can:")
+	for _, lang := range msg.Languages() {
+		fmt.Printf("    %s  %s\n", lang, msg.T(lang, "lesson.by", owner, topic))
+	}
+	fmt.Printf("  es and fr say %q: explicit indexes put the arguments\n", "%[2]s de %[1]s")
+	fmt.Println("  where the language wants them, while the call stays T(lang, key, owner, topic).")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Plurals Are Per Language ---")
+	fmt.Println("  n   en            es              fr")
+	for _, n := range []int{0, 1, 2} {
+		fmt.Printf("  %d   %-12s  %-14s  %s\n", n, msg.N("en", "lesson.count", n),
+			msg.N("es", "lesson.count", n), msg.N("fr", "lesson.count", n))
+	}
+	check(msg.N("fr", "lesson.count", 0) == "0 leçon" && msg.N("en", "lesson.count", 0) == "0 lessons",
+		"French says \"0 leçon\", English \"0 lessons\": the rule is the language's, not n == 1",
+		"plural rules are wrong")
+	fmt.Println(`  "lesson(s)" dodges the question in English only. Polish, Russian and`)
+	fmt.Println("  Arabic need four to six forms: that is when to reach for x/text/feature/plural.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Choosing the Language ---")
+	for _, env := range []string{"es_ES.UTF-8", "fr_CA.UTF-8", "de_DE.UTF-8", "C", ""} {
+		fmt.Printf("  LANG=%-12s → FromEnv %-7q → Match %s\n", env, msg.Normalize(env), msg.Match(msg.Normalize(env)))
+	}
+	srv := httptest.NewServer(http.HandlerFunc(greet))
+	defer srv.Close()
+	for _, tc := range []struct{ accept, query, want string }{
+		{"es-MX,es;q=0.9,en;q=0.8", "", "es"},
+		{"de-DE,fr;q=0.7,en;q=0.5", "", "fr"}, // No German: the NEXT choice, not English
+		{"pt-BR,pt;q=0.9", "", "en"},
+		{"es-MX,es;q=0.9", "&lang=fr", "fr"},
+	} {
+		req, _ := http.NewRequest("GET", srv.URL+"/?name=Ana"+tc.query, nil)
+		req.Header.Set("Accept-Language", tc.accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		got := resp.Header.Get("Content-Language")
+		check(got == tc.want, fmt.Sprintf("%-26s %-9s → %s  %s", tc.accept, tc.query, got, strings.TrimSpace(string(body))),
+			fmt.Sprintf("%s %s → %s, want %s", tc.accept, tc.query, got, tc.want))
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Match the Error, Not Its Text ---")
+	err := findLesson(42)
+	var le *lessonError
+	if !errors.As(err, &le) {
+		return fmt.Errorf("findLesson returned %T", err)
+	}
+	matched := map[string]bool{}
+	for _, lang := range []string{"en", "es"} {
+		text := le.In(lang)
+		matched[lang] = strings.Contains(text, "no lesson")
+		fmt.Printf("  %s: %-40s contains \"no lesson\"? %v\n", lang, text, matched[lang])
+	}
+	check(matched["en"] && !matched["es"],
+		"a caller matching the text works until the day it's translated",
+		"text matching survived translation?")
+	check(le.Key == "cli.no-lesson",
+		`errors.As + the key ("cli.no-lesson") works in every language`, "lost the key")
+	_, statErr := os.Stat("no/such/file")
+	check(errors.Is(statErr, fs.ErrNotExist),
+		"and the stdlib's way: sentinel errors, errors.Is(err, fs.ErrNotExist)", "Stat found it?")
+	fmt.Println("  gotut does this: usage errors are M(code, op, key, args...),")
+	fmt.Println("  report localizes them, and ExitCode reads the code, never the message.")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: What Not to Translate ---")
+	for _, line := range []string{
+		"exit codes, and gotut verify's ok/FAIL lines   scripts grep them",
+		"JSON field names, flag names, command names    they are an API",
+		"log lines                                      the on-call reader greps in English",
+		"Error()                                        wrapped, logged, compared in tests",
+	} {
+		fmt.Println("  keep:", line)
+	}
+	fmt.Printf("  And formats: fmt prints %v and %.2f in every locale, by design;\n", 1234567, 1234.5)
+	fmt.Println("  \"1.234.567\" or \"1 234 567\" is x/text/message's job, not Sprintf's.")
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Missing Keys Are Loud ---")
+	fmt.Printf("  T(\"es\", \"lesson.retry\") = %q: the key itself, so it shows up and greps\n", msg.T("es", "lesson.retry"))
+	fmt.Printf("  T(\"pt-BR\", \"greeting\", \"Ana\") = %q: no pt catalog, English\n", msg.T("pt-BR", "greeting", "Ana"))
+	var incomplete []string
+	for _, lang := range msg.Languages() {
+		if !slices.Equal(msg.Keys(lang), msg.Keys(msg.Default)) {
+			incomplete = append(incomplete, lang)
+		}
+	}
+	check(len(incomplete) == 0, fmt.Sprintf("every catalog has all %d keys of %s.json (TestCatalogs in pkg/msg also checks the arguments match)",
+		len(msg.Keys(msg.Default)), msg.Default), fmt.Sprintf("catalogs with different keys: %v", incomplete))
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: INTERNATIONALIZED MESSAGES — CATALOGS, PLURALS AND PITFALLS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Every string a person reads is a key; each language has a catalog.
+2. Never build sentences by concatenation: translations reorder with %[n].
+3. Plural forms come from the language's rules, not from n == 1.
+4. Pick the language: flag, then locale or Accept-Language, then a default.
+5. Translate messages, not identity: match errors by code, key or errors.Is.
+6. Leave machine-read output alone, and test every catalog against the default.
+	`)
+}
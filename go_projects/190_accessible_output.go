@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/a11y"
+)
+
+/*
+TOPIC: ACCESSIBLE OUTPUT — LESSONS A SCREEN READER CAN READ
+
+CONCEPT:
+The course decorates its output for eyes: ═══ banners, ✓ and ✗ marks,
+█▌ bar charts, ┌─┬─┐ tables, 📌 notes. A screen reader reads text, not
+pictures, so one banner is read out as the name of one character, sixty
+times over:
+
+    "box drawings double horizontal, box drawings double horizontal, ..."
+
+and "████▌ 25%" comes out as five block names and a number, with no word
+for how long the bar was.
+
+ACCESSIBLE MODE keeps the information and drops the drawing:
+
+    ═══════ banner / ─── rule        dropped
+    --- Example 2: Fan-Out ---       Section: Example 2: Fan-Out
+    ✓ built     ✗ exit 1   ⚠ ...     Pass: built   Fail: exit 1   Warning: ...
+    a → b                            a to b
+    │ name │ size │                  name; size
+    ████▌ 25%                        (bar 4.5) 25%
+    ▁▂▅▇█                            (sparkline 1 2 5 7 8)
+    • one  • two  • three            1. one  2. two  3. three
+    🎉 and other emoji               dropped
+    column    padding                column padding
+
+Numbers matter for the lists: "item 2 of a list" tells a listener where
+they are, and a nested list starts again at 1.
+
+IN THE RENDERER, NOT THE LESSONS. Nothing prints through a shared
+renderer: every lesson writes straight to os.Stdout. What every lesson
+DOES share is the runner that sits on that stdout (172, 176). So
+pkg/a11y is an io.Writer that rewrites line by line, and the runners put
+it between the lesson and the terminal. Two hundred lessons, no changes.
+
+TURNING IT ON, first match wins (a11y.Enabled):
+    the runner's flag       172 run -accessible, 176 run --accessible
+    $GOTUT_ACCESSIBLE       1 or 0, true or false
+    config.json             {"accessible": true} in $GOTUT_CONFIG_DIR,
+                            else <config dir>/gotut (where 168's notes live)
+
+WHAT IT LEAVES ALONE: 176's --format json. Events are for programs;
+a UI that reads them does its own accessible rendering.
+
+RUN:
+    go run 190_accessible_output.go
+    go run 176_run_events.go run 165 --accessible
+    go run 113_timers.go | go run 190_accessible_output.go tool plain
+    go test ./pkg/a11y
+*/
+
+// ---------------------------------------------------------
+// Part 1: What a Screen Reader Hears
+// ---------------------------------------------------------
+
+// names are how screen readers announce the decoration the course uses
+// most, close to the Unicode names they are built from.
+var names = map[rune]string{
+	'═': "box drawings double horizontal", '─': "box drawings light horizontal",
+	'│': "box drawings light vertical", '┌': "box drawings light down and right",
+	'┐': "box drawings light down and left", '└': "box drawings light up and right",
+	'┘': "box drawings light up and left", '┬': "box drawings light down and horizontal",
+	'┴': "box drawings light up and horizontal", '┼': "box drawings light vertical and horizontal",
+	'█': "full block", '▌': "left half block", '▁': "lower one eighth block",
+	'▂': "lower one quarter block", '▅': "lower five eighths block", '▇': "lower seven eighths block",
+	'✓': "check mark", '✗': "ballot x", '⚠': "warning sign", '→': "rightwards arrow",
+	'📌': "pushpin", '🎉': "party popper", '•': "bullet",
+}
+
+// spoken is roughly what a screen reader says for s: words as they are,
+// each symbol as its name.
+func spoken(s string) (words int, text string) {
+	var parts []string
+	for _, f := range strings.Fields(s) {
+		var word strings.Builder
+		for _, r := range f {
+			if name, ok := names[r]; ok {
+				if word.Len() > 0 {
+					parts = append(parts, word.String())
+					word.Reset()
+				}
+				parts = append(parts, name)
+				continue
+			}
+			word.WriteRune(r)
+		}
+		if word.Len() > 0 {
+			parts = append(parts, word.String())
+		}
+	}
+	for _, p := range parts {
+		words += len(strings.Fields(p))
+	}
+	return words, strings.Join(parts, ", ")
+}
+
+// ---------------------------------------------------------
+// Part 2: The Filter Tool
+// ---------------------------------------------------------
+// For output that doesn't come through a runner: pipe it in.
+
+func toolPlain(stdin io.Reader, stdout io.Writer) error {
+	w := a11y.NewWriter(stdout)
+	if _, err := io.Copy(w, stdin); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// ---------------------------------------------------------
+// Part 3: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// sample is output in the course's usual style: a banner, a section, a
+// check, a bar chart, a table and a list.
+const sample = `═══════════════════════════════════════════════════════════
+TOPIC: STATUS CODES
+═══════════════════════════════════════════════════════════
+
+--- Example 1: By Status ---
+  200 │████████████████▌ 1412
+  404 │█▏                  87
+  ✓ 2 codes, 1499 requests
+  p50 ▁▂▅▇█▅▂▁ ms
+
+  ┌────────┬──────┐
+  │ status │ hits │
+  ├────────┼──────┤
+  │ 200    │ 1412 │
+  └────────┴──────┘
+  📌 What to check next:
+    • the 404s → which paths?
+    • the 🎉 release at 12:00
+`
+
+func demo() error {
+	fmt.Println("--- Example 1: What a Screen Reader Hears ---")
+	first := strings.SplitN(sample, "\n", 2)[0]
+	words, text := spoken(first)
+	fmt.Printf("  The banner alone is %d words:\n    %.110s...\n", words, text)
+	words, text = spoken("  200 │████████████████▌ 1412")
+	fmt.Printf("  One bar is %d: %.90s...\n", words, text)
+	var plain bytes.Buffer
+	if err := toolPlain(strings.NewReader(sample), &plain); err != nil {
+		return err
+	}
+	before, _ := spoken(sample)
+	after, _ := spoken(plain.String())
+	check(after*3 < before, fmt.Sprintf("the whole sample: %d words as drawn, %d in accessible mode", before, after),
+		fmt.Sprintf("%d words before, %d after: not much of a saving", before, after))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: The Same Output, Accessible ---")
+	for _, l := range strings.Split(strings.TrimRight(plain.String(), "\n"), "\n") {
+		fmt.Println("  |", l)
+	}
+	decorated := strings.IndexFunc(plain.String(), func(r rune) bool {
+		return r > unicode.MaxLatin1 && (r < 0x2010 || r > 0x2027) // Left: dashes, quotes, "…"
+	})
+	check(decorated < 0, "no box drawing, blocks, marks or emoji left", fmt.Sprintf("a symbol survived at byte %d", decorated))
+	fmt.Println("  The bullets are numbered, the table is rows of \"a; b\", and each bar")
+	fmt.Println("  is its length in cells, eighths as decimals.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: One Line at a Time ---")
+	for _, in := range []string{
+		"✓ verify 153: ok",
+		"✗ exit status 1",
+		"⚠️ named .jpg but content is unknown",
+		"gotut: verify: pas de leçon 42 → exit 2",
+		"disk ███░░ 60%",
+		"the 🚀 release at 12:00",
+	} {
+		fmt.Printf("  %-42s → %s\n", in, a11y.Line(in))
+	}
+	fmt.Println("  A mark that opens a line labels it (\"Pass:\"); anywhere else it is a word.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Real Lesson Through a Runner ---")
+	if _, err := os.Stat("165_ascii_charts.go"); err != nil {
+		fmt.Println("  (run this from go_projects to see 165 through the filter)")
+	} else {
+		cmd := exec.Command("go", "run", "165_ascii_charts.go")
+		cmd.Env = append(os.Environ(), "COLUMNS=60")
+		var raw, acc bytes.Buffer
+		w := a11y.NewWriter(&acc)
+		cmd.Stdout = io.MultiWriter(&raw, w) // What 172 and 176 do, with a copy to compare
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("165: %w", err)
+		}
+		w.Flush()
+		rawWords, _ := spoken(raw.String())
+		accWords, _ := spoken(acc.String())
+		fmt.Printf("  165_ascii_charts.go: %d lines, about %d spoken words → %d lines, %d words\n",
+			strings.Count(raw.String(), "\n"), rawWords, strings.Count(acc.String(), "\n"), accWords)
+		for _, l := range strings.Split(acc.String(), "\n") {
+			if strings.Contains(l, "sparkline") || strings.HasPrefix(l, "  200") {
+				fmt.Println("  |", l)
+			}
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Turning It On ---")
+	dir, err := os.MkdirTemp("", "a11y-config-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	defer os.Setenv("GOTUT_CONFIG_DIR", os.Getenv("GOTUT_CONFIG_DIR"))
+	defer os.Setenv("GOTUT_ACCESSIBLE", os.Getenv("GOTUT_ACCESSIBLE"))
+	os.Setenv("GOTUT_CONFIG_DIR", dir)
+	config := filepath.Join(dir, "config.json")
+	for _, tc := range []struct {
+		label  string
+		flag   bool
+		env    string
+		config string
+		want   bool
+	}{
+		{"nothing set", false, "", "", false},
+		{`config.json {"accessible": true}`, false, "", `{"accessible": true}`, true},
+		{"…but GOTUT_ACCESSIBLE=0", false, "0", `{"accessible": true}`, false},
+		{"…but -accessible", true, "0", `{"accessible": true}`, true},
+	} {
+		os.Setenv("GOTUT_ACCESSIBLE", tc.env)
+		os.Remove(config)
+		if tc.config != "" {
+			if err := os.WriteFile(config, []byte(tc.config), 0o644); err != nil {
+				return err
+			}
+		}
+		on, err := a11y.Enabled(tc.flag)
+		check(err == nil && on == tc.want, fmt.Sprintf("%-34s → %v", tc.label, on),
+			fmt.Sprintf("%s → %v, %v; want %v", tc.label, on, err, tc.want))
+	}
+	os.WriteFile(config, []byte(`{"accessible": tru`), 0o644)
+	os.Setenv("GOTUT_ACCESSIBLE", "")
+	_, err = a11y.Enabled(false)
+	check(err != nil, "a config.json that doesn't parse is an error, not a silent \"off\"",
+		"a broken config.json was ignored")
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "plain" {
+		if err := toolPlain(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "plain:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: ACCESSIBLE OUTPUT — LESSONS A SCREEN READER CAN READ")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A screen reader names every symbol: decoration becomes noise.
+2. Keep the information, drop the drawing: bars become numbers, marks words.
+3. Number lists, restarting for nested ones, so a listener knows where they are.
+4. Put the rewrite where all output passes, the runner, not in 200 lessons.
+5. Flag, then environment, then config file; a broken config is an error.
+6. Leave machine-readable output alone.
+	`)
+}
@@ -0,0 +1,689 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+TOPIC: A LESSON LINTER — CHECKING EVERY TOPIC HAS THE SAME PARTS
+
+CONCEPT:
+Every tool that reads this course assumes a lesson has the same shape:
+171 picks sections by their "--- Example N ---" markers, 172 times them,
+176 streams them, 169 drills the reference tables, and a reader expects
+a summary up top and the takeaways at the end. Nothing enforced it. With
+two hundred lessons written over years, some are missing a part, and
+nobody finds out until a tool shows an empty section.
+
+A STRUCTURAL linter reads each lesson with go/parser (no build, no run)
+and checks four rules, the course's schema:
+
+    summary         a doc comment before the code: 186's "TOPIC: ...
+                    CONCEPT:" block, or intermediate_topics' "// ===="
+                    header. At least three lines; a title alone isn't one.
+    takeaways       KEY TAKEAWAYS (or ✅ KEY TAKEAWAY) in a string the
+                    lesson PRINTS. Takeaways only in a comment are
+                    never seen by anyone running it.
+    live            a func main that runs at least one section: a
+                    "--- Example N: ... ---" marker, the older
+                    "=== EXAMPLE N ===", or a []section registry (171).
+    reference card  a QUICK REFERENCE (or cheat sheet) table in the file,
+                    or a 169 flashcard deck for the topic.
+
+Violations come out as a checklist, one block per lesson, ready to paste
+into an issue:
+
+    ## 124_worker_pools.go
+    - [x] summary: TOPIC: WORKER POOLS
+    - [x] takeaways
+    - [x] live: 4 sections
+    - [ ] reference card: no QUICK REFERENCE table and no 169 deck
+
+A GROWING CORPUS. The rules would fail most old lessons on day one, so
+CI can't just demand zero. -baseline FILE lists the violations already
+known (-update-baseline writes it); only NEW ones fail the run. Fixing an
+old lesson shrinks the file. The exit status is 175's contract: 0 clean,
+2 usage, 3 a lesson broke the schema.
+
+RUN:
+    go run 191_lesson_lint.go                                  (demo)
+    go run 191_lesson_lint.go tool lint .                       (checklist)
+    go run 191_lesson_lint.go tool lint -all 186 187 ../intermediate_topics/86_file_paths.go
+    go run 191_lesson_lint.go tool lint -json ../intermediate_topics
+    go run 191_lesson_lint.go tool lint -baseline lint.baseline -update-baseline .
+*/
+
+// ---------------------------------------------------------
+// Part 1: A Lesson, Parsed
+// ---------------------------------------------------------
+
+// Lesson is one topic: a NNN_name.go file, or the .go files of a
+// NNN_name/ package directory.
+type Lesson struct {
+	Name     string // 186_hexdump.go, or 174_fileops/
+	Topic    int
+	Fset     *token.FileSet
+	Files    []*ast.File
+	ParseErr error
+	HasDeck  bool // 169 has flashcards for this topic
+}
+
+var lessonName = regexp.MustCompile(`^(\d+)_\w+`)
+
+// loadLesson parses path, keeping comments. A file that doesn't parse is
+// still a lesson; the rules report it.
+func loadLesson(path string, decks map[int]bool) (*Lesson, error) {
+	base := filepath.Base(path)
+	m := lessonName.FindStringSubmatch(base)
+	if m == nil {
+		return nil, fmt.Errorf("%s: not a lesson (want NNN_name.go or NNN_name/)", path)
+	}
+	topic, _ := strconv.Atoi(m[1])
+	l := &Lesson{Name: base, Topic: topic, Fset: token.NewFileSet(), HasDeck: decks[topic]}
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		l.Name += "/"
+		files, _ = filepath.Glob(filepath.Join(path, "*.go"))
+		files = slices.DeleteFunc(files, func(f string) bool { return strings.HasSuffix(f, "_test.go") })
+		if len(files) == 0 {
+			return nil, nil // 178_templates/, 148_migrations/: a lesson's data, not a lesson
+		}
+	}
+	for _, f := range files {
+		file, err := parser.ParseFile(l.Fset, f, nil, parser.ParseComments)
+		if err != nil {
+			l.ParseErr = err
+			return l, nil
+		}
+		l.Files = append(l.Files, file)
+	}
+	return l, nil
+}
+
+// strings returns every string literal in the lesson, unquoted.
+func (l *Lesson) strings() []string {
+	var out []string
+	for _, f := range l.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if s, err := strconv.Unquote(lit.Value); err == nil {
+					out = append(out, s)
+				}
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// comments returns the text of every comment in the lesson.
+func (l *Lesson) comments() []string {
+	var out []string
+	for _, f := range l.Files {
+		for _, cg := range f.Comments {
+			out = append(out, cg.Text())
+		}
+	}
+	return out
+}
+
+// ---------------------------------------------------------
+// Part 2: The Rules
+// ---------------------------------------------------------
+
+// Rule checks one part of the schema. Check returns whether the lesson
+// has it, and a detail either way: what was found, or what is missing.
+type Rule struct {
+	Name  string
+	Check func(*Lesson) (bool, string)
+}
+
+var rules = []Rule{
+	{"summary", checkSummary},
+	{"takeaways", checkTakeaways},
+	{"live", checkLive},
+	{"reference card", checkReference},
+}
+
+// checkSummary wants a comment of three or more lines before the first
+// function, in any of the lesson's files.
+func checkSummary(l *Lesson) (bool, string) {
+	for _, f := range l.Files {
+		first := token.Pos(-1)
+		for _, d := range f.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok {
+				first = fn.Pos()
+				break
+			}
+		}
+		for _, cg := range f.Comments {
+			if first >= 0 && cg.Pos() > first {
+				break
+			}
+			if lines := textLines(cg.Text()); len(lines) >= 3 {
+				return true, lines[0]
+			}
+		}
+	}
+	return false, "no doc comment of three or more lines before the code"
+}
+
+// textLines returns the lines of a comment that say something: not
+// blank, not only a rule of ═ or =.
+func textLines(text string) []string {
+	var out []string
+	for line := range strings.Lines(text) {
+		line = strings.TrimSpace(line)
+		if strings.Trim(line, "═=─-*/ ") != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+var takeawayRx = regexp.MustCompile(`(?i)\bkey\s+takeaways?\b`)
+
+func checkTakeaways(l *Lesson) (bool, string) {
+	for _, s := range l.strings() {
+		if takeawayRx.MatchString(s) {
+			return true, ""
+		}
+	}
+	for _, c := range l.comments() {
+		if takeawayRx.MatchString(c) {
+			return false, "only in a comment: running the lesson never shows them"
+		}
+	}
+	return false, "no KEY TAKEAWAYS printed"
+}
+
+// markerRx matches the section markers 171, 172 and 176 look for.
+var markerRx = regexp.MustCompile(`(?m)^\s*(?:---\s*Example\s+\d+|===\s*EXAMPLE\s+\d+|EXAMPLE\s+\d+:)`)
+
+func checkLive(l *Lesson) (bool, string) {
+	var main *ast.FuncDecl
+	registry := 0
+	for _, f := range l.Files {
+		for _, d := range f.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok && fn.Name.Name == "main" && fn.Recv == nil {
+				main = fn
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			if at, ok := lit.Type.(*ast.ArrayType); ok {
+				if id, ok := at.Elt.(*ast.Ident); ok && id.Name == "section" {
+					registry += len(lit.Elts)
+				}
+			}
+			return true
+		})
+	}
+	if main == nil || len(main.Body.List) == 0 {
+		return false, "no func main to run"
+	}
+	markers := 0
+	for _, s := range l.strings() {
+		markers += len(markerRx.FindAllString(s, -1))
+	}
+	switch n := max(markers, registry); n {
+	case 0:
+		return false, `main runs, but shows no section: no "--- Example N: ... ---" marker or []section registry`
+	case 1:
+		return true, "1 section"
+	default:
+		return true, fmt.Sprintf("%d sections", n)
+	}
+}
+
+var referenceRx = regexp.MustCompile(`(?i)quick\s+reference|reference\s+(?:table|card)|cheat\s*sheet`)
+
+func checkReference(l *Lesson) (bool, string) {
+	for _, s := range append(l.comments(), l.strings()...) {
+		if m := referenceRx.FindString(s); m != "" {
+			return true, strings.ToUpper(m)
+		}
+	}
+	if l.HasDeck {
+		return true, fmt.Sprintf("169 deck for topic %d", l.Topic)
+	}
+	return false, "no QUICK REFERENCE table and no 169 deck"
+}
+
+// decksIn reads the topic numbers of 169's flashcard decks: the keys of
+// its decks map, found with go/ast rather than kept in a second list.
+func decksIn(path string) (map[int]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	decks := map[int]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != "decks" || len(spec.Values) != 1 {
+			return true
+		}
+		if lit, ok := spec.Values[0].(*ast.CompositeLit); ok {
+			for _, e := range lit.Elts {
+				if kv, ok := e.(*ast.KeyValueExpr); ok {
+					if k, ok := kv.Key.(*ast.BasicLit); ok {
+						n, _ := strconv.Atoi(k.Value)
+						decks[n] = true
+					}
+				}
+			}
+		}
+		return false
+	})
+	return decks, nil
+}
+
+// ---------------------------------------------------------
+// Part 3: Running the Rules
+// ---------------------------------------------------------
+
+// Result is one rule on one lesson.
+type Result struct {
+	Rule   string `json:"rule"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Known  bool   `json:"known,omitempty"` // In the baseline: an old violation
+}
+
+// Report is every rule's result for one lesson.
+type Report struct {
+	Lesson  string   `json:"lesson"`
+	Results []Result `json:"results"`
+}
+
+// Lint runs every rule. A lesson that doesn't parse fails them all, with
+// the parse error as the detail: the rules can't see into it.
+func Lint(l *Lesson) Report {
+	r := Report{Lesson: l.Name}
+	for _, rule := range rules {
+		res := Result{Rule: rule.Name}
+		if l.ParseErr != nil {
+			res.Detail = "does not parse: " + firstLine(l.ParseErr.Error())
+		} else {
+			res.OK, res.Detail = rule.Check(l)
+		}
+		r.Results = append(r.Results, res)
+	}
+	return r
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// Failed reports whether any rule failed that the baseline doesn't know.
+func (r Report) Failed() bool {
+	return slices.ContainsFunc(r.Results, func(res Result) bool { return !res.OK && !res.Known })
+}
+
+// expand turns arguments into lesson paths: a directory of lessons (its
+// NNN_* entries), a lesson file or package, or a bare topic number
+// looked up here.
+func expand(args []string) ([]string, error) {
+	var out []string
+	for _, arg := range args {
+		if _, err := strconv.Atoi(arg); err == nil {
+			m, _ := filepath.Glob(fmt.Sprintf("%03s_*", arg))
+			if len(m) == 0 {
+				return nil, fmt.Errorf("no lesson %s here", arg)
+			}
+			out = append(out, m[0])
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() && !lessonName.MatchString(filepath.Base(arg)) {
+			entries, err := os.ReadDir(arg)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				if lessonName.MatchString(e.Name()) && (e.IsDir() || filepath.Ext(e.Name()) == ".go") {
+					out = append(out, filepath.Join(arg, e.Name()))
+				}
+			}
+			continue
+		}
+		out = append(out, arg)
+	}
+	slices.SortStableFunc(out, func(a, b string) int {
+		return topicOf(a) - topicOf(b)
+	})
+	return out, nil
+}
+
+func topicOf(path string) int {
+	m := lessonName.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// ---------------------------------------------------------
+// Part 4: The Baseline
+// ---------------------------------------------------------
+// One known violation per line, "lesson<TAB>rule", sorted, so the diff
+// of a fix is one deleted line.
+
+func readBaseline(path string) (map[string]bool, error) {
+	known := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			known[line] = true
+		}
+	}
+	return known, sc.Err()
+}
+
+func writeBaseline(path string, reports []Report) error {
+	var lines []string
+	for _, r := range reports {
+		for _, res := range r.Results {
+			if !res.OK {
+				lines = append(lines, r.Lesson+"\t"+res.Rule)
+			}
+		}
+	}
+	slices.Sort(lines)
+	content := "# Known lesson-schema violations (191_lesson_lint.go). Fix one, delete its line.\n" +
+		strings.Join(lines, "\n") + "\n"
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ---------------------------------------------------------
+// Part 5: The Checklist and the Tool
+// ---------------------------------------------------------
+
+const (
+	exitOK     = 0
+	exitUsage  = 2
+	exitSchema = 3 // 175's "verification failure"
+)
+
+// writeChecklist prints one block per lesson with a failure (every
+// lesson with all), then a line of totals.
+func writeChecklist(w io.Writer, reports []Report, all bool) {
+	failing, known := 0, 0
+	perRule := map[string]int{}
+	for _, r := range reports {
+		bad := slices.ContainsFunc(r.Results, func(res Result) bool { return !res.OK })
+		if r.Failed() {
+			failing++
+		} else if bad {
+			known++
+		}
+		if !bad && !all {
+			continue
+		}
+		fmt.Fprintf(w, "## %s\n", r.Lesson)
+		for _, res := range r.Results {
+			box, note := "[x]", ""
+			if !res.OK {
+				box = "[ ]"
+				perRule[res.Rule]++
+				if res.Known {
+					note = " (baseline)"
+				}
+			}
+			detail := ""
+			if res.Detail != "" {
+				detail = ": " + res.Detail
+			}
+			fmt.Fprintf(w, "- %s %s%s%s\n", box, res.Rule, detail, note)
+		}
+		fmt.Fprintln(w)
+	}
+	var totals []string
+	for _, rule := range rules {
+		totals = append(totals, fmt.Sprintf("%s %d", rule.Name, perRule[rule.Name]))
+	}
+	fmt.Fprintf(w, "%d lessons: %d with new violations, %d with only baseline ones (missing: %s)\n",
+		len(reports), failing, known, strings.Join(totals, ", "))
+}
+
+func toolLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	all := fs.Bool("all", false, "list passing lessons too")
+	asJSON := fs.Bool("json", false, "one JSON report per lesson, per line")
+	baseline := fs.String("baseline", "", "file of known violations; only new ones fail")
+	update := fs.Bool("update-baseline", false, "rewrite -baseline with the current violations")
+	decksPath := fs.String("decks", "169_flashcards.go", "the flashcards lesson whose decks count as reference cards")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *update && *baseline == "" {
+		fmt.Fprintln(stderr, "lint: -update-baseline needs -baseline FILE")
+		return exitUsage
+	}
+	targets := fs.Args()
+	if len(targets) == 0 {
+		targets = []string{"."}
+	}
+	paths, err := expand(targets)
+	if err != nil {
+		fmt.Fprintln(stderr, "lint:", err)
+		return exitUsage
+	}
+	decks, err := decksIn(*decksPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "lint: no decks (%v); only tables count as reference cards\n", err)
+		decks = map[int]bool{}
+	}
+	known := map[string]bool{}
+	if *baseline != "" && !*update {
+		if known, err = readBaseline(*baseline); err != nil {
+			fmt.Fprintln(stderr, "lint:", err)
+			return exitUsage
+		}
+	}
+
+	var reports []Report
+	for _, p := range paths {
+		l, err := loadLesson(p, decks)
+		if err != nil {
+			fmt.Fprintln(stderr, "lint:", err)
+			return exitUsage
+		}
+		if l == nil {
+			continue
+		}
+		r := Lint(l)
+		for i, res := range r.Results {
+			r.Results[i].Known = !res.OK && known[r.Lesson+"\t"+res.Rule]
+		}
+		reports = append(reports, r)
+	}
+	if *update {
+		if err := writeBaseline(*baseline, reports); err != nil {
+			fmt.Fprintln(stderr, "lint:", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", *baseline)
+		return exitOK
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		for _, r := range reports {
+			enc.Encode(r)
+		}
+	} else {
+		writeChecklist(stdout, reports, *all)
+	}
+	if slices.ContainsFunc(reports, Report.Failed) {
+		return exitSchema
+	}
+	return exitOK
+}
+
+// ---------------------------------------------------------
+// Part 6: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// course is a small course with one lesson per way to break a rule.
+var course = map[string]string{
+	"001_complete.go": "package main\n\nimport \"fmt\"\n\n/*\nTOPIC: COMPLETE\n\nCONCEPT:\nHas every part.\n\n" +
+		"QUICK REFERENCE:\n    fmt.Println    print a line\n*/\n\n" +
+		"func main() {\n\tfmt.Println(\"--- Example 1: Hello ---\")\n\tfmt.Println(\"KEY TAKEAWAYS\")\n}\n",
+	"002_no_summary.go": "package main\n\nimport \"fmt\"\n\n// TOPIC: TITLE ONLY\n\n" +
+		"func main() {\n\tfmt.Println(\"--- Example 1: Hello ---\")\n\tfmt.Println(\"KEY TAKEAWAYS\")\n\t// QUICK REFERENCE: none\n}\n",
+	"003_silent_takeaways.go": "package main\n\nimport \"fmt\"\n\n/*\nTOPIC: QUIET\nCONCEPT:\nKEY TAKEAWAYS are only down here.\nCHEAT SHEET: fmt\n*/\n\n" +
+		"func main() { fmt.Println(\"--- Example 1: Hello ---\") }\n",
+	"004_no_sections.go": "package main\n\nimport \"fmt\"\n\n/*\nTOPIC: ONE BLOB\nCONCEPT:\nAll in main.\nQUICK REFERENCE: fmt\n*/\n\n" +
+		"func main() {\n\tfmt.Println(\"hello\")\n\tfmt.Println(\"KEY TAKEAWAYS\")\n}\n",
+	"005_broken.go": "/*\nTOPIC: BROKEN\n*/\npackage main\n\nfunc main() {\n",
+	"071_verbs.go": "package main\n\nimport \"fmt\"\n\n// ====\n// Topic 71: Verbs\n// %v and friends.\n// Verbs for every type.\n// ====\n\n" +
+		"func main() {\n\tfmt.Println(\"EXAMPLE 1: %v\")\n\tfmt.Println(\"✅ KEY TAKEAWAY: use %v\")\n}\n",
+	"178_templates/page.tmpl": "{{.}}\n",
+}
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "lesson-lint-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	for name, src := range course {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	decks := filepath.Join(dir, "tools", "169_flashcards.go") // Outside the course: not linted
+	os.MkdirAll(filepath.Dir(decks), 0o755)
+	if err := os.WriteFile(decks, []byte("package main\n\nvar decks = map[int][]Card{\n\t71: {{\"verb\", \"%v\"}},\n}\n"), 0o644); err != nil {
+		return err
+	}
+	lint := func(args ...string) (int, string) {
+		var out, errs strings.Builder
+		code := toolLint(append([]string{"-decks", decks}, args...), &out, &errs)
+		return code, strings.ReplaceAll(out.String()+errs.String(), dir+string(filepath.Separator), "")
+	}
+
+	fmt.Println("--- Example 1: One Broken Rule per Lesson ---")
+	code, out := lint("-all", dir)
+	for _, l := range strings.Split(strings.TrimSpace(out), "\n") {
+		if l != "" {
+			fmt.Println("  |", l)
+		}
+	}
+	check(code == exitSchema, fmt.Sprintf("exit %d: the schema is broken, 175's code 3", code),
+		fmt.Sprintf("exit %d, want %d", code, exitSchema))
+	fmt.Println("  071 has no table, but 169 drills its verbs: the deck counts as its card.")
+	fmt.Println("  178_templates/ holds no Go: data for a lesson, not a lesson.")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: A Baseline for a Corpus That Already Exists ---")
+	baseline := filepath.Join(dir, "lint.baseline")
+	lint("-baseline", baseline, "-update-baseline", dir)
+	data, _ := os.ReadFile(baseline)
+	fmt.Printf("  -update-baseline wrote %d known violations\n", strings.Count(string(data), "\n")-1)
+	code, _ = lint("-baseline", baseline, dir)
+	check(code == exitOK, "the same corpus against its baseline: exit 0", fmt.Sprintf("exit %d", code))
+	newLesson := filepath.Join(dir, "006_new.go")
+	os.WriteFile(newLesson, []byte(course["004_no_sections.go"]), 0o644)
+	code, out = lint("-baseline", baseline, dir)
+	check(code == exitSchema && strings.Contains(out, "## 006_new.go\n- [x] summary: TOPIC: ONE BLOB\n- [x] takeaways\n- [ ] live: main runs, but shows no section: no \"--- Example N: ... ---\" marker or []section registry\n"),
+		"a NEW lesson without sections fails, though 004 with the same gap is known",
+		fmt.Sprintf("exit %d:\n%s", code, out))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The Real Course ---")
+	for _, target := range []string{".", "../intermediate_topics"} {
+		if _, err := os.Stat(target); err != nil {
+			continue
+		}
+		var out strings.Builder
+		code := toolLint([]string{target}, &out, io.Discard)
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		fmt.Printf("  %-24s exit %d  %s\n", target, code, lines[len(lines)-1])
+	}
+	fmt.Println("  Most gaps are reference cards: lessons written before 169 existed.")
+	fmt.Println("  Run \"tool lint .\" for the checklist; fix one and update the baseline.")
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "lint" {
+			os.Exit(toolLint(os.Args[3:], os.Stdout, os.Stderr))
+		}
+		fmt.Fprintf(os.Stderr, "unknown command %q (want: tool lint)\n", strings.Join(os.Args[1:], " "))
+		os.Exit(exitUsage)
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: A LESSON LINTER — CHECKING EVERY TOPIC HAS THE SAME PARTS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Write the schema the tools assume down as rules, and check it in code.
+2. Lint structure from the AST: no build, no run, and broken files still report.
+3. Report a checklist a person can act on, and JSON for the next tool.
+4. A baseline lets an old corpus adopt a rule: only new violations fail.
+5. Exit codes follow the contract, so CI can tell "schema" from "usage".
+	`)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/kata"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+)
+
+/*
+TOPIC: KATAS — TIMED CHALLENGES GRADED BY HIDDEN TESTS
+
+CONCEPT:
+A topic's exercise (175's "gotut test 5") is the lesson's own package:
+the tests sit next to the code, and reading them is half the lesson. A
+KATA is the other kind of practice. It is a short problem, solved from a
+one-line prompt against the clock, and graded by tests you don't get to
+read first, as in an interview:
+
+    gotut kata                      trimprefixfold  10m0s  TrimPrefixFold
+                                    parseduration   20m0s  Parse a duration string
+                                    dedupe          5m0s   Dedupe a slice
+    gotut kata dedupe               writes ./dedupe/kata.go; the clock starts
+    gotut kata -grade dedupe        ✓ TestKeepsFirstInOrder
+                                    ✗ TestInputUnchanged: Dedupe changed its input to [1 2 3 2 3]
+                                    dedupe: 3/4 hidden tests pass (2m10s of 5m0s): fail
+
+HOW THE TESTS STAY HIDDEN. pkg/kata embeds three files per kata with
+//go:embed: starter.go.txt, hidden_test.go.txt and solution.go.txt. The
+.txt keeps the go tool from compiling them into the package. Start copies
+only the starter into your work directory. Grade copies your kata.go and
+the hidden tests into a scratch directory, runs "go test -json" there,
+and reads test2json's events: a pass or fail per test, the first line
+each failure printed, and "build-output" if your file didn't compile.
+Nothing is ever secret (the files are in the repo); they just aren't in
+front of you.
+
+THE CLOCK. "started" goes into the progress log (pkg/progress:
+progress.json next to 169's cards.json). A grade's time is now minus that
+start, and its verdict is one of:
+    pass    every test, within the limit
+    late    every test, after it: exit 0, it works, but the log remembers
+    fail    anything else: exit 4, 175's "exercise failure"
+Starting again doesn't restart the clock; deleting the directory does.
+
+A KATA MUST BE SOLVABLE. pkg/kata's own test grades every reference
+solution (all tests pass) and every starter (it compiles and fails one),
+so a kata with a typo in a hidden test never ships.
+
+RUN:
+    go run 192_katas.go
+    cd gotut && go build -o gotut . && ./gotut kata
+    go test ./pkg/kata ./pkg/progress
+*/
+
+// ---------------------------------------------------------
+// Part 1: Attempts
+// ---------------------------------------------------------
+// What a learner might hand in for trimprefixfold. The first is the
+// answer everyone writes first; the Kelvin sign "K" (three bytes, folds
+// to "k") is why it's wrong.
+
+const byteSlicing = `package kata
+
+import "strings"
+
+func TrimPrefixFold(s, prefix string) string {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):]
+	}
+	return s
+}
+`
+
+const typo = `package kata
+
+func TrimPrefixFold(s, prefix string) string {
+	return s[len(prefx):]
+}
+`
+
+const endless = `package kata
+
+func Dedupe[T comparable](xs []T) []T {
+	for len(xs) > 0 {
+	}
+	return xs
+}
+`
+
+// ---------------------------------------------------------
+// Part 2: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// show prints a graded result the way gotut kata -grade does.
+func show(res kata.Result) {
+	if res.Build != "" {
+		fmt.Println("    does not compile:", res.Build)
+		return
+	}
+	for _, t := range res.Tests {
+		if t.Pass {
+			fmt.Printf("    ✓ %s\n", t.Name)
+		} else {
+			fmt.Printf("    ✗ %s: %s\n", t.Name, t.Message)
+		}
+	}
+}
+
+func demo() error {
+	ctx := context.Background()
+	work, err := os.MkdirTemp("", "katas-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(work)
+
+	fmt.Println("--- Example 1: The Katas ---")
+	for _, k := range kata.All() {
+		fmt.Printf("  %-15s %-6v %s\n", k.Name, k.Limit, k.Prompt)
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Starting One ---")
+	k, _ := kata.Get("trimprefixfold")
+	path, err := k.Start(work)
+	if err != nil {
+		return err
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	check(len(entries) == 1, fmt.Sprintf("Start wrote %s and nothing else: the tests stay in the binary",
+		strings.TrimPrefix(path, work+string(filepath.Separator))), fmt.Sprintf("%d files written", len(entries)))
+	os.WriteFile(path, []byte(byteSlicing), 0o644)
+	_, err = k.Start(work)
+	check(errors.Is(err, os.ErrExist), "a second Start keeps the learner's file (os.ErrExist), O_EXCL does the checking",
+		fmt.Sprintf("second Start: %v", err))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Grading the Starter ---")
+	res, err := k.Grade(ctx, k.Starter())
+	if err != nil {
+		return err
+	}
+	show(res)
+	check(!res.OK() && res.Passed() > 0, fmt.Sprintf("%d of %d: the starter's \"return s\" gets the no-match cases for free",
+		res.Passed(), len(res.Tests)), "the starter should fail some tests and pass others")
+	fmt.Println("  The messages come from t.Errorf; the file and line are cut, since you")
+	fmt.Println("  don't have the file.")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: The Answer Everyone Writes First ---")
+	src, _ := os.ReadFile(path)
+	res, err = k.Grade(ctx, src)
+	if err != nil {
+		return err
+	}
+	show(res)
+	fmt.Println("  s[:len(prefix)] counts bytes; folding can change a letter's byte length.")
+	res, err = k.Grade(ctx, []byte(typo))
+	if err != nil {
+		return err
+	}
+	check(res.Build != "" && len(res.Tests) == 0, "a compile error is a result too: "+firstLine(res.Build),
+		"a typo compiled?")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: The Clock and the Progress Log ---")
+	log := filepath.Join(work, "progress.json") // gotut uses progress.Path()
+	start := time.Now().Add(-12 * time.Minute)
+	progress.Record(log, progress.Event{Time: start, Kind: "kata", Item: k.Name, Result: "started"})
+	res, err = k.Grade(ctx, k.Solution())
+	if err != nil {
+		return err
+	}
+	for _, elapsed := range []time.Duration{8 * time.Minute, time.Since(start)} {
+		fmt.Printf("  all %d pass after %-8v → %s\n", len(res.Tests), elapsed.Round(time.Minute), k.Verdict(res, elapsed))
+	}
+	progress.Record(log, progress.Event{Kind: "kata", Item: k.Name, Result: k.Verdict(res, time.Since(start)),
+		Detail: fmt.Sprintf("%d/%d", res.Passed(), len(res.Tests)), Seconds: time.Since(start).Seconds()})
+	l, err := progress.Load(log)
+	if err != nil {
+		return err
+	}
+	last, _ := l.Last("kata", k.Name)
+	check(last.Result == "late", fmt.Sprintf("progress.json: %d events, the last %q %s: it works, over the %v limit",
+		len(l.Events), last.Result, last.Detail, k.Limit), fmt.Sprintf("last event %+v", last))
+	fmt.Println()
+
+	fmt.Println("--- Example 6: An Endless Loop ---")
+	d, _ := kata.Get("dedupe")
+	tctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	began := time.Now()
+	_, err = d.Grade(tctx, []byte(endless))
+	check(errors.Is(err, context.DeadlineExceeded),
+		fmt.Sprintf("Grade gave up after %v: context.DeadlineExceeded, gotut's timeout (exit 1)", time.Since(began).Round(100*time.Millisecond)),
+		fmt.Sprintf("Grade returned %v", err))
+	fmt.Println("  The deadline covers compiling too: gotut's -timeout is 30s, not the kata's limit.")
+	return nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: KATAS — TIMED CHALLENGES GRADED BY HIDDEN TESTS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Embed the starter, the tests and a solution; copy out only the starter.
+2. Grade in a scratch directory, and read go test -json, not its text.
+3. A failing test is a result; go failing or a deadline is an error.
+4. Record starts and grades in one append-only log: reports come from it.
+5. Test the kata itself: the solution passes, the starter doesn't.
+	`)
+}
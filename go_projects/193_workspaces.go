@@ -0,0 +1,448 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*
+TOPIC: MULTI-MODULE WORKSPACES — go.work AND internal/ VISIBILITY
+
+CONCEPT:
+One go.mod is one MODULE: a set of packages versioned and released
+together. A course that grows tools, a web front end and shared packages
+ends up wanting several, each with its own dependencies and its own
+release:
+
+    course/
+      go.work              use ./core ./lessons ./tools ./web
+      core/go.mod          gotut.example/core      registry, render, internal/
+      lessons/go.mod       gotut.example/lessons   lessons register with core
+      tools/go.mod         gotut.example/tools     the runner
+      web/go.mod           gotut.example/web       handlers, more lessons
+
+Each module requires the others at a version ("require gotut.example/core
+v0.0.0"), and no such version exists until core is tagged and published.
+go.work (Go 1.18) says: while I develop here, build these modules from
+these directories. It is a LOCAL file: commit it for a monorepo's own
+tooling, but a module someone downloads never sees it, so each go.mod
+must stand on its own once released.
+
+    go work init ./core ./lessons    write go.work
+    go work use ./tools              add a module to it
+    go list -m                       every module in the workspace
+    GOWORK=off go build ./...        pretend there is no go.work
+
+internal/ IS ABOUT IMPORT PATHS, NOT MODULES. A package under
+.../core/internal/ can be imported only by packages whose path starts
+with .../core/. A workspace doesn't loosen that: web sits next to core
+on disk, but gotut.example/web/... doesn't start with gotut.example/core,
+so "use of internal package ... not allowed". Splitting a module in two
+can turn a legal internal import into an illegal one; that is often the
+first thing a split finds.
+
+DISCOVERY ACROSS MODULES. A runner can't glob for lessons once they live
+in several modules — and a binary doesn't have the source anyway. The
+core module owns a REGISTRY (Topic 158 generated one; 180 compared eager
+and lazy ones): each lesson package calls registry.Register from init,
+and the runner blank-imports every lesson package it should know. Which
+lessons exist is decided by the imports, checked by the compiler. The
+demo's registry is ten lines; pkg/registry is the hardened one: numbers,
+slugs and aliases are one namespace, a clash panics at init naming both
+Register calls, and it is safe to use from parallel tests.
+
+THIS TREE is the layout above. The repository root has the go.work, and
+it uses four modules: the lessons (the root module itself, every
+directory of NNN_ files), core (go_projects/pkg, the shared packages and
+pkg/registry), tools (go_projects/gotut) and web (go_projects/web). A
+lesson imports "github.com/akarsh323/Go-tutorials-/go_projects/pkg/kata"
+like any module's package, and go.work points that path at the
+directory. gotut reads go.work and looks for NNN_ lessons in every
+module it uses; gotut help topics and the web front end list them
+through pkg/course.Topics, a pkg/registry table.
+
+RUN:
+    go run 193_workspaces.go
+    cd pkg && go test ./...          (the core module, through ../../go.work)
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Workspace
+// ---------------------------------------------------------
+// Four modules. core owns the registry and an internal/ helper; lessons
+// and web each register lessons; tools is the runner that imports them.
+
+var workspace = map[string]string{
+	"go.work": `go 1.22
+
+use (
+	./core
+	./lessons
+	./tools
+	./web
+)
+`,
+	"core/go.mod": "module gotut.example/core\n\ngo 1.22\n",
+	"core/registry/registry.go": `// Package registry is where every module's lessons sign in.
+package registry
+
+import (
+	"io"
+	"sort"
+)
+
+type Lesson struct {
+	Num    int
+	Title  string
+	Module string
+	Run    func(w io.Writer)
+}
+
+var lessons []Lesson
+
+// Register is called from each lesson package's init.
+func Register(l Lesson) { lessons = append(lessons, l) }
+
+// All returns every registered lesson, by number.
+func All() []Lesson {
+	out := append([]Lesson(nil), lessons...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Num < out[j].Num })
+	return out
+}
+`,
+	"core/internal/textutil/textutil.go": `// Package textutil is core's own: only gotut.example/core/... may import it.
+package textutil
+
+import "strings"
+
+func Banner(s string) string { return "== " + strings.ToUpper(s) + " ==" }
+`,
+	"core/render/render.go": `package render
+
+import (
+	"fmt"
+
+	"gotut.example/core/internal/textutil" // Allowed: render is under core/
+	"gotut.example/core/registry"
+)
+
+func Title(l registry.Lesson) string { return textutil.Banner(fmt.Sprintf("%d %s", l.Num, l.Title)) }
+`,
+	"lessons/go.mod": "module gotut.example/lessons\n\ngo 1.22\n\nrequire gotut.example/core v0.0.0\n",
+	"lessons/basics/basics.go": `package basics
+
+import (
+	"fmt"
+	"io"
+
+	"gotut.example/core/registry"
+)
+
+func init() {
+	registry.Register(registry.Lesson{Num: 1, Title: "Hello", Module: "gotut.example/lessons",
+		Run: func(w io.Writer) { fmt.Fprintln(w, "hello, workspace") }})
+	registry.Register(registry.Lesson{Num: 2, Title: "Variables", Module: "gotut.example/lessons",
+		Run: func(w io.Writer) { x := 42; fmt.Fprintln(w, "x =", x) }})
+}
+`,
+	"web/go.mod": "module gotut.example/web\n\ngo 1.22\n\nrequire gotut.example/core v0.0.0\n",
+	"web/handlers/handlers.go": `package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gotut.example/core/registry"
+)
+
+func init() {
+	registry.Register(registry.Lesson{Num: 147, Title: "HTTP handlers", Module: "gotut.example/web",
+		Run: func(w io.Writer) { fmt.Fprintln(w, "GET /lessons lists", len(registry.All()), "lessons") }})
+}
+
+// Lessons serves the registry as JSON.
+func Lessons(w http.ResponseWriter, r *http.Request) {
+	type entry struct {
+		Num   int
+		Title string
+	}
+	var out []entry
+	for _, l := range registry.All() {
+		out = append(out, entry{l.Num, l.Title})
+	}
+	json.NewEncoder(w).Encode(out)
+}
+`,
+	"tools/go.mod": `module gotut.example/tools
+
+go 1.22
+
+require (
+	gotut.example/core v0.0.0
+	gotut.example/lessons v0.0.0
+	gotut.example/web v0.0.0
+)
+`,
+	"tools/runner/main.go": `// The runner knows lessons only through the registry: the blank imports
+// below decide which modules' lessons it has.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gotut.example/core/registry"
+	"gotut.example/core/render"
+	_ "gotut.example/lessons/basics"
+	_ "gotut.example/web/handlers"
+)
+
+func main() {
+	if len(os.Args) == 1 {
+		for _, l := range registry.All() {
+			fmt.Printf("%-4d %-14s %s\n", l.Num, l.Title, l.Module)
+		}
+		return
+	}
+	n, _ := strconv.Atoi(os.Args[1])
+	for _, l := range registry.All() {
+		if l.Num == n {
+			fmt.Println(render.Title(l))
+			l.Run(os.Stdout)
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, "no lesson", os.Args[1])
+	os.Exit(2)
+}
+`,
+}
+
+// leak is a web package reaching into core's internal/: the compiler
+// refuses it, workspace or not.
+const leak = `package leak
+
+import "gotut.example/core/internal/textutil"
+
+var Banner = textutil.Banner
+`
+
+func writeFiles(root string, files map[string]string) error {
+	for name, src := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 2: Driving the go Tool
+// ---------------------------------------------------------
+
+// goIn runs go in dir in module mode, offline: nothing here is published,
+// so a lookup on the network would only be slower to fail. GOFLAGS is
+// cleared because -mod=mod, a common setting, is refused in a workspace.
+func goIn(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=", "GOPROXY=off", "GOTOOLCHAIN=local")
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// workUses reads a go.work's use directives with the go tool's own parser.
+func workUses(dir string) ([]string, error) {
+	out, err := goIn(dir, nil, "work", "edit", "-json")
+	if err != nil {
+		return nil, fmt.Errorf("go work edit: %v: %s", err, out)
+	}
+	var work struct{ Use []struct{ DiskPath string } }
+	if err := json.Unmarshal([]byte(out), &work); err != nil {
+		return nil, err
+	}
+	var uses []string
+	for _, u := range work.Use {
+		uses = append(uses, u.DiskPath)
+	}
+	return uses, nil
+}
+
+// ---------------------------------------------------------
+// Part 3: Demo
+// ---------------------------------------------------------
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func indent(s string) {
+	for l := range strings.Lines(s) {
+		fmt.Print("    ", strings.TrimRight(l, "\n"), "\n")
+	}
+}
+
+func demo() error {
+	root, err := os.MkdirTemp("", "workspace-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(root)
+	if err := writeFiles(root, workspace); err != nil {
+		return err
+	}
+
+	fmt.Println("--- Example 1: Four Modules, One go.work ---")
+	var mods []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && (d.Name() == "go.mod" || d.Name() == "go.work") {
+			rel, _ := filepath.Rel(root, path)
+			mods = append(mods, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(mods)
+	fmt.Println("  ", strings.Join(mods, "  "))
+	uses, err := workUses(root)
+	if err != nil {
+		return err
+	}
+	check(len(uses) == 4, "go work edit -json: use "+strings.Join(uses, " "), fmt.Sprintf("uses: %q", uses))
+	out, err := goIn(root, nil, "list", "-m")
+	if err != nil {
+		return fmt.Errorf("go list -m: %v\n%s", err, out)
+	}
+	fmt.Println("  go list -m, the workspace's main modules:")
+	indent(out)
+	fmt.Println()
+
+	fmt.Println("--- Example 2: The Runner Finds Lessons Through the Registry ---")
+	out, err = goIn(root, nil, "run", "gotut.example/tools/runner")
+	if err != nil {
+		return fmt.Errorf("go run runner: %v\n%s", err, out)
+	}
+	indent(out)
+	check(strings.Contains(out, "gotut.example/lessons") && strings.Contains(out, "gotut.example/web"),
+		"lessons from two modules, listed by a third, registered in a fourth",
+		"the runner missed a module's lessons")
+	out, err = goIn(root, nil, "run", "gotut.example/tools/runner", "147")
+	if err != nil {
+		return fmt.Errorf("go run runner 147: %v\n%s", err, out)
+	}
+	indent(out)
+	fmt.Println("  render.Title uses core/internal/textutil: legal, render is under core/.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: internal/ Follows Import Paths ---")
+	if err := writeFiles(root, map[string]string{"web/leak/leak.go": leak}); err != nil {
+		return err
+	}
+	out, err = goIn(root, nil, "build", "gotut.example/web/leak")
+	check(err != nil && strings.Contains(out, "use of internal package"),
+		"web → core/internal: "+lastLine(out), "web imported core's internal package: "+out)
+	fmt.Println("  Same workspace, neighbouring directory — but gotut.example/web/leak")
+	fmt.Println("  doesn't start with gotut.example/core/, so the import is refused.")
+	os.RemoveAll(filepath.Join(root, "web", "leak"))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Without the Workspace ---")
+	out, err = goIn(filepath.Join(root, "tools"), []string{"GOWORK=off"}, "build", "./runner")
+	check(err != nil, "GOWORK=off: "+strings.TrimSuffix(firstLine(out), "; to add:"), "tools built without go.work?")
+	fmt.Println("  tools/go.mod asks for gotut.example/core v0.0.0, which was never")
+	fmt.Println("  published. go.work supplies the directory while you develop; a")
+	fmt.Println("  release tags core first, then bumps the require lines.")
+	out, err = goIn(filepath.Join(root, "core"), []string{"GOWORK=off"}, "build", "./...")
+	check(err == nil, "core alone builds with GOWORK=off: it requires nothing", "core: "+out)
+	fmt.Println()
+
+	fmt.Println("--- Example 5: This Tree ---")
+	uses, err = workUses(".")
+	if err != nil {
+		fmt.Println("  (run from inside the repository to read its go.work)")
+		return nil
+	}
+	fmt.Printf("  The root go.work uses %s.\n", strings.Join(uses, " "))
+	out, err = goIn(".", nil, "list", "-m")
+	check(err == nil && len(strings.Fields(out)) == len(uses),
+		fmt.Sprintf("go list -m: %d modules, one per use", len(strings.Fields(out))), "go list -m: "+out)
+	out, err = goIn("pkg", nil, "list", "./...")
+	check(err == nil, fmt.Sprintf("go list ./... in pkg/: %d packages in the core module", len(strings.Fields(out))),
+		"pkg/: "+out)
+	out, err = goIn(".", nil, "build", "-o", os.DevNull, "./192_katas.go")
+	check(err == nil, "192_katas.go imports .../go_projects/pkg/kata and builds through go.work",
+		"192: "+out)
+	return nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: MULTI-MODULE WORKSPACES — go.work AND internal/")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	fmt.Println(quickReference)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A module is a unit of release; split when parts need their own versions.
+2. go.work builds several modules from local directories; GOWORK=off shows
+   what a downloader gets.
+3. Every go.mod must stand alone: require real versions, no stray replaces.
+4. internal/ is checked by import path; a split can make it illegal.
+5. Across modules, discover by registry and imports, not by globbing files.
+	`)
+}
+
+var quickReference = func() string {
+	var b bytes.Buffer
+	b.WriteString("QUICK REFERENCE\n")
+	for _, row := range [][2]string{
+		{"go work init ./a ./b", "create go.work using two modules"},
+		{"go work use ./c", "add a module (use -r to add every one below)"},
+		{"go work edit -json", "go.work as JSON: Go, Use, Replace"},
+		{"go work sync", "push the workspace's versions into each go.mod"},
+		{"go list -m", "the workspace's modules"},
+		{"GOWORK=off", "ignore go.work: build as a downloader would"},
+		{"a/internal/x", "importable only from a/..."},
+	} {
+		fmt.Fprintf(&b, "  %-22s %s\n", row[0], row[1])
+	}
+	return strings.TrimRight(b.String(), "\n")
+}()
@@ -0,0 +1,678 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*
+TOPIC: API STABILITY — SEMANTIC VERSIONING AND BREAKING CHANGES IN GO
+
+CONCEPT:
+A module's version is a promise about its exported API. Semantic
+versioning, which the go command builds on, says which promise:
+
+    v1.4.2 → v1.4.3   PATCH   fixes; the API is the same
+    v1.4.2 → v1.5.0   MINOR   additions; every caller still compiles
+    v1.4.2 → v2.0.0   MAJOR   something a caller used is gone or changed
+
+v0.x promises nothing: anything may change. From v2 on, the major
+version is part of the import path ("example.com/lib/v2"), so v1 and v2
+are different packages and a program can hold both. The go command
+picks the highest minor version anyone asks for (minimal version
+selection), which is only safe because minors don't break anyone.
+
+WHAT BREAKS A CALLER IN GO. The test is "is there a valid program using
+the old API that no longer compiles?":
+
+    breaking                                 compatible
+    remove or rename an exported name        add a func, type, var, const
+    change a func's parameters or results    add a method to a concrete type
+    change a field's type, remove a field    add a field to a struct
+    add a method to an interface             add a new interface
+    change a type's kind (struct → int)      change unexported anything
+
+Adding a parameter breaks even with a default in mind: Go has no
+defaults, and f(a) no longer compiles. Adding a method to an interface
+breaks every type OUTSIDE the package that implemented it. Adding a
+struct field is allowed by Go's own compatibility promise, though it
+breaks the rare unkeyed literal (T{1, 2}); that is why vet flags those.
+
+WHAT THE TYPES CAN'T SEE. Changing a constant's value, a default, an
+error message someone matched with strings.Contains, or what a function
+does: the API is the same, the behaviour isn't. A checker proves the
+compile-time part only.
+
+THE CHECKER. go/types type-checks each pkg/* package and writes its
+exported surface one feature per line, like Go's own api/go1.*.txt:
+
+    pkg bundle, func Read	(io.Reader, []uint8) (*Bundle, error)
+    pkg bundle, type Bundle	struct
+    pkg bundle, type Bundle struct, Learner	string
+    pkg kata, method (Kata) Grade	(context.Context, []uint8) (Result, error)
+
+The part before the tab is the KEY, the part after its shape. Against a
+stored baseline (pkg/api.txt): a key gone or a shape changed is breaking,
+a new key is compatible — except a new method on an interface that was
+already there. The verdict is the smallest version bump that covers the
+change, and the exit code is 175's contract: 3 when something broke.
+(golang.org/x/exp/apidiff and gorelease do this fully; this is the idea
+in one file.)
+
+RUN:
+    go run 194_api_compat.go                          (demo)
+    go run 194_api_compat.go tool apicheck            (pkg/ against pkg/api.txt)
+    go run 194_api_compat.go tool apicheck -update    (after a deliberate change)
+*/
+
+// ---------------------------------------------------------
+// Part 1: The Exported Surface
+// ---------------------------------------------------------
+
+// Feature is one exported thing. Key names it; Shape is everything about
+// it a caller could depend on.
+type Feature struct {
+	Key   string // "pkg bundle, func Read"
+	Shape string // "(io.Reader, []uint8) (*Bundle, error)"
+}
+
+func (f Feature) String() string { return f.Key + "\t" + f.Shape }
+
+// imp type-checks imports from source: the standard library, and the
+// other packages in pkg/, found through the module the way the go command
+// finds them. One for all packages, so each import is loaded once.
+var (
+	fset = token.NewFileSet()
+	imp  = importer.ForCompiler(fset, "source", nil)
+)
+
+// Surface type-checks the package in dir (tests left out) and returns its
+// exported features, sorted. name labels every key: "pkg NAME, ...".
+// Files with build constraints count as the go command would count them
+// here: pkg/term has one file per kind of OS, each defining MakeRaw.
+func Surface(dir, name string) ([]Feature, error) {
+	// Absolute, so that imports resolve in module mode: go/build only
+	// asks the go command about modules from an absolute directory.
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, filepath.Base(p)); err != nil || !ok {
+			continue
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s: no Go files", dir)
+	}
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(name, fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	var out []Feature
+	add := func(key, shape string) { out = append(out, Feature{"pkg " + name + ", " + key, shape}) }
+	scope := pkg.Scope()
+	for _, n := range scope.Names() { // Sorted
+		obj := scope.Lookup(n)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Const:
+			add("const "+n, types.TypeString(obj.Type(), q))
+		case *types.Var:
+			add("var "+n, types.TypeString(obj.Type(), q))
+		case *types.Func:
+			sig := obj.Type().(*types.Signature)
+			add("func "+n, typeParams(sig.TypeParams(), q)+signature(sig, q))
+		case *types.TypeName:
+			typeFeatures(obj, q, add)
+		}
+	}
+	slices.SortFunc(out, func(a, b Feature) int { return strings.Compare(a.Key, b.Key) })
+	return out, nil
+}
+
+// typeFeatures adds a type, its exported fields or interface methods, and
+// its exported methods. An interface's methods are keys of their own, so
+// adding one shows up as a new key under an old interface.
+func typeFeatures(obj *types.TypeName, q types.Qualifier, add func(key, shape string)) {
+	n := obj.Name()
+	if obj.IsAlias() {
+		add("type "+n, "= "+types.TypeString(obj.Type(), q))
+		return
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return
+	}
+	tp := typeParams(named.TypeParams(), q)
+	switch u := named.Underlying().(type) {
+	case *types.Struct:
+		add("type "+n, tp+"struct")
+		for i := range u.NumFields() {
+			f := u.Field(i)
+			switch {
+			case !f.Exported():
+			case f.Embedded():
+				add("type "+n+" struct, embedded "+f.Name(), types.TypeString(f.Type(), q))
+			default:
+				add("type "+n+" struct, "+f.Name(), types.TypeString(f.Type(), q))
+			}
+		}
+	case *types.Interface:
+		add("type "+n, tp+"interface")
+		for i := range u.NumMethods() {
+			if m := u.Method(i); m.Exported() {
+				add("type "+n+" interface, "+m.Name(), signature(m.Type().(*types.Signature), q))
+			}
+		}
+	default:
+		add("type "+n, tp+types.TypeString(u, q))
+	}
+	for i := range named.NumMethods() {
+		m := named.Method(i)
+		if !m.Exported() {
+			continue
+		}
+		sig := m.Type().(*types.Signature)
+		recv := n
+		if _, ok := sig.Recv().Type().(*types.Pointer); ok {
+			recv = "*" + n
+		}
+		add("method ("+recv+") "+m.Name(), signature(sig, q))
+	}
+}
+
+// signature is a func's parameter and result types, without names:
+// renaming a parameter breaks no one.
+func signature(sig *types.Signature, q types.Qualifier) string {
+	tuple := func(t *types.Tuple, variadic bool) []string {
+		var s []string
+		for i := range t.Len() {
+			typ := t.At(i).Type()
+			if variadic && i == t.Len()-1 {
+				s = append(s, "..."+types.TypeString(typ.(*types.Slice).Elem(), q))
+			} else {
+				s = append(s, types.TypeString(typ, q))
+			}
+		}
+		return s
+	}
+	out := "(" + strings.Join(tuple(sig.Params(), sig.Variadic()), ", ") + ")"
+	switch res := tuple(sig.Results(), false); len(res) {
+	case 0:
+	case 1:
+		out += " " + res[0]
+	default:
+		out += " (" + strings.Join(res, ", ") + ")"
+	}
+	return out
+}
+
+// typeParams is "[T comparable] ", or "" for a non-generic declaration.
+func typeParams(list *types.TypeParamList, q types.Qualifier) string {
+	if list.Len() == 0 {
+		return ""
+	}
+	var s []string
+	for i := range list.Len() {
+		p := list.At(i)
+		s = append(s, p.Obj().Name()+" "+types.TypeString(p.Constraint(), q))
+	}
+	return "[" + strings.Join(s, ", ") + "] "
+}
+
+// ---------------------------------------------------------
+// Part 2: Comparing Two Surfaces
+// ---------------------------------------------------------
+
+// Change is one difference from the baseline. Old is empty for an
+// addition, New for a removal.
+type Change struct {
+	Key, Old, New string
+	Breaking      bool
+	Why           string
+}
+
+// Compare lists what changed from old to new, breaking changes first.
+func Compare(old, new []Feature) []Change {
+	before, after := index(old), index(new)
+	var changes []Change
+	for _, f := range old {
+		shape, ok := after[f.Key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Key: f.Key, Old: f.Shape, Breaking: true, Why: "removed"})
+		case shape != f.Shape:
+			changes = append(changes, Change{Key: f.Key, Old: f.Shape, New: shape, Breaking: true, Why: "changed"})
+		}
+	}
+	for _, f := range new {
+		if _, ok := before[f.Key]; ok {
+			continue
+		}
+		c := Change{Key: f.Key, New: f.Shape, Why: "added"}
+		if iface, _, ok := strings.Cut(f.Key, " interface, "); ok {
+			if _, existed := before[iface]; existed {
+				c.Breaking, c.Why = true, "added to an interface others implement"
+			}
+		}
+		changes = append(changes, c)
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int {
+		if a.Breaking != b.Breaking {
+			if a.Breaking {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
+
+func index(fs []Feature) map[string]string {
+	m := make(map[string]string, len(fs))
+	for _, f := range fs {
+		m[f.Key] = f.Shape
+	}
+	return m
+}
+
+// Bump is the smallest semver bump that covers changes.
+func Bump(changes []Change) string {
+	switch {
+	case slices.ContainsFunc(changes, func(c Change) bool { return c.Breaking }):
+		return "major"
+	case len(changes) > 0:
+		return "minor"
+	}
+	return "patch"
+}
+
+// ---------------------------------------------------------
+// Part 3: The Baseline
+// ---------------------------------------------------------
+// One feature per line, "key<TAB>shape", sorted, so a deliberate change
+// is a one-line diff in review, as with 191's lint.baseline.
+
+func readBaseline(path string) ([]Feature, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var fs []Feature
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, shape, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("%s: %q: want KEY<TAB>SHAPE", path, line)
+		}
+		fs = append(fs, Feature{key, shape})
+	}
+	return fs, sc.Err()
+}
+
+func writeBaseline(path string, fs []Feature) error {
+	var b strings.Builder
+	b.WriteString("# Exported API of pkg/* (194_api_compat.go). After a deliberate change: tool apicheck -update.\n")
+	for _, f := range fs {
+		b.WriteString(f.String() + "\n")
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// packagesIn returns root's subdirectories holding a non-main package,
+// by name: pkg/bundle is "bundle".
+func packagesIn(root string) (map[string]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := map[string]string{}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || e.Name() == "testdata" {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		for _, f := range files {
+			if strings.HasSuffix(f, "_test.go") {
+				continue
+			}
+			if af, err := parser.ParseFile(token.NewFileSet(), f, nil, parser.PackageClauseOnly); err == nil && af.Name.Name != "main" {
+				pkgs[e.Name()] = dir
+			}
+			break
+		}
+	}
+	return pkgs, nil
+}
+
+// SurfaceOf is every package's surface under root, in one sorted list.
+func SurfaceOf(root string) ([]Feature, error) {
+	pkgs, err := packagesIn(root)
+	if err != nil {
+		return nil, err
+	}
+	var all []Feature
+	for name, dir := range pkgs {
+		fs, err := Surface(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, fs...)
+	}
+	slices.SortFunc(all, func(a, b Feature) int { return strings.Compare(a.Key, b.Key) })
+	return all, nil
+}
+
+// ---------------------------------------------------------
+// Part 4: The Tool
+// ---------------------------------------------------------
+
+const (
+	exitOK       = 0
+	exitRuntime  = 1
+	exitUsage    = 2
+	exitBreaking = 3 // 175's "verification failure"
+)
+
+func writeChanges(w io.Writer, changes []Change) {
+	for _, c := range changes {
+		mark := "+"
+		if c.Breaking {
+			mark = "!"
+		}
+		switch {
+		case c.Old == "":
+			fmt.Fprintf(w, "%s %s %s  (%s)\n", mark, c.Key, c.New, c.Why)
+		case c.New == "":
+			fmt.Fprintf(w, "%s %s %s  (%s)\n", mark, c.Key, c.Old, c.Why)
+		default:
+			fmt.Fprintf(w, "%s %s  (%s)\n      was %s\n      now %s\n", mark, c.Key, c.Why, c.Old, c.New)
+		}
+	}
+}
+
+func toolAPICheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("apicheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseline := fs.String("baseline", "", "the recorded API (default ROOT/api.txt)")
+	update := fs.Bool("update", false, "record the current API as the baseline")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	root := "pkg"
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		root = fs.Arg(0)
+	default:
+		fmt.Fprintln(stderr, "apicheck: want at most one ROOT directory")
+		return exitUsage
+	}
+	if *baseline == "" {
+		*baseline = filepath.Join(root, "api.txt")
+	}
+	current, err := SurfaceOf(root)
+	if err != nil {
+		fmt.Fprintln(stderr, "apicheck:", err)
+		return exitRuntime
+	}
+	if *update {
+		if err := writeBaseline(*baseline, current); err != nil {
+			fmt.Fprintln(stderr, "apicheck:", err)
+			return exitRuntime
+		}
+		fmt.Fprintf(stdout, "wrote %s: %d features\n", *baseline, len(current))
+		return exitOK
+	}
+	old, err := readBaseline(*baseline)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(stderr, "apicheck: no baseline %s; record one with -update\n", *baseline)
+		return exitUsage
+	} else if err != nil {
+		fmt.Fprintln(stderr, "apicheck:", err)
+		return exitRuntime
+	}
+
+	changes := Compare(old, current)
+	writeChanges(stdout, changes)
+	bump := Bump(changes)
+	fmt.Fprintf(stdout, "%d features, changed since the baseline: %d; a %s version bump\n", len(current), len(changes), bump)
+	if bump == "major" {
+		fmt.Fprintln(stdout, "Breaking changes (!) need a new major version, or a way to keep the old API.")
+		return exitBreaking
+	}
+	return exitOK
+}
+
+// ---------------------------------------------------------
+// Part 5: Demo
+// ---------------------------------------------------------
+
+const shapesV1 = `package shapes
+
+import "errors"
+
+const Version = "1"
+
+var ErrNegative = errors.New("negative size")
+
+type Shape interface {
+	Area() float64
+}
+
+type Rect struct {
+	W, H float64
+}
+
+func (r Rect) Area() float64 { return r.W * r.H }
+
+func Total(shapes ...Shape) float64 {
+	t := 0.0
+	for _, s := range shapes {
+		t += s.Area()
+	}
+	return t
+}
+`
+
+// edits are v1 → v2 variants, each one change, by what it does.
+var edits = []struct {
+	name     string
+	old, new string
+}{
+	{"add a function", "func Total(", "func Scale(r Rect, k float64) Rect { return Rect{r.W * k, r.H * k} }\n\nfunc Total("},
+	{"add a struct field", "W, H float64", "W, H  float64\n\tLabel string"},
+	{"add a method to Rect", "func Total(", "func (r Rect) Perimeter() float64 { return 2 * (r.W + r.H) }\n\nfunc Total("},
+	{"change a constant's value", `Version = "1"`, `Version = "2"`},
+	{"add a parameter", "func Total(shapes ...Shape) float64 {", "func Total(scale float64, shapes ...Shape) float64 {"},
+	{"add a method to Shape", "Area() float64\n}", "Area() float64\n\tPerimeter() float64\n}"},
+	{"change a field's type", "W, H float64\n}\n\nfunc (r Rect) Area() float64 { return r.W * r.H }", "W, H int\n}\n\nfunc (r Rect) Area() float64 { return float64(r.W * r.H) }"},
+	{"remove a variable", "var ErrNegative = errors.New(\"negative size\")\n", "var errNegative = errors.New(\"negative size\")\n\nvar _ = errNegative\n"},
+}
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "apicheck-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	pkg := filepath.Join(dir, "shapes")
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		return err
+	}
+	write := func(src string) error { return os.WriteFile(filepath.Join(pkg, "shapes.go"), []byte(src), 0o644) }
+
+	fmt.Println("--- Example 1: A Package's Surface ---")
+	if err := write(shapesV1); err != nil {
+		return err
+	}
+	v1, err := Surface(pkg, "shapes")
+	if err != nil {
+		return err
+	}
+	for _, f := range v1 {
+		fmt.Printf("  %-40s %s\n", f.Key, f.Shape)
+	}
+	fmt.Println("  No parameter names, no values, nothing unexported: a caller can't")
+	fmt.Println("  depend on any of those at compile time.")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: One Change at a Time ---")
+	for _, e := range edits {
+		if err := write(strings.Replace(shapesV1, e.old, e.new, 1)); err != nil {
+			return err
+		}
+		v2, err := Surface(pkg, "shapes")
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.name, err)
+		}
+		changes := Compare(v1, v2)
+		why := "nothing the types can see"
+		if len(changes) > 0 {
+			why = changes[0].Why
+		}
+		fmt.Printf("  %-26s → %-5s  %s\n", e.name, Bump(changes), why)
+	}
+	fmt.Println("  The constant's new value is a PATCH to the checker and may still break")
+	fmt.Println("  a caller who relied on it: the types prove compatibility, not behaviour.")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The Tool and Its Baseline ---")
+	if err := write(shapesV1); err != nil {
+		return err
+	}
+	var out, errOut strings.Builder
+	code := toolAPICheck([]string{"-update", dir}, &out, &errOut)
+	check(code == exitOK, strings.TrimSpace(out.String()), "update: "+errOut.String())
+	if err := write(strings.Replace(shapesV1, "Area() float64\n}", "Area() float64\n\tPerimeter() float64\n}", 1)); err != nil {
+		return err
+	}
+	out.Reset()
+	code = toolAPICheck([]string{dir}, &out, &errOut)
+	for l := range strings.Lines(out.String()) {
+		fmt.Print("    ", l)
+	}
+	check(code == exitBreaking, "exit 3, as 175's verify failures: CI stops here", fmt.Sprintf("exit %d", code))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: This Tree's pkg/ ---")
+	out.Reset()
+	errOut.Reset()
+	code = toolAPICheck(nil, &out, &errOut)
+	switch {
+	case code == exitUsage && strings.Contains(errOut.String(), "no baseline"):
+		fmt.Println("  (no pkg/api.txt here: run from go_projects/)")
+	case code == exitOK:
+		check(true, lastLine(out.String()), "")
+	default:
+		fmt.Print(out.String(), errOut.String())
+		check(false, "", fmt.Sprintf("pkg/ against pkg/api.txt: exit %d", code))
+	}
+	fmt.Println("  Change an exported name in pkg/ and this goes red; -update records a")
+	fmt.Println("  change you meant, and the api.txt diff shows it in review.")
+	return nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if len(os.Args) > 2 && os.Args[1] == "tool" && os.Args[2] == "apicheck" {
+			os.Exit(toolAPICheck(os.Args[3:], os.Stdout, os.Stderr))
+		}
+		fmt.Fprintf(os.Stderr, "unknown command %q (want: tool apicheck)\n", strings.Join(os.Args[1:], " "))
+		os.Exit(exitUsage)
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: API STABILITY — SEMANTIC VERSIONING AND BREAKING CHANGES IN GO")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  PATCH  v1.4.3   fixes only            go get example.com/lib@v1.4.3
+  MINOR  v1.5.0   additions             callers compile unchanged
+  MAJOR  v2.0.0   breaking              import "example.com/lib/v2"
+  v0.x            no promise            anything may change
+  breaking        remove/rename, change a signature or field type,
+                  add an interface method, change a type's kind
+  compatible      add funcs, types, fields, methods on concrete types`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A version number is a promise about the exported API; semver says which.
+2. Breaking means some valid caller stops compiling: removals, changed
+   signatures, and new methods on interfaces.
+3. Record the API as text with go/types; a baseline diff makes changes
+   visible in review.
+4. The checker proves the compile-time part; values and behaviour are on you.
+5. From v2, a break is a new import path: plan it, don't slip it into a minor.
+	`)
+}
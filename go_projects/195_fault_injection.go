@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing/fstest"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/faultfs"
+)
+
+/*
+TOPIC: FAULT INJECTION — RUNNING THE ERROR PATHS, NOT JUST WRITING THEM
+
+CONCEPT:
+"Always check errors" is advice nobody can see being followed. The
+`if err != nil` branch after a copy runs when a disk fails or a client
+hangs up, which is never on a laptop, so a branch that returns the wrong
+thing, leaves half a file behind or never runs at all looks exactly like
+one that works. The only way to know is to make the failure happen.
+
+pkg/faultfs wraps the three things I/O code is written against:
+
+    faultfs.Reader(r, fault)    an io.Reader
+    faultfs.Writer(w, fault)    an io.Writer
+    faultfs.NewFS(fsys)         an fs.FS: Fail(pattern, err) on Open,
+                                Inject(pattern, fault) on its files' reads
+
+and a Fault says what goes wrong and when:
+
+    Fault{After: 4096}                      EIO once 4 KiB have passed
+    Fault{After: 64, Err: syscall.ENOSPC}   your own error: a full disk
+    Fault{Max: 3}                           short reads, 3 bytes at most
+    Fault{Max: 10, Short: true}             a writer that lies about short writes
+    Fault{Delay: time.Millisecond}          every call slowed: a slow disk
+
+Faults are planned, not random: the same Fault fails at the same byte
+every run, so a test that catches a bug catches it every time. (Chaos
+tools in production pick failures at random; in a test that is a flake.)
+
+WHAT TURNS UP, in this lesson's order:
+    io.Copy         returns the bytes copied AND the error: both matter
+    bufio.Scanner   stops at the error and says nothing until you ask Err()
+    short reads     legal; one Read is not "read n bytes", io.ReadFull is
+    short writes    illegal without an error; io.Copy and bufio catch them
+    a backup        every failure reported, no half-copied file left behind
+    an upload       a client that hangs up must not leave a truncated file
+    slow I/O        one syscall per byte costs; bufio turns many into one
+
+RUN:
+    go run 195_fault_injection.go
+    go test ./pkg/faultfs
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 1: bufio.Scanner Hides Errors Until Asked
+// ---------------------------------------------------------
+// Scan returns false at the end of the input and at an error alike. A
+// loop that doesn't ask Err() afterwards reports half a file as all of it.
+
+func countLinesCareless(r io.Reader) int {
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	return n
+}
+
+func countLines(r io.Reader) (int, error) {
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	return n, sc.Err()
+}
+
+// ---------------------------------------------------------
+// Part 2: A Backup That Reports Every Failure
+// ---------------------------------------------------------
+// backupTree copies every file of fsys into dst, like 154's backup tool:
+// one failure doesn't stop the rest, every failure is returned (joined),
+// and no failed copy is left in dst looking like a good one — each file
+// is written to a temporary name and renamed only once it is complete.
+// out is the fault-injection hook on the writing side: nil writes to the
+// files as they are.
+
+func backupTree(fsys fs.FS, dst string, out func(name string, w io.Writer) io.Writer) (copied int, err error) {
+	var errs []error
+	walkErr := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, filepath.FromSlash(name)), 0o755)
+		}
+		if err := backupFile(fsys, name, filepath.Join(dst, filepath.FromSlash(name)), out); err != nil {
+			errs = append(errs, fmt.Errorf("backing up %s: %w", name, err))
+			return nil
+		}
+		copied++
+		return nil
+	})
+	return copied, errors.Join(append(errs, walkErr)...)
+}
+
+func backupFile(fsys fs.FS, name, dst string, out func(string, io.Writer) io.Writer) error {
+	in, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".partial-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Gone after the rename; cleanup on failure
+	var w io.Writer = tmp
+	if out != nil {
+		w = out(name, tmp)
+	}
+	_, err = io.Copy(w, in)
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// ---------------------------------------------------------
+// Part 3: An Upload Whose Client Hangs Up
+// ---------------------------------------------------------
+// A body that ends early arrives as an error from Read, usually
+// io.ErrUnexpectedEOF. The careless handler writes straight to the final
+// name and ignores it; the careful one writes to a temporary file, and
+// only renames it into place after a clean copy.
+
+type uploads struct {
+	dir     string
+	careful bool
+}
+
+func (u uploads) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	final := filepath.Join(u.dir, filepath.Base(r.URL.Path))
+	if !u.careful {
+		f, _ := os.Create(final)
+		io.Copy(f, r.Body)
+		f.Close()
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	tmp, err := os.CreateTemp(u.dir, ".upload-*")
+	if err != nil {
+		http.Error(w, "storage unavailable", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, r.Body)
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		http.Error(w, "upload incomplete: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := os.Rename(tmp.Name(), final); err != nil {
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func demo() error {
+	data := strings.Repeat("a line of the log\n", 100) // 1800 bytes
+
+	fmt.Println("--- Example 1: io.Copy Returns the Count AND the Error ---")
+	var dst bytes.Buffer
+	n, err := io.Copy(&dst, faultfs.Reader(strings.NewReader(data), faultfs.Fault{After: 1000}))
+	fmt.Printf("  io.Copy → %d bytes, %v\n", n, err)
+	check(n == 1000 && errors.Is(err, syscall.EIO),
+		"1000 bytes arrived before the EIO: a caller that only looks at n thinks it's done",
+		fmt.Sprintf("got %d, %v", n, err))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: bufio.Scanner Says Nothing Until Asked ---")
+	failing := func() io.Reader {
+		return faultfs.Reader(strings.NewReader(data), faultfs.Fault{After: 900})
+	}
+	careless := countLinesCareless(failing())
+	lines, err := countLines(failing())
+	fmt.Printf("  without Err(): %d lines; with it: %d lines, %v\n", careless, lines, err)
+	check(careless == 50 && errors.Is(err, syscall.EIO),
+		"same 50 lines both times; only the loop that checks sc.Err() knows it stopped early",
+		fmt.Sprintf("got %d and %d, %v", careless, lines, err))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Short Reads and Short Writes ---")
+	// A 16-byte header read with one Read, from a reader that returns at
+	// most 5 bytes a call, as a pipe or a socket may.
+	header := make([]byte, 16)
+	r := faultfs.Reader(strings.NewReader(data), faultfs.Fault{Max: 5})
+	n1, _ := r.Read(header)
+	r = faultfs.Reader(strings.NewReader(data), faultfs.Fault{Max: 5})
+	n2, err := io.ReadFull(r, header)
+	check(n1 == 5 && n2 == 16 && err == nil,
+		fmt.Sprintf("one Read: %d bytes of 16, and no error; io.ReadFull: %d", n1, n2),
+		fmt.Sprintf("Read %d, ReadFull %d, %v", n1, n2, err))
+
+	// A writer that takes 10 bytes a call and says nothing is wrong.
+	var sink bytes.Buffer
+	liar := faultfs.Writer(&sink, faultfs.Fault{Max: 10, Short: true})
+	nw, err := liar.Write([]byte(data[:64]))
+	check(nw == 10 && err == nil && sink.Len() == 10,
+		fmt.Sprintf("liar.Write(64 bytes) → %d, %v: a loop that ignores n loses 54 bytes silently", nw, err),
+		fmt.Sprintf("liar.Write → %d, %v", nw, err))
+	_, errCopy := io.Copy(faultfs.Writer(io.Discard, faultfs.Fault{Max: 10, Short: true}), strings.NewReader(data))
+	bw := bufio.NewWriter(faultfs.Writer(io.Discard, faultfs.Fault{Max: 10, Short: true}))
+	bw.WriteString(data)
+	errFlush := bw.Flush()
+	check(errors.Is(errCopy, io.ErrShortWrite) && errors.Is(errFlush, io.ErrShortWrite),
+		"io.Copy and bufio.Writer.Flush both notice: io.ErrShortWrite",
+		fmt.Sprintf("io.Copy %v, Flush %v", errCopy, errFlush))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Backup With a Bad File and a Full Disk ---")
+	dir, err := os.MkdirTemp("", "gotut-faults-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	src := faultfs.NewFS(fstest.MapFS{
+		"notes.txt":         {Data: []byte("remember the milk\n")},
+		"photos/cat.jpg":    {Data: bytes.Repeat([]byte{0xff}, 3000)},
+		"photos/dog.jpg":    {Data: bytes.Repeat([]byte{0xd8}, 3000)},
+		"private/diary.txt": {Data: []byte("dear diary\n")},
+		"logs/app.log":      {Data: []byte(data)},
+	})
+	src.Fail("private/*", fs.ErrPermission)                  // Can't even open it
+	src.Inject("photos/cat.jpg", faultfs.Fault{After: 2048}) // A bad sector half way
+	diskFull := func(name string, w io.Writer) io.Writer {
+		if name == "logs/app.log" {
+			return faultfs.Writer(w, faultfs.Fault{After: 1024, Err: syscall.ENOSPC})
+		}
+		return w
+	}
+	backup := filepath.Join(dir, "backup")
+	copied, err := backupTree(src, backup, diskFull)
+	fmt.Printf("  copied %d files; failures:\n", copied)
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Println("    " + line)
+	}
+	var left []string
+	filepath.WalkDir(backup, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(backup, p)
+			left = append(left, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	check(copied == 2 && errors.Is(err, fs.ErrPermission) && errors.Is(err, syscall.EIO) && errors.Is(err, syscall.ENOSPC),
+		"2 copied; the permission error, the EIO and the full disk all reported, none stopping the rest",
+		fmt.Sprintf("copied %d, err %v", copied, err))
+	check(strings.Join(left, " ") == "notes.txt photos/dog.jpg",
+		fmt.Sprintf("in the backup: %v — no half cat.jpg, no 1 KiB app.log, no .partial files", left),
+		fmt.Sprintf("in the backup: %v", left))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: An Upload Cut Off Half Way ---")
+	for _, careful := range []bool{false, true} {
+		updir := filepath.Join(dir, fmt.Sprintf("uploads-%v", careful))
+		if err := os.Mkdir(updir, 0o755); err != nil {
+			return err
+		}
+		// The client sends 1000 of 1800 bytes, then its connection drops.
+		body := faultfs.Reader(strings.NewReader(data), faultfs.Fault{After: 1000, Err: io.ErrUnexpectedEOF})
+		rec := httptest.NewRecorder()
+		uploads{updir, careful}.ServeHTTP(rec, httptest.NewRequest("PUT", "/app.log", body))
+		info, statErr := os.Stat(filepath.Join(updir, "app.log"))
+		if careful {
+			check(rec.Code == http.StatusBadRequest && errors.Is(statErr, fs.ErrNotExist),
+				fmt.Sprintf("careful handler: %d, and no app.log on disk", rec.Code),
+				fmt.Sprintf("careful handler: %d, stat %v", rec.Code, statErr))
+		} else {
+			check(rec.Code == http.StatusCreated && statErr == nil && info.Size() == 1000,
+				"careless handler: 201 Created, and a 1000-byte app.log that looks like a real one",
+				fmt.Sprintf("careless handler: %d, stat %v", rec.Code, statErr))
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 6: Slow I/O and bufio ---")
+	// 200 bytes, read a byte at a time, from a reader that takes a
+	// millisecond a call: the cost of one read(2) per byte, made visible.
+	slow := func() io.Reader {
+		return faultfs.Reader(strings.NewReader(data[:200]), faultfs.Fault{Delay: time.Millisecond})
+	}
+	timeIt := func(r io.ByteReader) time.Duration {
+		start := time.Now()
+		for {
+			if _, err := r.ReadByte(); err != nil {
+				return time.Since(start)
+			}
+		}
+	}
+	unbuffered := timeIt(byteReader{slow()})
+	buffered := timeIt(bufio.NewReader(slow()))
+	fmt.Printf("  byte by byte: %v; through bufio.Reader: %v\n", unbuffered.Round(time.Millisecond), buffered.Round(time.Millisecond))
+	check(buffered*10 < unbuffered,
+		"bufio made 2 slow calls instead of 201",
+		"bufio was not faster")
+	return nil
+}
+
+// byteReader reads one byte per Read call: what ReadByte costs without a
+// buffer.
+type byteReader struct{ r io.Reader }
+
+func (b byteReader) ReadByte() (byte, error) {
+	var p [1]byte
+	_, err := io.ReadFull(b.r, p[:])
+	return p[0], err
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: FAULT INJECTION — RUNNING THE ERROR PATHS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  faultfs.Reader(r, Fault{After: n})            EIO after n bytes
+  faultfs.Reader(r, Fault{Max: n})              short reads
+  faultfs.Writer(w, Fault{After: n, Err: e})    e after n bytes (ENOSPC: disk full)
+  faultfs.Writer(w, Fault{Max: n, Short: true}) short writes with a nil error
+  Fault{Delay: d}                               every call sleeps d
+  fsys := faultfs.NewFS(os.DirFS(dir))
+  fsys.Fail("private/*", fs.ErrPermission)      Open fails
+  fsys.Inject("*.log", Fault{After: n})         reads of matching files fail`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. An error path that has never run is a guess; inject the fault and run it.
+2. Make faults deterministic: the same byte, every run, so a test never flakes.
+3. bufio.Scanner stops quietly: always check sc.Err() after the loop.
+4. One Read may return less than asked; io.ReadFull reads it all or says why.
+5. Write to a temporary name and rename when complete: a failure then
+   leaves nothing behind, instead of something that looks finished.
+6. Report every failure, joined; one bad file shouldn't hide the others.
+	`)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/errorx"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/retry"
+)
+
+/*
+TOPIC: RETRIES — BACKOFF, JITTER, AND LETTING THE ERROR DECIDE
+
+CONCEPT:
+Some failures pass by themselves: a query that timed out while the
+database was busy, a connection reset during a deploy. Trying again is
+the right answer to those, and the wrong one to the rest. Three questions
+decide it, and the error can answer all of them (intermediate Topic 69):
+
+    is it SAFE to run again?     a read is; an INSERT might apply twice
+    could it PASS next time?     a timeout might; a syntax error never will
+    is anyone still waiting?     not if the caller's context is done
+
+errorx.DatabaseError answers the first two with one method,
+
+    func (d *DatabaseError) Retryable() bool { return d.CanRetry() && d.IsTimeout() }
+
+and pkg/retry asks it, through any amount of %w wrapping:
+
+    err := retry.Do(ctx, retry.Default, func(ctx context.Context) error {
+        return db.LoadUser(ctx, id)
+    })
+
+An error without a Retryable method is NOT retried. Retrying by default
+turns one duplicate-charge bug into several.
+
+HOW LONG TO WAIT. Immediately is too soon: whatever broke hasn't
+recovered, and now it has more load. Waits that double each time,
+EXPONENTIAL BACKOFF, give it room:
+
+    attempt 1 fails → wait 100ms → attempt 2 fails → wait 200ms → ...
+
+capped (MaxDelay) so a long outage doesn't mean hour-long sleeps, and
+limited (MaxAttempts) so the caller gets an answer.
+
+JITTER. A thousand clients that failed at the same moment, all waiting
+exactly 100ms, all come back at the same moment — a thundering herd that
+knocks the server over again. Jitter takes a random part off each wait so
+they arrive spread out; Jitter 1 ("full jitter") spreads them the most.
+
+Topic 149 retries whole transactions the same way, deciding on the
+SQLSTATE code instead.
+
+RUN:
+    go run 196_retry.go
+    go test ./pkg/retry ./pkg/errorx
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 1: A Flaky Database
+// ---------------------------------------------------------
+// flakyDB fails its first failures calls with cause, as an
+// *errorx.DatabaseError for op, then succeeds. calls counts every
+// attempt, so each example can say how many Do made.
+
+type flakyDB struct {
+	op       string // SELECT, INSERT...
+	failures int
+	cause    error
+	calls    int
+}
+
+func (db *flakyDB) Exec(ctx context.Context) error {
+	db.calls++
+	if db.calls <= db.failures {
+		return fmt.Errorf("loading user 42: %w", errorx.NewDatabaseError(db.op, "users", db.cause))
+	}
+	return nil
+}
+
+// logRetries is an OnRetry that prints each failed attempt and the wait.
+func logRetries(attempt int, err error, wait time.Duration) {
+	fmt.Printf("    attempt %d: %v → waiting %v\n", attempt, err, wait.Round(time.Microsecond))
+}
+
+func demo() error {
+	fast := retry.Policy{MaxAttempts: 4, BaseDelay: 2 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Jitter: 0.5, OnRetry: logRetries}
+
+	fmt.Println("--- Example 1: The Backoff Schedule ---")
+	p := retry.Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second, Jitter: 0.5}
+	var schedule []string
+	for n := 1; n <= 7; n++ {
+		d := p.Backoff(n)
+		schedule = append(schedule, fmt.Sprintf("%v–%v", time.Duration(float64(d)*(1-p.Jitter)), d))
+	}
+	fmt.Printf("  BaseDelay 100ms, MaxDelay 2s, Jitter 0.5:\n    %s\n", strings.Join(schedule, ", "))
+	check(p.Backoff(5) == 1600*time.Millisecond && p.Backoff(7) == 2*time.Second,
+		"doubling to 1.6s, then held at MaxDelay",
+		fmt.Sprintf("Backoff(5) = %v, Backoff(7) = %v", p.Backoff(5), p.Backoff(7)))
+	fmt.Println()
+
+	ctx := context.Background()
+
+	fmt.Println("--- Example 2: A Read That Times Out Twice ---")
+	db := &flakyDB{op: "SELECT", failures: 2, cause: context.DeadlineExceeded}
+	err := retry.Do(ctx, fast, db.Exec)
+	check(err == nil && db.calls == 3,
+		"third attempt succeeds: the caller never sees the two timeouts",
+		fmt.Sprintf("%v after %d calls", err, db.calls))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: A Write That Times Out ---")
+	db = &flakyDB{op: "INSERT", failures: 2, cause: context.DeadlineExceeded}
+	err = retry.Do(ctx, fast, db.Exec)
+	fmt.Printf("    %v\n", err)
+	check(db.calls == 1 && !retry.IsRetryable(err),
+		"1 attempt: the INSERT may have committed before the timeout, so not again",
+		fmt.Sprintf("%d calls", db.calls))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: A Read That Can Never Work ---")
+	db = &flakyDB{op: "SELECT", failures: 99, cause: errors.New(`syntax error at or near "FORM"`)}
+	err = retry.Do(ctx, fast, db.Exec)
+	fmt.Printf("    %v\n", err)
+	check(db.calls == 1,
+		"1 attempt: safe to repeat, but a syntax error won't pass by waiting",
+		fmt.Sprintf("%d calls", db.calls))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: The Database Stays Down ---")
+	db = &flakyDB{op: "SELECT", failures: 99, cause: context.DeadlineExceeded}
+	err = retry.Do(ctx, fast, db.Exec)
+	fmt.Printf("    %v\n", err)
+	var dbErr *errorx.DatabaseError
+	check(db.calls == 4 && errors.As(err, &dbErr) && dbErr.Table == "users",
+		"4 attempts, then the last error, still an *errorx.DatabaseError to errors.As",
+		fmt.Sprintf("%d calls, %v", db.calls, err))
+	fmt.Println()
+
+	fmt.Println("--- Example 6: The Caller Stops Waiting ---")
+	slow := retry.Policy{MaxAttempts: 10, BaseDelay: time.Second}
+	deadline, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+	db = &flakyDB{op: "SELECT", failures: 99, cause: context.DeadlineExceeded}
+	start := time.Now()
+	err = retry.Do(deadline, slow, db.Exec)
+	fmt.Printf("    %v\n", err)
+	check(errors.Is(err, context.DeadlineExceeded) && time.Since(start) < time.Second,
+		fmt.Sprintf("back after %v, not the 1s wait: the context ends the sleep", time.Since(start).Round(time.Millisecond)),
+		fmt.Sprintf("%v after %v", err, time.Since(start)))
+	fmt.Println()
+
+	fmt.Println("--- Example 7: Why Jitter ---")
+	// Five clients whose first attempt failed at the same instant: when
+	// does each come back?
+	for _, jitter := range []float64{0, 1} {
+		var waits []time.Duration
+		herd := retry.Policy{MaxAttempts: 2, BaseDelay: 10 * time.Millisecond, Jitter: jitter,
+			OnRetry: func(_ int, _ error, wait time.Duration) { waits = append(waits, wait.Round(time.Microsecond)) }}
+		for range 5 {
+			client := &flakyDB{op: "SELECT", failures: 1, cause: context.DeadlineExceeded}
+			if err := retry.Do(ctx, herd, client.Exec); err != nil {
+				return err
+			}
+		}
+		slices.Sort(waits)
+		fmt.Printf("  Jitter %v: retries after %v\n", jitter, waits)
+		if jitter == 0 {
+			check(slices.Equal(waits, []time.Duration{10e6, 10e6, 10e6, 10e6, 10e6}),
+				"all five at 10ms: the server gets them at once, again",
+				"the waits differ without jitter")
+		} else {
+			check(len(slices.Compact(waits)) > 1,
+				"spread between 0 and 10ms: the server gets them one by one",
+				"full jitter gave every client the same wait")
+		}
+	}
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: RETRIES — BACKOFF, JITTER, AND LETTING THE ERROR DECIDE")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  retry.Do(ctx, policy, op)           op until nil, a permanent error, or MaxAttempts
+  retry.Default                       4 attempts, 100ms base, 5s cap, Jitter 0.5
+  Policy{MaxAttempts, BaseDelay, MaxDelay, Jitter, OnRetry}
+  policy.Backoff(n)                   the wait after attempt n, before jitter
+  Retryable() bool                    on an error: "trying again could help"
+  retry.IsRetryable(err)              asks the first Retryable in err's chain`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Retry only what is safe to repeat AND might pass: let the error say so.
+2. An error that doesn't say is not retried; duplicates are worse than failures.
+3. Back off exponentially, cap the wait, and limit the attempts.
+4. Add jitter so clients that failed together don't return together.
+5. Respect the caller's context: stop waiting when they stop waiting.
+6. Return the last error, wrapped, so errors.Is and errors.As still work.
+	`)
+}
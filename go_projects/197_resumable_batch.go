@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/atomicfile"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/batch"
+)
+
+/*
+TOPIC: RESUMABLE BATCH JOBS — CHECKPOINTS, CANCELLATION, AND STARTING AGAIN
+
+CONCEPT:
+Intermediate Topic 88 processes a batch of files in a temp workspace: all
+of it in one go, or none. Fine for five files. For fifty thousand, the run
+that takes an hour gets stopped at minute 50 — Ctrl-C, a deploy, a laptop
+lid — and starting from the top throws the 50 minutes away.
+
+A CHECKPOINT file remembers how far the batch got, item by item:
+
+    {"version": 1, "items": [
+      {"name": "jan.txt", "status": "done",    "attempts": 1},
+      {"name": "feb.txt", "status": "failed",  "attempts": 1, "error": "not UTF-8"},
+      {"name": "mar.txt", "status": "pending"}
+    ]}
+
+and the next run skips what is done. pkg/batch does the bookkeeping:
+
+    p := batch.Processor{Checkpoint: "reports.checkpoint.json", Process: convert}
+    sum, err := p.Run(ctx, names)
+
+THREE THINGS MAKE IT SAFE TO STOP AT ANY MOMENT:
+
+1. The checkpoint is written ATOMICALLY after each item: a temporary file,
+   Sync, rename over the old one (Topic 168). A crash mid-write leaves the
+   previous checkpoint, never half a JSON document.
+
+2. Each item's OUTPUT is written the same way, so an item either produced
+   its file or didn't — no truncated outputs that look finished.
+
+3. Process is safe to REPEAT. Between "item finished" and "checkpoint
+   saved" there is a moment where a crash means doing the item again.
+   Overwriting the same output file with the same content is harmless;
+   appending to a log, or sending an email, is not.
+
+CANCELLATION. The run gets a context (Topic 112). Cancel it and Run stops
+before the next item; an item Process was in the middle of — if it gives
+up with ctx's error — is recorded as pending, not failed, so it runs again.
+FAILED is different: the item ran and broke, and running it again
+unchanged would break again. Those are skipped until someone fixes the
+input and asks for RetryFailed.
+
+RUN:
+    go run 197_resumable_batch.go
+    go test ./pkg/batch
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 1: The Job
+// ---------------------------------------------------------
+// converter turns each input/NAME into output/NAME: the text in upper
+// case with a word count on top. An input that isn't UTF-8 fails. Each
+// output is written atomically, so rerunning an item just replaces it.
+
+type converter struct {
+	dir   string
+	delay time.Duration // Per item, so there is a "middle" to interrupt
+	runs  []string      // Every item Process was called for, in order
+}
+
+func (c *converter) Process(ctx context.Context, name string) error {
+	c.runs = append(c.runs, name)
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return ctx.Err() // Interrupted: batch records the item as pending
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, "input", name))
+	if err != nil {
+		return err
+	}
+	if !utf8.Valid(data) {
+		return errors.New("not UTF-8")
+	}
+	out := fmt.Sprintf("words: %d\n%s", len(strings.Fields(string(data))), strings.ToUpper(string(data)))
+	return atomicfile.WriteFile(filepath.Join(c.dir, "output", name), []byte(out), 0o644)
+}
+
+// outputs lists the files in dir/output.
+func outputs(dir string) []string {
+	entries, _ := os.ReadDir(filepath.Join(dir, "output"))
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "resumable_batch_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	for _, sub := range []string{"input", "output"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+	names := []string{"jan.txt", "feb.txt", "mar.txt", "apr.txt", "may.txt", "jun.txt"}
+	for _, name := range names {
+		text := "monthly report for " + strings.TrimSuffix(name, ".txt") + "\n"
+		if name == "feb.txt" {
+			text = "monthly report \xff\xfe\n" // A bad export
+		}
+		if err := os.WriteFile(filepath.Join(dir, "input", name), []byte(text), 0o644); err != nil {
+			return err
+		}
+	}
+	checkpoint := filepath.Join(dir, "reports.checkpoint.json")
+	conv := &converter{dir: dir, delay: 5 * time.Millisecond}
+	p := batch.Processor{Checkpoint: checkpoint, Process: conv.Process}
+
+	fmt.Println("--- Example 1: Stopped After Three Items ---")
+	// OnItem plays the user pressing Ctrl-C once the third item is saved.
+	ctx, cancel := context.WithCancel(context.Background())
+	seen := 0
+	p.OnItem = func(it batch.Item) {
+		fmt.Printf("    %-8s %s %s\n", it.Name, it.Status, it.Error)
+		if seen++; seen == 3 {
+			fmt.Println("    ^C")
+			cancel()
+		}
+	}
+	sum, err := p.Run(ctx, names)
+	fmt.Printf("    Run: %+v, %v\n", sum, err)
+	check(errors.Is(err, context.Canceled) && sum.Done == 2 && sum.Failed == 1 && sum.Pending == 3,
+		"two done, feb.txt failed, three pending; Run's error is the cancellation",
+		fmt.Sprintf("%+v, %v", sum, err))
+	check(slices.Equal(outputs(dir), []string{"jan.txt", "mar.txt"}),
+		"output/ has exactly the finished items",
+		fmt.Sprintf("output/ has %v", outputs(dir)))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: What Is on Disk ---")
+	data, err := os.ReadFile(checkpoint)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if strings.Contains(line, `"updated"`) { // Timestamps change every run
+			lines[len(lines)-1] = strings.TrimSuffix(lines[len(lines)-1], ",")
+			continue
+		}
+		lines = append(lines, line)
+	}
+	fmt.Println("    " + strings.Join(lines, "\n    "))
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp-*"))
+	check(len(leftovers) == 0,
+		"one checkpoint file, no temporary files beside it",
+		fmt.Sprintf("left behind: %v", leftovers))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Interrupted in the Middle of an Item ---")
+	// A deadline that ends while apr.txt is being converted.
+	conv.runs, conv.delay = nil, 50*time.Millisecond
+	p.OnItem = nil
+	deadline, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	sum, err = p.Run(deadline, names)
+	fmt.Printf("    Run: %+v, %v\n", sum, err)
+	cp, err2 := batch.Load(checkpoint)
+	if err2 != nil {
+		return err2
+	}
+	check(errors.Is(err, context.DeadlineExceeded) && slices.Equal(conv.runs, []string{"apr.txt"}) &&
+		cp.Items[3].Status == batch.Pending && cp.Items[3].Attempts == 1,
+		"apr.txt was started, gave up with ctx's error, and is pending: not failed",
+		fmt.Sprintf("ran %v, apr.txt %+v, %v", conv.runs, cp.Items[3], err))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Resume ---")
+	conv.runs, conv.delay = nil, time.Millisecond
+	sum, err = p.Run(context.Background(), names)
+	fmt.Printf("    Run: %+v, %v\n", sum, err)
+	check(err == nil && slices.Equal(conv.runs, []string{"apr.txt", "may.txt", "jun.txt"}),
+		"only apr.txt, may.txt and jun.txt ran: jan and mar were done, feb failed",
+		fmt.Sprintf("ran %v, %v", conv.runs, err))
+	check(sum.Done == 5 && sum.Failed == 1 && sum.Pending == 0,
+		"five done, one failed, nothing pending",
+		fmt.Sprintf("%+v", sum))
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Fix the Input, Retry the Failure ---")
+	conv.runs = nil
+	if err := os.WriteFile(filepath.Join(dir, "input", "feb.txt"), []byte("monthly report for feb\n"), 0o644); err != nil {
+		return err
+	}
+	if _, err := p.Run(context.Background(), names); err != nil {
+		return err
+	}
+	check(len(conv.runs) == 0,
+		"without RetryFailed, a fixed input is still skipped: nothing ran",
+		fmt.Sprintf("ran %v", conv.runs))
+	p.RetryFailed = true
+	sum, err = p.Run(context.Background(), names)
+	fmt.Printf("    Run: %+v, %v\n", sum, err)
+	cp, _ = batch.Load(checkpoint)
+	check(err == nil && slices.Equal(conv.runs, []string{"feb.txt"}) && sum.Done == 6 && cp.Items[1].Attempts == 2,
+		"RetryFailed ran feb.txt alone, its second attempt; the batch is complete",
+		fmt.Sprintf("ran %v, %+v, %v", conv.runs, sum, err))
+	out, _ := os.ReadFile(filepath.Join(dir, "output", "feb.txt"))
+	fmt.Printf("    output/feb.txt: %q\n", out)
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: RESUMABLE BATCH JOBS — CHECKPOINTS, CANCELLATION, AND STARTING AGAIN")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  p := batch.Processor{Checkpoint: path, Process: fn}
+  p.Run(ctx, names)                   skip done items, run the rest, save after each
+  p.RetryFailed = true                run failed items again too
+  p.OnItem = func(batch.Item)         called after each item is saved
+  batch.Load(path)                    the checkpoint; no file is an empty one
+  Summary{Done, Failed, Pending, Ran, Skipped}`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Record progress per item, so a stopped run loses one item, not all of them.
+2. Write the checkpoint and the outputs atomically: temp file, then rename.
+3. Make each item safe to repeat; a crash can always repeat the last one.
+4. Interrupted is not failed: give up with ctx's error and the item stays pending.
+5. Don't retry failures blindly; fix the cause, then ask for RetryFailed.
+	`)
+}
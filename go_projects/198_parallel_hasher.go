@@ -0,0 +1,454 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*
+TOPIC: A PARALLEL DIRECTORY HASHER — SEMAPHORES, CANCELLATION, PROGRESS
+
+CONCEPT:
+Topic 154's backup tool fingerprints every file with SHA-256, one after
+another. On a big tree that is the slow part, and it leaves most of the
+machine idle: one core hashing while the disk waits, or the disk reading
+while the other cores wait. Hashing files is independent work, so do
+several at once — but not ALL at once:
+
+    for _, f := range files { go hash(f) }   // 100,000 files, 100,000
+                                             // open files: "too many open
+                                             // files", and the disk thrashes
+
+A SEMAPHORE bounds it: a buffered channel with one slot per worker.
+Taking a slot before starting and giving it back when done means at most
+cap(sem) files are open at a time (Topic 115's worker pool does the same
+with a fixed set of goroutines; either shape works).
+
+    sem := make(chan struct{}, workers)
+    sem <- struct{}{}          // blocks while all slots are taken
+    go func() { defer func() { <-sem }(); hash(f) }()
+
+CANCELLATION. Ctrl-C in the middle of a 200 GB tree should stop in
+milliseconds, not after the files already started — some of which are
+4 GB. So the context is checked in three places: before taking a slot,
+before starting each file, and between the 64 KB chunks of each file.
+
+PROGRESS. Workers finish in any order; a single counter of files and
+bytes, under a mutex, feeds one progress bar that redraws itself with
+"\r" at most every 100ms.
+
+THE RESULT is a manifest in the format sha256sum writes, sorted by path,
+so the same tree gives the same file no matter which worker finished
+first:
+
+    3a1f...e9  docs/readme.txt
+    b04c...12  src/main.go
+
+and "sha256sum -c MANIFEST" checks it.
+
+RUN:
+    go run 198_parallel_hasher.go                    (demo and benchmark)
+    go run 198_parallel_hasher.go hash -j 8 DIR      (manifest to stdout, progress to stderr; Ctrl-C stops it)
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 1: Hashing One File, Interruptibly
+// ---------------------------------------------------------
+
+// Entry is one line of the manifest.
+type Entry struct {
+	Path   string // Relative to the root, with forward slashes
+	Size   int64
+	SHA256 string
+}
+
+// chunk is how much is read between looks at the context.
+const chunk = 64 << 10
+
+// hashFile hashes p, giving up between chunks once ctx is done. onBytes
+// is told about each chunk, so progress moves during a big file too.
+func hashFile(ctx context.Context, p string, onBytes func(int)) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	buf := make([]byte, chunk)
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		n, err := f.Read(buf)
+		h.Write(buf[:n])
+		onBytes(n)
+		if err == io.EOF {
+			return hex.EncodeToString(h.Sum(nil)), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// Part 2: The Tree, With a Semaphore
+// ---------------------------------------------------------
+
+// Progress is how far HashTree has got.
+type Progress struct {
+	Files, TotalFiles int
+	Bytes, TotalBytes int64
+}
+
+type Hasher struct {
+	Workers  int            // Files hashed at once; below 1 means 1
+	Progress func(Progress) // If set, called after every chunk, one call at a time
+}
+
+// HashTree hashes every regular file under root and returns the
+// manifest sorted by path. It stops at the first error — or when ctx is
+// done — and returns that error once every worker has returned: nothing
+// keeps running after HashTree does.
+func (h Hasher) HashTree(ctx context.Context, root string) ([]Entry, error) {
+	// First the list, so the progress bar knows the total. Stat is cheap
+	// next to reading every byte.
+	var entries []Entry
+	var total int64
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		entries = append(entries, Entry{Path: filepath.ToSlash(rel), Size: info.Size()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The first failure cancels the rest.
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var mu sync.Mutex
+	prog := Progress{TotalFiles: len(entries), TotalBytes: total}
+	report := func(bytes int64, files int) {
+		mu.Lock()
+		defer mu.Unlock()
+		prog.Bytes += bytes
+		prog.Files += files
+		if h.Progress != nil {
+			h.Progress(prog)
+		}
+	}
+
+	sem := make(chan struct{}, max(h.Workers, 1))
+	var wg sync.WaitGroup
+feed:
+	for i := range entries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break feed // Don't wait for a slot we'd never use
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			e := &entries[i] // Each goroutine writes only its own entry
+			sum, err := hashFile(ctx, filepath.Join(root, filepath.FromSlash(e.Path)),
+				func(n int) { report(int64(n), 0) })
+			if err != nil {
+				cancel(fmt.Errorf("%s: %w", e.Path, err))
+				return
+			}
+			e.SHA256 = sum
+			report(0, 1)
+		}()
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Path, b.Path) })
+	return entries, nil
+}
+
+// WriteManifest writes entries as sha256sum does: hash, two spaces, path.
+func WriteManifest(w io.Writer, entries []Entry) error {
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "%s  %s\n", e.SHA256, e.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 3: The Progress Bar
+// ---------------------------------------------------------
+// A bar redraws the same terminal line: "\r" moves the cursor back to
+// column 0 without a new line. Redrawing on every chunk would spend more
+// time printing than hashing, so it draws at most every interval, and
+// always at 100%.
+
+type bar struct {
+	w        io.Writer
+	width    int
+	interval time.Duration
+	last     time.Time
+	start    time.Time
+}
+
+func newBar(w io.Writer) *bar {
+	return &bar{w: w, width: 30, interval: 100 * time.Millisecond, start: time.Now()}
+}
+
+func (b *bar) update(p Progress) {
+	done := p.Bytes == p.TotalBytes && p.Files == p.TotalFiles
+	if !done && time.Since(b.last) < b.interval {
+		return
+	}
+	b.last = time.Now()
+	frac := 1.0
+	if p.TotalBytes > 0 {
+		frac = float64(p.Bytes) / float64(p.TotalBytes)
+	}
+	filled := int(frac * float64(b.width))
+	mbps := float64(p.Bytes) / (1 << 20) / max(time.Since(b.start).Seconds(), 1e-3)
+	fmt.Fprintf(b.w, "\r  [%s%s] %3.0f%%  %d/%d files  %.1f MB/s", strings.Repeat("█", filled),
+		strings.Repeat("░", b.width-filled), frac*100, p.Files, p.TotalFiles, mbps)
+	if done {
+		fmt.Fprintln(b.w)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 4: A Synthetic Tree
+// ---------------------------------------------------------
+
+// makeTree writes files files of size bytes each into dirs
+// subdirectories of root. The content comes from a seeded generator:
+// the same arguments give the same tree, and so the same manifest.
+func makeTree(root string, dirs, files, size int) error {
+	rng := rand.New(rand.NewPCG(1, 2))
+	buf := make([]byte, size)
+	for i := range files {
+		dir := filepath.Join(root, fmt.Sprintf("dir%02d", i%dirs))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		for j := range buf {
+			buf[j] = byte(rng.Uint32())
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%04d.bin", i)), buf, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 5: The Command
+// ---------------------------------------------------------
+
+func cmdHash(args []string) error {
+	flags := flag.NewFlagSet("hash", flag.ExitOnError)
+	workers := flags.Int("j", runtime.NumCPU(), "files to hash at once")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("usage: hash [-j N] DIR")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	h := Hasher{Workers: *workers, Progress: newBar(os.Stderr).update}
+	entries, err := h.HashTree(ctx, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr)
+		return err
+	}
+	return WriteManifest(os.Stdout, entries)
+}
+
+// ---------------------------------------------------------
+// Demo
+// ---------------------------------------------------------
+
+func demo() error {
+	root, err := os.MkdirTemp("", "hasher_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(root)
+	const files, size = 240, 128 << 10
+	if err := makeTree(root, 12, files, size); err != nil {
+		return err
+	}
+	fmt.Printf("  synthetic tree: %d files of %d KB in 12 directories (%d MB)\n\n", files, size>>10, files*size>>20)
+	ctx := context.Background()
+
+	fmt.Println("--- Example 1: Hash the Tree, 4 at a Time ---")
+	// The bar, plus a count of goroutines on each update: with the
+	// semaphore there are never more than 4 hashing at once.
+	b := newBar(os.Stdout)
+	baseline, peak := runtime.NumGoroutine(), 0
+	h := Hasher{Workers: 4, Progress: func(p Progress) {
+		peak = max(peak, runtime.NumGoroutine()-baseline)
+		b.update(p)
+	}}
+	entries, err := h.HashTree(ctx, root)
+	if err != nil {
+		return err
+	}
+	var manifest strings.Builder
+	WriteManifest(&manifest, entries)
+	lines := strings.SplitAfter(manifest.String(), "\n")
+	fmt.Print("  ", lines[0], "  ", lines[1], "  ...\n")
+	seq, err := Hasher{Workers: 1}.HashTree(ctx, root)
+	if err != nil {
+		return err
+	}
+	check(peak <= 4,
+		fmt.Sprintf("at most %d files in progress at once: the semaphore held", peak),
+		fmt.Sprintf("%d files in progress at once with 4 workers", peak))
+	check(len(entries) == files && slices.Equal(entries, seq),
+		"240 entries, sorted by path, identical to hashing one file at a time",
+		fmt.Sprintf("%d entries; same as sequential: %v", len(entries), slices.Equal(entries, seq)))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Ctrl-C Half Way ---")
+	// The progress callback plays the user: cancel once half the bytes
+	// are hashed.
+	ctx2, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var stoppedAt Progress
+	half := Hasher{Workers: 4, Progress: func(p Progress) {
+		if p.Bytes >= p.TotalBytes/2 && stoppedAt.Files == 0 {
+			stoppedAt = p
+			cancel()
+		}
+	}}
+	before := runtime.NumGoroutine()
+	start := time.Now()
+	_, err = half.HashTree(ctx2, root)
+	fmt.Printf("    HashTree: %v after %v, at %d/%d files\n", err, time.Since(start).Round(time.Millisecond), stoppedAt.Files, stoppedAt.TotalFiles)
+	check(errors.Is(err, context.Canceled),
+		"the error is context.Canceled, and no half-finished manifest is returned",
+		fmt.Sprintf("err = %v", err))
+	check(runtime.NumGoroutine() <= before,
+		"every worker has returned: nothing is still reading after HashTree does",
+		fmt.Sprintf("%d goroutines before, %d after", before, runtime.NumGoroutine()))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: A File Vanishes Mid-Run ---")
+	// Listed by the walk, deleted before a worker gets to it: the last
+	// file, removed as soon as the first chunk is hashed.
+	last := filepath.Join(root, "dir11", "file0239.bin")
+	saved, err := os.ReadFile(last)
+	if err != nil {
+		return err
+	}
+	var once sync.Once
+	vanish := Hasher{Workers: 4, Progress: func(Progress) { once.Do(func() { os.Remove(last) }) }}
+	_, err = vanish.HashTree(ctx, root)
+	fmt.Printf("    HashTree: %v\n", err)
+	check(errors.Is(err, fs.ErrNotExist) && strings.HasPrefix(err.Error(), "dir11/file0239.bin:"),
+		"the first error, naming the file, cancels the other workers and is what HashTree returns",
+		fmt.Sprintf("err = %v", err))
+	if err := os.WriteFile(last, saved, 0o644); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Benchmark — 1, 4 and NumCPU Workers ---")
+	counts := []int{1, 4, runtime.NumCPU()}
+	slices.Sort(counts)
+	counts = slices.Compact(counts)
+	var base float64
+	for _, n := range counts {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.SetBytes(int64(files * size))
+			for b.Loop() {
+				if _, err := (Hasher{Workers: n}).HashTree(ctx, root); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		perOp := time.Duration(r.NsPerOp())
+		if base == 0 {
+			base = float64(perOp)
+		}
+		mbps := float64(r.Bytes) * float64(r.N) / r.T.Seconds() / (1 << 20)
+		fmt.Printf("  %2d workers: %8v per tree  %7.1f MB/s  %.1fx\n", n, perOp.Round(10*time.Microsecond), mbps, base/float64(perOp))
+	}
+	fmt.Printf("  (%d CPUs. Past the CPU count, or once the disk is the limit, more\n   workers stop helping: the files are in the page cache here, so it is CPU.)\n", runtime.NumCPU())
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "hash" {
+		if err := cmdHash(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "hash:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: A PARALLEL DIRECTORY HASHER — SEMAPHORES, CANCELLATION, PROGRESS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Bound concurrency with a semaphore: a buffered channel, one slot per worker.
+2. Check the context before each file AND inside big ones, between chunks.
+3. Let the first error cancel the rest (context.WithCancelCause), then wait for all.
+4. Collect results by index and sort, so the output doesn't depend on timing.
+5. Redraw progress with "\r", throttled; printing can cost more than the work.
+6. Measure: more workers than CPUs, or than the disk can feed, buys nothing.
+	`)
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+TOPIC: MERKLE TREES — ONE HASH FOR A WHOLE DIRECTORY, AND PROOF FOR ONE FILE
+
+CONCEPT:
+Intermediate Topic 82 hashes one file; Topic 198 hashes a whole tree
+(intermediate Topic 87) into a manifest, one line per file. To check a
+single downloaded file against that manifest you need the manifest — all
+of it, 100,000 lines for 100,000 files — and you need to trust it.
+
+A MERKLE TREE folds the manifest into ONE hash. The file hashes are the
+leaves; each pair of nodes is hashed together into its parent; the last
+hash standing is the ROOT:
+
+                      root = H(1 ‖ n01 ‖ n23)
+                     /                       \
+          n01 = H(1 ‖ l0 ‖ l1)         n23 = H(1 ‖ l2 ‖ l3)
+            /          \                 /          \
+          l0            l1             l2            l3
+     H(0 ‖ path ‖ 0 ‖ sha256(file)) ...
+
+Publish the 32-byte root (sign it, print it on the release page) and
+anyone can check any single file with a PROOF: the sibling hashes on the
+way up. To check l2 you need l3 and n01 — recompute n23, then the root,
+and compare. That is log2(N) hashes: 17 for 100,000 files, not 100,000.
+
+THREE DETAILS THAT MATTER:
+
+1. The leaf covers the PATH as well as the content. Otherwise a proof
+   for docs/faq.md would also "prove" an identical file at any other path.
+
+2. Leaves and inner nodes are hashed with different first bytes, 0 and 1
+   (as Certificate Transparency, RFC 6962, does). Without that, the 64
+   bytes of an inner node could be passed off as a leaf.
+
+3. An odd node at the end of a level moves up unchanged. Copying it to
+   make a pair (as Bitcoin does) lets two different lists of files have
+   the same root.
+
+The leaves here come from the same streaming hash as Topic 198: io.Copy
+into sha256.New(), 32 KB at a time, so a 4 GB file costs 32 KB of memory.
+
+RUN:
+    go run 199_merkle_tree.go                                  (demo)
+    go run 199_merkle_tree.go root DIR                         (the root hash)
+    go run 199_merkle_tree.go prove DIR PATH > proof.json      (the proof for one file)
+    go run 199_merkle_tree.go verify ROOT proof.json FILE      (check a file on its own)
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 1: Hashes and Nodes
+// ---------------------------------------------------------
+
+// Hash is a SHA-256 sum. It is written as hex, in JSON too.
+type Hash [sha256.Size]byte
+
+func (h Hash) String() string { return hex.EncodeToString(h[:]) }
+
+func (h Hash) MarshalText() ([]byte, error) { return []byte(h.String()), nil }
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	if len(text) != 2*len(h) {
+		return fmt.Errorf("hash %.16q: want %d hex digits", text, 2*len(h))
+	}
+	_, err := hex.Decode(h[:], text)
+	return err
+}
+
+// The first byte of everything hashed into the tree says what it is.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// leafHash is the leaf for the file at path with content hash file. A
+// path can't contain a NUL byte, so the 0 after it can't be part of it.
+func leafHash(path string, file Hash) Hash {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(file[:])
+	var sum Hash
+	h.Sum(sum[:0])
+	return sum
+}
+
+// nodeHash is the parent of left and right.
+func nodeHash(left, right Hash) Hash {
+	var buf [1 + 2*sha256.Size]byte
+	buf[0] = nodePrefix
+	copy(buf[1:], left[:])
+	copy(buf[1+sha256.Size:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+// hashFile is the SHA-256 of the file at p, read in chunks: the file is
+// never in memory all at once.
+func hashFile(p string) (Hash, error) {
+	var sum Hash
+	f, err := os.Open(p)
+	if err != nil {
+		return sum, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+	h.Sum(sum[:0])
+	return sum, nil
+}
+
+// ---------------------------------------------------------
+// Part 2: The Tree
+// ---------------------------------------------------------
+
+// Leaf is one file: its path, relative to the root with forward
+// slashes, and the SHA-256 of its content.
+type Leaf struct {
+	Path string
+	File Hash
+}
+
+// Tree is every level of a Merkle tree, kept so proofs can be read off it.
+type Tree struct {
+	leaves []Leaf   // Sorted by path
+	levels [][]Hash // levels[0] are the leaf hashes; the last level is the root alone
+}
+
+// Build makes the tree for leaves, in path order: the same files give
+// the same root whatever order they were found in.
+func Build(leaves []Leaf) *Tree {
+	leaves = slices.Clone(leaves)
+	slices.SortFunc(leaves, func(a, b Leaf) int { return strings.Compare(a.Path, b.Path) })
+	level := make([]Hash, len(leaves))
+	for i, l := range leaves {
+		level[i] = leafHash(l.Path, l.File)
+	}
+	t := &Tree{leaves: leaves, levels: [][]Hash{level}}
+	for len(level) > 1 {
+		next := make([]Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i]) // The odd one out moves up as it is
+				continue
+			}
+			next = append(next, nodeHash(level[i], level[i+1]))
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t
+}
+
+// Root is the hash of the whole tree. An empty tree's is the hash of
+// nothing.
+func (t *Tree) Root() Hash {
+	if len(t.leaves) == 0 {
+		return sha256.Sum256(nil)
+	}
+	return t.levels[len(t.levels)-1][0]
+}
+
+// BuildDir hashes every regular file under dir and builds their tree.
+func BuildDir(dir string) (*Tree, error) {
+	var leaves []Leaf
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		sum, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		leaves = append(leaves, Leaf{Path: filepath.ToSlash(rel), File: sum})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Build(leaves), nil
+}
+
+// ---------------------------------------------------------
+// Part 3: Proofs
+// ---------------------------------------------------------
+
+// Step is one sibling on the way from a leaf to the root.
+type Step struct {
+	Hash Hash `json:"hash"`
+	Left bool `json:"left,omitempty"` // The sibling is on the left: H(1 ‖ Hash ‖ ours)
+}
+
+// Proof says that a file with content hash File sits at Path in the
+// tree with some root.
+type Proof struct {
+	Path  string `json:"path"`
+	File  Hash   `json:"file"`
+	Steps []Step `json:"steps"`
+}
+
+// Prove returns the proof for the file at path.
+func (t *Tree) Prove(path string) (Proof, error) {
+	i, ok := slices.BinarySearchFunc(t.leaves, path, func(l Leaf, p string) int { return strings.Compare(l.Path, p) })
+	if !ok {
+		return Proof{}, fmt.Errorf("%s: not in the tree", path)
+	}
+	p := Proof{Path: path, File: t.leaves[i].File}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		// A node's sibling is its neighbour in the pair: i^1. The odd
+		// one out has none, and moves up without a step.
+		if sib := i ^ 1; sib < len(level) {
+			p.Steps = append(p.Steps, Step{Hash: level[sib], Left: sib < i})
+		}
+		i /= 2
+	}
+	return p, nil
+}
+
+// Root is the root p leads to: the leaf, hashed with each step in turn.
+func (p Proof) Root() Hash {
+	h := leafHash(p.Path, p.File)
+	for _, s := range p.Steps {
+		if s.Left {
+			h = nodeHash(s.Hash, h)
+		} else {
+			h = nodeHash(h, s.Hash)
+		}
+	}
+	return h
+}
+
+var (
+	ErrContent = errors.New("content doesn't match the proof")
+	ErrRoot    = errors.New("proof doesn't lead to the root")
+)
+
+// VerifyFile checks the file at name, on its own, against a root: its
+// content must hash to p.File, and p must lead to root.
+func VerifyFile(root Hash, p Proof, name string) error {
+	sum, err := hashFile(name)
+	if err != nil {
+		return err
+	}
+	if sum != p.File {
+		return fmt.Errorf("%s: %w: sha256 %.12s…, proof has %.12s…", name, ErrContent, sum, p.File)
+	}
+	if p.Root() != root {
+		return fmt.Errorf("%s as %s: %w %.12s…", name, p.Path, ErrRoot, root)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Part 4: The Commands
+// ---------------------------------------------------------
+
+func cmdRoot(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: root DIR")
+	}
+	t, err := BuildDir(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(t.Root())
+	return nil
+}
+
+func cmdProve(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: prove DIR PATH")
+	}
+	t, err := BuildDir(args[0])
+	if err != nil {
+		return err
+	}
+	p, err := t.Prove(filepath.ToSlash(args[1]))
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+func cmdVerify(args []string) error {
+	if len(args) != 3 {
+		return errors.New("usage: verify ROOT PROOF.json FILE")
+	}
+	var root Hash
+	if err := root.UnmarshalText([]byte(args[0])); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+	var p Proof
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("%s: %v", args[1], err)
+	}
+	if err := VerifyFile(root, p, args[2]); err != nil {
+		return err
+	}
+	fmt.Printf("OK: %s is %s under root %.12s…\n", args[2], p.Path, root)
+	return nil
+}
+
+// ---------------------------------------------------------
+// Demo
+// ---------------------------------------------------------
+
+// project is the tree the demo builds: nine files, so every level but
+// the top has an odd one out, and two files with the same content.
+var project = map[string]string{
+	"LICENSE":          "MIT License\n",
+	"README.md":        "# gizmo\nA tool that does one thing.\n",
+	"go.mod":           "module example.com/gizmo\n\ngo 1.24\n",
+	"assets/logo.svg":  "<svg xmlns=\"http://www.w3.org/2000/svg\"/>\n",
+	"docs/faq.md":      "Q: Why?\nA: Because.\n",
+	"docs/faq-copy.md": "Q: Why?\nA: Because.\n",
+	"docs/guide.md":    "Run it.\n",
+	"src/main.go":      "package main\n\nfunc main() { run() }\n",
+	"src/run.go":       "package main\n\nfunc run() {}\n",
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	for name, text := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func demo() error {
+	dir, err := os.MkdirTemp("", "merkle_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "gizmo")
+	if err := writeFiles(src, project); err != nil {
+		return err
+	}
+
+	fmt.Println("--- Example 1: One Hash for the Directory ---")
+	t, err := BuildDir(src)
+	if err != nil {
+		return err
+	}
+	for i := len(t.levels) - 1; i >= 0; i-- {
+		fmt.Printf("    level %d:", i)
+		for _, h := range t.levels[i] {
+			fmt.Printf(" %.6s", h)
+		}
+		fmt.Println()
+	}
+	root := t.Root()
+	fmt.Println("    root:", root)
+	shuffled := slices.Clone(t.leaves)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	check(Build(shuffled).Root() == root,
+		"the same files in another order give the same root: Build sorts by path",
+		"the root depends on the order the files were found in")
+	readme, _ := os.ReadFile(filepath.Join(src, "README.md"))
+	check(t.leaves[1].Path == "README.md" && t.leaves[1].File == sha256.Sum256(readme),
+		"a leaf's file hash is what sha256sum prints for the file",
+		fmt.Sprintf("leaf 1 is %s %s", t.leaves[1].Path, t.leaves[1].File))
+	fmt.Println()
+
+	fmt.Println("--- Example 2: A Proof for One File ---")
+	p, err := t.Prove("src/main.go")
+	if err != nil {
+		return err
+	}
+	data, _ := json.MarshalIndent(p, "    ", "  ")
+	fmt.Println("    " + string(data))
+	check(len(p.Steps) == 4 && p.Root() == root,
+		"4 sibling hashes lead from src/main.go's leaf to the root",
+		fmt.Sprintf("%d steps lead to %.12s…, not the root", len(p.Steps), p.Root()))
+	_, err = t.Prove("src/missing.go")
+	check(err != nil, "there is no proof for a file that isn't in the tree: "+fmt.Sprint(err), "Prove made up a proof")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Checking a Single Download ---")
+	// Someone has only main.go, the proof and the published root.
+	dl := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(dl, []byte(project["src/main.go"]), 0o644); err != nil {
+		return err
+	}
+	err = VerifyFile(root, p, dl)
+	check(err == nil, "main.go, its proof and the root agree, with no other file in sight", fmt.Sprint(err))
+	os.WriteFile(dl, []byte("package main\n\nfunc main() { evil() }\n"), 0o644)
+	err = VerifyFile(root, p, dl)
+	fmt.Println("    after an edit:", err)
+	check(errors.Is(err, ErrContent), "one changed line and the file no longer matches its proof", fmt.Sprint(err))
+
+	// faq-copy.md has faq.md's content; the path is in the leaf, so a
+	// proof for one isn't a proof for the other.
+	faq, _ := t.Prove("docs/faq.md")
+	copyProof := faq
+	copyProof.Path = "docs/faq-copy.md"
+	check(faq.Root() == root && copyProof.Root() != root,
+		"the same content under another path doesn't verify: the leaf covers the path",
+		"faq.md's proof also proves faq-copy.md")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Any Change Moves the Root ---")
+	if err := os.WriteFile(filepath.Join(src, "LICENSE"), []byte("MIT License.\n"), 0o644); err != nil {
+		return err
+	}
+	t2, err := BuildDir(src)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("    root before: %.16s…\n    root after:  %.16s…\n", root, t2.Root())
+	os.WriteFile(dl, []byte(project["src/main.go"]), 0o644)
+	p2, _ := t2.Prove("src/main.go")
+	check(t2.Root() != root && VerifyFile(t2.Root(), p, dl) != nil && VerifyFile(t2.Root(), p2, dl) == nil,
+		"one byte in LICENSE gives a new root; main.go needs a new proof for it, from the new tree",
+		"the root didn't change, or the old proof still works")
+	fmt.Println()
+
+	fmt.Println("--- Example 5: Proofs Stay Small ---")
+	// Made-up file hashes: building the tree is what is being measured.
+	const n = 100_000
+	leaves := make([]Leaf, n)
+	for i := range leaves {
+		leaves[i].Path = fmt.Sprintf("data/%06d.bin", i)
+		leaves[i].File = sha256.Sum256([]byte(leaves[i].Path))
+	}
+	start := time.Now()
+	big := Build(leaves)
+	built := time.Since(start)
+	bp, _ := big.Prove("data/031337.bin")
+	proofJSON, _ := json.Marshal(bp)
+	manifest := 0
+	for _, l := range leaves {
+		manifest += len(fmt.Sprintf("%s  %s\n", l.File, l.Path))
+	}
+	fmt.Printf("    %d files: built in %v, %d levels\n", n, built.Round(time.Millisecond), len(big.levels))
+	fmt.Printf("    manifest: %d KB; one proof: %d steps, %d bytes of JSON\n", manifest>>10, len(bp.Steps), len(proofJSON))
+	check(len(bp.Steps) == 17 && bp.Root() == big.Root(),
+		"17 hashes prove one file out of 100,000: log2(N), not N",
+		fmt.Sprintf("%d steps", len(bp.Steps)))
+	return nil
+}
+
+func main() {
+	commands := map[string]func([]string) error{"root": cmdRoot, "prove": cmdProve, "verify": cmdVerify}
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: MERKLE TREES — ONE HASH FOR A WHOLE DIRECTORY, AND PROOF FOR ONE FILE")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  leaf  = H(0 ‖ path ‖ 0 ‖ sha256(content))
+  node  = H(1 ‖ left ‖ right)          an odd node moves up unchanged
+  root  = the last node                publish this
+  proof = the sibling on each level    log2(N) hashes
+  check: hash the file, fold in each sibling (left or right), compare to root`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A Merkle root is one hash that changes if any file, or any path, changes.
+2. A proof is the siblings on the way up: one file checks in log2(N) hashes.
+3. Put the path in the leaf, or identical files can stand in for each other.
+4. Prefix leaves and nodes differently, so a node can't pose as a leaf.
+5. Sort the leaves first: the root must not depend on the order of a directory walk.
+6. A proof belongs to one root; after a change, prove again from the new tree.
+	`)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/fileops"
+)
+
+// ---------------------------------------------------------
+// Part 3: Carrying the Plan Out
+// ---------------------------------------------------------
+// Every file is copied to a temporary name beside its destination and
+// renamed into place (Topic 168), so a sync that is stopped half way
+// leaves each file either old or new — never half copied. The next run
+// sees what is still different and finishes the job.
+
+// Syncer makes one directory tree a copy of another.
+type Syncer struct {
+	Delete bool      // Remove what the destination has and the source doesn't
+	DryRun bool      // Plan and describe, change nothing
+	Log    io.Writer // Each action is described here, with "would " in a dry run; nil = silent
+}
+
+// ErrOverlap is returned for a destination inside the source or the
+// other way round: the sync would copy or delete its own input.
+var ErrOverlap = errors.New("source and destination overlap")
+
+// Sync scans both trees, plans, and carries the plan out. The plan is
+// returned in a dry run too, as what would have been done.
+func (s Syncer) Sync(src, dst string) (Plan, error) {
+	if err := overlap(src, dst); err != nil {
+		return Plan{}, err
+	}
+	from, err := Scan(src)
+	if err != nil {
+		return Plan{}, err
+	}
+	to, err := Scan(dst)
+	if errors.Is(err, fs.ErrNotExist) {
+		to, err = nil, nil // Everything is new
+	}
+	if err != nil {
+		return Plan{}, err
+	}
+	plan := Diff(from, to, s.Delete)
+	return plan, s.Apply(plan, src, dst)
+}
+
+// Apply carries out plan, stopping at the first error. Nothing is
+// written in a dry run.
+func (s Syncer) Apply(plan Plan, src, dst string) error {
+	for _, a := range plan.Actions {
+		if s.Log != nil {
+			verb := ""
+			if s.DryRun {
+				verb = "would "
+			}
+			fmt.Fprintf(s.Log, "%s%s\n", verb, a)
+		}
+		if s.DryRun {
+			continue
+		}
+		to := filepath.Join(dst, filepath.FromSlash(a.Path))
+		var err error
+		switch a.Op {
+		case Mkdir:
+			err = os.MkdirAll(to, 0o755)
+		case Copy:
+			err = copyFile(filepath.Join(src, filepath.FromSlash(a.Path)), to)
+		case Delete:
+			// The rails are for a plan gone wrong: a Path that climbs out
+			// of dst, or a directory in it that became a symlink.
+			err = fileops.SafeRemoveAll(to, fileops.SafeOptions{Root: dst})
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", a.Op, a.Path, err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies from to to through a temporary file and a rename,
+// keeping from's permissions and modification time.
+func copyFile(from, to string) (err error) {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(to), ".sync-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := io.Copy(tmp, in); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), to)
+}
+
+// overlap reports ErrOverlap if either directory is, or is inside, the
+// other. Both are compared with their symlinks resolved: a dst that is a
+// link to src/docs is inside src however it is spelt.
+func overlap(src, dst string) error {
+	a, err := realPath(src)
+	if err != nil {
+		return err
+	}
+	b, err := realPath(dst)
+	if err != nil {
+		return err
+	}
+	inside := func(dir, p string) bool {
+		return p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+	}
+	if inside(a, b) || inside(b, a) {
+		return fmt.Errorf("%s and %s: %w", src, dst, ErrOverlap)
+	}
+	return nil
+}
+
+// realPath is p made absolute with its symlinks resolved. A destination
+// may not exist yet, so the part of p that doesn't is kept as it is,
+// under its nearest parent that does.
+func realPath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return resolved, err
+	}
+	parent := filepath.Dir(abs)
+	if parent == abs {
+		return "", err // Not even the root exists
+	}
+	if parent, err = realPath(parent); err != nil {
+		return "", err
+	}
+	return filepath.Join(parent, filepath.Base(abs)), nil
+}
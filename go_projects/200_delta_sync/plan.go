@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"path"
+)
+
+// ---------------------------------------------------------
+// Part 2: The Plan — Comparing Two Trees
+// ---------------------------------------------------------
+// Diff walks the source and destination trees side by side. Where two
+// directory hashes match, nothing below can differ and the walk goes no
+// further; only the path down to a change is compared name by name.
+
+type Op string
+
+const (
+	Mkdir  Op = "mkdir"
+	Copy   Op = "copy"
+	Delete Op = "delete"
+)
+
+// Action is one step of a sync. Path is relative to the roots, with
+// forward slashes.
+type Action struct {
+	Op    Op
+	Path  string
+	Dir   bool   // Delete: the path is a directory, removed with everything in it
+	Size  int64  // Copy: bytes to copy; Delete: bytes removed
+	Files int    // Delete: files removed
+	Why   string // "new", "changed", "not in source", ...
+}
+
+func (a Action) String() string {
+	switch {
+	case a.Op == Copy:
+		return fmt.Sprintf("copy   %s (%s, %d B)", a.Path, a.Why, a.Size)
+	case a.Op == Delete && a.Dir:
+		return fmt.Sprintf("delete %s/ (%s, %d files, %d B)", a.Path, a.Why, a.Files, a.Size)
+	case a.Op == Delete:
+		return fmt.Sprintf("delete %s (%s, %d B)", a.Path, a.Why, a.Size)
+	}
+	if a.Path == "." {
+		return fmt.Sprintf("%-6s the destination", a.Op)
+	}
+	return fmt.Sprintf("%-6s %s/", a.Op, a.Path)
+}
+
+// Plan is what a sync will do, in the order to do it: a directory is
+// made before what goes in it, and a path is deleted before something
+// else is copied to it.
+type Plan struct {
+	Actions   []Action
+	Unchanged int // Source files the destination already has
+	Pruned    int // Directories skipped whole because their hashes matched
+	Extra     int // Destination files not in the source, kept: no --delete
+}
+
+// Bytes is how much the plan copies.
+func (p Plan) Bytes() int64 {
+	var n int64
+	for _, a := range p.Actions {
+		if a.Op == Copy {
+			n += a.Size
+		}
+	}
+	return n
+}
+
+// Diff plans how to make dst look like src. dst is nil when the
+// destination doesn't exist yet. With del, what dst has and src doesn't
+// is deleted; without it, it is counted in Extra and left alone. A path
+// that is a file on one side and a directory on the other is replaced
+// either way: the source's version can't be copied otherwise.
+func Diff(src, dst *Node, del bool) Plan {
+	var p Plan
+	if dst == nil {
+		p.Actions = append(p.Actions, Action{Op: Mkdir, Path: "."})
+	}
+	p.dir("", src, dst, del)
+	return p
+}
+
+func (p *Plan) dir(prefix string, src, dst *Node, del bool) {
+	if dst != nil && src.Hash == dst.Hash {
+		p.Unchanged += src.Files
+		p.Pruned++
+		return
+	}
+	if dst != nil {
+		for _, d := range dst.Children {
+			if src.child(d.Name) != nil {
+				continue
+			}
+			if !del {
+				p.Extra += d.Files
+				continue
+			}
+			p.Actions = append(p.Actions, Action{Op: Delete, Path: path.Join(prefix, d.Name), Dir: d.Dir,
+				Size: d.Size, Files: d.Files, Why: "not in source"})
+		}
+	}
+	for _, s := range src.Children {
+		name := path.Join(prefix, s.Name)
+		d := dst.child(s.Name)
+		if d != nil && d.Dir != s.Dir {
+			why := "now a file"
+			if s.Dir {
+				why = "now a directory"
+			}
+			p.Actions = append(p.Actions, Action{Op: Delete, Path: name, Dir: d.Dir, Size: d.Size, Files: d.Files, Why: why})
+			d = nil
+		}
+		switch {
+		case s.Dir:
+			if d == nil {
+				p.Actions = append(p.Actions, Action{Op: Mkdir, Path: name})
+			}
+			p.dir(name, s, d, del)
+		case d == nil:
+			p.Actions = append(p.Actions, Action{Op: Copy, Path: name, Size: s.Size, Why: "new"})
+		case d.Hash != s.Hash:
+			p.Actions = append(p.Actions, Action{Op: Copy, Path: name, Size: s.Size, Why: "changed"})
+		default:
+			p.Unchanged++
+		}
+	}
+}
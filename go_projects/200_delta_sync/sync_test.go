@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// synced returns a source tree and a destination already synced to it.
+func synced(t *testing.T) (src, dst string) {
+	t.Helper()
+	dir := t.TempDir()
+	src, dst = filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	if err := makeTree(src, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Syncer{}).Sync(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	return src, dst
+}
+
+// actions is the plan as "op path" strings.
+func actions(p Plan) []string {
+	var out []string
+	for _, a := range p.Actions {
+		out = append(out, string(a.Op)+" "+a.Path)
+	}
+	return out
+}
+
+func write(t *testing.T, root, name, text string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFirstSyncThenNothing(t *testing.T) {
+	src, dst := synced(t)
+	if !same(src, dst) {
+		t.Fatal("after the first sync the roots differ")
+	}
+	plan, err := Syncer{Delete: true}.Sync(src, dst)
+	if err != nil || len(plan.Actions) != 0 || plan.Unchanged != 30 || plan.Pruned != 1 {
+		t.Errorf("second sync: %v, %+v; want nothing to do, found at the root", err, plan)
+	}
+}
+
+func TestOnlyChangesAreCopied(t *testing.T) {
+	src, dst := synced(t)
+	write(t, src, "src/lib/f2.dat", "edited")
+	write(t, src, "docs/new/guide.md", "new")
+	os.Remove(filepath.Join(src, "assets", "img", "f1.dat"))
+
+	plan, err := Syncer{}.Sync(src, dst)
+	want := []string{"copy src/lib/f2.dat", "mkdir docs/new", "copy docs/new/guide.md"}
+	slices.Sort(want)
+	got := actions(plan)
+	slices.Sort(got)
+	if err != nil || !slices.Equal(got, want) {
+		t.Fatalf("plan %v, %v; want %v", got, err, want)
+	}
+	// assets/css and src/app matched by hash and weren't looked into.
+	if plan.Pruned != 2 || plan.Extra != 1 || plan.Unchanged != 28 {
+		t.Errorf("pruned %d, extra %d, unchanged %d; want 2, 1, 28", plan.Pruned, plan.Extra, plan.Unchanged)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "assets", "img", "f1.dat")); err != nil {
+		t.Errorf("without --delete the extra file should stay: %v", err)
+	}
+
+	plan, err = Syncer{Delete: true}.Sync(src, dst)
+	if err != nil || !slices.Equal(actions(plan), []string{"delete assets/img/f1.dat"}) || !same(src, dst) {
+		t.Errorf("with --delete: %v, %v", actions(plan), err)
+	}
+}
+
+func TestDryRunChangesNothing(t *testing.T) {
+	src, dst := synced(t)
+	write(t, src, "docs/f0.dat", "edited")
+	os.RemoveAll(filepath.Join(src, "src", "app"))
+	before := snapshot(dst)
+	var log strings.Builder
+	plan, err := Syncer{Delete: true, DryRun: true, Log: &log}.Sync(src, dst)
+	if err != nil || len(plan.Actions) != 2 {
+		t.Fatalf("dry run: %v, %v", actions(plan), err)
+	}
+	if !maps.Equal(before, snapshot(dst)) {
+		t.Error("a dry run changed the destination")
+	}
+	want := "would copy   docs/f0.dat (changed, 6 B)\nwould delete src/app/ (not in source, 6 files,"
+	if !strings.HasPrefix(log.String(), want) {
+		t.Errorf("log:\n%s\nwant it to start\n%s", log.String(), want)
+	}
+
+	// A dry run into a destination that doesn't exist yet plans all of it.
+	fresh := filepath.Join(t.TempDir(), "fresh")
+	plan, err = Syncer{DryRun: true}.Sync(src, fresh)
+	if err != nil || plan.Actions[0] != (Action{Op: Mkdir, Path: "."}) {
+		t.Errorf("dry run to a new destination: %v, %v", actions(plan), err)
+	}
+	if _, err := os.Stat(fresh); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the dry run created the destination: %v", err)
+	}
+}
+
+// A path whose type changed is replaced, with or without --delete.
+func TestTypeChanges(t *testing.T) {
+	src, dst := synced(t)
+	os.Remove(filepath.Join(src, "docs", "f0.dat"))
+	write(t, src, "docs/f0.dat/inner", "now a directory")
+	os.RemoveAll(filepath.Join(src, "src", "app"))
+	write(t, src, "src/app", "now a file")
+
+	plan, err := Syncer{}.Sync(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(actions(plan), ", ")
+	want := "delete docs/f0.dat, mkdir docs/f0.dat, copy docs/f0.dat/inner, delete src/app, copy src/app"
+	if got != want {
+		t.Errorf("plan\n %s\nwant\n %s", got, want)
+	}
+	if !same(src, dst) {
+		t.Error("the trees differ after replacing")
+	}
+}
+
+// Names are part of the hash: a renamed file is a different tree.
+func TestRenameIsAChange(t *testing.T) {
+	src, dst := synced(t)
+	os.Rename(filepath.Join(src, "docs", "f3.dat"), filepath.Join(src, "docs", "f3-renamed.dat"))
+	if same(src, dst) {
+		t.Fatal("a rename didn't change the root hash")
+	}
+	plan, _ := Syncer{Delete: true}.Sync(src, dst)
+	if got := actions(plan); !slices.Equal(got, []string{"delete docs/f3.dat", "copy docs/f3-renamed.dat"}) {
+		t.Errorf("plan %v", got)
+	}
+}
+
+func TestCopyKeepsModeAndTime(t *testing.T) {
+	src, dst := synced(t)
+	p := filepath.Join(src, "src", "app", "run.sh")
+	write(t, src, "src/app/run.sh", "#!/bin/sh\n")
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	os.Chmod(p, 0o755)
+	os.Chtimes(p, old, old)
+	if _, err := (Syncer{}).Sync(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "src", "app", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0o755 || !info.ModTime().Equal(old) {
+		t.Errorf("copy: %v, %v; want 0755 and %v", info.Mode(), info.ModTime(), old)
+	}
+	if left, _ := filepath.Glob(filepath.Join(dst, "src", "app", ".sync-*")); len(left) != 0 {
+		t.Errorf("temporary files left behind: %v", left)
+	}
+}
+
+func TestOverlapRefused(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := makeTree(src, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, dst := range []string{src, filepath.Join(src, "docs", "copy"), dir, src + "/."} {
+		if _, err := (Syncer{Delete: true}).Sync(src, dst); !errors.Is(err, ErrOverlap) {
+			t.Errorf("Sync(src, %s) = %v, want ErrOverlap", dst, err)
+		}
+	}
+	// A sibling whose name starts the same isn't inside.
+	if _, err := (Syncer{}).Sync(src, src+"-backup"); err != nil {
+		t.Errorf("Sync to src-backup: %v", err)
+	}
+	// Nor is a link to src/docs outside it, or a dst under that link.
+	link := filepath.Join(dir, "docs-link")
+	if err := os.Symlink(filepath.Join(src, "docs"), link); err != nil {
+		t.Skip("no symlinks:", err)
+	}
+	for _, dst := range []string{link, filepath.Join(link, "new", "copy")} {
+		if _, err := (Syncer{Delete: true}).Sync(src, dst); !errors.Is(err, ErrOverlap) {
+			t.Errorf("Sync(src, %s) = %v, want ErrOverlap", dst, err)
+		}
+	}
+}
+
+func TestDeleteStaysInDst(t *testing.T) {
+	src, dst := synced(t)
+	outside := filepath.Join(filepath.Dir(dst), "outside")
+	write(t, outside, "keep.txt", "not the sync's")
+	plan := Plan{Actions: []Action{{Op: Delete, Path: "../outside"}}}
+	if err := (Syncer{}).Apply(plan, src, dst); err == nil {
+		t.Error("Apply deleted ../outside")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "keep.txt")); err != nil {
+		t.Errorf("outside/keep.txt: %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	texttemplate "text/template"
+)
+
+/*
+TOPIC: HTML/TEMPLATE — AUTO-ESCAPING AND CROSS-SITE SCRIPTING (XSS)
+
+CONCEPT:
+Intermediate Topic 72 builds text with text/template. Point it at a web
+page and a visitor's name becomes part of your HTML:
+
+    <p>Hello, {{.Name}}!</p>      Name = <script>steal(document.cookie)</script>
+
+text/template copies the name in as it is, and every browser that loads
+the page runs the script — with the cookies, the session and the trust
+of YOUR site. That is cross-site scripting (XSS), for twenty years one of
+the most common web vulnerabilities.
+
+html/template has the same API — swap the import — and ESCAPES every
+value for the place it lands in the page. It parses the HTML around each
+{{...}} and knows the CONTEXT:
+
+    <p>{{.}}</p>                     HTML text:   < → &lt;
+    <a title="{{.}}">                attribute:   " → &#34;
+    <a href="{{.}}">                 URL:         javascript:... → #ZgotmplZ
+    <script>var x = {{.}};</script>  JavaScript:  a quoted JS string
+    <a href="/s?q={{.}}">            query:       & → %26
+
+The same value is escaped differently in each, because what is harmless
+in one is code in another. No call to remember, no filter to forget.
+
+THE WAY AROUND IT is a type: template.HTML (and .URL, .JS, .CSS...)
+says "this is already safe, insert it as it is". It exists for HTML
+your program built itself. Wrapped around anything a user typed, it
+switches the protection off for exactly the value that needed it.
+
+RUN:
+    go run 201_html_template_xss.go                  (demo)
+    go run 201_html_template_xss.go serve -addr :8080
+        then open http://localhost:8080/?name=<script>alert(1)</script>
+        and http://localhost:8080/unsafe?name=<script>alert(1)</script>
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// attack is what a visitor types into the name box.
+const attack = `<script>alert("xss")</script>`
+
+// ---------------------------------------------------------
+// Part 1: The Same Template, Two Packages
+// ---------------------------------------------------------
+
+const greeting = `<p>Hello, {{.}}!</p>`
+
+// render runs greeting through text/template or html/template.
+func render(html bool, name string) string {
+	var b strings.Builder
+	if html {
+		template.Must(template.New("g").Parse(greeting)).Execute(&b, name)
+	} else {
+		texttemplate.Must(texttemplate.New("g").Parse(greeting)).Execute(&b, name)
+	}
+	return b.String()
+}
+
+// ---------------------------------------------------------
+// Part 2: The Page and Its Handlers
+// ---------------------------------------------------------
+// One value in five contexts: text, attribute, URL, query and script.
+
+var page = template.Must(template.New("page").Parse(`<!doctype html>
+<title>Profile</title>
+<h1>Hello, {{.Name}}!</h1>
+<img alt="{{.Name}}" src="/avatar.png">
+<a href="{{.Website}}">website</a>
+<a href="/search?q={{.Name}}">more like {{.Name}}</a>
+<div class="bio">{{.Bio}}</div>
+<script>const user = {{.Name}};</script>
+`))
+
+// Profile is what the page shows. Bio is template.HTML: HTML the program
+// built, trusted as it is.
+type Profile struct {
+	Name, Website string
+	Bio           template.HTML
+}
+
+// bio turns plain text into HTML safely: escape it ALL first, then add
+// the markup. This is the only way a user's text should become
+// template.HTML.
+func bio(text string) template.HTML {
+	escaped := template.HTMLEscapeString(text)
+	return template.HTML(strings.ReplaceAll(escaped, "\n", "<br>"))
+}
+
+// profileHandler serves the page for ?name=, ?site= and ?bio=, the
+// right way.
+func profileHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	p := Profile{Name: q.Get("name"), Website: q.Get("site"), Bio: bio(q.Get("bio"))}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// Defence in depth: scripts only from this site's own files, none
+	// inline, so even an injected <script> wouldn't run. (It blocks this
+	// page's own inline one too; a real page moves it to a .js file or
+	// gives it a nonce.)
+	w.Header().Set("Content-Security-Policy", "script-src 'self'")
+	if err := page.Execute(w, p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// unsafeHandler is the mistake: the bio wrapped in template.HTML as it
+// came in, "so line breaks work".
+func unsafeHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p := Profile{Name: q.Get("name"), Website: q.Get("site"), Bio: template.HTML(q.Get("bio"))} // ✗ Never do this
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page.Execute(w, p)
+}
+
+func newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", profileHandler)
+	mux.HandleFunc("/unsafe", unsafeHandler)
+	return mux
+}
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	flags.Parse(args)
+	fmt.Printf("listening on http://%s/?name=%s\n", *addr, url.QueryEscape(attack))
+	return http.ListenAndServe(*addr, newMux())
+}
+
+// ---------------------------------------------------------
+// Demo
+// ---------------------------------------------------------
+
+// get fetches path from srv and returns the body.
+func get(srv *httptest.Server, path string, query url.Values) (string, http.Header, error) {
+	resp, err := http.Get(srv.URL + path + "?" + query.Encode())
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), resp.Header, err
+}
+
+func demo() error {
+	fmt.Println("--- Example 1: text/template vs html/template ---")
+	fmt.Printf("  name:  %s\n", attack)
+	textOut, htmlOut := render(false, attack), render(true, attack)
+	fmt.Printf("  text/template: %s\n  html/template: %s\n", textOut, htmlOut)
+	check(strings.Contains(textOut, "<script>"),
+		"text/template put a live <script> in the page",
+		"text/template escaped it?")
+	check(!strings.Contains(htmlOut, "<script>") && strings.Contains(htmlOut, "&lt;script&gt;"),
+		"html/template turned it into text: the browser shows it, doesn't run it",
+		"html/template left the script in")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: One Value, Five Contexts ---")
+	var b strings.Builder
+	err := page.Execute(&b, Profile{Name: `Bob" onmouseover="alert(1)`, Website: "javascript:alert(document.cookie)"})
+	if err != nil {
+		return err
+	}
+	for l := range strings.Lines(b.String()) {
+		if strings.Contains(l, "Bob") || strings.Contains(l, "href=\"#") {
+			fmt.Print("    ", l)
+		}
+	}
+	out := b.String()
+	check(strings.Contains(out, `alt="Bob&#34; onmouseover=&#34;alert(1)"`),
+		`attribute: the " that would end alt="..." is &#34;, so onmouseover stays inside it`,
+		"the attribute was broken out of")
+	check(strings.Contains(out, `href="#ZgotmplZ"`),
+		"URL: a javascript: link became #ZgotmplZ, a marker that goes nowhere",
+		"the javascript: URL survived")
+	check(strings.Contains(out, `q=Bob%22%20onmouseover%3d%22alert%281%29`),
+		"query: percent-encoded, the same name",
+		"the query wasn't encoded")
+	check(strings.Contains(out, `const user = "Bob\" onmouseover=\"alert(1)";`),
+		"script: a JavaScript string literal, quotes escaped, not code",
+		"the script context wasn't quoted")
+	fmt.Println()
+
+	fmt.Println("--- Example 3: The template.HTML Pitfall ---")
+	srv := httptest.NewServer(newMux())
+	defer srv.Close()
+	q := url.Values{"name": {"Eve"}, "bio": {"Hi!\n<img src=x onerror=alert(1)>"}}
+	unsafeBody, _, err := get(srv, "/unsafe", q)
+	if err != nil {
+		return err
+	}
+	safeBody, hdr, err := get(srv, "/", q)
+	if err != nil {
+		return err
+	}
+	// div is the bio's <div>, its line breaks shown as \n.
+	div := func(body string) string {
+		i := strings.Index(body, `<div class="bio">`)
+		j := strings.Index(body, "</div>")
+		if i < 0 || j < i {
+			return ""
+		}
+		return strings.ReplaceAll(body[i:j+len("</div>")], "\n", `\n`)
+	}
+	fmt.Println("  bio:", strings.ReplaceAll(q.Get("bio"), "\n", `\n`))
+	fmt.Println("  template.HTML(bio):     ", div(unsafeBody))
+	fmt.Println("  escape, then add <br>:  ", div(safeBody))
+	check(strings.Contains(unsafeBody, "<img src=x onerror=alert(1)>"),
+		"wrapping the input in template.HTML let the onerror script straight through",
+		"the unsafe handler escaped the bio")
+	check(strings.Contains(safeBody, "Hi!<br>&lt;img") && !strings.Contains(safeBody, "<img src=x"),
+		"escaping first keeps the <br> we added and neutralises the <img> they typed",
+		"the safe handler let the <img> through")
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Served Over HTTP ---")
+	body, hdr, err := get(srv, "/", url.Values{"name": {attack}})
+	if err != nil {
+		return err
+	}
+	h1 := ""
+	for l := range strings.Lines(body) {
+		if strings.HasPrefix(l, "<h1>") {
+			h1 = strings.TrimSpace(l)
+		}
+	}
+	fmt.Printf("  GET /?name=%s\n    %s\n    Content-Type: %s\n    Content-Security-Policy: %s\n",
+		url.QueryEscape(attack), h1, hdr.Get("Content-Type"), hdr.Get("Content-Security-Policy"))
+	check(!strings.Contains(body, attack) && strings.Contains(hdr.Get("Content-Type"), "charset=utf-8"),
+		"escaped in the response, with a charset so the browser can't guess another encoding",
+		"the response carried the script")
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := serve(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "serve:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: HTML/TEMPLATE — AUTO-ESCAPING AND CROSS-SITE SCRIPTING (XSS)")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  import "html/template"              same API as text/template; use it for ANY HTML
+  {{.}} in text / attr / href / <script>   escaped for that context, automatically
+  #ZgotmplZ in the output             a value was unsafe where it landed (javascript: URL)
+  template.HTML(s)                    "s is safe HTML": only for HTML your code built
+  template.HTMLEscapeString(s)        escape user text yourself, then add markup
+  Content-Type: text/html; charset=utf-8, Content-Security-Policy   defence in depth`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. Never build HTML with text/template or fmt.Sprintf: user input becomes code.
+2. html/template escapes each value for its context: text, attribute, URL, JS.
+3. template.HTML switches escaping off; never wrap what a user typed in it.
+4. To allow some markup, escape everything first, then add your own tags.
+5. Set a charset and a Content-Security-Policy as a second line of defence.
+	`)
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/shq"
+)
+
+/*
+TOPIC: SHELL COMMANDS FROM TEMPLATES — COMMAND INJECTION AND QUOTING
+
+CONCEPT:
+Intermediate Topic 72 writes text with templates. Sooner or later the
+text is a shell script — a cleanup job, a deploy step, a Makefile line:
+
+    {{range .}}rm {{.}}
+    {{end}}
+
+With report.txt that prints "rm report.txt". With a file someone ELSE
+named, the shell reads the name as more shell:
+
+    old report.txt         rm old report.txt      two files: old, report.txt
+    x; touch pwned         rm x; touch pwned      a second command
+    $(touch pwned)         rm $(touch pwned)      a command inside the first
+    notes<newline>id       rm notes               a new line is a new command
+                           id
+    -rf                    rm -rf                 an option, not a file
+
+That is COMMAND INJECTION, the shell's version of Topic 201's XSS, and
+the fix has the same shape: treat the value as data, never as code.
+
+FIX 1, ALWAYS FIRST: NO SHELL. exec.Command("rm", "--", name) hands the
+name to rm as one argument. No shell reads it, so there is nothing to
+quote and nothing to get wrong.
+
+FIX 2, WHEN A SHELL IS THE POINT (a generated script, a command to copy,
+a line for ssh): QUOTE every value. pkg/shq's Quote puts it in single
+quotes, inside which a POSIX shell treats nothing as special:
+
+    {{range .}}rm -- {{shquote .}}      shq.Funcs() adds shquote, shjoin,
+    {{end}}                             winquote and cmdquote
+
+QUOTING DOESN'T STOP OPTIONS. '-rf' quoted is still -rf to rm. Put "--"
+(end of options) before the values.
+
+WINDOWS IS DIFFERENT, twice over: the program's C runtime splits its
+command line by one set of rules (double quotes, backslashes before
+them), and cmd.exe reads the line first by another (^ escapes, %VAR%).
+shq.QuoteWindows and shq.QuoteCmd handle each; a POSIX-quoted name on
+Windows is wrong, and so is the reverse.
+
+Some names can't be passed at all: a NUL byte ends a C string. shq's
+template functions stop the template rather than write such a command.
+
+RUN:
+    go run 202_shell_quoting.go
+    go test ./pkg/shq
+*/
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+	} else {
+		fmt.Println("  ✗", fail)
+	}
+}
+
+// ---------------------------------------------------------
+// Part 1: A Cleanup Script, Generated
+// ---------------------------------------------------------
+// The job: delete the files named in a list. The directory also holds
+// files that must survive. Everything runs in a scratch directory; the
+// worst the hostile names do is create a file called "pwned".
+
+// doomed are the files to delete, named by someone else.
+var doomed = []string{
+	"old report.txt",
+	"x; touch pwned",
+	"$(touch pwned)",
+	"notes\ntouch pwned",
+	"it's done.log",
+}
+
+// innocent are files that must survive the cleanup.
+var innocent = []string{"old", "report.txt", "x", "notes", "keep.txt"}
+
+var (
+	naive = template.Must(template.New("naive").Parse("{{range .}}rm {{.}}\n{{end}}"))
+	// quoted is the same script with every value quoted, and "--".
+	quoted = template.Must(template.New("quoted").Funcs(shq.Funcs()).Parse("{{range .}}rm -f -- {{shquote .}}\n{{end}}"))
+)
+
+// scratch makes a directory holding the doomed and innocent files.
+func scratch() (string, error) {
+	dir, err := os.MkdirTemp("", "demo-shq-*")
+	if err != nil {
+		return "", err
+	}
+	for _, name := range slices.Concat(doomed, innocent) {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// runScript renders t with the doomed names and runs it with sh in a
+// new scratch directory, returning what is left there afterwards.
+func runScript(t *template.Template) (script string, left []string, err error) {
+	var b strings.Builder
+	if err := t.Execute(&b, doomed); err != nil {
+		return "", nil, err
+	}
+	dir, err := scratch()
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(dir)
+	cmd := exec.Command("sh", "-c", b.String())
+	cmd.Dir = dir
+	cmd.Run() // rm complains about names that aren't there; what's left is the result
+	entries, err := os.ReadDir(dir)
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	return b.String(), left, err
+}
+
+// showScript prints script as typed at a prompt: "$ " starts a command,
+// "> " continues one whose quote a newline didn't close.
+func showScript(script string) {
+	inQuote := false
+	for l := range strings.Lines(script) {
+		prompt := "$ "
+		if inQuote {
+			prompt = "> "
+		}
+		fmt.Print("    ", prompt, l)
+		for i := 0; i < len(l); i++ {
+			switch {
+			case l[i] == '\\' && !inQuote:
+				i++
+			case l[i] == '\'':
+				inQuote = !inQuote
+			}
+		}
+	}
+}
+
+// survived reports whether left is exactly the innocent files.
+func survived(left []string) bool {
+	want := slices.Clone(innocent)
+	slices.Sort(want)
+	return slices.Equal(left, want)
+}
+
+// ---------------------------------------------------------
+// Part 2: No Shell At All
+// ---------------------------------------------------------
+
+// removeAll deletes names in dir with one rm per name, each name one
+// argument of exec.Command: no shell reads it.
+func removeAll(dir string, names []string) error {
+	for _, name := range names {
+		cmd := exec.Command("rm", "--", name)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("rm %q: %v: %s", name, err, out)
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------
+// Demo
+// ---------------------------------------------------------
+
+func demo() error {
+	if _, err := exec.LookPath("sh"); err != nil {
+		fmt.Println("  (no sh here: Examples 1 to 4 need a POSIX shell; see Example 5 for Windows)")
+		return windows()
+	}
+
+	fmt.Println("--- Example 1: The Naive Template ---")
+	script, left, err := runScript(naive)
+	if err != nil {
+		return err
+	}
+	showScript(script)
+	fmt.Printf("  left: %q\n", left)
+	check(slices.Contains(left, "pwned"),
+		`three names ran "touch pwned": ";", "$(...)" and a newline all start commands`,
+		"no injected command ran?")
+	check(!slices.Contains(left, "report.txt") && slices.Contains(left, "old report.txt"),
+		`"old report.txt" split in two: the innocent old and report.txt went, the target stayed`,
+		"the space didn't split the name?")
+	fmt.Println()
+
+	fmt.Println("--- Example 2: Every Value Quoted ---")
+	script, left, err = runScript(quoted)
+	if err != nil {
+		return err
+	}
+	showScript(script)
+	fmt.Printf("  left: %q\n", left)
+	check(survived(left),
+		"exactly the five targets deleted, the innocent files kept, nothing ran",
+		fmt.Sprintf("left %q", left))
+	fmt.Println()
+
+	fmt.Println("--- Example 3: Better Still, No Shell ---")
+	dir, err := scratch()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	err = removeAll(dir, doomed)
+	entries, _ := os.ReadDir(dir)
+	left = nil
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	fmt.Println(`    exec.Command("rm", "--", name)   for each name`)
+	check(err == nil && survived(left),
+		"the same result with nothing quoted: each name is one argument, and no shell reads it",
+		fmt.Sprintf("left %q, err=%v", left, err))
+	fmt.Println()
+
+	fmt.Println("--- Example 4: Quoting Doesn't Stop Options ---")
+	os.WriteFile(filepath.Join(dir, "--version"), []byte("one line\n"), 0o644)
+	count := func(line string) string {
+		cmd := exec.Command("sh", "-c", line)
+		cmd.Dir = dir
+		out, _ := cmd.CombinedOutput()
+		first, _, _ := strings.Cut(string(out), "\n")
+		return first
+	}
+	without, with := count("wc -l "+shq.Quote("--version")), count("wc -l -- "+shq.Quote("--version"))
+	fmt.Printf("    $ wc -l %s\n      %s\n    $ wc -l -- %s\n      %s\n",
+		shq.Quote("--version"), without, shq.Quote("--version"), with)
+	check(without != with && strings.HasPrefix(with, "1 "),
+		`a file named --version is an option to wc until "--" ends the options`,
+		"the option and the file were read the same way")
+	fmt.Println()
+
+	return windows()
+}
+
+// windows shows the same names quoted for Windows, where there is no sh
+// to check them against: the round trip is in pkg/shq's tests.
+func windows() error {
+	fmt.Println("--- Example 5: The Same Names on Windows ---")
+	names := []string{"old report.txt", `C:\My Files\`, `say "hi"`, "100% & done"}
+	fmt.Printf("    %-16s %-20s %-20s %s\n", "name", "POSIX sh", "program (argv)", "through cmd.exe")
+	for _, name := range names {
+		cmd, _ := shq.QuoteCmd(name)
+		fmt.Printf("    %-16s %-20s %-20s %s\n", name, shq.Quote(name), shq.QuoteWindows(name), cmd)
+	}
+	_, err := shq.QuoteCmd("notes\ntouch pwned")
+	check(errors.Is(err, shq.ErrUnsafe),
+		"cmd.exe has no escape for a newline: QuoteCmd refuses rather than guess",
+		"QuoteCmd quoted a newline")
+	fmt.Println()
+
+	fmt.Println("--- Example 6: A Name That Can't Be Passed At All ---")
+	err = quoted.Execute(new(strings.Builder), []string{"ok.txt", "nul\x00byte"})
+	fmt.Println("   ", err)
+	check(errors.Is(err, shq.ErrUnsafe),
+		"a NUL byte ends a C string: the template stops instead of writing a wrong command",
+		"the template wrote a command with a NUL in it")
+	return nil
+}
+
+func main() {
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println("TOPIC: SHELL COMMANDS FROM TEMPLATES — COMMAND INJECTION AND QUOTING")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if err := demo(); err != nil {
+		fmt.Println("demo failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(`
+QUICK REFERENCE
+  exec.Command("rm", "--", name)      best: no shell, nothing to quote
+  shq.Quote(s), shq.Join(args...)     one POSIX shell word: 'it'\''s'
+  template.New(n).Funcs(shq.Funcs())  {{shquote .}} {{shjoin .Args}} in templates
+  cmd -- {{shquote .}}                "--": a value starting with - isn't an option
+  shq.QuoteWindows(s)                 a Windows program's argv: "C:\My Files\\"
+  shq.QuoteCmd(s)                     ...through cmd.exe: ^ before & | % " ...
+  shq.ErrUnsafe                       NUL anywhere; line breaks for cmd.exe`)
+
+	fmt.Println("\n═══════════════════════════════════════════════════════════")
+	fmt.Println("KEY TAKEAWAYS")
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Println(`
+1. A value pasted into a shell command is code: spaces, ;, $(), newlines all act.
+2. Don't use a shell to run a program: exec.Command takes the arguments as a list.
+3. When a script must be written, quote every value — all of them, every time.
+4. Quoting keeps a value one word, not a non-option: put -- before values.
+5. Quote for the shell that will read it: POSIX, a Windows argv and cmd.exe all differ.
+	`)
+}
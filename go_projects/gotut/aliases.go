@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/alias"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): Aliases
+// ---------------------------------------------------------
+// Shortcuts of the learner's own, kept in the config.json the gotut
+// tools share (pkg/alias). Run expands one only when the first word
+// isn't a command, so a broken config can't stop "gotut help" — and no
+// alias may take a command's name, so none is ever hidden.
+//
+//	gotut alias re = run 73                 define; the "=" is optional
+//	gotut re -timeout 5s                    runs "run 73 -timeout 5s"
+//	gotut alias re                          show one
+//	gotut alias -d re                       delete it
+//	gotut aliases                           list them all
+//
+// An alias may start with another; one that leads back to itself is a
+// usage error naming the loop, refused when it is defined and, for a
+// config edited by hand, when it is run.
+
+// loadAliases reads the learner's aliases. A config.json that doesn't
+// parse, or has an alias no command line could have defined, is the
+// learner's to fix: a usage error naming the file.
+func loadAliases(op string) (alias.Set, string, error) {
+	path, err := alias.Path()
+	if err != nil {
+		return nil, "", E(CodeIO, op, err)
+	}
+	set, err := alias.Load(path)
+	if err != nil {
+		return nil, "", E(CodeUsage, op, err)
+	}
+	for _, name := range set.Names() {
+		if lookupCommand(name) != nil || isHelp(name) {
+			return nil, "", M(CodeUsage, op, "cli.alias-is-command", name)
+		}
+	}
+	return set, path, nil
+}
+
+// isHelp reports whether name is one of the words Run takes for help.
+func isHelp(name string) bool {
+	switch name {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	return false
+}
+
+// expandAlias is Run's fallback for a first word that isn't a command. A
+// word that isn't an alias either comes back as it was, for Run to report.
+func (c *CLI) expandAlias(args []string) ([]string, error) {
+	set, _, err := loadAliases(args[0])
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := set.Expand(args)
+	if err != nil {
+		return nil, E(CodeUsage, args[0], err)
+	}
+	return expanded, nil
+}
+
+func (c *CLI) alias(args []string) error {
+	// Only flags before NAME are alias's: the rest are the expansion's,
+	// and c.flags would take them too.
+	n := 0
+	for n < len(args) && strings.HasPrefix(args[n], "-") {
+		n++
+	}
+	var del bool
+	extra, err := c.flags("alias", args[:n], nil, func(fs *flag.FlagSet) {
+		fs.BoolVar(&del, "d", false, "delete the alias")
+	})
+	if err != nil {
+		return err
+	}
+	args = append(extra, args[n:]...)
+	if len(args) == 0 || del && len(args) != 1 {
+		return M(CodeUsage, "alias", "cli.want-alias")
+	}
+	set, path, err := loadAliases("alias")
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	expansion, ok := set[name]
+	switch {
+	case len(args) == 1 && !ok:
+		return M(CodeUsage, "alias", "cli.no-alias", name)
+	case len(args) == 1 && !del:
+		fmt.Fprintf(c.Stdout, "%s = %s\n", name, expansion)
+		return nil
+	case del:
+		delete(set, name)
+		return c.saveAliases(path, set)
+	}
+
+	words := args[1:]
+	if words[0] == "=" {
+		words = words[1:]
+	}
+	expansion = strings.Join(words, " ")
+	if lookupCommand(name) != nil || isHelp(name) {
+		return M(CodeUsage, "alias", "cli.alias-is-command", name)
+	}
+	if err := alias.Validate(name, expansion); err != nil {
+		return E(CodeUsage, "alias", err)
+	}
+	set[name] = expansion
+	if _, err := set.Expand([]string{name}); err != nil {
+		return E(CodeUsage, "alias", err)
+	}
+	return c.saveAliases(path, set)
+}
+
+func (c *CLI) saveAliases(path string, set alias.Set) error {
+	err := alias.Save(path, set)
+	if errors.Is(err, alias.ErrInvalid) {
+		return E(CodeUsage, "alias", err)
+	}
+	if err != nil {
+		return E(CodeIO, "alias", err)
+	}
+	return nil
+}
+
+func (c *CLI) aliases(args []string) error {
+	args, err := c.flags("aliases", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return M(CodeUsage, "aliases", "cli.unexpected-args", strings.Join(args, " "))
+	}
+	set, _, err := loadAliases("aliases")
+	if err != nil {
+		return err
+	}
+	if len(set) == 0 {
+		fmt.Fprintln(c.Stdout, "No aliases yet; define one with 'gotut alias NAME = COMMAND...'.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(c.Stdout, 0, 0, 1, ' ', 0)
+	for _, name := range set.Names() {
+		fmt.Fprintf(tw, "%s\t= %s\n", name, set[name])
+	}
+	return tw.Flush()
+}
+
+// aliasHelp is help's answer for a name that isn't a command: what the
+// alias runs, then that command's page if it is one of gotut's.
+func (c *CLI) aliasHelp(name string) error {
+	set, _, err := loadAliases("help")
+	if err != nil {
+		return err
+	}
+	expansion, ok := set[name]
+	if !ok {
+		return M(CodeUsage, "help", "cli.unknown-command", name)
+	}
+	args, err := set.Expand([]string{name})
+	if err != nil {
+		return E(CodeUsage, "help", err)
+	}
+	fmt.Fprintf(c.Stdout, "%s is an alias for 'gotut %s'", name, expansion)
+	if full := strings.Join(args, " "); full != strings.Join(strings.Fields(expansion), " ") {
+		fmt.Fprintf(c.Stdout, ", which is 'gotut %s'", full)
+	}
+	fmt.Fprintln(c.Stdout, ".")
+	if lookupCommand(args[0]) == nil {
+		return nil
+	}
+	fmt.Fprintln(c.Stdout)
+	return c.help(args[:1])
+}
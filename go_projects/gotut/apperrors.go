@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/msg"
+)
+
+// ---------------------------------------------------------
+// Part 1: Errors That Carry a Code
+// ---------------------------------------------------------
+// These stay in gotut rather than pkg/: the codes are its exit-code
+// contract, and no other module exits by them. An *Error says WHAT kind of failure happened; the message says the rest.
+// Codes are fine-grained on purpose: a caller inside the program may care
+// that a topic was unknown rather than the flags wrong, while a shell
+// script only needs "was it my fault or the lesson's?" (Part 2).
+
+type Code int
+
+const (
+	CodeInternal     Code = iota // A bug in gotut itself
+	CodeIO                       // Reading, writing or starting a process failed
+	CodeTimeout                  // A lesson ran past its time limit
+	CodeUsage                    // Bad flags or arguments
+	CodeUnknownTopic             // No lesson with that number
+	CodeVerify                   // A lesson didn't build, or didn't exit 0
+	CodeTestFailed               // An exercise's tests ran and failed
+)
+
+var codeNames = [...]string{"internal", "io", "timeout", "usage", "unknown-topic", "verify", "test-failed"}
+
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return fmt.Sprintf("Code(%d)", int(c))
+}
+
+// Error is an error with a Code and the operation that failed:
+//
+//	verify 83_write_file: exit status 1
+type Error struct {
+	Code Code
+	Op   string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// E builds an *Error. err may be a string for a fresh error.
+func E(code Code, op string, err any) error {
+	switch err := err.(type) {
+	case error:
+		return &Error{Code: code, Op: op, Err: err}
+	default:
+		return &Error{Code: code, Op: op, Err: errors.New(fmt.Sprint(err))}
+	}
+}
+
+// Message is error text from the message catalog (pkg/msg, Topic 189), so
+// report can say it in the user's language. Error() is the English: logs,
+// tests and anything that wraps it see one language.
+type Message struct {
+	Key  string
+	Args []any
+}
+
+func (m *Message) Error() string { return msg.T(msg.Default, m.Key, m.Args...) }
+
+// M is E for a message the user reads: the catalog key and its arguments
+// instead of a string.
+//
+//	M(CodeUnknownTopic, "run", "cli.no-lesson", 42, dir)
+func M(code Code, op, key string, args ...any) error {
+	return &Error{Code: code, Op: op, Err: &Message{Key: key, Args: args}}
+}
+
+// Localize renders err in lang. Only Messages are translated; the rest —
+// an op name, a compiler's output, an OS error — is shown as it came. The
+// code, and so the exit status, is the same in every language.
+func Localize(err error, lang string) string {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		var lines []string
+		for _, err := range e.Unwrap() {
+			lines = append(lines, Localize(err, lang))
+		}
+		return strings.Join(lines, "\n")
+	case *Error:
+		if e.Op == "" {
+			return Localize(e.Err, lang)
+		}
+		return e.Op + ": " + Localize(e.Err, lang)
+	case *Message:
+		return msg.T(lang, e.Key, e.Args...)
+	}
+	return err.Error()
+}
+
+// CodeOf returns the code of the outermost *Error in err's chain, or
+// CodeInternal for an error nobody classified — if it reached the top
+// without a code, that is gotut's bug to fix.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): sessions as asciicasts
+// ---------------------------------------------------------
+// A recorded session can also be written as an asciicast v2 file, which
+// asciinema plays in a terminal and asciinema-player embeds in a web
+// page, pauses and colours and all:
+//
+//	gotut record -cast 85.cast 85                   record, and write both
+//	gotut replay -cast 85.cast -size 100x30 s.json  an existing session
+//
+//	{"version": 2, "width": 100, "height": 30, "timestamp": 1792141035, "title": "85_line_filters.go"}
+//	[0.477188, "o", "═══...═══\r\n"]
+//	[0.478301, "m", "1 Basic Line Filter"]
+//
+// The player draws the bytes as a terminal would, so they have to be
+// the ones a terminal got. The lesson wrote "\n", which a tty turns into
+// "\r\n" — without the "\r" the player draws a staircase. Escape
+// sequences go through untouched, encoded by JSON as \u001b. Times are
+// 176's, to the microsecond, from the start event: the build's wait is
+// in the cast too. Each section is a marker, to jump to from the
+// player's timeline. The size is the terminal the cast claims, where
+// the player wraps lines: -size, then $COLUMNS and $LINES, then 80x24.
+
+// termSize returns the size a cast is recorded at.
+func termSize(flagSize string) (cols, rows int, err error) {
+	if flagSize != "" {
+		if _, err := fmt.Sscanf(flagSize, "%dx%d", &cols, &rows); err != nil || cols <= 0 || rows <= 0 {
+			return 0, 0, M(CodeUsage, "cast", "cli.bad-size", flagSize)
+		}
+		return cols, rows, nil
+	}
+	cols, errC := strconv.Atoi(os.Getenv("COLUMNS"))
+	rows, errR := strconv.Atoi(os.Getenv("LINES"))
+	if errC == nil && errR == nil && cols > 0 && rows > 0 {
+		return cols, rows, nil
+	}
+	return 80, 24, nil
+}
+
+// writeCast writes a session's events to path as an asciicast v2 file: a
+// JSON header, then one [seconds, "o" or "m", data] array per line.
+func writeCast(path, lesson string, events []runEvent, cols, rows int) error {
+	start := events[0].Time
+	header := struct {
+		Version   int               `json:"version"`
+		Width     int               `json:"width"`
+		Height    int               `json:"height"`
+		Timestamp int64             `json:"timestamp"`
+		Title     string            `json:"title,omitempty"`
+		Env       map[string]string `json:"env,omitempty"`
+	}{2, cols, rows, start.Unix(), lesson, nil}
+	if term := os.Getenv("TERM"); term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	b.Write(h)
+	b.WriteByte('\n')
+	for _, ev := range events {
+		var kind, data string
+		switch ev.Type {
+		case "output":
+			kind, data = "o", ev.Text+"\r\n"
+		case "section":
+			kind, data = "m", fmt.Sprintf("%d %s", ev.Section, ev.Title)
+		default:
+			continue
+		}
+		d, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "[%.6f, %q, %s]\n", castTime(ev.Time.Sub(start)), kind, d)
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// castTime is d in seconds, truncated to the microsecond so that %.6f
+// never rounds a time past the next event's.
+func castTime(d time.Duration) float64 {
+	return max(d, 0).Truncate(time.Microsecond).Seconds()
+}
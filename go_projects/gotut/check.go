@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut check, once or on every save
+// ---------------------------------------------------------
+// test passes go test's output through; check reads it (-json) and
+// prints a summary: which tests failed, with their own messages, and
+// how many passed. With -watch it stays running and checks again each
+// time a .go file in the exercise changes.
+//
+//	gotut check 73                  one run: exit 0, or 4 if a test fails
+//	gotut check -watch 73           again on every save, until Ctrl-C
+//	gotut check -watch -bell 73     ...and ring the terminal bell after each
+//
+// Watching polls the files' sizes and modification times, as Topic 144
+// and intermediate Topic 72 do: no OS notification library, and it works
+// on network drives and with editors that save by renaming. A save shows
+// up within -interval. Editors often write a file in several steps, so a
+// change starts a run only once the files have stopped changing for one
+// more interval: one save, one run.
+//
+// On Ctrl-C the watch ends with the last run's result, so the exit status
+// says whether the exercise passes as it was left.
+
+func (c *CLI) check(args []string) error {
+	var timeout, interval time.Duration
+	var watch, bell bool
+	var color string
+	args, err := c.flags("check", args, &timeout, func(fs *flag.FlagSet) {
+		fs.BoolVar(&watch, "watch", false, "check again whenever a .go file changes, until interrupted")
+		fs.DurationVar(&interval, "interval", 300*time.Millisecond, "how often -watch looks for changes")
+		fs.BoolVar(&bell, "bell", false, "ring the terminal bell when a run finishes")
+		fs.StringVar(&color, "color", "auto", "color the summary: `auto`, always or never")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "check", "cli.want-one-topic")
+	}
+	var paint palette
+	switch color {
+	case "always":
+		paint = ansi
+	case "auto":
+		if isTerminal(c.Stdout) && os.Getenv("NO_COLOR") == "" {
+			paint = ansi
+		}
+	case "never":
+	default:
+		return M(CodeUsage, "check", "cli.bad-color", color)
+	}
+	if interval <= 0 {
+		return M(CodeUsage, "check", "cli.bad-interval", interval)
+	}
+	dir, err := c.findExercise("check", args[0])
+	if err != nil {
+		return err
+	}
+	op := "check " + filepath.Base(dir)
+
+	once := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		res, err := runTests(ctx, op, dir)
+		if err != nil {
+			return err
+		}
+		res.print(c.Stdout, paint)
+		if bell {
+			fmt.Fprint(c.Stdout, "\a")
+		}
+		return res.err(op)
+	}
+	if !watch {
+		return once(context.Background())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	last, err := fingerprint(dir)
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	result := once(ctx)
+	if CodeOf(result) == CodeIO {
+		return result
+	}
+	fmt.Fprintf(c.Stdout, "Watching %s; Ctrl-C to stop.\n", dir)
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return result
+		case <-tick.C:
+		}
+		fp, err := fingerprint(dir)
+		if err != nil {
+			return E(CodeIO, op, err) // The exercise deleted or moved: nothing left to watch
+		}
+		if maps.Equal(fp, last) {
+			continue
+		}
+		// Wait for the writes to settle: the same fingerprint twice in a row.
+		for settled := false; !settled; {
+			select {
+			case <-ctx.Done():
+				return result
+			case <-tick.C:
+			}
+			next, err := fingerprint(dir)
+			if err != nil {
+				return E(CodeIO, op, err)
+			}
+			settled, fp = maps.Equal(next, fp), next
+		}
+		fmt.Fprintf(c.Stdout, "\n── %s: %s ──\n", time.Now().Format(time.TimeOnly), strings.Join(changed(last, fp), ", "))
+		last = fp
+		next := once(ctx)
+		if ctx.Err() != nil { // Interrupted mid-run: that run doesn't count
+			return result
+		}
+		if result = next; CodeOf(result) == CodeIO {
+			return result
+		}
+	}
+}
+
+// fingerprint records each .go file in dir by size and modification
+// time, so that any save, new file or deleted one changes it.
+func fingerprint(dir string) (map[string]string, error) {
+	fp := make(map[string]string)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			fp[e.Name()] = fmt.Sprintf("%d@%d", info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return fp, nil
+}
+
+// changed names the files that differ between two fingerprints, sorted.
+func changed(old, now map[string]string) []string {
+	var names []string
+	for name, v := range now {
+		if old[name] != v {
+			names = append(names, name)
+		}
+	}
+	for name := range old {
+		if _, ok := now[name]; !ok {
+			names = append(names, name+" (deleted)")
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// testResult is one go test run, top-level tests only.
+type testResult struct {
+	passed, failed []string
+	output         map[string][]string // A failed test's own lines, subtests' included
+	build          string              // The compiler's errors when nothing ran
+	elapsed        time.Duration
+	timedOut       bool
+}
+
+// runTests runs dir's tests with -json. A failing test or a build error
+// is a result, not an error; an error is go not running at all.
+func runTests(ctx context.Context, op, dir string) (*testResult, error) {
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "go", "test", "-json", "-count=1", ".")
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	res := &testResult{output: map[string][]string{}, elapsed: time.Since(start)}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		res.timedOut = true
+		return res, nil
+	case ctx.Err() != nil: // Interrupted: go test was killed, and said nothing true
+		return nil, E(CodeIO, op, ctx.Err())
+	}
+	var build strings.Builder
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		var e struct{ Action, Test, Output string }
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		top, _, _ := strings.Cut(e.Test, "/")
+		switch {
+		case e.Action == "build-output":
+			build.WriteString(e.Output)
+		case e.Test == "":
+		case e.Action == "output":
+			line := strings.TrimSpace(e.Output)
+			if line != "" && !strings.HasPrefix(line, "=== ") && !strings.HasPrefix(line, "--- ") {
+				res.output[top] = append(res.output[top], line)
+			}
+		case e.Test != top:
+		case e.Action == "pass":
+			res.passed = append(res.passed, e.Test)
+		case e.Action == "fail":
+			res.failed = append(res.failed, e.Test)
+		}
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil || len(res.passed)+len(res.failed) > 0:
+	case errors.As(runErr, &exitErr):
+		res.build = strings.TrimSpace(build.String() + stderr.String())
+	default:
+		return nil, E(CodeIO, op, runErr)
+	}
+	return res, nil
+}
+
+// print writes the summary: failures first, with their messages, then a
+// count line.
+func (r *testResult) print(w io.Writer, paint palette) {
+	switch {
+	case r.timedOut:
+		fmt.Fprintln(w, paint.red("✗ timed out")+fmt.Sprintf(" after %v: a test that never returns?", r.elapsed.Round(time.Millisecond)))
+		return
+	case r.build != "":
+		fmt.Fprintln(w, paint.red("✗ does not compile"))
+		for _, l := range strings.Split(r.build, "\n") {
+			fmt.Fprintln(w, "    "+l)
+		}
+		return
+	}
+	for _, t := range r.failed {
+		fmt.Fprintln(w, paint.red("✗ "+t))
+		for _, l := range r.output[t] {
+			fmt.Fprintln(w, "    "+l)
+		}
+	}
+	summary := fmt.Sprintf("%d passed", len(r.passed))
+	if len(r.failed) > 0 {
+		summary = paint.red(fmt.Sprintf("%d failed", len(r.failed))) + ", " + summary
+	} else {
+		summary = paint.green("✓ " + summary)
+	}
+	fmt.Fprintf(w, "%s (%v)\n", summary, r.elapsed.Round(10*time.Millisecond))
+}
+
+// err is the run as an exit status: 0 when every test passed, else an
+// exercise failure, or a timeout.
+func (r *testResult) err(op string) error {
+	switch {
+	case r.timedOut:
+		return E(CodeTimeout, op, context.DeadlineExceeded)
+	case r.build != "":
+		return E(CodeTestFailed, op, "does not compile")
+	case len(r.failed) > 0:
+		return E(CodeTestFailed, op, fmt.Sprintf("%d of %d tests failed", len(r.failed), len(r.failed)+len(r.passed)))
+	}
+	return nil
+}
+
+// palette colors the summary, or doesn't: the zero palette leaves text
+// as it is.
+type palette struct{ on bool }
+
+var ansi = palette{on: true}
+
+func (p palette) red(s string) string   { return p.wrap("31", s) }
+func (p palette) green(s string) string { return p.wrap("32", s) }
+
+func (p palette) wrap(code, s string) string {
+	if !p.on {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// isTerminal reports whether w is a terminal: colors for a person, none
+// in a file or a pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/ring"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/safe"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/trace"
+)
+
+// ---------------------------------------------------------
+// Part 3: A gotut-Shaped CLI
+// ---------------------------------------------------------
+// The commands whose outcomes a script cares about, and the exercise
+// tools around them. Every failure leaves here as an
+// *Error with a code; nothing below calls os.Exit.
+//
+//	gotut run [-timeout D] TOPIC        go run the lesson; -trace times its sections, in trace.go
+//	gotut verify [-timeout D] TOPIC...  build and run each, report all
+//	gotut test TOPIC                    go test a multi-file lesson's package
+//	gotut check [-watch] TOPIC          ...summarized, and again on each save, in check.go
+//	gotut hint [-level N] TOPIC         an exercise's hints, in hint.go
+//	gotut solution [-diff] TOPIC        its reference solution, in solution.go
+//	gotut review export|import          signed peer-review bundles, in review.go
+//	gotut kata [-grade] [NAME]          timed challenges, in kata.go
+//	gotut deprecations                  deprecated calls left, in deprecations.go
+//	gotut record|replay                 a lesson run with its timing, in record.go
+//	gotut practice [-n N] [CONCEPT...]  generated fmt and regex problems, in practice.go
+//	gotut type [-n N] [SNIPPET...]      a typing drill on Go from the lessons, in typing.go
+//	gotut tmpl-check [-data F] DIR      lint DIR/*.tmpl, in tmplcheck.go
+//	gotut tool migrate DIR COMMAND      apply or revert DIR/*.sql, in migrate.go
+//	gotut help [COMMAND|topics]         generated from the command table, in help.go
+//	gotut alias NAME = COMMAND...       shortcuts in config.json, in aliases.go
+
+type CLI struct {
+	Dir    string    // Where the NNN_name.go lessons and NNN_name/ packages are
+	Stdin  io.Reader // Answers for gotut practice, keys for gotut type; nil reads nothing, as with exec.Cmd
+	Stdout io.Writer
+	Stderr io.Writer
+	Width  int        // Where help text wraps; 0 is $COLUMNS, or 80
+	Recent *ring.Ring // The last lines written to Stderr, for a crash report; nil leaves them out
+}
+
+// Run executes one command line. A panic becomes CodeInternal: left alone,
+// the Go runtime would exit with status 2 — the USAGE code — and a script
+// would tell the user to fix their command line. Its stack goes into a
+// crash report (Topic 139) whose path the error names: a bug report
+// needs it, and the recover would otherwise lose it.
+func (c *CLI) Run(args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = E(CodeInternal, "panic", c.crashReport(args, r, debug.Stack()))
+		}
+	}()
+	if len(args) == 0 {
+		return M(CodeUsage, "", "cli.no-command")
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		return c.help(args[1:])
+	}
+	if lookupCommand(args[0]) == nil {
+		if args, err = c.expandAlias(args); err != nil {
+			return err
+		}
+	}
+	if cmd := lookupCommand(args[0]); cmd != nil {
+		return cmd.Run(c, args[1:])
+	}
+	return M(CodeUsage, "", "cli.unknown-command", args[0])
+}
+
+// crashReport saves the report of a panic Run recovered, in the config
+// dir's crashes/ (pkg/safe), and says where it went.
+func (c *CLI) crashReport(args []string, r any, stack []byte) string {
+	dir, err := safe.Dir()
+	if err == nil {
+		var path string
+		runner := &safe.Runner{ReportDir: dir, Log: c.Recent}
+		if path, err = runner.Report(strings.Join(args, " "), r, stack); err == nil {
+			return fmt.Sprintf("%v\ncrash report saved to %s", r, path)
+		}
+	}
+	return fmt.Sprintf("%v (and the crash report could not be saved: %v)", r, err)
+}
+
+// flags parses a command's flags. The flag package's own printing is
+// switched off: the error comes back as a usage error and report prints it
+// once; -h prints the command's help page to stdout and exits 0. extra adds
+// a command's own flags. Flags may also follow the arguments, as in
+// "gotut hint 73 --level 2": the flag package stops at the first
+// argument, so parsing picks up again after each one.
+func (c *CLI) flags(name string, args []string, timeout *time.Duration, extra ...func(*flag.FlagSet)) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if timeout != nil {
+		fs.DurationVar(timeout, "timeout", 30*time.Second, "time limit for each lesson")
+	}
+	for _, add := range extra {
+		add(fs)
+	}
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				if err := c.commandHelp(name, fs); err != nil {
+					return nil, err
+				}
+				return nil, flag.ErrHelp
+			}
+			return nil, E(CodeUsage, name, err)
+		}
+		if fs.NArg() == 0 {
+			return rest, nil
+		}
+		rest, args = append(rest, fs.Arg(0)), fs.Args()[1:]
+	}
+}
+
+// find returns the lesson for topic: NNN_name.go or the NNN_name/ package.
+// The path is absolute, so it means the same thing from any directory.
+func (c *CLI) find(op, topic string) (string, error) {
+	matches, err := c.lessons(op, topic)
+	if err != nil {
+		return "", err
+	}
+	return matches[0], nil
+}
+
+// findExercise returns topic's exercise: the first of its NNN_name/
+// packages with tests. A topic can have several lessons (73_regex_detailed.go,
+// 73_regex_exercise/); the exercise is the one that can be graded.
+func (c *CLI) findExercise(op, topic string) (string, error) {
+	matches, err := c.lessons(op, topic)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range matches {
+		tests, err := filepath.Glob(filepath.Join(m, "*_test.go"))
+		if err != nil {
+			return "", E(CodeInternal, op, err)
+		}
+		if len(tests) > 0 {
+			return m, nil
+		}
+	}
+	return "", M(CodeUsage, op, "cli.no-tests", filepath.Base(matches[0]))
+}
+
+// lessons returns every lesson for topic, sorted. Topics under 100 are
+// named both ways in the tree: 073_x in a course of three-digit topics,
+// 73_x in intermediate_topics. Anything that isn't a .go file or a
+// directory — a binary someone built next to the lesson — isn't one.
+func (c *CLI) lessons(op, topic string) ([]string, error) {
+	n, err := strconv.Atoi(topic)
+	if err != nil || n <= 0 {
+		return nil, M(CodeUsage, op, "cli.topic-not-number", topic)
+	}
+	dirs, err := c.courseDirs(op)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, dir := range dirs {
+		for _, name := range []string{fmt.Sprintf("%03d_*", n), fmt.Sprintf("%d_*", n)} {
+			m, err := filepath.Glob(filepath.Join(dir, name))
+			if err != nil {
+				return nil, E(CodeInternal, op, err)
+			}
+			for _, path := range m {
+				info, err := os.Stat(path)
+				if err == nil && (info.IsDir() || filepath.Ext(path) == ".go") && !slices.Contains(matches, path) {
+					matches = append(matches, path)
+				}
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil, M(CodeUnknownTopic, op, "cli.no-lesson", n, c.Dir)
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// courseDirs is where lessons are looked for: c.Dir, and when it holds a
+// go.work, every module the workspace uses (Topic 193). A course split
+// into core, lessons and tools modules keeps lessons in more than one.
+func (c *CLI) courseDirs(op string) ([]string, error) {
+	dir, err := filepath.Abs(c.Dir)
+	if err != nil {
+		return nil, E(CodeIO, op, err)
+	}
+	dirs := []string{dir}
+	work := filepath.Join(dir, "go.work")
+	if _, err := os.Stat(work); errors.Is(err, fs.ErrNotExist) {
+		return dirs, nil
+	}
+	uses, err := workspaceUses(work)
+	if err != nil {
+		return nil, E(CodeIO, op, err)
+	}
+	for _, use := range uses {
+		if !filepath.IsAbs(use) {
+			use = filepath.Join(dir, use)
+		}
+		if !slices.Contains(dirs, use) {
+			dirs = append(dirs, use)
+		}
+	}
+	return dirs, nil
+}
+
+// workspaceUses is a go.work's use directives, read by the go tool
+// itself: "go work edit -json" knows the syntax, comments and all.
+func workspaceUses(path string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "work", "edit", "-json", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	var work struct{ Use []struct{ DiskPath string } }
+	if err := json.Unmarshal(out, &work); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var uses []string
+	for _, u := range work.Use {
+		uses = append(uses, filepath.FromSlash(u.DiskPath))
+	}
+	return uses, nil
+}
+
+// goCmd runs the go tool from path's directory, on "." or the file's
+// name: the go command finds the module, and the go.work, from where it
+// runs, and a lesson belongs to the one it is in, not to gotut's.
+func goCmd(ctx context.Context, code Code, op, path string, stdout, stderr io.Writer, args ...string) error {
+	cmd := exec.CommandContext(ctx, "go", append(args, filepath.Base(path))...)
+	cmd.Dir = filepath.Dir(path)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		cmd.Dir, cmd.Args[len(cmd.Args)-1] = path, "."
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	return classify(ctx, code, op, cmd.Run())
+}
+
+// classify turns a command's error into an *Error by HOW it failed: past
+// the deadline is a timeout, a non-zero exit is the caller's code (the
+// lesson or its tests failed), anything else — go not on PATH, a directory
+// that vanished — is I/O.
+func classify(ctx context.Context, code Code, op string, err error) error {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return E(CodeTimeout, op, ctx.Err())
+	case errors.As(err, &exitErr):
+		return E(code, op, err)
+	}
+	return E(CodeIO, op, err)
+}
+
+func (c *CLI) run(args []string) error {
+	var timeout time.Duration
+	var traceOn bool
+	args, err := c.flags("run", args, &timeout, func(fs *flag.FlagSet) {
+		fs.BoolVar(&traceOn, "trace", false, "print a timing breakdown of the build and each section to stderr")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "run", "cli.want-one-topic")
+	}
+	path, err := c.find("run", args[0])
+	if err != nil {
+		return err
+	}
+	var tracer *trace.Tracer // nil: every span call is a no-op
+	if traceOn {
+		tracer = trace.New()
+	}
+	err = runLesson(path, "", timeout, tracer, c.Stdout, c.Stderr)
+	if tracer != nil {
+		fmt.Fprintln(c.Stderr)
+		trace.ExportText(c.Stderr, tracer.Spans()) // A failed run is still worth timing
+	}
+	return err
+}
+
+// verify checks every topic and reports every failure, joined; ExitCode
+// picks the most severe.
+func (c *CLI) verify(args []string) error {
+	var timeout time.Duration
+	args, err := c.flags("verify", args, &timeout)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return M(CodeUsage, "verify", "cli.want-topics")
+	}
+	var errs []error
+	for _, topic := range args {
+		err := c.verifyOne(topic, timeout)
+		status := "ok  "
+		if err != nil {
+			status = "FAIL"
+			errs = append(errs, err)
+		}
+		fmt.Fprintf(c.Stdout, "%s %s\n", status, topic)
+	}
+	return errors.Join(errs...)
+}
+
+// verifyOne runs the lesson in a scratch directory with its output
+// captured; a failure carries the output's last line.
+func (c *CLI) verifyOne(topic string, timeout time.Duration) error {
+	path, err := c.find("verify", topic)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp("", "gotut-verify-")
+	if err != nil {
+		return E(CodeIO, "verify", err)
+	}
+	defer os.RemoveAll(tmp)
+	var out bytes.Buffer
+	return withTail(runLesson(path, tmp, timeout, nil, &out, &out), &out)
+}
+
+// runLesson builds the lesson and runs the binary in dir ("" = here).
+// Not "go run": on a timeout the kill would hit the go command and leave
+// the lesson running. A non-nil tracer times the build, the run, and each
+// of its sections (trace.go).
+func runLesson(path, dir string, timeout time.Duration, tracer *trace.Tracer, stdout, stderr io.Writer) error {
+	op := "run " + filepath.Base(path)
+	tmp, err := os.MkdirTemp("", "gotut-build-")
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "lesson")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx, span := tracer.Start(ctx, op)
+	defer span.End()
+
+	_, build := tracer.Start(ctx, "build")
+	err = goCmd(ctx, CodeVerify, "build "+filepath.Base(path), path, stdout, stderr, "build", "-o", bin)
+	build.End()
+	if err != nil {
+		return err
+	}
+	ctx, lesson := tracer.Start(ctx, "lesson")
+	defer lesson.End()
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, stdout, stderr
+	if tracer != nil {
+		sections := newSectionSpans(ctx, tracer, stdout)
+		defer sections.Close()
+		cmd.Stdout = sections
+	}
+	return classify(ctx, CodeVerify, op, cmd.Run())
+}
+
+// withTail adds the last line of a failed command's output to err, which
+// is usually the line that says why.
+func withTail(err error, out *bytes.Buffer) error {
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if last := lines[len(lines)-1]; last != "" {
+		var e *Error
+		if errors.As(err, &e) {
+			e.Err = fmt.Errorf("%w (%s)", e.Err, last)
+		}
+	}
+	return err
+}
+
+func (c *CLI) test(args []string) error {
+	args, err := c.flags("test", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "test", "cli.want-one-topic")
+	}
+	path, err := c.findExercise("test", args[0])
+	if err != nil {
+		return err
+	}
+	return goCmd(context.Background(), CodeTestFailed, "test "+filepath.Base(path), path, c.Stdout, c.Stderr, "test")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/deprecate"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut deprecations
+// ---------------------------------------------------------
+// Which deprecated APIs the course still calls. A name goes into
+// pkg/deprecate's registry when its replacement ships; the old function
+// stays as a shim that warns on stderr, and this lists what is left to
+// move — the shims, and the call sites of each:
+//
+//	gotut deprecations
+//	lessonFile → course.File
+//	  one copy in pkg/course instead of one per lesson, ...
+//	  172_run_summary.go:267  3 calls
+//	    172_run_summary.go:319
+//	    ...
+//
+// A shim with no calls left can be deleted; once every shim of a name is
+// gone, so can its notice. The listing is a report, not a check: it exits
+// 0 whatever it finds, as the warnings don't fail anything either.
+
+func (c *CLI) deprecations(args []string) error {
+	args, err := c.flags("deprecations", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return M(CodeUsage, "deprecations", "cli.no-arguments")
+	}
+	dirs, err := c.courseDirs("deprecations")
+	if err != nil {
+		return err
+	}
+	var shims []deprecate.Shim
+	var roots []string
+	for _, dir := range dirs {
+		if under(dir, roots) {
+			continue // A go.work module inside the course: walked already
+		}
+		roots = append(roots, dir)
+		found, err := deprecate.Find(dir)
+		if err != nil {
+			return E(CodeIO, "deprecations", err)
+		}
+		prefix, err := filepath.Rel(dirs[0], dir)
+		if err != nil {
+			return E(CodeInternal, "deprecations", err)
+		}
+		for _, s := range found {
+			s.Def.Filename = filepath.Join(prefix, s.Def.Filename)
+			for i := range s.Calls {
+				s.Calls[i].Filename = filepath.Join(prefix, s.Calls[i].Filename)
+			}
+			shims = append(shims, s)
+		}
+	}
+
+	calls := 0
+	for _, n := range deprecate.All() {
+		fmt.Fprintf(c.Stdout, "%s → %s\n  %s\n", n.Name, n.Use, n.Why)
+		left := 0
+		for _, s := range shims {
+			if s.Name != n.Name {
+				continue
+			}
+			left++
+			fmt.Fprintf(c.Stdout, "  %s:%d  %s\n", s.Def.Filename, s.Def.Line, plural(len(s.Calls), "call"))
+			for _, p := range s.Calls {
+				fmt.Fprintf(c.Stdout, "    %s:%d\n", p.Filename, p.Line)
+			}
+			calls += len(s.Calls)
+		}
+		if left == 0 {
+			fmt.Fprintln(c.Stdout, "  no shims left: the notice can go")
+		}
+		fmt.Fprintln(c.Stdout)
+	}
+	fmt.Fprintf(c.Stdout, "%s of %s left.\n", plural(calls, "call site"), plural(len(deprecate.All()), "deprecated name"))
+	return nil
+}
+
+// under reports whether dir is one of roots or inside one.
+func under(dir string, roots []string) bool {
+	for _, r := range roots {
+		if rel, err := filepath.Rel(r, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
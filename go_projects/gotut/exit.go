@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/msg"
+)
+
+// ---------------------------------------------------------
+// Part 2: The Exit-Code Contract
+// ---------------------------------------------------------
+// Scripts see one small number. Keep the set small, stable and documented
+// (gotut help prints it), and map codes onto it in ONE table:
+//
+//	0  ok
+//	1  runtime error         something broke: I/O, a timeout, a gotut bug
+//	2  usage error           the caller asked wrongly: fix the command line
+//	3  verification failure  a lesson didn't build or exited non-zero
+//	4  exercise failure      the exercise's tests ran and failed
+//
+// 2 matches what the flag package (and most Unix tools) already use for
+// usage errors. 1 is what Go gives a panic-free os.Exit(1) and what
+// "go run" gives for everything, so it is the catch-all.
+
+const (
+	ExitOK         = 0
+	ExitRuntime    = 1
+	ExitUsage      = 2
+	ExitVerify     = 3
+	ExitTestFailed = 4
+)
+
+var exitCodes = map[Code]int{
+	CodeInternal:     ExitRuntime,
+	CodeIO:           ExitRuntime,
+	CodeTimeout:      ExitRuntime,
+	CodeUsage:        ExitUsage,
+	CodeUnknownTopic: ExitUsage,
+	CodeVerify:       ExitVerify,
+	CodeTestFailed:   ExitTestFailed,
+}
+
+const exitHelp = `Exit status:
+  0  ok
+  1  runtime error (I/O, timeout, internal)
+  2  usage error (bad flags or arguments, unknown topic)
+  3  verification failure (a lesson didn't build or exited non-zero)
+  4  exercise failure (tests ran and failed)
+`
+
+// ExitCode maps err onto the contract. For errors.Join (verify reports
+// every lesson) the HIGHEST code wins: a run with one broken lesson and
+// one failed test still says "4", so a script's most specific branch runs.
+func ExitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		code := ExitOK
+		for _, e := range joined.Unwrap() {
+			code = max(code, ExitCode(e))
+		}
+		return code
+	}
+	if code, ok := exitCodes[CodeOf(err)]; ok {
+		return code
+	}
+	return ExitRuntime
+}
+
+// report prints err for a person, in their language, and returns the
+// status for the shell. Usage errors get a pointer to help; the others
+// speak for themselves.
+func report(w io.Writer, lang string, err error) int {
+	code := ExitCode(err)
+	if code == ExitOK {
+		return code
+	}
+	fmt.Fprintln(w, "gotut:", Localize(err, lang))
+	if code == ExitUsage {
+		fmt.Fprintln(w, msg.T(lang, "cli.see-help"))
+	}
+	return code
+}
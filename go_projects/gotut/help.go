@@ -0,0 +1,404 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/course"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/registry"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/textwrap"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut help
+// ---------------------------------------------------------
+// Eleven commands, some with subcommands and flags of their own, outgrew
+// one usage string. Every command is now an entry in a table — what it
+// takes, what it does, examples — and the help is generated from it:
+//
+//	gotut help                  every command, one line each
+//	gotut help COMMAND          its usage, description, flags and examples
+//	gotut COMMAND -h            the same
+//	gotut help topics [WORD]    the course's lessons, from pkg/registry
+//
+// A command's flags aren't written down twice. "help run" calls run with
+// -h, and flags prints the page with the FlagSet run just defined, so the
+// help lists exactly the flags the command parses, with their defaults.
+// Pages are templates; text wraps to the terminal's width, $COLUMNS.
+
+// command is one gotut command: what Run calls, and what help says.
+// The fields are exported for the page templates.
+type command struct {
+	Name     string // "run", or "review export" for a subcommand
+	Args     string // What follows the name in a usage line
+	Summary  string // One line, for the list
+	Doc      string // Paragraphs for its page; a line starting with a space is kept as it is
+	Examples []example
+	Run      func(c *CLI, args []string) error
+	Subs     []*command // review's export and import, tool's migrate; Run dispatches to them
+}
+
+type example struct {
+	Cmd, Says string
+}
+
+// commands is filled in init: Run reaches back to it through flags and
+// help, a cycle Go won't order for a variable's initializer.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{Name: "run", Args: "[-timeout D] [-trace] TOPIC", Summary: "run a lesson",
+			Doc: "Runs the lesson for TOPIC with go run: the NNN_name.go file, or the NNN_name/ package. " +
+				"Its output is passed through; its exit status is gotut's verification failure, 3. " +
+				"With -trace, the time spent building it and in each of its sections follows on stderr.",
+			Examples: []example{{"gotut run 153", "runs 153_*.go"}, {"gotut run -timeout 5s 73", "stops it after five seconds: exit 1"},
+				{"gotut run --trace 113", "and then which of 113's examples took the time"}},
+			Run: (*CLI).run},
+		{Name: "verify", Args: "[-timeout D] TOPIC...", Summary: "build and run lessons; report every failure",
+			Doc: "Builds and runs each TOPIC and reports every failure, not just the first. " +
+				"The exit status is the most severe: one lesson that doesn't build and one whose tests fail is 4.",
+			Examples: []example{{"gotut -dir go_projects verify 153 175", "checks two lessons, as CI does"}},
+			Run:      (*CLI).verify},
+		{Name: "test", Args: "TOPIC", Summary: "run a lesson package's tests",
+			Doc: "Runs go test in TOPIC's exercise: the first of its NNN_name/ packages that has tests. " +
+				"Failing tests are exit 4, an exercise failure.",
+			Examples: []example{{"gotut test 73", "tests 73_regex_exercise/"}},
+			Run:      (*CLI).test},
+		{Name: "check", Args: "[-watch [-bell] [-interval D]] TOPIC", Summary: "test an exercise and summarize; with -watch, on every save",
+			Doc: "Runs TOPIC's exercise tests like test, and prints the failing tests with their messages and a count. " +
+				"With -watch it keeps running and checks again whenever a .go file in the exercise is saved; " +
+				"Ctrl-C ends it with the last run's result as the exit status. Colors are on for a terminal, unless $NO_COLOR is set.",
+			Examples: []example{{"gotut check -watch 73", "edit 73_regex_exercise/ in another window; results appear on save"},
+				{"gotut check -watch -bell -interval 1s 73", "a bell after each run, for when the terminal is behind the editor"}},
+			Run: (*CLI).check},
+		{Name: "hint", Args: "[-level N] TOPIC", Summary: "the next hint for a topic's exercise",
+			Doc: "Shows a level of the exercise's hints.md: 1 is the idea, 2 the API, 3 code. " +
+				"Levels open in order, and each one read goes into the progress log.",
+			Examples: []example{{"gotut hint 73", "the next level not yet read"}, {"gotut hint 73 -level 2", "level 2, once level 1 has been read"}},
+			Run:      (*CLI).hint},
+		{Name: "solution", Args: "[-diff [-failed]] TOPIC", Summary: "the exercise's reference solution, or your diff to it",
+			Doc: "Prints the reference solution next to the exercise (X.go.solution for X.go). " +
+				"-diff shows a unified diff from your file to it; with -failed, only the functions whose tests fail.",
+			Examples: []example{{"gotut solution 73 -diff -failed", "the answers to what's still failing, and nothing else"}},
+			Run:      (*CLI).solution},
+		{Name: "review", Args: "export|import ...", Summary: "signed peer-review bundles of your exercises",
+			Doc: "Packs exercises and their test results into one file signed with the group's key, " +
+				"$GOTUT_REVIEW_KEY, or checks and shows a peer's. A bundle whose signature fails is exit 3, and nothing in it is shown.",
+			Run: (*CLI).review,
+			Subs: []*command{
+				{Name: "review export", Args: "[-o FILE] [-name NAME] TOPIC...", Summary: "sign your exercises and test results into one file",
+					Examples: []example{{"gotut review export -o ada.tar.gz 73 82", "two exercises, tested and signed"}},
+					Run:      (*CLI).reviewExport},
+				{Name: "review import", Args: "[-mine] [-into DIR] FILE", Summary: "check a peer's bundle; their results and diffs",
+					Examples: []example{{"gotut review import -mine ada.tar.gz", "their files against yours"}},
+					Run:      (*CLI).reviewImport},
+			}},
+		{Name: "kata", Args: "[-grade] [-workdir D] [NAME]", Summary: "timed challenges with hidden tests",
+			Doc: "Without a NAME, lists the katas and your last result in each. With one, writes it into -workdir " +
+				"and starts the clock; -grade runs the hidden tests against your file. A pass after the time limit still exits 0.",
+			Examples: []example{{"gotut kata dedupe", "start"}, {"gotut kata -grade dedupe", "grade: exit 4 until it passes"}},
+			Run:      (*CLI).kata},
+		{Name: "deprecations", Summary: "deprecated APIs the course still calls, and where",
+			Doc: "Lists every name in pkg/deprecate's registry, what replaces it, and the shims left in the course " +
+				"with their call sites. A shim with no calls left can be deleted. It is a report: it exits 0 whatever it finds.",
+			Examples: []example{{"gotut deprecations", "what is left to move"}},
+			Run:      (*CLI).deprecations},
+		{Name: "record", Args: "[-out FILE] [-cast FILE [-size COLSxROWS]] TOPIC", Summary: "run a lesson through 176 and save it with its timing",
+			Doc: "Runs TOPIC through 176's run --format json, shows its output, and saves the events in a session file. " +
+				"-cast also writes an asciicast v2 file for asciinema. A lesson that fails is still recorded, and exits 3.",
+			Examples: []example{{"gotut record -out session.json 85", "run Topic 85 and save it"}, {"gotut record -cast 85.cast 85", "an asciicast too"}},
+			Run:      (*CLI).record},
+		{Name: "replay", Args: "[-speed N] [-cast FILE [-size COLSxROWS]] FILE", Summary: "play a recorded session back at its pace",
+			Doc: "Prints a session's output again, each line when it came out, or -speed N times as fast. " +
+				"With -cast it writes the session as an asciicast instead of playing it.",
+			Examples: []example{{"gotut replay -speed 4 session.json", "four times as fast"}, {"gotut replay -cast 85.cast -size 100x30 session.json", "an existing session as a cast"}},
+			Run:      (*CLI).replay},
+		{Name: "practice", Args: "[-n N] [-seed S] [-stats] [CONCEPT|TOPIC...]", Summary: "generated fmt and regex problems, checked by running them",
+			Doc: "Makes up N problems, a format string to write or a pattern to match a set of strings, and reads an answer to each from stdin. " +
+				"An answer is right if it behaves right, including on values it wasn't shown. " +
+				"Each answer goes into the progress log; concepts answered wrong come back first. Limit it to CONCEPTs or TOPIC numbers; -stats shows each concept's record.",
+			Examples: []example{{"gotut practice", "5 problems, the most due concepts first"},
+				{"gotut practice -n 3 73", "regular expressions only"},
+				{"gotut practice -stats", "accuracy, box and next review per concept"}},
+			Run: (*CLI).practice},
+		{Name: "type", Args: "[-n N] [-seed S] [-bests] [SNIPPET|TOPIC...]", Summary: "a typing drill on Go lines from the lessons",
+			Doc: "Shows N snippets of Go from the lessons, one at a time, and times you typing each: words per minute, and the share of keys that were right. " +
+				"On a terminal every key counts as it is typed, and a wrong one has to be deleted; Esc ends the session. Piped, each line read is a line typed. " +
+				"Finished snippets go into the progress log; -bests shows the fastest of each.",
+			Examples: []example{{"gotut type", "3 snippets from anywhere in the course"},
+				{"gotut type -n 1 142", "one from Topic 142"},
+				{"gotut type -bests", "your personal best per snippet"}},
+			Run: (*CLI).typingDrill},
+		{Name: "tmpl-check", Args: "[-data FILE] DIR", Summary: "lint DIR/*.tmpl: syntax, functions, fields",
+			Doc: "Parses every .tmpl file in DIR as one set and reports undefined functions and, given sample data as JSON, " +
+				"fields the data doesn't have, on every branch. Problems are exit 3.",
+			Examples: []example{{"gotut tmpl-check -data sample.json templates", "as a CI step, before the templates ship"}},
+			Run:      (*CLI).tmplCheck},
+		{Name: "tool", Args: "migrate ...", Summary: "the course's runners, for your own files",
+			Doc: "Runs a tool from a lesson on files you give it. " +
+				"migrate is Topic 148's migration runner, on a directory of NNNN_name.up.sql and .down.sql files and a toy database kept in a file.",
+			Run: (*CLI).tool,
+			Subs: []*command{
+				{Name: "tool migrate", Args: "[-db FILE] DIR status|up [N]|down [N]|force VERSION", Summary: "apply, revert or show DIR's migrations",
+					Doc: "Applies DIR's migrations to pkg/migrate/memdb's database, kept in -db between runs. " +
+						"It tracks tables, not rows: enough to find a migration that doesn't apply or doesn't revert. " +
+						"A migration that fails leaves the database dirty, and every command but force refuses to run: exit 3.",
+					Examples: []example{{"gotut tool migrate migrations up", "apply every pending migration"},
+						{"gotut tool migrate migrations down 2", "and check the last two revert"},
+						{"gotut tool migrate migrations force 3", "after fixing a dirty 0004 by hand"}},
+					Run: (*CLI).toolMigrate},
+			}},
+		{Name: "alias", Args: "NAME [=] COMMAND... | NAME | -d NAME", Summary: "define, show or delete a shortcut",
+			Doc: "Saves a shortcut in config.json, in $GOTUT_CONFIG_DIR or your config directory: " +
+				"'gotut NAME ARGS...' then runs 'gotut COMMAND... ARGS...'. " +
+				"A COMMAND may start with another alias; one that leads back to itself, or a NAME a command already has, is refused.",
+			Examples: []example{{"gotut alias re = run 73", "then 'gotut re -timeout 5s' runs 'gotut run 73 -timeout 5s'"},
+				{"gotut alias check = tmpl-check -data sample.json templates", "a CI step in one word"},
+				{"gotut alias -d re", "delete it"}},
+			Run: (*CLI).alias},
+		{Name: "aliases", Summary: "list your shortcuts",
+			Examples: []example{{"gotut aliases", "each NAME = COMMAND..., sorted"}},
+			Run:      (*CLI).aliases},
+	}
+}
+
+// lookupCommand finds a command or subcommand by its full name.
+func lookupCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+		for _, sub := range cmd.Subs {
+			if sub.Name == name {
+				return sub
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CLI) help(args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.render(overviewPage, map[string]any{"Commands": commands, "Exits": exitRows()})
+	case args[0] == "topics":
+		return c.helpTopics(args[1:])
+	}
+	name := strings.Join(args, " ")
+	cmd := lookupCommand(name)
+	if cmd == nil {
+		return c.aliasHelp(name)
+	}
+	if cmd.Subs != nil {
+		return c.render(groupPage, cmd)
+	}
+	// The command parses -h and prints its own page, flags and all.
+	if err := cmd.Run(c, []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return nil
+}
+
+// commandHelp prints name's page, with the flags fs defines. flags calls
+// it for -h.
+func (c *CLI) commandHelp(name string, fs *flag.FlagSet) error {
+	cmd := lookupCommand(name)
+	if cmd == nil {
+		return c.help(nil)
+	}
+	type flagDoc struct{ Name, Usage string }
+	var flags []flagDoc
+	fs.VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		if arg != "" {
+			arg = " " + arg
+		}
+		if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		flags = append(flags, flagDoc{"-" + f.Name + arg, usage})
+	})
+	return c.render(commandPage, map[string]any{"Command": cmd, "Flags": flags})
+}
+
+// ---------------------------------------------------------
+// Topics
+// ---------------------------------------------------------
+
+// courseTopics is the course's topics, from every directory it is in.
+func (c *CLI) courseTopics() (*registry.Registry, error) {
+	dirs, err := c.courseDirs("help")
+	if err != nil {
+		return nil, err
+	}
+	reg, err := course.Topics(dirs...)
+	if err != nil {
+		return nil, E(CodeIO, "help", err)
+	}
+	return reg, nil
+}
+
+// helpTopics lists the course's topics, or those whose number, slug or
+// title contains words.
+func (c *CLI) helpTopics(words []string) error {
+	reg, err := c.courseTopics()
+	if err != nil {
+		return err
+	}
+	var shown []registry.Topic
+	slugWidth := 0
+	for _, t := range reg.All() {
+		text := strings.ToLower(fmt.Sprint(t.ID, " ", t.Slug, " ", t.Title))
+		match := true
+		for _, w := range words {
+			match = match && strings.Contains(text, strings.ToLower(w))
+		}
+		if match {
+			shown = append(shown, t)
+			slugWidth = max(slugWidth, min(len(t.Slug), 24))
+		}
+	}
+	return c.render(topicsPage, map[string]any{"Topics": shown, "Dir": c.Dir, "All": reg.Len(),
+		"Filter": strings.Join(words, " "), "SlugWidth": slugWidth})
+}
+
+// ---------------------------------------------------------
+// Pages
+// ---------------------------------------------------------
+
+var (
+	overviewPage = page("overview", `usage: {{hang 7 "gotut [-dir COURSE] [-lang LANG] <command> [arguments]"}}
+
+Commands:
+{{range .Commands}}  {{pad .Name 14}}{{hang 16 .Summary}}
+{{end}}
+{{wrap 0 "Run 'gotut help COMMAND' for a command's flags and examples, and 'gotut help topics' for the course's lessons."}}
+
+Exit status:
+{{range .Exits}}  {{.Code}}  {{hang 5 .Meaning}}
+{{end -}}
+`)
+
+	commandPage = page("command", `{{with .Command}}usage: {{hang 7 (printf "gotut %s %s" .Name .Args)}}
+{{with .Doc}}
+{{wrap 2 .}}
+{{end}}{{end}}{{with .Flags}}
+Flags:
+{{range .}}  {{pad .Name 20}}{{hang 22 .Usage}}
+{{end}}{{end}}{{with .Command.Examples}}
+Examples:
+{{range .}}  $ {{.Cmd}}
+{{wrap 6 .Says}}
+{{end}}{{end -}}
+`)
+
+	groupPage = page("group", `usage: {{hang 7 (printf "gotut %s %s" .Name .Args)}}
+
+{{wrap 2 .Doc}}
+
+Commands:
+{{range .Subs}}  gotut {{.Name}} {{.Args}}
+{{wrap 6 .Summary}}
+{{end}}
+{{wrap 0 (printf "Run 'gotut help %s SUBCOMMAND' for one's flags and examples." .Name)}}
+`)
+
+	topicsPage = page("topics", `{{if .Filter}}{{len .Topics}} of {{.All}} topics in {{.Dir}} match "{{.Filter}}"{{else}}{{.All}} topics in {{.Dir}}{{end}}:
+{{range .Topics}}  {{printf "%4d" .ID}}  {{pad .Slug (add $.SlugWidth 2)}}{{hang (add $.SlugWidth 10) .Title}}
+{{end}}{{if .Topics}}
+{{wrap 0 "Run 'gotut run N' for one; its files are N_* in the course directory."}}
+{{end -}}
+`)
+)
+
+// exitRows splits exitHelp's table, which the overview wraps like the
+// rest of the page, into codes and meanings.
+func exitRows() []struct{ Code, Meaning string } {
+	var rows []struct{ Code, Meaning string }
+	for l := range strings.Lines(exitHelp) {
+		code, meaning, ok := strings.Cut(strings.TrimSpace(l), "  ")
+		if ok {
+			rows = append(rows, struct{ Code, Meaning string }{code, meaning})
+		}
+	}
+	return rows
+}
+
+// page parses a help template. Its functions depend on the width, so
+// render binds them again on a clone; these are for the parse.
+func page(name, text string) *template.Template {
+	return template.Must(template.New(name).Funcs(pageFuncs(80)).Parse(text))
+}
+
+func pageFuncs(width int) template.FuncMap {
+	return template.FuncMap{
+		"wrap": func(indent int, text string) string { return wrap(text, indent, indent, width) },
+		"hang": func(col int, text string) string { return strings.TrimLeft(wrap(text, col, col, width), " ") },
+		// pad fills s out to n columns, leaving at least one space after it.
+		"pad": func(s string, n int) string {
+			return s + strings.Repeat(" ", max(n-utf8.RuneCountInString(s), 1))
+		},
+		"add": func(a, b int) int { return a + b },
+	}
+}
+
+func (c *CLI) render(t *template.Template, data any) error {
+	t, err := t.Clone()
+	if err == nil {
+		err = t.Funcs(pageFuncs(c.width())).Execute(c.Stdout, data)
+	}
+	if err != nil {
+		return E(CodeInternal, "help", err)
+	}
+	return nil
+}
+
+// width is where help wraps: c.Width, or $COLUMNS, or 80; never under 40.
+func (c *CLI) width() int {
+	w := c.Width
+	if w == 0 {
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil {
+			w = n // Unset or not a number is the default
+		}
+	}
+	if w == 0 {
+		w = 80
+	}
+	return max(w, 40)
+}
+
+// wrap fills text's paragraphs to width with textwrap.WrapIndent: the
+// first line starts at column first, the rest at indent. Blank lines
+// separate paragraphs; a line that starts with a space is kept as it is.
+func wrap(text string, first, indent, width int) string {
+	lead, rest := strings.Repeat(" ", first), strings.Repeat(" ", indent)
+	var paras []string
+	for _, para := range strings.Split(strings.TrimRight(text, "\n"), "\n\n") {
+		var lines, prose []string
+		flush := func() {
+			if len(prose) > 0 {
+				lines = append(lines, textwrap.WrapIndent(strings.Join(prose, " "), width, lead, rest))
+				lead, prose = rest, nil
+			}
+		}
+		for _, line := range strings.Split(para, "\n") {
+			if strings.HasPrefix(line, " ") {
+				flush()
+				lines = append(lines, lead+line)
+				lead = rest
+				continue
+			}
+			prose = append(prose, line)
+		}
+		flush()
+		paras = append(paras, strings.Join(lines, "\n"))
+	}
+	return strings.Join(paras, "\n\n")
+}
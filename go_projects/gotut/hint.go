@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut hint
+// ---------------------------------------------------------
+// An exercise's hints live next to it, in its package directory's
+// hints.md, one "## Level N" section per level:
+//
+//	level 1  the idea: what to reach for, in words
+//	level 2  the API: which functions, and the traps in them
+//	level 3  code: enough of the answer to finish it
+//
+// Levels open in order, so a learner sees the idea before the code.
+// Every level read goes into the progress log (pkg/progress) as a "hint"
+// event, which is what an instructor's report counts: "read level 3 of
+// topic 73" says more about an exercise than its passing tests do.
+//
+//	gotut hint TOPIC              the next level not yet read
+//	gotut hint TOPIC --level N    level N, if 1..N-1 have been read
+
+// hintsFile is the name of the hints next to an exercise.
+const hintsFile = "hints.md"
+
+var levelHeading = regexp.MustCompile(`^##\s+Level\s+(\d+)\s*$`)
+
+// loadHints returns the text of each level, level 1 first. Anything
+// before the first level is the file's own introduction and is skipped.
+func loadHints(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var levels []string
+	var cur *strings.Builder
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if m := levelHeading.FindStringSubmatch(sc.Text()); m != nil {
+			if cur != nil {
+				levels = append(levels, strings.TrimSpace(cur.String()))
+			}
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n != len(levels)+1 {
+				return nil, fmt.Errorf("%s: level %s follows level %d", path, m[1], len(levels))
+			}
+			cur = &strings.Builder{}
+			continue
+		}
+		if cur != nil {
+			cur.WriteString(sc.Text() + "\n")
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		levels = append(levels, strings.TrimSpace(cur.String()))
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%s: no \"## Level 1\" section", path)
+	}
+	return levels, nil
+}
+
+// hintsRead is the highest level of topic's hints in the log.
+func hintsRead(log *progress.Log, topic string) int {
+	read := 0
+	for _, e := range log.Filter("hint") {
+		if n, err := strconv.Atoi(strings.TrimPrefix(e.Result, "level ")); err == nil && e.Item == topic {
+			read = max(read, n)
+		}
+	}
+	return read
+}
+
+func (c *CLI) hint(args []string) error {
+	var level int
+	args, err := c.flags("hint", args, nil, func(fs *flag.FlagSet) {
+		fs.IntVar(&level, "level", 0, "the level to show (default: the next one)")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "hint", "cli.want-one-topic")
+	}
+	dir, err := c.findExercise("hint", args[0])
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return E(CodeInternal, "hint", err) // findExercise has checked it
+	}
+	topic := strconv.Itoa(n) // "073" and "73" are one topic in the log
+	op := "hint " + filepath.Base(dir)
+	levels, err := loadHints(filepath.Join(dir, hintsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return M(CodeUsage, op, "cli.no-hints", filepath.Base(dir))
+	} else if err != nil {
+		return E(CodeIO, op, err)
+	}
+
+	logPath, err := progress.Path()
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	log, err := progress.Load(logPath)
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	read := hintsRead(log, topic)
+	if level == 0 {
+		level = min(read+1, len(levels))
+	}
+	switch {
+	case level < 1 || level > len(levels):
+		return M(CodeUsage, op, "cli.hint-level", level, len(levels))
+	case level > read+1:
+		return M(CodeUsage, op, "cli.hint-locked", level, read+1, topic)
+	}
+
+	err = progress.Record(logPath, progress.Event{Kind: "hint", Item: topic, Result: fmt.Sprintf("level %d", level),
+		Detail: filepath.Base(dir)})
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	fmt.Fprintf(c.Stdout, "%s: hint %d of %d\n\n%s\n\n", filepath.Base(dir), level, len(levels), levels[level-1])
+	switch {
+	case level == len(levels):
+		fmt.Fprintln(c.Stdout, "That was the last level. Reading it is in your progress log for your instructor.")
+	case level == read+1:
+		fmt.Fprintf(c.Stdout, "Still stuck? gotut hint %s --level %d\n", topic, level+1)
+	}
+	return nil
+}
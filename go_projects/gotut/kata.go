@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/kata"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut kata
+// ---------------------------------------------------------
+// Timed challenges with hidden tests (pkg/kata, Topic 192). Unlike a
+// topic's exercise, a kata isn't part of the course directory: it is
+// written into a work directory of the learner's, and its tests stay in
+// the binary. Every start and grade goes into the progress log.
+//
+//	gotut kata                              list them, with your last result
+//	gotut kata [-workdir D] NAME            write D/NAME/kata.go; the clock starts
+//	gotut kata -grade [-workdir D] NAME     run the hidden tests against it
+//
+// A failed grade is exit 4, an exercise failure; a pass after the time
+// limit is still exit 0 — it works — and the log says "late".
+
+func (c *CLI) kata(args []string) error {
+	var timeout time.Duration
+	var grade bool
+	var workdir string
+	args, err := c.flags("kata", args, &timeout, func(fs *flag.FlagSet) {
+		fs.BoolVar(&grade, "grade", false, "grade the kata instead of starting it")
+		fs.StringVar(&workdir, "workdir", ".", "where kata directories are written")
+	})
+	if err != nil {
+		return err
+	}
+	path, err := progress.Path()
+	if err != nil {
+		return E(CodeIO, "kata", err)
+	}
+	log, err := progress.Load(path)
+	if err != nil {
+		return E(CodeIO, "kata", err)
+	}
+	switch {
+	case len(args) == 0 && !grade:
+		return c.listKatas(log)
+	case len(args) != 1:
+		return M(CodeUsage, "kata", "cli.want-one-kata")
+	}
+	k, ok := kata.Get(args[0])
+	if !ok {
+		return M(CodeUsage, "kata", "cli.no-kata", args[0])
+	}
+	if grade {
+		return c.gradeKata(k, workdir, timeout, path, log)
+	}
+	return c.startKata(k, workdir, path, log)
+}
+
+func (c *CLI) listKatas(log *progress.Log) error {
+	tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KATA\tLIMIT\tLAST\tTITLE")
+	for _, k := range kata.All() {
+		last := "-"
+		if e, ok := log.Last("kata", k.Name); ok {
+			last = e.Result
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%s\t%s\n", k.Name, k.Limit, last, k.Title)
+	}
+	return tw.Flush()
+}
+
+// startKata writes the starter and starts the clock. Run again on a kata
+// already started, it keeps both the file and the original start time:
+// re-running the command is not a way to get more time.
+func (c *CLI) startKata(k kata.Kata, workdir, logPath string, log *progress.Log) error {
+	file, err := k.Start(workdir)
+	switch {
+	case errors.Is(err, os.ErrExist):
+		if e, ok := log.Last("kata", k.Name, "started"); ok {
+			fmt.Fprintf(c.Stdout, "%s: already started %s ago; keeping %s\n", k.Name, time.Since(e.Time).Round(time.Second), file)
+			return nil
+		}
+	case err != nil:
+		return E(CodeIO, "kata "+k.Name, err)
+	}
+	if err := progress.Record(logPath, progress.Event{Kind: "kata", Item: k.Name, Result: "started"}); err != nil {
+		return E(CodeIO, "kata "+k.Name, err)
+	}
+	fmt.Fprintf(c.Stdout, "%s: %s (%v)\n  %s\n  Edit %s, then: gotut kata -grade %s\n",
+		k.Name, k.Title, k.Limit, k.Prompt, file, k.Name)
+	return nil
+}
+
+func (c *CLI) gradeKata(k kata.Kata, workdir string, timeout time.Duration, logPath string, log *progress.Log) error {
+	op := "kata " + k.Name
+	file := filepath.Join(workdir, k.Name, kata.File)
+	src, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return M(CodeUsage, op, "cli.kata-not-started", file, k.Name)
+	} else if err != nil {
+		return E(CodeIO, op, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := k.Grade(ctx, src)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return E(CodeTimeout, op, fmt.Errorf("the hidden tests didn't finish in %v: an endless loop?", timeout))
+	} else if err != nil {
+		return E(CodeIO, op, err)
+	}
+
+	var elapsed time.Duration
+	if e, ok := log.Last("kata", k.Name, "started"); ok {
+		elapsed = time.Since(e.Time)
+	}
+	verdict := k.Verdict(res, elapsed)
+	detail := fmt.Sprintf("%d/%d", res.Passed(), len(res.Tests))
+	if res.Build != "" {
+		detail = "does not compile"
+	}
+	err = progress.Record(logPath, progress.Event{Kind: "kata", Item: k.Name, Result: verdict,
+		Detail: detail, Seconds: elapsed.Seconds()})
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+
+	if res.Build != "" {
+		fmt.Fprintf(c.Stdout, "%s does not compile:\n", file)
+		for l := range strings.Lines(res.Build) {
+			fmt.Fprint(c.Stdout, "  ", l)
+		}
+		fmt.Fprintln(c.Stdout)
+		return E(CodeTestFailed, op, "does not compile")
+	}
+	for _, t := range res.Tests {
+		if t.Pass {
+			fmt.Fprintf(c.Stdout, "  ✓ %s\n", t.Name)
+		} else {
+			fmt.Fprintf(c.Stdout, "  ✗ %s: %s\n", t.Name, t.Message)
+		}
+	}
+	took := "time not recorded"
+	if elapsed > 0 {
+		took = fmt.Sprintf("%v of %v", elapsed.Round(time.Second), k.Limit)
+	}
+	fmt.Fprintf(c.Stdout, "%s: %s hidden tests pass (%s): %s\n", k.Name, detail, took, verdict)
+	if verdict == "fail" {
+		return E(CodeTestFailed, op, fmt.Sprintf("%s hidden tests pass", detail))
+	}
+	return nil
+}
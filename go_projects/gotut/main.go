@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/msg"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/ring"
+)
+
+/*
+GOTUT — THE COURSE'S COMMAND-LINE TOOL
+
+gotut runs, verifies and tests the course's lessons, and around them the
+exercise tools: hints, solutions, katas, practice problems, a typing
+drill, peer-review bundles. `gotut help` lists the commands; `gotut help
+COMMAND` prints one's page. Topic 175 (go_projects/175_exitcodes.go) is
+the lesson on its exit-code contract:
+
+    0  ok
+    1  runtime error          I/O, timeout, a bug in gotut
+    2  usage error            bad flags or arguments, unknown topic
+    3  verification failure   a lesson didn't build or exited non-zero
+    4  exercise failure       the tests ran and failed
+
+Errors carry a fine-grained code from where they happen (apperrors.go);
+one table maps codes to exit statuses (exit.go); main is the only place
+that calls os.Exit.
+
+    apperrors.go  → Code, *Error, E(), CodeOf — the codes errors carry;
+                    M() and Localize for messages in the user's language
+    exit.go       → the contract, ExitCode(err), report()
+    cli.go        → CLI.Run, its crash reports (pkg/safe, Topic 139),
+                    and run / verify / test
+    trace.go      → gotut run --trace: spans for the build and each
+                    section (pkg/trace, Topic 140)
+    hint.go       → gotut hint: an exercise's hints, one level at a time
+    solution.go   → gotut solution: the reference, or a diff against it
+    review.go     → gotut review: signed bundles for peer review (pkg/bundle)
+    kata.go       → gotut kata: timed challenges with hidden tests (Topic 192)
+    deprecations.go → gotut deprecations: shims and their callers (pkg/deprecate)
+    record.go     → gotut record / replay: lesson runs with timing (Topic 176)
+    cast.go       → sessions as asciicast v2 files, for asciinema
+    practice.go   → gotut practice: generated fmt and regex problems,
+                    scheduled per concept (pkg/practice)
+    typing.go     → gotut type: a typing drill on lines from the lessons,
+                    raw key input (pkg/term), personal bests (pkg/typing)
+    check.go      → gotut check: test summaries, and -watch to re-run on save
+    tmplcheck.go  → gotut tmpl-check: a template linter (intermediate Topic 72)
+    migrate.go    → gotut tool migrate: Topic 148's runner (pkg/migrate)
+                    on a directory of .sql files
+    help.go       → gotut help: pages generated from the command table
+    aliases.go    → gotut alias / aliases: shortcuts in config.json (pkg/alias)
+    main.go       → this overview, and main
+    main_test.go  → builds the binary and checks $? for every outcome
+    scenario_test.go → whole sessions through the binary: piped stdin,
+                    a fresh $HOME, the files left behind
+
+RUN (a module of its own, in the repository's go.work):
+    cd go_projects/gotut
+    go build -o gotut . && ./gotut -dir .. verify 153 175; echo $?
+    ./gotut -lang es verify 999; echo $?            → Spanish message, still 2
+    go test -v .
+    go test -v -run Scenarios .                     → only the end-to-end sessions
+"go run" itself exits 1 whatever the program's status ("exit status 2"),
+so build the binary to see the real $?.
+*/
+
+func main() {
+	// -dir and -lang come before the command, like go -C: they say where
+	// the course is and who is reading. Without -lang, the locale decides.
+	args := os.Args[1:]
+	dir, lang := ".", msg.FromEnv()
+	for len(args) >= 2 && (args[0] == "-dir" || args[0] == "-lang") {
+		if args[0] == "-dir" {
+			dir = args[1]
+		} else {
+			lang = args[1]
+		}
+		args = args[2:]
+	}
+	// A crash report carries the last lines gotut printed to stderr.
+	recent := ring.New(100)
+	cli := &CLI{Dir: dir, Stdin: os.Stdin, Stdout: os.Stdout, Stderr: io.MultiWriter(os.Stderr, recent), Recent: recent}
+	os.Exit(report(os.Stderr, msg.Match(lang), cli.Run(args)))
+}
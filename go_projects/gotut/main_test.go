@@ -0,0 +1,1187 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/bundle"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/kata"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/ring"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/term"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/typing"
+)
+
+// gotut is the binary under test, built once by TestMain.
+var gotut string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gotut-bin-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	gotut = filepath.Join(dir, "gotut")
+	build := exec.Command("go", "build", "-o", gotut, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building gotut: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// exitStatus runs the binary in the C locale, plus env, and returns $? and
+// stderr. Without the pinned locale a tester with LANG=fr_FR would get
+// French messages and failures.
+func exitStatus(t *testing.T, env []string, args ...string) (int, string) {
+	t.Helper()
+	var stderr strings.Builder
+	cmd := exec.Command(gotut, args...)
+	cmd.Env = append(append(os.Environ(), "LC_ALL=C"), env...)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0, stderr.String()
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode(), stderr.String()
+	}
+	t.Fatalf("running gotut: %v", err)
+	return 0, ""
+}
+
+// writeCourse lays out a tiny course with one lesson for each outcome.
+// The tests run the built binary against it. Like the real course, it
+// is a module: go test needs one.
+func writeCourse(dir string) error {
+	files := map[string]string{
+		"go.mod":        "module example.com/course\n\ngo 1.25\n",
+		"001_hello.go":  "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"hello\") }\n",
+		"002_broken.go": "package main\n\nfunc main() { undefined() }\n",
+		"003_exits.go":  "package main\n\nimport \"os\"\n\nfunc main() { os.Exit(7) }\n",
+		"004_sleeps.go": "package main\n\nimport \"time\"\n\nfunc main() { time.Sleep(time.Minute) }\n",
+		"005_sum/sum.go": "package main\n\nfunc Sum(xs ...int) int { return len(xs) } // The exercise: fix me\n\n" +
+			"func Neg(x int) int { return -x }\n\nfunc main() {}\n",
+		"005_sum/sum.go.solution": "package main\n\nfunc Sum(xs ...int) (total int) {\n\tfor _, x := range xs {\n\t\ttotal += x\n\t}\n" +
+			"\treturn total\n}\n\nfunc Neg(x int) int { return 0 - x }\n\nfunc main() {}\n",
+		"005_sum/sum_test.go": "package main\n\nimport \"testing\"\n\n" +
+			"func TestSum(t *testing.T) {\n\tif got := Sum(2, 3); got != 5 {\n\t\tt.Errorf(\"Sum(2, 3) = %d, want 5\", got)\n\t}\n}\n\n" +
+			"func TestNeg(t *testing.T) {\n\tif Neg(2) != -2 {\n\t\tt.Error(\"Neg(2) != -2\")\n\t}\n}\n",
+		"005_sum/hints.md": "# Hints\n\n## Level 1\n\nAdd them up.\n\n## Level 2\n\nrange over xs.\n\n" +
+			"## Level 3\n\n    for _, x := range xs { total += x }\n",
+		"006_done/done.go":      "package main\n\nfunc main() {}\n",
+		"006_done/done_test.go": "package main\n\nimport \"testing\"\n\nfunc TestDone(t *testing.T) {}\n",
+	}
+	for name, src := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExitCodes(t *testing.T) {
+	course := t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"help", ExitOK, ""},
+		{"run -h", ExitOK, ""},
+		{"verify 1", ExitOK, ""},
+		{"verify 1 6", ExitOK, ""},
+		{"test 6", ExitOK, ""},
+
+		{"run -timeout 1s 4", ExitRuntime, "deadline exceeded"},
+
+		{"--verbose", ExitUsage, `unknown command "--verbose"`},
+		{"launch 1", ExitUsage, `unknown command "launch"`},
+		{"run", ExitUsage, "exactly one TOPIC"},
+		{"run -timeout soon 1", ExitUsage, "invalid value"},
+		{"run -nope 1", ExitUsage, "-nope"},
+		{"run eighty", ExitUsage, "not a number"},
+		{"verify 42", ExitUsage, "no lesson 42"},
+		{"test 1", ExitUsage, "no tests"},
+		{"hint 1", ExitUsage, "no tests"},
+		{"hint 6", ExitUsage, "006_done has no hints"},
+		{"solution 6", ExitUsage, "006_done has no reference solution"},
+		{"review", ExitUsage, "review export"},
+		{"review import", ExitUsage, "exactly one bundle"},
+
+		{"verify 2", ExitVerify, "undefined"},
+		{"verify 3", ExitVerify, "exit status 7"},
+		{"run 3", ExitVerify, "exit status 7"},
+		{"verify 1 2 3", ExitVerify, "002_broken.go"},
+		{"verify 1 42 3", ExitVerify, "no lesson 42"}, // Every failure reported, most specific wins
+
+		{"test 5", ExitTestFailed, "test 005_sum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			args := append([]string{"-dir", course}, strings.Fields(tt.args)...)
+			got, stderr := exitStatus(t, nil, args...)
+			if got != tt.want {
+				t.Errorf("gotut %s: exit %d, want %d\nstderr: %s", tt.args, got, tt.want, stderr)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("gotut %s: stderr %q, want it to mention %q", tt.args, stderr, tt.stderr)
+			}
+			if got == ExitUsage && !strings.Contains(stderr, "gotut help") {
+				t.Errorf("gotut %s: usage error without a pointer to help: %q", tt.args, stderr)
+			}
+		})
+	}
+}
+
+// TestLanguages checks the message follows -lang or the locale, -lang
+// winning, while the exit status stays the same in every language.
+func TestLanguages(t *testing.T) {
+	course := t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		env    string
+		args   string
+		stderr []string
+	}{
+		{"", "verify 42", []string{"no lesson 42", "run 'gotut help'"}},
+		{"", "-lang es verify 42", []string{"no hay ninguna lección 42", "ejecute 'gotut help'"}},
+		{"LC_ALL=fr_FR.UTF-8", "run eighty", []string{`le sujet "eighty" n'est pas un nombre`}},
+		{"LC_ALL=fr_FR.UTF-8", "-lang es-MX run", []string{"run: se espera exactamente un TOPIC"}},
+		{"LC_ALL=pt_BR.UTF-8", "launch 1", []string{`unknown command "launch"`}}, // No pt catalog
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+" "+tt.args, func(t *testing.T) {
+			var env []string
+			if tt.env != "" {
+				env = []string{tt.env}
+			}
+			args := append([]string{"-dir", course}, strings.Fields(tt.args)...)
+			got, stderr := exitStatus(t, env, args...)
+			if got != ExitUsage {
+				t.Errorf("exit %d, want %d in every language\nstderr: %s", got, ExitUsage, stderr)
+			}
+			for _, want := range tt.stderr {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("stderr %q, want it to mention %q", stderr, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExitCodeMapping(t *testing.T) {
+	for code := range Code(len(codeNames)) {
+		if _, ok := exitCodes[code]; !ok {
+			t.Errorf("code %v has no exit status", code)
+		}
+	}
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{fmt.Errorf("wrapped: %w", E(CodeTimeout, "run", "slow")), ExitRuntime},
+		{E(CodeUsage, "run", E(CodeVerify, "inner", "x")), ExitUsage}, // Outermost code wins
+		{errors.Join(E(CodeTestFailed, "a", "x"), E(CodeUsage, "b", "y")), ExitTestFailed},
+		{errors.Join(errors.New("plain"), E(CodeUsage, "b", "y")), ExitUsage},
+		{errors.New("unclassified"), ExitRuntime},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+// A panic inside a command must not reach the runtime, whose exit status 2
+// would read as a usage error.
+func TestPanicIsInternal(t *testing.T) {
+	config := t.TempDir()
+	t.Setenv("GOTUT_CONFIG_DIR", config)
+	recent := ring.New(10)
+	fmt.Fprintln(recent, "before the panic")
+	err := (&CLI{Recent: recent}).Run([]string{"help"})
+	if CodeOf(err) != CodeInternal || ExitCode(err) != ExitRuntime {
+		t.Errorf("panic became %v (code %v, exit %d), want internal → 1", err, CodeOf(err), ExitCode(err))
+	}
+
+	// The stack isn't lost: it's in a report the error points to.
+	reports, _ := filepath.Glob(filepath.Join(config, "crashes", "crash-*.txt"))
+	if len(reports) != 1 {
+		t.Fatalf("crash reports: %q, want one", reports)
+	}
+	if !strings.Contains(err.Error(), "crash report saved to "+reports[0]) {
+		t.Errorf("the error doesn't name the report: %v", err)
+	}
+	data, err := os.ReadFile(reports[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"topic:", "help", "goroutine", "(*CLI).Run", "before the panic"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("the report has no %q:\n%s", want, data)
+		}
+	}
+}
+
+// TestRunTrace times a lesson's sections: one span each, under the run's,
+// while the output still reaches stdout as the lesson wrote it.
+func TestRunTrace(t *testing.T) {
+	course := t.TempDir()
+	lesson := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"TOPIC: TRACED\")\n" +
+		"\tfmt.Println(\"--- Example 1: First ---\")\n\tfmt.Println(\"one\")\n" +
+		"\tfmt.Print(\"--- Example 2: Second ---\\r\\ntwo\")\n}\n"
+	for name, src := range map[string]string{"go.mod": "module example.com/course\n\ngo 1.25\n", "001_traced.go": lesson} {
+		if err := os.WriteFile(filepath.Join(course, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	if err := (&CLI{Dir: course, Stdout: &stdout, Stderr: &stderr}).Run([]string{"run", "--trace", "1"}); err != nil {
+		t.Fatalf("run --trace: %v\n%s", err, stderr.String())
+	}
+	if want := "TOPIC: TRACED\n--- Example 1: First ---\none\n--- Example 2: Second ---\r\ntwo"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	// A blank line, the trace's ID, then a span a line: "  " and its
+	// indented name, then its time.
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")[1:]
+	want := []string{"run 001_traced.go", "  build", "  lesson", "    intro", "    Example 1: First", "    Example 2: Second"}
+	if len(lines) != len(want) {
+		t.Fatalf("the trace:\n%s\nwant spans %q", stderr.String(), want)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "  "+want[i]+" ") {
+			t.Errorf("span %d is %q, want %q", i, line, want[i])
+		}
+	}
+}
+
+// TestHints reads an exercise's hints in order through the binary: a
+// level can't be skipped, and every level read is in the progress log.
+func TestHints(t *testing.T) {
+	course, config := t.TempDir(), t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	env := []string{"GOTUT_CONFIG_DIR=" + config}
+	steps := []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"hint 5 --level 2", ExitUsage, "read level 1 first"},
+		{"hint 5", ExitOK, ""},
+		{"hint 005 --level 3", ExitUsage, "read level 2 first"}, // 005 is topic 5
+		{"hint --level 2 5", ExitOK, ""},
+		{"hint 5 --level 1", ExitOK, ""}, // Reading again is fine
+		{"hint 5", ExitOK, ""},
+		{"hint 5 --level 4", ExitUsage, "no hint level 4 (there are 3)"},
+		{"hint 5 6", ExitUsage, "exactly one TOPIC"},
+	}
+	for _, step := range steps {
+		args := append([]string{"-dir", course}, strings.Fields(step.args)...)
+		got, stderr := exitStatus(t, env, args...)
+		if got != step.want || !strings.Contains(stderr, step.stderr) {
+			t.Errorf("gotut %s: exit %d, stderr %q; want %d mentioning %q", step.args, got, stderr, step.want, step.stderr)
+		}
+	}
+	log, err := progress.Load(filepath.Join(config, "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read []string
+	for _, e := range log.Filter("hint") {
+		read = append(read, e.Item+" "+e.Result)
+	}
+	if want := []string{"5 level 1", "5 level 2", "5 level 1", "5 level 3"}; !slices.Equal(read, want) {
+		t.Errorf("progress log: %q, want %q", read, want)
+	}
+}
+
+// TestSolution shows an exercise's reference three ways. With --failed
+// only Sum, whose test fails, is shown: Neg differs from the reference
+// but passes, so it stays the learner's own.
+func TestSolution(t *testing.T) {
+	course := t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOTUT_CONFIG_DIR", t.TempDir())
+	tests := []struct {
+		args          string
+		want, notWant []string
+	}{
+		{"solution 5", []string{"// sum.go (reference)", "total += x", "0 - x"}, []string{"+++"}},
+		{"solution 5 --diff", []string{"--- sum.go (yours)", "+++ sum.go (reference)", "-func Neg(x int) int { return -x }", "+\t\ttotal += x"}, nil},
+		{"solution --diff --failed 5", []string{"Failing: TestSum", "-func Sum(xs ...int) int { return len(xs) }"}, []string{"Neg"}},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		c := &CLI{Dir: course, Stdout: &out, Stderr: io.Discard}
+		if err := c.Run(strings.Fields(tt.args)); err != nil {
+			t.Errorf("gotut %s: %v", tt.args, err)
+			continue
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("gotut %s: output doesn't contain %q:\n%s", tt.args, s, &out)
+			}
+		}
+		for _, s := range tt.notWant {
+			if strings.Contains(out.String(), s) {
+				t.Errorf("gotut %s: output contains %q:\n%s", tt.args, s, &out)
+			}
+		}
+	}
+
+	path, err := progress.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := progress.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen []string
+	for _, e := range log.Filter("solution") {
+		seen = append(seen, e.Item+" "+e.Result+" "+e.Detail)
+	}
+	if want := []string{"5 full all", "5 diff all", "5 diff Sum"}; !slices.Equal(seen, want) {
+		t.Errorf("progress log: %q, want %q", seen, want)
+	}
+}
+
+// TestReview exports two exercises, imports the bundle against the same
+// course three ways, and checks that a wrong key shows nothing and exits
+// 3, like any other failed verification.
+func TestReview(t *testing.T) {
+	course, work := t.TempDir(), t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(bundle.KeyEnv, strings.Repeat("ab", 32))
+	file := filepath.Join(work, "ada.tar.gz")
+	tests := []struct {
+		args          string
+		want, notWant []string
+	}{
+		{"review export -name ada -o " + file + " 5 6", []string{"005_sum", "1/2 tests pass", "006_done", "1/1 tests pass"}, nil},
+		{"review import " + file, []string{"ada's bundle, signature ok", "TestSum  FAIL    Sum(2, 3) = 2, want 5",
+			"+++ sum.go (reference)", "-func Sum(xs ...int) int { return len(xs) }", "done.go (theirs): nothing to compare with"},
+			[]string{"sum_test.go"}},
+		{"review import -mine " + file, []string{"sum.go (theirs): the same as yours"}, []string{"+++"}},
+		{"review import -into " + work + " " + file, []string{"under " + filepath.Join(work, "review-ada")}, nil},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		c := &CLI{Dir: course, Stdout: &out, Stderr: io.Discard}
+		if err := c.Run(strings.Fields(tt.args)); err != nil {
+			t.Fatalf("gotut %s: %v", tt.args, err)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("gotut %s: output doesn't contain %q:\n%s", tt.args, s, &out)
+			}
+		}
+		for _, s := range tt.notWant {
+			if strings.Contains(out.String(), s) {
+				t.Errorf("gotut %s: output contains %q:\n%s", tt.args, s, &out)
+			}
+		}
+	}
+	for _, name := range []string{"005_sum/sum.go", "005_sum/sum_test.go", "006_done/done.go"} {
+		if _, err := os.Stat(filepath.Join(work, "review-ada", name)); err != nil {
+			t.Errorf("-into: %v", err)
+		}
+	}
+
+	env := []string{bundle.KeyEnv + "=" + strings.Repeat("cd", 32)}
+	got, stderr := exitStatus(t, env, "-dir", course, "review", "import", file)
+	if got != ExitVerify || !strings.Contains(stderr, "signature does not match") {
+		t.Errorf("wrong key: exit %d, stderr %q; want %d and a bad signature", got, stderr, ExitVerify)
+	}
+}
+
+// TestLessonLookup checks topics are found by either spelling, 73_x and
+// 073_x, that a binary built next to a lesson is not a lesson, and that
+// a go.work's modules are searched too.
+func TestLessonLookup(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"73_regex.go":           "package main\n\nfunc main() {}\n",
+		"73_regex":              "\x7fELF",
+		"73_regex_ex/ex.go":     "package main\n\nfunc main() {}\n",
+		"73_regex_ex/x_test.go": "package main\n",
+		"074_time.go":           "package main\n\nfunc main() {}\n",
+		"go.work":               "go 1.25\n\nuse (\n\t./more // Topics 200 and up\n)\n",
+		"more/go.mod":           "module example.com/more\n\ngo 1.25\n",
+		"more/201_work.go":      "package main\n\nfunc main() {}\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &CLI{Dir: dir}
+	for _, tc := range []struct{ topic, lesson, exercise string }{
+		{"73", "73_regex.go", "73_regex_ex"},
+		{"073", "73_regex.go", "73_regex_ex"},
+		{"74", "074_time.go", ""},
+		{"201", "201_work.go", ""},
+	} {
+		if got, err := c.find("run", tc.topic); err != nil || filepath.Base(got) != tc.lesson {
+			t.Errorf("find(%s) = %q, %v; want %s", tc.topic, got, err, tc.lesson)
+		}
+		got, err := c.findExercise("test", tc.topic)
+		if tc.exercise == "" {
+			if CodeOf(err) != CodeUsage {
+				t.Errorf("findExercise(%s) = %q, %v; want a usage error", tc.topic, got, err)
+			}
+		} else if err != nil || filepath.Base(got) != tc.exercise {
+			t.Errorf("findExercise(%s) = %q, %v; want %s", tc.topic, got, err, tc.exercise)
+		}
+	}
+}
+
+// TestKata starts, fails and passes a kata through the binary, with the
+// progress log in a scratch config directory.
+func TestKata(t *testing.T) {
+	work, config := t.TempDir(), t.TempDir()
+	env := []string{"GOTUT_CONFIG_DIR=" + config}
+	steps := []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"kata", ExitOK, ""},
+		{"kata -grade dedupe", ExitUsage, "start the kata with 'gotut kata dedupe'"},
+		{"kata nope", ExitUsage, `no kata "nope"`},
+		{"kata dedupe parseduration", ExitUsage, "at most one kata"},
+		{"kata dedupe", ExitOK, ""},
+		{"kata dedupe", ExitOK, ""}, // Already started: kept, and the clock isn't reset
+		{"kata -grade dedupe", ExitTestFailed, "2/4 hidden tests pass"},
+		{"solve", 0, ""},
+		{"kata -grade dedupe", ExitOK, ""},
+	}
+	for _, step := range steps {
+		if step.args == "solve" {
+			k, _ := kata.Get("dedupe")
+			if err := os.WriteFile(filepath.Join(work, "dedupe", kata.File), k.Solution(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		args := append([]string{"kata", "-workdir", work}, strings.Fields(step.args)[1:]...)
+		got, stderr := exitStatus(t, env, args...)
+		if got != step.want || !strings.Contains(stderr, step.stderr) {
+			t.Errorf("gotut %s: exit %d, stderr %q; want %d mentioning %q", step.args, got, stderr, step.want, step.stderr)
+		}
+	}
+
+	log, err := progress.Load(filepath.Join(config, "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []string
+	for _, e := range log.Filter("kata") {
+		results = append(results, e.Item+" "+e.Result)
+	}
+	if want := []string{"dedupe started", "dedupe fail", "dedupe pass"}; !slices.Equal(results, want) {
+		t.Errorf("progress log: %q, want %q", results, want)
+	}
+}
+
+// TestDeprecations lists the shims in a course: the calls left to one,
+// and one no one calls any more.
+func TestDeprecations(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"172_summary.go": "package main\n\n// Deprecated: use course.File.\nfunc lessonFile(int) {}\n\n" +
+			"func main() {\n\tlessonFile(1)\n\tlessonFile(2)\n}\n",
+		"176_events.go":   "package main\n\nfunc main() { course.File(1) }\n",
+		"180_tool/cli.go": "package main\n\nfunc lessonFile(int) {}\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out strings.Builder
+	c := &CLI{Dir: dir, Stdout: &out, Stderr: io.Discard}
+	if err := c.Run([]string{"deprecations"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"lessonFile → course.File\n",
+		"  172_summary.go:4  2 calls\n    172_summary.go:7\n    172_summary.go:8\n",
+		"  " + filepath.Join("180_tool", "cli.go") + ":3  0 calls\n",
+		"2 call sites of 1 deprecated name left.\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output doesn't have %q:\n%s", want, out.String())
+		}
+	}
+	if err := c.Run([]string{"deprecations", "172"}); CodeOf(err) != CodeUsage {
+		t.Errorf("deprecations 172: %v, want a usage error", err)
+	}
+}
+
+// fakeRunEvents stands in for 176: it writes a v1 event stream with a
+// pause in it. Topic 2 fails, and 9 isn't a lesson.
+const fakeRunEvents = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+func emit(ev map[string]any) {
+	ev["v"], ev["time"] = 1, time.Now()
+	b, _ := json.Marshal(ev)
+	fmt.Println(string(b))
+}
+
+func main() {
+	topic := os.Args[2]
+	if topic == "9" {
+		fmt.Fprintln(os.Stderr, "run: no single-file lesson for topic 9")
+		os.Exit(2)
+	}
+	emit(map[string]any{"type": "start", "lesson": "00" + topic + "_demo.go"})
+	emit(map[string]any{"type": "section", "section": 1, "title": "Wait"})
+	emit(map[string]any{"type": "output", "section": 1, "stream": "stdout", "text": "--- Example 1: Wait ---"})
+	time.Sleep(300 * time.Millisecond)
+	emit(map[string]any{"type": "output", "section": 1, "stream": "stderr", "text": "\x1b[31mslow\x1b[0m"})
+	if topic == "2" {
+		emit(map[string]any{"type": "summary", "exit_code": 1, "failed": "run"})
+		os.Exit(3)
+	}
+	emit(map[string]any{"type": "summary", "exit_code": 0})
+}
+`
+
+// TestRecordReplay records a run of the fake 176 and plays it back, at
+// the recorded pace and faster.
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "176_run_events.go"), []byte(fakeRunEvents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	session := filepath.Join(dir, "session.json")
+	run := func(args ...string) (stdout, stderr string, err error) {
+		var out, errOut strings.Builder
+		c := &CLI{Dir: dir, Stdout: &out, Stderr: &errOut}
+		err = c.Run(args)
+		return out.String(), errOut.String(), err
+	}
+
+	stdout, stderr, err := run("record", "-out", session, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != "--- Example 1: Wait ---\n" || !strings.HasPrefix(stderr, "\x1b[31mslow\x1b[0m\nrecorded 001_demo.go: 5 events") {
+		t.Errorf("record showed %q and %q", stdout, stderr)
+	}
+
+	for _, tc := range []struct {
+		speed    string
+		min, max time.Duration
+	}{
+		{"1", 300 * time.Millisecond, time.Minute},
+		{"100", 0, 250 * time.Millisecond},
+	} {
+		start := time.Now()
+		stdout, stderr, err := run("replay", "-speed", tc.speed, session)
+		took := time.Since(start)
+		if err != nil || stdout != "--- Example 1: Wait ---\n" || stderr != "\x1b[31mslow\x1b[0m\n" {
+			t.Errorf("replay -speed %s: %q, %q, %v", tc.speed, stdout, stderr, err)
+		}
+		if took < tc.min || took > tc.max {
+			t.Errorf("replay -speed %s took %v, want %v to %v", tc.speed, took, tc.min, tc.max)
+		}
+	}
+
+	failed := filepath.Join(dir, "failed.json")
+	if _, _, err := run("record", "-out", failed, "2"); CodeOf(err) != CodeVerify {
+		t.Errorf("record 2: %v, want a verify error", err)
+	}
+	if _, _, err := run("replay", "-speed", "100", failed); err != nil {
+		t.Errorf("a failed lesson's recording doesn't replay: %v", err)
+	}
+	for _, tc := range []struct {
+		args []string
+		code Code
+	}{
+		{[]string{"record", "-out", session, "9"}, CodeUnknownTopic},
+		{[]string{"record", "x"}, CodeUsage},
+		{[]string{"replay", "-speed", "0", session}, CodeUsage},
+		{[]string{"replay", filepath.Join(dir, "176_run_events.go")}, CodeUsage},
+		{[]string{"replay", filepath.Join(dir, "missing.json")}, CodeIO},
+	} {
+		if _, _, err := run(tc.args...); CodeOf(err) != tc.code {
+			t.Errorf("gotut %s: %v, want %v", strings.Join(tc.args, " "), err, tc.code)
+		}
+	}
+}
+
+// TestCast records the fake 176 as an asciicast, and converts the
+// session to one: the same file both ways.
+func TestCast(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "176_run_events.go"), []byte(fakeRunEvents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TERM", "xterm-256color")
+	session, recorded, converted := filepath.Join(dir, "s.json"), filepath.Join(dir, "rec.cast"), filepath.Join(dir, "conv.cast")
+	c := &CLI{Dir: dir, Stdout: io.Discard, Stderr: io.Discard}
+	if err := c.Run([]string{"record", "-out", session, "-cast", recorded, "-size", "100x30", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run([]string{"replay", "-cast", converted, "-size", "100x30", session}); err != nil {
+		t.Fatal(err)
+	}
+	cast, err := os.ReadFile(recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := os.ReadFile(converted); err != nil || !slices.Equal(cast, again) {
+		t.Errorf("replay -cast wrote:\n%s\nrecord -cast wrote:\n%s", again, cast)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(cast), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("%d lines, want a header, a marker and two outputs:\n%s", len(lines), cast)
+	}
+	var header struct {
+		Version, Width, Height int
+		Timestamp              int64
+		Title                  string
+		Env                    map[string]string
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Width != 100 || header.Height != 30 || header.Title != "001_demo.go" ||
+		header.Env["TERM"] != "xterm-256color" || time.Since(time.Unix(header.Timestamp, 0)) > time.Minute {
+		t.Errorf("header %s", lines[0])
+	}
+	var events [][3]any
+	for _, l := range lines[1:] {
+		var ev [3]any
+		if err := json.Unmarshal([]byte(l), &ev); err != nil {
+			t.Fatalf("%s: %v", l, err)
+		}
+		events = append(events, ev)
+	}
+	want := [][2]string{{"m", "1 Wait"}, {"o", "--- Example 1: Wait ---\r\n"}, {"o", "\x1b[31mslow\x1b[0m\r\n"}}
+	for i, ev := range events {
+		if ev[1] != want[i][0] || ev[2] != want[i][1] {
+			t.Errorf("event %d: %q, want %q", i, ev, want[i])
+		}
+	}
+	if pause := events[2][0].(float64) - events[1][0].(float64); pause < 0.3 {
+		t.Errorf("the 300ms pause is %.6fs in the cast", pause)
+	}
+	if !strings.Contains(lines[3], `\u001b[31m`) || !regexp.MustCompile(`^\[\d+\.\d{6}, `).MatchString(lines[3]) {
+		t.Errorf("%s: want microseconds and the escape kept", lines[3])
+	}
+
+	if err := c.Run([]string{"replay", "-cast", converted, "-size", "wide", session}); CodeOf(err) != CodeUsage {
+		t.Errorf("-size wide: %v, want a usage error", err)
+	}
+}
+
+// TestTmplCheck lints a directory of templates through the binary, then
+// checks the problems themselves with checkTemplates.
+func TestTmplCheck(t *testing.T) {
+	good, bad, empty := t.TempDir(), t.TempDir(), t.TempDir()
+	files := map[string]string{
+		good + "/receipt.tmpl": `{{define "line"}}{{.Name | upper}} {{currency .Price}}{{end -}}
+Dear {{.Customer.Name}},
+{{range .Items}}{{template "line" .}}
+{{end}}{{with .Note}}{{.}}{{end}}`,
+		good + "/footer.tmpl": `{{/* no data */}}Thanks, {{"the shop" | title}}.`,
+		bad + "/receipt.tmpl": `Dear {{.Customer.Nmae}},
+{{range $i, $it := .Items}}{{$it.Price | shout}}{{$it.Name.First}}{{end}}
+{{if .Refunded}}refund of {{.Refund}}{{end}}`,
+		bad + "/broken.tmpl": `{{if .Customer}}unclosed`,
+	}
+	for name, text := range files {
+		if err := os.WriteFile(name, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sample := filepath.Join(t.TempDir(), "sample.json")
+	notJSON := filepath.Join(t.TempDir(), "sample.txt")
+	os.WriteFile(notJSON, []byte("Customer: Ada\n"), 0o644)
+	os.WriteFile(sample, []byte(`{"Customer": {"Name": "Ada"}, "Items": [{"Name": "tea", "Price": 3.5}], "Note": null, "Refunded": false}`), 0o644)
+
+	for _, tt := range []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"tmpl-check " + good, ExitOK, ""},
+		{"tmpl-check -data " + sample + " " + good, ExitOK, ""},
+		{"tmpl-check " + bad, ExitVerify, "problems in 2 templates"},
+		{"tmpl-check -data " + sample + " " + bad, ExitVerify, "5 problems in 2 templates"},
+		{"tmpl-check", ExitUsage, "exactly one template DIR"},
+		{"tmpl-check " + empty, ExitUsage, "no *.tmpl files"},
+		{"tmpl-check " + filepath.Join(empty, "["), ExitUsage, "syntax error in pattern"},
+		{"tmpl-check -data " + filepath.Join(empty, "none.json") + " " + good, ExitRuntime, "none.json"},
+		{"tmpl-check -data " + notJSON + " " + good, ExitUsage, "sample.txt"},
+	} {
+		got, stderr := exitStatus(t, nil, strings.Fields(tt.args)...)
+		if got != tt.want || !strings.Contains(stderr, tt.stderr) {
+			t.Errorf("gotut %s: exit %d, stderr %q; want %d mentioning %q", tt.args, got, stderr, tt.want, tt.stderr)
+		}
+	}
+
+	var sampleData any
+	data, _ := os.ReadFile(sample)
+	json.Unmarshal(data, &sampleData)
+	problems, err := checkTemplates([]string{bad + "/broken.tmpl", bad + "/receipt.tmpl"}, sampleData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"broken.tmpl:1: unexpected EOF",
+		"receipt.tmpl:1:16: .Customer.Nmae: no field Nmae in the sample data (it has Name)",
+		`receipt.tmpl:2:41: function "shout" not defined`,
+		"receipt.tmpl:2:53: $it.Name.First: the sample's $it.Name is a string, which has no fields",
+		"receipt.tmpl:3:28: .Refund: no field Refund in the sample data (it has Customer, Items, Note, Refunded)",
+	}
+	if !slices.Equal(problems, want) {
+		t.Errorf("problems:\n%s\nwant:\n%s", strings.Join(problems, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestToolMigrate(t *testing.T) {
+	dir := t.TempDir()
+	for name, sql := range map[string]string{
+		"0001_create_lessons.up.sql":   "CREATE TABLE lessons (id INTEGER);",
+		"0001_create_lessons.down.sql": "DROP TABLE lessons;",
+		"0002_add_scores.up.sql":       "CREATE TABLE scores (id INTEGER);\nALTR TABLE scores ADD COLUMN points INTEGER;",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(sql), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db := filepath.Join(t.TempDir(), "schema.json")
+
+	for _, tt := range []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"tool migrate -db " + db + " " + dir + " up 1", ExitOK, ""},
+		{"tool migrate -db " + db + " " + dir + " up", ExitVerify, "up 0002_add_scores: syntax error"},
+		{"tool migrate -db " + db + " " + dir + " status", ExitOK, ""},
+		{"tool migrate -db " + db + " " + dir + " down", ExitVerify, "database is dirty"},
+		{"tool migrate -db " + db + " " + dir + " force 1", ExitOK, ""},
+		{"tool migrate -db " + db + " " + dir + " down", ExitOK, ""},
+		{"tool migrate -db " + db + " " + dir + " sideways", ExitUsage, `unknown command "sideways"`},
+		{"tool migrate " + dir, ExitUsage, "want 'tool migrate DIR"},
+		{"tool migrate " + filepath.Join(dir, "none") + " status", ExitRuntime, "none"},
+		{"tool", ExitUsage, "want 'tool migrate"},
+	} {
+		got, stderr := exitStatus(t, nil, strings.Fields(tt.args)...)
+		if got != tt.want || !strings.Contains(stderr, tt.stderr) {
+			t.Errorf("gotut %s: exit %d, stderr %q; want %d mentioning %q", tt.args, got, stderr, tt.want, tt.stderr)
+		}
+	}
+	// Each run is a process of its own, so the state went through the file.
+	if data, err := os.ReadFile(db); err != nil || strings.Contains(string(data), "lessons") {
+		t.Errorf("%s after reverting 0001: %s, %v", db, data, err)
+	}
+}
+
+// TestHelp reads the generated pages: each command's comes from the same
+// table Run dispatches through, so a command can't be missing from help.
+func TestHelp(t *testing.T) {
+	course := t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		args string
+		want []string
+	}{
+		{"help", []string{"tmpl-check", "gotut help COMMAND", "Exit status:"}},
+		{"help run", []string{"usage: gotut run [-timeout D] [-trace] TOPIC", "-timeout duration", "(default 30s)", "$ gotut run 153"}},
+		{"help review", []string{"gotut review export [-o FILE]", "gotut review import [-mine]"}},
+		{"help review import", []string{"-into string", "-mine"}},
+		{"help topics", []string{"6 topics in", "1  hello", "5  sum"}},
+		{"help topics SUM", []string{"1 of 6 topics", "5  sum"}},
+	} {
+		var stdout strings.Builder
+		c := &CLI{Dir: course, Stdout: &stdout, Stderr: io.Discard}
+		if err := c.Run(strings.Fields(tt.args)); err != nil {
+			t.Errorf("gotut %s: %v", tt.args, err)
+			continue
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(stdout.String(), w) {
+				t.Errorf("gotut %s: no %q in\n%s", tt.args, w, stdout.String())
+			}
+		}
+	}
+
+	for _, args := range []string{"help nope", "help review nope", "help run extra"} {
+		if got, stderr := exitStatus(t, nil, strings.Fields(args)...); got != ExitUsage {
+			t.Errorf("gotut %s: exit %d, want %d\nstderr: %s", args, got, ExitUsage, stderr)
+		}
+	}
+
+	for _, width := range []int{40, 60, 100} {
+		var stdout strings.Builder
+		c := &CLI{Dir: course, Stdout: &stdout, Stderr: io.Discard, Width: width}
+		for _, args := range [][]string{{"help"}, {"help", "run"}, {"help", "kata"}} {
+			if err := c.Run(args); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for l := range strings.Lines(stdout.String()) {
+			// A word longer than the line can't be broken; none here is.
+			if n := len([]rune(strings.TrimRight(l, "\n"))); n > width {
+				t.Errorf("width %d: %d columns: %q", width, n, l)
+			}
+		}
+	}
+}
+
+// TestAliases defines, runs and deletes aliases through the binary, with
+// config.json in a temporary directory, and checks what the file holds.
+func TestAliases(t *testing.T) {
+	course, config := t.TempDir(), t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(config, "config.json")
+	os.WriteFile(path, []byte(`{"accessible": true}`), 0o644)
+	env := []string{"GOTUT_CONFIG_DIR=" + config}
+	gotutIn := func(args string) (int, string) {
+		return exitStatus(t, env, append([]string{"-dir", course}, strings.Fields(args)...)...)
+	}
+
+	for _, tt := range []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"aliases", ExitOK, ""},
+		{"alias hi = verify 1", ExitOK, ""},
+		{"alias slow run -timeout 1s 4", ExitOK, ""}, // -timeout is the expansion's, not alias's
+		{"alias both hi 3", ExitOK, ""},
+		{"alias t5 = test", ExitOK, ""},
+		{"hi", ExitOK, ""},
+		{"slow", ExitRuntime, "deadline exceeded"},
+		{"both", ExitVerify, "exit status 7"},
+		{"t5 5", ExitTestFailed, "test 005_sum"},
+
+		{"alias", ExitUsage, "alias NAME = COMMAND"},
+		{"alias nope", ExitUsage, `no alias "nope"`},
+		{"alias -d nope", ExitUsage, `no alias "nope"`},
+		{"alias run = verify 1", ExitUsage, `"run" is a gotut command`},
+		{"alias help = verify 1", ExitUsage, `"help" is a gotut command`},
+		{"alias 7 = run 7", ExitUsage, "want a letter"},
+		{"alias x =", ExitUsage, "nothing to expand to"},
+		{"alias x = -dir . run 1", ExitUsage, `"-dir" is a flag`},
+		{"alias hi = both", ExitUsage, "alias cycle: hi → both → hi"},
+		{"launch 1", ExitUsage, `unknown command "launch"`},
+	} {
+		if got, stderr := gotutIn(tt.args); got != tt.want || !strings.Contains(stderr, tt.stderr) {
+			t.Errorf("gotut %s: exit %d, stderr %q; want %d mentioning %q", tt.args, got, stderr, tt.want, tt.stderr)
+		}
+	}
+
+	var cfg struct {
+		Accessible bool
+		Aliases    map[string]string
+	}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &cfg); err != nil || !cfg.Accessible || cfg.Aliases["hi"] != "verify 1" || len(cfg.Aliases) != 4 {
+		t.Errorf("config.json after the aliases: %s", data)
+	}
+
+	var stdout strings.Builder
+	t.Setenv("GOTUT_CONFIG_DIR", config)
+	c := &CLI{Dir: course, Stdout: &stdout, Stderr: io.Discard}
+	for _, args := range []string{"alias -d t5", "aliases", "help both"} {
+		if err := c.Run(strings.Fields(args)); err != nil {
+			t.Fatalf("gotut %s: %v", args, err)
+		}
+	}
+	for _, want := range []string{
+		"both = hi 3\nhi   = verify 1\nslow = run -timeout 1s 4\n",
+		"both is an alias for 'gotut hi 3', which is 'gotut verify 1 3'.",
+		"usage: gotut verify",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("no %q in\n%s", want, stdout.String())
+		}
+	}
+
+	// A cycle written into config.json by hand is caught when it runs,
+	// and a command's name there stops every alias until it is fixed.
+	os.WriteFile(path, []byte(`{"aliases": {"a": "b", "b": "a 1"}}`), 0o644)
+	if got, stderr := gotutIn("a"); got != ExitUsage || !strings.Contains(stderr, "a → b → a") {
+		t.Errorf("gotut a with a cycle: exit %d, stderr %q", got, stderr)
+	}
+	os.WriteFile(path, []byte(`{"aliases": {"verify": "run 1", "hi": "verify 1"}}`), 0o644)
+	if got, stderr := gotutIn("hi"); got != ExitUsage || !strings.Contains(stderr, `"verify" is a gotut command`) {
+		t.Errorf("gotut hi with an alias named verify: exit %d, stderr %q", got, stderr)
+	}
+	if got, _ := gotutIn("verify 1"); got != ExitOK {
+		t.Errorf("gotut verify 1 with a broken config: exit %d; commands must not read it", got)
+	}
+}
+
+// TestCheck runs check once, then watches an exercise through the binary:
+// each save is one run, and Ctrl-C exits with the last run's result.
+func TestCheck(t *testing.T) {
+	course := t.TempDir()
+	if err := writeCourse(course); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		args   string
+		want   int
+		stderr string
+	}{
+		{"check 6", ExitOK, ""},
+		{"check 5", ExitTestFailed, "check 005_sum: 1 of 2 tests failed"},
+		{"check", ExitUsage, "exactly one TOPIC"},
+		{"check 1", ExitUsage, "no tests"},
+		{"check -color pink 5", ExitUsage, "-color pink"},
+		{"check -watch -interval 0s 5", ExitUsage, "-interval 0s"},
+	} {
+		got, stderr := exitStatus(t, nil, append([]string{"-dir", course}, strings.Fields(tt.args)...)...)
+		if got != tt.want || !strings.Contains(stderr, tt.stderr) {
+			t.Errorf("gotut %s: exit %d, stderr %q; want %d mentioning %q", tt.args, got, stderr, tt.want, tt.stderr)
+		}
+	}
+
+	var stdout strings.Builder
+	c := &CLI{Dir: course, Stdout: &stdout, Stderr: io.Discard}
+	c.Run([]string{"check", "5"})
+	for _, want := range []string{"✗ TestSum\n    sum_test.go:7: Sum(2, 3) = 2, want 5\n", "1 failed, 1 passed ("} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("check 5: no %q in\n%s", want, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("check 5 colored output that isn't a terminal: %q", stdout.String())
+	}
+
+	// The watch. Every step waits for a line of output, so a slow machine
+	// is slower, not a failure; the deadline is for a watch that hangs.
+	exercise := filepath.Join(course, "005_sum")
+	cmd := exec.Command(gotut, "-dir", course, "check", "-watch", "-interval", "50ms", "5")
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+	var seen []string
+	waitFor := func(prefix string) string {
+		t.Helper()
+		deadline := time.After(time.Minute)
+		for {
+			select {
+			case l, ok := <-lines:
+				if !ok {
+					t.Fatalf("output ended before %q:\n%s", prefix, strings.Join(seen, "\n"))
+				}
+				seen = append(seen, l)
+				if strings.HasPrefix(l, prefix) {
+					return l
+				}
+			case <-deadline:
+				t.Fatalf("no %q after a minute:\n%s", prefix, strings.Join(seen, "\n"))
+			}
+		}
+	}
+
+	waitFor("1 failed, 1 passed")
+	waitFor("Watching ")
+	solution, _ := os.ReadFile(filepath.Join(exercise, "sum.go.solution"))
+	os.WriteFile(filepath.Join(exercise, "sum.go"), solution, 0o644)
+	if l := waitFor("── "); !strings.HasSuffix(l, ": sum.go ──") {
+		t.Errorf("separator %q doesn't name sum.go", l)
+	}
+	waitFor("✓ 2 passed")
+	os.WriteFile(filepath.Join(exercise, "broken.go"), []byte("package main\n\nfunc Sum("), 0o644)
+	if l := waitFor("── "); !strings.HasSuffix(l, ": broken.go ──") {
+		t.Errorf("separator %q doesn't name broken.go", l)
+	}
+	waitFor("✗ does not compile")
+	cmd.Process.Signal(os.Interrupt)
+	for range lines {
+	}
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitTestFailed {
+		t.Errorf("after Ctrl-C: %v, want exit %d: the last run didn't compile", err, ExitTestFailed)
+	}
+	if n := strings.Count(strings.Join(seen, "\n"), "── "); n != 2 {
+		t.Errorf("%d runs for 2 saves:\n%s", n, strings.Join(seen, "\n"))
+	}
+}
+
+// TestPractice answers generated problems through Stdin, as a learner
+// would, and checks what reached the progress log and -stats. fmt-sign
+// and fmt-quote take the same format whatever numbers and strings they
+// are made with, so the answers don't depend on the seed.
+func TestPractice(t *testing.T) {
+	t.Setenv("GOTUT_CONFIG_DIR", t.TempDir())
+	for _, tt := range []struct {
+		args, stdin string
+		want        []string
+	}{
+		{"practice -n 2 fmt-sign fmt-quote", "%+d\n`%q`\n", []string{"[1/2] fmt-sign", "fmt.Sprintf(FORMAT, ", "[2/2] fmt-quote",
+			"✓ right — fmt-sign again in 3 days", "2 of 2 right"}},
+		{"practice -n 3 fmt-sign", "+%d\n?\n", []string{"✗ right for ", "one answer: %+d — fmt-sign again tomorrow",
+			"[3/3]", "0 of 2 right"}},
+		{"practice -n 1 regex-date", "\\d{4}-\\d{2}-\\d{2}\n", []string{"don't match: ", "1 of 1 right"}},
+		{"practice -n 1 73", "(\n", []string{"✗ doesn't compile", "0 of 1 right"}},
+		{"practice -stats 71", "", []string{"fmt-sign       71     1/3  33%  1    tomorrow", "fmt-quote      71     1/1 100%  2    in 3 days",
+			"fmt-zero-pad   71     -         -    now"}},
+	} {
+		var out strings.Builder
+		c := &CLI{Stdin: strings.NewReader(tt.stdin), Stdout: &out, Stderr: io.Discard}
+		if err := c.Run(strings.Fields(tt.args)); err != nil {
+			t.Errorf("gotut %s: %v", tt.args, err)
+			continue
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("gotut %s: output doesn't contain %q:\n%s", tt.args, s, &out)
+			}
+		}
+	}
+	for _, args := range []string{"practice -n 0", "practice nope", "practice 72"} {
+		if err := (&CLI{Stdout: io.Discard}).Run(strings.Fields(args)); CodeOf(err) != CodeUsage {
+			t.Errorf("gotut %s: %v, want a usage error", args, err)
+		}
+	}
+
+	path, err := progress.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := progress.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen []string
+	for _, e := range log.Filter("practice") {
+		seen = append(seen, e.Item+" "+e.Result+" "+e.Detail)
+	}
+	if want := []string{"fmt-sign right %+d", "fmt-quote right `%q`", "fmt-sign wrong +%d", "fmt-sign wrong ?",
+		"regex-date right \\d{4}-\\d{2}-\\d{2}"}; !slices.Equal(seen[:5], want) || len(seen) != 6 {
+		t.Errorf("progress log: %q, want %q and one more", seen, want)
+	}
+}
+
+// TestType types snippets through piped Stdin, a line per line, and then
+// drives the raw-mode path with keys from a string: pkg/term decodes the
+// same bytes whether or not a terminal sent them.
+func TestType(t *testing.T) {
+	t.Setenv("GOTUT_CONFIG_DIR", t.TempDir())
+	wrap := `outerErr := fmt.Errorf("operation failed: %w", ErrFileNotFound)`
+	for _, tt := range []struct {
+		args, stdin string
+		want        []string
+	}{
+		{"type -n 1 wrap-error", wrap + "\n", []string{"[1/1] wrap-error (Topic 68, ../intermediate_topics/68_errors_detailed.go)",
+			"  " + wrap + "\n", "100% accurate", "your first time", "1 of 1 typed"}},
+		{"type -n 2 138", "ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)\r\n    defer cancel()\n" +
+			"ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)\ndefer cancle()\n",
+			[]string{"[1/2] context-timeout", "[2/2] context-timeout", `✗ line 2 is not "defer cancel()" — not finished`, "1 of 2 typed"}},
+		{"type -n 1 wrap-error", "outerErr := fmt.Errorf(\n", []string{"✗ line 1 is not", "0 of 1 typed"}},
+		{"type wrap-error", "", []string{"0 of 3 typed"}}, // End of input
+		{"type -bests 68 138 142", "", []string{"SNIPPET          TOPIC  TRIES  BEST", "wrap-error       68     1      ",
+			"context-timeout  138    1 ", "select-timeout   142    0      -"}},
+	} {
+		var out strings.Builder
+		c := &CLI{Stdin: strings.NewReader(tt.stdin), Stdout: &out, Stderr: io.Discard}
+		if err := c.Run(strings.Fields(tt.args)); err != nil {
+			t.Errorf("gotut %s: %v", tt.args, err)
+			continue
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("gotut %s: output doesn't contain %q:\n%s", tt.args, s, &out)
+			}
+		}
+	}
+	for _, args := range []string{"type -n 0", "type nope", "type 72"} {
+		if err := (&CLI{Stdout: io.Discard}).Run(strings.Fields(args)); CodeOf(err) != CodeUsage {
+			t.Errorf("gotut %s: %v, want a usage error", args, err)
+		}
+	}
+
+	s, _ := typing.Get("context-timeout")
+	var out strings.Builder
+	c := &CLI{Stdout: &out}
+	keys := "ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)" +
+		"\x1b[A" + // An arrow: ignored
+		"\rdefer cancle\x7f\x7fel()"
+	d, err := c.typeKeys(term.NewReader(strings.NewReader(keys)), palette{on: true}, s)
+	if err != nil || d == nil || !d.Done() {
+		t.Fatalf("typeKeys: %v, %+v", err, d)
+	}
+	if sc := d.Score(); sc.Mistakes != 2 || sc.Keys != len(s.Text)+2 {
+		t.Errorf("score %+v, want the l and the e wrong", sc)
+	}
+	if !strings.Contains(out.String(), "\n  defer canc\x1b[31ml\x1b[0m\x1b[31me\x1b[0m\b \b\b \bel()") {
+		t.Errorf("drawn: %q", &out)
+	}
+	out.Reset()
+	if d, err := c.typeKeys(term.NewReader(strings.NewReader("ctx\x03")), palette{}, s); d != nil || err != nil {
+		t.Errorf("after Ctrl-C: %+v, %v; want the session over", d, err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/migrate"
+	_ "github.com/akarsh323/Go-tutorials-/go_projects/pkg/migrate/memdb"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut tool migrate
+// ---------------------------------------------------------
+// Topic 148's runner, for a directory of migrations being written:
+//
+//	gotut tool migrate DIR status           each one: pending, applied or DIRTY
+//	gotut tool migrate DIR up [N]           apply N, or every pending one
+//	gotut tool migrate DIR down [N]         revert N, or the latest
+//	gotut tool migrate DIR force VERSION    mark 1..VERSION applied, after a repair
+//
+// The database is pkg/migrate/memdb's, kept in a JSON file between runs
+// (-db, DIR/memdb.json unless set). It tracks tables, not rows: enough to
+// find a migration that doesn't apply, or doesn't revert, before it
+// meets a real database. A migration that fails, and a database left
+// dirty by one, are verification failures: exit 3.
+
+func (c *CLI) tool(args []string) error {
+	if len(args) == 0 || args[0] != "migrate" {
+		return M(CodeUsage, "tool", "cli.want-tool-command")
+	}
+	return c.toolMigrate(args[1:])
+}
+
+func (c *CLI) toolMigrate(args []string) error {
+	var dbFile string
+	args, err := c.flags("tool migrate", args, nil, func(fs *flag.FlagSet) {
+		fs.StringVar(&dbFile, "db", "", "the `file` memdb keeps the database in (default DIR/memdb.json)")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return M(CodeUsage, "tool migrate", "cli.want-migrate-command")
+	}
+	dir := args[0]
+	if dbFile == "" {
+		dbFile = filepath.Join(dir, "memdb.json")
+	}
+	migrations, err := migrate.Load(os.DirFS(dir), ".")
+	if err != nil {
+		return E(CodeIO, "tool migrate", fmt.Errorf("%s: %w", dir, err))
+	}
+	db, err := sql.Open("memdb", "file:"+dbFile)
+	if err != nil {
+		return E(CodeIO, "tool migrate", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	m, err := migrate.New(ctx, db, migrations)
+	if err != nil {
+		return E(CodeIO, "tool migrate", err)
+	}
+	m.Out = c.Stdout
+	switch err := m.Command(ctx, args[1:]); {
+	case err == nil:
+		return nil
+	case errors.Is(err, migrate.ErrUsage):
+		return E(CodeUsage, "tool migrate", err)
+	default:
+		return E(CodeVerify, "tool migrate", err) // A migration's SQL, or a dirty database
+	}
+}
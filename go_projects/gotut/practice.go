@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/practice"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut practice
+// ---------------------------------------------------------
+// Problems made up on the spot (pkg/practice): a format string for
+// Topic 71's verbs, a pattern for intermediate Topic 73's regular
+// expressions. The answer is run, not compared, and each one goes into
+// the progress log, which is all the scheduler needs: concepts answered
+// wrong come back tomorrow, ones answered right drift further out.
+//
+//	gotut practice                  5 problems, the concepts most due first
+//	gotut practice -n 3 73          3 problems on Topic 73's concepts
+//	gotut practice fmt-hex          only hexadecimal
+//	gotut practice -stats           accuracy, box and next review per concept
+//
+// Answers are read a line at a time from stdin. An empty line or "?"
+// shows an answer and counts as wrong; end of input ends the session,
+// keeping what was answered. Wrong answers are practice, not failure:
+// the exit status is 0 either way.
+
+func (c *CLI) practice(args []string) error {
+	var n int
+	var seed uint64
+	var stats bool
+	args, err := c.flags("practice", args, nil, func(fs *flag.FlagSet) {
+		fs.IntVar(&n, "n", 5, "how many problems")
+		fs.Uint64Var(&seed, "seed", 0, "make the same problems every time (0: new ones)")
+		fs.BoolVar(&stats, "stats", false, "show each concept's record instead of practising")
+	})
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return M(CodeUsage, "practice", "cli.bad-count", n)
+	}
+	only, err := pickConcepts(args)
+	if err != nil {
+		return err
+	}
+	path, err := progress.Path()
+	if err != nil {
+		return E(CodeIO, "practice", err)
+	}
+	log, err := progress.Load(path)
+	if err != nil {
+		return E(CodeIO, "practice", err)
+	}
+	var results []practice.Result
+	for _, e := range log.Filter("practice") {
+		results = append(results, practice.Result{Concept: e.Item, Right: e.Result == "right", Time: e.Time})
+	}
+	schedule := func(now time.Time) []practice.Stat {
+		var out []practice.Stat
+		for _, s := range practice.Schedule(results, now) {
+			if only == nil || only[s.Concept] {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	if stats {
+		return c.practiceStats(schedule(time.Now()), time.Now())
+	}
+
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+	in := bufio.NewScanner(strings.NewReader(""))
+	if c.Stdin != nil {
+		in = bufio.NewScanner(c.Stdin)
+	}
+	// The schedule's order, from the top again if n is longer: the due
+	// concepts first, then extra practice on the rest.
+	order := schedule(time.Now())
+	right, asked := 0, 0
+	for i := range n {
+		concept, _ := practice.Get(order[i%len(order)].Concept)
+		p := concept.New(rng)
+		fmt.Fprintf(c.Stdout, "[%d/%d] %s (Topic %d: %s)\n  %s\n> ", i+1, n, concept.Name, concept.Topic, concept.Title,
+			strings.ReplaceAll(p.Prompt, "\n", "\n  "))
+		if !in.Scan() {
+			fmt.Fprintln(c.Stdout)
+			break
+		}
+		asked++
+		answer := strings.TrimSuffix(in.Text(), "\r")
+		var wrong error
+		if answer != "" && answer != "?" {
+			wrong = p.Check(answer)
+		}
+		ok := answer != "" && answer != "?" && wrong == nil
+		result := practice.Result{Concept: concept.Name, Right: ok, Time: time.Now()}
+		event := progress.Event{Kind: "practice", Item: concept.Name, Result: "wrong", Detail: answer, Time: result.Time}
+		if ok {
+			event.Result = "right"
+		}
+		if err := progress.Record(path, event); err != nil {
+			return E(CodeIO, "practice", err)
+		}
+		results = append(results, result)
+
+		switch {
+		case ok:
+			right++
+			fmt.Fprint(c.Stdout, "  ✓ right")
+		case wrong != nil:
+			fmt.Fprintf(c.Stdout, "  ✗ %v\n  one answer: %s", wrong, p.Example)
+		default:
+			fmt.Fprintf(c.Stdout, "  one answer: %s", p.Example)
+		}
+		for _, s := range practice.Schedule(results, result.Time) {
+			if s.Concept == concept.Name {
+				fmt.Fprintf(c.Stdout, " — %s again %s\n\n", concept.Name, dueIn(result.Time, s.Due))
+			}
+		}
+	}
+	fmt.Fprintf(c.Stdout, "%d of %d right. 'gotut practice -stats' shows each concept's record.\n", right, asked)
+	return nil
+}
+
+// pickConcepts turns arguments, concept names or topic numbers, into the
+// set of concepts to practise; nil, for no arguments, means all of them.
+func pickConcepts(args []string) (map[string]bool, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	only := map[string]bool{}
+	for _, a := range args {
+		topic, err := strconv.Atoi(a)
+		found := false
+		for _, c := range practice.Concepts() {
+			if c.Name == a || err == nil && c.Topic == topic {
+				only[c.Name], found = true, true
+			}
+		}
+		if !found {
+			return nil, M(CodeUsage, "practice", "cli.no-concept", a)
+		}
+	}
+	return only, nil
+}
+
+func (c *CLI) practiceStats(stats []practice.Stat, now time.Time) error {
+	tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONCEPT\tTOPIC\tRIGHT\tBOX\tNEXT\tWHAT")
+	for _, s := range stats {
+		concept, _ := practice.Get(s.Concept)
+		record, box := "-", "-"
+		if s.Tries > 0 {
+			record = fmt.Sprintf("%d/%d %3.0f%%", s.Right, s.Tries, 100*s.Accuracy())
+			box = strconv.Itoa(s.Box)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n", s.Concept, concept.Topic, record, box, dueIn(now, s.Due), concept.Title)
+	}
+	return tw.Flush()
+}
+
+// dueIn says when a concept is due, in whole days as Topic 169 does.
+func dueIn(now, due time.Time) string {
+	if !now.Before(due) {
+		return "now"
+	}
+	y, m, d := now.Date()
+	switch days := int(due.Sub(time.Date(y, m, d, 0, 0, 0, 0, now.Location())) / (24 * time.Hour)); days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
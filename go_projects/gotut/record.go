@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut record and replay
+// ---------------------------------------------------------
+// A lesson run saved with its timing, to play back later at the pace it
+// was recorded — for a talk, a screencast, or a lesson that is slow to
+// build on the projector's laptop:
+//
+//	gotut record -out session.json 85    run Topic 85 and save it
+//	gotut replay session.json            print it again, as it happened
+//	gotut replay -speed 4 session.json   four times as fast
+//	gotut record -cast 85.cast 85        an asciicast too, in cast.go
+//
+// record doesn't time anything itself. It runs the lesson through 176's
+// run --format json, whose events already say when every line came out,
+// and saves those events, as 176 wrote them, after a small header. Any
+// tool that reads 176's stream reads a session's events too.
+
+// sessionVersion is the session file's format; 176's events carry their
+// own schema version inside it.
+const sessionVersion = 1
+
+// sessionFile is what a session file holds.
+type sessionFile struct {
+	Version  int               `json:"version"`
+	Topic    string            `json:"topic"`
+	Lesson   string            `json:"lesson"`
+	Recorded time.Time         `json:"recorded"`
+	Events   []json.RawMessage `json:"events"` // 176's event stream, one line each
+}
+
+// runEvent is the part of 176's Event that a recording reads.
+type runEvent struct {
+	V        int       `json:"v"`
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Section  int       `json:"section,omitempty"`
+	Title    string    `json:"title,omitempty"`
+	Lesson   string    `json:"lesson,omitempty"`
+	Stream   string    `json:"stream,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+	Failed   string    `json:"failed,omitempty"`
+}
+
+func (c *CLI) record(args []string) error {
+	var timeout time.Duration
+	var out, cast, size string
+	args, err := c.flags("record", args, &timeout, func(fs *flag.FlagSet) {
+		fs.StringVar(&out, "out", "session.json", "the session file to write")
+		fs.StringVar(&cast, "cast", "", "also write the run as an asciicast v2 file")
+		fs.StringVar(&size, "size", "", "the cast's terminal size, COLSxROWS")
+	})
+	if err != nil {
+		return err
+	}
+	cols, rows, err := termSize(size)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "record", "cli.want-one-topic")
+	}
+	topic := args[0]
+	if n, err := strconv.Atoi(topic); err != nil || n <= 0 {
+		return M(CodeUsage, "record", "cli.topic-not-number", topic)
+	}
+	runner, err := c.find("record", "176")
+	if err != nil {
+		return err
+	}
+	s, events, err := c.recordEvents(runner, topic, timeout)
+	if err != nil {
+		return err
+	}
+	if err := s.write(out); err != nil {
+		return E(CodeIO, "record", err)
+	}
+	if cast != "" {
+		if err := writeCast(cast, s.Lesson, events, cols, rows); err != nil {
+			return E(CodeIO, "record", err)
+		}
+	}
+	sum := events[len(events)-1]
+	fmt.Fprintf(c.Stderr, "recorded %s: %d events, %.1fs, in %s\n", s.Lesson, len(events),
+		sum.Time.Sub(events[0].Time).Seconds(), out)
+	if sum.Type == "summary" && sum.Failed != "" {
+		return E(CodeVerify, "record "+s.Lesson, fmt.Sprintf("the lesson failed to %s; recorded anyway", sum.Failed))
+	}
+	return nil
+}
+
+// recordEvents runs topic through 176 and reads its events as they come,
+// showing the lesson's output meanwhile. 176 is built first and its
+// binary run, for the reason runLesson gives.
+func (c *CLI) recordEvents(runner, topic string, timeout time.Duration) (*sessionFile, []runEvent, error) {
+	op := "record " + topic
+	tmp, err := os.MkdirTemp("", "gotut-record-")
+	if err != nil {
+		return nil, nil, E(CodeIO, op, err)
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "run_events")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := goCmd(ctx, CodeInternal, "build "+filepath.Base(runner), runner, c.Stdout, c.Stderr, "build", "-o", bin); err != nil {
+		return nil, nil, err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, "run", topic, "--format", "json")
+	cmd.Dir = filepath.Dir(runner) // 176 looks for lessons from where it is
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, E(CodeIO, op, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, E(CodeIO, op, err)
+	}
+
+	s := &sessionFile{Version: sessionVersion, Topic: topic, Recorded: time.Now().UTC()}
+	var events []runEvent
+	var bad error
+	lines := bufio.NewScanner(stdout)
+	lines.Buffer(nil, 1<<20) // A code event is a whole function
+	for lines.Scan() {
+		var ev runEvent
+		if err := json.Unmarshal(lines.Bytes(), &ev); err != nil || ev.V != 1 {
+			bad = fmt.Errorf("176 wrote %.60q, not a v1 event", lines.Text())
+			break
+		}
+		if ev.Type == "start" {
+			s.Lesson = ev.Lesson
+		}
+		s.Events = append(s.Events, json.RawMessage(bytes.Clone(lines.Bytes())))
+		events = append(events, ev)
+		c.show(ev)
+	}
+	if bad == nil {
+		bad = lines.Err()
+	}
+	io.Copy(io.Discard, stdout) // Let 176 finish after a bad line
+	err = cmd.Wait()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == ExitUsage:
+		return nil, nil, E(CodeUnknownTopic, op, strings.TrimSpace(stderr.String()))
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == ExitVerify:
+		// The lesson failed, and 176 said so in the summary: still a session.
+	case err != nil:
+		return nil, nil, withTail(classify(ctx, CodeInternal, op, err), &stderr)
+	}
+	if bad != nil {
+		return nil, nil, E(CodeInternal, op, bad)
+	}
+	if len(events) == 0 {
+		return nil, nil, E(CodeInternal, op, "176 wrote no events")
+	}
+	return s, events, nil
+}
+
+// show prints an output event the way the lesson printed it.
+func (c *CLI) show(ev runEvent) {
+	if ev.Type != "output" {
+		return
+	}
+	w := c.Stdout
+	if ev.Stream != "stdout" {
+		w = c.Stderr // stderr, and the build's own errors
+	}
+	fmt.Fprintln(w, ev.Text)
+}
+
+// write saves s with one event per line, so two recordings diff well.
+func (s *sessionFile) write(path string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "{\"version\": %d, \"topic\": %q, \"lesson\": %q, \"recorded\": %q, \"events\": [\n",
+		s.Version, s.Topic, s.Lesson, s.Recorded.Format(time.RFC3339Nano))
+	for i, ev := range s.Events {
+		b.Write(ev)
+		if i < len(s.Events)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("]}\n")
+	if !json.Valid(b.Bytes()) {
+		return fmt.Errorf("writing %s: not valid JSON", path) // A bug here, not in the events
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// readSession loads a session file and decodes its events.
+func readSession(op, path string) (*sessionFile, []runEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, E(CodeIO, op, err)
+	}
+	var s sessionFile
+	if err := json.Unmarshal(data, &s); err != nil || s.Version != sessionVersion {
+		return nil, nil, M(CodeUsage, op, "cli.bad-session", path, sessionVersion)
+	}
+	events := make([]runEvent, len(s.Events))
+	for i, raw := range s.Events {
+		if err := json.Unmarshal(raw, &events[i]); err != nil || events[i].V != 1 {
+			return nil, nil, M(CodeUsage, op, "cli.bad-session", path, sessionVersion)
+		}
+	}
+	if len(events) == 0 {
+		return nil, nil, M(CodeUsage, op, "cli.bad-session", path, sessionVersion)
+	}
+	return &s, events, nil
+}
+
+func (c *CLI) replay(args []string) error {
+	var speed float64
+	var cast, size string
+	args, err := c.flags("replay", args, nil, func(fs *flag.FlagSet) {
+		fs.Float64Var(&speed, "speed", 1, "play back this many times as fast")
+		fs.StringVar(&cast, "cast", "", "write the session as an asciicast v2 file instead of playing it")
+		fs.StringVar(&size, "size", "", "the cast's terminal size, COLSxROWS")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "replay", "cli.want-one-session")
+	}
+	if speed <= 0 {
+		return M(CodeUsage, "replay", "cli.bad-speed", speed)
+	}
+	s, events, err := readSession("replay", args[0])
+	if err != nil {
+		return err
+	}
+	if cast != "" {
+		cols, rows, err := termSize(size)
+		if err != nil {
+			return err
+		}
+		if err := writeCast(cast, s.Lesson, events, cols, rows); err != nil {
+			return E(CodeIO, "replay", err)
+		}
+		return nil
+	}
+	// The clock starts at the start event, so the wait for the build is
+	// part of the replay too, and each line comes out when it did.
+	start := time.Now()
+	for _, ev := range events {
+		if ev.Type != "output" {
+			continue
+		}
+		at := time.Duration(float64(ev.Time.Sub(events[0].Time)) / speed)
+		time.Sleep(time.Until(start.Add(at)))
+		c.show(ev)
+	}
+	return nil
+}
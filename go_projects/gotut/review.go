@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/bundle"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/diff"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/kata"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut review
+// ---------------------------------------------------------
+// Peer review: a learner packs their exercises, with how the tests went,
+// into one signed file (pkg/bundle) and hands it to someone in their
+// group, who checks it and reads it against their own course.
+//
+//	gotut review export [-o FILE] [-name NAME] TOPIC...
+//	gotut review import [-mine] [-into DIR] FILE
+//
+// The group shares a key in $GOTUT_REVIEW_KEY. Import shows each
+// exercise's test results as a table, then a diff of each file: from the
+// peer's version to the reference solution, or with -mine to the
+// reviewer's own. -into also writes the peer's files out, next to copies
+// of the course's tests, so "go test" runs there.
+//
+// A bundle that fails its signature is exit 3, a verification failure:
+// nothing in it is shown.
+
+func (c *CLI) review(args []string) error {
+	if len(args) == 0 {
+		return M(CodeUsage, "review", "cli.want-review-command")
+	}
+	switch args[0] {
+	case "export":
+		return c.reviewExport(args[1:])
+	case "import":
+		return c.reviewImport(args[1:])
+	}
+	return M(CodeUsage, "review", "cli.want-review-command")
+}
+
+func (c *CLI) reviewExport(args []string) error {
+	var timeout time.Duration
+	var out, name string
+	args, err := c.flags("review export", args, &timeout, func(fs *flag.FlagSet) {
+		fs.StringVar(&out, "o", "review.tar.gz", "the bundle to write")
+		fs.StringVar(&name, "name", learnerName(), "who the bundle says made it")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return M(CodeUsage, "review export", "cli.want-topics")
+	}
+	key, err := bundle.Key()
+	if err != nil {
+		return E(CodeUsage, "review export", err)
+	}
+
+	b := &bundle.Bundle{Learner: name}
+	for _, topic := range args {
+		ex, err := c.exportExercise(topic, timeout)
+		if err != nil {
+			return err
+		}
+		b.Exercises = append(b.Exercises, ex)
+	}
+	var buf bytes.Buffer
+	if err := bundle.Write(&buf, b, key); err != nil {
+		return E(CodeInternal, "review export", err)
+	}
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		return E(CodeIO, "review export", err)
+	}
+	fmt.Fprintf(c.Stdout, "%s: %s's bundle, signed\n", out, name)
+	for _, ex := range b.Exercises {
+		fmt.Fprintf(c.Stdout, "  %-32s %s\n", ex.Dir, testSummary(ex))
+	}
+	return nil
+}
+
+// exportExercise reads topic's exercise files, leaving out the tests and
+// the reference, and runs its tests.
+func (c *CLI) exportExercise(topic string, timeout time.Duration) (bundle.Exercise, error) {
+	dir, err := c.findExercise("review export", topic)
+	if err != nil {
+		return bundle.Exercise{}, err
+	}
+	op := "review export " + filepath.Base(dir)
+	ex := bundle.Exercise{Dir: filepath.Base(dir)}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return ex, E(CodeInternal, op, err)
+	}
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return ex, E(CodeIO, op, err)
+		}
+		ex.Files = append(ex.Files, bundle.File{Name: filepath.Base(p), Data: data})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := kata.RunTests(ctx, dir)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ex, E(CodeTimeout, op, err)
+	} else if err != nil {
+		return ex, E(CodeIO, op, err)
+	}
+	ex.Build = res.Build
+	for _, t := range res.Tests {
+		ex.Tests = append(ex.Tests, bundle.Test{Name: t.Name, Pass: t.Pass, Message: t.Message})
+	}
+	return ex, nil
+}
+
+// learnerName is the default -name: the login name, which is only a
+// default; the bundle carries whatever the author says.
+func learnerName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "someone"
+}
+
+func (c *CLI) reviewImport(args []string) error {
+	var mine bool
+	var into string
+	args, err := c.flags("review import", args, nil, func(fs *flag.FlagSet) {
+		fs.BoolVar(&mine, "mine", false, "diff against your own files instead of the reference")
+		fs.StringVar(&into, "into", "", "also write the peer's files under this directory")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "review import", "cli.want-one-bundle")
+	}
+	op := "review import " + filepath.Base(args[0])
+	key, err := bundle.Key()
+	if err != nil {
+		return E(CodeUsage, op, err)
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	defer f.Close()
+	b, err := bundle.Read(f, key)
+	if err != nil {
+		return E(CodeVerify, op, err) // Tampered, wrong key or not a bundle: don't show any of it
+	}
+
+	fmt.Fprintf(c.Stdout, "%s's bundle, signature ok, made %s\n", b.Learner, b.Created.Local().Format("2006-01-02 15:04"))
+	for _, ex := range b.Exercises {
+		fmt.Fprintf(c.Stdout, "\n=== %s: %s\n", ex.Dir, testSummary(ex))
+		if err := c.reviewExercise(op, ex, mine); err != nil {
+			return err
+		}
+	}
+	if into == "" {
+		return nil
+	}
+	dest := filepath.Join(into, "review-"+safeName(b.Learner))
+	if err := c.writeReview(op, dest, b); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "\nWrote %s's files under %s\n", b.Learner, dest)
+	return nil
+}
+
+// reviewExercise prints ex's test results and a diff of each of its files
+// against the matching file in this course.
+func (c *CLI) reviewExercise(op string, ex bundle.Exercise, mine bool) error {
+	if ex.Build != "" {
+		fmt.Fprintln(c.Stdout, "It did not compile:")
+		for l := range strings.Lines(ex.Build) {
+			fmt.Fprint(c.Stdout, "  ", l)
+		}
+		fmt.Fprintln(c.Stdout)
+	} else if len(ex.Tests) > 0 {
+		tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TEST\tRESULT\tMESSAGE")
+		for _, t := range ex.Tests {
+			result := "FAIL"
+			if t.Pass {
+				result = "ok"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Name, result, t.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return E(CodeIO, op, err)
+		}
+		fmt.Fprintln(c.Stdout)
+	}
+
+	dir, err := filepath.Abs(filepath.Join(c.Dir, ex.Dir))
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	against, label := solutionExt, "reference"
+	if mine {
+		against, label = "", "yours"
+	}
+	for _, f := range ex.Files {
+		theirs := f.Name + " (theirs)"
+		want, err := os.ReadFile(filepath.Join(dir, f.Name+against))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			fmt.Fprintf(c.Stdout, "// %s: nothing to compare with here\n%s\n", theirs, f.Data)
+		case err != nil:
+			return E(CodeIO, op, err)
+		case bytes.Equal(f.Data, want):
+			fmt.Fprintf(c.Stdout, "%s: the same as %s\n", theirs, label)
+		default:
+			fmt.Fprint(c.Stdout, diff.Unified(theirs, f.Name+" ("+label+")", f.Data, want))
+		}
+	}
+	return nil
+}
+
+// writeReview writes each exercise's files to dest/DIR, with the course's
+// tests for it copied alongside. The names are plain (bundle.Read checks),
+// so nothing lands outside dest.
+func (c *CLI) writeReview(op, dest string, b *bundle.Bundle) error {
+	for _, ex := range b.Exercises {
+		dir := filepath.Join(dest, ex.Dir)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return E(CodeIO, op, err)
+		}
+		for _, f := range ex.Files {
+			if err := os.WriteFile(filepath.Join(dir, f.Name), f.Data, 0o644); err != nil {
+				return E(CodeIO, op, err)
+			}
+		}
+		tests, err := filepath.Glob(filepath.Join(c.Dir, ex.Dir, "*_test.go"))
+		if err != nil {
+			return E(CodeInternal, op, err)
+		}
+		for _, t := range tests {
+			data, err := os.ReadFile(t)
+			if err != nil {
+				return E(CodeIO, op, err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, filepath.Base(t)), data, 0o644); err != nil {
+				return E(CodeIO, op, err)
+			}
+		}
+	}
+	return nil
+}
+
+// safeName keeps letters, digits, "-" and "_": Learner is whatever the
+// author typed, and "../../x" must not pick the directory.
+func safeName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+	if s == "" {
+		return "peer"
+	}
+	return s
+}
+
+// testSummary is "2/3 tests pass", or why there are no results.
+func testSummary(ex bundle.Exercise) string {
+	switch {
+	case ex.Build != "":
+		return "does not compile"
+	case len(ex.Tests) == 0:
+		return "no tests ran"
+	}
+	return fmt.Sprintf("%d/%d tests pass", ex.Passed(), len(ex.Tests))
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Scenarios drive the built binary through whole sessions, the way a
+// learner or a CI job would: several commands in a row against one
+// course, one home directory and one working directory, each step's exit
+// status and output checked, and afterwards the files the session left
+// behind. What they catch is what falls between the unit tests: a command
+// that passes alone but breaks the one after it, a file written to the
+// wrong place, a lesson's stdin that never arrives.
+
+// quizLesson is a lesson that reads its answers from stdin, as a quiz
+// does. It scores them, appends the score to scores.txt in the working
+// directory, and fails — exit 1 — unless every answer was right.
+const quizLesson = `package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	questions := []struct{ q, a string }{
+		{"What is 1+2?", "3"},
+		{"What is 10%3?", "1"},
+	}
+	in := bufio.NewScanner(os.Stdin)
+	right := 0
+	for i, q := range questions {
+		fmt.Printf("%d. %s ", i+1, q.q)
+		if !in.Scan() {
+			fmt.Println()
+			fmt.Fprintln(os.Stderr, "quiz: no answer")
+			os.Exit(2)
+		}
+		if strings.TrimSpace(in.Text()) == q.a {
+			right++
+			fmt.Println("right")
+		} else {
+			fmt.Println("wrong, it's", q.a)
+		}
+	}
+	fmt.Printf("score: %d/%d\n", right, len(questions))
+	f, err := os.OpenFile("scores.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(f, "%d/%d\n", right, len(questions))
+	f.Close()
+	if right < len(questions) {
+		os.Exit(1)
+	}
+}
+`
+
+// step is one thing a scenario does: a gotut command line with its stdin,
+// or, with do set, a change the learner makes between commands.
+type step struct {
+	args   string
+	stdin  string
+	want   int
+	stdout string // Substrings each output must contain; "" checks nothing
+	stderr string
+	do     func(t *testing.T, s *session)
+}
+
+// session is where a scenario runs: a course, a home directory with
+// nothing in it, and a working directory to run gotut from.
+type session struct {
+	course, home, work string
+	env                []string
+}
+
+func newSession(t *testing.T) *session {
+	t.Helper()
+	s := &session{course: t.TempDir(), home: t.TempDir(), work: t.TempDir()}
+	if err := writeCourse(s.course); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.course, "007_quiz.go"), []byte(quizLesson), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A fresh HOME, with nothing pointing elsewhere, so the progress log
+	// lands at its default path. The go tool keeps the real cache and
+	// GOPATH: a cold cache under the new HOME would rebuild the standard
+	// library for every lesson.
+	out, err := exec.Command("go", "env", "GOCACHE", "GOPATH").Output()
+	if err != nil {
+		t.Fatalf("go env: %v", err)
+	}
+	goenv := strings.Fields(string(out))
+	s.env = []string{
+		"LC_ALL=C", "HOME=" + s.home, "XDG_CONFIG_HOME=", "GOTUT_CONFIG_DIR=",
+		"GOCACHE=" + goenv[0], "GOPATH=" + goenv[1],
+	}
+	return s
+}
+
+// run runs one gotut command line in s.work and returns $?, stdout and
+// stderr, with the session's paths written as $COURSE and $HOME so a
+// failure message reads the same on every machine.
+func (s *session) run(t *testing.T, args, stdin string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	cmd := exec.Command(gotut, append([]string{"-dir", s.course}, strings.Fields(args)...)...)
+	cmd.Dir = s.work
+	cmd.Env = append(os.Environ(), s.env...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	code := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("running gotut %s: %v", args, err)
+	}
+	clean := strings.NewReplacer(s.course, "$COURSE", s.home, "$HOME").Replace
+	return code, clean(stdout.String()), clean(stderr.String())
+}
+
+// configDir is where gotut keeps its progress log under s.home, worked
+// out the way gotut itself does: os.UserConfigDir, with HOME moved.
+func (s *session) configDir(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", s.home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "gotut")
+}
+
+func TestScenarios(t *testing.T) {
+	scenarios := []struct {
+		name  string
+		steps []step
+		// files maps a path ($CONFIG is gotut's directory under $HOME,
+		// $WORK the working directory) to a substring of its contents;
+		// absent lists paths that must not exist.
+		files  map[string]string
+		absent []string
+	}{
+		{
+			name: "first lesson",
+			steps: []step{
+				{args: "help", stdout: "Exit status:"},
+				{args: "run 1", stdout: "hello"},
+				{args: "run 001", stdout: "hello"},
+				{args: "run 99", want: ExitUsage, stderr: "no lesson 99 in $COURSE"},
+			},
+			absent: []string{"$CONFIG/progress.json", "$WORK/scores.txt"},
+		},
+		{
+			name: "quiz with piped answers",
+			steps: []step{
+				{args: "run 7", stdin: "3\n1\n", stdout: "score: 2/2"},
+				{args: "run 7", stdin: "3\n2\n", want: ExitVerify, stdout: "wrong, it's 1", stderr: "exit status 1"},
+				{args: "run 7", stdin: "", want: ExitVerify, stderr: "exit status 2"}, // Nothing piped: EOF, not a hang
+				{args: "run 7", stdin: " 3 \r\n1", stdout: "score: 2/2"},              // Spaces, CRLF, no final newline
+			},
+			files: map[string]string{"$WORK/scores.txt": "2/2\n1/2\n2/2\n"},
+		},
+		{
+			name: "stuck on an exercise, then solved",
+			steps: []step{
+				{args: "test 5", want: ExitTestFailed, stdout: "Sum(2, 3) = 2, want 5"},
+				{args: "hint 5", stdout: "Add them up."},
+				{args: "hint 5 --level 3", want: ExitUsage, stderr: "read level 2 first"},
+				{args: "hint 5", stdout: "range over xs."},
+				{args: "solution --diff --failed 5", stdout: "Failing: TestSum"},
+				{do: func(t *testing.T, s *session) {
+					ref, err := os.ReadFile(filepath.Join(s.course, "005_sum", "sum.go.solution"))
+					if err == nil {
+						err = os.WriteFile(filepath.Join(s.course, "005_sum", "sum.go"), ref, 0o644)
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+				}},
+				{args: "test 5", stdout: "ok"},
+				{args: "solution --diff --failed 5", stdout: "Every test in 005_sum passes"},
+			},
+			files: map[string]string{"$CONFIG/progress.json": `"kind": "hint"`},
+		},
+		{
+			name: "CI verify",
+			steps: []step{
+				{args: "verify 1 6", stdout: "ok   1\nok   6\n"},
+				{args: "verify 1 2 3", want: ExitVerify, stdout: "ok   1\nFAIL 2\nFAIL 3\n", stderr: "undefined"},
+				{args: "verify -timeout 2s 4 5", want: ExitRuntime, stderr: "deadline exceeded"},
+			},
+			// verify builds and runs in scratch directories: nothing is
+			// left in the course, the working directory or $HOME.
+			absent: []string{"$COURSE/001_hello", "$COURSE/lesson", "$WORK/lesson", "$CONFIG/progress.json"},
+		},
+	}
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			s := newSession(t)
+			for _, st := range sc.steps {
+				if st.do != nil {
+					st.do(t, s)
+					continue
+				}
+				got, stdout, stderr := s.run(t, st.args, st.stdin)
+				if got != st.want {
+					t.Fatalf("gotut %s: exit %d, want %d\nstdout: %s\nstderr: %s", st.args, got, st.want, stdout, stderr)
+				}
+				if !strings.Contains(stdout, st.stdout) {
+					t.Errorf("gotut %s: stdout %q, want it to contain %q", st.args, stdout, st.stdout)
+				}
+				if !strings.Contains(stderr, st.stderr) {
+					t.Errorf("gotut %s: stderr %q, want it to contain %q", st.args, stderr, st.stderr)
+				}
+			}
+
+			path := strings.NewReplacer("$HOME", s.home, "$WORK", s.work, "$COURSE", s.course, "$CONFIG", s.configDir(t)).Replace
+			for name, want := range sc.files {
+				data, err := os.ReadFile(path(name))
+				if err != nil {
+					t.Errorf("%s: %v", name, err)
+				} else if !strings.Contains(string(data), want) {
+					t.Errorf("%s = %q, want it to contain %q", name, data, want)
+				}
+			}
+			for _, name := range sc.absent {
+				if _, err := os.Stat(path(name)); err == nil {
+					t.Errorf("%s exists, and nothing in this scenario should write it", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/diff"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut solution
+// ---------------------------------------------------------
+// An exercise's reference solution sits next to the file it solves:
+// strings.go.solution for strings.go. The go tool skips it (not .go), so
+// the exercise still builds with the learner's version.
+//
+//	gotut solution TOPIC                    print the reference
+//	gotut solution TOPIC --diff             your file → the reference, unified
+//	gotut solution TOPIC --diff --failed    only the functions whose tests fail
+//
+// --failed runs the tests first and maps each failing TestName (or
+// TestName_case) to the function Name, so a learner with one function
+// left sees the answer to that one and not the others. Every look goes
+// into the progress log, like the hints.
+
+// solutionExt marks a reference solution: X.go's is X.go.solution.
+const solutionExt = ".solution"
+
+func (c *CLI) solution(args []string) error {
+	var timeout time.Duration
+	var showDiff, failed bool
+	args, err := c.flags("solution", args, &timeout, func(fs *flag.FlagSet) {
+		fs.BoolVar(&showDiff, "diff", false, "show a unified diff from your file to the reference")
+		fs.BoolVar(&failed, "failed", false, "only the functions whose tests fail")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "solution", "cli.want-one-topic")
+	}
+	dir, err := c.findExercise("solution", args[0])
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return E(CodeInternal, "solution", err) // findExercise has checked it
+	}
+	op := "solution " + filepath.Base(dir)
+	refs, err := filepath.Glob(filepath.Join(dir, "*.go"+solutionExt))
+	if err != nil {
+		return E(CodeInternal, op, err)
+	}
+	if len(refs) == 0 {
+		return M(CodeUsage, op, "cli.no-solution", filepath.Base(dir))
+	}
+
+	var only []string // Function names to show; nil means everything
+	if failed {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		tests, err := failingTests(ctx, op, dir)
+		switch {
+		case errors.As(err, new(*buildError)):
+			fmt.Fprintf(c.Stdout, "%s doesn't compile, so no test ran: showing everything.\n\n", filepath.Base(dir))
+		case err != nil:
+			return err
+		case len(tests) == 0:
+			fmt.Fprintf(c.Stdout, "Every test in %s passes: nothing to reveal.\n", filepath.Base(dir))
+			return nil
+		default:
+			only = testedFuncs(tests)
+			fmt.Fprintf(c.Stdout, "Failing: %s\n\n", strings.Join(tests, ", "))
+		}
+	}
+
+	for _, ref := range refs {
+		mine := strings.TrimSuffix(ref, solutionExt)
+		name := filepath.Base(mine)
+		want, err := os.ReadFile(ref)
+		if err != nil {
+			return E(CodeIO, op, err)
+		}
+		got, err := os.ReadFile(mine)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return E(CodeIO, op, err)
+		}
+		if only != nil {
+			if got, err = funcSource(got, only); err != nil {
+				return E(CodeTestFailed, op, fmt.Errorf("%s: %w", name, err))
+			}
+			want, _ = funcSource(want, only) // The reference always parses: TestSolutions-style checks keep it so
+		}
+		switch {
+		case !showDiff:
+			fmt.Fprintf(c.Stdout, "// %s (reference)\n%s\n", name, want)
+		case bytes.Equal(got, want):
+			fmt.Fprintf(c.Stdout, "%s: the same as the reference\n", name)
+		default:
+			fmt.Fprint(c.Stdout, diff.Unified(name+" (yours)", name+" (reference)", got, want))
+		}
+	}
+
+	result := "full"
+	if showDiff {
+		result = "diff"
+	}
+	detail := "all"
+	if only != nil {
+		detail = strings.Join(only, ", ")
+	}
+	path, err := progress.Path()
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	err = progress.Record(path, progress.Event{Kind: "solution", Item: strconv.Itoa(n), Result: result, Detail: detail})
+	if err != nil {
+		return E(CodeIO, op, err)
+	}
+	return nil
+}
+
+// buildError is an exercise that didn't compile, so no test ran.
+type buildError struct{ out string }
+
+func (e *buildError) Error() string { return "does not compile: " + e.out }
+
+// failingTests runs dir's tests and returns the top-level tests that
+// failed, in the order they ran.
+func failingTests(ctx context.Context, op, dir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "go", "test", "-json", "-count=1", ".")
+	cmd.Dir = dir
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, E(CodeTimeout, op, ctx.Err())
+	}
+	var failed []string
+	var build strings.Builder
+	ran := false
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		var e struct{ Action, Test, Output string }
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		switch {
+		case e.Action == "build-output":
+			build.WriteString(e.Output)
+		case e.Test == "" || strings.Contains(e.Test, "/"):
+		case e.Action == "run":
+			ran = true
+		case e.Action == "fail":
+			failed = append(failed, e.Test)
+		}
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil || ran:
+		return failed, nil
+	case errors.As(runErr, &exitErr):
+		return nil, &buildError{strings.TrimSpace(build.String())}
+	}
+	return nil, E(CodeIO, op, runErr)
+}
+
+// testedFuncs maps tests to the functions they test by name:
+// TestSlugify and TestSlugify_unicode both test Slugify.
+func testedFuncs(tests []string) []string {
+	var funcs []string
+	for _, t := range tests {
+		name, _, _ := strings.Cut(strings.TrimPrefix(t, "Test"), "_")
+		if !slices.Contains(funcs, name) {
+			funcs = append(funcs, name)
+		}
+	}
+	return funcs
+}
+
+// funcSource cuts the named top-level functions, doc comments included,
+// out of src, in file order.
+func funcSource(src []byte, names []string) ([]byte, error) {
+	if len(src) == 0 {
+		return nil, nil
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("doesn't parse, so its functions can't be picked out: %v", err)
+	}
+	var parts [][]byte
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !slices.Contains(names, fn.Name.Name) {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		parts = append(parts, src[fset.Position(start).Offset:fset.Position(fn.End()).Offset])
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	return append(bytes.Join(parts, []byte("\n\n")), '\n'), nil
+}
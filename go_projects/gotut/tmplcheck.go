@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template/parse"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/tmplfuncs"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut tmpl-check
+// ---------------------------------------------------------
+// A template's mistakes show up when it runs — and only on the branch
+// that runs: a misspelt field inside {{if .Refunded}} waits for the
+// first refund. tmpl-check finds them before then, in CI, by walking
+// the parse tree of every *.tmpl file in a directory (parsed together,
+// as tmplreg.WatchDir and template.ParseGlob do, intermediate Topic 72):
+//
+//	gotut tmpl-check DIR                   syntax, and functions that don't exist
+//	gotut tmpl-check -data sample.json DIR ...and fields the sample doesn't have
+//
+// The functions a template may call are text/template's built-ins and
+// tmplfuncs.Default(). The sample is JSON, because a command line can't
+// be handed a Go struct: the shape of the data the program passes, a
+// method listed as the value it returns. Every branch is checked, taken
+// or not; {{range}} checks its body against the first element of a
+// list, and {{with}} against the value it names. Where the sample says
+// nothing (null, an empty list), nothing below it is checked.
+//
+// Problems are printed one per line, file:line:col first, like a
+// compiler's, and are a verification failure: exit 3.
+
+// builtinFuncs are the functions every text/template has.
+var builtinFuncs = []string{"and", "call", "html", "index", "slice", "js", "len", "not", "or",
+	"print", "printf", "println", "urlquery", "eq", "ge", "gt", "le", "lt", "ne"}
+
+func (c *CLI) tmplCheck(args []string) error {
+	var dataFile string
+	args, err := c.flags("tmpl-check", args, nil, func(fs *flag.FlagSet) {
+		fs.StringVar(&dataFile, "data", "", "a JSON `file` with sample data to check fields against")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return M(CodeUsage, "tmpl-check", "cli.want-one-dir")
+	}
+	files, err := filepath.Glob(filepath.Join(args[0], "*.tmpl"))
+	if err != nil {
+		return E(CodeUsage, "tmpl-check", fmt.Errorf("%s: %w", args[0], err)) // A "[" in DIR
+	}
+	if len(files) == 0 {
+		return M(CodeUsage, "tmpl-check", "cli.no-templates", args[0])
+	}
+	var sample any
+	if dataFile != "" {
+		data, err := os.ReadFile(dataFile)
+		if err != nil {
+			return E(CodeIO, "tmpl-check", err)
+		}
+		if err := json.Unmarshal(data, &sample); err != nil {
+			return E(CodeUsage, "tmpl-check", fmt.Errorf("%s: %w", dataFile, err))
+		}
+	}
+	problems, err := checkTemplates(files, sample)
+	if err != nil {
+		return E(CodeIO, "tmpl-check", err)
+	}
+	for _, p := range problems {
+		fmt.Fprintln(c.Stdout, p)
+	}
+	if len(problems) > 0 {
+		return E(CodeVerify, "tmpl-check", fmt.Sprintf("%s in %s", plural(len(problems), "problem"), plural(len(files), "template")))
+	}
+	fmt.Fprintln(c.Stdout, plural(len(files), "template"), "OK")
+	return nil
+}
+
+// tmplChecker walks parse trees, collecting problems.
+type tmplChecker struct {
+	trees    map[string]*parse.Tree
+	funcs    map[string]bool
+	problems []string
+	seen     map[string]bool // Problems already reported
+	walked   map[string]bool // Templates checked at least once
+	active   map[string]bool // Templates being checked now: {{template}} can recurse
+}
+
+// checkTemplates parses files into one set and checks every template in
+// it against sample; a nil sample checks no fields. Only an unreadable
+// file is an error; everything wrong inside one is a problem.
+func checkTemplates(files []string, sample any) ([]string, error) {
+	c := &tmplChecker{trees: map[string]*parse.Tree{}, funcs: map[string]bool{},
+		seen: map[string]bool{}, walked: map[string]bool{}, active: map[string]bool{}}
+	for _, name := range builtinFuncs {
+		c.funcs[name] = true
+	}
+	for name := range tmplfuncs.Default() {
+		c.funcs[name] = true
+	}
+	var names []string
+	for _, f := range files {
+		text, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		t := parse.New(filepath.Base(f))
+		t.Mode = parse.SkipFuncCheck | parse.ParseComments // Functions are checked below, all of them
+		if _, err := t.Parse(string(text), "", "", c.trees); err != nil {
+			c.problem(strings.TrimPrefix(err.Error(), "template: "))
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	for _, name := range names {
+		c.call(name, sample)
+	}
+	// {{define}}d templates no file calls still get their functions checked.
+	for _, name := range slices.Sorted(maps.Keys(c.trees)) {
+		if !c.walked[name] {
+			c.call(name, nil)
+		}
+	}
+	return c.problems, nil
+}
+
+func (c *tmplChecker) problem(p string) {
+	if !c.seen[p] {
+		c.seen[p] = true
+		c.problems = append(c.problems, p)
+	}
+}
+
+func (c *tmplChecker) report(t *parse.Tree, n parse.Node, format string, args ...any) {
+	loc, _ := t.ErrorContext(n)
+	c.problem(loc + ": " + fmt.Sprintf(format, args...))
+}
+
+// call checks the template name with dot as its data.
+func (c *tmplChecker) call(name string, dot any) {
+	t, ok := c.trees[name]
+	if !ok || c.active[name] {
+		return
+	}
+	c.walked[name], c.active[name] = true, true
+	defer delete(c.active, name)
+	c.walk(t, t.Root, dot, map[string]any{"$": dot})
+}
+
+// walk checks n with dot as "." and vars as the variables in scope. A
+// nil value is one the sample doesn't describe: nothing is checked on it.
+func (c *tmplChecker) walk(t *parse.Tree, n parse.Node, dot any, vars map[string]any) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		vars = maps.Clone(vars) // Variables end with the block that declared them
+		for _, child := range n.Nodes {
+			c.walk(t, child, dot, vars)
+		}
+	case *parse.ActionNode:
+		c.pipe(t, n.Pipe, dot, vars)
+	case *parse.IfNode:
+		c.pipe(t, n.Pipe, dot, vars)
+		c.walk(t, n.List, dot, vars)
+		c.walk(t, n.ElseList, dot, vars)
+	case *parse.WithNode:
+		v := c.pipe(t, n.Pipe, dot, vars)
+		c.walk(t, n.List, v, vars)
+		c.walk(t, n.ElseList, dot, vars)
+	case *parse.RangeNode:
+		inner := maps.Clone(vars)
+		v := c.pipe(t, n.Pipe, dot, inner)
+		var elem any
+		if list, ok := v.([]any); ok && len(list) > 0 {
+			elem = list[0]
+		}
+		// {{range $x := .L}} or {{range $i, $x := .L}}: the last one is the element.
+		if d := n.Pipe.Decl; len(d) > 0 {
+			for _, v := range d {
+				inner[v.Ident[0]] = nil
+			}
+			inner[d[len(d)-1].Ident[0]] = elem
+		}
+		c.walk(t, n.List, elem, inner)
+		c.walk(t, n.ElseList, dot, vars)
+	case *parse.TemplateNode:
+		var arg any
+		if n.Pipe != nil {
+			arg = c.pipe(t, n.Pipe, dot, vars)
+		}
+		c.call(n.Name, arg)
+	}
+}
+
+// pipe checks a pipeline and returns its value, when it is simply a
+// field, a variable or dot; anything computed is unknown (nil).
+func (c *tmplChecker) pipe(t *parse.Tree, p *parse.PipeNode, dot any, vars map[string]any) any {
+	if p == nil {
+		return nil
+	}
+	var v any
+	for _, cmd := range p.Cmds {
+		v = c.command(t, cmd, dot, vars)
+	}
+	if len(p.Cmds) > 1 {
+		v = nil // The last command was a function, given what was piped in
+	}
+	for _, d := range p.Decl {
+		vars[d.Ident[0]] = v
+	}
+	return v
+}
+
+func (c *tmplChecker) command(t *parse.Tree, cmd *parse.CommandNode, dot any, vars map[string]any) any {
+	var v any
+	for _, arg := range cmd.Args {
+		v = nil
+		switch a := arg.(type) {
+		case *parse.IdentifierNode:
+			if !c.funcs[a.Ident] {
+				c.report(t, a, "function %q not defined", a.Ident)
+			}
+		case *parse.DotNode:
+			v = dot
+		case *parse.FieldNode:
+			v = c.field(t, a, ".", dot, a.Ident)
+		case *parse.VariableNode:
+			v = c.field(t, a, a.Ident[0]+".", vars[a.Ident[0]], a.Ident[1:])
+		case *parse.ChainNode:
+			if p, ok := a.Node.(*parse.PipeNode); ok {
+				c.pipe(t, p, dot, vars)
+			}
+		case *parse.PipeNode:
+			c.pipe(t, a, dot, vars)
+		}
+	}
+	if len(cmd.Args) > 1 {
+		return nil
+	}
+	return v
+}
+
+// field follows idents from v, reporting the first one the sample lacks.
+// prefix is what the path starts with in a message: "." or "$x.".
+func (c *tmplChecker) field(t *parse.Tree, n parse.Node, prefix string, v any, idents []string) any {
+	for i, name := range idents {
+		path := prefix + strings.Join(idents[:i+1], ".")
+		switch m := v.(type) {
+		case nil:
+			return nil
+		case map[string]any:
+			next, ok := m[name]
+			if !ok {
+				c.report(t, n, "%s: no field %s in the sample data (it has %s)", path, name,
+					strings.Join(slices.Sorted(maps.Keys(m)), ", "))
+				return nil
+			}
+			v = next
+		default:
+			c.report(t, n, "%s: the sample's %s is %s, which has no fields", path,
+				strings.TrimSuffix(prefix+strings.Join(idents[:i], "."), "."), jsonKind(v))
+			return nil
+		}
+	}
+	return v
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a bool"
+	case []any:
+		return "a list"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/trace"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut run --trace
+// ---------------------------------------------------------
+// With --trace, run times the lesson with spans (pkg/trace, Topic 140)
+// and prints the tree to stderr once it has exited:
+//
+//	trace a56d6ebdf506dbf8ae10f5a01e61f4b9
+//	run 113_timers.go                  8.27397s  ████████████████████ 100.0%
+//	  build                           270.084ms  █                      3.3%
+//	  lesson                          8.003876s  ███████████████████   96.7%
+//	    intro                           1.731ms                         0.0%
+//	    Example 1: Basic Timer        2.000757s  █████                 24.2%
+//	    ...
+//
+// A section's span starts at its "--- Example N: ... ---" line and ends
+// at the next one, as 172's run summary cuts them; the lesson's output
+// reaches stdout untouched.
+
+var sectionMarker = regexp.MustCompile(`^\s*--- (.+?) ---\s*$`)
+
+// sectionSpans passes a lesson's stdout through to out and starts a span
+// at each section marker, as a child of the span in ctx.
+type sectionSpans struct {
+	out     io.Writer
+	tracer  *trace.Tracer
+	ctx     context.Context
+	cur     *trace.Span
+	partial []byte // Text after the last newline, waiting for the rest of its line
+}
+
+func newSectionSpans(ctx context.Context, tracer *trace.Tracer, out io.Writer) *sectionSpans {
+	w := &sectionSpans{out: out, tracer: tracer, ctx: ctx}
+	_, w.cur = tracer.Start(ctx, "intro")
+	return w
+}
+
+func (w *sectionSpans) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(p); err != nil {
+		return 0, err
+	}
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if m := sectionMarker.FindSubmatch(bytes.TrimRight(w.partial[:i], "\r")); m != nil {
+			w.cur.End()
+			_, w.cur = w.tracer.Start(w.ctx, string(m[1]))
+		}
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Close ends the last section's span, once the lesson has exited.
+func (w *sectionSpans) Close() {
+	w.cur.End()
+}
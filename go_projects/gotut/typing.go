@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/progress"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/term"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/typing"
+)
+
+// ---------------------------------------------------------
+// Part 3 (continued): gotut type
+// ---------------------------------------------------------
+// A typing drill on lines from the lessons (pkg/typing): the snippet is
+// shown, typed underneath key by key, and scored in words per minute and
+// accuracy. Each finished snippet goes into the progress log, and the
+// fastest one per snippet is the personal best to beat.
+//
+//	gotut type                 3 snippets from anywhere in the course
+//	gotut type -n 1 142        one from Topic 142
+//	gotut type select-timeout  that one
+//	gotut type -bests          the record for each snippet
+//
+// From a terminal, stdin goes into raw mode (pkg/term) so every key is
+// seen as it is typed: a wrong one shows in red and has to be deleted,
+// Esc or Ctrl-C ends the session. From a pipe, each line read is a line
+// typed, and the clock runs from showing the snippet to the last Enter;
+// that is how the tests drive it.
+
+func (c *CLI) typingDrill(args []string) error {
+	var n int
+	var seed uint64
+	var bests bool
+	args, err := c.flags("type", args, nil, func(fs *flag.FlagSet) {
+		fs.IntVar(&n, "n", 3, "how many snippets")
+		fs.Uint64Var(&seed, "seed", 0, "pick the same snippets every time (0: new ones)")
+		fs.BoolVar(&bests, "bests", false, "show each snippet's record instead of typing")
+	})
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return M(CodeUsage, "type", "cli.bad-count", n)
+	}
+	var pick []typing.Snippet
+	for _, a := range args {
+		topic, err := strconv.Atoi(a)
+		found := false
+		for _, s := range typing.Snippets() {
+			if s.Name == a || err == nil && s.Topic == topic {
+				pick, found = append(pick, s), true
+			}
+		}
+		if !found {
+			return M(CodeUsage, "type", "cli.no-snippet", a)
+		}
+	}
+	if len(args) == 0 {
+		pick = typing.Snippets()
+	}
+	path, err := progress.Path()
+	if err != nil {
+		return E(CodeIO, "type", err)
+	}
+	log, err := progress.Load(path)
+	if err != nil {
+		return E(CodeIO, "type", err)
+	}
+	var attempts []typing.Attempt
+	for _, e := range log.Filter("typing") {
+		if a, ok := attemptOf(e); ok {
+			attempts = append(attempts, a)
+		}
+	}
+	if bests {
+		return c.typingBests(typing.Records(attempts), pick)
+	}
+
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+	rng.Shuffle(len(pick), func(i, j int) { pick[i], pick[j] = pick[j], pick[i] })
+
+	var drill func(typing.Snippet) (*typing.Drill, error)
+	if f, ok := c.Stdin.(*os.File); ok {
+		if restore, err := term.MakeRaw(int(f.Fd())); err == nil {
+			defer restore()
+			keys := term.NewReader(f)
+			colors := palette{on: isTerminal(c.Stdout) && os.Getenv("NO_COLOR") == ""}
+			drill = func(s typing.Snippet) (*typing.Drill, error) { return c.typeKeys(keys, colors, s) }
+		}
+	}
+	if drill == nil {
+		in := bufio.NewScanner(strings.NewReader(""))
+		if c.Stdin != nil {
+			in = bufio.NewScanner(c.Stdin)
+		}
+		drill = func(s typing.Snippet) (*typing.Drill, error) { return c.typeLines(in, s) }
+	}
+
+	done := 0
+	for i := range n {
+		s := pick[i%len(pick)]
+		fmt.Fprintf(c.Stdout, "[%d/%d] %s (Topic %d, %s)\n\n%s\n\n", i+1, n, s.Name, s.Topic, s.Source,
+			"  "+strings.ReplaceAll(s.Text, "\n", "\n  "))
+		d, err := drill(s)
+		if err != nil {
+			return E(CodeIO, "type", err)
+		}
+		if d == nil || !d.Done() {
+			fmt.Fprintln(c.Stdout)
+			break
+		}
+		done++
+		score := d.Score()
+		event := progress.Event{Kind: "typing", Item: s.Name, Result: "done", Time: time.Now(), Seconds: score.Elapsed.Seconds(),
+			Detail: fmt.Sprintf("%d chars, %d keys, %d mistakes", score.Chars, score.Keys, score.Mistakes)}
+		if err := progress.Record(path, event); err != nil {
+			return E(CodeIO, "type", err)
+		}
+		fmt.Fprintf(c.Stdout, "\n\n  %.0f wpm, %.0f%% accurate, %.1fs", score.WPM(), 100*score.Accuracy(), score.Elapsed.Seconds())
+		for _, r := range typing.Records(attempts) {
+			switch {
+			case r.Snippet != s.Name:
+			case r.Tries == 0:
+				fmt.Fprint(c.Stdout, " — your first time: that's the best to beat")
+			case typing.Better(score, r.Best.Score):
+				fmt.Fprintf(c.Stdout, " — a new personal best (was %.0f wpm)", r.Best.Score.WPM())
+			default:
+				fmt.Fprintf(c.Stdout, " — best %.0f wpm", r.Best.Score.WPM())
+			}
+		}
+		fmt.Fprint(c.Stdout, "\n\n")
+		attempts = append(attempts, typing.Attempt{Snippet: s.Name, Score: score, Time: event.Time})
+	}
+	fmt.Fprintf(c.Stdout, "%d of %d typed. 'gotut type -bests' shows your records.\n", done, n)
+	return nil
+}
+
+// typeKeys runs one drill on a terminal in raw mode. Output processing is
+// still on there, so "\n" starts a new line as usual. It returns nil when
+// the learner ends the session.
+func (c *CLI) typeKeys(keys *term.Reader, colors palette, s typing.Snippet) (*typing.Drill, error) {
+	d := typing.NewDrill(s.Text)
+	fmt.Fprint(c.Stdout, "  "+d.Typed())
+	for !d.Done() {
+		k, err := keys.ReadKey()
+		if err != nil {
+			return nil, err
+		}
+		switch k {
+		case term.KeyEscape, term.KeyInterrupt, term.KeyEOF:
+			return nil, nil
+		case term.KeyBackspace:
+			if d.Backspace() {
+				fmt.Fprint(c.Stdout, "\b \b")
+			}
+			continue
+		case term.KeyEnter:
+			k = '\n'
+		case term.KeyTab:
+			k = '\t'
+		}
+		if !k.IsRune() {
+			continue
+		}
+		before := len(d.Typed())
+		switch right := d.Type(rune(k), time.Now()); {
+		case k == '\n' && right:
+			fmt.Fprint(c.Stdout, "\n  "+d.Typed()[before+1:]) // And the next line's indentation
+		case k == '\n':
+			fmt.Fprint(c.Stdout, "\a")
+		case right:
+			fmt.Fprint(c.Stdout, string(rune(k)))
+		default:
+			fmt.Fprint(c.Stdout, colors.red(visible(rune(k))))
+		}
+	}
+	return d, nil
+}
+
+// visible is how a wrong character is shown: one column wide, so that
+// Backspace's "\b \b" rubs out all of it.
+func visible(r rune) string {
+	switch r {
+	case ' ':
+		return "·"
+	case '\t':
+		return "→"
+	}
+	return string(r)
+}
+
+// typeLines runs one drill from piped input, a line of it per line of the
+// snippet. The indentation is typed already, so leading spaces and tabs
+// in the input are dropped. A line that isn't right ends the snippet
+// unfinished: there is no Backspace to fix it with.
+func (c *CLI) typeLines(in *bufio.Scanner, s typing.Snippet) (*typing.Drill, error) {
+	d := typing.NewDrill(s.Text)
+	shown := time.Now()
+	lines := strings.Split(s.Text, "\n")
+	for i := range lines {
+		if !in.Scan() {
+			return nil, in.Err()
+		}
+		now := time.Now()
+		line := strings.TrimLeft(strings.TrimSuffix(in.Text(), "\r"), " \t")
+		if i < len(lines)-1 {
+			line += "\n"
+		}
+		for j, r := range line {
+			t := now
+			if i == 0 && j == 0 {
+				t = shown
+			}
+			d.Type(r, t)
+		}
+		if !strings.HasPrefix(s.Text, d.Typed()) || len(d.Typed()) < len(strings.Join(lines[:i+1], "\n")) {
+			fmt.Fprintf(c.Stdout, "  ✗ line %d is not %q — not finished\n", i+1, strings.TrimLeft(lines[i], "\t"))
+			return d, nil
+		}
+	}
+	return d, nil
+}
+
+// attemptOf reads a finished drill back from the progress log.
+func attemptOf(e progress.Event) (typing.Attempt, bool) {
+	a := typing.Attempt{Snippet: e.Item, Time: e.Time, Score: typing.Score{Elapsed: time.Duration(e.Seconds * float64(time.Second))}}
+	_, err := fmt.Sscanf(e.Detail, "%d chars, %d keys, %d mistakes", &a.Score.Chars, &a.Score.Keys, &a.Score.Mistakes)
+	return a, e.Result == "done" && err == nil
+}
+
+func (c *CLI) typingBests(records []typing.Record, only []typing.Snippet) error {
+	tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SNIPPET\tTOPIC\tTRIES\tBEST\tACCURACY\tWHEN")
+	for _, r := range records {
+		for _, s := range only {
+			if s.Name != r.Snippet {
+				continue
+			}
+			best, accuracy, when := "-", "-", "-"
+			if r.Tries > 0 {
+				best = fmt.Sprintf("%.0f wpm", r.Best.Score.WPM())
+				accuracy = fmt.Sprintf("%.0f%%", 100*r.Best.Score.Accuracy())
+				when = r.Best.Time.Local().Format(time.DateOnly)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", s.Name, s.Topic, r.Tries, best, accuracy, when)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,389 @@
+// Package a11y rewrites lesson output for screen readers (see Topic 190).
+// A runner puts a Writer between the lesson and the terminal, so no lesson
+// has to change:
+//
+//	out := io.Writer(os.Stdout)
+//	if on, _ := a11y.Enabled(*accessible); on {
+//		w := a11y.NewWriter(os.Stdout)
+//		defer w.Flush()
+//		out = w
+//	}
+//	cmd.Stdout = out
+//
+// What a sighted reader skims, a screen reader reads out character by
+// character: "box drawings double horizontal" sixty times for a banner.
+// The Writer drops rules and banners, turns tables into "a; b; c", bars
+// and sparklines into numbers, marks like ✓ and ⚠ into words, emoji it
+// doesn't know into nothing, and bulleted lists into numbered ones, so
+// the reader hears "item 2" and knows where they are.
+package a11y
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Enabled reports whether output should be accessible. The first of these
+// that is set decides:
+//
+//	the runner's -accessible flag      (flag is true)
+//	$GOTUT_ACCESSIBLE                  "1", "true", "0", "false", ...
+//	"accessible" in config.json        in $GOTUT_CONFIG_DIR, else <config dir>/gotut
+//
+// The config file is shared with the other gotut tools, so a missing one
+// is not an error; one that doesn't parse is, along with the false.
+func Enabled(flag bool) (bool, error) {
+	if flag {
+		return true, nil
+	}
+	if v := os.Getenv("GOTUT_ACCESSIBLE"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("GOTUT_ACCESSIBLE=%q: want true or false", v)
+		}
+		return on, nil
+	}
+	dir := os.Getenv("GOTUT_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return false, nil // No home, so no config: the default
+		}
+		dir = filepath.Join(base, "gotut")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var cfg struct {
+		Accessible bool `json:"accessible"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return false, fmt.Errorf("%s: %w", filepath.Join(dir, "config.json"), err)
+	}
+	return cfg.Accessible, nil
+}
+
+// ---------------------------------------------------------
+// The Writer: line at a time, with list state
+// ---------------------------------------------------------
+
+// Writer rewrites complete lines as they arrive; Flush writes a last line
+// with no newline. Numbering carries from line to line, so one Writer
+// must see the whole stream in order.
+type Writer struct {
+	w       io.Writer
+	partial []byte // A line that hasn't seen its '\n' yet
+	lists   []list // Open lists, outermost first; nil outside a list
+	blank   bool   // The last line written was blank
+}
+
+// list is an open numbered list: its indentation and its last number.
+type list struct {
+	indent string
+	n      int
+}
+
+// NewWriter returns a Writer that rewrites into w.
+func NewWriter(w io.Writer) *Writer { return &Writer{w: w} }
+
+// Write rewrites every complete line in p. It reports len(p) on success,
+// as io.Writer requires, though what reaches w is a different length.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.partial[:i])
+		w.partial = w.partial[i+1:]
+		if err := w.line(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush rewrites and writes what is left of an unterminated last line.
+func (w *Writer) Flush() error {
+	if len(w.partial) == 0 {
+		return nil
+	}
+	line := string(w.partial)
+	w.partial = nil
+	return w.line(line)
+}
+
+func (w *Writer) line(s string) error {
+	out, ok := w.rewrite(strings.TrimRight(s, "\r"))
+	if !ok {
+		return nil
+	}
+	if out == "" {
+		if w.blank {
+			return nil // Runs of blank lines are one pause
+		}
+		w.blank = true
+	} else {
+		w.blank = false
+	}
+	_, err := io.WriteString(w.w, out+"\n")
+	return err
+}
+
+// rewrite returns the accessible form of one line, and false to drop it.
+func (w *Writer) rewrite(s string) (string, bool) {
+	s = ansi.ReplaceAllString(s, "")
+	if isRule(s) {
+		return "", false
+	}
+	if m := exampleMarker.FindStringSubmatch(s); m != nil {
+		w.lists = nil
+		return "Section: " + strings.TrimSpace(m[1]), true
+	}
+	indent, text := splitIndent(s)
+	if text == "" {
+		w.lists = nil
+		return "", true
+	}
+
+	// A bullet is the next number of the list at its indentation. A
+	// deeper one opens a nested list; a shallower one closes the nested
+	// lists and carries on counting the outer one.
+	if rest, ok := cutBullet(text); ok {
+		for len(w.lists) > 0 && len(w.lists[len(w.lists)-1].indent) > len(indent) {
+			w.lists = w.lists[:len(w.lists)-1]
+		}
+		if len(w.lists) == 0 || w.lists[len(w.lists)-1].indent != indent {
+			w.lists = append(w.lists, list{indent: indent})
+		}
+		top := &w.lists[len(w.lists)-1]
+		top.n++
+		return indent + strconv.Itoa(top.n) + ". " + Line(rest), true
+	}
+	w.lists = nil
+	return indent + Line(text), true
+}
+
+// ---------------------------------------------------------
+// One line of text
+// ---------------------------------------------------------
+
+var (
+	ansi          = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	exampleMarker = regexp.MustCompile(`^\s*(?:---|===)\s*(.+?)\s*(?:---|===)\s*$`)
+	spaces        = regexp.MustCompile(`(\S)\s{2,}`)
+)
+
+// marks are the symbols the course prints, with what they mean.
+var marks = map[rune]string{
+	'✓': "pass", '✔': "pass", '✅': "done", '✗': "fail", '✘': "fail", '❌': "fail",
+	'⚠': "warning", '📌': "note", '💡': "tip", 'ℹ': "info", '🔒': "locked",
+	'→': "to", '⇒': "gives", '←': "from", '↑': "up", '↓': "down", '▶': "to", '◀': "from",
+	'▼': "then", '…': "...", '≈': "about", '≤': "at most", '≥': "at least", '≠': "is not", '×': "times",
+}
+
+// labels are the marks that, opening a line, label it: "✓ built" is
+// "Pass: built". Anywhere else they are words like the rest.
+const labels = "✓✔✅✗✘❌⚠📌💡ℹ"
+
+// Line rewrites one line of text with no list context: marks become words,
+// table borders become "; ", bars and sparklines become numbers, and
+// emoji with no entry in the table are dropped.
+func Line(s string) string {
+	var b strings.Builder
+	label := true // Still at the start, where a mark is a label
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\uFE0F' || r == '\u200D': // Emoji presentation, joiner
+		case marks[r] != "":
+			word := marks[r]
+			if label && strings.ContainsRune(labels, r) {
+				word = strings.ToUpper(word[:1]) + word[1:] + ":"
+			}
+			space(&b)
+			b.WriteString(word)
+			label = false
+			spaceAfter(&b, s[i+size:])
+		case isBar(r):
+			j := i
+			for j < len(s) {
+				r2, n := utf8.DecodeRuneInString(s[j:])
+				if !isBar(r2) {
+					break
+				}
+				j += n
+			}
+			space(&b)
+			b.WriteString(describeBar(s[i:j]))
+			size = j - i
+			spaceAfter(&b, s[j:])
+		case isBoxLine(r):
+			space(&b)
+			b.WriteString("; ")
+			for i+size < len(s) { // One separator for "──┼──" and friends
+				r2, n := utf8.DecodeRuneInString(s[i+size:])
+				if !isBoxLine(r2) && r2 != ' ' {
+					break
+				}
+				size += n
+			}
+		case isEmoji(r):
+		default:
+			b.WriteRune(r)
+			if !unicode.IsSpace(r) {
+				label = false
+			}
+		}
+		i += size
+	}
+	// Column padding is silence to a screen reader: keep one space.
+	out := spaces.ReplaceAllString(strings.Trim(b.String(), " ;"), "$1 ")
+	out = strings.ReplaceAll(out, " ;", ";")
+	return strings.ReplaceAll(out, ";;", ";")
+}
+
+// space separates a word from what came before, unless that was a space.
+func space(b *strings.Builder) {
+	if s := b.String(); s != "" && !strings.HasSuffix(s, " ") {
+		b.WriteByte(' ')
+	}
+}
+
+// spaceAfter separates a word from what follows: "a→b" is "a to b".
+func spaceAfter(b *strings.Builder, rest string) {
+	if r, _ := utf8.DecodeRuneInString(rest); rest != "" && !unicode.IsSpace(r) {
+		b.WriteByte(' ')
+	}
+}
+
+// ---------------------------------------------------------
+// Classifying characters
+// ---------------------------------------------------------
+
+// isRule reports whether a line is decoration only: a banner, an
+// underline, a table border. Three or more such characters, nothing else.
+func isRule(s string) bool {
+	n := 0
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case strings.ContainsRune("=-_~*#", r), isBoxLine(r):
+			n++
+		case r == ' ':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
+// isBoxLine is U+2500–U+257F, box drawing: ─ │ ┌ ┼ ═ ║ ╔ and the rest.
+func isBoxLine(r rune) bool { return r >= 0x2500 && r <= 0x257F }
+
+// vertical bars (sparklines) are ▁ to █ by eighths; horizontal bars are
+// █ then ▉ down to ▏; shades are ░ ▒ ▓.
+const (
+	vertical   = "▁▂▃▄▅▆▇█"
+	horizontal = "▏▎▍▌▋▊▉█"
+	shades     = "░▒▓"
+)
+
+func isBar(r rune) bool {
+	return strings.ContainsRune(vertical+horizontal+shades, r) || r == '■' || r == '□'
+}
+
+// eighths returns r's height (vertical) or length (horizontal) in
+// eighths of a cell, 1 to 8. r must be in set.
+func eighths(set string, r rune) int {
+	return strings.IndexRune(set, r)/utf8.RuneLen(r) + 1
+}
+
+// describeBar says what a run of block characters shows. A run that uses
+// several heights is a sparkline, read as its levels (1–8); otherwise
+// it's a bar, read as its length in cells, partial blocks as eighths.
+func describeBar(run string) string {
+	rs := []rune(run)
+	sparkline := false
+	for _, r := range rs {
+		if r != '█' && strings.ContainsRune(vertical, r) {
+			sparkline = len(rs) > 1
+		}
+	}
+	if sparkline {
+		levels := make([]string, len(rs))
+		for i, r := range rs {
+			levels[i] = strconv.Itoa(eighths(vertical, r))
+		}
+		return "(sparkline " + strings.Join(levels, " ") + ")"
+	}
+	var cells, shaded float64
+	for _, r := range rs {
+		switch {
+		case strings.ContainsRune(horizontal, r):
+			cells += float64(eighths(horizontal, r)) / 8
+		case strings.ContainsRune(vertical, r):
+			cells += float64(eighths(vertical, r)) / 8
+		default: // Shades and squares: a cell each
+			shaded++
+		}
+	}
+	switch {
+	case shaded > 0 && cells > 0:
+		return fmt.Sprintf("(bar %s, then %s shaded)", strconv.FormatFloat(cells, 'f', -1, 64), strconv.FormatFloat(shaded, 'f', -1, 64))
+	case shaded > 0:
+		return fmt.Sprintf("(%s shaded)", strconv.FormatFloat(shaded, 'f', -1, 64))
+	}
+	return fmt.Sprintf("(bar %s)", strconv.FormatFloat(cells, 'f', -1, 64))
+}
+
+// isEmoji covers the pictographic blocks. Marks with a meaning were
+// handled first; what's left here is decoration.
+func isEmoji(r rune) bool {
+	return r >= 0x1F300 && r <= 0x1FAFF || r >= 0x2600 && r <= 0x27BF || r >= 0x1F000 && r <= 0x1F2FF
+}
+
+// splitIndent returns a line's leading spaces and the rest. Tree
+// branches ("│   ├── ") count as indentation: depth is what they mean.
+// A "│" with no branch after it is a table's border, and part of the text.
+func splitIndent(s string) (indent, text string) {
+	tree := strings.ContainsAny(s, "├└")
+	width := 0
+	for i, r := range s {
+		switch {
+		case r == ' ':
+			width++
+		case r == '\t':
+			width += 4
+		case tree && (r == '├' || r == '└' || r == '│' || r == '─'):
+			width++
+		default:
+			return strings.Repeat(" ", width), s[i:]
+		}
+	}
+	return "", ""
+}
+
+// cutBullet reports whether text is a bulleted list item, and its text.
+func cutBullet(text string) (string, bool) {
+	for _, b := range []string{"• ", "- ", "* ", "▸ ", "‣ ", "◦ ", "· "} {
+		if rest, ok := strings.CutPrefix(text, b); ok && strings.TrimSpace(rest) != "" {
+			return rest, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,121 @@
+package a11y
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLine(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"✓ built in 2s", "Pass: built in 2s"},
+		{"✗ exit status 1", "Fail: exit status 1"},
+		{"⚠️ named .jpg but content is unknown", "Warning: named .jpg but content is unknown"},
+		{"📌 Split functions see raw bytes", "Note: Split functions see raw bytes"},
+		{"index.html      Ext \".html\" → text/html", "index.html Ext \".html\" to text/html"},
+		{"pt-BR→pt→en", "pt-BR to pt to en"},
+		{"it passed ✓", "it passed pass"},
+		{"1  Basic Timer   2s  ████▌  25%", "1 Basic Timer 2s (bar 4.5) 25%"},
+		{"latency ▁▂▅▇█▃", "latency (sparkline 1 2 5 7 8 3)"},
+		{"disk ███░░ 60%", "disk (bar 3, then 2 shaded) 60%"},
+		{"│ name │ size │", "name; size"},
+		{"a ──┼── b", "a; b"},
+		{"Done 🎉🚀", "Done"},
+		{"plain text, nothing to do", "plain text, nothing to do"},
+	} {
+		if got := Line(tc.in); got != tc.want {
+			t.Errorf("Line(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+const lesson = "═══════════════════════════════════════\n" +
+	"TOPIC: \x1b[1mSPLIT FUNCTIONS\x1b[0m\n" +
+	"═══════════════════════════════════════\n" +
+	"\n" +
+	"--- Example 1: Lines ---\n" +
+	"  • CRLF\n" +
+	"  • NUL\n" +
+	"      - nested\n" +
+	"  • fixed width\n" +
+	"\n" +
+	"\n" +
+	"  ┌──────┬──────┐\n" +
+	"  │ a    │ 1    │\n" +
+	"  └──────┴──────┘\n" +
+	"  ✓ done"
+
+const want = "TOPIC: SPLIT FUNCTIONS\n" +
+	"\n" +
+	"Section: Example 1: Lines\n" +
+	"  1. CRLF\n" +
+	"  2. NUL\n" +
+	"      1. nested\n" +
+	"  3. fixed width\n" +
+	"\n" +
+	"  a; 1\n" +
+	"  Pass: done\n"
+
+// TestWriter feeds a lesson's output one byte at a time: a line split
+// across writes must come out as if it had arrived whole.
+func TestWriter(t *testing.T) {
+	var out strings.Builder
+	w := NewWriter(&out)
+	r := iotest.OneByteReader(strings.NewReader(lesson))
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOTUT_CONFIG_DIR", dir)
+	t.Setenv("GOTUT_ACCESSIBLE", "")
+	config := filepath.Join(dir, "config.json")
+
+	for _, tc := range []struct {
+		flag    bool
+		env     string
+		config  string
+		want    bool
+		wantErr bool
+	}{
+		{false, "", "", false, false}, // No config file at all
+		{true, "", "", true, false},
+		{false, "1", "", true, false},
+		{false, "", `{"accessible": true}`, true, false},
+		{false, "false", `{"accessible": true}`, false, false}, // The env beats the file
+		{true, "false", "", true, false},                       // The flag beats both
+		{false, "yes please", "", false, true},
+		{false, "", `{"accessible": `, false, true},
+	} {
+		t.Setenv("GOTUT_ACCESSIBLE", tc.env)
+		os.Remove(config)
+		if tc.config != "" {
+			if err := os.WriteFile(config, []byte(tc.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := Enabled(tc.flag)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("flag=%v env=%q config=%q: %v, %v; want %v, error %v", tc.flag, tc.env, tc.config, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
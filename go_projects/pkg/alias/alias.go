@@ -0,0 +1,188 @@
+// Package alias is gotut's command shortcuts, kept under "aliases" in the
+// config.json the gotut tools share (190's "accessible" lives there too):
+//
+//	path, _ := alias.Path()
+//	set, _ := alias.Load(path)               // {"re": "run 73", ...}
+//	args, err := set.Expand(os.Args[1:])     // re -timeout 5s → run 73 -timeout 5s
+//
+// An expansion is a command and its first arguments, split at spaces;
+// there is no quoting. Its first word may be another alias, so Expand
+// follows the chain, and an alias that leads back to itself is ErrCycle
+// rather than a hang. Which names are taken by real commands is the
+// caller's to check: this package doesn't know them.
+package alias
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Set maps an alias's name to its expansion, as written: "run 73".
+type Set map[string]string
+
+var (
+	// ErrInvalid is wrapped by Validate's errors, and Load's for a
+	// config.json with a bad entry.
+	ErrInvalid = errors.New("invalid alias")
+
+	// ErrCycle is wrapped by Expand's error for an alias that expands,
+	// directly or through others, back to itself.
+	ErrCycle = errors.New("alias cycle")
+)
+
+// nameRx is what a name looks like: a word, so it can't be mistaken for a
+// flag, a topic number or a path.
+var nameRx = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Path is config.json in $GOTUT_CONFIG_DIR, else <config dir>/gotut.
+func Path() (string, error) {
+	dir := os.Getenv("GOTUT_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, "gotut")
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Validate checks one definition: a name, and an expansion that starts
+// with a command. Global flags like -dir go before the alias when it is
+// used, not inside it.
+func Validate(name, expansion string) error {
+	if !nameRx.MatchString(name) {
+		return fmt.Errorf("%w name %q: want a letter, then letters, digits, _ or -", ErrInvalid, name)
+	}
+	words := strings.Fields(expansion)
+	switch {
+	case len(words) == 0:
+		return fmt.Errorf("%w %s: nothing to expand to", ErrInvalid, name)
+	case strings.HasPrefix(words[0], "-"):
+		return fmt.Errorf("%w %s: %q is a flag; the expansion starts with a command", ErrInvalid, name, words[0])
+	}
+	return nil
+}
+
+// Expand replaces an alias at the start of args with its expansion, again
+// while the first word is an alias, and keeps the rest of args after it.
+// args that don't start with an alias come back as they are.
+func (s Set) Expand(args []string) ([]string, error) {
+	var seen []string
+	for len(args) > 0 {
+		expansion, ok := s[args[0]]
+		if !ok {
+			break
+		}
+		if slices.Contains(seen, args[0]) {
+			return nil, fmt.Errorf("%w: %s", ErrCycle, strings.Join(append(seen, args[0]), " → "))
+		}
+		seen = append(seen, args[0])
+		args = append(strings.Fields(expansion), args[1:]...)
+	}
+	return args, nil
+}
+
+// Names returns the names, sorted.
+func (s Set) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Load reads the aliases from the config file at path. No file, or no
+// "aliases" in it, is an empty Set; a file that doesn't parse, or an
+// entry Validate rejects, is an error naming the file.
+func Load(path string) (Set, error) {
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	s := Set{}
+	if raw, ok := cfg["aliases"]; ok {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("%s: aliases: %v", path, err)
+		}
+	}
+	for _, name := range s.Names() {
+		if err := Validate(name, s[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+// Save writes s as the config file's "aliases", keeping every other key
+// as it was; an empty s removes the key. Each entry is validated first,
+// so nothing Load would reject is written. The file is replaced by rename
+// (see 168): a crash leaves the old config or the new one.
+func Save(path string, s Set) (err error) {
+	for _, name := range s.Names() {
+		if err := Validate(name, s[name]); err != nil {
+			return err
+		}
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+	if len(s) == 0 {
+		delete(cfg, "aliases")
+	} else if cfg["aliases"], err = json.Marshal(s); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "config.json.tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readConfig reads the whole config file, each key's value left as JSON
+// for the package that owns it.
+func readConfig(path string) (map[string]json.RawMessage, error) {
+	cfg := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,148 @@
+package alias
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	s := Set{
+		"re":    "run 73",
+		"quick": "re -timeout 5s",
+		"mylog": "tool logstats --format json",
+		"ping":  "pong",
+		"pong":  "ping",
+		"self":  "self -v",
+		"a":     "b",
+		"b":     "c",
+		"c":     "a x",
+	}
+	tests := []struct {
+		args  string
+		want  string
+		cycle string
+	}{
+		{"re", "run 73", ""},
+		{"re -timeout 1s", "run 73 -timeout 1s", ""},
+		{"quick", "run 73 -timeout 5s", ""},
+		{"mylog app.log", "tool logstats --format json app.log", ""},
+		{"run re", "run re", ""}, // Only the first word
+		{"verify 1 2", "verify 1 2", ""},
+		{"", "", ""},
+		{"ping", "", "ping → pong → ping"},
+		{"self", "", "self → self"},
+		{"a 1", "", "a → b → c → a"},
+	}
+	for _, tt := range tests {
+		got, err := s.Expand(strings.Fields(tt.args))
+		if tt.cycle != "" {
+			if !errors.Is(err, ErrCycle) || !strings.Contains(err.Error(), tt.cycle) {
+				t.Errorf("Expand(%q) = %q, %v; want ErrCycle %s", tt.args, got, err, tt.cycle)
+			}
+			continue
+		}
+		if err != nil || strings.Join(got, " ") != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct{ name, expansion string }{
+		{"re", "run 73"},
+		{"my-log", "tool logstats --format json"},
+		{"T2_x", "help topics"},
+	} {
+		if err := Validate(tc.name, tc.expansion); err != nil {
+			t.Errorf("Validate(%q, %q) = %v", tc.name, tc.expansion, err)
+		}
+	}
+	for _, tc := range []struct{ name, expansion string }{
+		{"", "run 73"},
+		{"-v", "verify"},
+		{"73", "run 73"},
+		{"two words", "run 73"},
+		{"a/b", "run 73"},
+		{"re", ""},
+		{"re", "   "},
+		{"re", "-dir .. run 73"},
+	} {
+		if err := Validate(tc.name, tc.expansion); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate(%q, %q) = %v, want ErrInvalid", tc.name, tc.expansion, err)
+		}
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gotut", "config.json")
+	s, err := Load(path)
+	if err != nil || len(s) != 0 {
+		t.Fatalf("Load of a missing file = %v, %v; want an empty set", s, err)
+	}
+	if err := Save(path, Set{"re": "run 73", "check": "tmpl-check templates"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err = Load(path)
+	if err != nil || s["re"] != "run 73" || !slices.Equal(s.Names(), []string{"check", "re"}) {
+		t.Fatalf("Load = %v, %v", s, err)
+	}
+
+	// Other tools' keys survive a save, and an empty set removes the key.
+	os.WriteFile(path, []byte(`{"accessible": true, "aliases": {"re": "run 73"}}`), 0o644)
+	if err := Save(path, Set{}); err != nil {
+		t.Fatal(err)
+	}
+	var cfg map[string]any
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &cfg); err != nil || cfg["accessible"] != true || cfg["aliases"] != nil {
+		t.Errorf("after saving no aliases: %s", data)
+	}
+
+	if err := Save(path, Set{"re": "-dir .. run 73"}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Save of an invalid alias = %v, want ErrInvalid", err)
+	}
+	if s, _ := Load(path); len(s) != 0 {
+		t.Errorf("a rejected Save wrote %v", s)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, tc := range []struct {
+		config  string
+		invalid bool
+		broken  bool // The whole file doesn't parse
+	}{
+		{`{"aliases": `, false, true},
+		{`{"aliases": ["run 73"]}`, false, false},
+		{`{"aliases": {"re": ""}}`, true, false},
+		{`{"aliases": {"7": "run 7"}}`, true, false},
+	} {
+		os.WriteFile(path, []byte(tc.config), 0o644)
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), path) || errors.Is(err, ErrInvalid) != tc.invalid {
+			t.Errorf("Load of %s = %v", tc.config, err)
+		}
+		// Saving replaces a bad "aliases", but not a file it can't read the
+		// other keys from.
+		if err := Save(path, Set{"re": "run 73"}); (err != nil) != tc.broken {
+			t.Errorf("Save over %s = %v", tc.config, err)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv("GOTUT_CONFIG_DIR", "/tmp/gotut-x")
+	if p, err := Path(); err != nil || p != filepath.Join("/tmp/gotut-x", "config.json") {
+		t.Errorf("Path() = %q, %v", p, err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package atomicfile replaces a file so that a reader, or the file system
+// after a crash, sees the old contents or the new ones — never half of
+// the new (Topics 163 and 168):
+//
+//	err := atomicfile.Write("notes.json", 0o600, func(w io.Writer) error {
+//		return json.NewEncoder(w).Encode(nb)
+//	})
+//
+// The steps are the ones the course repeats wherever a file must not be
+// torn: write a temporary file in the destination's own directory (a
+// rename is only atomic within one file system, and os.TempDir is often
+// another one), set its mode, Sync it so its data is on disk before its
+// name is, close it, and rename it over the destination. On any error the
+// temporary file is removed and the destination is left as it was.
+package atomicfile
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Write replaces path with what write writes, atomically, with mode perm.
+// The directory must exist.
+func Write(path string, perm fs.FileMode, write func(io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()           // Already failed; the first error is the one to report
+			os.Remove(tmp.Name()) // Never leave half a file lying around
+		}
+	}()
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// WriteFile is os.WriteFile, atomically.
+func WriteFile(path string, data []byte, perm fs.FileMode) error {
+	return Write(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,192 @@
+// Package batch works through a list of items one at a time and records
+// how each went in a checkpoint file, so a run that is stopped — Ctrl-C,
+// a deploy, a crash — starts again where it left off instead of from the
+// top (Topic 197, after intermediate Topic 88's batch workspace):
+//
+//	p := batch.Processor{
+//		Checkpoint: "thumbnails.checkpoint.json",
+//		Process:    func(ctx context.Context, name string) error { return thumbnail(ctx, name) },
+//	}
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	sum, err := p.Run(ctx, files) // Run it again after Ctrl-C: done items are skipped
+//
+// After every item the whole checkpoint is rewritten atomically, with
+// pkg/atomicfile — a temporary file, Sync, then a rename over the old
+// one — so on disk it is always the state after some item, never
+// half of one. An item that ctx interrupts stays pending and runs again
+// next time. Process must be safe to repeat for the same reason a crash
+// can repeat it: between an item finishing and the checkpoint saying so.
+//
+// One Run per checkpoint file at a time; two would overwrite each
+// other's progress.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/atomicfile"
+)
+
+// Status is where an item is.
+type Status string
+
+const (
+	Pending Status = "pending" // Not run yet, or interrupted
+	Done    Status = "done"
+	Failed  Status = "failed" // Process returned an error; Error says which
+)
+
+// Item is one entry of the checkpoint.
+type Item struct {
+	Name     string    `json:"name"`
+	Status   Status    `json:"status"`
+	Attempts int       `json:"attempts,omitempty"` // Calls to Process, over every run
+	Error    string    `json:"error,omitempty"`
+	Updated  time.Time `json:"updated,omitzero"`
+}
+
+// Checkpoint is the file: every item of the batch, in order.
+type Checkpoint struct {
+	Version int    `json:"version"`
+	Items   []Item `json:"items"`
+}
+
+// Load reads the checkpoint at path. No file is an empty checkpoint: a
+// batch that hasn't started.
+func Load(path string) (*Checkpoint, error) {
+	c := &Checkpoint{Version: 1}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %v", path, err)
+	}
+	return c, nil
+}
+
+// Count is how many items have status s.
+func (c *Checkpoint) Count(s Status) int {
+	n := 0
+	for _, it := range c.Items {
+		if it.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// save writes c to path atomically.
+func (c *Checkpoint) save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Processor runs a batch.
+type Processor struct {
+	Checkpoint string // The checkpoint file; its directory must exist
+	Process    func(ctx context.Context, name string) error
+	// RetryFailed runs items that failed in an earlier run again; without
+	// it they are skipped, and stay failed, until someone looks at them.
+	RetryFailed bool
+	// OnItem, if set, is called with each item Process ran, once its new
+	// status is in the checkpoint.
+	OnItem func(Item)
+	// Now is the clock for Item.Updated; nil means time.Now.
+	Now func() time.Time
+}
+
+// Summary is how a Run went. Done, Failed and Pending count every item
+// of the batch after the run; Ran and Skipped only this run's share.
+type Summary struct {
+	Done, Failed, Pending int
+	Ran                   int // Items Process was called for
+	Skipped               int // Items done, or failed, in an earlier run
+}
+
+// Run processes names in order, skipping those the checkpoint already
+// has as done, and saves the checkpoint after each one. The checkpoint
+// follows names: new names are added as pending, and items for names no
+// longer listed are dropped.
+//
+// An item's failure is recorded and the run goes on; it shows in the
+// Summary and the checkpoint, not in Run's error. Run's error is ctx's,
+// when it stops the run, or a checkpoint that can't be read or written,
+// which stops it too: progress that can't be saved would be lost.
+func (p *Processor) Run(ctx context.Context, names []string) (Summary, error) {
+	var sum Summary
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	cp, err := Load(p.Checkpoint)
+	if err != nil {
+		return sum, err
+	}
+	known := make(map[string]Item, len(cp.Items))
+	for _, it := range cp.Items {
+		known[it.Name] = it
+	}
+	cp.Items = cp.Items[:0]
+	for _, name := range names {
+		it, ok := known[name]
+		if !ok {
+			it = Item{Name: name, Status: Pending}
+		}
+		cp.Items = append(cp.Items, it)
+	}
+	if err := cp.save(p.Checkpoint); err != nil {
+		return sum, err
+	}
+
+	for i := range cp.Items {
+		it := &cp.Items[i]
+		if it.Status == Done || it.Status == Failed && !p.RetryFailed {
+			sum.Skipped++
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return sum.count(cp), err
+		}
+		it.Attempts++
+		sum.Ran++
+		err := p.Process(ctx, it.Name)
+		if err != nil && ctx.Err() != nil {
+			// Interrupted, not failed: pending, to run again next time.
+			it.Status, it.Error, it.Updated = Pending, "", now()
+			if err := cp.save(p.Checkpoint); err != nil {
+				return sum.count(cp), err
+			}
+			return sum.count(cp), ctx.Err()
+		}
+		it.Status, it.Error, it.Updated = Done, "", now()
+		if err != nil {
+			it.Status, it.Error = Failed, err.Error()
+		}
+		if err := cp.save(p.Checkpoint); err != nil {
+			return sum.count(cp), err
+		}
+		if p.OnItem != nil {
+			p.OnItem(*it)
+		}
+	}
+	return sum.count(cp), nil
+}
+
+func (s Summary) count(cp *Checkpoint) Summary {
+	s.Done, s.Failed, s.Pending = cp.Count(Done), cp.Count(Failed), cp.Count(Pending)
+	return s
+}
@@ -0,0 +1,140 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// statuses is the checkpoint at path as "name:status" strings.
+func statuses(t *testing.T, path string) []string {
+	t.Helper()
+	cp, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, it := range cp.Items {
+		out = append(out, it.Name+":"+string(it.Status))
+	}
+	return out
+}
+
+func TestInterruptAndResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.json")
+	names := []string{"a", "b", "c", "d", "e"}
+	var ran []string
+	ctx, cancel := context.WithCancel(context.Background())
+	p := Processor{Checkpoint: path, Process: func(ctx context.Context, name string) error {
+		ran = append(ran, name)
+		if name == "c" {
+			cancel() // Ctrl-C while c is running
+			return ctx.Err()
+		}
+		return nil
+	}}
+
+	sum, err := p.Run(ctx, names)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted Run = %v, want context.Canceled", err)
+	}
+	want := []string{"a:done", "b:done", "c:pending", "d:pending", "e:pending"}
+	if got := statuses(t, path); !slices.Equal(got, want) {
+		t.Errorf("checkpoint after the interrupt: %v, want %v", got, want)
+	}
+	if sum != (Summary{Done: 2, Pending: 3, Ran: 3}) {
+		t.Errorf("Summary = %+v", sum)
+	}
+
+	ran = nil
+	sum, err = p.Run(context.Background(), names)
+	if err != nil || !slices.Equal(ran, []string{"c", "d", "e"}) {
+		t.Errorf("resumed Run = %v, ran %v; want c, d, e", err, ran)
+	}
+	if sum != (Summary{Done: 5, Ran: 3, Skipped: 2}) {
+		t.Errorf("Summary = %+v", sum)
+	}
+	cp, _ := Load(path)
+	if cp.Items[2].Attempts != 2 || cp.Items[3].Attempts != 1 || cp.Items[2].Updated.IsZero() {
+		t.Errorf("c: %+v, d: %+v; want 2 attempts and 1", cp.Items[2], cp.Items[3])
+	}
+}
+
+func TestFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.json")
+	broken := map[string]bool{"b": true}
+	var seen []Item
+	p := Processor{
+		Checkpoint: path,
+		Process: func(_ context.Context, name string) error {
+			if broken[name] {
+				return errors.New(name + " is corrupt")
+			}
+			return nil
+		},
+		OnItem: func(it Item) { seen = append(seen, it) },
+	}
+	sum, err := p.Run(context.Background(), []string{"a", "b", "c"})
+	if err != nil || sum != (Summary{Done: 2, Failed: 1, Ran: 3}) {
+		t.Errorf("Run = %+v, %v; a failed item is not Run's error", sum, err)
+	}
+	if len(seen) != 3 || seen[1].Status != Failed || seen[1].Error != "b is corrupt" {
+		t.Errorf("OnItem saw %+v", seen)
+	}
+
+	// Failed items are skipped until asked for, then run again.
+	broken["b"] = false
+	if sum, _ := p.Run(context.Background(), []string{"a", "b", "c"}); sum.Ran != 0 || sum.Failed != 1 {
+		t.Errorf("without RetryFailed: %+v", sum)
+	}
+	p.RetryFailed = true
+	if sum, _ := p.Run(context.Background(), []string{"a", "b", "c"}); sum.Ran != 1 || sum.Done != 3 {
+		t.Errorf("with RetryFailed: %+v", sum)
+	}
+	cp, _ := Load(path)
+	if it := cp.Items[1]; it.Status != Done || it.Error != "" || it.Attempts != 2 {
+		t.Errorf("b after the retry: %+v", it)
+	}
+}
+
+// The checkpoint follows the list: new names are added, dropped ones forgotten.
+func TestListChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.json")
+	p := Processor{Checkpoint: path, Process: func(context.Context, string) error { return nil }}
+	p.Run(context.Background(), []string{"a", "b"})
+	var ran []string
+	p.Process = func(_ context.Context, name string) error { ran = append(ran, name); return nil }
+	p.Run(context.Background(), []string{"b", "c"})
+	if got := statuses(t, path); !slices.Equal(got, []string{"b:done", "c:done"}) || !slices.Equal(ran, []string{"c"}) {
+		t.Errorf("checkpoint %v, ran %v", got, ran)
+	}
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	dir := t.TempDir()
+	noop := func(context.Context, string) error { return nil }
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	os.WriteFile(corrupt, []byte("{not json"), 0o644)
+	p := Processor{Checkpoint: corrupt, Process: noop}
+	if _, err := p.Run(context.Background(), []string{"a"}); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("corrupt checkpoint: %v", err)
+	}
+
+	p = Processor{Checkpoint: filepath.Join(dir, "missing", "batch.json"), Process: noop}
+	if sum, err := p.Run(context.Background(), []string{"a"}); !errors.Is(err, os.ErrNotExist) || sum.Ran != 0 {
+		t.Errorf("unwritable checkpoint: %+v, %v; want nothing run", sum, err)
+	}
+
+	// Only the checkpoint itself is left behind, never a temporary file.
+	p = Processor{Checkpoint: filepath.Join(dir, "ok.json"), Process: noop}
+	p.Run(context.Background(), []string{"a", "b"})
+	matches, _ := filepath.Glob(filepath.Join(dir, "ok.json*"))
+	if len(matches) != 1 {
+		t.Errorf("files: %v", matches)
+	}
+}
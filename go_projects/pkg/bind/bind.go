@@ -0,0 +1,239 @@
+// Package bind decodes a request into a struct and validates it, for a
+// JSON API's handlers (Topic 146):
+//
+//	var in CreateUser
+//	if err := bind.Bind(r, &in); err != nil {
+//		bind.WriteError(w, err) // 400, 415 or 422, with a list of fields
+//		return
+//	}
+//
+// The input is the query string for a GET, else a JSON or form body, by
+// Content-Type. Input that can't be read — malformed JSON, a field of the
+// wrong type, an unknown field — is 400; input that breaks a validate tag
+// is 422:
+//
+//	type CreateUser struct {
+//		Name  string `json:"name" validate:"required,min=2"`
+//		Age   int    `json:"age" validate:"min=13,max=120"`
+//		Email string `json:"email" validate:"required,email"`
+//	}
+//
+// Each field's problem is an errorx.ValidationError (intermediate Topic
+// 69), and every problem is reported, not only the first, so a client
+// can fix a whole form in one round trip.
+package bind
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/errorx"
+)
+
+// MaxBodyBytes is the most of a JSON body Bind reads.
+const MaxBodyBytes = 1 << 20
+
+// Error is what Bind returns, and WriteError's response body. Status is
+// the HTTP status to send.
+type Error struct {
+	Status int                      `json:"-"`
+	Msg    string                   `json:"error"`
+	Fields []errorx.ValidationError `json:"fields,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %d field error(s)", e.Msg, len(e.Fields))
+}
+
+// WriteError sends err as a JSON body with its status; an error that
+// isn't an *Error is a 400.
+func WriteError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Status: http.StatusBadRequest, Msg: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(e) // The status is sent; a failed write has no one to tell
+}
+
+// Bind decodes r into dst, a pointer to a struct, and validates it.
+func Bind(r *http.Request, dst any) error {
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case r.Method == http.MethodGet:
+		if err := decodeValues(r.URL.Query(), dst); err != nil {
+			return err
+		}
+	case strings.HasPrefix(ct, "application/json"):
+		if err := decodeJSON(r.Body, dst); err != nil {
+			return err
+		}
+	case strings.HasPrefix(ct, "application/x-www-form-urlencoded"):
+		if err := r.ParseForm(); err != nil {
+			return &Error{Status: http.StatusBadRequest, Msg: "malformed form body"}
+		}
+		if err := decodeValues(r.PostForm, dst); err != nil {
+			return err
+		}
+	default:
+		return &Error{Status: http.StatusUnsupportedMediaType, Msg: "unsupported content type " + strconv.Quote(ct)}
+	}
+	return Validate(dst)
+}
+
+// decodeJSON turns encoding/json's errors into messages about fields.
+func decodeJSON(body io.Reader, dst any) error {
+	dec := json.NewDecoder(io.LimitReader(body, MaxBodyBytes))
+	dec.DisallowUnknownFields() // Typos like "emial" become errors, not silent data loss
+
+	err := dec.Decode(dst)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+	case errors.As(err, &syntaxErr):
+		return &Error{Status: http.StatusBadRequest, Msg: fmt.Sprintf("malformed JSON at byte %d", syntaxErr.Offset)}
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return &Error{Status: http.StatusBadRequest, Msg: "request body is empty or truncated"}
+	case errors.As(err, &typeErr):
+		return &Error{Status: http.StatusBadRequest, Msg: "wrong type", Fields: []errorx.ValidationError{
+			errorx.NewValidationError(typeErr.Field, "must be of type "+typeErr.Type.String(), "JSON "+typeErr.Value),
+		}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &Error{Status: http.StatusBadRequest, Msg: "unknown field", Fields: []errorx.ValidationError{
+			errorx.NewValidationError(field, "is not allowed", ""),
+		}}
+	default:
+		return &Error{Status: http.StatusBadRequest, Msg: err.Error()}
+	}
+
+	if dec.More() {
+		return &Error{Status: http.StatusBadRequest, Msg: "body must contain a single JSON object"}
+	}
+	return nil
+}
+
+// decodeValues fills dst's fields from url.Values (Topic 79), by their
+// json names.
+func decodeValues(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+	var problems []errorx.ValidationError
+	for i := 0; i < t.NumField(); i++ {
+		name := fieldName(t.Field(i))
+		raw := values.Get(name)
+		if raw == "" {
+			continue
+		}
+		f := v.Field(i)
+		switch {
+		case f.Kind() == reflect.String:
+			f.SetString(raw)
+		case f.CanInt():
+			n, err := strconv.ParseInt(raw, 10, f.Type().Bits())
+			if err != nil {
+				problems = append(problems, errorx.NewValidationError(name, "must be an integer", raw))
+				continue
+			}
+			f.SetInt(n)
+		case f.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				problems = append(problems, errorx.NewValidationError(name, "must be true or false", raw))
+				continue
+			}
+			f.SetBool(b)
+		}
+	}
+	if len(problems) > 0 {
+		return &Error{Status: http.StatusBadRequest, Msg: "wrong type", Fields: problems}
+	}
+	return nil
+}
+
+func fieldName(f reflect.StructField) string {
+	if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" {
+		return tag
+	}
+	return f.Name
+}
+
+// Validate checks dst's validate tags: required, min=N and max=N (a
+// string's length, or an integer's value), and email. It returns every
+// field that fails, one problem each, as a 422 *Error.
+func Validate(dst any) error {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+	var problems []errorx.ValidationError
+
+	for i := 0; i < t.NumField(); i++ {
+		rules := t.Field(i).Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		name := fieldName(t.Field(i))
+		f := v.Field(i)
+		for _, rule := range strings.Split(rules, ",") {
+			if issue := checkRule(f, rule); issue != "" {
+				problems = append(problems, errorx.NewValidationError(name, issue, fmt.Sprint(f.Interface())))
+				break // One message per field is enough
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &Error{Status: http.StatusUnprocessableEntity, Msg: "validation failed", Fields: problems}
+	}
+	return nil
+}
+
+// checkRule returns what is wrong with f by rule, or "". A min or max
+// without a number is a bug in the struct, not in the request: it panics,
+// as regexp.MustCompile does.
+func checkRule(f reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	var n int
+	if name == "min" || name == "max" {
+		var err error
+		if n, err = strconv.Atoi(arg); err != nil {
+			panic(fmt.Sprintf("bind: validate rule %q: %v", rule, err))
+		}
+	}
+	switch name {
+	case "required":
+		if f.IsZero() {
+			return "is required"
+		}
+	case "min":
+		if f.Kind() == reflect.String && len(f.String()) < n {
+			return fmt.Sprintf("must be at least %d characters", n)
+		}
+		if f.CanInt() && f.Int() < int64(n) {
+			return fmt.Sprintf("must be at least %d", n)
+		}
+	case "max":
+		if f.Kind() == reflect.String && len(f.String()) > n {
+			return fmt.Sprintf("must be at most %d characters", n)
+		}
+		if f.CanInt() && f.Int() > int64(n) {
+			return fmt.Sprintf("must be at most %d", n)
+		}
+	case "email":
+		at := strings.LastIndex(f.String(), "@")
+		if at < 1 || !strings.Contains(f.String()[at:], ".") {
+			return "must be a valid email address"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package bind
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/errorx"
+)
+
+type createUser struct {
+	Name  string `json:"name" validate:"required,min=2"`
+	Age   int    `json:"age" validate:"min=13,max=120"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func TestBind(t *testing.T) {
+	for _, tt := range []struct {
+		name, method, ctype, body, query string
+		status                           int      // 0: bound
+		fields                           []string // field: issue
+	}{
+		{"valid JSON", "POST", "application/json", `{"name":"Ann","age":30,"email":"ann@example.com"}`, "", 0, nil},
+		{"malformed JSON", "POST", "application/json", `{"name":"Ann",}`, "", 400, nil},
+		{"empty body", "POST", "application/json", ``, "", 400, nil},
+		{"unknown field", "POST", "application/json", `{"name":"Ann","emial":"a@b.co"}`, "", 400, []string{"emial: is not allowed"}},
+		{"type mismatch", "POST", "application/json", `{"name":"Ann","age":"thirty"}`, "", 400, []string{"age: must be of type int"}},
+		{"two objects", "POST", "application/json", `{"name":"Ann"}{"name":"Bob"}`, "", 400, nil},
+		{"rule violations", "POST", "application/json", `{"name":"A","age":7,"email":"nope"}`, "", 422,
+			[]string{"name: must be at least 2 characters", "age: must be at least 13", "email: must be a valid email address"}},
+		{"form body", "POST", "application/x-www-form-urlencoded", "name=Bo&age=40&email=bo@example.com", "", 0, nil},
+		{"query string", "GET", "", "", "name=Cy&age=abc&email=cy@example.com", 400, []string{"age: must be an integer"}},
+		{"wrong content type", "POST", "text/plain", "hello", "", 415, nil},
+	} {
+		r := httptest.NewRequest(tt.method, "/users?"+tt.query, strings.NewReader(tt.body))
+		if tt.ctype != "" {
+			r.Header.Set("Content-Type", tt.ctype)
+		}
+		var in createUser
+		err := Bind(r, &in)
+		if tt.status == 0 {
+			if err != nil || in.Name == "" {
+				t.Errorf("%s: Bind = %v, %+v", tt.name, err, in)
+			}
+			continue
+		}
+		var e *Error
+		if !errors.As(err, &e) || e.Status != tt.status {
+			t.Errorf("%s: Bind = %v, want status %d", tt.name, err, tt.status)
+			continue
+		}
+		var fields []string
+		for _, f := range e.Fields {
+			fields = append(fields, f.Field+": "+f.Issue)
+		}
+		if strings.Join(fields, "; ") != strings.Join(tt.fields, "; ") {
+			t.Errorf("%s: fields %q, want %q", tt.name, fields, tt.fields)
+		}
+		if tt.status == 422 && !errors.Is(e.Fields[0], errorx.ErrValidation) {
+			t.Errorf("%s: %v isn't an errorx validation error", tt.name, e.Fields[0])
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, Validate(&createUser{Name: "Ann", Age: 30}))
+	var body struct {
+		Error  string `json:"error"`
+		Fields []struct{ Field, Issue, Value string }
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || body.Error != "validation failed" ||
+		len(body.Fields) != 1 || body.Fields[0].Field != "email" || body.Fields[0].Issue != "is required" {
+		t.Errorf("WriteError: %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	WriteError(rec, errors.New("something else"))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"error":"something else"`) {
+		t.Errorf("WriteError(plain error): %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestBadRule(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), `validate rule "min=two"`) {
+			t.Errorf("recovered %v", r)
+		}
+	}()
+	var in struct {
+		Name string `validate:"min=two"`
+	}
+	Validate(&in)
+}
@@ -0,0 +1,230 @@
+// Package blobstore keeps blobs of bytes under the SHA-256 of their
+// content, the storage under Topic 154's backups and 187's uploads:
+//
+//	store, err := blobstore.Open("backups/blobs")
+//	addr, err := store.Put(f)   // "3a1f…e9": the same bytes always get the same address
+//	rc, err := store.Get(addr)  // reading to the end checks the content still hashes to addr
+//
+// The address is the content's name, so storing a file twice stores it
+// once, and a blob can't change without its address stopping to match —
+// which Get notices. Blobs live two directory levels down, by the first
+// two pairs of hex digits,
+//
+//	blobs/3a/1f/3a1f…e9
+//
+// so no directory holds more than a few hundred entries even with
+// millions of blobs: listing a directory of a million files is slow on
+// most filesystems.
+//
+// Put writes to a temporary file and renames it into place, so a blob is
+// there whole or not at all, and any number of goroutines or processes
+// can Put at once. GC is the exception: it removes what isn't listed as
+// live, including a blob a concurrent Put has just added, so run it
+// while nothing is putting.
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+var (
+	ErrNotFound = errors.New("blob not found")
+	ErrCorrupt  = errors.New("blob content does not match its address")
+	ErrAddress  = errors.New("not a blob address") // Not 64 lower-case hex digits
+)
+
+// Store is a directory of blobs.
+type Store struct {
+	root string
+}
+
+// tmpDir is where Put writes before renaming; inside the store, so the
+// rename never crosses filesystems.
+const tmpDir = "tmp"
+
+// Open returns the store in root, creating the directory if needed.
+func Open(root string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(root, tmpDir), 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{root: root}, nil
+}
+
+// path is where the blob addr lives.
+func (s *Store) path(addr string) string {
+	return filepath.Join(s.root, addr[:2], addr[2:4], addr)
+}
+
+func checkAddress(addr string) error {
+	if len(addr) != sha256.Size*2 {
+		return fmt.Errorf("%w: %q", ErrAddress, addr)
+	}
+	for _, c := range addr {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return fmt.Errorf("%w: %q", ErrAddress, addr)
+		}
+	}
+	return nil
+}
+
+// Put stores everything r has and returns its address. Content the
+// store already has is read, hashed and dropped. On an error from r
+// nothing is stored, and the error is returned as it is, so callers can
+// still errors.As it (an *http.MaxBytesError, say).
+func (s *Store) Put(r io.Reader) (addr string, err error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.root, tmpDir), "put-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	addr = hex.EncodeToString(h.Sum(nil))
+	dst := s.path(addr)
+	if _, err := os.Stat(dst); err == nil {
+		os.Remove(tmp.Name()) // Already stored
+		return addr, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", err
+	}
+	return addr, nil
+}
+
+// Has reports whether the store holds addr. It doesn't read the blob.
+func (s *Store) Has(addr string) bool {
+	if checkAddress(addr) != nil {
+		return false
+	}
+	_, err := os.Stat(s.path(addr))
+	return err == nil
+}
+
+// Get opens the blob addr. The content is hashed as it is read, and the
+// Read that reaches the end returns ErrCorrupt instead of io.EOF if it
+// doesn't hash to addr: a caller that copies to the end has checked it.
+// A caller that stops early hasn't.
+func (s *Store) Get(addr string) (io.ReadCloser, error) {
+	if err := checkAddress(addr); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.path(addr))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &verifier{f: f, h: sha256.New(), addr: addr}, nil
+}
+
+type verifier struct {
+	f    *os.File
+	h    hash.Hash
+	addr string
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	n, err := v.f.Read(p)
+	v.h.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(v.h.Sum(nil)) != v.addr {
+		return n, fmt.Errorf("%w: %s", ErrCorrupt, v.addr)
+	}
+	return n, err
+}
+
+func (v *verifier) Close() error { return v.f.Close() }
+
+// All returns the address of every blob, sorted.
+func (s *Store) All() ([]string, error) {
+	var addrs []string
+	err := s.walk(func(addr, _ string, _ fs.DirEntry) error {
+		addrs = append(addrs, addr)
+		return nil
+	})
+	slices.Sort(addrs)
+	return addrs, err
+}
+
+// walk calls fn for each blob with its address and path.
+func (s *Store) walk(fn func(addr, path string, d fs.DirEntry) error) error {
+	return filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == tmpDir && filepath.Dir(p) == filepath.Clean(s.root) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if checkAddress(d.Name()) != nil {
+			return nil // Not ours
+		}
+		return fn(d.Name(), p, d)
+	})
+}
+
+// Collected is what GC removed.
+type Collected struct {
+	Blobs int
+	Bytes int64
+}
+
+// GC removes every blob whose address live doesn't contain, and the
+// temporary files of Puts that never finished. The caller builds live
+// from whatever refers to blobs (for 154, every backup's manifest); a
+// blob it forgets to list is gone, so GC only runs on a complete set.
+func (s *Store) GC(live map[string]bool) (Collected, error) {
+	var c Collected
+	err := s.walk(func(addr, p string, d fs.DirEntry) error {
+		if live[addr] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+		c.Blobs++
+		c.Bytes += info.Size()
+		os.Remove(filepath.Dir(p)) // Fails, harmlessly, unless empty
+		os.Remove(filepath.Dir(filepath.Dir(p)))
+		return nil
+	})
+	if err != nil {
+		return c, err
+	}
+	tmps, err := os.ReadDir(filepath.Join(s.root, tmpDir))
+	for _, e := range tmps {
+		os.Remove(filepath.Join(s.root, tmpDir, e.Name()))
+	}
+	return c, err
+}
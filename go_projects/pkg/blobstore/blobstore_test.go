@@ -0,0 +1,141 @@
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func open(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func read(t *testing.T, s *Store, addr string) (string, error) {
+	t.Helper()
+	rc, err := s.Get(addr)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	return string(data), err
+}
+
+func TestPutGet(t *testing.T) {
+	s := open(t)
+	addr, err := s.Put(strings.NewReader("hello\n"))
+	sum := sha256.Sum256([]byte("hello\n"))
+	if err != nil || addr != hex.EncodeToString(sum[:]) {
+		t.Fatalf("Put = %q, %v; want the SHA-256", addr, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.root, addr[:2], addr[2:4], addr)); err != nil {
+		t.Errorf("not fanned out: %v", err)
+	}
+	if got, err := read(t, s, addr); got != "hello\n" || err != nil {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if !s.Has(addr) || s.Has(strings.Repeat("0", 64)) || s.Has("../../etc/passwd") {
+		t.Error("Has is wrong")
+	}
+
+	// The same content again: the same address, one blob.
+	again, err := s.Put(strings.NewReader("hello\n"))
+	if err != nil || again != addr {
+		t.Errorf("second Put = %q, %v", again, err)
+	}
+	empty, _ := s.Put(strings.NewReader(""))
+	if all, err := s.All(); err != nil || !slices.Equal(all, slices.Sorted(slices.Values([]string{addr, empty}))) {
+		t.Errorf("All = %v, %v", all, err)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	s := open(t)
+	for _, addr := range []string{"", "abc", strings.Repeat("G", 64), "../" + strings.Repeat("0", 61)} {
+		if _, err := s.Get(addr); !errors.Is(err, ErrAddress) {
+			t.Errorf("Get(%q) = %v, want ErrAddress", addr, err)
+		}
+	}
+	if _, err := s.Get(strings.Repeat("ab", 32)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) = %v, want ErrNotFound", err)
+	}
+
+	// Bit rot: the file changes under its address.
+	addr, _ := s.Put(strings.NewReader("the original"))
+	os.WriteFile(s.path(addr), []byte("the 0riginal"), 0o644)
+	if _, err := read(t, s, addr); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("reading a damaged blob: %v, want ErrCorrupt", err)
+	}
+}
+
+func TestPutFailure(t *testing.T) {
+	s := open(t)
+	boom := errors.New("client hung up")
+	r := io.MultiReader(strings.NewReader("half"), iotest.ErrReader(boom))
+	if _, err := s.Put(r); !errors.Is(err, boom) {
+		t.Errorf("Put = %v, want the reader's error", err)
+	}
+	all, _ := s.All()
+	tmps, _ := os.ReadDir(filepath.Join(s.root, tmpDir))
+	if len(all) != 0 || len(tmps) != 0 {
+		t.Errorf("a failed Put left %v and %d temporary files", all, len(tmps))
+	}
+}
+
+func TestConcurrentPut(t *testing.T) {
+	s := open(t)
+	var wg sync.WaitGroup
+	addrs := make([]string, 16)
+	for i := range addrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addr, err := s.Put(strings.NewReader("same bytes"))
+			if err != nil {
+				t.Error(err)
+			}
+			addrs[i] = addr
+		}()
+	}
+	wg.Wait()
+	if all, _ := s.All(); len(all) != 1 || len(slices.Compact(addrs)) != 1 {
+		t.Errorf("16 Puts of the same bytes: %v", all)
+	}
+}
+
+func TestGC(t *testing.T) {
+	s := open(t)
+	keep, _ := s.Put(strings.NewReader("referenced"))
+	drop, _ := s.Put(strings.NewReader("orphan"))
+	os.WriteFile(filepath.Join(s.root, tmpDir, "put-123"), []byte("crashed"), 0o644)
+
+	c, err := s.GC(map[string]bool{keep: true})
+	if err != nil || c != (Collected{Blobs: 1, Bytes: int64(len("orphan"))}) {
+		t.Errorf("GC = %+v, %v", c, err)
+	}
+	if !s.Has(keep) || s.Has(drop) {
+		t.Errorf("after GC: Has(keep) = %v, Has(drop) = %v", s.Has(keep), s.Has(drop))
+	}
+	if _, err := os.Stat(filepath.Join(s.root, drop[:2])); !errors.Is(err, os.ErrNotExist) && drop[:2] != keep[:2] {
+		t.Errorf("the orphan's empty directories are still there")
+	}
+	if tmps, _ := os.ReadDir(filepath.Join(s.root, tmpDir)); len(tmps) != 0 {
+		t.Errorf("GC left temporary files: %v", tmps)
+	}
+	if _, err := s.Put(strings.NewReader("after gc")); err != nil {
+		t.Errorf("Put after GC: %v", err)
+	}
+}
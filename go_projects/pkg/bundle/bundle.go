@@ -0,0 +1,271 @@
+// Package bundle packs a learner's exercise solutions and their test
+// results into one signed file, for a peer to review (gotut review):
+//
+//	key, _ := bundle.Key()                   // $GOTUT_REVIEW_KEY, shared by the group
+//	bundle.Write(f, &bundle.Bundle{Learner: "ada", Exercises: ex}, key)
+//	b, err := bundle.Read(f, key)           // ErrBadSignature if it was changed
+//
+// A bundle is a tar.gz: manifest.json lists every exercise, its test
+// results and the SHA-256 of each file; manifest.json.hmac signs it; the
+// files follow under files/DIR/NAME. Signing the manifest signs the files
+// through their hashes, the way Topic 179 signs SHA256SUMS and not each
+// archive.
+//
+// The signature is an HMAC, a shared secret: it tells a study group that
+// a bundle came from one of them and wasn't edited on the way — not
+// which one of them made it. Learner is what the author says it is.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Bundle is one learner's work on some exercises.
+type Bundle struct {
+	Learner   string     `json:"learner"`
+	Created   time.Time  `json:"created"`
+	Exercises []Exercise `json:"exercises"`
+}
+
+// Exercise is one exercise directory as its author left it: their files
+// (not the tests, which every copy of the course has) and how the tests
+// went on their machine.
+type Exercise struct {
+	Dir   string `json:"dir"` // "70_string_functions_exercise"
+	Files []File `json:"files"`
+	Tests []Test `json:"tests"`
+	Build string `json:"build,omitempty"` // The compiler's output, when it didn't compile
+}
+
+// File is one source file. Data travels in the archive, SHA256 in the
+// manifest.
+type File struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Data   []byte `json:"-"`
+}
+
+// Test is one top-level test's outcome, as pkg/kata reports it.
+type Test struct {
+	Name    string `json:"name"`
+	Pass    bool   `json:"pass"`
+	Message string `json:"message,omitempty"`
+}
+
+// Passed counts the passing tests.
+func (e Exercise) Passed() int {
+	n := 0
+	for _, t := range e.Tests {
+		if t.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+const (
+	manifestName = "manifest.json"
+	sigName      = "manifest.json.hmac"
+	filesDir     = "files/"
+	version      = 1
+)
+
+// MaxFile is the largest entry Read accepts. Exercise files are a few
+// KiB; the limit keeps a hostile bundle from filling memory.
+const MaxFile = 1 << 20
+
+// KeyEnv names the environment variable holding the group's key.
+const KeyEnv = "GOTUT_REVIEW_KEY"
+
+// ErrBadSignature means the manifest's HMAC doesn't match.
+var ErrBadSignature = errors.New("bundle signature does not match: wrong key, or the bundle was changed")
+
+// Key reads the hex key from the environment, never from a flag (flags
+// end up in shell history and ps output), as Topic 179's ReleaseKey does.
+func Key() ([]byte, error) {
+	v := os.Getenv(KeyEnv)
+	if v == "" {
+		return nil, fmt.Errorf("%s is not set; agree on one with your group: openssl rand -hex 32", KeyEnv)
+	}
+	key, err := hex.DecodeString(v)
+	if err != nil || len(key) < sha256.Size {
+		return nil, fmt.Errorf("%s: want at least %d bytes, hex-encoded", KeyEnv, sha256.Size)
+	}
+	return key, nil
+}
+
+// manifest is manifest.json: the bundle with a format version.
+type manifest struct {
+	Version int `json:"version"`
+	Bundle
+}
+
+// Write signs b with key and writes it to w. It fills in each file's
+// SHA256, and Created if it is zero.
+func Write(w io.Writer, b *Bundle, key []byte) error {
+	if b.Created.IsZero() {
+		b.Created = time.Now()
+	}
+	b.Created = b.Created.UTC().Truncate(time.Second)
+	for i := range b.Exercises {
+		ex := &b.Exercises[i]
+		if !plainName(ex.Dir) {
+			return fmt.Errorf("bundle: exercise directory %q is not a plain name", ex.Dir)
+		}
+		for j := range ex.Files {
+			f := &ex.Files[j]
+			if !plainName(f.Name) {
+				return fmt.Errorf("bundle: %s: file name %q is not a plain name", ex.Dir, f.Name)
+			}
+			f.SHA256 = sum(f.Data)
+		}
+	}
+	m, err := json.MarshalIndent(manifest{version, *b}, "", "  ")
+	if err != nil {
+		return err
+	}
+	sig := "hmac-sha256 " + hex.EncodeToString(mac(key, m)) + "\n"
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: b.Created, Format: tar.FormatPAX}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := add(manifestName, m); err != nil {
+		return err
+	}
+	if err := add(sigName, []byte(sig)); err != nil {
+		return err
+	}
+	for _, ex := range b.Exercises {
+		for _, f := range ex.Files {
+			if err := add(filesDir+ex.Dir+"/"+f.Name, f.Data); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Read checks a bundle and returns it, files loaded. The signature is
+// checked before anything in the manifest is believed, then every file
+// against its hash; an entry the manifest doesn't list is an error too,
+// so nothing unsigned rides along.
+func Read(r io.Reader, key []byte) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+	entries := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("bundle: %s is not a regular file", hdr.Name)
+		}
+		if hdr.Size > MaxFile {
+			return nil, fmt.Errorf("bundle: %s is %d bytes, over the %d limit", hdr.Name, hdr.Size, MaxFile)
+		}
+		if _, dup := entries[hdr.Name]; dup {
+			return nil, fmt.Errorf("bundle: %s appears twice", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %s: %w", hdr.Name, err)
+		}
+		entries[hdr.Name] = data
+	}
+
+	m, sig := entries[manifestName], entries[sigName]
+	if m == nil || sig == nil {
+		return nil, fmt.Errorf("bundle: no %s, or no %s: not a gotut review bundle", manifestName, sigName)
+	}
+	alg, hexMAC, _ := strings.Cut(strings.TrimSpace(string(sig)), " ")
+	got, err := hex.DecodeString(hexMAC)
+	if alg != "hmac-sha256" || err != nil {
+		return nil, fmt.Errorf("bundle: %s: want \"hmac-sha256 HEX\"", sigName)
+	}
+	if !hmac.Equal(got, mac(key, m)) { // Constant time: no timing hints for a forger
+		return nil, ErrBadSignature
+	}
+	delete(entries, manifestName)
+	delete(entries, sigName)
+
+	var man manifest
+	if err := json.Unmarshal(m, &man); err != nil {
+		return nil, fmt.Errorf("bundle: %s: %w", manifestName, err)
+	}
+	if man.Version != version {
+		return nil, fmt.Errorf("bundle: format version %d, want %d", man.Version, version)
+	}
+	b := &man.Bundle
+	for i := range b.Exercises {
+		ex := &b.Exercises[i]
+		if !plainName(ex.Dir) {
+			return nil, fmt.Errorf("bundle: exercise directory %q is not a plain name", ex.Dir)
+		}
+		for j := range ex.Files {
+			f := &ex.Files[j]
+			name := filesDir + ex.Dir + "/" + f.Name
+			data, ok := entries[name]
+			switch {
+			case !plainName(f.Name):
+				return nil, fmt.Errorf("bundle: %s: file name %q is not a plain name", ex.Dir, f.Name)
+			case !ok:
+				return nil, fmt.Errorf("bundle: %s is in the manifest but not the archive", name)
+			case sum(data) != f.SHA256:
+				return nil, fmt.Errorf("bundle: %s does not match its SHA-256 in the manifest", name)
+			}
+			f.Data = data
+			delete(entries, name)
+		}
+	}
+	for name := range entries {
+		return nil, fmt.Errorf("bundle: %s is not in the manifest", name)
+	}
+	return b, nil
+}
+
+// plainName is a name that stays where it's put when joined to a
+// directory: no separators, no "..". An importer writes files by these
+// names, so a manifest can't aim one at ~/.bashrc.
+func plainName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`) && path.Clean(name) == name
+}
+
+func mac(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func sum(data []byte) string {
+	s := sha256.Sum256(data)
+	return hex.EncodeToString(s[:])
+}
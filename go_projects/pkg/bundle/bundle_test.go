@@ -0,0 +1,136 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var key = bytes.Repeat([]byte{7}, 32)
+
+func sample() *Bundle {
+	return &Bundle{
+		Learner: "ada",
+		Created: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Exercises: []Exercise{{
+			Dir:   "70_string_functions_exercise",
+			Files: []File{{Name: "strings.go", Data: []byte("package main\n")}},
+			Tests: []Test{{Name: "TestInitials", Pass: true}, {Name: "TestSlugify", Message: `got "a b"`}},
+		}},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sample(), key); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Read(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := b.Exercises[0]
+	if b.Learner != "ada" || ex.Dir != "70_string_functions_exercise" || ex.Passed() != 1 || len(ex.Tests) != 2 {
+		t.Errorf("read back %+v", b)
+	}
+	if string(ex.Files[0].Data) != "package main\n" || ex.Tests[1].Message != `got "a b"` {
+		t.Errorf("read back %+v", ex)
+	}
+}
+
+// repack rewrites a bundle's tar entries through edit, keeping the
+// original manifest and signature unless edit changes them.
+func repack(t *testing.T, data []byte, edit func(map[string][]byte)) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	entries := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		entries[hdr.Name], _ = io.ReadAll(tr)
+	}
+	edit(entries)
+	for name := range entries {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	var out bytes.Buffer
+	gzw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gzw)
+	for _, name := range names {
+		if d, ok := entries[name]; ok {
+			tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(d))})
+			tw.Write(d)
+		}
+	}
+	tw.Close()
+	gzw.Close()
+	return out.Bytes()
+}
+
+func TestTampering(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sample(), key); err != nil {
+		t.Fatal(err)
+	}
+	good := buf.Bytes()
+	const file = "files/70_string_functions_exercise/strings.go"
+	for _, tc := range []struct {
+		name string
+		edit func(map[string][]byte)
+		key  []byte
+		want string
+	}{
+		{"wrong key", func(map[string][]byte) {}, bytes.Repeat([]byte{8}, 32), "signature does not match"},
+		{"file changed", func(e map[string][]byte) { e[file] = []byte("package main // mine\n") }, key, "does not match its SHA-256"},
+		{"manifest changed", func(e map[string][]byte) {
+			e[manifestName] = bytes.Replace(e[manifestName], []byte(`"pass": false`), []byte(`"pass": true`), 1)
+		}, key, "signature does not match"},
+		{"file removed", func(e map[string][]byte) { delete(e, file) }, key, "not the archive"},
+		{"file added", func(e map[string][]byte) { e["files/x/evil.go"] = []byte("package main\n") }, key, "not in the manifest"},
+		{"unsigned", func(e map[string][]byte) { delete(e, sigName) }, key, "not a gotut review bundle"},
+	} {
+		_, err := Read(bytes.NewReader(repack(t, good, tc.edit)), tc.key)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: Read error %v, want one mentioning %q", tc.name, err, tc.want)
+		}
+	}
+	if _, err := Read(bytes.NewReader(good), bytes.Repeat([]byte{8}, 32)); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("wrong key: %v, want ErrBadSignature", err)
+	}
+}
+
+// TestPlainNames checks Write refuses names an importer couldn't write
+// safely, so a bundle with one can only be hand-made.
+func TestPlainNames(t *testing.T) {
+	for _, name := range []string{"../../.bashrc", "a/b.go", `a\b.go`, "..", ""} {
+		b := sample()
+		b.Exercises[0].Files[0].Name = name
+		if err := Write(io.Discard, b, key); err == nil {
+			t.Errorf("Write accepted file name %q", name)
+		}
+	}
+	b := sample()
+	b.Exercises[0].Dir = "../70"
+	if err := Write(io.Discard, b, key); err == nil {
+		t.Error("Write accepted exercise directory \"../70\"")
+	}
+}
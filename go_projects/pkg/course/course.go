@@ -0,0 +1,135 @@
+// Package course finds the course's lessons on disk, for the lessons
+// that run, parse or index other lessons (170 snippets, 171 sections,
+// 172 run summary, 176 run events):
+//
+//	path, err := course.File(73) // "../intermediate_topics/73_regex_detailed.go"
+//
+// Each of those lessons used to carry its own copy of lessonFile and
+// courseDirs. The copies are deprecated now (pkg/deprecate); they call
+// File and say so on stderr until the last caller has moved.
+//
+// Paths are relative to go_projects/, where the lessons are run from.
+//
+// Topics is the course as a pkg/registry table, for the tools that list
+// it (gotut help topics, the web front end):
+//
+//	reg, err := course.Topics(course.Dirs()...)
+//	t, ok := reg.Lookup("regex") // or "73"
+package course
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/registry"
+)
+
+// dirs are where topics live: 57–102 in intermediate_topics, 103–128 in
+// go_advanced_concepts, the rest in go_projects itself.
+var dirs = []string{".", "../intermediate_topics", "../go_advanced_concepts"}
+
+// Dirs returns the directories lessons are in, go_projects/ first.
+func Dirs() []string { return slices.Clone(dirs) }
+
+// File returns the single .go file of a topic: the first one in name
+// order, tests left out. Multi-file topics (152, 160) are directories,
+// whole programs already, and aren't found; 73 has a file and a
+// directory, and the file is the fuller lesson.
+func File(topic int) (string, error) {
+	prefix := strconv.Itoa(topic) + "_"
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, prefix+"*.go"))
+		if err != nil {
+			return "", err
+		}
+		matches = slices.DeleteFunc(matches, func(m string) bool { return strings.HasSuffix(m, "_test.go") })
+		if len(matches) > 0 {
+			return matches[0], nil
+		}
+	}
+	return "", fmt.Errorf("no single-file lesson for topic %d", topic)
+}
+
+// titleRx finds a lesson's title in its header: "TOPIC: HTML/TEMPLATE —
+// ...", or "// Topic 73: Regular Expressions" in intermediate_topics.
+var titleRx = regexp.MustCompile(`(?mi)^\s*(?://\s*)?TOPIC(?:\s+\d+)?\s*:\s*(.+?)\s*$`)
+
+var lessonRx = regexp.MustCompile(`^(\d+)_(.+?)(\.go)?$`)
+
+// Topics registers every lesson in dirs as a topic: its number, a slug
+// from its first file's name, and a title from that file's header. A
+// topic's Files are names within its directory; a number in two
+// directories is one topic.
+func Topics(dirs ...string) (*registry.Registry, error) {
+	byID := map[int]*registry.Topic{}
+	var ids []int
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			m := lessonRx.FindStringSubmatch(e.Name())
+			if m == nil || (!e.IsDir() && m[3] == "") {
+				continue
+			}
+			id, err := strconv.Atoi(m[1])
+			if err != nil {
+				continue // Digits too many for an int: not a topic number
+			}
+			path := filepath.Join(dir, e.Name())
+			t, ok := byID[id]
+			if !ok {
+				t = &registry.Topic{ID: id, Slug: strings.ReplaceAll(strings.ToLower(m[2]), "_", "-")}
+				byID[id], ids = t, append(ids, id)
+			}
+			t.Files = append(t.Files, e.Name())
+			if t.Title == "" {
+				t.Title = lessonTitle(path, e.IsDir())
+			}
+		}
+	}
+	reg := registry.New()
+	for _, id := range ids {
+		t := byID[id]
+		if t.Title == "" {
+			t.Title = strings.ReplaceAll(t.Slug, "-", " ")
+		}
+		if reg.Register(*t) != nil {
+			t.Slug = fmt.Sprintf("topic-%d", id) // A slug two lessons share, or one registry rejects
+			if err := reg.Register(*t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return reg, nil
+}
+
+// lessonTitle reads the title from a lesson file's first lines, or from
+// the main.go of a lesson package.
+func lessonTitle(path string, dir bool) string {
+	if dir {
+		path = filepath.Join(path, "main.go")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	head := make([]byte, 4096)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "" // A file shorter than head is fine; a read that fails isn't
+	}
+	if m := titleRx.FindSubmatch(head[:n]); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package course
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"go_projects/172_run_summary.go",
+		"go_projects/160_interp/main.go",
+		"go_projects/7_b_test.go",
+		"go_projects/7_c.go",
+		"intermediate_topics/73_regex_detailed.go",
+		"intermediate_topics/73_regex_exercise/regex.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(filepath.Join(root, "go_projects"))
+	for topic, want := range map[int]string{
+		172: "172_run_summary.go",
+		7:   "7_c.go",
+		73:  "../intermediate_topics/73_regex_detailed.go",
+	} {
+		if got, err := File(topic); err != nil || got != filepath.FromSlash(want) {
+			t.Errorf("File(%d) = %q, %v; want %s", topic, got, err, want)
+		}
+	}
+	for _, topic := range []int{160, 999} {
+		if got, err := File(topic); err == nil {
+			t.Errorf("File(%d) = %q, want an error", topic, got)
+		}
+	}
+}
+
+func TestTopics(t *testing.T) {
+	root := t.TempDir()
+	for name, src := range map[string]string{
+		"a/73_regex_detailed.go":      "package main\n\n// Topic 73: Regular Expressions\n",
+		"a/73_regex_exercise/main.go": "package main\n",
+		"a/7_notes.txt":               "not a lesson",
+		"b/152_kvstore/main.go":       "package main\n\n/*\nTOPIC: A KEY-VALUE STORE\n*/\n",
+		"b/200_sha.go":                "package main\n",
+		"b/201_sha.go":                "package main\n", // The same slug as 200
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg, err := Topics(filepath.Join(root, "a"), filepath.Join(root, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reg.Len(); got != 4 {
+		t.Errorf("Len = %d, want 4", got)
+	}
+	for _, tc := range []struct {
+		name, slug, title string
+		files             int
+	}{
+		{"73", "regex-detailed", "Regular Expressions", 2},
+		{"kvstore", "kvstore", "A KEY-VALUE STORE", 1},
+		{"sha", "sha", "sha", 1},
+		{"201", "topic-201", "sha", 1},
+	} {
+		got, ok := reg.Lookup(tc.name)
+		if !ok || got.Slug != tc.slug || got.Title != tc.title || len(got.Files) != tc.files {
+			t.Errorf("Lookup(%q) = %+v, %v; want slug %q, title %q, %d files", tc.name, got, ok, tc.slug, tc.title, tc.files)
+		}
+	}
+	if _, err := Topics(filepath.Join(root, "missing")); err == nil {
+		t.Error("Topics of a missing directory succeeded")
+	}
+}
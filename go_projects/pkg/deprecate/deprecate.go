@@ -0,0 +1,217 @@
+// Package deprecate retires an API without breaking the code that still
+// calls it. The old function stays, as a shim over the new one, and says
+// so every time it is reached from somewhere new:
+//
+//	// lessonFile finds a topic's file.
+//	//
+//	// Deprecated: use course.File.
+//	func lessonFile(topic int) (string, error) {
+//		deprecate.Warn("lessonFile")
+//		return course.File(topic)
+//	}
+//
+// which logs, once per call site, a structured warning on stderr:
+//
+//	level=WARN msg=deprecated name=lessonFile use=course.File caller=172_run_summary.go:441
+//
+// That is the middle step of changing an API gracefully. First the new
+// API ships next to the old one; then the old one is marked — the
+// "Deprecated:" paragraph for gopls and staticcheck, the warning for
+// whoever runs the code — and callers move over at their own pace; only
+// when no caller is left is it deleted. Find is how anyone knows when
+// that is: it lists the call sites still left in the source, and
+// gotut deprecations prints them.
+//
+// Every deprecated name is in one registry, the notices below, so the
+// warning, the listing and the replacement can't drift apart. Warn on a
+// name that isn't registered panics: it is a bug in the shim.
+//
+// $GOTUT_DEPRECATIONS=off silences the warnings, =json writes them as
+// JSON lines for a log collector.
+package deprecate
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Notice is one deprecated name.
+type Notice struct {
+	Name string // The old function, as called: "lessonFile"
+	Use  string // What to call instead: "course.File"
+	Why  string // What the new one does better
+}
+
+var notices = []Notice{
+	{"lessonFile", "course.File",
+		"one copy in pkg/course instead of one per lesson, and filepath.Glob's errors aren't dropped"},
+}
+
+// All returns the registry, in the order names were deprecated.
+func All() []Notice { return slices.Clone(notices) }
+
+// Lookup returns the notice for name.
+func Lookup(name string) (Notice, bool) {
+	i := slices.IndexFunc(notices, func(n Notice) bool { return n.Name == name })
+	if i < 0 {
+		return Notice{}, false
+	}
+	return notices[i], true
+}
+
+var (
+	mu      sync.Mutex
+	logger  *slog.Logger
+	warned  = map[string]bool{} // "name file:line" already logged
+	envRead bool
+)
+
+// SetLogger sends warnings to l instead of stderr; nil drops them. Tests
+// and programs with their own logging call it before anything warns.
+func SetLogger(l *slog.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger, envRead = l, true
+}
+
+// defaultLogger is stderr, as text without the time (the line is read by
+// a person, next to the program's own output) or as JSON with it.
+func defaultLogger() *slog.Logger {
+	switch os.Getenv("GOTUT_DEPRECATIONS") {
+	case "off":
+		return nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// Warn logs that the deprecated function name was called. It is called
+// by the shim itself, so the call site it reports is the shim's caller.
+// Each call site is logged once: a loop calling a shim a thousand times
+// is one line.
+func Warn(name string) {
+	n, ok := Lookup(name)
+	if !ok {
+		panic(fmt.Sprintf("deprecate: %q is not registered", name))
+	}
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	mu.Lock()
+	if !envRead {
+		logger, envRead = defaultLogger(), true
+	}
+	l, seen := logger, warned[name+" "+caller]
+	warned[name+" "+caller] = true
+	mu.Unlock()
+	if l != nil && !seen {
+		l.Warn("deprecated", "name", n.Name, "use", n.Use, "caller", caller)
+	}
+}
+
+// Shim is a deprecated function still defined in the source, with the
+// calls to it that are left.
+type Shim struct {
+	Notice
+	Def   token.Position
+	Calls []token.Position
+}
+
+// Find parses the .go files under root and returns every shim of a
+// registered name, by file. A call counts when it is in the same file as
+// the shim: the lessons are one program per file, and a shim in one
+// can't be called from another. File names are relative to root.
+// Tests, testdata and hidden directories are skipped.
+func Find(root string) ([]Shim, error) {
+	var shims []Shim
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil // A shim's own tests call it on purpose
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		shims = append(shims, shimsIn(fset, f)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range shims {
+		rel := func(p *token.Position) {
+			if r, err := filepath.Rel(root, p.Filename); err == nil {
+				p.Filename = r
+			}
+		}
+		rel(&shims[i].Def)
+		for j := range shims[i].Calls {
+			rel(&shims[i].Calls[j])
+		}
+	}
+	slices.SortStableFunc(shims, func(a, b Shim) int { return cmp.Compare(a.Def.Filename, b.Def.Filename) })
+	return shims, nil
+}
+
+// shimsIn finds the registered functions f declares and the calls to
+// them in f.
+func shimsIn(fset *token.FileSet, f *ast.File) []Shim {
+	var shims []Shim
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		if n, ok := Lookup(fn.Name.Name); ok {
+			shims = append(shims, Shim{Notice: n, Def: fset.Position(fn.Name.Pos())})
+		}
+	}
+	if len(shims) == 0 {
+		return nil
+	}
+	ast.Inspect(f, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := call.Fun.(*ast.Ident); ok {
+			for i := range shims {
+				if shims[i].Name == id.Name {
+					shims[i].Calls = append(shims[i].Calls, fset.Position(id.Pos()))
+				}
+			}
+		}
+		return true
+	})
+	return shims
+}
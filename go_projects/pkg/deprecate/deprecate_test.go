@@ -0,0 +1,77 @@
+package deprecate
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lessonFile is a shim, as a lesson would have it.
+func lessonFile() { Warn("lessonFile") }
+
+func TestWarnOncePerCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer SetLogger(nil)
+	for range 3 {
+		lessonFile() // One site, three calls
+	}
+	lessonFile()
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d warnings, want one per call site:\n%s", len(lines), buf.String())
+	}
+	for _, want := range []string{"level=WARN", "msg=deprecated", "name=lessonFile", "use=course.File", "caller=deprecate_test.go:"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("%q doesn't have %s", lines[0], want)
+		}
+	}
+	if lines[0] == lines[1] {
+		t.Errorf("both warnings name the same caller: %q", lines[0])
+	}
+}
+
+func TestWarnUnregistered(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), `"nope" is not registered`) {
+			t.Errorf("recovered %v", r)
+		}
+	}()
+	Warn("nope")
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"172_run.go": "package main\n\n// Deprecated: use course.File.\nfunc lessonFile(int) {}\n\n" +
+			"func main() {\n\tlessonFile(1)\n\tif true {\n\t\tlessonFile(2)\n\t}\n}\n",
+		"176_events.go":          "package main\n\nfunc main() { course.File(1) }\n",           // Moved over
+		"177_other.go":           "package main\n\nfunc main() { lessonFile(1) }\n",            // Another program's lessonFile
+		"175_cli/cli.go":         "package main\n\nfunc lessonFile() {}\n",                     // A shim no one calls
+		"testdata/broken.go":     "package main\n\nfunc lessonFile( {\n",                       // Skipped
+		"pkg/x/x.go":             "package x\n\ntype T struct{}\n\nfunc (T) lessonFile() {}\n", // A method: not the shim
+		"pkg/x/registry_note.md": "lessonFile(1)\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shims, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shims) != 2 {
+		t.Fatalf("%d shims, want 2: %+v", len(shims), shims)
+	}
+	if s := shims[0]; s.Def.Filename != filepath.Join("172_run.go") || s.Def.Line != 4 || len(s.Calls) != 2 || s.Calls[1].Line != 9 {
+		t.Errorf("172: %+v", s)
+	}
+	if s := shims[1]; s.Def.Filename != filepath.Join("175_cli", "cli.go") || len(s.Calls) != 0 || s.Use != "course.File" {
+		t.Errorf("175: %+v", s)
+	}
+}
@@ -0,0 +1,134 @@
+// Package diff prints the unified diff of two texts, the format of
+// "diff -u" and "git diff" (see Topic 159; gotut solution --diff):
+//
+//	fmt.Print(diff.Unified("yours.go", "reference.go", mine, theirs))
+//
+// Go's own diff package is internal, so this is Topic 159's: the longest
+// common subsequence of the lines, then hunks with three lines of context.
+// Its O(n·m) table is fine for source files; real tools use Myers.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Context is how many unchanged lines surround each change.
+const Context = 3
+
+// edit is one line of the diff: kept (' '), removed ('-') or added ('+'),
+// with its 0-based line numbers in a and b.
+type edit struct {
+	op   byte
+	line string
+	i, j int
+}
+
+// Unified returns the diff from a to b, or "" if they are the same.
+// A last line without a newline is marked the way diff marks it.
+func Unified(oldName, newName string, a, b []byte) string {
+	edits := script(lines(a), lines(b))
+	var out strings.Builder
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+		start, end := max(0, k-Context), hunkEnd(edits, k)
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		var oldN, newN int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				oldN++
+			}
+			if e.op != '-' {
+				newN++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", span(edits[start].i, oldN), span(edits[start].j, newN))
+		for _, e := range edits[start:end] {
+			line := e.line
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n\\ No newline at end of file\n"
+			}
+			out.WriteString(string(e.op) + line)
+		}
+		k = end
+	}
+	return out.String()
+}
+
+// lines splits after each newline: "a\nb\n" is "a\n", "b\n".
+func lines(s []byte) []string {
+	l := strings.SplitAfter(string(s), "\n")
+	if l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	return l
+}
+
+// script is the shortest edit script from x to y, by LCS.
+func script(x, y []string) []edit {
+	n, m := len(x), len(y)
+	lcs := make([][]int, n+1) // lcs[i][j] = LCS length of x[i:], y[j:]
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && x[i] == y[j]:
+			edits = append(edits, edit{' ', x[i], i, j})
+			i, j = i+1, j+1
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', x[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', y[j], i, j})
+			j++
+		}
+	}
+	return edits
+}
+
+// hunkEnd extends a hunk from the change at k while the next change is
+// within 2*Context unchanged lines, then adds the trailing context.
+func hunkEnd(edits []edit, k int) int {
+	end := k
+	for end < len(edits) {
+		if edits[end].op != ' ' {
+			end++
+			continue
+		}
+		run := end
+		for run < len(edits) && edits[run].op == ' ' {
+			run++
+		}
+		if run == len(edits) || run-end > 2*Context {
+			return min(len(edits), end+Context)
+		}
+		end = run
+	}
+	return end
+}
+
+// span is a hunk header's "start,count". An empty side names the line
+// before the hunk, as diff does: "-0,0" for an insertion at the top.
+func span(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line)
+	}
+	return fmt.Sprintf("%d,%d", line+1, count)
+}
@@ -0,0 +1,28 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	for _, tc := range []struct {
+		name, a, b, want string
+	}{
+		{"same", "a\nb\n", "a\nb\n", ""},
+		{"empty", "", "", ""},
+		{"change", "a\nb\nc\n", "a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"},
+		{"insert at top", "a\n", "new\na\n",
+			"--- old\n+++ new\n@@ -1,1 +1,2 @@\n+new\n a\n"},
+		{"into empty", "", "a\n",
+			"--- old\n+++ new\n@@ -0,0 +1,1 @@\n+a\n"},
+		{"no newline at end", "a\nb", "a\nb\n",
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"},
+		{"two hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n", "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n"},
+		{"one hunk when close", "1\n2\n3\n4\n5\n6\n7\n", "one\n2\n3\n4\n5\n6\nseven\n",
+			"--- old\n+++ new\n@@ -1,7 +1,7 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n-7\n+seven\n"},
+	} {
+		if got := Unified("old", "new", []byte(tc.a), []byte(tc.b)); got != tc.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.name, got, tc.want)
+		}
+	}
+}
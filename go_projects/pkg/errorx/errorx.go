@@ -0,0 +1,288 @@
+// Package errorx holds the error types from intermediate Topic 69, ready to
+// import. Each one carries the context a plain errors.New loses, and the
+// ones that wrap a cause unwrap to it, so errors.Is and errors.As see
+// through them and through any fmt.Errorf("%w") around them:
+//
+//	err := errorx.Wrap(500, "failed to save file", io.ErrShortWrite)
+//	errors.Is(err, io.ErrShortWrite)          // true: Unwrap reaches the cause
+//
+//	var db *errorx.DatabaseError
+//	if errors.As(err, &db) && db.CanRetry() {
+//		// retry the read
+//	}
+//
+//	errors.Is(errorx.NewValidationError("email", "is empty", ""), errorx.ErrValidation) // true
+//
+// ValidationError and AuthError are values, WrappedError and
+// DatabaseError pointers, as in the lesson: errors.As needs a target of
+// the same kind, a ValidationError or a *DatabaseError.
+//
+// Each type has a StatusCode method, and the two about the request a
+// Detail method, which is how errorx/httpmap turns them into responses.
+//
+// MultiError collects several of them, one per bad field, and unwraps to
+// all of them as errors.Join does.
+//
+// WrapStack is Wrap that also records the call stack, printed by %+v:
+//
+//	fmt.Printf("%+v\n", errorx.WrapStack(500, "failed to save file", err))
+package errorx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+)
+
+// ErrValidation and ErrAuth are the kinds of ValidationError and
+// AuthError, for callers that only ask which kind of failure it was.
+var (
+	ErrValidation = errors.New("validation failed")
+	ErrAuth       = errors.New("authentication failed")
+)
+
+// WrappedError adds an HTTP-style code and what was being done to the
+// error that caused it.
+type WrappedError struct {
+	Code    int    // 404, 422, 500...
+	Message string // "failed to save file"
+	Err     error  // The root cause
+	stack   []uintptr
+}
+
+// Wrap returns err with a code and a message, or nil if err is nil, so
+// "return errorx.Wrap(500, ..., f())" keeps a nil a nil.
+func Wrap(code int, message string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &WrappedError{Code: code, Message: message, Err: err}
+}
+
+func (w *WrappedError) Error() string {
+	return fmt.Sprintf("Error %d: %s, caused by: %v", w.Code, w.Message, w.Err)
+}
+
+func (w *WrappedError) Unwrap() error { return w.Err }
+
+// StatusCode is Code: the wrapper decided how bad it is.
+func (w *WrappedError) StatusCode() int { return w.Code }
+
+// maxStack is the most frames WrapStack records.
+const maxStack = 32
+
+// WrapStack is Wrap that also records the stack: the function that
+// called it, that function's caller, and so on up. Recording costs more
+// than the wrapping, so Wrap doesn't: call WrapStack where an error
+// enters your code, and plain Wrap or fmt.Errorf on the way up.
+func WrapStack(code int, message string, err error) error {
+	if err == nil {
+		return nil
+	}
+	pcs := make([]uintptr, maxStack)
+	n := runtime.Callers(2, pcs) // Skip runtime.Callers and WrapStack
+	return &WrappedError{Code: code, Message: message, Err: err, stack: pcs[:n]}
+}
+
+// Frame is one call of a recorded stack.
+type Frame struct {
+	Function string // "main.saveReport"
+	File     string
+	Line     int
+}
+
+// String is the frame as a panic prints it: the function, then the file
+// and line indented below.
+func (f Frame) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line)
+}
+
+// StackTrace is the stack WrapStack recorded, innermost call first, or
+// nil for an error made by Wrap or a struct literal.
+func (w *WrappedError) StackTrace() []Frame {
+	if len(w.stack) == 0 {
+		return nil
+	}
+	var trace []Frame
+	frames := runtime.CallersFrames(w.stack)
+	for {
+		f, more := frames.Next()
+		trace = append(trace, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			return trace
+		}
+	}
+}
+
+// Format prints Error() for %v and %s, and %q quotes it. %+v adds the
+// stack trace below, one frame after another.
+func (w *WrappedError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, w.Error())
+		for _, f := range w.StackTrace() {
+			fmt.Fprintf(s, "\n%s", f)
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	default:
+		io.WriteString(s, w.Error())
+	}
+}
+
+// ValidationError is input that failed a check: which field, what is
+// wrong with it, and what was received. pkg/bind lists them in its 400
+// and 422 bodies, hence the tags.
+type ValidationError struct {
+	Field string `json:"field"`
+	Issue string `json:"issue"`
+	Value string `json:"value,omitempty"`
+}
+
+// NewValidationError returns a ValidationError for field.
+func NewValidationError(field, issue, value string) ValidationError {
+	return ValidationError{Field: field, Issue: issue, Value: value}
+}
+
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("Validation Error: field '%s' %s (received: %q)", v.Field, v.Issue, v.Value)
+}
+
+// Is reports ErrValidation as a match.
+func (v ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// StatusCode is 422 Unprocessable Entity.
+func (v ValidationError) StatusCode() int { return 422 }
+
+// Detail is the field and its issue; Value stays out, it may be a password.
+func (v ValidationError) Detail() (field, issue string) { return v.Field, v.Issue }
+
+// AuthError is a rejected credential: why, and which token.
+type AuthError struct {
+	Reason  string
+	TokenID string
+}
+
+// NewAuthError returns an AuthError for tokenID.
+func NewAuthError(reason, tokenID string) AuthError {
+	return AuthError{Reason: reason, TokenID: tokenID}
+}
+
+func (a AuthError) Error() string {
+	return fmt.Sprintf("Auth Error: %s (token: %s)", a.Reason, a.TokenID)
+}
+
+// Is reports ErrAuth as a match.
+func (a AuthError) Is(target error) bool { return target == ErrAuth }
+
+// StatusCode is 401 Unauthorized.
+func (a AuthError) StatusCode() int { return 401 }
+
+// Detail is the reason, with no field and never the token.
+func (a AuthError) Detail() (field, issue string) { return "", a.Reason }
+
+// DatabaseError is a failed statement: the operation, the table, and the
+// driver's error.
+type DatabaseError struct {
+	Operation string // SELECT, INSERT, UPDATE, DELETE
+	Table     string
+	Inner     error
+}
+
+// NewDatabaseError returns a DatabaseError wrapping inner.
+func NewDatabaseError(operation, table string, inner error) *DatabaseError {
+	return &DatabaseError{Operation: operation, Table: table, Inner: inner}
+}
+
+func (d *DatabaseError) Error() string {
+	return fmt.Sprintf("Database Error: %s on table '%s', caused by: %v", d.Operation, d.Table, d.Inner)
+}
+
+func (d *DatabaseError) Unwrap() error { return d.Inner }
+
+// StatusCode is 503 Service Unavailable for a timeout, which may pass if
+// the client tries again later, and 500 for anything else.
+func (d *DatabaseError) StatusCode() int {
+	if d.IsTimeout() {
+		return 503
+	}
+	return 500
+}
+
+// CanRetry reports whether running the statement again is safe: reads
+// are, writes might apply twice.
+func (d *DatabaseError) CanRetry() bool { return d.Operation == "SELECT" }
+
+// Retryable reports whether running the statement again could help: it
+// is safe to (CanRetry) and the failure may pass (IsTimeout). pkg/retry
+// asks this.
+func (d *DatabaseError) Retryable() bool { return d.CanRetry() && d.IsTimeout() }
+
+// IsTimeout reports whether the cause was a deadline: context's, or any
+// error in the chain with a Timeout method saying so, as net errors have.
+func (d *DatabaseError) IsTimeout() bool {
+	if errors.Is(d.Inner, context.DeadlineExceeded) {
+		return true
+	}
+	var t interface{ Timeout() bool }
+	return errors.As(d.Inner, &t) && t.Timeout()
+}
+
+// MultiError is every failure of one operation, not only the first: a
+// form with three bad fields says so three times. It behaves as the error
+// errors.Join returns, the messages one per line and Unwrap() []error, so
+// errors.Is and errors.As search every one:
+//
+//	var errs errorx.MultiError
+//	if f.Email == "" {
+//		errs.Append(errorx.NewValidationError("email", "is empty", ""))
+//	}
+//	...
+//	return errs.Err()
+//
+// The zero value is empty and ready to use.
+type MultiError struct {
+	Errs []error
+}
+
+// Append adds each non-nil err. Another MultiError is added error by
+// error, so nesting validators keeps one flat list; one wrapped with %w
+// stays as it is, with its context. A nil *MultiError in an error — a
+// validator's "var errs *MultiError; return errs" — adds nothing.
+func (m *MultiError) Append(errs ...error) {
+	for _, err := range errs {
+		if inner, ok := err.(*MultiError); ok {
+			if inner != nil {
+				m.Errs = append(m.Errs, inner.Errs...)
+			}
+		} else if err != nil {
+			m.Errs = append(m.Errs, err)
+		}
+	}
+}
+
+// Len is how many errors m holds.
+func (m *MultiError) Len() int { return len(m.Errs) }
+
+// Err is nil when m holds nothing and m otherwise, so "return errs.Err()"
+// keeps no problems a nil error, as errors.Join of only nils is nil.
+func (m *MultiError) Err() error {
+	if len(m.Errs) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Error is each message on its own line, the same text errors.Join gives.
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errs))
+	for i, err := range m.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (m *MultiError) Unwrap() []error { return m.Errs }
@@ -0,0 +1,173 @@
+package errorx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	err := fmt.Errorf("saving profile: %w", Wrap(500, "failed to save file", io.ErrShortWrite))
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("errors.Is(%v, io.ErrShortWrite) = false", err)
+	}
+	var w *WrappedError
+	if !errors.As(err, &w) || w.Code != 500 || w.Message != "failed to save file" {
+		t.Errorf("errors.As(%v) = %+v", err, w)
+	}
+	if want := "Error 500: failed to save file, caused by: short write"; w.Error() != want {
+		t.Errorf("Error() = %q, want %q", w.Error(), want)
+	}
+	if err := Wrap(500, "nothing to wrap", nil); err != nil {
+		t.Errorf("Wrap(nil) = %v, want nil", err)
+	}
+}
+
+func TestWrapStack(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	err := WrapStack(500, "failed to save file", io.ErrShortWrite) // Recorded on line+1
+	var w *WrappedError
+	if !errors.As(err, &w) || !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("WrapStack = %v, want a *WrappedError of io.ErrShortWrite", err)
+	}
+	trace := w.StackTrace()
+	if len(trace) < 2 || !strings.HasSuffix(trace[0].Function, ".TestWrapStack") ||
+		trace[0].File != file || trace[0].Line != line+1 {
+		t.Fatalf("StackTrace()[0] = %+v, want TestWrapStack at %s:%d", trace, file, line+1)
+	}
+	if got := fmt.Sprintf("%v|%s|%q", err, err, err); got != w.Error()+"|"+w.Error()+"|"+strconv.Quote(w.Error()) {
+		t.Errorf("%%v, %%s and %%q = %s; the trace is for %%+v only", got)
+	}
+	want := w.Error() + "\n" + trace[0].Function + "\n\t" + fmt.Sprintf("%s:%d", file, line+1) + "\n"
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, want) {
+		t.Errorf("%%+v = %q, want it to start %q", got, want)
+	}
+
+	if w := Wrap(500, "no stack", io.EOF).(*WrappedError); w.StackTrace() != nil || fmt.Sprintf("%+v", w) != w.Error() {
+		t.Errorf("Wrap: StackTrace() = %v, %%+v = %+v; want no trace", w.StackTrace(), w)
+	}
+	if err := WrapStack(500, "nothing to wrap", nil); err != nil {
+		t.Errorf("WrapStack(nil) = %v, want nil", err)
+	}
+}
+
+func TestKinds(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		kind, not error
+	}{
+		{NewValidationError("email", "invalid format", "notanemail"), ErrValidation, ErrAuth},
+		{NewAuthError("invalid token", "abc123"), ErrAuth, ErrValidation},
+	} {
+		wrapped := Wrap(422, "signing up", tc.err)
+		if !errors.Is(wrapped, tc.kind) || errors.Is(wrapped, tc.not) {
+			t.Errorf("%v: errors.Is %v = %v, %v = %v; want true, false",
+				wrapped, tc.kind, errors.Is(wrapped, tc.kind), tc.not, errors.Is(wrapped, tc.not))
+		}
+	}
+
+	var ve ValidationError
+	err := fmt.Errorf("signup: %w", NewValidationError("email", "invalid format", "notanemail"))
+	if !errors.As(err, &ve) || ve.Field != "email" {
+		t.Errorf("errors.As(%v, ValidationError) = %+v", err, ve)
+	}
+	var ae AuthError
+	if errors.As(err, &ae) {
+		t.Errorf("errors.As(%v, AuthError) matched a ValidationError", err)
+	}
+}
+
+func TestDatabaseError(t *testing.T) {
+	read := NewDatabaseError("SELECT", "users", context.DeadlineExceeded)
+	write := NewDatabaseError("INSERT", "users", io.ErrUnexpectedEOF)
+	if !read.CanRetry() || write.CanRetry() {
+		t.Errorf("CanRetry: SELECT %v, INSERT %v; want true, false", read.CanRetry(), write.CanRetry())
+	}
+	if !read.IsTimeout() || write.IsTimeout() {
+		t.Errorf("IsTimeout: deadline %v, EOF %v; want true, false", read.IsTimeout(), write.IsTimeout())
+	}
+	if !NewDatabaseError("SELECT", "users", fmt.Errorf("dial: %w", os.ErrDeadlineExceeded)).IsTimeout() {
+		t.Error("IsTimeout missed os.ErrDeadlineExceeded, which has a Timeout method")
+	}
+	slowWrite := NewDatabaseError("UPDATE", "users", context.DeadlineExceeded)
+	badRead := NewDatabaseError("SELECT", "users", errors.New("syntax error"))
+	if !read.Retryable() || slowWrite.Retryable() || badRead.Retryable() {
+		t.Errorf("Retryable: timed-out read %v, timed-out write %v, bad read %v; want only the first",
+			read.Retryable(), slowWrite.Retryable(), badRead.Retryable())
+	}
+
+	err := Wrap(500, "loading user", write)
+	var db *DatabaseError
+	if !errors.As(err, &db) || db.Table != "users" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("through Wrap: As %+v, Is EOF %v", db, errors.Is(err, io.ErrUnexpectedEOF))
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	for _, tc := range []struct {
+		err  interface{ StatusCode() int }
+		want int
+	}{
+		{NewValidationError("email", "is empty", ""), 422},
+		{NewAuthError("expired", "t-1"), 401},
+		{&WrappedError{Code: 404, Message: "no such user", Err: io.EOF}, 404},
+		{NewDatabaseError("SELECT", "users", context.DeadlineExceeded), 503},
+		{NewDatabaseError("INSERT", "users", io.ErrUnexpectedEOF), 500},
+	} {
+		if got := tc.err.StatusCode(); got != tc.want {
+			t.Errorf("%v: StatusCode() = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+	field, issue := NewValidationError("password", "is too short", "hunter2").Detail()
+	if field != "password" || issue != "is too short" {
+		t.Errorf("Detail() = %q, %q", field, issue)
+	}
+	if field, issue := NewAuthError("expired", "t-secret").Detail(); field != "" || issue != "expired" {
+		t.Errorf("AuthError.Detail() = %q, %q; want no field and no token", field, issue)
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	var errs MultiError
+	if errs.Err() != nil {
+		t.Fatalf("empty MultiError: Err() = %v, want nil", errs.Err())
+	}
+	email := NewValidationError("email", "is empty", "")
+	password := NewValidationError("password", "is too short", "abc")
+	db := NewDatabaseError("SELECT", "users", io.ErrUnexpectedEOF)
+
+	var nested MultiError
+	var typedNil *MultiError // Non-nil as an error
+	nested.Append(password, nil)
+	errs.Append(email, nil, &nested, typedNil, db)
+	if errs.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3: nils dropped, the nested MultiError flattened", errs.Len())
+	}
+
+	err := fmt.Errorf("signup: %w", errs.Err())
+	if want := errors.Join(email, password, db).Error(); errs.Error() != want {
+		t.Errorf("Error() = %q, want errors.Join's %q", errs.Error(), want)
+	}
+	if !errors.Is(err, ErrValidation) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("errors.Is through %%w and MultiError: ErrValidation %v, EOF %v",
+			errors.Is(err, ErrValidation), errors.Is(err, io.ErrUnexpectedEOF))
+	}
+	var ve ValidationError
+	var dbe *DatabaseError
+	if !errors.As(err, &ve) || ve.Field != "email" || !errors.As(err, &dbe) || dbe.Table != "users" {
+		t.Errorf("errors.As: %+v, %+v", ve, dbe)
+	}
+
+	// Inside an errors.Join, and a Join inside it, are both searched.
+	var outer MultiError
+	outer.Append(errors.Join(email, password))
+	if outer.Len() != 1 || !errors.Is(errors.Join(io.EOF, &outer), ErrValidation) {
+		t.Errorf("Join interplay: Len() = %d", outer.Len())
+	}
+}
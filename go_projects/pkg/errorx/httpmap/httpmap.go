@@ -0,0 +1,150 @@
+// Package httpmap turns an error into an HTTP response: a status code
+// chosen by what kind of error it is, and a JSON body a client can act
+// on (Topic 69's Section 6):
+//
+//	func getUser(w http.ResponseWriter, r *http.Request) {
+//		u, err := load(r.PathValue("id"))
+//		if err != nil {
+//			httpmap.Write(w, r, err)
+//			return
+//		}
+//		...
+//	}
+//
+//	HTTP/1.1 422 Unprocessable Entity
+//	{"code":422,"message":"invalid request","details":[{"field":"id","issue":"invalid format"}],"request_id":"9f2c01ab"}
+//
+// It knows errors by what they can do, not by their package: an error
+// with a StatusCode method names its own status (errorx's types all do),
+// one with a Detail method says what was wrong with the request. Both are
+// found with errors.As, so any amount of %w wrapping on top is fine.
+// Under that, the standard library's kinds: fs.ErrNotExist is a 404, a
+// deadline a 504. Everything else is a 500.
+//
+// A 5xx says only what the status says: the cause goes to the log, not to
+// the client, who can quote request_id to find it.
+package httpmap
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+)
+
+// StatusCoder is an error that knows its HTTP status.
+type StatusCoder interface {
+	error
+	StatusCode() int
+}
+
+// Detailer is an error that says what was wrong with the request: a field
+// and its problem, or only the problem when no field is to blame.
+type Detailer interface {
+	error
+	Detail() (field, issue string)
+}
+
+// Response is the body Write sends.
+type Response struct {
+	Code      int      `json:"code"`
+	Message   string   `json:"message"`
+	Details   []Detail `json:"details,omitempty"`
+	RequestID string   `json:"request_id,omitempty"`
+}
+
+// Detail is one thing wrong with the request.
+type Detail struct {
+	Field string `json:"field,omitempty"`
+	Issue string `json:"issue"`
+}
+
+// Status is the HTTP status for err, first match wins:
+//
+//	nil                                   200 OK
+//	a StatusCoder (the outermost one)     its StatusCode, if 400-599
+//	fs.ErrNotExist                        404 Not Found
+//	fs.ErrPermission                      403 Forbidden
+//	context.DeadlineExceeded              504 Gateway Timeout
+//	anything else                         500 Internal Server Error
+func Status(err error) int {
+	var sc StatusCoder
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.As(err, &sc) && sc.StatusCode() >= 400 && sc.StatusCode() <= 599:
+		return sc.StatusCode()
+	case errors.Is(err, fs.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return http.StatusForbidden
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// From builds the response body for err. A 4xx lists every Detailer in
+// the chain, through errors.Join too; a 5xx gets the status text and
+// nothing else.
+func From(err error, requestID string) Response {
+	code := Status(err)
+	resp := Response{Code: code, Message: http.StatusText(code), RequestID: requestID}
+	if code >= 500 {
+		return resp
+	}
+	walk(err, func(e error) {
+		if d, ok := e.(Detailer); ok {
+			field, issue := d.Detail()
+			resp.Details = append(resp.Details, Detail{Field: field, Issue: issue})
+		}
+	})
+	if code == http.StatusUnprocessableEntity || code == http.StatusBadRequest {
+		resp.Message = "invalid request"
+	}
+	return resp
+}
+
+// walk calls fn for err and everything under it, through both Unwrap
+// forms: errors.As stops at the first match, and a Join of validation
+// errors should report all of them.
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			walk(e, fn)
+		}
+	}
+}
+
+// RequestID is r's X-Request-Id, or a new random one, as Topic 145's
+// middleware assigns them.
+func RequestID(r *http.Request) string {
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Write sends err as a JSON error response and returns the body it sent,
+// so the caller can log the cause next to its request_id.
+func Write(w http.ResponseWriter, r *http.Request, err error) Response {
+	id := RequestID(r)
+	resp := From(err, id)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Request-Id", id)
+	w.WriteHeader(resp.Code)
+	json.NewEncoder(w).Encode(resp)
+	return resp
+}
@@ -0,0 +1,106 @@
+package httpmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// fieldError and codeError stand in for errorx's types: httpmap only
+// cares about their methods.
+type fieldError struct{ field, issue string }
+
+func (e fieldError) Error() string                 { return e.field + " " + e.issue }
+func (e fieldError) StatusCode() int               { return 422 }
+func (e fieldError) Detail() (field, issue string) { return e.field, e.issue }
+
+type codeError struct {
+	code int
+	err  error
+}
+
+func (e *codeError) Error() string   { return fmt.Sprintf("%d: %v", e.code, e.err) }
+func (e *codeError) Unwrap() error   { return e.err }
+func (e *codeError) StatusCode() int { return e.code }
+
+func TestStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 200},
+		{"plain", errors.New("boom"), 500},
+		{"field error under %w", fmt.Errorf("signup: %w", fieldError{"email", "is empty"}), 422},
+		{"not found", fmt.Errorf("open: %w", fs.ErrNotExist), 404},
+		{"permission", fs.ErrPermission, 403},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), 504},
+		{"outermost code wins", &codeError{500, fieldError{"id", "bad"}}, 500},
+		{"code over a sentinel", &codeError{409, fs.ErrNotExist}, 409},
+		{"code out of range", &codeError{200, fs.ErrNotExist}, 404},
+		{"code nonsense", &codeError{42, errors.New("x")}, 500},
+	} {
+		if got := Status(tc.err); got != tc.want {
+			t.Errorf("%s: Status(%v) = %d, want %d", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	joined := fmt.Errorf("signup: %w", errors.Join(fieldError{"email", "invalid format"}, fieldError{"password", "too short"}))
+	resp := From(joined, "abc")
+	want := []Detail{{"email", "invalid format"}, {"password", "too short"}}
+	if resp.Code != 422 || resp.Message != "invalid request" || resp.RequestID != "abc" || !slices.Equal(resp.Details, want) {
+		t.Errorf("From(join) = %+v, want 422 with %v", resp, want)
+	}
+
+	// A 5xx never shows what caused it, even when a Detailer is inside.
+	resp = From(&codeError{500, fieldError{"db_password", "rejected by the server"}}, "abc")
+	if resp.Message != "Internal Server Error" || resp.Details != nil {
+		t.Errorf("From(500) = %+v, want the status text and no details", resp)
+	}
+
+	resp = From(fs.ErrNotExist, "")
+	if resp.Code != 404 || resp.Message != "Not Found" || resp.Details != nil {
+		t.Errorf("From(ErrNotExist) = %+v", resp)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users/x", nil)
+	req.Header.Set("X-Request-Id", "req-7")
+	rec := httptest.NewRecorder()
+	sent := Write(rec, req, fieldError{"id", "invalid format"})
+
+	if rec.Code != 422 || rec.Header().Get("X-Request-Id") != "req-7" || rec.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("status %d, headers %v", rec.Code, rec.Header())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"code", "message", "details", "request_id"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("body %s has no %q", rec.Body, key)
+		}
+	}
+	if body["request_id"] != "req-7" || sent.RequestID != "req-7" {
+		t.Errorf("request_id %v, returned %q; want the incoming req-7", body["request_id"], sent.RequestID)
+	}
+
+	// No incoming ID: one is made up, and it's the same in the header,
+	// the body and the returned Response.
+	rec = httptest.NewRecorder()
+	sent = Write(rec, httptest.NewRequest("GET", "/", nil), errors.New("boom"))
+	var got Response
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if id := rec.Header().Get("X-Request-Id"); len(id) != 8 || got.RequestID != id || sent.RequestID != id || got.Code != http.StatusInternalServerError {
+		t.Errorf("header id %q, body %+v, returned %+v", id, got, sent)
+	}
+}
@@ -0,0 +1,175 @@
+// Package faultfs makes readers, writers and file systems fail on purpose,
+// so the error paths of code that copies, scans, backs up or receives
+// uploads can be run instead of only written (Topic 195):
+//
+//	r := faultfs.Reader(f, faultfs.Fault{After: 4096})             // EIO after 4 KiB
+//	w := faultfs.Writer(out, faultfs.Fault{Max: 100, Short: true}) // Lies about short writes
+//	_, err := io.Copy(w, r)
+//
+//	fsys := faultfs.NewFS(os.DirFS(src))
+//	fsys.Fail("secrets/*", fs.ErrPermission)       // Open fails
+//	fsys.Inject("*.log", faultfs.Fault{After: 10}) // Reads fail part way
+//
+// A Fault is a plan, not a probability: the same Fault fails at the same
+// byte every run, so a test that sees it once sees it every time.
+package faultfs
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"syscall"
+	"time"
+)
+
+// Fault is what goes wrong, and when. The zero Fault changes nothing.
+type Fault struct {
+	// After is how many bytes get through before Err. With Err nil, and
+	// After 0, nothing fails: set Err, or After, or both.
+	After int64
+	// Err is what comes after them; syscall.EIO, a disk's I/O error, when
+	// After is set and Err isn't.
+	Err error
+	// Max caps the bytes each Read or Write handles: short reads, which
+	// are legal and which code that calls Read once gets wrong.
+	Max int
+	// Short makes a Writer's capped writes return a nil error, which the
+	// io.Writer contract forbids and broken writers do anyway; io.Copy
+	// and bufio.Writer report it as io.ErrShortWrite.
+	Short bool
+	// Delay is slept before every Read or Write: a slow disk or network.
+	Delay time.Duration
+}
+
+// err is the error the fault ends with, or nil if it never ends.
+func (f Fault) err() error {
+	switch {
+	case f.Err != nil:
+		return f.Err
+	case f.After > 0:
+		return syscall.EIO
+	}
+	return nil
+}
+
+// limit is how much of an n-byte call may go through when done bytes
+// already have, and whether the fault's error comes after it: the call
+// that would pass byte After is the one that fails.
+func (f Fault) limit(n int, done int64) (int, bool) {
+	if f.Max > 0 && n > f.Max {
+		n = f.Max
+	}
+	if f.err() != nil && done+int64(n) > f.After {
+		return int(f.After - done), true
+	}
+	return n, false
+}
+
+type reader struct {
+	r    io.Reader
+	f    Fault
+	done int64
+}
+
+// Reader returns r with f applied. Once f's error is due, every Read
+// returns it. The bytes before it are returned first, by themselves, as
+// a disk returns what it could read before the bad sector.
+func Reader(r io.Reader, f Fault) io.Reader { return &reader{r: r, f: f} }
+
+func (r *reader) Read(p []byte) (int, error) {
+	time.Sleep(r.f.Delay)
+	n, failing := r.f.limit(len(p), r.done)
+	if failing && n == 0 {
+		return 0, r.f.err()
+	}
+	n, err := r.r.Read(p[:n])
+	r.done += int64(n)
+	return n, err
+}
+
+type writer struct {
+	w    io.Writer
+	f    Fault
+	done int64
+}
+
+// Writer returns w with f applied. A write that runs into f's error
+// writes what fits before it and returns that count with the error.
+func Writer(w io.Writer, f Fault) io.Writer { return &writer{w: w, f: f} }
+
+func (w *writer) Write(p []byte) (int, error) {
+	time.Sleep(w.f.Delay)
+	n, failing := w.f.limit(len(p), w.done)
+	n, err := w.w.Write(p[:n])
+	w.done += int64(n)
+	switch {
+	case err != nil:
+		return n, err
+	case failing:
+		return n, w.f.err()
+	case n < len(p) && !w.f.Short:
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// FS is a file system whose files fail as it is told to. Set it up with
+// Fail and Inject before handing it out; it isn't safe to change while in
+// use. Patterns are path.Match patterns against the name given to Open.
+type FS struct {
+	fsys   fs.FS
+	fail   []rule[error]
+	inject []rule[Fault]
+}
+
+type rule[T any] struct {
+	pattern string
+	v       T
+}
+
+// NewFS returns fsys with no faults yet.
+func NewFS(fsys fs.FS) *FS { return &FS{fsys: fsys} }
+
+// Fail makes Open fail with err, as an *fs.PathError, for names matching
+// pattern.
+func (f *FS) Fail(pattern string, err error) {
+	f.fail = append(f.fail, rule[error]{pattern, err})
+}
+
+// Inject applies fault to reads of regular files matching pattern. Each
+// Open starts the count again, as reopening a file would.
+func (f *FS) Inject(pattern string, fault Fault) {
+	f.inject = append(f.inject, rule[Fault]{pattern, fault})
+}
+
+// Open opens name in the wrapped file system, with the first matching
+// Fail or Inject applied. Directories are never wrapped, so fs.WalkDir
+// and fs.ReadDir work as before.
+func (f *FS) Open(name string) (fs.File, error) {
+	for _, r := range f.fail {
+		if ok, _ := path.Match(r.pattern, name); ok {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: r.v}
+		}
+	}
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range f.inject {
+		if ok, _ := path.Match(r.pattern, name); !ok {
+			continue
+		}
+		if info, err := file.Stat(); err != nil || !info.Mode().IsRegular() {
+			return file, nil
+		}
+		return &faultFile{File: file, r: Reader(file, r.v)}, nil
+	}
+	return file, nil
+}
+
+type faultFile struct {
+	fs.File
+	r io.Reader
+}
+
+func (f *faultFile) Read(p []byte) (int, error) { return f.r.Read(p) }
@@ -0,0 +1,138 @@
+package faultfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestReader(t *testing.T) {
+	data := strings.Repeat("0123456789", 10)
+	for _, tc := range []struct {
+		name  string
+		fault Fault
+		want  int
+		err   error
+	}{
+		{"zero fault", Fault{}, 100, nil},
+		{"EIO after 25", Fault{After: 25}, 25, syscall.EIO},
+		{"own error", Fault{After: 40, Err: io.ErrUnexpectedEOF}, 40, io.ErrUnexpectedEOF},
+		{"fails at once", Fault{Err: fs.ErrPermission}, 0, fs.ErrPermission},
+		{"short reads", Fault{Max: 3}, 100, nil},
+		{"short reads, then EIO", Fault{Max: 7, After: 50}, 50, syscall.EIO},
+		{"after the end", Fault{After: 500}, 100, nil},
+	} {
+		got, err := io.ReadAll(Reader(strings.NewReader(data), tc.fault))
+		if len(got) != tc.want || !errors.Is(err, tc.err) || data[:len(got)] != string(got) {
+			t.Errorf("%s: read %d bytes, %v; want %d, %v", tc.name, len(got), err, tc.want, tc.err)
+		}
+	}
+
+	r := Reader(strings.NewReader(data), Fault{Max: 4})
+	p := make([]byte, 10)
+	if n, _ := r.Read(p); n != 4 {
+		t.Errorf("Read with Max 4 = %d bytes", n)
+	}
+	if n, err := io.ReadFull(r, p); n != 10 || err != nil {
+		t.Errorf("io.ReadFull over short reads = %d, %v", n, err)
+	}
+}
+
+func TestReaderDelay(t *testing.T) {
+	start := time.Now()
+	io.ReadAll(Reader(strings.NewReader("abcdef"), Fault{Max: 2, Delay: 10 * time.Millisecond}))
+	// Three reads of 2 bytes and the one that sees EOF.
+	if took := time.Since(start); took < 40*time.Millisecond {
+		t.Errorf("4 delayed reads took %v", took)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	data := []byte(strings.Repeat("x", 100))
+	for _, tc := range []struct {
+		name  string
+		fault Fault
+		want  int
+		err   error
+	}{
+		{"zero fault", Fault{}, 100, nil},
+		{"EIO after 30", Fault{After: 30}, 30, syscall.EIO},
+		{"disk full", Fault{After: 64, Err: syscall.ENOSPC}, 64, syscall.ENOSPC},
+		{"honest short writes", Fault{Max: 10}, 10, io.ErrShortWrite},
+		{"lying short writes", Fault{Max: 10, Short: true}, 10, io.ErrShortWrite}, // io.Copy notices
+	} {
+		var buf bytes.Buffer
+		n, err := io.Copy(Writer(&buf, tc.fault), bytes.NewReader(data))
+		if n != int64(tc.want) || buf.Len() != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("%s: copied %d (%d in buf), %v; want %d, %v", tc.name, n, buf.Len(), err, tc.want, tc.err)
+		}
+	}
+
+	// The lie itself: a nil error with a count short of len(p).
+	var buf bytes.Buffer
+	if n, err := Writer(&buf, Fault{Max: 10, Short: true}).Write(data); n != 10 || err != nil {
+		t.Errorf("Write with Short = %d, %v; want 10, nil", n, err)
+	}
+	bw := bufio.NewWriterSize(Writer(io.Discard, Fault{Max: 10, Short: true}), 64)
+	bw.Write(data[:50])
+	if err := bw.Flush(); !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("bufio.Writer.Flush = %v, want io.ErrShortWrite", err)
+	}
+
+	// Exactly After bytes succeed; the next byte fails.
+	w := Writer(io.Discard, Fault{After: 10})
+	if n, err := w.Write(data[:10]); n != 10 || err != nil {
+		t.Errorf("first 10 bytes: %d, %v", n, err)
+	}
+	if n, err := w.Write(data[:1]); n != 0 || !errors.Is(err, syscall.EIO) {
+		t.Errorf("byte 11: %d, %v; want EIO", n, err)
+	}
+}
+
+func TestFS(t *testing.T) {
+	fsys := NewFS(fstest.MapFS{
+		"notes.txt":       {Data: []byte("hello")},
+		"app.log":         {Data: []byte(strings.Repeat("log line\n", 10))},
+		"secrets/key.pem": {Data: []byte("-----BEGIN")},
+		"logs/old.log":    {Data: []byte("old")},
+	})
+	fsys.Fail("secrets/*", fs.ErrPermission)
+	fsys.Inject("*.log", Fault{After: 20})
+	fsys.Inject("logs", Fault{After: 1}) // A directory: never wrapped
+
+	if data, err := fs.ReadFile(fsys, "notes.txt"); string(data) != "hello" || err != nil {
+		t.Errorf("notes.txt: %q, %v", data, err)
+	}
+	_, err := fs.ReadFile(fsys, "secrets/key.pem")
+	var pe *fs.PathError
+	if !errors.As(err, &pe) || pe.Path != "secrets/key.pem" || !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("secrets/key.pem: %v, want an *fs.PathError for ErrPermission", err)
+	}
+	for i := range 2 { // Each Open starts again
+		if data, err := fs.ReadFile(fsys, "app.log"); len(data) != 20 || !errors.Is(err, syscall.EIO) {
+			t.Errorf("app.log, open %d: %d bytes, %v; want 20 and EIO", i+1, len(data), err)
+		}
+	}
+	if data, err := fs.ReadFile(fsys, "logs/old.log"); string(data) != "old" || err != nil {
+		t.Errorf("logs/old.log (no pattern matches it): %q, %v", data, err)
+	}
+
+	var seen []string
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		seen = append(seen, p)
+		return nil
+	})
+	if err != nil || len(seen) != 7 {
+		t.Errorf("WalkDir: %v, %v", seen, err)
+	}
+}
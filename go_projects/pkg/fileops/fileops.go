@@ -0,0 +1,129 @@
+// Package fileops routes a tool's filesystem changes through one small
+// type, so that a dry run is a field, not a flag check sprinkled through
+// the code (Topic 174):
+//
+//	ops := fileops.Ops{DryRun: *dryRun, Log: os.Stdout}
+//	ops.RemoveAll(filepath.Join(dir, "build")) // "would remove -r build (3 files, 3072 B)"
+//
+// Ops is the single place where changes happen: with DryRun set, each
+// method checks what it can (does the path exist? what would be
+// deleted?) and logs "would ..." instead of touching the disk. Reads are
+// never routed through Ops: a dry run must see the same tree a real run
+// would.
+//
+// SafeRemoveAll puts rails around RemoveAll for paths that come from a
+// config file or a computation: inside an allow-listed root only, never
+// "/" or the home directory, symlinks resolved first, and optionally a
+// person confirms. 155's janitor and 200's delta sync delete through it.
+package fileops
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Ops makes filesystem changes, or with DryRun set, describes them.
+type Ops struct {
+	DryRun bool
+	Log    io.Writer // Every change (or planned change) is described here; nil = silent
+}
+
+func (o Ops) logf(format string, args ...any) {
+	if o.Log == nil {
+		return
+	}
+	if o.DryRun {
+		format = "would " + format
+	}
+	fmt.Fprintf(o.Log, format+"\n", args...)
+}
+
+func (o Ops) MkdirAll(path string, perm fs.FileMode) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil // Nothing to do, nothing to report
+	}
+	o.logf("mkdir %s", path)
+	if o.DryRun {
+		return nil
+	}
+	return os.MkdirAll(path, perm)
+}
+
+func (o Ops) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	verb := "create"
+	if _, err := os.Stat(name); err == nil {
+		verb = "overwrite"
+	}
+	o.logf("%s %s (%d B)", verb, name, len(data))
+	if o.DryRun {
+		return nil
+	}
+	return os.WriteFile(name, data, perm)
+}
+
+func (o Ops) Rename(oldpath, newpath string) error {
+	if o.DryRun {
+		if _, err := os.Lstat(oldpath); err != nil {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+		}
+	}
+	o.logf("rename %s → %s", oldpath, newpath)
+	if o.DryRun {
+		return nil
+	}
+	return os.Rename(oldpath, newpath)
+}
+
+// Remove deletes one file or empty directory. A dry run reports the same
+// "does not exist" error the real call would.
+func (o Ops) Remove(name string) error {
+	if o.DryRun {
+		if _, err := os.Lstat(name); err != nil {
+			return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	o.logf("remove %s", name)
+	if o.DryRun {
+		return nil
+	}
+	return os.Remove(name)
+}
+
+// RemoveAll is the call that deserves a dry run most: one wrong path and a
+// whole tree is gone. It always reports how much is at stake, and in dry-run
+// mode that report is all it does. Like os.RemoveAll, a missing path is not
+// an error.
+func (o Ops) RemoveAll(path string) error {
+	files, bytes := treeSize(path)
+	if files < 0 {
+		return nil
+	}
+	o.logf("remove -r %s (%d files, %d B)", path, files, bytes)
+	if o.DryRun {
+		return nil
+	}
+	return os.RemoveAll(path)
+}
+
+// treeSize counts the regular files under path and their total size
+// (files = -1 if path does not exist). Symlinks are counted, not followed:
+// RemoveAll deletes the link, never its target.
+func treeSize(path string) (files int, bytes int64) {
+	if _, err := os.Lstat(path); err != nil {
+		return -1, 0
+	}
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		files++
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			bytes += info.Size()
+		}
+		return nil
+	})
+	return files, bytes
+}
@@ -0,0 +1,136 @@
+package fileops
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tree creates a small project under a fresh temp dir.
+func tree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"build/app": "binary", "build/obj/a.o": "obj", "cache.tmp": "x", "notes.txt": "notes",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDryRunWritesNothing(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(o Ops, dir string) error
+		want string // Expected log line, minus the directory
+	}{
+		{"MkdirAll", func(o Ops, dir string) error { return o.MkdirAll(filepath.Join(dir, "a", "b"), 0o755) }, "would mkdir a/b"},
+		{"WriteFile new", func(o Ops, dir string) error { return o.WriteFile(filepath.Join(dir, "new"), []byte("hi"), 0o644) }, "would create new (2 B)"},
+		{"WriteFile existing", func(o Ops, dir string) error { return o.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644) }, "would overwrite notes.txt (0 B)"},
+		{"Rename", func(o Ops, dir string) error {
+			return o.Rename(filepath.Join(dir, "notes.txt"), filepath.Join(dir, "n"))
+		}, "would rename notes.txt → n"},
+		{"Remove", func(o Ops, dir string) error { return o.Remove(filepath.Join(dir, "cache.tmp")) }, "would remove cache.tmp"},
+		{"RemoveAll", func(o Ops, dir string) error { return o.RemoveAll(filepath.Join(dir, "build")) }, "would remove -r build (2 files, 9 B)"},
+		{"RemoveAll root", func(o Ops, dir string) error { return o.RemoveAll(dir) }, "(4 files, 15 B)"},
+		{"clean job", clean, "would remove -r build"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tree(t)
+			before := snapshot(dir)
+			var log strings.Builder
+			if err := tt.op(Ops{DryRun: true, Log: &log}, dir); err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+			if after := snapshot(dir); !maps.Equal(before, after) {
+				t.Errorf("dry run changed the tree:\nbefore %v\nafter  %v", before, after)
+			}
+			got := strings.ReplaceAll(filepath.ToSlash(log.String()), filepath.ToSlash(dir)+"/", "")
+			got = strings.ReplaceAll(got, filepath.ToSlash(dir), "")
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("log = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// clean is a typical tidy-up job written once against Ops, as a tool
+// would: the dry-run tests run it both ways.
+func clean(ops Ops, dir string) error {
+	if err := SafeRemoveAll(filepath.Join(dir, "build"), SafeOptions{Ops: ops, Root: dir}); err != nil {
+		return err
+	}
+	tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	for _, t := range tmps {
+		if err := ops.Remove(t); err != nil {
+			return err
+		}
+	}
+	if err := ops.MkdirAll(filepath.Join(dir, "archive"), 0o755); err != nil {
+		return err
+	}
+	if err := ops.Rename(filepath.Join(dir, "notes.txt"), filepath.Join(dir, "archive", "notes.txt")); err != nil {
+		return err
+	}
+	return ops.WriteFile(filepath.Join(dir, "archive", "README"), []byte("old notes\n"), 0o644)
+}
+
+// snapshot records every path under root with its size and mtime.
+func snapshot(root string) map[string]string {
+	snap := map[string]string{}
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		info, _ := d.Info()
+		snap[filepath.ToSlash(rel)] = fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return snap
+}
+
+// The real run must do what the dry run described, line for line.
+func TestDryRunMatchesRealRun(t *testing.T) {
+	dir := tree(t)
+	var plan, done strings.Builder
+	if err := clean(Ops{DryRun: true, Log: &plan}, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := clean(Ops{Log: &done}, dir); err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.ReplaceAll(plan.String(), "would ", ""); done.String() != want {
+		t.Errorf("real run:\n%s\nplan:\n%s", done.String(), want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("build still exists after the real run: %v", err)
+	}
+}
+
+func TestDryRunReportsErrors(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	o := Ops{DryRun: true}
+	if err := o.Remove(missing); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove(missing) = %v, want ErrNotExist", err)
+	}
+	if err := o.Rename(missing, missing+"2"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Rename(missing) = %v, want ErrNotExist", err)
+	}
+	if err := o.RemoveAll(missing); err != nil {
+		t.Errorf("RemoveAll(missing) = %v, want nil like os.RemoveAll", err)
+	}
+}
@@ -0,0 +1,105 @@
+package fileops
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A dry run helps when someone looks at it. SafeRemoveAll also protects the
+// runs nobody watches: a path computed from an empty variable ("" + "/"), a
+// config file that says "dir": "~", a symlink that points out of the sandbox.
+//
+//  1. The path must be strictly INSIDE an allow-listed root.
+//  2. "/" (or a volume root), the home directory and anything above home
+//     are refused even if the root would allow them.
+//  3. Symlinks in the path are resolved before those checks, so root/link/x
+//     with link → /etc is judged as /etc/x.
+//  4. Optionally, a person confirms the exact path and how much goes with it.
+
+// The refusals SafeRemoveAll's errors wrap.
+var (
+	ErrOutsideRoot  = errors.New("outside the allowed root")
+	ErrProtected    = errors.New("protected path")
+	ErrNotConfirmed = errors.New("not confirmed")
+)
+
+// SafeOptions configures SafeRemoveAll.
+type SafeOptions struct {
+	Ops                                // DryRun and Log, as for every other change
+	Root    string                     // Only paths strictly inside Root may go; required
+	Confirm func(question string) bool // Asked before deleting; nil = no question
+}
+
+// SafeRemoveAll is os.RemoveAll behind the rails above. Refusals are
+// *fs.PathError values wrapping ErrOutsideRoot, ErrProtected or
+// ErrNotConfirmed, so callers can tell them apart with errors.Is.
+func SafeRemoveAll(path string, opts SafeOptions) error {
+	refuse := func(err error) error { return &fs.PathError{Op: "safe remove", Path: path, Err: err} }
+	if path == "" || opts.Root == "" {
+		return refuse(ErrProtected) // An empty path is a bug upstream, not "the current directory"
+	}
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil // Like os.RemoveAll: nothing to do
+	}
+	target, err := resolve(path)
+	if err != nil {
+		return refuse(err)
+	}
+	if protected(target) {
+		return refuse(ErrProtected)
+	}
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return refuse(err)
+	}
+	// Unlike the target, the root is resolved all the way: a root that is
+	// itself a symlink stands for the directory it points to.
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return refuse(err)
+	}
+	if rel, err := filepath.Rel(root, target); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return refuse(fmt.Errorf("%w %s", ErrOutsideRoot, root))
+	}
+	if opts.Confirm != nil && !opts.DryRun {
+		files, bytes := treeSize(path)
+		if !opts.Confirm(fmt.Sprintf("remove -r %s (%d files, %d B)?", path, files, bytes)) {
+			return refuse(ErrNotConfirmed)
+		}
+	}
+	return opts.Ops.RemoveAll(path)
+}
+
+// resolve makes p absolute and resolves symlinks in every component but the
+// last: RemoveAll deletes a final symlink itself, never what it points to.
+func resolve(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	dir, base := filepath.Split(abs)
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, base), nil
+}
+
+// protected reports whether p is a filesystem root, the home directory or
+// one of its ancestors ("/home", "/Users").
+func protected(p string) bool {
+	if p == filepath.VolumeName(p)+string(filepath.Separator) {
+		return true
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	if h, err := filepath.EvalSymlinks(home); err == nil {
+		home = h
+	}
+	rel, err := filepath.Rel(p, home)
+	return err == nil && (rel == "." || !strings.HasPrefix(rel, ".."))
+}
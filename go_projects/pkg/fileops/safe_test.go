@@ -0,0 +1,149 @@
+package fileops
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/prompt"
+)
+
+func TestSafeRemoveAllRefuses(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "x"), []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skip("no symlinks here:", err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory:", err)
+	}
+
+	tests := []struct {
+		name, path, root string
+		want             error
+	}{
+		{"empty path", "", root, ErrProtected},
+		{"empty root", filepath.Join(root, "escape"), "", ErrProtected},
+		{"filesystem root", "/", "/", ErrProtected},
+		{"home", home, filepath.Dir(home), ErrProtected},
+		{"above home", filepath.Dir(home), "/", ErrProtected},
+		{"the root itself", root, root, ErrOutsideRoot},
+		{"sibling", outside, root, ErrOutsideRoot},
+		{"dot-dot", filepath.Join(root, "..", filepath.Base(outside)), root, ErrOutsideRoot},
+		{"through a symlink", filepath.Join(root, "escape", "x"), root, ErrOutsideRoot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SafeRemoveAll(tt.path, SafeOptions{Root: tt.root}); !errors.Is(err, tt.want) {
+				t.Errorf("SafeRemoveAll(%q, root %q) = %v, want %v", tt.path, tt.root, err, tt.want)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(outside, "x")); err != nil {
+		t.Errorf("file outside the root was removed: %v", err)
+	}
+}
+
+func TestSafeRemoveAllInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "build")
+	os.MkdirAll(filepath.Join(target, "obj"), 0o755)
+	os.WriteFile(filepath.Join(target, "obj", "a.o"), []byte("obj"), 0o644)
+	link := filepath.Join(root, "link")
+	os.Symlink(t.TempDir(), link)
+
+	// A final symlink is removed itself, never followed.
+	for _, p := range []string{target, link} {
+		if err := SafeRemoveAll(p, SafeOptions{Root: root}); err != nil {
+			t.Fatalf("SafeRemoveAll(%q) = %v", p, err)
+		}
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("%s still exists: %v", p, err)
+		}
+	}
+	if err := SafeRemoveAll(target, SafeOptions{Root: root}); err != nil {
+		t.Errorf("missing path: %v, want nil like os.RemoveAll", err)
+	}
+}
+
+func TestSafeRemoveAllSymlinkedRoot(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(t.TempDir(), "work")
+	if err := os.Symlink(dir, root); err != nil {
+		t.Skip("no symlinks here:", err)
+	}
+	// The same directory, named through the link and by its real path.
+	for _, p := range []string{filepath.Join(root, "a"), filepath.Join(dir, "b")} {
+		if err := os.Mkdir(p, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := SafeRemoveAll(p, SafeOptions{Root: root}); err != nil {
+			t.Fatalf("SafeRemoveAll(%q, root %q) = %v", p, root, err)
+		}
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("%s still exists: %v", p, err)
+		}
+	}
+	if err := SafeRemoveAll(root, SafeOptions{Root: root}); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("SafeRemoveAll(root) = %v, want %v", err, ErrOutsideRoot)
+	}
+}
+
+func TestSafeRemoveAllConfirm(t *testing.T) {
+	tests := []struct {
+		answer string
+		gone   bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false}, // EOF: nobody is there to say yes
+		{"yep\n", false},
+	}
+	for _, tt := range tests {
+		root := t.TempDir()
+		target := filepath.Join(root, "build")
+		os.MkdirAll(target, 0o755)
+		var out strings.Builder
+		err := SafeRemoveAll(target, SafeOptions{Root: root, Confirm: prompt.New(strings.NewReader(tt.answer), &out).YesNo})
+		_, statErr := os.Stat(target)
+		if gone := os.IsNotExist(statErr); gone != tt.gone {
+			t.Errorf("answer %q: removed = %v, want %v", tt.answer, gone, tt.gone)
+		}
+		if !tt.gone && !errors.Is(err, ErrNotConfirmed) {
+			t.Errorf("answer %q: err = %v, want ErrNotConfirmed", tt.answer, err)
+		}
+		if !strings.Contains(out.String(), "[y/N]") {
+			t.Errorf("prompt = %q, want a [y/N] question", out.String())
+		}
+	}
+}
+
+// A dry run never asks and never deletes.
+func TestSafeRemoveAllDryRun(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "build")
+	os.MkdirAll(target, 0o755)
+	asked := false
+	var log strings.Builder
+	err := SafeRemoveAll(target, SafeOptions{
+		Ops:     Ops{DryRun: true, Log: &log},
+		Root:    root,
+		Confirm: func(string) bool { asked = true; return true },
+	})
+	if err != nil || asked {
+		t.Fatalf("err = %v, asked = %v", err, asked)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Errorf("dry run removed %s", target)
+	}
+	if !strings.HasPrefix(log.String(), "would remove -r ") {
+		t.Errorf("log = %q", log.String())
+	}
+}
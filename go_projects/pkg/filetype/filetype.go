@@ -0,0 +1,102 @@
+// Package filetype recognizes common file formats by their magic number,
+// the fixed bytes a format puts at the start of every file (see Topic 187):
+//
+//	t, r, err := filetype.Detect(upload)
+//	if t != filetype.PNG { ... reject ... }
+//	io.Copy(dst, r) // r still yields every byte, the header included
+//
+// Only the first bytes are checked. A match says what a file claims to be,
+// not that the rest of it is valid.
+package filetype
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path"
+	"strings"
+)
+
+// Type is a recognized format. The zero Type is Unknown.
+type Type struct {
+	Name string // "PNG"
+	MIME string // "image/png"
+	Ext  string // ".png"; empty for formats without one, like ELF
+}
+
+var (
+	Unknown Type
+	PNG     = Type{"PNG", "image/png", ".png"}
+	JPEG    = Type{"JPEG", "image/jpeg", ".jpg"}
+	GZIP    = Type{"GZIP", "application/gzip", ".gz"}
+	ZIP     = Type{"ZIP", "application/zip", ".zip"}
+	PDF     = Type{"PDF", "application/pdf", ".pdf"}
+	ELF     = Type{"ELF", "application/x-executable", ""}
+)
+
+func (t Type) String() string {
+	if t == Unknown {
+		return "unknown"
+	}
+	return t.Name
+}
+
+// signatures are checked in order; the first whose magic starts the
+// data wins.
+var signatures = []struct {
+	t     Type
+	magic string
+}{
+	{PNG, "\x89PNG\r\n\x1a\n"},
+	{JPEG, "\xff\xd8\xff"},
+	{GZIP, "\x1f\x8b"},
+	{ZIP, "PK\x03\x04"}, // A local file header: any archive with a file in it
+	{ZIP, "PK\x05\x06"}, // The end record alone: an empty archive
+	{PDF, "%PDF-"},
+	{ELF, "\x7fELF"},
+}
+
+// HeaderLen is how many leading bytes Match needs to see.
+const HeaderLen = 8
+
+// Match returns the type whose magic number head starts with.
+func Match(head []byte) Type {
+	for _, s := range signatures {
+		if bytes.HasPrefix(head, []byte(s.magic)) {
+			return s.t
+		}
+	}
+	return Unknown
+}
+
+// Detect peeks at the first HeaderLen bytes of r without consuming them.
+// Read the rest from the returned reader, never from r: it is r itself
+// if r was a *bufio.Reader, and a bufio.Reader around r otherwise, which
+// holds the peeked bytes. A stream shorter than HeaderLen is fine.
+func Detect(r io.Reader) (Type, io.Reader, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	head, err := br.Peek(HeaderLen)
+	if err != nil && err != io.EOF {
+		return Unknown, br, err
+	}
+	return Match(head), br, nil
+}
+
+// extensions maps a file name extension to the format it promises.
+// Office documents, JARs and APKs are ZIP archives inside.
+var extensions = map[string]Type{
+	".png": PNG, ".jpg": JPEG, ".jpeg": JPEG,
+	".gz": GZIP, ".tgz": GZIP,
+	".zip": ZIP, ".docx": ZIP, ".xlsx": ZIP, ".pptx": ZIP, ".jar": ZIP, ".apk": ZIP,
+	".pdf": PDF,
+}
+
+// ByExt returns the type a file name's extension promises, and false for
+// extensions this package doesn't know.
+func ByExt(name string) (Type, bool) {
+	t, ok := extensions[strings.ToLower(path.Ext(name))]
+	return t, ok
+}
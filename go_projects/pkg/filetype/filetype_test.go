@@ -0,0 +1,91 @@
+package filetype
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		head string
+		want Type
+	}{
+		{"\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", PNG},
+		{"\xff\xd8\xff\xe0\x00\x10JFIF", JPEG},
+		{"\x1f\x8b\x08\x00", GZIP},
+		{"PK\x03\x04\x14\x00", ZIP},
+		{"PK\x05\x06" + strings.Repeat("\x00", 18), ZIP},
+		{"%PDF-1.7\n", PDF},
+		{"\x7fELF\x02\x01\x01", ELF},
+		{"\x89PNG", Unknown}, // Half a signature
+		{"hello, world", Unknown},
+		{"", Unknown},
+	} {
+		if got := Match([]byte(tc.head)); got != tc.want {
+			t.Errorf("Match(%q) = %v, want %v", tc.head, got, tc.want)
+		}
+	}
+}
+
+func TestDetectKeepsEveryByte(t *testing.T) {
+	data := "%PDF-1.4\n%âãÏÓ\n1 0 obj\n"
+	for name, r := range map[string]io.Reader{
+		"plain reader":  strings.NewReader(data),
+		"one byte read": iotest.OneByteReader(strings.NewReader(data)),
+		"bufio.Reader":  bufio.NewReader(strings.NewReader(data)),
+	} {
+		typ, rest, err := Detect(r)
+		if err != nil || typ != PDF {
+			t.Errorf("%s: Detect = %v, %v; want PDF", name, typ, err)
+			continue
+		}
+		if got, _ := io.ReadAll(rest); string(got) != data {
+			t.Errorf("%s: read %q after Detect, want all of %q", name, got, data)
+		}
+	}
+}
+
+func TestDetectReusesBufioReader(t *testing.T) {
+	br := bufio.NewReader(strings.NewReader("\x1f\x8b"))
+	if _, rest, _ := Detect(br); rest != br {
+		t.Error("Detect wrapped a *bufio.Reader again")
+	}
+}
+
+func TestDetectShortAndFailingStreams(t *testing.T) {
+	typ, rest, err := Detect(strings.NewReader("\x1f\x8b"))
+	if err != nil || typ != GZIP {
+		t.Errorf("2-byte stream: %v, %v; want GZIP, nil", typ, err)
+	}
+	if got, _ := io.ReadAll(rest); !bytes.Equal(got, []byte("\x1f\x8b")) {
+		t.Errorf("2-byte stream: read back %q", got)
+	}
+
+	boom := errors.New("disk on fire")
+	if _, _, err := Detect(iotest.ErrReader(boom)); !errors.Is(err, boom) {
+		t.Errorf("failing reader: err = %v, want %v", err, boom)
+	}
+}
+
+func TestByExt(t *testing.T) {
+	for name, want := range map[string]Type{
+		"cat.PNG":          PNG,
+		"photo.jpeg":       JPEG,
+		"backup.tar.gz":    GZIP,
+		"report.docx":      ZIP,
+		"dir/spec.pdf":     PDF,
+		"notes.txt":        Unknown,
+		"Makefile":         Unknown,
+		"archive.tar.gz.1": Unknown,
+	} {
+		got, ok := ByExt(name)
+		if got != want || ok != (want != Unknown) {
+			t.Errorf("ByExt(%q) = %v, %v; want %v", name, got, ok, want)
+		}
+	}
+}
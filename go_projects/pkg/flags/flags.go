@@ -0,0 +1,149 @@
+// Package flags is feature flags read from a JSON file: boolean flags,
+// and percentage rollouts with sticky per-user buckets (Topic 143):
+//
+//	store, err := flags.NewStore("flags.json")
+//	...
+//	go store.Watch(ctx, time.Second) // Picks up edits to the file
+//	if store.IsEnabled("fancy_renderer", user) {
+//		...
+//	}
+//
+// where flags.json is
+//
+//	{
+//	  "fancy_renderer": {"enabled": true, "rollout": 30},
+//	  "dark_mode":      {"enabled": true}
+//	}
+//
+// A user's bucket is a hash of the flag's name and the user (Topic 82),
+// so raising a rollout from 10% to 30% keeps the first 10% and adds to
+// them, and different flags pick different users. An unknown flag is
+// off. Readers never lock: a reload swaps in a new Set, and a file that
+// doesn't parse is rejected while the previous flags stay in force.
+package flags
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Flag is one entry of the file. A nil Rollout is a boolean flag.
+type Flag struct {
+	Enabled bool `json:"enabled"`
+	Rollout *int `json:"rollout,omitempty"` // 0..100 percent of users
+}
+
+// Set is a snapshot of the flags, never modified once loaded.
+type Set map[string]Flag
+
+// Bucket maps a flag and a user to a stable number in [0, 100).
+func Bucket(flag, user string) int {
+	sum := sha256.Sum256([]byte(flag + ":" + user))
+	return int(binary.BigEndian.Uint64(sum[:8]) % 100)
+}
+
+// IsEnabled reports whether the flag name is on for user.
+func (s Set) IsEnabled(name, user string) bool {
+	f, ok := s[name]
+	if !ok || !f.Enabled {
+		return false // Unknown flags are off: the safe default
+	}
+	if f.Rollout == nil {
+		return true
+	}
+	return Bucket(name, user) < *f.Rollout
+}
+
+// Parse reads a flags file's contents, rejecting a rollout outside 0..100.
+func Parse(data []byte) (Set, error) {
+	var s Set
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+	for name, f := range s {
+		if f.Rollout != nil && (*f.Rollout < 0 || *f.Rollout > 100) {
+			return nil, fmt.Errorf("flag %q: rollout %d is outside 0..100", name, *f.Rollout)
+		}
+	}
+	return s, nil
+}
+
+// Store holds the flags loaded from a file, and Watch reloads them when
+// the file changes.
+type Store struct {
+	OnEvent func(msg string) // Told of each reload and each rejected file; nil is silent
+
+	path    string
+	current atomic.Pointer[Set]
+	lastMod time.Time // Modification time of the file last read
+}
+
+// NewStore loads the flags in path.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// IsEnabled reports whether the flag name is on for user, by the flags
+// loaded last.
+func (s *Store) IsEnabled(name, user string) bool {
+	return s.current.Load().IsEnabled(name, user)
+}
+
+func (s *Store) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	s.lastMod = info.ModTime() // Even a rejected file counts as seen
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	set, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	s.current.Store(&set)
+	return nil
+}
+
+// Watch checks the file's modification time every interval, reloading
+// it when it changes, until ctx is done. Polling works the same on every
+// platform; the OS's notification APIs are faster, but each is its own.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(s.path)
+			if err != nil || info.ModTime().Equal(s.lastMod) {
+				continue
+			}
+			if err := s.reload(); err != nil {
+				s.event("reload rejected, keeping previous flags: " + err.Error())
+				continue
+			}
+			s.event("flags reloaded")
+		}
+	}
+}
+
+func (s *Store) event(msg string) {
+	if s.OnEvent != nil {
+		s.OnEvent(msg)
+	}
+}
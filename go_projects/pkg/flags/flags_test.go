@@ -0,0 +1,95 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsEnabled(t *testing.T) {
+	thirty := 30
+	set := Set{"on": {Enabled: true}, "off": {}, "rollout": {Enabled: true, Rollout: &thirty}}
+	if !set.IsEnabled("on", "alice") || set.IsEnabled("off", "alice") || set.IsEnabled("unknown", "alice") {
+		t.Error("boolean flags")
+	}
+	on := 0
+	for i := range 10000 {
+		user := fmt.Sprintf("user-%d", i)
+		if set.IsEnabled("rollout", user) {
+			on++
+		}
+		if set.IsEnabled("rollout", user) != (Bucket("rollout", user) < 30) {
+			t.Fatalf("%s isn't decided by their bucket", user)
+		}
+	}
+	if on < 2800 || on > 3200 {
+		t.Errorf("a 30%% rollout enabled %d of 10000 users", on)
+	}
+	if Bucket("rollout", "alice") != Bucket("rollout", "alice") {
+		t.Error("buckets aren't sticky")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for data, want := range map[string]string{
+		`{"a": {"enabled": true, "rollout": 100}}`: "",
+		`{"a": {"enabled": true, "rollout": 101}}`: `flag "a": rollout 101 is outside 0..100`,
+		`{"a": {"enabled": tru`:                    "parse flags:",
+	} {
+		_, err := Parse([]byte(data))
+		if (want == "") != (err == nil) || err != nil && !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("Parse(%s) = %v, want %q", data, err, want)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	mtime := time.Now()
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime = mtime.Add(time.Second) // Two writes in one filesystem tick would look the same
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"f": {"enabled": false}}`)
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan string, 10)
+	store.OnEvent = func(msg string) { events <- msg }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, 5*time.Millisecond)
+
+	for _, step := range []struct {
+		data, event string
+		on          bool
+	}{
+		{`{"f": {"enabled": true}}`, "flags reloaded", true},
+		{`{"f": {"enabled": tru`, "reload rejected, keeping previous flags: parse flags:", true},
+		{`{"f": {"enabled": false}}`, "flags reloaded", false},
+	} {
+		write(step.data)
+		select {
+		case msg := <-events:
+			if !strings.HasPrefix(msg, step.event) {
+				t.Errorf("after %s: %q, want %q", step.data, msg, step.event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no reload after %s", step.data)
+		}
+		if store.IsEnabled("f", "alice") != step.on {
+			t.Errorf("after %s: f is %v", step.data, !step.on)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/akarsh323/Go-tutorials-/go_projects/pkg
+
+go 1.25
@@ -0,0 +1,139 @@
+// Package health answers an orchestrator's two questions, liveness and
+// readiness, from checks that subsystems register (Topic 142):
+//
+//	checks := &health.Registry{}
+//	checks.Register("database", health.Readiness, 200*time.Millisecond, db.Ping)
+//	mux.Handle("GET /healthz", checks.Handler(health.Liveness))
+//	mux.Handle("GET /readyz", checks.Handler(health.Readiness))
+//
+// Liveness is "restart me?", so only what a restart would fix belongs
+// there; a dependency belongs in readiness, "send me traffic?". A handler
+// runs its kind's checks concurrently, each bounded by its own timeout,
+// and answers 200 or 503 with a JSON breakdown. With no checks of a kind,
+// the answer is ok: a process that can serve the request is alive.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Kind says which question a check answers.
+type Kind int
+
+const (
+	Liveness  Kind = iota // Would a restart fix it? /healthz
+	Readiness             // Can it take traffic now? /readyz
+)
+
+// CheckFunc returns nil when healthy. It should return when ctx is done;
+// one that doesn't is reported as timed out all the same.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name    string
+	kind    Kind
+	timeout time.Duration
+	fn      CheckFunc
+}
+
+// Registry holds the checks. The zero value is ready to use, and it is
+// safe to register while handlers run.
+type Registry struct {
+	mu     sync.RWMutex
+	checks []check
+}
+
+// Register adds a check named name, of kind, that fails if it takes
+// longer than timeout.
+func (r *Registry) Register(name string, kind Kind, timeout time.Duration, fn CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check{name: name, kind: kind, timeout: timeout, fn: fn})
+}
+
+// Result is one check's outcome.
+type Result struct {
+	Status     string `json:"status"` // "ok" or "fail"
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is every check of one kind: Status is "fail" if any failed.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Run runs every check of kind concurrently and waits for them all.
+func (r *Registry) Run(ctx context.Context, kind Kind) Report {
+	r.mu.RLock()
+	var selected []check
+	for _, c := range r.checks {
+		if c.kind == kind {
+			selected = append(selected, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: "ok", Checks: make(map[string]Result, len(selected))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range selected {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := runOne(ctx, c)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.name] = res
+			if res.Status != "ok" {
+				report.Status = "fail"
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// runOne enforces the timeout even if the check ignores ctx: the result is
+// taken from whichever finishes first, the check or the deadline.
+func runOne(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1) // Buffered: a late check can still send and exit
+	go func() { done <- c.fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %v", c.timeout)
+	}
+
+	res := Result{Status: "ok", DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		res.Status, res.Error = "fail", err.Error()
+	}
+	return res
+}
+
+// Handler serves one kind's report as JSON: 200 when healthy, 503
+// otherwise.
+func (r *Registry) Handler(kind Kind) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Run(req.Context(), kind)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store") // Every probe wants the answer now
+		if report.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report) // The status is sent; a failed write has no one to tell
+	})
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	r := &Registry{}
+	r.Register("loop", Liveness, time.Second, func(context.Context) error { return nil })
+	r.Register("db", Readiness, time.Second, func(context.Context) error { return errors.New("connection refused") })
+	r.Register("cache", Readiness, time.Second, func(context.Context) error { return nil })
+
+	live := r.Run(context.Background(), Liveness)
+	if live.Status != "ok" || len(live.Checks) != 1 || live.Checks["loop"].Status != "ok" {
+		t.Errorf("liveness = %+v", live)
+	}
+	ready := r.Run(context.Background(), Readiness)
+	if ready.Status != "fail" || ready.Checks["db"].Error != "connection refused" || ready.Checks["cache"].Status != "ok" {
+		t.Errorf("readiness = %+v", ready)
+	}
+	if empty := (&Registry{}).Run(context.Background(), Readiness); empty.Status != "ok" {
+		t.Errorf("no checks = %+v, want ok", empty)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r := &Registry{}
+	stuck := make(chan struct{})
+	defer close(stuck)
+	r.Register("stuck", Readiness, 20*time.Millisecond, func(context.Context) error {
+		<-stuck // Ignores ctx: the registry mustn't wait for it anyway
+		return nil
+	})
+	start := time.Now()
+	report := r.Run(context.Background(), Readiness)
+	if report.Status != "fail" || report.Checks["stuck"].Error != "timed out after 20ms" {
+		t.Errorf("report = %+v", report)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Run waited %v for a check that ignores its context", d)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := &Registry{}
+	var down bool
+	r.Register("db", Readiness, time.Second, func(context.Context) error {
+		if down {
+			return errors.New("down")
+		}
+		return nil
+	})
+	for _, tt := range []struct {
+		down bool
+		code int
+	}{{false, http.StatusOK}, {true, http.StatusServiceUnavailable}} {
+		down = tt.down
+		rec := httptest.NewRecorder()
+		r.Handler(Readiness).ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		var report Report
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != tt.code || rec.Header().Get("Content-Type") != "application/json" || report.Checks["db"].Status == "" {
+			t.Errorf("down=%v: %d %s %s", tt.down, rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package kata is a set of short, timed coding challenges graded by tests
+// the learner never sees (see Topic 192):
+//
+//	k, _ := kata.Get("dedupe")
+//	path, _ := k.Start("katas")          // katas/dedupe/kata.go, to edit
+//	src, _ := os.ReadFile(path)
+//	res, _ := k.Grade(ctx, src)           // the hidden tests, in a scratch dir
+//	fmt.Println(k.Verdict(res, elapsed))  // pass, late or fail
+//
+// Each kata is three embedded files under katas/NAME: the starter the
+// learner gets, the hidden tests, and a reference solution the package's
+// own tests grade, so a kata can't ship unsolvable. The files end in .txt
+// so the go tool doesn't build them as part of this package.
+//
+// "Hidden" means not copied into the learner's workspace. Anyone who goes
+// looking can read them here; the point is to solve from the prompt, as
+// with an interview question, not to keep a secret.
+package kata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//go:embed katas
+var files embed.FS
+
+// Kata is one challenge.
+type Kata struct {
+	Name   string        // "dedupe": the directory under katas/, and the command's argument
+	Title  string        // "Dedupe a slice"
+	Prompt string        // What to write, in a sentence or two
+	Limit  time.Duration // Time to solve it in, from Start to a passing Grade
+}
+
+// File is the name of the file the learner edits, in every kata.
+const File = "kata.go"
+
+var katas = []Kata{
+	{
+		Name:   "trimprefixfold",
+		Title:  "TrimPrefixFold",
+		Prompt: "strings.TrimPrefix, but matching the prefix case-insensitively, the way strings.EqualFold compares.",
+		Limit:  10 * time.Minute,
+	},
+	{
+		Name:   "parseduration",
+		Title:  "Parse a duration string",
+		Prompt: `Parse "1h30m", "90s" or "250ms" into a time.Duration, without time.ParseDuration. Reject anything else.`,
+		Limit:  20 * time.Minute,
+	},
+	{
+		Name:   "dedupe",
+		Title:  "Dedupe a slice",
+		Prompt: "Remove repeats from a slice of any comparable type, keeping the first of each in order.",
+		Limit:  5 * time.Minute,
+	},
+}
+
+// All returns every kata, in the order "gotut kata" lists them.
+func All() []Kata { return append([]Kata(nil), katas...) }
+
+// Get returns the kata called name.
+func Get(name string) (Kata, bool) {
+	for _, k := range katas {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Kata{}, false
+}
+
+func (k Kata) file(name string) []byte {
+	data, err := files.ReadFile("katas/" + k.Name + "/" + name)
+	if err != nil {
+		panic(fmt.Sprintf("kata %s: %v", k.Name, err)) // Embedded: a missing file is a bug in this package
+	}
+	return data
+}
+
+// Starter is the file the learner starts from: the signature, its doc
+// comment, and a body that compiles and is wrong.
+func (k Kata) Starter() []byte { return k.file("starter.go.txt") }
+
+// Solution is the reference answer.
+func (k Kata) Solution() []byte { return k.file("solution.go.txt") }
+
+func (k Kata) tests() []byte { return k.file("hidden_test.go.txt") }
+
+// Start writes the starter to dir/NAME/kata.go and returns its path. An
+// existing file is the learner's work: it is kept, and the error wraps
+// os.ErrExist.
+func (k Kata) Start(dir string) (string, error) {
+	path := filepath.Join(dir, k.Name, File)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return path, err
+	}
+	if _, err := f.Write(k.Starter()); err != nil {
+		f.Close()
+		return path, err
+	}
+	return path, f.Close()
+}
+
+// Test is one hidden test's outcome. Message is the first thing a failing
+// test reported, without the test file's line number: the learner sees
+// what was wrong, not where in a file they don't have.
+type Test struct {
+	Name    string
+	Pass    bool
+	Message string
+}
+
+// Result is a graded attempt. Build holds the compiler's output when
+// kata.go didn't compile, and then there are no Tests.
+type Result struct {
+	Tests []Test
+	Build string
+}
+
+// Passed counts the passing tests.
+func (r Result) Passed() int {
+	n := 0
+	for _, t := range r.Tests {
+		if t.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+// OK reports whether it compiled and every hidden test passed.
+func (r Result) OK() bool { return r.Build == "" && len(r.Tests) > 0 && r.Passed() == len(r.Tests) }
+
+// Verdict is the attempt's result for the progress log: "pass" if every
+// test passed within the time limit, "late" if they passed after it,
+// "fail" otherwise.
+func (k Kata) Verdict(r Result, elapsed time.Duration) string {
+	switch {
+	case !r.OK():
+		return "fail"
+	case elapsed > k.Limit:
+		return "late"
+	}
+	return "pass"
+}
+
+// testEvent is the part of a "go test -json" (test2json) line Grade reads.
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// testLine is a t.Errorf line in the test output: "    kata_test.go:12: msg".
+var testLine = regexp.MustCompile(`^\s+\w+_test\.go:\d+: `)
+
+// Grade copies src and the hidden tests into a scratch module and runs
+// go test there. A test failure is a Result, not an error; the error is
+// for go itself failing, or ctx running out (an endless loop in src).
+func (k Kata) Grade(ctx context.Context, src []byte) (Result, error) {
+	dir, err := os.MkdirTemp("", "kata-"+k.Name+"-")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module kata\n\ngo 1.25\n"), 0o644); err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, File), src, 0o644); err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "kata_test.go"), k.tests(), 0o644); err != nil {
+		return Result{}, err
+	}
+	return RunTests(ctx, dir)
+}
+
+// RunTests runs go test in dir, any package directory with tests, and
+// reads each top-level test's outcome from its -json output; Grade is
+// RunTests on a scratch copy. The errors are Grade's.
+func RunTests(ctx context.Context, dir string) (Result, error) {
+	cmd := exec.CommandContext(ctx, "go", "test", "-json", "-count=1", ".")
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return Result{}, ctx.Err()
+	}
+
+	var res Result
+	var build strings.Builder
+	index := map[string]int{}
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		var e testEvent
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		switch {
+		case e.Action == "build-output" && !strings.HasPrefix(e.Output, "#"):
+			build.WriteString(e.Output)
+		case e.Test == "" || strings.Contains(e.Test, "/"):
+			// The package's own lines, and subtests: the top-level test speaks for them
+		case e.Action == "run":
+			index[e.Test] = len(res.Tests)
+			res.Tests = append(res.Tests, Test{Name: e.Test})
+		case e.Action == "output":
+			if i, ok := index[e.Test]; ok && res.Tests[i].Message == "" && testLine.MatchString(e.Output) {
+				res.Tests[i].Message = strings.TrimSpace(testLine.ReplaceAllString(e.Output, ""))
+			}
+		case e.Action == "pass":
+			if i, ok := index[e.Test]; ok {
+				res.Tests[i].Pass = true
+			}
+		}
+	}
+	res.Build = strings.TrimSpace(build.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil, errors.As(runErr, &exitErr) && (len(res.Tests) > 0 || res.Build != ""):
+		return res, nil
+	case errors.As(runErr, &exitErr):
+		return res, fmt.Errorf("go test: %v: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	return res, fmt.Errorf("go test: %w", runErr)
+}
@@ -0,0 +1,105 @@
+package kata
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSolutions grades every kata's reference solution and its starter:
+// the solution must pass every hidden test, the starter must compile and
+// fail at least one, or the kata is unsolvable or already solved.
+func TestSolutions(t *testing.T) {
+	for _, k := range All() {
+		t.Run(k.Name, func(t *testing.T) {
+			res, err := k.Grade(context.Background(), k.Solution())
+			if err != nil || !res.OK() {
+				t.Fatalf("solution: %+v, %v", res, err)
+			}
+			res, err = k.Grade(context.Background(), k.Starter())
+			if err != nil || res.Build != "" {
+				t.Fatalf("starter: %+v, %v", res, err)
+			}
+			if res.OK() {
+				t.Errorf("the starter passes every hidden test")
+			}
+			for _, test := range res.Tests {
+				if !test.Pass && test.Message == "" {
+					t.Errorf("%s failed without saying why", test.Name)
+				}
+				if strings.Contains(test.Message, "_test.go:") {
+					t.Errorf("%s: message gives away the test file: %q", test.Name, test.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFailure(t *testing.T) {
+	k, _ := Get("dedupe")
+	res, err := k.Grade(context.Background(), []byte("package kata\n\nfunc Dedupe[T comparable](xs []T) []T { return xs + 1 }\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.Build, "kata.go:3") || len(res.Tests) != 0 || res.OK() {
+		t.Errorf("a compile error gave %+v", res)
+	}
+}
+
+func TestGradeTimeout(t *testing.T) {
+	k, _ := Get("dedupe")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := k.Grade(ctx, k.Solution())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Grade past its deadline = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+	k, ok := Get("trimprefixfold")
+	if !ok {
+		t.Fatal("no trimprefixfold kata")
+	}
+	path, err := k.Start(dir)
+	if err != nil || path != filepath.Join(dir, "trimprefixfold", File) {
+		t.Fatalf("Start = %q, %v", path, err)
+	}
+	os.WriteFile(path, []byte("my work"), 0o644)
+	if _, err := k.Start(dir); !errors.Is(err, os.ErrExist) {
+		t.Errorf("a second Start = %v, want os.ErrExist", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "my work" {
+		t.Errorf("a second Start overwrote the learner's file with %q", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Start wrote %d files; the hidden tests must stay hidden", len(entries))
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	k := Kata{Limit: time.Minute}
+	pass := Result{Tests: []Test{{Name: "A", Pass: true}}}
+	fail := Result{Tests: []Test{{Name: "A"}}}
+	for _, tc := range []struct {
+		res     Result
+		elapsed time.Duration
+		want    string
+	}{
+		{pass, 30 * time.Second, "pass"},
+		{pass, 2 * time.Minute, "late"},
+		{fail, 30 * time.Second, "fail"},
+		{Result{}, 0, "fail"}, // No tests ran at all
+		{Result{Build: "x"}, 0, "fail"},
+	} {
+		if got := k.Verdict(tc.res, tc.elapsed); got != tc.want {
+			t.Errorf("Verdict(%+v, %v) = %q, want %q", tc.res, tc.elapsed, got, tc.want)
+		}
+	}
+}
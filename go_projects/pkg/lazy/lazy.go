@@ -0,0 +1,75 @@
+// Package lazy holds values that are computed on first use, exactly once,
+// no matter how many goroutines ask at the same time (see Topic 183).
+//
+// It is a thin layer over sync.OnceValue and sync.OnceValues. What it adds
+// is a name for the pattern and Done, so a caller can tell "not loaded yet"
+// from "loaded" without triggering the load:
+//
+//	var wordRx = lazy.Of(func() *regexp.Regexp {
+//		return regexp.MustCompile(`\w+`)
+//	})
+//	...
+//	wordRx.Get().FindAllString(s, -1)
+//
+// Lessons import it by its module path, through the repository's go.work,
+// like every other package in pkg/.
+package lazy
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Value is a T computed by fn the first time Get is called.
+//
+// If fn panics, every Get panics with the same value and Done stays false:
+// a failed load is never mistaken for a finished one, and it is not retried.
+type Value[T any] struct {
+	get  func() T
+	done atomic.Bool
+}
+
+// Of returns a Value that calls fn at most once.
+func Of[T any](fn func() T) *Value[T] {
+	v := &Value[T]{}
+	v.get = sync.OnceValue(func() T {
+		t := fn()
+		v.done.Store(true)
+		return t
+	})
+	return v
+}
+
+// Get returns the value, calling fn first if no one has yet. Concurrent
+// callers block until the one running fn returns.
+func (v *Value[T]) Get() T { return v.get() }
+
+// Done reports whether fn has returned. It never calls fn.
+func (v *Value[T]) Done() bool { return v.done.Load() }
+
+// Result is Value for loads that can fail, such as reading a config file.
+// The error is cached along with the value: a file that was missing on
+// the first Get stays missing for the life of the process. Code that must
+// pick up a fixed file needs a reload (as in Topics 143 and 144), not a
+// lazy value.
+type Result[T any] struct {
+	get  func() (T, error)
+	done atomic.Bool
+}
+
+// OfErr returns a Result that calls fn at most once.
+func OfErr[T any](fn func() (T, error)) *Result[T] {
+	r := &Result[T]{}
+	r.get = sync.OnceValues(func() (T, error) {
+		t, err := fn()
+		r.done.Store(true)
+		return t, err
+	})
+	return r
+}
+
+// Get returns fn's value and error, calling fn first if no one has yet.
+func (r *Result[T]) Get() (T, error) { return r.get() }
+
+// Done reports whether fn has returned, with or without an error.
+func (r *Result[T]) Done() bool { return r.done.Load() }
@@ -0,0 +1,75 @@
+package lazy
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOfCallsOnce(t *testing.T) {
+	var calls atomic.Int32
+	v := Of(func() int {
+		calls.Add(1)
+		return 42
+	})
+	if v.Done() {
+		t.Fatal("Done before the first Get")
+	}
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			if got := v.Get(); got != 42 {
+				t.Errorf("Get = %d, want 42", got)
+			}
+		})
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	if !v.Done() {
+		t.Error("not Done after Get")
+	}
+}
+
+func TestOfPanicRepeats(t *testing.T) {
+	var calls int
+	v := Of(func() string {
+		calls++
+		panic("bad pattern")
+	})
+	for range 2 {
+		func() {
+			defer func() {
+				if r := recover(); r != "bad pattern" {
+					t.Errorf("recovered %v, want the original panic", r)
+				}
+			}()
+			v.Get()
+		}()
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times after a panic, want 1", calls)
+	}
+	if v.Done() {
+		t.Error("Done after fn panicked")
+	}
+}
+
+func TestOfErrCachesError(t *testing.T) {
+	errMissing := errors.New("missing")
+	var calls int
+	r := OfErr(func() ([]byte, error) {
+		calls++
+		return nil, errMissing
+	})
+	for range 3 {
+		if _, err := r.Get(); !errors.Is(err, errMissing) {
+			t.Fatalf("Get err = %v, want %v", err, errMissing)
+		}
+	}
+	if calls != 1 || !r.Done() {
+		t.Errorf("calls = %d, Done = %v; want 1, true", calls, r.Done())
+	}
+}
@@ -0,0 +1,177 @@
+// Package listparams parses a list endpoint's query — page, per_page,
+// sort and filter[field] — into SQL fragments that are safe to paste
+// into a query (Topic 147):
+//
+//	p, err := listparams.Parse(r.URL.Query(), spec)
+//	// ?page=2&per_page=20&sort=-created_at,title&filter[level]=advanced
+//	where, args := p.Where(1) // WHERE l.level = $1, ["advanced"]
+//	query := "SELECT ... " + where + " " + p.OrderBy() + " " + p.LimitOffset()
+//
+// Column names can't be bound as parameters, so they come only from the
+// Spec's allow-lists: a sort or filter on any other name is an error, not
+// SQL. Filter values only ever become bound parameters. Every problem in
+// the query is reported at once, as errorx.ValidationErrors (intermediate
+// Topic 69).
+package listparams
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/errorx"
+)
+
+// Spec is what one endpoint allows.
+type Spec struct {
+	SortColumns    map[string]string // API name → SQL column
+	FilterColumns  map[string]string // API name → SQL column
+	DefaultSort    string            // e.g. "-created_at"
+	DefaultPerPage int
+	MaxPerPage     int
+}
+
+// SortField is one ORDER BY column.
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Filter is one exact-match condition.
+type Filter struct {
+	Column string
+	Value  string
+}
+
+// Params is a parsed query. Its columns are all from the Spec.
+type Params struct {
+	Page    int // 1-based
+	PerPage int
+	Sort    []SortField
+	Filters []Filter // Ordered by parameter name, so the SQL is the same each time
+}
+
+// Error lists every problem in a query.
+type Error struct {
+	Problems []errorx.ValidationError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = fmt.Sprintf("%s %s (received: %q)", p.Field, p.Issue, p.Value)
+	}
+	return "invalid list parameters: " + strings.Join(msgs, "; ")
+}
+
+// Parse reads q by spec. A query with no page, per_page or sort gets
+// page 1, spec.DefaultPerPage and spec.DefaultSort.
+func Parse(q url.Values, spec Spec) (Params, error) {
+	p := Params{Page: 1, PerPage: spec.DefaultPerPage}
+	var problems []errorx.ValidationError
+	bad := func(field, issue, value string) {
+		problems = append(problems, errorx.NewValidationError(field, issue, value))
+	}
+
+	if raw := q.Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			bad("page", "must be a positive integer", raw)
+		} else {
+			p.Page = n
+		}
+	}
+
+	if raw := q.Get("per_page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		switch {
+		case err != nil || n < 1:
+			bad("per_page", "must be a positive integer", raw)
+		case n > spec.MaxPerPage:
+			bad("per_page", fmt.Sprintf("must be at most %d", spec.MaxPerPage), raw)
+		default:
+			p.PerPage = n
+		}
+	}
+
+	sortRaw := q.Get("sort")
+	if sortRaw == "" {
+		sortRaw = spec.DefaultSort
+	}
+	for _, part := range strings.Split(sortRaw, ",") {
+		if part == "" {
+			continue
+		}
+		name, desc := strings.TrimPrefix(part, "-"), strings.HasPrefix(part, "-")
+		col, ok := spec.SortColumns[name]
+		if !ok {
+			bad("sort", "is not a sortable field", part)
+			continue
+		}
+		p.Sort = append(p.Sort, SortField{Column: col, Desc: desc})
+	}
+
+	// filter[field]=value. Map iteration is random, so sort keys for a
+	// deterministic SQL string (good for logs and query caches).
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "filter[") || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		name := k[len("filter[") : len(k)-1]
+		col, ok := spec.FilterColumns[name]
+		if !ok {
+			bad(k, "is not a filterable field", q.Get(k))
+			continue
+		}
+		p.Filters = append(p.Filters, Filter{Column: col, Value: q.Get(k)})
+	}
+
+	if len(problems) > 0 {
+		return Params{}, &Error{Problems: problems}
+	}
+	return p, nil
+}
+
+// Where returns "WHERE a = $1 AND b = $2", numbering from firstArg, and
+// the values to bind; "" and nil with no filters.
+func (p Params) Where(firstArg int) (string, []any) {
+	if len(p.Filters) == 0 {
+		return "", nil
+	}
+	conds := make([]string, len(p.Filters))
+	args := make([]any, len(p.Filters))
+	for i, f := range p.Filters {
+		conds[i] = fmt.Sprintf("%s = $%d", f.Column, firstArg+i)
+		args[i] = f.Value
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// OrderBy returns "ORDER BY a DESC, b ASC", or "" with no sort.
+func (p Params) OrderBy() string {
+	if len(p.Sort) == 0 {
+		return ""
+	}
+	parts := make([]string, len(p.Sort))
+	for i, s := range p.Sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		parts[i] = s.Column + " " + dir
+	}
+	return "ORDER BY " + strings.Join(parts, ", ")
+}
+
+// LimitOffset returns "LIMIT n OFFSET m" for the page. The numbers are
+// integers Parse checked, so formatting them into SQL is safe.
+func (p Params) LimitOffset() string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", p.PerPage, (p.Page-1)*p.PerPage)
+}
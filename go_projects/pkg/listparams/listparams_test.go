@@ -0,0 +1,63 @@
+package listparams
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var lessons = Spec{
+	SortColumns:    map[string]string{"created_at": "l.created_at", "title": "l.title", "id": "l.id"},
+	FilterColumns:  map[string]string{"level": "l.level", "author": "a.name"},
+	DefaultSort:    "-created_at",
+	DefaultPerPage: 20,
+	MaxPerPage:     100,
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		query string
+		sql   string // WHERE, ORDER BY and LIMIT, or the error
+		args  []any
+	}{
+		{"", "ORDER BY l.created_at DESC LIMIT 20 OFFSET 0", nil},
+		{"page=3&per_page=10&sort=title,-id", "ORDER BY l.title ASC, l.id DESC LIMIT 10 OFFSET 20", nil},
+		{"filter[level]=advanced&filter[author]=Rob",
+			"WHERE a.name = $1 AND l.level = $2 ORDER BY l.created_at DESC LIMIT 20 OFFSET 0", []any{"Rob", "advanced"}},
+		{"filter[level]=x' OR '1'='1", "WHERE l.level = $1 ORDER BY l.created_at DESC LIMIT 20 OFFSET 0", []any{"x' OR '1'='1"}},
+		{"page=0&per_page=500&sort=title%3BDROP TABLE users--&filter[password]=hunter2",
+			`invalid list parameters: page must be a positive integer (received: "0"); ` +
+				`per_page must be at most 100 (received: "500"); ` +
+				`sort is not a sortable field (received: "title;DROP TABLE users--"); ` +
+				`filter[password] is not a filterable field (received: "hunter2")`, nil},
+		{"per_page=ten", `invalid list parameters: per_page must be a positive integer (received: "ten")`, nil},
+	} {
+		q, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := Parse(q, lessons)
+		var got string
+		var args []any
+		if err != nil {
+			got = err.Error()
+		} else {
+			var where string
+			where, args = p.Where(1)
+			got = strings.Join(strings.Fields(where+" "+p.OrderBy()+" "+p.LimitOffset()), " ")
+		}
+		if got != tt.sql || !slices.Equal(args, tt.args) {
+			t.Errorf("?%s:\n got %s %q\nwant %s %q", tt.query, got, args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	_, err := Parse(url.Values{"page": {"-1"}, "sort": {"nope"}}, lessons)
+	var e *Error
+	if !errors.As(err, &e) || len(e.Problems) != 2 || e.Problems[0].Field != "page" || e.Problems[1].Field != "sort" {
+		t.Errorf("Parse = %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package memdb is a toy database/sql driver that understands just enough
+// SQL for pkg/migrate's lessons and tests: CREATE, ALTER and DROP TABLE,
+// CREATE and DROP INDEX, and the schema_migrations statements. It keeps
+// which tables exist, not what is in them.
+//
+//	import _ "github.com/akarsh323/Go-tutorials-/go_projects/pkg/migrate/memdb"
+//
+//	db, err := sql.Open("memdb", "lessons")        // In memory, shared by name
+//	db, err := sql.Open("memdb", "file:schema.json") // Kept in a file between runs
+//
+// Statements in a transaction change the tables only if it commits; a
+// rollback puts back the tables it started with, as a database with
+// transactional DDL would. Anything else is a syntax error.
+package memdb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+func init() { sql.Register("memdb", drv) }
+
+var drv = &memDriver{dbs: map[string]*database{}}
+
+// Tables returns the tables in the database named dsn, sorted.
+func Tables(dsn string) []string {
+	drv.mu.Lock()
+	db := drv.dbs[dsn]
+	drv.mu.Unlock()
+	if db == nil {
+		return nil
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var out []string
+	for t := range db.Tables {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// database is one DSN's state; its fields are the file's JSON.
+type database struct {
+	mu     sync.Mutex
+	path   string          // "" keeps it in memory
+	Tables map[string]bool `json:"tables"`
+	Rows   map[int64]bool  `json:"schema_migrations"` // version → dirty
+}
+
+// save writes the state to the database's file, if it has one. The caller
+// holds mu.
+func (db *database) save() error {
+	if db.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(db.path, data, 0o644)
+}
+
+type memDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*database
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dbs[name] == nil {
+		db := &database{Tables: map[string]bool{}, Rows: map[int64]bool{}}
+		if path, ok := strings.CutPrefix(name, "file:"); ok {
+			db.path = path
+			data, err := os.ReadFile(path)
+			switch {
+			case errors.Is(err, fs.ErrNotExist): // A new database
+			case err != nil:
+				return nil, err
+			default:
+				if err := json.Unmarshal(data, db); err != nil {
+					return nil, fmt.Errorf("memdb: %s: %w", path, err)
+				}
+			}
+		}
+		d.dbs[name] = db
+	}
+	return &conn{db: d.dbs[name]}, nil
+}
+
+type conn struct {
+	db       *database
+	snapshot map[string]bool // The tables at Begin, put back by Rollback; nil outside a transaction
+}
+
+func (c *conn) Prepare(q string) (driver.Stmt, error) { return &stmt{c, q}, nil }
+func (c *conn) Close() error                          { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = map[string]bool{}
+	for t := range c.db.Tables {
+		c.snapshot[t] = true
+	}
+	return c, nil
+}
+
+func (c *conn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = nil
+	return c.db.save()
+}
+
+func (c *conn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.Tables, c.snapshot = c.snapshot, nil
+	return nil
+}
+
+type stmt struct {
+	c *conn
+	q string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+var ddl = regexp.MustCompile(`(?is)^(CREATE TABLE IF NOT EXISTS|CREATE TABLE|DROP TABLE|ALTER TABLE|CREATE INDEX|DROP INDEX)\s+(\w+)`)
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	q := strings.TrimSpace(s.q)
+	switch {
+	case strings.HasPrefix(q, "INSERT INTO schema_migrations"):
+		db.Rows[args[0].(int64)] = args[1].(bool)
+	case strings.HasPrefix(q, "UPDATE schema_migrations"):
+		db.Rows[args[1].(int64)] = args[0].(bool)
+	case strings.HasPrefix(q, "DELETE FROM schema_migrations"):
+		delete(db.Rows, args[0].(int64))
+	default:
+		m := ddl.FindStringSubmatch(q)
+		if m == nil {
+			return nil, fmt.Errorf("syntax error near %q", strings.Fields(q)[0])
+		}
+		verb, table := strings.ToUpper(m[1]), strings.ToLower(m[2])
+		switch verb {
+		case "CREATE TABLE IF NOT EXISTS":
+			db.Tables[table] = true
+		case "CREATE TABLE":
+			if db.Tables[table] {
+				return nil, fmt.Errorf("table %s already exists", table)
+			}
+			db.Tables[table] = true
+		case "DROP TABLE", "ALTER TABLE":
+			if !db.Tables[table] {
+				return nil, fmt.Errorf("no such table: %s", table)
+			}
+			if verb == "DROP TABLE" {
+				delete(db.Tables, table)
+			}
+		}
+	}
+	if s.c.snapshot != nil {
+		return driver.RowsAffected(1), nil // Saved when it commits
+	}
+	return driver.RowsAffected(1), db.save()
+}
+
+// Query answers the one SELECT pkg/migrate makes: every schema_migrations
+// row, by version.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.c.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	r := &rows{}
+	for v, dirty := range db.Rows {
+		r.data = append(r.data, []driver.Value{v, dirty})
+	}
+	sort.Slice(r.data, func(i, j int) bool { return r.data[i][0].(int64) < r.data[j][0].(int64) })
+	return r, nil
+}
+
+type rows struct {
+	data [][]driver.Value
+	i    int
+}
+
+func (r *rows) Columns() []string { return []string{"version", "dirty"} }
+func (r *rows) Close() error      { return nil }
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.i])
+	r.i++
+	return nil
+}
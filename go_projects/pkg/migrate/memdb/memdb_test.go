@@ -0,0 +1,55 @@
+package memdb
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	db, err := sql.Open("memdb", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE lessons (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec("CREATE TABLE scratch (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("ALTR TABLE lessons"); err == nil || !strings.Contains(err.Error(), "syntax error") {
+		t.Errorf("ALTR = %v, want a syntax error", err)
+	}
+
+	// A second process would start from the file.
+	delete(drv.dbs, "file:"+path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(Tables("file:"+path), " "); got != "" {
+		t.Errorf("Tables before reopening = %q", got)
+	}
+	db2, err := sql.Open("memdb", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db2.Close()
+	if err := db2.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(Tables("file:"+path), " "); got != "lessons" {
+		t.Errorf("Tables after reopening = %q; the file has %s", got, data)
+	}
+}
@@ -0,0 +1,332 @@
+// Package migrate applies numbered .sql files to a database in order,
+// once each, and can take them back (Topic 148):
+//
+//	//go:embed migrations/*.sql
+//	var files embed.FS
+//
+//	migrations, err := migrate.Load(files, "migrations")
+//	...
+//	m, err := migrate.New(ctx, db, migrations)
+//	...
+//	err = m.Up(ctx, 0) // Every pending migration
+//
+// where migrations/ holds NNNN_name.up.sql and, for one that can be
+// reverted, NNNN_name.down.sql. What has been applied is kept in the
+// database itself:
+//
+//	schema_migrations (version INTEGER PRIMARY KEY, dirty BOOLEAN)
+//
+// A migration's row goes in dirty before its SQL runs and is marked clean
+// after. If the SQL fails, or the process dies, the row stays dirty: some
+// databases can't roll DDL back, so the schema may be half changed, and
+// every command but Force refuses to run (ErrDirty) until a person has
+// looked. Force only fixes the bookkeeping; it runs no SQL.
+//
+// It uses nothing but database/sql, so it works with any driver;
+// migrate/memdb is a toy one for lessons and tests.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is one numbered change. Down is "" for one that can't be
+// reverted.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads dir's NNNN_name.up.sql and NNNN_name.down.sql files from
+// fsys, ordered by version. Other files are skipped.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		m := migrationFile.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: version %s is too large", e.Name(), m[1])
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("version %d has two names: %q and %q", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no .up.sql", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// splitStatements splits on ";". Good enough for DDL files; real tools
+// also handle semicolons inside strings and function bodies.
+func splitStatements(script string) []string {
+	var out []string
+	for _, s := range strings.Split(script, ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ErrDirty is what every command but Force returns while a migration is
+// marked dirty.
+var ErrDirty = errors.New("database is dirty")
+
+// ErrUsage is what Command returns for arguments it can't run.
+var ErrUsage = errors.New("want status, up [N], down [N] or force VERSION")
+
+// Migrator applies one set of migrations to one database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	Out        io.Writer // Where Status and each step are reported; New sets os.Stdout
+}
+
+// New returns a Migrator for db, creating its schema_migrations table if
+// it has none.
+func New(ctx context.Context, db *sql.DB, migrations []Migration) (*Migrator, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, dirty BOOLEAN NOT NULL)`)
+	if err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return &Migrator{db: db, migrations: migrations, Out: os.Stdout}, nil
+}
+
+// applied returns version → dirty for every recorded migration.
+func (m *Migrator) applied(ctx context.Context) (map[int]bool, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, dirty FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	state := map[int]bool{}
+	for rows.Next() {
+		var v int
+		var dirty bool
+		if err := rows.Scan(&v, &dirty); err != nil {
+			return nil, err
+		}
+		state[v] = dirty
+	}
+	return state, rows.Err()
+}
+
+// checkClean refuses to continue when any migration is dirty.
+func checkClean(state map[int]bool) error {
+	for v, dirty := range state {
+		if dirty {
+			return fmt.Errorf("%w: migration %04d failed part-way; fix the schema by hand, then run 'force'", ErrDirty, v)
+		}
+	}
+	return nil
+}
+
+// Status writes each migration and whether it is pending, applied or
+// DIRTY to Out.
+func (m *Migrator) Status(ctx context.Context) error {
+	state, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		mark := "pending"
+		if dirty, ok := state[mig.Version]; ok {
+			mark = "applied"
+			if dirty {
+				mark = "DIRTY"
+			}
+		}
+		fmt.Fprintf(m.Out, "  %04d %-20s %s\n", mig.Version, mig.Name, mark)
+	}
+	return nil
+}
+
+// Up applies up to n pending migrations, oldest first; n <= 0 is all.
+func (m *Migrator) Up(ctx context.Context, n int) error {
+	state, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkClean(state); err != nil {
+		return err
+	}
+	count := 0
+	for _, mig := range m.migrations {
+		if _, done := state[mig.Version]; done {
+			continue
+		}
+		if n > 0 && count == n {
+			break
+		}
+		if err := m.run(ctx, mig.Version, mig.Up, true); err != nil {
+			return fmt.Errorf("up %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		fmt.Fprintf(m.Out, "  ↑ applied %04d_%s\n", mig.Version, mig.Name)
+		count++
+	}
+	if count == 0 {
+		fmt.Fprintln(m.Out, "  no pending migrations")
+	}
+	return nil
+}
+
+// Down reverts the n most recent migrations; n <= 0 is 1.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	if n <= 0 {
+		n = 1
+	}
+	state, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkClean(state); err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0 && n > 0; i-- {
+		mig := m.migrations[i]
+		if _, done := state[mig.Version]; !done {
+			continue
+		}
+		if mig.Down == "" {
+			return fmt.Errorf("down %04d_%s: no .down.sql (irreversible migration)", mig.Version, mig.Name)
+		}
+		if err := m.run(ctx, mig.Version, mig.Down, false); err != nil {
+			return fmt.Errorf("down %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		fmt.Fprintf(m.Out, "  ↓ reverted %04d_%s\n", mig.Version, mig.Name)
+		n--
+	}
+	return nil
+}
+
+// run marks the version dirty, executes the script in a transaction, and
+// records the clean result. A failure leaves the dirty marker in place.
+func (m *Migrator) run(ctx context.Context, version int, script string, up bool) error {
+	mark, args := `INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)`, []any{version, true}
+	if !up {
+		mark, args = `UPDATE schema_migrations SET dirty = ? WHERE version = ?`, []any{true, version}
+	}
+	if _, err := m.db.ExecContext(ctx, mark, args...); err != nil {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback() // The statement's error is the one to report
+			return err    // The dirty row stays: a person must look
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if up {
+		_, err = m.db.ExecContext(ctx, `UPDATE schema_migrations SET dirty = ? WHERE version = ?`, false, version)
+	} else {
+		_, err = m.db.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, version)
+	}
+	return err
+}
+
+// Force records that exactly the migrations up to version are applied and
+// clean. It runs no migration SQL: it fixes the bookkeeping after a
+// person has repaired the schema.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	state, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for v := range state {
+		if _, err := m.db.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, v); err != nil {
+			return err
+		}
+	}
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		if _, err := m.db.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)`, mig.Version, false); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(m.Out, "  forced version to %04d\n", version)
+	return nil
+}
+
+// Command runs a migrate command line — status, up [N], down [N] or
+// force VERSION — for a CLI. Arguments it can't run wrap ErrUsage.
+func (m *Migrator) Command(ctx context.Context, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return ErrUsage
+	}
+	n := 0
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("%w: %s %q is not a number", ErrUsage, args[0], args[1])
+		}
+	}
+	switch args[0] {
+	case "status":
+		if len(args) == 2 {
+			return ErrUsage
+		}
+		return m.Status(ctx)
+	case "up":
+		return m.Up(ctx, n)
+	case "down":
+		return m.Down(ctx, n)
+	case "force":
+		if len(args) != 2 {
+			return fmt.Errorf("%w: force needs a VERSION", ErrUsage)
+		}
+		return m.Force(ctx, n)
+	}
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+}
@@ -0,0 +1,142 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/migrate/memdb"
+)
+
+var files = fstest.MapFS{
+	"m/0001_create_lessons.up.sql":   {Data: []byte("CREATE TABLE lessons (id INTEGER);")},
+	"m/0001_create_lessons.down.sql": {Data: []byte("DROP TABLE lessons;")},
+	"m/0002_add_level.up.sql":        {Data: []byte("ALTER TABLE lessons ADD COLUMN level TEXT;\nCREATE INDEX idx_level ON lessons (level);")},
+	"m/0002_add_level.down.sql":      {Data: []byte("DROP INDEX idx_level;\nALTER TABLE lessons DROP COLUMN level;")},
+	"m/0003_create_progress.up.sql":  {Data: []byte("CREATE TABLE progress (user_id TEXT);")},
+	"m/README.md":                    {Data: []byte("not a migration")},
+}
+
+// open returns a Migrator for files on a fresh memdb database, and what
+// it writes.
+func open(t *testing.T, migrations []Migration) (*Migrator, *strings.Builder) {
+	t.Helper()
+	db, err := sql.Open("memdb", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	m, err := New(context.Background(), db, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &strings.Builder{}
+	m.Out = out
+	return m, out
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(files, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Name)
+	}
+	if strings.Join(got, " ") != "create_lessons add_level create_progress" || migrations[2].Down != "" {
+		t.Errorf("Load = %+v", migrations)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"two names": {"m/0001_a.up.sql": {Data: []byte("x")}, "m/0001_b.down.sql": {Data: []byte("x")}},
+		"no up":     {"m/0001_a.down.sql": {Data: []byte("x")}},
+		"too large": {"m/99999999999999999999_a.up.sql": {Data: []byte("x")}},
+	} {
+		if _, err := Load(fsys, "m"); err == nil {
+			t.Errorf("%s: Load succeeded", name)
+		}
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	migrations, err := Load(files, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := open(t, migrations)
+	ctx := context.Background()
+	for _, step := range []struct {
+		args   string
+		tables string
+	}{
+		{"up 1", "lessons schema_migrations"},
+		{"up 1", "lessons schema_migrations"},
+		{"down 2", "schema_migrations"},
+		{"up", "lessons progress schema_migrations"},
+	} {
+		if err := m.Command(ctx, strings.Fields(step.args)); err != nil {
+			t.Fatalf("%s: %v", step.args, err)
+		}
+		if got := strings.Join(memdb.Tables(t.Name()), " "); got != step.tables {
+			t.Errorf("after %s: tables %s, want %s", step.args, got, step.tables)
+		}
+	}
+	if err := m.Down(ctx, 1); err == nil || !strings.Contains(err.Error(), "irreversible") {
+		t.Errorf("down 0003 = %v, want irreversible", err)
+	}
+}
+
+func TestDirty(t *testing.T) {
+	broken := fstest.MapFS{
+		"m/0001_a.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"m/0002_b.up.sql": {Data: []byte("CREATE TABLE b (id INTEGER);\nALTR TABLE b ADD COLUMN c TEXT;")},
+	}
+	migrations, err := Load(broken, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, out := open(t, migrations)
+	ctx := context.Background()
+	if err := m.Up(ctx, 0); err == nil || errors.Is(err, ErrDirty) {
+		t.Fatalf("Up = %v, want the syntax error", err)
+	}
+	if got := strings.Join(memdb.Tables(t.Name()), " "); got != "a schema_migrations" {
+		t.Errorf("tables %s: 0002's CREATE wasn't rolled back", got)
+	}
+	out.Reset()
+	if err := m.Status(ctx); err != nil || !strings.Contains(out.String(), "0002 b                    DIRTY") {
+		t.Errorf("Status = %v:\n%s", err, out)
+	}
+	if err := m.Up(ctx, 0); !errors.Is(err, ErrDirty) {
+		t.Errorf("Up while dirty = %v", err)
+	}
+	if err := m.Down(ctx, 1); !errors.Is(err, ErrDirty) {
+		t.Errorf("Down while dirty = %v", err)
+	}
+
+	if err := m.Force(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	broken["m/0002_b.up.sql"].Data = []byte("CREATE TABLE b (id INTEGER, c TEXT);")
+	if migrations, err = Load(broken, "m"); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = open(t, migrations)
+	if err := m.Up(ctx, 0); err != nil {
+		t.Errorf("Up after force = %v", err)
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	m, _ := open(t, nil)
+	for _, args := range []string{"", "sideways", "up two", "force", "status 3", "up 1 2"} {
+		if err := m.Command(context.Background(), strings.Fields(args)); !errors.Is(err, ErrUsage) {
+			t.Errorf("Command(%q) = %v, want ErrUsage", args, err)
+		}
+	}
+}
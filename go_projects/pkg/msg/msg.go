@@ -0,0 +1,200 @@
+// Package msg looks up user-facing strings by key in per-language catalogs
+// (see Topic 189), so a message is written once per language instead of
+// once per call site:
+//
+//	fmt.Fprintln(os.Stderr, msg.T(lang, "cli.no-lesson", n, dir))
+//	fmt.Println(msg.N(lang, "lesson.count", len(lessons)))
+//
+// The catalogs are catalogs/LANG.json, embedded in the binary: flat objects
+// from key to an fmt format. Translations reorder arguments with explicit
+// indexes ("%[2]s ... %[1]s"); plural forms are two keys, KEY.one and
+// KEY.other.
+//
+// A lookup falls back from "pt-BR" to "pt" to Default, and a key missing
+// even there comes back as itself: visible, and easy to grep for.
+package msg
+
+import (
+	"cmp"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default is the language every catalog falls back to. Its catalog must
+// have every key.
+const Default = "en"
+
+//go:embed catalogs/*.json
+var files embed.FS
+
+// catalogs maps a language to its messages. Loaded at init: a catalog that
+// doesn't parse is a build mistake, and should fail the first run.
+var catalogs = load()
+
+func load() map[string]map[string]string {
+	entries, err := files.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]map[string]string, len(entries))
+	for _, e := range entries {
+		data, err := files.ReadFile("catalogs/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		var c map[string]string
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("msg: catalogs/%s: %v", e.Name(), err))
+		}
+		out[strings.TrimSuffix(e.Name(), path.Ext(e.Name()))] = c
+	}
+	return out
+}
+
+// Languages returns the languages with a catalog, sorted.
+func Languages() []string {
+	var out []string
+	for lang := range catalogs {
+		out = append(out, lang)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Keys returns the keys of lang's own catalog, sorted; nil if there is none.
+func Keys(lang string) []string {
+	var out []string
+	for key := range catalogs[Normalize(lang)] {
+		out = append(out, key)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Normalize turns a language tag or POSIX locale into catalog form:
+// "es_ES.UTF-8" and "ES-es" both become "es-es". "C" and "POSIX", which
+// name no language, become "".
+func Normalize(tag string) string {
+	tag, _, _ = strings.Cut(tag, ".") // Encoding: es_ES.UTF-8
+	tag, _, _ = strings.Cut(tag, "@") // Modifier: sr_RS@latin
+	if tag == "C" || tag == "POSIX" {
+		return ""
+	}
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// chain lists the catalogs to try for lang: itself, its base language,
+// then Default.
+func chain(lang string) []string {
+	lang = Normalize(lang)
+	out := []string{lang}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		out = append(out, base)
+	}
+	return append(out, Default)
+}
+
+func lookup(lang, key string) (string, bool) {
+	for _, l := range chain(lang) {
+		if s, ok := catalogs[l][key]; ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// T returns the message for key in lang, formatted with args. A key no
+// catalog has comes back unformatted, as the key itself.
+func T(lang, key string, args ...any) string {
+	format, ok := lookup(lang, key)
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// N returns the plural form of key that lang uses for n (KEY.one or
+// KEY.other), formatted with n followed by args.
+func N(lang, key string, n int, args ...any) string {
+	return T(lang, key+"."+pluralForm(lang, n), append([]any{n}, args...)...)
+}
+
+// pluralForm is CLDR's rule for the catalogs we have. English and Spanish
+// say "0 lessons"; French says "0 leçon". Languages with more forms
+// (Polish, Russian, Arabic) need more keys than one/other, and a real
+// library: golang.org/x/text/feature/plural.
+func pluralForm(lang string, n int) string {
+	base, _, _ := strings.Cut(Normalize(lang), "-")
+	switch {
+	case base == "fr" && (n == 0 || n == 1):
+		return "one"
+	case base != "fr" && n == 1:
+		return "one"
+	}
+	return "other"
+}
+
+// Match returns the first of prefs that has a catalog, trying each one's
+// base language before moving on ("es-MX" → "es" before "en"), or Default
+// if none does.
+func Match(prefs ...string) string {
+	for _, p := range prefs {
+		c := chain(p)
+		for _, l := range c[:len(c)-1] { // Not Default yet: the next pref may match
+
+			if _, ok := catalogs[l]; ok {
+				return l
+			}
+		}
+	}
+	return Default
+}
+
+// ParseAcceptLanguage returns the tags of an Accept-Language header,
+// highest quality first. Tags refused with q=0, and ones that don't parse,
+// are dropped; "*" is kept, and Match skips it.
+func ParseAcceptLanguage(header string) []string {
+	type tagQ struct {
+		tag string
+		q   float64
+	}
+	var tags []tagQ
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		if tag != "" && q > 0 {
+			tags = append(tags, tagQ{tag, q})
+		}
+	}
+	slices.SortStableFunc(tags, func(a, b tagQ) int { return cmp.Compare(b.q, a.q) })
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.tag
+	}
+	return out
+}
+
+// FromEnv returns the language the POSIX locale variables ask for:
+// LC_ALL, then LC_MESSAGES, then LANG, the first one set. "" means none
+// (unset, or "C").
+func FromEnv() string {
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if s := os.Getenv(v); s != "" {
+			return Normalize(s)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package msg
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	for _, tc := range []struct {
+		lang, key string
+		args      []any
+		want      string
+	}{
+		{"en", "cli.no-lesson", []any{42, "course"}, "no lesson 42 in course"},
+		{"es", "cli.no-lesson", []any{42, "course"}, "no hay ninguna lección 42 en course"},
+		{"es-MX", "greeting", []any{"Ana"}, "¡Hola, Ana!"}, // Base language
+		{"fr_FR.UTF-8", "greeting", []any{"Ana"}, "Bonjour, Ana !"},
+		{"pt-BR", "greeting", []any{"Ana"}, "Hello, Ana!"}, // No pt at all: Default
+		{"", "greeting", []any{"Ana"}, "Hello, Ana!"},
+		{"es", "lesson.by", []any{"Ana", "regex"}, "la lección de regex de Ana"}, // Reordered
+		{"es", "no.such.key", nil, "no.such.key"},
+	} {
+		if got := T(tc.lang, tc.key, tc.args...); got != tc.want {
+			t.Errorf("T(%q, %q) = %q, want %q", tc.lang, tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestN(t *testing.T) {
+	for _, tc := range []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"en", 0, "0 lessons"},
+		{"en", 1, "1 lesson"},
+		{"en", 2, "2 lessons"},
+		{"es", 1, "1 lección"},
+		{"fr", 0, "0 leçon"}, // French zero is singular
+		{"fr-CA", 1, "1 leçon"},
+		{"fr", 2, "2 leçons"},
+	} {
+		if got := N(tc.lang, "lesson.count", tc.n); got != tc.want {
+			t.Errorf("N(%q, %d) = %q, want %q", tc.lang, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"de-DE, fr;q=0.5, en;q=0.4", "fr"}, // No de: the next choice, not Default
+		{"en;q=0.5, fr", "fr"},
+		{"es;q=0, fr;q=0.1", "fr"},
+		{"*", "en"},
+		{"pt-BR, pt", "en"},
+	} {
+		if got := Match(ParseAcceptLanguage(tc.header)...); got != tc.want {
+			t.Errorf("Match(%q) = %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("fr;q=0.5, es-MX , de;q=0, en;q=x, it;q=0.7,")
+	want := []string{"es-MX", "it", "fr"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAcceptLanguage = %q, want %q", got, want)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "fr_FR.UTF-8")
+	t.Setenv("LANG", "es_ES.UTF-8")
+	if got := FromEnv(); got != "fr-fr" {
+		t.Errorf("FromEnv = %q, want LC_MESSAGES's fr-fr", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	if got := FromEnv(); got != "" {
+		t.Errorf("FromEnv with LC_ALL=C = %q, want \"\"", got)
+	}
+}
+
+var verb = regexp.MustCompile(`%(\[(\d+)\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+// args returns which arguments a format uses, by position, so a
+// translation that reorders with %[n] still counts as the same.
+func args(format string) []int {
+	var out []int
+	next := 1
+	for _, m := range verb.FindAllStringSubmatch(format, -1) {
+		if m[0] == "%%" {
+			continue
+		}
+		if m[2] != "" {
+			next, _ = strconv.Atoi(m[2])
+		}
+		out = append(out, next)
+		next++
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// TestCatalogs checks every catalog against Default's: the same keys, and
+// each message using the same arguments, so no translation prints
+// %!d(MISSING) or drops a value.
+func TestCatalogs(t *testing.T) {
+	if !slices.Contains(Languages(), Default) {
+		t.Fatalf("no %s catalog", Default)
+	}
+	for _, lang := range Languages() {
+		if !slices.Equal(Keys(lang), Keys(Default)) {
+			t.Errorf("%s keys %q, want %s's %q", lang, Keys(lang), Default, Keys(Default))
+		}
+		for key, format := range catalogs[lang] {
+			if got, want := args(format), args(catalogs[Default][key]); !slices.Equal(got, want) {
+				t.Errorf("%s %s uses arguments %v, %s uses %v", lang, key, got, Default, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package negotiate picks a response type from a request's Accept header
+// (see Topic 188), so one endpoint can answer a browser with HTML and a
+// script with JSON:
+//
+//	switch negotiate.Best(r.Header.Get("Accept"), []string{"application/json", "text/html"}) {
+//	case "text/html":
+//		...
+//	case "":
+//		// 406 Not Acceptable
+//	}
+//
+// A handler that does this must also send "Vary: Accept", or a cache may
+// hand the HTML to the next script.
+package negotiate
+
+import (
+	"cmp"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Range is one media range from an Accept header: "text/html",
+// "image/*" or "*/*", with its quality.
+type Range struct {
+	Type, Subtype string
+	Q             float64 // 0 to 1; 0 means "not this"
+}
+
+// specificity ranks exact types above "type/*" above "*/*".
+func (r Range) specificity() int {
+	switch {
+	case r.Type == "*":
+		return 0
+	case r.Subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+func (r Range) matches(typ, sub string) bool {
+	return (r.Type == "*" || r.Type == typ) && (r.Subtype == "*" || r.Subtype == sub)
+}
+
+// Parse reads an Accept header into ranges, highest quality first (most
+// specific first among equals). Ranges that don't parse, or have a q
+// outside 0..1, are dropped, as a server should. Media type parameters
+// other than q ("level=1") are ignored.
+func Parse(accept string) []Range {
+	var out []Range
+	for part := range strings.SplitSeq(accept, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		typ, sub, ok := strings.Cut(mt, "/")
+		if !ok || typ == "*" && sub != "*" {
+			continue
+		}
+		r := Range{Type: typ, Subtype: sub, Q: 1}
+		if q, ok := params["q"]; ok {
+			if r.Q, err = strconv.ParseFloat(q, 64); err != nil || r.Q < 0 || r.Q > 1 {
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	slices.SortStableFunc(out, func(a, b Range) int {
+		return cmp.Or(cmp.Compare(b.Q, a.Q), cmp.Compare(b.specificity(), a.specificity()))
+	})
+	return out
+}
+
+// Quality returns how much the ranges accept a content type: the q of the
+// most specific range that matches it, or 0 if none does.
+func Quality(ranges []Range, contentType string) float64 {
+	typ, sub, _ := strings.Cut(strings.ToLower(contentType), "/")
+	best, q := -1, 0.0
+	for _, r := range ranges {
+		if s := r.specificity(); s > best && r.matches(typ, sub) {
+			best, q = s, r.Q
+		}
+	}
+	return q
+}
+
+// Best returns the offer the Accept header likes most, or "" if it
+// accepts none of them (reply 406). Offers are in the server's order of
+// preference, which breaks ties: "*/*", what curl sends, gets offers[0].
+// So does a missing or empty header, which means "anything".
+func Best(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+	ranges := Parse(accept)
+	best, bestQ := "", 0.0
+	for _, o := range offers {
+		if q := Quality(ranges, o); q > bestQ {
+			best, bestQ = o, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,56 @@
+package negotiate
+
+import (
+	"slices"
+	"testing"
+)
+
+const firefox = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestBest(t *testing.T) {
+	api := []string{"application/json", "text/html"}
+	for _, tc := range []struct {
+		accept string
+		offers []string
+		want   string
+	}{
+		{"", api, "application/json"},
+		{"*/*", api, "application/json"},
+		{"application/json", api, "application/json"},
+		{firefox, api, "text/html"},
+		{"text/*", api, "text/html"},
+		{"TEXT/HTML", api, "text/html"},
+		{"text/html;q=0.5, application/json;q=0.9", api, "application/json"},
+		{"text/html, application/json", api, "application/json"}, // A tie: server order wins
+		{"text/*;q=0.3, text/html;q=0.7, */*;q=0.5", api, "text/html"},
+		{"*/*, application/json;q=0", api, "text/html"}, // q=0 refuses JSON outright
+		{"image/png", api, ""},
+		{"application/json;q=0", api, ""},
+		{"", nil, ""},
+	} {
+		if got := Best(tc.accept, tc.offers); got != tc.want {
+			t.Errorf("Best(%q, %q) = %q, want %q", tc.accept, tc.offers, got, tc.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	got := Parse("text/*;q=0.5, bogus, text/html;level=1, */*;q=0.1, a/b;q=2, */json, image/png;q=x")
+	want := []Range{{"text", "html", 1}, {"text", "*", 0.5}, {"*", "*", 0.1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Parse = %v, want %v", got, want)
+	}
+}
+
+func TestQuality(t *testing.T) {
+	ranges := Parse(firefox)
+	for ct, want := range map[string]float64{
+		"text/html":       1,
+		"application/xml": 0.9,
+		"image/webp":      0.8,
+	} {
+		if got := Quality(ranges, ct); got != want {
+			t.Errorf("Quality(%q) = %v, want %v", ct, got, want)
+		}
+	}
+}
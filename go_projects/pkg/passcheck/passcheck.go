@@ -0,0 +1,120 @@
+// Package passcheck checks passwords against a policy in plain Go
+// (intermediate Topic 73, Part 5):
+//
+//	if err := passcheck.Default.Check(pw); err != nil {
+//		// err lists every rule pw breaks; errors.Is(err, passcheck.ErrTooShort) ...
+//	}
+//
+// A regular expression is the wrong tool here. The usual one,
+// ^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$, needs lookaheads, which Go's
+// RE2 engine doesn't have: regexp.MustCompile panics on it. And a single
+// pattern can only say yes or no, where a user needs to hear what is
+// missing. Each rule here is a few lines of Go and reports itself.
+//
+// Lengths are in characters, not bytes, and the classes are Unicode's:
+// "É" is an upper case letter and "٣" a digit. Check never changes the
+// password — no trimming, no truncating: what is checked is what will
+// be hashed.
+package passcheck
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy is a set of rules. The zero Policy accepts anything.
+type Policy struct {
+	MinLength int // In characters
+	MaxLength int // In characters; 0 means no limit
+
+	// The character classes a password must contain.
+	Upper, Lower, Digit, Symbol bool
+
+	// Deny lists passwords refused whatever they contain, compared
+	// without regard to case. "Password1!" is refused when "password"
+	// is listed: digits and symbols added at the end don't make a
+	// common password less common.
+	Deny []string
+}
+
+// Default is a policy for a typical sign-up form: 8 to 64 characters,
+// upper and lower case letters and a digit, and not a common password.
+var Default = Policy{
+	MinLength: 8,
+	MaxLength: 64,
+	Upper:     true,
+	Lower:     true,
+	Digit:     true,
+	Deny:      Common,
+}
+
+// Common are some of the passwords most often found in breaches. A real
+// service checks a much longer list, or a breach-lookup service.
+var Common = []string{
+	"password", "passw0rd", "123456", "12345678", "123456789", "1234567890",
+	"qwerty", "qwertyuiop", "abc123", "111111", "000000", "iloveyou",
+	"letmein", "welcome", "admin", "administrator", "monkey", "dragon",
+	"football", "baseball", "sunshine", "princess", "master", "shadow",
+	"superman", "trustno1", "hello", "freedom", "whatever", "changeme",
+}
+
+// The rules a password can break. Check's error wraps one for each.
+var (
+	ErrTooShort = errors.New("too short")
+	ErrTooLong  = errors.New("too long")
+	ErrNoUpper  = errors.New("no upper case letter")
+	ErrNoLower  = errors.New("no lower case letter")
+	ErrNoDigit  = errors.New("no digit")
+	ErrNoSymbol = errors.New("no symbol")
+	ErrCommon   = errors.New("too common")
+)
+
+// Check returns nil if pw follows every rule in p, or an error for each
+// rule it breaks, joined, in the order the fields are declared.
+func (p Policy) Check(pw string) error {
+	var errs []error
+	n := utf8.RuneCountInString(pw)
+	if n < p.MinLength {
+		errs = append(errs, fmt.Errorf("%w: %d characters, want at least %d", ErrTooShort, n, p.MinLength))
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		errs = append(errs, fmt.Errorf("%w: %d characters, want at most %d", ErrTooLong, n, p.MaxLength))
+	}
+	for _, class := range []struct {
+		required bool
+		has      func(rune) bool
+		err      error
+	}{
+		{p.Upper, unicode.IsUpper, ErrNoUpper},
+		{p.Lower, unicode.IsLower, ErrNoLower},
+		{p.Digit, unicode.IsDigit, ErrNoDigit},
+		{p.Symbol, isSymbol, ErrNoSymbol},
+	} {
+		if class.required && !strings.ContainsFunc(pw, class.has) {
+			errs = append(errs, class.err)
+		}
+	}
+	if p.denied(pw) {
+		errs = append(errs, fmt.Errorf("%w: on the list of passwords attackers try first", ErrCommon))
+	}
+	return errors.Join(errs...)
+}
+
+// isSymbol reports whether r is punctuation or a symbol: !, #, €, ©.
+// A space is neither; it is allowed, but doesn't count as a symbol.
+func isSymbol(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }
+
+// denied reports whether pw, in any case and with any digits and symbols
+// after it removed, is on p's deny list.
+func (p Policy) denied(pw string) bool {
+	lower := strings.ToLower(pw)
+	base := strings.TrimRightFunc(lower, func(r rune) bool { return unicode.IsDigit(r) || isSymbol(r) })
+	return slices.ContainsFunc(p.Deny, func(d string) bool {
+		d = strings.ToLower(d)
+		return d == lower || d == base && base != ""
+	})
+}
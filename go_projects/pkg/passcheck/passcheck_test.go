@@ -0,0 +1,125 @@
+package passcheck
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var all = []error{ErrTooShort, ErrTooLong, ErrNoUpper, ErrNoLower, ErrNoDigit, ErrNoSymbol, ErrCommon}
+
+// broken returns the rules err reports, in all's order.
+func broken(err error) []error {
+	var got []error
+	for _, rule := range all {
+		if errors.Is(err, rule) {
+			got = append(got, rule)
+		}
+	}
+	return got
+}
+
+func same(a, b []error) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		pw   string
+		want []error // Every rule pw breaks; none for a password that passes
+	}{
+		{"ValidPass123", nil},
+		{"correct Horse battery 9", nil}, // Spaces are fine
+		{"Été2026été", nil},              // Unicode letters count
+		{"Password٣x", nil},              // ...and digits: an Arabic-Indic three
+		{"Abcdefg1", nil},                // Exactly 8
+		{"Ééééééé1", nil},                // 8 characters, 15 bytes
+		{"Abcdef1", []error{ErrTooShort}},
+		{"A1" + strings.Repeat("b", 62), nil}, // Exactly 64
+		{"A1" + strings.Repeat("b", 63), []error{ErrTooLong}},
+		{"", []error{ErrTooShort, ErrNoUpper, ErrNoLower, ErrNoDigit}},
+		{"invalid", []error{ErrTooShort, ErrNoUpper, ErrNoDigit}},
+		{"Pass1", []error{ErrTooShort}},
+		{"weakpass", []error{ErrNoUpper, ErrNoDigit}},
+		{"NoDigitsHere", []error{ErrNoDigit}},
+		{"ALLUPPER123", []error{ErrNoLower}},
+		{"        ", []error{ErrNoUpper, ErrNoLower, ErrNoDigit}}, // Not trimmed
+		{"Password1", []error{ErrCommon}},
+		{"PASSWORD123!", []error{ErrNoLower, ErrCommon}},
+		{"Passw0rd", []error{ErrCommon}},
+		{"Letmein2026!?", []error{ErrCommon}},
+		{"12345678", []error{ErrNoUpper, ErrNoLower, ErrCommon}},
+		{"1Password", nil}, // Only what follows is stripped
+		{"Passwords1", nil},
+	}
+	for _, tt := range tests {
+		err := Default.Check(tt.pw)
+		if got := broken(err); !same(got, tt.want) {
+			t.Errorf("Check(%q) = %v\nbreaks %v, want %v", tt.pw, err, got, tt.want)
+		}
+		if (err == nil) != (tt.want == nil) {
+			t.Errorf("Check(%q) = %v: want nil exactly when no rule is broken", tt.pw, err)
+		}
+	}
+}
+
+func TestMessages(t *testing.T) {
+	err := Default.Check("Ééé1")
+	if want := "too short: 4 characters, want at least 8"; err == nil || err.Error() != want {
+		t.Errorf("Check = %v, want %q", err, want)
+	}
+	err = Default.Check("password")
+	want := "no upper case letter\nno digit\ntoo common: on the list of passwords attackers try first"
+	if err == nil || err.Error() != want {
+		t.Errorf("Check = %q, want %q", err, want)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	if err := (Policy{}).Check(""); err != nil {
+		t.Errorf("the zero Policy refused the empty password: %v", err)
+	}
+
+	symbols := Policy{Symbol: true}
+	for pw, ok := range map[string]bool{
+		"abc!": true, "a#b": true, "€": true, "©": true, "a_b": true,
+		"abc": false, "a b": false, "": false,
+	} {
+		if err := symbols.Check(pw); (err == nil) != ok {
+			t.Errorf("Symbol: Check(%q) = %v", pw, err)
+		}
+	}
+
+	deny := Policy{Deny: []string{"Acme", "acme-corp"}}
+	for pw, ok := range map[string]bool{
+		"acme": false, "ACME": false, "Acme2026!": false, "ACME-CORP1": false,
+		"acme corp": true, "myacme": true, "acmeX": true,
+		"": true, "123": true, // Nothing left after stripping isn't a match
+	} {
+		if err := deny.Check(pw); (err == nil) != ok {
+			t.Errorf("Deny: Check(%q) = %v", pw, err)
+		}
+	}
+
+	unlimited := Policy{MinLength: 1}
+	if err := unlimited.Check(strings.Repeat("x", 10000)); err != nil {
+		t.Errorf("MaxLength 0: %v", err)
+	}
+}
+
+// TestLookahead records why the package exists: the regular expression it
+// replaces doesn't compile in Go.
+func TestLookahead(t *testing.T) {
+	if _, err := regexp.Compile(`^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$`); err == nil {
+		t.Error("RE2 compiled a lookahead; Part 5 could use the regular expression after all")
+	}
+}
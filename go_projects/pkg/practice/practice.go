@@ -0,0 +1,454 @@
+// Package practice makes up exercises on the spot — fmt verbs from
+// Topic 71, regular expressions from intermediate Topic 73 — checks an
+// answer by running it, and schedules which concept to practise next
+// (gotut practice):
+//
+//	c, _ := practice.Get("fmt-zero-pad")
+//	p := c.New(rng)         // fmt.Sprintf(FORMAT, 42) == "000042"
+//	err := p.Check("%06d")  // nil: right; else what the answer did instead
+//
+// A flashcard (Topic 169) asks the same question every time, and after
+// a few reviews the learner remembers the card, not the verb. Here the
+// numbers, widths, words and strings are new each time, and an answer
+// isn't compared with a stored one: a format string goes through
+// fmt.Sprintf, a pattern through regexp. Any answer that behaves right
+// is right.
+//
+// Behaving right on the values shown isn't enough, though: "000042"
+// typed as the format gives "000042" too, and a|b|c|d|e matches five
+// given strings. Every problem keeps a few values it doesn't show, and
+// the answer has to work on those as well.
+//
+// Scheduling is Topic 169's Leitner system, per concept rather than per
+// card: a right answer moves a concept up a box and further out, a wrong
+// one back to box 1 and tomorrow. Schedule rebuilds the boxes from the
+// answers so far, so the caller stores only results, one per answer
+// (gotut keeps them in the progress log, pkg/progress).
+package practice
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Concept is one skill that problems are made for.
+type Concept struct {
+	Name  string // "fmt-zero-pad": how results and the command line refer to it
+	Topic int    // The lesson that teaches it
+	Title string // "zero-padded integers"
+	gen   func(*rand.Rand) Problem
+}
+
+// Problem is one generated exercise.
+type Problem struct {
+	Concept string
+	Prompt  string // What to write, with the values to use; may span lines
+	Example string // A right answer, to show after a wrong one
+	check   func(answer string) error
+}
+
+// New makes a problem for c from rng.
+func (c Concept) New(rng *rand.Rand) Problem {
+	p := c.gen(rng)
+	p.Concept = c.Name
+	return p
+}
+
+// Check runs answer and returns nil if it does what the problem asks, or
+// an error saying what it did instead. An answer in Go quotes, "%5d" or
+// `\d+`, is unquoted first.
+func (p Problem) Check(answer string) error {
+	if strings.HasPrefix(answer, `"`) || strings.HasPrefix(answer, "`") {
+		if s, err := strconv.Unquote(answer); err == nil {
+			answer = s
+		}
+	}
+	if answer == "" {
+		return errors.New("no answer")
+	}
+	return p.check(answer)
+}
+
+var concepts = []Concept{
+	{"fmt-zero-pad", 71, "zero-padded integers", zeroPad},
+	{"fmt-align", 71, "strings in a column, left or right", align},
+	{"fmt-float", 71, "decimals and width for floats", float},
+	{"fmt-hex", 71, "hexadecimal, with and without 0x", hex},
+	{"fmt-sign", 71, "a sign on positive numbers too", sign},
+	{"fmt-quote", 71, "quoted, escaped strings", quote},
+	{"fmt-go-syntax", 71, "values as Go source", goSyntax},
+	{"regex-date", 73, "ISO dates", date},
+	{"regex-hex-color", 73, "CSS hex colors", hexColor},
+	{"regex-version", 73, "version tags", version},
+	{"regex-identifier", 73, "snake_case names", identifier},
+}
+
+// Concepts returns every concept, Topic 71's first.
+func Concepts() []Concept { return slices.Clone(concepts) }
+
+// Get returns the concept called name.
+func Get(name string) (Concept, bool) {
+	i := slices.IndexFunc(concepts, func(c Concept) bool { return c.Name == name })
+	if i < 0 {
+		return Concept{}, false
+	}
+	return concepts[i], true
+}
+
+// ---------------------------------------------------------
+// fmt verbs: the answer is a format string
+// ---------------------------------------------------------
+
+// formatProblem asks for a format that turns shown into what model makes
+// of it, and holds the answer to the same on each hidden value.
+func formatProblem(model, what string, shown any, hidden ...any) Problem {
+	return Problem{
+		Prompt:  fmt.Sprintf("%s\n  fmt.Sprintf(FORMAT, %#v) == %q", what, shown, fmt.Sprintf(model, shown)),
+		Example: model,
+		check: func(answer string) error {
+			if !strings.Contains(answer, "%") {
+				return fmt.Errorf("%q has no verb: it prints the same whatever the value", answer)
+			}
+			for i, v := range append([]any{shown}, hidden...) {
+				got, want := fmt.Sprintf(answer, v), fmt.Sprintf(model, v)
+				switch {
+				case got == want:
+				case i == 0:
+					return fmt.Errorf("%s gives %q", answer, got)
+				default:
+					return fmt.Errorf("right for %#v, but with %#v it gives %q, not %q", shown, v, got, want)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func zeroPad(rng *rand.Rand) Problem {
+	width := 4 + rng.IntN(5)
+	n := 1 + rng.IntN(999)
+	return formatProblem(fmt.Sprintf("%%0%dd", width), fmt.Sprintf("Zero-pad the number to %d digits.", width),
+		n, 1+rng.IntN(9), -n)
+}
+
+var words = []string{"go", "chan", "defer", "select", "struct", "rune", "slice", "iota", "panic", "goroutine"}
+
+func align(rng *rand.Rand) Problem {
+	width := 8 + rng.IntN(8)
+	w := rng.Perm(len(words))
+	if rng.IntN(2) == 0 {
+		return formatProblem(fmt.Sprintf("%%-%ds", width), fmt.Sprintf("Left-align the word in %d columns.", width),
+			words[w[0]], words[w[1]], words[w[2]])
+	}
+	return formatProblem(fmt.Sprintf("%%%ds", width), fmt.Sprintf("Right-align the word in %d columns.", width),
+		words[w[0]], words[w[1]], words[w[2]])
+}
+
+func float(rng *rand.Rand) Problem {
+	prec := 1 + rng.IntN(3)
+	width := prec + 4 + rng.IntN(4)
+	x := func() float64 { return float64(rng.IntN(100000)) / 997 }
+	return formatProblem(fmt.Sprintf("%%%d.%df", width, prec),
+		fmt.Sprintf("Round to %d decimals, right-aligned in %d columns.", prec, width), x(), x(), -x())
+}
+
+func hex(rng *rand.Rand) Problem {
+	variants := []struct{ model, what string }{
+		{"%x", "Write the number in lower case hexadecimal."},
+		{"%X", "Write the number in upper case hexadecimal."},
+		{"%#x", "Write the number in hexadecimal with a 0x prefix."},
+		{"%08x", "Write the number in hexadecimal, zero-padded to 8 digits."},
+	}
+	v := variants[rng.IntN(len(variants))]
+	return formatProblem(v.model, v.what, 10+rng.IntN(4000), 171+rng.IntN(50000))
+}
+
+func sign(rng *rand.Rand) Problem {
+	n := 1 + rng.IntN(500)
+	return formatProblem("%+d", "Print the number with its sign, + or -.", n, -n, 0)
+}
+
+var quotable = []string{`say "hi"`, "tab\there", "line\nbreak", `C:\temp`, "plain", `it's "done"`}
+
+func quote(rng *rand.Rand) Problem {
+	w := rng.Perm(len(quotable))
+	return formatProblem("%q", "Print the string as a Go string literal, quoted and escaped.",
+		quotable[w[0]], quotable[w[1]], quotable[w[2]])
+}
+
+func goSyntax(rng *rand.Rand) Problem {
+	ints := func() []int {
+		s := make([]int, 1+rng.IntN(4))
+		for i := range s {
+			s[i] = rng.IntN(100)
+		}
+		return s
+	}
+	return formatProblem("%#v", "Print the value the way you'd write it in Go source.",
+		ints(), map[string]int{words[rng.IntN(len(words))]: rng.IntN(10)}, words[rng.IntN(len(words))])
+}
+
+// ---------------------------------------------------------
+// Regular expressions: the answer is a pattern
+// ---------------------------------------------------------
+
+const (
+	regexShow   = 5 // Strings to match, shown
+	regexReject = 3 // Strings not to match, shown
+	regexHidden = 5 // Of each, not shown
+)
+
+// regexProblem generates strings from yes and no until it has enough of
+// each that model accepts and rejects, and asks for a pattern that sorts
+// them the same way. The whole string has to match, as if the answer
+// were wrapped in ^(?:...)$.
+func regexProblem(rng *rand.Rand, model, what string, yes, no func(*rand.Rand) string) Problem {
+	rx := regexp.MustCompile(`^(?:` + model + `)$`)
+	collect := func(gen func(*rand.Rand) string, match bool, n int) []string {
+		seen := map[string]bool{}
+		var out []string
+		for tries := 0; len(out) < n && tries < 1000; tries++ {
+			if s := gen(rng); !seen[s] && rx.MatchString(s) == match {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	match := collect(yes, true, regexShow+regexHidden)
+	reject := collect(no, false, regexReject+regexHidden)
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "%s The whole string must match.\n", what)
+	fmt.Fprintf(&prompt, "  match:       %s\n", quoteAll(match[:regexShow]))
+	fmt.Fprintf(&prompt, "  don't match: %s", quoteAll(reject[:regexReject]))
+	return Problem{
+		Prompt:  prompt.String(),
+		Example: model,
+		check: func(answer string) error {
+			if _, err := regexp.Compile(answer); err != nil {
+				return fmt.Errorf("doesn't compile: %v", err)
+			}
+			re := regexp.MustCompile(`^(?:` + answer + `)$`)
+			for i, s := range match {
+				if !re.MatchString(s) {
+					if i < regexShow {
+						return fmt.Errorf("doesn't match %q", s)
+					}
+					return fmt.Errorf("right for the examples, but doesn't match %q: too narrow", s)
+				}
+			}
+			for i, s := range reject {
+				if re.MatchString(s) {
+					if i < regexReject {
+						return fmt.Errorf("matches %q too", s)
+					}
+					return fmt.Errorf("right for the examples, but matches %q too: too loose", s)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func quoteAll(ss []string) string {
+	q := make([]string, len(ss))
+	for i, s := range ss {
+		q[i] = strconv.Quote(s)
+	}
+	return strings.Join(q, " ")
+}
+
+// digits returns n random decimal digits.
+func digits(rng *rand.Rand, n int) string {
+	var b strings.Builder
+	for range n {
+		b.WriteByte(byte('0' + rng.IntN(10)))
+	}
+	return b.String()
+}
+
+func date(rng *rand.Rand) Problem {
+	d := func(rng *rand.Rand) (int, int, int) { return 1990 + rng.IntN(50), 1 + rng.IntN(12), 1 + rng.IntN(28) }
+	return regexProblem(rng, `\d{4}-\d{2}-\d{2}`, "Match dates written YYYY-MM-DD.",
+		func(rng *rand.Rand) string {
+			y, m, day := d(rng)
+			return fmt.Sprintf("%d-%02d-%02d", y, m, day)
+		},
+		func(rng *rand.Rand) string {
+			y, m, day := d(rng)
+			return fmt.Sprintf([]string{"%d/%02d/%02d", "%d-%d-%d", "%d-%02d-%02dT10:00", "%d.%02d.%02d", "%d%02d%02d"}[rng.IntN(5)], y, m, day)
+		})
+}
+
+const hexDigits = "0123456789abcdefABCDEF"
+
+func hexColor(rng *rand.Rand) Problem {
+	hx := func(rng *rand.Rand, n int) string {
+		var b strings.Builder
+		for range n {
+			b.WriteByte(hexDigits[rng.IntN(len(hexDigits))])
+		}
+		return b.String()
+	}
+	return regexProblem(rng, `#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})`, "Match CSS colors: # and then 3 or 6 hex digits.",
+		func(rng *rand.Rand) string { return "#" + hx(rng, []int{3, 6}[rng.IntN(2)]) },
+		func(rng *rand.Rand) string {
+			switch rng.IntN(4) {
+			case 0:
+				return hx(rng, 6) // No #
+			case 1:
+				return "#" + hx(rng, []int{2, 4, 5, 7}[rng.IntN(4)])
+			case 2:
+				s := []byte(hx(rng, 6))
+				s[rng.IntN(6)] = "ghxz"[rng.IntN(4)]
+				return "#" + string(s)
+			}
+			return "##" + hx(rng, 3)
+		})
+}
+
+func version(rng *rand.Rand) Problem {
+	n := func(rng *rand.Rand) string { return digits(rng, 1+rng.IntN(2)) }
+	return regexProblem(rng, `v\d+\.\d+\.\d+`, "Match version tags like v1.22.3.",
+		func(rng *rand.Rand) string { return "v" + n(rng) + "." + n(rng) + "." + n(rng) },
+		func(rng *rand.Rand) string {
+			a, b, c := n(rng), n(rng), n(rng)
+			return []string{a + "." + b + "." + c, "v" + a + "." + b, "v" + a + "." + b + "." + c + "." + n(rng),
+				"V" + a + "." + b + "." + c, "v" + a + ".x." + c, "v" + a + "-" + b + "-" + c}[rng.IntN(6)]
+		})
+}
+
+var nameParts = []string{"user", "id", "max", "retries", "http", "client", "count", "item", "last", "seen"}
+
+func identifier(rng *rand.Rand) Problem {
+	name := func(rng *rand.Rand) string {
+		parts := make([]string, 1+rng.IntN(3))
+		for i := range parts {
+			parts[i] = nameParts[rng.IntN(len(nameParts))]
+			if rng.IntN(4) == 0 {
+				parts[i] += digits(rng, 1)
+			}
+		}
+		return strings.Join(parts, "_")
+	}
+	return regexProblem(rng, `[a-z][a-z0-9]*(_[a-z0-9]+)*`,
+		"Match snake_case names: lower case words and digits joined by single underscores, starting with a letter.",
+		name,
+		func(rng *rand.Rand) string {
+			s := name(rng)
+			switch rng.IntN(5) {
+			case 0:
+				return digits(rng, 1) + s
+			case 1:
+				return strings.ToUpper(s[:1]) + s[1:]
+			case 2:
+				return "_" + s
+			case 3:
+				return s + "_"
+			}
+			return s + "-" + name(rng)
+		})
+}
+
+// ---------------------------------------------------------
+// Scheduling
+// ---------------------------------------------------------
+
+// Result is one answer, as the caller stores it.
+type Result struct {
+	Concept string
+	Right   bool
+	Time    time.Time
+}
+
+// Intervals[b] is how long a concept in box b+1 waits before it is due
+// again: Topic 169's cards' gaps.
+var Intervals = []time.Duration{1 * day, 3 * day, 7 * day, 14 * day, 30 * day}
+
+const day = 24 * time.Hour
+
+// Stat is what the results say about one concept.
+type Stat struct {
+	Concept      string
+	Tries, Right int
+	Box          int       // 1..len(Intervals); 0 for a concept never tried
+	Due          time.Time // Zero for a concept never tried: due now
+}
+
+// Accuracy is the share of tries that were right, 0 before the first.
+func (s Stat) Accuracy() float64 {
+	if s.Tries == 0 {
+		return 0
+	}
+	return float64(s.Right) / float64(s.Tries)
+}
+
+// IsDue reports whether the concept should be practised at now.
+func (s Stat) IsDue(now time.Time) bool { return !now.Before(s.Due) }
+
+// review applies one answer, as Topic 169's Recall.Review does a card's.
+// A concept never tried starts in box 1.
+func (s Stat) review(right bool, at time.Time) Stat {
+	if s.Box == 0 {
+		s.Box = 1
+	}
+	s.Tries++
+	if right {
+		s.Right++
+		s.Box = min(s.Box+1, len(Intervals))
+	} else {
+		s.Box = 1
+	}
+	y, m, d := at.Date()
+	s.Due = time.Date(y, m, d, 0, 0, 0, 0, at.Location()).Add(Intervals[s.Box-1])
+	return s
+}
+
+// Schedule replays results, oldest first, and returns a Stat for every
+// concept in the order to practise them: those due at now first, lowest
+// box first and then least accurate, and after them the rest by due
+// date. Ties keep the order of Concepts. Results for concepts this
+// package no longer has are ignored.
+func Schedule(results []Result, now time.Time) []Stat {
+	stats := make([]Stat, len(concepts))
+	index := map[string]int{}
+	for i, c := range concepts {
+		stats[i].Concept = c.Name
+		index[c.Name] = i
+	}
+	results = slices.Clone(results)
+	slices.SortStableFunc(results, func(a, b Result) int { return a.Time.Compare(b.Time) })
+	for _, r := range results {
+		if i, ok := index[r.Concept]; ok {
+			stats[i] = stats[i].review(r.Right, r.Time)
+		}
+	}
+	slices.SortStableFunc(stats, func(a, b Stat) int {
+		aDue, bDue := a.IsDue(now), b.IsDue(now)
+		switch {
+		case aDue != bDue:
+			if aDue {
+				return -1
+			}
+			return 1
+		case !aDue:
+			return a.Due.Compare(b.Due)
+		case a.Box != b.Box:
+			return a.Box - b.Box
+		}
+		switch {
+		case a.Accuracy() < b.Accuracy():
+			return -1
+		case a.Accuracy() > b.Accuracy():
+			return 1
+		}
+		return 0
+	})
+	return stats
+}
@@ -0,0 +1,168 @@
+package practice
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExamples makes many problems of every concept and checks that each
+// one's own example passes: a generator can't ship a problem nobody can
+// answer.
+func TestExamples(t *testing.T) {
+	for _, c := range Concepts() {
+		rng := rand.New(rand.NewPCG(1, uint64(len(c.Name))))
+		for range 200 {
+			p := c.New(rng)
+			if p.Concept != c.Name || p.Prompt == "" {
+				t.Fatalf("%s: problem %+v", c.Name, p)
+			}
+			if err := p.Check(p.Example); err != nil {
+				t.Fatalf("%s: the example %q fails: %v\n%s", c.Name, p.Example, err, p.Prompt)
+			}
+		}
+	}
+}
+
+func problem(t *testing.T, name string, seed uint64) Problem {
+	t.Helper()
+	c, ok := Get(name)
+	if !ok {
+		t.Fatalf("no concept %q", name)
+	}
+	return c.New(rand.New(rand.NewPCG(seed, seed)))
+}
+
+func TestFormat(t *testing.T) {
+	p := problem(t, "fmt-sign", 1)
+	// The output the prompt shows, typed as the format.
+	shown := p.Prompt[strings.LastIndex(p.Prompt, `== "`)+4 : len(p.Prompt)-1]
+
+	for _, tt := range []struct {
+		answer string
+		want   string // In the error; "" for right
+	}{
+		{"%+d", ""},
+		{`"%+d"`, ""}, // Quoted
+		{"`%+d`", ""},
+		{"%+1d", ""}, // Any answer that behaves right is right
+		{"%d", "gives"},
+		{"%+s", "gives"},
+		{shown, "has no verb"},
+		{"+%d", "but with"}, // Right for the positive number shown, not for the hidden negative one
+		{"", "no answer"},
+	} {
+		err := p.Check(tt.answer)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("Check(%q) = %v, want right", tt.answer, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("Check(%q) = %v, want an error mentioning %q", tt.answer, err, tt.want)
+		}
+	}
+}
+
+func TestRegex(t *testing.T) {
+	p := problem(t, "regex-date", 2)
+	lines := strings.Split(p.Prompt, "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "match:") || !strings.Contains(lines[2], "don't match:") {
+		t.Fatalf("prompt:\n%s", p.Prompt)
+	}
+	shown := strings.Fields(strings.TrimPrefix(strings.TrimSpace(lines[1]), "match:"))
+	if len(shown) != regexShow {
+		t.Fatalf("%d strings to match shown, want %d:\n%s", len(shown), regexShow, p.Prompt)
+	}
+	var alternation []string
+	for _, s := range shown {
+		alternation = append(alternation, strings.Trim(s, `"`))
+	}
+
+	for _, tt := range []struct {
+		answer string
+		want   string
+	}{
+		{`\d{4}-\d{2}-\d{2}`, ""},
+		{`[0-9]{4}-[0-9]{2}-[0-9]{2}`, ""},
+		{`\d+-\d+-\d+`, "too loose"},
+		{`^\d{4}-\d\d-\d\d$`, ""}, // Anchors of its own are harmless
+		{`\d{4}-\d{2}`, "doesn't match"},
+		{`.*`, "matches"},
+		{`\d{4}.\d{2}.\d{2}`, "too"},
+		{strings.Join(alternation, "|"), "too narrow"},
+		{`\d{4}-(\d{2}`, "doesn't compile"},
+	} {
+		err := p.Check(tt.answer)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("Check(%q) = %v, want right", tt.answer, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("Check(%q) = %v, want an error mentioning %q", tt.answer, err, tt.want)
+		}
+	}
+}
+
+func TestRandomized(t *testing.T) {
+	a, b := problem(t, "fmt-zero-pad", 1), problem(t, "fmt-zero-pad", 1)
+	if a.Prompt != b.Prompt {
+		t.Errorf("the same seed made different problems:\n%s\n%s", a.Prompt, b.Prompt)
+	}
+	prompts := map[string]bool{}
+	for seed := range uint64(20) {
+		prompts[problem(t, "fmt-zero-pad", seed).Prompt] = true
+	}
+	if len(prompts) < 10 {
+		t.Errorf("20 seeds made only %d different problems", len(prompts))
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	results := []Result{
+		{"fmt-hex", true, monday.Add(time.Hour)},
+		{"fmt-zero-pad", true, monday},
+		{"fmt-zero-pad", true, monday.AddDate(0, 0, 1)},
+		{"regex-date", false, monday},
+		{"fmt-sign", true, monday},
+		{"fmt-sign", false, monday.Add(time.Minute)},
+		{"fmt-sign", true, monday.Add(2 * time.Minute)},
+		{"gone", true, monday}, // A concept since removed
+	}
+	stats := Schedule(results, monday.AddDate(0, 0, 2))
+	if len(stats) != len(Concepts()) {
+		t.Fatalf("%d stats, want one per concept", len(stats))
+	}
+	by := map[string]Stat{}
+	for _, s := range stats {
+		by[s.Concept] = s
+	}
+
+	zp := by["fmt-zero-pad"]
+	if zp.Tries != 2 || zp.Right != 2 || zp.Box != 3 || !zp.Due.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("fmt-zero-pad: %+v, want box 3, due a week after Tuesday", zp)
+	}
+	if s := by["fmt-sign"]; s.Tries != 3 || s.Box != 2 || s.Accuracy() != 2.0/3 {
+		t.Errorf("fmt-sign: %+v, want back to box 1 and up again, 2 of 3", s)
+	}
+	if s := by["regex-version"]; s.Tries != 0 || s.Box != 0 || !s.IsDue(monday) || s.Accuracy() != 0 {
+		t.Errorf("regex-version: %+v, want untried and due", s)
+	}
+
+	// On Wednesday: the untried concepts (box 0) in package order, then
+	// regex-date (box 1), then the rest by due date.
+	var order []string
+	for _, s := range stats {
+		order = append(order, s.Concept)
+	}
+	want := []string{
+		"fmt-align", "fmt-float", "fmt-quote", "fmt-go-syntax", // Box 0
+		"regex-hex-color", "regex-version", "regex-identifier",
+		"regex-date",   // Box 1, due since Tuesday
+		"fmt-hex",      // Box 2, due Thursday
+		"fmt-sign",     // Box 2, due Thursday too: a tie keeps package order
+		"fmt-zero-pad", // Box 3, due next Tuesday
+	}
+	if strings.Join(order, " ") != strings.Join(want, " ") {
+		t.Errorf("order:\n got %v\nwant %v", order, want)
+	}
+}
@@ -0,0 +1,143 @@
+// Package progress is the learner's record: what they tried, when, and
+// how it went, kept in progress.json next to the other gotut files (168's
+// notes, 169's cards, 190's config.json):
+//
+//	path, _ := progress.Path()
+//	progress.Record(path, progress.Event{Kind: "kata", Item: "dedupe", Result: "pass"})
+//	log, _ := progress.Load(path)
+//	last, ok := log.Last("kata", "dedupe")
+//
+// The log is append-only. Tools add events and never rewrite old ones, so
+// a report can be rebuilt from it at any time (Topic 192 and onward).
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Event is one thing the learner did. Kind says which tool recorded it
+// ("kata", ...), Item what it was about (a kata's name, a topic number),
+// and Result how it ended; the values are the recording tool's own.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Item    string    `json:"item"`
+	Result  string    `json:"result"`
+	Detail  string    `json:"detail,omitempty"`
+	Seconds float64   `json:"seconds,omitempty"` // How long it took, when that means something
+}
+
+// Log is the whole file.
+type Log struct {
+	Version int     `json:"version"`
+	Events  []Event `json:"events"`
+}
+
+// Path is progress.json in $GOTUT_CONFIG_DIR, else <config dir>/gotut.
+func Path() (string, error) {
+	dir := os.Getenv("GOTUT_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, "gotut")
+	}
+	return filepath.Join(dir, "progress.json"), nil
+}
+
+// Load reads the log at path. No file is an empty log: nothing done yet.
+func Load(path string) (*Log, error) {
+	l := &Log{Version: 1}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %v", path, err)
+	}
+	return l, nil
+}
+
+// Record appends e to the log at path, stamping it with the current time
+// if it has none. The file is replaced by rename (see 168), so a crash
+// leaves the old log or the new one, never half of each. Two tools
+// recording at the same instant can still lose one event; gotut runs one
+// command at a time.
+func Record(path string, e Event) error {
+	l, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	l.Events = append(l.Events, e)
+	return save(path, l)
+}
+
+func save(path string, l *Log) (err error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "progress.json.tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Last returns the latest event of kind about item, restricted to the
+// given results if there are any.
+func (l *Log) Last(kind, item string, results ...string) (Event, bool) {
+	for i := len(l.Events) - 1; i >= 0; i-- {
+		e := l.Events[i]
+		if e.Kind != kind || e.Item != item {
+			continue
+		}
+		if len(results) == 0 || slices.Contains(results, e.Result) {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
+// Filter returns the events of kind, oldest first; "" means every kind.
+func (l *Log) Filter(kind string) []Event {
+	var out []Event
+	for _, e := range l.Events {
+		if kind == "" || e.Kind == kind {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gotut", "progress.json")
+	l, err := Load(path)
+	if err != nil || len(l.Events) != 0 {
+		t.Fatalf("Load of a missing file = %v, %v; want an empty log", l, err)
+	}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, e := range []Event{
+		{Time: start, Kind: "kata", Item: "dedupe", Result: "started"},
+		{Kind: "kata", Item: "dedupe", Result: "fail", Detail: "2/4"},
+		{Kind: "hint", Item: "73", Result: "level 1"},
+		{Kind: "kata", Item: "dedupe", Result: "pass", Seconds: 95},
+	} {
+		if err := Record(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Events) != 4 || !l.Events[0].Time.Equal(start) || l.Events[1].Time.IsZero() {
+		t.Fatalf("events = %+v", l.Events)
+	}
+	if e, ok := l.Last("kata", "dedupe"); !ok || e.Result != "pass" || e.Seconds != 95 {
+		t.Errorf("Last(kata, dedupe) = %+v, %v", e, ok)
+	}
+	if e, ok := l.Last("kata", "dedupe", "started"); !ok || !e.Time.Equal(start) {
+		t.Errorf("Last(kata, dedupe, started) = %+v, %v", e, ok)
+	}
+	if _, ok := l.Last("kata", "trimprefixfold"); ok {
+		t.Error("Last found a kata never tried")
+	}
+	if got := len(l.Filter("kata")); got != 3 {
+		t.Errorf("Filter(kata) has %d events, want 3", got)
+	}
+	if got := len(l.Filter("")); got != 4 {
+		t.Errorf("Filter(\"\") has %d events, want 4", got)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	os.WriteFile(path, []byte(`{"events": [`), 0o644)
+	if _, err := Load(path); err == nil {
+		t.Error("Load of a corrupt file succeeded")
+	}
+	if err := Record(path, Event{Kind: "kata"}); err == nil {
+		t.Error("Record over a corrupt file succeeded: it would have thrown the history away")
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv("GOTUT_CONFIG_DIR", "/tmp/gotut-x")
+	if p, err := Path(); err != nil || p != filepath.Join("/tmp/gotut-x", "progress.json") {
+		t.Errorf("Path() = %q, %v", p, err)
+	}
+}
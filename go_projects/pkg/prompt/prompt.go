@@ -0,0 +1,41 @@
+// Package prompt asks a person yes or no (Topic 174's rm -i, and
+// fileops.SafeOptions.Confirm):
+//
+//	opts.Confirm = prompt.New(os.Stdin, os.Stdout).YesNo
+//
+// It reads from any io.Reader, which keeps it testable, and the default
+// is NO: an empty line, EOF or anything unexpected is never a yes.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompt asks on Out and reads the answer from In.
+type Prompt struct {
+	In  *bufio.Reader
+	Out io.Writer
+}
+
+// New returns a Prompt reading in and writing out.
+func New(in io.Reader, out io.Writer) *Prompt {
+	return &Prompt{In: bufio.NewReader(in), Out: out}
+}
+
+// YesNo asks question and accepts "y" or "yes" (any case) as yes.
+func (p *Prompt) YesNo(question string) bool {
+	fmt.Fprintf(p.Out, "%s [y/N] ", question)
+	line, err := p.In.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.Out)
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYesNo(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want bool
+	}{
+		{"y\n", true},
+		{"Yes\n", true},
+		{"  yes  \n", true},
+		{"y", true}, // The last line, without its newline
+		{"n\n", false},
+		{"\n", false},
+		{"", false}, // EOF: nobody is there to say yes
+		{"yep\n", false},
+	} {
+		var out strings.Builder
+		if got := New(strings.NewReader(tt.in), &out).YesNo("remove build?"); got != tt.want {
+			t.Errorf("YesNo with %q = %v, want %v", tt.in, got, tt.want)
+		}
+		if !strings.HasPrefix(out.String(), "remove build? [y/N] ") {
+			t.Errorf("asked %q", out.String())
+		}
+	}
+
+	// One Prompt asks again and again, each answer on its own line.
+	p := New(strings.NewReader("y\nn\ny\n"), &strings.Builder{})
+	for i, want := range []bool{true, false, true, false} {
+		if got := p.YesNo("again?"); got != want {
+			t.Errorf("answer %d = %v, want %v", i+1, got, want)
+		}
+	}
+}
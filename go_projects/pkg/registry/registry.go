@@ -0,0 +1,186 @@
+// Package registry is the course's table of topics, filled in by the
+// topics themselves from init, the pattern Topic 193's workspace uses
+// (and 180 weighs against a generated index):
+//
+//	func init() {
+//		registry.Register(registry.Topic{ID: 82, Slug: "sha", Title: "SHA hashes",
+//			Files: []string{"intermediate_topics/82_sha_detailed.go"}, Aliases: []string{"sha256"}})
+//	}
+//	...
+//	t, ok := registry.Lookup("sha")        // or "82", or the alias "sha256"
+//
+// A topic is one number; the course has numbers with several files (73
+// has six), so Files is a list and the number is still unique. Every name
+// a topic answers to — its ID, its slug, its aliases — finds only it:
+// registering a second topic with any of the same names is a bug in the
+// course, and Register panics saying which two registrations collided, as
+// database/sql does for a driver registered twice. Aliases keep old names
+// working after a renumbering: the topic that was 82 answers to "82".
+//
+// Registration and lookups are safe from any goroutine. Default is one
+// registry per process — "go test ./..." gives every package its own —
+// so tests inside a package that want a clean table use New.
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Topic is one entry.
+type Topic struct {
+	ID      int      // The topic number, > 0
+	Slug    string   // "sha": lower-case words and digits joined by "-", not all digits
+	Title   string   // "SHA hashes"
+	Files   []string // Its lesson files and directories, relative to the repository
+	Aliases []string // Old numbers or slugs that find it too
+}
+
+// Registry is a set of topics that share one namespace of names.
+type Registry struct {
+	mu     sync.RWMutex
+	topics map[int]*entry
+	names  map[string]*entry // Slug, aliases and the ID in decimal, all to their entry
+}
+
+type entry struct {
+	Topic
+	at string // file:line of the Register call, for the duplicate message
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{topics: map[int]*entry{}, names: map[string]*entry{}}
+}
+
+// Default is the registry the package-level functions use.
+var Default = New()
+
+var slugRx = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Register adds t. It returns an error, and adds nothing, if t is
+// malformed or any of its names is taken.
+func (r *Registry) Register(t Topic) error {
+	return r.register(t, caller(1))
+}
+
+// MustRegister is Register that panics on error, for init functions: a
+// duplicate is a bug to fix, not a condition to handle.
+func (r *Registry) MustRegister(t Topic) {
+	if err := r.register(t, caller(1)); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) register(t Topic, at string) error {
+	if t.ID <= 0 {
+		return fmt.Errorf("registry: topic %q: ID %d is not a positive topic number", t.Slug, t.ID)
+	}
+	if err := checkSlug(t.Slug); err != nil {
+		return fmt.Errorf("registry: topic %d: slug %w", t.ID, err)
+	}
+	t.Files = slices.Clone(t.Files)
+	t.Aliases = slices.Clone(t.Aliases)
+	names := append([]string{strconv.Itoa(t.ID), t.Slug}, t.Aliases...)
+	for i, a := range t.Aliases {
+		if _, err := strconv.Atoi(a); err != nil && checkSlug(a) != nil {
+			return fmt.Errorf("registry: topic %d: alias %q is neither a number nor a slug", t.ID, a)
+		}
+		if slices.Contains(names[:2+i], a) {
+			return fmt.Errorf("registry: topic %d: alias %q repeats one of its own names", t.ID, a)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		if old, ok := r.names[name]; ok {
+			return fmt.Errorf("registry: duplicate name %q: topic %d %q (registered at %s) and topic %d %q (at %s)",
+				name, old.ID, old.Slug, old.at, t.ID, t.Slug, at)
+		}
+	}
+	e := &entry{Topic: t, at: at}
+	r.topics[t.ID] = e
+	for _, name := range names {
+		r.names[name] = e
+	}
+	return nil
+}
+
+// checkSlug rejects what would not make a clean URL path segment, and
+// all-digit slugs, which would shadow topic numbers.
+func checkSlug(s string) error {
+	if !slugRx.MatchString(s) {
+		return fmt.Errorf("%q: want lower-case words and digits joined by \"-\"", s)
+	}
+	if _, err := strconv.Atoi(s); err == nil {
+		return fmt.Errorf("%q is all digits; it would read as a topic number", s)
+	}
+	return nil
+}
+
+// caller is the file:line that called the function calling caller(1);
+// each extra skip goes one frame further up.
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// Lookup finds a topic by number, slug or alias.
+func (r *Registry) Lookup(name string) (Topic, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.names[name]
+	if !ok {
+		return Topic{}, false
+	}
+	return e.copy(), true
+}
+
+// All returns every topic, by ID.
+func (r *Registry) All() []Topic {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make([]Topic, 0, len(r.topics))
+	for _, e := range r.topics {
+		all = append(all, e.copy())
+	}
+	slices.SortFunc(all, func(a, b Topic) int { return a.ID - b.ID })
+	return all
+}
+
+// Len is how many topics are registered.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.topics)
+}
+
+// copy is the Topic with its own slices, so a caller can't change the
+// registry's through the ones it was given.
+func (e *entry) copy() Topic {
+	t := e.Topic
+	t.Files = slices.Clone(t.Files)
+	t.Aliases = slices.Clone(t.Aliases)
+	return t
+}
+
+// Register adds t to Default and panics if it can't: call it from init.
+func Register(t Topic) {
+	if err := Default.register(t, caller(1)); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup finds a topic in Default.
+func Lookup(name string) (Topic, bool) { return Default.Lookup(name) }
+
+// All returns Default's topics, by ID.
+func All() []Topic { return Default.All() }
@@ -0,0 +1,212 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func sha() Topic {
+	return Topic{ID: 82, Slug: "sha", Title: "SHA hashes", Files: []string{"intermediate_topics/82_sha_detailed.go"}, Aliases: []string{"sha256", "12"}}
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	r := New()
+	if err := r.Register(sha()); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"82", "sha", "sha256", "12"} {
+		if got, ok := r.Lookup(name); !ok || got.ID != 82 || got.Title != "SHA hashes" {
+			t.Errorf("Lookup(%q) = %+v, %v; want topic 82", name, got, ok)
+		}
+	}
+	for _, name := range []string{"", "83", "SHA", "sha-", "082"} {
+		if got, ok := r.Lookup(name); ok {
+			t.Errorf("Lookup(%q) = %+v, want nothing", name, got)
+		}
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name  string
+		topic Topic
+		dup   string
+	}{
+		{"same ID", Topic{ID: 82, Slug: "hashing"}, `"82"`},
+		{"same slug", Topic{ID: 83, Slug: "sha"}, `"sha"`},
+		{"alias is a taken slug", Topic{ID: 83, Slug: "hmac", Aliases: []string{"sha"}}, `"sha"`},
+		{"alias is a taken alias", Topic{ID: 83, Slug: "hmac", Aliases: []string{"sha256"}}, `"sha256"`},
+		{"alias is a taken number", Topic{ID: 83, Slug: "hmac", Aliases: []string{"82"}}, `"82"`},
+		{"ID is a taken alias", Topic{ID: 12, Slug: "hmac"}, `"12"`},
+		{"slug is a taken alias", Topic{ID: 83, Slug: "sha256"}, `"sha256"`},
+	} {
+		r := New()
+		r.MustRegister(sha())
+		err := r.Register(tc.topic)
+		if err == nil {
+			t.Errorf("%s: Register(%+v) succeeded", tc.name, tc.topic)
+			continue
+		}
+		// The message names the clash and both registrations, with where
+		// each one came from.
+		for _, want := range []string{"duplicate name " + tc.dup, "topic 82 \"sha\"", "registry_test.go:"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: %v\nwant it to mention %s", tc.name, err, want)
+			}
+		}
+		if strings.Count(err.Error(), "registry_test.go:") != 2 {
+			t.Errorf("%s: %v\nwant both registration sites", tc.name, err)
+		}
+		if r.Len() != 1 {
+			t.Errorf("%s: a failed Register left %d topics, want 1", tc.name, r.Len())
+		}
+		if _, ok := r.Lookup("hmac"); ok {
+			t.Errorf("%s: a failed Register left its slug behind", tc.name)
+		}
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		topic Topic
+		want  string
+	}{
+		{Topic{ID: 0, Slug: "zero"}, "not a positive topic number"},
+		{Topic{ID: -3, Slug: "minus"}, "not a positive topic number"},
+		{Topic{ID: 5, Slug: ""}, "want lower-case words"},
+		{Topic{ID: 5, Slug: "Go-Basics"}, "want lower-case words"},
+		{Topic{ID: 5, Slug: "go basics"}, "want lower-case words"},
+		{Topic{ID: 5, Slug: "go--basics"}, "want lower-case words"},
+		{Topic{ID: 5, Slug: "-go"}, "want lower-case words"},
+		{Topic{ID: 5, Slug: "42"}, "all digits"},
+		{Topic{ID: 5, Slug: "basics", Aliases: []string{"old name"}}, "neither a number nor a slug"},
+		{Topic{ID: 5, Slug: "basics", Aliases: []string{"basics"}}, "repeats one of its own names"},
+		{Topic{ID: 5, Slug: "basics", Aliases: []string{"5"}}, "repeats one of its own names"},
+		{Topic{ID: 5, Slug: "basics", Aliases: []string{"intro", "intro"}}, "repeats one of its own names"},
+	} {
+		err := New().Register(tc.topic)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Register(%+v) = %v, want an error mentioning %q", tc.topic, err, tc.want)
+		}
+	}
+}
+
+func TestMustRegisterPanics(t *testing.T) {
+	t.Parallel()
+	r := New()
+	r.MustRegister(sha())
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !strings.Contains(err.Error(), `duplicate name "sha"`) {
+			t.Errorf("MustRegister of a duplicate panicked with %v, want the duplicate error", err)
+		}
+	}()
+	r.MustRegister(Topic{ID: 83, Slug: "sha"})
+	t.Error("MustRegister of a duplicate returned")
+}
+
+func TestCopies(t *testing.T) {
+	t.Parallel()
+	r := New()
+	topic := sha()
+	r.MustRegister(topic)
+	topic.Files[0] = "changed by the caller"
+	got, _ := r.Lookup("sha")
+	got.Aliases[0] = "changed through Lookup"
+	r.All()[0].Files[0] = "changed through All"
+	if again, _ := r.Lookup("sha"); !slices.Equal(again.Files, sha().Files) || !slices.Equal(again.Aliases, sha().Aliases) {
+		t.Errorf("the registry's topic changed from outside: %+v", again)
+	}
+}
+
+func TestAllSorted(t *testing.T) {
+	t.Parallel()
+	r := New()
+	for _, id := range []int{190, 57, 103, 138} {
+		r.MustRegister(Topic{ID: id, Slug: fmt.Sprintf("t%d", id)})
+	}
+	var ids []int
+	for _, topic := range r.All() {
+		ids = append(ids, topic.ID)
+	}
+	if want := []int{57, 103, 138, 190}; !slices.Equal(ids, want) {
+		t.Errorf("All() IDs = %v, want %v", ids, want)
+	}
+}
+
+// TestConcurrent registers from many goroutines while others read, as
+// parallel tests sharing Default would; run it with -race. Each ID is
+// also raced for by two goroutines with different slugs: exactly one of
+// each pair may win.
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+	r := New()
+	const n = 200
+	var wg sync.WaitGroup
+	var wins, losses atomic.Int32
+	for i := 1; i <= n; i++ {
+		for _, slug := range []string{"a", "b"} {
+			wg.Go(func() {
+				err := r.Register(Topic{ID: i, Slug: fmt.Sprintf("%s%d", slug, i)})
+				if err == nil {
+					wins.Add(1)
+				} else if strings.Contains(err.Error(), fmt.Sprintf("duplicate name %q", fmt.Sprint(i))) {
+					losses.Add(1)
+				} else {
+					t.Errorf("topic %d: %v", i, err)
+				}
+			})
+		}
+		wg.Go(func() {
+			r.Lookup(fmt.Sprint(i))
+			r.All()
+			r.Len()
+		})
+	}
+	wg.Wait()
+	if wins.Load() != n || losses.Load() != n || r.Len() != n {
+		t.Errorf("%d registered, %d refused, Len %d; want %d, %d, %d", wins.Load(), losses.Load(), r.Len(), n, n, n)
+	}
+	for i := 1; i <= n; i++ {
+		topic, ok := r.Lookup(fmt.Sprint(i))
+		if !ok {
+			t.Fatalf("topic %d is missing", i)
+		}
+		other := "b"
+		if topic.Slug[0] == 'b' {
+			other = "a"
+		}
+		if _, ok := r.Lookup(fmt.Sprintf("%s%d", other, i)); ok {
+			t.Errorf("topic %d: the losing registration's slug %s%d was kept", i, other, i)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	// Not parallel, so the swap can't race: parallel tests resume only
+	// after every sequential one has finished.
+	saved := Default
+	Default = New()
+	defer func() { Default = saved }()
+
+	Register(Topic{ID: 9001, Slug: "default-only"})
+	if got, ok := Lookup("default-only"); !ok || got.ID != 9001 {
+		t.Errorf("Lookup in Default = %+v, %v", got, ok)
+	}
+	if !slices.ContainsFunc(All(), func(t Topic) bool { return t.ID == 9001 }) {
+		t.Error("All() misses the topic Register added to Default")
+	}
+	defer func() {
+		if err, _ := recover().(error); err == nil || !strings.Contains(err.Error(), "registry_test.go:") {
+			t.Errorf("Register of a duplicate panicked with %v, want the two call sites", err)
+		}
+	}()
+	Register(Topic{ID: 9001, Slug: "again"})
+}
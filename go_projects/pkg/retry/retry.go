@@ -0,0 +1,111 @@
+// Package retry runs an operation again when its error says that could
+// help, waiting longer each time (Topic 196):
+//
+//	err := retry.Do(ctx, retry.Default, func(ctx context.Context) error {
+//		return loadUser(ctx, id)
+//	})
+//
+// Whether to try again is the error's decision, not the caller's: an
+// error with a Retryable method that reports true is retried, anything
+// else is returned at once. errorx.DatabaseError has one (a read that
+// timed out); a plain error has none, and isn't retried, because running
+// an operation twice is only safe when something says it is.
+//
+// Between attempts Do sleeps for exponential backoff with jitter:
+// BaseDelay, doubled each attempt up to MaxDelay, less a random part so
+// that clients that failed together don't all come back together.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Retryable is an error that knows whether trying again could help.
+type Retryable interface {
+	error
+	Retryable() bool
+}
+
+// IsRetryable reports whether the first error in err's chain with a
+// Retryable method says to try again. An error without one says no.
+func IsRetryable(err error) bool {
+	var r Retryable
+	return errors.As(err, &r) && r.Retryable()
+}
+
+// Policy is how hard Do tries.
+type Policy struct {
+	MaxAttempts int           // Including the first; below 1 means 1
+	BaseDelay   time.Duration // The wait before the second attempt
+	MaxDelay    time.Duration // The most any one wait grows to; 0 for no limit
+	// Jitter is the fraction of each wait that is random, 0 to 1: a wait
+	// of d becomes anything from d*(1-Jitter) to d. 1 is "full jitter",
+	// which spreads a crowd of clients out the most.
+	Jitter float64
+	// OnRetry, if set, is called before each wait with the attempt that
+	// failed (1 for the first), its error and how long Do will wait.
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// Default tries 4 times, waiting up to 100ms, 200ms and 400ms between.
+var Default = Policy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second, Jitter: 0.5}
+
+// random is the jitter's source, replaced in tests.
+var random = rand.Float64
+
+// Backoff is the wait after failed attempt n (1 for the first), before
+// jitter: BaseDelay doubled n-1 times, capped at MaxDelay.
+func (p Policy) Backoff(n int) time.Duration {
+	limit := time.Duration(math.MaxInt64 / 2) // Doubling past this overflows
+	if p.MaxDelay > 0 {
+		limit = min(limit, p.MaxDelay)
+	}
+	d := p.BaseDelay
+	for i := 1; i < n && d < limit; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 {
+		d = min(d, p.MaxDelay)
+	}
+	return d
+}
+
+func (p Policy) wait(n int) time.Duration {
+	d := p.Backoff(n)
+	j := min(max(p.Jitter, 0), 1)
+	return d - time.Duration(j*random()*float64(d))
+}
+
+// Do calls op until it succeeds, returns an error that isn't retryable,
+// or has been tried p.MaxAttempts times. It returns nil, that error as
+// it is, or the last error wrapped with the number of attempts. Once ctx
+// is done nothing is retried: an error then is returned as it is, and
+// if ctx ends during a wait, Do returns ctx's error with the last one.
+func Do(ctx context.Context, p Policy, op func(context.Context) error) error {
+	attempts := max(p.MaxAttempts, 1)
+	for n := 1; ; n++ {
+		err := op(ctx)
+		if !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if n == attempts {
+			return fmt.Errorf("gave up after %d attempts: %w", n, err)
+		}
+		wait := p.wait(n)
+		if p.OnRetry != nil {
+			p.OnRetry(n, err, wait)
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("%w after %d attempts, last error: %w", ctx.Err(), n, err)
+		}
+	}
+}
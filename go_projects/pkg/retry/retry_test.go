@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// flaky is a transient error when retry is true, a permanent one when not.
+type flaky struct{ retry bool }
+
+func (f flaky) Error() string   { return fmt.Sprintf("flaky(retry=%v)", f.retry) }
+func (f flaky) Retryable() bool { return f.retry }
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("plain"), false},
+		{flaky{true}, true},
+		{flaky{false}, false},
+		{fmt.Errorf("load: %w", flaky{true}), true},
+		{fmt.Errorf("outer: %w", fmt.Errorf("%w", flaky{false})), false},
+		{errors.Join(errors.New("x"), flaky{true}), true},
+		{fmt.Errorf("query: %w: %w", flaky{true}, context.DeadlineExceeded), true}, // A query's own timeout
+		{context.Canceled, false},
+	} {
+		if got := IsRetryable(tc.err); got != tc.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	var got []time.Duration
+	for n := 1; n <= 6; n++ {
+		got = append(got, p.Backoff(n))
+	}
+	want := []time.Duration{100e6, 200e6, 400e6, 800e6, 1e9, 1e9}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Backoff(1..6) = %v, want %v", got, want)
+	}
+	if d := (Policy{BaseDelay: time.Second}).Backoff(200); d <= 0 {
+		t.Errorf("Backoff(200) with no MaxDelay = %v: overflowed", d)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	defer func(r func() float64) { random = r }(random)
+	p := Policy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+	for _, tc := range []struct {
+		r    float64
+		want time.Duration
+	}{{0, 100e6}, {0.5, 75e6}, {0.999, 50050000}} {
+		random = func() float64 { return tc.r }
+		if got := p.wait(1); got != tc.want {
+			t.Errorf("random %v: wait %v, want %v", tc.r, got, tc.want)
+		}
+	}
+	p.Jitter = 3 // Treated as 1: a wait is never negative
+	random = func() float64 { return 0.999 }
+	if got := p.wait(1); got < 0 || got > time.Millisecond {
+		t.Errorf("Jitter 3: wait %v", got)
+	}
+}
+
+func TestDo(t *testing.T) {
+	p := Policy{MaxAttempts: 4, BaseDelay: time.Millisecond}
+	calls := 0
+	succeedOn := func(n int, err error) func(context.Context) error {
+		calls = 0
+		return func(context.Context) error {
+			calls++
+			if calls >= n {
+				return nil
+			}
+			return err
+		}
+	}
+
+	var waits []time.Duration
+	p.OnRetry = func(attempt int, err error, wait time.Duration) { waits = append(waits, wait) }
+	if err := Do(context.Background(), p, succeedOn(3, flaky{true})); err != nil || calls != 3 {
+		t.Errorf("transient twice: %v after %d calls", err, calls)
+	}
+	if len(waits) != 2 || waits[0] != time.Millisecond || waits[1] != 2*time.Millisecond {
+		t.Errorf("OnRetry waits %v, want [1ms 2ms]", waits)
+	}
+	p.OnRetry = nil
+
+	err := Do(context.Background(), p, succeedOn(99, flaky{true}))
+	var f flaky
+	if calls != 4 || !errors.As(err, &f) || err.Error() != "gave up after 4 attempts: flaky(retry=true)" {
+		t.Errorf("always transient: %v after %d calls", err, calls)
+	}
+
+	permanent := flaky{false}
+	if err := Do(context.Background(), p, succeedOn(99, permanent)); err != permanent || calls != 1 {
+		t.Errorf("permanent: %v after %d calls, want it returned as is after 1", err, calls)
+	}
+	plain := errors.New("plain")
+	if err := Do(context.Background(), p, succeedOn(99, plain)); err != plain || calls != 1 {
+		t.Errorf("unclassified: %v after %d calls", err, calls)
+	}
+	if err := Do(context.Background(), Policy{}, succeedOn(99, flaky{true})); calls != 1 || err == nil {
+		t.Errorf("zero Policy: %v after %d calls, want one attempt", err, calls)
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	calls := 0
+	err := Do(ctx, Policy{MaxAttempts: 10, BaseDelay: time.Hour}, func(context.Context) error {
+		calls++
+		return flaky{true}
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, new(flaky)) || calls != 1 {
+		t.Errorf("Do = %v after %d calls, want the deadline and the last error", err, calls)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("Do waited %v past its context", took)
+	}
+
+	// Already done: the first error comes back as it is, with no waiting.
+	cancel()
+	calls = 0
+	err = Do(ctx, Policy{MaxAttempts: 10, BaseDelay: time.Hour}, func(context.Context) error {
+		calls++
+		return flaky{true}
+	})
+	if err != (flaky{true}) || calls != 1 {
+		t.Errorf("done context: %v after %d calls", err, calls)
+	}
+}
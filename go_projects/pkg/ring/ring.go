@@ -0,0 +1,58 @@
+// Package ring keeps the last lines written to it, at a fixed memory
+// cost, for a crash report's "what happened just before" (Topic 139):
+//
+//	recent := ring.New(100)
+//	logger := log.New(io.MultiWriter(os.Stderr, recent), "", 0)
+//	...
+//	recent.Lines() // The last 100 lines, oldest first
+//
+// A Ring is an io.Writer, so it plugs into log.New or io.MultiWriter, and
+// it is safe to write from several goroutines.
+package ring
+
+import (
+	"strings"
+	"sync"
+)
+
+// Ring keeps the last size lines written to it.
+type Ring struct {
+	mu    sync.Mutex
+	lines []string
+	next  int  // Index where the next line will be written
+	full  bool // True once we've wrapped around at least once
+}
+
+// New returns a Ring that keeps size lines; size must be positive.
+func New(size int) *Ring {
+	return &Ring{lines: make([]string, size)}
+}
+
+// Write splits p into lines and keeps them. It never fails.
+func (r *Ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines[r.next] = line
+		r.next = (r.next + 1) % len(r.lines)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the buffered lines, oldest first. A nil Ring has none.
+func (r *Ring) Lines() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
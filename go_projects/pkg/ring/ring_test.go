@@ -0,0 +1,43 @@
+package ring
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := New(3)
+	if got := r.Lines(); len(got) != 0 {
+		t.Errorf("empty ring: %q", got)
+	}
+	fmt.Fprint(r, "one\n")
+	fmt.Fprint(r, "two\nthree\nfour\n") // One write, three lines
+	if got, want := r.Lines(), []string{"two", "three", "four"}; !slices.Equal(got, want) {
+		t.Errorf("Lines = %q, want %q", got, want)
+	}
+	fmt.Fprint(r, "five")
+	if got, want := r.Lines(), []string{"three", "four", "five"}; !slices.Equal(got, want) {
+		t.Errorf("Lines = %q, want %q", got, want)
+	}
+	if got := (*Ring)(nil).Lines(); got != nil {
+		t.Errorf("nil ring: %q", got)
+	}
+}
+
+func TestRingConcurrent(t *testing.T) {
+	r := New(10)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for j := range 100 {
+				fmt.Fprintf(r, "%d-%d\n", i, j)
+			}
+		})
+	}
+	wg.Wait()
+	if got := r.Lines(); len(got) != 10 {
+		t.Errorf("kept %d lines, want 10", len(got))
+	}
+}
@@ -0,0 +1,119 @@
+// Package rxcache compiles regular expressions through a size-bounded
+// cache, for patterns that arrive at run time: a search box, a filter in
+// a config file, a -match flag (intermediate Topic 73, Part 6):
+//
+//	rx, err := rxcache.Compile(r.URL.Query().Get("q"))   // compiled once per pattern
+//	if err != nil { ... }
+//	rx.MatchString(line)
+//
+// A pattern written in the source belongs in a package-level variable,
+// compiled once with regexp.MustCompile; nothing here beats that. The
+// cache is for the case Part 6 warns about, a regexp.Compile inside a
+// handler or a loop, where the same few patterns come back again and
+// again and each call pays for parsing and compiling them.
+//
+// The cache keeps the most recently used patterns and drops the least
+// recently used when it is full, so a caller feeding it endless distinct
+// patterns can't make it grow. A pattern that doesn't compile is cached
+// too, with its error: a bad filter retried on every request costs a
+// lookup, not a parse. A *regexp.Regexp is safe for concurrent use, so
+// every caller gets the same one.
+package rxcache
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// DefaultSize is the capacity of the cache behind the package-level
+// Compile.
+const DefaultSize = 256
+
+// Cache is a concurrency-safe LRU cache of compiled patterns. The zero
+// value is not usable; call New.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List               // Front is the most recently used; each element is an *entry
+	entries map[string]*list.Element // pattern → its element in order
+	stats   Stats
+}
+
+type entry struct {
+	pattern string
+	rx      *regexp.Regexp
+	err     error
+}
+
+// Stats counts what a Cache has done since New.
+type Stats struct {
+	Hits      int // Compile calls answered from the cache
+	Misses    int // ...that compiled the pattern
+	Evictions int // Patterns dropped to make room
+}
+
+// New returns an empty cache holding up to size patterns. It panics if
+// size is less than 1.
+func New(size int) *Cache {
+	if size < 1 {
+		panic("rxcache: size must be at least 1")
+	}
+	return &Cache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Compile returns regexp.Compile(pattern), from the cache when the pattern
+// has been compiled before. The compiling happens outside the lock, so a
+// long pattern doesn't hold up lookups of others; two goroutines missing
+// on the same pattern at once may both compile it, and one result is kept.
+func (c *Cache) Compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(el)
+		c.stats.Hits++
+		e := el.Value.(*entry)
+		c.mu.Unlock()
+		return e.rx, e.err
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	rx, err := regexp.Compile(pattern)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[pattern]; ok { // Another goroutine got there first
+		c.order.MoveToFront(el)
+		e := el.Value.(*entry)
+		return e.rx, e.err
+	}
+	c.entries[pattern] = c.order.PushFront(&entry{pattern, rx, err})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).pattern)
+		c.stats.Evictions++
+	}
+	return rx, err
+}
+
+// Len returns the number of patterns in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the counts so far.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+var std = New(DefaultSize)
+
+// Compile compiles pattern through a shared cache of DefaultSize patterns.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	return std.Compile(pattern)
+}
@@ -0,0 +1,163 @@
+package rxcache
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	c := New(4)
+	rx, err := c.Compile(`\d+`)
+	if err != nil || rx.FindString("abc 123") != "123" {
+		t.Fatalf("Compile(`\\d+`) = %v, %v", rx, err)
+	}
+	again, _ := c.Compile(`\d+`)
+	if again != rx {
+		t.Error("the second Compile of a pattern compiled it again")
+	}
+	if s := c.Stats(); s != (Stats{Hits: 1, Misses: 1}) {
+		t.Errorf("Stats = %+v, want 1 hit and 1 miss", s)
+	}
+
+	// A bad pattern is cached with its error.
+	_, err1 := c.Compile(`[unclosed`)
+	_, err2 := c.Compile(`[unclosed`)
+	if err1 == nil || err2 != err1 {
+		t.Errorf("errors %v, %v: want the same error twice", err1, err2)
+	}
+	if s := c.Stats(); s.Hits != 2 || s.Misses != 2 {
+		t.Errorf("Stats = %+v after a bad pattern twice", s)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	c := New(3)
+	for _, p := range []string{"a", "b", "c"} {
+		c.Compile(p)
+	}
+	c.Compile("a") // a is now the most recently used; b the least
+	c.Compile("d") // ...so b goes
+	c.Compile("a") // Hit
+	c.Compile("c") // Hit
+	c.Compile("b") // Miss, and d goes
+	c.Compile("d") // Miss, and a goes
+	c.Compile("c") // Hit
+	s := c.Stats()
+	if want := (Stats{Hits: 4, Misses: 6, Evictions: 3}); s != want {
+		t.Errorf("Stats = %+v, want %+v", s, want)
+	}
+	if c.Len() != 3 {
+		t.Errorf("Len = %d, want 3", c.Len())
+	}
+}
+
+func TestBounded(t *testing.T) {
+	c := New(10)
+	for i := range 1000 {
+		c.Compile(fmt.Sprintf("user%d", i))
+	}
+	if c.Len() != 10 || c.Stats().Evictions != 990 {
+		t.Errorf("Len %d, Stats %+v: want 10 kept and 990 evicted", c.Len(), c.Stats())
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	c := New(8)
+	patterns := make([]string, 16)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf(`^item-%d-\d+$`, i)
+	}
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				p := patterns[(g+i)%len(patterns)]
+				rx, err := c.Compile(p)
+				if err != nil || rx.String() != p {
+					t.Errorf("Compile(%q) = %v, %v", p, rx, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	s := c.Stats()
+	if c.Len() != 8 || s.Hits+s.Misses != 16000 {
+		t.Errorf("Len %d, Stats %+v: want 8 kept and 16000 calls", c.Len(), s)
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New(0) didn't panic")
+		}
+	}()
+	New(0)
+}
+
+// TestSpeedup checks the point of the package: a hit is far cheaper than
+// compiling. The real margin is a hundred times or more; 5 leaves room
+// for a loaded machine.
+func TestSpeedup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs benchmarks")
+	}
+	compile := testing.Benchmark(BenchmarkCompile)
+	cached := testing.Benchmark(BenchmarkCached)
+	if compile.NsPerOp() < 5*cached.NsPerOp() {
+		t.Errorf("regexp.Compile %v, cached %v: want the cache at least 5× faster", compile, cached)
+	}
+	t.Logf("regexp.Compile %d ns/op, cached %d ns/op", compile.NsPerOp(), cached.NsPerOp())
+}
+
+// benchPattern is the kind a user types into a log filter.
+const benchPattern = `(?i)^(\d{4}-\d{2}-\d{2})T[\d:.]+Z\s+(ERROR|WARN)\s+\[([a-z-]+)\]\s+(.*timeout.*)$`
+
+func BenchmarkCompile(b *testing.B) {
+	for b.Loop() {
+		if _, err := regexp.Compile(benchPattern); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCached(b *testing.B) {
+	c := New(DefaultSize)
+	for b.Loop() {
+		if _, err := c.Compile(benchPattern); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCachedParallel(b *testing.B) {
+	c := New(DefaultSize)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Compile(benchPattern); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkChurn cycles through more patterns than the cache holds: every
+// call misses. It is the worst case, and costs what regexp.Compile does
+// plus a little.
+func BenchmarkChurn(b *testing.B) {
+	c := New(16)
+	patterns := make([]string, 17)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf(`%s|n%d`, benchPattern, i)
+	}
+	i := 0
+	for b.Loop() {
+		c.Compile(patterns[i%len(patterns)])
+		i++
+	}
+}
@@ -0,0 +1,320 @@
+// Package rxlib is intermediate Topic 73's cookbook of common patterns,
+// compiled once when the package loads (Section 6 of the lesson):
+//
+//	tags := rxlib.Hashtag.FindAllString(post, -1)
+//	if err := rxlib.ValidateEmail(form.Email); err != nil { ... }
+//
+// The exported regexps are unanchored, to FIND matches in running text.
+// The Validate functions check that a whole string is one, with the same
+// pattern anchored at both ends, and some checks a regexp can't make:
+// ValidateDateISO rejects February 30th.
+//
+// NamedGroups and Bind read a match by its (?P<name>...) groups instead
+// of by number (Section 7): a map, or the fields of a struct.
+//
+// The patterns are practical, not the standards' full grammars: Email
+// takes the addresses people type, not every one RFC 5322 allows, and
+// URL only http and https. When a validator says no to something real,
+// the pattern is the place to look.
+package rxlib
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The patterns, as source, so each is compiled twice: as it is for
+// finding and anchored for validating.
+const (
+	emailPattern = `[A-Za-z0-9!#$%&'*+/=?^_{|}~-]+(?:\.[A-Za-z0-9!#$%&'*+/=?^_{|}~-]+)*@` + domain
+
+	// An area code and exchange don't start with 0 or 1. A leading \b keeps
+	// a match from starting in the middle of a longer number.
+	phoneUSPattern = `(?:(?:\+1[-. ]?)?\([2-9]\d{2}\) ?|(?:\+1[-. ]?|\b1[-. ]?|\b)[2-9]\d{2}[-. ]?)[2-9]\d{2}[-. ]?\d{4}\b`
+
+	dateISOPattern = `\b\d{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])\b`
+
+	// The path ends before trailing punctuation: "see https://go.dev." is
+	// a sentence ending, not part of the link.
+	urlPattern = `(?i:https?)://(?:` + domain + `|localhost|` + ipv4 + `)(?::\d{1,5})?` +
+		`(?:[/?#](?:[^\s<>"']*[^\s<>"'.,;:!?)\]])?)?`
+
+	// A tag has a letter in it: #1 is a number, not a tag. \B keeps
+	// "issue#12" and "a#b" out.
+	hashtagPattern = `\B#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*`
+
+	// Twitter's rule: 1 to 15 letters, digits or underscores. \B keeps
+	// the domain of an email address from being a mention.
+	mentionPattern = `\B@[A-Za-z0-9_]{1,15}\b`
+
+	// semver.org's own pattern, with an optional "v" as Go modules and
+	// git tags write it.
+	semverPattern = `\bv?(?:0|[1-9]\d*)\.(?:0|[1-9]\d*)\.(?:0|[1-9]\d*)` +
+		`(?:-(?:0|[1-9]\d*|\d*[A-Za-z-][0-9A-Za-z-]*)(?:\.(?:0|[1-9]\d*|\d*[A-Za-z-][0-9A-Za-z-]*))*)?` +
+		`(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?`
+
+	// Leading zeros aren't allowed: some parsers read 010 as octal 8.
+	ipv4Pattern = `\b` + ipv4 + `\b`
+
+	domain = `(?:[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)+[A-Za-z]{2,63}`
+	octet  = `(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)`
+	ipv4   = octet + `(?:\.` + octet + `){3}`
+)
+
+var (
+	// Email matches an address: user.name+tag@mail.example.com.
+	Email = regexp.MustCompile(emailPattern)
+
+	// PhoneUS matches a US number: 212-555-0123, (212) 555-0123,
+	// 212.555.0123, 2125550123, +1 212 555 0123.
+	PhoneUS = regexp.MustCompile(phoneUSPattern)
+
+	// DateISO matches an ISO 8601 date, YYYY-MM-DD, with a month of 01-12
+	// and a day of 01-31.
+	DateISO = regexp.MustCompile(dateISOPattern)
+
+	// URL matches an http or https URL: a host name, localhost or an IPv4
+	// address, an optional port, and a path, query and fragment.
+	URL = regexp.MustCompile(urlPattern)
+
+	// Hashtag matches #golang, #Go2 and #café.
+	Hashtag = regexp.MustCompile(hashtagPattern)
+
+	// Mention matches @gopher.
+	Mention = regexp.MustCompile(mentionPattern)
+
+	// Semver matches a semantic version: 1.2.3, v1.2.3-rc.1+build.5.
+	Semver = regexp.MustCompile(semverPattern)
+
+	// IPv4 matches a dotted-quad address: 192.168.0.1.
+	IPv4 = regexp.MustCompile(ipv4Pattern)
+)
+
+var (
+	emailWhole   = whole(emailPattern)
+	phoneUSWhole = whole(phoneUSPattern)
+	dateISOWhole = whole(dateISOPattern)
+	urlWhole     = whole(urlPattern)
+	hashtagWhole = whole(hashtagPattern)
+	mentionWhole = whole(mentionPattern)
+	semverWhole  = whole(semverPattern)
+	ipv4Whole    = whole(ipv4Pattern)
+)
+
+// whole compiles pattern to match all of a string and nothing else.
+func whole(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`^(?:` + pattern + `)$`)
+}
+
+// ErrInvalid is wrapped by every error a Validate function returns.
+var ErrInvalid = errors.New("invalid")
+
+func invalid(kind, s, why string) error {
+	if why != "" {
+		return fmt.Errorf("%w %s %q: %s", ErrInvalid, kind, s, why)
+	}
+	return fmt.Errorf("%w %s %q", ErrInvalid, kind, s)
+}
+
+// ValidateEmail checks that s is one email address, within the lengths
+// mail servers accept: 64 bytes before the @ and 254 in all.
+func ValidateEmail(s string) error {
+	if !emailWhole.MatchString(s) {
+		return invalid("email", s, "")
+	}
+	if len(s) > 254 {
+		return invalid("email", s, "longer than 254 bytes")
+	}
+	if strings.LastIndexByte(s, '@') > 64 {
+		return invalid("email", s, "more than 64 bytes before the @")
+	}
+	return nil
+}
+
+// ValidatePhoneUS checks that s is one US phone number.
+func ValidatePhoneUS(s string) error {
+	if !phoneUSWhole.MatchString(s) {
+		return invalid("US phone number", s, "")
+	}
+	return nil
+}
+
+// ValidateDateISO checks that s is a YYYY-MM-DD date that exists:
+// 2024-02-29 does, 2025-02-29 doesn't.
+func ValidateDateISO(s string) error {
+	if !dateISOWhole.MatchString(s) {
+		return invalid("date", s, "want YYYY-MM-DD")
+	}
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		return invalid("date", s, "no such day")
+	}
+	return nil
+}
+
+// ValidateURL checks that s is one http or https URL.
+func ValidateURL(s string) error {
+	if !urlWhole.MatchString(s) {
+		return invalid("URL", s, "")
+	}
+	return nil
+}
+
+// ValidateHashtag checks that s is one hashtag, # included.
+func ValidateHashtag(s string) error {
+	if !hashtagWhole.MatchString(s) {
+		return invalid("hashtag", s, "")
+	}
+	return nil
+}
+
+// ValidateMention checks that s is one @mention, @ included.
+func ValidateMention(s string) error {
+	if !mentionWhole.MatchString(s) {
+		return invalid("mention", s, "")
+	}
+	return nil
+}
+
+// ValidateSemver checks that s is one semantic version.
+func ValidateSemver(s string) error {
+	if !semverWhole.MatchString(s) {
+		return invalid("semantic version", s, "")
+	}
+	return nil
+}
+
+// ValidateIPv4 checks that s is one IPv4 address.
+func ValidateIPv4(s string) error {
+	if !ipv4Whole.MatchString(s) {
+		return invalid("IPv4 address", s, "")
+	}
+	return nil
+}
+
+// NamedGroups returns the named groups of re's first match in s, name →
+// text, or nil when there is no match. A group that took no part in the
+// match, (?P<ext>x\d+)? with no extension, is there as "".
+func NamedGroups(re *regexp.Regexp, s string) map[string]string {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	groups := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			groups[name] = m[i]
+		}
+	}
+	return groups
+}
+
+// ErrNoMatch is returned by Bind when s doesn't match.
+var ErrNoMatch = errors.New("rxlib: no match")
+
+// Bind fills the struct dst points to from re's first match in s. Each
+// exported field takes the group named in its rx tag, or else the group
+// with the field's own name:
+//
+//	type Entry struct {
+//		Time    time.Time     `rx:"time"`    // RFC 3339
+//		Status  int           `rx:"status"`
+//		Latency time.Duration `rx:"latency"` // "1.5s", "250ms"
+//		Path    string                       // the group named Path
+//		Seen    bool          `rx:"-"`       // never set
+//	}
+//
+// A field whose tag names a group re doesn't have is an error, to catch
+// the typo; an untagged field with no group of its name is left alone. A
+// group that took no part in the match leaves its field as it was.
+//
+// Fields may be strings, bools, integers, floats, time.Duration (as
+// time.ParseDuration reads it), or anything whose pointer is an
+// encoding.TextUnmarshaler — time.Time takes RFC 3339. A group's text
+// that doesn't convert is an error naming the field and the group.
+func Bind(re *regexp.Regexp, s string, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("rxlib: Bind needs a non-nil pointer to a struct, not %T", dst)
+	}
+	v = v.Elem()
+	m := re.FindStringSubmatchIndex(s)
+	if m == nil {
+		return ErrNoMatch
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		tag, tagged := f.Tag.Lookup("rx")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		group := f.Name
+		if tagged {
+			group = tag
+		}
+		g := re.SubexpIndex(group)
+		switch {
+		case g < 0 && tagged:
+			return fmt.Errorf("rxlib: field %s: the pattern has no group (?P<%s>...)", f.Name, group)
+		case g < 0 || m[2*g] < 0:
+			continue
+		}
+		if err := setField(v.Field(i), s[m[2*g]:m[2*g+1]]); err != nil {
+			return fmt.Errorf("rxlib: field %s from group %s: %w", f.Name, group, err)
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// setField converts text to field's type and stores it.
+func setField(field reflect.Value, text string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(text))
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(x)
+	default:
+		return fmt.Errorf("can't set a %s", field.Type())
+	}
+	return nil
+}
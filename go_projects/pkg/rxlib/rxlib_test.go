@@ -0,0 +1,373 @@
+package rxlib
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) error
+		valid    []string
+		invalid  []string
+	}{
+		{"Email", ValidateEmail,
+			[]string{
+				"alice@example.com",
+				"user.name+tag@mail.example.co.uk",
+				"o'brien@example.ie",
+				"x@a-b.io",
+				"UPPER@EXAMPLE.COM",
+				"a_b-c%d@sub.domain.travel",
+				strings.Repeat("a", 64) + "@example.com",
+			},
+			[]string{
+				"",
+				"bob@test",
+				"invalid@",
+				"@example.com",
+				"plainaddress",
+				"two@@example.com",
+				"a..b@example.com",
+				".a@example.com",
+				"a.@example.com",
+				"a b@example.com",
+				"a@-example.com",
+				"a@example-.com",
+				"a@example.c",
+				"a@example.123",
+				"a@example..com",
+				" alice@example.com",
+				"alice@example.com\n",
+				strings.Repeat("a", 65) + "@example.com",
+				"a@" + strings.Repeat(strings.Repeat("b", 60)+".", 5) + "com",
+			}},
+		{"PhoneUS", ValidatePhoneUS,
+			[]string{
+				"212-555-0123",
+				"(212) 555-0123",
+				"(212)555-0123",
+				"212.555.0123",
+				"212 555 0123",
+				"2125550123",
+				"+1 212 555 0123",
+				"+1-212-555-0123",
+				"+12125550123",
+				"1-212-555-0123",
+				"+1 (212) 555-0123",
+			},
+			[]string{
+				"",
+				"112-555-0123",    // Area code starts with 1
+				"012-555-0123",    // ...or 0
+				"212-155-0123",    // Exchange too
+				"212-555-012",     // Short
+				"212-555-01234",   // Long
+				"(212 555-0123",   // Unbalanced
+				"212) 555-0123",   //
+				"+2 212 555 0123", // Not +1
+				"212--555-0123",
+				"phone 212-555-0123",
+				"212-555-0123x",
+			}},
+		{"DateISO", ValidateDateISO,
+			[]string{"2026-10-16", "2024-02-29", "2000-02-29", "1999-12-31", "0001-01-01", "2026-01-31"},
+			[]string{
+				"",
+				"2025-02-29", // Not a leap year
+				"1900-02-29", // Not one either
+				"2026-04-31",
+				"2026-02-30",
+				"2026-13-01",
+				"2026-00-10",
+				"2026-10-00",
+				"2026-10-32",
+				"26-10-16",
+				"2026-1-16",
+				"2026/10/16",
+				"20261016",
+				"2026-10-16T09:00:00Z",
+				" 2026-10-16",
+			}},
+		{"URL", ValidateURL,
+			[]string{
+				"https://go.dev",
+				"http://go.dev/",
+				"https://pkg.go.dev/regexp#Regexp.FindAllString",
+				"https://example.com:8443/a/b?q=1&r=two#frag",
+				"HTTPS://EXAMPLE.COM",
+				"http://localhost:8080/health",
+				"http://192.168.0.1/admin",
+				"https://sub-domain.example.co.uk/a_(b)/c",
+			},
+			[]string{
+				"",
+				"go.dev",
+				"ftp://example.com",
+				"javascript:alert(1)",
+				"https://",
+				"https://example",
+				"https://-bad.example.com",
+				"https://exa mple.com",
+				"https://example.com/ space",
+				"https://example.com.", // Punctuation after: a sentence's end
+				"https://999.1.1.1",
+				"https://example.com:123456",
+				"http//example.com",
+			}},
+		{"Hashtag", ValidateHashtag,
+			[]string{"#golang", "#Go2", "#go_lang", "#café", "#日本語", "#2024goals", "#_x"},
+			[]string{"", "#", "golang", "#123", "#go-lang", "#go lang", "##go", "a#b", "#go!"}},
+		{"Mention", ValidateMention,
+			[]string{"@gopher", "@Rob_Pike", "@a", "@x123", "@" + strings.Repeat("a", 15)},
+			[]string{"", "@", "gopher", "@go-pher", "@go.pher", "@" + strings.Repeat("a", 16), "@@gopher", "a@gopher", "@gopher!"}},
+		{"Semver", ValidateSemver,
+			[]string{
+				"0.0.0",
+				"1.2.3",
+				"v1.2.3",
+				"10.20.30",
+				"1.0.0-alpha",
+				"1.0.0-alpha.1",
+				"1.0.0-0.3.7",
+				"1.0.0-x.7.z.92",
+				"1.0.0-alpha+001",
+				"1.0.0+20130313144700",
+				"1.0.0-beta+exp.sha.5114f85",
+				"1.0.0-x-y-z.--",
+				"v2.0.0-rc.1+build.5",
+			},
+			[]string{
+				"",
+				"1",
+				"1.2",
+				"1.2.3.4",
+				"01.2.3",
+				"1.02.3",
+				"1.2.03",
+				"1.2.3-01",       // A numeric pre-release part has no leading zero
+				"1.2.3-",         //
+				"1.2.3+",         //
+				"1.2.3-alpha..1", //
+				"1.2.3-alpha_beta",
+				"V1.2.3",
+				"vv1.2.3",
+				"-1.2.3",
+				"1.2.3 ",
+			}},
+		{"IPv4", ValidateIPv4,
+			[]string{"0.0.0.0", "127.0.0.1", "192.168.0.1", "255.255.255.255", "10.0.0.254", "8.8.8.8", "100.99.9.199"},
+			[]string{
+				"",
+				"256.1.1.1",
+				"1.1.1.256",
+				"1.1.1",
+				"1.1.1.1.1",
+				"01.1.1.1",
+				"1.1.1.01",
+				"1..1.1",
+				"1.1.1.1.",
+				"a.b.c.d",
+				"-1.1.1.1",
+				"1.1.1.1/24",
+				" 1.1.1.1",
+				"300.300.300.300",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.valid {
+				if err := tt.validate(s); err != nil {
+					t.Errorf("Validate%s(%q) = %v, want nil", tt.name, s, err)
+				}
+			}
+			for _, s := range tt.invalid {
+				err := tt.validate(s)
+				if !errors.Is(err, ErrInvalid) {
+					t.Errorf("Validate%s(%q) = %v, want ErrInvalid", tt.name, s, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{ValidateDateISO("2025-02-29"), `invalid date "2025-02-29": no such day`},
+		{ValidateDateISO("16/10/2026"), `invalid date "16/10/2026": want YYYY-MM-DD`},
+		{ValidateEmail("bob@test"), `invalid email "bob@test"`},
+		{ValidateEmail(strings.Repeat("a", 65) + "@example.com"), "more than 64 bytes before the @"},
+		{ValidateIPv4("256.0.0.1"), `invalid IPv4 address "256.0.0.1"`},
+	} {
+		if tc.err == nil || !strings.Contains(tc.err.Error(), tc.want) {
+			t.Errorf("error %v, want it to contain %q", tc.err, tc.want)
+		}
+	}
+}
+
+// TestFind checks the exported regexps pick the right things out of
+// running text, and nothing from inside something else.
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name string
+		re   *regexp.Regexp
+		text string
+		want []string
+	}{
+		{"Email", Email,
+			"Mail alice@example.com or bob.smith+go@mail.example.org. Not bob@test, not @example.com.",
+			[]string{"alice@example.com", "bob.smith+go@mail.example.org"}},
+		{"PhoneUS", PhoneUS,
+			"Call 212-555-0123 or (415) 555-0199, fax +1 646 555 0100; order 912125550123, zip 21255.",
+			[]string{"212-555-0123", "(415) 555-0199", "+1 646 555 0100"}},
+		{"DateISO", DateISO,
+			"Released 2026-10-16, patched 2026-11-02; not 2026-13-01, 12026-10-16 or 2026-10-160.",
+			[]string{"2026-10-16", "2026-11-02"}},
+		{"URL", URL,
+			"See https://go.dev/doc/effective_go. Or (http://localhost:6060/pkg/), or ftp://x.org, or go.dev.",
+			[]string{"https://go.dev/doc/effective_go", "http://localhost:6060/pkg/"}},
+		{"Hashtag", Hashtag,
+			"#golang is #awesome for #programming! Not issue#12, not #42, yes #Go2 and #café.",
+			[]string{"#golang", "#awesome", "#programming", "#Go2", "#café"}},
+		{"Mention", Mention,
+			"Thanks @rob_pike and @gopher! Mail gopher@golang.org; @this_name_is_too_long_by_far isn't one.",
+			[]string{"@rob_pike", "@gopher"}},
+		{"Semver", Semver,
+			"Upgrade from v1.21.0 to 1.22.0-rc.1, not 1.2 or 01.2.3.",
+			[]string{"v1.21.0", "1.22.0-rc.1"}},
+		{"IPv4", IPv4,
+			"Hosts 10.0.0.1 and 192.168.1.254, not 256.1.1.1 or 1.2.3.",
+			[]string{"10.0.0.1", "192.168.1.254"}},
+	}
+	for _, tt := range tests {
+		if got := tt.re.FindAllString(tt.text, -1); !slices.Equal(got, tt.want) {
+			t.Errorf("%s in %q:\n got %q\nwant %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+// Every exported regexp is unanchored: anchors would stop it finding
+// anything in the middle of a text.
+func TestUnanchored(t *testing.T) {
+	for name, re := range map[string]*regexp.Regexp{
+		"Email": Email, "PhoneUS": PhoneUS, "DateISO": DateISO, "URL": URL,
+		"Hashtag": Hashtag, "Mention": Mention, "Semver": Semver, "IPv4": IPv4,
+	} {
+		if s := re.String(); strings.HasPrefix(s, "^") || strings.HasSuffix(s, "$") {
+			t.Errorf("%s is anchored: %s", name, s)
+		}
+	}
+}
+
+func TestNamedGroups(t *testing.T) {
+	re := regexp.MustCompile(`(?P<area>\d{3})-(?P<line>\d{4})(?: x(?P<ext>\d+))?`)
+	got := NamedGroups(re, "call 555-0123 today")
+	want := map[string]string{"area": "555", "line": "0123", "ext": ""}
+	if !maps.Equal(got, want) {
+		t.Errorf("NamedGroups = %q, want %q", got, want)
+	}
+	if got := NamedGroups(re, "555-0123 x42")["ext"]; got != "42" {
+		t.Errorf("ext = %q, want 42", got)
+	}
+	if got := NamedGroups(re, "no number"); got != nil {
+		t.Errorf("NamedGroups with no match = %q, want nil", got)
+	}
+	if got := NamedGroups(regexp.MustCompile(`(\d+)`), "7"); len(got) != 0 {
+		t.Errorf("NamedGroups without named groups = %q, want none", got)
+	}
+	// Go 1.22's (?<name>...) spelling is the same group.
+	if got := NamedGroups(regexp.MustCompile(`(?<word>\w+)`), "hi")["word"]; got != "hi" {
+		t.Errorf("(?<word>...) = %q", got)
+	}
+}
+
+type level string
+
+func (l *level) UnmarshalText(b []byte) error {
+	*l = level(strings.ToLower(string(b)))
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	re := regexp.MustCompile(`^(?P<time>\S+) (?P<level>[A-Z]+) (?P<Path>/\S*) (?P<status>\d{3}) (?P<bytes>\d+) (?P<latency>[\d.]+m?s)(?: (?P<cached>true|false))?(?: (?P<ratio>[\d.]+))?$`)
+	type entry struct {
+		Time    time.Time     `rx:"time"`
+		Level   level         `rx:"level"`
+		Path    string        // Untagged: the group named Path
+		Status  int           `rx:"status"`
+		Bytes   uint32        `rx:"bytes"`
+		Latency time.Duration `rx:"latency"`
+		Cached  bool          `rx:"cached"`
+		Ratio   float64       `rx:"ratio"`
+		Note    string        // No group: left alone
+		Skipped int           `rx:"-"`
+		private string
+	}
+	var e entry
+	e.Note, e.Cached = "kept", true
+	err := Bind(re, "2026-10-16T09:00:00Z WARN /api/users 503 1024 1.5s", &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := entry{
+		Time:    time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Level:   "warn",
+		Path:    "/api/users",
+		Status:  503,
+		Bytes:   1024,
+		Latency: 1500 * time.Millisecond,
+		Cached:  true, // The optional group didn't match: unchanged
+		Note:    "kept",
+	}
+	if e != want {
+		t.Errorf("Bind =\n%+v\nwant\n%+v", e, want)
+	}
+	if err := Bind(re, "2026-10-16T09:00:00Z INFO / 200 5 250ms false 0.5", &e); err != nil || e.Cached || e.Ratio != 0.5 {
+		t.Errorf("Bind with the optional groups = %+v, %v", e, err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		s    string
+		dst  any
+		want string
+	}{
+		{"no match", "nothing", &e, "no match"},
+		{"not a pointer", "", e, "pointer to a struct"},
+		{"nil", "", (*entry)(nil), "pointer to a struct"},
+		{"not a struct", "", new(int), "pointer to a struct"},
+		{"tag typo", "2026-10-16T09:00:00Z INFO / 200 5 1s",
+			&struct {
+				Status int `rx:"stauts"`
+			}{}, "field Status: the pattern has no group (?P<stauts>...)"},
+		{"overflow", "2026-10-16T09:00:00Z INFO / 200 300 1s",
+			&struct {
+				Bytes int8 `rx:"bytes"`
+			}{}, "field Bytes from group bytes"},
+		{"bad time", "yesterday INFO / 200 5 1s",
+			&struct {
+				Time time.Time `rx:"time"`
+			}{}, "field Time from group time"},
+		{"unsupported", "2026-10-16T09:00:00Z INFO / 200 5 1s",
+			&struct {
+				Path []string
+			}{}, "can't set a []string"},
+	} {
+		err := Bind(re, tc.s, tc.dst)
+		if tc.name == "no match" && !errors.Is(err, ErrNoMatch) {
+			t.Errorf("%s: %v, want ErrNoMatch", tc.name, err)
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: Bind error %v, want it to mention %q", tc.name, err, tc.want)
+		}
+	}
+}
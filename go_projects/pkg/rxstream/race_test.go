@@ -0,0 +1,5 @@
+//go:build race
+
+package rxstream
+
+func init() { raceEnabled = true }
@@ -0,0 +1,171 @@
+// Package rxstream runs a regular expression over an io.Reader a line at
+// a time and sends the matching lines on a channel, so a log of any size
+// is searched in the memory of one line (intermediate Topics 73 and 85):
+//
+//	s := rxstream.Lines(ctx, f, regexp.MustCompile(`status=(5\d\d)`))
+//	for m := range s.C {
+//		fmt.Println(m.Line, m.Groups[1])
+//	}
+//	if err := s.Err(); err != nil { ... }
+//
+// Reading the whole file first — os.ReadFile, then FindAll — needs as
+// much memory as the file, and shows nothing until all of it is read.
+// Here a bufio.Scanner reads ahead a buffer at a time, each line is
+// matched in that buffer, and only a line that matches is copied out:
+// 10 GB with three matching lines costs three strings.
+//
+// The reading happens in a goroutine, so the caller can work on one
+// match while the next are being found. The channel is closed at the
+// end of the input, on a read error, or when ctx is done; Err then says
+// which. A caller that stops reading C before it is closed calls Stop,
+// or the goroutine waits forever to send the next match:
+//
+//	for m := range s.C {
+//		if found(m) {
+//			break
+//		}
+//	}
+//	err := s.Stop()   // nil, unless reading failed before the break
+package rxstream
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+)
+
+// Match is one line the filter let through.
+type Match struct {
+	Line   int    // 1 for the first line
+	Offset int64  // Where the line starts in the input, in bytes
+	Text   string // The line, without its \n or \r\n
+
+	// Groups are the leftmost match and its submatches, as
+	// FindStringSubmatch returns them: Groups[0] is the text the whole
+	// pattern matched, Groups[1] the first group's. nil with Invert.
+	Groups []string
+}
+
+// DefaultMaxLine is the longest line a Filter accepts unless told
+// otherwise. bufio.Scanner's own limit, 64 KB, is short for logs that
+// carry JSON or stack traces.
+const DefaultMaxLine = 1 << 20
+
+// Filter says which lines to send.
+type Filter struct {
+	Re      *regexp.Regexp
+	Invert  bool // Send the lines that don't match instead, as grep -v does
+	MaxLine int  // In bytes; a longer line stops the stream with bufio.ErrTooLong. 0 is DefaultMaxLine
+	Buffer  int  // Matches the channel holds before the reader waits; 0 is 64
+}
+
+// Stream is a search in progress.
+type Stream struct {
+	C <-chan Match // Closed when the search ends
+
+	cancel  context.CancelFunc
+	stopped bool
+	err     error
+	lines   int
+	bytes   int64
+}
+
+// Lines searches r for lines that re matches. It is Filter{Re: re}.Lines.
+func Lines(ctx context.Context, r io.Reader, re *regexp.Regexp) *Stream {
+	return Filter{Re: re}.Lines(ctx, r)
+}
+
+// Lines starts reading r and returns at once; the matches arrive on the
+// Stream's C.
+func (f Filter) Lines(ctx context.Context, r io.Reader) *Stream {
+	size := f.Buffer
+	if size <= 0 {
+		size = 64
+	}
+	c := make(chan Match, size)
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream{C: c, cancel: cancel}
+	go func() {
+		defer cancel()
+		defer close(c)
+		s.err = f.scan(ctx, r, c, s)
+	}()
+	return s
+}
+
+func (f Filter) scan(ctx context.Context, r io.Reader, c chan<- Match, s *Stream) error {
+	maxLine := f.MaxLine
+	if maxLine <= 0 {
+		maxLine = DefaultMaxLine
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, min(64<<10, maxLine)), maxLine)
+	// ScanLines drops the line ending, so the split function counts the
+	// bytes each line really took, ending included, to keep Offset right.
+	var start, next int64
+	sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil {
+			start, next = next, next+int64(advance)
+		}
+		return advance, token, err
+	})
+	for sc.Scan() {
+		s.lines++
+		s.bytes = next
+		if s.lines%1024 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// sc.Bytes is valid until the next Scan: matched in place, not
+		// copied. Match allocates nothing and skips the bookkeeping for
+		// groups, so lines that don't match cost only the matching; the
+		// groups' positions are worked out for the lines that do.
+		line := sc.Bytes()
+		if f.Re.Match(line) == f.Invert {
+			continue
+		}
+		m := Match{Line: s.lines, Offset: start, Text: string(line)}
+		if !f.Invert {
+			loc := f.Re.FindStringSubmatchIndex(m.Text)
+			m.Groups = make([]string, len(loc)/2)
+			for i := range m.Groups {
+				if loc[2*i] >= 0 {
+					m.Groups[i] = m.Text[loc[2*i]:loc[2*i+1]] // Slices of Text: no more copies
+				}
+			}
+		}
+		select {
+		case c <- m:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return sc.Err()
+}
+
+// Err returns why the search ended: nil at the end of the input or after
+// Stop, else the read error, bufio.ErrTooLong, or ctx's error. Call it
+// after C is closed.
+func (s *Stream) Err() error {
+	if s.stopped && errors.Is(s.err, context.Canceled) {
+		return nil
+	}
+	return s.err
+}
+
+// Stop ends the search early: the reading stops, matches not received
+// yet are dropped, and C is closed. It returns Err. Stopping a search
+// that has ended already does no harm.
+func (s *Stream) Stop() error {
+	s.stopped = true
+	s.cancel()
+	for range s.C {
+	}
+	return s.Err()
+}
+
+// Scanned returns how many lines and bytes were read, matching or not.
+// Like Err, it is for after C is closed.
+func (s *Stream) Scanned() (lines int, bytes int64) { return s.lines, s.bytes }
@@ -0,0 +1,217 @@
+package rxstream
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func collect(s *Stream) []Match {
+	var ms []Match
+	for m := range s.C {
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+func TestLines(t *testing.T) {
+	input := "GET /a 200\r\nGET /b 503\n\nPOST /c 500\nGET /d 204" // CRLF, an empty line, no final newline
+	s := Lines(context.Background(), strings.NewReader(input), regexp.MustCompile(`(GET|POST) (\S+) (5\d\d)`))
+	ms := collect(s)
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []Match{
+		{Line: 2, Offset: 12, Text: "GET /b 503", Groups: []string{"GET /b 503", "GET", "/b", "503"}},
+		{Line: 4, Offset: 24, Text: "POST /c 500", Groups: []string{"POST /c 500", "POST", "/c", "500"}},
+	}
+	if fmt.Sprint(ms) != fmt.Sprint(want) {
+		t.Errorf("matches:\n got %v\nwant %v", ms, want)
+	}
+	if lines, bytes := s.Scanned(); lines != 5 || bytes != int64(len(input)) {
+		t.Errorf("Scanned = %d lines, %d bytes; want 5, %d", lines, bytes, len(input))
+	}
+	for _, m := range ms {
+		if got := input[m.Offset : m.Offset+int64(len(m.Text))]; got != m.Text {
+			t.Errorf("line %d: input at Offset %d is %q, not the line", m.Line, m.Offset, got)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	re := regexp.MustCompile(`id=(\d+)(?: user=(\w+))?`)
+	ms := collect(Lines(context.Background(), strings.NewReader("id=1 user=ada\nid=2\n"), re))
+	if len(ms) != 2 || ms[0].Groups[2] != "ada" || ms[1].Groups[1] != "2" || ms[1].Groups[2] != "" {
+		t.Errorf("matches %q", ms)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	f := Filter{Re: regexp.MustCompile(`DEBUG`), Invert: true}
+	s := f.Lines(context.Background(), strings.NewReader("DEBUG a\nINFO b\nDEBUG c\nWARN d\n"))
+	var got []string
+	for m := range s.C {
+		if m.Groups != nil {
+			t.Errorf("line %d: Groups %q with Invert", m.Line, m.Groups)
+		}
+		got = append(got, m.Text)
+	}
+	if strings.Join(got, ",") != "INFO b,WARN d" {
+		t.Errorf("Invert sent %q", got)
+	}
+}
+
+func TestTooLong(t *testing.T) {
+	input := "ok 1\n" + strings.Repeat("x", 100) + "\nok 2\n"
+	s := Filter{Re: regexp.MustCompile(`ok`), MaxLine: 64}.Lines(context.Background(), strings.NewReader(input))
+	ms := collect(s)
+	if len(ms) != 1 || !errors.Is(s.Err(), bufio.ErrTooLong) {
+		t.Errorf("%d matches, Err %v; want the first line, then ErrTooLong", len(ms), s.Err())
+	}
+
+	// The default takes lines far past bufio.Scanner's 64 KB.
+	long := "ok " + strings.Repeat("x", 500<<10)
+	s = Lines(context.Background(), strings.NewReader(long), regexp.MustCompile(`^ok`))
+	if ms := collect(s); len(ms) != 1 || s.Err() != nil {
+		t.Errorf("a 500 KB line: %d matches, Err %v", len(ms), s.Err())
+	}
+}
+
+func TestReadError(t *testing.T) {
+	broken := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("match 1\nmatch 2\n"), iotest.ErrReader(broken))
+	s := Lines(context.Background(), r, regexp.MustCompile(`match`))
+	if ms := collect(s); len(ms) != 2 || !errors.Is(s.Err(), broken) {
+		t.Errorf("%d matches, Err %v; want both lines, then the read error", len(ms), s.Err())
+	}
+}
+
+// TestStop breaks off an endless input after a few matches, and stops
+// one that never matches. Either way the goroutine must end, and
+// stopping isn't an error; a canceled ctx is.
+func TestStop(t *testing.T) {
+	s := Filter{Re: regexp.MustCompile(`line`), Buffer: 1}.Lines(context.Background(), newLog(-1))
+	for m := range s.C {
+		if m.Line == 3 {
+			break
+		}
+	}
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop = %v", err)
+	}
+	if _, ok := <-s.C; ok {
+		t.Error("C still open after Stop")
+	}
+
+	s = Lines(context.Background(), newLog(-1), regexp.MustCompile(`never`))
+	time.Sleep(10 * time.Millisecond)
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop while scanning = %v", err)
+	}
+
+	s = Lines(context.Background(), strings.NewReader("a\nb\n"), regexp.MustCompile(`b`))
+	if ms := collect(s); len(ms) != 1 || s.Stop() != nil {
+		t.Errorf("Stop after the end: %v", s.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s = Lines(ctx, newLog(-1), regexp.MustCompile(`never`))
+	cancel()
+	for range s.C { // Closed soon after cancel, or the test times out
+	}
+	if !errors.Is(s.Err(), context.Canceled) {
+		t.Errorf("ctx canceled: Err = %v, want context.Canceled", s.Err())
+	}
+}
+
+// raceEnabled is set by race_test.go when the race detector is on.
+var raceEnabled bool
+
+// TestMemory streams 64 MB and checks the point of the package: memory
+// is spent on the matching lines, not on the input.
+func TestMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("reads 64 MB")
+	}
+	if raceEnabled {
+		t.Skip("the race detector allocates on its own; the count would measure it")
+	}
+	const lines = 1 << 20 // 64 bytes each
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	s := Lines(context.Background(), newLog(lines), regexp.MustCompile(`status=(5\d\d) .* line 1000\d\d\b`))
+	ms := collect(s)
+	runtime.ReadMemStats(&after)
+	if s.Err() != nil || len(ms) == 0 {
+		t.Fatalf("%d matches, Err %v", len(ms), s.Err())
+	}
+	_, read := s.Scanned()
+	allocated := after.TotalAlloc - before.TotalAlloc
+	if allocated > 1<<20 {
+		t.Errorf("allocated %d KB to read %d MB", allocated>>10, read>>20)
+	}
+	t.Logf("read %d MB, %d matches, allocated %d KB", read>>20, len(ms), allocated>>10)
+}
+
+// log is an io.Reader of n made-up log lines, 64 bytes each, generated
+// as they are read; n < 0 never ends.
+type log struct {
+	n, i    int
+	pending []byte
+	buf     [64]byte
+}
+
+func newLog(n int) *log { return &log{n: n} }
+
+func (l *log) Read(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		if len(l.pending) == 0 {
+			if l.i == l.n {
+				break
+			}
+			// strconv, not fmt.Appendf: boxing the numbers would allocate,
+			// and TestMemory would measure this reader.
+			line := append(l.buf[:0], "2026-10-16T09:00:00Z status="...)
+			line = strconv.AppendInt(line, []int64{200, 200, 200, 404, 503}[l.i%5], 10)
+			line = append(line, " path=/api/v1 line "...)
+			line = strconv.AppendInt(line, int64(l.i), 10)
+			for len(line) < 63 {
+				line = append(line, ' ')
+			}
+			l.pending = append(line, '\n')
+			l.i++
+		}
+		n := copy(p, l.pending)
+		l.pending, p, total = l.pending[n:], p[n:], total+n
+	}
+	if total == 0 {
+		return 0, io.EOF
+	}
+	return total, nil
+}
+
+// BenchmarkLines reports throughput in MB/s: the reading, splitting and
+// matching, for a pattern that matches one line in five.
+func BenchmarkLines(b *testing.B) {
+	re := regexp.MustCompile(`status=5\d\d`)
+	const lines = 1 << 14
+	b.SetBytes(lines * 64)
+	for b.Loop() {
+		s := Lines(context.Background(), newLog(lines), re)
+		for range s.C {
+		}
+		if s.Err() != nil {
+			b.Fatal(s.Err())
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package safe turns a panic into a saved crash report and an ordinary
+// error (Topic 139). The report holds what a bug report needs: the Go
+// version and platform, what was running, the panic and its stack, and
+// the last log lines from a pkg/ring:
+//
+//	runner := &safe.Runner{ReportDir: dir, Log: recent}
+//	err := runner.Run("73", lesson) // "topic 73 panicked: ...\ncrash report saved to ..."
+//
+// A caller with its own recover, one that must return an error of its
+// own kind, calls Report from its deferred function instead:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			path, saveErr := runner.Report("run 73", r, debug.Stack())
+//			...
+//		}
+//	}()
+//
+// debug.Stack must be called inside the deferred function: there, the
+// stack still includes the panic site.
+package safe
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/ring"
+)
+
+// Dir is where gotut keeps crash reports: crashes/ in $GOTUT_CONFIG_DIR,
+// else in <config dir>/gotut.
+func Dir() (string, error) {
+	dir := os.Getenv("GOTUT_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, "gotut")
+	}
+	return filepath.Join(dir, "crashes"), nil
+}
+
+// CrashReport is everything saved about one panic.
+type CrashReport struct {
+	Time      time.Time
+	GoVersion string
+	OS        string
+	Arch      string
+	Topic     string
+	Panic     any
+	Stack     []byte
+	RecentLog []string
+}
+
+// WriteTo writes the report as text: a header, the recent log, the stack.
+func (c CrashReport) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gotut crash report\n")
+	fmt.Fprintf(&b, "==================\n")
+	fmt.Fprintf(&b, "time:       %s\n", c.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "go version: %s\n", c.GoVersion)
+	fmt.Fprintf(&b, "platform:   %s/%s\n", c.OS, c.Arch)
+	fmt.Fprintf(&b, "topic:      %s\n", c.Topic)
+	fmt.Fprintf(&b, "panic:      %v\n\n", c.Panic)
+	fmt.Fprintf(&b, "--- recent log (%d lines) ---\n", len(c.RecentLog))
+	for _, line := range c.RecentLog {
+		fmt.Fprintf(&b, "%s\n", line)
+	}
+	fmt.Fprintf(&b, "\n--- stack trace ---\n%s", c.Stack)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Save writes the report to dir/crash-<timestamp>.txt, readable only by
+// its owner (it may hold log lines), and returns the path.
+func (c CrashReport) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("crash-%s.txt", c.Time.Format("20060102-150405.000"))
+	path := filepath.Join(dir, name)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := c.WriteTo(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// Runner saves a crash report in ReportDir for every panic it recovers.
+// Log, if set, supplies the report's recent log lines.
+type Runner struct {
+	ReportDir string
+	Log       *ring.Ring
+}
+
+// Run calls fn. If fn panics, Run recovers, saves a report, and returns
+// an error naming the report file.
+func (s *Runner) Run(topic string, fn func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		path, saveErr := s.Report(topic, r, debug.Stack())
+		if saveErr != nil {
+			err = fmt.Errorf("topic %s panicked: %v (and the crash report could not be saved: %v)", topic, r, saveErr)
+			return
+		}
+		err = fmt.Errorf("topic %s panicked: %v\ncrash report saved to %s", topic, r, path)
+	}()
+
+	fn()
+	return nil
+}
+
+// Report saves a report of the panic r, recovered while topic ran, with
+// its stack, and returns the report's path.
+func (s *Runner) Report(topic string, r any, stack []byte) (string, error) {
+	report := CrashReport{
+		Time:      time.Now(),
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Topic:     topic,
+		Panic:     r,
+		Stack:     stack,
+		RecentLog: s.Log.Lines(),
+	}
+	return report.Save(s.ReportDir)
+}
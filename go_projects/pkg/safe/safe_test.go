@@ -0,0 +1,85 @@
+package safe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/ring"
+)
+
+func crashes(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestRunSavesAReport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crashes") // Created on the first crash
+	recent := ring.New(2)
+	runner := &Runner{ReportDir: dir, Log: recent}
+
+	if err := runner.Run("ok", func() { fmt.Fprintln(recent, "fine") }); err != nil {
+		t.Fatalf("Run without a panic = %v", err)
+	}
+	if got := crashes(t, dir); len(got) != 0 {
+		t.Fatalf("reports without a panic: %v", got)
+	}
+
+	err := runner.Run("73", func() {
+		fmt.Fprintln(recent, "step 1")
+		fmt.Fprintln(recent, "step 2")
+		var m map[string]int
+		m["boom"]++ // A nil map write: a runtime panic
+	})
+	got := crashes(t, dir)
+	if len(got) != 1 {
+		t.Fatalf("reports = %v, want one", got)
+	}
+	if err == nil || !strings.Contains(err.Error(), "topic 73 panicked") || !strings.Contains(err.Error(), got[0]) {
+		t.Errorf("Run = %v; want it to name the topic and %s", err, got[0])
+	}
+	data, err := os.ReadFile(got[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"go version: " + runtime.Version(),
+		"platform:   " + runtime.GOOS + "/" + runtime.GOARCH,
+		"topic:      73",
+		"panic:      assignment to entry in nil map",
+		"--- recent log (2 lines) ---\nstep 1\nstep 2\n",
+		"safe_test.go", // The panic site is in the stack
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report has no %q:\n%s", want, data)
+		}
+	}
+	if info, err := os.Stat(got[0]); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("report mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestRunUnsaved(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file") // Not a directory
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := (&Runner{ReportDir: file}).Run("4", func() { panic("boom") })
+	if err == nil || !strings.Contains(err.Error(), "topic 4 panicked: boom (and the crash report could not be saved") {
+		t.Errorf("Run with nowhere to save = %v", err)
+	}
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv("GOTUT_CONFIG_DIR", "/config")
+	if got, err := Dir(); err != nil || got != filepath.Join("/config", "crashes") {
+		t.Errorf("Dir = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,98 @@
+// Package section runs one part of a lesson. A lesson lists its parts
+// as data and hands them to Run, which runs the one the command line
+// asks for, or all of them:
+//
+//	var sections = []section.Section{
+//		{Name: "basic-sha256-hashing", Run: basicSHA256Hashing},
+//		{Name: "avalanche-effect", Run: avalancheEffect},
+//	}
+//
+//	func main() {
+//		section.Run(sections)
+//	}
+//
+//	$ go run 82_sha_detailed.go -section avalanche
+//
+// A query is a whole name, a 1-based position or a unique prefix. Topic
+// 171 is the lesson on the idea, and the tool (sectionize) that turns
+// a lesson's main into the table; each lesson it converted carried its
+// own copy of Pick until the copies moved here.
+package section
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Section is one part of a lesson.
+type Section struct {
+	Name string // What -section matches: "avalanche-effect"
+	Run  func()
+}
+
+// Pick returns the sections "-section QUERY" in args asks for, or all
+// of them when args is empty. args is parsed with package flag, so
+// "-section=QUERY" and "--section QUERY" work too; any other flag or
+// argument is an error rather than ignored, since running every section
+// when the reader asked for one is the wrong answer. A query that
+// matches no section, or more than one by prefix, is an error listing
+// the names.
+func Pick(all []Section, args []string) ([]Section, error) {
+	fs := flag.NewFlagSet("section", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // The error says it, with the names
+	query := fs.String("section", "", "")
+	if err := fs.Parse(args); err != nil {
+		return nil, listed(all, "%v; usage: -section NAME", err)
+	}
+	if fs.NArg() > 0 {
+		return nil, listed(all, "unexpected argument %q; usage: -section NAME", fs.Arg(0))
+	}
+	asked := false
+	fs.Visit(func(*flag.Flag) { asked = true })
+	if !asked {
+		return all, nil
+	}
+	var hits []Section
+	for i, s := range all {
+		if s.Name == *query || strconv.Itoa(i+1) == *query {
+			return []Section{s}, nil
+		}
+		if strings.HasPrefix(s.Name, *query) {
+			hits = append(hits, s)
+		}
+	}
+	if len(hits) == 1 {
+		return hits, nil
+	}
+	return nil, listed(all, "no single section matches %q", *query)
+}
+
+// listed is an error saying format, then naming every section.
+func listed(all []Section, format string, args ...any) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, format, args...)
+	b.WriteString("; sections:")
+	for i, s := range all {
+		fmt.Fprintf(&b, "\n  %d  %s", i+1, s.Name)
+	}
+	return errors.New(b.String())
+}
+
+// Run runs the sections os.Args asks for, in order. A query Pick
+// rejects is printed to stderr, and the lesson exits 2 without running
+// anything.
+func Run(all []Section) {
+	picked, err := Pick(all, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	for _, s := range picked {
+		s.Run()
+	}
+}
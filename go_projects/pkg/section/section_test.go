@@ -0,0 +1,49 @@
+package section
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPick(t *testing.T) {
+	all := []Section{{Name: "md5"}, {Name: "md5-vs-sha256"}, {Name: "sha256-basics"}, {Name: "avalanche-effect"}}
+	for _, tt := range []struct {
+		args []string
+		want string // The names picked, or the error's start
+	}{
+		{nil, "md5 md5-vs-sha256 sha256-basics avalanche-effect"},
+		{[]string{"-section", "avalanche"}, "avalanche-effect"},
+		{[]string{"-section=avalanche"}, "avalanche-effect"},
+		{[]string{"--section", "3"}, "sha256-basics"},
+		{[]string{"--section=3"}, "sha256-basics"},
+		{[]string{"-section", "md5"}, "md5"}, // Exact beats prefix
+		{[]string{"-section", "md5-"}, "md5-vs-sha256"},
+		{[]string{"-section", "sha"}, "sha256-basics"},
+		{[]string{"-section", "5"}, `no single section matches "5"`},
+		{[]string{"-section", ""}, `no single section matches ""`}, // Every name is a prefix match
+		{[]string{"-section", "bcrypt"}, `no single section matches "bcrypt"`},
+		// Anything else is refused, not ignored: all four would run.
+		{[]string{"-width", "50"}, "flag provided but not defined: -width"},
+		{[]string{"section", "md5"}, `unexpected argument "section"`},
+		{[]string{"-section", "md5", "extra"}, `unexpected argument "extra"`},
+		{[]string{"-section"}, "flag needs an argument: -section"},
+	} {
+		picked, err := Pick(all, tt.args)
+		var got string
+		if err != nil {
+			got = err.Error()
+		} else {
+			var names []string
+			for _, s := range picked {
+				names = append(names, s.Name)
+			}
+			got = strings.Join(names, " ")
+		}
+		if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("Pick(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+	if _, err := Pick(all, []string{"-section", "x"}); !strings.Contains(err.Error(), "\n  4  avalanche-effect") {
+		t.Errorf("the error doesn't list the sections: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package shq quotes strings so that a shell reads each back as one
+// argument, unchanged, whatever it contains (Topic 202):
+//
+//	cmd := "rm -- " + shq.Quote(name)   // name = "x; rm -rf ~" stays a file name
+//
+// Quote and Join are for POSIX shells (sh, bash, zsh, dash). Windows has
+// two layers: QuoteWindows for the program's argument parser, and
+// QuoteCmd on top of it when the line goes through cmd.exe. Funcs puts
+// them in a template.FuncMap, for templates that write commands
+// (intermediate Topic 72).
+//
+// Quoting is the fallback. A program that runs another one should pass
+// the arguments to exec.Command as a list, with no shell and nothing to
+// quote; shq is for when a shell is the point — a generated script, a
+// command shown to the user to copy, a line sent over ssh.
+package shq
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+)
+
+// ErrUnsafe is returned for an argument no quoting can carry: a NUL
+// byte anywhere (it ends a C string, so no program can receive it), and
+// for cmd.exe a line break too (it ends the command).
+var ErrUnsafe = errors.New("shq: argument can't be quoted safely")
+
+// safe reports whether s needs no quotes in a POSIX shell: it is not
+// empty and has only letters, digits and @%+=:,./_- — none of them
+// special to the shell. A leading "-" is still read as an option by
+// most programs; put "--" before such arguments.
+func safe(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("@%+=:,./_-", c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Quote returns s as one POSIX shell word. Strings that need no quoting
+// come back as they are; the rest go in single quotes, inside which
+// nothing is special — not $, `, \, or a newline — except the single
+// quote itself: the quotes are closed, the quote escaped, and reopened.
+//
+//	report.txt     report.txt
+//	my file.txt    'my file.txt'
+//	it's           'it'\''s'
+//	""             ''
+//
+// s must not contain a NUL byte; see ErrUnsafe.
+func Quote(s string) string {
+	if safe(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Join quotes each argument and joins them with spaces: a command line
+// that a POSIX shell splits back into exactly args.
+func Join(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = Quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// QuoteWindows returns s as one argument of a Windows command line, as
+// the C runtime and CommandLineToArgvW split it (and as os/exec builds
+// one, syscall.EscapeArg): in double quotes when it has spaces, tabs,
+// line breaks or quotes, with a quote inside written \" and the
+// backslashes just before a quote doubled. Other backslashes are
+// literal, so paths are left alone:
+//
+//	C:\Temp\a.txt      C:\Temp\a.txt
+//	C:\My Files\       "C:\My Files\\"
+//	say "hi"           "say \"hi\""
+//
+// This is what the program sees. cmd.exe reads the line first, by its
+// own rules; for a line that goes through it, use QuoteCmd.
+func QuoteWindows(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(s, " \t\n\v\"") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			slashes++
+		case '"':
+			// 2n+1 backslashes and the quote: n literal backslashes and a literal quote.
+			b.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		b.WriteByte(s[i])
+	}
+	// The closing quote must not be escaped by backslashes that end s.
+	b.WriteString(strings.Repeat(`\`, slashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// JoinWindows quotes each argument with QuoteWindows and joins them with
+// spaces.
+func JoinWindows(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = QuoteWindows(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// cmdSpecial are the characters cmd.exe acts on, quoted or not.
+const cmdSpecial = `()%!^"<>&|`
+
+// QuoteCmd returns s as one argument of a line run by cmd.exe (cmd /c,
+// or typed at its prompt): QuoteWindows, then a ^ before each character
+// cmd.exe would act on, quotes included, so that cmd.exe passes the
+// line to the program as QuoteWindows wrote it. It refuses line breaks,
+// which no escape keeps inside the command, and NUL. Batch files are
+// different again: there % must be doubled, and this isn't enough.
+func QuoteCmd(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n\x00") {
+		return "", ErrUnsafe
+	}
+	q := QuoteWindows(s)
+	var b strings.Builder
+	for i := 0; i < len(q); i++ {
+		if strings.IndexByte(cmdSpecial, q[i]) >= 0 {
+			b.WriteByte('^')
+		}
+		b.WriteByte(q[i])
+	}
+	return b.String(), nil
+}
+
+// Funcs returns template functions for writing commands, a new map each
+// call:
+//
+//	shquote   {{shquote .Name}}     Quote
+//	shjoin    {{shjoin .Args}}      Join, of a []string
+//	winquote  {{winquote .Name}}    QuoteWindows
+//	cmdquote  {{cmdquote .Name}}    QuoteCmd
+//
+// Unlike the functions they wrap, all of them stop the template with
+// ErrUnsafe on a NUL byte: a template's data comes from somewhere else,
+// and a command that can't be written must not be written wrong.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"shquote":  checked(Quote),
+		"winquote": checked(QuoteWindows),
+		"cmdquote": QuoteCmd,
+		"shjoin": func(args []string) (string, error) {
+			for _, a := range args {
+				if strings.IndexByte(a, 0) >= 0 {
+					return "", ErrUnsafe
+				}
+			}
+			return Join(args...), nil
+		},
+	}
+}
+
+func checked(quote func(string) string) func(string) (string, error) {
+	return func(s string) (string, error) {
+		if strings.IndexByte(s, 0) >= 0 {
+			return "", ErrUnsafe
+		}
+		return quote(s), nil
+	}
+}
@@ -0,0 +1,223 @@
+package shq
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// hostile are file names built to break a command line that isn't quoted.
+var hostile = []string{
+	"plain.txt",
+	"",
+	"my file.txt",
+	"  leading and trailing  ",
+	"it's.txt",
+	`say "hi".txt`,
+	"'''",
+	`back\slash\`,
+	"line\nbreak.txt",
+	"tab\there",
+	"$(touch pwned)",
+	"`touch pwned`",
+	"x; touch pwned",
+	"a && touch pwned",
+	"$HOME ~ * ? [a] {b,c}",
+	"-rf",
+	"--",
+	"!event",
+	"日本語 ファイル.txt",
+	`C:\My Files\`,
+	`\\"\\`,
+	"100% ^caret^ (paren) <in >out | pipe & amp",
+}
+
+func TestQuote(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"report.txt", "report.txt"},
+		{"a/b-c_d.e:f,g@h%i+j=k", "a/b-c_d.e:f,g@h%i+j=k"},
+		{"", "''"},
+		{"my file.txt", "'my file.txt'"},
+		{"it's", `'it'\''s'`},
+		{"$(id)", "'$(id)'"},
+		{"line\nbreak", "'line\nbreak'"},
+		{"~", "'~'"},
+	} {
+		if got := Quote(tc.in); got != tc.want {
+			t.Errorf("Quote(%q) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestQuoteRoundTrip has a real shell split Join's output back into
+// arguments, and expects the hostile names back, byte for byte.
+func TestQuoteRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh:", err)
+	}
+	dir := t.TempDir()
+	cmd := exec.Command(sh, "-c", `printf '%s\0' `+Join(hostile...))
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if !slices.Equal(got, hostile) {
+		t.Errorf("sh split the line into\n%q\nwant\n%q", got, hostile)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+		t.Error("a quoted argument ran a command")
+	}
+}
+
+// TestRemoveHostileFiles creates each hostile file, then removes it with
+// a generated "rm --" line: only that file goes, and no command runs.
+func TestRemoveHostileFiles(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh:", err)
+	}
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep")
+	os.WriteFile(keep, nil, 0o644)
+	for _, name := range hostile {
+		if name == "" || name == "--" || strings.ContainsAny(name, `/\`) {
+			continue // Not a file name on every system
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(sh, "-c", "rm -- "+Quote(name))
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("rm -- %s: %v\n%s", Quote(name), err, out)
+		}
+		left, _ := os.ReadDir(dir)
+		if len(left) != 1 || left[0].Name() != "keep" {
+			var names []string
+			for _, e := range left {
+				names = append(names, e.Name())
+			}
+			t.Fatalf("after removing %q the directory has %q, want only keep", name, names)
+		}
+	}
+}
+
+// splitWindows splits a command line as the C runtime does (since 2008;
+// CommandLineToArgvW agrees), the other half of QuoteWindows.
+func splitWindows(line string) []string {
+	var args []string
+	var b strings.Builder
+	inQuote, started := false, false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case (c == ' ' || c == '\t') && !inQuote:
+			if started {
+				args = append(args, b.String())
+				b.Reset()
+				started = false
+			}
+		case c == '\\':
+			n := 0
+			for i < len(line) && line[i] == '\\' {
+				n++
+				i++
+			}
+			if i < len(line) && line[i] == '"' {
+				b.WriteString(strings.Repeat(`\`, n/2))
+				if n%2 == 1 {
+					b.WriteByte('"')
+				} else {
+					i-- // An unescaped quote: the next turn handles it
+				}
+			} else {
+				b.WriteString(strings.Repeat(`\`, n))
+				i--
+			}
+			started = true
+		case c == '"':
+			if inQuote && i+1 < len(line) && line[i+1] == '"' {
+				b.WriteByte('"')
+				i++
+			} else {
+				inQuote = !inQuote
+			}
+			started = true
+		default:
+			b.WriteByte(c)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, b.String())
+	}
+	return args
+}
+
+func TestQuoteWindows(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{`C:\Temp\a.txt`, `C:\Temp\a.txt`},
+		{"", `""`},
+		{`C:\My Files\`, `"C:\My Files\\"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`a\"b`, `"a\\\"b"`},
+		{"it's", "it's"},
+	} {
+		if got := QuoteWindows(tc.in); got != tc.want {
+			t.Errorf("QuoteWindows(%q) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+	line := JoinWindows(hostile...)
+	if got := splitWindows(line); !slices.Equal(got, hostile) {
+		t.Errorf("%s\nsplits into\n%q\nwant\n%q", line, got, hostile)
+	}
+}
+
+func TestQuoteCmd(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"plain.txt", "plain.txt"},
+		{"a & b", `^"a ^& b^"`},
+		{"100%", "100^%"},
+		{"x|y>z", "x^|y^>z"},
+		{`say "hi"`, `^"say \^"hi\^"^"`},
+	} {
+		got, err := QuoteCmd(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("QuoteCmd(%q) = %s, %v; want %s", tc.in, got, err, tc.want)
+		}
+	}
+	for _, s := range []string{"line\nbreak", "cr\r", "nul\x00"} {
+		if _, err := QuoteCmd(s); !errors.Is(err, ErrUnsafe) {
+			t.Errorf("QuoteCmd(%q) = %v, want ErrUnsafe", s, err)
+		}
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("cmd").Funcs(Funcs()).Parse(
+		`cp -- {{shquote .Src}} {{shquote .Dst}}; tar cf out.tar -- {{shjoin .Files}}; copy {{winquote .Src}}; {{cmdquote .Dst}}`))
+	var b strings.Builder
+	err := tmpl.Execute(&b, map[string]any{"Src": "my file", "Dst": "a&b", "Files": []string{"x", "it's"}})
+	want := `cp -- 'my file' 'a&b'; tar cf out.tar -- x 'it'\''s'; copy "my file"; a^&b`
+	if err != nil || b.String() != want {
+		t.Errorf("got %s, %v\nwant %s", b.String(), err, want)
+	}
+	for _, data := range []map[string]any{
+		{"Src": "nul\x00", "Dst": "x", "Files": []string{}},
+		{"Src": "x", "Dst": "x", "Files": []string{"ok", "nul\x00"}},
+	} {
+		if err := tmpl.Execute(&b, data); !errors.Is(err, ErrUnsafe) {
+			t.Errorf("%q: %v, want ErrUnsafe", data, err)
+		}
+	}
+	if Funcs()["shquote"] == nil || len(Funcs()) != 4 {
+		t.Error("Funcs is missing functions")
+	}
+}
@@ -0,0 +1,145 @@
+// Package splitters holds bufio.SplitFunc values for records that are not
+// plain lines (see Topic 80):
+//
+//	sc := bufio.NewScanner(r)
+//	sc.Split(splitters.Timestamped)
+//	for sc.Scan() {
+//		entry := sc.Text() // A log line and the stack trace under it
+//	}
+//
+// A SplitFunc sees whatever the Scanner has buffered so far. When that
+// isn't a whole record yet it returns (0, nil, nil) and the Scanner reads
+// more; at EOF it must return what is left. The tests feed every function
+// one byte at a time to check exactly that.
+package splitters
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// CRLF splits on "\r\n" only, as HTTP/1.1 headers, SMTP and RFC 4180 CSV
+// do. A lone "\n" or "\r" inside a record is data, unlike bufio.ScanLines,
+// which ends a line at any "\n".
+func CRLF(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.Index(data, []byte("\r\n")); i >= 0 {
+		return i + 2, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// Null splits NUL-terminated records: the output of find -print0,
+// xargs -0 and git's -z flags, where a file name may contain newlines.
+func Null(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// ErrShortRecord is returned by a FixedWidth split when the input ends in
+// the middle of a record.
+var ErrShortRecord = errors.New("splitters: input ends inside a fixed-width record")
+
+// FixedWidth splits records of exactly n bytes, as in mainframe exports
+// and many binary formats. A trailing partial record is an error, not a
+// short token: silently accepting it would shift every field.
+func FixedWidth(n int) bufio.SplitFunc {
+	if n <= 0 {
+		panic(fmt.Sprintf("splitters: FixedWidth(%d)", n))
+	}
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if len(data) >= n {
+			return n, data[:n], nil
+		}
+		if atEOF && len(data) > 0 {
+			return 0, nil, fmt.Errorf("%w: %d of %d bytes", ErrShortRecord, len(data), n)
+		}
+		return 0, nil, nil
+	}
+}
+
+// Entries splits multi-line records: a record starts at a line for which
+// isStart is true and runs up to the next such line, so the indented
+// lines of a stack trace stay with the log line above them. Lines before
+// the first start line form a record of their own. The final newline of
+// each record is dropped, like bufio.ScanLines does.
+//
+// A record must fit in the Scanner's buffer (64 KiB by default); raise it
+// with Scanner.Buffer for long traces, or Scan fails with ErrTooLong.
+func Entries(isStart func(line []byte) bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		// The record's own first line never ends it; look from the second.
+		first := bytes.IndexByte(data, '\n')
+		for next := first + 1; first >= 0 && next < len(data); {
+			end := bytes.IndexByte(data[next:], '\n')
+			if end < 0 && !atEOF {
+				break // Can't tell yet whether this partial line starts a record
+			}
+			line := data[next:]
+			if end >= 0 {
+				line = data[next : next+end]
+			}
+			if isStart(line) {
+				return next, dropNewline(data[:next]), nil
+			}
+			if end < 0 {
+				break
+			}
+			next += end + 1
+		}
+		if atEOF {
+			return len(data), dropNewline(data), nil
+		}
+		return 0, nil, nil // Need more data to find where this record ends
+	}
+}
+
+func dropNewline(b []byte) []byte {
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	return bytes.TrimSuffix(b, []byte("\r"))
+}
+
+// StartsWithTimestamp reports whether line begins with an RFC 3339 time
+// such as "2026-10-16T10:00:01" (a space instead of the T also counts).
+// It checks the shape only, which is all a splitter needs.
+func StartsWithTimestamp(line []byte) bool {
+	const shape = "dddd-dd-ddTdd:dd:dd"
+	if len(line) < len(shape) {
+		return false
+	}
+	for i := range len(shape) {
+		c := line[i]
+		switch shape[i] {
+		case 'd':
+			if c < '0' || c > '9' {
+				return false
+			}
+		case 'T':
+			if c != 'T' && c != ' ' {
+				return false
+			}
+		default:
+			if c != shape[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Timestamped splits a log whose entries start with a timestamp; the
+// lines after one that don't (a panic's goroutine dump, a wrapped
+// message) belong to it.
+var Timestamped = Entries(StartsWithTimestamp)
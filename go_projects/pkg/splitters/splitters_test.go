@@ -0,0 +1,116 @@
+package splitters
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// scan runs split over input twice — all at once, and one byte per Read,
+// the way a slow pipe delivers it — and checks both give the same tokens.
+func scan(t *testing.T, split bufio.SplitFunc, input string) ([]string, error) {
+	t.Helper()
+	var results [2][]string
+	var errs [2]error
+	for i, r := range []io.Reader{
+		strings.NewReader(input),
+		iotest.OneByteReader(strings.NewReader(input)),
+	} {
+		sc := bufio.NewScanner(r)
+		sc.Split(split)
+		for sc.Scan() {
+			results[i] = append(results[i], sc.Text())
+		}
+		errs[i] = sc.Err()
+	}
+	if !slices.Equal(results[0], results[1]) || fmt.Sprint(errs[0]) != fmt.Sprint(errs[1]) {
+		t.Fatalf("whole input gave %q (%v), one byte at a time gave %q (%v)", results[0], errs[0], results[1], errs[1])
+	}
+	return results[0], errs[0]
+}
+
+func TestCRLF(t *testing.T) {
+	got, err := scan(t, CRLF, "Host: a\r\nX-Note: one\ntwo\r\n\r\nbody")
+	want := []string{"Host: a", "X-Note: one\ntwo", "", "body"}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestNull(t *testing.T) {
+	got, err := scan(t, Null, "a.txt\x00new\nline.txt\x00\x00last")
+	want := []string{"a.txt", "new\nline.txt", "", "last"}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestFixedWidth(t *testing.T) {
+	got, err := scan(t, FixedWidth(4), "AAAABBBBCCCC")
+	if want := []string{"AAAA", "BBBB", "CCCC"}; err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %q, %v; want %q", got, err, want)
+	}
+	got, err = scan(t, FixedWidth(4), "AAAABB")
+	if !errors.Is(err, ErrShortRecord) || !slices.Equal(got, []string{"AAAA"}) {
+		t.Errorf("short tail: got %q, %v; want [AAAA], ErrShortRecord", got, err)
+	}
+}
+
+const panicLog = `2026-10-16T10:00:01Z INFO started
+2026-10-16T10:00:02Z ERROR handler panicked: runtime error: index out of range [3] with length 3
+goroutine 7 [running]:
+main.handle(...)
+	/app/main.go:42 +0x1d
+2026-10-16 10:00:03 INFO recovered
+`
+
+func TestTimestamped(t *testing.T) {
+	got, err := scan(t, Timestamped, panicLog)
+	if err != nil || len(got) != 3 {
+		t.Fatalf("got %d entries %q, %v; want 3", len(got), got, err)
+	}
+	if n := strings.Count(got[1], "\n"); n != 3 || !strings.HasSuffix(got[1], "+0x1d") {
+		t.Errorf("the trace did not stay with its entry: %q", got[1])
+	}
+	if got[2] != "2026-10-16 10:00:03 INFO recovered" {
+		t.Errorf("last entry = %q", got[2])
+	}
+}
+
+func TestEntriesEdges(t *testing.T) {
+	for _, tc := range []struct {
+		name, input string
+		want        []string
+	}{
+		{"empty", "", nil},
+		{"no trailing newline", "2026-10-16T10:00:01 a\n  more", []string{"2026-10-16T10:00:01 a\n  more"}},
+		{"preamble before first entry", "junk\nmore junk\n2026-10-16T10:00:01 a\n", []string{"junk\nmore junk", "2026-10-16T10:00:01 a"}},
+		{"CRLF endings", "2026-10-16T10:00:01 a\r\n\tat x\r\n2026-10-16T10:00:02 b\r\n", []string{"2026-10-16T10:00:01 a\r\n\tat x", "2026-10-16T10:00:02 b"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := scan(t, Timestamped, tc.input)
+			if err != nil || !slices.Equal(got, tc.want) {
+				t.Errorf("got %q, %v; want %q", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestStartsWithTimestamp(t *testing.T) {
+	for line, want := range map[string]bool{
+		"2026-10-16T10:00:01Z x": true,
+		"2026-10-16 10:00:01 x":  true,
+		"2026-10-16":             false,
+		"\tat main.go:42":        false,
+		"2026/10/16 10:00:01 x":  false, // log.LstdFlags uses slashes
+	} {
+		if got := StartsWithTimestamp([]byte(line)); got != want {
+			t.Errorf("StartsWithTimestamp(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
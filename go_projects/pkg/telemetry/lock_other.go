@@ -0,0 +1,35 @@
+//go:build !unix
+
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// staleLock is how old a flush lock must be before Flush says it was
+// probably left by a flush that died.
+const staleLock = 10 * time.Minute
+
+// lock takes the flush lock shared by every process using t.Dir: a file
+// only one of them can create. Without flock(2) nothing releases it if
+// its holder dies, and removing someone else's lock can't be done without
+// a race, so an old lock is reported, not broken.
+func (t *Telemetry) lock() (unlock func(), err error) {
+	f, err := os.OpenFile(t.lockPath(), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err == nil {
+		f.Close()
+		return func() { os.Remove(t.lockPath()) }, nil
+	}
+	if !errors.Is(err, fs.ErrExist) {
+		return nil, err
+	}
+	if info, err := os.Stat(t.lockPath()); err == nil && time.Since(info.ModTime()) > staleLock {
+		return nil, fmt.Errorf("%w: %s is %v old; remove it if no flush is running",
+			ErrFlushBusy, t.lockPath(), time.Since(info.ModTime()).Round(time.Minute))
+	}
+	return nil, ErrFlushBusy
+}
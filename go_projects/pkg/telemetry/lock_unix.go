@@ -0,0 +1,28 @@
+//go:build unix
+
+package telemetry
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lock takes the flush lock shared by every process using t.Dir: flock(2)
+// on a file that is never removed. The kernel releases it when the holder
+// closes the file or dies, so a flush that crashed leaves no lock behind,
+// and there is no stale lock for two processes to race to break.
+func (t *Telemetry) lock() (unlock func(), err error) {
+	f, err := os.OpenFile(t.lockPath(), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrFlushBusy
+		}
+		return nil, err
+	}
+	return func() { f.Close() }, nil // Closing releases the lock
+}
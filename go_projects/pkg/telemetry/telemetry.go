@@ -0,0 +1,348 @@
+// Package telemetry records anonymous usage events to a local queue,
+// only after the user opted in, and flushes them to an endpoint in
+// batches (Topic 138):
+//
+//	t := telemetry.New(dir, endpoint)
+//	t.Record(telemetry.Event{Name: "lesson_run", Topic: "73", Time: time.Now()})
+//	sent, err := t.Flush(ctx)
+//
+// Events are redacted before they touch disk: only allow-listed props
+// are kept, and only when their value doesn't look like a path or an
+// address. Record appends to the queue with O_APPEND, so several
+// processes can record at once. Flush takes a lock, so that only one
+// process flushes at a time, and renames the queue before reading it, so
+// that an event appended while a batch is in flight lands in a new queue
+// instead of being overwritten. A batch the endpoint rejects (4xx) is
+// dropped, not kept: it would be rejected again, and at the head of the
+// queue it would hold back every event behind it.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/atomicfile"
+)
+
+// Event is one anonymous usage record.
+type Event struct {
+	Name  string            `json:"name"`
+	Topic string            `json:"topic,omitempty"`
+	Time  time.Time         `json:"time"`
+	Props map[string]string `json:"props,omitempty"`
+}
+
+// allowedProps is the ONLY data we are willing to keep.
+// An allow-list is safer than a deny-list: new fields are dropped by default.
+var allowedProps = map[string]bool{
+	"go_version":  true,
+	"os":          true,
+	"arch":        true,
+	"duration_ms": true,
+	"result":      true,
+}
+
+// Redact returns a copy of e that is safe to store.
+func Redact(e Event) Event {
+	out := Event{
+		Name:  e.Name,
+		Topic: e.Topic,
+		// Round to the hour: exact timestamps can fingerprint a user.
+		Time: e.Time.UTC().Truncate(time.Hour),
+	}
+	for k, v := range e.Props {
+		if !allowedProps[k] || looksSensitive(v) {
+			continue
+		}
+		if out.Props == nil {
+			out.Props = make(map[string]string)
+		}
+		out.Props[k] = v
+	}
+	return out
+}
+
+// looksSensitive catches values that slipped into an allowed field by mistake.
+func looksSensitive(v string) bool {
+	if strings.ContainsAny(v, `/\@`) {
+		return true
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && strings.Contains(v, home) {
+		return true
+	}
+	return len(v) > 64
+}
+
+// ErrFlushBusy is returned by Flush while another process is flushing
+// the same directory.
+var ErrFlushBusy = errors.New("telemetry: another flush is in progress")
+
+// ErrRejected is wrapped by Flush's error when the endpoint rejected a
+// batch with a 4xx status. The batch was dropped; the rest were sent.
+var ErrRejected = errors.New("telemetry: endpoint rejected a batch, which was dropped")
+
+// Telemetry owns a directory holding the consent marker and the queue.
+type Telemetry struct {
+	Dir        string
+	Endpoint   string
+	Client     *http.Client
+	BatchSize  int
+	MaxRetries int
+	BaseDelay  time.Duration
+
+	// OnRetry, if set, is called before each retry of a batch.
+	OnRetry func(attempt int, delay time.Duration, err error)
+
+	mu sync.Mutex // Serializes flushes within this process
+}
+
+// New returns a Telemetry for dir that flushes to endpoint.
+func New(dir, endpoint string) *Telemetry {
+	return &Telemetry{
+		Dir:        dir,
+		Endpoint:   endpoint,
+		Client:     &http.Client{Timeout: 5 * time.Second},
+		BatchSize:  50,
+		MaxRetries: 3,
+		BaseDelay:  200 * time.Millisecond,
+	}
+}
+
+// QueuePath is the file Record appends to.
+func (t *Telemetry) QueuePath() string { return filepath.Join(t.Dir, "queue.jsonl") }
+
+func (t *Telemetry) consentPath() string { return filepath.Join(t.Dir, "enabled") }
+func (t *Telemetry) sendingPath() string { return filepath.Join(t.Dir, "sending.jsonl") }
+func (t *Telemetry) lockPath() string    { return filepath.Join(t.Dir, "flush.lock") }
+
+// Enabled reports whether the user opted in. No marker file = no consent.
+func (t *Telemetry) Enabled() bool {
+	_, err := os.Stat(t.consentPath())
+	return err == nil
+}
+
+// SetEnabled records consent. Turning telemetry off also deletes the queue,
+// so no collected data is left behind.
+func (t *Telemetry) SetEnabled(on bool) error {
+	if on {
+		if err := os.MkdirAll(t.Dir, 0o700); err != nil {
+			return err
+		}
+		return os.WriteFile(t.consentPath(), []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0o600)
+	}
+	for _, p := range []string{t.consentPath(), t.QueuePath(), t.sendingPath()} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record appends a redacted event to the queue. It is a no-op without consent.
+func (t *Telemetry) Record(e Event) error {
+	if !t.Enabled() {
+		return nil
+	}
+	line, err := json.Marshal(Redact(e))
+	if err != nil {
+		return err
+	}
+	// O_APPEND: every write lands at the end, even if another process
+	// appends too, and a single small write is never interleaved.
+	f, err := os.OpenFile(t.QueuePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Pending reads every queued event, the ones a failed flush left first.
+// Corrupt lines (e.g. a torn write) are skipped.
+func (t *Telemetry) Pending() ([]Event, error) {
+	var all []Event
+	for _, p := range []string{t.sendingPath(), t.QueuePath()} {
+		events, err := readEvents(p)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, events...)
+	}
+	return all, nil
+}
+
+func readEvents(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Flush sends the queue in batches and returns how many events were delivered.
+// Events that could not be delivered stay behind for the next flush, except
+// for batches the endpoint rejected: those are dropped, the rest are still
+// sent, and the error wraps ErrRejected. While another process is flushing,
+// Flush returns ErrFlushBusy and sends nothing.
+func (t *Telemetry) Flush(ctx context.Context) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	unlock, err := t.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	// Two passes: what a failed flush left in the sending file, then the
+	// queue. A pass that fails leaves its rest for the next flush.
+	sent := 0
+	var rejected error
+	for range 2 {
+		n, rej, err := t.flushOnce(ctx)
+		sent += n
+		rejected = errors.Join(rejected, rej)
+		if err != nil {
+			return sent, errors.Join(rejected, err)
+		}
+		if n == 0 && rej == nil {
+			break // Nothing was queued
+		}
+	}
+	return sent, rejected
+}
+
+// flushOnce sends the sending file, first renaming the queue to it if
+// there is none. From the rename on, Record appends to a new queue, so
+// nothing recorded meanwhile is overwritten when the rest is saved.
+// rejected is the batches the endpoint refused, which are not kept.
+func (t *Telemetry) flushOnce(ctx context.Context) (sent int, rejected, err error) {
+	if _, err := os.Stat(t.sendingPath()); errors.Is(err, fs.ErrNotExist) {
+		if err := os.Rename(t.QueuePath(), t.sendingPath()); errors.Is(err, fs.ErrNotExist) {
+			return 0, nil, nil
+		} else if err != nil {
+			return 0, nil, err
+		}
+	}
+	events, err := readEvents(t.sendingPath())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	next := 0
+	var sendErr error
+	for next < len(events) {
+		end := min(next+t.BatchSize, len(events))
+		err := t.postWithRetry(ctx, events[next:end])
+		if err != nil && !errors.Is(err, ErrRejected) {
+			sendErr = err
+			break
+		}
+		if err != nil {
+			rejected = errors.Join(rejected, err)
+		} else {
+			sent += end - next
+		}
+		next = end
+	}
+
+	if err := t.saveRest(events[next:]); err != nil {
+		return sent, rejected, err
+	}
+	return sent, rejected, sendErr
+}
+
+// saveRest replaces the sending file atomically (pkg/atomicfile: temp
+// file, Sync, rename), or removes it once everything was delivered. Only the holder
+// of the flush lock writes it.
+func (t *Telemetry) saveRest(rest []Event) error {
+	if len(rest) == 0 {
+		err := os.Remove(t.sendingPath())
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range rest {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return atomicfile.WriteFile(t.sendingPath(), buf.Bytes(), 0o600)
+}
+
+// postWithRetry retries server errors with exponential backoff: 200ms, 400ms, 800ms...
+// Client errors (4xx) are NOT retried; sending the same bad batch again won't help.
+func (t *Telemetry) postWithRetry(ctx context.Context, batch []Event) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= t.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := t.BaseDelay << (attempt - 1)
+			if t.OnRetry != nil {
+				t.OnRetry(attempt, delay, lastErr)
+			}
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.Client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode < 300:
+			return nil
+		case resp.StatusCode < 500:
+			return fmt.Errorf("%w: %s", ErrRejected, resp.Status)
+		default:
+			lastErr = fmt.Errorf("server error: %s", resp.Status)
+		}
+	}
+	return fmt.Errorf("giving up after %d retries: %w", t.MaxRetries, lastErr)
+}
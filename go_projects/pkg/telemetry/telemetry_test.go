@@ -0,0 +1,274 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRedact(t *testing.T) {
+	t.Setenv("HOME", "/home/learner")
+	when := time.Date(2026, 3, 14, 15, 9, 26, 0, time.FixedZone("CET", 3600))
+	for _, tc := range []struct {
+		name  string
+		props map[string]string
+		want  map[string]string
+	}{
+		{"allowed", map[string]string{"os": "linux", "arch": "amd64", "result": "ok"},
+			map[string]string{"os": "linux", "arch": "amd64", "result": "ok"}},
+		{"not allow-listed", map[string]string{"email": "x", "cwd": "work", "os": "linux"},
+			map[string]string{"os": "linux"}},
+		{"unix path", map[string]string{"result": "/home/learner/secret/main.go"}, nil},
+		{"relative path", map[string]string{"result": "secret/main.go"}, nil},
+		{"windows path", map[string]string{"result": `C:\Users\learner`}, nil},
+		{"email in an allowed field", map[string]string{"result": "learner@example.com"}, nil},
+		{"64 bytes", map[string]string{"result": strings.Repeat("x", 64)},
+			map[string]string{"result": strings.Repeat("x", 64)}},
+		{"65 bytes", map[string]string{"result": strings.Repeat("x", 65)}, nil},
+		{"no props", nil, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			in := Event{Name: "lesson_run", Topic: "73", Time: when, Props: tc.props}
+			before := make(map[string]string)
+			for k, v := range tc.props {
+				before[k] = v
+			}
+			got := Redact(in)
+			if !reflect.DeepEqual(got.Props, tc.want) {
+				t.Errorf("Props = %v, want %v", got.Props, tc.want)
+			}
+			if want := time.Date(2026, 3, 14, 14, 0, 0, 0, time.UTC); !got.Time.Equal(want) || got.Time.Location() != time.UTC {
+				t.Errorf("Time = %v, want %v", got.Time, want)
+			}
+			if got.Name != in.Name || got.Topic != in.Topic {
+				t.Errorf("Name, Topic = %q, %q, want %q, %q", got.Name, got.Topic, in.Name, in.Topic)
+			}
+			if len(tc.props) > 0 && !reflect.DeepEqual(tc.props, before) {
+				t.Errorf("Redact changed its argument's Props to %v", tc.props)
+			}
+		})
+	}
+}
+
+// TestRedactHome covers a home directory the path rules alone would miss.
+func TestRedactHome(t *testing.T) {
+	t.Setenv("HOME", "learnerhome")
+	got := Redact(Event{Props: map[string]string{"result": "in learnerhome", "os": "linux"}})
+	if want := map[string]string{"os": "linux"}; !reflect.DeepEqual(got.Props, want) {
+		t.Errorf("Props = %v, want %v", got.Props, want)
+	}
+}
+
+func TestRecordWritesOnlyRedacted(t *testing.T) {
+	tel := New(t.TempDir(), "")
+	secret := Event{Name: "lesson_run", Time: time.Now(), Props: map[string]string{
+		"result": "/home/learner/secret-project", "email": "learner@example.com", "os": "linux",
+	}}
+	if err := tel.Record(secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(tel.QueuePath()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("queue written without consent: %v", err)
+	}
+
+	if err := tel.SetEnabled(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := tel.Record(secret); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(tel.QueuePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"secret-project", "/home/", "@", "email"} {
+		if strings.Contains(string(raw), s) {
+			t.Errorf("queue contains %q: %s", s, raw)
+		}
+	}
+	if !strings.Contains(string(raw), `"os":"linux"`) {
+		t.Errorf("queue lost the allowed prop: %s", raw)
+	}
+}
+
+// collector is a fake endpoint; fail decides, per request, what to answer.
+type collector struct {
+	mu     sync.Mutex
+	topics []string
+	fail   func(call int) int
+	calls  int
+	during func() // Runs while a request is in flight
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.during != nil {
+		c.during()
+	}
+	if c.fail != nil {
+		if code := c.fail(c.calls); code != 0 {
+			w.WriteHeader(code)
+			return
+		}
+	}
+	var batch []Event
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	for _, e := range batch {
+		c.topics = append(c.topics, e.Topic)
+	}
+}
+
+func setup(t *testing.T, c *collector, topics ...string) *Telemetry {
+	t.Helper()
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	tel := New(t.TempDir(), srv.URL)
+	tel.BatchSize = 2
+	tel.BaseDelay = time.Millisecond
+	if err := tel.SetEnabled(true); err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range topics {
+		if err := tel.Record(Event{Name: "lesson_run", Topic: topic}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tel
+}
+
+func pendingTopics(t *testing.T, tel *Telemetry) []string {
+	t.Helper()
+	events, err := tel.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var topics []string
+	for _, e := range events {
+		topics = append(topics, e.Topic)
+	}
+	return topics
+}
+
+func TestFlushKeepsEventsRecordedMeanwhile(t *testing.T) {
+	c := &collector{fail: func(call int) int {
+		if call == 2 {
+			return http.StatusBadRequest // Second batch rejected: dropped
+		}
+		return 0
+	}}
+	tel := setup(t, c, "1", "2", "3", "4")
+	// Another process, with its own Telemetry, records while the first
+	// two batches are sent.
+	other := New(tel.Dir, "")
+	c.during = func() {
+		if c.calls > 2 {
+			return
+		}
+		if err := other.Record(Event{Name: "lesson_run", Topic: "late"}); err != nil {
+			t.Error(err)
+		}
+	}
+
+	sent, err := tel.Flush(context.Background())
+	if sent != 4 || !errors.Is(err, ErrRejected) {
+		t.Fatalf("Flush = %d, %v; want 4 and ErrRejected", sent, err)
+	}
+	c.mu.Lock()
+	if want := []string{"1", "2", "late", "late"}; !reflect.DeepEqual(c.topics, want) {
+		t.Errorf("server got %v, want %v: the events after the rejected batch", c.topics, want)
+	}
+	c.mu.Unlock()
+	if got := pendingTopics(t, tel); len(got) != 0 {
+		t.Errorf("pending = %v; a rejected batch must not stay queued", got)
+	}
+
+	// Nothing is stuck: the next events go out, and nothing else.
+	if err := tel.Record(Event{Name: "lesson_run", Topic: "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := tel.Flush(context.Background()); sent != 1 || err != nil {
+		t.Fatalf("second Flush = %d, %v; want 1, nil", sent, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if want := []string{"1", "2", "late", "late", "5"}; !reflect.DeepEqual(c.topics, want) {
+		t.Errorf("server got %v, want %v", c.topics, want)
+	}
+}
+
+func TestFlushRetriesServerErrors(t *testing.T) {
+	c := &collector{fail: func(call int) int {
+		if call <= 2 {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}}
+	tel := setup(t, c, "1", "2", "3")
+	var retries []int
+	tel.OnRetry = func(attempt int, _ time.Duration, _ error) { retries = append(retries, attempt) }
+	if sent, err := tel.Flush(context.Background()); sent != 3 || err != nil {
+		t.Fatalf("Flush = %d, %v; want 3, nil", sent, err)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(retries, want) {
+		t.Errorf("retries = %v, want %v", retries, want)
+	}
+}
+
+func TestFlushLock(t *testing.T) {
+	c := &collector{}
+	tel := setup(t, c, "1")
+	unlock, err := New(tel.Dir, "").lock() // Another process is flushing
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := tel.Flush(context.Background()); sent != 0 || !errors.Is(err, ErrFlushBusy) {
+		t.Errorf("Flush while locked = %d, %v; want 0, ErrFlushBusy", sent, err)
+	}
+	unlock()
+
+	// The lock file stays; it is the lock on it that counts, and that went
+	// with its holder.
+	if _, err := os.Stat(tel.lockPath()); err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := tel.Flush(context.Background()); sent != 1 || err != nil {
+		t.Errorf("Flush after the holder let go = %d, %v; want 1, nil", sent, err)
+	}
+}
+
+func TestOffDeletesEverything(t *testing.T) {
+	c := &collector{fail: func(int) int { return http.StatusServiceUnavailable }}
+	tel := setup(t, c, "1", "2", "3")
+	tel.MaxRetries = 0
+	if _, err := tel.Flush(context.Background()); err == nil {
+		t.Fatal("Flush to a failing endpoint succeeded")
+	}
+	if _, err := os.Stat(tel.sendingPath()); err != nil {
+		t.Fatalf("no sending file left: %v", err)
+	}
+	if err := tel.Record(Event{Name: "lesson_run", Topic: "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tel.SetEnabled(false); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingTopics(t, tel); len(got) != 0 {
+		t.Errorf("pending after off = %v", got)
+	}
+	if tel.Enabled() {
+		t.Error("still enabled after off")
+	}
+}
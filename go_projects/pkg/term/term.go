@@ -0,0 +1,174 @@
+// Package term reads a terminal one key press at a time, for the
+// interactive parts of the course: 160's REPL line editor and gotut's
+// typing drill (gotut type). A terminal normally hands a program whole
+// lines, echoed and editable; in raw mode every key arrives as it is
+// typed and the program draws what it likes:
+//
+//	restore, err := term.MakeRaw(int(os.Stdin.Fd()))
+//	if err != nil {
+//		// A pipe or a file: read lines instead
+//	}
+//	defer restore()
+//	keys := term.NewReader(os.Stdin)
+//	for {
+//		k, err := keys.ReadKey()
+//		if err != nil || k == term.KeyInterrupt {
+//			break
+//		}
+//		if k.IsRune() {
+//			fmt.Print(string(rune(k)))
+//		}
+//	}
+//
+// Raw mode turns off the terminal's signals too: Ctrl-C arrives as
+// KeyInterrupt, not SIGINT, and a program that ignores it can't be
+// interrupted. It also outlives the program, so restore must run on
+// every way out — a defer, and a recover if the program may panic.
+//
+// MakeRaw uses termios via ioctl (term_unix.go), the core of
+// golang.org/x/term; elsewhere it returns errors.ErrUnsupported, and the
+// caller falls back to reading lines. The key decoding works on any
+// io.Reader, so it is tested without a terminal.
+package term
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Key is one key press: a character, or one of the keys below. Those are
+// negative, so no character is mistaken for one.
+type Key rune
+
+const (
+	KeyUnknown Key = -(iota + 1) // An escape sequence this package doesn't decode
+	KeyEnter                     // \r from a terminal, \n from a pipe
+	KeyTab
+	KeyBackspace // DEL on most terminals, ^H on some
+	KeyDelete
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyInterrupt // Ctrl-C
+	KeyEOF       // Ctrl-D
+)
+
+var keyNames = map[Key]string{
+	KeyUnknown: "Unknown", KeyEnter: "Enter", KeyTab: "Tab", KeyBackspace: "Backspace",
+	KeyDelete: "Delete", KeyEscape: "Esc", KeyUp: "Up", KeyDown: "Down", KeyRight: "Right",
+	KeyLeft: "Left", KeyHome: "Home", KeyEnd: "End", KeyInterrupt: "Ctrl-C", KeyEOF: "Ctrl-D",
+}
+
+// IsRune reports whether k is a character: a printable one, or a control
+// character without a Key of its own (Ctrl-A is 1).
+func (k Key) IsRune() bool { return k >= 0 }
+
+func (k Key) String() string {
+	if k.IsRune() {
+		return fmt.Sprintf("%q", rune(k))
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key(%d)", int(k))
+}
+
+// Reader decodes key presses from what a terminal sends.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader reads keys from r, usually a terminal in raw mode.
+func NewReader(r io.Reader) *Reader { return &Reader{r: bufio.NewReader(r)} }
+
+// ReadKey waits for the next key press. Arrows and the like come as
+// escape sequences, ESC [ A for ↑; a terminal writes each sequence in
+// one go, so an ESC with nothing behind it is the Esc key itself.
+func (r *Reader) ReadKey() (Key, error) {
+	c, size, err := r.r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	switch c {
+	case '\r':
+		// \r\n, pasted or from a file, is one Enter. Only what has arrived
+		// is looked at: Peek would wait for the next key.
+		if r.r.Buffered() > 0 {
+			if b, _ := r.r.Peek(1); b[0] == '\n' {
+				r.r.ReadByte()
+			}
+		}
+		return KeyEnter, nil
+	case '\n':
+		return KeyEnter, nil
+	case '\t':
+		return KeyTab, nil
+	case 127, 8:
+		return KeyBackspace, nil
+	case 3:
+		return KeyInterrupt, nil
+	case 4:
+		return KeyEOF, nil
+	case 27:
+		if r.r.Buffered() == 0 {
+			return KeyEscape, nil
+		}
+		return r.escape()
+	}
+	if c == utf8.RuneError && size == 1 {
+		return KeyUnknown, nil // Not UTF-8
+	}
+	return Key(c), nil
+}
+
+// escape decodes the rest of a sequence after ESC: "[" or "O", parameter
+// bytes such as "3" or "1;5", and one final byte that names the key.
+// Anything else after ESC — Alt-x sends ESC x — leaves x to be read as
+// a key of its own.
+func (r *Reader) escape() (Key, error) {
+	if b, _ := r.r.Peek(1); len(b) == 0 || b[0] != '[' && b[0] != 'O' {
+		return KeyEscape, nil
+	}
+	r.r.ReadByte()
+	var params []byte
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return KeyUnknown, nil // Cut short at the end of the input
+		}
+		if b >= 0x30 && b <= 0x3f {
+			params = append(params, b)
+			continue
+		}
+		switch b {
+		case 'A':
+			return KeyUp, nil
+		case 'B':
+			return KeyDown, nil
+		case 'C':
+			return KeyRight, nil
+		case 'D':
+			return KeyLeft, nil
+		case 'H':
+			return KeyHome, nil
+		case 'F':
+			return KeyEnd, nil
+		case '~': // VT220 style: ESC [ 3 ~
+			switch string(params) {
+			case "1", "7":
+				return KeyHome, nil
+			case "4", "8":
+				return KeyEnd, nil
+			case "3":
+				return KeyDelete, nil
+			}
+		}
+		return KeyUnknown, nil
+	}
+}
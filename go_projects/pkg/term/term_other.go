@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package term
+
+import "errors"
+
+// Without termios there is no raw mode: callers fall back to reading
+// whole lines (no Tab completion or arrow keys while typing).
+func MakeRaw(fd int) (restore func(), err error) { return nil, errors.ErrUnsupported }
+
+// IsTerminal can't tell here, so it says no, as MakeRaw does.
+func IsTerminal(fd int) bool { return false }
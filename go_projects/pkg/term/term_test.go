@@ -0,0 +1,89 @@
+package term
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func readAll(t *testing.T, r io.Reader) []Key {
+	t.Helper()
+	keys := NewReader(r)
+	var out []Key
+	for {
+		k, err := keys.ReadKey()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, k)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want []Key
+	}{
+		{"go", []Key{'g', 'o'}},
+		{"héllo, 世界", []Key{'h', 'é', 'l', 'l', 'o', ',', ' ', '世', '界'}},
+		{"a\rb\nc\r\nd", []Key{'a', KeyEnter, 'b', KeyEnter, 'c', KeyEnter, 'd'}},
+		{"\r\r", []Key{KeyEnter, KeyEnter}},
+		{"\t\x7f\x08\x03\x04\x01", []Key{KeyTab, KeyBackspace, KeyBackspace, KeyInterrupt, KeyEOF, 1}},
+		{"\x1b[A\x1b[B\x1b[C\x1b[D", []Key{KeyUp, KeyDown, KeyRight, KeyLeft}},
+		{"\x1bOA\x1bOH\x1bOF", []Key{KeyUp, KeyHome, KeyEnd}},                        // Application mode
+		{"\x1b[3~\x1b[1~\x1b[4~\x1b[7~", []Key{KeyDelete, KeyHome, KeyEnd, KeyHome}}, // VT220
+		{"\x1b[1;5C", []Key{KeyRight}},                                               // Ctrl-→: the modifier is dropped
+		{"\x1b[15~x", []Key{KeyUnknown, 'x'}},                                        // F5
+		{"\x1b", []Key{KeyEscape}},
+		{"\x1bx", []Key{KeyEscape, 'x'}}, // Alt-x
+		{"\x1b[", []Key{KeyUnknown}},
+		{"a\xffb", []Key{'a', KeyUnknown, 'b'}},
+	} {
+		if got := readAll(t, strings.NewReader(tt.in)); !slices.Equal(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestOneByteAtATime reads as a slow terminal might deliver: an ESC is
+// alone in the buffer, so it is the Esc key, and the rest of what would
+// have been a sequence arrives as characters.
+func TestOneByteAtATime(t *testing.T) {
+	got := readAll(t, iotest.OneByteReader(strings.NewReader("a\x1b[A\r\n")))
+	want := []Key{'a', KeyEscape, '[', 'A', KeyEnter, KeyEnter}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestString(t *testing.T) {
+	for k, want := range map[Key]string{'x': "'x'", '\x01': `'\x01'`, KeyUp: "Up", KeyInterrupt: "Ctrl-C", -100: "Key(-100)"} {
+		if got := k.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(k), got, want)
+		}
+	}
+}
+
+func TestNotATerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if IsTerminal(int(f.Fd())) {
+		t.Error("IsTerminal(a file) = true")
+	}
+	if restore, err := MakeRaw(int(f.Fd())); err == nil {
+		restore()
+		t.Error("MakeRaw(a file) succeeded")
+	} else if errors.Is(err, errors.ErrUnsupported) {
+		t.Log("no raw mode on this OS")
+	}
+}
@@ -0,0 +1,44 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// MakeRaw switches the terminal on fd to raw mode: no echo, no line
+// buffering, no signals from Ctrl-C or Ctrl-Z. Every key press arrives as
+// it is typed. Output processing (OPOST) stays on, so "\n" still moves to
+// the start of the next line. restore puts the old mode back.
+func MakeRaw(fd int) (restore func(), err error) {
+	var old syscall.Termios
+	if err := ioctlTermios(fd, ioctlGetTermios, &old); err != nil {
+		return nil, err // Not a terminal (a pipe or a file)
+	}
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1 // read() returns after every byte
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() { ioctlTermios(fd, ioctlSetTermios, &old) }, nil
+}
+
+// IsTerminal reports whether fd is a terminal: whether it has termios
+// settings to read.
+func IsTerminal(fd int) bool {
+	var t syscall.Termios
+	return ioctlTermios(fd, ioctlGetTermios, &t) == nil
+}
+
+func ioctlTermios(fd int, req uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package textwrap reflows prose to a terminal's width (Topic 167):
+//
+//	fmt.Println(textwrap.Wrap(text, 60))
+//	fmt.Println(textwrap.WrapIndent(item, 60, "- ", "  ")) // A hanging indent
+//
+// The algorithm is greedy — words go on a line until the next one would
+// not fit — which is what terminals, editors and fmt(1) do. Existing
+// line breaks count as spaces. A word is what lies between spaces, tabs
+// and newlines, so a no-break space (U+00A0) glues "10 MB" together, and
+// a `code span` is one word even with spaces in it. A word longer than
+// the line gets a line of its own rather than being cut in half.
+//
+// Widths are in columns: ANSI colour codes and combining marks take
+// none. East Asian wide characters need the Unicode width tables, which
+// the standard library doesn't ship, so they count as one.
+package textwrap
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ansi matches the escape sequences a terminal uses for colour and
+// style (CSI ... letter). They are printed but take no columns.
+var ansi = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// Width is the number of columns s occupies: runes, minus ANSI escapes
+// and combining marks (the accent in "é" written as e + U+0301).
+func Width(s string) int {
+	n := 0
+	for _, r := range ansi.ReplaceAllString(s, "") {
+		if !unicode.Is(unicode.Mn, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// isBreak reports whether r may separate words. unicode.IsSpace would
+// also accept U+00A0, whose whole job is to not be a break.
+func isBreak(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '\r' }
+
+// Words splits text where Wrap may break it. An unclosed backtick runs
+// to the end of the text: the span is still one word.
+func Words(text string) []string {
+	var out []string
+	var word strings.Builder
+	inCode := false
+	for _, r := range text {
+		switch {
+		case r == '`':
+			inCode = !inCode
+			word.WriteRune(r)
+		case isBreak(r) && inCode:
+			if r == '\n' || r == '\t' {
+				r = ' ' // A span that was wrapped in the source
+			}
+			word.WriteRune(r)
+		case isBreak(r):
+			if word.Len() > 0 {
+				out = append(out, word.String())
+				word.Reset()
+			}
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if word.Len() > 0 {
+		out = append(out, word.String())
+	}
+	return out
+}
+
+// Wrap reflows text into lines of at most width columns, joined by
+// newlines, with no trailing newline. width < 1 means don't wrap.
+func Wrap(text string, width int) string {
+	return WrapIndent(text, width, "", "")
+}
+
+// WrapIndent is Wrap with a prefix for the first line and another for
+// the rest. A hanging indent is first = "- ", rest = "  ": the marker
+// sticks out and the text lines up. Prefixes count toward width.
+func WrapIndent(text string, width int, first, rest string) string {
+	var b strings.Builder
+	prefix, line := first, 0 // line: columns used after the prefix
+	b.WriteString(prefix)
+	for _, w := range Words(text) {
+		ww := Width(w)
+		switch {
+		case line == 0:
+			// The first word always goes on the line, even if it is
+			// too long: there is nowhere better for it.
+		case width < 1 || Width(prefix)+line+1+ww <= width:
+			b.WriteByte(' ')
+			line++
+		default:
+			prefix = rest
+			b.WriteString("\n" + prefix)
+			line = 0
+		}
+		b.WriteString(w)
+		line += ww
+	}
+	return strings.TrimRight(b.String(), " ") // A bare prefix on empty text
+}
@@ -0,0 +1,51 @@
+package textwrap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	for _, tt := range []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"the quick brown fox jumps over the lazy dog", 15, "the quick brown\nfox jumps over\nthe lazy dog"},
+		{"line breaks\nin the source\ncount as spaces", 80, "line breaks in the source count as spaces"},
+		{"no width means no wrapping at all", 0, "no width means no wrapping at all"},
+		{"see https://pkg.go.dev/path/filepath#WalkDir now", 20, "see\nhttps://pkg.go.dev/path/filepath#WalkDir\nnow"},
+		{"run `go test -run X` here", 12, "run\n`go test -run X`\nhere"},
+		{"a size of 10 MB stays", 10, "a size of\n10 MB\nstays"},
+		{"\x1b[1mbold\x1b[0m text fits", 14, "\x1b[1mbold\x1b[0m text fits"},
+		{"", 10, ""},
+	} {
+		if got := Wrap(tt.text, tt.width); got != tt.want {
+			t.Errorf("Wrap(%q, %d) =\n%s\nwant\n%s", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWrapIndent(t *testing.T) {
+	got := WrapIndent("return SkipDir from the callback to skip a directory", 24, "- ", "  ")
+	want := "- return SkipDir from\n  the callback to skip a\n  directory"
+	if got != want {
+		t.Errorf("WrapIndent =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	for s, want := range map[string]int{"abc": 3, "é": 1, "\x1b[31mred\x1b[0m": 3, "日本": 2} {
+		if got := Width(s); got != want {
+			t.Errorf("Width(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("a `b\nc` d\te `unclosed f")
+	want := []string{"a", "`b c`", "d", "e", "`unclosed f"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+// Package tmplfuncs is a FuncMap of the helpers most text templates end
+// up wanting (intermediate Topic 72, Part 6):
+//
+//	t := template.Must(template.New("receipt").Funcs(tmplfuncs.Default()).Parse(
+//		`{{.Name | title}}: {{.Count}} {{pluralize .Count "item"}}, {{currency .Total}}`))
+//
+// Functions that take a setting and a value take the value last, so it
+// can come down a pipeline: {{.Body | truncate 20}}, {{.When | date "2 Jan"}}.
+package tmplfuncs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Default returns a new FuncMap each call, so a caller can add to or
+// override it without changing anyone else's:
+//
+//	upper     {{upper "go"}}                    GO
+//	lower     {{lower "GO"}}                    go
+//	title     {{title "ada lovelace"}}          Ada Lovelace
+//	trim      {{trim "  x  "}}                  x
+//	repeat    {{repeat 3 "ab"}}                 ababab
+//	date      {{date "2006-01-02" .When}}       2026-10-16
+//	currency  {{currency 1234.5}}               $1,234.50
+//	pluralize {{pluralize 2 "file"}}            files   ({{pluralize 2 "child" "children"}})
+//	truncate  {{truncate 9 "hello, world"}}     hello, w…
+func Default() template.FuncMap {
+	return template.FuncMap{
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"title":     Title,
+		"trim":      strings.TrimSpace,
+		"repeat":    Repeat,
+		"date":      Date,
+		"currency":  Currency,
+		"pluralize": Pluralize,
+		"truncate":  Truncate,
+	}
+}
+
+// Title upper-cases the first letter of each space-separated word and
+// leaves the rest alone: "o'brien mcdonald" is "O'brien Mcdonald", and
+// "iPhone" becomes "IPhone". Names that need more use golang.org/x/text/cases.
+func Title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		out := r
+		if unicode.IsSpace(prev) {
+			out = unicode.ToTitle(r)
+		}
+		prev = r
+		return out
+	}, s)
+}
+
+// Repeat is strings.Repeat with the count first, for pipelines, and
+// without the panic: a negative count is "".
+func Repeat(count int, s string) string {
+	return strings.Repeat(s, max(count, 0))
+}
+
+// Date formats t with a Go layout, or one of the time package's names
+// for them: "RFC3339", "DateOnly", "Kitchen"... The zero time is "", so
+// a missing date prints as nothing rather than as year 1.
+func Date(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if named, ok := layouts[layout]; ok {
+		layout = named
+	}
+	return t.Format(layout)
+}
+
+var layouts = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"DateTime": time.DateTime,
+	"DateOnly": time.DateOnly,
+	"TimeOnly": time.TimeOnly,
+	"Kitchen":  time.Kitchen,
+}
+
+// Currency formats an amount of dollars rounded to cents, with
+// thousands separators: 1234.5 is "$1,234.50", -3 is "-$3.00". It takes
+// any integer or float type, since a template's numbers are whatever the
+// data's fields are; anything else is an error, which stops the template.
+func Currency(amount any) (string, error) {
+	var v float64
+	switch a := amount.(type) {
+	case float64:
+		v = a
+	case float32:
+		v = float64(a)
+	default:
+		n, err := toInt64(amount)
+		if err != nil {
+			return "", fmt.Errorf("currency: %w", err)
+		}
+		v = float64(n)
+	}
+	cents := int64(math.Round(math.Abs(v) * 100))
+	sign := ""
+	if v < 0 && cents > 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, group(strconv.FormatInt(cents/100, 10)), cents%100), nil
+}
+
+// group puts a comma every three digits from the right.
+func group(digits string) string {
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// Pluralize returns singular when n is 1 and the plural otherwise: the
+// one given, or singular with an "s". n may be any integer type. The
+// count itself is the template's to print: {{.N}} {{pluralize .N "file"}}.
+func Pluralize(n any, singular string, plural ...string) (string, error) {
+	count, err := toInt64(n)
+	if err != nil {
+		return "", fmt.Errorf("pluralize: %w", err)
+	}
+	if count == 1 || count == -1 {
+		return singular, nil
+	}
+	if len(plural) > 0 {
+		return plural[0], nil
+	}
+	return singular + "s", nil
+}
+
+// toInt64 converts any of Go's integer types, named ones too (type
+// Cents int64): a template's count is whatever type the data's field
+// has, and text/template passes it as it is.
+func toInt64(v any) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return rv.Int(), nil
+	case rv.CanUint() && rv.Uint() <= math.MaxInt64:
+		return int64(rv.Uint()), nil
+	case rv.CanUint():
+		return 0, fmt.Errorf("%v is out of range", v)
+	case rv.CanFloat():
+		return 0, fmt.Errorf("%v is a %T, not an integer", v, v)
+	}
+	return 0, fmt.Errorf("%v is a %T, not a number", v, v)
+}
+
+// Truncate shortens s to at most n characters, counting runes, not
+// bytes, and ends a shortened string with "…" (which counts as one).
+func Truncate(n int, s string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return strings.TrimRightFunc(string(runes[:n-1]), unicode.IsSpace) + "…"
+}
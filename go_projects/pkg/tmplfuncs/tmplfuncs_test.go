@@ -0,0 +1,87 @@
+package tmplfuncs
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func render(t *testing.T, text string, data any) (string, error) {
+	t.Helper()
+	tmpl, err := template.New("t").Funcs(Default()).Parse(text)
+	if err != nil {
+		t.Fatalf("%s: %v", text, err)
+	}
+	var b strings.Builder
+	err = tmpl.Execute(&b, data)
+	return b.String(), err
+}
+
+func TestFuncs(t *testing.T) {
+	when := time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC)
+	for _, tc := range []struct{ text, want string }{
+		{`{{upper "go"}} {{lower "GO"}} [{{trim "  x \n"}}]`, "GO go [x]"},
+		{`{{title "ada lovelace"}}|{{title "o'brien  mcdonald"}}|{{title ""}}`, "Ada Lovelace|O'brien  Mcdonald|"},
+		{`{{repeat 3 "ab"}}|{{"-" | repeat 5}}|{{repeat -1 "x"}}`, "ababab|-----|"},
+		{`{{date "2006-01-02 15:04" .When}}|{{.When | date "DateOnly"}}|{{date "Kitchen" .When}}`, "2026-10-16 09:05|2026-10-16|9:05AM"},
+		{`[{{date "DateOnly" .Never}}]`, "[]"},
+		{`{{currency 0}} {{currency 1234.5}} {{currency 1234567}} {{currency -3}} {{currency 0.005}} {{currency -0.001}}`,
+			"$0.00 $1,234.50 $1,234,567.00 -$3.00 $0.01 $0.00"},
+		{`{{currency .Cents}} {{currency .Price}}`, "$999.00 $19.99"},
+		{`{{range .Counts}}{{.}} {{pluralize . "file"}}, {{end}}`, "0 files, 1 file, 2 files, "},
+		{`{{pluralize 1 "child" "children"}} {{pluralize 3 "child" "children"}}`, "child children"},
+		{`{{truncate 9 "hello, world"}}|{{truncate 12 "hello, world"}}|{{"日本語のテキスト" | truncate 4}}|{{truncate 0 "x"}}`,
+			"hello, w…|hello, world|日本語…|"},
+		{`{{truncate 8 "hello, world"}}`, "hello,…"}, // No space left dangling before the …
+	} {
+		got, err := render(t, tc.text, map[string]any{
+			"When": when, "Never": time.Time{}, "Cents": 999, "Price": float32(19.99), "Counts": []int{0, 1, 2},
+		})
+		if err != nil || got != tc.want {
+			t.Errorf("%s\n got %q, %v\nwant %q", tc.text, got, err, tc.want)
+		}
+	}
+}
+
+// A template's numbers have whatever type the data's fields have.
+func TestIntegerKinds(t *testing.T) {
+	type cents int64
+	for _, n := range []any{1, int8(1), int16(1), int32(1), int64(1), uint(1), uint8(1), uint16(1), uint32(1), uint64(1), uintptr(1), cents(1)} {
+		got, err := render(t, `{{pluralize . "file"}} {{currency .}}`, n)
+		if err != nil || got != "file $1.00" {
+			t.Errorf("%T: got %q, %v", n, got, err)
+		}
+	}
+	for _, n := range []any{int8(-3), int64(2), uint32(0), uint64(1 << 40)} {
+		if got, err := render(t, `{{pluralize . "file"}}`, n); err != nil || got != "files" {
+			t.Errorf("pluralize %T(%v): got %q, %v", n, n, got, err)
+		}
+	}
+	for data, want := range map[any]string{
+		1.0:                    "pluralize: 1 is a float64, not an integer",
+		"1":                    "pluralize: 1 is a string, not a number",
+		uint64(math.MaxUint64): "pluralize: 18446744073709551615 is out of range",
+	} {
+		if _, err := render(t, `{{pluralize . "file"}}`, data); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("pluralize %T(%v): %v, want %q", data, data, err, want)
+		}
+	}
+}
+
+func TestCurrencyError(t *testing.T) {
+	_, err := render(t, `{{currency .}}`, "12.50")
+	if err == nil || !strings.Contains(err.Error(), "string, not a number") {
+		t.Errorf("currency of a string: %v", err)
+	}
+}
+
+// Default returns a fresh map: one caller's override isn't another's.
+func TestDefaultIsACopy(t *testing.T) {
+	a := Default()
+	a["upper"] = strings.ToLower
+	if got, _ := render(t, `{{upper "x"}}`, nil); got != "X" {
+		t.Errorf("a change to one Default() reached another: %q", got)
+	}
+}
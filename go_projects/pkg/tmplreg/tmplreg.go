@@ -0,0 +1,237 @@
+// Package tmplreg keeps parsed text/templates by name, the storage map of
+// intermediate Topic 72's CLI menu made safe to share between goroutines:
+//
+//	reg := tmplreg.New()
+//	if err := reg.Register("welcome", "Welcome, {{.Name}}!\n"); err != nil {
+//		return err // A template that doesn't parse is caught at startup
+//	}
+//	...
+//	err := reg.RenderTo(os.Stdout, choice, data) // errors.Is(err, tmplreg.ErrNotFound) for an unknown choice
+//
+// Templates are parsed once, in Register, and executed as often as
+// needed. Lookups take a read lock, so any number of goroutines render at
+// once; Register takes the write lock, and registering a name again
+// replaces its template for every render that starts afterwards.
+//
+// WatchDir fills a registry from the *.tmpl files of a directory instead,
+// and Run re-parses them when they change, so templates can be edited
+// while the program runs:
+//
+//	w, err := tmplreg.WatchDir("templates") // templates/welcome.tmpl is "welcome"
+//	...
+//	go w.Run(ctx, time.Second, func(err error) { log.Println("templates reloaded:", err) })
+package tmplreg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// ErrNotFound is the kind of error for a name nothing was registered as.
+var ErrNotFound = errors.New("template not found")
+
+// Registry is a set of templates by name. Use New; the zero value has no
+// map to register into.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{templates: map[string]*template.Template{}}
+}
+
+// Register parses text as the template name and stores it, replacing any
+// template already registered as name. A parse error stores nothing.
+func (r *Registry) Register(name, text string) error {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return fmt.Errorf("tmplreg: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[name] = t
+	return nil
+}
+
+// Names returns the registered names, sorted: the choices of a menu.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lookup returns the template name, or an ErrNotFound that lists what
+// there is instead.
+func (r *Registry) lookup(name string) (*template.Template, error) {
+	r.mu.RLock()
+	t, ok := r.templates[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("tmplreg: %q: %w (have %s)", name, ErrNotFound, strings.Join(r.Names(), ", "))
+	}
+	return t, nil
+}
+
+// RenderTo executes the template name with data and writes the result to
+// w. The output is built in memory first, so a template that fails half
+// way writes nothing rather than half a page.
+func (r *Registry) RenderTo(w io.Writer, name string, data any) error {
+	t, err := r.lookup(name)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Errorf("tmplreg: %w", err)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Render is RenderTo into a string.
+func (r *Registry) Render(name string, data any) (string, error) {
+	var b strings.Builder
+	err := r.RenderTo(&b, name, data)
+	return b.String(), err
+}
+
+// MustRender is Render that panics on error, for templates and data the
+// program itself controls, where an error is a bug.
+func (r *Registry) MustRender(name string, data any) string {
+	s, err := r.Render(name, data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Watcher is a Registry that holds the templates of a directory and
+// reloads them when the files change. Its templates come only from the
+// directory: a reload replaces all of them, including any Registered by
+// hand.
+type Watcher struct {
+	*Registry
+	dir  string
+	mu   sync.Mutex // One Check at a time
+	seen string     // The files' fingerprint at the last load, good or bad
+}
+
+// Ext is the extension of the files a Watcher loads. Files without it —
+// an editor's welcome.tmpl~ or .welcome.tmpl.swp — are left alone.
+const Ext = ".tmpl"
+
+// WatchDir loads every *.tmpl file in dir, each as the template named by
+// its file name without the extension. The files are parsed together,
+// with template.ParseGlob, so one can use what another defines:
+// {{template "footer" .}} works in every file once any file has
+// {{define "footer"}}. Polling starts with Run; until then the templates
+// stay as loaded.
+func WatchDir(dir string) (*Watcher, error) {
+	fp, err := fingerprint(dir)
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{Registry: New(), dir: dir, seen: fp}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// load parses the directory and, only if every file parses, swaps the
+// new templates in.
+func (w *Watcher) load() error {
+	set, err := template.ParseGlob(filepath.Join(w.dir, "*"+Ext))
+	if err != nil {
+		return fmt.Errorf("tmplreg: %w", err)
+	}
+	templates := map[string]*template.Template{}
+	files, err := filepath.Glob(filepath.Join(w.dir, "*"+Ext))
+	if err != nil {
+		return fmt.Errorf("tmplreg: %w", err)
+	}
+	for _, f := range files {
+		base := filepath.Base(f)
+		templates[strings.TrimSuffix(base, Ext)] = set.Lookup(base)
+	}
+	w.Registry.mu.Lock()
+	defer w.Registry.mu.Unlock()
+	w.templates = templates
+	return nil
+}
+
+// fingerprint sums up the name, size and modification time of every
+// *.tmpl file in dir; a file edited, added or removed changes it. The
+// only error is a dir that makes a bad pattern, one with an unclosed "[".
+func fingerprint(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return "", fmt.Errorf("tmplreg: %w", err)
+	}
+	var b strings.Builder
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			fmt.Fprintf(&b, "%s %d %d\n", filepath.Base(f), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String(), nil
+}
+
+// Check reloads the templates if the files have changed since the last
+// load. A reload that fails — a file half saved, a typo — keeps the
+// templates from before and returns the error; the same files aren't
+// tried again, so an error is reported once per change.
+func (w *Watcher) Check() (reloaded bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fp, err := fingerprint(w.dir)
+	if err != nil {
+		return false, err
+	}
+	if fp == w.seen {
+		return false, nil
+	}
+	w.seen = fp
+	if err := w.load(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Run calls Check every interval until ctx is done. Polling works on
+// every OS and file system, including the network mounts and containers
+// where change notifications don't arrive; the cost is up to one
+// interval's delay. If report is not nil it is called after each reload
+// attempt, with nil when the new templates are in.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, report func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := w.Check()
+			if (reloaded || err != nil) && report != nil {
+				report(err)
+			}
+		}
+	}
+}
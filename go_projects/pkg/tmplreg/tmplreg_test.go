@@ -0,0 +1,211 @@
+package tmplreg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func menu(t *testing.T) *Registry {
+	t.Helper()
+	r := New()
+	for name, text := range map[string]string{
+		"welcome": "Welcome, {{.Name}}! Your total: ${{.Total}}",
+		"goodbye": "Goodbye {{.Name}}!",
+	} {
+		if err := r.Register(name, text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func TestRender(t *testing.T) {
+	r := menu(t)
+	if got := r.MustRender("welcome", map[string]any{"Name": "Alice", "Total": 42.5}); got != "Welcome, Alice! Your total: $42.5" {
+		t.Errorf("MustRender = %q", got)
+	}
+	var b strings.Builder
+	if err := r.RenderTo(&b, "goodbye", struct{ Name string }{"Bob"}); err != nil || b.String() != "Goodbye Bob!" {
+		t.Errorf("RenderTo = %q, %v", b.String(), err)
+	}
+	if got := r.Names(); !slices.Equal(got, []string{"goodbye", "welcome"}) {
+		t.Errorf("Names() = %v", got)
+	}
+
+	// Registering a name again replaces it.
+	if err := r.Register("goodbye", "Bye, {{.Name}}."); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.MustRender("goodbye", struct{ Name string }{"Bob"}); got != "Bye, Bob." {
+		t.Errorf("after re-Register: %q", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	r := menu(t)
+	_, err := r.Render("refund", nil)
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), `"refund"`) || !strings.Contains(err.Error(), "goodbye, welcome") {
+		t.Errorf("unknown name: %v; want ErrNotFound naming it and what there is", err)
+	}
+
+	if err := r.Register("broken", "{{.Name"); err == nil {
+		t.Error("Register accepted a template that doesn't parse")
+	}
+	if slices.Contains(r.Names(), "broken") {
+		t.Error("a template that failed to parse was registered")
+	}
+
+	// An execution error writes nothing.
+	r.Register("field", "before {{.Missing}} after")
+	var b strings.Builder
+	if err := r.RenderTo(&b, "field", struct{}{}); err == nil || b.Len() != 0 || errors.Is(err, ErrNotFound) {
+		t.Errorf("RenderTo = %q, %v; want an execution error and no output", b.String(), err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRender of an unknown name didn't panic")
+		}
+	}()
+	r.MustRender("refund", nil)
+}
+
+// Run with -race: renders and registrations from many goroutines at once.
+func TestConcurrent(t *testing.T) {
+	r := menu(t)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if _, err := r.Render("welcome", map[string]any{"Name": "A", "Total": 1}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				r.Register(fmt.Sprintf("extra-%d", i), fmt.Sprintf("version %d", j))
+				r.Names()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(r.Names()); got != 10 {
+		t.Errorf("%d templates, want 10", got)
+	}
+}
+
+func TestWatchDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("welcome.tmpl", `Hi {{.}}.{{template "footer"}}`)
+	write("footer.tmpl", `{{define "footer"}} -- the shop{{end}}`)
+	write("welcome.tmpl~", `{{broken`) // An editor's backup: not loaded
+
+	w, err := WatchDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.MustRender("welcome", "Ann"); got != "Hi Ann. -- the shop" {
+		t.Errorf("welcome = %q; files should share their {{define}}s", got)
+	}
+	if got := w.Names(); !slices.Equal(got, []string{"footer", "welcome"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	if reloaded, err := w.Check(); reloaded || err != nil {
+		t.Errorf("Check with nothing changed = %v, %v", reloaded, err)
+	}
+
+	// An edit is picked up; a broken one keeps what was there, and is
+	// reported once.
+	write("welcome.tmpl", `Hello again, {{.}}.`)
+	if reloaded, err := w.Check(); !reloaded || err != nil || w.MustRender("welcome", "Ann") != "Hello again, Ann." {
+		t.Errorf("after an edit: %v, %v, %q", reloaded, err, w.MustRender("welcome", "Ann"))
+	}
+	write("welcome.tmpl", `Hello {{.`)
+	if reloaded, err := w.Check(); reloaded || err == nil {
+		t.Errorf("after a broken edit: %v, %v; want an error", reloaded, err)
+	}
+	if got := w.MustRender("welcome", "Ann"); got != "Hello again, Ann." {
+		t.Errorf("a broken edit replaced the template: %q", got)
+	}
+	if _, err := w.Check(); err != nil {
+		t.Errorf("the same broken file reported twice: %v", err)
+	}
+
+	// Files added and removed come and go as names.
+	write("welcome.tmpl", `Hi {{.}}.`)
+	write("receipt.tmpl", `Paid.`)
+	os.Remove(filepath.Join(dir, "footer.tmpl"))
+	if reloaded, err := w.Check(); !reloaded || err != nil {
+		t.Fatalf("after adding and removing: %v, %v", reloaded, err)
+	}
+	if got := w.Names(); !slices.Equal(got, []string{"receipt", "welcome"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	if _, err := w.Render("footer", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("a removed file still renders: %v", err)
+	}
+
+	if _, err := WatchDir(t.TempDir()); err == nil {
+		t.Error("WatchDir of a directory with no templates: no error")
+	}
+	if _, err := WatchDir(filepath.Join(dir, "[")); !errors.Is(err, filepath.ErrBadPattern) {
+		t.Errorf("WatchDir of a directory that makes a bad pattern: %v", err)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.tmpl"), []byte("one"), 0o644)
+	w, err := WatchDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	reports := make(chan error, 10)
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx, time.Millisecond, func(err error) { reports <- err })
+		close(done)
+	}()
+
+	// Renders go on while Run swaps the templates underneath (go test -race).
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			w.MustRender("a", nil)
+		}
+	}()
+	os.WriteFile(filepath.Join(dir, "a.tmpl"), []byte("two!"), 0o644)
+	select {
+	case err := <-reports:
+		if err != nil || w.MustRender("a", nil) != "two!" {
+			t.Errorf("reload: %v, a = %q", err, w.MustRender("a", nil))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run never picked up the change")
+	}
+	cancel()
+	<-done
+	wg.Wait()
+}
@@ -0,0 +1,66 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportText prints spans as an indented tree, each with its duration, a
+// bar for its share of its root, and its attributes.
+func ExportText(w io.Writer, spans []*Span) {
+	ids := make(map[string]bool, len(spans))
+	for _, s := range spans {
+		ids[s.SpanID] = true
+	}
+	children := make(map[string][]*Span)
+	var roots []*Span
+	for _, s := range spans {
+		if s.ParentID == "" || !ids[s.ParentID] { // A remote or unfinished parent: a root here
+			roots = append(roots, s)
+		} else {
+			children[s.ParentID] = append(children[s.ParentID], s)
+		}
+	}
+
+	var walk func(s *Span, depth int, total time.Duration)
+	walk = func(s *Span, depth int, total time.Duration) {
+		share := 1.0
+		if total > 0 {
+			share = float64(s.Duration()) / float64(total)
+		}
+		bar := strings.Repeat("█", int(share*20+0.5))
+		label := strings.Repeat("  ", depth) + s.Name
+		fmt.Fprintf(w, "  %-32s %10v  %-20s %5.1f%%", label, s.Duration().Round(time.Microsecond), bar, share*100)
+		keys := make([]string, 0, len(s.Attributes))
+		for k := range s.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s=%s", k, s.Attributes[k])
+		}
+		fmt.Fprintln(w)
+		for _, c := range children[s.SpanID] {
+			walk(c, depth+1, total)
+		}
+	}
+	for _, r := range roots {
+		fmt.Fprintf(w, "  trace %s\n", r.TraceID)
+		walk(r, 0, r.Duration())
+	}
+}
+
+// ExportJSON writes one JSON object per span (JSON Lines).
+func ExportJSON(w io.Writer, spans []*Span) error {
+	enc := json.NewEncoder(w)
+	for _, s := range spans {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Package trace is lightweight debug tracing with spans (Topic 140): a
+// span is a named piece of work with a start, an end, attributes and a
+// parent, and a Tracer collects the finished ones in memory.
+//
+//	tracer := trace.New()
+//	ctx, lesson := tracer.Start(ctx, "lesson 82")
+//	defer lesson.End()
+//	_, section := tracer.Start(ctx, "section: md5") // A child of lesson
+//	section.SetAttr("bytes", 1024)
+//	section.End()
+//	...
+//	trace.ExportText(os.Stderr, tracer.Spans())
+//
+// The current span travels in the context, so nesting follows the calls.
+// A nil *Tracer is tracing switched off: Start returns a nil *Span, and
+// every method of a nil *Span does nothing, so instrumented code reads
+// the same either way. Topic 141 exports the spans as OTLP, 172 times a
+// lesson's sections with them, and `gotut run --trace` prints that
+// breakdown.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A span's kind, numbered as OTLP numbers them (Topic 141). Zero leaves
+// it unset.
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+// Span is one timed piece of work. Its exported fields are what the
+// exporters write; set attributes with SetAttr, which is safe while
+// other goroutines end the span.
+type Span struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	Name       string            `json:"name"`
+	Kind       int               `json:"kind,omitempty"`
+	Start      time.Time         `json:"start"`
+	Finish     time.Time         `json:"end"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	tracer *Tracer
+	mu     sync.Mutex
+	ended  bool
+}
+
+// SetAttr attaches a detail to the span, as text.
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = fmt.Sprint(value)
+}
+
+// End records the end time and hands the span to its tracer. Only the
+// first call counts.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.Finish = s.tracer.now()
+	s.mu.Unlock()
+	s.tracer.collect(s)
+}
+
+// Duration is how long the span ran; zero for a nil span.
+func (s *Span) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.Finish.Sub(s.Start)
+}
+
+// Tracer starts spans and collects the finished ones.
+type Tracer struct {
+	Service string           // The process the spans come from, for an export (OTLP's service.name)
+	Now     func() time.Time // The clock; nil is time.Now
+
+	mu       sync.Mutex
+	finished []*Span
+}
+
+// New returns a Tracer with an empty collection.
+func New() *Tracer { return &Tracer{} }
+
+type spanKey struct{} // Unexported key type: no other package can collide with it
+
+// Start begins a span as a child of the span in ctx, or as the root of a
+// new trace, and returns a context that holds it.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	s := &Span{Name: name, SpanID: newID(8), Start: t.now(), tracer: t}
+	if parent := FromContext(ctx); parent != nil {
+		s.TraceID = parent.TraceID
+		s.ParentID = parent.SpanID
+	} else {
+		s.TraceID = newID(16)
+	}
+	return ContextWithSpan(ctx, s), s
+}
+
+// Spans returns the finished spans, ordered by start time.
+func (t *Tracer) Spans() []*Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := append([]*Span(nil), t.finished...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+func (t *Tracer) now() time.Time {
+	if t.Now == nil {
+		return time.Now()
+	}
+	return t.Now()
+}
+
+func (t *Tracer) collect(s *Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.finished = append(t.finished, s)
+}
+
+// FromContext returns the span in ctx, or nil.
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// ContextWithSpan returns a copy of ctx holding s, so the spans started
+// from it are its children. A span that arrived from another process —
+// only its TraceID and SpanID, as 141's traceparent header carries them —
+// continues that process's trace this way.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+func newID(bytes int) string {
+	b := make([]byte, bytes)
+	rand.Read(b) // Never fails (crypto/rand)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,94 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clock is a fake Now: each call is a millisecond after the last.
+func clock() func() time.Time {
+	t := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Millisecond)
+		return t
+	}
+}
+
+func TestSpans(t *testing.T) {
+	tracer := &Tracer{Now: clock()}
+	ctx, root := tracer.Start(context.Background(), "lesson")
+	_, child := tracer.Start(ctx, "section: md5")
+	child.SetAttr("bytes", 1024)
+	child.End()
+	child.End() // Only the first End counts
+	root.End()
+
+	spans := tracer.Spans()
+	if len(spans) != 2 || spans[0] != root || spans[1] != child {
+		t.Fatalf("Spans = %v, want root then child", spans)
+	}
+	if child.TraceID != root.TraceID || child.ParentID != root.SpanID || root.ParentID != "" {
+		t.Errorf("child %+v isn't under root %+v", child, root)
+	}
+	if root.Duration() != 3*time.Millisecond || child.Duration() != time.Millisecond {
+		t.Errorf("durations %v and %v, want 3ms and 1ms", root.Duration(), child.Duration())
+	}
+	if child.Attributes["bytes"] != "1024" {
+		t.Errorf("attributes %v", child.Attributes)
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	ctx, s := tracer.Start(context.Background(), "off")
+	s.SetAttr("k", "v")
+	s.End()
+	if s != nil || FromContext(ctx) != nil || tracer.Spans() != nil || s.Duration() != 0 {
+		t.Errorf("a nil tracer traced: %v", s)
+	}
+}
+
+func TestRemoteParent(t *testing.T) {
+	remote := &Span{TraceID: strings.Repeat("a", 32), SpanID: strings.Repeat("b", 16)}
+	_, s := New().Start(ContextWithSpan(context.Background(), remote), "GET /lessons")
+	if s.TraceID != remote.TraceID || s.ParentID != remote.SpanID {
+		t.Errorf("span %+v doesn't continue the remote trace", s)
+	}
+}
+
+func TestExport(t *testing.T) {
+	tracer := &Tracer{Now: clock()}
+	ctx, root := tracer.Start(context.Background(), "lesson")
+	_, child := tracer.Start(ctx, "section: md5")
+	child.SetAttr("bytes", 64)
+	child.End()
+	root.End()
+
+	var text bytes.Buffer
+	ExportText(&text, tracer.Spans())
+	lines := strings.Split(strings.TrimSpace(text.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "trace "+root.TraceID) ||
+		!strings.Contains(lines[1], "lesson") || !strings.Contains(lines[1], "100.0%") ||
+		!strings.Contains(lines[2], "    section: md5") || !strings.HasSuffix(lines[2], "bytes=64") {
+		t.Errorf("ExportText:\n%s", text.String())
+	}
+
+	var out bytes.Buffer
+	if err := ExportJSON(&out, tracer.Spans()); err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(&out)
+	for _, want := range []*Span{root, child} {
+		var got Span
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got.SpanID != want.SpanID || got.ParentID != want.ParentID || !got.Finish.Equal(want.Finish) {
+			t.Errorf("ExportJSON wrote %+v, want %+v", &got, want)
+		}
+	}
+}
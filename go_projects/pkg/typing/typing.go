@@ -0,0 +1,233 @@
+// Package typing is a typing drill for Go syntax: short snippets cut from
+// the lessons, typed key by key, scored for speed and accuracy, with a
+// personal best per snippet (gotut type):
+//
+//	d := typing.NewDrill(s.Text)
+//	right := d.Type('f', time.Now())   // false: show it in red
+//	d.Backspace()
+//	...
+//	if d.Done() {
+//		fmt.Printf("%.0f wpm, %.0f%%\n", d.Score().WPM(), 100*d.Score().Accuracy())
+//	}
+//
+// Reading Go and writing it are different skills. The braces, := and
+// backquoted struct tags that the eye skips over are what the fingers
+// trip on, and a learner who has to look for the backquote key isn't
+// thinking about the program. A few minutes on real lines from the
+// course fixes that.
+//
+// The drill follows the usual typing-test rules. A wrong character is
+// shown and has to be deleted, so a snippet is only done when every
+// character is right; Enter is only taken at the end of a line typed
+// right. Indentation is typed for you, as an editor would. Speed is in
+// words per minute, a word being 5 characters; accuracy counts every
+// character typed, including the ones deleted since.
+//
+// Drill knows nothing about terminals. pkg/term turns key presses into
+// the runes given to Type, and the caller draws; a test types a string.
+package typing
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Snippet is a few lines from a lesson.
+type Snippet struct {
+	Name   string // "select-timeout": how results and the command line refer to it
+	Topic  int    // The lesson it comes from
+	Source string // That lesson's file, relative to go_projects/
+	Text   string // Indented with tabs as gofmt leaves it; no final newline
+}
+
+var snippets = []Snippet{
+	{"range-string", 60, "../intermediate_topics/60_strings_and_runes_detailed.go",
+		"for idx, char := range text {\n\tfmt.Printf(\"  Index %d: '%c' (rune: %v)\\n\", idx, char, char)\n}"},
+	{"generic-stack", 67, "../intermediate_topics/67_generics_detailed.go",
+		"type Stack[T any] struct {\n\telements []T // This slice will hold items of type T\n}"},
+	{"wrap-error", 68, "../intermediate_topics/68_errors_detailed.go",
+		`outerErr := fmt.Errorf("operation failed: %w", ErrFileNotFound)`},
+	{"error-method", 69, "../intermediate_topics/69_custom_errors_detailed.go",
+		"func (d DemoError) Error() string {\n\treturn fmt.Sprintf(\"DemoError (%d): %s\", d.Code, d.Message)\n}"},
+	{"sprintf-pad", 71, "../intermediate_topics/71_string_formatting_detailed.go",
+		`timestamp := fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)`},
+	{"regexp-word", 73, "../intermediate_topics/73_regex_detailed.go",
+		"wordRegex := regexp.MustCompile(`\\b\\w{5}\\b`)"},
+	{"scanner-loop", 80, "../intermediate_topics/80_bufio_detailed.go",
+		"scanner := bufio.NewScanner(strings.NewReader(input))\nfor scanner.Scan() {\n\tfmt.Printf(\"  %s\\n\", scanner.Text())\n}"},
+	{"defer-close", 83, "../intermediate_topics/83_write_file_detailed.go",
+		"if err != nil {\n\tfmt.Println(\"Error creating file:\", err)\n\treturn\n}\ndefer file.Close()"},
+	{"struct-tag", 95, "../intermediate_topics/95_struct_tags.go",
+		"FirstName string `json:\"first_name\"`"},
+	{"context-timeout", 138, "138_telemetry_opt_in.go",
+		"ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)\ndefer cancel()"},
+	{"select-timeout", 142, "142_health_checks.go",
+		"select {\ncase err = <-done:\ncase <-ctx.Done():\n\terr = fmt.Errorf(\"timed out after %v\", c.timeout)\n}"},
+	{"waitgroup-go", 179, "179_xbuild.go",
+		"var wg sync.WaitGroup\nfor i, t := range targets {\n\twg.Go(func() {\n\t\tartifacts[i], errs[i] = x.build(t)\n\t})\n}\nwg.Wait()"},
+}
+
+// Snippets returns every snippet, in topic order.
+func Snippets() []Snippet { return slices.Clone(snippets) }
+
+// Get returns the snippet called name.
+func Get(name string) (Snippet, bool) {
+	i := slices.IndexFunc(snippets, func(s Snippet) bool { return s.Name == name })
+	if i < 0 {
+		return Snippet{}, false
+	}
+	return snippets[i], true
+}
+
+// Drill is one snippet being typed.
+type Drill struct {
+	text     []rune
+	typed    []rune
+	floor    int // Backspace stops here: where the current line's typing began
+	indented int // Runes of indentation typed for the learner
+	keys     int
+	mistakes int
+	first    time.Time
+	last     time.Time
+}
+
+// NewDrill starts a drill on text.
+func NewDrill(text string) *Drill {
+	d := &Drill{text: []rune(text)}
+	d.indent()
+	return d
+}
+
+// indent types the indentation at the start of a line.
+func (d *Drill) indent() {
+	for len(d.typed) < len(d.text) && (d.text[len(d.typed)] == '\t' || d.text[len(d.typed)] == ' ') {
+		d.typed = append(d.typed, d.text[len(d.typed)])
+		d.indented++
+	}
+	d.floor = len(d.typed)
+}
+
+// Type records one key typed at now and reports whether it was the
+// character due. A wrong character is kept, to be deleted; a '\n' typed
+// anywhere but the end of a line typed right is counted as a mistake and
+// dropped, so the lines above the current one are always right. Keys
+// typed after the drill is done are ignored.
+func (d *Drill) Type(r rune, now time.Time) (right bool) {
+	if d.Done() {
+		return false
+	}
+	if d.keys == 0 {
+		d.first = now
+	}
+	d.keys++
+	d.last = now
+	pos := len(d.typed)
+	right = pos < len(d.text) && d.text[pos] == r && d.clean()
+	if !right {
+		d.mistakes++
+	}
+	if r == '\n' {
+		if right {
+			d.typed = append(d.typed, r)
+			d.indent()
+		}
+		return right
+	}
+	d.typed = append(d.typed, r)
+	return right
+}
+
+// Backspace deletes the last character typed on the current line and
+// reports whether there was one.
+func (d *Drill) Backspace() bool {
+	if len(d.typed) <= d.floor || d.Done() {
+		return false
+	}
+	d.typed = d.typed[:len(d.typed)-1]
+	return true
+}
+
+// clean reports whether everything typed so far is right.
+func (d *Drill) clean() bool {
+	return len(d.typed) <= len(d.text) && slices.Equal(d.typed, d.text[:len(d.typed)])
+}
+
+// Done reports whether the whole snippet has been typed right.
+func (d *Drill) Done() bool { return len(d.typed) == len(d.text) && d.clean() }
+
+// Typed returns what has been typed, indentation included.
+func (d *Drill) Typed() string { return string(d.typed) }
+
+// Score is how a drill went, so far or in the end.
+func (d *Drill) Score() Score {
+	return Score{Chars: len(d.text) - d.indented, Keys: d.keys, Mistakes: d.mistakes, Elapsed: d.last.Sub(d.first)}
+}
+
+// Score measures one drill.
+type Score struct {
+	Chars    int           // The snippet's length in characters, indentation left out
+	Keys     int           // Characters typed, right or wrong; Backspace isn't one
+	Mistakes int           // Keys that weren't the character due
+	Elapsed  time.Duration // From the first key to the last
+}
+
+// WPM is the speed in words per minute, a word being 5 characters.
+func (s Score) WPM() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Chars) / 5 / s.Elapsed.Minutes()
+}
+
+// Accuracy is the fraction of keys that were right, 0 with none typed.
+func (s Score) Accuracy() float64 {
+	if s.Keys == 0 {
+		return 0
+	}
+	return float64(s.Keys-s.Mistakes) / float64(s.Keys)
+}
+
+// Attempt is a finished drill, as the caller stores it.
+type Attempt struct {
+	Snippet string
+	Score   Score
+	Time    time.Time
+}
+
+// Record is a snippet's history: how often it was finished, and the
+// fastest of those, the more accurate of two as fast.
+type Record struct {
+	Snippet string
+	Tries   int
+	Best    Attempt // Zero when Tries is 0
+}
+
+// Records returns one Record per snippet, in topic order, from the
+// attempts so far. Attempts on snippets since removed are left out.
+func Records(attempts []Attempt) []Record {
+	out := make([]Record, len(snippets))
+	for i, s := range snippets {
+		out[i].Snippet = s.Name
+	}
+	for _, a := range attempts {
+		i := slices.IndexFunc(snippets, func(s Snippet) bool { return s.Name == a.Snippet })
+		if i < 0 {
+			continue
+		}
+		r := &out[i]
+		r.Tries++
+		if r.Tries == 1 || Better(a.Score, r.Best.Score) {
+			r.Best = a
+		}
+	}
+	return out
+}
+
+// Better reports whether a beats b: faster, or as fast and more accurate.
+func Better(a, b Score) bool {
+	if c := cmp.Compare(a.WPM(), b.WPM()); c != 0 {
+		return c > 0
+	}
+	return a.Accuracy() > b.Accuracy()
+}
@@ -0,0 +1,159 @@
+package typing
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSnippetsInLessons checks that every snippet is still in its
+// lesson, line for line: a lesson edited since can't leave the drill
+// teaching code the course no longer has.
+func TestSnippetsInLessons(t *testing.T) {
+	root := filepath.Join("..", "..") // go_projects
+	if _, err := os.Stat(filepath.Join(root, "gotut")); err != nil {
+		t.Skip("not in the course tree")
+	}
+	for _, s := range Snippets() {
+		src, err := os.ReadFile(filepath.Join(root, s.Source))
+		if err != nil {
+			t.Errorf("%s: %v", s.Name, err)
+			continue
+		}
+		if !strings.HasPrefix(filepath.Base(s.Source), strconv.Itoa(s.Topic)+"_") {
+			t.Errorf("%s: Topic %d, but from %s", s.Name, s.Topic, s.Source)
+		}
+		if !containsLines(trimmed(string(src)), trimmed(s.Text)) {
+			t.Errorf("%s: not in %s:\n%s", s.Name, s.Source, s.Text)
+		}
+	}
+}
+
+func trimmed(s string) []string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return lines
+}
+
+func containsLines(src, want []string) bool {
+	for i := 0; i+len(want) <= len(src); i++ {
+		if strings.Join(src[i:i+len(want)], "\n") == strings.Join(want, "\n") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeAll types s a second apart from start and returns the keys' results.
+func typeAll(d *Drill, s string, start time.Time) (rights []bool) {
+	for i, r := range s {
+		if r == '\b' {
+			d.Backspace()
+			continue
+		}
+		rights = append(rights, d.Type(r, start.Add(time.Duration(i)*time.Second)))
+	}
+	return rights
+}
+
+func TestDrill(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	d := NewDrill("if ok {\n\treturn\n}")
+
+	typeAll(d, "if ko", start)
+	if d.Typed() != "if ko" || d.Done() {
+		t.Fatalf("typed %q", d.Typed())
+	}
+	typeAll(d, "\n", start) // Enter after a mistake: dropped
+	if d.Typed() != "if ko" {
+		t.Fatalf("Enter mid-line typed %q", d.Typed())
+	}
+	typeAll(d, "\b\bok {\n", start)
+	if d.Typed() != "if ok {\n\t" {
+		t.Fatalf("after the first line: %q, want the tab typed too", d.Typed())
+	}
+	if d.Backspace() || d.Typed() != "if ok {\n\t" {
+		t.Fatalf("Backspace went back past the indentation: %q", d.Typed())
+	}
+	typeAll(d, "return\n}", start)
+	if !d.Done() || d.Typed() != "if ok {\n\treturn\n}" {
+		t.Fatalf("not done: %q", d.Typed())
+	}
+	if d.Type('x', start) || d.Backspace() || d.Typed() != "if ok {\n\treturn\n}" {
+		t.Error("typing went on after the drill was done")
+	}
+
+	s := d.Score()
+	// Keys: "if ko" (5), the dropped Enter, "ok {\n" (5), "return\n}" (8).
+	// Mistakes: 'k', 'o', the Enter. Chars: 17, less one tab.
+	if s.Chars != 16 || s.Keys != 19 || s.Mistakes != 3 {
+		t.Errorf("score %+v, want 16 chars, 19 keys, 3 mistakes", s)
+	}
+	if s.Accuracy() != 16.0/19 {
+		t.Errorf("accuracy %v", s.Accuracy())
+	}
+}
+
+func TestMistakesStick(t *testing.T) {
+	d := NewDrill("abc")
+	rights := typeAll(d, "xbc", time.Now())
+	// b and c are the characters due at their places, but after an x
+	// they are wrong too: the line has to be fixed from the x.
+	if rights[0] || rights[1] || rights[2] || d.Done() {
+		t.Errorf("rights %v, done %v", rights, d.Done())
+	}
+	typeAll(d, "\b\b\babcd", time.Now())
+	if !d.Done() || d.Typed() != "abc" {
+		t.Errorf("typed %q, done %v: the d after the end should be ignored", d.Typed(), d.Done())
+	}
+}
+
+func TestScore(t *testing.T) {
+	s := Score{Chars: 50, Keys: 55, Mistakes: 5, Elapsed: 30 * time.Second}
+	if s.WPM() != 20 {
+		t.Errorf("WPM = %v, want 20: 10 words in half a minute", s.WPM())
+	}
+	if (Score{}).WPM() != 0 || (Score{}).Accuracy() != 0 {
+		t.Error("an empty score isn't 0")
+	}
+	if !Better(Score{Chars: 50, Elapsed: 20 * time.Second}, s) {
+		t.Error("faster isn't better")
+	}
+	if !Better(Score{Chars: 50, Keys: 50, Elapsed: 30 * time.Second}, s) || Better(s, s) {
+		t.Error("as fast and more accurate isn't better")
+	}
+}
+
+func TestRecords(t *testing.T) {
+	at := func(name string, seconds int, mistakes int) Attempt {
+		return Attempt{name, Score{Chars: 60, Keys: 60 + mistakes, Mistakes: mistakes, Elapsed: time.Duration(seconds) * time.Second}, time.Time{}}
+	}
+	records := Records([]Attempt{
+		at("wrap-error", 30, 2),
+		at("wrap-error", 20, 4), // Fastest
+		at("wrap-error", 20, 6),
+		at("struct-tag", 40, 0),
+		at("gone", 1, 0), // A snippet since removed
+	})
+	if len(records) != len(Snippets()) || records[0].Snippet != "range-string" {
+		t.Fatalf("%d records, first %q: want one per snippet in topic order", len(records), records[0].Snippet)
+	}
+	by := map[string]Record{}
+	for _, r := range records {
+		by[r.Snippet] = r
+	}
+	if r := by["wrap-error"]; r.Tries != 3 || r.Best.Score.Elapsed != 20*time.Second || r.Best.Score.Mistakes != 4 {
+		t.Errorf("wrap-error: %+v", r)
+	}
+	if r := by["struct-tag"]; r.Tries != 1 || r.Best.Score.WPM() != 18 {
+		t.Errorf("struct-tag: %+v", r)
+	}
+	if r := by["select-timeout"]; r.Tries != 0 || r.Best != (Attempt{}) {
+		t.Errorf("select-timeout: %+v", r)
+	}
+}
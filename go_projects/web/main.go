@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/course"
+)
+
+/*
+WEB — THE COURSE IN A BROWSER
+
+A read-only front end to the course: the table of topics as an HTML page
+and as JSON, from the same pkg/course.Topics that gotut help topics uses.
+It is the workspace's fourth module (Topic 193): the lessons at the
+repository root, the packages in go_projects/pkg, the tools in
+go_projects/gotut, and this.
+
+    GET /               → the topics, as a page
+    GET /topics         → the topics, as JSON
+    GET /topics/{name}  → one topic, by number, slug or alias
+    GET /healthz        → liveness, and GET /readyz → readiness: 503 with
+                          no topics (pkg/health, Topic 142)
+
+    main.go    → this overview, flags, and the listener
+    server.go  → the handlers
+    server_test.go → every route through httptest
+
+RUN (a module of its own, in the repository's go.work):
+    cd go_projects/web
+    go run . -addr localhost:8080
+    curl localhost:8080/topics/73
+    go test -v .
+*/
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	dir := flag.String("dir", "..", "the go_projects directory")
+	flag.Parse()
+
+	var dirs []string
+	for _, d := range course.Dirs() {
+		dirs = append(dirs, filepath.Join(*dir, d))
+	}
+	topics, err := course.Topics(dirs...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "web:", err)
+		os.Exit(1)
+	}
+	log.Printf("%d topics, listening on http://%s", topics.Len(), *addr)
+	log.Fatal(http.ListenAndServe(*addr, NewServer(topics)))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/health"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/registry"
+)
+
+// NewServer returns the front end's routes over topics.
+func NewServer(topics *registry.Registry) http.Handler {
+	// Alive whenever it can answer; ready once there is a course to show
+	// (Topic 142 on the difference).
+	checks := &health.Registry{}
+	checks.Register("topics", health.Readiness, time.Second, func(context.Context) error {
+		if topics.Len() == 0 {
+			return errors.New("no topics loaded")
+		}
+		return nil
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", checks.Handler(health.Liveness))
+	mux.Handle("GET /readyz", checks.Handler(health.Readiness))
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		// html/template escapes every title: they come from lesson files,
+		// and Topic 201 is the lesson on why that matters.
+		if err := index.Execute(w, topics.All()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc("GET /topics", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, topics.All())
+	})
+	mux.HandleFunc("GET /topics/{name}", func(w http.ResponseWriter, r *http.Request) {
+		t, ok := topics.Lookup(r.PathValue("name"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no topic " + r.PathValue("name")})
+			return
+		}
+		writeJSON(w, http.StatusOK, t)
+	})
+	return mux
+}
+
+var index = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Go tutorials</title></head>
+<body>
+<h1>Go tutorials</h1>
+<ol>
+{{- range .}}
+<li value="{{.ID}}"><a href="/topics/{{.Slug}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</body>
+</html>
+`))
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/registry"
+)
+
+func TestServer(t *testing.T) {
+	topics := registry.New()
+	topics.MustRegister(registry.Topic{ID: 73, Slug: "regex", Title: "Regular <Expressions>", Files: []string{"73_regex.go"}})
+	topics.MustRegister(registry.Topic{ID: 152, Slug: "kvstore", Title: "A key-value store", Files: []string{"152_kvstore"}})
+	srv := httptest.NewServer(NewServer(topics))
+	defer srv.Close()
+
+	get := func(path string) (int, string, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	code, ctype, body := get("/")
+	if code != http.StatusOK || !strings.HasPrefix(ctype, "text/html") {
+		t.Errorf("GET / = %d %s", code, ctype)
+	}
+	for _, want := range []string{`<li value="73"><a href="/topics/regex">Regular &lt;Expressions&gt;</a>`, `/topics/kvstore`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("GET / has no %q:\n%s", want, body)
+		}
+	}
+
+	code, _, body = get("/topics")
+	var all []registry.Topic
+	if err := json.Unmarshal([]byte(body), &all); code != http.StatusOK || err != nil || len(all) != 2 {
+		t.Errorf("GET /topics = %d, %d topics, %v", code, len(all), err)
+	}
+
+	for _, name := range []string{"73", "regex"} {
+		code, _, body = get("/topics/" + name)
+		var one registry.Topic
+		if err := json.Unmarshal([]byte(body), &one); code != http.StatusOK || err != nil || one.ID != 73 {
+			t.Errorf("GET /topics/%s = %d, %+v, %v", name, code, one, err)
+		}
+	}
+	if code, _, _ = get("/topics/999"); code != http.StatusNotFound {
+		t.Errorf("GET /topics/999 = %d, want 404", code)
+	}
+	if code, _, _ = get("/nowhere"); code != http.StatusNotFound {
+		t.Errorf("GET /nowhere = %d, want 404", code)
+	}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if code, ctype, body = get(path); code != http.StatusOK || ctype != "application/json" || !strings.Contains(body, `"status":"ok"`) {
+			t.Errorf("GET %s = %d %s %s", path, code, ctype, body)
+		}
+	}
+}
+
+// TestNotReady: with no topics the server is alive, and says it can't
+// serve yet.
+func TestNotReady(t *testing.T) {
+	srv := httptest.NewServer(NewServer(registry.New()))
+	defer srv.Close()
+	for path, want := range map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusServiceUnavailable} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
@@ -1,9 +1,10 @@
-package intermediate
+package main
 
 import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 )
 
 // ============================================================================
@@ -18,6 +19,8 @@ import (
 // In other languages (Java, Python): Use try/catch/finally
 // In Go: Return errors as values and check them with "if err != nil"
 //
+// Topic 69 builds error TYPES on this, with Unwrap chains several layers deep.
+//
 // ============================================================================
 
 func main() {
@@ -74,7 +77,7 @@ This simple design means:
 	// ========================================================================
 	// SECTION 3: The Standard Error-Checking Pattern
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 3: Error Checking Pattern (if err != nil) ---")
 
 	result, err := Sqrt(-16) // This will return an error
@@ -99,7 +102,7 @@ This simple design means:
 	// ========================================================================
 	// SECTION 4: Error Wrapping - Adding Context to Errors
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 4: Error Wrapping (Adding Context) ---")
 
 	fmt.Println(`
@@ -126,7 +129,7 @@ Solution: Wrap errors with fmt.Errorf and %w verb
 	// ========================================================================
 	// SECTION 5: Unwrapping Errors - Checking Inner Errors
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 5: Unwrapping (Finding Original Error) ---")
 
 	fmt.Println(`
@@ -142,12 +145,14 @@ errors.As(err, &target):
   If err (or its chain) matches target type, extract it
 `)
 
-	// Test errors.Is
-	outerErr := fmt.Errorf("operation failed: %w", errors.New("file not found"))
-	originalErr := errors.New("file not found")
+	// Test errors.Is: it compares against ONE value, a sentinel declared once
+	outerErr := fmt.Errorf("operation failed: %w", ErrFileNotFound)
 
-	if errors.Is(outerErr, originalErr) {
-		fmt.Println("✓ Found the original 'file not found' error inside the wrapped error")
+	if errors.Is(outerErr, ErrFileNotFound) {
+		fmt.Println("✓ Found the original ErrFileNotFound inside the wrapped error")
+	}
+	if !errors.Is(outerErr, errors.New("file not found")) {
+		fmt.Println("✓ A new errors.New(\"file not found\") does not match: same text, different error")
 	}
 
 	// Test errors.As with custom error type
@@ -167,7 +172,7 @@ errors.As(err, &target):
 	// ========================================================================
 	// SECTION 6: The Guard Clause Pattern (Early Return)
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 6: Guard Clause Pattern (Preferred Style) ---")
 
 	fmt.Println(`
@@ -199,7 +204,7 @@ WHY guard clause is better:
 	// ========================================================================
 	// SECTION 7: Custom Errors - The "Power User" Move
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 7: Custom Errors (Structs + Error() method) ---")
 
 	fmt.Println(`
@@ -218,7 +223,7 @@ Benefits:
 `)
 
 	// Create and use a custom error
-	apiErr := &APIError{
+	var apiErr error = &APIError{
 		StatusCode: 500,
 		Message:    "Database connection failed",
 		Endpoint:   "/api/users",
@@ -234,7 +239,7 @@ Benefits:
 	// ========================================================================
 	// SECTION 8: Error Handling Patterns & Best Practices
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 8: Error Handling Patterns ---")
 
 	demonstrateErrorPatterns()
@@ -242,7 +247,7 @@ Benefits:
 	// ========================================================================
 	// SECTION 9: The Golden Rules
 	// ========================================================================
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 9: The Golden Rules of Go Error Handling ---")
 	fmt.Println(`
 ✓ DO:
@@ -284,6 +289,10 @@ func Sqrt(x float64) (float64, error) {
 	return math.Sqrt(x), nil
 }
 
+// ErrFileNotFound is a sentinel error: callers test for it with errors.Is,
+// which matches this one value anywhere in a chain of %w wraps.
+var ErrFileNotFound = errors.New("file not found")
+
 // ============================================================================
 // HELPER FUNCTION 2: readConfig - Demonstrates error wrapping
 // ============================================================================
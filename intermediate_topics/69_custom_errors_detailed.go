@@ -1,9 +1,16 @@
-package intermediate
+package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/errorx"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/errorx/httpmap"
 )
 
 // ============================================================================
@@ -21,6 +28,13 @@ import (
 //
 // HOW: Create a struct with an Error() method = custom error type
 //
+// WrappedError, ValidationError, AuthError and DatabaseError live in
+// go_projects/pkg/errorx, with tests and Unwrap methods, so other code can
+// import them; this lesson uses them from there, through the go.work at
+// the repository root:
+//
+//	go run 69_custom_errors_detailed.go
+//
 // ============================================================================
 
 func main() {
@@ -103,11 +117,7 @@ Let's create custom error types that hold MORE information.
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 3: Validation Error (Domain-Specific) ---")
 
-	valErr := ValidationError{
-		Field: "email",
-		Issue: "invalid format",
-		Value: "notanemail",
-	}
+	valErr := errorx.NewValidationError("email", "invalid format", "notanemail")
 	fmt.Printf("Validation error: %v\n", valErr)
 
 	// Type-assert and inspect
@@ -171,6 +181,7 @@ Step 3: Caller sees the full chain
 WHY This Matters for Debugging
 ───────────────────────────────
 • Chain of events: Like a stack trace in other languages
+  (and errorx.WrapStack records a real one: Section 4b)
 • Error codes: Tells you severity (404 vs 500 vs 422)
 • Context: Tells you WHERE in the code it failed
 • Root cause: Tells you WHY it ultimately failed
@@ -191,6 +202,42 @@ With wrapping: You have a complete story
 		fmt.Println("  → This is the FULL chain of what went wrong!")
 	}
 
+	// ========================================================================
+	// SECTION 4b: A Real Stack Trace - WrapStack and %+v
+	// ========================================================================
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("--- SECTION 4b: A Real Stack Trace (WrapStack and %+v) ---")
+
+	fmt.Println(`
+The chain of messages says WHAT was being done at each layer, but not
+WHERE in the code. A panic prints file:line for every call; an error
+can carry the same, if it records the stack when it is made:
+
+  return errorx.WrapStack(500, "failed to save report", err)
+
+  fmt.Printf("%v\n", err)    → the message, as always
+  fmt.Printf("%+v\n", err)   → the message, then the stack
+  wrapped.StackTrace()       → []errorx.Frame{Function, File, Line}
+
+runtime.Callers does the recording, and it isn't free, so plain Wrap
+skips it: use WrapStack once, where the error enters your code.
+`)
+
+	err = saveReport()
+	fmt.Printf("✓ %%v:\n  %v\n\n", err)
+	fmt.Printf("✓ %%+v:\n%+v\n\n", err)
+	var traced *errorx.WrappedError
+	check(errors.As(err, &traced) && len(traced.StackTrace()) > 1 &&
+		strings.HasSuffix(traced.StackTrace()[0].Function, ".saveReport") &&
+		strings.HasSuffix(traced.StackTrace()[1].Function, ".main"),
+		"StackTrace()[0] is saveReport, where WrapStack was called; [1] its caller, main",
+		fmt.Sprintf("StackTrace() = %v", traced.StackTrace()))
+	var plain *errorx.WrappedError
+	errors.As(doSomething(), &plain)
+	check(plain.StackTrace() == nil && !strings.Contains(fmt.Sprintf("%+v", plain), "\n"),
+		"the struct literal in doSomething has no stack: %+v is just the message",
+		fmt.Sprintf("%+v", plain))
+
 	// ========================================================================
 	// SECTION 5: Unwrapping Wrapped Errors
 	// ========================================================================
@@ -216,7 +263,7 @@ Go's type assertion lets you "unwrap" an error and access its fields:
 
   err := doSomething()
   if err != nil {
-      if wrapped, ok := err.(*WrappedError); ok {
+      if wrapped, ok := err.(*errorx.WrappedError); ok {
           fmt.Printf("Code: %d\n", wrapped.Code)
           fmt.Printf("Message: %s\n", wrapped.Message)
           fmt.Printf("Inner error: %v\n", wrapped.Err)
@@ -224,7 +271,7 @@ Go's type assertion lets you "unwrap" an error and access its fields:
   }
 
 Breakdown:
-  err.(*WrappedError) → Try to convert err to *WrappedError type
+  err.(*errorx.WrappedError) → Try to convert err to *WrappedError type
   ok → True if conversion succeeded, false otherwise
   wrapped → The actual WrappedError struct with all fields accessible
 `)
@@ -233,7 +280,7 @@ Breakdown:
 	if err != nil {
 		fmt.Println("\n✓ Extracting wrapped error details:")
 		// Type-assert to see if it's a WrappedError
-		if wrapped, ok := err.(*WrappedError); ok {
+		if wrapped, ok := err.(*errorx.WrappedError); ok {
 			fmt.Printf("  • Is a WrappedError? YES\n")
 			fmt.Printf("  • Code: %d\n", wrapped.Code)
 			fmt.Printf("  • Message: %s\n", wrapped.Message)
@@ -251,13 +298,45 @@ Breakdown:
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("--- SECTION 6: Real-World Example - API Errors ---")
 
-	// Simulate fetching a user
-	user := getUserByID("invalid-id")
-	if user == nil {
-		// This demonstrates how custom errors help in API handlers
-		fmt.Println("Could not fetch user - see custom error above")
+	// A real handler behind a real mux, called without a network:
+	// httptest.NewRecorder is the ResponseWriter, and afterwards holds the
+	// status, headers and body the client would have got.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", getUserByID)
+	get := func(path, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Request-Id", requestID)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		fmt.Printf("GET %s → %d %s\n  %s", path, rec.Code, http.StatusText(rec.Code), rec.Body)
+		return rec
 	}
 
+	bad := get("/users/invalid-id", "req-6a")
+	var body httpmap.Response
+	json.Unmarshal(bad.Body.Bytes(), &body)
+	check(bad.Code == 422 && len(body.Details) == 1 && body.Details[0].Field == "id",
+		`the ValidationError became 422 and {"field":"id","issue":"invalid format"}`,
+		fmt.Sprintf("got %d %+v", bad.Code, body))
+	check(body.RequestID == "req-6a" && bad.Header().Get("X-Request-Id") == "req-6a",
+		"request_id is the caller's X-Request-Id, in the body and the header",
+		fmt.Sprintf("request_id %q, header %q", body.RequestID, bad.Header().Get("X-Request-Id")))
+
+	good := get("/users/7", "req-6b")
+	check(good.Code == 200 && strings.Contains(good.Body.String(), `"name":"John Doe"`),
+		"a good ID is a 200 with the user",
+		fmt.Sprintf("got %d %s", good.Code, good.Body))
+
+	// The status comes from the error's own StatusCode method, found
+	// through any wrapping; a 5xx tells the client nothing about why.
+	dbDown := fmt.Errorf("loading user 7: %w",
+		errorx.NewDatabaseError("SELECT", "users", errors.New("connection refused: 10.0.0.5:5432")))
+	resp := httpmap.From(dbDown, "req-6c")
+	fmt.Printf("From(%q)\n  → %+v\n", dbDown, resp)
+	check(resp.Code == 500 && resp.Message == "Internal Server Error" && resp.Details == nil,
+		"a DatabaseError is a 500 with no details: the address stays in the log",
+		fmt.Sprintf("got %+v", resp))
+
 	// ========================================================================
 	// SECTION 7: Multiple Custom Error Types
 	// ========================================================================
@@ -280,24 +359,13 @@ You handle each type differently:
 		fn   func() error
 	}{
 		{"validate email", func() error {
-			return ValidationError{
-				Field: "email",
-				Issue: "already exists",
-				Value: "user@example.com",
-			}
+			return errorx.NewValidationError("email", "already exists", "user@example.com")
 		}},
 		{"check auth", func() error {
-			return AuthError{
-				Reason:  "invalid token",
-				TokenID: "abc123",
-			}
+			return errorx.NewAuthError("invalid token", "abc123")
 		}},
 		{"connect database", func() error {
-			return &DatabaseError{
-				Operation: "INSERT",
-				Table:     "users",
-				Inner:     errors.New("connection timeout"),
-			}
+			return errorx.NewDatabaseError("INSERT", "users", errors.New("connection timeout"))
 		}},
 	}
 
@@ -344,24 +412,155 @@ This lets callers DECIDE how to handle the error:
 `)
 
 	// Demonstrate CanRetry() method
-	dbErr := DatabaseError{
-		Operation: "SELECT",
-		Table:     "users",
-		Inner:     errors.New("timeout"),
-	}
+	dbErr := errorx.NewDatabaseError("SELECT", "users", errors.New("timeout"))
 	if dbErr.CanRetry() {
 		fmt.Println("✓ Can retry a SELECT operation")
 	}
 
-	dbErr2 := DatabaseError{
-		Operation: "DELETE",
-		Table:     "users",
-		Inner:     errors.New("timeout"),
-	}
+	dbErr2 := errorx.NewDatabaseError("DELETE", "users", errors.New("timeout"))
 	if !dbErr2.CanRetry() {
 		fmt.Println("✗ Cannot retry a DELETE operation (too risky)")
 	}
 
+	// errorx's DatabaseError answers both questions in one method,
+	// Retryable: safe to repeat AND likely to pass. pkg/retry (Topic 196)
+	// asks it before every retry, through any wrapping.
+	slowRead := errorx.NewDatabaseError("SELECT", "users", os.ErrDeadlineExceeded)
+	badRead := errorx.NewDatabaseError("SELECT", "users", errors.New("syntax error"))
+	slowDelete := errorx.NewDatabaseError("DELETE", "users", os.ErrDeadlineExceeded)
+	check(slowRead.Retryable() && !badRead.Retryable() && !slowDelete.Retryable(),
+		"Retryable(): a timed-out SELECT yes; a syntax error or a timed-out DELETE no",
+		fmt.Sprintf("Retryable(): %v %v %v", slowRead.Retryable(), badRead.Retryable(), slowDelete.Retryable()))
+
+	// ========================================================================
+	// SECTION 8b: Unwrap - errors.Is and errors.As Through the Chain
+	// ========================================================================
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("--- SECTION 8b: Unwrap (errors.Is and errors.As) ---")
+
+	fmt.Println(`
+A type assertion only looks at the OUTERMOST error. Once someone adds
+another layer, fmt.Errorf("loading user: %w", err), it stops matching.
+
+An Unwrap() method hands back the wrapped error, and errors.Is/errors.As
+follow those links all the way down (Topic 68):
+
+  func (w *WrappedError) Unwrap() error { return w.Err }
+
+  errors.Is(err, io.EOF)          → is io.EOF anywhere in the chain?
+  errors.As(err, &dbErr)          → is a *DatabaseError anywhere? fill dbErr`)
+	fmt.Printf("%s\n", `
+Wrapping with %w in fmt.Errorf gives the new error an Unwrap() too, so a
+chain can mix both kinds of layer. Here three functions each add one:
+
+  handleGetUser  → fmt.Errorf("GET /users/%s: %w", id, err)
+  loadProfile    → errorx.Wrap(500, "failed to load profile", err)
+  queryUser      → errorx.NewDatabaseError("SELECT", "users", ErrConnTimeout)
+`)
+
+	err = handleGetUser("42")
+	fmt.Printf("Error: %v\n\n", err)
+	fmt.Println("The chain, one errors.Unwrap at a time:")
+	for i, layer := range errorChain(err) {
+		fmt.Printf("  %d. %s\n", i+1, layer)
+	}
+	fmt.Println()
+
+	_, isWrapped := err.(*errorx.WrappedError)
+	check(!isWrapped,
+		"err.(*errorx.WrappedError) misses it: the outermost layer is fmt.Errorf's",
+		"the type assertion matched the outermost layer")
+	var wrapped *errorx.WrappedError
+	check(errors.As(err, &wrapped) && wrapped.Code == 500,
+		"errors.As finds the *WrappedError one level down: code 500",
+		"errors.As did not find the *WrappedError")
+	var db *errorx.DatabaseError
+	check(errors.As(err, &db) && db.CanRetry(),
+		"errors.As finds the *DatabaseError two levels down: a SELECT, safe to retry",
+		"errors.As did not find the *DatabaseError")
+	check(errors.Is(err, ErrConnTimeout),
+		"errors.Is finds ErrConnTimeout three levels down",
+		"errors.Is did not find ErrConnTimeout")
+	check(!errors.Is(err, errors.New("connection timeout")),
+		`errors.New("connection timeout") is NOT a match: Is compares identity, not text`,
+		"errors.Is matched a different error with the same text")
+	check(statusFor(err) == 500,
+		"statusFor(err) = 500, the WrappedError's code",
+		fmt.Sprintf("statusFor(err) = %d, want 500", statusFor(err)))
+	check(statusFor(handleGetUser("")) == 422,
+		`statusFor(handleGetUser("")) = 422: errors.Is(err, errorx.ErrValidation)`,
+		fmt.Sprintf(`statusFor(handleGetUser("")) = %d, want 422`, statusFor(handleGetUser(""))))
+	check(handleGetUser("7") == nil && statusFor(nil) == 200,
+		"a user that loads is no error at all: statusFor(nil) = 200",
+		"handleGetUser(\"7\") failed")
+
+	fmt.Println(`
+errors.Join wraps SEVERAL errors: its Unwrap() returns []error, so
+errors.Unwrap (one error or nil) gives up, but errors.Is/As try every branch.`)
+	fmt.Println()
+	joined := validateSignup("notanemail", "abc")
+	fmt.Printf("Error:\n%v\n\n", joined)
+	check(errors.Unwrap(joined) == nil,
+		"errors.Unwrap(joined) = nil: a Join has many causes, not one",
+		"errors.Unwrap returned a single cause for a Join")
+	var ve errorx.ValidationError
+	check(errors.As(joined, &ve) && ve.Field == "email",
+		"errors.As finds the first ValidationError in the Join: field 'email'",
+		"errors.As did not find a ValidationError in the Join")
+	check(errors.Is(joined, errorx.ErrValidation) && statusFor(joined) == 422,
+		"errors.Is(joined, errorx.ErrValidation): statusFor = 422",
+		"a Join of ValidationErrors is not a validation error")
+	check(validateSignup("ada@example.com", "correct horse") == nil,
+		"valid input: validateSignup returns nil (errors.Join of nothing is nil)",
+		"validateSignup rejected valid input")
+
+	// ========================================================================
+	// SECTION 8c: MultiError - Every Bad Field of a Struct at Once
+	// ========================================================================
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("--- SECTION 8c: MultiError (all field errors at once) ---")
+	fmt.Println(`
+errors.Join is fine for two checks. A struct with many fields wants
+somewhere to collect them as it goes: errorx.MultiError. Append skips
+nils, Err() is nil when nothing was appended, and it unwraps to every
+error as a Join does, so errors.Is/As and httpmap all see each one:
+
+  func (f SignupForm) Validate() error {
+      var errs errorx.MultiError
+      if !strings.Contains(f.Email, "@") {
+          errs.Append(errorx.NewValidationError("email", "invalid format", f.Email))
+      }
+      ...
+      return errs.Err()
+  }`)
+	fmt.Println()
+
+	form := SignupForm{Email: "notanemail", Password: "abc", Age: 12, Country: "Atlantis"}
+	formErr := form.Validate()
+	fmt.Printf("%+v.Validate():\n%v\n\n", form, formErr)
+	var multi *errorx.MultiError
+	check(errors.As(formErr, &multi) && multi.Len() == 4,
+		"errors.As gets the *errorx.MultiError: 4 errors, one per bad field",
+		fmt.Sprintf("got %v", formErr))
+	check(formErr.Error() == errors.Join(multi.Errs...).Error(),
+		"its message is exactly errors.Join's: one line per error",
+		"the MultiError's message differs from errors.Join's")
+	check(errors.Is(formErr, errorx.ErrValidation) && statusFor(formErr) == 422,
+		"errors.Is(err, errorx.ErrValidation) looks inside: statusFor = 422",
+		"errors.Is did not look inside the MultiError")
+	formResp := httpmap.From(formErr, "req-8c")
+	fmt.Printf("httpmap.From → %d, details:\n", formResp.Code)
+	for _, d := range formResp.Details {
+		fmt.Printf("  %-9s %s\n", d.Field, d.Issue)
+	}
+	check(formResp.Code == 422 && len(formResp.Details) == 4,
+		"the API response lists all 4 fields, so the client can fix them in one go",
+		fmt.Sprintf("got %+v", formResp))
+	goodForm := SignupForm{Email: "ada@example.com", Password: "correct horse", Age: 36, Country: "GB"}
+	check(goodForm.Validate() == nil,
+		"a good form: Validate() returns a nil error, not an empty MultiError",
+		fmt.Sprintf("got %#v", goodForm.Validate()))
+
 	// ========================================================================
 	// SECTION 9: The Golden Rules for Custom Errors
 	// ========================================================================
@@ -374,6 +573,8 @@ This lets callers DECIDE how to handle the error:
   • Use meaningful field names (Operation, Table, Field, etc.)
   • Implement Error() method to format error message
   • Add helper methods for decision-making (CanRetry, IsTimeout)
+  • Give wrapping types an Unwrap() method, so errors.Is/As see the cause
+  • Ask errors.Is / errors.As, not == or a type assertion: they see through %w
   • Export error types (start with capital letter) if used outside package
   • Use pointer receivers in Error() method
 
@@ -395,6 +596,8 @@ PATTERN (The Standard Way):
   func (c *CustomError) Error() string {
       return fmt.Sprintf("Error %d: %s, %v", c.Code, c.Message, c.Inner)
   }
+
+  func (c *CustomError) Unwrap() error { return c.Inner }
 `)
 
 	// ========================================================================
@@ -421,6 +624,11 @@ RULE OF THUMB:
   When you need more, switch to custom errors
   Don't over-engineer early
 `)
+
+	if failures > 0 {
+		fmt.Printf("%d checks failed\n", failures)
+		os.Exit(1)
+	}
 }
 
 // ============================================================================
@@ -442,26 +650,24 @@ func (d DemoError) Error() string {
 }
 
 // ============================================================================
-// CUSTOM ERROR TYPE 2: ValidationError - Domain-Specific
+// CUSTOM ERROR TYPE 2: ValidationError - Domain-Specific (errorx)
 // ============================================================================
 //
 // Used when input validation fails.
-// Holds information about which field failed and why.
-
-type ValidationError struct {
-	Field string // Which field failed?
-	Issue string // What's wrong with it?
-	Value string // What value was provided?
-}
-
-// Implement the error interface
-func (v ValidationError) Error() string {
-	return fmt.Sprintf("Validation Error: field '%s' %s (received: %q)",
-		v.Field, v.Issue, v.Value)
-}
+// Holds information about which field failed and why:
+//
+//   type ValidationError struct {
+//       Field string // Which field failed?
+//       Issue string // What's wrong with it?
+//       Value string // What value was provided?
+//   }
+//
+// errorx.ValidationError also has an Is method, so
+// errors.Is(err, errorx.ErrValidation) asks "was it a validation error?"
+// without caring which field.
 
 // ============================================================================
-// CUSTOM ERROR TYPE 3: WrappedError - With Original Error (KEY PATTERN)
+// CUSTOM ERROR TYPE 3: WrappedError - With Original Error (KEY PATTERN, errorx)
 // ============================================================================
 //
 // This is the MOST IMPORTANT pattern for professional Go code.
@@ -495,20 +701,11 @@ func (v ValidationError) Error() string {
 //
 // This is the COMPLETE picture: WHAT + HOW BAD + WHY
 //
+// And Unwrap() returns Err, so errors.Is(err, cause) still finds the root
+// cause under the context (Section 8b).
+//
 // ============================================================================
 
-type WrappedError struct {
-	Code    int    // HTTP-style code
-	Message string // Context message
-	Err     error  // The ORIGINAL error (the root cause)
-}
-
-// Implement the error interface
-func (w *WrappedError) Error() string {
-	// Format: "Error [Code]: [Message], caused by: [Original Error]"
-	return fmt.Sprintf("Error %d: %s, caused by: %v", w.Code, w.Message, w.Err)
-}
-
 // ============================================================================
 // HELPER FUNCTION 1: doSomethingElse - The "Inner" Function
 // ============================================================================
@@ -539,7 +736,7 @@ func doSomethingElse() error {
 //   if err != nil {
 //
 // Step 3: Create a new error that wraps the original
-//   return &WrappedError{
+//   return &errorx.WrappedError{
 //       Code:    500,           ← Our decision: This is a server error
 //       Message: "failed to save file",  ← Our context
 //       Err:     err,           ← Original error (preserved!)
@@ -569,7 +766,7 @@ func doSomething() error {
 	if err != nil {
 		// Instead of just returning the raw error,
 		// we wrap it in our custom error to add context
-		return &WrappedError{
+		return &errorx.WrappedError{
 			Code:    500,
 			Message: "failed to save file",
 			Err:     err, // Preserve the original error!
@@ -581,68 +778,63 @@ func doSomething() error {
 }
 
 // ============================================================================
-// CUSTOM ERROR TYPE 4: ValidationError (Domain-Specific)
+// HELPER FUNCTION 2b: saveReport - Wrapping With a Stack Trace
 // ============================================================================
+//
+// doSomething again, with errorx.WrapStack instead of a struct literal:
+// the error also remembers that saveReport made it, called from main, and
+// the file and line of each.
 
-type AuthError struct {
-	Reason  string // Why authentication failed
-	TokenID string // Which token had the issue
-}
-
-func (a AuthError) Error() string {
-	return fmt.Sprintf("Auth Error: %s (token: %s)", a.Reason, a.TokenID)
+func saveReport() error {
+	return errorx.WrapStack(500, "failed to save report", doSomethingElse())
 }
 
 // ============================================================================
-// CUSTOM ERROR TYPE 5: DatabaseError (With Helper Methods)
+// CUSTOM ERROR TYPE 4: AuthError (Domain-Specific, errorx)
 // ============================================================================
 //
-// This error type demonstrates helper methods for decision-making.
-
-type DatabaseError struct {
-	Operation string // SELECT, INSERT, UPDATE, DELETE
-	Table     string // Which table
-	Inner     error  // The original database error
-}
-
-func (d *DatabaseError) Error() string {
-	return fmt.Sprintf("Database Error: %s on table '%s', caused by: %v",
-		d.Operation, d.Table, d.Inner)
-}
-
-// Helper method: Can we retry this operation?
-func (d *DatabaseError) CanRetry() bool {
-	// Only retry SELECT operations (safe to retry reads)
-	// Don't retry writes (INSERT, UPDATE, DELETE) to avoid duplicates
-	return d.Operation == "SELECT"
-}
+//   type AuthError struct {
+//       Reason  string // Why authentication failed
+//       TokenID string // Which token had the issue
+//   }
+//
+// errors.Is(err, errorx.ErrAuth) matches any AuthError.
 
-// Helper method: Is this a timeout error?
-func (d *DatabaseError) IsTimeout() bool {
-	return d.Inner.Error() == "timeout"
-}
+// ============================================================================
+// CUSTOM ERROR TYPE 5: DatabaseError (With Helper Methods, errorx)
+// ============================================================================
+//
+// This error type demonstrates helper methods for decision-making:
+//
+//   CanRetry()  → only SELECT: a retried INSERT might insert twice
+//   IsTimeout() → the cause was a deadline (context or a net timeout)
+//
+// Unwrap() returns Inner, the driver's own error.
 
 // ============================================================================
 // HELPER FUNCTION 3: getUserByID - Demonstrates Real-World Error Handling
 // ============================================================================
 //
-// This simulates an API endpoint that fetches a user.
-// Shows how custom errors help in real applications.
+// An API endpoint that fetches a user. On error it doesn't print: it hands
+// the error to httpmap.Write, which picks the status from the error's
+// StatusCode method and writes the JSON body, and logs the cause under the
+// same request_id the client sees.
 
-func getUserByID(id string) *struct{ ID, Name string } {
-	// Validate input
+func getUserByID(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/users/")
 	if id == "" || id == "invalid-id" {
-		err := ValidationError{
-			Field: "id",
-			Issue: "invalid format",
-			Value: id,
-		}
-		fmt.Printf("getUserByID error: %v\n", err)
-		return nil
+		err := errorx.NewValidationError("id", "invalid format", id)
+		resp := httpmap.Write(w, r, err)
+		fmt.Printf("  log: request %s: %v\n", resp.RequestID, err)
+		return
 	}
 
 	// In real code, this would query a database
-	return &struct{ ID, Name string }{ID: id, Name: "John Doe"}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}{ID: id, Name: "John Doe"})
 }
 
 // ============================================================================
@@ -656,15 +848,15 @@ func handleErrorByType(operation string, err error) {
 
 	// Switch on error type
 	switch e := err.(type) {
-	case ValidationError:
+	case errorx.ValidationError:
 		fmt.Printf("  → Validation error on field '%s': %s\n", e.Field, e.Issue)
 		fmt.Println("  → Action: Return 400 Bad Request to client")
 
-	case AuthError:
+	case errorx.AuthError:
 		fmt.Printf("  → Auth error: %s\n", e.Reason)
 		fmt.Println("  → Action: Return 401 Unauthorized to client")
 
-	case *DatabaseError:
+	case *errorx.DatabaseError:
 		fmt.Printf("  → Database error: %s on %s\n", e.Operation, e.Table)
 		if e.CanRetry() {
 			fmt.Println("  → Action: Retry the operation")
@@ -678,6 +870,156 @@ func handleErrorByType(operation string, err error) {
 	}
 }
 
+// ============================================================================
+// HELPER FUNCTION 5: queryUser / loadProfile / handleGetUser - A Chain
+// ============================================================================
+//
+// Three layers of one request, each adding what it knows (Section 8b):
+//
+//   handleGetUser  → which request    (fmt.Errorf with %w)
+//   loadProfile    → how bad          (errorx.Wrap, code 500)
+//   queryUser      → which statement  (errorx.DatabaseError)
+//
+// ErrConnTimeout is a SENTINEL: one value, declared once, that errors.Is
+// compares against. Another errors.New with the same text is a different
+// error.
+
+var ErrConnTimeout = errors.New("connection timeout")
+
+func queryUser(id string) (string, error) {
+	if id == "7" {
+		return "Ada", nil
+	}
+	return "", errorx.NewDatabaseError("SELECT", "users", ErrConnTimeout)
+}
+
+func loadProfile(id string) (string, error) {
+	name, err := queryUser(id)
+	if err != nil {
+		return "", errorx.Wrap(500, "failed to load profile", err)
+	}
+	return name, nil
+}
+
+func handleGetUser(id string) error {
+	if id == "" {
+		return fmt.Errorf("GET /users/: %w", errorx.NewValidationError("id", "is empty", id))
+	}
+	if _, err := loadProfile(id); err != nil {
+		return fmt.Errorf("GET /users/%s: %w", id, err)
+	}
+	return nil
+}
+
+// ============================================================================
+// HELPER FUNCTION 6: errorChain - Walking the Chain by Hand
+// ============================================================================
+//
+// errors.Unwrap returns the next error down, or nil at the bottom (or at a
+// Join, which has several). errors.Is and errors.As do this same walk for
+// you; errorChain shows each step, with the type of each layer.
+
+func errorChain(err error) []string {
+	var layers []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		layers = append(layers, fmt.Sprintf("%T: %v", err, err))
+	}
+	return layers
+}
+
+// ============================================================================
+// HELPER FUNCTION 7: statusFor - Deciding With errors.Is / errors.As
+// ============================================================================
+//
+// The Section 0 promise: the right HTTP status, automatically, however
+// many layers of context sit on top. Kinds first (errorx.ErrValidation,
+// errorx.ErrAuth), then the code a WrappedError carries. httpmap.Status,
+// which Section 6's handler uses, asks the error's StatusCode method
+// instead and lands on the same numbers for these.
+
+func statusFor(err error) int {
+	var wrapped *errorx.WrappedError
+	switch {
+	case err == nil:
+		return 200
+	case errors.Is(err, errorx.ErrValidation):
+		return 422
+	case errors.Is(err, errorx.ErrAuth):
+		return 401
+	case errors.As(err, &wrapped):
+		return wrapped.Code
+	}
+	return 500
+}
+
+// ============================================================================
+// HELPER FUNCTION 8: validateSignup - Several Errors at Once
+// ============================================================================
+//
+// Reporting every bad field, not just the first, with errors.Join. Join
+// drops nils, and a Join of only nils is nil, so "no problems" stays nil.
+
+func validateSignup(email, password string) error {
+	var emailErr, passwordErr error
+	if !strings.Contains(email, "@") {
+		emailErr = errorx.NewValidationError("email", "invalid format", email)
+	}
+	if len(password) < 8 {
+		passwordErr = errorx.NewValidationError("password", "is shorter than 8 characters", password)
+	}
+	return errors.Join(emailErr, passwordErr)
+}
+
+// ============================================================================
+// HELPER FUNCTION 9: SignupForm.Validate - Collecting With MultiError
+// ============================================================================
+//
+// One check per field, every failure appended, none short-circuiting the
+// rest. Returning errs.Err(), not &errs, matters: a *MultiError holding
+// nothing is still a non-nil error, and "if err != nil" would trip on it.
+
+type SignupForm struct {
+	Email    string
+	Password string
+	Age      int
+	Country  string
+}
+
+func (f SignupForm) Validate() error {
+	var errs errorx.MultiError
+	if !strings.Contains(f.Email, "@") {
+		errs.Append(errorx.NewValidationError("email", "invalid format", f.Email))
+	}
+	if len(f.Password) < 8 {
+		errs.Append(errorx.NewValidationError("password", "is shorter than 8 characters", f.Password))
+	}
+	if f.Age < 13 {
+		errs.Append(errorx.NewValidationError("age", "is under 13", fmt.Sprint(f.Age)))
+	}
+	if len(f.Country) != 2 {
+		errs.Append(errorx.NewValidationError("country", "is not a 2-letter code", f.Country))
+	}
+	return errs.Err()
+}
+
+// ============================================================================
+// HELPER FUNCTION 10: check - A Runnable Assertion
+// ============================================================================
+//
+// Prints ✓ or ✗; any ✗ makes the lesson exit 1, so a change to errorx that
+// breaks what this lesson claims shows up as a failed run.
+
+var failures int
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("  ✓", pass)
+		return
+	}
+	fmt.Println("  ✗", fail)
+	failures++
+}
+
 // ============================================================================
 // COMPREHENSIVE PATTERN EXAMPLES
 // ============================================================================
@@ -699,15 +1041,21 @@ func handleErrorByType(operation string, err error) {
 //   }
 //
 // Pattern 3: Type Assertion (Extract Information)
-//   if ve, ok := err.(ValidationError); ok {
+//   if ve, ok := err.(errorx.ValidationError); ok {
 //       fmt.Printf("Field: %s, Issue: %s\n", ve.Field, ve.Issue)
 //   }
 //
 // Pattern 4: Helper Methods (Decision Making)
-//   if dbErr, ok := err.(*DatabaseError); ok {
+//   var dbErr *errorx.DatabaseError
+//   if errors.As(err, &dbErr) {  // Finds it under any wrapping
 //       if dbErr.CanRetry() {
 //           retry()
 //       }
 //   }
 //
+// Pattern 5: Sentinel Check (Through Every Layer)
+//   if errors.Is(err, ErrConnTimeout) {
+//       // Same value, however deep; == would only see the top layer
+//   }
+//
 // ============================================================================
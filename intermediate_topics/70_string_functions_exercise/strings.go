@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Topic 70 (exercise): String Functions
+// ====================================
+//
+// Three functions to write with the strings and unicode packages, after
+// reading 70_string_functions_detailed.go. strings_test.go says what each
+// must do; run it with
+//
+//	go test .          (or: gotut -dir .. test 70)
+//
+// Stuck? gotut -dir .. hint 70. Done, or done trying? Compare with the
+// reference:
+//
+//	gotut -dir .. solution 70 --diff             the whole file
+//	gotut -dir .. solution 70 --diff --failed    only what fails its test
+
+var _ = strings.Fields // Delete once you use strings
+
+// Initials returns the first letter of each word, upper-cased:
+// "ada lovelace" gives "AL", "  émile   zola " gives "ÉZ".
+func Initials(name string) string {
+	return "" // TODO
+}
+
+// Slugify makes a title URL-safe: lower case, runs of anything but
+// letters and digits become one "-", none at either end.
+// "Hello, World!  Go 1.22" gives "hello-world-go-1-22".
+func Slugify(title string) string {
+	return title // TODO
+}
+
+// Truncate shortens s to at most n characters (runes, not bytes), ending
+// in "…" when it had to cut: Truncate("gopher", 4) is "gop…".
+func Truncate(s string, n int) string {
+	return s // TODO
+}
+
+func main() {
+	fmt.Println(Initials("ada lovelace"))
+	fmt.Println(Slugify("Hello, World!  Go 1.22"))
+	fmt.Println(Truncate("gopher", 4))
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestInitials(t *testing.T) {
+	tests := map[string]string{
+		"ada lovelace":     "AL",
+		"  émile   zola ":  "ÉZ",
+		"grace":            "G",
+		"":                 "",
+		"ken\tthompson\nx": "KTX",
+	}
+	for in, want := range tests {
+		if got := Initials(in); got != want {
+			t.Errorf("Initials(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Hello, World!  Go 1.22": "hello-world-go-1-22",
+		"  --Already-Slugged-- ": "already-slugged",
+		"Crème Brûlée":           "crème-brûlée",
+		"!!!":                    "",
+	}
+	for in, want := range tests {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"gopher", 4, "gop…"},
+		{"gopher", 6, "gopher"},
+		{"gopher", 10, "gopher"},
+		{"héllo wörld", 5, "héll…"}, // Runes, not bytes
+		{"日本語のテキスト", 3, "日本…"},
+		{"go", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/tmplfuncs"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/tmplreg"
 )
 
 // Topic 72: text_templates - Complete Breakdown
@@ -17,34 +27,66 @@ import (
 // Part 3: Loops - Range with the Shape-Shifting Dot
 // Part 4: The CLI Menu App - Architecture with template storage
 // Part 5: Key Terms Reference - os.Stdout, bytes.Buffer, FuncMap, {{with}}
+// Part 6: FuncMap in Action - upper, title, date, currency, pluralize, truncate
+// Part 7: Hot Reload - templates/*.tmpl re-parsed when the files change
+//
+// Part 4's template storage is go_projects/pkg/tmplreg, a registry that
+// is safe to share between goroutines, and Part 7 uses its WatchDir;
+// Part 6's functions are go_projects/pkg/tmplfuncs. The imports resolve
+// through the go.work at the repository root:
+//
+//	go run 72_text_templates_detailed.go
 
 func main() {
 	fmt.Println("=== 72 TEXT TEMPLATES: Complete Breakdown ===\n")
 
-	// ============================================================
-	// PART 1: THE BASICS - Simple Substitution
-	// ============================================================
-	part1Basics()
-
-	// ============================================================
-	// PART 2: LOGIC INSIDE TEMPLATES - If/Else
-	// ============================================================
-	part2LogicConditionals()
-
-	// ============================================================
-	// PART 3: LOOPS - Range with Shape-Shifting Dot
-	// ============================================================
-	part3Loops()
-
-	// ============================================================
-	// PART 4: THE CLI MENU APP - Complete Architecture
-	// ============================================================
-	part4CLIMenuApp()
-
-	// ============================================================
-	// PART 5: KEY TERMS REFERENCE
-	// ============================================================
-	part5KeyTermsReference()
+	section.Run(sections)
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basics", Run: func() {
+		// ============================================================
+		// PART 1: THE BASICS - Simple Substitution
+		// ============================================================
+		part1Basics()
+	}},
+	{Name: "logic-conditionals", Run: func() {
+		// ============================================================
+		// PART 2: LOGIC INSIDE TEMPLATES - If/Else
+		// ============================================================
+		part2LogicConditionals()
+	}},
+	{Name: "loops", Run: func() {
+		// ============================================================
+		// PART 3: LOOPS - Range with Shape-Shifting Dot
+		// ============================================================
+		part3Loops()
+	}},
+	{Name: "cli-menu-app", Run: func() {
+		// ============================================================
+		// PART 4: THE CLI MENU APP - Complete Architecture
+		// ============================================================
+		part4CLIMenuApp()
+	}},
+	{Name: "key-terms-reference", Run: func() {
+		// ============================================================
+		// PART 5: KEY TERMS REFERENCE
+		// ============================================================
+		part5KeyTermsReference()
+	}},
+	{Name: "funcmap", Run: func() {
+		// ============================================================
+		// PART 6: FUNCMAP IN ACTION
+		// ============================================================
+		part6FuncMap()
+	}},
+	{Name: "hot-reload", Run: func() {
+		// ============================================================
+		// PART 7: HOT RELOAD FROM DISK
+		// ============================================================
+		part7HotReload()
+	}},
 }
 
 // ============================================================
@@ -440,46 +482,51 @@ Why structure it this way?
 	fmt.Println("\n📝 THE CODE (The 'How'):")
 	fmt.Println("=========================\n")
 
-	fmt.Println("STEP 1: THE SETUP - Pre-load templates into a storage map")
-	fmt.Println("=========================================================\n")
+	fmt.Println("STEP 1: THE SETUP - Pre-load templates into a registry")
+	fmt.Println("=======================================================\n")
 
 	code1 := `
-	// Create an empty map to store templates
-	// Key = template name (string)
-	// Value = the compiled template (*template.Template)
-	parsedTemplates := make(map[string]*template.Template)
-
-	// Fill the map with templates
-	parsedTemplates["welcome"] = template.Must(
-		template.New("welcome").Parse(
-			"🎉 Welcome, {{.Name}}! Your total: ${{.Total}}\n"))
-
-	parsedTemplates["goodbye"] = template.Must(
-		template.New("goodbye").Parse(
-			"👋 Goodbye {{.Name}}! Thanks for ${{.Amount}}.\n"))
-
-	parsedTemplates["error"] = template.Must(
-		template.New("error").Parse(
-			"⚠️  Error: {{.Message}}\n"))
+	// The storage is a map from name to compiled template. Written
+	// inline it is
+	//
+	//     parsedTemplates := make(map[string]*template.Template)
+	//     parsedTemplates["welcome"] = template.Must(template.New("welcome").Parse(...))
+	//
+	// which is fine until a web server reads it from 1000 goroutines
+	// while something else adds a template: a data race. tmplreg wraps
+	// the same map with a sync.RWMutex: many readers at once, one writer.
+	menu := tmplreg.New()
+
+	// Register parses once, and reports a broken template now, at startup
+	for name, text := range map[string]string{
+		"welcome": "🎉 Welcome, {{.Name}}! Your total: ${{.Total}}\n",
+		"goodbye": "👋 Goodbye {{.Name}}! Thanks for ${{.Amount}}.\n",
+		"error":   "⚠️  Error: {{.Message}}\n",
+	} {
+		if err := menu.Register(name, text); err != nil {
+			log.Fatal(err)
+		}
+	}
 	`
 	fmt.Println(code1)
 
 	// LIVE: Set up templates
-	parsedTemplates := make(map[string]*template.Template)
-
-	parsedTemplates["welcome"] = template.Must(
-		template.New("welcome").Parse(
-			"🎉 Welcome, {{.Name}}! Your total: ${{.Total}}\n"))
-
-	parsedTemplates["goodbye"] = template.Must(
-		template.New("goodbye").Parse(
-			"👋 Goodbye {{.Name}}! Thanks for ${{.Amount}}.\n"))
+	menu := tmplreg.New()
+	for name, text := range map[string]string{
+		"welcome": "🎉 Welcome, {{.Name}}! Your total: ${{.Total}}\n",
+		"goodbye": "👋 Goodbye {{.Name}}! Thanks for ${{.Amount}}.\n",
+		"error":   "⚠️  Error: {{.Message}}\n",
+	} {
+		if err := menu.Register(name, text); err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+	}
 
-	parsedTemplates["error"] = template.Must(
-		template.New("error").Parse(
-			"⚠️  Error: {{.Message}}\n"))
+	fmt.Printf("✅ Step 1 Complete: %d templates are now loaded in memory (parsed & ready): %v\n\n", len(menu.Names()), menu.Names())
 
-	fmt.Println("✅ Step 1 Complete: 3 templates are now loaded in memory (parsed & ready)\n")
+	err := menu.Register("broken", "Hi {{.Name")
+	fmt.Printf("A typo is caught by Register, not by the first user:\n  %v\n\n", err)
 
 	fmt.Println("\nSTEP 2: THE USER INPUT - Listen to keyboard (Simulated)")
 	fmt.Println("=======================================================\n")
@@ -511,12 +558,14 @@ Why structure it this way?
 		{"welcome", map[string]interface{}{"Name": "Alice", "Total": 42.50}},
 		{"goodbye", map[string]interface{}{"Name": "Bob", "Amount": 25.00}},
 		{"error", map[string]interface{}{"Message": "Invalid card"}},
+		{"refund", nil}, // Not a template: the user mistyped
 	}
 
 	// For each choice, look it up and execute
 	for _, c := range choices {
-		if tmpl, exists := parsedTemplates[c.choice]; exists {
-			tmpl.Execute(os.Stdout, c.data)
+		err := menu.RenderTo(os.Stdout, c.choice, c.data)
+		if errors.Is(err, tmplreg.ErrNotFound) {
+			fmt.Println("No such option:", err)
 		}
 	}
 	`
@@ -532,19 +581,47 @@ Why structure it this way?
 		{"welcome", map[string]interface{}{"Name": "Alice", "Total": 42.50}},
 		{"goodbye", map[string]interface{}{"Name": "Bob", "Amount": 25.00}},
 		{"error", map[string]interface{}{"Message": "Invalid card"}},
+		{"refund", nil},
 	}
 
 	for _, c := range choices {
 		fmt.Printf("User pressed: '%s'\n", c.choice)
 		fmt.Print("Output: ")
-		if tmpl, exists := parsedTemplates[c.choice]; exists {
-			tmpl.Execute(os.Stdout, c.data)
-		} else {
-			fmt.Printf("Template '%s' not found\n", c.choice)
+		err := menu.RenderTo(os.Stdout, c.choice, c.data)
+		if errors.Is(err, tmplreg.ErrNotFound) {
+			fmt.Println("No such option:", err)
+		} else if err != nil {
+			fmt.Println("❌", err)
 		}
 		fmt.Println()
 	}
 
+	fmt.Println("STEP 4: MANY USERS AT ONCE - Why the RWMutex")
+	fmt.Println("============================================\n")
+	fmt.Println(`
+A server renders for every request in its own goroutine. Reads of a
+plain map from many goroutines are fine; ONE write at the same time (a
+template added or replaced while serving) is a data race, and Go may
+crash with "concurrent map read and map write". tmplreg's RenderTo takes
+a read lock, Register the write lock; go run -race finds nothing.
+`)
+	var wg sync.WaitGroup
+	results := make([]string, 50)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = menu.MustRender("goodbye", map[string]interface{}{"Name": fmt.Sprint("user", i), "Amount": i})
+		}()
+	}
+	// Meanwhile, a new template arrives.
+	if err := menu.Register("thanks", "🙏 Thanks, {{.Name}}!\n"); err != nil {
+		fmt.Println("❌", err)
+	}
+	wg.Wait()
+	fmt.Printf("50 goroutines rendered %q ... %q\n", strings.TrimSpace(results[0]), strings.TrimSpace(results[49]))
+	fmt.Printf("while %q was registered: now %v\n", "thanks", menu.Names())
+
 	fmt.Println("\n💡 PERFORMANCE EXPLANATION:")
 	fmt.Println("============================")
 	fmt.Println(`
@@ -638,6 +715,8 @@ Common custom functions:
   "lower"     → "HELLO" → "hello"
   "reverse"   → "abc" → "cba"
   "repeat"    → "a" × 5 → "aaaaa"
+
+Part 6 runs templates with a ready-made set of them: tmplfuncs.Default().
 `)
 
 	fmt.Println("\n📌 {{with .Field}} (Spotlight shortcut)")
@@ -726,5 +805,272 @@ What is os.Stdin?
 	fmt.Println("✅ PART 3: The dot (.) changes meaning in loops. Use {{range}} and {{with}} carefully.")
 	fmt.Println("✅ PART 4: Pre-parse templates, execute fast. This is the universal web server pattern.")
 	fmt.Println("✅ PART 5: os.Stdout, bytes.Buffer, FuncMap, {{with}} are powerful tools.")
-	fmt.Println("\n🎯 Master these 5 parts, and you master Go text templates.\n")
+	fmt.Println("✅ PART 6: tmplfuncs.Default() gives every template upper, title, date, currency and more.")
+	fmt.Println("✅ PART 7: tmplreg.WatchDir loads templates/*.tmpl and reloads them when they change.")
+	fmt.Println("\n🎯 Master these 7 parts, and you master Go text templates.\n")
+}
+
+// ============================================================
+// PART 6: FUNCMAP IN ACTION
+// ============================================================
+
+// order is the data Part 6's templates format.
+type order struct {
+	Customer string
+	Placed   time.Time
+	Items    []orderItem
+	Note     string
+}
+
+type orderItem struct {
+	Name  string
+	Qty   int
+	Price float64
+}
+
+// Total is a method, so templates can call it as {{.Total}}.
+func (o order) Total() float64 {
+	var t float64
+	for _, it := range o.Items {
+		t += float64(it.Qty) * it.Price
+	}
+	return t
+}
+
+func part6FuncMap() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("PART 6: FUNCMAP IN ACTION")
+	fmt.Println(strings.Repeat("=", 70) + "\n")
+
+	fmt.Println("📌 THE CONCEPT (The 'What'):")
+	fmt.Println("============================")
+	fmt.Println(`
+Part 5 described FuncMap. Here it runs. go_projects/pkg/tmplfuncs
+has the functions most templates end up writing for themselves:
+
+  upper, lower, title, trim     text
+  repeat 3 "ab"                 ababab
+  date "2 Jan 2006" .When       a time.Time in any layout ("DateOnly" too)
+  currency 1234.5               $1,234.50
+  pluralize .Count "file"       file or files
+  truncate 20 .Body             at most 20 characters, ending in …
+
+Two rules to know:
+  1. Funcs BEFORE Parse. The parser rejects {{currency .X}} if it
+     has never heard of currency.
+  2. The VALUE comes LAST, so it can arrive through a pipe:
+     {{.Note | truncate 24}} is {{truncate 24 .Note}}.
+`)
+
+	fmt.Println("\n📝 THE CODE (The 'How'):")
+	fmt.Println("=========================\n")
+
+	receipt := `Receipt for {{.Customer | title}} — {{.Placed | date "Mon 2 Jan 2006"}}
+{{repeat 44 "─"}}
+{{range .Items}}{{printf "%-20s" (.Name | title)}} {{printf "%3d" .Qty}} {{printf "%-5s" (pluralize .Qty "unit")}} {{printf "%11s" (currency .Price)}}
+{{end}}{{repeat 44 "─"}}
+{{len .Items}} {{pluralize (len .Items) "line"}}, total {{currency .Total}}
+Note: {{.Note | trim | truncate 30}}
+`
+	fmt.Println("STEP 1: Funcs(tmplfuncs.Default()), then Parse")
+	fmt.Println("==============================================")
+	fmt.Println(`
+  tmpl := template.Must(template.New("receipt").
+      Funcs(tmplfuncs.Default()).
+      Parse(receipt))`)
+	fmt.Println("\nThe template:")
+	fmt.Print(receipt)
+
+	tmpl := template.Must(template.New("receipt").Funcs(tmplfuncs.Default()).Parse(receipt))
+	o := order{
+		Customer: "ada lovelace",
+		Placed:   time.Date(2026, 3, 9, 14, 30, 0, 0, time.UTC),
+		Items: []orderItem{
+			{"analytical engine", 1, 12500},
+			{"punch card", 300, 0.25},
+			{"brass gear", 12, 3.5},
+		},
+		Note: "  Please deliver to the back door, the front one sticks.  ",
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, o); err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	fmt.Println("\nOUTPUT:")
+	fmt.Print(out.String())
+	check(strings.Contains(out.String(), "Receipt for Ada Lovelace — Mon 9 Mar 2026") &&
+		strings.Contains(out.String(), "1 unit   $12,500.00") &&
+		strings.Contains(out.String(), "total $12,617.00"),
+		"title, date, pluralize and currency did the formatting, not Go code")
+
+	fmt.Println("\nSTEP 2: A function that returns an error stops Execute")
+	fmt.Println("======================================================")
+	fmt.Println(`
+  currency returns (string, error). Given something that isn't a
+  number, its error becomes Execute's — the template doesn't print
+  "$NaN" and carry on.`)
+	bad := template.Must(template.New("bad").Funcs(tmplfuncs.Default()).Parse("Total: {{currency .}}\n"))
+	err := bad.Execute(&strings.Builder{}, "twelve")
+	fmt.Println("\n  Execute(..., \"twelve\") →", err)
+	check(err != nil && strings.Contains(err.Error(), "not a number"), "the function's error came back from Execute")
+
+	fmt.Println("\nSTEP 3: Parse without Funcs fails, at parse time")
+	fmt.Println("=================================================")
+	_, err = template.New("nofuncs").Parse("{{currency 5}}")
+	fmt.Println("\n  Parse(\"{{currency 5}}\") →", err)
+	check(err != nil && strings.Contains(err.Error(), `function "currency" not defined`),
+		"unknown functions are caught before anything runs")
+
+	fmt.Println("\nSTEP 4: Add your own on top")
+	fmt.Println("===========================")
+	fmt.Println(`
+  Default() returns a NEW map each call, so adding to it is safe:
+
+  funcs := tmplfuncs.Default()
+  funcs["initials"] = func(s string) string { ... }`)
+	funcs := tmplfuncs.Default()
+	funcs["initials"] = func(s string) string {
+		var b strings.Builder
+		for _, w := range strings.Fields(s) {
+			b.WriteString(strings.ToUpper(w[:1]))
+		}
+		return b.String()
+	}
+	sig := template.Must(template.New("sig").Funcs(funcs).Parse(`{{.Customer | initials}} / {{.Customer | upper}}`))
+	out.Reset()
+	sig.Execute(&out, o)
+	fmt.Println("\n  {{.Customer | initials}} / {{.Customer | upper}} →", out.String())
+	check(out.String() == "AL / ADA LOVELACE", "the built-ins and your own sit in one FuncMap")
+
+	fmt.Println("\n✅ KEY TAKEAWAY:")
+	fmt.Println("Formatting belongs in the template: register functions with Funcs before Parse, and let the value come last so it pipes.\n")
+}
+
+// ============================================================
+// PART 7: HOT RELOAD FROM DISK
+// ============================================================
+
+func part7HotReload() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("PART 7: HOT RELOAD FROM DISK")
+	fmt.Println(strings.Repeat("=", 70) + "\n")
+
+	fmt.Println("📌 THE CONCEPT (The 'What'):")
+	fmt.Println("============================")
+	fmt.Println(`
+Part 4 wrote its templates inside the Go code: changing a word means
+rebuilding and restarting. Real programs keep them in FILES:
+
+  templates/
+    welcome.tmpl    → the template "welcome"
+    receipt.tmpl    → the template "receipt"
+    footer.tmpl     → {{define "footer"}}...{{end}}, used by the others
+
+tmplreg.WatchDir("templates") parses them all with template.ParseGlob
+(so every file can use what another {{define}}s), and w.Run polls the
+directory: when a file's size or modification time changes, the whole
+directory is parsed again and swapped in.
+
+Two safety rules:
+  1. ALL OR NOTHING. If any file fails to parse (you saved half an
+     edit), the old templates stay. Users never see a broken page.
+  2. RENDERS DON'T WAIT. The swap happens under the registry's lock;
+     a render in progress finishes with the templates it started with.
+
+Why polling, not OS notifications (fsnotify)? It needs no library,
+and works everywhere — network drives, containers, editors that save
+by renaming. The price is up to one interval's delay.
+`)
+
+	fmt.Println("\n📝 THE CODE (The 'How'):")
+	fmt.Println("=========================\n")
+
+	dir, err := os.MkdirTemp("", "templates_*")
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, text string) {
+		os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644)
+	}
+	write("welcome.tmpl", "Welcome, {{.Name}}!{{template \"footer\"}}\n")
+	write("footer.tmpl", `{{define "footer"}} — The Corner Shop{{end}}`)
+
+	fmt.Println("STEP 1: Load the directory, start watching")
+	fmt.Println("==========================================")
+	fmt.Println(`
+  w, err := tmplreg.WatchDir("templates")
+  if err != nil {
+      log.Fatal(err)    // A broken template at startup is a real error
+  }
+  go w.Run(ctx, 50*time.Millisecond, func(err error) {
+      log.Println("reload:", err)    // nil means the new templates are in
+  })`)
+	w, err := tmplreg.WatchDir(dir)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan error, 10)
+	go w.Run(ctx, 50*time.Millisecond, func(err error) { reloads <- err })
+	// wait plays the person watching the log after saving a file.
+	wait := func() error {
+		select {
+		case err := <-reloads:
+			return err
+		case <-time.After(5 * time.Second):
+			return errors.New("no reload seen")
+		}
+	}
+	data := map[string]string{"Name": "Alice"}
+	fmt.Printf("\n  templates: %v\n", w.Names())
+	fmt.Print("  welcome → ", w.MustRender("welcome", data))
+
+	fmt.Println("\nSTEP 2: Edit a file while the program runs")
+	fmt.Println("==========================================")
+	write("welcome.tmpl", "Good morning, {{.Name}}! Fresh bread today.{{template \"footer\"}}\n")
+	err = wait()
+	fmt.Println("\n  (saved welcome.tmpl)  reload:", err)
+	fmt.Print("  welcome → ", w.MustRender("welcome", data))
+	check(err == nil && strings.HasPrefix(w.MustRender("welcome", data), "Good morning"),
+		"the next render used the new text: no rebuild, no restart")
+
+	fmt.Println("\nSTEP 3: Save a typo")
+	fmt.Println("===================")
+	write("welcome.tmpl", "Good morning, {{.Name!\n")
+	err = wait()
+	fmt.Println("\n  (saved a broken welcome.tmpl)  reload:", err)
+	fmt.Print("  welcome → ", w.MustRender("welcome", data))
+	check(err != nil && strings.HasPrefix(w.MustRender("welcome", data), "Good morning, Alice! Fresh bread"),
+		"the error is reported and the last good templates keep serving")
+
+	fmt.Println("\nSTEP 4: Fix it, and add a new file")
+	fmt.Println("==================================")
+	write("welcome.tmpl", "Good morning, {{.Name}}!{{template \"footer\"}}\n")
+	write("goodbye.tmpl", "See you soon, {{.Name}}.{{template \"footer\"}}\n")
+	err = wait()
+	for err == nil && !slices.Contains(w.Names(), "goodbye") {
+		err = wait() // A poll between the two saves sees only the first
+	}
+	fmt.Println("\n  (fixed welcome.tmpl, added goodbye.tmpl)  reload:", err)
+	fmt.Printf("  templates: %v\n", w.Names())
+	fmt.Print("  goodbye → ", w.MustRender("goodbye", data))
+	check(err == nil && slices.Contains(w.Names(), "goodbye"),
+		"a new file is a new template, and it can use footer.tmpl's {{define}}")
+
+	fmt.Println("\n✅ KEY TAKEAWAY:")
+	fmt.Println("Keep templates in files, parse them together, and reload all-or-nothing: a bad save never replaces good templates.\n")
+}
+
+// check prints whether a Part 6 or Part 7 claim held.
+func check(ok bool, what string) {
+	if ok {
+		fmt.Println("  ✓", what)
+	} else {
+		fmt.Println("  ✗ NOT:", what)
+	}
 }
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/passcheck"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/rxcache"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/rxstream"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 // ============================================================
@@ -15,40 +22,59 @@ import (
 // Use MatchString to check, FindString/FindAllString to extract,
 // ReplaceAllString to substitute, and FindAllStringSubmatch for
 // capturing groups.
+//
+// Part 5's password check is go_projects/pkg/passcheck; Part 6's cache
+// for runtime patterns is go_projects/pkg/rxcache, and its streaming
+// matcher for big inputs go_projects/pkg/rxstream. The imports resolve
+// through the go.work at the repository root:
+//
+//	go run 73_regex_comprehensive.go -section best-practices
 // ============================================================
 
 func main() {
 	fmt.Println("=== GO REGEX: Comprehensive Guide ===\n")
 
-	// ============================================================
-	// PART 1: BASICS - Does a pattern exist? (MatchString)
-	// ============================================================
-	part1MatchString()
-
-	// ============================================================
-	// PART 2: FINDING - Extract data from text
-	// ============================================================
-	part2FindingPatterns()
-
-	// ============================================================
-	// PART 3: REPLACING - Modify matched patterns
-	// ============================================================
-	part3ReplacingPatterns()
-
-	// ============================================================
-	// PART 4: CAPTURING - Extract specific parts (Groups)
-	// ============================================================
-	part4CapturingGroups()
-
-	// ============================================================
-	// PART 5: PRACTICAL EXAMPLES - Real-world use cases
-	// ============================================================
-	part5PracticalExamples()
-
-	// ============================================================
-	// PART 6: PERFORMANCE & BEST PRACTICES
-	// ============================================================
-	part6BestPractices()
+	section.Run(sections)
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "match-string", Run: func() {
+		// ============================================================
+		// PART 1: BASICS - Does a pattern exist? (MatchString)
+		// ============================================================
+		part1MatchString()
+	}},
+	{Name: "finding-patterns", Run: func() {
+		// ============================================================
+		// PART 2: FINDING - Extract data from text
+		// ============================================================
+		part2FindingPatterns()
+	}},
+	{Name: "replacing-patterns", Run: func() {
+		// ============================================================
+		// PART 3: REPLACING - Modify matched patterns
+		// ============================================================
+		part3ReplacingPatterns()
+	}},
+	{Name: "capturing-groups", Run: func() {
+		// ============================================================
+		// PART 4: CAPTURING - Extract specific parts (Groups)
+		// ============================================================
+		part4CapturingGroups()
+	}},
+	{Name: "practical-examples", Run: func() {
+		// ============================================================
+		// PART 5: PRACTICAL EXAMPLES - Real-world use cases
+		// ============================================================
+		part5PracticalExamples()
+	}},
+	{Name: "best-practices", Run: func() {
+		// ============================================================
+		// PART 6: PERFORMANCE & BEST PRACTICES
+		// ============================================================
+		part6BestPractices()
+	}},
 }
 
 // ============================================================
@@ -469,6 +495,7 @@ if len(matches) > 0 {
 
 	fmt.Println("\n✅ KEY TAKEAWAY:")
 	fmt.Println("Use FindAllStringSubmatch for groups. matches[i][0] is full match, [i][j] is group j.")
+	fmt.Println("Groups by name instead of number, (?P<year>...): 73_regex_detailed.go, Section 7.")
 }
 
 // ============================================================
@@ -482,23 +509,32 @@ func part5PracticalExamples() {
 
 	fmt.Println("📌 EXAMPLE 1: Validate a password\n")
 
-	code1 := `
-// Password must have: uppercase, lowercase, digit, 8+ chars
-passwordRegex := regexp.MustCompile("^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$")
+	fmt.Println("The pattern you find online for \"upper, lower, digit, 8+ chars\" is")
+	fmt.Println("  ^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$")
+	fmt.Println("and it doesn't compile in Go. (?=...) is a lookahead, and RE2 has none:")
+	fmt.Println("they need backtracking, which is what RE2 gives up to guarantee linear time.\n")
+
+	_, err := regexp.Compile(`^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$`)
+	fmt.Printf("regexp.Compile: %v\n\n", err)
+
+	fmt.Println("Checking each rule in Go is shorter anyway, and can tell the user which")
+	fmt.Println("rule failed. go_projects/pkg/passcheck does that, plus a deny-list:\n")
 
+	code1 := `
+// passcheck.Default: 8-64 characters, upper, lower, digit, not a common password
 passwords := []string{
     "ValidPass123",   // ✓ has upper, lower, digit, 8+ chars
-    "invalid",        // ✗ no upper, no digit
+    "invalid",        // ✗ too short, no upper, no digit
     "Pass1",          // ✗ too short
+    "Password123!",   // ✗ "password" with a number on the end
 }
 
 for _, pwd := range passwords {
-    valid := passwordRegex.MatchString(pwd)
-    status := "❌"
-    if valid {
-        status = "✓"
+    if err := passcheck.Default.Check(pwd); err != nil {
+        fmt.Printf("❌ %q: %s\n", pwd, strings.ReplaceAll(err.Error(), "\n", "; "))
+        continue
     }
-    fmt.Printf("%s %q is valid\n", status, pwd)
+    fmt.Printf("✓ %q is valid\n", pwd)
 }
 	`
 	fmt.Println("```go")
@@ -507,20 +543,19 @@ for _, pwd := range passwords {
 
 	fmt.Println("\n🔄 LIVE EXECUTION:\n")
 
-	passwordRegex := regexp.MustCompile("^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$")
 	passwords := []string{
 		"ValidPass123",
 		"invalid",
 		"Pass1",
+		"Password123!",
 	}
 
 	for _, pwd := range passwords {
-		valid := passwordRegex.MatchString(pwd)
-		status := "❌"
-		if valid {
-			status = "✓"
+		if err := passcheck.Default.Check(pwd); err != nil {
+			fmt.Printf("❌ %q: %s\n", pwd, strings.ReplaceAll(err.Error(), "\n", "; "))
+			continue
 		}
-		fmt.Printf("%s %q is valid\n", status, pwd)
+		fmt.Printf("✓ %q is valid\n", pwd)
 	}
 
 	fmt.Println("\n\n📌 EXAMPLE 2: Extract URLs from text\n")
@@ -579,13 +614,93 @@ fmt.Println("Censored:", censored)
 	fmt.Printf("Censored: %s\n", censored)
 
 	fmt.Println("\n✅ KEY TAKEAWAY:")
-	fmt.Println("Regex solves password validation, URL extraction, and data censoring elegantly.")
+	fmt.Println("Regex solves URL extraction and data censoring elegantly. A password policy is")
+	fmt.Println("several rules, not one pattern: check them in Go, and say which one failed.")
 }
 
 // ============================================================
 // PART 6: PERFORMANCE & BEST PRACTICES
 // ============================================================
 
+// cacheDemo serves 3,000 "requests" whose filters are five patterns
+// users keep typing, compiling each one with regexp.Compile and then
+// through an rxcache.Cache, and times both.
+func cacheDemo() {
+	filters := []string{
+		`(?i)error|fatal`,
+		`timeout after \d+ms`,
+		`^\d{4}-\d{2}-\d{2}T`,
+		`user=(\w+) status=5\d\d`,
+		`(?i)disk (full|quota)`,
+	}
+	line := "2026-10-16T09:00:00Z user=ada status=503 timeout after 1500ms"
+	const requests = 3000
+
+	serve := func(compile func(string) (*regexp.Regexp, error)) (time.Duration, int) {
+		matched := 0
+		start := time.Now()
+		for i := 0; i < requests; i++ {
+			rx, err := compile(filters[i%len(filters)])
+			if err != nil {
+				panic(err)
+			}
+			if rx.MatchString(line) {
+				matched++
+			}
+		}
+		return time.Since(start), matched
+	}
+
+	cache := rxcache.New(64)
+	plain, m1 := serve(regexp.Compile)
+	cached, m2 := serve(cache.Compile)
+	s := cache.Stats()
+
+	fmt.Printf("%d requests, %d distinct filters:\n", requests, len(filters))
+	fmt.Printf("  regexp.Compile each time:  %8v\n", plain.Round(time.Microsecond))
+	fmt.Printf("  rxcache:                   %8v  (%d misses, %d hits)\n", cached.Round(time.Microsecond), s.Misses, s.Hits)
+	if m1 == m2 && cached > 0 {
+		fmt.Printf("  Same %d matches, about %.0f× faster.\n", m1, float64(plain)/float64(cached))
+	}
+	fmt.Println("  (The benchmarks: cd go_projects/pkg && go test -bench . ./rxcache)")
+}
+
+// streamDemo counts server errors by status code as the matches arrive,
+// then shows stopping early: after the first three, Stop, or the
+// goroutine reading the rest waits forever to send the fourth.
+func streamDemo() {
+	log := strings.Repeat(
+		"GET /api/users status=200 took=12ms\n"+
+			"GET /api/orders status=503 took=3001ms\n"+
+			"POST /api/orders status=201 took=40ms\n"+
+			"GET /api/users/7 status=500 took=8ms\n", 25_000)
+	serverError := regexp.MustCompile(`(\S+) status=(5\d\d)`)
+
+	counts := map[string]int{}
+	s := rxstream.Lines(context.Background(), strings.NewReader(log), serverError)
+	for m := range s.C {
+		counts[m.Groups[2]]++
+	}
+	if err := s.Err(); err != nil {
+		panic(err)
+	}
+	lines, _ := s.Scanned()
+	fmt.Printf("%d lines: %d × 500, %d × 503\n", lines, counts["500"], counts["503"])
+
+	fmt.Println("The first three, then stop:")
+	s = rxstream.Lines(context.Background(), strings.NewReader(log), serverError)
+	found := 0
+	for m := range s.C {
+		fmt.Printf("  line %d: %s %s\n", m.Line, m.Groups[2], m.Groups[1])
+		if found++; found == 3 {
+			break
+		}
+	}
+	err := s.Stop() // Ends the goroutine; stopping isn't an error
+	lines, _ = s.Scanned()
+	fmt.Printf("  Stop() = %v, after reading %d of 100000 lines: it ran ahead to fill C's buffer\n", err, lines)
+}
+
 func part6BestPractices() {
 	fmt.Println("\n\n" + strings.Repeat("=", 70))
 	fmt.Println("PART 6: PERFORMANCE & BEST PRACTICES")
@@ -663,6 +778,42 @@ Use Compile() when the pattern comes from user input!
 Use MustCompile() for compile-time patterns (string literals).
 `)
 
+	fmt.Println("\n📌 BEST PRACTICE 6: Cache runtime patterns that come back\n")
+
+	fmt.Println(`
+A pattern from user input can't be a package variable, so a handler
+compiles it on every request, paying Practice 1's cost each time:
+
+  rx, err := regexp.Compile(r.URL.Query().Get("q"))   // Every request!
+
+Users repeat themselves: the same few filters, over and over.
+go_projects/pkg/rxcache keeps the recently used ones compiled, with a
+size limit, so endless distinct patterns can't fill memory:
+
+  rx, err := rxcache.Compile(r.URL.Query().Get("q"))  // Once per pattern
+`)
+	cacheDemo()
+
+	fmt.Println("\n\n📌 BEST PRACTICE 7: Stream big inputs a line at a time\n")
+
+	fmt.Println(`
+WRONG for a 5 GB log (all of it in memory before the first match):
+  data, _ := os.ReadFile("app.log")
+  matches := pattern.FindAllString(string(data), -1)
+
+RIGHT (one line in memory at a time; matches arrive as they're found):
+  s := rxstream.Lines(ctx, file, pattern)
+  for m := range s.C {
+    fmt.Println(m.Line, m.Groups[1])
+  }
+  if err := s.Err(); err != nil { ... }       // Or s.Stop() to quit early
+
+go_projects/pkg/rxstream reads with bufio.Scanner in a goroutine and
+sends each matching line, with its number and groups, on a channel.
+Topic 85, Example 7, runs it over 30 MB in about 100 KB of memory.
+`)
+	streamDemo()
+
 	fmt.Println("\n\n📌 COMMON REGEX PATTERNS (Copy-Paste Ready)\n")
 
 	fmt.Println(`
@@ -699,6 +850,9 @@ Hashtag:
 
 Mention (@username):
   @\\w+
+
+Precompiled and tested, with stricter versions of these and Validate
+functions: go_projects/pkg/rxlib (73_regex_detailed.go, Section 6).
 `)
 
 	fmt.Println("\n\n" + strings.Repeat("=", 70))
@@ -710,6 +864,6 @@ Mention (@username):
 	fmt.Println("✅ PART 3: Use ReplaceAllString/ReplaceAllStringFunc to modify")
 	fmt.Println("✅ PART 4: Use FindAllStringSubmatch to extract groups")
 	fmt.Println("✅ PART 5: Apply to real-world problems (validation, parsing, censoring)")
-	fmt.Println("✅ PART 6: Compile once, reuse many. Use raw strings. Use anchors.")
+	fmt.Println("✅ PART 6: Compile once, reuse many. Use raw strings. Use anchors. Cache runtime patterns. Stream big inputs.")
 	fmt.Println("\n🎯 Master these 6 parts, and you master Go regex.\n")
 }
@@ -5,6 +5,11 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/passcheck"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/rxlib"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 // Topic 73: Regular Expressions (Regex)
@@ -13,34 +18,64 @@ import (
 // TL;DR: Go uses RE2 syntax for regex. Compile patterns once (expensive),
 // execute many times (cheap). MatchString checks existence, FindString/FindAllString
 // extract data, ReplaceAllString modifies, FindAllStringSubmatch captures groups.
+//
+// Section 5's password check is go_projects/pkg/passcheck. Section 6's
+// patterns are go_projects/pkg/rxlib, the common ones compiled once and
+// tested; Section 7 uses its NamedGroups and Bind. The imports resolve
+// through the go.work at the repository root:
+//
+//	go run 73_regex_detailed.go -section cookbook
 
 func main() {
 	fmt.Println("=== 73 REGULAR EXPRESSIONS: Deep Dive ===\n")
 
-	// ============================================================
-	// SECTION 1: Pattern Matching (MatchString)
-	// ============================================================
-	section1PatternMatching()
-
-	// ============================================================
-	// SECTION 2: Finding Patterns
-	// ============================================================
-	section2FindingPatterns()
-
-	// ============================================================
-	// SECTION 3: Replacing Patterns
-	// ============================================================
-	section3ReplacingPatterns()
-
-	// ============================================================
-	// SECTION 4: Capturing Groups
-	// ============================================================
-	section4CapturingGroups()
-
-	// ============================================================
-	// SECTION 5: Real-World Examples
-	// ============================================================
-	section5RealWorldExamples()
+	section.Run(sections)
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "pattern-matching", Run: func() {
+		// ============================================================
+		// SECTION 1: Pattern Matching (MatchString)
+		// ============================================================
+		section1PatternMatching()
+	}},
+	{Name: "finding-patterns", Run: func() {
+		// ============================================================
+		// SECTION 2: Finding Patterns
+		// ============================================================
+		section2FindingPatterns()
+	}},
+	{Name: "replacing-patterns", Run: func() {
+		// ============================================================
+		// SECTION 3: Replacing Patterns
+		// ============================================================
+		section3ReplacingPatterns()
+	}},
+	{Name: "capturing-groups", Run: func() {
+		// ============================================================
+		// SECTION 4: Capturing Groups
+		// ============================================================
+		section4CapturingGroups()
+	}},
+	{Name: "real-world-examples", Run: func() {
+		// ============================================================
+		// SECTION 5: Real-World Examples
+		// ============================================================
+		section5RealWorldExamples()
+	}},
+	{Name: "cookbook", Run: func() {
+		// ============================================================
+		// SECTION 6: The Cookbook Package (pkg/rxlib)
+		// ============================================================
+		section6Cookbook()
+	}},
+	{Name: "named-groups", Run: func() {
+		// ============================================================
+		// SECTION 7: Named Capture Groups
+		// ============================================================
+		section7NamedGroups()
+	}},
 }
 
 // ============================================================
@@ -379,24 +414,26 @@ func section5RealWorldExamples() {
 	// Validate password
 	fmt.Println("\n\nExample 4: Validate password strength\n")
 
-	// Must have: uppercase, lowercase, digit, 8+ chars
-	pwdRegex := regexp.MustCompile("^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$")
-
+	// Must have: uppercase, lowercase, digit, 8+ chars. The usual regex,
+	// ^(?=.*[A-Z])(?=.*[a-z])(?=.*[0-9]).{8,}$, needs lookaheads, which RE2
+	// doesn't support: MustCompile would panic. Check the rules in Go instead.
 	passwords := []string{
 		"ValidPass123",
 		"weakpass",
 		"NoDigits",
 		"Short1",
+		"Welcome2024",
 	}
 
-	fmt.Println("Requirements: Uppercase, lowercase, digit, 8+ chars\n")
+	fmt.Println("Requirements: Uppercase, lowercase, digit, 8+ chars, not a common password")
+	fmt.Println("(passcheck.Default: no regex, RE2 has no lookaheads)\n")
 	for _, pwd := range passwords {
-		valid := pwdRegex.MatchString(pwd)
-		status := "❌"
-		if valid {
-			status = "✓"
+		err := passcheck.Default.Check(pwd)
+		if err != nil {
+			fmt.Printf("❌ %q: %s\n", pwd, strings.ReplaceAll(err.Error(), "\n", "; "))
+			continue
 		}
-		fmt.Printf("%s %q\n", status, pwd)
+		fmt.Printf("✓ %q\n", pwd)
 	}
 
 	// Split by pattern
@@ -430,3 +467,157 @@ func section5RealWorldExamples() {
 	}
 	fmt.Printf("Successfully compiled: %v\n", validRegex)
 }
+
+// ============================================================
+// SECTION 6: The Cookbook Package (pkg/rxlib)
+// ============================================================
+
+func section6Cookbook() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("SECTION 6: The Cookbook Package (pkg/rxlib)")
+	fmt.Println(strings.Repeat("=", 70) + "\n")
+
+	fmt.Println("📌 EXPLANATION:")
+	fmt.Println("Section 5 compiles its patterns inside the function, every call.")
+	fmt.Println("rxlib compiles the common ones once, as package variables, and has")
+	fmt.Println("tests for what each must and must not match. Unanchored to FIND in")
+	fmt.Println("text; the Validate functions check a WHOLE string.\n")
+
+	fmt.Println("Example 1: Pull everything out of a post\n")
+
+	post := "Shipped v1.4.0-rc.1 on 2026-10-16! Thanks @gopher and @rob_pike. " +
+		"Notes (https://go.dev/blog/), questions to team@example.com. #golang #release, not issue#12."
+	fmt.Printf("Post: %s\n\n", post)
+	for _, p := range []struct {
+		name string
+		re   *regexp.Regexp
+	}{
+		{"Hashtag", rxlib.Hashtag},
+		{"Mention", rxlib.Mention},
+		{"URL", rxlib.URL},
+		{"Email", rxlib.Email},
+		{"Semver", rxlib.Semver},
+		{"DateISO", rxlib.DateISO},
+	} {
+		fmt.Printf("  %-8s %q\n", p.name, p.re.FindAllString(post, -1))
+	}
+	fmt.Println("\n  The URL stops before \")\", the mention skips the @ in the email,")
+	fmt.Println("  and issue#12 isn't a hashtag.")
+
+	fmt.Println("\n\nExample 2: Validate a form\n")
+
+	for _, f := range []struct {
+		field, value string
+		validate     func(string) error
+	}{
+		{"email", "alice@example.com", rxlib.ValidateEmail},
+		{"email", "bob@test", rxlib.ValidateEmail},
+		{"phone", "(212) 555-0123", rxlib.ValidatePhoneUS},
+		{"phone", "123-456-7890", rxlib.ValidatePhoneUS},
+		{"birthday", "2024-02-29", rxlib.ValidateDateISO},
+		{"birthday", "2025-02-29", rxlib.ValidateDateISO},
+		{"server", "192.168.0.1", rxlib.ValidateIPv4},
+		{"server", "192.168.0.256", rxlib.ValidateIPv4},
+		{"version", "v2.0.0", rxlib.ValidateSemver},
+		{"version", "2.0", rxlib.ValidateSemver},
+	} {
+		if err := f.validate(f.value); err != nil {
+			fmt.Printf("❌ %-9s %v\n", f.field, err)
+		} else {
+			fmt.Printf("✓ %-9s %q\n", f.field, f.value)
+		}
+	}
+	fmt.Println("\n2025-02-29 has the right SHAPE; ValidateDateISO also asks the calendar.")
+
+	fmt.Println("\n\nExample 3: Find is not validate\n")
+
+	input := "call me: alice@example.com!"
+	fmt.Printf("Input: %q\n", input)
+	fmt.Printf("  rxlib.Email.MatchString:  %v   (an email is IN it)\n", rxlib.Email.MatchString(input))
+	fmt.Printf("  rxlib.ValidateEmail:      %v\n", rxlib.ValidateEmail(input))
+	fmt.Println("\nMatchString on an unanchored pattern finds a match ANYWHERE; to check")
+	fmt.Println("a form field, the whole value must match: ^(?:...)$, which Validate uses.")
+}
+
+// ============================================================
+// SECTION 7: Named Capture Groups
+// ============================================================
+
+// accessLog is one line of a web server's log, named group by group.
+// (?P<name>...) and, since Go 1.22, (?<name>...) are the same thing.
+var accessLog = regexp.MustCompile(`^(?P<ip>\d+(?:\.\d+){3}) \[(?P<time>[^\]]+)\] ` +
+	`"(?P<method>[A-Z]+) (?P<path>\S+)" (?P<status>\d{3}) (?P<bytes>\d+) (?P<latency>[\d.]+m?s)$`)
+
+// request is what Bind fills from an accessLog match: each field from
+// the group its rx tag names.
+type request struct {
+	IP      string        `rx:"ip"`
+	Time    time.Time     `rx:"time"`
+	Method  string        `rx:"method"`
+	Path    string        `rx:"path"`
+	Status  int           `rx:"status"`
+	Bytes   int64         `rx:"bytes"`
+	Latency time.Duration `rx:"latency"`
+}
+
+func section7NamedGroups() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("SECTION 7: Named Capture Groups")
+	fmt.Println(strings.Repeat("=", 70) + "\n")
+
+	fmt.Println("📌 EXPLANATION:")
+	fmt.Println("Section 4 reads groups by number: matches[0][3]. Add a group at the")
+	fmt.Println("front and every number after it shifts, silently. (?P<name>...) gives")
+	fmt.Println("a group a name to look it up by, wherever it ends up.\n")
+
+	line := `203.0.113.7 [2026-10-16T09:00:00Z] "GET /api/users?page=2" 503 1024 1.5s`
+	fmt.Printf("Line: %s\n\n", line)
+
+	fmt.Println("Example 1: SubexpNames and SubexpIndex\n")
+
+	m := accessLog.FindStringSubmatch(line)
+	fmt.Printf("  SubexpNames():  %q\n", accessLog.SubexpNames())
+	fmt.Printf("  m[SubexpIndex(\"status\")] = %q\n", m[accessLog.SubexpIndex("status")])
+	fmt.Println("\n  SubexpNames()[i] is group i's name, \"\" for the whole match and")
+	fmt.Println("  unnamed groups; SubexpIndex is -1 for a name the pattern doesn't have.")
+
+	fmt.Println("\n\nExample 2: rxlib.NamedGroups, a map\n")
+
+	groups := rxlib.NamedGroups(accessLog, line)
+	for _, name := range []string{"ip", "method", "path", "status", "latency"} {
+		fmt.Printf("  %-8s %s\n", name, groups[name])
+	}
+	fmt.Printf("\n  No match, no map: %v\n", rxlib.NamedGroups(accessLog, "garbage") == nil)
+
+	fmt.Println("\n\nExample 3: Names in a replacement\n")
+
+	date := regexp.MustCompile(`(?P<year>\d{4})-(?P<month>\d{2})-(?P<day>\d{2})`)
+	us := date.ReplaceAllString("Due 2026-10-16, paid 2026-11-02.", "${month}/${day}/${year}")
+	fmt.Printf("  ${month}/${day}/${year}:  %s\n", us)
+	fmt.Println("\n  Write ${name}, with braces: $month_x would look for a group called")
+	fmt.Println("  \"month_x\" and, finding none, put nothing there.")
+
+	fmt.Println("\n\nExample 4: rxlib.Bind, straight into a struct\n")
+
+	var r request
+	if err := rxlib.Bind(accessLog, line, &r); err != nil {
+		fmt.Println("  ❌", err)
+		return
+	}
+	fmt.Printf("  %+v\n\n", r)
+	fmt.Printf("  r.Status >= 500: %v   r.Latency > time.Second: %v   r.Time.Weekday(): %v\n",
+		r.Status >= 500, r.Latency > time.Second, r.Time.Weekday())
+	fmt.Println("\n  Bind converts each group to its field's type: int, time.Duration,")
+	fmt.Println("  time.Time. A group that won't convert is an error naming both:")
+
+	bad := strings.Replace(line, " 1024 ", " 99999999999999999999 ", 1)
+	fmt.Println("   ", rxlib.Bind(accessLog, bad, &r))
+	var typo struct {
+		Status int `rx:"stauts"`
+	}
+	fmt.Println("   ", rxlib.Bind(accessLog, line, &typo))
+
+	fmt.Println("\n✅ KEY TAKEAWAY:")
+	fmt.Println("Name the groups you read. NamedGroups for a quick map; Bind when the")
+	fmt.Println("fields have types, so the conversions and their errors happen in one place.")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Topic 73 (exercise): Regular Expressions
+// ====================================
+//
+// Three functions to write with the regexp package, after reading
+// 73_regex_detailed.go. regex_test.go says what each must do; run it with
+//
+//	go test .          (or: gotut -dir .. test 73)
+//
+// Stuck? Hints come in three levels, concept, then API, then code:
+//
+//	gotut -dir .. hint 73              the next level you haven't seen
+//	gotut -dir .. hint 73 -level 2
+//
+// Each one you read is recorded in your progress. To compare with the
+// reference when you're done: gotut -dir .. solution 73 --diff
+
+var _ = regexp.MustCompile // Delete once you use regexp
+
+// FindEmails returns every email address in text, in order:
+// "mail ana@go.dev or bo@example.org" gives [ana@go.dev bo@example.org].
+func FindEmails(text string) []string {
+	return nil // TODO
+}
+
+// ParseLogLine splits "2026-01-02 15:04:05 [ERROR] disk full" into its
+// level and message, "ERROR" and "disk full". ok is false for a line of
+// any other shape.
+func ParseLogLine(line string) (level, msg string, ok bool) {
+	return "", "", false // TODO
+}
+
+// CollapseSpaces replaces every run of spaces, tabs and newlines with
+// one space and trims both ends: "  a \t b\n" becomes "a b".
+func CollapseSpaces(s string) string {
+	return s // TODO
+}
+
+func main() {
+	fmt.Println(FindEmails("mail ana@go.dev or bo@example.org"))
+	fmt.Println(ParseLogLine("2026-01-02 15:04:05 [ERROR] disk full"))
+	fmt.Printf("%q\n", CollapseSpaces("  a \t b\n"))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindEmails(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"mail ana@go.dev or bo@example.org", []string{"ana@go.dev", "bo@example.org"}},
+		{"<first.last+tag@sub.example.co.uk>", []string{"first.last+tag@sub.example.co.uk"}},
+		{"no address @ here, nor user@", nil},
+	}
+	for _, tt := range tests {
+		if got := FindEmails(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("FindEmails(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		line       string
+		level, msg string
+		ok         bool
+	}{
+		{"2026-01-02 15:04:05 [ERROR] disk full", "ERROR", "disk full", true},
+		{"2026-01-02 15:04:05 [INFO] started: port 8080", "INFO", "started: port 8080", true},
+		{"2026-01-02 [ERROR] no time", "", "", false},
+		{"2026-01-02 15:04:05 ERROR no brackets", "", "", false},
+		{"prefix 2026-01-02 15:04:05 [WARN] anchored?", "", "", false},
+	}
+	for _, tt := range tests {
+		level, msg, ok := ParseLogLine(tt.line)
+		if level != tt.level || msg != tt.msg || ok != tt.ok {
+			t.Errorf("ParseLogLine(%q) = %q, %q, %v; want %q, %q, %v", tt.line, level, msg, ok, tt.level, tt.msg, tt.ok)
+		}
+	}
+}
+
+func TestCollapseSpaces(t *testing.T) {
+	tests := map[string]string{
+		"  a \t b\n":   "a b",
+		"one":          "one",
+		"a\n\n\nb  c ": "a b c",
+		"":             "",
+	}
+	for in, want := range tests {
+		if got := CollapseSpaces(in); got != want {
+			t.Errorf("CollapseSpaces(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
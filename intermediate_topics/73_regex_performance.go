@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"time"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
+)
+
+// ============================================================
+// Topic 73 (continued): Regex Performance — RE2 and Backtracking
+// ============================================================
+//
+// TL;DR: Most regex engines (Perl, PCRE, Python, JavaScript, Java) match by
+// BACKTRACKING: try one way, and on failure go back and try the next. For
+// some patterns the number of ways is exponential in the input length, so a
+// 30-byte string can take seconds ("catastrophic backtracking", the cause of
+// real outages). Go's regexp is RE2: it simulates every way AT ONCE, so the
+// time is linear in the input for any pattern. The price is features that
+// need backtracking: backreferences (\1) and lookaround ((?=...)).
+//
+// This file builds a tiny backtracking matcher on top of regexp/syntax (the
+// same parser regexp uses), races it against regexp on pathological input,
+// and then shows when you don't need a regex at all.
+
+func main() {
+	fmt.Print("=== 73 REGEX PERFORMANCE: RE2 vs BACKTRACKING ===\n\n")
+
+	section.Run(sections)
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "backtracking-matcher", Run: part1BacktrackingMatcher},
+	{Name: "catastrophic-inputs", Run: part2CatastrophicInputs},
+	{Name: "re2-guarantees", Run: part3RE2Guarantees},
+	{Name: "strings-fast-paths", Run: part4StringsFastPaths},
+}
+
+func heading(title string) {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("=", 70) + "\n")
+}
+
+// ============================================================
+// PART 1: A Backtracking Matcher in 80 Lines
+// ============================================================
+
+// errBudget stops a match that has taken too many steps, so the demo
+// finishes. A real backtracking engine has no such limit by default.
+var errBudget = errors.New("step budget exhausted")
+
+// Backtracker matches a pattern the way Perl-style engines do: depth
+// first, one alternative at a time, retrying on failure.
+type Backtracker struct {
+	re     *syntax.Regexp
+	Steps  int // Match attempts made by the last call
+	Budget int // Give up after this many steps; 0 = no limit
+}
+
+// Backtrack parses pattern with the same parser regexp uses. Counted
+// repeats ({2,5}) are expanded by Simplify into the operators below.
+func Backtrack(pattern string) (*Backtracker, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return &Backtracker{re: re.Simplify()}, nil
+}
+
+// MatchString reports whether s contains a match, trying every start
+// position like regexp.MatchString does.
+func (b *Backtracker) MatchString(s string) (bool, error) {
+	b.Steps = 0
+	for start := 0; start <= len(s); start++ {
+		ok, err := b.match(b.re, s, start, func(int) (bool, error) { return true, nil })
+		if ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// match tries re at s[i:] and calls k with where it ended. k is "the rest
+// of the pattern": if it fails, match tries re's NEXT way of matching —
+// that retry is the backtracking.
+func (b *Backtracker) match(re *syntax.Regexp, s string, i int, k func(int) (bool, error)) (bool, error) {
+	b.Steps++
+	if b.Budget > 0 && b.Steps > b.Budget {
+		return false, errBudget
+	}
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return k(i)
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if i >= len(s) || rune(s[i]) != r { // ASCII is enough for the demo
+				return false, nil
+			}
+			i++
+		}
+		return k(i)
+	case syntax.OpCharClass:
+		if i < len(s) && inClass(re.Rune, rune(s[i])) {
+			return k(i + 1)
+		}
+		return false, nil
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		if i < len(s) && (re.Op == syntax.OpAnyChar || s[i] != '\n') {
+			return k(i + 1)
+		}
+		return false, nil
+	case syntax.OpBeginText, syntax.OpBeginLine:
+		if i == 0 {
+			return k(i)
+		}
+		return false, nil
+	case syntax.OpEndText, syntax.OpEndLine:
+		if i == len(s) {
+			return k(i)
+		}
+		return false, nil
+	case syntax.OpCapture:
+		return b.match(re.Sub[0], s, i, k)
+	case syntax.OpConcat:
+		return b.concat(re.Sub, s, i, k)
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub { // Left to right: first success wins
+			if ok, err := b.match(sub, s, i, k); ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	case syntax.OpQuest:
+		if ok, err := b.match(re.Sub[0], s, i, k); ok || err != nil {
+			return ok, err
+		}
+		return k(i)
+	case syntax.OpStar:
+		return b.star(re.Sub[0], s, i, k)
+	case syntax.OpPlus:
+		return b.match(re.Sub[0], s, i, func(j int) (bool, error) { return b.star(re.Sub[0], s, j, k) })
+	}
+	return false, fmt.Errorf("operator %v not supported by the demo matcher", re.Op)
+}
+
+func (b *Backtracker) concat(subs []*syntax.Regexp, s string, i int, k func(int) (bool, error)) (bool, error) {
+	if len(subs) == 0 {
+		return k(i)
+	}
+	return b.match(subs[0], s, i, func(j int) (bool, error) { return b.concat(subs[1:], s, j, k) })
+}
+
+// star is greedy: one more repetition first, then stop. The j > i check
+// keeps an empty repetition from looping forever.
+func (b *Backtracker) star(sub *syntax.Regexp, s string, i int, k func(int) (bool, error)) (bool, error) {
+	ok, err := b.match(sub, s, i, func(j int) (bool, error) {
+		if j == i {
+			return false, nil
+		}
+		return b.star(sub, s, j, k)
+	})
+	if ok || err != nil {
+		return ok, err
+	}
+	return k(i)
+}
+
+// inClass reports whether r is in a class given as [lo, hi] pairs.
+func inClass(ranges []rune, r rune) bool {
+	for i := 0; i < len(ranges); i += 2 {
+		if ranges[i] <= r && r <= ranges[i+1] {
+			return true
+		}
+	}
+	return false
+}
+
+func part1BacktrackingMatcher() {
+	heading("PART 1: A BACKTRACKING MATCHER")
+
+	fmt.Println("📌 Same parser, different engine: regexp/syntax gives us the tree,")
+	fmt.Print("   and match walks it depth-first, retrying alternatives on failure.\n\n")
+
+	cases := []struct{ pattern, input string }{
+		{`^[a-z]+@[a-z]+\.(com|org)$`, "gopher@golang.org"},
+		{`^[a-z]+@[a-z]+\.(com|org)$`, "gopher@golang.net"},
+		{`colou?r`, "what colour is it"},
+		{`\d{3}-\d{4}`, "call 555-0199"},
+		{`(ab)*c`, "ababab"},
+	}
+	agree := 0
+	for _, c := range cases {
+		bt, err := Backtrack(c.pattern)
+		if err != nil {
+			fmt.Println("   parse:", err)
+			continue
+		}
+		got, err := bt.MatchString(c.input)
+		want := regexp.MustCompile(c.pattern).MatchString(c.input)
+		mark := "✓"
+		if got != want || err != nil {
+			mark = "✗"
+		} else {
+			agree++
+		}
+		fmt.Printf("   %s %-30s %-20q backtracker=%-5v regexp=%-5v steps=%d\n", mark, c.pattern, c.input, got, want, bt.Steps)
+	}
+	fmt.Printf("\n   %d of %d agree with regexp.\n\n", agree, len(cases))
+
+	fmt.Println("✅ KEY TAKEAWAY:")
+	fmt.Println("On ordinary patterns and input a backtracker is quick. The trouble is")
+	fmt.Print("patterns where many different paths can match the same characters.\n\n")
+}
+
+// ============================================================
+// PART 2: Catastrophic Inputs
+// ============================================================
+
+// pathological patterns: nested or overlapping repetition, anchored so
+// that a near-miss input forces every split to be tried.
+var pathological = []struct {
+	pattern string
+	input   func(n int) string
+	why     string
+}{
+	{`^(a+)+$`, func(n int) string { return strings.Repeat("a", n) + "!" },
+		"n a's split among the groups in 2^(n-1) ways"},
+	{`^(a|aa)+$`, func(n int) string { return strings.Repeat("a", n) + "!" },
+		"overlapping alternatives: Fibonacci(n) ways"},
+	{`^(\w+\s?)+$`, func(n int) string { return strings.Repeat("x", n) + "!" },
+		"the shape of real-world outages: a \"words\" validator"},
+}
+
+// timeIt runs fn until about 20ms have passed and returns the time per
+// run. testing.Benchmark (Topic 151) would be steadier, but takes a
+// second per measurement and this file makes thirty.
+func timeIt(fn func()) time.Duration {
+	for n := 1; ; n *= 4 {
+		start := time.Now()
+		for range n {
+			fn()
+		}
+		if took := time.Since(start); took > 20*time.Millisecond {
+			return took / time.Duration(n)
+		}
+	}
+}
+
+func part2CatastrophicInputs() {
+	heading("PART 2: CATASTROPHIC BACKTRACKING")
+
+	const budget = 20_000_000
+	for _, p := range pathological {
+		fmt.Printf("📌 %s on \"aaa...!\" — %s\n\n", p.pattern, p.why)
+		fmt.Printf("   %4s %14s %14s %14s\n", "n", "backtracker", "steps", "regexp")
+		bt, _ := Backtrack(p.pattern)
+		bt.Budget = budget
+		re := regexp.MustCompile(p.pattern)
+		for _, n := range []int{8, 12, 16, 20, 24, 28} {
+			s := p.input(n)
+			start := time.Now()
+			_, err := bt.MatchString(s)
+			took := time.Since(start)
+			rt := timeIt(func() { re.MatchString(s) })
+			btCol := took.Round(time.Microsecond).String()
+			if errors.Is(err, errBudget) {
+				btCol = "> " + btCol
+			}
+			fmt.Printf("   %4d %14s %14d %14s\n", n, btCol, bt.Steps, rt)
+			if errors.Is(err, errBudget) {
+				fmt.Printf("   ... gave up after %d steps; each extra character multiplies the work\n", budget)
+				break
+			}
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("✅ KEY TAKEAWAY:")
+	fmt.Println("The input is tiny and wrong by one character. A backtracker tries every")
+	fmt.Print("way to split it before saying no; regexp stays in the microseconds.\n\n")
+}
+
+// ============================================================
+// PART 3: What RE2 Guarantees, and What It Gives Up
+// ============================================================
+
+func part3RE2Guarantees() {
+	heading("PART 3: RE2 GUARANTEES")
+
+	fmt.Println("📌 Linear time: regexp keeps the SET of pattern positions it could be")
+	fmt.Println("   in (a Thompson NFA, cached as a DFA when it can), so each input byte")
+	fmt.Print("   is looked at a bounded number of times, whatever the pattern.\n\n")
+
+	re := regexp.MustCompile(`^(a+)+$`)
+	var prev time.Duration
+	for _, n := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		s := strings.Repeat("a", n) + "!"
+		took := timeIt(func() { re.MatchString(s) })
+		ratio := ""
+		if prev > 0 {
+			ratio = fmt.Sprintf("(×%.1f)", float64(took)/float64(prev))
+		}
+		fmt.Printf("   ^(a+)+$ on %9d bytes: %12s %s\n", n+1, took, ratio)
+		prev = took
+	}
+	fmt.Print("\n   10× the input, about 10× the time: linear.\n\n")
+
+	fmt.Print("📌 What that costs: features that need to remember a path.\n\n")
+	for _, p := range []struct{ pattern, what string }{
+		{`(\w+) \1`, "backreference: \"the same word twice\""},
+		{`^(?=.*[A-Z])(?=.*\d).{8,}$`, "lookahead: \"has an upper case letter and a digit\""},
+		{`foo(?!bar)`, "negative lookahead"},
+		{`(?<=\$)\d+`, "lookbehind"},
+	} {
+		_, err := regexp.Compile(p.pattern)
+		fmt.Printf("   %-30s %s\n      → %v\n", p.pattern, p.what, err)
+	}
+
+	fmt.Print("\n📌 Do those checks in Go instead — one simple regex or loop per rule:\n\n")
+	strong := func(pw string) bool {
+		return len(pw) >= 8 && strings.ContainsFunc(pw, func(r rune) bool { return 'A' <= r && r <= 'Z' }) &&
+			strings.ContainsAny(pw, "0123456789")
+	}
+	for _, pw := range []string{"hunter22", "Hunter22", "Hun2"} {
+		fmt.Printf("   strong(%q) = %v\n", pw, strong(pw))
+	}
+	fmt.Print("   A whole policy, with a deny-list and a message per rule: go_projects/pkg/passcheck.\n\n")
+
+	fmt.Println("📌 Patterns from users are safe to run (no ReDoS), but still bound them:")
+	_, err := regexp.Compile(`a{1001}`)
+	fmt.Printf("   regexp.Compile(`a{1001}`) → %v\n", err)
+	fmt.Println("   Cap the pattern and input length too; linear is still linear in a")
+	fmt.Print("   100 MB request body.\n\n")
+
+	fmt.Println("✅ KEY TAKEAWAY:")
+	fmt.Println("RE2 trades backreferences and lookaround for a time bound you can rely on,")
+	fmt.Print("even with patterns or input you don't control.\n\n")
+}
+
+// ============================================================
+// PART 4: When You Don't Need a Regex
+// ============================================================
+
+func part4StringsFastPaths() {
+	heading("PART 4: STRINGS FAST PATHS")
+
+	// A 64 KiB log chunk with the interesting line near the end.
+	line := "2024-03-01T12:00:00Z INFO request served path=/lessons/73 status=200\n"
+	text := strings.Repeat(line, 1000) + "2024-03-01T12:00:01Z ERROR database timeout\n"
+
+	contains := regexp.MustCompile(`ERROR`)
+	prefix := regexp.MustCompile(`^2024-03-01`)
+	fold := regexp.MustCompile(`(?i)database TIMEOUT`)
+	rows := []struct {
+		task     string
+		re, str  func()
+		reS, stS string
+	}{
+		{"contains a literal",
+			func() { contains.MatchString(text) }, func() { strings.Contains(text, "ERROR") },
+			"regexp `ERROR`", `strings.Contains`},
+		{"where is it",
+			func() { contains.FindStringIndex(text) }, func() { strings.Index(text, "ERROR") },
+			"FindStringIndex", `strings.Index`},
+		{"starts with",
+			func() { prefix.MatchString(text) }, func() { strings.HasPrefix(text, "2024-03-01") },
+			"regexp `^2024-03-01`", `strings.HasPrefix`},
+		{"case-insensitive",
+			func() { fold.MatchString(text) }, func() { strings.Contains(strings.ToLower(text), "database timeout") },
+			"regexp `(?i)...`", `Contains(ToLower(...))`},
+	}
+	fmt.Printf("   %d KiB of log, the match near the end:\n\n", len(text)>>10)
+	fmt.Printf("   %-20s %-33s   %s\n", "task", "regexp", "strings")
+	for _, r := range rows {
+		reT, stT := timeIt(r.re), timeIt(r.str)
+		fmt.Printf("   %-20s %-22s %10s   %-24s %10s  (%.1f×)\n", r.task, r.reS, reT, r.stS, stT, float64(reT)/float64(stT))
+	}
+	fmt.Println()
+	fmt.Println("   regexp already finds a literal with the same search strings.Index uses,")
+	fmt.Println("   so `ERROR` is close. It can't for (?i): ToLower copies the whole text")
+	fmt.Print("   and still wins. Lower a text once if you search it many times.\n\n")
+
+	fmt.Println("✅ KEY TAKEAWAY:")
+	fmt.Println("For a fixed string, prefix or suffix use strings.Contains/Index/HasPrefix:")
+	fmt.Println("clearer, and never slower. Reach for regexp when the pattern has structure.")
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing/iotest"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/splitters"
+)
+
+// ============================================================
+// Topic 80 (continued): Custom Split Functions — a bufio Cookbook
+// ============================================================
+//
+// TL;DR: bufio.Scanner knows nothing about lines. It fills a buffer and asks
+// a SplitFunc "is there a whole token in here?"; ScanLines is just the
+// default answer. Records that are not lines (CRLF-terminated protocol
+// lines, NUL-separated file names, fixed-width rows, log entries followed by
+// a stack trace) each need their own SplitFunc. Writing one takes a dozen
+// lines; getting it right means handling input that arrives in pieces and
+// a last record with no terminator.
+//
+// The finished functions live in go_projects/pkg/splitters, with tests, and
+// logstats in go_projects/166_report_exporter.go uses the multi-line one.
+// The import resolves through the go.work at the repository root:
+//
+//	go run 80_bufio_splitters.go [-section NAME]
+
+func main() {
+	fmt.Print("=== 80 BUFIO SPLIT FUNCTIONS: A COOKBOOK ===\n\n")
+
+	section.Run(sections)
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "split-contract", Run: part1SplitContract},
+	{Name: "crlf-and-nul", Run: part2CRLFAndNUL},
+	{Name: "fixed-width", Run: part3FixedWidth},
+	{Name: "multi-line-entries", Run: part4MultiLineEntries},
+}
+
+func heading(title string) {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("=", 70) + "\n")
+}
+
+// tokens scans r with split and returns every token and the Scanner's
+// error.
+func tokens(r io.Reader, split bufio.SplitFunc) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(split)
+	var out []string
+	for sc.Scan() {
+		out = append(out, sc.Text())
+	}
+	return out, sc.Err()
+}
+
+// chunkReader returns at most n bytes per Read, like a slow network
+// connection.
+type chunkReader struct {
+	r io.Reader
+	n int
+}
+
+func (c chunkReader) Read(p []byte) (int, error) {
+	return c.r.Read(p[:min(len(p), c.n)])
+}
+
+func check(ok bool, pass, fail string) {
+	if ok {
+		fmt.Println("   ✓", pass)
+	} else {
+		fmt.Println("   ✗", fail)
+	}
+}
+
+// ============================================================
+// PART 1: The SplitFunc Contract
+// ============================================================
+
+// semicolons is a first SplitFunc: records end at ';'. Every line of it
+// is one rule of the contract.
+func semicolons(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, ';'); i >= 0 {
+		return i + 1, data[:i], nil // Whole record: consume it and its ';'
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil // Input ended: the rest is the last record
+	}
+	return 0, nil, nil // Not enough yet: read more and call again
+}
+
+// semicolonsNoEOF forgets the atEOF rule, the most common SplitFunc bug.
+func semicolonsNoEOF(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, ';'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	return 0, nil, nil
+}
+
+func part1SplitContract() {
+	heading("PART 1: THE SplitFunc CONTRACT")
+
+	fmt.Println("📌 func(data []byte, atEOF bool) (advance int, token []byte, err error)")
+	fmt.Println("   data   what the Scanner has buffered and not yet consumed")
+	fmt.Println("   atEOF  no more data will come")
+	fmt.Println("   return (n, tok, nil)  a token; drop n bytes from the buffer")
+	fmt.Println("          (0, nil, nil)  not enough yet: read more, call me again")
+	fmt.Print("          (0, nil, err)  stop; sc.Err() returns err\n\n")
+
+	fmt.Println("📌 Watching the Scanner call it. A network read rarely ends on a record")
+	fmt.Print("   boundary, so feed it 4 bytes per Read:\n\n")
+	calls := 0
+	traced := func(data []byte, atEOF bool) (int, []byte, error) {
+		calls++
+		adv, tok, err := semicolons(data, atEOF)
+		verdict := "need more"
+		if tok != nil {
+			verdict = fmt.Sprintf("token %q", tok)
+		}
+		fmt.Printf("   call %2d  data=%-16q atEOF=%-5v → %s\n", calls, data, atEOF, verdict)
+		return adv, tok, err
+	}
+	got, _ := tokens(chunkReader{strings.NewReader("alpha;beta;gamma"), 4}, traced)
+	fmt.Println()
+	check(len(got) == 3 && got[2] == "gamma",
+		fmt.Sprintf("%d calls, %d tokens: %q", calls, len(got), got),
+		fmt.Sprintf("got %q", got))
+
+	fmt.Print("\n📌 Forget the atEOF branch and the last record disappears, silently:\n\n")
+	lost, err := tokens(strings.NewReader("alpha;beta;gamma"), semicolonsNoEOF)
+	fmt.Printf("   without atEOF: %q, err=%v\n", lost, err)
+	check(len(lost) == 2, `"gamma" had no ';' after it, so it was never returned`,
+		"the last record survived?")
+
+	fmt.Println("\n✅ KEY TAKEAWAY:")
+	fmt.Println("A SplitFunc must cope with a record split across reads (return 0, nil,")
+	fmt.Print("nil and wait) and with a last record that has no terminator (atEOF).\n\n")
+}
+
+// ============================================================
+// PART 2: CRLF Lines and NUL-Separated Records
+// ============================================================
+
+func part2CRLFAndNUL() {
+	heading("PART 2: CRLF LINES AND NUL-SEPARATED RECORDS")
+
+	fmt.Println("📌 HTTP/1.1, SMTP and RFC 4180 CSV end records with \\r\\n, and a bare \\n")
+	fmt.Println("   inside a quoted CSV field is data. ScanLines ends a line at any \\n")
+	fmt.Print("   (and drops a \\r before it); splitters.CRLF ends only at \\r\\n:\n\n")
+	csv := "id,comment\r\n1,\"first line\nsecond line\"\r\n2,short\r\n"
+	byLine, _ := tokens(strings.NewReader(csv), bufio.ScanLines)
+	byCRLF, _ := tokens(strings.NewReader(csv), splitters.CRLF)
+	fmt.Printf("   ScanLines       %d records %q\n", len(byLine), byLine)
+	fmt.Printf("   splitters.CRLF  %d records %q\n\n", len(byCRLF), byCRLF)
+	check(len(byCRLF) == 3 && len(byLine) == 4,
+		"CRLF kept the quoted two-line comment in one record",
+		"unexpected record counts")
+
+	fmt.Println("\n📌 A Unix file name may contain a newline, so `find -print0`, `xargs -0`")
+	fmt.Print("   and `git ls-files -z` separate names with NUL, the one byte it can't:\n\n")
+	listing := "notes.txt\x00odd\nname.txt\x00photos/cat.jpg\x00"
+	byLine, _ = tokens(strings.NewReader(listing), bufio.ScanLines)
+	names, _ := tokens(strings.NewReader(listing), splitters.Null)
+	fmt.Printf("   ScanLines       %d names %q\n", len(byLine), byLine)
+	fmt.Printf("   splitters.Null  %d names %q\n\n", len(names), names)
+	check(len(names) == 3 && names[1] == "odd\nname.txt",
+		`"odd\nname.txt" came back as one name`,
+		"a file name was split")
+
+	fmt.Println("\n✅ KEY TAKEAWAY:")
+	fmt.Println("Split on the terminator the format actually uses. Newline is only a")
+	fmt.Print("safe separator when the data can't contain one.\n\n")
+}
+
+// ============================================================
+// PART 3: Fixed-Width Records
+// ============================================================
+
+func part3FixedWidth() {
+	heading("PART 3: FIXED-WIDTH RECORDS")
+
+	fmt.Println("📌 Bank and mainframe exports often have no separators at all: every")
+	fmt.Print("   record is N bytes and every field sits at a fixed offset.\n\n")
+	// id(4) name(10) cents(6) = 20 bytes per record, no newlines.
+	const width = 20
+	export := "0001Ada       0012500002Grace     0300000003Linus     000099"
+	recs, err := tokens(strings.NewReader(export), splitters.FixedWidth(width))
+	var total int
+	for _, r := range recs {
+		cents, _ := strconv.Atoi(r[14:20])
+		total += cents
+		fmt.Printf("   id=%s name=%-10q cents=%6d\n", r[0:4], strings.TrimSpace(r[4:14]), cents)
+	}
+	fmt.Println()
+	check(err == nil && len(recs) == 3, fmt.Sprintf("3 records, total %d.%02d", total/100, total%100),
+		fmt.Sprintf("%d records, err=%v", len(recs), err))
+
+	fmt.Println("\n📌 A truncated file is an ERROR, not a short last record. Accepting 7")
+	fmt.Print("   bytes as a record would put garbage in every field after the first:\n\n")
+	recs, err = tokens(strings.NewReader(export[:width+7]), splitters.FixedWidth(width))
+	fmt.Printf("   %d record(s), err=%v\n", len(recs), err)
+	check(errors.Is(err, splitters.ErrShortRecord), "sc.Err() reports ErrShortRecord",
+		"the truncation went unnoticed")
+
+	fmt.Println("\n✅ KEY TAKEAWAY:")
+	fmt.Println("A SplitFunc can fail: return (0, nil, err) and the Scanner stops with")
+	fmt.Print("that error, so bad input is reported where it is read.\n\n")
+}
+
+// ============================================================
+// PART 4: Multi-Line Log Entries
+// ============================================================
+
+const appLog = `2026-10-16T10:00:01Z INFO listening on :8080
+2026-10-16T10:00:04Z ERROR handler panicked: runtime error: index out of range [3] with length 3
+goroutine 21 [running]:
+main.grade(...)
+	/srv/quiz/grade.go:42 +0x1d
+main.quizHandler({0x7f3a2c, 0xc000120000}, 0xc000132000)
+	/srv/quiz/handler.go:17 +0x8b
+2026-10-16T10:00:05Z WARN slow request: GET /big took 1.2s
+`
+
+func part4MultiLineEntries() {
+	heading("PART 4: MULTI-LINE LOG ENTRIES")
+
+	fmt.Println("📌 A log entry starts with a timestamp; a panic adds a stack trace under")
+	fmt.Println("   it. Line by line, the trace becomes five \"entries\" with no time and")
+	fmt.Print("   no level. splitters.Timestamped ends an entry where the next begins:\n\n")
+	lines, _ := tokens(strings.NewReader(appLog), bufio.ScanLines)
+	entries, _ := tokens(iotest.OneByteReader(strings.NewReader(appLog)), splitters.Timestamped)
+	fmt.Printf("   ScanLines: %d lines; Timestamped: %d entries\n", len(lines), len(entries))
+	for i, e := range entries {
+		first, rest, _ := strings.Cut(e, "\n")
+		extra := ""
+		if rest != "" {
+			extra = fmt.Sprintf("  (+%d trace lines)", strings.Count(rest, "\n")+1)
+		}
+		fmt.Printf("   %d. %s%s\n", i+1, strings.TrimSpace(fmt.Sprintf("%.60s", first)), extra)
+	}
+	fmt.Println()
+	check(len(entries) == 3, "the trace stayed with its ERROR entry, even fed one byte at a time",
+		fmt.Sprintf("%d entries", len(entries)))
+
+	fmt.Println("\n📌 How it decides: an entry can only end at a line that starts a new")
+	fmt.Println("   one, so with a partial line at the end of the buffer the function")
+	fmt.Println("   can't answer yet and asks for more. At EOF, whatever is left is the")
+	fmt.Print("   last entry. Any line test works: splitters.Entries(isStart).\n\n")
+
+	fmt.Println("📌 The whole entry must fit in the Scanner's buffer, 64 KiB by default.")
+	fmt.Print("   A deep goroutine dump doesn't:\n\n")
+	var big strings.Builder
+	big.WriteString("2026-10-16T10:00:04Z ERROR fatal error: all goroutines are asleep\n")
+	for i := range 2000 {
+		fmt.Fprintf(&big, "goroutine %d [chan receive]:\n\tmain.worker()\n", i)
+	}
+	_, err := tokens(strings.NewReader(big.String()), splitters.Timestamped)
+	fmt.Printf("   %d KiB entry, default buffer: err=%v\n", big.Len()>>10, err)
+	sc := bufio.NewScanner(strings.NewReader(big.String()))
+	sc.Buffer(nil, 1<<20)
+	sc.Split(splitters.Timestamped)
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	fmt.Printf("   sc.Buffer(nil, 1<<20):           %d entry, err=%v\n\n", n, sc.Err())
+	check(errors.Is(err, bufio.ErrTooLong) && n == 1 && sc.Err() == nil,
+		"ErrTooLong by default; fine with a 1 MiB limit",
+		"buffer limits behaved unexpectedly")
+
+	fmt.Println("\n   go_projects/166_report_exporter.go's logstats --format app reads an")
+	fmt.Print("   app log this way and counts the entries that carry a trace.\n\n")
+
+	fmt.Println("✅ KEY TAKEAWAY:")
+	fmt.Println("When a record spans lines, split on where records START, and raise")
+	fmt.Println("Scanner.Buffer for the largest record you expect. Always check sc.Err().")
+}
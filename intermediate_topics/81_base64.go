@@ -3,6 +3,8 @@ package main
 import (
 	"encoding/base64"
 	"fmt"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -253,11 +255,7 @@ func main() {
 	fmt.Println("================================================================================")
 
 	// Run all examples
-	standardEncodingDecoding()
-	urlSafeEncoding()
-	jsonWithBase64()
-	encodingDifferentData()
-	errorHandlingInDecoding()
+	section.Run(sections)
 
 	fmt.Println("\n================================================================================")
 	fmt.Println("Key Takeaways:")
@@ -268,3 +266,12 @@ func main() {
 	fmt.Println("5. Always handle errors when decoding untrusted input")
 	fmt.Println("================================================================================")
 }
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "standard-encoding-decoding", Run: standardEncodingDecoding},
+	{Name: "url-safe-encoding", Run: urlSafeEncoding},
+	{Name: "json-with-base64", Run: jsonWithBase64},
+	{Name: "encoding-different-data", Run: encodingDifferentData},
+	{Name: "error-handling-in-decoding", Run: errorHandlingInDecoding},
+}
@@ -7,6 +7,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -227,9 +230,9 @@ Example:
 
 // EXAMPLE 1: BASIC SHA256 HASHING
 func basicSHA256Hashing() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 1: BASIC SHA256 HASHING")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	password := "password123"
 
@@ -246,9 +249,9 @@ func basicSHA256Hashing() {
 
 // EXAMPLE 2: SHA256 vs SHA512
 func sha256VsSha512() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 2: SHA256 vs SHA512")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	password := "password123"
 
@@ -270,9 +273,9 @@ func sha256VsSha512() {
 
 // EXAMPLE 3: DETERMINISTIC NATURE OF HASHING
 func deterministicHashing() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 3: DETERMINISTIC NATURE")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	password := "password123"
 
@@ -294,12 +297,12 @@ func deterministicHashing() {
 
 // EXAMPLE 4: AVALANCHE EFFECT
 func avalancheEffect() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 4: AVALANCHE EFFECT (SMALL CHANGE = BIG DIFFERENCE)")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	password1 := "course"
-	password2 := "course" // Only one letter different!
+	password2 := "cuurse" // Only one letter different!
 
 	hash1 := sha256.Sum256([]byte(password1))
 	hash2 := sha256.Sum256([]byte(password2))
@@ -342,9 +345,9 @@ func hashPasswordWithSalt(password string, salt []byte) string {
 
 // EXAMPLE 7: PASSWORD STORAGE AND VERIFICATION
 func passwordStorageAndVerification() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 7: PASSWORD STORAGE & VERIFICATION WITH SALT")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	// SIGNUP PHASE
 	fmt.Println("\n--- SIGNUP PHASE ---")
@@ -416,13 +419,13 @@ func passwordStorageAndVerification() {
 
 // EXAMPLE 8: SALT PREVENTS IDENTICAL HASHES FOR SAME PASSWORD
 func saltPreventsIdenticalHashes() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 8: SALT ENSURES DIFFERENT HASHES FOR SAME PASSWORD")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	password := "password123"
 
-	fmt.Println("Two users with same password: 'password123'\n")
+	fmt.Print("Two users with same password: 'password123'\n\n")
 
 	// User 1
 	salt1, _ := generateSalt()
@@ -448,9 +451,9 @@ func saltPreventsIdenticalHashes() {
 
 // EXAMPLE 9: COMPARING PASSWORD HASH WITH UNSALTED HASH
 func saltingBenefit() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("EXAMPLE 9: BENEFIT OF SALTING")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	password := "password123"
 
@@ -477,9 +480,9 @@ func saltingBenefit() {
 
 // EXAMPLE 10: SECURITY CONSIDERATIONS
 func securityNote() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("IMPORTANT: BASE64 IS NOT ENCRYPTION")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	fmt.Println(`
 Why we encode salt with Base64:
@@ -557,21 +560,26 @@ SUMMARY
 */
 
 func main() {
-	fmt.Println("\n" + "="*80)
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("                    HASHING AND SHA DETAILED")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
 
 	// Run all examples
-	basicSHA256Hashing()
-	sha256VsSha512()
-	deterministicHashing()
-	avalancheEffect()
-	passwordStorageAndVerification()
-	saltPreventsIdenticalHashes()
-	saltingBenefit()
-	securityNote()
-
-	fmt.Println("\n" + "="*80)
+	section.Run(sections)
+
+	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("END OF EXAMPLES")
-	fmt.Println("=" * 80)
+	fmt.Println(strings.Repeat("=", 80))
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic-sha256-hashing", Run: basicSHA256Hashing},
+	{Name: "sha256-vs-sha512", Run: sha256VsSha512},
+	{Name: "deterministic-hashing", Run: deterministicHashing},
+	{Name: "avalanche-effect", Run: avalancheEffect},
+	{Name: "password-storage-and-verification", Run: passwordStorageAndVerification},
+	{Name: "salt-prevents-identical-hashes", Run: saltPreventsIdenticalHashes},
+	{Name: "salting-benefit", Run: saltingBenefit},
+	{Name: "security-note", Run: securityNote},
 }
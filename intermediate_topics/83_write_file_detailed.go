@@ -47,6 +47,13 @@ func main() {
 		fmt.Println("Error writing bytes:", err)
 		return
 	}
+	// Close again, explicitly, and check it: the OS may only report a failed
+	// write now. The deferred Close still runs when main returns; on a file
+	// already closed it just returns os.ErrClosed, which defer ignores.
+	if err := file.Close(); err != nil {
+		fmt.Println("Error closing file:", err)
+		return
+	}
 
 	// Appending to file
 	file2, err := os.OpenFile("append.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -67,6 +74,10 @@ func main() {
 		fmt.Println("Error appending:", err)
 		return
 	}
+	if err := file2.Close(); err != nil {
+		fmt.Println("Error closing file:", err)
+		return
+	}
 	fmt.Println("File appended successfully")
 	defer os.Remove("append.txt")
 
@@ -81,6 +92,10 @@ func main() {
 	fmt.Fprintf(file3, "Name: %s\n", "John")
 	fmt.Fprintf(file3, "Age: %d\n", 25)
 	fmt.Fprintf(file3, "Score: %.2f\n", 95.5)
+	if err := file3.Close(); err != nil {
+		fmt.Println("Error closing file:", err)
+		return
+	}
 	fmt.Println("Formatted file written successfully")
 	defer os.Remove("formatted.txt")
 
@@ -115,6 +130,10 @@ func main() {
 		fmt.Println("Error flushing buffer:", err)
 		return
 	}
+	if err := file4.Close(); err != nil {
+		fmt.Println("Error closing file:", err)
+		return
+	}
 	fmt.Println("Buffered file written successfully")
 	defer os.Remove("buffered.txt")
 
@@ -147,6 +166,10 @@ func main() {
 		fmt.Println("Error flushing buffer:", err)
 		return
 	}
+	if err := file5.Close(); err != nil {
+		fmt.Println("Error closing file:", err)
+		return
+	}
 	fmt.Println("Large dataset written successfully with buffering")
 	defer os.Remove("large_dataset.txt")
 
@@ -156,7 +179,7 @@ func main() {
 	// Use os.WriteFile for small files (Go 1.16+)
 	// This is a one-liner that handles everything
 
-	content := []byte("Hello, World!\nThis is a simple file.\nCreated with os.WriteFile\n")
+	content = []byte("Hello, World!\nThis is a simple file.\nCreated with os.WriteFile\n")
 	err = os.WriteFile("simple.txt", content, 0644)
 	if err != nil {
 		fmt.Println("Error writing file:", err)
@@ -2,10 +2,17 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/rxstream"
 )
 
 /*
@@ -33,6 +40,12 @@ It's fundamental to:
 
 This three-step pattern is the foundation of all line filtering operations.
 
+Example 7 streams with go_projects/pkg/rxstream. The import resolves
+through the go.work at the repository root:
+
+    go run 85_line_filters.go
+    go run 85_line_filters.go grep [-v] PATTERN [FILE]
+
 ═══════════════════════════════════════════════════════════════════════════════
 */
 
@@ -227,6 +240,10 @@ func Example3_FilteredOutputToFile() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	// Flush only reaches the OS; Close reports a failed write-back
+	if err := outputFile.Close(); err != nil {
+		log.Fatal(err)
+	}
 
 	if err := scanner.Err(); err != nil {
 		fmt.Println("Error scanning file:", err)
@@ -448,6 +465,136 @@ func Example6_StatisticsAndAggregation() {
 	fmt.Printf("  Average matched length:   %.1f characters\n", averageLength)
 }
 
+/*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+  EXAMPLE 7: STREAMING A HUGE LOG WITH A REGEX (pkg/rxstream)
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Examples 1-6 read a line, check it, move on: memory stays at one line, so
+the file can be any size. go_projects/pkg/rxstream packages that loop for
+the commonest check, a regular expression:
+
+  s := rxstream.Lines(ctx, file, regexp.MustCompile(`status=(5\d\d)`))
+  for m := range s.C {                // Matches arrive as they are found
+      fmt.Println(m.Line, m.Groups[1])
+  }
+  if err := s.Err(); err != nil { ... }   // scanner.Err(), as in Example 1
+
+PROCESS FLOW:
+  • A goroutine scans the reader with bufio.Scanner (lines up to 1 MB,
+    not the Scanner's default 64 KB)
+  • Each line is matched in the Scanner's buffer, without copying it
+  • A matching line is copied and sent on the channel, with its line
+    number, byte offset and capture groups
+  • The channel closes at EOF, on a read error, or when ctx is canceled
+
+The reader here is a made-up log generated as it is read, 30 MB of it, so
+the example needs no file. A real one is the same call on an *os.File, or
+on os.Stdin: try the grep command at the bottom of this file.
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+*/
+
+// generatedLog is an io.Reader of n log lines, made up as they are read:
+// a stand-in for a file far bigger than anything worth loading.
+type generatedLog struct {
+	n, i    int
+	buf     []byte // The current line, reused for the next
+	pending []byte // What of it Read hasn't returned yet
+}
+
+func (g *generatedLog) Read(p []byte) (int, error) {
+	if len(g.pending) == 0 {
+		if g.i == g.n {
+			return 0, io.EOF
+		}
+		level, took := "INFO ", 5+g.i%40
+		if g.i%50_000 == 49_999 {
+			level, took = "ERROR", 1000+g.i%997
+		}
+		// Built with strconv rather than fmt.Appendf, which would allocate
+		// for every number and blur the memory figures below.
+		line := append(g.buf[:0], "2026-10-16T09:"...)
+		line = append(line, byte('0'+g.i/600%6), byte('0'+g.i/60%10), ':', byte('0'+g.i/10%6), byte('0'+g.i%10))
+		line = append(line, "Z level="...)
+		line = append(line, level...)
+		line = append(line, " path=/api/orders/"...)
+		line = strconv.AppendInt(line, int64(g.i), 10)
+		line = append(line, " took="...)
+		line = strconv.AppendInt(line, int64(took), 10)
+		g.buf = append(line, "ms\n"...)
+		g.pending = g.buf
+		g.i++
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	return n, nil
+}
+
+func Example7_StreamingRegex() {
+	fmt.Println("\n=== EXAMPLE 7: Streaming a Huge Log with a Regex ===\n")
+
+	slow := regexp.MustCompile(`level=ERROR .*path=(\S+) took=(\d+)ms`)
+	fmt.Printf("Pattern: %s\n", slow)
+	fmt.Println(strings.Repeat("─", 60))
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	s := rxstream.Lines(context.Background(), &generatedLog{n: 500_000}, slow)
+	worst := 0
+	for m := range s.C {
+		took, _ := strconv.Atoi(m.Groups[2])
+		worst = max(worst, took)
+		fmt.Printf("line %6d (byte %8d): %s took %dms\n", m.Line, m.Offset, m.Groups[1], took)
+	}
+	if err := s.Err(); err != nil {
+		fmt.Println("Error scanning:", err)
+		return
+	}
+
+	runtime.ReadMemStats(&after)
+	lines, bytes := s.Scanned()
+	fmt.Printf("\nScanned %d lines, %.1f MB; slowest error took %dms\n", lines, float64(bytes)/(1<<20), worst)
+	fmt.Printf("Memory allocated along the way: %.0f KB, for %.1f MB read\n",
+		float64(after.TotalAlloc-before.TotalAlloc)/(1<<10), float64(bytes)/(1<<20))
+	fmt.Println("Loading the file first (os.ReadFile) would have needed all of it at once.")
+}
+
+// grep is Example 7 on a real file: matching lines with their numbers,
+// like grep -n. -v prints the lines that don't match.
+//
+//	go run 85_line_filters.go grep 'level=(ERROR|WARN)' app.log
+//	journalctl | go run 85_line_filters.go grep -v DEBUG
+func grep(args []string) error {
+	invert := len(args) > 0 && args[0] == "-v"
+	if invert {
+		args = args[1:]
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: grep [-v] PATTERN [FILE]")
+	}
+	re, err := regexp.Compile(args[0])
+	if err != nil {
+		return err
+	}
+	in := io.Reader(os.Stdin)
+	if len(args) == 2 {
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	s := rxstream.Filter{Re: re, Invert: invert}.Lines(context.Background(), in)
+	for m := range s.C {
+		fmt.Fprintf(out, "%d:%s\n", m.Line, m.Text)
+	}
+	return s.Err()
+}
+
 /*
 ═══════════════════════════════════════════════════════════════════════════════
                     KEY CONCEPTS & BEST PRACTICES
@@ -501,6 +648,13 @@ func Example6_StatisticsAndAggregation() {
 */
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "grep" {
+		if err := grep(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("                 GO LINE FILTERING - COMPREHENSIVE GUIDE")
 	fmt.Println(strings.Repeat("═", 80))
@@ -515,10 +669,13 @@ func main() {
 	fmt.Println("  4. Field-Based Filtering (word-level analysis)")
 	fmt.Println("  5. Complex Transformations (normalization)")
 	fmt.Println("  6. Statistics & Aggregation (reporting)")
+	fmt.Println("  7. Streaming a Huge Log with a Regex (pkg/rxstream) — runs below")
 
 	fmt.Println("\n💡 KEY TAKEAWAY:")
 	fmt.Println("  Line filtering = Read → Check → Process (or Skip)")
 	fmt.Println("  Always use bufio.Scanner for efficiency on large files!")
 
+	Example7_StreamingRegex()
+
 	fmt.Println("\n" + strings.Repeat("═", 80) + "\n")
 }
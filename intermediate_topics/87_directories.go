@@ -34,6 +34,10 @@ CURRENT WORKING DIRECTORY (CWD):
   • Default: The directory where the program is executed from
   • Check:   os.Getwd() returns the current directory
   • Change:  os.Chdir(path) moves the cursor to a new location
+  • Scope:   The cursor belongs to the whole PROCESS, not to one function.
+             A demo that changes it and forgets to change it back moves
+             every demo that runs after it. So the examples below take the
+             directory to work in as a parameter and never rely on the cwd.
 
 PERMISSIONS (0755):
   Linux-style file permissions represented as octal:
@@ -51,7 +55,7 @@ PERMISSIONS (0755):
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example1_CreatingDirectories() {
+func Example1_CreatingDirectories(dir string) error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 1: Creating Directories")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -65,14 +69,12 @@ func Example1_CreatingDirectories() {
 	singleDir := "demo_single_dir"
 	fmt.Printf("Creating: %q\n", singleDir)
 
-	err := os.Mkdir(singleDir, 0755)
-	if err != nil {
-		fmt.Printf("  ✗ Error: %v\n", err)
-		fmt.Println("  (Directory might already exist or parent doesn't exist)\n")
-	} else {
-		fmt.Printf("  ✓ Successfully created\n\n")
-		defer os.Remove(singleDir) // Clean up after demonstration
+	// Fails if the directory already exists or its parent doesn't
+	if err := os.Mkdir(filepath.Join(dir, singleDir), 0755); err != nil {
+		return err
 	}
+	fmt.Printf("  ✓ Successfully created\n\n")
+	defer os.Remove(filepath.Join(dir, singleDir)) // Clean up after demonstration
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// os.MkdirAll(): Create nested directories (RECOMMENDED)
@@ -83,14 +85,12 @@ func Example1_CreatingDirectories() {
 	nestedPath := "demo_nested/level1/level2/level3"
 	fmt.Printf("Creating: %q\n", nestedPath)
 
-	err = os.MkdirAll(nestedPath, 0755)
-	if err != nil {
-		fmt.Printf("  ✗ Error: %v\n", err)
-	} else {
-		fmt.Printf("  ✓ Successfully created entire path\n")
-		fmt.Printf("  (Creates all parent directories automatically)\n\n")
-		defer os.RemoveAll("demo_nested") // Clean up entire tree
+	if err := os.MkdirAll(filepath.Join(dir, nestedPath), 0755); err != nil {
+		return err
 	}
+	fmt.Printf("  ✓ Successfully created entire path\n")
+	fmt.Printf("  (Creates all parent directories automatically)\n\n")
+	defer os.RemoveAll(filepath.Join(dir, "demo_nested")) // Clean up entire tree
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// os.MkdirTemp(): Create temporary directory
@@ -100,12 +100,12 @@ func Example1_CreatingDirectories() {
 
 	tempDir, err := os.MkdirTemp("", "gotut_demo_")
 	if err != nil {
-		fmt.Printf("  ✗ Error: %v\n", err)
-	} else {
-		fmt.Printf("  ✓ Created temp directory: %q\n", tempDir)
-		fmt.Println("  (OS manages cleanup, typically in /tmp or %TEMP%)")
-		defer os.RemoveAll(tempDir)
+		return err
 	}
+	fmt.Printf("  ✓ Created temp directory: %q\n", tempDir)
+	fmt.Printf("  (OS manages cleanup, typically in /tmp or %s)\n", "%TEMP%")
+	defer os.RemoveAll(tempDir)
+	return nil
 }
 
 /*
@@ -114,7 +114,7 @@ func Example1_CreatingDirectories() {
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example2_NavigatingDirectories() {
+func Example2_NavigatingDirectories(dir string) error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 2: Navigating Directories")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -127,10 +127,9 @@ func Example2_NavigatingDirectories() {
 
 	cwd, err := os.Getwd()
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-	} else {
-		fmt.Printf("Current location: %q\n\n", cwd)
+		return err
 	}
+	fmt.Printf("Current location: %q\n\n", cwd)
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// os.Chdir(): Change current working directory
@@ -139,24 +138,60 @@ func Example2_NavigatingDirectories() {
 	fmt.Println(strings.Repeat("─", 80))
 
 	// Create a test directory to navigate to
-	testDir := "demo_nav_test"
-	os.Mkdir(testDir, 0755)
+	testDir := filepath.Join(dir, "demo_nav_test")
+	if err := os.Mkdir(testDir, 0755); err != nil {
+		return err
+	}
 	defer os.RemoveAll(testDir)
 
 	fmt.Printf("Original location: %q\n", cwd)
 
-	err = os.Chdir(testDir)
+	if err := os.Chdir(testDir); err != nil {
+		return err
+	}
+	// Change back as soon as the change succeeded, so an early return
+	// or a panic below can't leave the whole program somewhere else
+	defer os.Chdir(cwd)
+
+	newCwd, err := os.Getwd()
 	if err != nil {
-		fmt.Printf("Error changing directory: %v\n", err)
-	} else {
-		newCwd, _ := os.Getwd()
-		fmt.Printf("Changed to:       %q\n", newCwd)
+		return err
+	}
+	fmt.Printf("Changed to:       %q\n", filepath.Base(newCwd))
+	fmt.Println("  (deferred os.Chdir puts it back when this function returns)")
+	fmt.Println()
 
-		// Change back to original location
-		os.Chdir(cwd)
-		backCwd, _ := os.Getwd()
-		fmt.Printf("Changed back to:  %q\n\n", backCwd)
+	// ─────────────────────────────────────────────────────────────────────────
+	// os.OpenRoot(): Work inside a directory WITHOUT changing the cwd
+	// ─────────────────────────────────────────────────────────────────────────
+	fmt.Println("📌 os.OpenRoot() - Work inside a directory without os.Chdir")
+	fmt.Println(strings.Repeat("─", 80))
+
+	// A Root's methods take names relative to it, like a private cwd that
+	// nothing else in the program can see. It also refuses to leave the
+	// directory, so "../" can't escape it.
+	root, err := os.OpenRoot(testDir)
+	if err != nil {
+		return err
 	}
+	defer root.Close()
+
+	if err := root.Mkdir("notes", 0755); err != nil {
+		return err
+	}
+	f, err := root.Create("notes/today.txt")
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Println("  ✓ root.Create(\"notes/today.txt\") - cwd untouched")
+	if _, err := root.Create("../escape.txt"); err != nil {
+		fmt.Printf("  ✓ root.Create(\"../escape.txt\") refused: %v\n", err)
+	}
+	fmt.Println()
+	return nil
 }
 
 /*
@@ -173,16 +208,16 @@ Each DirEntry is lightweight and can tell you:
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example3_ReadingDirectoryContents() {
+func Example3_ReadingDirectoryContents(dir string) error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 3: Reading Directory Contents")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
 
 	// Create a test directory structure
-	testDir := "demo_read_test"
-	os.MkdirAll(testDir+"/subdir", 0755)
-	os.Create(testDir + "/file1.txt")
-	os.Create(testDir + "/file2.go")
+	testDir := filepath.Join(dir, "demo_read_test")
+	if err := makeTree(testDir, "subdir/", "file1.txt", "file2.go"); err != nil {
+		return err
+	}
 	defer os.RemoveAll(testDir)
 
 	// ─────────────────────────────────────────────────────────────────────────
@@ -191,12 +226,11 @@ func Example3_ReadingDirectoryContents() {
 	fmt.Println("📌 os.ReadDir() - List directory contents")
 	fmt.Println(strings.Repeat("─", 80))
 
-	fmt.Printf("Contents of %q:\n\n", testDir)
+	fmt.Printf("Contents of %q:\n\n", filepath.Base(testDir))
 
 	entries, err := os.ReadDir(testDir)
 	if err != nil {
-		fmt.Printf("Error reading directory: %v\n", err)
-		return
+		return err
 	}
 
 	for i, entry := range entries {
@@ -209,7 +243,10 @@ func Example3_ReadingDirectoryContents() {
 		}
 
 		// Get more detailed info
-		info, _ := entry.Info()
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
 		fmt.Printf("Size: %d bytes\n", info.Size())
 	}
 
@@ -235,6 +272,7 @@ func Example3_ReadingDirectoryContents() {
 			fmt.Printf("  📄 %s\n", entry.Name())
 		}
 	}
+	return nil
 }
 
 /*
@@ -261,21 +299,17 @@ RETURN VALUE:
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example4_WalkingDirectoryTrees() {
+func Example4_WalkingDirectoryTrees(dir string) error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 4: Walking Directory Trees Recursively")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
 
 	// Create a nested directory structure for testing
-	testRoot := "demo_walk_test"
-	os.MkdirAll(testRoot+"/src/pkg1", 0755)
-	os.MkdirAll(testRoot+"/src/pkg2", 0755)
-	os.MkdirAll(testRoot+"/docs", 0755)
-	os.Create(testRoot + "/README.md")
-	os.Create(testRoot + "/src/main.go")
-	os.Create(testRoot + "/src/pkg1/file1.go")
-	os.Create(testRoot + "/src/pkg2/file2.go")
-	os.Create(testRoot + "/docs/guide.txt")
+	testRoot := filepath.Join(dir, "demo_walk_test")
+	err := makeTree(testRoot, "README.md", "src/main.go", "src/pkg1/file1.go", "src/pkg2/file2.go", "docs/guide.txt")
+	if err != nil {
+		return err
+	}
 	defer os.RemoveAll(testRoot)
 
 	// ─────────────────────────────────────────────────────────────────────────
@@ -287,13 +321,17 @@ func Example4_WalkingDirectoryTrees() {
 	fileCount := 0
 	dirCount := 0
 
-	err := filepath.WalkDir(testRoot, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(testRoot, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Indent based on depth
-		depth := strings.Count(path, string(os.PathSeparator))
+		// Indent based on depth below the working directory
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		depth := strings.Count(rel, string(os.PathSeparator))
 		indent := strings.Repeat("  ", depth)
 
 		if d.IsDir() {
@@ -308,7 +346,7 @@ func Example4_WalkingDirectoryTrees() {
 	})
 
 	if err != nil {
-		fmt.Printf("Error walking directory: %v\n", err)
+		return err
 	}
 
 	fmt.Printf("\nSummary: Found %d directories and %d files\n\n", dirCount, fileCount)
@@ -321,19 +359,26 @@ func Example4_WalkingDirectoryTrees() {
 
 	goCount := 0
 
-	filepath.WalkDir(testRoot, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(testRoot, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Skip directories in the filter
 		if !d.IsDir() && strings.HasSuffix(d.Name(), ".go") {
-			fmt.Printf("  ✓ %s\n", path)
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  ✓ %s\n", rel)
 			goCount++
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("Found %d Go files\n\n", goCount)
 
@@ -345,19 +390,23 @@ func Example4_WalkingDirectoryTrees() {
 
 	fmt.Println("Walking tree (but skipping 'docs' directory):")
 
-	filepath.WalkDir(testRoot, func(path string, d fs.DirEntry, err error) error {
+	return filepath.WalkDir(testRoot, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Skip the 'docs' directory entirely
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
 		if d.IsDir() && d.Name() == "docs" {
-			fmt.Printf("  ⏭️  Skipping: %s\n", path)
+			fmt.Printf("  ⏭️  Skipping: %s\n", rel)
 			return filepath.SkipDir // Don't descend into this directory
 		}
 
 		if !d.IsDir() {
-			fmt.Printf("  ✓ %s\n", path)
+			fmt.Printf("  ✓ %s\n", rel)
 		}
 
 		return nil
@@ -381,10 +430,21 @@ os.RemoveAll(path):
   • Equivalent to: rm -rf on Linux
   • DANGEROUS: Can delete large directory trees
   • Use with caution!
+
+Dry run first:
+  • Walk the tree and print what RemoveAll WOULD delete, delete nothing
+  • Tools expose this as --dry-run (see go_projects/174_fileops)
+  • removeAll(root, path, dryRun) below is the same idea in one function
+
+Guard computed paths:
+  • filepath.Join(dir, name) with an empty dir is just "name" — relative to
+    wherever the process happens to be. An empty path is worse.
+  • removeAll refuses anything not strictly inside root, and "/" or your
+    home directory outright (174_fileops' SafeRemoveAll, in small)
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example5_DeletingDirectories() {
+func Example5_DeletingDirectories(dir string) error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 5: Deleting Directories")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -396,15 +456,15 @@ func Example5_DeletingDirectories() {
 	fmt.Println(strings.Repeat("─", 80))
 
 	emptyDir := "demo_empty_dir"
-	os.Mkdir(emptyDir, 0755)
+	if err := os.Mkdir(filepath.Join(dir, emptyDir), 0755); err != nil {
+		return err
+	}
 
 	fmt.Printf("Attempting to delete empty directory: %q\n", emptyDir)
-	err := os.Remove(emptyDir)
-	if err != nil {
-		fmt.Printf("  ✗ Error: %v\n", err)
-	} else {
-		fmt.Printf("  ✓ Successfully deleted\n\n")
+	if err := os.Remove(filepath.Join(dir, emptyDir)); err != nil {
+		return err
 	}
+	fmt.Printf("  ✓ Successfully deleted\n\n")
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// os.Remove() on non-empty directory: FAILS
@@ -413,18 +473,22 @@ func Example5_DeletingDirectories() {
 	fmt.Println(strings.Repeat("─", 80))
 
 	nonEmptyDir := "demo_nonempty_dir"
-	os.Mkdir(nonEmptyDir, 0755)
-	os.Create(nonEmptyDir + "/file.txt")
+	if err := makeTree(filepath.Join(dir, nonEmptyDir), "file.txt"); err != nil {
+		return err
+	}
 
 	fmt.Printf("Directory: %q (contains 1 file)\n", nonEmptyDir)
 	fmt.Println("Attempting os.Remove()...")
 
-	err = os.Remove(nonEmptyDir)
-	if err != nil {
-		fmt.Printf("  ✗ Failed (expected): %v\n\n", err)
+	err := os.Remove(filepath.Join(dir, nonEmptyDir))
+	if err == nil {
+		return fmt.Errorf("os.Remove deleted non-empty %s", nonEmptyDir)
 	}
+	fmt.Printf("  ✗ Failed (expected): %v\n\n", err)
 
-	os.RemoveAll(nonEmptyDir) // Clean up properly
+	if err := os.RemoveAll(filepath.Join(dir, nonEmptyDir)); err != nil { // Clean up properly
+		return err
+	}
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// os.RemoveAll(): Delete directory and ALL contents
@@ -433,10 +497,10 @@ func Example5_DeletingDirectories() {
 	fmt.Println(strings.Repeat("─", 80))
 
 	treeDir := "demo_tree_delete"
-	os.MkdirAll(treeDir+"/level1/level2", 0755)
-	os.Create(treeDir + "/file1.txt")
-	os.Create(treeDir + "/level1/file2.txt")
-	os.Create(treeDir + "/level1/level2/file3.txt")
+	treePath := filepath.Join(dir, treeDir)
+	if err := makeTree(treePath, "file1.txt", "level1/file2.txt", "level1/level2/file3.txt"); err != nil {
+		return err
+	}
 
 	fmt.Printf("Directory structure created:\n")
 	fmt.Printf("  %s/\n", treeDir)
@@ -446,13 +510,122 @@ func Example5_DeletingDirectories() {
 	fmt.Printf("        └── level2/\n")
 	fmt.Printf("            └── file3.txt\n\n")
 
+	fmt.Println("Dry run (nothing is deleted yet):")
+	if err := removeAll(dir, treePath, true); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(treePath, "level1", "level2", "file3.txt")); err != nil {
+		return fmt.Errorf("dry run deleted files: %w", err)
+	}
+	fmt.Printf("  ✓ Tree still intact after the dry run\n\n")
+
 	fmt.Println("Executing os.RemoveAll()...")
-	err = os.RemoveAll(treeDir)
+	if err := removeAll(dir, treePath, false); err != nil {
+		return err
+	}
+	fmt.Printf("  ✓ Entire tree deleted\n\n")
+
+	fmt.Println("Computed paths that removeAll refuses:")
+	home, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Printf("  ✗ Error: %v\n", err)
-	} else {
-		fmt.Printf("  ✓ Entire tree deleted\n")
+		home = "" // No home directory to protect; "" is refused anyway
 	}
+	for _, p := range []string{"", "/", home, filepath.Dir(dir), filepath.Join(dir, "..", "elsewhere")} {
+		if err := removeAll(dir, p, false); err != nil {
+			fmt.Printf("  ✓ %q refused: %v\n", p, err)
+		} else {
+			return fmt.Errorf("removeAll accepted %q", p)
+		}
+	}
+	return nil
+}
+
+// makeTree creates root and the given files (empty) and directories
+// (names ending in "/") below it, with any parents they need.
+func makeTree(root string, names ...string) error {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	for _, name := range names {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeAll lists everything under path (deepest first, the order RemoveAll
+// deletes in) and then deletes it, or with dryRun only lists it. path must be
+// strictly inside root; "/" and the home directory are never deleted.
+func removeAll(root, path string, dryRun bool) error {
+	if err := insideRoot(root, path); err != nil {
+		return err
+	}
+	var doomed []string
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Dir(path), p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			rel += "/"
+		}
+		doomed = append(doomed, rel)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	verb := "deleting"
+	if dryRun {
+		verb = "would delete"
+	}
+	for i := len(doomed) - 1; i >= 0; i-- {
+		fmt.Printf("  %s %s\n", verb, doomed[i])
+	}
+	if dryRun {
+		return nil
+	}
+	return os.RemoveAll(path)
+}
+
+func insideRoot(root, path string) error {
+	if path == "" || root == "" {
+		return fmt.Errorf("empty path")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "" // Nothing to protect
+	}
+	if abs == filepath.VolumeName(abs)+string(filepath.Separator) || (home != "" && abs == home) {
+		return fmt.Errorf("%s is protected", abs)
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(absRoot, abs)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is not inside %s", abs, absRoot)
+	}
+	return nil
 }
 
 /*
@@ -470,32 +643,29 @@ This combines:
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example6_FindingFilesByExtension() {
+func Example6_FindingFilesByExtension(dir string) error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 6: Practical Pattern - Finding Files by Extension")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
 
 	// Create a sample directory structure with various files
-	testDir := "demo_image_search"
-	os.MkdirAll(testDir+"/photos/vacation", 0755)
-	os.MkdirAll(testDir+"/documents", 0755)
-	os.MkdirAll(testDir+"/code", 0755)
+	testDir := filepath.Join(dir, "demo_image_search")
 
 	// Create sample files
 	files := []string{
-		testDir + "/photo1.jpg",
-		testDir + "/photo2.JPG", // uppercase extension
-		testDir + "/photos/sunset.jpg",
-		testDir + "/photos/vacation/beach.png",
-		testDir + "/photos/vacation/people.jpg",
-		testDir + "/documents/report.pdf",
-		testDir + "/documents/image.jpg",
-		testDir + "/code/main.go",
-		testDir + "/code/utils.go",
+		"photo1.jpg",
+		"photo2.JPG", // uppercase extension
+		"photos/sunset.jpg",
+		"photos/vacation/beach.png",
+		"photos/vacation/people.jpg",
+		"documents/report.pdf",
+		"documents/image.jpg",
+		"code/main.go",
+		"code/utils.go",
 	}
 
-	for _, f := range files {
-		os.Create(f)
+	if err := makeTree(testDir, files...); err != nil {
+		return err
 	}
 	defer os.RemoveAll(testDir)
 
@@ -517,10 +687,17 @@ func Example6_FindingFilesByExtension() {
 		if !d.IsDir() {
 			// Check extension (case-insensitive)
 			if strings.EqualFold(filepath.Ext(path), targetExt) {
-				jpgFiles = append(jpgFiles, path)
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					return err
+				}
+				jpgFiles = append(jpgFiles, rel)
 
 				// Get file size
-				info, _ := d.Info()
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
 				totalSize += info.Size()
 			}
 		}
@@ -529,8 +706,7 @@ func Example6_FindingFilesByExtension() {
 	})
 
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	// Display results
@@ -540,6 +716,7 @@ func Example6_FindingFilesByExtension() {
 	}
 
 	fmt.Printf("\nTotal size: %d bytes\n", totalSize)
+	return nil
 }
 
 /*
@@ -548,10 +725,11 @@ func Example6_FindingFilesByExtension() {
 ═══════════════════════════════════════════════════════════════════════════════
 
 1. ERROR HANDLING IS MANDATORY
-   All file system operations can fail. Always check errors:
+   All file system operations can fail. Always check errors, and return
+   them from helpers so the caller (in the end, main or the runner) decides:
 
    if err != nil {
-       log.Fatal(err)  // or handle appropriately
+       return fmt.Errorf("creating cache dir: %w", err)
    }
 
 2. USE MkdirAll() BY DEFAULT
@@ -580,7 +758,10 @@ func Example6_FindingFilesByExtension() {
 6. USE DEFER FOR CLEANUP
    Ensures resources are cleaned up even if function panics:
 
-   tempDir, _ := os.MkdirTemp("", "prefix")
+   tempDir, err := os.MkdirTemp("", "prefix")
+   if err != nil {
+       return err
+   }
    defer os.RemoveAll(tempDir)
    // tempDir will be cleaned up when function exits
 
@@ -588,17 +769,38 @@ func Example6_FindingFilesByExtension() {
 */
 
 // Demo function matching the original style
-func Demo87Directories() {
+func Demo87Directories() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("87 DIRECTORIES - COMPLETE GUIDE")
 	fmt.Println(strings.Repeat("═", 80))
 
-	Example1_CreatingDirectories()
-	Example2_NavigatingDirectories()
-	Example3_ReadingDirectoryContents()
-	Example4_WalkingDirectoryTrees()
-	Example5_DeletingDirectories()
-	Example6_FindingFilesByExtension()
+	// Every example works inside this directory, handed to it explicitly,
+	// rather than in whatever directory the runner was started from: no
+	// demo_* folders are left behind, and no example depends on the cwd.
+	dir, err := os.MkdirTemp("", "gotut_87_")
+	if err != nil {
+		return fmt.Errorf("creating working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// An example that fails stops the demo: the ones after it build on the
+	// same directory, and the runner reports the error with its causes.
+	examples := []struct {
+		name string
+		run  func(string) error
+	}{
+		{"example 1 (creating)", Example1_CreatingDirectories},
+		{"example 2 (navigating)", Example2_NavigatingDirectories},
+		{"example 3 (reading)", Example3_ReadingDirectoryContents},
+		{"example 4 (walking)", Example4_WalkingDirectoryTrees},
+		{"example 5 (deleting)", Example5_DeletingDirectories},
+		{"example 6 (finding)", Example6_FindingFilesByExtension},
+	}
+	for _, ex := range examples {
+		if err := ex.run(dir); err != nil {
+			return fmt.Errorf("%s: %w", ex.name, err)
+		}
+	}
 
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("KEY TAKEAWAYS:")
@@ -606,6 +808,10 @@ func Demo87Directories() {
 	fmt.Println("  • Use filepath.WalkDir() for recursive traversal")
 	fmt.Println("  • Always handle errors from file operations")
 	fmt.Println("  • Be careful with RemoveAll() - it's irreversible!")
+	fmt.Println("  • Offer a dry run: list what RemoveAll() would delete first")
+	fmt.Println("  • Check computed paths are inside the directory you meant")
 	fmt.Println("  • Use filepath.Join() for portable path construction")
+	fmt.Println("  • Pass directories explicitly; os.Chdir changes the whole process")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
+	return nil
 }
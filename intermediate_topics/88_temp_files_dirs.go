@@ -56,7 +56,7 @@ NAMING:
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example1_CreatingTemporaryFiles() {
+func Example1_CreatingTemporaryFiles() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 1: Creating Temporary Files")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -69,28 +69,37 @@ func Example1_CreatingTemporaryFiles() {
 
 	file, err := os.CreateTemp("", "gotut_example_*.txt")
 	if err != nil {
-		fmt.Printf("Error creating temp file: %v\n", err)
-		return
+		return fmt.Errorf("creating temp file: %w", err)
 	}
+	defer os.Remove(file.Name()) // Remove the file from disk
 
 	fmt.Printf("Temp file created at: %q\n", file.Name())
 	fmt.Printf("File is ready for:    Reading and Writing\n\n")
 
 	// Write some content to the file
-	_, err = file.WriteString("This is temporary content.\n")
-	if err != nil {
-		fmt.Printf("Error writing to file: %v\n", err)
+	if _, err := file.WriteString("This is temporary content.\n"); err != nil {
+		file.Close()
+		return fmt.Errorf("writing to temp file: %w", err)
 	}
 
 	// Seek back to beginning to read what we wrote
-	file.Seek(0, 0)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
 	content := make([]byte, 100)
-	n, _ := file.Read(content)
+	n, err := file.Read(content)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("reading back temp file: %w", err)
+	}
 	fmt.Printf("Content written:      %q\n\n", string(content[:n]))
 
-	// CLEANUP: Always close the file handle
-	file.Close()
-	defer os.Remove(file.Name()) // Remove the file from disk
+	// CLEANUP: Always close the file handle (and check: Close can report
+	// a write that failed late)
+	if err := file.Close(); err != nil {
+		return err
+	}
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// Multiple temporary files with clear naming
@@ -104,14 +113,20 @@ func Example1_CreatingTemporaryFiles() {
 		"cache_*.tmp",
 	}
 
-	fmt.Println("Creating multiple temp files:\n")
+	fmt.Print("Creating multiple temp files:\n\n")
 
 	for _, pattern := range tempFiles {
-		f, _ := os.CreateTemp("", pattern)
-		fmt.Printf("  Pattern: %-20s → %s\n", pattern, filepath.Base(f.Name()))
-		f.Close()
+		f, err := os.CreateTemp("", pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %s: %w", pattern, err)
+		}
 		defer os.Remove(f.Name())
+		fmt.Printf("  Pattern: %-20s → %s\n", pattern, filepath.Base(f.Name()))
+		if err := f.Close(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 /*
@@ -132,7 +147,7 @@ CLEANUP REQUIREMENT:
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example2_CreatingTemporaryDirectories() {
+func Example2_CreatingTemporaryDirectories() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 2: Creating Temporary Directories")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -145,9 +160,10 @@ func Example2_CreatingTemporaryDirectories() {
 
 	tempDir, err := os.MkdirTemp("", "gotut_workspace_*")
 	if err != nil {
-		fmt.Printf("Error creating temp directory: %v\n", err)
-		return
+		return fmt.Errorf("creating temp directory: %w", err)
 	}
+	// CLEANUP: Remove entire directory tree
+	defer os.RemoveAll(tempDir)
 
 	fmt.Printf("Temp directory created at: %q\n", tempDir)
 	fmt.Printf("Ready to create files in:  %q\n\n", tempDir)
@@ -158,34 +174,39 @@ func Example2_CreatingTemporaryDirectories() {
 
 	for _, fname := range files {
 		fullPath := filepath.Join(tempDir, fname)
-		f, _ := os.Create(fullPath)
-		f.WriteString("Temporary content for: " + fname)
-		f.Close()
+		if err := os.WriteFile(fullPath, []byte("Temporary content for: "+fname), 0644); err != nil {
+			return err
+		}
 		fmt.Printf("  ✓ Created: %s\n", fname)
 	}
 
 	fmt.Println()
 
 	// List contents
-	entries, _ := os.ReadDir(tempDir)
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return err
+	}
 	fmt.Printf("Directory contains %d items\n\n", len(entries))
 
-	// CLEANUP: Remove entire directory tree
-	defer os.RemoveAll(tempDir)
-
 	// ─────────────────────────────────────────────────────────────────────────
 	// Nested temporary directories
 	// ─────────────────────────────────────────────────────────────────────────
 	fmt.Println("📌 Nested Temporary Directories")
 	fmt.Println(strings.Repeat("─", 80))
 
-	nestedTemp, _ := os.MkdirTemp("", "gotut_project_*")
+	nestedTemp, err := os.MkdirTemp("", "gotut_project_*")
+	if err != nil {
+		return err
+	}
 	defer os.RemoveAll(nestedTemp)
 
 	// Create subdirectories
 	subdirs := []string{"data", "logs", "cache"}
 	for _, subdir := range subdirs {
-		os.Mkdir(filepath.Join(nestedTemp, subdir), 0755)
+		if err := os.Mkdir(filepath.Join(nestedTemp, subdir), 0755); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Created temp directory: %s\n", filepath.Base(nestedTemp))
@@ -194,6 +215,7 @@ func Example2_CreatingTemporaryDirectories() {
 	for _, subdir := range subdirs {
 		fmt.Printf("  📁 %s/\n", subdir)
 	}
+	return nil
 }
 
 /*
@@ -218,7 +240,7 @@ CLEANUP STRATEGIES:
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example3_CleanupPatterns() {
+func Example3_CleanupPatterns() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 3: Cleanup Patterns (Critical for Safety)")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -229,20 +251,28 @@ func Example3_CleanupPatterns() {
 	fmt.Println("📌 PATTERN 1: Using defer for guaranteed cleanup")
 	fmt.Println(strings.Repeat("─", 80))
 
-	file, _ := os.CreateTemp("", "cleanup_defer_*.txt")
+	file, err := os.CreateTemp("", "cleanup_defer_*.txt")
+	if err != nil {
+		return err
+	}
 	filename := file.Name()
 
 	// IMMEDIATELY after creation, set up cleanup with defer
 	defer os.Remove(filename)
 	defer file.Close()
 
-	file.WriteString("This will be cleaned up properly")
+	if _, err := file.WriteString("This will be cleaned up properly"); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil { // The deferred Close is the safety net
+		return err
+	}
 
 	fmt.Printf("Created file: %s\n", filepath.Base(filename))
 	fmt.Println("defer statements registered:")
 	fmt.Println("  1. defer file.Close()")
 	fmt.Println("  2. defer os.Remove(filename)")
-	fmt.Println("  → Cleanup GUARANTEED even if function panics!\n")
+	fmt.Print("  → Cleanup GUARANTEED even if function panics!\n\n")
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// PATTERN 2: Nested cleanup (files inside temp directory)
@@ -250,22 +280,27 @@ func Example3_CleanupPatterns() {
 	fmt.Println("📌 PATTERN 2: Cleanup nested structure")
 	fmt.Println(strings.Repeat("─", 80))
 
-	tempDir, _ := os.MkdirTemp("", "nested_cleanup_*")
+	tempDir, err := os.MkdirTemp("", "nested_cleanup_*")
+	if err != nil {
+		return err
+	}
 
 	// Register cleanup for entire tree FIRST
 	defer os.RemoveAll(tempDir)
 
 	// Then create files inside
 	for i := 1; i <= 3; i++ {
-		filepath := filepath.Join(tempDir, fmt.Sprintf("file_%d.txt", i))
-		os.WriteFile(filepath, []byte(fmt.Sprintf("content %d", i)), 0644)
+		path := filepath.Join(tempDir, fmt.Sprintf("file_%d.txt", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("content %d", i)), 0644); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Created temp directory: %s\n", filepath.Base(tempDir))
 	fmt.Println("  ├── file_1.txt")
 	fmt.Println("  ├── file_2.txt")
 	fmt.Println("  └── file_3.txt")
-	fmt.Println("defer os.RemoveAll() will clean entire tree\n")
+	fmt.Print("defer os.RemoveAll() will clean entire tree\n\n")
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// PATTERN 3: Manual cleanup vs defer
@@ -284,13 +319,19 @@ func Example3_CleanupPatterns() {
 
 	fmt.Println("\n✓ DO THIS INSTEAD (defer guarantees cleanup):")
 	fmt.Println(`
-  f, _ := os.CreateTemp("", "good_*.txt")
-  defer f.Close()
+  f, err := os.CreateTemp("", "good_*.txt")
+  if err != nil {
+      return err
+  }
   defer os.Remove(f.Name())
-  
-  f.WriteString("content")
-  // Even if we return early, defer will execute!
+  defer f.Close()
+
+  if _, err := f.WriteString("content"); err != nil {
+      return err // Even if we return early, defer will execute!
+  }
+  return f.Close() // Checked: a late write error surfaces here
 	`)
+	return nil
 }
 
 /*
@@ -308,7 +349,7 @@ This demonstrates a complete workflow using temporary files.
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example4_FileUploadProcessing() {
+func Example4_FileUploadProcessing() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 4: Practical Pattern - File Upload Processing")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -323,8 +364,7 @@ func Example4_FileUploadProcessing() {
 	// Step 1: Create temp file for the upload
 	tempFile, err := os.CreateTemp("", "upload_*.tmp")
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	defer tempFile.Close()
@@ -336,8 +376,7 @@ func Example4_FileUploadProcessing() {
 	fmt.Println("\nStep 2: Write uploaded content to temp file")
 	_, err = tempFile.Write(uploadedContent)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 	fmt.Printf("  ✓ Wrote %d bytes\n", len(uploadedContent))
 
@@ -345,24 +384,36 @@ func Example4_FileUploadProcessing() {
 	fmt.Println("\nStep 3: Validate uploaded file")
 
 	// Calculate hash for validation
-	tempFile.Seek(0, 0)
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
 	hash := md5.New()
-	io.Copy(hash, tempFile)
+	if _, err := io.Copy(hash, tempFile); err != nil {
+		return err
+	}
 	checksum := fmt.Sprintf("%x", hash.Sum(nil))
 
 	fmt.Printf("  ✓ Calculated MD5: %s\n", checksum[:16]+"...")
 
 	// Step 4: Process the file
 	fmt.Println("\nStep 4: Process the file")
-	tempFile.Seek(0, 0)
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
 	content := make([]byte, len(uploadedContent))
-	tempFile.Read(content)
+	if _, err := io.ReadFull(tempFile, content); err != nil {
+		return err
+	}
 	fmt.Printf("  ✓ Read content: %q\n", string(content[:20])+"...")
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
 
 	// Step 5: Report results
 	fmt.Println("\nStep 5: Processing complete")
 	fmt.Printf("  ✓ Temp file automatically cleaned by defer: {tempFile.Close(), os.Remove()}")
-	fmt.Println("\n  (Deferred cleanup executes when function returns)\n")
+	fmt.Print("\n  (Deferred cleanup executes when function returns)\n\n")
+	return nil
 }
 
 /*
@@ -380,7 +431,7 @@ This demonstrates organizing multiple temp files together.
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 */
 
-func Example5_BatchProcessingWithTempDir() {
+func Example5_BatchProcessingWithTempDir() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 5: Batch Processing with Temp Directory")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -391,8 +442,7 @@ func Example5_BatchProcessingWithTempDir() {
 	// Create temp directory for batch processing
 	batchDir, err := os.MkdirTemp("", "batch_processing_*")
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	defer os.RemoveAll(batchDir)
@@ -404,15 +454,17 @@ func Example5_BatchProcessingWithTempDir() {
 	outputDir := filepath.Join(batchDir, "output")
 	logsDir := filepath.Join(batchDir, "logs")
 
-	os.Mkdir(inputDir, 0755)
-	os.Mkdir(outputDir, 0755)
-	os.Mkdir(logsDir, 0755)
+	for _, d := range []string{inputDir, outputDir, logsDir} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			return err
+		}
+	}
 
 	fmt.Println("Directory structure:")
 	fmt.Printf("  %s/\n", filepath.Base(batchDir))
 	fmt.Println("  ├── input/")
 	fmt.Println("  ├── output/")
-	fmt.Println("  └── logs/\n")
+	fmt.Print("  └── logs/\n\n")
 
 	// Simulate processing multiple files
 	fmt.Println("Processing workflow:")
@@ -423,7 +475,9 @@ func Example5_BatchProcessingWithTempDir() {
 	fmt.Println("\n  Step 1: Create input files")
 	for _, fname := range files {
 		path := filepath.Join(inputDir, fname)
-		os.WriteFile(path, []byte("Sample data for "+fname), 0644)
+		if err := os.WriteFile(path, []byte("Sample data for "+fname), 0644); err != nil {
+			return err
+		}
 		fmt.Printf("    ✓ Created: %s\n", fname)
 	}
 
@@ -434,13 +488,18 @@ func Example5_BatchProcessingWithTempDir() {
 		outputPath := filepath.Join(outputDir, "processed_"+fname)
 
 		// Read input
-		content, _ := os.ReadFile(inputPath)
+		content, err := os.ReadFile(inputPath)
+		if err != nil {
+			return err
+		}
 
 		// Process (simulate uppercase transformation)
 		processed := strings.ToUpper(string(content))
 
 		// Write output
-		os.WriteFile(outputPath, []byte(processed), 0644)
+		if err := os.WriteFile(outputPath, []byte(processed), 0644); err != nil {
+			return err
+		}
 		fmt.Printf("    ✓ Processed: %s\n", fname)
 	}
 
@@ -449,7 +508,9 @@ func Example5_BatchProcessingWithTempDir() {
 	logPath := filepath.Join(logsDir, "processing.log")
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 	logEntry := fmt.Sprintf("[%s] Batch processing completed\n", timestamp)
-	os.WriteFile(logPath, []byte(logEntry), 0644)
+	if err := os.WriteFile(logPath, []byte(logEntry), 0644); err != nil {
+		return err
+	}
 	fmt.Println("    ✓ Log created: processing.log")
 
 	// Summary
@@ -457,7 +518,8 @@ func Example5_BatchProcessingWithTempDir() {
 	fmt.Printf("    Input files:   %d\n", len(files))
 	fmt.Println("    Output files:  3")
 	fmt.Println("    Logs created:  1")
-	fmt.Println("\n  Entire workspace will be cleaned up by defer os.RemoveAll()\n")
+	fmt.Print("\n  Entire workspace will be cleaned up by defer os.RemoveAll()\n\n")
+	return nil
 }
 
 /*
@@ -503,7 +565,7 @@ SECURITY CONSIDERATIONS:
 ═══════════════════════════════════════════════════════════════════════════════
 */
 
-func Example6_SecurityAndBestPractices() {
+func Example6_SecurityAndBestPractices() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("EXAMPLE 6: Security and Best Practices")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
@@ -515,33 +577,44 @@ func Example6_SecurityAndBestPractices() {
 	fmt.Println("   Pattern: 'myapp_cache_*'")
 
 	for i := 0; i < 3; i++ {
-		f, _ := os.CreateTemp("", "myapp_cache_*")
-		fmt.Printf("   Result: %s\n", filepath.Base(f.Name()))
-		f.Close()
+		f, err := os.CreateTemp("", "myapp_cache_*")
+		if err != nil {
+			return err
+		}
 		defer os.Remove(f.Name())
+		fmt.Printf("   Result: %s\n", filepath.Base(f.Name()))
+		if err := f.Close(); err != nil {
+			return err
+		}
 	}
 
-	fmt.Println("\n   (Each file has a unique random suffix - no collisions!)\n")
+	fmt.Print("\n   (Each file has a unique random suffix - no collisions!)\n\n")
 
 	fmt.Println("2. NAMING PATTERNS FOR IDENTIFICATION")
 	fmt.Println("   ✓ GOOD:  'gotut_upload_*.tmp'  (clear source)")
 	fmt.Println("   ✓ GOOD:  'batch_worker_*.log'  (clear purpose)")
 	fmt.Println("   ✗ BAD:   'tmp_*'               (too generic)")
-	fmt.Println("   ✗ BAD:   'x_*'                 (unclear)\n")
+	fmt.Print("   ✗ BAD:   'x_*'                 (unclear)\n\n")
 
 	fmt.Println("3. PROPER ERROR HANDLING")
 	fmt.Println("   ALWAYS check for errors:")
 
 	// Demonstrate error handling
-	_, err := os.CreateTemp("/invalid/path", "*.txt")
-	if err != nil {
-		fmt.Printf("   Error caught: %v\n\n", err)
+	f, err := os.CreateTemp("/invalid/path", "*.txt")
+	if err == nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("CreateTemp in /invalid/path unexpectedly worked")
 	}
+	fmt.Printf("   Error caught: %v\n\n", err)
 
 	fmt.Println("4. STRUCTURED TEMP DIRECTORIES")
 	fmt.Println("   Create dedicated temp directory for related files:")
 
-	tempDir, _ := os.MkdirTemp("", "session_*")
+	tempDir, err := os.MkdirTemp("", "session_*")
+	if err != nil {
+		return err
+	}
 	defer os.RemoveAll(tempDir)
 
 	fmt.Printf("   Main dir:  %s/\n", filepath.Base(tempDir))
@@ -549,7 +622,7 @@ func Example6_SecurityAndBestPractices() {
 	fmt.Println("     ├── cache/")
 	fmt.Println("     ├── uploads/")
 	fmt.Println("     └── processing/")
-	fmt.Println("\n   Single os.RemoveAll() cleans everything!\n")
+	fmt.Print("\n   Single os.RemoveAll() cleans everything!\n\n")
 
 	fmt.Println("5. DEFER FOR GUARANTEED CLEANUP")
 	fmt.Println("   Pattern:")
@@ -563,6 +636,7 @@ func Example6_SecurityAndBestPractices() {
     // Use resource here
     // Cleanup ALWAYS happens when function returns!
 	`)
+	return nil
 }
 
 /*
@@ -594,17 +668,27 @@ STORAGE LOCATIONS (automatic):
 */
 
 // Demo function matching the original style
-func Demo88TempFilesDirs() {
+func Demo88TempFilesDirs() error {
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("88 TEMPORARY FILES AND DIRECTORIES - COMPLETE GUIDE")
 	fmt.Println(strings.Repeat("═", 80))
 
-	Example1_CreatingTemporaryFiles()
-	Example2_CreatingTemporaryDirectories()
-	Example3_CleanupPatterns()
-	Example4_FileUploadProcessing()
-	Example5_BatchProcessingWithTempDir()
-	Example6_SecurityAndBestPractices()
+	examples := []struct {
+		name string
+		run  func() error
+	}{
+		{"example 1 (temp files)", Example1_CreatingTemporaryFiles},
+		{"example 2 (temp dirs)", Example2_CreatingTemporaryDirectories},
+		{"example 3 (cleanup)", Example3_CleanupPatterns},
+		{"example 4 (upload)", Example4_FileUploadProcessing},
+		{"example 5 (batch)", Example5_BatchProcessingWithTempDir},
+		{"example 6 (security)", Example6_SecurityAndBestPractices},
+	}
+	for _, ex := range examples {
+		if err := ex.run(); err != nil {
+			return fmt.Errorf("%s: %w", ex.name, err)
+		}
+	}
 
 	fmt.Println("\n" + strings.Repeat("═", 80))
 	fmt.Println("KEY TAKEAWAYS:")
@@ -613,5 +697,7 @@ func Demo88TempFilesDirs() {
 	fmt.Println("  • Unique naming (with *) prevents collisions automatically")
 	fmt.Println("  • Clear naming patterns help identify temp files in system")
 	fmt.Println("  • Missing cleanup leads to disk bloat and security issues!")
+	fmt.Println("  • Return setup errors instead of printing and carrying on")
 	fmt.Println(strings.Repeat("═", 80) + "\n")
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 )
 
 // ============================================================================
@@ -19,22 +20,28 @@ import (
 // Key Progression:
 // - Basic output (fmt) → Simple timestamps (log) → Files → Structured JSON
 //
+// A note on global state: log.Println, log.SetFlags and log.SetPrefix all
+// act on ONE logger shared by the whole program (log.Default()). Change its
+// prefix in one place and every later log line carries it. So the examples
+// here receive a *log.Logger to work with and build their own variations
+// from it, and the file examples write into a directory they are given.
+//
 // ============================================================================
 
 // ============================================================================
 // PART 1: THE STANDARD log PACKAGE
 // ============================================================================
 
-func Demo93_Part1_StandardLog() {
+func Demo93_Part1_StandardLog(logger *log.Logger) {
 	fmt.Println("\n=== PART 1: THE STANDARD log PACKAGE ===")
 	fmt.Println()
 
-	exampleBasicLogging()
-	examplePrefixes()
-	exampleFlags()
+	exampleBasicLogging(logger)
+	examplePrefixes(logger)
+	exampleFlags(logger)
 }
 
-func exampleBasicLogging() {
+func exampleBasicLogging(logger *log.Logger) {
 	fmt.Println("📌 Basic Logging Functions:")
 	fmt.Println("   log.Println() - Print with automatic timestamp")
 	fmt.Println("   log.Printf()  - Formatted print with timestamp")
@@ -43,41 +50,38 @@ func exampleBasicLogging() {
 	fmt.Println("   log.Panic()   - Print and panic")
 	fmt.Println()
 
+	fmt.Println("   A *log.Logger has the same methods, without the shared state:")
+	fmt.Println("   logger.Println(...) behaves like log.Println(...)")
+	fmt.Println()
+
 	fmt.Println("   Example output:")
-	log.Println("Application started")
-	log.Printf("Processing user %d\n", 42)
+	logger.Println("Application started")
+	logger.Printf("Processing user %d\n", 42)
 	fmt.Println()
 }
 
-func examplePrefixes() {
+func examplePrefixes(logger *log.Logger) {
 	fmt.Println("📌 Adding Prefixes to Messages:")
 	fmt.Println("   log.SetPrefix(\"PREFIX: \") adds text to start of every message")
+	fmt.Println("   ...of EVERY message, everywhere. Instead, make a logger per prefix")
+	fmt.Println("   that shares the original's writer and flags:")
 	fmt.Println()
 
-	// Save original prefix
-	originalPrefix := log.Prefix()
-
-	log.SetPrefix("INFO: ")
-	log.Println("This has the INFO prefix")
-
-	log.SetPrefix("ERROR: ")
-	log.Println("This has the ERROR prefix")
-
-	log.SetPrefix("WARN: ")
-	log.Println("This has the WARN prefix")
+	for _, prefix := range []string{"INFO: ", "ERROR: ", "WARN: "} {
+		prefixed := log.New(logger.Writer(), prefix, logger.Flags())
+		prefixed.Printf("This has the %s prefix", prefix[:len(prefix)-2])
+	}
 
-	// Restore
-	log.SetPrefix(originalPrefix)
+	// The logger we were given is untouched
+	fmt.Printf("   logger.Prefix() is still %q\n", logger.Prefix())
 	fmt.Println()
 }
 
-func exampleFlags() {
+func exampleFlags(logger *log.Logger) {
 	fmt.Println("📌 Controlling Log Metadata (Flags):")
 	fmt.Println("   Flags control what information appears in each log:")
 	fmt.Println()
 
-	originalFlags := log.Flags()
-
 	// Show different flag combinations
 	flagExamples := []struct {
 		name  string
@@ -93,12 +97,10 @@ func exampleFlags() {
 	}
 
 	for _, example := range flagExamples {
-		log.SetFlags(example.flags)
+		flagged := log.New(logger.Writer(), logger.Prefix(), example.flags)
 		fmt.Printf("   [%s]:\n", example.name)
-		log.Println("   Sample message")
+		flagged.Println("   Sample message")
 	}
-
-	log.SetFlags(originalFlags)
 	fmt.Println()
 }
 
@@ -141,12 +143,12 @@ func Demo93_Part2_CustomLogLevels() {
 // PART 3: LOGGING TO FILES
 // ============================================================================
 
-func Demo93_Part3_LogToFile() {
+func Demo93_Part3_LogToFile(dir string) {
 	fmt.Println("\n=== PART 3: LOGGING TO FILES ===")
 	fmt.Println()
 
 	exampleOpeningFiles()
-	exampleFileLogging()
+	exampleFileLogging(dir)
 }
 
 func exampleOpeningFiles() {
@@ -174,21 +176,22 @@ func exampleOpeningFiles() {
 	fmt.Println()
 }
 
-func exampleFileLogging() {
+func exampleFileLogging(dir string) {
 	fmt.Println("📌 Writing Logs to File:")
 
-	// Create temp log file for demonstration
-	logFile := "/tmp/demo_app.log"
+	// The log file goes in the directory we were given, not a fixed path
+	logFile := filepath.Join(dir, "demo_app.log")
 	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
-		log.Fatal(err)
+		fmt.Println("   ✗ Opening log file:", err) // Not log.Fatal: that would end every demo after this one too
+		return
 	}
 	defer file.Close()
 
 	// Create file logger
 	fileLogger := log.New(file, "[APP] ", log.Ldate|log.Ltime|log.Lshortfile)
 
-	fmt.Printf("   Writing to: %s\n", logFile)
+	fmt.Printf("   Writing to: %s\n", filepath.Base(logFile))
 	fileLogger.Println("Server started")
 	fileLogger.Println("Listening on port 8080")
 	fileLogger.Println("Request received from 192.168.1.1")
@@ -200,6 +203,9 @@ func exampleFileLogging() {
 	multiWriter := io.MultiWriter(os.Stdout, file)
 	dualLogger := log.New(multiWriter, "[DUAL] ", log.Ltime)
 	dualLogger.Println("This appears in both terminal and file")
+	if err := file.Close(); err != nil { // Last chance to see a failed log write
+		fmt.Println("   ✗ Closing log file:", err)
+	}
 	fmt.Println()
 }
 
@@ -402,7 +408,7 @@ func Demo93_Part6_BestPractices() {
 // PART 7: COMPLETE EXAMPLE
 // ============================================================================
 
-func Demo93_Part7_CompleteExample() {
+func Demo93_Part7_CompleteExample(dir string) {
 	fmt.Println("\n=== PART 7: COMPLETE EXAMPLE ===")
 	fmt.Println()
 
@@ -410,10 +416,11 @@ func Demo93_Part7_CompleteExample() {
 	fmt.Println()
 
 	// Simulate production logger setup
-	logFile := "/tmp/app_example.log"
+	logFile := filepath.Join(dir, "app_example.log")
 	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
-		log.Fatal("Failed to open log file:", err)
+		fmt.Println("   ✗ Failed to open log file:", err)
+		return
 	}
 	defer file.Close()
 
@@ -426,7 +433,7 @@ func Demo93_Part7_CompleteExample() {
 	errorLogger := log.New(multiWriter, "[ERROR] ", log.Ldate|log.Ltime|log.Lshortfile)
 
 	fmt.Println("   Logger Configuration:")
-	fmt.Printf("   - Log file: %s\n", logFile)
+	fmt.Printf("   - Log file: %s\n", filepath.Base(logFile))
 	fmt.Println("   - Output: Both terminal and file")
 	fmt.Println("   - Format: Timestamp + Log level + Message")
 	fmt.Println()
@@ -440,6 +447,9 @@ func Demo93_Part7_CompleteExample() {
 	infoLogger.Println("Server listening on :8080")
 	errorLogger.Println("Failed to send email notification")
 	infoLogger.Println("Application shutdown")
+	if err := file.Close(); err != nil { // Last chance to see a failed log write
+		fmt.Println("   ✗ Closing log file:", err)
+	}
 
 	fmt.Println()
 }
@@ -455,13 +465,24 @@ func Demo93Logging() {
 	fmt.Println("Progression: fmt → log → files → structured JSON")
 	fmt.Println()
 
-	Demo93_Part1_StandardLog()
+	// One logger with the standard logger's settings, passed to the parts
+	// that need it, and one directory for the log files. Nothing here
+	// touches log.Default() or depends on where the program was started.
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	dir, err := os.MkdirTemp("", "gotut_93_")
+	if err != nil {
+		fmt.Println("✗ Creating a directory for the log files:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	Demo93_Part1_StandardLog(logger)
 	Demo93_Part2_CustomLogLevels()
-	Demo93_Part3_LogToFile()
+	Demo93_Part3_LogToFile(dir)
 	Demo93_Part4_StructuredLogging()
 	Demo93_Part5_ImportantConcepts()
 	Demo93_Part6_BestPractices()
-	Demo93_Part7_CompleteExample()
+	Demo93_Part7_CompleteExample(dir)
 
 	fmt.Println("\n=== SUMMARY ===")
 	fmt.Println("✓ log package: Simple, built-in logging with timestamps")
@@ -470,4 +491,5 @@ func Demo93Logging() {
 	fmt.Println("✓ Structured logging: Use Logrus or Zap for JSON output")
 	fmt.Println("✓ Log rotation: Prevent log files from consuming all disk space")
 	fmt.Println("✓ Best practice: Don't log sensitive data, use log levels")
+	fmt.Println("✓ Pass a *log.Logger around; log.SetFlags/SetPrefix change it for everyone")
 }
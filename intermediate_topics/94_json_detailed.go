@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 )
 
 func main() {
@@ -30,70 +31,61 @@ func main() {
 // PART 1: THE STANDARD LOG PACKAGE
 // ============================================================================
 
-func Demo93_Part1_StandardLog() {
+func Demo93_Part1_StandardLog(logger *log.Logger) {
 	fmt.Println("\n=== PART 1: THE STANDARD LOG PACKAGE ===")
 	fmt.Println()
 
-	exampleBasicPrinting()
-	examplePrefixes()
-	exampleFlags()
+	exampleBasicPrinting(logger)
+	examplePrefixes(logger)
+	exampleFlags(logger)
 }
 
-func exampleBasicPrinting() {
+func exampleBasicPrinting(logger *log.Logger) {
 	fmt.Println("📌 Basic Logging:")
 	fmt.Println("   The 'log' package provides robust support for logging.")
 	fmt.Println("   It adds a timestamp to every message by default.")
 	fmt.Println()
 
+	fmt.Println("   log.Println writes through log.Default(), one logger shared by the")
+	fmt.Println("   whole program. A *log.Logger passed in has the same methods:")
+	fmt.Println()
+
 	fmt.Println("   Output:")
-	log.Println("This is a simple log message")
+	logger.Println("This is a simple log message")
 	// log.Fatal("This would exit the program") // Commented out to keep demo running
 	fmt.Println()
 }
 
-func examplePrefixes() {
+func examplePrefixes(logger *log.Logger) {
 	fmt.Println("📌 Log Prefixes:")
 	fmt.Println("   We can add a prefix (like 'INFO: ') to identify log sources.")
-	fmt.Println("   Use log.SetPrefix(\"STRING \")")
+	fmt.Println("   log.SetPrefix(\"STRING \") changes it for the whole program, so")
+	fmt.Println("   make a logger with its own prefix instead:")
 	fmt.Println()
 
-	// Store original prefix to restore later
-	originalPrefix := log.Prefix()
-
-	log.SetPrefix("INFO: ")
-	log.Println("This is an info message")
-
-	log.SetPrefix("WARNING: ")
-	log.Println("This is a warning message")
+	infoLogger := log.New(logger.Writer(), "INFO: ", logger.Flags())
+	infoLogger.Println("This is an info message")
 
-	// Restore original
-	log.SetPrefix(originalPrefix)
+	warnLogger := log.New(logger.Writer(), "WARNING: ", logger.Flags())
+	warnLogger.Println("This is a warning message")
 	fmt.Println()
 }
 
-func exampleFlags() {
+func exampleFlags(logger *log.Logger) {
 	fmt.Println("📌 Log Flags (Metadata):")
 	fmt.Println("   Flags control the metadata (date, time, file info).")
-	fmt.Println("   Use log.SetFlags() with constants like log.Ldate, log.Lshortfile.")
+	fmt.Println("   Pass constants like log.Ldate, log.Lshortfile to log.New (log.SetFlags sets them for everyone).")
 	fmt.Println()
 
-	originalFlags := log.Flags()
-
 	fmt.Println("   1. Date Only (log.Ldate):")
-	log.SetFlags(log.Ldate)
-	log.Println("Message with date only")
+	log.New(logger.Writer(), logger.Prefix(), log.Ldate).Println("Message with date only")
 
 	fmt.Println("   2. Date + Time (log.Ldate | log.Ltime):")
-	log.SetFlags(log.Ldate | log.Ltime)
-	log.Println("Message with date and time")
+	log.New(logger.Writer(), logger.Prefix(), log.Ldate|log.Ltime).Println("Message with date and time")
 
 	fmt.Println("   3. Short File (log.Lshortfile):")
 	fmt.Println("   (Shows filename and line number - useful for debugging)")
-	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
-	log.Println("Message with filename")
-
-	// Restore original
-	log.SetFlags(originalFlags)
+	log.New(logger.Writer(), logger.Prefix(), log.Ldate|log.Ltime|log.Lshortfile).Println("Message with filename")
 	fmt.Println()
 }
 
@@ -130,7 +122,7 @@ func Demo93_Part2_CustomLogLevels() {
 // PART 3: LOGGING TO FILES
 // ============================================================================
 
-func Demo93_Part3_LogToFile() {
+func Demo93_Part3_LogToFile(dir string) {
 	fmt.Println("\n=== PART 3: LOGGING TO FILES ===")
 	fmt.Println()
 
@@ -146,11 +138,12 @@ func Demo93_Part3_LogToFile() {
 	fmt.Println("   - Permission: 0666 (Read/Write for everyone)")
 	fmt.Println()
 
-	// 1. Open/Create the file
+	// 1. Open/Create the file, in the directory we were given
 	fileName := "app.log"
-	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	file, err := os.OpenFile(filepath.Join(dir, fileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
-		log.Fatal(err)
+		fmt.Println("   ✗ Opening log file:", err)
+		return
 	}
 	defer file.Close() // Always defer close!
 
@@ -171,6 +164,9 @@ func Demo93_Part3_LogToFile() {
 	multiWriter := io.MultiWriter(os.Stdout, file)
 	dualLogger := log.New(multiWriter, "DUAL: ", log.Ldate|log.Ltime)
 	dualLogger.Println("This appears in both places!")
+	if err := file.Close(); err != nil { // Last chance to see a failed log write
+		fmt.Println("   ✗ Closing log file:", err)
+	}
 	fmt.Println()
 }
 
@@ -298,13 +294,26 @@ func Demo93Logging() {
 	fmt.Println("3. Structured Logging Concepts")
 	fmt.Println()
 
-	Demo93_Part1_StandardLog()
+	// Our own logger and a directory for app.log, instead of changing
+	// log.Default() and writing into wherever the program was started
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	dir, err := os.MkdirTemp("", "gotut_93_")
+	if err != nil {
+		fmt.Println("✗ Creating a directory for app.log:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	Demo93_Part1_StandardLog(logger)
 	Demo93_Part2_CustomLogLevels()
-	Demo93_Part3_LogToFile()
+	Demo93_Part3_LogToFile(dir)
 	Demo93_Part4_StructuredLogging()
 	Demo93_Part5_ImportantConcepts()
 	Demo93_Part6_BestPractices()
 
 	fmt.Println("\n=== END OF DEMO ===")
-	fmt.Println("Check the directory for 'app.log' to see the file output.")
+	fmt.Println("The file output, app.log:")
+	if data, err := os.ReadFile(filepath.Join(dir, "app.log")); err == nil {
+		fmt.Print(string(data))
+	}
 }
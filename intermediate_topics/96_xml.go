@@ -4,6 +4,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"strings"
+
+	"github.com/akarsh323/Go-tutorials-/go_projects/pkg/section"
 )
 
 /*
@@ -190,7 +192,13 @@ BEST PRACTICES:
 
 func main() {
 	fmt.Println("XML ENCODING DEMO - GO")
-	Example1_BasicXML()
-	Example2_NestedAndLists()
-	Example3_Unmarshalling()
+
+	section.Run(sections)
+}
+
+// sections are this lesson's parts, in order.
+var sections = []section.Section{
+	{Name: "basic-xml", Run: Example1_BasicXML},
+	{Name: "nested-and-lists", Run: Example2_NestedAndLists},
+	{Name: "unmarshalling", Run: Example3_Unmarshalling},
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ============================================================================
+// Error Policy for Demos
+// ============================================================================
+//
+// Lessons SHOW errors on purpose: os.Remove on a full directory, CreateTemp
+// in a path that doesn't exist. Those are printed where they happen, with
+// "(expected)". Everything else is a real failure, and a demo that prints
+// it and carries on hides it in a wall of output. So:
+//
+//   1. Example and helper functions return error; setup calls (Mkdir,
+//      WriteFile, CreateTemp...) are checked, never assigned to _.
+//   2. Each layer adds what it was doing: fmt.Errorf("example 2: %w", err).
+//   3. A demo stops at the first failure and returns it (type func() error);
+//      demos without anything that can fail stay func() and are adapted
+//      with noError.
+//   4. The runner prints the whole chain, one line per layer, and exits 1.
+//
+// The "errors" analyzer in go_projects/157_cleanup_linter.go enforces rule 1:
+//   go run 157_cleanup_linter.go -lib ..
+//
+// ============================================================================
+
+// noError adapts a demo that cannot fail to the runner's func() error.
+func noError(demo func()) func() error {
+	return func() error { demo(); return nil }
+}
+
+// errorChain lists err and everything it wraps, outermost first. Each entry
+// is the part of the message that layer added, plus its type, so the chain
+// shows where each piece came from:
+//
+//	example 2 (navigating)       *fmt.wrapError
+//	chdir /x                     *fs.PathError
+//	no such file or directory    syscall.Errno
+func errorChain(err error) []string {
+	var chain []string
+	for err != nil {
+		msg := err.Error()
+		next := errors.Unwrap(err)
+		if next != nil {
+			msg = strings.TrimSuffix(msg, ": "+next.Error())
+		} else if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() { // errors.Join: every branch, indented
+				for _, line := range errorChain(e) {
+					chain = append(chain, "  "+line)
+				}
+			}
+			return chain
+		}
+		chain = append(chain, fmt.Sprintf("%-40s %T", msg, err))
+		err = next
+	}
+	return chain
+}
+
+// reportDemoError prints a failed demo's error and its chain.
+func reportDemoError(w io.Writer, topic string, err error) {
+	fmt.Fprintf(w, "✗ %s failed: %v\n", topic, err)
+	for _, line := range errorChain(err) {
+		fmt.Fprintln(w, "    "+line)
+	}
+}
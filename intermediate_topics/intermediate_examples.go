@@ -1,54 +1,78 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"slices"
+)
 
-// Runner: calls per-topic demo functions organized in separate files.
+// demo is one topic's demo, as the runner calls it.
+type demo struct {
+	topic string
+	run   func() error
+}
+
+// Runner: calls the per-topic demo functions organized in separate files.
+// Most topics here are programs of their own, run with go run NN_name.go;
+// the ones below only define a DemoNN function, and this is their main.
+// The directory holds many mains, so the runner is built from its files,
+// not the package:
+//
+//	go run intermediate_examples.go state_guard.go demo_errors.go \
+//	    86_file_paths.go 87_directories.go 88_temp_files_dirs.go \
+//	    91_subcommands.go 92_env_vars.go 93_logging.go \
+//	    100_math.go 101_math_examples.go 102_summary.go [TOPIC...]
+//
+// that is, every package main file here without a main() of its own.
+// With TOPIC numbers it runs only those demos.
+//
+// Every demo runs under GuardState (state_guard.go), so a demo that leaves
+// the working directory, the standard logger or the environment changed
+// is reported and can't affect the demos after it. A demo that returns an
+// error is reported with its full chain (demo_errors.go) and the rest still
+// run; either problem makes the runner exit 1.
 func main() {
-	fmt.Println("Intermediate topics runner — calling demos 57–102")
-	Demo57Closures()
-	Demo58Recursion()
-	Demo59Pointers()
-	Demo60StringsRunes()
-	Demo61FmtVerbs()
-	Demo62FmtPackage()
-	Demo63Structs()
-	Demo64Methods()
-	Demo65Interfaces()
-	Demo66StructEmbedding()
-	Demo67Generics()
-	Demo68Errors()
-	Demo69CustomErrors()
-	Demo70StringFunctions()
-	Demo71StringFormatting()
-	Demo72TextTemplates()
-	Demo73Regex()
-	Demo74Time()
-	Demo75Epoch()
-	Demo76TimeFormatParse()
-	Demo77Random()
-	Demo78NumberParsing()
-	Demo79URLParsing()
-	Demo80Bufio()
-	Demo81Base64()
-	Demo82SHA()
-	Demo83WriteFile()
-	Demo84ReadFile()
-	Demo85LineFilters()
-	Demo86FilePaths()
-	Demo87Directories()
-	Demo88TempFilesDirs()
-	Demo89EmbedDirective()
-	Demo90CmdArgsFlags()
-	Demo91SubCommands()
-	Demo92EnvVars()
-	Demo93Logging()
-	Demo94JSON()
-	Demo95StructTags()
-	Demo96XML()
-	Demo97GoExtensionNotes()
-	Demo98TypeConversions()
-	Demo99IO()
-	Demo100Math()
-	Demo101MathExamples()
-	Demo102Summary()
+	demos := []demo{
+		{"86", noError(Demo86FilePaths)},
+		{"87", Demo87Directories},
+		{"88", Demo88TempFilesDirs},
+		{"91", noError(Demo91SubCommands)},
+		{"92", noError(Demo92EnvVars)},
+		{"93", noError(Demo93Logging)},
+		{"100", noError(Demo100Math)},
+		{"101", noError(Demo101MathExamples)},
+		{"102", noError(Demo102Summary)},
+	}
+	only := os.Args[1:]
+	for _, t := range only {
+		if !slices.ContainsFunc(demos, func(d demo) bool { return d.topic == t }) {
+			fmt.Fprintf(os.Stderr, "no demo for topic %s; this runner has 86-88, 91-93 and 100-102\n", t)
+			os.Exit(2)
+		}
+	}
+	fmt.Println("Intermediate topics runner — calling demos 86–102")
+
+	leaky, failed := 0, 0
+	for _, d := range demos {
+		if len(only) > 0 && !slices.Contains(only, d.topic) {
+			continue
+		}
+		var err error
+		if !GuardState(d.topic, func() { err = d.run() }) {
+			leaky++
+		}
+		if err != nil {
+			reportDemoError(os.Stderr, d.topic, err)
+			failed++
+		}
+	}
+	if leaky > 0 {
+		fmt.Fprintf(os.Stderr, "%d demo(s) changed process-wide state\n", leaky)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d demo(s) failed\n", failed)
+	}
+	if leaky > 0 || failed > 0 {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// State Guard for the Intermediate Topics Runner
+// ============================================================================
+//
+// intermediate_examples.go runs every demo in ONE process, one after another.
+// Anything a demo changes for the whole process is still changed when the
+// next demo starts:
+//
+//   - the working directory   (os.Chdir)
+//   - the standard logger     (log.SetFlags, log.SetPrefix, log.SetOutput)
+//   - environment variables   (os.Setenv, os.Unsetenv)
+//
+// Demos should work on directories and loggers they are handed instead (see
+// 87 and 93). GuardState checks that they do: it records this state before a
+// demo, compares it afterwards, puts back anything that changed and reports
+// it, so one careless demo can't break the ones after it.
+//
+// ============================================================================
+
+// processState is the process-wide state a demo can change by accident.
+type processState struct {
+	dir       string
+	logFlags  int
+	logPrefix string
+	logOutput io.Writer
+	env       map[string]string
+}
+
+func captureState() processState {
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = "" // The directory was deleted under us: nothing to compare or return to
+	}
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	return processState{dir, log.Flags(), log.Prefix(), log.Writer(), env}
+}
+
+// restore puts back everything that differs from s and describes each
+// change it had to undo.
+func (s processState) restore() []string {
+	now := captureState()
+	var changed []string
+	if now.dir != s.dir && s.dir != "" {
+		changed = append(changed, fmt.Sprintf("working directory %q, was %q", now.dir, s.dir))
+		os.Chdir(s.dir)
+	}
+	if now.logFlags != s.logFlags {
+		changed = append(changed, fmt.Sprintf("log flags %d, were %d", now.logFlags, s.logFlags))
+		log.SetFlags(s.logFlags)
+	}
+	if now.logPrefix != s.logPrefix {
+		changed = append(changed, fmt.Sprintf("log prefix %q, was %q", now.logPrefix, s.logPrefix))
+		log.SetPrefix(s.logPrefix)
+	}
+	if now.logOutput != s.logOutput {
+		changed = append(changed, "log output replaced")
+		log.SetOutput(s.logOutput)
+	}
+	for _, k := range slices.Sorted(maps.Keys(now.env)) {
+		old, ok := s.env[k]
+		switch {
+		case !ok:
+			changed = append(changed, "env "+k+" set")
+			os.Unsetenv(k)
+		case old != now.env[k]:
+			changed = append(changed, "env "+k+" changed")
+			os.Setenv(k, old)
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(s.env)) {
+		if _, ok := now.env[k]; !ok {
+			changed = append(changed, "env "+k+" unset")
+			os.Setenv(k, s.env[k])
+		}
+	}
+	return changed
+}
+
+// GuardState runs demo, then reports any process-wide state it left
+// changed after putting that state back. It returns false if there was
+// anything to put back.
+func GuardState(name string, demo func()) bool {
+	before := captureState()
+	demo()
+	changed := before.restore()
+	for _, c := range changed {
+		fmt.Fprintf(os.Stderr, "⚠️  %s left global state changed: %s\n", name, c)
+	}
+	return len(changed) == 0
+}